@@ -3,11 +3,14 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/Ulpio/guIA-backend/internal/config"
 	"github.com/Ulpio/guIA-backend/internal/database"
+	"github.com/Ulpio/guIA-backend/internal/events"
 	"github.com/Ulpio/guIA-backend/internal/handlers.go"
 	"github.com/Ulpio/guIA-backend/internal/middleware"
+	"github.com/Ulpio/guIA-backend/internal/realtime"
 	"github.com/Ulpio/guIA-backend/internal/repositories"
 	"github.com/Ulpio/guIA-backend/internal/services"
 
@@ -40,20 +43,284 @@ func main() {
 	userRepo := repositories.NewUserRepository(db)
 	postRepo := repositories.NewPostRepository(db)
 	itineraryRepo := repositories.NewItineraryRepository(db)
+	geoRepo := repositories.NewGeoRepository(db)
+	challengeRepo := repositories.NewChallengeRepository(db)
+	conversationRepo := repositories.NewConversationRepository(db)
+	travelBuddyRepo := repositories.NewTravelBuddyRepository(db)
+	experienceRepo := repositories.NewExperienceRepository(db)
+	tipRepo := repositories.NewTipRepository(db)
+	ledgerRepo := repositories.NewLedgerRepository(db)
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	fraudRepo := repositories.NewFraudRepository(db)
+	moderationRepo := repositories.NewModerationRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	spamRepo := repositories.NewSpamRepository(db)
+	adminRepo := repositories.NewAdminRepository(db)
+	verificationRepo := repositories.NewVerificationRepository(db)
+	mediaRepo := repositories.NewMediaRepository(db)
+	translationRepo := repositories.NewTranslationRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	tripReminderRepo := repositories.NewTripReminderRepository(db)
+	memoryRepo := repositories.NewMemoryRepository(db)
+	yearReviewRepo := repositories.NewYearReviewRepository(db)
+	storyRepo := repositories.NewStoryRepository(db)
+	feedSettingsRepo := repositories.NewFeedSettingsRepository(db)
+	commentRepo := repositories.NewCommentRepository(db)
+	riskRepo := repositories.NewRiskRepository(db)
+	complianceRepo := repositories.NewComplianceRepository(db)
+	collectionRepo := repositories.NewCollectionRepository(db)
+	requestLogRepo := repositories.NewRequestLogRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	emailRepo := repositories.NewEmailRepository(db)
+	clientErrorRepo := repositories.NewClientErrorRepository(db)
+	destinationRepo := repositories.NewDestinationRepository(db)
+	statsRepo := repositories.NewStatsRepository(db)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(db)
+	exportRepo := repositories.NewExportRepository(db)
+	uploadRepo := repositories.NewUploadRepository(db)
+	transcodeRepo := repositories.NewTranscodeRepository(db)
+	placeRepo := repositories.NewPlaceRepository(db)
+	routeRepo := repositories.NewRouteRepository(db)
+	templateRepo := repositories.NewTemplateRepository(db)
+	expenseRepo := repositories.NewExpenseRepository(db)
+	tripRepo := repositories.NewTripRepository(db)
+	weatherRepo := repositories.NewWeatherRepository(db)
+	shareRepo := repositories.NewShareRepository(db)
+	featuredRepo := repositories.NewFeaturedRepository(db)
+	ratingRepo := repositories.NewRatingRepository(db)
+	travelRepo := repositories.NewTravelRepository(db)
+	reviewRepo := repositories.NewReviewRepository(db)
+	invitationRepo := repositories.NewInvitationRepository(db)
+	searchRepo := repositories.NewSearchRepository(db)
+	recommendationRepo := repositories.NewRecommendationRepository(db)
+	suspensionRepo := repositories.NewSuspensionRepository(db)
+
+	// Barramento de eventos de domínio
+	eventBus := events.NewInProcessBus()
+
+	// Conexões em tempo real (WebSocket) abertas por usuário
+	realtimeHub := realtime.NewHub()
 
 	// Inicializar serviços
-	userService := services.NewUserService(userRepo)
-	postService := services.NewPostService(postRepo)
-	itineraryService := services.NewItineraryService(itineraryRepo)
+	emailService := services.NewEmailService(emailRepo, userRepo, notificationRepo, cfg.EmailConfig)
+	notificationService := services.NewNotificationService(notificationRepo, userRepo, realtimeHub, emailService)
+	feedSettingsService := services.NewFeedSettingsService(feedSettingsRepo)
+	// Filtro de palavrões dos posts, comentários e avaliações
+	profanityService := services.NewProfanityService(reportRepo, cfg.ProfanityConfig)
+	// Detecção de spam nos posts e follows
+	spamService := services.NewSpamService(spamRepo, reportRepo, cfg.SpamConfig)
+	postService := services.NewPostService(postRepo, userRepo, itineraryRepo, mediaRepo, feedSettingsService, profanityService, spamService, eventBus)
+	geoService := services.NewGeoService(geoRepo, itineraryRepo)
+	currencyService := services.NewCurrencyService(exchangeRateRepo, geoRepo, cfg.CurrencyConfig)
+	itineraryService := services.NewItineraryService(itineraryRepo, geoService, eventBus, currencyService, profanityService)
 	authService := services.NewAuthService(userRepo, cfg.JWTSecret)
-	mediaService := services.NewMediaService(cfg.MediaConfig)
+	mediaService := services.NewMediaService(cfg.MediaConfig, mediaRepo, eventBus)
+	userService := services.NewUserService(userRepo, mediaService, spamService, eventBus)
+	uploadService := services.NewUploadService(uploadRepo, mediaRepo, mediaService, cfg.MediaConfig)
+	photoService := services.NewPhotoService(itineraryRepo, mediaService)
+	challengeService := services.NewChallengeService(challengeRepo, eventBus)
+	conversationService := services.NewConversationService(conversationRepo, userRepo, itineraryRepo, mediaRepo, mediaService, cfg.AttachmentConfig, eventBus)
+	travelBuddyService := services.NewTravelBuddyService(travelBuddyRepo, userRepo, geoService, conversationService)
+	experienceService := services.NewExperienceService(experienceRepo, userRepo, geoService, conversationService)
+	billingProvider := services.NewBillingProvider(cfg.BillingConfig)
+	ledgerService := services.NewLedgerService(ledgerRepo, tipRepo, billingProvider, cfg.BillingConfig)
+	fraudService := services.NewFraudService(fraudRepo)
+	translationService := services.NewTranslationService(translationRepo, postRepo, services.NewTranslationProvider(cfg.TranslationConfig))
+	moderationService := services.NewModerationService(moderationRepo, conversationRepo, mediaService, conversationService)
+	reportService := services.NewReportService(reportRepo, moderationRepo, postRepo, commentRepo, itineraryRepo, ratingRepo, userRepo, services.ReportHideThresholds{
+		Post:      cfg.PostReportHideThreshold,
+		Comment:   cfg.CommentReportHideThreshold,
+		Itinerary: cfg.ItineraryReportHideThreshold,
+		Rating:    cfg.RatingReportHideThreshold,
+	})
+	realtimeService := services.NewRealtimeService(realtimeHub, postRepo, userRepo, conversationRepo, eventBus)
+	// Notificações das interações entre usuários, a partir dos eventos
+	services.NewNotificationListener(notificationService, postRepo, userRepo, conversationRepo, ratingRepo, itineraryRepo, eventBus)
+	memoryService := services.NewMemoryService(memoryRepo, notificationService)
+	yearReviewService := services.NewYearReviewService(yearReviewRepo, postRepo, geoService, mediaService)
+	storyService := services.NewStoryService(storyRepo, userRepo, mediaRepo)
+	exploreService := services.NewExploreService(postService, itineraryService)
+	searchService := services.NewSearchService(searchRepo, userService, postService, itineraryService, geoService, notificationService, eventBus)
+	recommendationService := services.NewRecommendationService(recommendationRepo, postService, itineraryService)
+	commentService := services.NewCommentService(commentRepo, postRepo, userRepo, profanityService)
+	adminService := services.NewAdminService(adminRepo, moderationRepo)
+	suspensionService := services.NewSuspensionService(suspensionRepo, moderationRepo, notificationService, realtimeHub)
+	verificationService := services.NewVerificationService(verificationRepo, userRepo, notificationService)
+	riskService := services.NewRiskService(riskRepo, cfg.BotRiskBlockThreshold)
+	complianceService := services.NewComplianceService(complianceRepo, moderationRepo, itineraryRepo)
+	collectionService := services.NewCollectionService(collectionRepo, itineraryRepo)
+	requestLogService := services.NewRequestLogService(requestLogRepo, time.Duration(cfg.RequestLogRetentionDays)*24*time.Hour)
+	webhookService := services.NewWebhookService(webhookRepo, cfg.WebhookConfig)
+	clientErrorService := services.NewClientErrorService(clientErrorRepo, cfg.ClientErrorConfig)
+	destinationService := services.NewDestinationService(destinationRepo, geoRepo, userRepo, itineraryRepo, itineraryService)
+	statsService := services.NewStatsService(statsRepo)
+	exportService := services.NewExportService(exportRepo, itineraryRepo, geoService, mediaService, services.NewPDFRenderer(cfg.ExportConfig, mediaService.LoadImage), cfg.ExportConfig)
+	placeService := services.NewPlaceService(itineraryRepo, placeRepo, mediaService, services.NewPlacesProvider(cfg.PlacesConfig), time.Duration(cfg.PlacesConfig.CacheDays)*24*time.Hour)
+	routeService := services.NewRouteService(routeRepo, itineraryRepo, geoService, services.NewRoutingProvider(cfg.RoutingConfig), time.Duration(cfg.RoutingConfig.CacheDays)*24*time.Hour)
+	templateService := services.NewTemplateService(templateRepo, itineraryRepo)
+	budgetService := services.NewBudgetService(itineraryRepo, currencyService)
+	expenseService := services.NewExpenseService(expenseRepo, itineraryRepo, userRepo, currencyService)
+	tripService := services.NewTripService(tripRepo, itineraryRepo, geoService)
+	shareService := services.NewShareService(shareRepo, itineraryRepo, itineraryService, cfg.ShareBaseURL)
+	featuredService := services.NewFeaturedService(featuredRepo, itineraryRepo)
+	ratingService := services.NewRatingService(ratingRepo, itineraryRepo, eventBus)
+	travelService := services.NewTravelService(travelRepo, itineraryRepo, tripRepo, userRepo, eventBus)
+	reviewService := services.NewReviewService(reviewRepo, itineraryRepo)
+	invitationService := services.NewInvitationService(invitationRepo, itineraryRepo, userRepo, notificationService, emailService, eventBus)
+	transcodeService := services.NewTranscodeService(transcodeRepo, mediaRepo, mediaService, notificationService, eventBus, cfg.TranscodeConfig)
+	weatherService := services.NewWeatherService(weatherRepo, itineraryRepo, services.NewWeatherProvider(cfg.WeatherConfig), time.Duration(cfg.WeatherConfig.CacheHours)*time.Hour)
+	geoIPService := services.NewGeoIPService(cfg.GeoIPConfig)
+	tipService := services.NewTipService(tipRepo, userRepo, itineraryRepo, ledgerService, fraudService, webhookService, billingProvider, cfg.BillingConfig)
+	tripReminderService := services.NewTripReminderService(tripRepo, tripReminderRepo, weatherService, notificationService)
+	tripBudgetService := services.NewTripBudgetService(tripRepo, itineraryRepo, expenseRepo, currencyService)
+	subscriptionService := services.NewSubscriptionService(subscriptionRepo, ledgerService, webhookService, billingProvider, cfg.BillingConfig)
+	promotionPaymentService := services.NewPromotionPaymentService(destinationRepo, userRepo, ledgerService, fraudService, webhookService, billingProvider, cfg.BillingConfig)
+
+	// Dados de referência geográfica e normalização dos roteiros existentes
+	if err := geoService.SeedReferenceData(cfg.GeoDataPath); err != nil {
+		log.Println("Falha ao importar dados geográficos:", err)
+	} else if updated, err := geoService.BackfillItineraries(); err != nil {
+		log.Println("Falha ao normalizar localização dos roteiros:", err)
+	} else if updated > 0 {
+		log.Printf("%d roteiros associados aos dados de referência geográfica", updated)
+	}
+
+	// Contagem inicial das hashtags para as sugestões de busca
+	if counted, err := searchService.BackfillHashtags(); err != nil {
+		log.Println("Falha ao contar hashtags dos posts:", err)
+	} else if counted > 0 {
+		log.Printf("Hashtags de %d posts contadas para as sugestões de busca", counted)
+	}
 
 	// Inicializar handlers
 	userHandler := handlers.NewUserHandler(userService)
-	postHandler := handlers.NewPostHandler(postService)
-	itineraryHandler := handlers.NewItineraryHandler(itineraryService)
-	authHandler := handlers.NewAuthHandler(authService)
+	postHandler := handlers.NewPostHandler(postService, riskService, complianceService)
+	itineraryHandler := handlers.NewItineraryHandler(itineraryService, complianceService)
+	authHandler := handlers.NewAuthHandler(authService, riskService)
 	mediaHandler := handlers.NewMediaHandler(mediaService)
+	uploadHandler := handlers.NewUploadHandler(uploadService)
+	transcodeHandler := handlers.NewTranscodeHandler(transcodeService)
+	photoHandler := handlers.NewPhotoHandler(photoService)
+	geoHandler := handlers.NewGeoHandler(geoService)
+	challengeHandler := handlers.NewChallengeHandler(challengeService)
+	conversationHandler := handlers.NewConversationHandler(conversationService)
+	travelBuddyHandler := handlers.NewTravelBuddyHandler(travelBuddyService)
+	experienceHandler := handlers.NewExperienceHandler(experienceService)
+	tipHandler := handlers.NewTipHandler(tipService)
+	tripReminderHandler := handlers.NewTripReminderHandler(tripReminderService)
+	tripBudgetHandler := handlers.NewTripBudgetHandler(tripBudgetService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	fraudHandler := handlers.NewFraudHandler(fraudService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	suspensionHandler := handlers.NewSuspensionHandler(suspensionService, authService)
+	verificationHandler := handlers.NewVerificationHandler(verificationService)
+	translationHandler := handlers.NewTranslationHandler(translationService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeService)
+	memoryHandler := handlers.NewMemoryHandler(memoryService)
+	yearReviewHandler := handlers.NewYearReviewHandler(yearReviewService)
+	storyHandler := handlers.NewStoryHandler(storyService)
+	feedSettingsHandler := handlers.NewFeedSettingsHandler(feedSettingsService)
+	exploreHandler := handlers.NewExploreHandler(exploreService, complianceService)
+	searchHandler := handlers.NewSearchHandler(searchService, complianceService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService, complianceService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	riskHandler := handlers.NewRiskHandler(riskService)
+	complianceHandler := handlers.NewComplianceHandler(complianceService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	requestLogHandler := handlers.NewRequestLogHandler(requestLogService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	clientErrorHandler := handlers.NewClientErrorHandler(clientErrorService)
+	destinationHandler := handlers.NewDestinationHandler(destinationService, complianceService)
+	promotionPaymentHandler := handlers.NewPromotionPaymentHandler(promotionPaymentService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	exportHandler := handlers.NewExportHandler(exportService, complianceService)
+	placeHandler := handlers.NewPlaceHandler(placeService)
+	routeHandler := handlers.NewRouteHandler(routeService, complianceService)
+	templateHandler := handlers.NewTemplateHandler(templateService, complianceService)
+	budgetHandler := handlers.NewBudgetHandler(budgetService, complianceService)
+	expenseHandler := handlers.NewExpenseHandler(expenseService)
+	tripHandler := handlers.NewTripHandler(tripService)
+	weatherHandler := handlers.NewWeatherHandler(weatherService, complianceService)
+	shareHandler := handlers.NewShareHandler(shareService, complianceService)
+	featuredHandler := handlers.NewFeaturedHandler(featuredService)
+	ratingHandler := handlers.NewRatingHandler(ratingService, complianceService)
+	travelHandler := handlers.NewTravelHandler(travelService, complianceService)
+	reviewHandler := handlers.NewReviewHandler(reviewService, complianceService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+
+	// Regras antifraude padrão
+	if err := fraudService.EnsureDefaultRules(); err != nil {
+		log.Println("Falha ao criar regras antifraude:", err)
+	}
+
+	// Envio periódico dos saques agendados
+	ledgerService.StartPayoutScheduler(time.Hour)
+
+	// Notificação diária de lembranças ("neste dia")
+	memoryService.StartMemoriesScheduler(time.Hour)
+
+	// Lembretes das viagens que estão para começar
+	tripReminderService.StartTripReminderScheduler(time.Hour)
+
+	// Alertas de roteiros novos para as buscas salvas
+	searchService.StartSavedSearchAlertScheduler(time.Hour)
+
+	// Recomendações personalizadas, recalculadas toda noite
+	recommendationService.StartRecommendationScheduler(time.Hour)
+
+	// Geração dos resumos do ano que terminou
+	yearReviewService.StartYearReviewScheduler(24 * time.Hour)
+
+	// Arquivamento dos stories expirados
+	storyService.StartStoryCleanupScheduler(10 * time.Minute)
+
+	// Invalidação do cache da CDN das mídias apagadas, em lotes
+	mediaService.StartCDNInvalidationScheduler(time.Minute)
+
+	// Fila dos lotes de moderação
+	moderationService.StartBulkModerationWorker()
+
+	// Registros das requisições com erro (consulta por trace ID)
+	requestLogService.StartRequestLogWriter()
+	requestLogService.StartRequestLogCleanupScheduler(24 * time.Hour)
+
+	// Callbacks dos provedores (pagamentos, transcodificação e push)
+	webhookService.StartWebhookWorker()
+	webhookService.StartWebhookRetryScheduler(time.Minute)
+
+	// Envio dos e-mails transacionais, novas tentativas e resumo semanal
+	emailService.StartEmailWorker()
+	emailService.StartEmailRetryScheduler(time.Minute)
+	emailService.StartWeeklyDigestScheduler(time.Hour)
+
+	// Pushes segurados pelo horário de silêncio, enviados quando ele termina
+	notificationService.StartDeferredPushScheduler(time.Minute)
+
+	// Limpeza dos relatos de erro dos apps
+	clientErrorService.StartClientErrorCleanupScheduler(24 * time.Hour)
+
+	// Estatísticas públicas do site institucional
+	statsService.StartStatsScheduler(time.Hour)
+
+	// Cotações das moedas para os filtros de custo
+	currencyService.StartExchangeRateScheduler(12 * time.Hour)
+
+	// Exportações de roteiros grandes e limpeza dos arquivos expirados
+	exportService.StartExportWorker()
+	exportService.StartExportCleanupScheduler(6 * time.Hour)
+
+	// Conversão dos vídeos enviados para HLS
+	transcodeService.StartTranscodeWorker()
+
+	// Limpeza das sessões de upload em partes expiradas
+	uploadService.StartUploadCleanupScheduler(time.Hour)
+
+	// Reativação das contas ao fim da suspensão
+	suspensionService.StartUnsuspensionScheduler(5 * time.Minute)
 
 	// Configurar Gin
 	if cfg.Environment == "production" {
@@ -62,12 +329,24 @@ func main() {
 
 	r := gin.Default()
 
+	// Só os proxies/CDN configurados podem informar o IP real do cliente
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Proxies confiáveis inválidos:", err)
+	}
+
+	// Trace ID de cada requisição, devolvido nas respostas de erro
+	r.Use(middleware.TracingMiddleware(requestLogService.Record))
+
+	// País de origem (CDN confiável ou GeoIP), usado nas restrições por país
+	// e na avaliação antifraude
+	r.Use(middleware.CountryMiddleware(cfg.GeoIPConfig.CountryHeader, cfg.TrustedProxies, geoIPService.Country))
+
 	// Middleware CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.FormStartedAtHeader, middleware.RequestIDHeader, handlers.UploadOffsetHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader, handlers.UploadOffsetHeader},
 		AllowCredentials: true,
 	}))
 
@@ -77,32 +356,110 @@ func main() {
 		// Autenticação
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
+			auth.POST("/register", middleware.BotDetectionMiddleware(), authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/appeal", suspensionHandler.CreateAppeal)
 		}
 
+		// Dados de referência geográfica (autocomplete)
+		geo := api.Group("/geo")
+		{
+			geo.GET("/countries", geoHandler.SearchCountries)
+			geo.GET("/cities", geoHandler.SearchCities)
+		}
+
+		// Callbacks dos provedores, autenticados pela assinatura
+		api.POST("/webhooks/:provider", webhookHandler.ReceiveWebhook)
+
+		// Estatísticas públicas (calculadas em segundo plano)
+		api.GET("/stats/public", statsHandler.GetPublicStats)
+
+		// Relatos de erro dos apps (login opcional)
+		api.POST("/client-errors",
+			middleware.OptionalAuthMiddleware(cfg.JWTSecret),
+			middleware.RateLimitMiddleware(cfg.ClientErrorConfig.RateLimitPerMinute, time.Minute),
+			clientErrorHandler.ReportClientError)
+
+		// Mapa do roteiro em GeoJSON (login opcional, para embeds de roteiros públicos)
+		api.GET("/itineraries/:id/geojson",
+			middleware.OptionalAuthMiddleware(cfg.JWTSecret),
+			exportHandler.GetItineraryGeoJSON)
+
+		// Roteiros abertos pelo link de compartilhamento (sem login)
+		api.GET("/public/itineraries/:slug", shareHandler.GetPublicItinerary)
+
+		// Canal em tempo real e o fallback SSE para proxies que não repassam
+		// WebSocket; o token pode vir na URL, já que navegadores não enviam
+		// cabeçalhos ao abrir essas conexões
+		api.GET("/ws", middleware.StreamAuthMiddleware(cfg.JWTSecret, suspensionService.CheckAccount), realtimeHandler.Connect)
+		api.GET("/notifications/stream", middleware.StreamAuthMiddleware(cfg.JWTSecret, suspensionService.CheckAccount), realtimeHandler.Stream)
+
+		// Anexos das conversas, por link assinado (sem cabeçalho de autenticação)
+		api.GET("/attachments/:id", conversationHandler.GetAttachment)
+
 		// Rotas protegidas
 		protected := api.Group("/")
-		protected.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+		protected.Use(middleware.AuthMiddleware(cfg.JWTSecret, suspensionService.CheckAccount))
 		{
 			// Usuários
 			users := protected.Group("/users")
 			{
 				users.GET("/profile", userHandler.GetProfile)
 				users.PUT("/profile", userHandler.UpdateProfile)
+				users.POST("/avatar", userHandler.UploadAvatar)
+				users.POST("/cover", userHandler.UploadCover)
+				users.GET("/me/memories", memoryHandler.GetMemories)
+				users.PUT("/me/memories/settings", memoryHandler.UpdateMemorySettings)
+				users.GET("/me/year-review/:year", yearReviewHandler.GetYearReview)
+				users.GET("/trips/upcoming", tripHandler.GetUpcomingTrips)
+				users.GET("/trip-invitations", invitationHandler.GetTripInvitations)
+				users.POST("/trip-invitations/:id/accept", invitationHandler.AcceptTripInvitation)
+				users.POST("/trip-invitations/:id/decline", invitationHandler.DeclineTripInvitation)
+				users.GET("/me/feed-settings", feedSettingsHandler.GetFeedSettings)
+				users.PUT("/me/feed-settings", feedSettingsHandler.UpdateFeedSettings)
+				users.GET("/me/presence", realtimeHandler.GetPresenceSettings)
+				users.PUT("/me/presence", realtimeHandler.UpdatePresenceSettings)
+				users.GET("/me/verification", verificationHandler.GetMyVerification)
+				users.POST("/me/verification", verificationHandler.RequestVerification)
+				users.GET("/collections", collectionHandler.GetCollections)
+				users.POST("/collections", collectionHandler.CreateCollection)
+				users.GET("/collections/:id", collectionHandler.GetCollection)
+				users.PUT("/collections/:id", collectionHandler.UpdateCollection)
+				users.DELETE("/collections/:id", collectionHandler.DeleteCollection)
+				users.POST("/collections/:id/items", collectionHandler.AddItinerary)
+				users.DELETE("/collections/:id/items/:itineraryId", collectionHandler.RemoveItinerary)
+				users.GET("/blocked", userHandler.GetBlockedUsers)
+				users.GET("/search", userHandler.SearchUsers)
+				users.POST("/:id/block", userHandler.BlockUser)
+				users.DELETE("/:id/block", userHandler.UnblockUser)
+				users.POST("/:id/report", reportHandler.ReportUser)
 				users.GET("/:id", userHandler.GetUserByID)
+				users.GET("/:id/badges", challengeHandler.GetUserBadges)
+				users.GET("/:id/completed-trips", travelHandler.GetCompletedTrips)
+				users.GET("/:id/travel-map", travelHandler.GetTravelMap)
 			}
 
 			// Posts
 			posts := protected.Group("/posts")
 			{
 				posts.GET("/", postHandler.GetFeed)
-				posts.POST("/", postHandler.CreatePost)
+				posts.POST("/", middleware.BotDetectionMiddleware(), postHandler.CreatePost)
+				posts.GET("/nearby", postHandler.GetNearbyPosts)
+				posts.GET("/trending", postHandler.GetTrendingPosts)
+				posts.GET("/author", postHandler.GetPostsByAuthor)
+				posts.GET("/search", postHandler.SearchPosts)
 				posts.GET("/:id", postHandler.GetPostByID)
 				posts.PUT("/:id", postHandler.UpdatePost)
 				posts.DELETE("/:id", postHandler.DeletePost)
 				posts.POST("/:id/like", postHandler.LikePost)
 				posts.DELETE("/:id/like", postHandler.UnlikePost)
+				posts.GET("/:id/likes", postHandler.GetPostLikers)
+				posts.POST("/:id/share", postHandler.SharePost)
+				posts.GET("/:id/comments", commentHandler.GetComments)
+				posts.POST("/:id/comments", commentHandler.CreateComment)
+				posts.GET("/:id/insights", postHandler.GetPostInsights)
+				posts.GET("/:id/translation", translationHandler.GetPostTranslation)
+				posts.POST("/:id/report", reportHandler.ReportPost)
 			}
 
 			// Roteiros
@@ -110,10 +467,209 @@ func main() {
 			{
 				itineraries.GET("/", itineraryHandler.GetItineraries)
 				itineraries.POST("/", itineraryHandler.CreateItinerary)
+				itineraries.GET("/templates", templateHandler.GetTemplates)
+				itineraries.GET("/search", itineraryHandler.SearchItineraries)
+				itineraries.GET("/templates/metrics", middleware.CompanyMiddleware(), templateHandler.GetTemplateMetrics)
 				itineraries.GET("/:id", itineraryHandler.GetItineraryByID)
 				itineraries.PUT("/:id", itineraryHandler.UpdateItinerary)
 				itineraries.DELETE("/:id", itineraryHandler.DeleteItinerary)
 				itineraries.POST("/:id/rate", itineraryHandler.RateItinerary)
+				itineraries.POST("/:id/report", reportHandler.ReportItinerary)
+				itineraries.GET("/:id/ratings", ratingHandler.GetRatings)
+				itineraries.POST("/:id/ratings/:ratingId/report", reportHandler.ReportRating)
+				itineraries.PUT("/:id/ratings/:ratingId/reply", ratingHandler.ReplyToRating)
+				itineraries.DELETE("/:id/ratings/:ratingId/reply", ratingHandler.DeleteRatingReply)
+				itineraries.PUT("/:id/ratings/:ratingId/vote", ratingHandler.VoteRating)
+				itineraries.DELETE("/:id/ratings/:ratingId/vote", ratingHandler.RemoveRatingVote)
+				itineraries.POST("/:id/complete", travelHandler.CompleteItinerary)
+				itineraries.DELETE("/:id/complete", travelHandler.UncompleteItinerary)
+				itineraries.GET("/:id/check-ins", travelHandler.GetItineraryProgress)
+				itineraries.POST("/:id/locations/:locationId/check-in", travelHandler.CheckIn)
+				itineraries.DELETE("/:id/locations/:locationId/check-in", travelHandler.RemoveCheckIn)
+				itineraries.GET("/:id/locations/:locationId/reviews", reviewHandler.GetLocationReviews)
+				itineraries.PUT("/:id/locations/:locationId/review", reviewHandler.ReviewLocation)
+				itineraries.DELETE("/:id/locations/:locationId/review", reviewHandler.DeleteLocationReview)
+				itineraries.POST("/:id/like", itineraryHandler.LikeItinerary)
+				itineraries.DELETE("/:id/like", itineraryHandler.UnlikeItinerary)
+				itineraries.POST("/:id/clone", itineraryHandler.CloneItinerary)
+				itineraries.GET("/:id/share", shareHandler.GetShareLink)
+				itineraries.PUT("/:id/share", shareHandler.UpdateShareLink)
+				itineraries.POST("/:id/instantiate", templateHandler.InstantiateTemplate)
+				itineraries.PUT("/:id/template", middleware.CompanyMiddleware(), templateHandler.SetTemplate)
+				itineraries.GET("/:id/export/pdf", exportHandler.ExportItineraryPDF)
+				itineraries.GET("/:id/export/ics", exportHandler.ExportItineraryICS)
+				itineraries.GET("/:id/export/gpx", exportHandler.ExportItineraryGPX)
+				itineraries.GET("/:id/export/kml", exportHandler.ExportItineraryKML)
+				itineraries.POST("/:id/import", itineraryHandler.ImportItineraryTrack)
+				itineraries.POST("/:id/places/import/preview", placeHandler.PreviewPlacesImport)
+				itineraries.POST("/:id/places/import", placeHandler.ImportPlaces)
+				itineraries.POST("/:id/places/enrich", placeHandler.EnrichItinerary)
+				itineraries.POST("/:id/locations/:locationId/enrich", placeHandler.EnrichLocation)
+				itineraries.GET("/:id/exports/:exportId", exportHandler.GetItineraryExport)
+				itineraries.GET("/:id/suggestions", itineraryHandler.GetTripSuggestions)
+				itineraries.GET("/:id/posts", postHandler.GetPostsByItinerary)
+				itineraries.POST("/:id/days/:dayId/suggestions", itineraryHandler.AddSuggestedPlace)
+				itineraries.GET("/:id/days/:dayId/route", routeHandler.GetDayRoute)
+				itineraries.GET("/:id/weather", weatherHandler.GetItineraryWeather)
+				itineraries.GET("/:id/budget", budgetHandler.GetItineraryBudget)
+				itineraries.POST("/:id/expenses", expenseHandler.AddExpense)
+				itineraries.GET("/:id/expenses", expenseHandler.GetExpenses)
+				itineraries.GET("/:id/expenses/summary", expenseHandler.GetExpenseSummary)
+				itineraries.DELETE("/:id/expenses/:expenseId", expenseHandler.DeleteExpense)
+				itineraries.GET("/:id/collaborators", invitationHandler.GetParticipants)
+				itineraries.DELETE("/:id/collaborators/:userId", invitationHandler.RemoveParticipant)
+				itineraries.GET("/:id/chat", conversationHandler.GetTripConversation)
+				itineraries.POST("/:id/invite", invitationHandler.Invite)
+				itineraries.GET("/:id/invitations", invitationHandler.GetItineraryInvitations)
+				itineraries.DELETE("/:id/invitations/:invitationId", invitationHandler.CancelInvitation)
+				itineraries.POST("/:id/photos/organize", photoHandler.OrganizePhotos)
+				itineraries.POST("/:id/photos/confirm", photoHandler.ConfirmPhotos)
+			}
+
+			// Viagens com datas reais seguindo um roteiro
+			trips := protected.Group("/trips")
+			{
+				trips.POST("/", tripHandler.CreateTrip)
+				trips.GET("/:id", tripHandler.GetTrip)
+				trips.PUT("/:id", tripHandler.UpdateTrip)
+				trips.DELETE("/:id", tripHandler.DeleteTrip)
+			}
+
+			// Busca de lugares para o editor de roteiros (chave da API fica no servidor)
+			places := protected.Group("/places")
+			places.Use(middleware.RateLimitMiddleware(cfg.PlacesConfig.AutocompleteRateLimit, time.Minute))
+			{
+				places.GET("/autocomplete", placeHandler.AutocompletePlaces)
+				places.GET("/details/:placeId", placeHandler.GetPlaceDetails)
+			}
+			// Avaliações da comunidade não consomem a cota da API de lugares
+			protected.GET("/places/:placeId/reviews", reviewHandler.GetPlaceReviews)
+
+			// Desafios e campanhas
+			challenges := protected.Group("/challenges")
+			{
+				challenges.GET("/", challengeHandler.GetActiveChallenges)
+				challenges.GET("/:id", challengeHandler.GetChallengeByID)
+				challenges.POST("/:id/enroll", challengeHandler.EnrollChallenge)
+				challenges.DELETE("/:id/enroll", challengeHandler.UnenrollChallenge)
+				challenges.GET("/:id/leaderboard", challengeHandler.GetLeaderboard)
+			}
+
+			// Mensagens diretas
+			conversations := protected.Group("/conversations")
+			{
+				conversations.GET("/", conversationHandler.GetConversations)
+				conversations.POST("/", conversationHandler.StartConversation)
+				conversations.PATCH("/:id", conversationHandler.UpdateConversation)
+				conversations.GET("/:id/messages", conversationHandler.GetMessages)
+				conversations.POST("/:id/messages", conversationHandler.SendMessage)
+				conversations.POST("/:id/messages/:messageId/report", moderationHandler.ReportMessage)
+				conversations.POST("/:id/read", conversationHandler.MarkConversationRead)
+				conversations.POST("/:id/typing", realtimeHandler.SendTyping)
+				conversations.GET("/:id/presence", realtimeHandler.GetConversationPresence)
+				conversations.POST("/:id/attachments", conversationHandler.UploadAttachment)
+				conversations.POST("/:id/members", conversationHandler.AddConversationMember)
+				conversations.DELETE("/:id/members/:userId", conversationHandler.RemoveConversationMember)
+			}
+
+			// Companheiros de viagem
+			travelBuddies := protected.Group("/travel-buddies")
+			{
+				travelBuddies.GET("/intents", travelBuddyHandler.GetMyIntents)
+				travelBuddies.POST("/intents", travelBuddyHandler.CreateIntent)
+				travelBuddies.PUT("/intents/:id", travelBuddyHandler.UpdateIntent)
+				travelBuddies.DELETE("/intents/:id", travelBuddyHandler.DeleteIntent)
+				travelBuddies.GET("/intents/:id/matches", travelBuddyHandler.GetSuggestions)
+				travelBuddies.POST("/intents/:id/interest", travelBuddyHandler.ExpressInterest)
+				travelBuddies.GET("/matches", travelBuddyHandler.GetMatches)
+			}
+
+			// Páginas de destino com promoções patrocinadas
+			destinations := protected.Group("/destinations")
+			{
+				destinations.GET("/:cityId", destinationHandler.GetDestinationPage)
+				destinations.POST("/:cityId/promotions", middleware.CompanyMiddleware(), destinationHandler.CreatePromotion)
+				destinations.GET("/promotions", middleware.CompanyMiddleware(), destinationHandler.GetMyPromotions)
+				destinations.PUT("/promotions/:id", middleware.CompanyMiddleware(), destinationHandler.UpdatePromotion)
+				destinations.DELETE("/promotions/:id", middleware.CompanyMiddleware(), destinationHandler.DeletePromotion)
+				destinations.GET("/promotions/:id/report", middleware.CompanyMiddleware(), destinationHandler.GetPromotionReport)
+				destinations.POST("/promotions/:id/checkout", middleware.CompanyMiddleware(), promotionPaymentHandler.CheckoutPromotion)
+				destinations.POST("/promotions/:id/confirm", middleware.CompanyMiddleware(), promotionPaymentHandler.ConfirmPromotionPayment)
+			}
+
+			// Marketplace de experiências com guias locais
+			experiences := protected.Group("/experiences")
+			{
+				experiences.GET("/", experienceHandler.SearchExperiences)
+				experiences.GET("/mine", middleware.CompanyMiddleware(), experienceHandler.GetMyExperiences)
+				experiences.POST("/", middleware.CompanyMiddleware(), experienceHandler.CreateExperience)
+				experiences.GET("/:id", experienceHandler.GetExperienceByID)
+				experiences.PUT("/:id", middleware.CompanyMiddleware(), experienceHandler.UpdateExperience)
+				experiences.DELETE("/:id", middleware.CompanyMiddleware(), experienceHandler.DeleteExperience)
+				experiences.GET("/:id/availability", experienceHandler.GetAvailability)
+				experiences.POST("/:id/availability", middleware.CompanyMiddleware(), experienceHandler.AddSlots)
+				experiences.DELETE("/:id/availability/:slotId", middleware.CompanyMiddleware(), experienceHandler.CancelSlot)
+				experiences.POST("/:id/bookings", experienceHandler.RequestBooking)
+			}
+
+			// Pedidos de reserva
+			bookings := protected.Group("/bookings")
+			{
+				bookings.GET("/", experienceHandler.GetMyBookings)
+				bookings.GET("/received", middleware.CompanyMiddleware(), experienceHandler.GetReceivedBookings)
+				bookings.PUT("/:id/status", experienceHandler.UpdateBookingStatus)
+			}
+
+			// Orçamento previsto x realizado das viagens
+			tripBudgets := protected.Group("/trips")
+			{
+				tripBudgets.GET("/:id/budget-report", tripBudgetHandler.GetBudgetReport)
+			}
+
+			// Lembretes antes da partida e lista de bagagem das viagens
+			tripReminders := protected.Group("/trips")
+			{
+				tripReminders.GET("/:id/reminders", tripReminderHandler.GetReminderSettings)
+				tripReminders.PUT("/:id/reminders", tripReminderHandler.UpdateReminderSettings)
+				tripReminders.GET("/:id/packing", tripReminderHandler.GetPackingList)
+				tripReminders.POST("/:id/packing", tripReminderHandler.AddPackingItem)
+				tripReminders.PATCH("/:id/packing/:itemId", tripReminderHandler.UpdatePackingItem)
+				tripReminders.DELETE("/:id/packing/:itemId", tripReminderHandler.DeletePackingItem)
+			}
+
+			// Apoio a criadores
+			tips := protected.Group("/tips")
+			{
+				tips.POST("/", tipHandler.CreateTip)
+				tips.GET("/sent", tipHandler.GetSentTips)
+				tips.GET("/received", tipHandler.GetReceivedTips)
+				tips.GET("/earnings", tipHandler.GetEarnings)
+				tips.POST("/:id/confirm", tipHandler.ConfirmTip)
+			}
+
+			// Plano guIA Plus
+			subscriptions := protected.Group("/subscriptions")
+			{
+				subscriptions.POST("/", subscriptionHandler.Subscribe)
+				subscriptions.GET("/", subscriptionHandler.GetSubscriptions)
+				subscriptions.GET("/status", subscriptionHandler.GetSubscriptionStatus)
+				subscriptions.POST("/:id/confirm", subscriptionHandler.ConfirmSubscription)
+			}
+
+			// Contas de recebimento e saques dos criadores
+			payouts := protected.Group("/payouts")
+			{
+				payouts.GET("/", ledgerHandler.GetPayouts)
+				payouts.POST("/", ledgerHandler.RequestPayout)
+				payouts.GET("/account", tipHandler.GetPayoutAccount)
+				payouts.POST("/account", tipHandler.SetupPayoutAccount)
+			}
+
+			// Livro-razão
+			ledger := protected.Group("/ledger")
+			{
+				ledger.GET("/balance", ledgerHandler.GetBalance)
+				ledger.GET("/entries", ledgerHandler.GetEntries)
 			}
 
 			// Mídia
@@ -124,6 +680,122 @@ func main() {
 				media.POST("/upload/multiple", mediaHandler.UploadMultiple)
 				media.DELETE("/delete", mediaHandler.DeleteMedia)
 				media.GET("/info", mediaHandler.GetMediaInfo)
+				media.GET("/:id", mediaHandler.GetMedia)
+				media.GET("/:id/status", transcodeHandler.GetMediaStatus)
+
+				// Uploads em partes (retomáveis) para arquivos grandes
+				media.POST("/uploads", uploadHandler.CreateUpload)
+				media.GET("/uploads/:id", uploadHandler.GetUpload)
+				media.PATCH("/uploads/:id", uploadHandler.AppendUpload)
+				media.POST("/uploads/:id/complete", uploadHandler.CompleteUpload)
+				media.DELETE("/uploads/:id", uploadHandler.AbortUpload)
+			}
+
+			// Comentários
+			comments := protected.Group("/comments")
+			{
+				comments.DELETE("/:id", commentHandler.DeleteComment)
+				comments.POST("/:id/report", reportHandler.ReportComment)
+			}
+
+			// Explorar
+			protected.GET("/explore", exploreHandler.GetExplore)
+
+			// Busca unificada
+			protected.GET("/search", searchHandler.Search)
+			protected.GET("/search/suggest", searchHandler.Suggest)
+			protected.POST("/search/saved", searchHandler.SaveSearch)
+			protected.GET("/search/saved", searchHandler.GetSavedSearches)
+			protected.DELETE("/search/saved/:id", searchHandler.DeleteSavedSearch)
+
+			// Recomendações
+			protected.GET("/recommendations", recommendationHandler.GetRecommendations)
+
+			// Stories
+			stories := protected.Group("/stories")
+			{
+				stories.GET("/", storyHandler.GetStories)
+				stories.POST("/", storyHandler.CreateStory)
+				stories.GET("/archive", storyHandler.GetArchivedStories)
+				stories.POST("/:id/view", storyHandler.ViewStory)
+				stories.GET("/:id/viewers", storyHandler.GetStoryViewers)
+				stories.DELETE("/:id", storyHandler.DeleteStory)
+			}
+
+			// Notificações
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("/", notificationHandler.GetNotifications)
+				notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+				notifications.POST("/read", notificationHandler.MarkNotificationsRead)
+				notifications.GET("/settings", notificationHandler.GetNotificationSettings)
+				notifications.PUT("/settings", notificationHandler.UpdateNotificationSettings)
+				notifications.GET("/quiet-hours", notificationHandler.GetQuietHours)
+				notifications.PUT("/quiet-hours", notificationHandler.UpdateQuietHours)
+				notifications.GET("/:id", notificationHandler.GetNotification)
+			}
+
+			// Administração
+			admin := protected.Group("/admin")
+			admin.Use(middleware.AdminMiddleware())
+			{
+				admin.GET("/users", adminHandler.GetUsers)
+				admin.GET("/users/:id", adminHandler.GetUser)
+				admin.POST("/users/:id/suspend", suspensionHandler.SuspendUser)
+				admin.POST("/users/:id/ban", suspensionHandler.BanUser)
+				admin.POST("/users/:id/unban", suspensionHandler.UnbanUser)
+				admin.GET("/appeals", suspensionHandler.GetAppeals)
+				admin.PUT("/appeals/:id", suspensionHandler.ReviewAppeal)
+				admin.GET("/verifications", verificationHandler.GetVerificationRequests)
+				admin.PUT("/verifications/:id", verificationHandler.ReviewVerification)
+				admin.GET("/challenges", challengeHandler.GetAllChallenges)
+				admin.POST("/challenges", challengeHandler.CreateChallenge)
+				admin.PUT("/challenges/:id", challengeHandler.UpdateChallenge)
+				admin.DELETE("/challenges/:id", challengeHandler.DeleteChallenge)
+				admin.POST("/tips/:id/refund", tipHandler.RefundTip)
+				admin.POST("/payouts/process", ledgerHandler.ProcessPayouts)
+				admin.GET("/ledger/reconciliation", ledgerHandler.GetReconciliation)
+				admin.GET("/fraud/rules", fraudHandler.GetRules)
+				admin.PUT("/fraud/rules/:code", fraudHandler.UpdateRule)
+				admin.GET("/fraud/reviews", fraudHandler.GetReviewQueue)
+				admin.POST("/fraud/reviews/:id", fraudHandler.ReviewCheck)
+				admin.GET("/reports", reportHandler.GetReportCases)
+				admin.GET("/reports/:id", reportHandler.GetReportCase)
+				admin.PUT("/reports/:id", reportHandler.ResolveReportCase)
+				admin.PUT("/reports/:id/assignee", reportHandler.AssignReportCase)
+				admin.DELETE("/reports/:id/assignee", reportHandler.UnassignReportCase)
+				admin.POST("/posts/:id/restore", moderationHandler.RestorePost)
+				admin.DELETE("/posts/:id", moderationHandler.RemovePost)
+				admin.POST("/itineraries/:id/restore", moderationHandler.RestoreItinerary)
+				admin.DELETE("/itineraries/:id", moderationHandler.RemoveItinerary)
+				admin.GET("/reports/messages", moderationHandler.GetMessageReports)
+				admin.GET("/reports/messages/:id", moderationHandler.GetMessageReport)
+				admin.PUT("/reports/messages/:id", moderationHandler.ResolveMessageReport)
+				admin.DELETE("/messages/:id", moderationHandler.RemoveMessage)
+				admin.POST("/moderation/bulk", moderationHandler.BulkModeration)
+				admin.GET("/moderation/jobs/:id", moderationHandler.GetBulkModerationJob)
+				admin.GET("/moderation/actions", moderationHandler.GetModerationActions)
+				admin.GET("/moderation/media", moderationHandler.GetFlaggedMedia)
+				admin.POST("/moderation/media/:id/approve", moderationHandler.ApproveMedia)
+				admin.POST("/moderation/media/:id/reject", moderationHandler.RejectMedia)
+				admin.GET("/risk-assessments", riskHandler.GetRiskAssessments)
+				admin.GET("/restrictions", complianceHandler.GetRestrictions)
+				admin.POST("/restrictions", complianceHandler.RestrictContent)
+				admin.DELETE("/restrictions/:id", complianceHandler.RemoveRestriction)
+				admin.GET("/compliance/report", complianceHandler.GetTransparencyReport)
+				admin.GET("/support/traces/:traceId", requestLogHandler.GetRequestLogs)
+				admin.GET("/webhooks", webhookHandler.GetWebhookEvents)
+				admin.POST("/webhooks/:id/redeliver", webhookHandler.RedeliverWebhookEvent)
+				admin.GET("/client-errors/groups", clientErrorHandler.GetClientErrorGroups)
+				admin.GET("/client-errors/groups/:fingerprint", clientErrorHandler.GetClientErrorReports)
+				admin.GET("/client-errors/endpoints", clientErrorHandler.GetClientErrorEndpoints)
+				admin.GET("/destination-partners", destinationHandler.GetDestinationPartners)
+				admin.POST("/destination-partners", destinationHandler.AddDestinationPartner)
+				admin.DELETE("/destination-partners/:id", destinationHandler.RemoveDestinationPartner)
+				admin.GET("/featured", featuredHandler.GetFeaturedItineraries)
+				admin.PUT("/featured/order", featuredHandler.ReorderFeaturedItineraries)
+				admin.PUT("/itineraries/:id/feature", featuredHandler.FeatureItinerary)
+				admin.DELETE("/itineraries/:id/feature", featuredHandler.UnfeatureItinerary)
 			}
 		}
 	}
@@ -1,19 +1,53 @@
 package config
 
 import (
+	"log"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Ulpio/guIA-backend/internal/services"
 )
 
 type Config struct {
-	DatabaseURL string
-	JWTSecret   string
-	Port        string
-	Environment string
-	MediaConfig *services.MediaConfig
+	DatabaseURL       string
+	JWTSecret         string
+	Port              string
+	Environment       string
+	MediaConfig       *services.MediaConfig
+	GeoDataPath       string
+	BillingConfig     *services.BillingConfig
+	TranslationConfig *services.TranslationConfig
+	WebhookConfig     *services.WebhookConfig
+	ClientErrorConfig *services.ClientErrorConfig
+	CurrencyConfig    *services.CurrencyConfig
+	ExportConfig      *services.ExportConfig
+	TranscodeConfig   *services.TranscodeConfig
+	PlacesConfig      *services.PlacesConfig
+	RoutingConfig     *services.RoutingConfig
+	WeatherConfig     *services.WeatherConfig
+	EmailConfig       *services.EmailConfig
+	AttachmentConfig  *services.AttachmentConfig
+	ProfanityConfig   *services.ProfanityConfig
+	SpamConfig        *services.SpamConfig
+	GeoIPConfig       *services.GeoIPConfig
+	// Proxies/CDN à frente da API (IPs ou CIDRs), os únicos autorizados a
+	// informar o IP do cliente e o país; vazio não confia em nenhum
+	TrustedProxies []string
+	// Denúncias pendentes que ocultam um post automaticamente (0 desativa)
+	PostReportHideThreshold int
+	// Denúncias pendentes que retiram um roteiro das listagens (0 desativa)
+	ItineraryReportHideThreshold int
+	// Denúncias pendentes que ocultam um comentário ou uma avaliação (0 desativa)
+	CommentReportHideThreshold int
+	RatingReportHideThreshold  int
+	// Pontuação de risco de automação que bloqueia cadastros e posts (0 apenas registra)
+	BotRiskBlockThreshold int
+	// Dias que os registros das requisições com erro ficam disponíveis ao suporte
+	RequestLogRetentionDays int
+	// Endereço das páginas públicas de roteiros compartilhados (o slug é acrescentado)
+	ShareBaseURL string
 }
 
 func Load() *Config {
@@ -23,12 +57,133 @@ func Load() *Config {
 		Port:        getEnv("PORT", "8080"),
 		Environment: getEnv("ENVIRONMENT", "development"),
 		MediaConfig: loadMediaConfig(),
+		GeoDataPath: getEnv("GEONAMES_DATA_PATH", ""), // diretório com os dumps do GeoNames (opcional)
+		BillingConfig: &services.BillingConfig{
+			Provider:           getEnv("BILLING_PROVIDER", "stripe"),
+			StripeSecretKey:    getEnv("STRIPE_SECRET_KEY", ""),
+			StripeAPIURL:       getEnv("STRIPE_API_URL", ""),
+			PlatformFeePercent: getEnvAsInt("TIP_PLATFORM_FEE_PERCENT", 10),
+			MinTipAmount:       int64(getEnvAsInt("TIP_MIN_AMOUNT_CENTS", 100)),
+			MaxTipAmount:       int64(getEnvAsInt("TIP_MAX_AMOUNT_CENTS", 100000)),
+			MinPayoutAmount:    int64(getEnvAsInt("PAYOUT_MIN_AMOUNT_CENTS", 1000)),
+			PayoutDelayDays:    getEnvAsInt("PAYOUT_DELAY_DAYS", 7),
+
+			PromotionDailyPrice: int64(getEnvAsInt("PROMOTION_DAILY_PRICE_CENTS", 5000)),
+			PromotionCurrency:   getEnv("PROMOTION_CURRENCY", "BRL"),
+
+			SubscriptionMonthlyPrice: int64(getEnvAsInt("SUBSCRIPTION_MONTHLY_PRICE_CENTS", 1990)),
+			SubscriptionCurrency:     getEnv("SUBSCRIPTION_CURRENCY", "BRL"),
+		},
+		PostReportHideThreshold:      getEnvAsInt("POST_REPORT_HIDE_THRESHOLD", 5),
+		ItineraryReportHideThreshold: getEnvAsInt("ITINERARY_REPORT_HIDE_THRESHOLD", 5),
+		CommentReportHideThreshold:   getEnvAsInt("COMMENT_REPORT_HIDE_THRESHOLD", 5),
+		RatingReportHideThreshold:    getEnvAsInt("RATING_REPORT_HIDE_THRESHOLD", 5),
+		BotRiskBlockThreshold:        getEnvAsInt("BOT_RISK_BLOCK_THRESHOLD", 80),
+		RequestLogRetentionDays:      getEnvAsInt("REQUEST_LOG_RETENTION_DAYS", 14),
+		ShareBaseURL:                 getEnv("SHARE_BASE_URL", ""),
+		TranslationConfig: &services.TranslationConfig{
+			Provider: getEnv("TRANSLATION_PROVIDER", ""),
+			APIKey:   getEnv("TRANSLATION_API_KEY", ""),
+			APIURL:   getEnv("TRANSLATION_API_URL", ""),
+		},
+		WebhookConfig: &services.WebhookConfig{
+			StripeSecret:      getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			TranscodingSecret: getEnv("TRANSCODING_WEBHOOK_SECRET", ""),
+			PushSecret:        getEnv("PUSH_WEBHOOK_SECRET", ""),
+			ToleranceSeconds:  getEnvAsInt("WEBHOOK_TOLERANCE_SECONDS", 300),
+			MaxAttempts:       getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		},
+		ClientErrorConfig: &services.ClientErrorConfig{
+			SamplePercent:      getEnvAsInt("CLIENT_ERROR_SAMPLE_PERCENT", 100),
+			RateLimitPerMinute: getEnvAsInt("CLIENT_ERROR_RATE_LIMIT", 30),
+			RetentionDays:      getEnvAsInt("CLIENT_ERROR_RETENTION_DAYS", 30),
+		},
+		CurrencyConfig: &services.CurrencyConfig{
+			RatesURL: getEnv("EXCHANGE_RATES_URL", "https://open.er-api.com/v6/latest/USD"),
+		},
+		ExportConfig: &services.ExportConfig{
+			PDFRendererURL:   getEnv("PDF_RENDERER_URL", ""),
+			StaticMapURL:     getEnv("STATIC_MAP_URL", ""),
+			SyncMaxLocations: getEnvAsInt("EXPORT_SYNC_MAX_LOCATIONS", 15),
+			RetentionDays:    getEnvAsInt("EXPORT_RETENTION_DAYS", 7),
+		},
+		TranscodeConfig: &services.TranscodeConfig{
+			FFmpegPath:     getEnv("MEDIA_FFMPEG_PATH", ""),
+			FFprobePath:    getEnv("MEDIA_FFPROBE_PATH", ""),
+			Renditions:     parseHLSRenditions(getEnv("MEDIA_HLS_RENDITIONS", "")),
+			SegmentSeconds: getEnvAsInt("MEDIA_HLS_SEGMENT_SECONDS", 6),
+		},
+		PlacesConfig: &services.PlacesConfig{
+			Provider:              getEnv("PLACES_PROVIDER", ""),
+			APIKey:                getEnv("PLACES_API_KEY", ""),
+			APIURL:                getEnv("PLACES_API_URL", ""),
+			CacheDays:             getEnvAsInt("PLACES_CACHE_DAYS", 30),
+			FallbackURL:           getEnv("PLACES_FALLBACK_URL", ""),
+			AutocompleteRateLimit: getEnvAsInt("PLACES_AUTOCOMPLETE_RATE_LIMIT", 60),
+		},
+		RoutingConfig: &services.RoutingConfig{
+			Provider:  getEnv("ROUTING_PROVIDER", ""),
+			APIKey:    getEnv("ROUTING_API_KEY", ""),
+			APIURL:    getEnv("ROUTING_API_URL", ""),
+			CacheDays: getEnvAsInt("ROUTING_CACHE_DAYS", 30),
+		},
+		WeatherConfig: &services.WeatherConfig{
+			Provider:   getEnv("WEATHER_PROVIDER", ""),
+			APIKey:     getEnv("WEATHER_API_KEY", ""),
+			APIURL:     getEnv("WEATHER_API_URL", ""),
+			CacheHours: getEnvAsInt("WEATHER_CACHE_HOURS", 3),
+		},
+		EmailConfig: &services.EmailConfig{
+			Provider:       getEnv("EMAIL_PROVIDER", ""),
+			FromAddress:    getEnv("EMAIL_FROM_ADDRESS", ""),
+			FromName:       getEnv("EMAIL_FROM_NAME", "guIA"),
+			AppURL:         getEnv("EMAIL_APP_URL", "http://localhost:3000"),
+			MaxAttempts:    getEnvAsInt("EMAIL_MAX_ATTEMPTS", 6),
+			DigestWeekday:  time.Weekday(getEnvAsInt("EMAIL_DIGEST_WEEKDAY", int(time.Monday))),
+			DigestHour:     getEnvAsInt("EMAIL_DIGEST_HOUR", 9),
+			SMTPHost:       getEnv("SMTP_HOST", ""),
+			SMTPPort:       getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+			SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+			SendGridAPIURL: getEnv("SENDGRID_API_URL", ""),
+			AWSRegion:      getEnv("AWS_REGION", "us-east-1"),
+			AWSAccessKey:   getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretKey:   getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		},
+		AttachmentConfig: &services.AttachmentConfig{
+			BaseURL: getEnv("ATTACHMENT_URL_BASE", "http://localhost:8080"),
+			Secret:  getEnv("ATTACHMENT_URL_SECRET", ""),
+			URLTTL:  time.Duration(getEnvAsInt("ATTACHMENT_URL_TTL_MINUTES", 15)) * time.Minute,
+		},
+		// Filtro de palavrões dos posts, comentários e avaliações ("off",
+		// "mask", "flag" ou "reject")
+		ProfanityConfig: &services.ProfanityConfig{
+			ProfanityAction: services.ProfanityAction(getEnv("PROFANITY_ACTION", "mask")),
+			SlurAction:      services.ProfanityAction(getEnv("SLUR_ACTION", "reject")),
+			ExtraWords:      parseModerationLabels(getEnv("PROFANITY_EXTRA_WORDS", "")),
+			AllowedWords:    parseModerationLabels(getEnv("PROFANITY_ALLOWED_WORDS", "")),
+		},
+		// Detecção de spam nos posts e follows (0 desativa cada sinal)
+		SpamConfig: &services.SpamConfig{
+			LimitThreshold:   getEnvAsInt("SPAM_LIMIT_THRESHOLD", 60),
+			LimitHours:       getEnvAsInt("SPAM_LIMIT_HOURS", 24),
+			DuplicateLimit:   getEnvAsInt("SPAM_DUPLICATE_POST_LIMIT", 3),
+			MaxLinks:         getEnvAsInt("SPAM_MAX_LINKS", 3),
+			FollowBurstLimit: getEnvAsInt("SPAM_FOLLOW_BURST_LIMIT", 100),
+			FollowChurnLimit: getEnvAsInt("SPAM_FOLLOW_CHURN_LIMIT", 20),
+		},
+		GeoIPConfig: &services.GeoIPConfig{
+			CountryHeader: getEnv("GEOIP_COUNTRY_HEADER", ""),
+			DatabasePath:  getEnv("GEOIP_DATABASE_PATH", ""),
+		},
+		TrustedProxies: parseList(getEnv("TRUSTED_PROXIES", "")),
 	}
 }
 
 func loadMediaConfig() *services.MediaConfig {
 	// Configurações básicas
-	storageType := getEnv("MEDIA_STORAGE_TYPE", "local") // "local" ou "s3"
+	storageType := getEnv("MEDIA_STORAGE_TYPE", "local") // "local", "s3", "gcs" ou "azure"
 	localPath := getEnv("MEDIA_LOCAL_PATH", "./uploads")
 	baseURL := getEnv("MEDIA_BASE_URL", "http://localhost:8080/uploads")
 
@@ -36,17 +191,65 @@ func loadMediaConfig() *services.MediaConfig {
 	maxFileSizeMB := getEnvAsInt("MEDIA_MAX_FILE_SIZE_MB", 50)
 	maxFileSize := int64(maxFileSizeMB * 1024 * 1024)
 
+	// Uploads em partes (retomáveis), para vídeos grandes
+	maxChunkedUploadSizeMB := getEnvAsInt("MEDIA_MAX_CHUNKED_UPLOAD_SIZE_MB", 200)
+	uploadSessionHours := getEnvAsInt("MEDIA_UPLOAD_SESSION_HOURS", 24)
+	maxOpenUploads := getEnvAsInt("MEDIA_MAX_OPEN_UPLOADS", 5)
+	maxPendingUploadMB := getEnvAsInt("MEDIA_MAX_PENDING_UPLOAD_MB", 1024)
+
+	// Limite de pixels das imagens, contra bombas de descompressão
+	maxImageMegapixels := getEnvAsInt("MEDIA_MAX_IMAGE_MEGAPIXELS", 50)
+
 	// Extensões permitidas
 	allowedImageExt := getEnvAsSlice("MEDIA_ALLOWED_IMAGE_EXT", ".jpg,.jpeg,.png,.gif,.webp")
 	allowedVideoExt := getEnvAsSlice("MEDIA_ALLOWED_VIDEO_EXT", ".mp4,.avi,.mov,.wmv,.webm")
 
 	config := &services.MediaConfig{
-		StorageType:     storageType,
-		LocalPath:       localPath,
-		BaseURL:         baseURL,
-		MaxFileSize:     maxFileSize,
-		AllowedImageExt: allowedImageExt,
-		AllowedVideoExt: allowedVideoExt,
+		StorageType:      storageType,
+		LocalPath:        localPath,
+		PrivateLocalPath: getEnv("MEDIA_PRIVATE_LOCAL_PATH", "./private-uploads"),
+		BaseURL:          baseURL,
+		MaxFileSize:      maxFileSize,
+		AllowedImageExt:  allowedImageExt,
+		AllowedVideoExt:  allowedVideoExt,
+
+		MaxChunkedUploadSize: int64(maxChunkedUploadSizeMB) * 1024 * 1024,
+		UploadTempPath:       getEnv("MEDIA_UPLOAD_TMP_PATH", ""),
+		UploadSessionTTL:     time.Duration(uploadSessionHours) * time.Hour,
+
+		MaxOpenUploadSessions: maxOpenUploads,
+		MaxPendingUploadBytes: int64(maxPendingUploadMB) * 1024 * 1024,
+
+		MaxImagePixels: int64(maxImageMegapixels) * 1_000_000,
+
+		ImageVariants:   parseImageVariants(getEnv("MEDIA_IMAGE_VARIANTS", "")),
+		WebPEncoderPath: getEnv("MEDIA_CWEBP_PATH", ""),
+
+		ExtractPhotoMetadata: getEnv("MEDIA_EXTRACT_PHOTO_METADATA", "true") == "true",
+
+		// Moderação automática das imagens enviadas ("rekognition" ou "http")
+		Moderation: &services.ImageModerationConfig{
+			Provider:      getEnv("MEDIA_MODERATION_PROVIDER", ""),
+			MinConfidence: float64(getEnvAsInt("MEDIA_MODERATION_MIN_CONFIDENCE", 80)),
+			Labels:        parseModerationLabels(getEnv("MEDIA_MODERATION_LABELS", "")),
+			AWSRegion:     getEnv("AWS_REGION", "us-east-1"),
+			AWSAccessKey:  getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretKey:  getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			HTTPURL:       getEnv("MEDIA_MODERATION_URL", ""),
+			HTTPToken:     getEnv("MEDIA_MODERATION_TOKEN", ""),
+		},
+
+		// Invalidação da CDN ao apagar arquivos ("cloudfront" ou "cloudflare")
+		CDN: &services.CDNConfig{
+			Provider:           getEnv("MEDIA_CDN_INVALIDATION", ""),
+			MaxBatchSize:       getEnvAsInt("MEDIA_CDN_INVALIDATION_BATCH_SIZE", 1000),
+			DistributionID:     getEnv("AWS_CLOUDFRONT_DISTRIBUTION_ID", ""),
+			AWSRegion:          getEnv("AWS_REGION", "us-east-1"),
+			AWSAccessKey:       getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretKey:       getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			CloudflareZoneID:   getEnv("CLOUDFLARE_ZONE_ID", ""),
+			CloudflareAPIToken: getEnv("CLOUDFLARE_API_TOKEN", ""),
+		},
 	}
 
 	// Configurações AWS S3 (se necessário)
@@ -57,12 +260,129 @@ func loadMediaConfig() *services.MediaConfig {
 			AccessKey: getEnv("AWS_ACCESS_KEY_ID", ""),
 			SecretKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
 			CDNUrl:    getEnv("AWS_CLOUDFRONT_URL", ""), // opcional
+
+			// Storages compatíveis com S3 (MinIO etc.)
+			Endpoint:       getEnv("AWS_S3_ENDPOINT", ""),
+			ForcePathStyle: getEnv("AWS_S3_FORCE_PATH_STYLE", "false") == "true",
+		}
+	}
+
+	// Configurações Google Cloud Storage (se necessário)
+	if storageType == "gcs" {
+		config.GCSConfig = &services.GCSConfig{
+			Bucket:          getEnv("GCS_BUCKET", ""),
+			CredentialsFile: getEnv("GOOGLE_APPLICATION_CREDENTIALS", ""), // vazio usa a conta da instância
+			CDNUrl:          getEnv("GCS_CDN_URL", ""),                    // opcional
+			PrivateBucket:   getEnv("GCS_PRIVATE_BUCKET", ""),             // anexos das conversas
+		}
+	}
+
+	// Configurações Azure Blob Storage (se necessário)
+	if storageType == "azure" {
+		config.AzureConfig = &services.AzureConfig{
+			AccountName:      getEnv("AZURE_STORAGE_ACCOUNT", ""),
+			AccountKey:       getEnv("AZURE_STORAGE_KEY", ""),
+			Container:        getEnv("AZURE_STORAGE_CONTAINER", ""),
+			PrivateContainer: getEnv("AZURE_STORAGE_PRIVATE_CONTAINER", ""), // anexos das conversas
+			CDNUrl:           getEnv("AZURE_CDN_URL", ""),                   // opcional
+			Endpoint:         getEnv("AZURE_STORAGE_ENDPOINT", ""),          // opcional (Azurite)
 		}
 	}
 
 	return config
 }
 
+// parseModerationLabels lê as categorias bloqueadas separadas por vírgula;
+// vazio usa as padrão
+func parseModerationLabels(value string) []string {
+	return parseList(value)
+}
+
+// parseList lê valores separados por vírgula, ignorando os vazios
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseImageVariants lê as variantes no formato "nome:tamanho[:crop][:webp]"
+// separadas por vírgula; vazio usa as padrão e "none" desativa
+func parseImageVariants(value string) []services.ImageVariant {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if value == "none" {
+		return []services.ImageVariant{}
+	}
+
+	variants := []services.ImageVariant{}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 {
+			log.Printf("Variante de imagem inválida ignorada: %q", entry)
+			continue
+		}
+		size, err := strconv.Atoi(parts[1])
+		if err != nil || size <= 0 || parts[0] == "" {
+			log.Printf("Variante de imagem inválida ignorada: %q", entry)
+			continue
+		}
+
+		variant := services.ImageVariant{Name: parts[0], Size: size}
+		for _, option := range parts[2:] {
+			switch option {
+			case "crop":
+				variant.Crop = true
+			case "webp":
+				variant.Format = "webp"
+			default:
+				log.Printf("Opção %q da variante %s ignorada", option, variant.Name)
+			}
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+// parseHLSRenditions lê as qualidades no formato "altura:kbps_video:kbps_audio"
+// separadas por vírgula; vazio usa as padrão
+func parseHLSRenditions(value string) []services.HLSRendition {
+	var renditions []services.HLSRendition
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		values := make([]int, len(parts))
+		valid := len(parts) == 3
+		for i, part := range parts {
+			number, err := strconv.Atoi(part)
+			if err != nil || number <= 0 {
+				valid = false
+			}
+			values[i] = number
+		}
+		if !valid {
+			log.Printf("Qualidade HLS inválida ignorada: %q", entry)
+			continue
+		}
+
+		renditions = append(renditions, services.HLSRendition{
+			Height:       values[0],
+			VideoBitrate: values[1],
+			AudioBitrate: values[2],
+		})
+	}
+	return renditions
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -31,7 +31,7 @@ func Connect(databaseURL string) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.User{},
 		&models.Post{},
 		&models.PostLike{},
@@ -40,6 +40,101 @@ func Migrate(db *gorm.DB) error {
 		&models.ItineraryDay{},
 		&models.ItineraryLocation{},
 		&models.ItineraryRating{},
+		&models.ItineraryLike{},
+		&models.ItineraryRatingVote{},
 		&models.Follow{},
+		&models.UserBlock{},
+		&models.GeoCountry{},
+		&models.GeoState{},
+		&models.GeoCity{},
+		&models.Challenge{},
+		&models.ChallengeEnrollment{},
+		&models.ChallengeContribution{},
+		&models.UserBadge{},
+		&models.Conversation{},
+		&models.ConversationParticipant{},
+		&models.Message{},
+		&models.TravelIntent{},
+		&models.TravelBuddyInterest{},
+		&models.TravelMatch{},
+		&models.Experience{},
+		&models.ExperienceSlot{},
+		&models.BookingRequest{},
+		&models.Tip{},
+		&models.PayoutAccount{},
+		&models.LedgerAccount{},
+		&models.LedgerTransaction{},
+		&models.LedgerEntry{},
+		&models.Payout{},
+		&models.Subscription{},
+		&models.FraudRule{},
+		&models.FraudCheck{},
+		&models.ReportCase{},
+		&models.Report{},
+		&models.MessageReport{},
+		&models.ItineraryCompletion{},
+		&models.LocationCheckIn{},
+		&models.LocationReview{},
+		&models.TripInvitation{},
+		&models.PostEvent{},
+		&models.Media{},
+		&models.UploadSession{},
+		&models.TranscodeJob{},
+		&models.PostTranslation{},
+		&models.Notification{},
+		&models.TripReminderSetting{},
+		&models.TripPackingItem{},
+		&models.NotificationActor{},
+		&models.NotificationPreference{},
+		&models.QuietHours{},
+		&models.YearReview{},
+		&models.Story{},
+		&models.StoryView{},
+		&models.FeedSettings{},
+		&models.ModerationAction{},
+		&models.BulkModerationJob{},
+		&models.RiskAssessment{},
+		&models.ContentRestriction{},
+		&models.ComplianceEnforcement{},
+		&models.Collection{},
+		&models.CollectionItem{},
+		&models.RequestLog{},
+		&models.WebhookEvent{},
+		&models.EmailMessage{},
+		&models.ClientErrorReport{},
+		&models.DestinationPartner{},
+		&models.DestinationPromotion{},
+		&models.PromotionImpression{},
+		&models.PlatformStats{},
+		&models.ExchangeRate{},
+		&models.ItineraryExport{},
+		&models.PlaceDetails{},
+		&models.RouteCache{},
+		&models.TemplateUsage{},
+		&models.ItineraryCollaborator{},
+		&models.Expense{},
+		&models.Trip{},
+		&models.WeatherForecast{},
+		&models.ItineraryShareLink{},
+		&models.SearchTerm{},
+		&models.Hashtag{},
+		&models.SavedSearch{},
+		&models.Recommendation{},
+		&models.VerificationRequest{},
+		&models.AccountAppeal{},
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := migrateReports(db); err != nil {
+		return err
+	}
+	if err := migrateSearch(db); err != nil {
+		return err
+	}
+	if err := migrateSuspensions(db); err != nil {
+		return err
+	}
+	return migrateGeo(db)
 }
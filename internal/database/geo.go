@@ -0,0 +1,37 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// geoPointTables têm colunas latitude/longitude e recebem a coluna geo_point
+// para as buscas por raio e por área
+var geoPointTables = []string{"posts", "itinerary_locations"}
+
+// migrateGeo habilita o PostGIS e cria as colunas geo_point (geography,
+// gerada a partir da latitude e da longitude) com índices GiST. Coordenadas
+// fora do intervalo válido ficam sem ponto
+func migrateGeo(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS postgis").Error; err != nil {
+		return err
+	}
+
+	for _, table := range geoPointTables {
+		if err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS geo_point geography(Point, 4326)
+			GENERATED ALWAYS AS (CASE WHEN latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180
+				THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography END) STORED`,
+			table,
+		)).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS idx_%s_geo_point ON %s USING GIST (geo_point)",
+			table, table,
+		)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
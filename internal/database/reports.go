@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// legacyReportTables são as tabelas de denúncias de posts e roteiros
+// anteriores à fila unificada de casos
+var legacyReportTables = []struct {
+	table       string
+	column      string
+	targetTable string
+	targetType  models.ReportTargetType
+}{
+	{"post_reports", "post_id", "posts", models.ReportTargetPost},
+	{"itinerary_reports", "itinerary_id", "itineraries", models.ReportTargetItinerary},
+}
+
+// migrateReports move as denúncias antigas para report_cases e reports,
+// agrupando por alvo e status (as pendentes formam o caso aberto do alvo), e
+// apaga as tabelas antigas. Alvos já excluídos definitivamente ficam com
+// dono 0
+func migrateReports(db *gorm.DB) error {
+	for _, legacy := range legacyReportTables {
+		if !db.Migrator().HasTable(legacy.table) {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf(`INSERT INTO report_cases
+				(target_type, target_id, target_owner_id, status, reports_count, hidden_at,
+					resolved_by_id, resolution_note, resolved_at, created_at, updated_at)
+				SELECT ?, r.%[2]s, COALESCE(MAX(t.author_id), 0), r.status, COUNT(*),
+					CASE WHEN r.status = ? THEN MAX(t.hidden_at) END,
+					(array_agg(r.resolved_by_id ORDER BY r.resolved_at DESC NULLS LAST))[1],
+					COALESCE((array_agg(r.resolution_note ORDER BY r.resolved_at DESC NULLS LAST))[1], ''),
+					MAX(r.resolved_at), MIN(r.created_at), COALESCE(MAX(r.resolved_at), MAX(r.created_at))
+				FROM %[1]s r
+				LEFT JOIN %[3]s t ON t.id = r.%[2]s
+				GROUP BY r.%[2]s, r.status`,
+				legacy.table, legacy.column, legacy.targetTable,
+			), legacy.targetType, models.ReportStatusPending).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Exec(fmt.Sprintf(`INSERT INTO reports
+				(case_id, target_type, target_id, reporter_id, reason, details, created_at)
+				SELECT c.id, c.target_type, c.target_id, r.reporter_id, r.reason, r.details, r.created_at
+				FROM %[1]s r
+				JOIN report_cases c ON c.target_type = ? AND c.target_id = r.%[2]s AND c.status = r.status`,
+				legacy.table, legacy.column,
+			), legacy.targetType).Error; err != nil {
+				return err
+			}
+
+			return tx.Migrator().DropTable(legacy.table)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
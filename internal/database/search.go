@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchDictionaries são os idiomas em que o texto é indexado; o conteúdo do
+// app mistura português e inglês
+var searchDictionaries = []string{"portuguese", "english"}
+
+// searchField é uma coluna do documento de busca e seu peso no ts_rank (A é
+// o mais relevante)
+type searchField struct {
+	column string
+	weight string
+}
+
+// searchDocuments define, por tabela, o que entra na coluna search_vector
+var searchDocuments = []struct {
+	table  string
+	fields []searchField
+}{
+	{"posts", []searchField{{"content", "A"}, {"location", "B"}}},
+	{"itineraries", []searchField{{"title", "A"}, {"city", "B"}, {"country", "B"}, {"description", "C"}}},
+	{"users", []searchField{{"username", "A"}, {"first_name", "A"}, {"last_name", "A"}, {"company_name", "B"}}},
+}
+
+// Índices de trigramas (pg_trgm): os do autocomplete, que busca por prefixo
+// com LIKE, e o da busca aproximada de usuários, que compara o username e o
+// nome sem acentos por similaridade (a expressão precisa ser idêntica à de
+// repositories.userSearchName)
+var trigramIndexes = []struct {
+	name       string
+	table      string
+	expression string
+}{
+	{"idx_users_username_trgm", "users", "LOWER(username)"},
+	{"idx_hashtags_tag_trgm", "hashtags", "tag"},
+	{"idx_users_name_trgm", "users", "search_unaccent(lower(username || ' ' || coalesce(first_name, '') || ' ' || coalesce(last_name, '')))"},
+}
+
+// unaccent não é IMMUTABLE (depende do dicionário configurado), então não
+// pode ser usada em índices; search_unaccent fixa o dicionário
+const searchUnaccentFunction = `CREATE OR REPLACE FUNCTION search_unaccent(text) RETURNS text AS
+$$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT`
+
+// migrateSearch cria as colunas search_vector (tsvector gerado a partir do
+// documento) e seus índices GIN. Por ser uma coluna gerada, o Postgres a
+// calcula para as linhas existentes ao criá-la e a mantém a cada escrita.
+// Mudar o documento de uma tabela exige remover a coluna antes
+func migrateSearch(db *gorm.DB) error {
+	for _, document := range searchDocuments {
+		var parts []string
+		for _, field := range document.fields {
+			for _, dictionary := range searchDictionaries {
+				parts = append(parts, fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%s, '')), '%s')",
+					dictionary, field.column, field.weight))
+			}
+		}
+
+		if err := db.Exec(fmt.Sprintf(
+			"ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%s) STORED",
+			document.table, strings.Join(parts, " || "),
+		)).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS idx_%s_search_vector ON %s USING GIN (search_vector)",
+			document.table, document.table,
+		)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS unaccent").Error; err != nil {
+		return err
+	}
+	if err := db.Exec(searchUnaccentFunction).Error; err != nil {
+		return err
+	}
+	for _, index := range trigramIndexes {
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s gin_trgm_ops)",
+			index.name, index.table, index.expression,
+		)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// migrateSuspensions marca como banidas as contas desativadas por um
+// banimento anterior aos campos de suspensão, usando a última decisão de
+// ban/unban da auditoria; as demais contas inativas foram desativadas pelo
+// próprio usuário
+func migrateSuspensions(db *gorm.DB) error {
+	return db.Exec(`UPDATE users u SET suspended_at = a.created_at
+		FROM (
+			SELECT DISTINCT ON (target_id) target_id, action, created_at
+			FROM moderation_actions
+			WHERE target_type = ? AND action IN (?, ?)
+			ORDER BY target_id, created_at DESC, id DESC
+		) a
+		WHERE a.target_id = u.id AND a.action = ? AND u.is_active = false AND u.suspended_at IS NULL`,
+		models.ModerationTargetUser, models.ModerationActionBanUser, models.ModerationActionUnbanUser,
+		models.ModerationActionBanUser,
+	).Error
+}
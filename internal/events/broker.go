@@ -0,0 +1,77 @@
+package events
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+)
+
+// Broker é o transporte de um barramento externo (NATS, RabbitMQ): publica e
+// assina mensagens por assunto. Um adaptador para o cliente do broker basta
+// para trocar o InProcessBus pelo BrokerBus sem mudar os serviços
+type Broker interface {
+	Publish(subject string, payload []byte) error
+	Subscribe(subject string, handler func(payload []byte)) error
+}
+
+// Prefixo dos assuntos no broker; cada tipo de evento tem o seu
+// ("guia.events.post.liked")
+const brokerSubjectPrefix = "guia.events."
+
+// BrokerBus publica os eventos em JSON no broker e entrega aos assinantes
+// desta instância o que chega dele, com as mesmas garantias do InProcessBus
+// (goroutine por assinante e recuperação de pânicos)
+type BrokerBus struct {
+	broker Broker
+	local  BusInterface
+
+	mu         sync.Mutex
+	subscribed map[EventType]bool
+}
+
+func NewBrokerBus(broker Broker) BusInterface {
+	return &BrokerBus{
+		broker:     broker,
+		local:      NewInProcessBus(),
+		subscribed: make(map[EventType]bool),
+	}
+}
+
+func (b *BrokerBus) Publish(event Event) {
+	stamp(&event)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Falha ao serializar evento %s: %v", event.Type, err)
+		return
+	}
+	if err := b.broker.Publish(brokerSubjectPrefix+string(event.Type), payload); err != nil {
+		log.Printf("Falha ao publicar evento %s no broker: %v", event.Type, err)
+	}
+}
+
+// Subscribe registra o assinante local e, na primeira vez que o tipo é
+// assinado, passa a receber o assunto do broker
+func (b *BrokerBus) Subscribe(eventType EventType, handler Handler) {
+	b.local.Subscribe(eventType, handler)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribed[eventType] {
+		return
+	}
+
+	err := b.broker.Subscribe(brokerSubjectPrefix+string(eventType), func(payload []byte) {
+		var event Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			log.Printf("Evento inválido recebido do broker em %s: %v", eventType, err)
+			return
+		}
+		b.local.Publish(event)
+	})
+	if err != nil {
+		log.Printf("Falha ao assinar eventos %s no broker: %v", eventType, err)
+		return
+	}
+	b.subscribed[eventType] = true
+}
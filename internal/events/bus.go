@@ -0,0 +1,109 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	PostCreated       EventType = "post.created"
+	ItineraryCreated  EventType = "itinerary.created"
+	ItineraryTraveled EventType = "itinerary.traveled"
+	LocationCheckedIn EventType = "location.checked_in"
+	MediaUploaded     EventType = "media.uploaded"
+	MediaProcessed    EventType = "media.processed"
+
+	// Interações entre usuários; notificações e contadores reagem a elas
+	// sem que o serviço de origem conheça quem as consome
+	PostLiked      EventType = "post.liked"      // EntityID: post; Data: author_id
+	UserFollowed   EventType = "user.followed"   // EntityID: usuário seguido
+	ItineraryRated EventType = "itinerary.rated" // EntityID: roteiro; Data: author_id, rating
+	RatingReplied  EventType = "rating.replied"  // EntityID: avaliação; Data: itinerary_id
+	MessageSent    EventType = "message.sent"    // EntityID: mensagem; Data: conversation_id, text
+	UserBlocked    EventType = "user.blocked"    // EntityID: usuário bloqueado
+	UserUnblocked  EventType = "user.unblocked"  // EntityID: usuário desbloqueado
+
+	// Confirmações de entrega e leitura; o ator é quem recebeu ou leu
+	MessagesDelivered EventType = "messages.delivered" // EntityID: conversa; Data: message_id
+	MessagesRead      EventType = "messages.read"      // EntityID: conversa; Data: message_id
+
+	// Mudanças numa viagem, avisadas no grupo dela
+	ItineraryUpdated      EventType = "itinerary.updated"       // EntityID: roteiro; Data: fields, old_title
+	TripParticipantJoined EventType = "trip.participant_joined" // EntityID: roteiro; Data: user_id
+	TripParticipantLeft   EventType = "trip.participant_left"   // EntityID: roteiro; Data: user_id
+)
+
+// Event representa um acontecimento de domínio publicado pelos serviços. Só
+// carrega tipos serializáveis, para poder atravessar um broker externo; ID
+// permite aos consumidores descartar entregas repetidas
+type Event struct {
+	ID         string            `json:"id"`
+	Type       EventType         `json:"type"`
+	ActorID    uint              `json:"actor_id"`
+	EntityID   uint              `json:"entity_id"`
+	Data       map[string]string `json:"data,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+type Handler func(event Event)
+
+type BusInterface interface {
+	Publish(event Event)
+	Subscribe(eventType EventType, handler Handler)
+}
+
+// InProcessBus entrega os eventos aos assinantes em goroutines, sem bloquear
+// a requisição que originou o evento
+type InProcessBus struct {
+	mu       sync.RWMutex
+	handlers map[EventType][]Handler
+}
+
+func NewInProcessBus() BusInterface {
+	return &InProcessBus{
+		handlers: make(map[EventType][]Handler),
+	}
+}
+
+func (b *InProcessBus) Publish(event Event) {
+	stamp(&event)
+
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers[event.Type]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(handler Handler) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Erro ao processar evento %s: %v", event.Type, r)
+				}
+			}()
+			handler(event)
+		}(handler)
+	}
+}
+
+func (b *InProcessBus) Subscribe(eventType EventType, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// stamp preenche o id e o horário de eventos novos
+func stamp(event *Event) {
+	if event.ID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err == nil {
+			event.ID = hex.EncodeToString(id)
+		}
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService services.AdminServiceInterface
+}
+
+func NewAdminHandler(adminService services.AdminServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// GetUsers godoc
+// @Summary List users (admin)
+// @Description List users, suspended, banned and deactivated ones included, newest first, with account data not shown on public profiles. q matches part of the username, email, name or company name
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Part of the username, email, name or company name"
+// @Param user_type query string false "User type (normal, company, admin)"
+// @Param status query string false "Account status (active, suspended, banned, deactivated)"
+// @Param verified query bool false "Only verified (true) or unverified (false) accounts"
+// @Param limit query int false "Number of users per page (max 100)" default(20)
+// @Param offset query int false "Number of users to skip" default(0)
+// @Success 200 {array} models.AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users [get]
+func (h *AdminHandler) GetUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	query := &services.AdminUserQuery{
+		Query:    c.Query("q"),
+		UserType: models.UserType(c.Query("user_type")),
+		Status:   models.AccountStatus(c.Query("status")),
+	}
+	if verified := c.Query("verified"); verified != "" {
+		value, err := strconv.ParseBool(verified)
+		if err != nil {
+			errorJSON(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "Parâmetro inválido",
+				Message: "O parâmetro 'verified' deve ser true ou false",
+			})
+			return
+		}
+		query.Verified = &value
+	}
+
+	users, err := h.adminService.GetUsers(query, limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar usuários",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuários obtidos com sucesso",
+		Data:    users,
+	})
+}
+
+// GetUser godoc
+// @Summary Get a user (admin)
+// @Description Get a user's account, including banned accounts
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	user, err := h.adminService.GetUser(uint(targetID))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar usuário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuário obtido com sucesso",
+		Data:    user,
+	})
+}
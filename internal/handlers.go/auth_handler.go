@@ -3,29 +3,35 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Ulpio/guIA-backend/internal/middleware"
+	"github.com/Ulpio/guIA-backend/internal/models"
 	"github.com/Ulpio/guIA-backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
 	authService services.AuthServiceInterface
+	riskService services.RiskServiceInterface
 }
 
-func NewAuthHandler(authService services.AuthServiceInterface) *AuthHandler {
+func NewAuthHandler(authService services.AuthServiceInterface, riskService services.RiskServiceInterface) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		riskService: riskService,
 	}
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user account
+// @Description Register a new user account. Requests flagged as automated (honeypot fields, bot headers, instant form submission via X-Form-Started-At) are refused
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body services.RegisterRequest true "User registration data"
+// @Param X-Form-Started-At header int false "When the registration form was opened (Unix milliseconds)"
 // @Success 201 {object} services.AuthResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/register [post]
@@ -33,13 +39,19 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	var req services.RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	risk := botRisk(c)
+	if h.riskService.Assess(risk, models.RiskActionRegister, nil) {
+		respondBotBlocked(c)
+		return
+	}
+
 	response, err := h.authService.Register(&req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
@@ -53,13 +65,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro no registro",
 			Message: errorMsg,
 		})
 		return
 	}
 
+	h.riskService.LinkUser(risk, response.User.ID)
+
 	c.JSON(http.StatusCreated, SuccessResponse{
 		Message: "Usuário registrado com sucesso",
 		Data:    response,
@@ -68,7 +82,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token. Suspended and banned accounts get 403 with the reason (and, for suspensions, when it ends)
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -76,13 +90,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} services.AuthResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req services.LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -96,13 +111,15 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		// Determinar código de status baseado no erro
 		errorMsg := err.Error()
 		switch {
+		case contains(errorMsg, "conta suspensa"), contains(errorMsg, "conta banida"):
+			statusCode = http.StatusForbidden
 		case contains(errorMsg, "credenciais inválidas"), contains(errorMsg, "conta desativada"):
 			statusCode = http.StatusUnauthorized
 		case contains(errorMsg, "obrigatório"):
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro no login",
 			Message: errorMsg,
 		})
@@ -125,13 +142,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Success 200 {object} services.AuthResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req RefreshTokenRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -144,13 +162,15 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 		errorMsg := err.Error()
 		switch {
+		case contains(errorMsg, "conta suspensa"), contains(errorMsg, "conta banida"):
+			statusCode = http.StatusForbidden
 		case contains(errorMsg, "inválido"), contains(errorMsg, "expirado"):
 			statusCode = http.StatusUnauthorized
 		case contains(errorMsg, "não encontrado"), contains(errorMsg, "desativada"):
 			statusCode = http.StatusUnauthorized
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao renovar token",
 			Message: errorMsg,
 		})
@@ -196,7 +216,7 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	// O token já foi validado pelo middleware, então só retornamos as informações
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Token inválido",
 			Message: "Não foi possível extrair informações do token",
 		})
@@ -232,11 +252,22 @@ type TokenValidationResponse struct {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
+	TraceID string `json:"trace_id,omitempty"` // informado pelo usuário ao suporte
 }
 
 type SuccessResponse struct {
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Facets     interface{} `json:"facets,omitempty"`
+}
+
+// errorJSON responde o erro com o trace ID da requisição e guarda a mensagem
+// para o registro da requisição
+func errorJSON(c *gin.Context, code int, response ErrorResponse) {
+	response.TraceID = c.GetString(middleware.RequestIDKey)
+	c.Set(middleware.ErrorMessageKey, response.Error+": "+response.Message)
+	c.JSON(code, response)
 }
 
 // Função auxiliar para verificar se uma string contém uma substring
@@ -252,3 +283,17 @@ func contains(s, substr string) bool {
 				return false
 			}())))
 }
+
+// errorStatusCode converte as mensagens de erro padrão dos serviços no status HTTP correspondente
+func errorStatusCode(errorMsg string) int {
+	switch {
+	case contains(errorMsg, "não encontrad"):
+		return http.StatusNotFound
+	case contains(errorMsg, "não tem permissão"):
+		return http.StatusForbidden
+	case contains(errorMsg, "erro ao"):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetHandler struct {
+	budgetService     services.BudgetServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewBudgetHandler(budgetService services.BudgetServiceInterface, complianceService services.ComplianceServiceInterface) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService:     budgetService,
+		complianceService: complianceService,
+	}
+}
+
+// GetItineraryBudget godoc
+// @Summary Get the itinerary budget
+// @Description Roll up estimated costs from locations to days to the itinerary, with a breakdown by category (lodging, food, transport, activities, shopping, other) derived from the location types. Days without location costs use their own estimated cost, and an itinerary without day costs uses its own; those amounts are reported as unallocated
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param currency query string false "ISO 4217 currency to convert the amounts to (defaults to the itinerary currency)"
+// @Success 200 {object} services.ItineraryBudget
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/budget [get]
+func (h *BudgetHandler) GetItineraryBudget(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	budget, err := h.budgetService.GetBudget(uint(itineraryID), userID.(uint), c.Query("currency"))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao calcular orçamento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Orçamento calculado com sucesso",
+		Data:    budget,
+	})
+}
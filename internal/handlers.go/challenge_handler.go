@@ -0,0 +1,504 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ChallengeHandler struct {
+	challengeService services.ChallengeServiceInterface
+}
+
+func NewChallengeHandler(challengeService services.ChallengeServiceInterface) *ChallengeHandler {
+	return &ChallengeHandler{
+		challengeService: challengeService,
+	}
+}
+
+// GetActiveChallenges godoc
+// @Summary List active challenges
+// @Description Get the seasonal challenges currently running, with the current user's progress
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of challenges per page" default(20)
+// @Param offset query int false "Number of challenges to skip" default(0)
+// @Success 200 {array} models.ChallengeResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /challenges [get]
+func (h *ChallengeHandler) GetActiveChallenges(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	challenges, err := h.challengeService.GetActiveChallenges(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar desafios",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Desafios obtidos com sucesso",
+		Data:    challenges,
+	})
+}
+
+// GetChallengeByID godoc
+// @Summary Get challenge by ID
+// @Description Get a challenge with the current user's enrollment and progress
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Success 200 {object} models.ChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /challenges/{id} [get]
+func (h *ChallengeHandler) GetChallengeByID(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do desafio deve ser um número válido",
+		})
+		return
+	}
+
+	challenge, err := h.challengeService.GetChallengeByID(uint(challengeID), userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusNotFound, ErrorResponse{
+			Error:   "Desafio não encontrado",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Desafio encontrado",
+		Data:    challenge,
+	})
+}
+
+// EnrollChallenge godoc
+// @Summary Enroll in a challenge
+// @Description Join a running challenge; qualifying posts or itineraries created afterwards count towards it
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Success 200 {object} models.ChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /challenges/{id}/enroll [post]
+func (h *ChallengeHandler) EnrollChallenge(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do desafio deve ser um número válido",
+		})
+		return
+	}
+
+	challenge, err := h.challengeService.Enroll(uint(challengeID), userID.(uint))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrado"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "já está inscrito"):
+			statusCode = http.StatusConflict
+		case contains(errorMsg, "não está em andamento"):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao se inscrever no desafio",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Inscrição realizada com sucesso",
+		Data:    challenge,
+	})
+}
+
+// UnenrollChallenge godoc
+// @Summary Leave a challenge
+// @Description Leave a challenge that has not been completed yet, discarding its progress
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /challenges/{id}/enroll [delete]
+func (h *ChallengeHandler) UnenrollChallenge(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do desafio deve ser um número válido",
+		})
+		return
+	}
+
+	err = h.challengeService.Unenroll(uint(challengeID), userID.(uint))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrada"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "concluído"):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao sair do desafio",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Você saiu do desafio",
+		Data:    nil,
+	})
+}
+
+// GetLeaderboard godoc
+// @Summary Challenge leaderboard
+// @Description Get participants ranked by progress; ties are broken by who completed first
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Param limit query int false "Number of entries per page" default(20)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {array} models.ChallengeLeaderboardEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /challenges/{id}/leaderboard [get]
+func (h *ChallengeHandler) GetLeaderboard(c *gin.Context) {
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do desafio deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	entries, err := h.challengeService.GetLeaderboard(uint(challengeID), limit, offset)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao buscar ranking",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Ranking obtido com sucesso",
+		Data:    entries,
+	})
+}
+
+// GetUserBadges godoc
+// @Summary List user badges
+// @Description Get the badges a user earned by completing challenges
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.UserBadge
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/badges [get]
+func (h *ChallengeHandler) GetUserBadges(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	badges, err := h.challengeService.GetUserBadges(uint(userID))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar insígnias",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Insígnias obtidas com sucesso",
+		Data:    badges,
+	})
+}
+
+// GetAllChallenges godoc
+// @Summary List all challenges (admin)
+// @Description Get every campaign, including scheduled, finished and inactive ones
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of challenges per page" default(20)
+// @Param offset query int false "Number of challenges to skip" default(0)
+// @Success 200 {array} models.ChallengeResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/challenges [get]
+func (h *ChallengeHandler) GetAllChallenges(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	challenges, err := h.challengeService.GetAllChallenges(limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar desafios",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Desafios obtidos com sucesso",
+		Data:    challenges,
+	})
+}
+
+// CreateChallenge godoc
+// @Summary Create a challenge (admin)
+// @Description Create a seasonal campaign with a goal, validity period and completion badge
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.ChallengeRequest true "Challenge data"
+// @Success 201 {object} models.ChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/challenges [post]
+func (h *ChallengeHandler) CreateChallenge(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.ChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	challenge, err := h.challengeService.CreateChallenge(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Erro ao criar desafio",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Desafio criado com sucesso",
+		Data:    challenge,
+	})
+}
+
+// UpdateChallenge godoc
+// @Summary Update a challenge (admin)
+// @Description Replace the settings of an existing campaign
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Param request body services.ChallengeRequest true "Challenge data"
+// @Success 200 {object} models.ChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/challenges/{id} [put]
+func (h *ChallengeHandler) UpdateChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do desafio deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	challenge, err := h.challengeService.UpdateChallenge(uint(challengeID), &req)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao atualizar desafio",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Desafio atualizado com sucesso",
+		Data:    challenge,
+	})
+}
+
+// DeleteChallenge godoc
+// @Summary Delete a challenge (admin)
+// @Description Remove a campaign; badges already awarded are kept
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/challenges/{id} [delete]
+func (h *ChallengeHandler) DeleteChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do desafio deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.challengeService.DeleteChallenge(uint(challengeID)); err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao deletar desafio",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Desafio deletado com sucesso",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ClientErrorHandler struct {
+	clientErrorService services.ClientErrorServiceInterface
+}
+
+func NewClientErrorHandler(clientErrorService services.ClientErrorServiceInterface) *ClientErrorHandler {
+	return &ClientErrorHandler{
+		clientErrorService: clientErrorService,
+	}
+}
+
+// ReportClientError godoc
+// @Summary Report a client error
+// @Description Submit a crash or error report from the mobile or web app, with app version and device metadata. Authentication is optional. Reports are rate limited per user or IP, and errors and warnings may be sampled by the server (crashes are always kept); send sample_rate when the app already samples
+// @Tags client-errors
+// @Accept json
+// @Produce json
+// @Param request body services.ClientErrorReportRequest true "Error report"
+// @Success 202 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /client-errors [post]
+func (h *ClientErrorHandler) ReportClientError(c *gin.Context) {
+	var req services.ClientErrorReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var userID *uint
+	if id, exists := c.Get("user_id"); exists {
+		uid := id.(uint)
+		userID = &uid
+	}
+
+	stored, err := h.clientErrorService.Report(userID, &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao registrar relato",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: "Relato recebido",
+		Data:    gin.H{"stored": stored},
+	})
+}
+
+// GetClientErrorGroups godoc
+// @Summary Aggregate client errors (admin)
+// @Description Group client error reports by fingerprint, most frequent first (estimated from sampling), to spot widespread app issues
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param platform query string false "Platform (ios, android, web)"
+// @Param app_version query string false "App version"
+// @Param since query string false "Start date (YYYY-MM-DD or RFC 3339); defaults to the last 7 days"
+// @Param limit query int false "Number of groups per page" default(20)
+// @Param offset query int false "Number of groups to skip" default(0)
+// @Success 200 {array} models.ClientErrorGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/client-errors/groups [get]
+func (h *ClientErrorHandler) GetClientErrorGroups(c *gin.Context) {
+	since, err := parseDateParam(c.Query("since"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "Use o formato YYYY-MM-DD ou RFC 3339",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	groups, err := h.clientErrorService.GetGroups(c.Query("platform"), c.Query("app_version"), since, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar erros dos apps",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Erros dos apps obtidos com sucesso",
+		Data:    groups,
+	})
+}
+
+// GetClientErrorEndpoints godoc
+// @Summary Client errors by API endpoint (admin)
+// @Description Aggregate client error reports by the API call that caused them, to tie app issues to API changes
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param platform query string false "Platform (ios, android, web)"
+// @Param app_version query string false "App version"
+// @Param since query string false "Start date (YYYY-MM-DD or RFC 3339); defaults to the last 7 days"
+// @Param limit query int false "Number of endpoints per page" default(20)
+// @Param offset query int false "Number of endpoints to skip" default(0)
+// @Success 200 {array} models.ClientEndpointErrors
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/client-errors/endpoints [get]
+func (h *ClientErrorHandler) GetClientErrorEndpoints(c *gin.Context) {
+	since, err := parseDateParam(c.Query("since"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "Use o formato YYYY-MM-DD ou RFC 3339",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	endpoints, err := h.clientErrorService.GetEndpointErrors(c.Query("platform"), c.Query("app_version"), since, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar erros dos apps",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Erros por endpoint obtidos com sucesso",
+		Data:    endpoints,
+	})
+}
+
+// GetClientErrorReports godoc
+// @Summary List reports of a client error group (admin)
+// @Description List the individual reports that share a fingerprint, most recent first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param fingerprint path string true "Error group fingerprint"
+// @Param limit query int false "Number of reports per page" default(20)
+// @Param offset query int false "Number of reports to skip" default(0)
+// @Success 200 {array} models.ClientErrorReport
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/client-errors/groups/{fingerprint} [get]
+func (h *ClientErrorHandler) GetClientErrorReports(c *gin.Context) {
+	limit, offset := paginationParams(c)
+
+	reports, err := h.clientErrorService.GetReports(c.Param("fingerprint"), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar relatos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Relatos obtidos com sucesso",
+		Data:    reports,
+	})
+}
+
+func paginationParams(c *gin.Context) (int, int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
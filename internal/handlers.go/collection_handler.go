@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionHandler struct {
+	collectionService services.CollectionServiceInterface
+}
+
+func NewCollectionHandler(collectionService services.CollectionServiceInterface) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CreateCollection godoc
+// @Summary Create a collection
+// @Description Create a private collection to save itineraries into (e.g. "Eurotrip 2025")
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateCollectionRequest true "Collection data"
+// @Success 201 {object} models.CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/collections [post]
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar coleção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Coleção criada com sucesso",
+		Data:    collection,
+	})
+}
+
+// GetCollections godoc
+// @Summary List my collections
+// @Description Get the current user's collections, most recently updated first
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of collections per page" default(20)
+// @Param offset query int false "Number of collections to skip" default(0)
+// @Success 200 {array} models.CollectionResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/collections [get]
+func (h *CollectionHandler) GetCollections(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	collections, err := h.collectionService.GetCollections(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar coleções",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Coleções obtidas com sucesso",
+		Data:    collections,
+	})
+}
+
+// GetCollection godoc
+// @Summary Get a collection
+// @Description Get one of the current user's collections with its saved itineraries, most recently saved first
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param limit query int false "Number of itineraries per page" default(20)
+// @Param offset query int false "Number of itineraries to skip" default(0)
+// @Success 200 {object} models.CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da coleção deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	collection, err := h.collectionService.GetCollection(uint(collectionID), userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar coleção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Coleção encontrada",
+		Data:    collection,
+	})
+}
+
+// UpdateCollection godoc
+// @Summary Update a collection
+// @Description Rename a collection or change its description
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param request body services.UpdateCollectionRequest true "Collection update data"
+// @Success 200 {object} models.CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/collections/{id} [put]
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da coleção deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(uint(collectionID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar coleção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Coleção atualizada com sucesso",
+		Data:    collection,
+	})
+}
+
+// DeleteCollection godoc
+// @Summary Delete a collection
+// @Description Delete a collection; the saved itineraries themselves are not affected
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da coleção deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(uint(collectionID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao excluir coleção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Coleção excluída com sucesso",
+	})
+}
+
+// AddItinerary godoc
+// @Summary Save an itinerary into a collection
+// @Description Bookmark a public (or own) itinerary into one of the current user's collections
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param request body services.AddCollectionItemRequest true "Itinerary to save"
+// @Success 201 {object} models.CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /users/collections/{id}/items [post]
+func (h *CollectionHandler) AddItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da coleção deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	collection, err := h.collectionService.AddItinerary(uint(collectionID), userID.(uint), &req)
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if contains(err.Error(), "já está") {
+			statusCode = http.StatusConflict
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao salvar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Roteiro salvo na coleção",
+		Data:    collection,
+	})
+}
+
+// RemoveItinerary godoc
+// @Summary Remove an itinerary from a collection
+// @Description Remove a saved itinerary from one of the current user's collections
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param itineraryId path int true "Itinerary ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/collections/{id}/items/{itineraryId} [delete]
+func (h *CollectionHandler) RemoveItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da coleção deve ser um número válido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("itineraryId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.collectionService.RemoveItinerary(uint(collectionID), uint(itineraryID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover roteiro da coleção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro removido da coleção",
+	})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService services.CommentServiceInterface
+}
+
+func NewCommentHandler(commentService services.CommentServiceInterface) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// CreateComment godoc
+// @Summary Comment on a post
+// @Description Add a comment or reply to a post, subject to the post's comment policy (everyone, followers, mentioned or off)
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param request body services.CreateCommentRequest true "Comment data"
+// @Success 201 {object} models.CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(uint(postID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao comentar",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Comentário criado com sucesso",
+		Data:    comment,
+	})
+}
+
+// GetComments godoc
+// @Summary List post comments
+// @Description Get the top-level comments of a post with their replies, oldest first
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param limit query int false "Number of comments per page" default(20)
+// @Param offset query int false "Number of comments to skip" default(0)
+// @Success 200 {array} models.CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	comments, err := h.commentService.GetComments(uint(postID), userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar comentários",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Comentários obtidos com sucesso",
+		Data:    comments,
+	})
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Delete a comment and its replies (by the comment author or the post author)
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Comment ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do comentário deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.commentService.DeleteComment(uint(commentID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao deletar comentário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Comentário deletado com sucesso",
+	})
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ComplianceHandler struct {
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewComplianceHandler(complianceService services.ComplianceServiceInterface) *ComplianceHandler {
+	return &ComplianceHandler{
+		complianceService: complianceService,
+	}
+}
+
+// RestrictContent godoc
+// @Summary Restrict content by country (admin)
+// @Description Withhold a post or itinerary in the given countries (ISO 3166-1 alpha-2) for legal compliance; countries already restricted are ignored
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.RestrictContentRequest true "Content and countries"
+// @Success 201 {array} models.ContentRestriction
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/restrictions [post]
+func (h *ComplianceHandler) RestrictContent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.RestrictContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	restrictions, err := h.complianceService.RestrictContent(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao restringir conteúdo",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Conteúdo restrito com sucesso",
+		Data:    restrictions,
+	})
+}
+
+// GetRestrictions godoc
+// @Summary List country restrictions (admin)
+// @Description Get country restrictions, most recent first, optionally for a single content
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param content_type query string false "Content type (post, itinerary)"
+// @Param content_id query int false "Content ID"
+// @Param limit query int false "Number of restrictions per page" default(20)
+// @Param offset query int false "Number of restrictions to skip" default(0)
+// @Success 200 {array} models.ContentRestriction
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/restrictions [get]
+func (h *ComplianceHandler) GetRestrictions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	contentType := models.RestrictedContentType(c.Query("content_type"))
+	contentID, _ := strconv.ParseUint(c.Query("content_id"), 10, 32)
+
+	restrictions, err := h.complianceService.GetRestrictions(contentType, uint(contentID), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar restrições",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Restrições obtidas com sucesso",
+		Data:    restrictions,
+	})
+}
+
+// RemoveRestriction godoc
+// @Summary Remove a country restriction (admin)
+// @Description Make the content available again in the restriction's country
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Restriction ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/restrictions/{id} [delete]
+func (h *ComplianceHandler) RemoveRestriction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	restrictionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da restrição deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.complianceService.RemoveRestriction(uint(restrictionID), userID.(uint), &req); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover restrição",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Restrição removida com sucesso",
+	})
+}
+
+// GetTransparencyReport godoc
+// @Summary Country restrictions transparency report (admin)
+// @Description Get how many contents were withheld and how many times, per country and content type, in a period (defaults to the last 30 days)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End date (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} models.TransparencyReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/compliance/report [get]
+func (h *ComplianceHandler) GetTransparencyReport(c *gin.Context) {
+	from, err := parseDateParam(c.Query("from"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "from deve estar no formato RFC3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	to, err := parseDateParam(c.Query("to"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "to deve estar no formato RFC3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	report, err := h.complianceService.GetTransparencyReport(from, to)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao gerar relatório",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Relatório de transparência gerado com sucesso",
+		Data:    report,
+	})
+}
+
+// isUnavailableInCountry indica se o conteúdo está bloqueado no país da
+// requisição
+func isUnavailableInCountry(c *gin.Context, complianceService services.ComplianceServiceInterface, contentType models.RestrictedContentType, contentID uint) bool {
+	return complianceService.FilterRestricted(contentType, []uint{contentID}, requestCountry(c))[contentID]
+}
+
+// respondUnavailableInCountry responde 451 para conteúdo bloqueado no país
+func respondUnavailableInCountry(c *gin.Context) {
+	errorJSON(c, http.StatusUnavailableForLegalReasons, ErrorResponse{
+		Error:   "Conteúdo indisponível",
+		Message: "este conteúdo não está disponível no seu país",
+	})
+}
+
+// filterRestrictedPosts remove da lista os posts bloqueados no país da
+// requisição, sem alterar a lista original
+func filterRestrictedPosts(c *gin.Context, complianceService services.ComplianceServiceInterface, posts []models.PostResponse) []models.PostResponse {
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	restricted := complianceService.FilterRestricted(models.RestrictedContentPost, ids, requestCountry(c))
+	if len(restricted) == 0 {
+		return posts
+	}
+
+	visible := make([]models.PostResponse, 0, len(posts))
+	for _, post := range posts {
+		if !restricted[post.ID] {
+			visible = append(visible, post)
+		}
+	}
+	return visible
+}
+
+// filterRestrictedItineraries remove da lista os roteiros bloqueados no país
+// da requisição, sem alterar a lista original
+func filterRestrictedItineraries(c *gin.Context, complianceService services.ComplianceServiceInterface, itineraries []models.ItineraryResponse) []models.ItineraryResponse {
+	ids := make([]uint, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		ids = append(ids, itinerary.ID)
+	}
+
+	restricted := complianceService.FilterRestricted(models.RestrictedContentItinerary, ids, requestCountry(c))
+	if len(restricted) == 0 {
+		return itineraries
+	}
+
+	visible := make([]models.ItineraryResponse, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		if !restricted[itinerary.ID] {
+			visible = append(visible, itinerary)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,677 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ConversationHandler struct {
+	conversationService services.ConversationServiceInterface
+}
+
+func NewConversationHandler(conversationService services.ConversationServiceInterface) *ConversationHandler {
+	return &ConversationHandler{
+		conversationService: conversationService,
+	}
+}
+
+// GetConversations godoc
+// @Summary List conversations
+// @Description Get the current user's direct conversations and trip group chats, most recent activity first
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of conversations per page" default(20)
+// @Param offset query int false "Number of conversations to skip" default(0)
+// @Success 200 {array} models.ConversationResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /conversations [get]
+func (h *ConversationHandler) GetConversations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	conversations, err := h.conversationService.GetConversations(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar conversas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conversas obtidas com sucesso",
+		Data:    conversations,
+	})
+}
+
+// StartConversation godoc
+// @Summary Start a direct conversation
+// @Description Open (or return the existing) direct conversation with another user
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.StartConversationRequest true "Other participant"
+// @Success 200 {object} models.ConversationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations [post]
+func (h *ConversationHandler) StartConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	conversation, err := h.conversationService.StartDirectConversation(userID.(uint), req.UserID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrado"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "consigo mesmo"):
+			statusCode = http.StatusBadRequest
+		case contains(errorMsg, "bloqueio"):
+			statusCode = http.StatusForbidden
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao iniciar conversa",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conversa iniciada",
+		Data:    conversation,
+	})
+}
+
+// GetMessages godoc
+// @Summary List messages
+// @Description Get the messages of a conversation, newest first (participants only). Fetching confirms delivery up to the newest message; each message carries its delivery status (sent, delivered or read by every other participant) and who has read it
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param limit query int false "Number of messages per page" default(50)
+// @Param offset query int false "Number of messages to skip" default(0)
+// @Success 200 {array} models.MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/messages [get]
+func (h *ConversationHandler) GetMessages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	messages, err := h.conversationService.GetMessages(uint(conversationID), userID.(uint), limit, offset)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrada") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao buscar mensagens",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Mensagens obtidas com sucesso",
+		Data:    messages,
+	})
+}
+
+// SendMessage godoc
+// @Summary Send a message
+// @Description Send a message to a conversation (participants only): text, an attachment uploaded to /conversations/{id}/attachments (media_id) or both. Direct conversations frozen by a block reject new messages
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param request body services.SendMessageRequest true "Message"
+// @Success 201 {object} models.MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/messages [post]
+func (h *ConversationHandler) SendMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message, err := h.conversationService.SendMessage(uint(conversationID), userID.(uint), &req)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrada"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "congelada"):
+			statusCode = http.StatusForbidden
+		case contains(errorMsg, "erro ao"):
+			statusCode = http.StatusInternalServerError
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao enviar mensagem",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Mensagem enviada",
+		Data:    message,
+	})
+}
+
+// UploadAttachment godoc
+// @Summary Upload a message attachment
+// @Description Upload a private image or video to send in the conversation (participants only). The file is never public: the returned url and variants are signed links that expire. Send it with the media_id of the response
+// @Tags conversations
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param file formData file true "Image or video file"
+// @Success 200 {object} services.MediaUploadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /conversations/{id}/attachments [post]
+func (h *ConversationHandler) UploadAttachment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo não encontrado",
+			Message: "É necessário enviar um arquivo no campo 'file'",
+		})
+		return
+	}
+
+	response, err := h.conversationService.UploadAttachment(uint(conversationID), userID.(uint), file)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrada"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "congelada"):
+			statusCode = http.StatusForbidden
+		case contains(errorMsg, "muito grande"):
+			statusCode = http.StatusRequestEntityTooLarge
+		case contains(errorMsg, "não permitida"), contains(errorMsg, "não suportado"),
+			contains(errorMsg, "conteúdo do arquivo"), contains(errorMsg, "inválida"):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro no upload do anexo",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Anexo enviado com sucesso",
+		Data:    response,
+	})
+}
+
+// GetAttachment godoc
+// @Summary Download a message attachment
+// @Description Serve a message attachment through the signed link returned with the messages. The link is checked against its signature and expiry, and its user must still be the owner or a participant of a conversation where the attachment was sent
+// @Tags conversations
+// @Produce octet-stream
+// @Param id path int true "Media ID"
+// @Param variant query string false "Variant name (thumb, medium...); empty for the original"
+// @Param user query int true "User the link was signed for"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /attachments/{id} [get]
+func (h *ConversationHandler) GetAttachment(c *gin.Context) {
+	mediaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do anexo deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.AttachmentRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errorJSON(c, http.StatusForbidden, ErrorResponse{
+			Error:   "Link inválido",
+			Message: "link do anexo inválido",
+		})
+		return
+	}
+
+	file, err := h.conversationService.OpenAttachment(uint(mediaID), &req)
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if contains(err.Error(), "link do anexo") {
+			statusCode = http.StatusForbidden
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao abrir anexo",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Content.Close()
+
+	// O navegador guarda o arquivo só enquanto o link vale
+	maxAge := max(0, int(time.Until(file.ExpiresAt).Seconds()))
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+	c.Header("Content-Type", file.ContentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	// Arquivos locais aceitam Range, para o vídeo avançar sem baixar tudo
+	if seeker, ok := file.Content.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, "", time.Time{}, seeker)
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, file.ContentType, file.Content, nil)
+}
+
+// MarkConversationRead godoc
+// @Summary Mark a conversation as read
+// @Description Mark the conversation as read up to message_id, or up to the latest message when omitted. The other participants receive a conversation.receipt event over the realtime channel
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param request body services.MarkConversationReadRequest false "Last message read"
+// @Success 200 {object} models.ConversationReadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/read [post]
+func (h *ConversationHandler) MarkConversationRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.MarkConversationReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.conversationService.MarkRead(uint(conversationID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao marcar conversa como lida",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conversa marcada como lida",
+		Data:    result,
+	})
+}
+
+// GetTripConversation godoc
+// @Summary Get the trip group chat
+// @Description Open the group chat of a collaborative itinerary, creating it with the author and all participants on first access. A participant who left the group rejoins it
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} models.ConversationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/chat [get]
+func (h *ConversationHandler) GetTripConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	conversation, err := h.conversationService.GetTripConversation(uint(itineraryID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao abrir grupo da viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Grupo da viagem obtido com sucesso",
+		Data:    conversation,
+	})
+}
+
+// UpdateConversation godoc
+// @Summary Rename a trip group chat
+// @Description Change the title of a trip group chat (members only). Direct conversations cannot be renamed
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param request body services.UpdateConversationRequest true "New title"
+// @Success 200 {object} models.ConversationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id} [patch]
+func (h *ConversationHandler) UpdateConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.UpdateConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	conversation, err := h.conversationService.UpdateConversation(uint(conversationID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar conversa",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conversa atualizada",
+		Data:    conversation,
+	})
+}
+
+// AddConversationMember godoc
+// @Summary Add a member to a trip group chat
+// @Description Add a trip participant back to the group chat (itinerary author only)
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param request body services.AddConversationMemberRequest true "Member"
+// @Success 200 {object} models.ConversationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/members [post]
+func (h *ConversationHandler) AddConversationMember(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.AddConversationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	conversation, err := h.conversationService.AddMember(uint(conversationID), userID.(uint), req.UserID)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao adicionar membro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Membro adicionado",
+		Data:    conversation,
+	})
+}
+
+// RemoveConversationMember godoc
+// @Summary Remove a member from a trip group chat
+// @Description The itinerary author removes a member; any member removes themselves to leave the group. Leaving the group does not leave the trip
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param userId path int true "Member user ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/members/{userId} [delete]
+func (h *ConversationHandler) RemoveConversationMember(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.conversationService.RemoveMember(uint(conversationID), userID.(uint), uint(memberID)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover membro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Membro removido",
+	})
+}
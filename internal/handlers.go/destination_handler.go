@@ -0,0 +1,490 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type DestinationHandler struct {
+	destinationService services.DestinationServiceInterface
+	complianceService  services.ComplianceServiceInterface
+}
+
+func NewDestinationHandler(destinationService services.DestinationServiceInterface, complianceService services.ComplianceServiceInterface) *DestinationHandler {
+	return &DestinationHandler{
+		destinationService: destinationService,
+		complianceService:  complianceService,
+	}
+}
+
+// GetDestinationPage godoc
+// @Summary Destination page
+// @Description Get a destination (reference city) page with the currently active sponsored promotions, always labeled with their sponsor, and the most popular public itineraries
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cityId path int true "Reference city ID"
+// @Success 200 {object} models.DestinationPage
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/{cityId} [get]
+func (h *DestinationHandler) GetDestinationPage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	cityID, err := strconv.ParseUint(c.Param("cityId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do destino deve ser um número válido",
+		})
+		return
+	}
+
+	page, err := h.destinationService.GetDestinationPage(uint(cityID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar destino",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	page.Itineraries = filterRestrictedItineraries(c, h.complianceService, page.Itineraries)
+	page.Promotions = h.filterRestrictedPromotions(c, page.Promotions)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Destino encontrado",
+		Data:    page,
+	})
+}
+
+// CreatePromotion godoc
+// @Summary Create a destination promotion
+// @Description Pin a public itinerary or an announcement to a destination page for a date range. Available to admins and to company accounts that are partners of the destination (e.g. tourism boards). The sponsor name is required and shown as a sponsorship label. Partner promotions are only shown once paid (see the checkout endpoint); admin promotions are free
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cityId path int true "Reference city ID"
+// @Param request body services.PromotionRequest true "Promotion data"
+// @Success 201 {object} models.DestinationPromotionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/{cityId}/promotions [post]
+func (h *DestinationHandler) CreatePromotion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	cityID, err := strconv.ParseUint(c.Param("cityId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do destino deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	promotion, err := h.destinationService.CreatePromotion(uint(cityID), userID.(uint), isAdmin(c), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar promoção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Promoção criada com sucesso",
+		Data:    promotion,
+	})
+}
+
+// GetMyPromotions godoc
+// @Summary List my destination promotions
+// @Description List the promotions created by the current partner account (admins see all), including scheduled and ended ones, with their impressions
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of promotions per page" default(20)
+// @Param offset query int false "Number of promotions to skip" default(0)
+// @Success 200 {array} models.DestinationPromotionResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /destinations/promotions [get]
+func (h *DestinationHandler) GetMyPromotions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	promotions, err := h.destinationService.GetMyPromotions(userID.(uint), isAdmin(c), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar promoções",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Promoções obtidas com sucesso",
+		Data:    promotions,
+	})
+}
+
+// UpdatePromotion godoc
+// @Summary Update a destination promotion
+// @Description Update the content or the date range of a promotion. The promotion kind and the promoted itinerary cannot change
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Param request body services.PromotionRequest true "Promotion data"
+// @Success 200 {object} models.DestinationPromotionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/promotions/{id} [put]
+func (h *DestinationHandler) UpdatePromotion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da promoção deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	promotion, err := h.destinationService.UpdatePromotion(uint(promotionID), userID.(uint), isAdmin(c), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar promoção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Promoção atualizada com sucesso",
+		Data:    promotion,
+	})
+}
+
+// DeletePromotion godoc
+// @Summary Delete a destination promotion
+// @Description Remove a promotion from the destination page
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/promotions/{id} [delete]
+func (h *DestinationHandler) DeletePromotion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da promoção deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.destinationService.DeletePromotion(uint(promotionID), userID.(uint), isAdmin(c)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover promoção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Promoção removida com sucesso",
+		Data:    nil,
+	})
+}
+
+// GetPromotionReport godoc
+// @Summary Destination promotion report
+// @Description Get the total and daily impressions of a promotion
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} models.PromotionReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/promotions/{id}/report [get]
+func (h *DestinationHandler) GetPromotionReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da promoção deve ser um número válido",
+		})
+		return
+	}
+
+	report, err := h.destinationService.GetPromotionReport(uint(promotionID), userID.(uint), isAdmin(c))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar relatório da promoção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Relatório da promoção obtido com sucesso",
+		Data:    report,
+	})
+}
+
+// AddDestinationPartner godoc
+// @Summary Add a destination partner (admin)
+// @Description Allow a company account (e.g. a tourism board) to promote content on a destination page
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.DestinationPartnerRequest true "Partner account and destination"
+// @Success 201 {object} models.DestinationPartner
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/destination-partners [post]
+func (h *DestinationHandler) AddDestinationPartner(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.DestinationPartnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	partner, err := h.destinationService.AddPartner(userID.(uint), &req)
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if contains(err.Error(), "já é parceira") {
+			statusCode = http.StatusConflict
+		}
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao adicionar parceiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Parceiro do destino adicionado com sucesso",
+		Data:    partner,
+	})
+}
+
+// GetDestinationPartners godoc
+// @Summary List destination partners (admin)
+// @Description List the partner accounts of the destinations, optionally for a single city
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param city_id query int false "Reference city ID"
+// @Param limit query int false "Number of partners per page" default(20)
+// @Param offset query int false "Number of partners to skip" default(0)
+// @Success 200 {array} models.DestinationPartner
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/destination-partners [get]
+func (h *DestinationHandler) GetDestinationPartners(c *gin.Context) {
+	var cityID uint64
+	if value := c.Query("city_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			errorJSON(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "ID inválido",
+				Message: "O ID do destino deve ser um número válido",
+			})
+			return
+		}
+		cityID = parsed
+	}
+
+	limit, offset := paginationParams(c)
+
+	partners, err := h.destinationService.GetPartners(uint(cityID), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar parceiros",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Parceiros obtidos com sucesso",
+		Data:    partners,
+	})
+}
+
+// RemoveDestinationPartner godoc
+// @Summary Remove a destination partner (admin)
+// @Description Revoke a partner account's permission to create new promotions on the destination; existing promotions are kept
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Partner ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/destination-partners/{id} [delete]
+func (h *DestinationHandler) RemoveDestinationPartner(c *gin.Context) {
+	partnerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do parceiro deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.destinationService.RemovePartner(uint(partnerID)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover parceiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Parceiro do destino removido com sucesso",
+		Data:    nil,
+	})
+}
+
+// filterRestrictedPromotions remove as promoções de roteiros indisponíveis no
+// país da requisição
+func (h *DestinationHandler) filterRestrictedPromotions(c *gin.Context, promotions []models.DestinationPromotionResponse) []models.DestinationPromotionResponse {
+	var ids []uint
+	for _, promotion := range promotions {
+		if promotion.Itinerary != nil {
+			ids = append(ids, promotion.Itinerary.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return promotions
+	}
+
+	restricted := h.complianceService.FilterRestricted(models.RestrictedContentItinerary, ids, requestCountry(c))
+	if len(restricted) == 0 {
+		return promotions
+	}
+
+	visible := make([]models.DestinationPromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		if promotion.Itinerary == nil || !restricted[promotion.Itinerary.ID] {
+			visible = append(visible, promotion)
+		}
+	}
+	return visible
+}
+
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("user_type") == string(models.UserTypeAdmin)
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ExpenseHandler struct {
+	expenseService services.ExpenseServiceInterface
+}
+
+func NewExpenseHandler(expenseService services.ExpenseServiceInterface) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+	}
+}
+
+// AddExpense godoc
+// @Summary Record a trip expense
+// @Description Record an actual spend of the trip. Only the itinerary author and its collaborators can record expenses. The payer defaults to the current user and the amount is split equally between split_with (all trip participants when empty)
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.CreateExpenseRequest true "Expense data"
+// @Success 201 {object} models.ExpenseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/expenses [post]
+func (h *ExpenseHandler) AddExpense(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.CreateExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	expense, err := h.expenseService.AddExpense(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao registrar despesa",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Despesa registrada com sucesso",
+		Data:    expense,
+	})
+}
+
+// GetExpenses godoc
+// @Summary List trip expenses
+// @Description List the expenses recorded for the itinerary, most recent first. Only visible to the author and collaborators
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param category query string false "Category filter (lodging, food, transport, activities, shopping, other)"
+// @Param limit query int false "Number of expenses per page" default(20)
+// @Param offset query int false "Number of expenses to skip" default(0)
+// @Success 200 {array} models.ExpenseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/expenses [get]
+func (h *ExpenseHandler) GetExpenses(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	expenses, err := h.expenseService.GetExpenses(uint(itineraryID), userID.(uint), c.Query("category"), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar despesas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Despesas encontradas",
+		Data:    expenses,
+	})
+}
+
+// DeleteExpense godoc
+// @Summary Delete a trip expense
+// @Description Remove an expense. Allowed for whoever recorded it, the payer or the itinerary author
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param expenseId path int true "Expense ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/expenses/{expenseId} [delete]
+func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	expenseID, err := strconv.ParseUint(c.Param("expenseId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da despesa deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.expenseService.DeleteExpense(uint(itineraryID), uint(expenseID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover despesa",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Despesa removida com sucesso",
+	})
+}
+
+// GetExpenseSummary godoc
+// @Summary Summarize trip expenses
+// @Description Total actual spends per category compared with the estimated itinerary budget, how much each participant paid and owes, and the transfers that settle the balances. Amounts in other currencies are converted with the current exchange rates
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param currency query string false "ISO 4217 currency for the summary (defaults to the itinerary currency)"
+// @Success 200 {object} services.ExpenseSummary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/expenses/summary [get]
+func (h *ExpenseHandler) GetExpenseSummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	summary, err := h.expenseService.GetSummary(uint(itineraryID), userID.(uint), c.Query("currency"))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao calcular resumo de despesas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Resumo de despesas calculado com sucesso",
+		Data:    summary,
+	})
+}
@@ -0,0 +1,698 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ExperienceHandler struct {
+	experienceService services.ExperienceServiceInterface
+}
+
+func NewExperienceHandler(experienceService services.ExperienceServiceInterface) *ExperienceHandler {
+	return &ExperienceHandler{
+		experienceService: experienceService,
+	}
+}
+
+// SearchExperiences godoc
+// @Summary Browse experiences
+// @Description List active bookable experiences from local guides, optionally filtered by destination
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param country query string false "Country name or ISO code"
+// @Param city query string false "City"
+// @Param limit query int false "Number of experiences per page" default(20)
+// @Param offset query int false "Number of experiences to skip" default(0)
+// @Success 200 {array} models.ExperienceResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /experiences [get]
+func (h *ExperienceHandler) SearchExperiences(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	experiences, err := h.experienceService.SearchExperiences(c.Query("country"), c.Query("city"), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar experiências",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Experiências obtidas com sucesso",
+		Data:    experiences,
+	})
+}
+
+// GetMyExperiences godoc
+// @Summary List my experiences
+// @Description List the experiences published by the current guide, including inactive ones
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of experiences per page" default(20)
+// @Param offset query int false "Number of experiences to skip" default(0)
+// @Success 200 {array} models.ExperienceResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /experiences/mine [get]
+func (h *ExperienceHandler) GetMyExperiences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	experiences, err := h.experienceService.GetMyExperiences(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar experiências",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Experiências obtidas com sucesso",
+		Data:    experiences,
+	})
+}
+
+// GetExperienceByID godoc
+// @Summary Get experience by ID
+// @Description Get the details of a bookable experience
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Success 200 {object} models.ExperienceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id} [get]
+func (h *ExperienceHandler) GetExperienceByID(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	currentUserID, _ := userID.(uint)
+	experience, err := h.experienceService.GetExperienceByID(uint(experienceID), currentUserID)
+	if err != nil {
+		errorJSON(c, http.StatusNotFound, ErrorResponse{
+			Error:   "Experiência não encontrada",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Experiência encontrada",
+		Data:    experience,
+	})
+}
+
+// CreateExperience godoc
+// @Summary Publish an experience
+// @Description Publish a bookable experience (verified company/guide accounts only)
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.ExperienceRequest true "Experience data"
+// @Success 201 {object} models.ExperienceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /experiences [post]
+func (h *ExperienceHandler) CreateExperience(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.ExperienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	experience, err := h.experienceService.CreateExperience(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar experiência",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Experiência publicada com sucesso",
+		Data:    experience,
+	})
+}
+
+// UpdateExperience godoc
+// @Summary Update an experience
+// @Description Update an experience published by the current guide
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Param request body services.ExperienceRequest true "Experience data"
+// @Success 200 {object} models.ExperienceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id} [put]
+func (h *ExperienceHandler) UpdateExperience(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ExperienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	experience, err := h.experienceService.UpdateExperience(uint(experienceID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar experiência",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Experiência atualizada com sucesso",
+		Data:    experience,
+	})
+}
+
+// DeleteExperience godoc
+// @Summary Delete an experience
+// @Description Remove an experience published by the current guide
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id} [delete]
+func (h *ExperienceHandler) DeleteExperience(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.experienceService.DeleteExperience(uint(experienceID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao deletar experiência",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Experiência deletada com sucesso",
+		Data:    nil,
+	})
+}
+
+// GetAvailability godoc
+// @Summary Experience availability calendar
+// @Description List the time slots of an experience with remaining capacity (defaults to the next 30 days)
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Param from query string false "Start of the period (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the period (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {array} models.ExperienceSlotResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id}/availability [get]
+func (h *ExperienceHandler) GetAvailability(c *gin.Context) {
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	from, err := parseDateParam(c.Query("from"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'from' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	to, err := parseDateParam(c.Query("to"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'to' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	slots, err := h.experienceService.GetAvailability(uint(experienceID), from, to)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar disponibilidade",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Disponibilidade obtida com sucesso",
+		Data:    slots,
+	})
+}
+
+// AddSlots godoc
+// @Summary Add availability slots
+// @Description Add bookable time slots to an experience (owner only)
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Param request body services.AddSlotsRequest true "Slots"
+// @Success 201 {array} models.ExperienceSlotResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id}/availability [post]
+func (h *ExperienceHandler) AddSlots(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.AddSlotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	slots, err := h.experienceService.AddSlots(uint(experienceID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar horários",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Horários criados com sucesso",
+		Data:    slots,
+	})
+}
+
+// CancelSlot godoc
+// @Summary Cancel an availability slot
+// @Description Cancel a time slot; pending and accepted booking requests for it are cancelled too
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Param slotId path int true "Slot ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id}/availability/{slotId} [delete]
+func (h *ExperienceHandler) CancelSlot(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	slotID, err := strconv.ParseUint(c.Param("slotId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do horário deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.experienceService.CancelSlot(uint(experienceID), uint(slotID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao cancelar horário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Horário cancelado com sucesso",
+		Data:    nil,
+	})
+}
+
+// RequestBooking godoc
+// @Summary Request a booking
+// @Description Send a booking request for a slot; the request is forwarded to the guide through a direct conversation
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Experience ID"
+// @Param request body services.CreateBookingRequest true "Booking request"
+// @Success 201 {object} models.BookingRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /experiences/{id}/bookings [post]
+func (h *ExperienceHandler) RequestBooking(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	experienceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da experiência deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	booking, err := h.experienceService.RequestBooking(uint(experienceID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao solicitar reserva",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Pedido de reserva enviado ao guia",
+		Data:    booking,
+	})
+}
+
+// GetMyBookings godoc
+// @Summary List my booking requests
+// @Description List the booking requests sent by the current user
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of bookings per page" default(20)
+// @Param offset query int false "Number of bookings to skip" default(0)
+// @Success 200 {array} models.BookingRequestResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bookings [get]
+func (h *ExperienceHandler) GetMyBookings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	bookings, err := h.experienceService.GetMyBookings(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar reservas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Reservas obtidas com sucesso",
+		Data:    bookings,
+	})
+}
+
+// GetReceivedBookings godoc
+// @Summary List received booking requests
+// @Description List the booking requests received by the current guide, optionally filtered by status
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Status filter (pending, accepted, declined, cancelled)"
+// @Param limit query int false "Number of bookings per page" default(20)
+// @Param offset query int false "Number of bookings to skip" default(0)
+// @Success 200 {array} models.BookingRequestResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bookings/received [get]
+func (h *ExperienceHandler) GetReceivedBookings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	status := models.BookingStatus(c.Query("status"))
+	bookings, err := h.experienceService.GetReceivedBookings(userID.(uint), status, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar reservas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Reservas obtidas com sucesso",
+		Data:    bookings,
+	})
+}
+
+// UpdateBookingStatus godoc
+// @Summary Respond to or cancel a booking
+// @Description The guide accepts or declines a pending request; either party can cancel a pending or accepted one
+// @Tags experiences
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param request body services.UpdateBookingStatusRequest true "New status"
+// @Success 200 {object} models.BookingRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /bookings/{id}/status [put]
+func (h *ExperienceHandler) UpdateBookingStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da reserva deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.UpdateBookingStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	booking, err := h.experienceService.UpdateBookingStatus(uint(bookingID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar reserva",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Reserva atualizada com sucesso",
+		Data:    booking,
+	})
+}
+
+// parseDateParam aceita datas em RFC 3339 ou apenas YYYY-MM-DD; vazio resulta em zero
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
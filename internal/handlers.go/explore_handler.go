@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ExploreHandler struct {
+	exploreService    services.ExploreServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewExploreHandler(exploreService services.ExploreServiceInterface, complianceService services.ComplianceServiceInterface) *ExploreHandler {
+	return &ExploreHandler{
+		exploreService:    exploreService,
+		complianceService: complianceService,
+	}
+}
+
+// GetExplore godoc
+// @Summary Explore page
+// @Description Get trending posts, featured itineraries and trending destinations in a single response for the explore tab
+// @Tags explore
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ExploreResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /explore [get]
+func (h *ExploreHandler) GetExplore(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	explore, err := h.exploreService.GetExplore(userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao montar a aba Explorar",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	explore.TrendingPosts = filterRestrictedPosts(c, h.complianceService, explore.TrendingPosts)
+	explore.FeaturedItineraries = filterRestrictedItineraries(c, h.complianceService, explore.FeaturedItineraries)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Explorar obtido com sucesso",
+		Data:    explore,
+	})
+}
@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	exportService     services.ExportServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewExportHandler(exportService services.ExportServiceInterface, complianceService services.ComplianceServiceInterface) *ExportHandler {
+	return &ExportHandler{
+		exportService:     exportService,
+		complianceService: complianceService,
+	}
+}
+
+// ExportItineraryPDF godoc
+// @Summary Export an itinerary as PDF
+// @Description Render the itinerary (cover, days, locations, map thumbnails and costs) into a downloadable PDF. Small itineraries are returned directly as the file; large ones are generated in the background and the response is the export job (202 while pending, 200 with file_url when ready), to be polled at /itineraries/{id}/exports/{exportId}
+// @Tags itineraries
+// @Accept json
+// @Produce application/pdf
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {file} file
+// @Success 202 {object} models.ItineraryExport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/export/pdf [get]
+func (h *ExportHandler) ExportItineraryPDF(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	result, err := h.exportService.ExportPDF(uint(itineraryID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao exportar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respondExport(c, result)
+}
+
+// ExportItineraryICS godoc
+// @Summary Export an itinerary as an ICS calendar
+// @Description Generate calendar events from the locations' start and end times, dated from the trip start date, for import into Google/Apple Calendar. Days without scheduled locations become all-day events
+// @Tags itineraries
+// @Accept json
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param start_date query string true "Date of the first trip day (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/export/ics [get]
+func (h *ExportHandler) ExportItineraryICS(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	startDate, err := parseDateParam(c.Query("start_date"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "Use o formato YYYY-MM-DD para start_date",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	result, err := h.exportService.ExportICS(uint(itineraryID), userID.(uint), startDate)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao exportar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respondExport(c, result)
+}
+
+// ExportItineraryGPX godoc
+// @Summary Export an itinerary as GPX
+// @Description Export the geolocated locations as GPX waypoints, plus one track per day linking them in visiting order
+// @Tags itineraries
+// @Accept json
+// @Produce application/gpx+xml
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/export/gpx [get]
+func (h *ExportHandler) ExportItineraryGPX(c *gin.Context) {
+	h.exportTrack(c, models.ExportFormatGPX)
+}
+
+// ExportItineraryKML godoc
+// @Summary Export an itinerary as KML
+// @Description Export the geolocated locations as KML placemarks, with one folder per day and the day's path as a line
+// @Tags itineraries
+// @Accept json
+// @Produce application/vnd.google-earth.kml+xml
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/export/kml [get]
+func (h *ExportHandler) ExportItineraryKML(c *gin.Context) {
+	h.exportTrack(c, models.ExportFormatKML)
+}
+
+func (h *ExportHandler) exportTrack(c *gin.Context, format models.ExportFormat) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	result, err := h.exportService.ExportTrack(uint(itineraryID), userID.(uint), format)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao exportar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	respondExport(c, result)
+}
+
+// GetItineraryGeoJSON godoc
+// @Summary Get an itinerary as GeoJSON
+// @Description Return a GeoJSON FeatureCollection with one Point per geolocated location (properties carry the day and visiting order) and one LineString per day path, ready to draw on a map. Public itineraries can be fetched without a token, for embeds
+// @Tags itineraries
+// @Accept json
+// @Produce application/geo+json
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} services.GeoJSONFeatureCollection
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/geojson [get]
+func (h *ExportHandler) GetItineraryGeoJSON(c *gin.Context) {
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	var viewerID uint
+	if userID, exists := c.Get("user_id"); exists {
+		viewerID = userID.(uint)
+	}
+
+	collection, err := h.exportService.GetGeoJSON(uint(itineraryID), viewerID)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// O corpo é a FeatureCollection pura, sem o envelope das demais respostas,
+	// para ser passado direto às bibliotecas de mapas
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, collection)
+}
+
+// GetItineraryExport godoc
+// @Summary Get an itinerary export status
+// @Description Poll a background itinerary export; file_url is filled once the status is ready
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param exportId path int true "Export ID"
+// @Success 200 {object} models.ItineraryExport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/exports/{exportId} [get]
+func (h *ExportHandler) GetItineraryExport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	exportID, err := strconv.ParseUint(c.Param("exportId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da exportação deve ser um número válido",
+		})
+		return
+	}
+
+	export, err := h.exportService.GetExport(uint(itineraryID), uint(exportID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar exportação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Exportação encontrada",
+		Data:    export,
+	})
+}
+
+// respondExport envia o arquivo gerado na hora ou, para exportações em
+// segundo plano, o estado da geração
+func respondExport(c *gin.Context, result *services.ExportResult) {
+	if result.Export == nil {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.FileName))
+		c.Data(http.StatusOK, result.ContentType, result.Data)
+		return
+	}
+
+	if result.Export.Status == models.ExportStatusReady {
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: "Exportação pronta",
+			Data:    result.Export,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: "Exportação em andamento",
+		Data:    result.Export,
+	})
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FeaturedHandler struct {
+	featuredService services.FeaturedServiceInterface
+}
+
+func NewFeaturedHandler(featuredService services.FeaturedServiceInterface) *FeaturedHandler {
+	return &FeaturedHandler{
+		featuredService: featuredService,
+	}
+}
+
+// GetFeaturedItineraries godoc
+// @Summary List featured itineraries (admin)
+// @Description List every featured itinerary in carousel order, including scheduled and expired ones, with the status of its featuring window
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} services.FeaturedItinerary
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/featured [get]
+func (h *FeaturedHandler) GetFeaturedItineraries(c *gin.Context) {
+	featured, err := h.featuredService.GetFeatured()
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar destaques",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Destaques encontrados",
+		Data:    featured,
+	})
+}
+
+// FeatureItinerary godoc
+// @Summary Feature an itinerary (admin)
+// @Description Feature a public itinerary, optionally within a scheduled window and at a given carousel position. Calling it again changes the window and position
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.FeatureItineraryRequest true "Featuring window and position"
+// @Success 200 {object} services.FeaturedItinerary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/itineraries/{id}/feature [put]
+func (h *FeaturedHandler) FeatureItinerary(c *gin.Context) {
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.FeatureItineraryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	featured, err := h.featuredService.FeatureItinerary(uint(itineraryID), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao destacar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro destacado com sucesso",
+		Data:    featured,
+	})
+}
+
+// UnfeatureItinerary godoc
+// @Summary Remove an itinerary from the featured list (admin)
+// @Description Stop featuring an itinerary and clear its window and position
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/itineraries/{id}/feature [delete]
+func (h *FeaturedHandler) UnfeatureItinerary(c *gin.Context) {
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.featuredService.UnfeatureItinerary(uint(itineraryID)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover destaque",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Destaque removido com sucesso",
+	})
+}
+
+// ReorderFeaturedItineraries godoc
+// @Summary Order the featured carousel (admin)
+// @Description Set the carousel order: the listed itineraries take the first positions in the given order, and the remaining featured itineraries follow without a position
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.ReorderFeaturedRequest true "Featured itinerary IDs in carousel order"
+// @Success 200 {array} services.FeaturedItinerary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/featured/order [put]
+func (h *FeaturedHandler) ReorderFeaturedItineraries(c *gin.Context) {
+	var req services.ReorderFeaturedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	featured, err := h.featuredService.ReorderFeatured(&req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao ordenar destaques",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Destaques ordenados com sucesso",
+		Data:    featured,
+	})
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedSettingsHandler struct {
+	feedSettingsService services.FeedSettingsServiceInterface
+}
+
+func NewFeedSettingsHandler(feedSettingsService services.FeedSettingsServiceInterface) *FeedSettingsHandler {
+	return &FeedSettingsHandler{
+		feedSettingsService: feedSettingsService,
+	}
+}
+
+// GetFeedSettings godoc
+// @Summary Get feed settings
+// @Description Get the current user's home feed mix settings, falling back to the defaults of their experiment bucket
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.FeedSettingsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/feed-settings [get]
+func (h *FeedSettingsHandler) GetFeedSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	settings, err := h.feedSettingsService.GetFeedSettings(userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar preferências do feed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Preferências do feed obtidas com sucesso",
+		Data:    settings,
+	})
+}
+
+// UpdateFeedSettings godoc
+// @Summary Update feed settings
+// @Description Tune the home feed mix (more itineraries or posts, suggested content, reposts); omitted fields keep their current value
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.UpdateFeedSettingsRequest true "Feed settings"
+// @Success 200 {object} models.FeedSettingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/feed-settings [put]
+func (h *FeedSettingsHandler) UpdateFeedSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.UpdateFeedSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.feedSettingsService.UpdateFeedSettings(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar preferências do feed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Preferências do feed atualizadas com sucesso",
+		Data:    settings,
+	})
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/middleware"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FraudHandler struct {
+	fraudService services.FraudServiceInterface
+}
+
+func NewFraudHandler(fraudService services.FraudServiceInterface) *FraudHandler {
+	return &FraudHandler{
+		fraudService: fraudService,
+	}
+}
+
+// GetRules godoc
+// @Summary List fraud rules (admin)
+// @Description Get the fraud rules evaluated before accepting payments, with their thresholds
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FraudRule
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/fraud/rules [get]
+func (h *FraudHandler) GetRules(c *gin.Context) {
+	rules, err := h.fraudService.GetRules()
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar regras antifraude",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Regras antifraude obtidas com sucesso",
+		Data:    rules,
+	})
+}
+
+// UpdateRule godoc
+// @Summary Update a fraud rule (admin)
+// @Description Enable or disable a fraud rule and adjust its action and thresholds
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Rule code (payment_velocity, geo_mismatch, disposable_email)"
+// @Param request body services.FraudRuleRequest true "Rule settings"
+// @Success 200 {object} models.FraudRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/fraud/rules/{code} [put]
+func (h *FraudHandler) UpdateRule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.FraudRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.fraudService.UpdateRule(models.FraudRuleCode(c.Param("code")), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar regra antifraude",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Regra antifraude atualizada com sucesso",
+		Data:    rule,
+	})
+}
+
+// GetReviewQueue godoc
+// @Summary Fraud manual-review queue (admin)
+// @Description Get the payment attempts held for manual review, oldest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Review status (pending, approved, rejected)" default(pending)
+// @Param limit query int false "Number of checks per page" default(20)
+// @Param offset query int false "Number of checks to skip" default(0)
+// @Success 200 {array} models.FraudCheckResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/fraud/reviews [get]
+func (h *FraudHandler) GetReviewQueue(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	status := models.FraudReviewStatus(c.Query("status"))
+
+	checks, err := h.fraudService.GetReviewQueue(status, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar fila de revisão",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Fila de revisão obtida com sucesso",
+		Data:    checks,
+	})
+}
+
+// ReviewCheck godoc
+// @Summary Decide a fraud review (admin)
+// @Description Approve or reject a payment held for review; approving captures the payment and rejecting cancels it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Fraud check ID"
+// @Param request body services.FraudReviewRequest true "Review decision"
+// @Success 200 {object} models.FraudCheckResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/fraud/reviews/{id} [post]
+func (h *FraudHandler) ReviewCheck(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	checkID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da avaliação deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.FraudReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	check, err := h.fraudService.ReviewCheck(uint(checkID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao revisar pagamento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Revisão registrada com sucesso",
+		Data:    check,
+	})
+}
+
+// requestCountry obtém o país de origem da requisição, resolvido pelo
+// middleware.CountryMiddleware a partir da CDN confiável ou do GeoIP
+func requestCountry(c *gin.Context) string {
+	return c.GetString(middleware.RequestCountryKey)
+}
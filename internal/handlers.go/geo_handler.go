@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type GeoHandler struct {
+	geoService services.GeoServiceInterface
+}
+
+func NewGeoHandler(geoService services.GeoServiceInterface) *GeoHandler {
+	return &GeoHandler{
+		geoService: geoService,
+	}
+}
+
+// SearchCountries godoc
+// @Summary Autocomplete countries
+// @Description Search reference countries by name prefix (English, Portuguese or common aliases)
+// @Tags geo
+// @Accept json
+// @Produce json
+// @Param q query string true "Search prefix"
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {array} models.GeoSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /geo/countries [get]
+func (h *GeoHandler) SearchCountries(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetro obrigatório",
+			Message: "O parâmetro 'q' (query) é obrigatório",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	countries, err := h.geoService.SearchCountries(query, limit)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar países",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Países encontrados",
+		Data:    countries,
+	})
+}
+
+// SearchCities godoc
+// @Summary Autocomplete cities
+// @Description Search reference cities by name prefix, optionally restricted to a country
+// @Tags geo
+// @Accept json
+// @Produce json
+// @Param q query string true "Search prefix"
+// @Param country query string false "ISO 3166-1 alpha-2 country code"
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {array} models.GeoSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /geo/cities [get]
+func (h *GeoHandler) SearchCities(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetro obrigatório",
+			Message: "O parâmetro 'q' (query) é obrigatório",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	cities, err := h.geoService.SearchCities(query, c.Query("country"), limit)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar cidades",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Cidades encontradas",
+		Data:    cities,
+	})
+}
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationService services.InvitationServiceInterface
+}
+
+func NewInvitationHandler(invitationService services.InvitationServiceInterface) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// Invite godoc
+// @Summary Invite someone to a trip
+// @Description Invite one of your followers (user_id) or someone by email to join the itinerary's trip as a collaborator (shares expenses) or viewer (can see the itinerary even if private). Invitations are the only way into a trip: nobody joins without accepting. Email invitations to people without an account wait until they sign up with that email. Users with a block between them, or who declined an invitation to the same trip in the last 30 days, cannot be invited. Only the itinerary author can invite
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.InviteRequest true "Invitation"
+// @Success 201 {object} models.TripInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/invite [post]
+func (h *InvitationHandler) Invite(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	invitation, err := h.invitationService.Invite(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao enviar convite",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Convite enviado com sucesso",
+		Data:    invitation,
+	})
+}
+
+// GetItineraryInvitations godoc
+// @Summary List trip invitations sent
+// @Description List the invitations sent for the itinerary's trip, newest first. Only the itinerary author can see them
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param limit query int false "Number of invitations per page" default(20)
+// @Param offset query int false "Number of invitations to skip" default(0)
+// @Success 200 {array} models.TripInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/invitations [get]
+func (h *InvitationHandler) GetItineraryInvitations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	invitations, err := h.invitationService.GetItineraryInvitations(uint(itineraryID), userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar convites",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Convites encontrados",
+		Data:    invitations,
+	})
+}
+
+// CancelInvitation godoc
+// @Summary Cancel a trip invitation
+// @Description Cancel a pending invitation for the itinerary's trip
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param invitationId path int true "Invitation ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/invitations/{invitationId} [delete]
+func (h *InvitationHandler) CancelInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("invitationId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do convite deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.invitationService.CancelInvitation(uint(itineraryID), uint(invitationID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao cancelar convite",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Convite cancelado com sucesso",
+		Data:    nil,
+	})
+}
+
+// GetTripInvitations godoc
+// @Summary List received trip invitations
+// @Description List the trip invitations received by the current user, including those sent to their email before signing up, newest first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter: pending, accepted, declined or all" default(pending)
+// @Param limit query int false "Number of invitations per page" default(20)
+// @Param offset query int false "Number of invitations to skip" default(0)
+// @Success 200 {array} models.TripInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/trip-invitations [get]
+func (h *InvitationHandler) GetTripInvitations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	invitations, err := h.invitationService.GetInbox(userID.(uint), c.Query("status"), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar convites",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Convites encontrados",
+		Data:    invitations,
+	})
+}
+
+// AcceptTripInvitation godoc
+// @Summary Accept a trip invitation
+// @Description Accept a pending trip invitation, joining the itinerary with the invited role. The author is notified
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} models.TripInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/trip-invitations/{id}/accept [post]
+func (h *InvitationHandler) AcceptTripInvitation(c *gin.Context) {
+	h.respond(c, true)
+}
+
+// DeclineTripInvitation godoc
+// @Summary Decline a trip invitation
+// @Description Decline a pending trip invitation
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} models.TripInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/trip-invitations/{id}/decline [post]
+func (h *InvitationHandler) DeclineTripInvitation(c *gin.Context) {
+	h.respond(c, false)
+}
+
+func (h *InvitationHandler) respond(c *gin.Context, accept bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do convite deve ser um número válido",
+		})
+		return
+	}
+
+	respond := h.invitationService.DeclineInvitation
+	message := "Convite recusado"
+	if accept {
+		respond = h.invitationService.AcceptInvitation
+		message = "Convite aceito"
+	}
+
+	invitation, err := respond(uint(invitationID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao responder convite",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data:    invitation,
+	})
+}
+
+// GetParticipants godoc
+// @Summary List trip participants
+// @Description List the itinerary author followed by everyone who joined the trip by accepting an invitation, with their role (author, collaborator or viewer). Only participants can see the list
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {array} models.TripParticipantResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/collaborators [get]
+func (h *InvitationHandler) GetParticipants(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	participants, err := h.invitationService.GetParticipants(uint(itineraryID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar participantes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Participantes encontrados",
+		Data:    participants,
+	})
+}
+
+// RemoveParticipant godoc
+// @Summary Remove a trip participant
+// @Description Remove a collaborator or viewer from the trip. Allowed for the itinerary author or for the participant themselves, to leave the trip; expenses already recorded stay in the balances
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/collaborators/{userId} [delete]
+func (h *InvitationHandler) RemoveParticipant(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	participantID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.invitationService.RemoveParticipant(uint(itineraryID), uint(participantID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover participante",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Participante removido com sucesso",
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 
@@ -10,12 +11,14 @@ import (
 )
 
 type ItineraryHandler struct {
-	itineraryService services.ItineraryServiceInterface
+	itineraryService  services.ItineraryServiceInterface
+	complianceService services.ComplianceServiceInterface
 }
 
-func NewItineraryHandler(itineraryService services.ItineraryServiceInterface) *ItineraryHandler {
+func NewItineraryHandler(itineraryService services.ItineraryServiceInterface, complianceService services.ComplianceServiceInterface) *ItineraryHandler {
 	return &ItineraryHandler{
-		itineraryService: itineraryService,
+		itineraryService:  itineraryService,
+		complianceService: complianceService,
 	}
 }
 
@@ -35,7 +38,7 @@ func NewItineraryHandler(itineraryService services.ItineraryServiceInterface) *I
 func (h *ItineraryHandler) CreateItinerary(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -44,7 +47,7 @@ func (h *ItineraryHandler) CreateItinerary(c *gin.Context) {
 
 	var req services.CreateItineraryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -60,7 +63,7 @@ func (h *ItineraryHandler) CreateItinerary(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao criar roteiro",
 			Message: errorMsg,
 		})
@@ -87,17 +90,21 @@ func (h *ItineraryHandler) CreateItinerary(c *gin.Context) {
 // @Param max_duration query int false "Maximum duration in days"
 // @Param difficulty query int false "Filter by difficulty (1-5)"
 // @Param featured query bool false "Show only featured itineraries"
-// @Param order_by query string false "Order by: recent, popular, rating" default(recent)
+// @Param min_cost query number false "Minimum estimated cost, in the requested currency"
+// @Param max_cost query number false "Maximum estimated cost, in the requested currency"
+// @Param currency query string false "ISO 4217 currency for cost filters and normalized costs (defaults to the viewer's country currency)"
+// @Param order_by query string false "Order by: recent, popular, rating, cost_asc, cost_desc" default(recent)
 // @Param limit query int false "Number of results per page" default(20)
 // @Param offset query int false "Number of results to skip" default(0)
 // @Success 200 {array} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /itineraries [get]
 func (h *ItineraryHandler) GetItineraries(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -111,6 +118,9 @@ func (h *ItineraryHandler) GetItineraries(c *gin.Context) {
 		City:       c.Query("city"),
 		OrderBy:    c.DefaultQuery("order_by", "recent"),
 		IsFeatured: c.Query("featured") == "true",
+		Currency:   c.Query("currency"),
+
+		ViewerCountry: requestCountry(c),
 	}
 
 	// Parse numeric filters
@@ -132,6 +142,18 @@ func (h *ItineraryHandler) GetItineraries(c *gin.Context) {
 		}
 	}
 
+	if minCost := c.Query("min_cost"); minCost != "" {
+		if val, err := strconv.ParseFloat(minCost, 64); err == nil {
+			filters.MinCost = val
+		}
+	}
+
+	if maxCost := c.Query("max_cost"); maxCost != "" {
+		if val, err := strconv.ParseFloat(maxCost, 64); err == nil {
+			filters.MaxCost = val
+		}
+	}
+
 	// Parse pagination
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	if err != nil || limit <= 0 {
@@ -147,13 +169,15 @@ func (h *ItineraryHandler) GetItineraries(c *gin.Context) {
 
 	itineraries, err := h.itineraryService.GetItineraries(filters, currentUserID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
 			Error:   "Erro ao buscar roteiros",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	itineraries = filterRestrictedItineraries(c, h.complianceService, itineraries)
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Roteiros encontrados",
 		Data:    itineraries,
@@ -177,7 +201,7 @@ func (h *ItineraryHandler) GetItineraries(c *gin.Context) {
 func (h *ItineraryHandler) GetItineraryByID(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -187,7 +211,7 @@ func (h *ItineraryHandler) GetItineraryByID(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -201,13 +225,18 @@ func (h *ItineraryHandler) GetItineraryByID(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao buscar roteiro",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, itinerary.ID) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Roteiro encontrado",
 		Data:    itinerary,
@@ -233,7 +262,7 @@ func (h *ItineraryHandler) GetItineraryByID(c *gin.Context) {
 func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -243,7 +272,7 @@ func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -252,7 +281,7 @@ func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
 
 	var req services.UpdateItineraryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -273,7 +302,7 @@ func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao atualizar roteiro",
 			Message: errorMsg,
 		})
@@ -304,7 +333,7 @@ func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
 func (h *ItineraryHandler) DeleteItinerary(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -314,7 +343,7 @@ func (h *ItineraryHandler) DeleteItinerary(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -333,7 +362,7 @@ func (h *ItineraryHandler) DeleteItinerary(c *gin.Context) {
 			statusCode = http.StatusForbidden
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao deletar roteiro",
 			Message: errorMsg,
 		})
@@ -365,7 +394,7 @@ func (h *ItineraryHandler) DeleteItinerary(c *gin.Context) {
 func (h *ItineraryHandler) RateItinerary(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -375,7 +404,7 @@ func (h *ItineraryHandler) RateItinerary(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -384,7 +413,7 @@ func (h *ItineraryHandler) RateItinerary(c *gin.Context) {
 
 	var req RateItineraryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -405,7 +434,7 @@ func (h *ItineraryHandler) RateItinerary(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao avaliar roteiro",
 			Message: errorMsg,
 		})
@@ -436,7 +465,7 @@ func (h *ItineraryHandler) RateItinerary(c *gin.Context) {
 func (h *ItineraryHandler) UpdateRating(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -446,7 +475,7 @@ func (h *ItineraryHandler) UpdateRating(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -455,7 +484,7 @@ func (h *ItineraryHandler) UpdateRating(c *gin.Context) {
 
 	var req RateItineraryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -474,7 +503,7 @@ func (h *ItineraryHandler) UpdateRating(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao atualizar avaliação",
 			Message: errorMsg,
 		})
@@ -503,7 +532,7 @@ func (h *ItineraryHandler) UpdateRating(c *gin.Context) {
 func (h *ItineraryHandler) DeleteRating(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -513,7 +542,7 @@ func (h *ItineraryHandler) DeleteRating(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -527,7 +556,7 @@ func (h *ItineraryHandler) DeleteRating(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao deletar avaliação",
 			Message: err.Error(),
 		})
@@ -540,14 +569,144 @@ func (h *ItineraryHandler) DeleteRating(c *gin.Context) {
 	})
 }
 
+// LikeItinerary godoc
+// @Summary Like an itinerary
+// @Description Like a specific itinerary
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/like [post]
+func (h *ItineraryHandler) LikeItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	err = h.itineraryService.LikeItinerary(userID.(uint), uint(itineraryID))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrado"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "já curtiu"):
+			statusCode = http.StatusConflict
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao curtir roteiro",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro curtido com sucesso",
+		Data:    nil,
+	})
+}
+
+// UnlikeItinerary godoc
+// @Summary Unlike an itinerary
+// @Description Remove like from a specific itinerary
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/like [delete]
+func (h *ItineraryHandler) UnlikeItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	err = h.itineraryService.UnlikeItinerary(userID.(uint), uint(itineraryID))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMsg := err.Error()
+
+		if contains(errorMsg, "não encontrado") || contains(errorMsg, "não curtiu") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao descurtir roteiro",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Curtida removida com sucesso",
+		Data:    nil,
+	})
+}
+
 // SearchItineraries godoc
 // @Summary Search itineraries
-// @Description Search for itineraries by title, description, city or country
+// @Description Search for itineraries by title, description, city or country, with the same filters as the itinerary list. With lat/lng (and radius_km) or bbox, only itineraries with a location inside the area are returned; without q, those with the closest location first (radius) or the most liked (bbox). The first page (offset 0) also returns in facets the result counts by category, country, difficulty, duration range and price band (in the requested or the visitor's currency), to render the filter chips. Each facet is counted with the other filters applied but not its own
 // @Tags itineraries
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param q query string true "Search query"
+// @Param q query string false "Search query; required without an area"
+// @Param lat query number false "Latitude of the search center; matches itineraries with a location in the radius"
+// @Param lng query number false "Longitude of the search center"
+// @Param radius_km query number false "Search radius in kilometers (max 100)" default(10)
+// @Param bbox query string false "Bounding box as west,south,east,north, instead of lat/lng"
+// @Param category query string false "Filter by category"
+// @Param country query string false "Filter by country"
+// @Param city query string false "Filter by city"
+// @Param difficulty query int false "Filter by difficulty (1-5)"
+// @Param min_duration query int false "Minimum duration in days"
+// @Param max_duration query int false "Maximum duration in days"
+// @Param min_cost query number false "Minimum estimated cost in the chosen currency"
+// @Param max_cost query number false "Maximum estimated cost in the chosen currency"
+// @Param currency query string false "Currency for cost filters and price bands (defaults to the visitor's country currency)"
+// @Param facets query bool false "Set to false to skip the facet counts" default(true)
 // @Param limit query int false "Number of results per page" default(20)
 // @Param offset query int false "Number of results to skip" default(0)
 // @Success 200 {array} models.ItineraryResponse
@@ -558,18 +717,27 @@ func (h *ItineraryHandler) DeleteRating(c *gin.Context) {
 func (h *ItineraryHandler) SearchItineraries(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
 		return
 	}
 
+	geo, err := geoFilterFromQuery(c)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetros inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	query := c.Query("q")
-	if query == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+	if query == "" && geo == nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Parâmetro obrigatório",
-			Message: "O parâmetro 'q' (query) é obrigatório",
+			Message: "Informe o parâmetro 'q' (query) ou uma área ('lat' e 'lng', ou 'bbox')",
 		})
 		return
 	}
@@ -584,19 +752,57 @@ func (h *ItineraryHandler) SearchItineraries(c *gin.Context) {
 		offset = 0
 	}
 
-	itineraries, err := h.itineraryService.SearchItineraries(query, currentUserID.(uint), limit, offset)
+	req := &services.ItinerarySearchRequest{
+		Query:    query,
+		Category: models.ItineraryCategory(c.Query("category")),
+		Country:  c.Query("country"),
+		City:     c.Query("city"),
+		Currency: c.Query("currency"),
+		Geo:      geo,
+		Limit:    limit,
+		Offset:   offset,
+
+		// As contagens só mudam com os filtros, então vêm na primeira página
+		Facets: offset == 0 && c.Query("facets") != "false",
+
+		ViewerCountry: requestCountry(c),
+	}
+
+	if val, err := strconv.Atoi(c.Query("min_duration")); err == nil {
+		req.MinDuration = val
+	}
+	if val, err := strconv.Atoi(c.Query("max_duration")); err == nil {
+		req.MaxDuration = val
+	}
+	if val, err := strconv.Atoi(c.Query("difficulty")); err == nil {
+		req.Difficulty = val
+	}
+	if val, err := strconv.ParseFloat(c.Query("min_cost"), 64); err == nil {
+		req.MinCost = val
+	}
+	if val, err := strconv.ParseFloat(c.Query("max_cost"), 64); err == nil {
+		req.MaxCost = val
+	}
+
+	itineraries, facets, err := h.itineraryService.SearchItinerariesWithFacets(req, currentUserID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
 			Error:   "Erro na busca de roteiros",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, SuccessResponse{
+	itineraries = filterRestrictedItineraries(c, h.complianceService, itineraries)
+
+	response := SuccessResponse{
 		Message: "Busca realizada com sucesso",
 		Data:    itineraries,
-	})
+	}
+	if facets != nil {
+		response.Facets = facets
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // GetItinerariesByAuthor godoc
@@ -617,7 +823,7 @@ func (h *ItineraryHandler) SearchItineraries(c *gin.Context) {
 func (h *ItineraryHandler) GetItinerariesByAuthor(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -626,7 +832,7 @@ func (h *ItineraryHandler) GetItinerariesByAuthor(c *gin.Context) {
 
 	authorIDParam := c.Query("authorId")
 	if authorIDParam == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Parâmetro obrigatório",
 			Message: "O parâmetro 'authorId' é obrigatório",
 		})
@@ -635,7 +841,7 @@ func (h *ItineraryHandler) GetItinerariesByAuthor(c *gin.Context) {
 
 	authorID, err := strconv.ParseUint(authorIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do autor deve ser um número válido",
 		})
@@ -654,13 +860,15 @@ func (h *ItineraryHandler) GetItinerariesByAuthor(c *gin.Context) {
 
 	itineraries, err := h.itineraryService.GetItinerariesByAuthor(uint(authorID), currentUserID.(uint), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "Erro ao buscar roteiros do autor",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	itineraries = filterRestrictedItineraries(c, h.complianceService, itineraries)
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Roteiros encontrados",
 		Data:    itineraries,
@@ -669,7 +877,7 @@ func (h *ItineraryHandler) GetItinerariesByAuthor(c *gin.Context) {
 
 // GetSimilarItineraries godoc
 // @Summary Get similar itineraries
-// @Description Get itineraries similar to a specific one
+// @Description Get public itineraries ranked by a weighted similarity score: same category, destination proximity, similar duration, daily cost in the same band and shared places
 // @Tags itineraries
 // @Accept json
 // @Produce json
@@ -684,7 +892,7 @@ func (h *ItineraryHandler) GetItinerariesByAuthor(c *gin.Context) {
 func (h *ItineraryHandler) GetSimilarItineraries(c *gin.Context) {
 	_, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -694,7 +902,7 @@ func (h *ItineraryHandler) GetSimilarItineraries(c *gin.Context) {
 	idParam := c.Param("id")
 	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do roteiro deve ser um número válido",
 		})
@@ -708,19 +916,291 @@ func (h *ItineraryHandler) GetSimilarItineraries(c *gin.Context) {
 
 	itineraries, err := h.itineraryService.GetSimilarItineraries(uint(itineraryID), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "Erro ao buscar roteiros similares",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	itineraries = filterRestrictedItineraries(c, h.complianceService, itineraries)
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Roteiros similares encontrados",
 		Data:    itineraries,
 	})
 }
 
+// GetTripSuggestions godoc
+// @Summary Get "complete your trip" suggestions
+// @Description Suggest places from other public itineraries in the same destination that could be added to this one
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {array} models.PlaceSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/suggestions [get]
+func (h *ItineraryHandler) GetTripSuggestions(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	idParam := c.Param("id")
+	itineraryID, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	suggestions, err := h.itineraryService.GetTripSuggestions(uint(itineraryID), currentUserID.(uint), limit)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao buscar sugestões",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Sugestões encontradas",
+		Data:    suggestions,
+	})
+}
+
+// AddSuggestedPlace godoc
+// @Summary Add a suggested place to a day
+// @Description Add a suggested place to an itinerary day. Authors edit the itinerary directly; other users get a private copy with the place added
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param dayId path int true "Day ID"
+// @Param request body services.AddSuggestedPlaceRequest true "Suggested location"
+// @Success 200 {object} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/days/{dayId}/suggestions [post]
+func (h *ItineraryHandler) AddSuggestedPlace(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	dayID, err := strconv.ParseUint(c.Param("dayId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do dia deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.AddSuggestedPlaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	itinerary, err := h.itineraryService.AddSuggestedPlace(uint(itineraryID), uint(dayID), userID.(uint), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao adicionar local",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Local adicionado com sucesso",
+		Data:    itinerary,
+	})
+}
+
+// CloneItinerary godoc
+// @Summary Clone an itinerary
+// @Description Copy a visible itinerary, with its days and locations, into a new private itinerary owned by the current user ("use this itinerary"). The copy records the original in cloned_from_id and the original's clones_count is incremented
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 201 {object} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/clone [post]
+func (h *ItineraryHandler) CloneItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	itinerary, err := h.itineraryService.CloneItinerary(uint(itineraryID), userID.(uint))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao copiar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Roteiro copiado com sucesso",
+		Data:    itinerary,
+	})
+}
+
+// ImportItineraryTrack godoc
+// @Summary Import days and locations from GPX/KML
+// @Description Bootstrap the itinerary from a GPX or KML file: each GPX route, each GPX date (waypoints by time) or each KML folder becomes a new day after the existing ones, and each waypoint/point placemark becomes a location. Points with invalid coordinates are skipped and listed. Only the author can import
+// @Tags itineraries
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param file formData file true "GPX or KML file (up to 5 MB)"
+// @Success 201 {object} services.TrackImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/import [post]
+func (h *ItineraryHandler) ImportItineraryTrack(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo não encontrado",
+			Message: "É necessário enviar um arquivo no campo 'file'",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo inválido",
+			Message: "Não foi possível ler o arquivo enviado",
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo inválido",
+			Message: "Não foi possível ler o arquivo enviado",
+		})
+		return
+	}
+
+	result, err := h.itineraryService.ImportTrack(uint(itineraryID), userID.(uint), fileHeader.Filename, data)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao importar arquivo",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Arquivo importado com sucesso",
+		Data:    result,
+	})
+}
+
 // Structs auxiliares
 type RateItineraryRequest struct {
 	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
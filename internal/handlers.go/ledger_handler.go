@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	ledgerService services.LedgerServiceInterface
+}
+
+func NewLedgerHandler(ledgerService services.LedgerServiceInterface) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// GetBalance godoc
+// @Summary Get balance
+// @Description Get the available and payout-reserved balances of the current user per currency
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.LedgerBalance
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /ledger/balance [get]
+func (h *LedgerHandler) GetBalance(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	balances, err := h.ledgerService.GetBalances(userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar saldo",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Saldo obtido com sucesso",
+		Data:    balances,
+	})
+}
+
+// GetEntries godoc
+// @Summary Get statement
+// @Description Get the ledger entries affecting the available balance of the current user
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of entries per page" default(20)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {array} models.LedgerEntryResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /ledger/entries [get]
+func (h *LedgerHandler) GetEntries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	entries, err := h.ledgerService.GetEntries(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar extrato",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Extrato obtido com sucesso",
+		Data:    entries,
+	})
+}
+
+// RequestPayout godoc
+// @Summary Request a payout
+// @Description Reserve part of the available balance and schedule a payout to the creator's payout account
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.PayoutRequest true "Payout amount in cents"
+// @Success 201 {object} models.PayoutResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payouts [post]
+func (h *LedgerHandler) RequestPayout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.PayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payout, err := h.ledgerService.RequestPayout(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao solicitar saque",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Saque agendado com sucesso",
+		Data:    payout,
+	})
+}
+
+// GetPayouts godoc
+// @Summary List payouts
+// @Description Get the payouts requested by the current user
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of payouts per page" default(20)
+// @Param offset query int false "Number of payouts to skip" default(0)
+// @Success 200 {array} models.PayoutResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payouts [get]
+func (h *LedgerHandler) GetPayouts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	payouts, err := h.ledgerService.GetPayouts(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar saques",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Saques obtidos com sucesso",
+		Data:    payouts,
+	})
+}
+
+// ProcessPayouts godoc
+// @Summary Process due payouts (admin)
+// @Description Send the scheduled payouts that are due to the payment provider without waiting for the scheduler; each payout is claimed before sending, and payouts without a definitive provider answer stay in processing for reconciliation
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/payouts/process [post]
+func (h *LedgerHandler) ProcessPayouts(c *gin.Context) {
+	paid, err := h.ledgerService.ProcessDuePayouts()
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao processar saques",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Saques processados",
+		Data:    gin.H{"paid": paid},
+	})
+}
+
+// GetReconciliation godoc
+// @Summary Ledger reconciliation report (admin)
+// @Description Get ledger totals per transaction kind, the inconsistencies between the ledger and the tips table and the payouts stuck in processing; defaults to the current month
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End date (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} models.ReconciliationReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ledger/reconciliation [get]
+func (h *LedgerHandler) GetReconciliation(c *gin.Context) {
+	from, err := parseDateParam(c.Query("from"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'from' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	to, err := parseDateParam(c.Query("to"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'to' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	report, err := h.ledgerService.GetReconciliationReport(from, to)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao gerar conciliação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conciliação gerada com sucesso",
+		Data:    report,
+	})
+}
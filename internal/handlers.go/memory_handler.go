@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MemoryHandler struct {
+	memoryService services.MemoryServiceInterface
+}
+
+func NewMemoryHandler(memoryService services.MemoryServiceInterface) *MemoryHandler {
+	return &MemoryHandler{
+		memoryService: memoryService,
+	}
+}
+
+// GetMemories godoc
+// @Summary On this day
+// @Description Get the current user's posts and itineraries created on the same day in previous years, grouped by year
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Reference date (RFC 3339 or YYYY-MM-DD); defaults to today"
+// @Success 200 {object} models.MemoriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/memories [get]
+func (h *MemoryHandler) GetMemories(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	date, err := parseDateParam(c.Query("date"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'date' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	memories, err := h.memoryService.GetMemories(userID.(uint), date)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar lembranças",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lembranças obtidas com sucesso",
+		Data:    memories,
+	})
+}
+
+// UpdateMemorySettings godoc
+// @Summary Update memories preference
+// @Description Enable or disable "on this day" memories and their daily notification for the current user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.MemorySettingsRequest true "Memories preference"
+// @Success 200 {object} models.MemoriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/memories/settings [put]
+func (h *MemoryHandler) UpdateMemorySettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.MemorySettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	memories, err := h.memoryService.SetMemoriesEnabled(userID.(uint), *req.Enabled)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar preferência",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Preferência de lembranças atualizada com sucesso",
+		Data:    memories,
+	})
+}
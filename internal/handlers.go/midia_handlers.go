@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Ulpio/guIA-backend/internal/services"
@@ -37,7 +38,7 @@ func NewMediaHandler(mediaService services.MediaServiceInterface) *MediaHandler
 func (h *MediaHandler) UploadImage(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -47,7 +48,7 @@ func (h *MediaHandler) UploadImage(c *gin.Context) {
 	// Obter arquivo do form
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Arquivo não encontrado",
 			Message: "É necessário enviar um arquivo no campo 'file'",
 		})
@@ -63,11 +64,12 @@ func (h *MediaHandler) UploadImage(c *gin.Context) {
 		switch {
 		case strings.Contains(errorMsg, "muito grande"):
 			statusCode = http.StatusRequestEntityTooLarge
-		case strings.Contains(errorMsg, "não permitida"), strings.Contains(errorMsg, "não suportado"):
+		case strings.Contains(errorMsg, "não permitida"), strings.Contains(errorMsg, "não suportado"),
+			strings.Contains(errorMsg, "conteúdo do arquivo"), strings.Contains(errorMsg, "inválida"):
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro no upload da imagem",
 			Message: errorMsg,
 		})
@@ -97,7 +99,7 @@ func (h *MediaHandler) UploadImage(c *gin.Context) {
 func (h *MediaHandler) UploadVideo(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -107,7 +109,7 @@ func (h *MediaHandler) UploadVideo(c *gin.Context) {
 	// Obter arquivo do form
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Arquivo não encontrado",
 			Message: "É necessário enviar um arquivo no campo 'file'",
 		})
@@ -123,11 +125,12 @@ func (h *MediaHandler) UploadVideo(c *gin.Context) {
 		switch {
 		case strings.Contains(errorMsg, "muito grande"):
 			statusCode = http.StatusRequestEntityTooLarge
-		case strings.Contains(errorMsg, "não permitida"), strings.Contains(errorMsg, "não suportado"):
+		case strings.Contains(errorMsg, "não permitida"), strings.Contains(errorMsg, "não suportado"),
+			strings.Contains(errorMsg, "conteúdo do arquivo"), strings.Contains(errorMsg, "inválida"):
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro no upload do vídeo",
 			Message: errorMsg,
 		})
@@ -158,7 +161,7 @@ func (h *MediaHandler) UploadVideo(c *gin.Context) {
 func (h *MediaHandler) UploadMultiple(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -168,7 +171,7 @@ func (h *MediaHandler) UploadMultiple(c *gin.Context) {
 	// Obter form
 	form, err := c.MultipartForm()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Erro no formulário",
 			Message: err.Error(),
 		})
@@ -177,7 +180,7 @@ func (h *MediaHandler) UploadMultiple(c *gin.Context) {
 
 	files := form.File["files"]
 	if len(files) == 0 {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Nenhum arquivo encontrado",
 			Message: "É necessário enviar pelo menos um arquivo no campo 'files'",
 		})
@@ -187,7 +190,7 @@ func (h *MediaHandler) UploadMultiple(c *gin.Context) {
 	// Limitar número de arquivos
 	maxFiles := 10
 	if len(files) > maxFiles {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Muitos arquivos",
 			Message: fmt.Sprintf("Máximo de %d arquivos por vez", maxFiles),
 		})
@@ -256,7 +259,7 @@ func (h *MediaHandler) UploadMultiple(c *gin.Context) {
 
 // DeleteMedia godoc
 // @Summary Delete a media file
-// @Description Delete an uploaded media file
+// @Description Delete an uploaded media file. Only the owner (or an admin) can delete it, and only while no post, story, profile, itinerary, check-in or experience still uses it
 // @Tags media
 // @Accept json
 // @Produce json
@@ -265,12 +268,15 @@ func (h *MediaHandler) UploadMultiple(c *gin.Context) {
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /media/delete [delete]
 func (h *MediaHandler) DeleteMedia(c *gin.Context) {
-	_, exists := c.Get("user_id")
+	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -279,7 +285,7 @@ func (h *MediaHandler) DeleteMedia(c *gin.Context) {
 
 	var req DeleteMediaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -287,16 +293,21 @@ func (h *MediaHandler) DeleteMedia(c *gin.Context) {
 	}
 
 	if req.FilePath == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Caminho do arquivo obrigatório",
 			Message: "O campo 'file_path' é obrigatório",
 		})
 		return
 	}
 
-	err := h.mediaService.DeleteFile(req.FilePath)
+	err := h.mediaService.DeleteMedia(req.FilePath, userID.(uint), isAdmin(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		statusCode := errorStatusCode(err.Error())
+		if strings.Contains(err.Error(), "em uso") {
+			statusCode = http.StatusConflict
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao deletar arquivo",
 			Message: err.Error(),
 		})
@@ -311,20 +322,22 @@ func (h *MediaHandler) DeleteMedia(c *gin.Context) {
 
 // GetMediaInfo godoc
 // @Summary Get media file information
-// @Description Get information about a media file
+// @Description Get the record of an uploaded media file (owner, type, size, dimensions) and how many contents still use it. Only the owner or an admin can see it
 // @Tags media
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param file_path query string true "File path"
-// @Success 200 {object} MediaInfoResponse
+// @Success 200 {object} services.MediaInfo
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /media/info [get]
 func (h *MediaHandler) GetMediaInfo(c *gin.Context) {
-	_, exists := c.Get("user_id")
+	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -333,24 +346,73 @@ func (h *MediaHandler) GetMediaInfo(c *gin.Context) {
 
 	filePath := c.Query("file_path")
 	if filePath == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Parâmetro obrigatório",
 			Message: "O parâmetro 'file_path' é obrigatório",
 		})
 		return
 	}
 
-	url := h.mediaService.GetFileURL(filePath)
-
-	response := MediaInfoResponse{
-		FilePath:  filePath,
-		URL:       url,
-		MediaType: h.determineMediaType(filePath),
+	info, err := h.mediaService.GetMediaInfo(filePath, userID.(uint), isAdmin(c))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar arquivo",
+			Message: err.Error(),
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Informações do arquivo",
-		Data:    response,
+		Data:    info,
+	})
+}
+
+// GetMedia godoc
+// @Summary Get media by ID
+// @Description Get an uploaded media with its processing status (uploaded, processing, ready or failed) and moderation status; usable is true once the media is ready and approved. Only the owner or an admin can see it
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Media ID"
+// @Success 200 {object} services.MediaInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /media/{id} [get]
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da mídia deve ser um número válido",
+		})
+		return
+	}
+
+	info, err := h.mediaService.GetMediaByID(uint(mediaID), userID.(uint), isAdmin(c))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar mídia",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Mídia encontrada",
+		Data:    info,
 	})
 }
 
@@ -396,9 +458,3 @@ type FailedUpload struct {
 type DeleteMediaRequest struct {
 	FilePath string `json:"file_path" binding:"required"`
 }
-
-type MediaInfoResponse struct {
-	FilePath  string             `json:"file_path"`
-	URL       string             `json:"url"`
-	MediaType services.MediaType `json:"media_type"`
-}
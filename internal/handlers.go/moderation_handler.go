@@ -0,0 +1,761 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	moderationService services.ModerationServiceInterface
+}
+
+func NewModerationHandler(moderationService services.ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// RestorePost godoc
+// @Summary Restore a hidden post (admin)
+// @Description Make a post hidden by reports visible again, dismissing its pending reports
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} models.PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/posts/{id}/restore [post]
+func (h *ModerationHandler) RestorePost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	post, err := h.moderationService.RestorePost(uint(postID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao restaurar post",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Post restaurado com sucesso",
+		Data:    post,
+	})
+}
+
+// RemovePost godoc
+// @Summary Permanently remove a post (admin)
+// @Description Permanently delete a post with its likes and comments, upholding its pending reports
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/posts/{id} [delete]
+func (h *ModerationHandler) RemovePost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.moderationService.RemovePost(uint(postID), userID.(uint), &req); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover post",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Post removido definitivamente",
+	})
+}
+
+// RestoreItinerary godoc
+// @Summary Restore a reported itinerary (admin)
+// @Description Put an itinerary removed from listings by reports back into listings, dismissing its pending reports
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/itineraries/{id}/restore [post]
+func (h *ModerationHandler) RestoreItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	itinerary, err := h.moderationService.RestoreItinerary(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao restaurar roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro restaurado com sucesso",
+		Data:    itinerary,
+	})
+}
+
+// RemoveItinerary godoc
+// @Summary Remove a reported itinerary (admin)
+// @Description Delete an itinerary, upholding its pending reports
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/itineraries/{id} [delete]
+func (h *ModerationHandler) RemoveItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.moderationService.RemoveItinerary(uint(itineraryID), userID.(uint), &req); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro removido com sucesso",
+	})
+}
+
+// ReportMessage godoc
+// @Summary Report a message
+// @Description Report a message received in a conversation with a categorized reason. A copy of the message is kept for the moderators
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param messageId path int true "Message ID"
+// @Param request body services.ReportMessageRequest true "Report reason (spam, harassment, hate_speech, nudity, violence, misinformation, copyright, other)"
+// @Success 201 {object} models.MessageReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/messages/{messageId}/report [post]
+func (h *ModerationHandler) ReportMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	messageID, err := strconv.ParseUint(c.Param("messageId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da mensagem deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ReportMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	report, err := h.moderationService.ReportMessage(uint(conversationID), uint(messageID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao denunciar mensagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Denúncia registrada com sucesso",
+		Data:    report,
+	})
+}
+
+// GetMessageReports godoc
+// @Summary Message moderation queue (admin)
+// @Description Get message reports by status, oldest first. The reported content is not included; it is shown only in the report detail
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Report status (pending, resolved, dismissed)" default(pending)
+// @Param limit query int false "Number of reports per page" default(20)
+// @Param offset query int false "Number of reports to skip" default(0)
+// @Success 200 {array} models.MessageReportResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/reports/messages [get]
+func (h *ModerationHandler) GetMessageReports(c *gin.Context) {
+	limit, offset := paginationParams(c)
+	status := models.ReportStatus(c.Query("status"))
+
+	reports, err := h.moderationService.GetMessageReports(status, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar denúncias",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Denúncias obtidas com sucesso",
+		Data:    reports,
+	})
+}
+
+// GetMessageReport godoc
+// @Summary Message report detail (admin)
+// @Description Get a message report with the reported content, its attachment and the surrounding messages of the conversation. Every access is recorded in the moderation audit log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report ID"
+// @Success 200 {object} models.MessageReportDetail
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reports/messages/{id} [get]
+func (h *ModerationHandler) GetMessageReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da denúncia deve ser um número válido",
+		})
+		return
+	}
+
+	report, err := h.moderationService.GetMessageReport(uint(reportID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar denúncia",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Denúncia obtida com sucesso",
+		Data:    report,
+	})
+}
+
+// ResolveMessageReport godoc
+// @Summary Resolve a message report (admin)
+// @Description Mark a single message report as resolved (upheld) or dismissed without changing the message
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report ID"
+// @Param request body services.ResolveReportRequest true "Resolution"
+// @Success 200 {object} models.MessageReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reports/messages/{id} [put]
+func (h *ModerationHandler) ResolveMessageReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da denúncia deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	report, err := h.moderationService.ResolveMessageReport(uint(reportID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao resolver denúncia",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Denúncia resolvida com sucesso",
+		Data:    report,
+	})
+}
+
+// RemoveMessage godoc
+// @Summary Remove a reported message (admin)
+// @Description Delete a message from its conversation for every participant, upholding its pending reports
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/messages/{id} [delete]
+func (h *ModerationHandler) RemoveMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da mensagem deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.moderationService.RemoveMessage(uint(messageID), userID.(uint), &req); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover mensagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Mensagem removida com sucesso",
+	})
+}
+
+// BulkModeration godoc
+// @Summary Bulk moderation action (admin)
+// @Description Hide posts, delete posts or ban users in batches of up to 500 IDs. With dry_run the outcome for each ID is returned without changing anything; otherwise a background job is queued and its progress can be followed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.BulkModerationRequest true "Action (hide_posts, delete_posts, ban_users) and target IDs"
+// @Success 200 {object} models.BulkModerationPreview
+// @Success 202 {object} models.BulkModerationJob
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/moderation/bulk [post]
+func (h *ModerationHandler) BulkModeration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.BulkModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.DryRun {
+		preview, err := h.moderationService.PreviewBulkModeration(&req)
+		if err != nil {
+			errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+				Error:   "Erro ao simular lote de moderação",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: "Simulação do lote concluída",
+			Data:    preview,
+		})
+		return
+	}
+
+	job, err := h.moderationService.CreateBulkModerationJob(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar lote de moderação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: "Lote de moderação enfileirado",
+		Data:    job,
+	})
+}
+
+// GetBulkModerationJob godoc
+// @Summary Bulk moderation job progress (admin)
+// @Description Get the status, progress counters and per-ID results of a bulk moderation job
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Job ID"
+// @Success 200 {object} models.BulkModerationJob
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/moderation/jobs/{id} [get]
+func (h *ModerationHandler) GetBulkModerationJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do lote deve ser um número válido",
+		})
+		return
+	}
+
+	job, err := h.moderationService.GetBulkModerationJob(uint(jobID))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar lote de moderação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lote de moderação obtido com sucesso",
+		Data:    job,
+	})
+}
+
+// GetModerationActions godoc
+// @Summary Moderation audit log (admin)
+// @Description Get moderation actions, most recent first, optionally filtered by admin or target
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param admin_id query int false "Admin who took the action"
+// @Param target_type query string false "Target type (post, user, post_report, itinerary, itinerary_report, media, message, message_report)"
+// @Param target_id query int false "Target ID"
+// @Param limit query int false "Number of actions per page" default(20)
+// @Param offset query int false "Number of actions to skip" default(0)
+// @Success 200 {array} models.ModerationAction
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/moderation/actions [get]
+func (h *ModerationHandler) GetModerationActions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	// Filtros inválidos ou ausentes são ignorados
+	adminID, _ := strconv.ParseUint(c.Query("admin_id"), 10, 32)
+	targetID, _ := strconv.ParseUint(c.Query("target_id"), 10, 32)
+	targetType := models.ModerationTargetType(c.Query("target_type"))
+
+	actions, err := h.moderationService.GetModerationActions(uint(adminID), targetType, uint(targetID), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar histórico de moderação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Histórico de moderação obtido com sucesso",
+		Data:    actions,
+	})
+}
+
+// GetFlaggedMedia godoc
+// @Summary Image moderation queue (admin)
+// @Description Get images quarantined by the automatic moderation, oldest first, with the detected labels
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Moderation status (flagged, approved, rejected)" default(flagged)
+// @Param limit query int false "Number of media per page" default(20)
+// @Param offset query int false "Number of media to skip" default(0)
+// @Success 200 {array} models.FlaggedMediaResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/moderation/media [get]
+func (h *ModerationHandler) GetFlaggedMedia(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	status := models.MediaModerationStatus(c.Query("status"))
+
+	media, err := h.moderationService.GetFlaggedMedia(status, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar mídias sinalizadas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Mídias sinalizadas obtidas com sucesso",
+		Data:    media,
+	})
+}
+
+// ApproveMedia godoc
+// @Summary Approve a flagged image (admin)
+// @Description Release an image quarantined by the automatic moderation so it can be used in posts and stories
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Media ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} models.FlaggedMediaResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/moderation/media/{id}/approve [post]
+func (h *ModerationHandler) ApproveMedia(c *gin.Context) {
+	h.reviewMedia(c, h.moderationService.ApproveMedia, "Erro ao aprovar mídia", "Mídia aprovada com sucesso")
+}
+
+// RejectMedia godoc
+// @Summary Reject a flagged image (admin)
+// @Description Reject an image quarantined by the automatic moderation, deleting its files from storage
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Media ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} models.FlaggedMediaResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/moderation/media/{id}/reject [post]
+func (h *ModerationHandler) RejectMedia(c *gin.Context) {
+	h.reviewMedia(c, h.moderationService.RejectMedia, "Erro ao recusar mídia", "Mídia recusada com sucesso")
+}
+
+func (h *ModerationHandler) reviewMedia(
+	c *gin.Context,
+	review func(mediaID, adminID uint, req *services.ModerationActionRequest) (*models.FlaggedMediaResponse, error),
+	errorTitle, successMessage string,
+) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da mídia deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	media, err := review(uint(mediaID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   errorTitle,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: successMessage,
+		Data:    media,
+	})
+}
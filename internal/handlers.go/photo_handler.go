@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PhotoHandler struct {
+	photoService services.PhotoServiceInterface
+}
+
+func NewPhotoHandler(photoService services.PhotoServiceInterface) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+	}
+}
+
+// OrganizePhotos godoc
+// @Summary Organize trip photos by itinerary day
+// @Description Bulk-upload trip photos and get a proposed day/location for each one, matched by EXIF GPS within a radius or by EXIF time against the locations' schedule. Nothing is saved to the itinerary until confirmed
+// @Tags itineraries
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param files formData file true "Photos (multiple, up to 30)"
+// @Param start_date formData string false "Date of the first trip day (YYYY-MM-DD)"
+// @Param radius_km formData number false "Max distance from a location for GPS matching" default(1)
+// @Param window_minutes formData int false "Tolerance around a location's schedule for time matching" default(60)
+// @Success 200 {object} services.PhotoOrganizationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/photos/organize [post]
+func (h *PhotoHandler) OrganizePhotos(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Erro no formulário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Nenhum arquivo encontrado",
+			Message: "É necessário enviar pelo menos uma foto no campo 'files'",
+		})
+		return
+	}
+
+	startDate, err := parseDateParam(c.PostForm("start_date"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O campo 'start_date' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	req := services.OrganizePhotosRequest{StartDate: startDate}
+	if radius, err := strconv.ParseFloat(c.PostForm("radius_km"), 64); err == nil {
+		req.RadiusKm = radius
+	}
+	if window, err := strconv.Atoi(c.PostForm("window_minutes")); err == nil {
+		req.WindowMinutes = window
+	}
+
+	result, err := h.photoService.OrganizePhotos(uint(itineraryID), userID.(uint), files, &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao organizar fotos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Fotos enviadas; confirme a organização proposta",
+		Data:    result,
+	})
+}
+
+// ConfirmPhotos godoc
+// @Summary Confirm trip photo organization
+// @Description Save the accepted (or corrected) photo assignments to the galleries of the itinerary days and locations
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.ConfirmPhotosRequest true "Confirmed assignments"
+// @Success 200 {object} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/photos/confirm [post]
+func (h *PhotoHandler) ConfirmPhotos(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ConfirmPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	itinerary, err := h.photoService.ConfirmPhotos(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao salvar fotos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Fotos adicionadas ao roteiro com sucesso",
+		Data:    itinerary,
+	})
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PlaceHandler struct {
+	placeService services.PlaceServiceInterface
+}
+
+func NewPlaceHandler(placeService services.PlaceServiceInterface) *PlaceHandler {
+	return &PlaceHandler{
+		placeService: placeService,
+	}
+}
+
+// PreviewPlacesImport godoc
+// @Summary Preview an import of Google Maps saved places
+// @Description Dry run of the import of a Google Takeout "Saved Places.json" (GeoJSON) or a Google Maps list exported as CSV. Places without coordinates are geocoded, and places already in the itinerary or repeated in the file are marked as duplicate. Nothing is saved; the "new" items can be sent to /itineraries/{id}/places/import. Only the author can import
+// @Tags itineraries
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param file formData file true "JSON or CSV export (up to 2 MB, 200 places)"
+// @Success 200 {object} services.PlaceImportPreview
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/places/import/preview [post]
+func (h *PlaceHandler) PreviewPlacesImport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo não encontrado",
+			Message: "É necessário enviar um arquivo no campo 'file'",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo inválido",
+			Message: "Não foi possível ler o arquivo enviado",
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo inválido",
+			Message: "Não foi possível ler o arquivo enviado",
+		})
+		return
+	}
+
+	preview, err := h.placeService.PreviewPlacesImport(uint(itineraryID), userID.(uint), fileHeader.Filename, data)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao ler lugares",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Prévia da importação gerada",
+		Data:    preview,
+	})
+}
+
+// ImportPlaces godoc
+// @Summary Import saved places into an itinerary
+// @Description Create itinerary locations in bulk from the places returned by the preview. Without day_id a new day is added after the existing ones. Places without valid coordinates or already in the itinerary are skipped and listed. Only the author can import
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.ImportPlacesRequest true "Places to import"
+// @Success 201 {object} services.ImportPlacesResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/places/import [post]
+func (h *PlaceHandler) ImportPlaces(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ImportPlacesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.placeService.ImportPlaces(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao importar lugares",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Lugares importados com sucesso",
+		Data:    result,
+	})
+}
+
+// EnrichLocation godoc
+// @Summary Enrich a location from its Google place ID
+// @Description Fill the location's address, coordinates, phone, website, opening hours, rating and photos from the places provider, using its google_place_id. Place details are cached to save API quota. Only the author can enrich
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param locationId path int true "Location ID"
+// @Success 200 {object} models.ItineraryLocation
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/locations/{locationId}/enrich [post]
+func (h *PlaceHandler) EnrichLocation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	locationID, err := strconv.ParseUint(c.Param("locationId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do local deve ser um número válido",
+		})
+		return
+	}
+
+	location, err := h.placeService.EnrichLocation(uint(itineraryID), uint(locationID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao enriquecer local",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Local atualizado com sucesso",
+		Data:    location,
+	})
+}
+
+// EnrichItinerary godoc
+// @Summary Enrich all itinerary locations from their Google place IDs
+// @Description Enrich every location that has a google_place_id, as in /itineraries/{id}/locations/{locationId}/enrich. Locations that fail are listed without stopping the others. Only the author can enrich
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} services.PlaceEnrichmentResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/places/enrich [post]
+func (h *PlaceHandler) EnrichItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	result, err := h.placeService.EnrichItinerary(uint(itineraryID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao enriquecer locais",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Locais atualizados com sucesso",
+		Data:    result,
+	})
+}
+
+// AutocompletePlaces godoc
+// @Summary Autocomplete places
+// @Description Search places for the itinerary editor through the configured provider (Google Places, with an optional Nominatim fallback), keeping the API key on the server. Send the same session_token (UUID) on every keystroke and on the /places/details call of the chosen place, so Google bills them as one session. Rate limited per user
+// @Tags places
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search text (at least 2 characters)"
+// @Param session_token query string false "Autocomplete session token (UUID)"
+// @Param lat query number false "Latitude to bias results"
+// @Param lng query number false "Longitude to bias results"
+// @Success 200 {array} services.PlaceSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /places/autocomplete [get]
+func (h *PlaceHandler) AutocompletePlaces(c *gin.Context) {
+	query := &services.PlaceAutocompleteQuery{
+		Input:        c.Query("q"),
+		SessionToken: c.Query("session_token"),
+	}
+
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		latitude, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		longitude, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+		if latErr != nil || lngErr != nil {
+			errorJSON(c, http.StatusBadRequest, ErrorResponse{
+				Error:   "Parâmetros inválidos",
+				Message: "Os parâmetros 'lat' e 'lng' devem ser números válidos",
+			})
+			return
+		}
+		query.Latitude, query.Longitude = &latitude, &longitude
+	}
+
+	suggestions, err := h.placeService.Autocomplete(query)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar lugares",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lugares encontrados",
+		Data:    suggestions,
+	})
+}
+
+// GetPlaceDetails godoc
+// @Summary Get place details
+// @Description Get address, coordinates, contact, opening hours, rating and photos of a place chosen in the autocomplete, ending the autocomplete session. Details are cached to save API quota
+// @Tags places
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param placeId path string true "Google place ID"
+// @Param session_token query string false "Autocomplete session token (UUID)"
+// @Success 200 {object} models.PlaceDetails
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /places/details/{placeId} [get]
+func (h *PlaceHandler) GetPlaceDetails(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	details, err := h.placeService.GetPlaceDetails(c.Param("placeId"), c.Query("session_token"), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar lugar",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lugar encontrado",
+		Data:    details,
+	})
+}
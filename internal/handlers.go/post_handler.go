@@ -4,37 +4,45 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/Ulpio/guIA-backend/internal/models"
 	"github.com/Ulpio/guIA-backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 type PostHandler struct {
-	postService services.PostServiceInterface
+	postService       services.PostServiceInterface
+	riskService       services.RiskServiceInterface
+	complianceService services.ComplianceServiceInterface
 }
 
-func NewPostHandler(postService services.PostServiceInterface) *PostHandler {
+func NewPostHandler(postService services.PostServiceInterface, riskService services.RiskServiceInterface, complianceService services.ComplianceServiceInterface) *PostHandler {
 	return &PostHandler{
-		postService: postService,
+		postService:       postService,
+		riskService:       riskService,
+		complianceService: complianceService,
 	}
 }
 
 // CreatePost godoc
 // @Summary Create a new post
-// @Description Create a new post with text, images or videos
+// @Description Create a new post with text, images or videos. Requests flagged as automated are refused. The same text posted repeatedly or a post made mostly of links is flagged for moderation as spam, and past the spam score limit the post is refused and the account cannot post or follow for a while (429)
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body services.CreatePostRequest true "Post creation data"
+// @Param X-Form-Started-At header int false "When the post composer was opened (Unix milliseconds)"
 // @Success 201 {object} models.PostResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /posts [post]
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -43,23 +51,33 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 	var req services.CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	post, err := h.postService.CreatePost(userID.(uint), &req)
+	authorID := userID.(uint)
+	if h.riskService.Assess(botRisk(c), models.RiskActionCreatePost, &authorID) {
+		respondBotBlocked(c)
+		return
+	}
+
+	post, err := h.postService.CreatePost(authorID, &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		errorMsg := err.Error()
 
-		if contains(errorMsg, "obrigatório") || contains(errorMsg, "inválido") || contains(errorMsg, "deve ter") {
+		if contains(errorMsg, "obrigatório") || contains(errorMsg, "inválido") || contains(errorMsg, "deve ter") ||
+			contains(errorMsg, "roteiro") {
 			statusCode = http.StatusBadRequest
 		}
+		if contains(errorMsg, "limitada por suspeita de spam") {
+			statusCode = http.StatusTooManyRequests
+		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao criar post",
 			Message: errorMsg,
 		})
@@ -74,21 +92,23 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 // GetFeed godoc
 // @Summary Get user feed
-// @Description Get the personalized feed for the authenticated user
+// @Description Get the personalized feed for the authenticated user, assembled according to their feed settings (content mix, suggested posts, reposts)
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Number of posts per page" default(20)
 // @Param offset query int false "Number of posts to skip" default(0)
+// @Param cursor query string false "Opaque cursor returned as next_cursor by the previous page; takes precedence over offset"
 // @Success 200 {array} models.PostResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /posts [get]
 func (h *PostHandler) GetFeed(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -105,18 +125,21 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 		offset = 0
 	}
 
-	posts, err := h.postService.GetFeed(userID.(uint), limit, offset)
+	posts, nextCursor, err := h.postService.GetFeed(userID.(uint), c.Query("cursor"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
 			Error:   "Erro ao buscar feed",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	posts = filterRestrictedPosts(c, h.complianceService, posts)
+
 	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Feed encontrado",
-		Data:    posts,
+		Message:    "Feed encontrado",
+		Data:       posts,
+		NextCursor: nextCursor,
 	})
 }
 
@@ -137,7 +160,7 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 func (h *PostHandler) GetPostByID(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -147,7 +170,7 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 	idParam := c.Param("id")
 	postID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do post deve ser um número válido",
 		})
@@ -161,13 +184,18 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao buscar post",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentPost, post.ID) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Post encontrado",
 		Data:    post,
@@ -193,7 +221,7 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 func (h *PostHandler) UpdatePost(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -203,7 +231,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	idParam := c.Param("id")
 	postID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do post deve ser um número válido",
 		})
@@ -212,7 +240,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 
 	var req services.UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -225,6 +253,8 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		errorMsg := err.Error()
 
 		switch {
+		case contains(errorMsg, "roteiro"):
+			statusCode = http.StatusBadRequest
 		case contains(errorMsg, "não encontrado"):
 			statusCode = http.StatusNotFound
 		case contains(errorMsg, "não tem permissão"):
@@ -233,7 +263,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao atualizar post",
 			Message: errorMsg,
 		})
@@ -264,7 +294,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -274,7 +304,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	idParam := c.Param("id")
 	postID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do post deve ser um número válido",
 		})
@@ -293,7 +323,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 			statusCode = http.StatusForbidden
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao deletar post",
 			Message: errorMsg,
 		})
@@ -324,7 +354,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 func (h *PostHandler) LikePost(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -334,7 +364,7 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 	idParam := c.Param("id")
 	postID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do post deve ser um número válido",
 		})
@@ -353,7 +383,7 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 			statusCode = http.StatusConflict
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao curtir post",
 			Message: errorMsg,
 		})
@@ -384,7 +414,7 @@ func (h *PostHandler) LikePost(c *gin.Context) {
 func (h *PostHandler) UnlikePost(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -394,7 +424,7 @@ func (h *PostHandler) UnlikePost(c *gin.Context) {
 	idParam := c.Param("id")
 	postID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do post deve ser um número válido",
 		})
@@ -413,7 +443,7 @@ func (h *PostHandler) UnlikePost(c *gin.Context) {
 			statusCode = http.StatusConflict
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao descurtir post",
 			Message: errorMsg,
 		})
@@ -426,6 +456,181 @@ func (h *PostHandler) UnlikePost(c *gin.Context) {
 	})
 }
 
+// GetPostLikers godoc
+// @Summary List users who liked a post
+// @Description Get the users who liked a post, most recent first, with follow flags relative to the current user
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param limit query int false "Number of users per page" default(20)
+// @Param offset query int false "Number of users to skip" default(0)
+// @Success 200 {array} models.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/{id}/likes [get]
+func (h *PostHandler) GetPostLikers(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	users, err := h.postService.GetPostLikers(uint(postID), currentUserID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar curtidas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Curtidas encontradas",
+		Data:    users,
+	})
+}
+
+// SharePost godoc
+// @Summary Share a post
+// @Description Record that the current user shared a post, counting toward the author's insights
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/{id}/share [post]
+func (h *PostHandler) SharePost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.postService.SharePost(userID.(uint), uint(postID)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao compartilhar post",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Compartilhamento registrado com sucesso",
+	})
+}
+
+// GetPostInsights godoc
+// @Summary Post insights for the author
+// @Description Get impressions, reach, detail views, likes, comments and shares of a post per day; only the author can see them
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param from query string false "Start date (RFC 3339 or YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date, inclusive (RFC 3339 or YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.PostInsights
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/{id}/insights [get]
+func (h *PostHandler) GetPostInsights(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	from, err := parseDateParam(c.Query("from"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'from' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	to, err := parseDateParam(c.Query("to"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'to' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	insights, err := h.postService.GetPostInsights(uint(postID), userID.(uint), from, to)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar estatísticas do post",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Estatísticas do post obtidas com sucesso",
+		Data:    insights,
+	})
+}
+
 // GetPostsByAuthor godoc
 // @Summary Get posts by author
 // @Description Get all posts from a specific author
@@ -436,6 +641,7 @@ func (h *PostHandler) UnlikePost(c *gin.Context) {
 // @Param authorId query int true "Author ID"
 // @Param limit query int false "Number of posts per page" default(20)
 // @Param offset query int false "Number of posts to skip" default(0)
+// @Param cursor query string false "Opaque cursor returned as next_cursor by the previous page; takes precedence over offset"
 // @Success 200 {array} models.PostResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -444,7 +650,7 @@ func (h *PostHandler) UnlikePost(c *gin.Context) {
 func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -453,7 +659,7 @@ func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
 
 	authorIDParam := c.Query("authorId")
 	if authorIDParam == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Parâmetro obrigatório",
 			Message: "O parâmetro 'authorId' é obrigatório",
 		})
@@ -462,7 +668,7 @@ func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
 
 	authorID, err := strconv.ParseUint(authorIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do autor deve ser um número válido",
 		})
@@ -479,29 +685,36 @@ func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
 		offset = 0
 	}
 
-	posts, err := h.postService.GetPostsByAuthor(uint(authorID), currentUserID.(uint), limit, offset)
+	posts, nextCursor, err := h.postService.GetPostsByAuthor(uint(authorID), currentUserID.(uint), c.Query("cursor"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
 			Error:   "Erro ao buscar posts do autor",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	posts = filterRestrictedPosts(c, h.complianceService, posts)
+
 	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Posts encontrados",
-		Data:    posts,
+		Message:    "Posts encontrados",
+		Data:       posts,
+		NextCursor: nextCursor,
 	})
 }
 
 // SearchPosts godoc
 // @Summary Search posts
-// @Description Search for posts by content or location
+// @Description Search for posts by content or location. With lat/lng (and radius_km) or bbox, only posts published inside the area are returned; without q, the closest ones first (radius) or the most recent (bbox)
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param q query string true "Search query"
+// @Param q query string false "Search query; required without an area"
+// @Param lat query number false "Latitude of the search center"
+// @Param lng query number false "Longitude of the search center"
+// @Param radius_km query number false "Search radius in kilometers (max 100)" default(10)
+// @Param bbox query string false "Bounding box as west,south,east,north, instead of lat/lng"
 // @Param limit query int false "Number of results per page" default(20)
 // @Param offset query int false "Number of results to skip" default(0)
 // @Success 200 {array} models.PostResponse
@@ -512,18 +725,27 @@ func (h *PostHandler) GetPostsByAuthor(c *gin.Context) {
 func (h *PostHandler) SearchPosts(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
 		return
 	}
 
+	geo, err := geoFilterFromQuery(c)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetros inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	query := c.Query("q")
-	if query == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+	if query == "" && geo == nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Parâmetro obrigatório",
-			Message: "O parâmetro 'q' (query) é obrigatório",
+			Message: "Informe o parâmetro 'q' (query) ou uma área ('lat' e 'lng', ou 'bbox')",
 		})
 		return
 	}
@@ -538,15 +760,17 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 		offset = 0
 	}
 
-	posts, err := h.postService.SearchPosts(query, currentUserID.(uint), limit, offset)
+	posts, err := h.postService.SearchPosts(query, geo, currentUserID.(uint), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
 			Error:   "Erro na busca de posts",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	posts = filterRestrictedPosts(c, h.complianceService, posts)
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Busca realizada com sucesso",
 		Data:    posts,
@@ -562,14 +786,16 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 // @Security BearerAuth
 // @Param limit query int false "Number of posts per page" default(20)
 // @Param offset query int false "Number of posts to skip" default(0)
+// @Param cursor query string false "Opaque cursor returned as next_cursor by the previous page; takes precedence over offset"
 // @Success 200 {array} models.PostResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /posts/trending [get]
 func (h *PostHandler) GetTrendingPosts(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -586,17 +812,156 @@ func (h *PostHandler) GetTrendingPosts(c *gin.Context) {
 		offset = 0
 	}
 
-	posts, err := h.postService.GetTrendingPosts(currentUserID.(uint), limit, offset)
+	posts, nextCursor, err := h.postService.GetTrendingPosts(currentUserID.(uint), c.Query("cursor"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
 			Error:   "Erro ao buscar posts em alta",
 			Message: err.Error(),
 		})
 		return
 	}
 
+	posts = filterRestrictedPosts(c, h.complianceService, posts)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message:    "Posts em alta encontrados",
+		Data:       posts,
+		NextCursor: nextCursor,
+	})
+}
+
+// GetPostsByItinerary godoc
+// @Summary Get posts linked to an itinerary
+// @Description Get the posts that attached a given itinerary, respecting post visibility
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param limit query int false "Number of posts per page" default(20)
+// @Param offset query int false "Number of posts to skip" default(0)
+// @Success 200 {array} models.PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/posts [get]
+func (h *PostHandler) GetPostsByItinerary(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	posts, err := h.postService.GetPostsByItinerary(uint(itineraryID), currentUserID.(uint), limit, offset)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "não encontrado") {
+			statusCode = http.StatusNotFound
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao buscar posts do roteiro",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	posts = filterRestrictedPosts(c, h.complianceService, posts)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Posts do roteiro obtidos com sucesso",
+		Data:    posts,
+	})
+}
+
+// GetNearbyPosts godoc
+// @Summary Get nearby posts
+// @Description Get posts published within a radius of the given coordinates, closest first
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius_km query number false "Search radius in kilometers (max 100)" default(10)
+// @Param limit query int false "Number of posts per page" default(20)
+// @Param offset query int false "Number of posts to skip" default(0)
+// @Success 200 {array} models.PostResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/nearby [get]
+func (h *PostHandler) GetNearbyPosts(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	latitude, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	longitude, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	if latErr != nil || lngErr != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetros obrigatórios",
+			Message: "Os parâmetros 'lat' e 'lng' devem ser números válidos",
+		})
+		return
+	}
+
+	radiusKm, err := strconv.ParseFloat(c.DefaultQuery("radius_km", "10"), 64)
+	if err != nil {
+		radiusKm = 10
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	posts, err := h.postService.GetNearbyPosts(latitude, longitude, radiusKm, currentUserID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar posts próximos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	posts = filterRestrictedPosts(c, h.complianceService, posts)
+
 	c.JSON(http.StatusOK, SuccessResponse{
-		Message: "Posts em alta encontrados",
+		Message: "Posts próximos encontrados",
 		Data:    posts,
 	})
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionPaymentHandler struct {
+	promotionPaymentService services.PromotionPaymentServiceInterface
+}
+
+func NewPromotionPaymentHandler(promotionPaymentService services.PromotionPaymentServiceInterface) *PromotionPaymentHandler {
+	return &PromotionPaymentHandler{
+		promotionPaymentService: promotionPaymentService,
+	}
+}
+
+// CheckoutPromotion godoc
+// @Summary Pay for a destination promotion
+// @Description Create a payment intent for a partner promotion, charged per day of its date range; the client completes the payment with the returned client secret. The promotion is only shown on the destination page once paid. Failed payments can be retried
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Param request body services.PromotionCheckoutRequest false "Billing data"
+// @Success 201 {object} models.PromotionCheckoutResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/promotions/{id}/checkout [post]
+func (h *PromotionPaymentHandler) CheckoutPromotion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da promoção deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.PromotionCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	req.IPCountry = requestCountry(c)
+
+	checkout, err := h.promotionPaymentService.CheckoutPromotion(uint(promotionID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao pagar promoção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message := "Pagamento da promoção iniciado"
+	if checkout.Promotion.PaymentStatus == models.PromotionPaymentReview {
+		message = "Pagamento da promoção iniciado; a cobrança será concluída após análise de segurança"
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: message,
+		Data:    checkout,
+	})
+}
+
+// ConfirmPromotionPayment godoc
+// @Summary Confirm a promotion payment
+// @Description Refresh the payment status of a pending promotion with the payment provider after the client finishes the payment
+// @Tags destinations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} models.DestinationPromotionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /destinations/promotions/{id}/confirm [post]
+func (h *PromotionPaymentHandler) ConfirmPromotionPayment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da promoção deve ser um número válido",
+		})
+		return
+	}
+
+	promotion, err := h.promotionPaymentService.ConfirmPromotionPayment(uint(promotionID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao confirmar pagamento da promoção",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Status do pagamento da promoção atualizado",
+		Data:    promotion,
+	})
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RatingHandler struct {
+	ratingService     services.RatingServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewRatingHandler(ratingService services.RatingServiceInterface, complianceService services.ComplianceServiceInterface) *RatingHandler {
+	return &RatingHandler{
+		ratingService:     ratingService,
+		complianceService: complianceService,
+	}
+}
+
+type ReplyRatingRequest struct {
+	Reply string `json:"reply" binding:"required"`
+}
+
+type VoteRatingRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+// GetRatings godoc
+// @Summary List itinerary ratings
+// @Description List the ratings of an itinerary with the author's replies and helpful vote counts (viewer_vote is the current user's vote), plus the rating distribution (count and percentage per star, computed over all ratings)
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param sort query string false "Sort order: recent, highest, lowest or helpful (most helpful votes first)" default(recent)
+// @Param limit query int false "Number of ratings per page" default(20)
+// @Param offset query int false "Number of ratings to skip" default(0)
+// @Success 200 {object} services.ItineraryRatings
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/ratings [get]
+func (h *RatingHandler) GetRatings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	ratings, err := h.ratingService.GetRatings(uint(itineraryID), userID.(uint), c.Query("sort"), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar avaliações",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Avaliações encontradas",
+		Data:    ratings,
+	})
+}
+
+// ReplyToRating godoc
+// @Summary Reply to a rating
+// @Description Post or edit the itinerary author's reply to a rating. Each rating has at most one reply; only the itinerary author can reply
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param ratingId path int true "Rating ID"
+// @Param request body ReplyRatingRequest true "Reply"
+// @Success 200 {object} models.ItineraryRatingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/ratings/{ratingId}/reply [put]
+func (h *RatingHandler) ReplyToRating(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, ratingID, ok := ratingParams(c)
+	if !ok {
+		return
+	}
+
+	var req ReplyRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rating, err := h.ratingService.ReplyToRating(itineraryID, ratingID, userID.(uint), req.Reply)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao responder avaliação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Resposta publicada com sucesso",
+		Data:    rating,
+	})
+}
+
+// DeleteRatingReply godoc
+// @Summary Delete a rating reply
+// @Description Remove the itinerary author's reply to a rating
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param ratingId path int true "Rating ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/ratings/{ratingId}/reply [delete]
+func (h *RatingHandler) DeleteRatingReply(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, ratingID, ok := ratingParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ratingService.DeleteReply(itineraryID, ratingID, userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover resposta",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Resposta removida com sucesso",
+		Data:    nil,
+	})
+}
+
+// VoteRating godoc
+// @Summary Vote on a rating's helpfulness
+// @Description Mark a rating as helpful or unhelpful. Voting again replaces the previous vote; users cannot vote on their own ratings
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param ratingId path int true "Rating ID"
+// @Param request body VoteRatingRequest true "Vote"
+// @Success 200 {object} models.ItineraryRatingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/ratings/{ratingId}/vote [put]
+func (h *RatingHandler) VoteRating(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, ratingID, ok := ratingParams(c)
+	if !ok {
+		return
+	}
+
+	var req VoteRatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rating, err := h.ratingService.VoteRating(itineraryID, ratingID, userID.(uint), *req.Helpful)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao votar na avaliação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Voto registrado com sucesso",
+		Data:    rating,
+	})
+}
+
+// RemoveRatingVote godoc
+// @Summary Remove a rating vote
+// @Description Remove the current user's helpful/unhelpful vote from a rating
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param ratingId path int true "Rating ID"
+// @Success 200 {object} models.ItineraryRatingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/ratings/{ratingId}/vote [delete]
+func (h *RatingHandler) RemoveRatingVote(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, ratingID, ok := ratingParams(c)
+	if !ok {
+		return
+	}
+
+	rating, err := h.ratingService.RemoveVote(itineraryID, ratingID, userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover voto",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Voto removido com sucesso",
+		Data:    rating,
+	})
+}
+
+func ratingParams(c *gin.Context) (uint, uint, bool) {
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return 0, 0, false
+	}
+
+	ratingID, err := strconv.ParseUint(c.Param("ratingId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da avaliação deve ser um número válido",
+		})
+		return 0, 0, false
+	}
+
+	return uint(itineraryID), uint(ratingID), true
+}
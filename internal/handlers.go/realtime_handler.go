@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/realtime"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// Intervalo do "ping" enviado aos clientes; quem não responder nada em
+	// dois intervalos é considerado desconectado
+	realtimePingInterval = 30 * time.Second
+	realtimeReadTimeout  = 2*realtimePingInterval + 10*time.Second
+	realtimeWriteTimeout = 10 * time.Second
+	// Mensagens dos clientes são o "pong" e os avisos de digitação; nada
+	// grande é aceito
+	realtimeMaxPayload = 4 * 1024
+	// Espera sugerida ao EventSource antes de reconectar
+	streamRetry = 5 * time.Second
+)
+
+type RealtimeHandler struct {
+	realtimeService *services.RealtimeService
+}
+
+func NewRealtimeHandler(realtimeService *services.RealtimeService) *RealtimeHandler {
+	return &RealtimeHandler{
+		realtimeService: realtimeService,
+	}
+}
+
+// Connect godoc
+// @Summary Open realtime channel
+// @Description Upgrade to a WebSocket that pushes notifications ("notification"), unread count changes ("notification.unread_count"), new posts from followed users ("feed.post"), media processing updates ("media.status"), message receipts ("conversation.receipt"), typing indicators ("conversation.typing") and contacts going online or offline ("presence"). Typing and presence messages have no id and are not replayed. Send {"type":"typing","data":{"conversation_id":1,"typing":true}} while the user types, at most every few seconds, and typing false when they stop. The server sends {"type":"ping"} every 30 seconds and closes connections that stay silent for over a minute; reply with {"type":"pong"}. Browsers may pass the JWT in the token query parameter
+// @Tags realtime
+// @Security BearerAuth
+// @Param token query string false "JWT, when the Authorization header can't be set"
+// @Success 101 {object} realtime.Message
+// @Failure 401 {object} ErrorResponse
+// @Router /ws [get]
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	server := websocket.Server{
+		// A autenticação é pelo token, não por cookies, então conexões de
+		// outras origens não herdam a sessão do usuário
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.serve(conn, userID.(uint))
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *RealtimeHandler) serve(conn *websocket.Conn, userID uint) {
+	conn.MaxPayloadBytes = realtimeMaxPayload
+
+	client := h.realtimeService.Register(userID)
+	defer h.realtimeService.Unregister(client)
+	defer conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			conn.SetReadDeadline(time.Now().Add(realtimeReadTimeout))
+			var incoming []byte
+			if err := websocket.Message.Receive(conn, &incoming); err != nil {
+				return
+			}
+			var message realtime.ClientMessage
+			if json.Unmarshal(incoming, &message) == nil && message.Type == realtime.ClientMessageTyping {
+				h.typing(userID, message.Data)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(realtimePingInterval)
+	defer ticker.Stop()
+
+	for {
+		var message realtime.Message
+		select {
+		case message = <-client.Messages():
+		case <-ticker.C:
+			message = realtime.Message{Type: realtime.MessagePing, SentAt: time.Now()}
+		case <-client.Done():
+			return
+		case <-closed:
+			return
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+		if err := websocket.JSON.Send(conn, message); err != nil {
+			return
+		}
+	}
+}
+
+// typing repassa o aviso de digitação recebido pelo WebSocket; avisos
+// inválidos ou de conversas fora do alcance do usuário são ignorados
+func (h *RealtimeHandler) typing(userID uint, data json.RawMessage) {
+	var req services.TypingRequest
+	if err := json.Unmarshal(data, &req); err != nil || req.Typing == nil {
+		return
+	}
+	h.realtimeService.Typing(req.ConversationID, userID, *req.Typing)
+}
+
+// Stream godoc
+// @Summary Open realtime event stream (SSE)
+// @Description Server-Sent Events fallback for clients whose proxies break WebSockets, carrying the same messages as /ws. Each event has an id; on reconnect the browser sends it as Last-Event-ID (or pass last_event_id) and the events missed in the last few minutes are replayed. When they can't be, a "resync" event asks the client to reload notifications and feed through the API. A comment line is sent every 30 seconds to keep the connection open
+// @Tags realtime
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param token query string false "JWT, when the Authorization header can't be set"
+// @Param Last-Event-ID header string false "Id of the last event received"
+// @Param last_event_id query string false "Id of the last event received, for the first connection"
+// @Success 200 {object} realtime.Message
+// @Failure 401 {object} ErrorResponse
+// @Router /notifications/stream [get]
+func (h *RealtimeHandler) Stream(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	// Registrar antes de reproduzir o histórico para nada se perder entre os
+	// dois; o que chegar repetido é ignorado pelo id
+	client := h.realtimeService.Register(userID.(uint))
+	defer h.realtimeService.Unregister(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx não deve segurar os eventos
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	var lastSent uint64
+	if lastEventID != "" {
+		var missed []realtime.Message
+		complete := false
+		if afterID, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+			missed, complete = h.realtimeService.Replay(userID.(uint), afterID)
+			if complete {
+				lastSent = afterID
+			}
+		}
+		if !complete {
+			if writeEvent(c.Writer, realtime.Message{Type: realtime.MessageResync, SentAt: time.Now()}) != nil {
+				return
+			}
+		}
+		for _, message := range missed {
+			if writeEvent(c.Writer, message) != nil {
+				return
+			}
+			lastSent = message.ID
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(realtimePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message := <-client.Messages():
+			// Mensagens efêmeras não têm id e nunca repetem
+			if message.ID != 0 && message.ID <= lastSent {
+				continue
+			}
+			if writeEvent(c.Writer, message) != nil {
+				return
+			}
+			if message.ID != 0 {
+				lastSent = message.ID
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-client.Done():
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent escreve a mensagem no formato SSE, com o tipo em "event" e o
+// envelope completo em "data"
+func writeEvent(w gin.ResponseWriter, message realtime.Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Falha ao serializar mensagem %q para o stream: %v", message.Type, err)
+		return nil
+	}
+	if message.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", message.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Type, data)
+	return err
+}
+
+// SendTyping godoc
+// @Summary Send typing indicator
+// @Description Tell the other participants connected to the realtime channel that the user started or stopped typing, for clients on the SSE stream. WebSocket clients send {"type":"typing"} over the socket instead. Repeated typing true within a few seconds is ignored; the indicator expires after expires_in seconds
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Param request body services.TypingRequest true "Typing state"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /conversations/{id}/typing [post]
+func (h *RealtimeHandler) SendTyping(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.TypingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.realtimeService.Typing(uint(conversationID), userID.(uint), *req.Typing); err != nil {
+		statusCode := http.StatusBadRequest
+		errorMsg := err.Error()
+
+		switch {
+		case contains(errorMsg, "não encontrada"):
+			statusCode = http.StatusNotFound
+		case contains(errorMsg, "congelada"):
+			statusCode = http.StatusForbidden
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao enviar digitação",
+			Message: errorMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Digitação enviada",
+	})
+}
+
+// GetConversationPresence godoc
+// @Summary Get participants' presence
+// @Description Whether the other participants are online and when they were last seen. Presence is hidden for users who turned it off, users who blocked each other or were blocked, and for everyone when the viewer hides their own presence. Changes arrive as "presence" messages on the realtime channel
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Conversation ID"
+// @Success 200 {array} services.ParticipantPresence
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /conversations/{id}/presence [get]
+func (h *RealtimeHandler) GetConversationPresence(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da conversa deve ser um número válido",
+		})
+		return
+	}
+
+	presence, err := h.realtimeService.GetConversationPresence(uint(conversationID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar presença",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Presença obtida com sucesso",
+		Data:    presence,
+	})
+}
+
+// GetPresenceSettings godoc
+// @Summary Get presence preference
+// @Description Whether the current user hides their online status and last seen time
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} services.PresenceSettingsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/presence [get]
+func (h *RealtimeHandler) GetPresenceSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	settings, err := h.realtimeService.GetPresenceSettings(userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar preferência",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Preferência de presença obtida com sucesso",
+		Data:    settings,
+	})
+}
+
+// UpdatePresenceSettings godoc
+// @Summary Update presence preference
+// @Description Hide or show the current user's online status and last seen time. Hiding it also hides the presence of others from the user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.PresenceSettingsRequest true "Presence preference"
+// @Success 200 {object} services.PresenceSettingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/presence [put]
+func (h *RealtimeHandler) UpdatePresenceSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.PresenceSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.realtimeService.UpdatePresenceSettings(userID.(uint), *req.HidePresence)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar preferência",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Preferência de presença atualizada com sucesso",
+		Data:    settings,
+	})
+}
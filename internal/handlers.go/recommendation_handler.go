@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendationService services.RecommendationServiceInterface
+	complianceService     services.ComplianceServiceInterface
+}
+
+func NewRecommendationHandler(recommendationService services.RecommendationServiceInterface, complianceService services.ComplianceServiceInterface) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+		complianceService:     complianceService,
+	}
+}
+
+// GetRecommendations godoc
+// @Summary Personalized recommendations
+// @Description Get itineraries and posts recommended for the user, combining what similar users liked, who the user follows, the categories and destinations the user interacts with, and what is popular. Recommendations are precomputed nightly (computed on the first request for new users); items already liked or saved are left out. Each item carries a recommendation_reason (similar_users, following, interests or popular)
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Items per list (max 50)" default(20)
+// @Success 200 {object} models.RecommendationsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /recommendations [get]
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	recommendations, err := h.recommendationService.GetRecommendations(userID.(uint), limit)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar recomendações",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	recommendations.Itineraries = filterRestrictedItineraries(c, h.complianceService, recommendations.Itineraries)
+	recommendations.Posts = filterRestrictedPosts(c, h.complianceService, recommendations.Posts)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Recomendações obtidas com sucesso",
+		Data:    recommendations,
+	})
+}
@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService services.ReportServiceInterface
+}
+
+func NewReportHandler(reportService services.ReportServiceInterface) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// ReportUser godoc
+// @Summary Report a user
+// @Description Report a user with a categorized reason. Reports against the same user are grouped in a single moderation case
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body services.ReportRequest true "Report reason (spam, harassment, hate_speech, nudity, violence, misinformation, copyright, other)"
+// @Success 201 {object} services.ReportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/{id}/report [post]
+func (h *ReportHandler) ReportUser(c *gin.Context) {
+	h.report(c, models.ReportTargetUser, "O ID do usuário deve ser um número válido", "Erro ao denunciar usuário")
+}
+
+// ReportPost godoc
+// @Summary Report a post
+// @Description Report a post with a categorized reason; posts reaching the report threshold are hidden until the case is reviewed
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param request body services.ReportRequest true "Report reason (spam, harassment, hate_speech, nudity, violence, misinformation, copyright, other)"
+// @Success 201 {object} services.ReportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /posts/{id}/report [post]
+func (h *ReportHandler) ReportPost(c *gin.Context) {
+	h.report(c, models.ReportTargetPost, "O ID do post deve ser um número válido", "Erro ao denunciar post")
+}
+
+// ReportComment godoc
+// @Summary Report a comment
+// @Description Report a comment with a categorized reason; comments reaching the report threshold are hidden until the case is reviewed
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Comment ID"
+// @Param request body services.ReportRequest true "Report reason (spam, harassment, hate_speech, nudity, violence, misinformation, copyright, other)"
+// @Success 201 {object} services.ReportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /comments/{id}/report [post]
+func (h *ReportHandler) ReportComment(c *gin.Context) {
+	h.report(c, models.ReportTargetComment, "O ID do comentário deve ser um número válido", "Erro ao denunciar comentário")
+}
+
+// ReportItinerary godoc
+// @Summary Report an itinerary
+// @Description Report a public itinerary with a categorized reason; itineraries reaching the report threshold are removed from listings and search until the case is reviewed
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.ReportRequest true "Report reason (spam, harassment, hate_speech, nudity, violence, misinformation, copyright, other)"
+// @Success 201 {object} services.ReportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/report [post]
+func (h *ReportHandler) ReportItinerary(c *gin.Context) {
+	h.report(c, models.ReportTargetItinerary, "O ID do roteiro deve ser um número válido", "Erro ao denunciar roteiro")
+}
+
+// ReportRating godoc
+// @Summary Report an itinerary rating
+// @Description Report a rating of a public itinerary; ratings reaching the report threshold are hidden and left out of the itinerary's average until the case is reviewed
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param ratingId path int true "Rating ID"
+// @Param request body services.ReportRequest true "Report reason (spam, harassment, hate_speech, nudity, violence, misinformation, copyright, other)"
+// @Success 201 {object} services.ReportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/ratings/{ratingId}/report [post]
+func (h *ReportHandler) ReportRating(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	ratingID, err := strconv.ParseUint(c.Param("ratingId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da avaliação deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.reportService.ReportRating(uint(itineraryID), uint(ratingID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao denunciar avaliação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Denúncia registrada com sucesso",
+		Data:    result,
+	})
+}
+
+// GetReportCases godoc
+// @Summary Report moderation queue (admin)
+// @Description Get report cases, each grouping the reports against one user, post, comment, itinerary or rating, with the count per reason. Cases flagged automatically (profanity filter) carry flag_reason and flag_details and may have no reports. Defaults to pending cases, oldest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Case status (pending, resolved, dismissed)" default(pending)
+// @Param target_type query string false "Target type (user, post, comment, itinerary, rating)"
+// @Param assigned query string false "Assignee: me, none or an admin ID"
+// @Param sort query string false "Order (oldest, reports)" default(oldest)
+// @Param flagged query bool false "Only cases flagged automatically"
+// @Param limit query int false "Number of cases per page" default(20)
+// @Param offset query int false "Number of cases to skip" default(0)
+// @Success 200 {array} models.ReportCaseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/reports [get]
+func (h *ReportHandler) GetReportCases(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	query := &services.ReportCaseQuery{
+		Status:     models.ReportStatus(c.Query("status")),
+		TargetType: models.ReportTargetType(c.Query("target_type")),
+		Assigned:   c.Query("assigned"),
+		Sort:       c.Query("sort"),
+		Flagged:    c.Query("flagged") == "true",
+	}
+
+	cases, err := h.reportService.GetCases(userID.(uint), query, limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar denúncias",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Denúncias obtidas com sucesso",
+		Data:    cases,
+	})
+}
+
+// GetReportCase godoc
+// @Summary Get a report case (admin)
+// @Description Get a report case with all its reports and the reported content as moderators see it, even if hidden
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Case ID"
+// @Success 200 {object} models.ReportCaseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reports/{id} [get]
+func (h *ReportHandler) GetReportCase(c *gin.Context) {
+	h.reportCase(c, h.reportService.GetCase, "Erro ao buscar denúncia", "Denúncia obtida com sucesso")
+}
+
+// AssignReportCase godoc
+// @Summary Assign a report case to yourself (admin)
+// @Description Take a pending report case, even if it was assigned to another admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Case ID"
+// @Success 200 {object} models.ReportCaseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reports/{id}/assignee [put]
+func (h *ReportHandler) AssignReportCase(c *gin.Context) {
+	h.reportCase(c, h.reportService.AssignCase, "Erro ao atribuir denúncia", "Denúncia atribuída com sucesso")
+}
+
+// UnassignReportCase godoc
+// @Summary Unassign a report case (admin)
+// @Description Return a pending report case to the queue without an assignee
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Case ID"
+// @Success 200 {object} models.ReportCaseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reports/{id}/assignee [delete]
+func (h *ReportHandler) UnassignReportCase(c *gin.Context) {
+	h.reportCase(c, h.reportService.UnassignCase, "Erro ao liberar denúncia", "Denúncia liberada com sucesso")
+}
+
+// ResolveReportCase godoc
+// @Summary Resolve a report case (admin)
+// @Description Decide all the reports of a case at once. Dismissed cases make content hidden by the reports visible again; resolved (upheld) cases keep the content hidden, hiding it if it was still visible. Reported users are suspended or banned through the user administration endpoints; dismissing a user case lifts the automatic spam limit
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Case ID"
+// @Param request body services.ResolveReportRequest true "Resolution"
+// @Success 200 {object} models.ReportCaseResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/reports/{id} [put]
+func (h *ReportHandler) ResolveReportCase(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	caseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da denúncia deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reportCase, err := h.reportService.ResolveCase(uint(caseID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao resolver denúncia",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Denúncia resolvida com sucesso",
+		Data:    reportCase,
+	})
+}
+
+func (h *ReportHandler) report(
+	c *gin.Context,
+	targetType models.ReportTargetType,
+	invalidIDMessage, errorTitle string,
+) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: invalidIDMessage,
+		})
+		return
+	}
+
+	var req services.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.reportService.Report(targetType, uint(targetID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   errorTitle,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Denúncia registrada com sucesso",
+		Data:    result,
+	})
+}
+
+func (h *ReportHandler) reportCase(
+	c *gin.Context,
+	action func(caseID, adminID uint) (*models.ReportCaseResponse, error),
+	errorTitle, successMessage string,
+) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	caseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da denúncia deve ser um número válido",
+		})
+		return
+	}
+
+	reportCase, err := action(uint(caseID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   errorTitle,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: successMessage,
+		Data:    reportCase,
+	})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RequestLogHandler struct {
+	requestLogService services.RequestLogServiceInterface
+}
+
+func NewRequestLogHandler(requestLogService services.RequestLogServiceInterface) *RequestLogHandler {
+	return &RequestLogHandler{
+		requestLogService: requestLogService,
+	}
+}
+
+// GetRequestLogs godoc
+// @Summary Look up a trace ID (admin)
+// @Description Resolve the trace ID a user quoted from an error response into the sanitized logs of the failed request (no bodies or headers; sensitive query parameters redacted and client IP masked)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param traceId path string true "Trace ID (X-Request-ID)"
+// @Success 200 {array} models.RequestLog
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/support/traces/{traceId} [get]
+func (h *RequestLogHandler) GetRequestLogs(c *gin.Context) {
+	logs, err := h.requestLogService.GetByTraceID(c.Param("traceId"))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar registros",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Registros da requisição obtidos com sucesso",
+		Data:    logs,
+	})
+}
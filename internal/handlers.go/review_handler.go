@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService     services.ReviewServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewReviewHandler(reviewService services.ReviewServiceInterface, complianceService services.ComplianceServiceInterface) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService:     reviewService,
+		complianceService: complianceService,
+	}
+}
+
+type LocationReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment"`
+}
+
+// ReviewLocation godoc
+// @Summary Review a location
+// @Description Create or update the current user's review of an itinerary location. Locations with a Google place ID share reviews across itineraries, so reviewing the same place again replaces the previous review
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param locationId path int true "Location ID"
+// @Param request body LocationReviewRequest true "Review"
+// @Success 200 {object} models.LocationReviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/locations/{locationId}/review [put]
+func (h *ReviewHandler) ReviewLocation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, locationID, ok := locationParams(c)
+	if !ok {
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, itineraryID) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	var req LocationReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	review, err := h.reviewService.ReviewLocation(itineraryID, locationID, userID.(uint), req.Rating, req.Comment)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao avaliar local",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Local avaliado com sucesso",
+		Data:    review,
+	})
+}
+
+// DeleteLocationReview godoc
+// @Summary Delete a location review
+// @Description Remove the current user's review of an itinerary location (or of its Google place)
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param locationId path int true "Location ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/locations/{locationId}/review [delete]
+func (h *ReviewHandler) DeleteLocationReview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, locationID, ok := locationParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteLocationReview(itineraryID, locationID, userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover avaliação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Avaliação removida com sucesso",
+		Data:    nil,
+	})
+}
+
+// GetLocationReviews godoc
+// @Summary List location reviews
+// @Description List the community reviews of an itinerary location, aggregated across all itineraries that include the same Google place, with the rating distribution and the current user's review. Reviews written on private itineraries only count towards the aggregate
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param locationId path int true "Location ID"
+// @Param sort query string false "Sort order: recent, highest or lowest" default(recent)
+// @Param limit query int false "Number of reviews per page" default(20)
+// @Param offset query int false "Number of reviews to skip" default(0)
+// @Success 200 {object} services.PlaceReviews
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/locations/{locationId}/reviews [get]
+func (h *ReviewHandler) GetLocationReviews(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, locationID, ok := locationParams(c)
+	if !ok {
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, itineraryID) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	reviews, err := h.reviewService.GetLocationReviews(itineraryID, locationID, userID.(uint), c.Query("sort"), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar avaliações",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Avaliações encontradas",
+		Data:    reviews,
+	})
+}
+
+// GetPlaceReviews godoc
+// @Summary List place reviews
+// @Description List the community reviews of a Google place across all itineraries, with the rating distribution and the current user's review
+// @Tags places
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param placeId path string true "Google place ID"
+// @Param sort query string false "Sort order: recent, highest or lowest" default(recent)
+// @Param limit query int false "Number of reviews per page" default(20)
+// @Param offset query int false "Number of reviews to skip" default(0)
+// @Success 200 {object} services.PlaceReviews
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /places/{placeId}/reviews [get]
+func (h *ReviewHandler) GetPlaceReviews(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	reviews, err := h.reviewService.GetPlaceReviews(c.Param("placeId"), userID.(uint), c.Query("sort"), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar avaliações",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Avaliações encontradas",
+		Data:    reviews,
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/middleware"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskHandler struct {
+	riskService services.RiskServiceInterface
+}
+
+func NewRiskHandler(riskService services.RiskServiceInterface) *RiskHandler {
+	return &RiskHandler{
+		riskService: riskService,
+	}
+}
+
+// GetRiskAssessments godoc
+// @Summary Bot risk assessments (admin)
+// @Description Get recorded automation risk assessments for registrations and posts, highest score first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "Assessed action (register, create_post)"
+// @Param min_score query int false "Minimum risk score (0-100)" default(0)
+// @Param limit query int false "Number of assessments per page" default(20)
+// @Param offset query int false "Number of assessments to skip" default(0)
+// @Success 200 {array} models.RiskAssessment
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/risk-assessments [get]
+func (h *RiskHandler) GetRiskAssessments(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	minScore, _ := strconv.Atoi(c.DefaultQuery("min_score", "0"))
+	action := models.RiskAction(c.Query("action"))
+
+	assessments, err := h.riskService.GetAssessments(action, minScore, limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar avaliações de risco",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Avaliações de risco obtidas com sucesso",
+		Data:    assessments,
+	})
+}
+
+// botRisk devolve a avaliação feita pelo BotDetectionMiddleware, ou nil se a
+// rota não passou por ele
+func botRisk(c *gin.Context) *models.RiskAssessment {
+	value, exists := c.Get(middleware.BotRiskKey)
+	if !exists {
+		return nil
+	}
+	assessment, _ := value.(*models.RiskAssessment)
+	return assessment
+}
+
+// respondBotBlocked recusa a requisição considerada automatizada
+func respondBotBlocked(c *gin.Context) {
+	errorJSON(c, http.StatusForbidden, ErrorResponse{
+		Error:   "Requisição bloqueada",
+		Message: "atividade automatizada detectada",
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RouteHandler struct {
+	routeService      services.RouteServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewRouteHandler(routeService services.RouteServiceInterface, complianceService services.ComplianceServiceInterface) *RouteHandler {
+	return &RouteHandler{
+		routeService:      routeService,
+		complianceService: complianceService,
+	}
+}
+
+// GetDayRoute godoc
+// @Summary Get the route of an itinerary day
+// @Description Return the day's geolocated locations in visiting order with the distance and travel duration between consecutive stops, computed by the routing provider and cached by coordinates. Without a provider, distances are estimated in a straight line (estimated=true). Warnings flag unrealistic days: long total travel, long walks and start times that leave no room for the trip from the previous stop
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param dayId path int true "Day ID"
+// @Param mode query string false "Travel mode (walking, driving)" default(walking)
+// @Success 200 {object} services.DayRoute
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/days/{dayId}/route [get]
+func (h *RouteHandler) GetDayRoute(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	dayID, err := strconv.ParseUint(c.Param("dayId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do dia deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	route, err := h.routeService.GetDayRoute(uint(itineraryID), uint(dayID), userID.(uint), services.RouteMode(c.Query("mode")))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao calcular rota",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Rota calculada com sucesso",
+		Data:    route,
+	})
+}
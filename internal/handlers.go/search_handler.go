@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService     services.SearchServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewSearchHandler(searchService services.SearchServiceInterface, complianceService services.ComplianceServiceInterface) *SearchHandler {
+	return &SearchHandler{
+		searchService:     searchService,
+		complianceService: complianceService,
+	}
+}
+
+// Search godoc
+// @Summary Unified search
+// @Description Search users, posts and itineraries at once for the app's search bar, with the first results of each section. Every section has its own next_cursor: to load more of one section, repeat the search with type set to it and the cursor, and only that section is returned
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param type query string false "Section to paginate (users, posts or itineraries)"
+// @Param cursor query string false "next_cursor returned by the section; requires type"
+// @Param limit query int false "Results per page when paginating a section (max 20)" default(5)
+// @Success 200 {object} models.SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetro obrigatório",
+			Message: "O parâmetro 'q' (query) é obrigatório",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+
+	results, err := h.searchService.Search(userID.(uint), &services.SearchRequest{
+		Query:  query,
+		Type:   c.Query("type"),
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro na busca",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if results.Posts != nil {
+		results.Posts.Items = filterRestrictedPosts(c, h.complianceService, results.Posts.Items)
+	}
+	if results.Itineraries != nil {
+		results.Itineraries.Items = filterRestrictedItineraries(c, h.complianceService, results.Itineraries.Items)
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Busca realizada com sucesso",
+		Data:    results,
+	})
+}
+
+// Suggest godoc
+// @Summary Search suggestions
+// @Description Autocomplete for the search bar: destinations (countries and cities), hashtags and usernames starting with the text, ordered by their own popularity (population, posts using the tag, followers) weighted by how often each one is searched. Start with # to get only hashtags or @ to get only users
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Text typed so far"
+// @Param limit query int false "Number of suggestions (max 20)" default(10)
+// @Success 200 {array} models.SearchSuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	_, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetro obrigatório",
+			Message: "O parâmetro 'q' (query) é obrigatório",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	suggestions, err := h.searchService.Suggest(query, limit)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar sugestões",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Sugestões obtidas com sucesso",
+		Data:    suggestions,
+	})
+}
+
+// SaveSearch godoc
+// @Summary Save an itinerary search
+// @Description Save itinerary search criteria (text, category, country, city and duration range) to run again later. With alerts on (the default), a notification is sent when new public itineraries matching the search are published. Country and city are matched to the geographic reference data, and the name defaults to a label built from the criteria
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.SavedSearchRequest true "Search criteria"
+// @Success 201 {object} models.SavedSearch
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /search/saved [post]
+func (h *SearchHandler) SaveSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	search, err := h.searchService.SaveSearch(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao salvar busca",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Busca salva com sucesso",
+		Data:    search,
+	})
+}
+
+// GetSavedSearches godoc
+// @Summary List saved searches
+// @Description List the current user's saved itinerary searches, newest first
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SavedSearch
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /search/saved [get]
+func (h *SearchHandler) GetSavedSearches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	searches, err := h.searchService.GetSavedSearches(userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar buscas salvas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Buscas salvas obtidas com sucesso",
+		Data:    searches,
+	})
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete a saved search
+// @Description Delete one of the current user's saved searches, stopping its alerts
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Saved search ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /search/saved/{id} [delete]
+func (h *SearchHandler) DeleteSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da busca salva deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.searchService.DeleteSavedSearch(uint(searchID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover busca salva",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Busca salva removida com sucesso",
+	})
+}
+
+// geoFilterFromQuery lê o filtro geográfico das buscas: lat, lng e radius_km
+// ou bbox=oeste,sul,leste,norte. Nil quando nenhum foi informado
+func geoFilterFromQuery(c *gin.Context) (*services.GeoFilter, error) {
+	lat, lng, bbox := c.Query("lat"), c.Query("lng"), c.Query("bbox")
+
+	if bbox != "" {
+		if lat != "" || lng != "" {
+			return nil, errors.New("informe 'lat' e 'lng' ou 'bbox', não os dois")
+		}
+		parts := strings.Split(bbox, ",")
+		values := make([]float64, len(parts))
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, errors.New("o parâmetro 'bbox' deve ter quatro números: oeste,sul,leste,norte")
+			}
+			values[i] = value
+		}
+		return &services.GeoFilter{BBox: values}, nil
+	}
+
+	if lat == "" && lng == "" {
+		return nil, nil
+	}
+	latitude, latErr := strconv.ParseFloat(lat, 64)
+	longitude, lngErr := strconv.ParseFloat(lng, 64)
+	if latErr != nil || lngErr != nil {
+		return nil, errors.New("os parâmetros 'lat' e 'lng' devem ser números válidos")
+	}
+
+	radiusKm, err := strconv.ParseFloat(c.DefaultQuery("radius_km", "10"), 64)
+	if err != nil {
+		radiusKm = 10
+	}
+	return &services.GeoFilter{Latitude: latitude, Longitude: longitude, RadiusKm: radiusKm}, nil
+}
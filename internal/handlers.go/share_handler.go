@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ShareHandler struct {
+	shareService      services.ShareServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewShareHandler(shareService services.ShareServiceInterface, complianceService services.ComplianceServiceInterface) *ShareHandler {
+	return &ShareHandler{
+		shareService:      shareService,
+		complianceService: complianceService,
+	}
+}
+
+// GetShareLink godoc
+// @Summary Get an itinerary share link
+// @Description Get the public share link of an itinerary, with its settings and view count. Only the author can see it
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} services.ShareLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/share [get]
+func (h *ShareHandler) GetShareLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	link, err := h.shareService.GetShareLink(uint(itineraryID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar link de compartilhamento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Link de compartilhamento encontrado",
+		Data:    link,
+	})
+}
+
+// UpdateShareLink godoc
+// @Summary Create or update an itinerary share link
+// @Description Create the public share link on the first call, then enable/disable it, toggle noindex or regenerate the slug (the previous link stops working). Only public itineraries can have an enabled link
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.UpdateShareLinkRequest true "Share link settings"
+// @Success 200 {object} services.ShareLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/share [put]
+func (h *ShareHandler) UpdateShareLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.UpdateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	link, err := h.shareService.UpdateShareLink(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar link de compartilhamento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Link de compartilhamento atualizado com sucesso",
+		Data:    link,
+	})
+}
+
+// GetPublicItinerary godoc
+// @Summary View a shared itinerary
+// @Description Open an itinerary through its public share link, without authentication. Each access counts as a view. When the author enabled noindex, the response carries the X-Robots-Tag header and noindex=true for the page's robots meta tag
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Param slug path string true "Share link slug"
+// @Success 200 {object} services.PublicItinerary
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /public/itineraries/{slug} [get]
+func (h *ShareHandler) GetPublicItinerary(c *gin.Context) {
+	shared, err := h.shareService.GetPublicItinerary(c.Param("slug"))
+	if err != nil {
+		errorJSON(c, http.StatusNotFound, ErrorResponse{
+			Error:   "Roteiro não encontrado",
+			Message: "O link não existe ou foi desativado pelo autor",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, shared.Itinerary.ID) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	if shared.NoIndex {
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro encontrado",
+		Data:    shared,
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Tempo que navegadores e CDNs podem reutilizar as estatísticas públicas
+const publicStatsMaxAge = 5 * time.Minute
+
+type StatsHandler struct {
+	statsService services.StatsServiceInterface
+}
+
+func NewStatsHandler(statsService services.StatsServiceInterface) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// GetPublicStats godoc
+// @Summary Public platform statistics
+// @Description Get aggregate platform numbers for the marketing site (public itineraries, destinations and countries covered, community size). The numbers are computed periodically by a background job, so they may be slightly behind
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PlatformStats
+// @Failure 503 {object} ErrorResponse
+// @Router /stats/public [get]
+func (h *StatsHandler) GetPublicStats(c *gin.Context) {
+	stats, err := h.statsService.GetPublicStats()
+	if err != nil {
+		errorJSON(c, http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Estatísticas indisponíveis",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(publicStatsMaxAge.Seconds())))
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Estatísticas obtidas com sucesso",
+		Data:    stats,
+	})
+}
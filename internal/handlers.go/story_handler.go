@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type StoryHandler struct {
+	storyService services.StoryServiceInterface
+}
+
+func NewStoryHandler(storyService services.StoryServiceInterface) *StoryHandler {
+	return &StoryHandler{
+		storyService: storyService,
+	}
+}
+
+// CreateStory godoc
+// @Summary Create a story
+// @Description Publish an image or video uploaded by the current user as a story that expires after 24 hours
+// @Tags stories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateStoryRequest true "Story data"
+// @Success 201 {object} models.StoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stories [post]
+func (h *StoryHandler) CreateStory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.CreateStoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	story, err := h.storyService.CreateStory(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar story",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Story criado com sucesso",
+		Data:    story,
+	})
+}
+
+// GetStories godoc
+// @Summary Stories feed
+// @Description Get the active stories of the current user and followed users, grouped by author with unseen authors first
+// @Tags stories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StoryGroup
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stories [get]
+func (h *StoryHandler) GetStories(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	groups, err := h.storyService.GetStoriesFeed(userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar stories",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Stories obtidos com sucesso",
+		Data:    groups,
+	})
+}
+
+// GetArchivedStories godoc
+// @Summary Stories archive
+// @Description Get the current user's expired stories
+// @Tags stories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of stories per page" default(20)
+// @Param offset query int false "Number of stories to skip" default(0)
+// @Success 200 {array} models.StoryResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stories/archive [get]
+func (h *StoryHandler) GetArchivedStories(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	stories, err := h.storyService.GetArchivedStories(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar stories arquivados",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Stories arquivados obtidos com sucesso",
+		Data:    stories,
+	})
+}
+
+// ViewStory godoc
+// @Summary View a story
+// @Description Get a story and record that the current user has seen it
+// @Tags stories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Story ID"
+// @Success 200 {object} models.StoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stories/{id}/view [post]
+func (h *StoryHandler) ViewStory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	storyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do story deve ser um número válido",
+		})
+		return
+	}
+
+	story, err := h.storyService.ViewStory(uint(storyID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao visualizar story",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Story obtido com sucesso",
+		Data:    story,
+	})
+}
+
+// GetStoryViewers godoc
+// @Summary List story viewers
+// @Description Get who has seen a story (only by the author)
+// @Tags stories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Story ID"
+// @Param limit query int false "Number of viewers per page" default(20)
+// @Param offset query int false "Number of viewers to skip" default(0)
+// @Success 200 {array} models.StoryViewerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stories/{id}/viewers [get]
+func (h *StoryHandler) GetStoryViewers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	storyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do story deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	viewers, err := h.storyService.GetStoryViewers(uint(storyID), userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar visualizações",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Visualizações obtidas com sucesso",
+		Data:    viewers,
+	})
+}
+
+// DeleteStory godoc
+// @Summary Delete a story
+// @Description Delete a story (only by the author)
+// @Tags stories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Story ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stories/{id} [delete]
+func (h *StoryHandler) DeleteStory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	storyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do story deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.storyService.DeleteStory(uint(storyID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao deletar story",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Story deletado com sucesso",
+	})
+}
@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SuspensionHandler struct {
+	suspensionService services.SuspensionServiceInterface
+	authService       services.AuthServiceInterface
+}
+
+func NewSuspensionHandler(suspensionService services.SuspensionServiceInterface, authService services.AuthServiceInterface) *SuspensionHandler {
+	return &SuspensionHandler{
+		suspensionService: suspensionService,
+		authService:       authService,
+	}
+}
+
+// SuspendUser godoc
+// @Summary Suspend a user (admin)
+// @Description Deactivate an account for a number of days. Tokens already issued stop working right away, realtime connections are closed and the account is reactivated automatically when the suspension ends. The reason is shown to the user; suspending an already suspended account replaces the suspension. Administrators and banned accounts cannot be suspended. The decision is recorded in the moderation audit log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body services.SuspendUserRequest true "Suspension"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/suspend [post]
+func (h *SuspensionHandler) SuspendUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.suspensionService.SuspendUser(uint(targetID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao suspender usuário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuário suspenso com sucesso",
+		Data:    user,
+	})
+}
+
+// BanUser godoc
+// @Summary Ban a user (admin)
+// @Description Deactivate an account indefinitely. Tokens already issued stop working right away and realtime connections are closed. A suspended account can be banned. The optional reason is shown to the user; administrators cannot be banned. The decision is recorded in the moderation audit log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body services.BanUserRequest false "Reason and moderator note"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/ban [post]
+func (h *SuspensionHandler) BanUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	// O motivo e a nota são opcionais
+	var req services.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.suspensionService.BanUser(uint(targetID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao banir usuário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuário banido com sucesso",
+		Data:    user,
+	})
+}
+
+// UnbanUser godoc
+// @Summary Unban a user (admin)
+// @Description Reactivate a suspended or banned account before the restriction ends. A pending appeal is closed. The decision is recorded in the moderation audit log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body services.ModerationActionRequest false "Moderator note"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/unban [post]
+func (h *SuspensionHandler) UnbanUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	// A nota do moderador é opcional
+	var req services.ModerationActionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	user, err := h.suspensionService.UnbanUser(uint(targetID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao desbanir usuário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuário desbanido com sucesso",
+		Data:    user,
+	})
+}
+
+// CreateAppeal godoc
+// @Summary Appeal a suspension or ban
+// @Description Ask the moderation team to review the suspension or ban of the account. Since restricted accounts cannot log in, the user identifies with the login and password. Only one appeal can be pending at a time, and each restriction can be appealed once
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body services.AccountAppealRequest true "Credentials and appeal"
+// @Success 201 {object} models.AccountAppealResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/appeal [post]
+func (h *SuspensionHandler) CreateAppeal(c *gin.Context) {
+	var req services.AccountAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.authService.Authenticate(&req.LoginRequest)
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if contains(err.Error(), "credenciais inválidas") {
+			statusCode = http.StatusUnauthorized
+		}
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao registrar recurso",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	appeal, err := h.suspensionService.CreateAppeal(user, req.Message)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao registrar recurso",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Recurso registrado com sucesso",
+		Data:    appeal,
+	})
+}
+
+// GetAppeals godoc
+// @Summary Account appeal queue (admin)
+// @Description Get appeals against suspensions and bans by status, oldest first, with the restricted account. Appeals are closed without review when the restriction ends or is replaced
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Appeal status (pending, accepted, rejected, closed)" default(pending)
+// @Param limit query int false "Number of appeals per page (max 100)" default(20)
+// @Param offset query int false "Number of appeals to skip" default(0)
+// @Success 200 {array} models.AccountAppealResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/appeals [get]
+func (h *SuspensionHandler) GetAppeals(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	status := models.AppealStatus(c.Query("status"))
+
+	appeals, err := h.suspensionService.GetAppeals(status, limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar recursos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Recursos obtidos com sucesso",
+		Data:    appeals,
+	})
+}
+
+// ReviewAppeal godoc
+// @Summary Review an account appeal (admin)
+// @Description Accept a pending appeal, reactivating the account and notifying the user, or reject it. The note is shown to the user; a rejected restriction cannot be appealed again. The decision is recorded in the moderation audit log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Appeal ID"
+// @Param request body services.ReviewAppealRequest true "Decision"
+// @Success 200 {object} models.AccountAppealResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/appeals/{id} [put]
+func (h *SuspensionHandler) ReviewAppeal(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	appealID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do recurso deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ReviewAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	appeal, err := h.suspensionService.ReviewAppeal(uint(appealID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao analisar recurso",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Recurso analisado com sucesso",
+		Data:    appeal,
+	})
+}
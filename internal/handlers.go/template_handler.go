@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templateService   services.TemplateServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewTemplateHandler(templateService services.TemplateServiceInterface, complianceService services.ComplianceServiceInterface) *TemplateHandler {
+	return &TemplateHandler{
+		templateService:   templateService,
+		complianceService: complianceService,
+	}
+}
+
+// GetTemplates godoc
+// @Summary List itinerary templates
+// @Description Catalog of reusable itinerary templates published by companies, with the company branding and how many trips were created from each
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category query string false "Filter by category"
+// @Param country query string false "Filter by country"
+// @Param city query string false "Filter by city"
+// @Param company_id query int false "Filter by company"
+// @Param limit query int false "Number of results per page" default(20)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {array} models.TemplateResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/templates [get]
+func (h *TemplateHandler) GetTemplates(c *gin.Context) {
+	limit, offset := paginationParams(c)
+	filter := repositories.TemplateFilter{
+		Category: models.ItineraryCategory(c.Query("category")),
+		Country:  c.Query("country"),
+		City:     c.Query("city"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if companyID, err := strconv.ParseUint(c.Query("company_id"), 10, 32); err == nil {
+		filter.CompanyID = uint(companyID)
+	}
+
+	templates, err := h.templateService.GetTemplates(filter)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar modelos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ids := make([]uint, 0, len(templates))
+	for _, template := range templates {
+		ids = append(ids, template.Itinerary.ID)
+	}
+	if restricted := h.complianceService.FilterRestricted(models.RestrictedContentItinerary, ids, requestCountry(c)); len(restricted) > 0 {
+		visible := make([]models.TemplateResponse, 0, len(templates))
+		for _, template := range templates {
+			if !restricted[template.Itinerary.ID] {
+				visible = append(visible, template)
+			}
+		}
+		templates = visible
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Modelos encontrados",
+		Data:    templates,
+	})
+}
+
+// InstantiateTemplate godoc
+// @Summary Create a trip from a template
+// @Description Copy a company template (days and locations) into a new private itinerary owned by the current user
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template itinerary ID"
+// @Success 201 {object} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/instantiate [post]
+func (h *TemplateHandler) InstantiateTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(templateID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	itinerary, err := h.templateService.InstantiateTemplate(uint(templateID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao usar modelo",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Roteiro criado a partir do modelo",
+		Data:    itinerary,
+	})
+}
+
+// SetTemplate godoc
+// @Summary Publish or unpublish an itinerary as a template
+// @Description Company accounts can publish their public itineraries as reusable templates in the catalog, or remove them from it
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.SetTemplateRequest true "Template flag"
+// @Success 200 {object} models.ItineraryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/{id}/template [put]
+func (h *TemplateHandler) SetTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.SetTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	itinerary, err := h.templateService.SetTemplate(uint(itineraryID), userID.(uint), *req.IsTemplate)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar modelo",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Roteiro atualizado com sucesso",
+		Data:    itinerary,
+	})
+}
+
+// GetTemplateMetrics godoc
+// @Summary Get usage metrics of the company's templates
+// @Description For each template of the current company: trips created from it (total and last 30 days), unique users, views and likes
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TemplateMetrics
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /itineraries/templates/metrics [get]
+func (h *TemplateHandler) GetTemplateMetrics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	metrics, err := h.templateService.GetTemplateMetrics(userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar métricas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Métricas dos modelos",
+		Data:    metrics,
+	})
+}
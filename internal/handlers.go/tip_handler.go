@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TipHandler struct {
+	tipService services.TipServiceInterface
+}
+
+func NewTipHandler(tipService services.TipServiceInterface) *TipHandler {
+	return &TipHandler{
+		tipService: tipService,
+	}
+}
+
+// CreateTip godoc
+// @Summary Support a creator
+// @Description Create a payment intent to tip a creator; the client completes the payment with the returned client secret
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateTipRequest true "Tip data (amount in cents)"
+// @Success 201 {object} models.TipIntentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tips [post]
+func (h *TipHandler) CreateTip(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.CreateTipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	req.IPCountry = requestCountry(c)
+
+	intent, err := h.tipService.CreateTip(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar apoio",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message := "Pagamento do apoio iniciado"
+	if intent.Tip.Status == models.TipStatusReview {
+		message = "Pagamento do apoio iniciado; a cobrança será concluída após análise de segurança"
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: message,
+		Data:    intent,
+	})
+}
+
+// ConfirmTip godoc
+// @Summary Confirm a tip payment
+// @Description Refresh the status of a pending tip with the payment provider after the client finishes the payment
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tip ID"
+// @Success 200 {object} models.TipResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tips/{id}/confirm [post]
+func (h *TipHandler) ConfirmTip(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do apoio deve ser um número válido",
+		})
+		return
+	}
+
+	tip, err := h.tipService.ConfirmTip(uint(tipID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao confirmar apoio",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Status do apoio atualizado",
+		Data:    tip,
+	})
+}
+
+// GetSentTips godoc
+// @Summary List sent tips
+// @Description Get the tips sent by the current user
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of tips per page" default(20)
+// @Param offset query int false "Number of tips to skip" default(0)
+// @Success 200 {array} models.TipResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tips/sent [get]
+func (h *TipHandler) GetSentTips(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	tips, err := h.tipService.GetSentTips(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar apoios",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Apoios enviados obtidos com sucesso",
+		Data:    tips,
+	})
+}
+
+// GetReceivedTips godoc
+// @Summary List received tips
+// @Description Get the confirmed tips received by the current user as a creator
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of tips per page" default(20)
+// @Param offset query int false "Number of tips to skip" default(0)
+// @Success 200 {array} models.TipResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tips/received [get]
+func (h *TipHandler) GetReceivedTips(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	tips, err := h.tipService.GetReceivedTips(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar apoios",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Apoios recebidos obtidos com sucesso",
+		Data:    tips,
+	})
+}
+
+// GetEarnings godoc
+// @Summary Get creator earnings
+// @Description Get the confirmed tip totals of the current user per currency; defaults to the last 30 days
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End date (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} services.EarningsReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tips/earnings [get]
+func (h *TipHandler) GetEarnings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	from, err := parseDateParam(c.Query("from"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'from' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	to, err := parseDateParam(c.Query("to"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "O parâmetro 'to' deve estar no formato RFC 3339 ou YYYY-MM-DD",
+		})
+		return
+	}
+
+	report, err := h.tipService.GetEarnings(userID.(uint), from, to)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao calcular ganhos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Ganhos obtidos com sucesso",
+		Data:    report,
+	})
+}
+
+// SetupPayoutAccount godoc
+// @Summary Start payout account onboarding
+// @Description Create the creator's payout account with the payment provider if needed and return the onboarding link
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.PayoutOnboardingRequest true "Onboarding redirect URLs"
+// @Success 200 {object} models.PayoutAccountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payouts/account [post]
+func (h *TipHandler) SetupPayoutAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.PayoutOnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	account, err := h.tipService.SetupPayoutAccount(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao configurar conta de recebimento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Cadastro da conta de recebimento iniciado",
+		Data:    account,
+	})
+}
+
+// GetPayoutAccount godoc
+// @Summary Get payout account status
+// @Description Get the onboarding status of the current user's payout account
+// @Tags tips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PayoutAccountResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /payouts/account [get]
+func (h *TipHandler) GetPayoutAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	account, err := h.tipService.GetPayoutAccount(userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar conta de recebimento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Conta de recebimento obtida com sucesso",
+		Data:    account,
+	})
+}
+
+// RefundTip godoc
+// @Summary Refund a tip (admin)
+// @Description Refund a confirmed tip with the payment provider and reverse its ledger entries
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tip ID"
+// @Success 200 {object} models.TipResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/tips/{id}/refund [post]
+func (h *TipHandler) RefundTip(c *gin.Context) {
+	tipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do apoio deve ser um número válido",
+		})
+		return
+	}
+
+	tip, err := h.tipService.RefundTip(uint(tipID))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao estornar apoio",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Apoio estornado com sucesso",
+		Data:    tip,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TranscodeHandler struct {
+	transcodeService services.TranscodeServiceInterface
+}
+
+func NewTranscodeHandler(transcodeService services.TranscodeServiceInterface) *TranscodeHandler {
+	return &TranscodeHandler{
+		transcodeService: transcodeService,
+	}
+}
+
+// GetMediaStatus godoc
+// @Summary Get media processing status
+// @Description Get the processing status of an uploaded media. Videos are converted to HLS in the background (pending, running, ready or failed); once ready, hls_url and poster_url are filled and the owner is notified. Images are always ready. Only the owner or an admin can check it
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Media ID"
+// @Success 200 {object} services.MediaStatus
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /media/{id}/status [get]
+func (h *TranscodeHandler) GetMediaStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da mídia deve ser um número válido",
+		})
+		return
+	}
+
+	status, err := h.transcodeService.GetMediaStatus(uint(mediaID), userID.(uint), isAdmin(c))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar status da mídia",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Status da mídia",
+		Data:    status,
+	})
+}
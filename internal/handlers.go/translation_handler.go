@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	translationService services.TranslationServiceInterface
+}
+
+func NewTranslationHandler(translationService services.TranslationServiceInterface) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+	}
+}
+
+// GetPostTranslation godoc
+// @Summary Translate a post
+// @Description Translate the post content to the requested language, caching the result and recording the detected source language
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Post ID"
+// @Param lang query string false "Target language (e.g. pt, en, es); defaults to the Accept-Language header"
+// @Success 200 {object} models.PostTranslationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /posts/{id}/translation [get]
+func (h *TranslationHandler) GetPostTranslation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do post deve ser um número válido",
+		})
+		return
+	}
+
+	language := c.Query("lang")
+	if language == "" {
+		language = preferredLanguage(c.GetHeader("Accept-Language"))
+	}
+	if language == "" {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Parâmetro obrigatório",
+			Message: "Informe o idioma de destino no parâmetro 'lang'",
+		})
+		return
+	}
+
+	translation, err := h.translationService.TranslatePost(uint(postID), userID.(uint), language)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao traduzir post",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Tradução obtida com sucesso",
+		Data:    translation,
+	})
+}
+
+// preferredLanguage retorna o primeiro idioma do cabeçalho Accept-Language
+func preferredLanguage(header string) string {
+	first := strings.Split(header, ",")[0]
+	first = strings.TrimSpace(strings.Split(first, ";")[0])
+	if first == "*" {
+		return ""
+	}
+	return first
+}
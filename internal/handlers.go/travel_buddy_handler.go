@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TravelBuddyHandler struct {
+	travelBuddyService services.TravelBuddyServiceInterface
+}
+
+func NewTravelBuddyHandler(travelBuddyService services.TravelBuddyServiceInterface) *TravelBuddyHandler {
+	return &TravelBuddyHandler{
+		travelBuddyService: travelBuddyService,
+	}
+}
+
+// CreateIntent godoc
+// @Summary Publish a trip intent
+// @Description Opt in to travel buddy matching by publishing destination, dates and travel style
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.TravelIntentRequest true "Trip intent"
+// @Success 201 {object} models.TravelIntentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /travel-buddies/intents [post]
+func (h *TravelBuddyHandler) CreateIntent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.TravelIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	intent, err := h.travelBuddyService.CreateIntent(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Erro ao publicar intenção de viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Intenção de viagem publicada com sucesso",
+		Data:    intent,
+	})
+}
+
+// GetMyIntents godoc
+// @Summary List my trip intents
+// @Description Get the trip intents published by the current user
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TravelIntentResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /travel-buddies/intents [get]
+func (h *TravelBuddyHandler) GetMyIntents(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	intents, err := h.travelBuddyService.GetMyIntents(userID.(uint))
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar intenções de viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Intenções de viagem obtidas com sucesso",
+		Data:    intents,
+	})
+}
+
+// UpdateIntent godoc
+// @Summary Update a trip intent
+// @Description Update a trip intent; set is_active to false to stop appearing in matches
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Intent ID"
+// @Param request body services.TravelIntentRequest true "Trip intent"
+// @Success 200 {object} models.TravelIntentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /travel-buddies/intents/{id} [put]
+func (h *TravelBuddyHandler) UpdateIntent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	intentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da intenção deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.TravelIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	intent, err := h.travelBuddyService.UpdateIntent(uint(intentID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar intenção de viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Intenção de viagem atualizada com sucesso",
+		Data:    intent,
+	})
+}
+
+// DeleteIntent godoc
+// @Summary Delete a trip intent
+// @Description Remove a trip intent published by the current user
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Intent ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /travel-buddies/intents/{id} [delete]
+func (h *TravelBuddyHandler) DeleteIntent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	intentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da intenção deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.travelBuddyService.DeleteIntent(uint(intentID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao deletar intenção de viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Intenção de viagem deletada com sucesso",
+		Data:    nil,
+	})
+}
+
+// GetSuggestions godoc
+// @Summary Suggest travel buddies
+// @Description Get compatible travelers for a trip intent, scored by date overlap, city, style, mutual interest and follow ties
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Intent ID"
+// @Param limit query int false "Number of suggestions" default(20)
+// @Success 200 {array} models.TravelBuddySuggestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /travel-buddies/intents/{id}/matches [get]
+func (h *TravelBuddyHandler) GetSuggestions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	intentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da intenção deve ser um número válido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	suggestions, err := h.travelBuddyService.GetSuggestions(uint(intentID), userID.(uint), limit)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar viajantes compatíveis",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viajantes compatíveis encontrados",
+		Data:    suggestions,
+	})
+}
+
+// ExpressInterest godoc
+// @Summary Express interest in a traveler
+// @Description Mark interest in another traveler's intent; when the interest is mutual a match is created and a direct conversation is opened
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Own intent ID"
+// @Param request body services.ExpressInterestRequest true "Target intent"
+// @Success 200 {object} services.ExpressInterestResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /travel-buddies/intents/{id}/interest [post]
+func (h *TravelBuddyHandler) ExpressInterest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	intentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da intenção deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ExpressInterestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.travelBuddyService.ExpressInterest(uint(intentID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao registrar interesse",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message := "Interesse registrado"
+	if result.Matched {
+		message = "Interesse mútuo! Conversa iniciada"
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data:    result,
+	})
+}
+
+// GetMatches godoc
+// @Summary List travel buddy matches
+// @Description Get the mutual matches of the current user with their conversation IDs
+// @Tags travel-buddies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of matches per page" default(20)
+// @Param offset query int false "Number of matches to skip" default(0)
+// @Success 200 {array} models.TravelMatchResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /travel-buddies/matches [get]
+func (h *TravelBuddyHandler) GetMatches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	matches, err := h.travelBuddyService.GetMatches(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar matches",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Matches obtidos com sucesso",
+		Data:    matches,
+	})
+}
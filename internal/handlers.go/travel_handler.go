@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TravelHandler struct {
+	travelService     services.TravelServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewTravelHandler(travelService services.TravelServiceInterface, complianceService services.ComplianceServiceInterface) *TravelHandler {
+	return &TravelHandler{
+		travelService:     travelService,
+		complianceService: complianceService,
+	}
+}
+
+// CompleteItinerary godoc
+// @Summary Mark itinerary as traveled
+// @Description Record that the current user traveled the itinerary. trip_id links the scheduled trip that followed it; traveled_at defaults to the trip's end date (or today) and cannot be in the future. Completions feed the travel map, challenges and the completed-trips tab
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body services.CompleteItineraryRequest false "Completion details"
+// @Success 201 {object} models.ItineraryCompletionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/complete [post]
+func (h *TravelHandler) CompleteItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	// O corpo é opcional
+	var req services.CompleteItineraryRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	completion, err := h.travelService.CompleteItinerary(uint(itineraryID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao registrar viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Viagem registrada com sucesso",
+		Data:    completion,
+	})
+}
+
+// UncompleteItinerary godoc
+// @Summary Unmark itinerary as traveled
+// @Description Remove the current user's completion of the itinerary. Check-ins are kept
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/complete [delete]
+func (h *TravelHandler) UncompleteItinerary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.travelService.UncompleteItinerary(uint(itineraryID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viagem removida com sucesso",
+		Data:    nil,
+	})
+}
+
+// CheckIn godoc
+// @Summary Check in at a location
+// @Description Check in at a location of the itinerary, with optional photos (up to 10 http(s) URLs) and a note. Checking in again updates the photos and note, keeping the original check-in time
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param locationId path int true "Location ID"
+// @Param request body services.CheckInRequest false "Check-in details"
+// @Success 200 {object} models.LocationCheckIn
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/locations/{locationId}/check-in [post]
+func (h *TravelHandler) CheckIn(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, locationID, ok := locationParams(c)
+	if !ok {
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, itineraryID) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	// O corpo é opcional
+	var req services.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	checkIn, err := h.travelService.CheckIn(itineraryID, locationID, userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao fazer check-in",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Check-in realizado com sucesso",
+		Data:    checkIn,
+	})
+}
+
+// RemoveCheckIn godoc
+// @Summary Remove a check-in
+// @Description Remove the current user's check-in at a location of the itinerary
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param locationId path int true "Location ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /itineraries/{id}/locations/{locationId}/check-in [delete]
+func (h *TravelHandler) RemoveCheckIn(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, locationID, ok := locationParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.travelService.RemoveCheckIn(itineraryID, locationID, userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover check-in",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Check-in removido com sucesso",
+		Data:    nil,
+	})
+}
+
+// GetItineraryProgress godoc
+// @Summary Get travel progress on an itinerary
+// @Description Get whether the current user traveled the itinerary and their check-ins at its locations
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} services.ItineraryProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/check-ins [get]
+func (h *TravelHandler) GetItineraryProgress(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	progress, err := h.travelService.GetItineraryProgress(uint(itineraryID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar check-ins",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Check-ins encontrados",
+		Data:    progress,
+	})
+}
+
+// GetCompletedTrips godoc
+// @Summary List completed trips
+// @Description List the itineraries a user marked as traveled, most recent first, with the number of check-ins of each. Other users only see completions of public itineraries
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param limit query int false "Number of trips per page" default(20)
+// @Param offset query int false "Number of trips to skip" default(0)
+// @Success 200 {array} models.ItineraryCompletionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/{id}/completed-trips [get]
+func (h *TravelHandler) GetCompletedTrips(c *gin.Context) {
+	viewerID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	trips, err := h.travelService.GetCompletedTrips(uint(targetID), viewerID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar viagens concluídas",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viagens concluídas encontradas",
+		Data:    trips,
+	})
+}
+
+// GetTravelMap godoc
+// @Summary Get travel map
+// @Description Get the countries and cities of a user's completed trips and the points of their check-ins. Check-ins at private itineraries are only shown to the user themselves
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} services.TravelMap
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/{id}/travel-map [get]
+func (h *TravelHandler) GetTravelMap(c *gin.Context) {
+	viewerID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	travelMap, err := h.travelService.GetTravelMap(uint(targetID), viewerID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar mapa de viagens",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Mapa de viagens obtido com sucesso",
+		Data:    travelMap,
+	})
+}
+
+func locationParams(c *gin.Context) (uint, uint, bool) {
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return 0, 0, false
+	}
+
+	locationID, err := strconv.ParseUint(c.Param("locationId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do local deve ser um número válido",
+		})
+		return 0, 0, false
+	}
+
+	return uint(itineraryID), uint(locationID), true
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TripBudgetHandler struct {
+	tripBudgetService services.TripBudgetServiceInterface
+}
+
+func NewTripBudgetHandler(tripBudgetService services.TripBudgetServiceInterface) *TripBudgetHandler {
+	return &TripBudgetHandler{
+		tripBudgetService: tripBudgetService,
+	}
+}
+
+// GetBudgetReport godoc
+// @Summary Get the trip budget report
+// @Description Compare the itinerary's estimated budget (see /itineraries/{id}/budget) with the expenses recorded between the trip start and end dates, per category (lodging, food, transport, activities, shopping, other). All amounts are converted to the requested currency. Expenses are only counted when the traveler takes part in the itinerary. Use format=csv to download the report as a CSV file
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Param currency query string false "ISO 4217 currency to convert the amounts to (defaults to the itinerary currency)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} services.TripBudgetReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /trips/{id}/budget-report [get]
+func (h *TripBudgetHandler) GetBudgetReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		report, err := h.tripBudgetService.GetBudgetReport(uint(tripID), userID.(uint), c.Query("currency"))
+		if err != nil {
+			errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+				Error:   "Erro ao gerar relatório de orçamento",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, SuccessResponse{
+			Message: "Relatório de orçamento gerado com sucesso",
+			Data:    report,
+		})
+	case "csv":
+		result, err := h.tripBudgetService.ExportBudgetReport(uint(tripID), userID.(uint), c.Query("currency"))
+		if err != nil {
+			errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+				Error:   "Erro ao gerar relatório de orçamento",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		respondExport(c, result)
+	default:
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Formato inválido",
+			Message: "Use format=json ou format=csv",
+		})
+	}
+}
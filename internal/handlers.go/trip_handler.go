@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TripHandler struct {
+	tripService services.TripServiceInterface
+}
+
+func NewTripHandler(tripService services.TripServiceInterface) *TripHandler {
+	return &TripHandler{
+		tripService: tripService,
+	}
+}
+
+// CreateTrip godoc
+// @Summary Schedule a trip
+// @Description Schedule a real trip following an itinerary: day 1 of the itinerary falls on start_date. The end date defaults to the start date plus the itinerary duration. Reminder notifications are sent before the trip starts (see /trips/{id}/reminders)
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateTripRequest true "Trip data"
+// @Success 201 {object} models.TripResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips [post]
+func (h *TripHandler) CreateTrip(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.CreateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	trip, err := h.tripService.CreateTrip(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao criar viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Viagem criada com sucesso",
+		Data:    trip,
+	})
+}
+
+// GetTrip godoc
+// @Summary Get a trip schedule
+// @Description Get a trip with the itinerary days on their calendar dates and the locations' start and end times as concrete timestamps, in each day's timezone
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Success 200 {object} services.TripSchedule
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id} [get]
+func (h *TripHandler) GetTrip(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	schedule, err := h.tripService.GetTrip(uint(tripID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viagem encontrada",
+		Data:    schedule,
+	})
+}
+
+// UpdateTrip godoc
+// @Summary Update a trip
+// @Description Change the trip title, notes or dates. Changing only the start date moves the whole trip, keeping its length
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Param request body services.UpdateTripRequest true "Trip changes"
+// @Success 200 {object} models.TripResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id} [put]
+func (h *TripHandler) UpdateTrip(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.UpdateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	trip, err := h.tripService.UpdateTrip(uint(tripID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viagem atualizada com sucesso",
+		Data:    trip,
+	})
+}
+
+// DeleteTrip godoc
+// @Summary Delete a trip
+// @Description Remove a scheduled trip; the itinerary is not affected
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id} [delete]
+func (h *TripHandler) DeleteTrip(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.tripService.DeleteTrip(uint(tripID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover viagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viagem removida com sucesso",
+	})
+}
+
+// GetUpcomingTrips godoc
+// @Summary List upcoming trips
+// @Description List the current user's ongoing and future trips, closest first, with a countdown in days
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of trips per page" default(20)
+// @Param offset query int false "Number of trips to skip" default(0)
+// @Success 200 {array} models.TripResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/trips/upcoming [get]
+func (h *TripHandler) GetUpcomingTrips(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	trips, err := h.tripService.GetUpcomingTrips(userID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar viagens",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Viagens encontradas",
+		Data:    trips,
+	})
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TripReminderHandler struct {
+	tripReminderService services.TripReminderServiceInterface
+}
+
+func NewTripReminderHandler(tripReminderService services.TripReminderServiceInterface) *TripReminderHandler {
+	return &TripReminderHandler{
+		tripReminderService: tripReminderService,
+	}
+}
+
+// GetReminderSettings godoc
+// @Summary Get the trip reminder settings
+// @Description Get how many days before the trip starts the reminders are sent (7 and 1 by default). Reminders carry the packing list status and the weather outlook and follow the trip_reminder notification preferences
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Success 200 {object} models.TripReminderSetting
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id}/reminders [get]
+func (h *TripReminderHandler) GetReminderSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	setting, err := h.tripReminderService.GetReminderSettings(uint(tripID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar lembretes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lembretes da viagem encontrados",
+		Data:    setting,
+	})
+}
+
+// UpdateReminderSettings godoc
+// @Summary Choose the trip reminders
+// @Description Choose which reminders the trip gets: 7 days and/or 1 day before it starts. An empty list disables them
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Param request body services.TripReminderSettingsRequest true "Reminder days"
+// @Success 200 {object} models.TripReminderSetting
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id}/reminders [put]
+func (h *TripReminderHandler) UpdateReminderSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.TripReminderSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	setting, err := h.tripReminderService.UpdateReminderSettings(uint(tripID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar lembretes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lembretes da viagem atualizados",
+		Data:    setting,
+	})
+}
+
+// GetPackingList godoc
+// @Summary Get the trip packing list
+// @Description List the items of the trip packing list, in the order they were added; the reminders before the trip report how many are packed
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Success 200 {array} models.TripPackingItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id}/packing [get]
+func (h *TripReminderHandler) GetPackingList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	items, err := h.tripReminderService.GetPackingList(uint(tripID), userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar lista de bagagem",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Lista de bagagem encontrada",
+		Data:    items,
+	})
+}
+
+// AddPackingItem godoc
+// @Summary Add a packing list item
+// @Description Add an item (up to 100 characters) to the trip packing list, which holds up to 200 items
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Param request body services.PackingItemRequest true "Item"
+// @Success 201 {object} models.TripPackingItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id}/packing [post]
+func (h *TripReminderHandler) AddPackingItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.PackingItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	item, err := h.tripReminderService.AddPackingItem(uint(tripID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao adicionar item",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Item adicionado à lista de bagagem",
+		Data:    item,
+	})
+}
+
+// UpdatePackingItem godoc
+// @Summary Update a packing list item
+// @Description Rename an item or mark it as packed or not
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Param itemId path int true "Item ID"
+// @Param request body services.UpdatePackingItemRequest true "Item changes"
+// @Success 200 {object} models.TripPackingItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id}/packing/{itemId} [patch]
+func (h *TripReminderHandler) UpdatePackingItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do item deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.UpdatePackingItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	packingItem, err := h.tripReminderService.UpdatePackingItem(uint(tripID), uint(itemID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao atualizar item",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Item atualizado com sucesso",
+		Data:    packingItem,
+	})
+}
+
+// DeletePackingItem godoc
+// @Summary Delete a packing list item
+// @Description Remove an item from the trip packing list
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Trip ID"
+// @Param itemId path int true "Item ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trips/{id}/packing/{itemId} [delete]
+func (h *TripReminderHandler) DeletePackingItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID da viagem deve ser um número válido",
+		})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do item deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.tripReminderService.DeletePackingItem(uint(tripID), uint(itemID), userID.(uint)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao remover item",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Item removido da lista de bagagem",
+	})
+}
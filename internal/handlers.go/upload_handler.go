@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// UploadOffsetHeader informa o offset de cada parte enviada e, nas respostas,
+// até onde o upload já chegou
+const UploadOffsetHeader = "Upload-Offset"
+
+type UploadHandler struct {
+	uploadService services.UploadServiceInterface
+}
+
+func NewUploadHandler(uploadService services.UploadServiceInterface) *UploadHandler {
+	return &UploadHandler{
+		uploadService: uploadService,
+	}
+}
+
+// CreateUpload godoc
+// @Summary Start a chunked upload
+// @Description Open a resumable upload session for a large file (videos up to the chunked upload limit). Send the bytes with PATCH /media/uploads/{id} and finish with POST /media/uploads/{id}/complete. Sessions expire after a period without new chunks
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.CreateUploadRequest true "File metadata"
+// @Success 201 {object} models.UploadSession
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /media/uploads [post]
+func (h *UploadHandler) CreateUpload(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	session, err := h.uploadService.CreateSession(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, uploadStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao iniciar upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Upload iniciado",
+		Data:    session,
+	})
+}
+
+// GetUpload godoc
+// @Summary Get a chunked upload
+// @Description Get the upload session, including the offset to resume from after a dropped connection. The offset is also returned in the Upload-Offset header
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Upload session ID"
+// @Success 200 {object} models.UploadSession
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /media/uploads/{id} [get]
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	userID, sessionID, ok := uploadParams(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.uploadService.GetSession(sessionID, userID)
+	if err != nil {
+		errorJSON(c, uploadStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header(UploadOffsetHeader, strconv.FormatInt(session.Offset, 10))
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Upload encontrado",
+		Data:    session,
+	})
+}
+
+// AppendUpload godoc
+// @Summary Send a chunk of a chunked upload
+// @Description Append the raw request body (up to 10MB) to the upload. The Upload-Offset header must match the session's current offset; a mismatch returns 409 with the expected offset. If the connection drops mid-chunk, the bytes received are kept and the client resumes from the new offset
+// @Tags media
+// @Accept application/offset+octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Upload session ID"
+// @Param Upload-Offset header int true "Offset of the first byte of the chunk"
+// @Success 200 {object} models.UploadSession
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /media/uploads/{id} [patch]
+func (h *UploadHandler) AppendUpload(c *gin.Context) {
+	userID, sessionID, ok := uploadParams(c)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader(UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Offset inválido",
+			Message: "O cabeçalho Upload-Offset deve ser um número válido",
+		})
+		return
+	}
+
+	session, err := h.uploadService.AppendChunk(sessionID, userID, offset, c.Request.Body)
+	if err != nil {
+		errorJSON(c, uploadStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao enviar parte do upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header(UploadOffsetHeader, strconv.FormatInt(session.Offset, 10))
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Parte recebida",
+		Data:    session,
+	})
+}
+
+// CompleteUpload godoc
+// @Summary Complete a chunked upload
+// @Description Store the uploaded file once every byte has been received and register it as the user's media. Calling it again returns the same media
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Upload session ID"
+// @Success 200 {object} services.MediaUploadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /media/uploads/{id}/complete [post]
+func (h *UploadHandler) CompleteUpload(c *gin.Context) {
+	userID, sessionID, ok := uploadParams(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.uploadService.CompleteSession(sessionID, userID)
+	if err != nil {
+		errorJSON(c, uploadStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao concluir upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Upload concluído com sucesso",
+		Data:    response,
+	})
+}
+
+// AbortUpload godoc
+// @Summary Abort a chunked upload
+// @Description Cancel an unfinished upload and discard the bytes received
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Upload session ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /media/uploads/{id} [delete]
+func (h *UploadHandler) AbortUpload(c *gin.Context) {
+	userID, sessionID, ok := uploadParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uploadService.AbortSession(sessionID, userID); err != nil {
+		errorJSON(c, uploadStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao cancelar upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Upload cancelado",
+		Data:    nil,
+	})
+}
+
+func uploadParams(c *gin.Context) (userID, sessionID uint, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do upload deve ser um número válido",
+		})
+		return 0, 0, false
+	}
+
+	return user.(uint), uint(id), true
+}
+
+func uploadStatusCode(errorMsg string) int {
+	switch {
+	case strings.Contains(errorMsg, "muito grande"), strings.Contains(errorMsg, "excede"):
+		return http.StatusRequestEntityTooLarge
+	case strings.Contains(errorMsg, "uploads em andamento atingido"):
+		return http.StatusTooManyRequests
+	case strings.Contains(errorMsg, "offset inválido"), strings.Contains(errorMsg, "já concluído"),
+		strings.Contains(errorMsg, "incompleto"), strings.Contains(errorMsg, "já foram recebidos"):
+		return http.StatusConflict
+	default:
+		return errorStatusCode(errorMsg)
+	}
+}
@@ -33,7 +33,7 @@ func NewUserHandler(userService services.UserServiceInterface) *UserHandler {
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -47,7 +47,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao buscar perfil",
 			Message: err.Error(),
 		})
@@ -77,7 +77,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -86,7 +86,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	var req services.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -105,7 +105,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao atualizar perfil",
 			Message: errorMsg,
 		})
@@ -118,6 +118,98 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	})
 }
 
+// UploadAvatar godoc
+// @Summary Upload profile picture
+// @Description Upload a new profile picture, cropped to a square (400, 200 and 64 px); the previous picture is deleted
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Image file"
+// @Param x formData int false "Crop rectangle left edge, in pixels"
+// @Param y formData int false "Crop rectangle top edge, in pixels"
+// @Param width formData int false "Crop rectangle width, in pixels (empty crops the center)"
+// @Param height formData int false "Crop rectangle height, in pixels"
+// @Success 200 {object} services.ProfileImageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/avatar [post]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	h.uploadProfileImage(c, services.ProfileImageAvatar, "Foto de perfil atualizada com sucesso")
+}
+
+// UploadCover godoc
+// @Summary Upload cover photo
+// @Description Upload a new cover photo, cropped to a 3:1 banner (1500x500 and 750x250 px); the previous cover is deleted
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Image file"
+// @Param x formData int false "Crop rectangle left edge, in pixels"
+// @Param y formData int false "Crop rectangle top edge, in pixels"
+// @Param width formData int false "Crop rectangle width, in pixels (empty crops the center)"
+// @Param height formData int false "Crop rectangle height, in pixels"
+// @Success 200 {object} services.ProfileImageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/cover [post]
+func (h *UserHandler) UploadCover(c *gin.Context) {
+	h.uploadProfileImage(c, services.ProfileImageCover, "Foto de capa atualizada com sucesso")
+}
+
+func (h *UserHandler) uploadProfileImage(c *gin.Context, kind services.ProfileImageKind, successMessage string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Arquivo não encontrado",
+			Message: "É necessário enviar um arquivo no campo 'file'",
+		})
+		return
+	}
+
+	var crop services.CropRect
+	if err := c.ShouldBind(&crop); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: "A área de recorte deve ter números inteiros em x, y, width e height",
+		})
+		return
+	}
+
+	response, err := h.userService.UpdateProfileImage(userID.(uint), kind, file, &crop)
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if contains(err.Error(), "muito grande") {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao atualizar foto",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: successMessage,
+		Data:    response,
+	})
+}
+
 // GetUserByID godoc
 // @Summary Get user by ID
 // @Description Get a user's public profile by their ID
@@ -135,7 +227,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	idParam := c.Param("id")
 	userID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do usuário deve ser um número válido",
 		})
@@ -149,7 +241,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao buscar usuário",
 			Message: err.Error(),
 		})
@@ -164,7 +256,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 // SearchUsers godoc
 // @Summary Search users
-// @Description Search for users by username, name or company name
+// @Description Search for users by username, name or company name. Usernames and names are matched by trigram similarity ignoring accents, so misspelled queries still find the profile; results are ranked by similarity, with more followed users first among close matches
 // @Tags users
 // @Accept json
 // @Produce json
@@ -179,7 +271,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 func (h *UserHandler) SearchUsers(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Parâmetro obrigatório",
 			Message: "O parâmetro 'q' (query) é obrigatório",
 		})
@@ -198,7 +290,7 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 
 	users, err := h.userService.SearchUsers(query, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "Erro na busca",
 			Message: err.Error(),
 		})
@@ -213,7 +305,7 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 
 // FollowUser godoc
 // @Summary Follow a user
-// @Description Follow another user
+// @Description Follow another user. Following many accounts in a short time, or following and unfollowing the same accounts, flags the account for moderation as spam; past the spam score limit the account cannot post or follow for a while (429)
 // @Tags users
 // @Accept json
 // @Produce json
@@ -224,12 +316,13 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 // @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users/{id}/follow [post]
 func (h *UserHandler) FollowUser(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -239,7 +332,7 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 	idParam := c.Param("id")
 	followedID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do usuário deve ser um número válido",
 		})
@@ -256,11 +349,13 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		case contains(errorMsg, "não pode seguir a si mesmo"), contains(errorMsg, "já está seguindo"):
 			statusCode = http.StatusConflict
+		case contains(errorMsg, "limitada por suspeita de spam"):
+			statusCode = http.StatusTooManyRequests
 		case contains(errorMsg, "inválido"):
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao seguir usuário",
 			Message: errorMsg,
 		})
@@ -291,7 +386,7 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
 func (h *UserHandler) UnfollowUser(c *gin.Context) {
 	currentUserID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -301,7 +396,7 @@ func (h *UserHandler) UnfollowUser(c *gin.Context) {
 	idParam := c.Param("id")
 	followedID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do usuário deve ser um número válido",
 		})
@@ -320,7 +415,7 @@ func (h *UserHandler) UnfollowUser(c *gin.Context) {
 			statusCode = http.StatusBadRequest
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao deixar de seguir usuário",
 			Message: errorMsg,
 		})
@@ -351,7 +446,7 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 	idParam := c.Param("id")
 	userID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do usuário deve ser um número válido",
 		})
@@ -370,7 +465,7 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 
 	followers, err := h.userService.GetFollowers(uint(userID), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "Erro ao buscar seguidores",
 			Message: err.Error(),
 		})
@@ -401,7 +496,7 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 	idParam := c.Param("id")
 	userID, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "ID inválido",
 			Message: "O ID do usuário deve ser um número válido",
 		})
@@ -420,7 +515,7 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 
 	following, err := h.userService.GetFollowing(uint(userID), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "Erro ao buscar usuários seguidos",
 			Message: err.Error(),
 		})
@@ -433,6 +528,141 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 	})
 }
 
+// BlockUser godoc
+// @Summary Block a user
+// @Description Block another user; the direct conversation between the two is frozen until every block between them is removed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID to block"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /users/{id}/block [post]
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	blockedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.userService.BlockUser(currentUserID.(uint), uint(blockedID)); err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if contains(err.Error(), "já bloqueou") {
+			statusCode = http.StatusConflict
+		}
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao bloquear usuário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuário bloqueado com sucesso",
+	})
+}
+
+// UnblockUser godoc
+// @Summary Unblock a user
+// @Description Remove a block; the direct conversation is unfrozen unless the other user also blocked
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID to unblock"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/{id}/block [delete]
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	blockedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do usuário deve ser um número válido",
+		})
+		return
+	}
+
+	if err := h.userService.UnblockUser(currentUserID.(uint), uint(blockedID)); err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao desbloquear usuário",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuário desbloqueado com sucesso",
+	})
+}
+
+// GetBlockedUsers godoc
+// @Summary List blocked users
+// @Description Get the users blocked by the authenticated user, most recent first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of results per page" default(20)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {array} models.UserResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/blocked [get]
+func (h *UserHandler) GetBlockedUsers(c *gin.Context) {
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	users, err := h.userService.GetBlockedUsers(currentUserID.(uint), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar usuários bloqueados",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Usuários bloqueados obtidos com sucesso",
+		Data:    users,
+	})
+}
+
 // ChangePassword godoc
 // @Summary Change user password
 // @Description Change the password of the authenticated user
@@ -449,7 +679,7 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 func (h *UserHandler) ChangePassword(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -458,7 +688,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 
 	var req ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Dados inválidos",
 			Message: err.Error(),
 		})
@@ -479,7 +709,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 			statusCode = http.StatusNotFound
 		}
 
-		c.JSON(statusCode, ErrorResponse{
+		errorJSON(c, statusCode, ErrorResponse{
 			Error:   "Erro ao alterar senha",
 			Message: errorMsg,
 		})
@@ -506,7 +736,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 func (h *UserHandler) DeactivateAccount(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
 			Error:   "Não autorizado",
 			Message: "Token inválido",
 		})
@@ -515,7 +745,7 @@ func (h *UserHandler) DeactivateAccount(c *gin.Context) {
 
 	err := h.userService.DeactivateAccount(userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
 			Error:   "Erro ao desativar conta",
 			Message: err.Error(),
 		})
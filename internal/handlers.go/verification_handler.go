@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type VerificationHandler struct {
+	verificationService services.VerificationServiceInterface
+}
+
+func NewVerificationHandler(verificationService services.VerificationServiceInterface) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+	}
+}
+
+// RequestVerification godoc
+// @Summary Request account verification
+// @Description Ask for the verified badge, explaining why the account should be verified. Only one request can be under review at a time, and after a rejection a new request is accepted after 30 days
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.RequestVerificationRequest true "Verification request"
+// @Success 201 {object} models.VerificationRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/verification [post]
+func (h *VerificationHandler) RequestVerification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req services.RequestVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	request, err := h.verificationService.RequestVerification(userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao pedir verificação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Message: "Pedido de verificação enviado com sucesso",
+		Data:    request,
+	})
+}
+
+// GetMyVerification godoc
+// @Summary Get my verification request
+// @Description Get the user's latest verification request and its review status
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.VerificationRequestResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/verification [get]
+func (h *VerificationHandler) GetMyVerification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	request, err := h.verificationService.GetMyVerification(userID.(uint))
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar pedido de verificação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Pedido de verificação obtido com sucesso",
+		Data:    request,
+	})
+}
+
+// GetVerificationRequests godoc
+// @Summary Verification review queue (admin)
+// @Description Get verification requests by status, oldest first, with the requesting account
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Request status (pending, approved, rejected)" default(pending)
+// @Param limit query int false "Number of requests per page (max 100)" default(20)
+// @Param offset query int false "Number of requests to skip" default(0)
+// @Success 200 {array} models.VerificationRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/verifications [get]
+func (h *VerificationHandler) GetVerificationRequests(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	status := models.VerificationStatus(c.Query("status"))
+
+	requests, err := h.verificationService.GetVerificationRequests(status, limit, offset)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar pedidos de verificação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Pedidos de verificação obtidos com sucesso",
+		Data:    requests,
+	})
+}
+
+// ReviewVerification godoc
+// @Summary Review a verification request (admin)
+// @Description Approve a pending verification request, giving the account the verified badge, or reject it. The user is notified and the note is shown to them
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Verification request ID"
+// @Param request body services.ReviewVerificationRequest true "Decision"
+// @Success 200 {object} models.VerificationRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/verifications/{id} [put]
+func (h *VerificationHandler) ReviewVerification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do pedido deve ser um número válido",
+		})
+		return
+	}
+
+	var req services.ReviewVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	request, err := h.verificationService.ReviewVerification(uint(requestID), userID.(uint), &req)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao analisar pedido de verificação",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Pedido de verificação analisado com sucesso",
+		Data:    request,
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type WeatherHandler struct {
+	weatherService    services.WeatherServiceInterface
+	complianceService services.ComplianceServiceInterface
+}
+
+func NewWeatherHandler(weatherService services.WeatherServiceInterface, complianceService services.ComplianceServiceInterface) *WeatherHandler {
+	return &WeatherHandler{
+		weatherService:    weatherService,
+		complianceService: complianceService,
+	}
+}
+
+// GetItineraryWeather godoc
+// @Summary Get the weather forecast for each itinerary day
+// @Description Daily forecast for each itinerary day, dated from the trip start date, at the day's primary location (its lodging or, without one, the first geolocated location; days without locations use the previous day's). Forecasts are cached per city and day. Days in the past or beyond the provider's forecast range are returned with status out_of_range
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param start_date query string true "Date of the first trip day (YYYY-MM-DD)"
+// @Success 200 {object} services.ItineraryWeather
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 451 {object} ErrorResponse
+// @Router /itineraries/{id}/weather [get]
+func (h *WeatherHandler) GetItineraryWeather(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	itineraryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "O ID do roteiro deve ser um número válido",
+		})
+		return
+	}
+
+	startDate, err := parseDateParam(c.Query("start_date"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Data inválida",
+			Message: "Use o formato YYYY-MM-DD para start_date",
+		})
+		return
+	}
+
+	if isUnavailableInCountry(c, h.complianceService, models.RestrictedContentItinerary, uint(itineraryID)) {
+		respondUnavailableInCountry(c)
+		return
+	}
+
+	weather, err := h.weatherService.GetItineraryWeather(uint(itineraryID), userID.(uint), startDate)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar previsão do tempo",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Previsão do tempo encontrada",
+		Data:    weather,
+	})
+}
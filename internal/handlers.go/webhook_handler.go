@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookSignatureHeader é o cabeçalho de assinatura dos provedores que não
+// usam o cabeçalho próprio da Stripe
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+type WebhookHandler struct {
+	webhookService services.WebhookServiceInterface
+}
+
+func NewWebhookHandler(webhookService services.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ReceiveWebhook godoc
+// @Summary Receive a provider webhook
+// @Description Receive a signed callback from the payment (stripe), transcoding or push provider. The signature header ("Stripe-Signature" for stripe, "X-Webhook-Signature" otherwise) has the form "t=<unix>,v1=<hex HMAC-SHA256 of t.payload>". Repeated deliveries of the same event are acknowledged without being processed again
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider (stripe, transcoding, push)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /webhooks/{provider} [post]
+func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
+	// O corpo é lido até um byte além do limite, o suficiente para o serviço
+	// recusar o payload grande sem carregar o corpo inteiro na memória
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxWebhookPayload+1)
+	payload, err := c.GetRawData()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			errorJSON(c, http.StatusRequestEntityTooLarge, ErrorResponse{
+				Error:   "Erro ao receber webhook",
+				Message: "payload do webhook muito grande",
+			})
+			return
+		}
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Dados inválidos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	signature := c.GetHeader("Stripe-Signature")
+	if signature == "" {
+		signature = c.GetHeader(WebhookSignatureHeader)
+	}
+
+	event, duplicate, err := h.webhookService.Receive(c.Param("provider"), signature, payload)
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if strings.Contains(err.Error(), "muito grande") {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao receber webhook",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	message := "Evento recebido com sucesso"
+	if duplicate {
+		message = "Evento já recebido"
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: message,
+		Data: gin.H{
+			"id":     event.ID,
+			"status": event.Status,
+		},
+	})
+}
+
+// GetWebhookEvents godoc
+// @Summary List webhook events (admin)
+// @Description List received webhook events, optionally filtered by provider and status (use status=dead_letter for the dead-letter queue)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param provider query string false "Provider (stripe, transcoding, push)"
+// @Param status query string false "Status (pending, processing, processed, ignored, failed, dead_letter)"
+// @Param limit query int false "Number of events per page" default(20)
+// @Param offset query int false "Number of events to skip" default(0)
+// @Success 200 {array} models.WebhookEvent
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) GetWebhookEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	events, err := h.webhookService.GetEvents(c.Query("provider"), c.Query("status"), limit, offset)
+	if err != nil {
+		errorJSON(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Erro ao buscar eventos",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Eventos obtidos com sucesso",
+		Data:    events,
+	})
+}
+
+// RedeliverWebhookEvent godoc
+// @Summary Redeliver a webhook event (admin)
+// @Description Put a webhook event back in the processing queue with its attempts reset, typically to drain the dead-letter queue after fixing the cause
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook event ID"
+// @Success 202 {object} models.WebhookEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/webhooks/{id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhookEvent(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "ID inválido",
+			Message: "ID do evento deve ser um número",
+		})
+		return
+	}
+
+	event, err := h.webhookService.Redeliver(uint(eventID))
+	if err != nil {
+		statusCode := errorStatusCode(err.Error())
+		if strings.Contains(err.Error(), "já está") {
+			statusCode = http.StatusConflict
+		}
+		errorJSON(c, statusCode, ErrorResponse{
+			Error:   "Erro ao reenviar evento",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Message: "Evento reenfileirado com sucesso",
+		Data:    event,
+	})
+}
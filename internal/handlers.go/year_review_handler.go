@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type YearReviewHandler struct {
+	yearReviewService services.YearReviewServiceInterface
+}
+
+func NewYearReviewHandler(yearReviewService services.YearReviewServiceInterface) *YearReviewHandler {
+	return &YearReviewHandler{
+		yearReviewService: yearReviewService,
+	}
+}
+
+// GetYearReview godoc
+// @Summary Year in review
+// @Description Get the current user's shareable summary for the year (countries visited, estimated km traveled, top photos, most-liked post) with a generated image
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param year path int true "Year"
+// @Success 200 {object} models.YearReviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/year-review/{year} [get]
+func (h *YearReviewHandler) GetYearReview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorJSON(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Não autorizado",
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		errorJSON(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Ano inválido",
+			Message: "O ano deve ser um número válido",
+		})
+		return
+	}
+
+	review, err := h.yearReviewService.GetYearReview(userID.(uint), year)
+	if err != nil {
+		errorJSON(c, errorStatusCode(err.Error()), ErrorResponse{
+			Error:   "Erro ao buscar resumo do ano",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Resumo do ano obtido com sucesso",
+		Data:    review,
+	})
+}
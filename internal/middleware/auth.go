@@ -15,12 +15,19 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// AccountChecker diz se a conta ainda pode usar a API; o erro (conta
+// suspensa, banida ou desativada) é devolvido ao cliente
+type AccountChecker func(userID uint) error
+
+// AuthMiddleware valida o token e a situação da conta, para que a suspensão
+// e o banimento derrubem na hora os tokens já emitidos
+func AuthMiddleware(jwtSecret string, checkAccount AccountChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Token de autorização requerido",
+				"error":    "Token de autorização requerido",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
@@ -30,7 +37,8 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Formato de token inválido",
+				"error":    "Formato de token inválido",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
@@ -43,13 +51,19 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Token inválido",
+				"error":    "Token inválido",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
 		}
 
 		if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+			if err := checkAccount(claims.UserID); err != nil {
+				abortAccountRestricted(c, err)
+				return
+			}
+
 			// Adicionar informações do usuário ao contexto
 			c.Set("user_id", claims.UserID)
 			c.Set("username", claims.Username)
@@ -57,21 +71,101 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			c.Next()
 		} else {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Token inválido",
+				"error":    "Token inválido",
+				"trace_id": c.GetString(RequestIDKey),
+			})
+			c.Abort()
+			return
+		}
+	}
+}
+
+// OptionalAuthMiddleware identifica o usuário quando há um token válido, mas
+// deixa seguir requisições anônimas (ex.: relatos de erro antes do login)
+func OptionalAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString == "" {
+			c.Next()
+			return
+		}
+
+		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+			return []byte(jwtSecret), nil
+		})
+		if err == nil && token.Valid {
+			if claims, ok := token.Claims.(*Claims); ok {
+				c.Set("user_id", claims.UserID)
+				c.Set("username", claims.Username)
+				c.Set("user_type", claims.UserType)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// StreamAuthMiddleware autentica a abertura das conexões em tempo real
+// (WebSocket e SSE). Como os navegadores não enviam cabeçalhos no upgrade nem
+// no EventSource, o token também é aceito no parâmetro "token" da URL
+func StreamAuthMiddleware(jwtSecret string, checkAccount AccountChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString == "" {
+			tokenString = c.Query("token")
+		}
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":    "Token de autorização requerido",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
 		}
+
+		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+			return []byte(jwtSecret), nil
+		})
+		var claims *Claims
+		if err == nil && token.Valid {
+			claims, _ = token.Claims.(*Claims)
+		}
+		if claims == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":    "Token inválido",
+				"trace_id": c.GetString(RequestIDKey),
+			})
+			c.Abort()
+			return
+		}
+		if err := checkAccount(claims.UserID); err != nil {
+			abortAccountRestricted(c, err)
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("user_type", claims.UserType)
+		c.Next()
 	}
 }
 
+func abortAccountRestricted(c *gin.Context, err error) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":    err.Error(),
+		"trace_id": c.GetString(RequestIDKey),
+	})
+	c.Abort()
+}
+
 // AdminMiddleware verifica se o usuário é admin
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userType, exists := c.Get("user_type")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Usuário não autenticado",
+				"error":    "Usuário não autenticado",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
@@ -79,7 +173,8 @@ func AdminMiddleware() gin.HandlerFunc {
 
 		if userType != "admin" {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Acesso negado. Apenas administradores podem acessar este recurso",
+				"error":    "Acesso negado. Apenas administradores podem acessar este recurso",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
@@ -95,7 +190,8 @@ func CompanyMiddleware() gin.HandlerFunc {
 		userType, exists := c.Get("user_type")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Usuário não autenticado",
+				"error":    "Usuário não autenticado",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
@@ -103,7 +199,8 @@ func CompanyMiddleware() gin.HandlerFunc {
 
 		if userType != "company" && userType != "admin" {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Acesso negado. Apenas empresas podem acessar este recurso",
+				"error":    "Acesso negado. Apenas empresas podem acessar este recurso",
+				"trace_id": c.GetString(RequestIDKey),
 			})
 			c.Abort()
 			return
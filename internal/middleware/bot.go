@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// BotRiskKey é a chave do contexto com a avaliação de risco da requisição
+const BotRiskKey = "bot_risk"
+
+// FormStartedAtHeader informa quando o cliente abriu o formulário, em
+// milissegundos desde a época Unix
+const FormStartedAtHeader = "X-Form-Started-At"
+
+const (
+	// Tempo mínimo para uma pessoa preencher um formulário
+	minFormFillTime = 3 * time.Second
+	// Corpo máximo lido em busca dos campos honeypot
+	maxHoneypotBodySize = 64 * 1024
+)
+
+// honeypotFields são campos que os clientes oficiais deixam invisíveis e
+// vazios; só robôs que preenchem todo o formulário os enviam
+var honeypotFields = []string{"website", "fax"}
+
+// automationUserAgents identifica clientes HTTP e navegadores automatizados
+var automationUserAgents = []string{
+	"curl", "wget", "python-requests", "python-urllib", "go-http-client",
+	"httpclient", "scrapy", "headlesschrome", "phantomjs", "selenium",
+	"puppeteer", "playwright", "bot", "spider", "crawler",
+}
+
+// BotDetectionMiddleware avalia sinais de automação (honeypot, cabeçalhos e
+// tempo de preenchimento) e deixa no contexto a avaliação de risco, que os
+// handlers de cadastro e publicação usam para bloquear ou registrar a ação
+func BotDetectionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		assessment := &models.RiskAssessment{
+			IPAddress: c.ClientIP(),
+			UserAgent: truncate(c.GetHeader("User-Agent"), 500),
+		}
+
+		score := 0
+		addSignal := func(signal string, weight int) {
+			assessment.Signals = append(assessment.Signals, signal)
+			score += weight
+		}
+
+		if honeypotFilled(c) {
+			addSignal(models.RiskSignalHoneypot, 100)
+		}
+
+		userAgent := strings.ToLower(assessment.UserAgent)
+		if userAgent == "" {
+			addSignal(models.RiskSignalMissingUserAgent, 40)
+		} else {
+			for _, pattern := range automationUserAgents {
+				if strings.Contains(userAgent, pattern) {
+					addSignal(models.RiskSignalAutomationUserAgent, 50)
+					break
+				}
+			}
+		}
+
+		if c.GetHeader("Accept-Language") == "" {
+			addSignal(models.RiskSignalMissingAcceptLanguage, 10)
+		}
+
+		// O horário de abertura do formulário é opcional; quando enviado,
+		// um preenchimento instantâneo ou um horário no futuro é suspeito
+		if startedAt := c.GetHeader(FormStartedAtHeader); startedAt != "" {
+			millis, err := strconv.ParseInt(startedAt, 10, 64)
+			elapsed := time.Since(time.UnixMilli(millis))
+			switch {
+			case err != nil || elapsed < 0:
+				addSignal(models.RiskSignalInvalidFormTiming, 20)
+			case elapsed < minFormFillTime:
+				addSignal(models.RiskSignalFormTooFast, 40)
+			}
+		}
+
+		if score > 100 {
+			score = 100
+		}
+		assessment.Score = score
+
+		c.Set(BotRiskKey, assessment)
+		c.Next()
+	}
+}
+
+// honeypotFilled procura os campos honeypot no corpo JSON, devolvendo o corpo
+// intacto para o handler
+func honeypotFilled(c *gin.Context) bool {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxHoneypotBodySize))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), c.Request.Body))
+	if err != nil {
+		return false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+
+	for _, name := range honeypotFields {
+		value := strings.TrimSpace(string(fields[name]))
+		if value != "" && value != "null" && value != `""` {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate limita o texto a max bytes sem partir um caractere UTF-8 ao meio,
+// o que o Postgres recusaria ao gravar
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestCountryKey é a chave do contexto com o país de origem da requisição
+// (código ISO de duas letras, vazio quando desconhecido)
+const RequestCountryKey = "request_country"
+
+// CountryMiddleware resolve o país de origem da requisição. O cabeçalho da
+// CDN (countryHeader) só vale quando a conexão chega de um dos
+// trustedProxies; nos demais casos o país vem da base de GeoIP aplicada ao IP
+// do cliente, que o Gin só extrai dos cabeçalhos de encaminhamento quando o
+// mesmo conjunto de proxies está configurado no roteador
+func CountryMiddleware(countryHeader string, trustedProxies []string, lookup func(ip string) string) gin.HandlerFunc {
+	var prefixes []netip.Prefix
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		}
+	}
+
+	fromTrustedProxy := func(c *gin.Context) bool {
+		addr, err := netip.ParseAddr(c.RemoteIP())
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		country := ""
+		if countryHeader != "" && fromTrustedProxy(c) {
+			country = normalizeCountryCode(c.GetHeader(countryHeader))
+		}
+		if country == "" && lookup != nil {
+			country = normalizeCountryCode(lookup(c.ClientIP()))
+		}
+
+		c.Set(RequestCountryKey, country)
+		c.Next()
+	}
+}
+
+// normalizeCountryCode aceita só códigos de país de duas letras; os valores
+// especiais das CDNs (XX desconhecido, T1 Tor) contam como desconhecidos
+func normalizeCountryCode(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 2 || value == "XX" || value == "T1" || value[0] < 'A' || value[0] > 'Z' || value[1] < 'A' || value[1] > 'Z' {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware limita as requisições por usuário autenticado (ou por
+// IP) em janelas fixas. O contador fica em memória, então o limite vale por
+// instância da API
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	counters := make(map[string]int)
+	windowStart := time.Now()
+
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		key := "ip:" + c.ClientIP()
+		if userID, exists := c.Get("user_id"); exists {
+			key = fmt.Sprintf("user:%v", userID)
+		}
+
+		mu.Lock()
+		now := time.Now()
+		if now.Sub(windowStart) >= window {
+			counters = make(map[string]int)
+			windowStart = now
+		}
+		counters[key]++
+		count := counters[key]
+		retryAfter := windowStart.Add(window).Sub(now)
+		mu.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":    "Muitas requisições, tente novamente mais tarde",
+				"trace_id": c.GetString(RequestIDKey),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDKey é a chave do contexto com o trace ID da requisição
+const RequestIDKey = "request_id"
+
+// RequestIDHeader propaga o trace ID entre cliente, gateway e API
+const RequestIDHeader = "X-Request-ID"
+
+// ErrorMessageKey guarda no contexto a mensagem de erro respondida, para o
+// registro da requisição
+const ErrorMessageKey = "error_message"
+
+// sensitiveQueryParams nunca são gravados nos registros de requisição
+var sensitiveQueryParams = []string{"token", "password", "secret", "key", "code", "signature", "auth"}
+
+// TracingMiddleware atribui um trace ID a cada requisição (reaproveitando o
+// X-Request-ID recebido quando válido), devolve-o no cabeçalho da resposta e
+// entrega as requisições com erro (status >= 400) para record
+func TracingMiddleware(record func(*models.RequestLog)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		traceID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(traceID) {
+			traceID = uuid.NewString()
+		}
+		c.Set(RequestIDKey, traceID)
+		c.Header(RequestIDHeader, traceID)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < 400 || record == nil {
+			return
+		}
+
+		entry := &models.RequestLog{
+			TraceID:      traceID,
+			Method:       c.Request.Method,
+			Route:        c.FullPath(),
+			Path:         truncate(c.Request.URL.Path, 500),
+			Query:        truncate(sanitizeQuery(c.Request.URL.Query()), 1000),
+			Status:       status,
+			LatencyMs:    time.Since(start).Milliseconds(),
+			ClientIP:     maskIP(c.ClientIP()),
+			UserAgent:    truncate(c.GetHeader("User-Agent"), 500),
+			ErrorMessage: truncate(c.GetString(ErrorMessageKey), 1000),
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(uint); ok {
+				entry.UserID = &id
+			}
+		}
+
+		record(entry)
+	}
+}
+
+// validRequestID aceita IDs de até 64 caracteres alfanuméricos, '-' ou '_'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeQuery mascara os parâmetros sensíveis da query string
+func sanitizeQuery(values url.Values) string {
+	for name := range values {
+		lower := strings.ToLower(name)
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.Contains(lower, sensitive) {
+				values[name] = []string{"REDACTED"}
+				break
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// maskIP remove o último octeto do IPv4 ou os últimos 80 bits do IPv6
+func maskIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
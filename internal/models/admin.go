@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// AdminUserResponse é o usuário como visto pelos administradores, com os
+// dados da conta que o perfil público não mostra
+type AdminUserResponse struct {
+	UserResponse
+	IsActive   bool          `json:"is_active"`
+	Status     AccountStatus `json:"status"`
+	RiskScore  int           `json:"risk_score"`
+	LastSeenAt *time.Time    `json:"last_seen_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+
+	// Suspensão ou banimento em vigor
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+
+	// Limite automático por suspeita de spam em vigor
+	SpamLimitedUntil *time.Time `json:"spam_limited_until,omitempty"`
+}
+
+func (u *User) ToAdminResponse() *AdminUserResponse {
+	response := &AdminUserResponse{
+		UserResponse: *u.ToResponse(),
+		IsActive:     u.IsActive,
+		Status:       u.AccountStatus(time.Now()),
+		RiskScore:    u.RiskScore,
+		LastSeenAt:   u.LastSeenAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
+	if response.Status == AccountStatusSuspended || response.Status == AccountStatusBanned {
+		response.SuspendedAt = u.SuspendedAt
+		response.SuspendedUntil = u.SuspendedUntil
+		response.SuspensionReason = u.SuspensionReason
+	}
+	if u.SpamLimited(time.Now()) {
+		response.SpamLimitedUntil = u.SpamLimitedUntil
+	}
+	return response
+}
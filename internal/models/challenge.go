@@ -0,0 +1,134 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ChallengeAction define qual atividade do usuário conta para o desafio
+type ChallengeAction string
+
+const (
+	ChallengeActionPost      ChallengeAction = "post"
+	ChallengeActionItinerary ChallengeAction = "itinerary"
+	ChallengeActionTravel    ChallengeAction = "travel"
+	ChallengeActionCheckIn   ChallengeAction = "check_in"
+)
+
+// Challenge representa um desafio/campanha sazonal da plataforma
+// (ex.: "publique 5 viagens de praia neste verão")
+type Challenge struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Title       string            `json:"title" gorm:"not null;size:200"`
+	Description string            `json:"description" gorm:"type:text"`
+	Action      ChallengeAction   `json:"action" gorm:"size:20;not null;index"`
+	Category    ItineraryCategory `json:"category" gorm:"size:20"` // categoria de roteiro exigida (opcional)
+	Keyword     string            `json:"keyword" gorm:"size:100"` // termo exigido no conteúdo (opcional)
+	TargetCount int               `json:"target_count" gorm:"not null"`
+	StartsAt    time.Time         `json:"starts_at" gorm:"index"`
+	EndsAt      time.Time         `json:"ends_at" gorm:"index"`
+	BadgeName   string            `json:"badge_name" gorm:"size:100"`
+	BadgeImage  string            `json:"badge_image"`
+	IsActive    bool              `json:"is_active" gorm:"default:true"`
+	CreatedByID uint              `json:"created_by_id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"-" gorm:"index"`
+
+	ParticipantsCount int `json:"participants_count" gorm:"default:0"`
+}
+
+// ChallengeEnrollment representa a participação de um usuário em um desafio
+type ChallengeEnrollment struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	ChallengeID uint       `json:"challenge_id" gorm:"not null;uniqueIndex:idx_challenge_user"`
+	UserID      uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_challenge_user"`
+	Progress    int        `json:"progress" gorm:"default:0"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	Challenge Challenge `json:"challenge,omitempty" gorm:"foreignKey:ChallengeID"`
+	User      User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// ChallengeContribution registra cada atividade que já contou para o
+// progresso, evitando contar o mesmo conteúdo duas vezes
+type ChallengeContribution struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	ChallengeID uint            `json:"challenge_id" gorm:"not null;uniqueIndex:idx_challenge_contribution"`
+	UserID      uint            `json:"user_id" gorm:"not null;uniqueIndex:idx_challenge_contribution"`
+	Action      ChallengeAction `json:"action" gorm:"size:20;not null;uniqueIndex:idx_challenge_contribution"`
+	EntityID    uint            `json:"entity_id" gorm:"not null;uniqueIndex:idx_challenge_contribution"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// UserBadge é a insígnia concedida ao concluir um desafio
+type UserBadge struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_badge"`
+	ChallengeID uint      `json:"challenge_id" gorm:"not null;uniqueIndex:idx_user_badge"`
+	Name        string    `json:"name" gorm:"size:100"`
+	Image       string    `json:"image"`
+	AwardedAt   time.Time `json:"awarded_at"`
+}
+
+type ChallengeResponse struct {
+	ID                uint              `json:"id"`
+	Title             string            `json:"title"`
+	Description       string            `json:"description"`
+	Action            ChallengeAction   `json:"action"`
+	Category          ItineraryCategory `json:"category,omitempty"`
+	Keyword           string            `json:"keyword,omitempty"`
+	TargetCount       int               `json:"target_count"`
+	StartsAt          time.Time         `json:"starts_at"`
+	EndsAt            time.Time         `json:"ends_at"`
+	BadgeName         string            `json:"badge_name"`
+	BadgeImage        string            `json:"badge_image"`
+	IsActive          bool              `json:"is_active"`
+	ParticipantsCount int               `json:"participants_count"`
+	IsEnrolled        bool              `json:"is_enrolled"`
+	Progress          int               `json:"progress"`
+	CompletedAt       *time.Time        `json:"completed_at,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+}
+
+type ChallengeLeaderboardEntry struct {
+	Position    int           `json:"position"`
+	User        *UserResponse `json:"user"`
+	Progress    int           `json:"progress"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+}
+
+func (ch *Challenge) ToResponse(enrollment *ChallengeEnrollment) *ChallengeResponse {
+	response := &ChallengeResponse{
+		ID:                ch.ID,
+		Title:             ch.Title,
+		Description:       ch.Description,
+		Action:            ch.Action,
+		Category:          ch.Category,
+		Keyword:           ch.Keyword,
+		TargetCount:       ch.TargetCount,
+		StartsAt:          ch.StartsAt,
+		EndsAt:            ch.EndsAt,
+		BadgeName:         ch.BadgeName,
+		BadgeImage:        ch.BadgeImage,
+		IsActive:          ch.IsActive,
+		ParticipantsCount: ch.ParticipantsCount,
+		CreatedAt:         ch.CreatedAt,
+	}
+
+	if enrollment != nil {
+		response.IsEnrolled = true
+		response.Progress = enrollment.Progress
+		response.CompletedAt = enrollment.CompletedAt
+	}
+
+	return response
+}
+
+// IsRunning indica se o desafio está ativo e dentro do período de vigência
+func (ch *Challenge) IsRunning(now time.Time) bool {
+	return ch.IsActive && !now.Before(ch.StartsAt) && now.Before(ch.EndsAt)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+type ClientPlatform string
+
+const (
+	ClientPlatformIOS     ClientPlatform = "ios"
+	ClientPlatformAndroid ClientPlatform = "android"
+	ClientPlatformWeb     ClientPlatform = "web"
+)
+
+type ClientErrorLevel string
+
+const (
+	ClientErrorCrash   ClientErrorLevel = "crash"
+	ClientErrorError   ClientErrorLevel = "error"
+	ClientErrorWarning ClientErrorLevel = "warning"
+)
+
+// ClientErrorReport é um erro ou crash enviado pelos apps. Weight é o inverso
+// da taxa de amostragem aplicada (cliente e servidor), usado para estimar o
+// total real de ocorrências; Fingerprint agrupa relatos do mesmo problema
+type ClientErrorReport struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	UserID      *uint            `json:"user_id" gorm:"index"`
+	Platform    ClientPlatform   `json:"platform" gorm:"size:10;not null"`
+	AppVersion  string           `json:"app_version" gorm:"size:50;not null;index"`
+	OSVersion   string           `json:"os_version" gorm:"size:50"`
+	DeviceModel string           `json:"device_model" gorm:"size:100"`
+	Locale      string           `json:"locale" gorm:"size:10"`
+	Level       ClientErrorLevel `json:"level" gorm:"size:10;not null"`
+	ErrorType   string           `json:"error_type" gorm:"size:200"`
+	Message     string           `json:"message" gorm:"size:1000;not null"`
+	StackTrace  string           `json:"stack_trace" gorm:"type:text"`
+	Fingerprint string           `json:"fingerprint" gorm:"size:64;not null;index"`
+	APIEndpoint string           `json:"api_endpoint" gorm:"size:255;index"` // chamada à API que falhou, se houver
+	APIStatus   int              `json:"api_status"`
+	TraceID     string           `json:"trace_id" gorm:"size:64"` // X-Request-ID da resposta com erro
+	Weight      float64          `json:"weight" gorm:"default:1"`
+	OccurredAt  time.Time        `json:"occurred_at"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"index"`
+}
+
+// ClientErrorGroup agrega os relatos com o mesmo fingerprint
+type ClientErrorGroup struct {
+	Fingerprint    string         `json:"fingerprint"`
+	Platform       ClientPlatform `json:"platform"`
+	Level          string         `json:"level"`
+	ErrorType      string         `json:"error_type"`
+	Message        string         `json:"message"`
+	Reports        int64          `json:"reports"`
+	EstimatedCount float64        `json:"estimated_count"`
+	Users          int64          `json:"users"`
+	AppVersions    int64          `json:"app_versions"`
+	LatestVersion  string         `json:"latest_version"`
+	FirstSeen      time.Time      `json:"first_seen"`
+	LastSeen       time.Time      `json:"last_seen"`
+}
+
+// ClientEndpointErrors agrega os erros dos apps pela chamada à API que os
+// causou, para relacionar problemas nos clientes a mudanças na API
+type ClientEndpointErrors struct {
+	APIEndpoint    string    `json:"api_endpoint"`
+	APIStatus      int       `json:"api_status"`
+	Reports        int64     `json:"reports"`
+	EstimatedCount float64   `json:"estimated_count"`
+	Users          int64     `json:"users"`
+	AppVersions    int64     `json:"app_versions"`
+	FirstSeen      time.Time `json:"first_seen"`
+	LastSeen       time.Time `json:"last_seen"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// Collection é uma pasta de roteiros salvos pelo usuário ("Eurotrip 2025");
+// as coleções são privadas
+type Collection struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Description string    `json:"description" gorm:"size:500"`
+	ItemsCount  int       `json:"items_count" gorm:"default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relacionamentos
+	User  User             `json:"-" gorm:"foreignKey:UserID"`
+	Items []CollectionItem `json:"-" gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE"`
+}
+
+// CollectionItem é um roteiro salvo em uma coleção
+type CollectionItem struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	CollectionID uint      `json:"collection_id" gorm:"not null;uniqueIndex:idx_collection_items_collection_itinerary"`
+	ItineraryID  uint      `json:"itinerary_id" gorm:"not null;uniqueIndex:idx_collection_items_collection_itinerary;index"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relacionamentos
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+}
+
+type CollectionResponse struct {
+	ID          uint                `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	ItemsCount  int                 `json:"items_count"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Itineraries []ItineraryResponse `json:"itineraries,omitempty"`
+}
+
+func (c *Collection) ToResponse() *CollectionResponse {
+	return &CollectionResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		ItemsCount:  c.ItemsCount,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
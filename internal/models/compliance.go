@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+type RestrictedContentType string
+
+const (
+	RestrictedContentPost      RestrictedContentType = "post"
+	RestrictedContentItinerary RestrictedContentType = "itinerary"
+)
+
+// ContentRestriction bloqueia um conteúdo em um país (código ISO 3166-1
+// alfa-2) por exigência legal local
+type ContentRestriction struct {
+	ID          uint                  `json:"id" gorm:"primaryKey"`
+	ContentType RestrictedContentType `json:"content_type" gorm:"size:20;not null;uniqueIndex:idx_content_restrictions_content_country"`
+	ContentID   uint                  `json:"content_id" gorm:"not null;uniqueIndex:idx_content_restrictions_content_country"`
+	CountryCode string                `json:"country_code" gorm:"size:2;not null;uniqueIndex:idx_content_restrictions_content_country;index"`
+	Reason      string                `json:"reason" gorm:"size:500"`
+	CreatedByID uint                  `json:"created_by_id" gorm:"not null"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// ComplianceEnforcement conta, por dia, quantas vezes um conteúdo restrito
+// deixou de ser exibido em um país; é a base dos relatórios de transparência
+type ComplianceEnforcement struct {
+	Date        time.Time             `json:"date" gorm:"type:date;primaryKey"`
+	CountryCode string                `json:"country_code" gorm:"size:2;primaryKey"`
+	ContentType RestrictedContentType `json:"content_type" gorm:"size:20;primaryKey"`
+	ContentID   uint                  `json:"content_id" gorm:"primaryKey;autoIncrement:false"`
+	Count       int                   `json:"count" gorm:"not null;default:0"`
+}
+
+// TransparencyReportEntry resume as restrições aplicadas em um país no período
+type TransparencyReportEntry struct {
+	CountryCode      string                `json:"country_code"`
+	ContentType      RestrictedContentType `json:"content_type"`
+	ContentsWithheld int64                 `json:"contents_withheld"` // conteúdos distintos bloqueados
+	Enforcements     int64                 `json:"enforcements"`      // exibições bloqueadas
+}
+
+type TransparencyReport struct {
+	From    time.Time                 `json:"from"`
+	To      time.Time                 `json:"to"`
+	Entries []TransparencyReportEntry `json:"entries"`
+}
@@ -0,0 +1,226 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ConversationType string
+
+const (
+	ConversationTypeDirect ConversationType = "direct"
+	// Grupo da viagem: ligado a um roteiro colaborativo, com o autor e os
+	// participantes como membros
+	ConversationTypeTrip ConversationType = "trip"
+)
+
+type MessageKind string
+
+const (
+	MessageKindText MessageKind = "text"
+	// Aviso gerado pelo sistema (entrada na viagem, roteiro alterado); o
+	// remetente é quem causou a mudança
+	MessageKindSystem MessageKind = "system"
+)
+
+// MessageStatus é a confirmação de uma mensagem entre os outros
+// participantes da conversa
+type MessageStatus string
+
+const (
+	MessageStatusSent      MessageStatus = "sent"
+	MessageStatusDelivered MessageStatus = "delivered" // todos receberam
+	MessageStatusRead      MessageStatus = "read"      // todos leram
+)
+
+// Conversation representa uma conversa privada entre usuários ou o grupo
+// de uma viagem
+type Conversation struct {
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	Type          ConversationType `json:"type" gorm:"size:20;default:'direct'"`
+	DirectKey     *string          `json:"-" gorm:"uniqueIndex;size:50"` // "menorID:maiorID" para conversas diretas
+	ItineraryID   *uint            `json:"itinerary_id,omitempty" gorm:"uniqueIndex"`
+	Title         string           `json:"title,omitempty" gorm:"size:100"`
+	LastMessageAt *time.Time       `json:"last_message_at" gorm:"index"`
+	// Conversa direta congelada por um bloqueio entre os participantes; não
+	// aceita novas mensagens até o bloqueio ser desfeito
+	FrozenAt  *time.Time     `json:"frozen_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relacionamentos
+	Participants []ConversationParticipant `json:"participants,omitempty" gorm:"foreignKey:ConversationID"`
+	LastMessage  *Message                  `json:"last_message,omitempty" gorm:"-"`
+	UnreadCount  int64                     `json:"unread_count" gorm:"-"`
+}
+
+type ConversationParticipant struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ConversationID uint      `json:"conversation_id" gorm:"not null;uniqueIndex:idx_conversation_participant"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_conversation_participant;index"`
+	JoinedAt       time.Time `json:"joined_at"`
+	// Maiores ids de mensagem que o participante recebeu e leu. Como os ids
+	// crescem, cada um confirma também todas as mensagens anteriores
+	LastDeliveredMessageID uint       `json:"last_delivered_message_id" gorm:"default:0"`
+	LastReadMessageID      uint       `json:"last_read_message_id" gorm:"default:0"`
+	LastReadAt             *time.Time `json:"last_read_at"`
+
+	User User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+type Message struct {
+	ID             uint        `json:"id" gorm:"primaryKey"`
+	ConversationID uint        `json:"conversation_id" gorm:"not null;index"`
+	SenderID       uint        `json:"sender_id" gorm:"not null"`
+	Kind           MessageKind `json:"kind" gorm:"size:20;default:'text'"`
+	Content        string      `json:"content" gorm:"type:text;not null"`
+	// Foto ou vídeo anexado, uma mídia privada de quem enviou
+	MediaID   *uint          `json:"media_id,omitempty" gorm:"index"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	Sender User   `json:"sender" gorm:"foreignKey:SenderID"`
+	Media  *Media `json:"-" gorm:"foreignKey:MediaID"`
+}
+
+type ConversationResponse struct {
+	ID            uint             `json:"id"`
+	Type          ConversationType `json:"type"`
+	ItineraryID   *uint            `json:"itinerary_id,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	Participants  []UserResponse   `json:"participants"`
+	LastMessage   *MessageResponse `json:"last_message,omitempty"`
+	UnreadCount   int64            `json:"unread_count"`
+	Frozen        bool             `json:"frozen"`
+	LastMessageAt *time.Time       `json:"last_message_at"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type MessageResponse struct {
+	ID             uint          `json:"id"`
+	ConversationID uint          `json:"conversation_id"`
+	SenderID       uint          `json:"sender_id"`
+	Sender         *UserResponse `json:"sender,omitempty"`
+	Kind           MessageKind   `json:"kind"`
+	Content        string        `json:"content"`
+	// Os links do anexo são assinados para quem lista as mensagens e expiram
+	Attachment *MessageAttachment `json:"attachment,omitempty"`
+	Status     MessageStatus      `json:"status,omitempty"`
+	ReadBy     []uint             `json:"read_by,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// MessageAttachment é a mídia anexada a uma mensagem, com links assinados
+// para o original (url) e as variantes
+type MessageAttachment struct {
+	MediaID   uint              `json:"media_id"`
+	MediaType MediaType         `json:"media_type"`
+	MimeType  string            `json:"mime_type"`
+	Width     int               `json:"width,omitempty"`
+	Height    int               `json:"height,omitempty"`
+	URL       string            `json:"url"`
+	Variants  map[string]string `json:"variants,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type ConversationReadResponse struct {
+	ConversationID    uint  `json:"conversation_id"`
+	LastReadMessageID uint  `json:"last_read_message_id"`
+	UnreadCount       int64 `json:"unread_count"`
+}
+
+func (c *Conversation) ToResponse() *ConversationResponse {
+	response := &ConversationResponse{
+		ID:            c.ID,
+		Type:          c.Type,
+		ItineraryID:   c.ItineraryID,
+		Title:         c.Title,
+		UnreadCount:   c.UnreadCount,
+		Frozen:        c.FrozenAt != nil,
+		LastMessageAt: c.LastMessageAt,
+		CreatedAt:     c.CreatedAt,
+	}
+
+	for _, participant := range c.Participants {
+		response.Participants = append(response.Participants, *participant.User.ToResponse())
+	}
+
+	if c.LastMessage != nil {
+		response.LastMessage = c.ReceiptResponse(c.LastMessage)
+	}
+
+	return response
+}
+
+// ReceiptResponse converte a mensagem com as confirmações dos outros
+// participantes; avisos do sistema não têm confirmação
+func (c *Conversation) ReceiptResponse(message *Message) *MessageResponse {
+	response := message.ToResponse()
+	if message.Kind == MessageKindSystem {
+		return response
+	}
+
+	response.Status = MessageStatusSent
+	others, delivered, read := 0, 0, 0
+	for _, participant := range c.Participants {
+		if participant.UserID == message.SenderID {
+			continue
+		}
+		others++
+		if participant.LastDeliveredMessageID >= message.ID {
+			delivered++
+		}
+		if participant.LastReadMessageID >= message.ID {
+			read++
+			response.ReadBy = append(response.ReadBy, participant.UserID)
+		}
+	}
+
+	switch {
+	case others == 0:
+	case read == others:
+		response.Status = MessageStatusRead
+	case delivered == others:
+		response.Status = MessageStatusDelivered
+	}
+	return response
+}
+
+// Participant retorna o registro do usuário na conversa
+func (c *Conversation) Participant(userID uint) *ConversationParticipant {
+	for i := range c.Participants {
+		if c.Participants[i].UserID == userID {
+			return &c.Participants[i]
+		}
+	}
+	return nil
+}
+
+// HasParticipant indica se o usuário faz parte da conversa
+func (c *Conversation) HasParticipant(userID uint) bool {
+	for _, participant := range c.Participants {
+		if participant.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Message) ToResponse() *MessageResponse {
+	response := &MessageResponse{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		SenderID:       m.SenderID,
+		Kind:           m.Kind,
+		Content:        m.Content,
+		CreatedAt:      m.CreatedAt,
+	}
+
+	if m.Sender.ID != 0 {
+		response.Sender = m.Sender.ToResponse()
+	}
+
+	return response
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// ExchangeRate guarda quantas unidades da moeda valem 1 USD, a base comum
+// usada para comparar custos informados em moedas diferentes
+type ExchangeRate struct {
+	Currency  string    `json:"currency" gorm:"primaryKey;size:3"`
+	Rate      float64   `json:"rate" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PromotionKind string
+
+const (
+	PromotionKindItinerary    PromotionKind = "itinerary"
+	PromotionKindAnnouncement PromotionKind = "announcement"
+)
+
+type PromotionStatus string
+
+const (
+	PromotionStatusScheduled PromotionStatus = "scheduled"
+	PromotionStatusActive    PromotionStatus = "active"
+	PromotionStatusEnded     PromotionStatus = "ended"
+)
+
+// PromotionPaymentStatus acompanha a compra das promoções criadas por
+// parceiros; as criadas por admins não são cobradas
+type PromotionPaymentStatus string
+
+const (
+	PromotionPaymentNone    PromotionPaymentStatus = "none"   // criada por admin, sem cobrança
+	PromotionPaymentUnpaid  PromotionPaymentStatus = "unpaid" // aguardando o parceiro iniciar o pagamento
+	PromotionPaymentPending PromotionPaymentStatus = "pending"
+	PromotionPaymentReview  PromotionPaymentStatus = "review" // pagamento autorizado aguardando revisão antifraude
+	PromotionPaymentPaid    PromotionPaymentStatus = "paid"
+	PromotionPaymentFailed  PromotionPaymentStatus = "failed"
+)
+
+// DestinationPartner autoriza uma conta de empresa (ex.: órgão de turismo) a
+// promover conteúdo na página de um destino
+type DestinationPartner struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_destination_partners_user_city"`
+	CityID    uint      `json:"city_id" gorm:"not null;uniqueIndex:idx_destination_partners_user_city"`
+	GrantedBy uint      `json:"granted_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relacionamentos
+	User User    `json:"-" gorm:"foreignKey:UserID"`
+	City GeoCity `json:"-" gorm:"foreignKey:CityID"`
+}
+
+// DestinationPromotion fixa um roteiro ou aviso patrocinado na página de um
+// destino durante o período [StartsAt, EndsAt). Promoções de parceiros só são
+// exibidas depois de pagas; os valores ficam em centavos
+type DestinationPromotion struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	CityID      uint          `json:"city_id" gorm:"not null;index"`
+	CreatorID   uint          `json:"creator_id" gorm:"not null;index"`
+	Kind        PromotionKind `json:"kind" gorm:"size:20;not null"`
+	ItineraryID *uint         `json:"itinerary_id"`
+	Title       string        `json:"title" gorm:"size:200;not null"`
+	Body        string        `json:"body" gorm:"size:1000"`
+	ImageURL    string        `json:"image_url" gorm:"size:500"`
+	LinkURL     string        `json:"link_url" gorm:"size:500"`
+	SponsorName string        `json:"sponsor_name" gorm:"size:100;not null"`
+	Position    int           `json:"position" gorm:"default:0"` // menor aparece primeiro
+	StartsAt    time.Time     `json:"starts_at" gorm:"not null;index"`
+	EndsAt      time.Time     `json:"ends_at" gorm:"not null;index"`
+	Impressions int64         `json:"impressions" gorm:"default:0"`
+
+	PaymentStatus     PromotionPaymentStatus `json:"payment_status" gorm:"size:20;default:'none';index"`
+	Amount            int64                  `json:"amount"`
+	Currency          string                 `json:"currency" gorm:"size:3"`
+	Provider          string                 `json:"provider" gorm:"size:20"`
+	ProviderPaymentID string                 `json:"provider_payment_id" gorm:"size:100;index"`
+	FraudCheckID      *uint                  `json:"fraud_check_id"`
+	PaidAt            *time.Time             `json:"paid_at"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relacionamentos
+	Itinerary *Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+}
+
+// PromotionImpression conta as exibições diárias de uma promoção
+type PromotionImpression struct {
+	PromotionID uint      `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Date        time.Time `json:"date" gorm:"type:date;primaryKey"`
+	Impressions int64     `json:"impressions" gorm:"not null;default:0"`
+}
+
+// DestinationPromotionResponse sempre traz a identificação do patrocínio, que
+// os apps devem exibir junto do conteúdo
+type DestinationPromotionResponse struct {
+	ID           uint               `json:"id"`
+	CityID       uint               `json:"city_id"`
+	Kind         PromotionKind      `json:"kind"`
+	Title        string             `json:"title"`
+	Body         string             `json:"body,omitempty"`
+	ImageURL     string             `json:"image_url,omitempty"`
+	LinkURL      string             `json:"link_url,omitempty"`
+	Itinerary    *ItineraryResponse `json:"itinerary,omitempty"`
+	Sponsored    bool               `json:"sponsored"`
+	SponsorName  string             `json:"sponsor_name"`
+	SponsorLabel string             `json:"sponsor_label"`
+	Position     int                `json:"position"`
+	StartsAt     time.Time          `json:"starts_at"`
+	EndsAt       time.Time          `json:"ends_at"`
+	Status       PromotionStatus    `json:"status"`
+	Impressions  *int64             `json:"impressions,omitempty"` // apenas para quem gerencia a promoção
+
+	// Pagamento, apenas para quem gerencia a promoção
+	PaymentStatus PromotionPaymentStatus `json:"payment_status,omitempty"`
+	Amount        int64                  `json:"amount,omitempty"`
+	Currency      string                 `json:"currency,omitempty"`
+	PaidAt        *time.Time             `json:"paid_at,omitempty"`
+}
+
+// PromotionCheckoutResponse devolve a promoção junto com o client secret usado
+// pelo app para concluir o pagamento no SDK do provedor
+type PromotionCheckoutResponse struct {
+	Promotion    *DestinationPromotionResponse `json:"promotion"`
+	ClientSecret string                        `json:"client_secret"`
+}
+
+func (p *DestinationPromotion) StatusAt(now time.Time) PromotionStatus {
+	switch {
+	case now.Before(p.StartsAt):
+		return PromotionStatusScheduled
+	case now.Before(p.EndsAt):
+		return PromotionStatusActive
+	default:
+		return PromotionStatusEnded
+	}
+}
+
+func (p *DestinationPromotion) ToResponse() *DestinationPromotionResponse {
+	response := &DestinationPromotionResponse{
+		ID:           p.ID,
+		CityID:       p.CityID,
+		Kind:         p.Kind,
+		Title:        p.Title,
+		Body:         p.Body,
+		ImageURL:     p.ImageURL,
+		LinkURL:      p.LinkURL,
+		Sponsored:    true,
+		SponsorName:  p.SponsorName,
+		SponsorLabel: "Patrocinado por " + p.SponsorName,
+		Position:     p.Position,
+		StartsAt:     p.StartsAt,
+		EndsAt:       p.EndsAt,
+		Status:       p.StatusAt(time.Now()),
+	}
+
+	if p.Itinerary != nil && p.Itinerary.ID != 0 {
+		response.Itinerary = p.Itinerary.ToResponse()
+	}
+
+	return response
+}
+
+// ToManagedResponse inclui as exibições e o pagamento, visíveis só para quem
+// gerencia a promoção
+func (p *DestinationPromotion) ToManagedResponse() *DestinationPromotionResponse {
+	response := p.ToResponse()
+	response.Impressions = &p.Impressions
+	response.PaymentStatus = p.PaymentStatus
+	response.Amount = p.Amount
+	response.Currency = p.Currency
+	response.PaidAt = p.PaidAt
+	return response
+}
+
+// DestinationPage reúne as promoções ativas e os roteiros populares de uma
+// cidade
+type DestinationPage struct {
+	City        *GeoCity                       `json:"city"`
+	Promotions  []DestinationPromotionResponse `json:"promotions"`
+	Itineraries []ItineraryResponse            `json:"itineraries"`
+}
+
+// PromotionReport resume as exibições de uma promoção
+type PromotionReport struct {
+	Promotion   *DestinationPromotionResponse `json:"promotion"`
+	Impressions int64                         `json:"impressions"`
+	Daily       []PromotionImpression         `json:"daily"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+type EmailTemplate string
+
+const (
+	EmailTemplateVerification   EmailTemplate = "verification"
+	EmailTemplatePasswordReset  EmailTemplate = "password_reset"
+	EmailTemplateTripInvitation EmailTemplate = "trip_invitation"
+	EmailTemplateWeeklyDigest   EmailTemplate = "weekly_digest"
+	EmailTemplateNotification   EmailTemplate = "notification"
+)
+
+type EmailStatus string
+
+const (
+	EmailStatusPending    EmailStatus = "pending"
+	EmailStatusSending    EmailStatus = "sending"
+	EmailStatusSent       EmailStatus = "sent"
+	EmailStatusFailed     EmailStatus = "failed" // aguardando nova tentativa
+	EmailStatusDeadLetter EmailStatus = "dead_letter"
+)
+
+// EmailMessage é um e-mail transacional já renderizado, na fila de envio.
+// Key, quando informada, evita que o mesmo e-mail seja enfileirado duas vezes
+// para o destinatário (ex.: o resumo de uma semana)
+type EmailMessage struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	UserID        *uint         `json:"user_id" gorm:"index"`
+	To            string        `json:"to" gorm:"size:255;not null;uniqueIndex:idx_email_messages_to_key"`
+	Template      EmailTemplate `json:"template" gorm:"size:30;not null;index"`
+	Subject       string        `json:"subject" gorm:"size:255;not null"`
+	HTMLBody      string        `json:"-" gorm:"type:text"`
+	TextBody      string        `json:"-" gorm:"type:text"`
+	Key           *string       `json:"-" gorm:"size:100;uniqueIndex:idx_email_messages_to_key"`
+	Status        EmailStatus   `json:"status" gorm:"size:20;default:'pending';index"`
+	Attempts      int           `json:"attempts" gorm:"default:0"`
+	LastError     string        `json:"last_error,omitempty" gorm:"size:500"`
+	NextAttemptAt *time.Time    `json:"next_attempt_at,omitempty" gorm:"index"`
+	SentAt        *time.Time    `json:"sent_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
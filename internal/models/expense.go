@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+)
+
+// CollaboratorRole define o que o participante pode fazer no roteiro
+type CollaboratorRole string
+
+const (
+	CollaboratorRoleCollaborator CollaboratorRole = "collaborator" // registra e divide despesas
+	CollaboratorRoleViewer       CollaboratorRole = "viewer"       // só acompanha o roteiro, mesmo privado
+	// O autor não é gravado como participante; o papel só aparece na lista
+	// de participantes da viagem
+	CollaboratorRoleAuthor CollaboratorRole = "author"
+)
+
+// ItineraryCollaborator é um companheiro de viagem do autor. Colaboradores
+// registram e dividem despesas do roteiro; espectadores só podem vê-lo
+type ItineraryCollaborator struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	ItineraryID uint             `json:"itinerary_id" gorm:"not null;uniqueIndex:idx_itinerary_collaborators_itinerary_user"`
+	UserID      uint             `json:"user_id" gorm:"not null;uniqueIndex:idx_itinerary_collaborators_itinerary_user;index"`
+	Role        CollaboratorRole `json:"role" gorm:"size:20;not null;default:collaborator"`
+	CreatedAt   time.Time        `json:"created_at"`
+
+	// Relacionamentos
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type ExpenseCategory string
+
+const (
+	ExpenseCategoryLodging    ExpenseCategory = "lodging"
+	ExpenseCategoryFood       ExpenseCategory = "food"
+	ExpenseCategoryTransport  ExpenseCategory = "transport"
+	ExpenseCategoryActivities ExpenseCategory = "activities"
+	ExpenseCategoryShopping   ExpenseCategory = "shopping"
+	ExpenseCategoryOther      ExpenseCategory = "other"
+)
+
+// Expense é um gasto real da viagem, pago por um participante e dividido
+// igualmente entre os usuários de SplitWith
+type Expense struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	ItineraryID uint            `json:"itinerary_id" gorm:"not null;index"`
+	DayID       *uint           `json:"day_id"`
+	PayerID     uint            `json:"payer_id" gorm:"not null;index"`
+	CreatedByID uint            `json:"created_by_id" gorm:"not null"`
+	Description string          `json:"description" gorm:"size:200;not null"`
+	Amount      float64         `json:"amount" gorm:"not null"`
+	Currency    string          `json:"currency" gorm:"size:3;not null"`
+	Category    ExpenseCategory `json:"category" gorm:"size:20;not null;index"`
+	SplitWith   []uint          `json:"split_with" gorm:"serializer:json"`
+	SpentAt     time.Time       `json:"spent_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+
+	// Relacionamentos
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+	Payer     User      `json:"-" gorm:"foreignKey:PayerID"`
+}
+
+type ExpenseResponse struct {
+	ID          uint            `json:"id"`
+	ItineraryID uint            `json:"itinerary_id"`
+	DayID       *uint           `json:"day_id"`
+	CreatedByID uint            `json:"created_by_id"`
+	Description string          `json:"description"`
+	Amount      float64         `json:"amount"`
+	Currency    string          `json:"currency"`
+	Category    ExpenseCategory `json:"category"`
+	SplitWith   []uint          `json:"split_with"`
+	SpentAt     time.Time       `json:"spent_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Payer       *UserResponse   `json:"payer,omitempty"`
+}
+
+func (e *Expense) ToResponse() *ExpenseResponse {
+	response := &ExpenseResponse{
+		ID:          e.ID,
+		ItineraryID: e.ItineraryID,
+		DayID:       e.DayID,
+		CreatedByID: e.CreatedByID,
+		Description: e.Description,
+		Amount:      e.Amount,
+		Currency:    e.Currency,
+		Category:    e.Category,
+		SplitWith:   e.SplitWith,
+		SpentAt:     e.SpentAt,
+		CreatedAt:   e.CreatedAt,
+	}
+
+	if e.Payer.ID != 0 {
+		response.Payer = e.Payer.ToResponse()
+	}
+
+	return response
+}
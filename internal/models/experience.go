@@ -0,0 +1,201 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Experience é uma experiência reservável publicada por um guia/empresa
+// verificado (passeio, aula, degustação...)
+type Experience struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	GuideID         uint           `json:"guide_id" gorm:"not null;index"`
+	Title           string         `json:"title" gorm:"not null;size:200"`
+	Description     string         `json:"description" gorm:"type:text"`
+	Country         string         `json:"country" gorm:"not null;size:100"`
+	City            string         `json:"city" gorm:"size:100"`
+	CountryCode     string         `json:"country_code" gorm:"size:2;index"`
+	CityID          *uint          `json:"city_id" gorm:"index"`
+	MeetingPoint    string         `json:"meeting_point" gorm:"size:300"`
+	Latitude        *float64       `json:"latitude"`
+	Longitude       *float64       `json:"longitude"`
+	GooglePlaceID   string         `json:"google_place_id" gorm:"size:100"`
+	Price           float64        `json:"price" gorm:"not null"`
+	Currency        string         `json:"currency" gorm:"size:3;default:'BRL'"`
+	DurationMinutes int            `json:"duration_minutes"`
+	Capacity        int            `json:"capacity" gorm:"not null"` // vagas padrão por horário
+	Languages       []string       `json:"languages" gorm:"serializer:json"`
+	Images          []string       `json:"images" gorm:"serializer:json"`
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relacionamentos
+	Guide User `json:"guide" gorm:"foreignKey:GuideID"`
+}
+
+// ExperienceSlot é um horário disponível no calendário da experiência
+type ExperienceSlot struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ExperienceID uint      `json:"experience_id" gorm:"not null;index"`
+	StartsAt     time.Time `json:"starts_at" gorm:"not null;index"`
+	EndsAt       time.Time `json:"ends_at" gorm:"not null"`
+	Capacity     int       `json:"capacity" gorm:"not null"`
+	BookedCount  int       `json:"booked_count" gorm:"default:0"`
+	IsCancelled  bool      `json:"is_cancelled" gorm:"default:false"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type BookingStatus string
+
+const (
+	BookingStatusPending   BookingStatus = "pending"
+	BookingStatusAccepted  BookingStatus = "accepted"
+	BookingStatusDeclined  BookingStatus = "declined"
+	BookingStatusCancelled BookingStatus = "cancelled"
+)
+
+// BookingRequest é o pedido de reserva de um viajante para um horário; o
+// guia aceita ou recusa e a negociação segue na conversa direta
+type BookingRequest struct {
+	ID             uint          `json:"id" gorm:"primaryKey"`
+	ExperienceID   uint          `json:"experience_id" gorm:"not null;index"`
+	SlotID         uint          `json:"slot_id" gorm:"not null;index"`
+	UserID         uint          `json:"user_id" gorm:"not null;index"`
+	GuideID        uint          `json:"guide_id" gorm:"not null;index"`
+	Participants   int           `json:"participants" gorm:"not null"`
+	Message        string        `json:"message" gorm:"type:text"`
+	Status         BookingStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	ConversationID *uint         `json:"conversation_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+
+	// Relacionamentos
+	Experience Experience     `json:"experience" gorm:"foreignKey:ExperienceID"`
+	Slot       ExperienceSlot `json:"slot" gorm:"foreignKey:SlotID"`
+	User       User           `json:"user" gorm:"foreignKey:UserID"`
+}
+
+type ExperienceResponse struct {
+	ID              uint          `json:"id"`
+	GuideID         uint          `json:"guide_id"`
+	Guide           *UserResponse `json:"guide,omitempty"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	Country         string        `json:"country"`
+	City            string        `json:"city"`
+	CountryCode     string        `json:"country_code"`
+	MeetingPoint    string        `json:"meeting_point"`
+	Latitude        *float64      `json:"latitude"`
+	Longitude       *float64      `json:"longitude"`
+	GooglePlaceID   string        `json:"google_place_id"`
+	Price           float64       `json:"price"`
+	Currency        string        `json:"currency"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Capacity        int           `json:"capacity"`
+	Languages       []string      `json:"languages"`
+	Images          []string      `json:"images"`
+	IsActive        bool          `json:"is_active"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+type ExperienceSlotResponse struct {
+	ID          uint      `json:"id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Capacity    int       `json:"capacity"`
+	BookedCount int       `json:"booked_count"`
+	Available   int       `json:"available"`
+	IsCancelled bool      `json:"is_cancelled"`
+}
+
+type BookingRequestResponse struct {
+	ID             uint                    `json:"id"`
+	ExperienceID   uint                    `json:"experience_id"`
+	Experience     *ExperienceResponse     `json:"experience,omitempty"`
+	Slot           *ExperienceSlotResponse `json:"slot,omitempty"`
+	User           *UserResponse           `json:"user,omitempty"`
+	Participants   int                     `json:"participants"`
+	Message        string                  `json:"message"`
+	Status         BookingStatus           `json:"status"`
+	ConversationID *uint                   `json:"conversation_id"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+func (e *Experience) ToResponse() *ExperienceResponse {
+	response := &ExperienceResponse{
+		ID:              e.ID,
+		GuideID:         e.GuideID,
+		Title:           e.Title,
+		Description:     e.Description,
+		Country:         e.Country,
+		City:            e.City,
+		CountryCode:     e.CountryCode,
+		MeetingPoint:    e.MeetingPoint,
+		Latitude:        e.Latitude,
+		Longitude:       e.Longitude,
+		GooglePlaceID:   e.GooglePlaceID,
+		Price:           e.Price,
+		Currency:        e.Currency,
+		DurationMinutes: e.DurationMinutes,
+		Capacity:        e.Capacity,
+		Languages:       e.Languages,
+		Images:          e.Images,
+		IsActive:        e.IsActive,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+
+	if e.Guide.ID != 0 {
+		response.Guide = e.Guide.ToResponse()
+	}
+
+	return response
+}
+
+func (s *ExperienceSlot) ToResponse() *ExperienceSlotResponse {
+	available := s.Capacity - s.BookedCount
+	if available < 0 || s.IsCancelled {
+		available = 0
+	}
+
+	return &ExperienceSlotResponse{
+		ID:          s.ID,
+		StartsAt:    s.StartsAt,
+		EndsAt:      s.EndsAt,
+		Capacity:    s.Capacity,
+		BookedCount: s.BookedCount,
+		Available:   available,
+		IsCancelled: s.IsCancelled,
+	}
+}
+
+func (b *BookingRequest) ToResponse() *BookingRequestResponse {
+	response := &BookingRequestResponse{
+		ID:             b.ID,
+		ExperienceID:   b.ExperienceID,
+		Participants:   b.Participants,
+		Message:        b.Message,
+		Status:         b.Status,
+		ConversationID: b.ConversationID,
+		CreatedAt:      b.CreatedAt,
+		UpdatedAt:      b.UpdatedAt,
+	}
+
+	if b.Experience.ID != 0 {
+		response.Experience = b.Experience.ToResponse()
+	}
+	if b.Slot.ID != 0 {
+		response.Slot = b.Slot.ToResponse()
+	}
+	if b.User.ID != 0 {
+		response.User = b.User.ToResponse()
+	}
+
+	return response
+}
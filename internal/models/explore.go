@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// TrendingDestination é um destino em alta, calculado a partir do engajamento
+// recente dos roteiros públicos
+type TrendingDestination struct {
+	Country          string `json:"country"`
+	City             string `json:"city"`
+	CoverImage       string `json:"cover_image"`
+	ItinerariesCount int    `json:"itineraries_count"`
+	Score            int    `json:"score"`
+}
+
+// ExploreResponse reúne as seções da aba Explorar em uma única resposta
+type ExploreResponse struct {
+	TrendingPosts        []PostResponse        `json:"trending_posts"`
+	FeaturedItineraries  []ItineraryResponse   `json:"featured_itineraries"`
+	TrendingDestinations []TrendingDestination `json:"trending_destinations"`
+	GeneratedAt          time.Time             `json:"generated_at"`
+}
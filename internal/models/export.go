@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatPDF ExportFormat = "pdf"
+	ExportFormatICS ExportFormat = "ics"
+	ExportFormatGPX ExportFormat = "gpx"
+	ExportFormatKML ExportFormat = "kml"
+)
+
+type ExportStatus string
+
+const (
+	ExportStatusPending ExportStatus = "pending"
+	ExportStatusRunning ExportStatus = "running"
+	ExportStatusReady   ExportStatus = "ready"
+	ExportStatusFailed  ExportStatus = "failed"
+)
+
+// ItineraryExport é a geração em segundo plano de um arquivo do roteiro
+// (roteiros grandes); o cliente acompanha o status até o arquivo ficar pronto.
+// Version guarda o UpdatedAt do roteiro, para reaproveitar o arquivo enquanto
+// o roteiro não mudar
+type ItineraryExport struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	ItineraryID uint         `json:"itinerary_id" gorm:"not null;index"`
+	UserID      uint         `json:"user_id" gorm:"not null;index"`
+	Format      ExportFormat `json:"format" gorm:"size:10;not null"`
+	Status      ExportStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	Version     time.Time    `json:"version"`
+	FileURL     string       `json:"file_url,omitempty" gorm:"size:500"`
+	FilePath    string       `json:"-" gorm:"size:500"`
+	FileSize    int64        `json:"file_size,omitempty"`
+	Error       string       `json:"error,omitempty" gorm:"size:500"`
+	StartedAt   *time.Time   `json:"started_at"`
+	FinishedAt  *time.Time   `json:"finished_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+type FeedContentMix string
+
+const (
+	FeedMixBalanced        FeedContentMix = "balanced"
+	FeedMixMoreItineraries FeedContentMix = "more_itineraries" // prioriza posts com roteiro vinculado
+	FeedMixMorePosts       FeedContentMix = "more_posts"       // prioriza posts sem roteiro
+)
+
+// FeedSettings guarda as preferências do usuário para montar o feed inicial.
+// Sem registro salvo valem os padrões do grupo de experimento do usuário.
+type FeedSettings struct {
+	UserID           uint           `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	ContentMix       FeedContentMix `json:"content_mix" gorm:"size:20;not null"`
+	IncludeSuggested bool           `json:"include_suggested"`
+	HideReposts      bool           `json:"hide_reposts"` // oculta posts que compartilham roteiros de outros autores
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type FeedSettingsResponse struct {
+	ContentMix       FeedContentMix `json:"content_mix"`
+	IncludeSuggested bool           `json:"include_suggested"`
+	HideReposts      bool           `json:"hide_reposts"`
+	Bucket           string         `json:"bucket"`     // grupo de experimento que define os padrões
+	Customized       bool           `json:"customized"` // false enquanto valem os padrões do grupo
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"time"
+)
+
+type FraudRuleCode string
+
+const (
+	FraudRulePaymentVelocity FraudRuleCode = "payment_velocity"
+	FraudRuleGeoMismatch     FraudRuleCode = "geo_mismatch"
+	FraudRuleDisposableEmail FraudRuleCode = "disposable_email"
+)
+
+type FraudAction string
+
+const (
+	FraudActionReview FraudAction = "review"
+	FraudActionBlock  FraudAction = "block"
+)
+
+type FraudDecision string
+
+const (
+	FraudDecisionApproved FraudDecision = "approved"
+	FraudDecisionReview   FraudDecision = "review"
+	FraudDecisionBlocked  FraudDecision = "blocked"
+)
+
+type FraudSubject string
+
+const (
+	FraudSubjectTip       FraudSubject = "tip"
+	FraudSubjectPromotion FraudSubject = "promotion"
+)
+
+type FraudReviewStatus string
+
+const (
+	FraudReviewNone     FraudReviewStatus = "none"
+	FraudReviewPending  FraudReviewStatus = "pending"
+	FraudReviewApproved FraudReviewStatus = "approved"
+	FraudReviewRejected FraudReviewStatus = "rejected"
+)
+
+// FraudRule é uma regra antifraude com limites configuráveis pelos admins.
+// Cada regra usa apenas os parâmetros relevantes ao seu código
+type FraudRule struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	Code          FraudRuleCode `json:"code" gorm:"uniqueIndex;size:50;not null"`
+	Description   string        `json:"description" gorm:"size:300"`
+	IsEnabled     bool          `json:"is_enabled" gorm:"default:true"`
+	Action        FraudAction   `json:"action" gorm:"size:20;not null"`
+	MaxCount      int           `json:"max_count"`                      // payment_velocity: tentativas na janela
+	MaxAmount     int64         `json:"max_amount"`                     // payment_velocity: soma em centavos na janela
+	WindowMinutes int           `json:"window_minutes"`                 // payment_velocity
+	Domains       []string      `json:"domains" gorm:"serializer:json"` // disposable_email
+	UpdatedByID   *uint         `json:"updated_by_id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// FraudCheck registra a avaliação de uma tentativa de pagamento e, quando
+// necessário, o resultado da revisão manual
+type FraudCheck struct {
+	ID           uint              `json:"id" gorm:"primaryKey"`
+	Subject      FraudSubject      `json:"subject" gorm:"size:20;not null;index:idx_fraud_checks_user_subject"`
+	SubjectID    uint              `json:"subject_id" gorm:"index"`
+	UserID       uint              `json:"user_id" gorm:"not null;index:idx_fraud_checks_user_subject"`
+	Amount       int64             `json:"amount"`
+	Currency     string            `json:"currency" gorm:"size:3"`
+	IPCountry    string            `json:"ip_country" gorm:"size:2"`
+	Country      string            `json:"country" gorm:"size:2"`
+	Decision     FraudDecision     `json:"decision" gorm:"size:20;not null;index"`
+	Reasons      []string          `json:"reasons" gorm:"serializer:json"`
+	ReviewStatus FraudReviewStatus `json:"review_status" gorm:"size:20;default:'none';index"`
+	ReviewedByID *uint             `json:"reviewed_by_id"`
+	ReviewNote   string            `json:"review_note" gorm:"size:500"`
+	ReviewedAt   *time.Time        `json:"reviewed_at"`
+	CreatedAt    time.Time         `json:"created_at" gorm:"index:idx_fraud_checks_user_subject"`
+
+	// Relacionamentos
+	User User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+// FraudCheckInput reúne os dados da tentativa avaliados pelas regras
+type FraudCheckInput struct {
+	Subject   FraudSubject
+	UserID    uint
+	Email     string
+	Amount    int64
+	Currency  string
+	IPCountry string // país da requisição informado pelo proxy/CDN
+	Country   string // país declarado para a cobrança
+}
+
+type FraudCheckResponse struct {
+	ID           uint              `json:"id"`
+	Subject      FraudSubject      `json:"subject"`
+	SubjectID    uint              `json:"subject_id"`
+	User         *UserResponse     `json:"user,omitempty"`
+	Amount       int64             `json:"amount"`
+	Currency     string            `json:"currency"`
+	IPCountry    string            `json:"ip_country"`
+	Country      string            `json:"country"`
+	Decision     FraudDecision     `json:"decision"`
+	Reasons      []string          `json:"reasons"`
+	ReviewStatus FraudReviewStatus `json:"review_status"`
+	ReviewNote   string            `json:"review_note"`
+	ReviewedAt   *time.Time        `json:"reviewed_at"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+func (f *FraudCheck) ToResponse() *FraudCheckResponse {
+	response := &FraudCheckResponse{
+		ID:           f.ID,
+		Subject:      f.Subject,
+		SubjectID:    f.SubjectID,
+		Amount:       f.Amount,
+		Currency:     f.Currency,
+		IPCountry:    f.IPCountry,
+		Country:      f.Country,
+		Decision:     f.Decision,
+		Reasons:      f.Reasons,
+		ReviewStatus: f.ReviewStatus,
+		ReviewNote:   f.ReviewNote,
+		ReviewedAt:   f.ReviewedAt,
+		CreatedAt:    f.CreatedAt,
+	}
+
+	if f.User.ID != 0 {
+		response.User = f.User.ToResponse()
+	}
+
+	return response
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// GeoCountry representa um país do conjunto de referência (GeoNames)
+type GeoCountry struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	GeoNameID    int       `json:"geoname_id" gorm:"index"`
+	Code         string    `json:"code" gorm:"uniqueIndex;size:2;not null"` // ISO 3166-1 alpha-2
+	Name         string    `json:"name" gorm:"not null;size:100"`
+	NamePT       string    `json:"name_pt" gorm:"size:100"`
+	SearchName   string    `json:"-" gorm:"index;size:100"`
+	Aliases      []string  `json:"aliases,omitempty" gorm:"serializer:json"`
+	CurrencyCode string    `json:"currency_code" gorm:"size:3"`
+	Timezone     string    `json:"timezone" gorm:"size:64"` // fuso principal (IANA)
+	Locale       string    `json:"locale" gorm:"size:10"`   // idioma principal (BCP 47)
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// GeoState representa uma subdivisão de primeiro nível (estado/província)
+type GeoState struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	GeoNameID   int       `json:"geoname_id" gorm:"uniqueIndex"`
+	CountryCode string    `json:"country_code" gorm:"index;size:2;not null"`
+	Code        string    `json:"code" gorm:"size:20"` // código admin1 do GeoNames
+	Name        string    `json:"name" gorm:"not null;size:100"`
+	SearchName  string    `json:"-" gorm:"index;size:100"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GeoCity representa uma cidade do conjunto de referência
+type GeoCity struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	GeoNameID   int       `json:"geoname_id" gorm:"uniqueIndex"`
+	CountryCode string    `json:"country_code" gorm:"index;size:2;not null"`
+	StateCode   string    `json:"state_code" gorm:"size:20"`
+	Name        string    `json:"name" gorm:"not null;size:200"`
+	ASCIIName   string    `json:"ascii_name" gorm:"size:200"`
+	SearchName  string    `json:"-" gorm:"index;size:200"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Population  int64     `json:"population" gorm:"default:0"`
+	Timezone    string    `json:"timezone" gorm:"size:64"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GeoSuggestion é o item retornado pelos endpoints de autocomplete
+type GeoSuggestion struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	CountryCode string   `json:"country_code"`
+	CountryName string   `json:"country_name,omitempty"`
+	StateName   string   `json:"state_name,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusDeclined InvitationStatus = "declined"
+)
+
+// TripInvitation é o convite do autor para alguém participar da viagem do
+// roteiro. Convites por e-mail de quem ainda não tem conta ficam sem
+// InviteeID e aparecem para o usuário que se cadastrar com o mesmo e-mail
+type TripInvitation struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	ItineraryID uint             `json:"itinerary_id" gorm:"not null;index"`
+	InviterID   uint             `json:"inviter_id" gorm:"not null"`
+	InviteeID   *uint            `json:"invitee_id" gorm:"index"`
+	Email       string           `json:"email" gorm:"size:255;index"` // sempre em minúsculas
+	Role        CollaboratorRole `json:"role" gorm:"size:20;not null"`
+	Status      InvitationStatus `json:"status" gorm:"size:20;not null;default:pending;index"`
+	Message     string           `json:"message" gorm:"size:500"`
+	RespondedAt *time.Time       `json:"responded_at"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+
+	// Relacionamentos
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+	Inviter   User      `json:"-" gorm:"foreignKey:InviterID"`
+	Invitee   *User     `json:"-" gorm:"foreignKey:InviteeID"`
+}
+
+type TripInvitationResponse struct {
+	ID          uint               `json:"id"`
+	ItineraryID uint               `json:"itinerary_id"`
+	Itinerary   *ItineraryResponse `json:"itinerary,omitempty"`
+	Inviter     *UserResponse      `json:"inviter,omitempty"`
+	Invitee     *UserResponse      `json:"invitee,omitempty"`
+	Email       string             `json:"email,omitempty"`
+	Role        CollaboratorRole   `json:"role"`
+	Status      InvitationStatus   `json:"status"`
+	Message     string             `json:"message"`
+	RespondedAt *time.Time         `json:"responded_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func (i *TripInvitation) ToResponse() *TripInvitationResponse {
+	response := &TripInvitationResponse{
+		ID:          i.ID,
+		ItineraryID: i.ItineraryID,
+		Email:       i.Email,
+		Role:        i.Role,
+		Status:      i.Status,
+		Message:     i.Message,
+		RespondedAt: i.RespondedAt,
+		CreatedAt:   i.CreatedAt,
+	}
+
+	if i.Itinerary.ID != 0 {
+		response.Itinerary = i.Itinerary.ToResponse()
+	}
+	if i.Inviter.ID != 0 {
+		response.Inviter = i.Inviter.ToResponse()
+	}
+	if i.Invitee != nil && i.Invitee.ID != 0 {
+		response.Invitee = i.Invitee.ToResponse()
+	}
+
+	return response
+}
+
+// TripParticipantResponse é um participante da viagem: o autor ou quem
+// entrou por convite aceito
+type TripParticipantResponse struct {
+	User     *UserResponse    `json:"user"`
+	Role     CollaboratorRole `json:"role"`
+	JoinedAt *time.Time       `json:"joined_at,omitempty"` // vazio para o autor
+}
+
+func (c *ItineraryCollaborator) ToParticipantResponse() *TripParticipantResponse {
+	return &TripParticipantResponse{
+		User:     c.User.ToResponse(),
+		Role:     c.Role,
+		JoinedAt: &c.CreatedAt,
+	}
+}
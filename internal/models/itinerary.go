@@ -36,12 +36,26 @@ type Itinerary struct {
 	Country       string            `json:"country" gorm:"size:100"`
 	City          string            `json:"city" gorm:"size:100"`
 	State         string            `json:"state" gorm:"size:100"`
+	CountryID     *uint             `json:"country_id" gorm:"index"`
+	StateID       *uint             `json:"state_id"`
+	CityID        *uint             `json:"city_id" gorm:"index"`
+	Timezone      string            `json:"timezone" gorm:"size:64"`
+	Locale        string            `json:"locale" gorm:"size:10"`
+	GeoCheckedAt  *time.Time        `json:"-"` // última tentativa do backfill de associar aos dados de referência
 	IsPublic      bool              `json:"is_public" gorm:"default:true"`
 	IsFeatured    bool              `json:"is_featured" gorm:"default:false"`
+	FeaturedOrder *int              `json:"featured_order"` // posição no carrossel de destaques (menor primeiro)
+	FeaturedFrom  *time.Time        `json:"featured_from"`  // janela do destaque; nulo = sem limite
+	FeaturedUntil *time.Time        `json:"featured_until"`
 	ViewsCount    int               `json:"views_count" gorm:"default:0"`
 	LikesCount    int               `json:"likes_count" gorm:"default:0"`
 	RatingsCount  int               `json:"ratings_count" gorm:"default:0"`
 	AverageRating float64           `json:"average_rating" gorm:"default:0"`
+	ClonesCount   int               `json:"clones_count" gorm:"default:0"`
+	ClonedFromID  *uint             `json:"cloned_from_id" gorm:"index"`            // roteiro de origem quando criado por "usar este roteiro"
+	IsTemplate    bool              `json:"is_template" gorm:"default:false;index"` // modelo publicado por empresa
+	ReportsCount  int               `json:"reports_count" gorm:"default:0"`
+	HiddenAt      *time.Time        `json:"hidden_at" gorm:"index"` // retirado das listagens pela moderação após denúncias
 	CreatedAt     time.Time         `json:"created_at"`
 	UpdatedAt     time.Time         `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt    `json:"-" gorm:"index"`
@@ -54,11 +68,13 @@ type Itinerary struct {
 
 type ItineraryDay struct {
 	ID            uint      `json:"id" gorm:"primaryKey"`
-	ItineraryID   uint      `json:"itinerary_id" gorm:"not null"`
+	ItineraryID   uint      `json:"itinerary_id" gorm:"not null;index"`
 	DayNumber     int       `json:"day_number" gorm:"not null"`
 	Title         string    `json:"title" gorm:"size:200"`
 	Description   string    `json:"description" gorm:"type:text"`
 	EstimatedCost *float64  `json:"estimated_cost"`
+	Timezone      string    `json:"timezone" gorm:"size:64"` // vazio = fuso do roteiro
+	Images        []string  `json:"images" gorm:"serializer:json"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
@@ -80,14 +96,14 @@ const (
 
 type ItineraryLocation struct {
 	ID            uint         `json:"id" gorm:"primaryKey"`
-	DayID         uint         `json:"day_id" gorm:"not null"`
+	DayID         uint         `json:"day_id" gorm:"not null;index"`
 	Name          string       `json:"name" gorm:"not null;size:200"`
 	Description   string       `json:"description" gorm:"type:text"`
 	LocationType  LocationType `json:"location_type" gorm:"not null"`
 	Address       string       `json:"address" gorm:"size:300"`
 	Latitude      *float64     `json:"latitude"`
 	Longitude     *float64     `json:"longitude"`
-	GooglePlaceID string       `json:"google_place_id" gorm:"size:100"`
+	GooglePlaceID string       `json:"google_place_id" gorm:"size:100;index"`
 	EstimatedCost *float64     `json:"estimated_cost"`
 	StartTime     *time.Time   `json:"start_time"`
 	EndTime       *time.Time   `json:"end_time"`
@@ -96,6 +112,8 @@ type ItineraryLocation struct {
 	Website       string       `json:"website" gorm:"size:200"`
 	Phone         string       `json:"phone" gorm:"size:20"`
 	Rating        *float64     `json:"rating"`
+	OpeningHours  []string     `json:"opening_hours" gorm:"serializer:json"`
+	EnrichedAt    *time.Time   `json:"enriched_at"` // última atualização pelo provedor de lugares
 	CreatedAt     time.Time    `json:"created_at"`
 	UpdatedAt     time.Time    `json:"updated_at"`
 
@@ -103,44 +121,132 @@ type ItineraryLocation struct {
 	Day ItineraryDay `json:"day" gorm:"foreignKey:DayID"`
 }
 
-type ItineraryRating struct {
+type ItineraryLike struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
-	ItineraryID uint      `json:"itinerary_id" gorm:"not null"`
-	UserID      uint      `json:"user_id" gorm:"not null"`
-	Rating      int       `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
-	Comment     string    `json:"comment" gorm:"type:text"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_itinerary_likes_user_itinerary"`
+	ItineraryID uint      `json:"itinerary_id" gorm:"not null;uniqueIndex:idx_itinerary_likes_user_itinerary;index"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relacionamentos
+	User      User      `json:"user" gorm:"foreignKey:UserID"`
+	Itinerary Itinerary `json:"itinerary" gorm:"foreignKey:ItineraryID"`
+}
+
+type ItineraryRating struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	ItineraryID uint       `json:"itinerary_id" gorm:"not null"`
+	UserID      uint       `json:"user_id" gorm:"not null"`
+	Rating      int        `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
+	Comment     string     `json:"comment" gorm:"type:text"`
+	HiddenAt    *time.Time `json:"hidden_at"` // ocultada pela moderação após denúncias
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Resposta do autor do roteiro (uma por avaliação)
+	Reply     string     `json:"reply" gorm:"type:text"`
+	RepliedAt *time.Time `json:"replied_at"`
+
+	// Votos de utilidade (desnormalizados de itinerary_rating_votes)
+	HelpfulCount   int `json:"helpful_count" gorm:"default:0"`
+	UnhelpfulCount int `json:"unhelpful_count" gorm:"default:0"`
 
 	// Relacionamentos
 	Itinerary Itinerary `json:"itinerary" gorm:"foreignKey:ItineraryID"`
 	User      User      `json:"user" gorm:"foreignKey:UserID"`
 }
 
+// ItineraryRatingVote é o voto de um usuário sobre a utilidade de uma
+// avaliação; cada usuário tem um voto por avaliação
+type ItineraryRatingVote struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	RatingID  uint      `json:"rating_id" gorm:"not null;uniqueIndex:idx_itinerary_rating_votes_rating_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_itinerary_rating_votes_rating_user;index"`
+	Helpful   bool      `json:"helpful"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relacionamentos
+	Rating ItineraryRating `json:"-" gorm:"foreignKey:RatingID"`
+	User   User            `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type ItineraryRatingResponse struct {
+	ID          uint          `json:"id"`
+	ItineraryID uint          `json:"itinerary_id"`
+	Rating      int           `json:"rating"`
+	Comment     string        `json:"comment"`
+	Reply       string        `json:"reply,omitempty"`
+	RepliedAt   *time.Time    `json:"replied_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	User        *UserResponse `json:"user,omitempty"`
+
+	HelpfulCount   int   `json:"helpful_count"`
+	UnhelpfulCount int   `json:"unhelpful_count"`
+	ViewerVote     *bool `json:"viewer_vote"` // voto do usuário atual: true (útil), false (não útil) ou null
+}
+
+func (r *ItineraryRating) ToResponse() *ItineraryRatingResponse {
+	response := &ItineraryRatingResponse{
+		ID:          r.ID,
+		ItineraryID: r.ItineraryID,
+		Rating:      r.Rating,
+		Comment:     r.Comment,
+		Reply:       r.Reply,
+		RepliedAt:   r.RepliedAt,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+
+		HelpfulCount:   r.HelpfulCount,
+		UnhelpfulCount: r.UnhelpfulCount,
+	}
+
+	if r.User.ID != 0 {
+		response.User = r.User.ToResponse()
+	}
+
+	return response
+}
+
 type ItineraryResponse struct {
-	ID            uint              `json:"id"`
-	AuthorID      uint              `json:"author_id"`
-	Title         string            `json:"title"`
-	Description   string            `json:"description"`
-	Category      ItineraryCategory `json:"category"`
-	EstimatedCost *float64          `json:"estimated_cost"`
-	Currency      string            `json:"currency"`
-	Duration      int               `json:"duration"`
-	Difficulty    int               `json:"difficulty"`
-	CoverImage    string            `json:"cover_image"`
-	Images        []string          `json:"images"`
-	Country       string            `json:"country"`
-	City          string            `json:"city"`
-	State         string            `json:"state"`
-	IsFeatured    bool              `json:"is_featured"`
-	ViewsCount    int               `json:"views_count"`
-	LikesCount    int               `json:"likes_count"`
-	RatingsCount  int               `json:"ratings_count"`
-	AverageRating float64           `json:"average_rating"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
-	Author        *UserResponse     `json:"author,omitempty"`
-	Days          []ItineraryDay    `json:"days,omitempty"`
+	ID                 uint              `json:"id"`
+	AuthorID           uint              `json:"author_id"`
+	Title              string            `json:"title"`
+	Description        string            `json:"description"`
+	Category           ItineraryCategory `json:"category"`
+	EstimatedCost      *float64          `json:"estimated_cost"`
+	Currency           string            `json:"currency"`
+	NormalizedCost     *float64          `json:"normalized_cost,omitempty"` // custo na moeda do visitante, quando há cotação
+	NormalizedCurrency string            `json:"normalized_currency,omitempty"`
+	Duration           int               `json:"duration"`
+	Difficulty         int               `json:"difficulty"`
+	CoverImage         string            `json:"cover_image"`
+	Images             []string          `json:"images"`
+	Country            string            `json:"country"`
+	City               string            `json:"city"`
+	State              string            `json:"state"`
+	CountryID          *uint             `json:"country_id"`
+	StateID            *uint             `json:"state_id"`
+	CityID             *uint             `json:"city_id"`
+	Timezone           string            `json:"timezone"`
+	Locale             string            `json:"locale"`
+	IsFeatured         bool              `json:"is_featured"`
+	ViewsCount         int               `json:"views_count"`
+	LikesCount         int               `json:"likes_count"`
+	RatingsCount       int               `json:"ratings_count"`
+	AverageRating      float64           `json:"average_rating"`
+	ClonesCount        int               `json:"clones_count"`
+	ClonedFromID       *uint             `json:"cloned_from_id"`
+	IsTemplate         bool              `json:"is_template"`
+	IsLiked            bool              `json:"is_liked"` // o usuário atual curtiu o roteiro
+	IsSaved            bool              `json:"is_saved"` // está em alguma coleção do usuário atual
+	HiddenAt           *time.Time        `json:"hidden_at,omitempty"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	Author             *UserResponse     `json:"author,omitempty"`
+	Days               []ItineraryDay    `json:"days,omitempty"`
+
+	RecommendationReason RecommendationReason `json:"recommendation_reason,omitempty"` // só em /recommendations
 }
 
 func (i *Itinerary) ToResponse() *ItineraryResponse {
@@ -159,11 +265,20 @@ func (i *Itinerary) ToResponse() *ItineraryResponse {
 		Country:       i.Country,
 		City:          i.City,
 		State:         i.State,
+		CountryID:     i.CountryID,
+		StateID:       i.StateID,
+		CityID:        i.CityID,
+		Timezone:      i.Timezone,
+		Locale:        i.Locale,
 		IsFeatured:    i.IsFeatured,
 		ViewsCount:    i.ViewsCount,
 		LikesCount:    i.LikesCount,
 		RatingsCount:  i.RatingsCount,
 		AverageRating: i.AverageRating,
+		ClonesCount:   i.ClonesCount,
+		ClonedFromID:  i.ClonedFromID,
+		IsTemplate:    i.IsTemplate,
+		HiddenAt:      i.HiddenAt,
 		CreatedAt:     i.CreatedAt,
 		UpdatedAt:     i.UpdatedAt,
 		Days:          i.Days,
@@ -175,3 +290,13 @@ func (i *Itinerary) ToResponse() *ItineraryResponse {
 
 	return response
 }
+
+// PlaceSuggestion é um local de outros roteiros públicos do mesmo destino que
+// pode ser adicionado ao roteiro visualizado ("complete sua viagem")
+type PlaceSuggestion struct {
+	Location          ItineraryLocation `json:"location"`
+	SourceItineraryID uint              `json:"source_itinerary_id"`
+	SourceTitle       string            `json:"source_title"`
+	Occurrences       int               `json:"occurrences"` // em quantos roteiros o local aparece
+	Score             float64           `json:"score"`
+}
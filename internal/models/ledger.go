@@ -0,0 +1,184 @@
+package models
+
+import (
+	"time"
+)
+
+type LedgerAccountType string
+
+const (
+	// Contas da plataforma (OwnerID = 0)
+	LedgerAccountProviderClearing LedgerAccountType = "provider_clearing" // valores em trânsito no provedor de pagamentos
+	LedgerAccountPlatformRevenue  LedgerAccountType = "platform_revenue"  // comissões e vendas da plataforma
+
+	// Contas dos usuários
+	LedgerAccountCreatorAvailable     LedgerAccountType = "creator_available"      // saldo disponível para saque
+	LedgerAccountCreatorPayoutPending LedgerAccountType = "creator_payout_pending" // saldo reservado para saques agendados
+)
+
+type LedgerTransactionKind string
+
+const (
+	LedgerKindTip          LedgerTransactionKind = "tip"
+	LedgerKindRefund       LedgerTransactionKind = "refund"
+	LedgerKindPayout       LedgerTransactionKind = "payout"
+	LedgerKindPayoutPaid   LedgerTransactionKind = "payout_paid"
+	LedgerKindPayoutFailed LedgerTransactionKind = "payout_failed"
+	LedgerKindPromotion    LedgerTransactionKind = "promotion"
+	LedgerKindSubscription LedgerTransactionKind = "subscription"
+)
+
+// LedgerAccount é uma conta do livro-razão por dono e moeda; Balance é a soma
+// dos lançamentos, mantida na mesma transação que os registra
+type LedgerAccount struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	Type      LedgerAccountType `json:"type" gorm:"size:30;not null;uniqueIndex:idx_ledger_account_owner"`
+	OwnerID   uint              `json:"owner_id" gorm:"not null;default:0;uniqueIndex:idx_ledger_account_owner"`
+	Currency  string            `json:"currency" gorm:"size:3;not null;uniqueIndex:idx_ledger_account_owner"`
+	Balance   int64             `json:"balance" gorm:"not null;default:0"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// LedgerTransaction agrupa lançamentos cuja soma é sempre zero (partidas
+// dobradas). Reference identifica a origem (ex.: "tip:42") e torna o registro
+// idempotente
+type LedgerTransaction struct {
+	ID          uint                  `json:"id" gorm:"primaryKey"`
+	Kind        LedgerTransactionKind `json:"kind" gorm:"size:30;not null;index"`
+	Reference   string                `json:"reference" gorm:"size:100;not null;uniqueIndex"`
+	Currency    string                `json:"currency" gorm:"size:3;not null"`
+	Description string                `json:"description" gorm:"size:300"`
+	CreatedAt   time.Time             `json:"created_at" gorm:"index"`
+
+	// Relacionamentos
+	Entries []LedgerEntry `json:"entries" gorm:"foreignKey:TransactionID"`
+}
+
+// LedgerEntry é um lançamento em uma conta; valores positivos aumentam o
+// saldo e negativos o diminuem, em centavos
+type LedgerEntry struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	TransactionID uint      `json:"transaction_id" gorm:"not null;index"`
+	AccountID     uint      `json:"account_id" gorm:"not null;index"`
+	Amount        int64     `json:"amount" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relacionamentos
+	Account     LedgerAccount     `json:"account" gorm:"foreignKey:AccountID"`
+	Transaction LedgerTransaction `json:"transaction" gorm:"foreignKey:TransactionID"`
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusScheduled PayoutStatus = "scheduled"
+	// Enviado ao provedor; fica assim quando a resposta não confirma nem
+	// recusa o saque, até a conciliação
+	PayoutStatusProcessing PayoutStatus = "processing"
+	PayoutStatusPaid       PayoutStatus = "paid"
+	PayoutStatusFailed     PayoutStatus = "failed"
+)
+
+// Payout é um saque agendado do saldo disponível do criador para a sua conta
+// de recebimento
+type Payout struct {
+	ID               uint         `json:"id" gorm:"primaryKey"`
+	UserID           uint         `json:"user_id" gorm:"not null;index"`
+	Amount           int64        `json:"amount" gorm:"not null"`
+	Currency         string       `json:"currency" gorm:"size:3;not null"`
+	Status           PayoutStatus `json:"status" gorm:"size:20;default:'scheduled';index"`
+	ScheduledFor     time.Time    `json:"scheduled_for" gorm:"not null;index"`
+	ProviderPayoutID string       `json:"provider_payout_id" gorm:"size:100"`
+	FailureReason    string       `json:"failure_reason" gorm:"size:300"`
+	PaidAt           *time.Time   `json:"paid_at"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// LedgerLeg descreve um lançamento a registrar, antes de a conta existir
+type LedgerLeg struct {
+	AccountType LedgerAccountType
+	OwnerID     uint
+	Amount      int64
+}
+
+type LedgerBalance struct {
+	Currency      string `json:"currency"`
+	Available     int64  `json:"available"`
+	PendingPayout int64  `json:"pending_payout"`
+}
+
+type LedgerEntryResponse struct {
+	ID          uint                  `json:"id"`
+	Kind        LedgerTransactionKind `json:"kind"`
+	Reference   string                `json:"reference"`
+	Description string                `json:"description"`
+	AccountType LedgerAccountType     `json:"account_type"`
+	Amount      int64                 `json:"amount"`
+	Currency    string                `json:"currency"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+type PayoutResponse struct {
+	ID            uint         `json:"id"`
+	Amount        int64        `json:"amount"`
+	Currency      string       `json:"currency"`
+	Status        PayoutStatus `json:"status"`
+	ScheduledFor  time.Time    `json:"scheduled_for"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	PaidAt        *time.Time   `json:"paid_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// ReconciliationTotal soma os lançamentos de um tipo de transação por moeda
+type ReconciliationTotal struct {
+	Kind         LedgerTransactionKind `json:"kind"`
+	Currency     string                `json:"currency"`
+	Transactions int64                 `json:"transactions"`
+	Volume       int64                 `json:"volume"` // soma dos lançamentos positivos
+}
+
+// ReconciliationReport confronta o livro-razão com as tabelas de origem no
+// período: transações desbalanceadas e gorjetas, promoções e assinaturas pagas
+// sem lançamento correspondente
+type ReconciliationReport struct {
+	From                     time.Time             `json:"from"`
+	To                       time.Time             `json:"to"`
+	Totals                   []ReconciliationTotal `json:"totals"`
+	UnbalancedTransactionIDs []uint                `json:"unbalanced_transaction_ids"`
+	MissingTipIDs            []uint                `json:"missing_tip_ids"`
+	MissingRefundTipIDs      []uint                `json:"missing_refund_tip_ids"`
+	MissingPromotionIDs      []uint                `json:"missing_promotion_ids"`
+	MissingSubscriptionIDs   []uint                `json:"missing_subscription_ids"`
+	AccountsOutOfSync        []uint                `json:"accounts_out_of_sync"`
+	// Saques sem confirmação do provedor há mais de uma hora, a conferir no
+	// painel do provedor
+	ProcessingPayoutIDs []uint `json:"processing_payout_ids"`
+}
+
+func (e *LedgerEntry) ToResponse() *LedgerEntryResponse {
+	return &LedgerEntryResponse{
+		ID:          e.ID,
+		Kind:        e.Transaction.Kind,
+		Reference:   e.Transaction.Reference,
+		Description: e.Transaction.Description,
+		AccountType: e.Account.Type,
+		Amount:      e.Amount,
+		Currency:    e.Account.Currency,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func (p *Payout) ToResponse() *PayoutResponse {
+	return &PayoutResponse{
+		ID:            p.ID,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        p.Status,
+		ScheduledFor:  p.ScheduledFor,
+		FailureReason: p.FailureReason,
+		PaidAt:        p.PaidAt,
+		CreatedAt:     p.CreatedAt,
+	}
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"time"
+)
+
+type MediaType string
+
+const (
+	MediaTypeImage MediaType = "image"
+	MediaTypeVideo MediaType = "video"
+)
+
+// Media registra cada arquivo enviado e seu dono; posts só podem referenciar
+// mídias registradas do próprio autor
+type Media struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	OwnerID   uint      `json:"owner_id" gorm:"not null;index;index:idx_media_owner_hash,priority:1"`
+	URL       string    `json:"url" gorm:"size:500;not null;uniqueIndex"`
+	FilePath  string    `json:"file_path" gorm:"size:500;not null"`
+	MediaType MediaType `json:"media_type" gorm:"size:10;not null"`
+	MimeType  string    `json:"mime_type" gorm:"size:100"`
+	FileSize  int64     `json:"file_size"`
+	// SHA-256 do arquivo como foi enviado, para reaproveitar reenvios do
+	// mesmo arquivo pelo mesmo usuário
+	ContentHash string `json:"-" gorm:"size:64;index:idx_media_owner_hash,priority:2"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	// Anexo de conversa: o arquivo não tem acesso público e só é servido por
+	// links assinados aos participantes; não pode ser usado em posts
+	Private   bool      `json:"private" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Versões redimensionadas das imagens, por nome (thumb, medium...), e o
+	// HLS e a capa dos vídeos
+	Variants map[string]MediaVariant `json:"variants,omitempty" gorm:"serializer:json"`
+	// Arquivos derivados sem URL própria (playlists e segmentos HLS), apagados
+	// junto com a mídia
+	ExtraFiles []string `json:"-" gorm:"serializer:json"`
+
+	// Andamento do processamento em segundo plano (conversão dos vídeos);
+	// imagens ficam prontas já no upload
+	Status          MediaProcessingStatus `json:"status" gorm:"size:20;default:'ready';index"`
+	ProcessingError string                `json:"processing_error,omitempty" gorm:"size:500"`
+
+	// Resultado da moderação automática das imagens; mídias sinalizadas não
+	// podem ser usadas em posts e stories até a revisão
+	ModerationStatus MediaModerationStatus `json:"moderation_status" gorm:"size:20;default:'approved';index"`
+	ModerationLabels []ModerationLabel     `json:"moderation_labels,omitempty" gorm:"serializer:json"`
+	ReviewedByID     *uint                 `json:"reviewed_by_id,omitempty"`
+	ReviewedAt       *time.Time            `json:"reviewed_at,omitempty"`
+
+	// Relacionamentos
+	Owner User `json:"-" gorm:"foreignKey:OwnerID"`
+}
+
+type MediaProcessingStatus string
+
+const (
+	MediaStatusUploaded   MediaProcessingStatus = "uploaded"   // na fila de processamento
+	MediaStatusProcessing MediaProcessingStatus = "processing" // sendo convertida
+	MediaStatusReady      MediaProcessingStatus = "ready"
+	MediaStatusFailed     MediaProcessingStatus = "failed" // o original continua disponível
+)
+
+// IsUsable indica se a mídia já está na forma final e liberada pela
+// moderação. Mídias ainda em processamento podem ser anexadas, mas exibem só o
+// original
+func (m *Media) IsUsable() bool {
+	moderated := m.ModerationStatus == "" || m.ModerationStatus == MediaModerationApproved
+	ready := m.Status == "" || m.Status == MediaStatusReady
+	return moderated && ready
+}
+
+type MediaModerationStatus string
+
+const (
+	MediaModerationApproved MediaModerationStatus = "approved"
+	MediaModerationFlagged  MediaModerationStatus = "flagged"  // em quarentena, aguardando revisão
+	MediaModerationRejected MediaModerationStatus = "rejected" // arquivos removidos pela moderação
+)
+
+// ModerationLabel é uma categoria de conteúdo impróprio detectada na imagem,
+// com a confiança de 0 a 100
+type ModerationLabel struct {
+	Name       string  `json:"name"`
+	Parent     string  `json:"parent,omitempty"`
+	Confidence float64 `json:"confidence"`
+}
+
+// FlaggedMediaResponse é uma mídia na fila de moderação, com o dono
+type FlaggedMediaResponse struct {
+	Media
+	Owner *UserResponse `json:"owner,omitempty"`
+}
+
+func (m *Media) ToFlaggedResponse() *FlaggedMediaResponse {
+	response := &FlaggedMediaResponse{Media: *m}
+	if m.Owner.ID != 0 {
+		response.Owner = m.Owner.ToResponse()
+	}
+	return response
+}
+
+// MediaVariant é uma versão redimensionada de uma imagem, gravada ao lado do
+// original. Quando a imagem já cabe no tamanho da variante, ela aponta para o
+// próprio original e FilePath fica vazio
+type MediaVariant struct {
+	URL      string `json:"url"`
+	FilePath string `json:"file_path,omitempty"`
+	MimeType string `json:"mime_type"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// PostMediaItem descreve cada anexo de um post, permitindo posts com imagens
+// e vídeos misturados
+type PostMediaItem struct {
+	MediaID   uint      `json:"media_id"`
+	URL       string    `json:"url"`
+	MediaType MediaType `json:"media_type"`
+	MimeType  string    `json:"mime_type"`
+	Width     int       `json:"width,omitempty"`
+	Height    int       `json:"height,omitempty"`
+
+	Variants map[string]MediaVariant `json:"variants,omitempty"`
+}
+
+type UploadStatus string
+
+const (
+	UploadStatusUploading UploadStatus = "uploading"
+	UploadStatusCompleted UploadStatus = "completed"
+)
+
+// UploadSession é um upload em partes (retomável): o cliente envia os bytes
+// em sequência a partir de Offset e, se a conexão cair, consulta a sessão e
+// continua de onde parou. Ao concluir, a mídia é registrada em MediaID
+type UploadSession struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	OwnerID   uint         `json:"owner_id" gorm:"not null;index"`
+	FileName  string       `json:"file_name" gorm:"size:255;not null"`
+	MediaType MediaType    `json:"media_type" gorm:"size:10;not null"`
+	MimeType  string       `json:"mime_type" gorm:"size:100"`
+	TotalSize int64        `json:"total_size" gorm:"not null"`
+	Offset    int64        `json:"offset" gorm:"not null;default:0"`
+	Status    UploadStatus `json:"status" gorm:"size:20;default:'uploading';index"`
+	MediaID   *uint        `json:"media_id"`
+	ExpiresAt time.Time    `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	Owner User `json:"-" gorm:"foreignKey:OwnerID"`
+}
+
+type TranscodeStatus string
+
+const (
+	TranscodeStatusPending TranscodeStatus = "pending"
+	TranscodeStatusRunning TranscodeStatus = "running"
+	TranscodeStatusReady   TranscodeStatus = "ready"
+	TranscodeStatusFailed  TranscodeStatus = "failed"
+)
+
+// TranscodeJob é a conversão em segundo plano de um vídeo enviado para HLS,
+// com uma capa extraída do vídeo; o resultado fica nas variantes da mídia
+type TranscodeJob struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	MediaID    uint            `json:"media_id" gorm:"not null;uniqueIndex"`
+	OwnerID    uint            `json:"owner_id" gorm:"not null;index"`
+	Status     TranscodeStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	Error      string          `json:"error,omitempty" gorm:"size:500"`
+	StartedAt  *time.Time      `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+
+	Media Media `json:"-" gorm:"foreignKey:MediaID"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// MemoryYear agrupa o que o usuário publicou na mesma data de um ano anterior
+type MemoryYear struct {
+	Year        int                 `json:"year"`
+	YearsAgo    int                 `json:"years_ago"`
+	Posts       []PostResponse      `json:"posts"`
+	Itineraries []ItineraryResponse `json:"itineraries"`
+}
+
+// MemoriesResponse é a resposta de "neste dia" para o usuário autenticado
+type MemoriesResponse struct {
+	Date    string       `json:"date"`
+	Enabled bool         `json:"enabled"`
+	Years   []MemoryYear `json:"years"`
+}
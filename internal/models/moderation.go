@@ -0,0 +1,236 @@
+package models
+
+import (
+	"time"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonHateSpeech     ReportReason = "hate_speech"
+	ReportReasonNudity         ReportReason = "nudity"
+	ReportReasonViolence       ReportReason = "violence"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonCopyright      ReportReason = "copyright"
+	ReportReasonOther          ReportReason = "other"
+)
+
+// ReportReasons lista os motivos aceitos nas denúncias
+var ReportReasons = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonNudity,
+	ReportReasonViolence,
+	ReportReasonMisinformation,
+	ReportReasonCopyright,
+	ReportReasonOther,
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusResolved  ReportStatus = "resolved"  // denúncia procedente
+	ReportStatusDismissed ReportStatus = "dismissed" // denúncia improcedente
+)
+
+// MessageReport é a denúncia de uma mensagem por um participante da
+// conversa. O conteúdo é copiado no momento da denúncia, para que a
+// moderação veja o que foi denunciado mesmo se a mensagem sumir depois; ele
+// só é exibido no detalhe da denúncia, e cada acesso fica na auditoria
+type MessageReport struct {
+	ID             uint         `json:"id" gorm:"primaryKey"`
+	MessageID      uint         `json:"message_id" gorm:"not null;uniqueIndex:idx_message_reports_message_reporter"`
+	ConversationID uint         `json:"conversation_id" gorm:"not null;index"`
+	ReporterID     uint         `json:"reporter_id" gorm:"not null;uniqueIndex:idx_message_reports_message_reporter"`
+	SenderID       uint         `json:"sender_id" gorm:"not null;index"`
+	Content        string       `json:"-" gorm:"type:text"`
+	MediaID        *uint        `json:"-" gorm:"index"`
+	MessageSentAt  time.Time    `json:"message_sent_at"`
+	Reason         ReportReason `json:"reason" gorm:"size:30;not null"`
+	Details        string       `json:"details" gorm:"size:1000"`
+	Status         ReportStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	ResolvedByID   *uint        `json:"resolved_by_id"`
+	ResolutionNote string       `json:"resolution_note" gorm:"size:500"`
+	ResolvedAt     *time.Time   `json:"resolved_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+
+	// Relacionamentos (sem chave estrangeira para a mensagem, como nos casos
+	// de denúncia)
+	Reporter User `json:"reporter" gorm:"foreignKey:ReporterID"`
+	Sender   User `json:"sender" gorm:"foreignKey:SenderID"`
+}
+
+type MessageReportResponse struct {
+	ID             uint          `json:"id"`
+	MessageID      uint          `json:"message_id"`
+	ConversationID uint          `json:"conversation_id"`
+	Reporter       *UserResponse `json:"reporter,omitempty"`
+	Sender         *UserResponse `json:"sender,omitempty"`
+	MessageSentAt  time.Time     `json:"message_sent_at"`
+	Reason         ReportReason  `json:"reason"`
+	Details        string        `json:"details"`
+	Status         ReportStatus  `json:"status"`
+	ResolutionNote string        `json:"resolution_note"`
+	ResolvedAt     *time.Time    `json:"resolved_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+func (r *MessageReport) ToResponse() *MessageReportResponse {
+	response := &MessageReportResponse{
+		ID:             r.ID,
+		MessageID:      r.MessageID,
+		ConversationID: r.ConversationID,
+		MessageSentAt:  r.MessageSentAt,
+		Reason:         r.Reason,
+		Details:        r.Details,
+		Status:         r.Status,
+		ResolutionNote: r.ResolutionNote,
+		ResolvedAt:     r.ResolvedAt,
+		CreatedAt:      r.CreatedAt,
+	}
+
+	if r.Reporter.ID != 0 {
+		response.Reporter = r.Reporter.ToResponse()
+	}
+	if r.Sender.ID != 0 {
+		response.Sender = r.Sender.ToResponse()
+	}
+
+	return response
+}
+
+// MessageReportDetail é a denúncia com o conteúdo denunciado e as mensagens
+// vizinhas da conversa, para a moderação entender o contexto
+type MessageReportDetail struct {
+	*MessageReportResponse
+	Content string `json:"content"`
+	// Links assinados para o moderador que abriu a denúncia
+	Attachment *MessageAttachment `json:"attachment,omitempty"`
+	// Removed indica que a mensagem já foi apagada da conversa
+	Removed bool              `json:"removed"`
+	Context []MessageResponse `json:"context"`
+}
+
+type ModerationActionType string
+
+const (
+	ModerationActionResolveReport    ModerationActionType = "resolve_report"
+	ModerationActionDismissReport    ModerationActionType = "dismiss_report"
+	ModerationActionHidePost         ModerationActionType = "hide_post"
+	ModerationActionRestorePost      ModerationActionType = "restore_post"
+	ModerationActionRemovePost       ModerationActionType = "remove_post"
+	ModerationActionRestoreItinerary ModerationActionType = "restore_itinerary"
+	ModerationActionRemoveItinerary  ModerationActionType = "remove_itinerary"
+	ModerationActionBanUser          ModerationActionType = "ban_user"
+	ModerationActionUnbanUser        ModerationActionType = "unban_user"
+	ModerationActionSuspendUser      ModerationActionType = "suspend_user"
+	ModerationActionRestrict         ModerationActionType = "restrict_content"
+	ModerationActionUnrestrict       ModerationActionType = "unrestrict_content"
+	ModerationActionApproveMedia     ModerationActionType = "approve_media"
+	ModerationActionRejectMedia      ModerationActionType = "reject_media"
+	ModerationActionViewMessage      ModerationActionType = "view_reported_message"
+	ModerationActionRemoveMessage    ModerationActionType = "remove_message"
+
+	ModerationActionApproveVerification ModerationActionType = "approve_verification"
+	ModerationActionRejectVerification  ModerationActionType = "reject_verification"
+
+	ModerationActionAcceptAppeal ModerationActionType = "accept_appeal"
+	ModerationActionRejectAppeal ModerationActionType = "reject_appeal"
+)
+
+type ModerationTargetType string
+
+const (
+	ModerationTargetPost            ModerationTargetType = "post"
+	ModerationTargetUser            ModerationTargetType = "user"
+	ModerationTargetPostReport      ModerationTargetType = "post_report"
+	ModerationTargetItinerary       ModerationTargetType = "itinerary"
+	ModerationTargetItineraryReport ModerationTargetType = "itinerary_report"
+	ModerationTargetMedia           ModerationTargetType = "media"
+	ModerationTargetMessage         ModerationTargetType = "message"
+	ModerationTargetMessageReport   ModerationTargetType = "message_report"
+	ModerationTargetVerification    ModerationTargetType = "verification"
+	ModerationTargetReportCase      ModerationTargetType = "report_case"
+	ModerationTargetAppeal          ModerationTargetType = "appeal"
+)
+
+// ModerationAction é o registro de auditoria de cada decisão tomada pela
+// moderação, individual ou em lote
+type ModerationAction struct {
+	ID         uint                 `json:"id" gorm:"primaryKey"`
+	AdminID    uint                 `json:"admin_id" gorm:"not null;index"`
+	Action     ModerationActionType `json:"action" gorm:"size:30;not null"`
+	TargetType ModerationTargetType `json:"target_type" gorm:"size:20;not null;index:idx_moderation_actions_target"`
+	TargetID   uint                 `json:"target_id" gorm:"not null;index:idx_moderation_actions_target"`
+	Note       string               `json:"note" gorm:"size:1000"`
+	JobID      *uint                `json:"job_id" gorm:"index"` // ação executada por um lote
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+type BulkModerationAction string
+
+const (
+	BulkModerationHidePosts   BulkModerationAction = "hide_posts"
+	BulkModerationDeletePosts BulkModerationAction = "delete_posts"
+	BulkModerationBanUsers    BulkModerationAction = "ban_users"
+)
+
+type BulkModerationStatus string
+
+const (
+	BulkModerationPending   BulkModerationStatus = "pending"
+	BulkModerationRunning   BulkModerationStatus = "running"
+	BulkModerationCompleted BulkModerationStatus = "completed"
+)
+
+type BulkItemStatus string
+
+const (
+	BulkItemApplied    BulkItemStatus = "applied"     // ação executada
+	BulkItemWouldApply BulkItemStatus = "would_apply" // simulação: seria executada
+	BulkItemSkipped    BulkItemStatus = "skipped"     // nada a fazer (já oculto, já banido...)
+	BulkItemNotFound   BulkItemStatus = "not_found"
+	BulkItemFailed     BulkItemStatus = "failed"
+)
+
+// BulkModerationItem é o resultado da ação em lote para um ID
+type BulkModerationItem struct {
+	ID      uint           `json:"id"`
+	Status  BulkItemStatus `json:"status"`
+	Message string         `json:"message,omitempty"`
+}
+
+// BulkModerationJob é um lote de ações de moderação processado em segundo
+// plano; os contadores mostram o progresso enquanto ele executa
+type BulkModerationJob struct {
+	ID         uint                 `json:"id" gorm:"primaryKey"`
+	AdminID    uint                 `json:"admin_id" gorm:"not null;index"`
+	Action     BulkModerationAction `json:"action" gorm:"size:30;not null"`
+	TargetIDs  []uint               `json:"target_ids" gorm:"serializer:json;type:text"`
+	Note       string               `json:"note" gorm:"size:1000"`
+	Status     BulkModerationStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	Total      int                  `json:"total"`
+	Processed  int                  `json:"processed"`
+	Applied    int                  `json:"applied"`
+	Skipped    int                  `json:"skipped"`
+	Failed     int                  `json:"failed"`
+	Results    []BulkModerationItem `json:"results" gorm:"serializer:json;type:text"`
+	StartedAt  *time.Time           `json:"started_at"`
+	FinishedAt *time.Time           `json:"finished_at"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+// BulkModerationPreview é o resultado de uma simulação (dry-run): o que o
+// lote faria, sem alterar nada
+type BulkModerationPreview struct {
+	Action     BulkModerationAction `json:"action"`
+	DryRun     bool                 `json:"dry_run"`
+	Total      int                  `json:"total"`
+	Applicable int                  `json:"applicable"`
+	Items      []BulkModerationItem `json:"items"`
+}
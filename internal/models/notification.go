@@ -0,0 +1,179 @@
+package models
+
+import (
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationTypeMemory       NotificationType = "memory"
+	NotificationTypeTripReminder NotificationType = "trip_reminder"
+	NotificationTypeRatingReply  NotificationType = "rating_reply"
+
+	NotificationTypeTripInvitation         NotificationType = "trip_invitation"
+	NotificationTypeTripInvitationAccepted NotificationType = "trip_invitation_accepted"
+
+	NotificationTypeMediaReady NotificationType = "media_ready"
+
+	// Roteiros novos que atendem a uma busca salva
+	NotificationTypeSavedSearch NotificationType = "saved_search"
+
+	// Decisão sobre o pedido de verificação da conta
+	NotificationTypeVerification NotificationType = "verification"
+
+	// Fim da suspensão ou resposta ao recurso contra ela
+	NotificationTypeAccountStatus NotificationType = "account_status"
+
+	// Tipos agrupados: uma rajada vira uma só notificação ("ana e mais 22
+	// pessoas curtiram seu post")
+	NotificationTypePostLike    NotificationType = "post_like"
+	NotificationTypeNewFollower NotificationType = "new_follower"
+
+	NotificationTypeDirectMessage NotificationType = "direct_message"
+
+	// Resumo semanal das notificações não lidas, enviado só por e-mail
+	NotificationTypeWeeklyDigest NotificationType = "weekly_digest"
+)
+
+// NotificationChannels diz por quais canais um tipo de notificação é entregue
+type NotificationChannels struct {
+	InApp bool `json:"in_app"`
+	Push  bool `json:"push"`
+	Email bool `json:"email"`
+}
+
+// Any indica se algum canal está ativo
+func (c NotificationChannels) Any() bool {
+	return c.InApp || c.Push || c.Email
+}
+
+// notificationDefaults são os canais de cada tipo para quem não mudou as
+// preferências: tudo no app e no push, e por e-mail só o que tem data ou
+// depende de resposta
+var notificationDefaults = []struct {
+	Type     NotificationType
+	Channels NotificationChannels
+}{
+	{NotificationTypeTripInvitation, NotificationChannels{InApp: true, Push: true, Email: true}},
+	{NotificationTypeTripInvitationAccepted, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeTripReminder, NotificationChannels{InApp: true, Push: true, Email: true}},
+	{NotificationTypeRatingReply, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeMemory, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeMediaReady, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeSavedSearch, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeVerification, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeAccountStatus, NotificationChannels{InApp: true, Push: true, Email: true}},
+	{NotificationTypePostLike, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeNewFollower, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeDirectMessage, NotificationChannels{InApp: true, Push: true}},
+	{NotificationTypeWeeklyDigest, NotificationChannels{Email: true}},
+}
+
+// BypassesQuietHours indica os tipos cujo push sai mesmo no horário de
+// silêncio: mensagens diretas e alertas da viagem
+func (t NotificationType) BypassesQuietHours() bool {
+	return t == NotificationTypeDirectMessage || t == NotificationTypeTripReminder
+}
+
+// NotificationTypes lista os tipos configuráveis, na ordem da tela de ajustes
+func NotificationTypes() []NotificationType {
+	types := make([]NotificationType, 0, len(notificationDefaults))
+	for _, d := range notificationDefaults {
+		types = append(types, d.Type)
+	}
+	return types
+}
+
+// DefaultNotificationChannels retorna os canais padrão do tipo; tipos
+// desconhecidos vão só para o app
+func DefaultNotificationChannels(notificationType NotificationType) NotificationChannels {
+	for _, d := range notificationDefaults {
+		if d.Type == notificationType {
+			return d.Channels
+		}
+	}
+	return NotificationChannels{InApp: true}
+}
+
+// Notification é uma notificação exibida no app; Key, quando informada, evita
+// que a mesma notificação seja criada duas vezes para o usuário. GroupKey
+// junta na mesma notificação não lida os eventos de vários usuários (ActorCount)
+type Notification struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	UserID      uint              `json:"user_id" gorm:"not null;index;uniqueIndex:idx_notifications_user_key;index:idx_notifications_unread,where:read_at IS NULL AND hidden_in_app = false;index:idx_notifications_group"`
+	Type        NotificationType  `json:"type" gorm:"size:30;not null"`
+	Title       string            `json:"title" gorm:"size:200"`
+	Body        string            `json:"body" gorm:"size:500"`
+	Data        map[string]string `json:"data" gorm:"serializer:json"`
+	Key         *string           `json:"-" gorm:"size:100;uniqueIndex:idx_notifications_user_key"`
+	GroupKey    *string           `json:"group_key,omitempty" gorm:"size:100;index:idx_notifications_group"`
+	ActorCount  int               `json:"actor_count" gorm:"default:0"`
+	HiddenInApp bool              `json:"-" gorm:"default:false"` // entregue só por push ou e-mail; o registro evita reenvios
+	ReadAt      *time.Time        `json:"read_at"`
+	CreatedAt   time.Time         `json:"created_at" gorm:"index"`
+
+	// Push segurado pelo horário de silêncio, enviado no fim dele
+	PushDeferredUntil *time.Time `json:"-" gorm:"index:idx_notifications_push_deferred,where:push_deferred_until IS NOT NULL"`
+}
+
+// NotificationActor registra quem participou de uma notificação agrupada,
+// para a tela de detalhes
+type NotificationActor struct {
+	ID             uint      `json:"-" gorm:"primaryKey"`
+	NotificationID uint      `json:"-" gorm:"not null;uniqueIndex:idx_notification_actors_notification_actor"`
+	ActorID        uint      `json:"-" gorm:"not null;uniqueIndex:idx_notification_actors_notification_actor"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NotificationDetailResponse é a notificação com os usuários do grupo, do
+// mais recente para o mais antigo
+type NotificationDetailResponse struct {
+	Notification
+	Actors []UserResponse `json:"actors"`
+}
+
+// NotificationPreference guarda os canais escolhidos pelo usuário para um tipo
+// de notificação; sem registro valem os padrões do tipo
+type NotificationPreference struct {
+	ID                   uint             `json:"-" gorm:"primaryKey"`
+	UserID               uint             `json:"-" gorm:"not null;uniqueIndex:idx_notification_preferences_user_type"`
+	Type                 NotificationType `json:"type" gorm:"size:30;not null;uniqueIndex:idx_notification_preferences_user_type"`
+	NotificationChannels `gorm:"embedded"`
+	UpdatedAt            time.Time `json:"-"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type NotificationType `json:"type"`
+	NotificationChannels
+	Customized bool `json:"customized"`
+}
+
+// QuietHours é a janela diária, no fuso do usuário, em que pushes não urgentes
+// ficam para o fim do período. Os horários são minutos desde a meia-noite; a
+// janela pode virar o dia (22:00 às 07:00)
+type QuietHours struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	UserID      uint      `json:"-" gorm:"not null;uniqueIndex"`
+	Enabled     bool      `json:"enabled" gorm:"default:false"`
+	StartMinute int       `json:"-" gorm:"not null"`
+	EndMinute   int       `json:"-" gorm:"not null"`
+	UpdatedAt   time.Time `json:"-"`
+}
+
+type QuietHoursResponse struct {
+	Timezone string `json:"timezone"`
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"` // HH:MM
+	End      string `json:"end"`
+}
+
+// NotificationUnreadCount é o contador do ícone de notificações
+type NotificationUnreadCount struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type NotificationReadResponse struct {
+	Updated     int64 `json:"updated"`
+	UnreadCount int64 `json:"unread_count"`
+}
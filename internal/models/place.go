@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// PlaceDetails é o cache dos detalhes de um lugar no provedor externo, para
+// não repetir consultas (e downloads de fotos) dentro da cota da API. Photos
+// guarda as fotos já copiadas para o armazenamento de mídia
+type PlaceDetails struct {
+	PlaceID      string    `json:"place_id" gorm:"primaryKey;size:255"`
+	Provider     string    `json:"provider" gorm:"size:20"`
+	Name         string    `json:"name" gorm:"size:200"`
+	Address      string    `json:"address" gorm:"size:300"`
+	Latitude     *float64  `json:"latitude"`
+	Longitude    *float64  `json:"longitude"`
+	Phone        string    `json:"phone" gorm:"size:20"`
+	Website      string    `json:"website" gorm:"size:200"`
+	Rating       *float64  `json:"rating"`
+	OpeningHours []string  `json:"opening_hours" gorm:"serializer:json"`
+	Photos       []string  `json:"photos" gorm:"serializer:json"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// LocationReview é a avaliação de um usuário sobre um local de roteiro. Locais
+// com lugar no provedor compartilham PlaceKey ("place:<id>"), o que junta as
+// avaliações do mesmo ponto feitas em roteiros diferentes; os demais usam
+// "location:<id>". Cada usuário avalia cada ponto uma vez
+type LocationReview struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_location_reviews_user_place"`
+	PlaceKey    string    `json:"-" gorm:"size:300;not null;uniqueIndex:idx_location_reviews_user_place;index"`
+	PlaceID     string    `json:"place_id" gorm:"size:255"`
+	LocationID  uint      `json:"location_id" gorm:"not null;index"`
+	ItineraryID uint      `json:"itinerary_id" gorm:"not null;index"`
+	Rating      int       `json:"rating" gorm:"not null;check:rating >= 1 AND rating <= 5"`
+	Comment     string    `json:"comment" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relacionamentos
+	User User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+type LocationReviewResponse struct {
+	ID          uint          `json:"id"`
+	PlaceID     string        `json:"place_id"`
+	LocationID  uint          `json:"location_id"`
+	ItineraryID uint          `json:"itinerary_id"`
+	Rating      int           `json:"rating"`
+	Comment     string        `json:"comment"`
+	User        *UserResponse `json:"user,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func (r *LocationReview) ToResponse() *LocationReviewResponse {
+	response := &LocationReviewResponse{
+		ID:          r.ID,
+		PlaceID:     r.PlaceID,
+		LocationID:  r.LocationID,
+		ItineraryID: r.ItineraryID,
+		Rating:      r.Rating,
+		Comment:     r.Comment,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+
+	if r.User.ID != 0 {
+		response.User = r.User.ToResponse()
+	}
+
+	return response
+}
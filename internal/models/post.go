@@ -12,30 +12,57 @@ const (
 	PostTypeText  PostType = "text"
 	PostTypeImage PostType = "image"
 	PostTypeVideo PostType = "video"
+	PostTypeMixed PostType = "mixed" // imagens e vídeos no mesmo post
+)
+
+type PostVisibility string
+
+const (
+	PostVisibilityPublic    PostVisibility = "public"
+	PostVisibilityFollowers PostVisibility = "followers"
+	PostVisibilityPrivate   PostVisibility = "private"
+)
+
+// CommentPolicy define quem pode comentar em um post; o autor sempre pode
+type CommentPolicy string
+
+const (
+	CommentPolicyEveryone  CommentPolicy = "everyone"
+	CommentPolicyFollowers CommentPolicy = "followers" // apenas quem segue o autor
+	CommentPolicyMentioned CommentPolicy = "mentioned" // apenas usuários citados com @ no post
+	CommentPolicyOff       CommentPolicy = "off"
 )
 
 type Post struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	AuthorID      uint           `json:"author_id" gorm:"not null"`
-	Content       string         `json:"content" gorm:"type:text"`
-	PostType      PostType       `json:"post_type" gorm:"default:'text'"`
-	MediaURL      string         `json:"media_url"`
-	MediaURLs     []string       `json:"media_urls" gorm:"serializer:json"`
-	Location      string         `json:"location" gorm:"size:200"`
-	Latitude      *float64       `json:"latitude"`
-	Longitude     *float64       `json:"longitude"`
-	LikesCount    int            `json:"likes_count" gorm:"default:0"`
-	CommentsCount int            `json:"comments_count" gorm:"default:0"`
-	SharesCount   int            `json:"shares_count" gorm:"default:0"`
-	IsActive      bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	AuthorID      uint            `json:"author_id" gorm:"not null;index:idx_posts_author_created"`
+	Content       string          `json:"content" gorm:"type:text"`
+	Language      string          `json:"language" gorm:"size:10"` // idioma detectado do conteúdo
+	PostType      PostType        `json:"post_type" gorm:"default:'text'"`
+	MediaURL      string          `json:"media_url"`
+	MediaURLs     []string        `json:"media_urls" gorm:"serializer:json"`
+	MediaItems    []PostMediaItem `json:"media_items" gorm:"serializer:json"`
+	Location      string          `json:"location" gorm:"size:200"`
+	Latitude      *float64        `json:"latitude" gorm:"index:idx_posts_coordinates"`
+	Longitude     *float64        `json:"longitude" gorm:"index:idx_posts_coordinates"`
+	LikesCount    int             `json:"likes_count" gorm:"default:0"`
+	CommentsCount int             `json:"comments_count" gorm:"default:0"`
+	SharesCount   int             `json:"shares_count" gorm:"default:0"`
+	IsActive      bool            `json:"is_active" gorm:"default:true"`
+	ReportsCount  int             `json:"reports_count" gorm:"default:0"`
+	HiddenAt      *time.Time      `json:"hidden_at"` // ocultado pela moderação após denúncias
+	Visibility    PostVisibility  `json:"visibility" gorm:"size:20;default:'public';index"`
+	CommentPolicy CommentPolicy   `json:"comment_policy" gorm:"size:20;default:'everyone'"`
+	ItineraryID   *uint           `json:"itinerary_id" gorm:"index"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"index:idx_posts_author_created"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt  `json:"-" gorm:"index"`
 
 	// Relacionamentos
-	Author   User       `json:"author" gorm:"foreignKey:AuthorID"`
-	Likes    []PostLike `json:"likes,omitempty" gorm:"foreignKey:PostID"`
-	Comments []Comment  `json:"comments,omitempty" gorm:"foreignKey:PostID"`
+	Author    User       `json:"author" gorm:"foreignKey:AuthorID"`
+	Itinerary *Itinerary `json:"itinerary,omitempty" gorm:"foreignKey:ItineraryID"`
+	Likes     []PostLike `json:"likes,omitempty" gorm:"foreignKey:PostID"`
+	Comments  []Comment  `json:"comments,omitempty" gorm:"foreignKey:PostID"`
 }
 
 type PostLike struct {
@@ -54,6 +81,7 @@ type Comment struct {
 	AuthorID  uint           `json:"author_id" gorm:"not null"`
 	Content   string         `json:"content" gorm:"type:text;not null"`
 	ParentID  *uint          `json:"parent_id"`
+	HiddenAt  *time.Time     `json:"hidden_at"` // ocultado pela moderação após denúncias
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
@@ -65,23 +93,65 @@ type Comment struct {
 	Replies []Comment `json:"replies,omitempty" gorm:"foreignKey:ParentID"`
 }
 
+type CommentResponse struct {
+	ID        uint              `json:"id"`
+	PostID    uint              `json:"post_id"`
+	ParentID  *uint             `json:"parent_id"`
+	Content   string            `json:"content"`
+	Author    *UserResponse     `json:"author,omitempty"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func (c *Comment) ToResponse() *CommentResponse {
+	response := &CommentResponse{
+		ID:        c.ID,
+		PostID:    c.PostID,
+		ParentID:  c.ParentID,
+		Content:   c.Content,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+
+	if c.Author.ID != 0 {
+		response.Author = c.Author.ToResponse()
+	}
+
+	for _, reply := range c.Replies {
+		response.Replies = append(response.Replies, *reply.ToResponse())
+	}
+
+	return response
+}
+
 type PostResponse struct {
-	ID            uint          `json:"id"`
-	AuthorID      uint          `json:"author_id"`
-	Content       string        `json:"content"`
-	PostType      PostType      `json:"post_type"`
-	MediaURL      string        `json:"media_url"`
-	MediaURLs     []string      `json:"media_urls"`
-	Location      string        `json:"location"`
-	Latitude      *float64      `json:"latitude"`
-	Longitude     *float64      `json:"longitude"`
-	LikesCount    int           `json:"likes_count"`
-	CommentsCount int           `json:"comments_count"`
-	SharesCount   int           `json:"shares_count"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
-	Author        *UserResponse `json:"author,omitempty"`
-	IsLiked       bool          `json:"is_liked"`
+	ID            uint               `json:"id"`
+	AuthorID      uint               `json:"author_id"`
+	Content       string             `json:"content"`
+	Language      string             `json:"language,omitempty"`
+	PostType      PostType           `json:"post_type"`
+	MediaURL      string             `json:"media_url"`
+	MediaURLs     []string           `json:"media_urls"`
+	MediaItems    []PostMediaItem    `json:"media_items"`
+	Location      string             `json:"location"`
+	Latitude      *float64           `json:"latitude"`
+	Longitude     *float64           `json:"longitude"`
+	LikesCount    int                `json:"likes_count"`
+	CommentsCount int                `json:"comments_count"`
+	SharesCount   int                `json:"shares_count"`
+	Visibility    PostVisibility     `json:"visibility"`
+	CommentPolicy CommentPolicy      `json:"comment_policy"`
+	ItineraryID   *uint              `json:"itinerary_id"`
+	Itinerary     *ItineraryResponse `json:"itinerary,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	Author        *UserResponse      `json:"author,omitempty"`
+	IsLiked       bool               `json:"is_liked"`
+	DistanceKm    *float64           `json:"distance_km,omitempty"`
+	Suggested     bool               `json:"suggested,omitempty"` // sugerido no feed, de autor que o usuário não segue
+
+	RecommendationReason RecommendationReason `json:"recommendation_reason,omitempty"` // só em /recommendations
 }
 
 func (p *Post) ToResponse(currentUserID uint) *PostResponse {
@@ -89,15 +159,20 @@ func (p *Post) ToResponse(currentUserID uint) *PostResponse {
 		ID:            p.ID,
 		AuthorID:      p.AuthorID,
 		Content:       p.Content,
+		Language:      p.Language,
 		PostType:      p.PostType,
 		MediaURL:      p.MediaURL,
 		MediaURLs:     p.MediaURLs,
+		MediaItems:    p.MediaItems,
 		Location:      p.Location,
 		Latitude:      p.Latitude,
 		Longitude:     p.Longitude,
 		LikesCount:    p.LikesCount,
 		CommentsCount: p.CommentsCount,
 		SharesCount:   p.SharesCount,
+		Visibility:    p.Visibility,
+		CommentPolicy: p.CommentPolicy,
+		ItineraryID:   p.ItineraryID,
 		CreatedAt:     p.CreatedAt,
 		UpdatedAt:     p.UpdatedAt,
 	}
@@ -106,6 +181,11 @@ func (p *Post) ToResponse(currentUserID uint) *PostResponse {
 		response.Author = p.Author.ToResponse()
 	}
 
+	// Resumo do roteiro vinculado, omitido se ele deixou de ser público
+	if p.Itinerary != nil && (p.Itinerary.IsPublic || p.Itinerary.AuthorID == currentUserID) {
+		response.Itinerary = p.Itinerary.ToResponse()
+	}
+
 	// Verificar se o usuário atual curtiu o post
 	for _, like := range p.Likes {
 		if like.UserID == currentUserID {
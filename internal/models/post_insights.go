@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+type PostEventKind string
+
+const (
+	PostEventImpression PostEventKind = "impression" // post entregue no feed
+	PostEventView       PostEventKind = "view"       // detalhe do post aberto
+	PostEventShare      PostEventKind = "share"
+)
+
+// PostEvent registra a exibição ou o compartilhamento de um post para as
+// estatísticas do autor; a tabela guarda apenas o essencial por ser volumosa
+type PostEvent struct {
+	ID        uint64        `json:"id" gorm:"primaryKey"`
+	PostID    uint          `json:"post_id" gorm:"not null;index:idx_post_events_post_created"`
+	ViewerID  uint          `json:"viewer_id" gorm:"not null"`
+	Kind      PostEventKind `json:"kind" gorm:"size:20;not null"`
+	CreatedAt time.Time     `json:"created_at" gorm:"index:idx_post_events_post_created"`
+}
+
+// PostInsightsPoint agrega as métricas de um post em um dia
+type PostInsightsPoint struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	Impressions int64  `json:"impressions"`
+	Views       int64  `json:"views"`
+	Likes       int64  `json:"likes"`
+	Comments    int64  `json:"comments"`
+	Shares      int64  `json:"shares"`
+}
+
+// PostInsights reúne as métricas do período; reach conta usuários distintos
+// que viram o post no feed ou no detalhe
+type PostInsights struct {
+	PostID      uint                `json:"post_id"`
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	Impressions int64               `json:"impressions"`
+	Reach       int64               `json:"reach"`
+	Views       int64               `json:"views"`
+	Likes       int64               `json:"likes"`
+	Comments    int64               `json:"comments"`
+	Shares      int64               `json:"shares"`
+	Series      []PostInsightsPoint `json:"series"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+type RecommendationKind string
+
+const (
+	RecommendationKindItinerary RecommendationKind = "itinerary"
+	RecommendationKindPost      RecommendationKind = "post"
+)
+
+// RecommendationReason é o sinal que mais pesou na recomendação
+type RecommendationReason string
+
+const (
+	RecommendationReasonSimilarUsers RecommendationReason = "similar_users" // curtido por quem curte o mesmo que o usuário
+	RecommendationReasonFollowing    RecommendationReason = "following"     // de quem o usuário segue ou curtido por essas pessoas
+	RecommendationReasonInterests    RecommendationReason = "interests"     // categorias e destinos com que o usuário interage
+	RecommendationReasonPopular      RecommendationReason = "popular"       // em alta, para quem ainda tem poucas interações
+)
+
+// Recommendation é uma sugestão pré-calculada de roteiro ou post para o
+// usuário; o job noturno substitui as do usuário a cada execução
+type Recommendation struct {
+	ID        uint                 `json:"id" gorm:"primaryKey"`
+	UserID    uint                 `json:"user_id" gorm:"not null;index:idx_recommendations_user_kind"`
+	Kind      RecommendationKind   `json:"kind" gorm:"size:20;not null;index:idx_recommendations_user_kind"`
+	ItemID    uint                 `json:"item_id" gorm:"not null"`
+	Score     float64              `json:"score"`
+	Reason    RecommendationReason `json:"reason" gorm:"size:20"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// RecommendationsResponse traz os roteiros e posts recomendados, do mais
+// para o menos relevante
+type RecommendationsResponse struct {
+	Itineraries []ItineraryResponse `json:"itineraries"`
+	Posts       []PostResponse      `json:"posts"`
+	GeneratedAt *time.Time          `json:"generated_at"`
+}
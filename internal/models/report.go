@@ -0,0 +1,166 @@
+package models
+
+import (
+	"time"
+)
+
+type ReportTargetType string
+
+const (
+	ReportTargetUser      ReportTargetType = "user"
+	ReportTargetPost      ReportTargetType = "post"
+	ReportTargetComment   ReportTargetType = "comment"
+	ReportTargetItinerary ReportTargetType = "itinerary"
+	ReportTargetRating    ReportTargetType = "rating"
+)
+
+// ReportTargetTypes lista o que pode ser denunciado pela fila unificada (as
+// mensagens diretas têm fila própria, ver MessageReport)
+var ReportTargetTypes = []ReportTargetType{
+	ReportTargetUser,
+	ReportTargetPost,
+	ReportTargetComment,
+	ReportTargetItinerary,
+	ReportTargetRating,
+}
+
+// ReportCase junta as denúncias de um mesmo alvo num caso da fila de
+// moderação, que é atribuído a um admin e decidido de uma vez. Cada alvo tem
+// no máximo um caso pendente; denúncias depois da decisão abrem um novo caso
+type ReportCase struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	TargetType     ReportTargetType `json:"target_type" gorm:"size:20;not null;uniqueIndex:idx_report_cases_pending_target,where:status = 'pending'"`
+	TargetID       uint             `json:"target_id" gorm:"not null;uniqueIndex:idx_report_cases_pending_target,where:status = 'pending'"`
+	TargetOwnerID  uint             `json:"target_owner_id" gorm:"not null;index"` // autor do conteúdo ou o próprio usuário denunciado
+	Status         ReportStatus     `json:"status" gorm:"size:20;default:'pending';index"`
+	ReportsCount   int              `json:"reports_count" gorm:"default:0"`
+	AssignedToID   *uint            `json:"assigned_to_id" gorm:"index"`
+	AssignedAt     *time.Time       `json:"assigned_at"`
+	HiddenAt       *time.Time       `json:"hidden_at"` // alvo ocultado automaticamente ao atingir o limite de denúncias
+	ResolvedByID   *uint            `json:"resolved_by_id"`
+	ResolutionNote string           `json:"resolution_note" gorm:"size:500"`
+	ResolvedAt     *time.Time       `json:"resolved_at"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	// Sinalização automática (filtro de palavrões), que abre o caso mesmo
+	// sem denúncias de usuários
+	FlagReason  ReportReason `json:"flag_reason" gorm:"size:30"`
+	FlagDetails string       `json:"flag_details" gorm:"size:500"`
+	FlaggedAt   *time.Time   `json:"flagged_at" gorm:"index"`
+
+	// Relacionamentos (sem chave estrangeira para o alvo, o caso fica como
+	// histórico após a remoção)
+	Reports     []Report `json:"reports,omitempty" gorm:"foreignKey:CaseID"`
+	TargetOwner User     `json:"-" gorm:"foreignKey:TargetOwnerID;constraint:-"`
+	AssignedTo  *User    `json:"-" gorm:"foreignKey:AssignedToID"`
+}
+
+// Report é a denúncia de um usuário, post, comentário, roteiro ou avaliação;
+// cada usuário denuncia o mesmo alvo uma única vez
+type Report struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	CaseID     uint             `json:"case_id" gorm:"not null;index"`
+	TargetType ReportTargetType `json:"target_type" gorm:"size:20;not null;uniqueIndex:idx_reports_target_reporter"`
+	TargetID   uint             `json:"target_id" gorm:"not null;uniqueIndex:idx_reports_target_reporter"`
+	ReporterID uint             `json:"reporter_id" gorm:"not null;uniqueIndex:idx_reports_target_reporter"`
+	Reason     ReportReason     `json:"reason" gorm:"size:30;not null"`
+	Details    string           `json:"details" gorm:"size:1000"`
+	CreatedAt  time.Time        `json:"created_at"`
+
+	Reporter User `json:"reporter" gorm:"foreignKey:ReporterID"`
+}
+
+type ReportResponse struct {
+	ID         uint             `json:"id"`
+	CaseID     uint             `json:"case_id"`
+	TargetType ReportTargetType `json:"target_type"`
+	TargetID   uint             `json:"target_id"`
+	Reporter   *UserResponse    `json:"reporter,omitempty"`
+	Reason     ReportReason     `json:"reason"`
+	Details    string           `json:"details"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+func (r *Report) ToResponse() *ReportResponse {
+	response := &ReportResponse{
+		ID:         r.ID,
+		CaseID:     r.CaseID,
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+		Reason:     r.Reason,
+		Details:    r.Details,
+		CreatedAt:  r.CreatedAt,
+	}
+
+	if r.Reporter.ID != 0 {
+		response.Reporter = r.Reporter.ToResponse()
+	}
+
+	return response
+}
+
+// ReportTarget é o conteúdo denunciado como a moderação o vê, inclusive se
+// estiver oculto; só o campo do tipo do alvo vem preenchido, e nenhum se ele
+// foi removido (usuários denunciados vêm em target_owner)
+type ReportTarget struct {
+	Post      *PostResponse            `json:"post,omitempty"`
+	Comment   *CommentResponse         `json:"comment,omitempty"`
+	Itinerary *ItineraryResponse       `json:"itinerary,omitempty"`
+	Rating    *ItineraryRatingResponse `json:"rating,omitempty"`
+	Hidden    bool                     `json:"hidden"`
+	Removed   bool                     `json:"removed"`
+}
+
+type ReportCaseResponse struct {
+	ID             uint                 `json:"id"`
+	TargetType     ReportTargetType     `json:"target_type"`
+	TargetID       uint                 `json:"target_id"`
+	TargetOwner    *UserResponse        `json:"target_owner,omitempty"`
+	Target         *ReportTarget        `json:"target,omitempty"`
+	Status         ReportStatus         `json:"status"`
+	ReportsCount   int                  `json:"reports_count"`
+	Reasons        map[ReportReason]int `json:"reasons"`
+	AssignedTo     *UserResponse        `json:"assigned_to,omitempty"`
+	AssignedAt     *time.Time           `json:"assigned_at"`
+	HiddenAt       *time.Time           `json:"hidden_at"`
+	FlagReason     ReportReason         `json:"flag_reason,omitempty"`
+	FlagDetails    string               `json:"flag_details,omitempty"`
+	FlaggedAt      *time.Time           `json:"flagged_at,omitempty"`
+	ResolutionNote string               `json:"resolution_note"`
+	ResolvedAt     *time.Time           `json:"resolved_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	// Denúncias do caso, apenas no detalhe
+	Reports []ReportResponse `json:"reports,omitempty"`
+}
+
+func (c *ReportCase) ToResponse() *ReportCaseResponse {
+	response := &ReportCaseResponse{
+		ID:             c.ID,
+		TargetType:     c.TargetType,
+		TargetID:       c.TargetID,
+		Status:         c.Status,
+		ReportsCount:   c.ReportsCount,
+		Reasons:        make(map[ReportReason]int),
+		AssignedAt:     c.AssignedAt,
+		HiddenAt:       c.HiddenAt,
+		FlagReason:     c.FlagReason,
+		FlagDetails:    c.FlagDetails,
+		FlaggedAt:      c.FlaggedAt,
+		ResolutionNote: c.ResolutionNote,
+		ResolvedAt:     c.ResolvedAt,
+		CreatedAt:      c.CreatedAt,
+	}
+
+	for _, report := range c.Reports {
+		response.Reasons[report.Reason]++
+	}
+	if c.TargetOwner.ID != 0 {
+		response.TargetOwner = c.TargetOwner.ToResponse()
+	}
+	if c.AssignedTo != nil {
+		response.AssignedTo = c.AssignedTo.ToResponse()
+	}
+
+	return response
+}
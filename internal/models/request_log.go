@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// RequestLog registra uma requisição que terminou em erro, para o suporte
+// localizar pelo trace ID informado pelo usuário. Não guarda corpo nem
+// cabeçalhos; a query string e o IP são sanitizados antes de gravar
+type RequestLog struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	TraceID      string    `json:"trace_id" gorm:"size:64;not null;index"`
+	Method       string    `json:"method" gorm:"size:10"`
+	Route        string    `json:"route" gorm:"size:200"` // rota do Gin, ex.: /api/v1/posts/:id
+	Path         string    `json:"path" gorm:"size:500"`
+	Query        string    `json:"query" gorm:"size:1000"`
+	Status       int       `json:"status"`
+	LatencyMs    int64     `json:"latency_ms"`
+	UserID       *uint     `json:"user_id"`
+	ClientIP     string    `json:"client_ip" gorm:"size:45"` // último octeto/grupos mascarados
+	UserAgent    string    `json:"user_agent" gorm:"size:500"`
+	ErrorMessage string    `json:"error_message" gorm:"size:1000"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+type RiskAction string
+
+const (
+	RiskActionRegister   RiskAction = "register"
+	RiskActionCreatePost RiskAction = "create_post"
+)
+
+// Sinais de automação detectados na requisição
+const (
+	RiskSignalHoneypot              = "honeypot"                // campo invisível preenchido
+	RiskSignalMissingUserAgent      = "missing_user_agent"      // sem User-Agent
+	RiskSignalAutomationUserAgent   = "automation_user_agent"   // User-Agent de ferramenta de automação
+	RiskSignalMissingAcceptLanguage = "missing_accept_language" // sem Accept-Language
+	RiskSignalFormTooFast           = "form_too_fast"           // formulário enviado rápido demais
+	RiskSignalInvalidFormTiming     = "invalid_form_timing"     // horário de abertura do formulário forjado
+)
+
+// RiskAssessment é a avaliação de risco de automação de um cadastro ou
+// publicação; só avaliações com algum sinal são gravadas, para análise da
+// equipe de abuso
+type RiskAssessment struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    *uint      `json:"user_id" gorm:"index"` // vazio para cadastros bloqueados
+	Action    RiskAction `json:"action" gorm:"size:30;not null;index"`
+	Score     int        `json:"score" gorm:"not null;index"` // 0 a 100
+	Signals   []string   `json:"signals" gorm:"serializer:json;type:text"`
+	IPAddress string     `json:"ip_address" gorm:"size:45"`
+	UserAgent string     `json:"user_agent" gorm:"size:500"`
+	Blocked   bool       `json:"blocked" gorm:"default:false"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// RouteLeg é o trecho entre duas paradas consecutivas
+type RouteLeg struct {
+	DistanceMeters  float64 `json:"distance_meters"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// RouteCache guarda os trechos calculados pelo provedor de rotas, indexados
+// pelo hash do modo e das coordenadas das paradas, para não repetir consultas
+// enquanto os locais do dia não mudarem
+type RouteCache struct {
+	Hash       string     `json:"hash" gorm:"primaryKey;size:64"`
+	Mode       string     `json:"mode" gorm:"size:10"`
+	Provider   string     `json:"provider" gorm:"size:20"`
+	Legs       []RouteLeg `json:"legs" gorm:"serializer:json"`
+	ComputedAt time.Time  `json:"computed_at"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import "time"
+
+// Seções da busca unificada
+const (
+	SearchTypeUsers       = "users"
+	SearchTypePosts       = "posts"
+	SearchTypeItineraries = "itineraries"
+)
+
+// Cada seção da busca traz os resultados e o cursor da próxima página só
+// dela; sem next_cursor não há mais resultados
+type UserSearchSection struct {
+	Items      []UserResponse `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type PostSearchSection struct {
+	Items      []PostResponse `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type ItinerarySearchSection struct {
+	Items      []ItineraryResponse `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// SearchResponse reúne as seções da busca unificada. Ao pedir a próxima
+// página de uma seção, só ela vem preenchida
+type SearchResponse struct {
+	Query       string                  `json:"query"`
+	Users       *UserSearchSection      `json:"users,omitempty"`
+	Posts       *PostSearchSection      `json:"posts,omitempty"`
+	Itineraries *ItinerarySearchSection `json:"itineraries,omitempty"`
+}
+
+// FacetCount é quantos resultados da busca têm um valor da faceta
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// RangeFacetCount é quantos resultados caem numa faixa, de Min a Max
+// inclusive; Max nulo é sem limite superior
+type RangeFacetCount struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+// ItinerarySearchFacets traz as contagens para os filtros da busca de
+// roteiros. Cada faceta é contada com os demais filtros aplicados, mas sem o
+// próprio, para mostrar as alternativas ao filtro escolhido. As faixas de
+// preço são na moeda Currency
+type ItinerarySearchFacets struct {
+	Categories   []FacetCount      `json:"categories"`
+	Countries    []FacetCount      `json:"countries"`
+	Difficulties []FacetCount      `json:"difficulties"`
+	Durations    []RangeFacetCount `json:"durations"`
+	PriceBands   []RangeFacetCount `json:"price_bands"`
+	Currency     string            `json:"currency"`
+}
+
+// SearchTerm conta quantas vezes um texto foi buscado, já normalizado (sem
+// acentos, em minúsculas); os mais buscados sobem nas sugestões
+type SearchTerm struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Term           string    `json:"term" gorm:"uniqueIndex;size:100;not null"`
+	SearchCount    int64     `json:"search_count" gorm:"default:0"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Hashtag é uma tag usada no texto dos posts, em minúsculas e sem o "#"
+type Hashtag struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Tag        string    `json:"tag" gorm:"uniqueIndex;size:100;not null"`
+	PostsCount int64     `json:"posts_count" gorm:"default:0"` // posts publicados com a tag
+	LastUsedAt time.Time `json:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type SearchSuggestionType string
+
+const (
+	SearchSuggestionDestination SearchSuggestionType = "destination"
+	SearchSuggestionHashtag     SearchSuggestionType = "hashtag"
+	SearchSuggestionUser        SearchSuggestionType = "user"
+)
+
+// SearchSuggestion é um item do autocomplete da barra de busca; Text é o que
+// o app coloca na busca ao escolher a sugestão
+type SearchSuggestion struct {
+	Type           SearchSuggestionType `json:"type"`
+	Text           string               `json:"text"`
+	Detail         string               `json:"detail,omitempty"` // estado e país do destino, nome do usuário
+	CountryCode    string               `json:"country_code,omitempty"`
+	UserID         uint                 `json:"user_id,omitempty"`
+	ProfilePicture string               `json:"profile_picture,omitempty"`
+}
+
+// SavedSearch é uma busca de roteiros guardada pelo usuário. Com alertas
+// ligados, um job avisa quando saem roteiros públicos novos que a atendem
+type SavedSearch struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	UserID      uint              `json:"user_id" gorm:"not null;index"`
+	Name        string            `json:"name" gorm:"size:150"` // rótulo exibido, ex.: "Japão, 7–10 dias, nature"
+	Query       string            `json:"query" gorm:"size:200"`
+	Category    ItineraryCategory `json:"category,omitempty" gorm:"size:30"`
+	Country     string            `json:"country,omitempty" gorm:"size:100"`
+	CountryID   *uint             `json:"country_id,omitempty"`
+	City        string            `json:"city,omitempty" gorm:"size:100"`
+	CityID      *uint             `json:"city_id,omitempty"`
+	MinDuration int               `json:"min_duration,omitempty"`
+	MaxDuration int               `json:"max_duration,omitempty"`
+	Alerts      bool              `json:"alerts" gorm:"default:true;index"`
+	// Roteiros publicados até aqui já foram verificados pelo job de alertas
+	LastCheckedAt  time.Time  `json:"-"`
+	LastNotifiedAt *time.Time `json:"last_notified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
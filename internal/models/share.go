@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// ItineraryShareLink é o link público de um roteiro, acessível sem login pelo
+// slug. O autor pode desativá-lo ou gerar outro slug, invalidando o anterior;
+// NoIndex pede aos buscadores que não indexem a página
+type ItineraryShareLink struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ItineraryID uint      `json:"itinerary_id" gorm:"not null;uniqueIndex"`
+	Slug        string    `json:"slug" gorm:"size:80;not null;uniqueIndex"`
+	Enabled     bool      `json:"enabled" gorm:"default:true"`
+	NoIndex     bool      `json:"noindex" gorm:"default:false"`
+	ViewsCount  int       `json:"views_count" gorm:"default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+}
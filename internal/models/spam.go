@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Sinais de spam detectados nos posts e nos follows
+const (
+	SpamSignalDuplicateBurst = "duplicate_burst" // o mesmo texto publicado várias vezes em pouco tempo
+	SpamSignalLinkHeavy      = "link_heavy"      // post feito quase só de links
+	SpamSignalFollowBurst    = "follow_burst"    // muitos follows em pouco tempo
+	SpamSignalFollowChurn    = "follow_churn"    // seguir e logo deixar de seguir as mesmas contas
+)
+
+// SpamLimited informa se a conta está com posts e follows bloqueados pela
+// detecção de spam em now
+func (u *User) SpamLimited(now time.Time) bool {
+	return u.SpamLimitedUntil != nil && now.Before(*u.SpamLimitedUntil)
+}
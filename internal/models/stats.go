@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// PlatformStats é um retrato dos números agregados da plataforma exibidos no
+// site institucional. É calculado periodicamente e o histórico é mantido
+type PlatformStats struct {
+	ID                uint      `json:"-" gorm:"primaryKey"`
+	PublicItineraries int64     `json:"public_itineraries"`
+	Destinations      int64     `json:"destinations"` // cidades com ao menos um roteiro público
+	Countries         int64     `json:"countries"`
+	CommunitySize     int64     `json:"community_size"` // contas ativas
+	Creators          int64     `json:"creators"`       // autores de roteiros públicos
+	ComputedAt        time.Time `json:"computed_at" gorm:"index"`
+}
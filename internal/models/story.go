@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Story é uma publicação efêmera visível aos seguidores por 24 horas; depois
+// de expirar fica arquivada e visível apenas para o autor
+type Story struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	AuthorID   uint           `json:"author_id" gorm:"not null;index"`
+	MediaID    uint           `json:"media_id" gorm:"not null"`
+	MediaURL   string         `json:"media_url" gorm:"not null"`
+	MediaType  MediaType      `json:"media_type" gorm:"size:10;not null"`
+	Caption    string         `json:"caption" gorm:"size:300"`
+	Location   string         `json:"location" gorm:"size:200"`
+	ViewsCount int            `json:"views_count" gorm:"default:0"`
+	ExpiresAt  time.Time      `json:"expires_at" gorm:"not null;index"`
+	ArchivedAt *time.Time     `json:"archived_at" gorm:"index"`
+	CreatedAt  time.Time      `json:"created_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+
+	Author User `json:"author" gorm:"foreignKey:AuthorID"`
+}
+
+// StoryView registra que um usuário viu o story
+type StoryView struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	StoryID   uint      `json:"story_id" gorm:"not null;uniqueIndex:idx_story_views_story_viewer"`
+	ViewerID  uint      `json:"viewer_id" gorm:"not null;uniqueIndex:idx_story_views_story_viewer"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Viewer User `json:"viewer" gorm:"foreignKey:ViewerID"`
+}
+
+type StoryResponse struct {
+	ID         uint       `json:"id"`
+	AuthorID   uint       `json:"author_id"`
+	MediaURL   string     `json:"media_url"`
+	MediaType  MediaType  `json:"media_type"`
+	Caption    string     `json:"caption"`
+	Location   string     `json:"location"`
+	ViewsCount int        `json:"views_count,omitempty"` // apenas para o autor
+	Viewed     bool       `json:"viewed"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// StoryGroup reúne os stories ativos de um autor para o carrossel
+type StoryGroup struct {
+	Author    UserResponse    `json:"author"`
+	Stories   []StoryResponse `json:"stories"`
+	HasUnseen bool            `json:"has_unseen"`
+	LatestAt  time.Time       `json:"latest_at"`
+}
+
+type StoryViewerResponse struct {
+	Viewer   UserResponse `json:"viewer"`
+	ViewedAt time.Time    `json:"viewed_at"`
+}
+
+func (s *Story) ToResponse(currentUserID uint) *StoryResponse {
+	response := &StoryResponse{
+		ID:         s.ID,
+		AuthorID:   s.AuthorID,
+		MediaURL:   s.MediaURL,
+		MediaType:  s.MediaType,
+		Caption:    s.Caption,
+		Location:   s.Location,
+		ExpiresAt:  s.ExpiresAt,
+		ArchivedAt: s.ArchivedAt,
+		CreatedAt:  s.CreatedAt,
+	}
+	if s.AuthorID == currentUserID {
+		response.ViewsCount = s.ViewsCount
+		response.Viewed = true
+	}
+	return response
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusPending SubscriptionStatus = "pending"
+	SubscriptionStatusPaid    SubscriptionStatus = "paid"
+	SubscriptionStatusFailed  SubscriptionStatus = "failed"
+)
+
+// Subscription é a compra de meses do plano guIA Plus. O período só é
+// definido quando o pagamento é confirmado e começa no fim do período pago
+// anterior, para que renovar antes do vencimento não perca dias. Os valores
+// são armazenados em centavos
+type Subscription struct {
+	ID                uint               `json:"id" gorm:"primaryKey"`
+	UserID            uint               `json:"user_id" gorm:"not null;index"`
+	Months            int                `json:"months" gorm:"not null"`
+	Amount            int64              `json:"amount" gorm:"not null"`
+	Currency          string             `json:"currency" gorm:"size:3;not null"`
+	Status            SubscriptionStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	Provider          string             `json:"provider" gorm:"size:20;not null"`
+	ProviderPaymentID string             `json:"provider_payment_id" gorm:"uniqueIndex;size:100"`
+	PeriodStart       *time.Time         `json:"period_start"`
+	PeriodEnd         *time.Time         `json:"period_end" gorm:"index"`
+	PaidAt            *time.Time         `json:"paid_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+type SubscriptionResponse struct {
+	ID          uint               `json:"id"`
+	Months      int                `json:"months"`
+	Amount      int64              `json:"amount"`
+	Currency    string             `json:"currency"`
+	Status      SubscriptionStatus `json:"status"`
+	PeriodStart *time.Time         `json:"period_start"`
+	PeriodEnd   *time.Time         `json:"period_end"`
+	PaidAt      *time.Time         `json:"paid_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// SubscriptionIntentResponse devolve a compra criada junto com o client
+// secret usado pelo app para concluir o pagamento no SDK do provedor
+type SubscriptionIntentResponse struct {
+	Subscription *SubscriptionResponse `json:"subscription"`
+	ClientSecret string                `json:"client_secret"`
+}
+
+// SubscriptionStatusResponse diz se o usuário tem o plano ativo agora e até
+// quando os meses já pagos cobrem
+type SubscriptionStatusResponse struct {
+	Active    bool       `json:"active"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (s *Subscription) ToResponse() *SubscriptionResponse {
+	return &SubscriptionResponse{
+		ID:          s.ID,
+		Months:      s.Months,
+		Amount:      s.Amount,
+		Currency:    s.Currency,
+		Status:      s.Status,
+		PeriodStart: s.PeriodStart,
+		PeriodEnd:   s.PeriodEnd,
+		PaidAt:      s.PaidAt,
+		CreatedAt:   s.CreatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// AccountStatus é a situação da conta: ativa, suspensa até uma data, banida
+// por tempo indeterminado ou desativada pelo próprio usuário
+type AccountStatus string
+
+const (
+	AccountStatusActive      AccountStatus = "active"
+	AccountStatusSuspended   AccountStatus = "suspended"
+	AccountStatusBanned      AccountStatus = "banned"
+	AccountStatusDeactivated AccountStatus = "deactivated"
+)
+
+// AccountStatus calcula a situação da conta em now; a suspensão já vencida
+// conta como ativa mesmo antes de a rotina de reativação passar por ela
+func (u *User) AccountStatus(now time.Time) AccountStatus {
+	if u.IsActive {
+		return AccountStatusActive
+	}
+	if u.SuspendedAt == nil {
+		return AccountStatusDeactivated
+	}
+	if u.SuspendedUntil == nil {
+		return AccountStatusBanned
+	}
+	if !now.Before(*u.SuspendedUntil) {
+		return AccountStatusActive
+	}
+	return AccountStatusSuspended
+}
+
+type AppealStatus string
+
+const (
+	AppealPending  AppealStatus = "pending"
+	AppealAccepted AppealStatus = "accepted"
+	AppealRejected AppealStatus = "rejected"
+	// Encerrado sem análise porque a restrição acabou antes (suspensão
+	// vencida ou retirada pela administração)
+	AppealClosed AppealStatus = "closed"
+)
+
+// AccountAppeal é o recurso do usuário suspenso ou banido contra a
+// restrição; cada conta tem no máximo um recurso em análise
+type AccountAppeal struct {
+	ID           uint         `json:"id" gorm:"primaryKey"`
+	UserID       uint         `json:"user_id" gorm:"not null;index;uniqueIndex:idx_account_appeals_pending,where:status = 'pending'"`
+	Status       AppealStatus `json:"status" gorm:"size:20;not null;default:'pending';index"`
+	Message      string       `json:"message" gorm:"size:2000"` // a versão do usuário
+	SuspendedAt  time.Time    `json:"suspended_at"`             // restrição contestada
+	ReviewedByID *uint        `json:"reviewed_by_id"`
+	ReviewNote   string       `json:"review_note" gorm:"size:1000"`
+	ReviewedAt   *time.Time   `json:"reviewed_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type AccountAppealResponse struct {
+	ID         uint               `json:"id"`
+	User       *AdminUserResponse `json:"user,omitempty"` // apenas na fila dos administradores
+	Status     AppealStatus       `json:"status"`
+	Message    string             `json:"message"`
+	ReviewNote string             `json:"review_note,omitempty"`
+	ReviewedAt *time.Time         `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+func (a *AccountAppeal) ToResponse() *AccountAppealResponse {
+	return &AccountAppealResponse{
+		ID:         a.ID,
+		Status:     a.Status,
+		Message:    a.Message,
+		ReviewNote: a.ReviewNote,
+		ReviewedAt: a.ReviewedAt,
+		CreatedAt:  a.CreatedAt,
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// TemplateUsage registra cada roteiro criado a partir de um modelo publicado
+// por uma empresa, para as métricas de uso do modelo
+type TemplateUsage struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TemplateID  uint      `json:"template_id" gorm:"not null;index"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	ItineraryID uint      `json:"itinerary_id" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+
+	// Relacionamentos
+	Template Itinerary `json:"-" gorm:"foreignKey:TemplateID"`
+	User     User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TemplateCompany é a identidade visual da empresa exibida no catálogo
+type TemplateCompany struct {
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	CompanyName    string `json:"company_name"`
+	ProfilePicture string `json:"profile_picture"`
+	Website        string `json:"website"`
+	IsVerified     bool   `json:"is_verified"`
+}
+
+type TemplateResponse struct {
+	Itinerary *ItineraryResponse `json:"itinerary"`
+	Company   TemplateCompany    `json:"company"`
+	UsesCount int64              `json:"uses_count"`
+}
+
+// TemplateMetrics resume o uso de um modelo da empresa
+type TemplateMetrics struct {
+	TemplateID     uint       `json:"template_id" gorm:"column:template_id"`
+	Title          string     `json:"title" gorm:"column:title"`
+	IsPublic       bool       `json:"is_public" gorm:"column:is_public"`
+	ViewsCount     int        `json:"views_count" gorm:"column:views_count"`
+	LikesCount     int        `json:"likes_count" gorm:"column:likes_count"`
+	UsesCount      int64      `json:"uses_count" gorm:"column:uses_count"`
+	UsesLast30Days int64      `json:"uses_last_30_days" gorm:"column:uses_last_30_days"`
+	UniqueUsers    int64      `json:"unique_users" gorm:"column:unique_users"`
+	LastUsedAt     *time.Time `json:"last_used_at" gorm:"column:last_used_at"`
+}
+
+func (u *User) ToTemplateCompany() TemplateCompany {
+	name := u.CompanyName
+	if name == "" {
+		name = u.Username
+	}
+	return TemplateCompany{
+		ID:             u.ID,
+		Username:       u.Username,
+		CompanyName:    name,
+		ProfilePicture: u.ProfilePicture,
+		Website:        u.Website,
+		IsVerified:     u.IsVerified,
+	}
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+)
+
+type TipStatus string
+
+const (
+	TipStatusPending   TipStatus = "pending"
+	TipStatusReview    TipStatus = "review" // pagamento autorizado aguardando revisão antifraude
+	TipStatusSucceeded TipStatus = "succeeded"
+	TipStatusFailed    TipStatus = "failed"
+	TipStatusRefunded  TipStatus = "refunded"
+)
+
+// Tip é uma gorjeta de apoio enviada a um criador de conteúdo. Os valores são
+// armazenados em centavos na moeda da cobrança
+type Tip struct {
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	SupporterID       uint       `json:"supporter_id" gorm:"not null;index"`
+	CreatorID         uint       `json:"creator_id" gorm:"not null;index"`
+	ItineraryID       *uint      `json:"itinerary_id" gorm:"index"`
+	Amount            int64      `json:"amount" gorm:"not null"`
+	PlatformFee       int64      `json:"platform_fee" gorm:"not null"`
+	Currency          string     `json:"currency" gorm:"size:3;not null"`
+	Message           string     `json:"message" gorm:"size:500"`
+	Status            TipStatus  `json:"status" gorm:"size:20;default:'pending';index"`
+	Provider          string     `json:"provider" gorm:"size:20;not null"`
+	ProviderPaymentID string     `json:"provider_payment_id" gorm:"uniqueIndex;size:100"`
+	FraudCheckID      *uint      `json:"fraud_check_id"`
+	PaidAt            *time.Time `json:"paid_at"`
+	RefundedAt        *time.Time `json:"refunded_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relacionamentos
+	Supporter User `json:"supporter" gorm:"foreignKey:SupporterID"`
+	Creator   User `json:"creator" gorm:"foreignKey:CreatorID"`
+}
+
+// PayoutAccount é a conta de recebimento do criador no provedor de pagamentos
+type PayoutAccount struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	UserID            uint      `json:"user_id" gorm:"uniqueIndex;not null"`
+	Provider          string    `json:"provider" gorm:"size:20;not null"`
+	ProviderAccountID string    `json:"provider_account_id" gorm:"size:100;not null"`
+	Country           string    `json:"country" gorm:"size:2"`
+	ChargesEnabled    bool      `json:"charges_enabled" gorm:"default:false"`
+	PayoutsEnabled    bool      `json:"payouts_enabled" gorm:"default:false"`
+	DetailsSubmitted  bool      `json:"details_submitted" gorm:"default:false"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type TipResponse struct {
+	ID          uint          `json:"id"`
+	Supporter   *UserResponse `json:"supporter,omitempty"`
+	Creator     *UserResponse `json:"creator,omitempty"`
+	ItineraryID *uint         `json:"itinerary_id"`
+	Amount      int64         `json:"amount"`
+	PlatformFee int64         `json:"platform_fee"`
+	NetAmount   int64         `json:"net_amount"`
+	Currency    string        `json:"currency"`
+	Message     string        `json:"message"`
+	Status      TipStatus     `json:"status"`
+	PaidAt      *time.Time    `json:"paid_at"`
+	RefundedAt  *time.Time    `json:"refunded_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// TipIntentResponse devolve a gorjeta criada junto com o client secret usado
+// pelo app para concluir o pagamento no SDK do provedor
+type TipIntentResponse struct {
+	Tip          *TipResponse `json:"tip"`
+	ClientSecret string       `json:"client_secret"`
+}
+
+type PayoutAccountResponse struct {
+	Provider         string    `json:"provider"`
+	Country          string    `json:"country"`
+	ChargesEnabled   bool      `json:"charges_enabled"`
+	PayoutsEnabled   bool      `json:"payouts_enabled"`
+	DetailsSubmitted bool      `json:"details_submitted"`
+	OnboardingURL    string    `json:"onboarding_url,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// CreatorEarnings resume as gorjetas recebidas em uma moeda no período
+type CreatorEarnings struct {
+	Currency    string `json:"currency"`
+	TipsCount   int64  `json:"tips_count"`
+	GrossAmount int64  `json:"gross_amount"`
+	PlatformFee int64  `json:"platform_fee"`
+	NetAmount   int64  `json:"net_amount"`
+}
+
+func (t *Tip) ToResponse() *TipResponse {
+	response := &TipResponse{
+		ID:          t.ID,
+		ItineraryID: t.ItineraryID,
+		Amount:      t.Amount,
+		PlatformFee: t.PlatformFee,
+		NetAmount:   t.Amount - t.PlatformFee,
+		Currency:    t.Currency,
+		Message:     t.Message,
+		Status:      t.Status,
+		PaidAt:      t.PaidAt,
+		RefundedAt:  t.RefundedAt,
+		CreatedAt:   t.CreatedAt,
+	}
+
+	if t.Supporter.ID != 0 {
+		response.Supporter = t.Supporter.ToResponse()
+	}
+	if t.Creator.ID != 0 {
+		response.Creator = t.Creator.ToResponse()
+	}
+
+	return response
+}
+
+func (a *PayoutAccount) ToResponse() *PayoutAccountResponse {
+	return &PayoutAccountResponse{
+		Provider:         a.Provider,
+		Country:          a.Country,
+		ChargesEnabled:   a.ChargesEnabled,
+		PayoutsEnabled:   a.PayoutsEnabled,
+		DetailsSubmitted: a.DetailsSubmitted,
+		CreatedAt:        a.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// PostTranslation guarda a tradução de um post para um idioma; SourceHash
+// identifica a versão do conteúdo traduzida e invalida o cache após edições
+type PostTranslation struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	PostID         uint      `json:"post_id" gorm:"not null;uniqueIndex:idx_post_translations_post_language"`
+	Language       string    `json:"language" gorm:"size:10;not null;uniqueIndex:idx_post_translations_post_language"`
+	SourceLanguage string    `json:"source_language" gorm:"size:10"`
+	SourceHash     string    `json:"-" gorm:"size:64;not null"`
+	Content        string    `json:"content" gorm:"type:text"`
+	Provider       string    `json:"provider" gorm:"size:30"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type PostTranslationResponse struct {
+	PostID         uint   `json:"post_id"`
+	Language       string `json:"language"`
+	SourceLanguage string `json:"source_language"`
+	Content        string `json:"content"`
+	Translated     bool   `json:"translated"` // false quando o post já está no idioma pedido
+	Provider       string `json:"provider,omitempty"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+)
+
+// ItineraryCompletion registra que o usuário fez a viagem de um roteiro;
+// alimenta o mapa de viagens, os desafios e a aba de viagens concluídas
+type ItineraryCompletion struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_itinerary_completions_user_itinerary"`
+	ItineraryID uint      `json:"itinerary_id" gorm:"not null;uniqueIndex:idx_itinerary_completions_user_itinerary;index"`
+	TripID      *uint     `json:"trip_id"`
+	TraveledAt  time.Time `json:"traveled_at" gorm:"type:date;not null;index"`
+	Notes       string    `json:"notes" gorm:"size:1000"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relacionamentos
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+}
+
+type ItineraryCompletionResponse struct {
+	ID          uint               `json:"id"`
+	ItineraryID uint               `json:"itinerary_id"`
+	TripID      *uint              `json:"trip_id"`
+	TraveledAt  string             `json:"traveled_at"` // YYYY-MM-DD
+	Notes       string             `json:"notes"`
+	CheckIns    int                `json:"check_ins"`
+	Itinerary   *ItineraryResponse `json:"itinerary,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func (c *ItineraryCompletion) ToResponse() *ItineraryCompletionResponse {
+	response := &ItineraryCompletionResponse{
+		ID:          c.ID,
+		ItineraryID: c.ItineraryID,
+		TripID:      c.TripID,
+		TraveledAt:  c.TraveledAt.Format("2006-01-02"),
+		Notes:       c.Notes,
+		CreatedAt:   c.CreatedAt,
+	}
+
+	if c.Itinerary.ID != 0 {
+		response.Itinerary = c.Itinerary.ToResponse()
+	}
+
+	return response
+}
+
+// LocationCheckIn é a passagem do usuário por um local do roteiro. Nome e
+// coordenadas são copiados do local, para o registro continuar no mapa se o
+// roteiro for editado
+type LocationCheckIn struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_location_check_ins_user_location"`
+	LocationID   uint      `json:"location_id" gorm:"not null;uniqueIndex:idx_location_check_ins_user_location"`
+	ItineraryID  uint      `json:"itinerary_id" gorm:"not null;index"`
+	LocationName string    `json:"location_name" gorm:"size:200"`
+	Latitude     *float64  `json:"latitude"`
+	Longitude    *float64  `json:"longitude"`
+	Photos       []string  `json:"photos" gorm:"serializer:json"`
+	Note         string    `json:"note" gorm:"size:500"`
+	CheckedInAt  time.Time `json:"checked_in_at"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relacionamentos
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// TravelMapPlace é uma cidade (ou país, sem cidade de referência) das
+// viagens concluídas do usuário, com as coordenadas da cidade quando conhecida
+type TravelMapPlace struct {
+	CountryID   *uint    `json:"country_id"`
+	CountryCode string   `json:"country_code"`
+	Country     string   `json:"country"`
+	CityID      *uint    `json:"city_id"`
+	City        string   `json:"city"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Trips       int      `json:"trips"`
+}
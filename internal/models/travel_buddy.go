@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TravelIntent é a intenção de viagem publicada por quem procura companhia
+// ("estarei em Lisboa de 10 a 20 de julho")
+type TravelIntent struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	UserID      uint              `json:"user_id" gorm:"not null;index"`
+	Country     string            `json:"country" gorm:"not null;size:100"`
+	City        string            `json:"city" gorm:"size:100"`
+	CountryCode string            `json:"country_code" gorm:"size:2;index"`
+	CityID      *uint             `json:"city_id" gorm:"index"`
+	StartDate   time.Time         `json:"start_date" gorm:"not null"`
+	EndDate     time.Time         `json:"end_date" gorm:"not null;index"`
+	Style       ItineraryCategory `json:"style" gorm:"size:20"`
+	Description string            `json:"description" gorm:"type:text"`
+	IsActive    bool              `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"-" gorm:"index"`
+
+	User User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+// TravelBuddyInterest registra o interesse de um viajante na intenção de outro
+type TravelBuddyInterest struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	IntentID       uint      `json:"intent_id" gorm:"not null;uniqueIndex:idx_buddy_interest"`
+	TargetIntentID uint      `json:"target_intent_id" gorm:"not null;uniqueIndex:idx_buddy_interest;index"`
+	UserID         uint      `json:"user_id" gorm:"not null"`
+	TargetUserID   uint      `json:"target_user_id" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TravelMatch é criado quando o interesse é mútuo; o par de intenções é
+// armazenado com o menor ID primeiro
+type TravelMatch struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	IntentID        uint      `json:"intent_id" gorm:"not null;uniqueIndex:idx_travel_match"`
+	MatchedIntentID uint      `json:"matched_intent_id" gorm:"not null;uniqueIndex:idx_travel_match"`
+	UserID          uint      `json:"user_id" gorm:"not null;index"`
+	MatchedUserID   uint      `json:"matched_user_id" gorm:"not null;index"`
+	ConversationID  uint      `json:"conversation_id"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	Intent        TravelIntent `json:"intent" gorm:"foreignKey:IntentID"`
+	MatchedIntent TravelIntent `json:"matched_intent" gorm:"foreignKey:MatchedIntentID"`
+}
+
+type TravelIntentResponse struct {
+	ID          uint              `json:"id"`
+	UserID      uint              `json:"user_id"`
+	User        *UserResponse     `json:"user,omitempty"`
+	Country     string            `json:"country"`
+	City        string            `json:"city"`
+	CountryCode string            `json:"country_code"`
+	StartDate   time.Time         `json:"start_date"`
+	EndDate     time.Time         `json:"end_date"`
+	Style       ItineraryCategory `json:"style"`
+	Description string            `json:"description"`
+	IsActive    bool              `json:"is_active"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// TravelBuddySuggestion é um viajante compatível sugerido para uma intenção
+type TravelBuddySuggestion struct {
+	Intent          TravelIntentResponse `json:"intent"`
+	Score           float64              `json:"score"`
+	OverlapDays     int                  `json:"overlap_days"`
+	SameCity        bool                 `json:"same_city"`
+	SameStyle       bool                 `json:"same_style"`
+	InterestedInYou bool                 `json:"interested_in_you"`
+}
+
+type TravelMatchResponse struct {
+	ID             uint                 `json:"id"`
+	Intent         TravelIntentResponse `json:"intent"`
+	MatchedIntent  TravelIntentResponse `json:"matched_intent"`
+	ConversationID uint                 `json:"conversation_id"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+func (t *TravelIntent) ToResponse() *TravelIntentResponse {
+	response := &TravelIntentResponse{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		Country:     t.Country,
+		City:        t.City,
+		CountryCode: t.CountryCode,
+		StartDate:   t.StartDate,
+		EndDate:     t.EndDate,
+		Style:       t.Style,
+		Description: t.Description,
+		IsActive:    t.IsActive,
+		CreatedAt:   t.CreatedAt,
+	}
+
+	if t.User.ID != 0 {
+		response.User = t.User.ToResponse()
+	}
+
+	return response
+}
+
+// ToResponse apresenta o match do ponto de vista do usuário informado,
+// com a intenção dele em Intent e a do outro viajante em MatchedIntent
+func (m *TravelMatch) ToResponse(userID uint) *TravelMatchResponse {
+	own, other := m.Intent, m.MatchedIntent
+	if m.UserID != userID {
+		own, other = other, own
+	}
+
+	return &TravelMatchResponse{
+		ID:             m.ID,
+		Intent:         *own.ToResponse(),
+		MatchedIntent:  *other.ToResponse(),
+		ConversationID: m.ConversationID,
+		CreatedAt:      m.CreatedAt,
+	}
+}
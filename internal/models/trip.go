@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TripStatus string
+
+const (
+	TripStatusUpcoming TripStatus = "upcoming"
+	TripStatusOngoing  TripStatus = "ongoing"
+	TripStatusPast     TripStatus = "past"
+)
+
+// Trip é a viagem de um usuário em datas reais, seguindo um roteiro: o dia 1
+// do roteiro cai em StartDate, o que dá horários concretos aos locais
+type Trip struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	UserID      uint           `json:"user_id" gorm:"not null;index:idx_trips_user_end"`
+	ItineraryID uint           `json:"itinerary_id" gorm:"not null;index"`
+	Title       string         `json:"title" gorm:"size:200"`
+	StartDate   time.Time      `json:"start_date" gorm:"type:date;not null;index"`
+	EndDate     time.Time      `json:"end_date" gorm:"type:date;not null;index:idx_trips_user_end"`
+	Notes       string         `json:"notes" gorm:"type:text"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relacionamentos
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+	Itinerary Itinerary `json:"-" gorm:"foreignKey:ItineraryID"`
+}
+
+type TripResponse struct {
+	ID          uint               `json:"id"`
+	UserID      uint               `json:"user_id"`
+	ItineraryID uint               `json:"itinerary_id"`
+	Title       string             `json:"title"`
+	StartDate   string             `json:"start_date"` // YYYY-MM-DD
+	EndDate     string             `json:"end_date"`   // YYYY-MM-DD
+	Notes       string             `json:"notes"`
+	Status      TripStatus         `json:"status"`
+	DaysUntil   int                `json:"days_until"`
+	Itinerary   *ItineraryResponse `json:"itinerary,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// ToResponse calcula o status e a contagem regressiva em relação a today
+// (data do calendário, sem horário)
+func (t *Trip) ToResponse(today time.Time) *TripResponse {
+	response := &TripResponse{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		ItineraryID: t.ItineraryID,
+		Title:       t.Title,
+		StartDate:   t.StartDate.Format("2006-01-02"),
+		EndDate:     t.EndDate.Format("2006-01-02"),
+		Notes:       t.Notes,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+
+	start := time.Date(t.StartDate.Year(), t.StartDate.Month(), t.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(t.EndDate.Year(), t.EndDate.Month(), t.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch {
+	case today.Before(start):
+		response.Status = TripStatusUpcoming
+		response.DaysUntil = int(start.Sub(today).Hours() / 24)
+	case today.After(end):
+		response.Status = TripStatusPast
+	default:
+		response.Status = TripStatusOngoing
+	}
+
+	if t.Itinerary.ID != 0 {
+		response.Itinerary = t.Itinerary.ToResponse()
+	}
+
+	return response
+}
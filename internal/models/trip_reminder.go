@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// TripReminderSetting guarda com quantos dias de antecedência (7 e/ou 1) a
+// viagem recebe lembretes; viagens sem registro usam os padrões e uma lista
+// vazia desativa os lembretes
+type TripReminderSetting struct {
+	TripID    uint      `json:"trip_id" gorm:"primaryKey;autoIncrement:false"`
+	Days      []int     `json:"days" gorm:"serializer:json;not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TripPackingItem é um item da lista de bagagem da viagem
+type TripPackingItem struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TripID    uint      `json:"trip_id" gorm:"not null;index"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	Packed    bool      `json:"packed" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TripPackingStatus resume a lista de bagagem
+type TripPackingStatus struct {
+	Total  int64 `json:"total"`
+	Packed int64 `json:"packed"`
+}
@@ -23,6 +23,7 @@ type User struct {
 	LastName         string         `json:"last_name" gorm:"size:50"`
 	Bio              string         `json:"bio" gorm:"size:500"`
 	ProfilePicture   string         `json:"profile_picture"`
+	CoverPhoto       string         `json:"cover_photo"`
 	UserType         UserType       `json:"user_type" gorm:"default:'normal'"`
 	IsVerified       bool           `json:"is_verified" gorm:"default:false"`
 	IsActive         bool           `json:"is_active" gorm:"default:true"`
@@ -34,6 +35,15 @@ type User struct {
 	FollowingCount   int            `json:"following_count" gorm:"default:0"`
 	PostsCount       int            `json:"posts_count" gorm:"default:0"`
 	ItinerariesCount int            `json:"itineraries_count" gorm:"default:0"`
+	MemoriesEnabled  bool           `json:"-" gorm:"default:true"`
+	Timezone         string         `json:"-" gorm:"size:50"`       // fuso IANA, usado no horário de silêncio
+	RiskScore        int            `json:"-" gorm:"default:0"`     // risco de automação medido no cadastro
+	LastSeenAt       *time.Time     `json:"-"`                      // fim da última conexão em tempo real
+	HidePresence     bool           `json:"-" gorm:"default:false"` // esconde o "online" e o "visto por último"
+	SuspendedAt      *time.Time     `json:"-"`                      // início da suspensão ou do banimento
+	SuspendedUntil   *time.Time     `json:"-" gorm:"index"`         // fim da suspensão; vazio no banimento
+	SuspensionReason string         `json:"-" gorm:"size:500"`      // motivo mostrado ao usuário
+	SpamLimitedUntil *time.Time     `json:"-"`                      // posts e follows bloqueados por suspeita de spam
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
@@ -57,6 +67,17 @@ type Follow struct {
 	Followed User `json:"followed" gorm:"foreignKey:FollowedID"`
 }
 
+// UserBlock é o bloqueio de um usuário por outro; enquanto existir, a
+// conversa direta entre os dois fica congelada
+type UserBlock struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BlockerID uint      `json:"blocker_id" gorm:"not null;uniqueIndex:idx_user_blocks_pair"`
+	BlockedID uint      `json:"blocked_id" gorm:"not null;uniqueIndex:idx_user_blocks_pair;index"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Blocked User `json:"blocked" gorm:"foreignKey:BlockedID"`
+}
+
 // UserResponse para retornar dados sem informações sensíveis
 type UserResponse struct {
 	ID               uint      `json:"id"`
@@ -66,6 +87,7 @@ type UserResponse struct {
 	LastName         string    `json:"last_name"`
 	Bio              string    `json:"bio"`
 	ProfilePicture   string    `json:"profile_picture"`
+	CoverPhoto       string    `json:"cover_photo"`
 	UserType         UserType  `json:"user_type"`
 	IsVerified       bool      `json:"is_verified"`
 	Location         string    `json:"location"`
@@ -76,6 +98,10 @@ type UserResponse struct {
 	PostsCount       int       `json:"posts_count"`
 	ItinerariesCount int       `json:"itineraries_count"`
 	CreatedAt        time.Time `json:"created_at"`
+
+	// Relação com o usuário autenticado, preenchida apenas em listagens que a exibem
+	IsFollowing *bool `json:"is_following,omitempty"`
+	FollowsYou  *bool `json:"follows_you,omitempty"`
 }
 
 func (u *User) ToResponse() *UserResponse {
@@ -87,6 +113,7 @@ func (u *User) ToResponse() *UserResponse {
 		LastName:         u.LastName,
 		Bio:              u.Bio,
 		ProfilePicture:   u.ProfilePicture,
+		CoverPhoto:       u.CoverPhoto,
 		UserType:         u.UserType,
 		IsVerified:       u.IsVerified,
 		Location:         u.Location,
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type VerificationStatus string
+
+const (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationApproved VerificationStatus = "approved"
+	VerificationRejected VerificationStatus = "rejected"
+)
+
+// VerificationRequest é o pedido do selo de conta verificada, analisado por
+// um administrador; cada usuário tem no máximo um pedido em análise
+type VerificationRequest struct {
+	ID           uint               `json:"id" gorm:"primaryKey"`
+	UserID       uint               `json:"user_id" gorm:"not null;index;uniqueIndex:idx_verification_requests_pending,where:status = 'pending'"`
+	Status       VerificationStatus `json:"status" gorm:"size:20;not null;default:'pending';index"`
+	Message      string             `json:"message" gorm:"size:1000"` // por que a conta deve ser verificada
+	ReviewedByID *uint              `json:"reviewed_by_id"`
+	ReviewNote   string             `json:"review_note" gorm:"size:1000"`
+	ReviewedAt   *time.Time         `json:"reviewed_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type VerificationRequestResponse struct {
+	ID         uint               `json:"id"`
+	User       *AdminUserResponse `json:"user,omitempty"` // apenas na fila dos administradores
+	Status     VerificationStatus `json:"status"`
+	Message    string             `json:"message"`
+	ReviewNote string             `json:"review_note,omitempty"`
+	ReviewedAt *time.Time         `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+func (v *VerificationRequest) ToResponse() *VerificationRequestResponse {
+	return &VerificationRequestResponse{
+		ID:         v.ID,
+		Status:     v.Status,
+		Message:    v.Message,
+		ReviewNote: v.ReviewNote,
+		ReviewedAt: v.ReviewedAt,
+		CreatedAt:  v.CreatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+type WeatherCondition string
+
+const (
+	WeatherClear        WeatherCondition = "clear"
+	WeatherPartlyCloudy WeatherCondition = "partly_cloudy"
+	WeatherCloudy       WeatherCondition = "cloudy"
+	WeatherFog          WeatherCondition = "fog"
+	WeatherDrizzle      WeatherCondition = "drizzle"
+	WeatherRain         WeatherCondition = "rain"
+	WeatherSnow         WeatherCondition = "snow"
+	WeatherThunderstorm WeatherCondition = "thunderstorm"
+	WeatherUnknown      WeatherCondition = "unknown"
+)
+
+// WeatherForecast é a previsão diária de uma célula da grade de coordenadas
+// (cerca de 10 km, o tamanho de uma cidade). Serve de cache para que os dias
+// de roteiros na mesma cidade e data não repitam consultas ao provedor
+type WeatherForecast struct {
+	Cell                     string           `json:"-" gorm:"primaryKey;size:20"`
+	Date                     time.Time        `json:"date" gorm:"type:date;primaryKey"`
+	Provider                 string           `json:"provider" gorm:"size:20"`
+	Condition                WeatherCondition `json:"condition" gorm:"size:20"`
+	Description              string           `json:"description" gorm:"size:100"`
+	TempMinC                 float64          `json:"temp_min_c"`
+	TempMaxC                 float64          `json:"temp_max_c"`
+	PrecipitationProbability *float64         `json:"precipitation_probability"` // %
+	PrecipitationMm          float64          `json:"precipitation_mm"`
+	WindSpeedKmh             float64          `json:"wind_speed_kmh"`
+	FetchedAt                time.Time        `json:"fetched_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+type WebhookProvider string
+
+const (
+	WebhookProviderStripe      WebhookProvider = "stripe"
+	WebhookProviderTranscoding WebhookProvider = "transcoding"
+	WebhookProviderPush        WebhookProvider = "push"
+)
+
+type WebhookEventStatus string
+
+const (
+	WebhookEventPending    WebhookEventStatus = "pending"
+	WebhookEventProcessing WebhookEventStatus = "processing"
+	WebhookEventProcessed  WebhookEventStatus = "processed"
+	WebhookEventIgnored    WebhookEventStatus = "ignored" // nenhum processador para o provedor
+	WebhookEventFailed     WebhookEventStatus = "failed"  // aguardando nova tentativa
+	WebhookEventDeadLetter WebhookEventStatus = "dead_letter"
+)
+
+// WebhookEvent é um callback recebido de um provedor externo. O par
+// provedor + ID do evento é único, então reenvios do provedor não são
+// processados duas vezes; eventos que esgotam as tentativas vão para a fila
+// de mensagens mortas até um admin reenviá-los
+type WebhookEvent struct {
+	ID            uint               `json:"id" gorm:"primaryKey"`
+	Provider      WebhookProvider    `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_webhook_events_provider_event"`
+	EventID       string             `json:"event_id" gorm:"size:255;not null;uniqueIndex:idx_webhook_events_provider_event"`
+	EventType     string             `json:"event_type" gorm:"size:100;index"`
+	Payload       string             `json:"payload" gorm:"type:text"`
+	Status        WebhookEventStatus `json:"status" gorm:"size:20;default:'pending';index"`
+	Attempts      int                `json:"attempts" gorm:"default:0"`
+	LastError     string             `json:"last_error,omitempty" gorm:"size:500"`
+	NextAttemptAt *time.Time         `json:"next_attempt_at,omitempty" gorm:"index"`
+	ProcessedAt   *time.Time         `json:"processed_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// YearReviewPhoto é uma das fotos mais curtidas do ano
+type YearReviewPhoto struct {
+	PostID     uint   `json:"post_id"`
+	URL        string `json:"url"`
+	LikesCount int    `json:"likes_count"`
+}
+
+// YearReviewStats reúne os números do resumo anual de um usuário
+type YearReviewStats struct {
+	PostsCount       int               `json:"posts_count"`
+	ItinerariesCount int               `json:"itineraries_count"`
+	Countries        []string          `json:"countries"`
+	CountriesCount   int               `json:"countries_count"`
+	KmTraveled       float64           `json:"km_traveled"` // estimativa pelas coordenadas dos posts
+	LikesReceived    int               `json:"likes_received"`
+	CommentsReceived int               `json:"comments_received"`
+	TopPhotos        []YearReviewPhoto `json:"top_photos"`
+	MostLikedPostID  *uint             `json:"most_liked_post_id"`
+}
+
+// YearReview é o resumo anual gerado para um usuário, com a imagem para compartilhar
+type YearReview struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	UserID      uint            `json:"user_id" gorm:"not null;uniqueIndex:idx_year_reviews_user_year"`
+	Year        int             `json:"year" gorm:"not null;uniqueIndex:idx_year_reviews_user_year"`
+	Stats       YearReviewStats `json:"stats" gorm:"serializer:json"`
+	ImageURL    string          `json:"image_url"`
+	ImagePath   string          `json:"-"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// YearReviewResponse é o payload compartilhável do resumo anual
+type YearReviewResponse struct {
+	Year          int             `json:"year"`
+	User          UserResponse    `json:"user"`
+	Stats         YearReviewStats `json:"stats"`
+	MostLikedPost *PostResponse   `json:"most_liked_post,omitempty"`
+	ImageURL      string          `json:"image_url"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+}
@@ -0,0 +1,361 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// Tipos das mensagens enviadas aos clientes conectados
+const (
+	MessageNotification = "notification"
+	MessageUnreadCount  = "notification.unread_count"
+	MessageFeedPost     = "feed.post"
+	MessageMediaStatus  = "media.status"
+	MessageReceipt      = "conversation.receipt"
+	MessageTyping       = "conversation.typing"
+	MessagePresence     = "presence"
+	MessagePing         = "ping"
+	// Enviada a quem reconecta com um cursor que não pode mais ser
+	// reproduzido: o cliente deve recarregar notificações e feed pela API
+	MessageResync = "resync"
+)
+
+const (
+	// Mensagens aguardando envio por conexão; um cliente lento que enche o
+	// buffer é desconectado em vez de atrasar os demais
+	clientBufferSize = 64
+	// Conexões simultâneas por usuário (abas, aparelhos); ao passar do
+	// limite a mais antiga é encerrada
+	maxClientsPerUser = 10
+	// Mensagens recentes guardadas por usuário para quem reconecta informar
+	// o último id recebido (Last-Event-ID) e receber o que perdeu
+	historySize = 100
+	historyTTL  = 5 * time.Minute
+)
+
+// Mensagem do cliente avisando que está digitando; as demais (o "pong")
+// só mantêm a conexão viva
+const ClientMessageTyping = "typing"
+
+// Message é o envelope entregue aos clientes. ID cresce a cada envio e serve
+// de cursor para a reconexão; pings e mensagens efêmeras (digitação,
+// presença) não têm id e não são reproduzidas
+type Message struct {
+	ID     uint64      `json:"id,omitempty"`
+	Type   string      `json:"type"`
+	Data   interface{} `json:"data,omitempty"`
+	SentAt time.Time   `json:"sent_at"`
+}
+
+// Client é uma conexão de um usuário, independente do transporte: quem a
+// registrou lê Messages() e encerra a conexão quando Done() fecha
+type Client struct {
+	UserID      uint
+	ConnectedAt time.Time
+
+	send chan Message
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *Client) Messages() <-chan Message { return c.send }
+
+// Done fecha quando o hub descarta o cliente (buffer cheio ou excesso de
+// conexões do usuário)
+func (c *Client) Done() <-chan struct{} { return c.done }
+
+func (c *Client) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// ClientMessage é o envelope recebido dos clientes pelo WebSocket
+type ClientMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// PresenceHandler é chamado quando o usuário abre a primeira conexão ou
+// fecha a última. As chamadas são assíncronas e podem chegar fora de ordem:
+// quem recebe deve consultar IsOnline em vez de confiar na transição
+type PresenceHandler func(userID uint)
+
+type HubInterface interface {
+	Register(userID uint) *Client
+	Unregister(client *Client)
+	DisconnectUser(userID uint)
+	SendToUser(userID uint, messageType string, data interface{})
+	SendToUsers(userIDs []uint, messageType string, data interface{})
+	SendEphemeral(userIDs []uint, messageType string, data interface{})
+	OnlineUsers() []uint
+	IsOnline(userID uint) bool
+	OnPresenceChange(handler PresenceHandler)
+	Replay(userID uint, afterID uint64) ([]Message, bool)
+}
+
+// userHistory são as últimas mensagens do usuário; dropped é o maior id já
+// descartado dela
+type userHistory struct {
+	messages []Message
+	dropped  uint64
+}
+
+// Hub mantém as conexões abertas de cada usuário e entrega as mensagens sem
+// bloquear quem as envia
+type Hub struct {
+	mu       sync.RWMutex
+	clients  map[uint][]*Client
+	presence PresenceHandler
+
+	historyMu sync.Mutex
+	history   map[uint]*userHistory
+	lastID    uint64
+	// Cursores abaixo de floor não podem ser reproduzidos: são de antes do
+	// hub subir ou de históricos já expirados
+	floor      uint64
+	lastPruned time.Time
+}
+
+func NewHub() HubInterface {
+	// Os ids partem do relógio para que cursores de antes de um restart
+	// fiquem abaixo de floor em vez de coincidirem com mensagens novas
+	start := uint64(time.Now().UnixNano())
+	return &Hub{
+		clients:    make(map[uint][]*Client),
+		history:    make(map[uint]*userHistory),
+		lastID:     start,
+		floor:      start,
+		lastPruned: time.Now(),
+	}
+}
+
+func (h *Hub) Register(userID uint) *Client {
+	client := &Client{
+		UserID:      userID,
+		ConnectedAt: time.Now(),
+		send:        make(chan Message, clientBufferSize),
+		done:        make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	first := len(h.clients[userID]) == 0
+	clients := append(h.clients[userID], client)
+	if len(clients) > maxClientsPerUser {
+		clients[0].close()
+		clients = clients[1:]
+	}
+	h.clients[userID] = clients
+	presence := h.presence
+	h.mu.Unlock()
+
+	if first && presence != nil {
+		go presence(userID)
+	}
+	return client
+}
+
+func (h *Hub) Unregister(client *Client) {
+	client.close()
+
+	h.mu.Lock()
+	last := h.remove(client)
+	presence := h.presence
+	h.mu.Unlock()
+
+	if last && presence != nil {
+		go presence(client.UserID)
+	}
+}
+
+// DisconnectUser encerra todas as conexões do usuário (conta suspensa ou
+// banida); a reconexão é barrada na autenticação
+func (h *Hub) DisconnectUser(userID uint) {
+	h.mu.Lock()
+	clients := h.clients[userID]
+	delete(h.clients, userID)
+	presence := h.presence
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		client.close()
+	}
+	if len(clients) > 0 && presence != nil {
+		go presence(userID)
+	}
+}
+
+// remove tira o cliente do registro e indica se era a última conexão do
+// usuário; chamado com o lock de escrita
+func (h *Hub) remove(client *Client) bool {
+	clients := h.clients[client.UserID]
+	found := false
+	for i, c := range clients {
+		if c == client {
+			clients = append(clients[:i:i], clients[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	if len(clients) == 0 {
+		delete(h.clients, client.UserID)
+		return true
+	}
+	h.clients[client.UserID] = clients
+	return false
+}
+
+// OnPresenceChange registra quem é avisado quando usuários entram e saem
+func (h *Hub) OnPresenceChange(handler PresenceHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.presence = handler
+}
+
+func (h *Hub) SendToUser(userID uint, messageType string, data interface{}) {
+	h.SendToUsers([]uint{userID}, messageType, data)
+}
+
+func (h *Hub) SendToUsers(userIDs []uint, messageType string, data interface{}) {
+	h.deliver(userIDs, h.record(userIDs, messageType, data))
+}
+
+// SendEphemeral entrega só a quem está conectado agora, sem numerar nem
+// guardar no histórico; para estados passageiros como a digitação
+func (h *Hub) SendEphemeral(userIDs []uint, messageType string, data interface{}) {
+	h.deliver(userIDs, Message{Type: messageType, Data: data, SentAt: time.Now()})
+}
+
+func (h *Hub) deliver(userIDs []uint, message Message) {
+	var slow []*Client
+	h.mu.RLock()
+	for _, userID := range userIDs {
+		for _, client := range h.clients[userID] {
+			select {
+			case client.send <- message:
+			default:
+				slow = append(slow, client)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+	var offline []uint
+	h.mu.Lock()
+	for _, client := range slow {
+		log.Printf("Conexão em tempo real do usuário %d descartada: mensagens acumuladas", client.UserID)
+		client.close()
+		if h.remove(client) {
+			offline = append(offline, client.UserID)
+		}
+	}
+	presence := h.presence
+	h.mu.Unlock()
+
+	if presence != nil {
+		for _, userID := range offline {
+			go presence(userID)
+		}
+	}
+}
+
+// record numera a mensagem e a guarda no histórico dos usuários
+func (h *Hub) record(userIDs []uint, messageType string, data interface{}) Message {
+	h.historyMu.Lock()
+	defer h.historyMu.Unlock()
+
+	h.lastID++
+	message := Message{ID: h.lastID, Type: messageType, Data: data, SentAt: time.Now()}
+
+	for _, userID := range userIDs {
+		history := h.history[userID]
+		if history == nil {
+			history = &userHistory{}
+			h.history[userID] = history
+		}
+		history.messages = append(history.messages, message)
+		if len(history.messages) > historySize {
+			history.dropped = history.messages[0].ID
+			history.messages = history.messages[1:]
+		}
+	}
+
+	if message.SentAt.Sub(h.lastPruned) >= historyTTL {
+		h.prune(message.SentAt)
+	}
+	return message
+}
+
+// prune descarta as mensagens mais antigas que historyTTL; chamado com
+// historyMu
+func (h *Hub) prune(now time.Time) {
+	h.lastPruned = now
+	for userID, history := range h.history {
+		expired := 0
+		for expired < len(history.messages) && now.Sub(history.messages[expired].SentAt) >= historyTTL {
+			expired++
+		}
+		if expired == 0 {
+			continue
+		}
+		if expired == len(history.messages) {
+			if last := history.messages[expired-1].ID; last > h.floor {
+				h.floor = last
+			}
+			delete(h.history, userID)
+			continue
+		}
+		history.dropped = history.messages[expired-1].ID
+		history.messages = append([]Message(nil), history.messages[expired:]...)
+	}
+}
+
+// Replay retorna as mensagens do usuário posteriores a afterID. O segundo
+// valor é false quando parte delas já saiu do histórico (ou o cursor é de
+// antes de um restart) e o cliente precisa recarregar pela API
+func (h *Hub) Replay(userID uint, afterID uint64) ([]Message, bool) {
+	h.historyMu.Lock()
+	defer h.historyMu.Unlock()
+
+	complete := afterID >= h.floor && afterID <= h.lastID
+	history := h.history[userID]
+	if history == nil {
+		return nil, complete
+	}
+	if afterID < history.dropped {
+		complete = false
+	}
+
+	var messages []Message
+	for _, message := range history.messages {
+		if message.ID > afterID {
+			messages = append(messages, message)
+		}
+	}
+	return messages, complete
+}
+
+// IsOnline indica se o usuário tem alguma conexão aberta
+func (h *Hub) IsOnline(userID uint) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID]) > 0
+}
+
+// OnlineUsers lista os usuários com ao menos uma conexão aberta
+func (h *Hub) OnlineUsers() []uint {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	users := make([]uint, 0, len(h.clients))
+	for userID := range h.clients {
+		users = append(users, userID)
+	}
+	return users
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type AdminRepositoryInterface interface {
+	GetUsers(filter AdminUserFilter, limit, offset int) ([]models.User, error)
+}
+
+// AdminUserFilter restringe a listagem de usuários dos administradores;
+// campos vazios não filtram
+type AdminUserFilter struct {
+	Query      string // parte do username, e-mail ou nome
+	UserType   models.UserType
+	Status     models.AccountStatus
+	IsVerified *bool
+}
+
+type AdminRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminRepository(db *gorm.DB) AdminRepositoryInterface {
+	return &AdminRepository{db: db}
+}
+
+// GetUsers lista os usuários, inclusive os suspensos, banidos e desativados, dos cadastros mais
+// recentes para os mais antigos
+func (r *AdminRepository) GetUsers(filter AdminUserFilter, limit, offset int) ([]models.User, error) {
+	var users []models.User
+	query := r.db.Model(&models.User{})
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where(
+			"LOWER(username) LIKE ? OR LOWER(email) LIKE ? OR LOWER(first_name || ' ' || last_name) LIKE ? OR LOWER(company_name) LIKE ?",
+			pattern, pattern, pattern, pattern,
+		)
+	}
+	if filter.UserType != "" {
+		query = query.Where("user_type = ?", filter.UserType)
+	}
+	switch filter.Status {
+	case models.AccountStatusActive:
+		query = query.Where("is_active = ?", true)
+	case models.AccountStatusSuspended:
+		query = query.Where("is_active = ? AND suspended_until IS NOT NULL", false)
+	case models.AccountStatusBanned:
+		query = query.Where("is_active = ? AND suspended_at IS NOT NULL AND suspended_until IS NULL", false)
+	case models.AccountStatusDeactivated:
+		query = query.Where("is_active = ? AND suspended_at IS NULL", false)
+	}
+	if filter.IsVerified != nil {
+		query = query.Where("is_verified = ?", *filter.IsVerified)
+	}
+
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChallengeRepositoryInterface interface {
+	Create(challenge *models.Challenge) error
+	GetByID(id uint) (*models.Challenge, error)
+	Update(challenge *models.Challenge) error
+	Delete(id uint) error
+	GetAll(limit, offset int) ([]models.Challenge, error)
+	GetRunning(now time.Time, limit, offset int) ([]models.Challenge, error)
+	GetRunningByAction(action models.ChallengeAction, now time.Time) ([]models.Challenge, error)
+	Enroll(enrollment *models.ChallengeEnrollment) error
+	Unenroll(challengeID, userID uint) error
+	GetEnrollment(challengeID, userID uint) (*models.ChallengeEnrollment, error)
+	GetEnrollmentsByUser(userID uint, challengeIDs []uint) ([]models.ChallengeEnrollment, error)
+	AddContribution(contribution *models.ChallengeContribution) (bool, error)
+	CompleteEnrollment(enrollment *models.ChallengeEnrollment, badge *models.UserBadge) error
+	GetLeaderboard(challengeID uint, limit, offset int) ([]models.ChallengeEnrollment, error)
+	GetBadgesByUser(userID uint) ([]models.UserBadge, error)
+}
+
+type ChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewChallengeRepository(db *gorm.DB) ChallengeRepositoryInterface {
+	return &ChallengeRepository{db: db}
+}
+
+func (r *ChallengeRepository) Create(challenge *models.Challenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *ChallengeRepository) GetByID(id uint) (*models.Challenge, error) {
+	var challenge models.Challenge
+	err := r.db.Where("id = ?", id).First(&challenge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *ChallengeRepository) Update(challenge *models.Challenge) error {
+	return r.db.Save(challenge).Error
+}
+
+func (r *ChallengeRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Challenge{}, id).Error
+}
+
+func (r *ChallengeRepository) GetAll(limit, offset int) ([]models.Challenge, error) {
+	var challenges []models.Challenge
+	err := r.db.Order("starts_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&challenges).Error
+	return challenges, err
+}
+
+func (r *ChallengeRepository) GetRunning(now time.Time, limit, offset int) ([]models.Challenge, error) {
+	var challenges []models.Challenge
+	err := r.db.Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, now, now).
+		Order("ends_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&challenges).Error
+	return challenges, err
+}
+
+func (r *ChallengeRepository) GetRunningByAction(action models.ChallengeAction, now time.Time) ([]models.Challenge, error) {
+	var challenges []models.Challenge
+	err := r.db.Where("action = ? AND is_active = ? AND starts_at <= ? AND ends_at > ?", action, true, now, now).
+		Find(&challenges).Error
+	return challenges, err
+}
+
+func (r *ChallengeRepository) Enroll(enrollment *models.ChallengeEnrollment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(enrollment).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Challenge{}).
+			Where("id = ?", enrollment.ChallengeID).
+			Update("participants_count", gorm.Expr("participants_count + 1")).Error
+	})
+}
+
+func (r *ChallengeRepository) Unenroll(challengeID, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+			Delete(&models.ChallengeEnrollment{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+			Delete(&models.ChallengeContribution{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Challenge{}).
+			Where("id = ?", challengeID).
+			Update("participants_count", gorm.Expr("participants_count - 1")).Error
+	})
+}
+
+func (r *ChallengeRepository) GetEnrollment(challengeID, userID uint) (*models.ChallengeEnrollment, error) {
+	var enrollment models.ChallengeEnrollment
+	err := r.db.Where("challenge_id = ? AND user_id = ?", challengeID, userID).First(&enrollment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *ChallengeRepository) GetEnrollmentsByUser(userID uint, challengeIDs []uint) ([]models.ChallengeEnrollment, error) {
+	var enrollments []models.ChallengeEnrollment
+	if len(challengeIDs) == 0 {
+		return enrollments, nil
+	}
+	err := r.db.Where("user_id = ? AND challenge_id IN ?", userID, challengeIDs).Find(&enrollments).Error
+	return enrollments, err
+}
+
+// AddContribution registra a atividade e incrementa o progresso da inscrição.
+// Retorna false quando a atividade já havia sido contabilizada.
+func (r *ChallengeRepository) AddContribution(contribution *models.ChallengeContribution) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(contribution)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+
+		return tx.Model(&models.ChallengeEnrollment{}).
+			Where("challenge_id = ? AND user_id = ?", contribution.ChallengeID, contribution.UserID).
+			Update("progress", gorm.Expr("progress + 1")).Error
+	})
+	return added, err
+}
+
+func (r *ChallengeRepository) CompleteEnrollment(enrollment *models.ChallengeEnrollment, badge *models.UserBadge) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ChallengeEnrollment{}).
+			Where("id = ? AND completed_at IS NULL", enrollment.ID).
+			Update("completed_at", enrollment.CompletedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Já concluído por outra atividade processada em paralelo
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(badge).Error
+	})
+}
+
+// GetLeaderboard ordena por progresso e, em caso de empate, por quem concluiu primeiro
+func (r *ChallengeRepository) GetLeaderboard(challengeID uint, limit, offset int) ([]models.ChallengeEnrollment, error) {
+	var enrollments []models.ChallengeEnrollment
+	err := r.db.Preload("User").
+		Where("challenge_id = ? AND progress > 0", challengeID).
+		Order("progress DESC").
+		Order("completed_at ASC NULLS LAST").
+		Order("updated_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+func (r *ChallengeRepository) GetBadgesByUser(userID uint) ([]models.UserBadge, error) {
+	var badges []models.UserBadge
+	err := r.db.Where("user_id = ?", userID).
+		Order("awarded_at DESC").
+		Find(&badges).Error
+	return badges, err
+}
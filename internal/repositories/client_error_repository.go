@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ClientErrorFilter struct {
+	Platform   models.ClientPlatform
+	AppVersion string
+	Since      time.Time
+}
+
+type ClientErrorRepositoryInterface interface {
+	Create(report *models.ClientErrorReport) error
+	GetGroups(filter ClientErrorFilter, limit, offset int) ([]models.ClientErrorGroup, error)
+	GetEndpointErrors(filter ClientErrorFilter, limit, offset int) ([]models.ClientEndpointErrors, error)
+	GetByFingerprint(fingerprint string, limit, offset int) ([]models.ClientErrorReport, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type ClientErrorRepository struct {
+	db *gorm.DB
+}
+
+func NewClientErrorRepository(db *gorm.DB) ClientErrorRepositoryInterface {
+	return &ClientErrorRepository{db: db}
+}
+
+func (r *ClientErrorRepository) Create(report *models.ClientErrorReport) error {
+	return r.db.Create(report).Error
+}
+
+func (r *ClientErrorRepository) filtered(filter ClientErrorFilter) *gorm.DB {
+	query := r.db.Model(&models.ClientErrorReport{}).Where("created_at >= ?", filter.Since)
+	if filter.Platform != "" {
+		query = query.Where("platform = ?", filter.Platform)
+	}
+	if filter.AppVersion != "" {
+		query = query.Where("app_version = ?", filter.AppVersion)
+	}
+	return query
+}
+
+// GetGroups agrupa os relatos por fingerprint, dos mais frequentes (estimados
+// pela amostragem) aos menos
+func (r *ClientErrorRepository) GetGroups(filter ClientErrorFilter, limit, offset int) ([]models.ClientErrorGroup, error) {
+	var groups []models.ClientErrorGroup
+	err := r.filtered(filter).
+		Select(`fingerprint, platform, MAX(level) AS level, MAX(error_type) AS error_type, MAX(message) AS message,
+			COUNT(*) AS reports, SUM(weight) AS estimated_count, COUNT(DISTINCT user_id) AS users,
+			COUNT(DISTINCT app_version) AS app_versions, MAX(app_version) AS latest_version,
+			MIN(created_at) AS first_seen, MAX(created_at) AS last_seen`).
+		Group("fingerprint, platform").
+		Order("estimated_count DESC, last_seen DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&groups).Error
+	return groups, err
+}
+
+func (r *ClientErrorRepository) GetEndpointErrors(filter ClientErrorFilter, limit, offset int) ([]models.ClientEndpointErrors, error) {
+	var endpoints []models.ClientEndpointErrors
+	err := r.filtered(filter).
+		Select(`api_endpoint, api_status, COUNT(*) AS reports, SUM(weight) AS estimated_count,
+			COUNT(DISTINCT user_id) AS users, COUNT(DISTINCT app_version) AS app_versions,
+			MIN(created_at) AS first_seen, MAX(created_at) AS last_seen`).
+		Where("api_endpoint <> ''").
+		Group("api_endpoint, api_status").
+		Order("estimated_count DESC, last_seen DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&endpoints).Error
+	return endpoints, err
+}
+
+func (r *ClientErrorRepository) GetByFingerprint(fingerprint string, limit, offset int) ([]models.ClientErrorReport, error) {
+	var reports []models.ClientErrorReport
+	err := r.db.Where("fingerprint = ?", fingerprint).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *ClientErrorRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.ClientErrorReport{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CollectionRepositoryInterface interface {
+	Create(collection *models.Collection) error
+	GetByID(id uint) (*models.Collection, error)
+	Update(collection *models.Collection) error
+	Delete(id uint) error
+	GetByUser(userID uint, limit, offset int) ([]models.Collection, error)
+	CountByUser(userID uint) (int64, error)
+	AddItem(collectionID, itineraryID uint) (bool, error)
+	RemoveItem(collectionID, itineraryID uint) (bool, error)
+	GetItineraries(collectionID, userID uint, limit, offset int) ([]models.Itinerary, error)
+}
+
+type CollectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) CollectionRepositoryInterface {
+	return &CollectionRepository{db: db}
+}
+
+func (r *CollectionRepository) Create(collection *models.Collection) error {
+	return r.db.Omit("User", "Items").Create(collection).Error
+}
+
+func (r *CollectionRepository) GetByID(id uint) (*models.Collection, error) {
+	var collection models.Collection
+	err := r.db.Where("id = ?", id).First(&collection).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *CollectionRepository) Update(collection *models.Collection) error {
+	return r.db.Model(collection).Select("name", "description").Updates(collection).Error
+}
+
+// Delete remove a coleção e os roteiros salvos nela
+func (r *CollectionRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Collection{}, id).Error
+	})
+}
+
+func (r *CollectionRepository) GetByUser(userID uint, limit, offset int) ([]models.Collection, error) {
+	var collections []models.Collection
+	err := r.db.Where("user_id = ?", userID).
+		Order("updated_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&collections).Error
+	return collections, err
+}
+
+func (r *CollectionRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Collection{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// AddItem salva o roteiro na coleção; retorna false se ele já estava salvo
+func (r *CollectionRepository) AddItem(collectionID, itineraryID uint) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		item := &models.CollectionItem{
+			CollectionID: collectionID,
+			ItineraryID:  itineraryID,
+		}
+		result := tx.Omit("Itinerary").
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(item)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		added = true
+		return tx.Model(&models.Collection{}).Where("id = ?", collectionID).
+			Updates(map[string]interface{}{
+				"items_count": gorm.Expr("items_count + 1"),
+				"updated_at":  gorm.Expr("NOW()"),
+			}).Error
+	})
+	return added, err
+}
+
+// RemoveItem tira o roteiro da coleção; retorna false se ele não estava salvo
+func (r *CollectionRepository) RemoveItem(collectionID, itineraryID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("collection_id = ? AND itinerary_id = ?", collectionID, itineraryID).
+			Delete(&models.CollectionItem{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		removed = true
+		return tx.Model(&models.Collection{}).Where("id = ?", collectionID).
+			Updates(map[string]interface{}{
+				"items_count": gorm.Expr("GREATEST(items_count - 1, 0)"),
+				"updated_at":  gorm.Expr("NOW()"),
+			}).Error
+	})
+	return removed, err
+}
+
+// GetItineraries lista os roteiros da coleção, dos salvos mais recentemente
+// para os mais antigos, omitindo os que foram excluídos ou deixaram de ser
+// visíveis para o usuário
+func (r *CollectionRepository) GetItineraries(collectionID, userID uint, limit, offset int) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	err := r.db.Preload("Author").
+		Joins("JOIN collection_items ON collection_items.itinerary_id = itineraries.id").
+		Where("collection_items.collection_id = ?", collectionID).
+		Where("itineraries.is_public = ? OR itineraries.author_id = ?", true, userID).
+		Order("collection_items.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&itineraries).Error
+	return itineraries, err
+}
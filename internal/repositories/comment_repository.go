@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentRepositoryInterface interface {
+	Create(comment *models.Comment) error
+	GetByID(id uint) (*models.Comment, error)
+	GetByPost(postID uint, limit, offset int) ([]models.Comment, error)
+	Delete(comment *models.Comment) error
+}
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepositoryInterface {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) Create(comment *models.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Post", "Author", "Parent", "Replies").Create(comment).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Post{}).Where("id = ?", comment.PostID).
+			Update("comments_count", gorm.Expr("comments_count + 1")).Error
+	})
+}
+
+func (r *CommentRepository) GetByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.Preload("Author").First(&comment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetByPost lista os comentários de primeiro nível com as respostas, dos mais
+// antigos para os mais recentes; os ocultados pela moderação ficam de fora
+func (r *CommentRepository) GetByPost(postID uint, limit, offset int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Preload("Author").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Where("hidden_at IS NULL").Order("created_at ASC, id ASC")
+		}).
+		Preload("Replies.Author").
+		Where("post_id = ? AND parent_id IS NULL AND hidden_at IS NULL", postID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&comments).Error
+	return comments, err
+}
+
+// Delete remove o comentário junto com as respostas e atualiza o contador do post
+func (r *CommentRepository) Delete(comment *models.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		replies := tx.Where("parent_id = ?", comment.ID).Delete(&models.Comment{})
+		if replies.Error != nil {
+			return replies.Error
+		}
+
+		if err := tx.Delete(&models.Comment{}, comment.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Post{}).Where("id = ?", comment.PostID).
+			Update("comments_count", gorm.Expr("GREATEST(comments_count - ?, 0)", replies.RowsAffected+1)).Error
+	})
+}
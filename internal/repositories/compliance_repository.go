@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ComplianceRepositoryInterface interface {
+	CreateRestrictions(restrictions []models.ContentRestriction, audits []models.ModerationAction) ([]models.ContentRestriction, error)
+	GetRestrictionByID(id uint) (*models.ContentRestriction, error)
+	DeleteRestriction(id uint, audit *models.ModerationAction) error
+	GetRestrictions(contentType models.RestrictedContentType, contentID uint, limit, offset int) ([]models.ContentRestriction, error)
+	GetRestrictedIDs(contentType models.RestrictedContentType, contentIDs []uint, countryCode string) (map[uint]bool, error)
+	RecordEnforcements(contentType models.RestrictedContentType, contentIDs []uint, countryCode string, date time.Time) error
+	GetTransparencyReport(from, to time.Time) ([]models.TransparencyReportEntry, error)
+}
+
+type ComplianceRepository struct {
+	db *gorm.DB
+}
+
+func NewComplianceRepository(db *gorm.DB) ComplianceRepositoryInterface {
+	return &ComplianceRepository{db: db}
+}
+
+// CreateRestrictions grava as restrições novas com sua auditoria; países já
+// restritos para o conteúdo são ignorados. Retorna as restrições criadas
+func (r *ComplianceRepository) CreateRestrictions(restrictions []models.ContentRestriction, audits []models.ModerationAction) ([]models.ContentRestriction, error) {
+	var created []models.ContentRestriction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range restrictions {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&restrictions[i])
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			created = append(created, restrictions[i])
+			if err := tx.Create(&audits[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+func (r *ComplianceRepository) GetRestrictionByID(id uint) (*models.ContentRestriction, error) {
+	var restriction models.ContentRestriction
+	err := r.db.Where("id = ?", id).First(&restriction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &restriction, nil
+}
+
+func (r *ComplianceRepository) DeleteRestriction(id uint, audit *models.ModerationAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ContentRestriction{}, id).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// GetRestrictions lista as restrições mais recentes; filtros zerados não
+// restringem a busca
+func (r *ComplianceRepository) GetRestrictions(contentType models.RestrictedContentType, contentID uint, limit, offset int) ([]models.ContentRestriction, error) {
+	query := r.db.Model(&models.ContentRestriction{})
+	if contentType != "" {
+		query = query.Where("content_type = ?", contentType)
+	}
+	if contentID != 0 {
+		query = query.Where("content_id = ?", contentID)
+	}
+
+	var restrictions []models.ContentRestriction
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&restrictions).Error
+	return restrictions, err
+}
+
+func (r *ComplianceRepository) GetRestrictedIDs(contentType models.RestrictedContentType, contentIDs []uint, countryCode string) (map[uint]bool, error) {
+	restricted := make(map[uint]bool)
+	if len(contentIDs) == 0 {
+		return restricted, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&models.ContentRestriction{}).
+		Where("content_type = ? AND country_code = ? AND content_id IN ?", contentType, countryCode, contentIDs).
+		Pluck("content_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		restricted[id] = true
+	}
+	return restricted, nil
+}
+
+// RecordEnforcements incrementa o contador diário de bloqueios de cada conteúdo
+func (r *ComplianceRepository) RecordEnforcements(contentType models.RestrictedContentType, contentIDs []uint, countryCode string, date time.Time) error {
+	if len(contentIDs) == 0 {
+		return nil
+	}
+
+	enforcements := make([]models.ComplianceEnforcement, 0, len(contentIDs))
+	for _, id := range contentIDs {
+		enforcements = append(enforcements, models.ComplianceEnforcement{
+			Date:        date,
+			CountryCode: countryCode,
+			ContentType: contentType,
+			ContentID:   id,
+			Count:       1,
+		})
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}, {Name: "country_code"}, {Name: "content_type"}, {Name: "content_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count": gorm.Expr("compliance_enforcements.count + 1"),
+		}),
+	}).Create(&enforcements).Error
+}
+
+// GetTransparencyReport agrega os bloqueios do período por país e tipo de
+// conteúdo
+func (r *ComplianceRepository) GetTransparencyReport(from, to time.Time) ([]models.TransparencyReportEntry, error) {
+	var entries []models.TransparencyReportEntry
+	err := r.db.Model(&models.ComplianceEnforcement{}).
+		Select("country_code, content_type, COUNT(DISTINCT content_id) AS contents_withheld, SUM(count) AS enforcements").
+		Where("date >= ? AND date <= ?", from, to).
+		Group("country_code, content_type").
+		Order("country_code, content_type").
+		Scan(&entries).Error
+	return entries, err
+}
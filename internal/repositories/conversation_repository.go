@@ -0,0 +1,350 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ConversationRepositoryInterface interface {
+	GetOrCreateDirect(directKey string, userIDs []uint) (*models.Conversation, error)
+	GetOrCreateTrip(itineraryID uint, title string, userIDs []uint) (*models.Conversation, bool, error)
+	GetByID(id uint) (*models.Conversation, error)
+	GetByItinerary(itineraryID uint) (*models.Conversation, error)
+	AddParticipants(conversationID uint, userIDs []uint) ([]uint, error)
+	RemoveParticipant(conversationID, userID uint) (bool, error)
+	UpdateTitle(conversationID uint, title string) error
+	GetByUser(userID uint, limit, offset int) ([]models.Conversation, error)
+	GetLastMessages(conversationIDs []uint) (map[uint]models.Message, error)
+	CreateMessage(message *models.Message) error
+	GetMessages(conversationID uint, limit, offset int) ([]models.Message, error)
+	GetMessage(conversationID, messageID uint) (*models.Message, error)
+	MarkDelivered(conversationID, userID, messageID uint) (bool, error)
+	MarkRead(conversationID, userID, messageID uint) (bool, error)
+	CountUnread(userID uint, conversationIDs []uint) (map[uint]int64, error)
+	CanAccessMedia(userID, mediaID uint) (bool, error)
+	CanModerateMedia(userID, mediaID uint) (bool, error)
+	SetDirectFrozen(directKey string, frozen bool) (bool, error)
+	FilterPresenceContacts(userID uint, candidateIDs []uint) ([]uint, error)
+}
+
+type ConversationRepository struct {
+	db *gorm.DB
+}
+
+func NewConversationRepository(db *gorm.DB) ConversationRepositoryInterface {
+	return &ConversationRepository{db: db}
+}
+
+// GetOrCreateDirect retorna a conversa direta identificada pela chave do par
+// de usuários, criando-a com os participantes caso ainda não exista
+func (r *ConversationRepository) GetOrCreateDirect(directKey string, userIDs []uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("direct_key = ?", directKey).First(&conversation).Error
+		if err == nil {
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		conversation = models.Conversation{
+			Type:      models.ConversationTypeDirect,
+			DirectKey: &directKey,
+		}
+		if err := tx.Create(&conversation).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, userID := range userIDs {
+			participant := &models.ConversationParticipant{
+				ConversationID: conversation.ID,
+				UserID:         userID,
+				JoinedAt:       now,
+			}
+			if err := tx.Create(participant).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(conversation.ID)
+}
+
+// GetOrCreateTrip retorna o grupo da viagem, criando-o com os membros
+// informados caso ainda não exista; o segundo valor indica se foi criado
+func (r *ConversationRepository) GetOrCreateTrip(itineraryID uint, title string, userIDs []uint) (*models.Conversation, bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		conversation := models.Conversation{
+			Type:        models.ConversationTypeTrip,
+			ItineraryID: &itineraryID,
+			Title:       title,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&conversation)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+
+		_, err := addConversationParticipants(tx, conversation.ID, userIDs)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	conversation, err := r.GetByItinerary(itineraryID)
+	return conversation, created, err
+}
+
+func (r *ConversationRepository) GetByID(id uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.Preload("Participants.User").
+		Where("id = ?", id).
+		First(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *ConversationRepository) GetByItinerary(itineraryID uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.Preload("Participants.User").
+		Where("itinerary_id = ?", itineraryID).
+		First(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// AddParticipants inclui os usuários na conversa e retorna os que ainda não
+// eram membros
+func (r *ConversationRepository) AddParticipants(conversationID uint, userIDs []uint) ([]uint, error) {
+	return addConversationParticipants(r.db, conversationID, userIDs)
+}
+
+func (r *ConversationRepository) RemoveParticipant(conversationID, userID uint) (bool, error) {
+	result := r.db.Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Delete(&models.ConversationParticipant{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ConversationRepository) UpdateTitle(conversationID uint, title string) error {
+	return r.db.Model(&models.Conversation{}).Where("id = ?", conversationID).Update("title", title).Error
+}
+
+func addConversationParticipants(db *gorm.DB, conversationID uint, userIDs []uint) ([]uint, error) {
+	var added []uint
+	now := time.Now()
+	for _, userID := range userIDs {
+		participant := &models.ConversationParticipant{
+			ConversationID: conversationID,
+			UserID:         userID,
+			JoinedAt:       now,
+		}
+		result := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(participant)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			added = append(added, userID)
+		}
+	}
+	return added, nil
+}
+
+func (r *ConversationRepository) GetByUser(userID uint, limit, offset int) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := r.db.Preload("Participants.User").
+		Where("id IN (SELECT conversation_id FROM conversation_participants WHERE user_id = ?)", userID).
+		Order("last_message_at DESC NULLS LAST").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&conversations).Error
+	return conversations, err
+}
+
+func (r *ConversationRepository) GetLastMessages(conversationIDs []uint) (map[uint]models.Message, error) {
+	lastMessages := make(map[uint]models.Message)
+	if len(conversationIDs) == 0 {
+		return lastMessages, nil
+	}
+
+	var messages []models.Message
+	err := r.db.Preload("Media").
+		Where("id IN (SELECT MAX(id) FROM messages WHERE conversation_id IN ? AND deleted_at IS NULL GROUP BY conversation_id)", conversationIDs).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, message := range messages {
+		lastMessages[message.ConversationID] = message
+	}
+	return lastMessages, nil
+}
+
+func (r *ConversationRepository) CreateMessage(message *models.Message) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Conversation{}).
+			Where("id = ?", message.ConversationID).
+			Update("last_message_at", message.CreatedAt).Error; err != nil {
+			return err
+		}
+
+		// Quem envia já leu a conversa até a própria mensagem
+		return tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ? AND last_read_message_id < ?", message.ConversationID, message.SenderID, message.ID).
+			Updates(map[string]interface{}{
+				"last_read_message_id":      message.ID,
+				"last_delivered_message_id": gorm.Expr("GREATEST(last_delivered_message_id, ?)", message.ID),
+				"last_read_at":              message.CreatedAt,
+			}).Error
+	})
+}
+
+func (r *ConversationRepository) GetMessages(conversationID uint, limit, offset int) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.Preload("Sender").
+		Preload("Media").
+		Where("conversation_id = ?", conversationID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *ConversationRepository) GetMessage(conversationID, messageID uint) (*models.Message, error) {
+	var message models.Message
+	err := r.db.Where("id = ? AND conversation_id = ?", messageID, conversationID).First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// MarkDelivered avança a confirmação de entrega do participante até
+// messageID; false quando ela já estava lá
+func (r *ConversationRepository) MarkDelivered(conversationID, userID, messageID uint) (bool, error) {
+	result := r.db.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND last_delivered_message_id < ?", conversationID, userID, messageID).
+		Update("last_delivered_message_id", messageID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkRead avança a confirmação de leitura do participante até messageID,
+// o que também confirma a entrega; false quando ela já estava lá
+func (r *ConversationRepository) MarkRead(conversationID, userID, messageID uint) (bool, error) {
+	result := r.db.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND last_read_message_id < ?", conversationID, userID, messageID).
+		Updates(map[string]interface{}{
+			"last_read_message_id":      messageID,
+			"last_delivered_message_id": gorm.Expr("GREATEST(last_delivered_message_id, ?)", messageID),
+			"last_read_at":              time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountUnread conta, por conversa, as mensagens dos outros participantes
+// depois da última lida pelo usuário; avisos do sistema não contam
+func (r *ConversationRepository) CountUnread(userID uint, conversationIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(conversationIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ConversationID uint
+		Count          int64
+	}
+	err := r.db.Table("messages").
+		Select("messages.conversation_id, COUNT(*) AS count").
+		Joins("JOIN conversation_participants ON conversation_participants.conversation_id = messages.conversation_id AND conversation_participants.user_id = ?", userID).
+		Where("messages.conversation_id IN ? AND messages.deleted_at IS NULL", conversationIDs).
+		Where("messages.id > conversation_participants.last_read_message_id AND messages.sender_id <> ? AND messages.kind <> ?", userID, models.MessageKindSystem).
+		Group("messages.conversation_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ConversationID] = row.Count
+	}
+	return counts, nil
+}
+
+// CanAccessMedia indica se o usuário participa de alguma conversa em que a
+// mídia foi enviada
+func (r *ConversationRepository) CanAccessMedia(userID, mediaID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Message{}).
+		Joins("JOIN conversation_participants ON conversation_participants.conversation_id = messages.conversation_id").
+		Where("messages.media_id = ? AND conversation_participants.user_id = ?", mediaID, userID).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CanModerateMedia indica se o usuário é admin e a mídia foi enviada numa
+// mensagem denunciada, o que libera o anexo para a moderação
+func (r *ConversationRepository) CanModerateMedia(userID, mediaID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.MessageReport{}).
+		Joins("JOIN users ON users.id = ? AND users.user_type = ?", userID, models.UserTypeAdmin).
+		Where("message_reports.media_id = ?", mediaID).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SetDirectFrozen congela ou descongela a conversa direta do par de usuários;
+// false quando não há conversa ou ela já estava assim
+func (r *ConversationRepository) SetDirectFrozen(directKey string, frozen bool) (bool, error) {
+	query := r.db.Model(&models.Conversation{}).Where("direct_key = ?", directKey)
+	var result *gorm.DB
+	if frozen {
+		result = query.Where("frozen_at IS NULL").Update("frozen_at", time.Now())
+	} else {
+		result = query.Where("frozen_at IS NOT NULL").Update("frozen_at", nil)
+	}
+	return result.RowsAffected > 0, result.Error
+}
+
+// FilterPresenceContacts retorna, dentre os candidatos, quem pode ver a
+// presença do usuário: participa com ele de alguma conversa não congelada,
+// não esconde a própria presença e não tem bloqueio com ele
+func (r *ConversationRepository) FilterPresenceContacts(userID uint, candidateIDs []uint) ([]uint, error) {
+	var contactIDs []uint
+	if len(candidateIDs) == 0 {
+		return contactIDs, nil
+	}
+
+	err := r.db.Table("conversation_participants AS contacts").
+		Distinct("contacts.user_id").
+		Joins("JOIN conversation_participants AS mine ON mine.conversation_id = contacts.conversation_id AND mine.user_id = ?", userID).
+		Joins("JOIN conversations ON conversations.id = contacts.conversation_id AND conversations.frozen_at IS NULL AND conversations.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = contacts.user_id AND users.hide_presence = ?", false).
+		Where("contacts.user_id IN ? AND contacts.user_id <> ?", candidateIDs, userID).
+		Where("NOT EXISTS (SELECT 1 FROM user_blocks WHERE (user_blocks.blocker_id = ? AND user_blocks.blocked_id = contacts.user_id) OR (user_blocks.blocker_id = contacts.user_id AND user_blocks.blocked_id = ?))", userID, userID).
+		Pluck("contacts.user_id", &contactIDs).Error
+	return contactIDs, err
+}
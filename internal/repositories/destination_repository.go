@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DestinationRepositoryInterface interface {
+	CreatePartner(partner *models.DestinationPartner) (bool, error)
+	GetPartnerByID(id uint) (*models.DestinationPartner, error)
+	DeletePartner(id uint) error
+	GetPartners(cityID uint, limit, offset int) ([]models.DestinationPartner, error)
+	IsPartner(userID, cityID uint) (bool, error)
+	CreatePromotion(promotion *models.DestinationPromotion) error
+	GetPromotionByID(id uint) (*models.DestinationPromotion, error)
+	UpdatePromotion(promotion *models.DestinationPromotion) error
+	DeletePromotion(id uint) error
+	GetActivePromotions(cityID uint, now time.Time, limit int) ([]models.DestinationPromotion, error)
+	GetPromotionsByCreator(creatorID uint, limit, offset int) ([]models.DestinationPromotion, error)
+	GetPromotionByProviderPaymentID(providerPaymentID string) (*models.DestinationPromotion, error)
+	StartPromotionPayment(promotion *models.DestinationPromotion, from models.PromotionPaymentStatus) (bool, error)
+	UpdatePromotionPaymentStatus(promotion *models.DestinationPromotion, from, to models.PromotionPaymentStatus) (bool, error)
+	RecordImpressions(promotionIDs []uint, date time.Time) error
+	GetDailyImpressions(promotionID uint) ([]models.PromotionImpression, error)
+}
+
+type DestinationRepository struct {
+	db *gorm.DB
+}
+
+func NewDestinationRepository(db *gorm.DB) DestinationRepositoryInterface {
+	return &DestinationRepository{db: db}
+}
+
+// CreatePartner retorna false quando a conta já é parceira do destino
+func (r *DestinationRepository) CreatePartner(partner *models.DestinationPartner) (bool, error) {
+	result := r.db.Omit("User", "City").Clauses(clause.OnConflict{DoNothing: true}).Create(partner)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *DestinationRepository) GetPartnerByID(id uint) (*models.DestinationPartner, error) {
+	var partner models.DestinationPartner
+	if err := r.db.Where("id = ?", id).First(&partner).Error; err != nil {
+		return nil, err
+	}
+	return &partner, nil
+}
+
+func (r *DestinationRepository) DeletePartner(id uint) error {
+	return r.db.Delete(&models.DestinationPartner{}, id).Error
+}
+
+func (r *DestinationRepository) GetPartners(cityID uint, limit, offset int) ([]models.DestinationPartner, error) {
+	var partners []models.DestinationPartner
+	query := r.db.Model(&models.DestinationPartner{})
+	if cityID != 0 {
+		query = query.Where("city_id = ?", cityID)
+	}
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&partners).Error
+	return partners, err
+}
+
+func (r *DestinationRepository) IsPartner(userID, cityID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.DestinationPartner{}).
+		Where("user_id = ? AND city_id = ?", userID, cityID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *DestinationRepository) CreatePromotion(promotion *models.DestinationPromotion) error {
+	return r.db.Omit("Itinerary").Create(promotion).Error
+}
+
+func (r *DestinationRepository) GetPromotionByID(id uint) (*models.DestinationPromotion, error) {
+	var promotion models.DestinationPromotion
+	err := r.db.Preload("Itinerary.Author").Where("id = ?", id).First(&promotion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *DestinationRepository) UpdatePromotion(promotion *models.DestinationPromotion) error {
+	return r.db.Model(promotion).
+		Select("Title", "Body", "ImageURL", "LinkURL", "SponsorName", "Position", "StartsAt", "EndsAt").
+		Updates(promotion).Error
+}
+
+func (r *DestinationRepository) DeletePromotion(id uint) error {
+	return r.db.Delete(&models.DestinationPromotion{}, id).Error
+}
+
+// GetActivePromotions retorna as promoções vigentes do destino; promoções de
+// parceiros ainda não pagas e roteiros promovidos que deixaram de ser
+// públicos não são exibidos
+func (r *DestinationRepository) GetActivePromotions(cityID uint, now time.Time, limit int) ([]models.DestinationPromotion, error) {
+	var promotions []models.DestinationPromotion
+	err := r.db.Preload("Itinerary.Author").
+		Joins("LEFT JOIN itineraries ON itineraries.id = destination_promotions.itinerary_id").
+		Where("destination_promotions.city_id = ? AND destination_promotions.starts_at <= ? AND destination_promotions.ends_at > ?", cityID, now, now).
+		Where("destination_promotions.payment_status IN ?", []models.PromotionPaymentStatus{models.PromotionPaymentNone, models.PromotionPaymentPaid}).
+		Where("destination_promotions.itinerary_id IS NULL OR (itineraries.is_public = ? AND itineraries.deleted_at IS NULL)", true).
+		Order("destination_promotions.position ASC, destination_promotions.starts_at DESC").
+		Limit(limit).
+		Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *DestinationRepository) GetPromotionsByCreator(creatorID uint, limit, offset int) ([]models.DestinationPromotion, error) {
+	var promotions []models.DestinationPromotion
+	query := r.db.Preload("Itinerary.Author")
+	if creatorID != 0 {
+		query = query.Where("creator_id = ?", creatorID)
+	}
+	err := query.Order("starts_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *DestinationRepository) GetPromotionByProviderPaymentID(providerPaymentID string) (*models.DestinationPromotion, error) {
+	var promotion models.DestinationPromotion
+	err := r.db.Where("provider_payment_id = ?", providerPaymentID).First(&promotion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// StartPromotionPayment grava a cobrança criada no provedor se a promoção
+// ainda estiver no status de origem, para que dois pagamentos simultâneos não
+// sejam registrados. Retorna se a transição foi aplicada
+func (r *DestinationRepository) StartPromotionPayment(promotion *models.DestinationPromotion, from models.PromotionPaymentStatus) (bool, error) {
+	result := r.db.Model(&models.DestinationPromotion{}).
+		Where("id = ? AND payment_status = ?", promotion.ID, from).
+		Updates(map[string]interface{}{
+			"payment_status":      promotion.PaymentStatus,
+			"amount":              promotion.Amount,
+			"currency":            promotion.Currency,
+			"provider":            promotion.Provider,
+			"provider_payment_id": promotion.ProviderPaymentID,
+			"fraud_check_id":      promotion.FraudCheckID,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdatePromotionPaymentStatus só altera promoções que ainda estão no status
+// de origem, evitando que uma confirmação repetida sobrescreva um estado final
+func (r *DestinationRepository) UpdatePromotionPaymentStatus(promotion *models.DestinationPromotion, from, to models.PromotionPaymentStatus) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{"payment_status": to}
+	if to == models.PromotionPaymentPaid {
+		updates["paid_at"] = now
+	}
+
+	result := r.db.Model(&models.DestinationPromotion{}).
+		Where("id = ? AND payment_status = ?", promotion.ID, from).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	promotion.PaymentStatus = to
+	if to == models.PromotionPaymentPaid {
+		promotion.PaidAt = &now
+	}
+	return true, nil
+}
+
+// RecordImpressions soma uma exibição ao contador diário e ao total de cada
+// promoção
+func (r *DestinationRepository) RecordImpressions(promotionIDs []uint, date time.Time) error {
+	if len(promotionIDs) == 0 {
+		return nil
+	}
+
+	impressions := make([]models.PromotionImpression, 0, len(promotionIDs))
+	for _, id := range promotionIDs {
+		impressions = append(impressions, models.PromotionImpression{
+			PromotionID: id,
+			Date:        date,
+			Impressions: 1,
+		})
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "promotion_id"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"impressions": gorm.Expr("promotion_impressions.impressions + 1"),
+			}),
+		}).Create(&impressions).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.DestinationPromotion{}).
+			Where("id IN ?", promotionIDs).
+			UpdateColumn("impressions", gorm.Expr("impressions + 1")).Error
+	})
+}
+
+func (r *DestinationRepository) GetDailyImpressions(promotionID uint) ([]models.PromotionImpression, error) {
+	var impressions []models.PromotionImpression
+	err := r.db.Where("promotion_id = ?", promotionID).
+		Order("date ASC").
+		Find(&impressions).Error
+	return impressions, err
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmailRepositoryInterface interface {
+	Create(message *models.EmailMessage) (bool, error)
+	GetByID(id uint) (*models.EmailMessage, error)
+	Claim(id uint) (bool, error)
+	UpdateResult(message *models.EmailMessage) error
+	GetDue(now time.Time, limit int) ([]uint, error)
+	ReleaseSending() error
+}
+
+type EmailRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailRepository(db *gorm.DB) EmailRepositoryInterface {
+	return &EmailRepository{db: db}
+}
+
+// Create enfileira o e-mail; retorna false se já existia um com a mesma chave
+// para o destinatário
+func (r *EmailRepository) Create(message *models.EmailMessage) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(message)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *EmailRepository) GetByID(id uint) (*models.EmailMessage, error) {
+	var message models.EmailMessage
+	if err := r.db.Where("id = ?", id).First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// Claim marca o e-mail como em envio apenas se ele ainda aguarda envio,
+// evitando que o worker e o agendador o enviem juntos
+func (r *EmailRepository) Claim(id uint) (bool, error) {
+	result := r.db.Model(&models.EmailMessage{}).
+		Where("id = ? AND status IN ?", id, []models.EmailStatus{models.EmailStatusPending, models.EmailStatusFailed}).
+		Updates(map[string]interface{}{
+			"status":   models.EmailStatusSending,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *EmailRepository) UpdateResult(message *models.EmailMessage) error {
+	return r.db.Model(message).
+		Select("Status", "LastError", "NextAttemptAt", "SentAt").
+		Updates(message).Error
+}
+
+// GetDue retorna os e-mails cuja próxima tentativa já venceu
+func (r *EmailRepository) GetDue(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.EmailMessage{}).
+		Where("status IN ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+			[]models.EmailStatus{models.EmailStatusPending, models.EmailStatusFailed}, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ReleaseSending devolve à fila os e-mails interrompidos por uma
+// reinicialização no meio do envio
+func (r *EmailRepository) ReleaseSending() error {
+	return r.db.Model(&models.EmailMessage{}).
+		Where("status = ?", models.EmailStatusSending).
+		Updates(map[string]interface{}{
+			"status":          models.EmailStatusPending,
+			"next_attempt_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepositoryInterface interface {
+	SaveRates(rates []models.ExchangeRate) error
+	GetRates() ([]models.ExchangeRate, error)
+}
+
+type ExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepositoryInterface {
+	return &ExchangeRateRepository{db: db}
+}
+
+func (r *ExchangeRateRepository) SaveRates(rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&rates).Error
+}
+
+func (r *ExchangeRateRepository) GetRates() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Find(&rates).Error
+	return rates, err
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExpenseRepositoryInterface interface {
+	Create(expense *models.Expense) error
+	GetByID(id uint) (*models.Expense, error)
+	Delete(id uint) error
+	GetByItinerary(itineraryID uint, category models.ExpenseCategory, limit, offset int) ([]models.Expense, error)
+	GetAllByItinerary(itineraryID uint) ([]models.Expense, error)
+	GetCollaborators(itineraryID uint) ([]models.User, error)
+	IsCollaborator(itineraryID, userID uint) (bool, error)
+}
+
+type ExpenseRepository struct {
+	db *gorm.DB
+}
+
+func NewExpenseRepository(db *gorm.DB) ExpenseRepositoryInterface {
+	return &ExpenseRepository{db: db}
+}
+
+func (r *ExpenseRepository) Create(expense *models.Expense) error {
+	return r.db.Omit(clause.Associations).Create(expense).Error
+}
+
+func (r *ExpenseRepository) GetByID(id uint) (*models.Expense, error) {
+	var expense models.Expense
+	err := r.db.Preload("Payer").Where("id = ?", id).First(&expense).Error
+	if err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func (r *ExpenseRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Expense{}, id).Error
+}
+
+func (r *ExpenseRepository) GetByItinerary(itineraryID uint, category models.ExpenseCategory, limit, offset int) ([]models.Expense, error) {
+	var expenses []models.Expense
+
+	query := r.db.Preload("Payer").Where("itinerary_id = ?", itineraryID)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	err := query.Order("spent_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&expenses).Error
+	return expenses, err
+}
+
+func (r *ExpenseRepository) GetAllByItinerary(itineraryID uint) ([]models.Expense, error) {
+	var expenses []models.Expense
+	err := r.db.Where("itinerary_id = ?", itineraryID).Order("spent_at, id").Find(&expenses).Error
+	return expenses, err
+}
+
+// GetCollaborators lista quem divide as despesas; espectadores ficam de fora
+func (r *ExpenseRepository) GetCollaborators(itineraryID uint) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Joins("JOIN itinerary_collaborators ON itinerary_collaborators.user_id = users.id").
+		Where("itinerary_collaborators.itinerary_id = ? AND itinerary_collaborators.role = ?", itineraryID, models.CollaboratorRoleCollaborator).
+		Order("itinerary_collaborators.created_at").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *ExpenseRepository) IsCollaborator(itineraryID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ItineraryCollaborator{}).
+		Where("itinerary_id = ? AND user_id = ? AND role = ?", itineraryID, userID, models.CollaboratorRoleCollaborator).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// addParticipant inclui o usuário no roteiro com o papel informado. Um
+// espectador pode virar colaborador, mas um colaborador nunca perde o papel;
+// retorna false quando nada mudou
+func addParticipant(db *gorm.DB, itineraryID, userID uint, role models.CollaboratorRole) (bool, error) {
+	participant := &models.ItineraryCollaborator{
+		ItineraryID: itineraryID,
+		UserID:      userID,
+		Role:        role,
+	}
+
+	onConflict := clause.OnConflict{DoNothing: true}
+	if role == models.CollaboratorRoleCollaborator {
+		onConflict = clause.OnConflict{
+			Columns:   []clause.Column{{Name: "itinerary_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"role": role}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: "itinerary_collaborators", Name: "role"}, Value: models.CollaboratorRoleViewer},
+			}},
+		}
+	}
+
+	result := db.Omit(clause.Associations).Clauses(onConflict).Create(participant)
+	return result.RowsAffected > 0, result.Error
+}
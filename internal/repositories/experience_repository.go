@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrSlotFull indica que o horário não tem vagas suficientes para a reserva
+var ErrSlotFull = errors.New("slot full")
+
+type ExperienceRepositoryInterface interface {
+	Create(experience *models.Experience) error
+	GetByID(id uint) (*models.Experience, error)
+	Update(experience *models.Experience) error
+	Delete(id uint) error
+	Search(countryCode, country, city string, limit, offset int) ([]models.Experience, error)
+	GetByGuide(guideID uint, limit, offset int) ([]models.Experience, error)
+	CreateSlots(slots []models.ExperienceSlot) error
+	GetSlotByID(id uint) (*models.ExperienceSlot, error)
+	GetSlots(experienceID uint, from, to time.Time) ([]models.ExperienceSlot, error)
+	CancelSlot(id uint) error
+	CreateBooking(booking *models.BookingRequest) error
+	GetBookingByID(id uint) (*models.BookingRequest, error)
+	UpdateBookingStatus(booking *models.BookingRequest, status models.BookingStatus) error
+	GetBookingsByUser(userID uint, limit, offset int) ([]models.BookingRequest, error)
+	GetBookingsByGuide(guideID uint, status models.BookingStatus, limit, offset int) ([]models.BookingRequest, error)
+}
+
+type ExperienceRepository struct {
+	db *gorm.DB
+}
+
+func NewExperienceRepository(db *gorm.DB) ExperienceRepositoryInterface {
+	return &ExperienceRepository{db: db}
+}
+
+func (r *ExperienceRepository) Create(experience *models.Experience) error {
+	return r.db.Omit("Guide").Create(experience).Error
+}
+
+func (r *ExperienceRepository) GetByID(id uint) (*models.Experience, error) {
+	var experience models.Experience
+	err := r.db.Preload("Guide").Where("id = ?", id).First(&experience).Error
+	if err != nil {
+		return nil, err
+	}
+	return &experience, nil
+}
+
+func (r *ExperienceRepository) Update(experience *models.Experience) error {
+	return r.db.Omit("Guide").Save(experience).Error
+}
+
+func (r *ExperienceRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Experience{}, id).Error
+}
+
+func (r *ExperienceRepository) Search(countryCode, country, city string, limit, offset int) ([]models.Experience, error) {
+	var experiences []models.Experience
+
+	query := r.db.Preload("Guide").Where("is_active = ?", true)
+	switch {
+	case countryCode != "":
+		query = query.Where("country_code = ?", countryCode)
+	case country != "":
+		query = query.Where("country ILIKE ?", "%"+country+"%")
+	}
+	if city != "" {
+		query = query.Where("city ILIKE ?", "%"+strings.TrimSpace(city)+"%")
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&experiences).Error
+	return experiences, err
+}
+
+func (r *ExperienceRepository) GetByGuide(guideID uint, limit, offset int) ([]models.Experience, error) {
+	var experiences []models.Experience
+	err := r.db.Where("guide_id = ?", guideID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&experiences).Error
+	return experiences, err
+}
+
+func (r *ExperienceRepository) CreateSlots(slots []models.ExperienceSlot) error {
+	return r.db.Create(&slots).Error
+}
+
+func (r *ExperienceRepository) GetSlotByID(id uint) (*models.ExperienceSlot, error) {
+	var slot models.ExperienceSlot
+	err := r.db.Where("id = ?", id).First(&slot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *ExperienceRepository) GetSlots(experienceID uint, from, to time.Time) ([]models.ExperienceSlot, error) {
+	var slots []models.ExperienceSlot
+	err := r.db.Where("experience_id = ? AND starts_at >= ? AND starts_at < ?", experienceID, from, to).
+		Order("starts_at ASC").
+		Find(&slots).Error
+	return slots, err
+}
+
+func (r *ExperienceRepository) CancelSlot(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ExperienceSlot{}).
+			Where("id = ?", id).
+			Update("is_cancelled", true).Error; err != nil {
+			return err
+		}
+
+		// Pedidos em aberto ou aceitos para o horário deixam de valer
+		return tx.Model(&models.BookingRequest{}).
+			Where("slot_id = ? AND status IN ?", id, []models.BookingStatus{models.BookingStatusPending, models.BookingStatusAccepted}).
+			Update("status", models.BookingStatusCancelled).Error
+	})
+}
+
+func (r *ExperienceRepository) CreateBooking(booking *models.BookingRequest) error {
+	return r.db.Omit("Experience", "Slot", "User").Create(booking).Error
+}
+
+func (r *ExperienceRepository) GetBookingByID(id uint) (*models.BookingRequest, error) {
+	var booking models.BookingRequest
+	err := r.db.Preload("Experience").
+		Preload("Slot").
+		Preload("User").
+		Where("id = ?", id).
+		First(&booking).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// UpdateBookingStatus altera o status do pedido reservando as vagas do
+// horário ao aceitar e liberando-as ao cancelar um pedido já aceito
+func (r *ExperienceRepository) UpdateBookingStatus(booking *models.BookingRequest, status models.BookingStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		switch {
+		case status == models.BookingStatusAccepted:
+			result := tx.Model(&models.ExperienceSlot{}).
+				Where("id = ? AND is_cancelled = ? AND booked_count + ? <= capacity", booking.SlotID, false, booking.Participants).
+				Update("booked_count", gorm.Expr("booked_count + ?", booking.Participants))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrSlotFull
+			}
+		case booking.Status == models.BookingStatusAccepted:
+			if err := tx.Model(&models.ExperienceSlot{}).
+				Where("id = ?", booking.SlotID).
+				Update("booked_count", gorm.Expr("booked_count - ?", booking.Participants)).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.BookingRequest{}).
+			Where("id = ?", booking.ID).
+			Update("status", status).Error
+	})
+}
+
+func (r *ExperienceRepository) GetBookingsByUser(userID uint, limit, offset int) ([]models.BookingRequest, error) {
+	var bookings []models.BookingRequest
+	err := r.db.Preload("Experience").
+		Preload("Slot").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *ExperienceRepository) GetBookingsByGuide(guideID uint, status models.BookingStatus, limit, offset int) ([]models.BookingRequest, error) {
+	var bookings []models.BookingRequest
+	query := r.db.Preload("Experience").
+		Preload("Slot").
+		Preload("User").
+		Where("guide_id = ?", guideID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&bookings).Error
+	return bookings, err
+}
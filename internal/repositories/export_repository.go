@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ExportRepositoryInterface interface {
+	Create(export *models.ItineraryExport) error
+	GetByID(id uint) (*models.ItineraryExport, error)
+	GetReusable(itineraryID, userID uint, format models.ExportFormat, version time.Time) (*models.ItineraryExport, error)
+	Update(export *models.ItineraryExport) error
+	GetUnfinished() ([]models.ItineraryExport, error)
+	GetOlderThan(before time.Time, limit int) ([]models.ItineraryExport, error)
+	Delete(id uint) error
+}
+
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) ExportRepositoryInterface {
+	return &ExportRepository{db: db}
+}
+
+func (r *ExportRepository) Create(export *models.ItineraryExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *ExportRepository) GetByID(id uint) (*models.ItineraryExport, error) {
+	var export models.ItineraryExport
+	err := r.db.Where("id = ?", id).First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetReusable busca uma exportação em andamento ou pronta da mesma versão do
+// roteiro, evitando gerar o arquivo de novo
+func (r *ExportRepository) GetReusable(itineraryID, userID uint, format models.ExportFormat, version time.Time) (*models.ItineraryExport, error) {
+	var export models.ItineraryExport
+	err := r.db.Where("itinerary_id = ? AND user_id = ? AND format = ? AND version = ?", itineraryID, userID, format, version).
+		Where("status <> ?", models.ExportStatusFailed).
+		Order("id DESC").
+		First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Update grava o estado e o arquivo gerado
+func (r *ExportRepository) Update(export *models.ItineraryExport) error {
+	return r.db.Select("status", "file_url", "file_path", "file_size", "error", "started_at", "finished_at").
+		Updates(export).Error
+}
+
+// GetUnfinished busca as exportações interrompidas, na ordem de criação
+func (r *ExportRepository) GetUnfinished() ([]models.ItineraryExport, error) {
+	var exports []models.ItineraryExport
+	err := r.db.Where("status IN ?", []models.ExportStatus{models.ExportStatusPending, models.ExportStatusRunning}).
+		Order("id ASC").
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *ExportRepository) GetOlderThan(before time.Time, limit int) ([]models.ItineraryExport, error) {
+	var exports []models.ItineraryExport
+	err := r.db.Where("created_at < ?", before).
+		Order("id ASC").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *ExportRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ItineraryExport{}, id).Error
+}
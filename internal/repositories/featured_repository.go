@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type FeaturedRepositoryInterface interface {
+	GetAll() ([]models.Itinerary, error)
+	SetFeatured(itineraryID uint, from, until *time.Time, order *int) error
+	Unfeature(itineraryID uint) error
+	Reorder(itineraryIDs []uint) error
+}
+
+type FeaturedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeaturedRepository(db *gorm.DB) FeaturedRepositoryInterface {
+	return &FeaturedRepository{db: db}
+}
+
+// GetAll retorna todos os destaques, inclusive agendados e expirados, para a
+// tela de curadoria
+func (r *FeaturedRepository) GetAll() ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	err := r.db.Preload("Author").
+		Where("is_featured = ?", true).
+		Order("featured_order ASC NULLS LAST, featured_from ASC NULLS FIRST, id").
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
+func (r *FeaturedRepository) SetFeatured(itineraryID uint, from, until *time.Time, order *int) error {
+	return r.db.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+		Updates(map[string]interface{}{
+			"is_featured":    true,
+			"featured_from":  from,
+			"featured_until": until,
+			"featured_order": order,
+		}).Error
+}
+
+func (r *FeaturedRepository) Unfeature(itineraryID uint) error {
+	return r.db.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+		Updates(map[string]interface{}{
+			"is_featured":    false,
+			"featured_from":  nil,
+			"featured_until": nil,
+			"featured_order": nil,
+		}).Error
+}
+
+// Reorder numera os roteiros informados a partir de 1; os demais destaques
+// ficam sem posição, depois deles
+func (r *FeaturedRepository) Reorder(itineraryIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Itinerary{}).
+			Where("is_featured = ?", true).
+			Update("featured_order", nil).Error; err != nil {
+			return err
+		}
+
+		for i, id := range itineraryIDs {
+			if err := tx.Model(&models.Itinerary{}).
+				Where("id = ?", id).
+				Update("featured_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeedSettingsRepositoryInterface interface {
+	Get(userID uint) (*models.FeedSettings, error)
+	Save(settings *models.FeedSettings) error
+}
+
+type FeedSettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedSettingsRepository(db *gorm.DB) FeedSettingsRepositoryInterface {
+	return &FeedSettingsRepository{db: db}
+}
+
+func (r *FeedSettingsRepository) Get(userID uint) (*models.FeedSettings, error) {
+	var settings models.FeedSettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *FeedSettingsRepository) Save(settings *models.FeedSettings) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content_mix", "include_suggested", "hide_reposts", "updated_at"}),
+	}).Create(settings).Error
+}
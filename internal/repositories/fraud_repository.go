@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FraudRepositoryInterface interface {
+	EnsureRules(rules []models.FraudRule) error
+	GetRules() ([]models.FraudRule, error)
+	GetRuleByCode(code models.FraudRuleCode) (*models.FraudRule, error)
+	UpdateRule(rule *models.FraudRule) error
+	CreateCheck(check *models.FraudCheck) error
+	GetCheckByID(id uint) (*models.FraudCheck, error)
+	AttachSubject(checkID, subjectID uint) error
+	GetRecentActivity(userID uint, subject models.FraudSubject, since time.Time) (int64, int64, error)
+	GetChecksByReviewStatus(status models.FraudReviewStatus, limit, offset int) ([]models.FraudCheck, error)
+	CompleteReview(check *models.FraudCheck, status models.FraudReviewStatus) (bool, error)
+}
+
+type FraudRepository struct {
+	db *gorm.DB
+}
+
+func NewFraudRepository(db *gorm.DB) FraudRepositoryInterface {
+	return &FraudRepository{db: db}
+}
+
+// EnsureRules cria as regras padrão que ainda não existem, sem alterar os
+// limites já ajustados pelos admins
+func (r *FraudRepository) EnsureRules(rules []models.FraudRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoNothing: true,
+	}).Create(&rules).Error
+}
+
+func (r *FraudRepository) GetRules() ([]models.FraudRule, error) {
+	var rules []models.FraudRule
+	err := r.db.Order("code").Find(&rules).Error
+	return rules, err
+}
+
+func (r *FraudRepository) GetRuleByCode(code models.FraudRuleCode) (*models.FraudRule, error) {
+	var rule models.FraudRule
+	err := r.db.Where("code = ?", code).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *FraudRepository) UpdateRule(rule *models.FraudRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *FraudRepository) CreateCheck(check *models.FraudCheck) error {
+	return r.db.Omit("User").Create(check).Error
+}
+
+func (r *FraudRepository) GetCheckByID(id uint) (*models.FraudCheck, error) {
+	var check models.FraudCheck
+	err := r.db.Preload("User").Where("id = ?", id).First(&check).Error
+	if err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+func (r *FraudRepository) AttachSubject(checkID, subjectID uint) error {
+	return r.db.Model(&models.FraudCheck{}).
+		Where("id = ?", checkID).
+		Update("subject_id", subjectID).Error
+}
+
+// GetRecentActivity conta as tentativas do usuário desde o instante informado
+// e soma seus valores, incluindo as bloqueadas
+func (r *FraudRepository) GetRecentActivity(userID uint, subject models.FraudSubject, since time.Time) (int64, int64, error) {
+	var result struct {
+		Attempts int64
+		Total    int64
+	}
+	err := r.db.Model(&models.FraudCheck{}).
+		Select("COUNT(*) AS attempts, COALESCE(SUM(amount), 0) AS total").
+		Where("user_id = ? AND subject = ? AND created_at >= ?", userID, subject, since).
+		Scan(&result).Error
+	return result.Attempts, result.Total, err
+}
+
+func (r *FraudRepository) GetChecksByReviewStatus(status models.FraudReviewStatus, limit, offset int) ([]models.FraudCheck, error) {
+	var checks []models.FraudCheck
+	err := r.db.Preload("User").
+		Where("review_status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&checks).Error
+	return checks, err
+}
+
+// CompleteReview encerra uma revisão pendente; retorna false se ela já tinha
+// sido decidida por outro admin
+func (r *FraudRepository) CompleteReview(check *models.FraudCheck, status models.FraudReviewStatus) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&models.FraudCheck{}).
+		Where("id = ? AND review_status = ?", check.ID, models.FraudReviewPending).
+		Updates(map[string]interface{}{
+			"review_status":  status,
+			"reviewed_by_id": check.ReviewedByID,
+			"review_note":    check.ReviewNote,
+			"reviewed_at":    now,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	check.ReviewStatus = status
+	check.ReviewedAt = &now
+	return true, nil
+}
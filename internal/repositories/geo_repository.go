@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GeoRepositoryInterface interface {
+	UpsertCountries(countries []models.GeoCountry, updateColumns []string) error
+	UpsertStates(states []models.GeoState) error
+	UpsertCities(cities []models.GeoCity) error
+	GetAllCountries() ([]models.GeoCountry, error)
+	GetCountryByCode(code string) (*models.GeoCountry, error)
+	SearchCountries(query string, limit int) ([]models.GeoCountry, error)
+	FindState(countryCode, searchName string) (*models.GeoState, error)
+	GetStatesByCountry(countryCode string) ([]models.GeoState, error)
+	FindCity(countryCode, stateCode, searchName string) (*models.GeoCity, error)
+	GetCityByID(id uint) (*models.GeoCity, error)
+	FindNearestCity(latitude, longitude float64) (*models.GeoCity, error)
+	SearchCities(query, countryCode string, limit int) ([]models.GeoCity, error)
+	CountCountries() (int64, error)
+}
+
+type GeoRepository struct {
+	db *gorm.DB
+}
+
+func NewGeoRepository(db *gorm.DB) GeoRepositoryInterface {
+	return &GeoRepository{db: db}
+}
+
+// UpsertCountries insere ou atualiza países pelo código ISO, alterando apenas
+// as colunas informadas (o seed embutido e o dump do GeoNames são
+// complementares e não devem sobrescrever os dados um do outro)
+func (r *GeoRepository) UpsertCountries(countries []models.GeoCountry, updateColumns []string) error {
+	if len(countries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns(append(updateColumns, "updated_at")),
+	}).CreateInBatches(countries, 500).Error
+}
+
+func (r *GeoRepository) UpsertStates(states []models.GeoState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "geo_name_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"country_code", "code", "name", "search_name", "updated_at"}),
+	}).CreateInBatches(states, 500).Error
+}
+
+func (r *GeoRepository) UpsertCities(cities []models.GeoCity) error {
+	if len(cities) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "geo_name_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"country_code", "state_code", "name", "ascii_name", "search_name",
+			"latitude", "longitude", "population", "timezone", "updated_at",
+		}),
+	}).CreateInBatches(cities, 1000).Error
+}
+
+func (r *GeoRepository) GetAllCountries() ([]models.GeoCountry, error) {
+	var countries []models.GeoCountry
+	err := r.db.Order("name ASC").Find(&countries).Error
+	return countries, err
+}
+
+func (r *GeoRepository) GetCountryByCode(code string) (*models.GeoCountry, error) {
+	var country models.GeoCountry
+	err := r.db.Where("code = ?", code).First(&country).Error
+	if err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+func (r *GeoRepository) SearchCountries(query string, limit int) ([]models.GeoCountry, error) {
+	var countries []models.GeoCountry
+	err := r.db.Where("search_name LIKE ?", query+"%").
+		Order("name ASC").
+		Limit(limit).
+		Find(&countries).Error
+	return countries, err
+}
+
+func (r *GeoRepository) FindState(countryCode, searchName string) (*models.GeoState, error) {
+	var state models.GeoState
+	err := r.db.Where("country_code = ? AND (search_name = ? OR LOWER(code) = ?)", countryCode, searchName, searchName).
+		First(&state).Error
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *GeoRepository) GetStatesByCountry(countryCode string) ([]models.GeoState, error) {
+	var states []models.GeoState
+	err := r.db.Where("country_code = ?", countryCode).Find(&states).Error
+	return states, err
+}
+
+func (r *GeoRepository) FindCity(countryCode, stateCode, searchName string) (*models.GeoCity, error) {
+	var city models.GeoCity
+	query := r.db.Where("country_code = ? AND search_name = ?", countryCode, searchName)
+	if stateCode != "" {
+		query = query.Where("state_code = ?", stateCode)
+	}
+	// Em caso de homônimos, preferir a cidade mais populosa
+	err := query.Order("population DESC").First(&city).Error
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (r *GeoRepository) GetCityByID(id uint) (*models.GeoCity, error) {
+	var city models.GeoCity
+	err := r.db.Where("id = ?", id).First(&city).Error
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (r *GeoRepository) FindNearestCity(latitude, longitude float64) (*models.GeoCity, error) {
+	var city models.GeoCity
+
+	// Restringir a uma caixa de ~1 grau para aproveitar os índices antes de
+	// ordenar pela distância aproximada
+	err := r.db.Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+		latitude-1, latitude+1, longitude-1, longitude+1).
+		Order(gorm.Expr("(latitude - ?) * (latitude - ?) + (longitude - ?) * (longitude - ?)",
+			latitude, latitude, longitude, longitude)).
+		First(&city).Error
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (r *GeoRepository) SearchCities(query, countryCode string, limit int) ([]models.GeoCity, error) {
+	var cities []models.GeoCity
+	db := r.db.Where("search_name LIKE ?", query+"%")
+	if countryCode != "" {
+		db = db.Where("country_code = ?", countryCode)
+	}
+	err := db.Order("population DESC").
+		Limit(limit).
+		Find(&cities).Error
+	return cities, err
+}
+
+func (r *GeoRepository) CountCountries() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.GeoCountry{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvitationRepositoryInterface interface {
+	Create(invitation *models.TripInvitation) error
+	GetByID(id uint) (*models.TripInvitation, error)
+	GetPending(itineraryID, inviteeID uint, email string) (*models.TripInvitation, error)
+	GetByItinerary(itineraryID uint, limit, offset int) ([]models.TripInvitation, error)
+	GetInbox(userID uint, email string, status models.InvitationStatus, limit, offset int) ([]models.TripInvitation, error)
+	Respond(invitation *models.TripInvitation, userID uint, status models.InvitationStatus) (bool, error)
+	Delete(id uint) error
+	HasDeclinedSince(itineraryID, inviteeID uint, since time.Time) (bool, error)
+	GetParticipants(itineraryID uint) ([]models.ItineraryCollaborator, error)
+	RemoveParticipant(itineraryID, userID uint) (bool, error)
+}
+
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepositoryInterface {
+	return &InvitationRepository{db: db}
+}
+
+func (r *InvitationRepository) Create(invitation *models.TripInvitation) error {
+	return r.db.Omit(clause.Associations).Create(invitation).Error
+}
+
+func (r *InvitationRepository) GetByID(id uint) (*models.TripInvitation, error) {
+	var invitation models.TripInvitation
+	err := r.db.Preload("Itinerary").Preload("Itinerary.Author").
+		Preload("Inviter").Preload("Invitee").
+		Where("id = ?", id).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetPending busca um convite pendente para o mesmo usuário ou e-mail; um dos
+// dois pode vir vazio
+func (r *InvitationRepository) GetPending(itineraryID, inviteeID uint, email string) (*models.TripInvitation, error) {
+	var invitation models.TripInvitation
+	err := r.db.Where("itinerary_id = ? AND status = ?", itineraryID, models.InvitationStatusPending).
+		Where("invitee_id = ? OR (email <> '' AND email = ?)", inviteeID, email).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) GetByItinerary(itineraryID uint, limit, offset int) ([]models.TripInvitation, error) {
+	var invitations []models.TripInvitation
+	err := r.db.Preload("Invitee").
+		Where("itinerary_id = ?", itineraryID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// GetInbox lista os convites recebidos pelo usuário, incluindo os enviados
+// para o e-mail dele antes do cadastro. Convites de roteiros excluídos somem
+func (r *InvitationRepository) GetInbox(userID uint, email string, status models.InvitationStatus, limit, offset int) ([]models.TripInvitation, error) {
+	var invitations []models.TripInvitation
+
+	query := r.db.Preload("Itinerary").Preload("Itinerary.Author").Preload("Inviter").
+		Joins("JOIN itineraries ON itineraries.id = trip_invitations.itinerary_id AND itineraries.deleted_at IS NULL").
+		Where("trip_invitations.invitee_id = ? OR (trip_invitations.invitee_id IS NULL AND trip_invitations.email = ?)", userID, email)
+
+	if status != "" {
+		query = query.Where("trip_invitations.status = ?", status)
+	}
+
+	err := query.Order("trip_invitations.created_at DESC, trip_invitations.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// Respond aceita ou recusa o convite; ao aceitar, o usuário entra na viagem
+// com o papel do convite. Retorna false se o convite já tinha sido respondido
+func (r *InvitationRepository) Respond(invitation *models.TripInvitation, userID uint, status models.InvitationStatus) (bool, error) {
+	responded := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.TripInvitation{}).
+			Where("id = ? AND status = ?", invitation.ID, models.InvitationStatusPending).
+			Updates(map[string]interface{}{
+				"status":       status,
+				"invitee_id":   userID,
+				"responded_at": now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if status == models.InvitationStatusAccepted {
+			if _, err := addParticipant(tx, invitation.ItineraryID, userID, invitation.Role); err != nil {
+				return err
+			}
+		}
+
+		responded = true
+		invitation.Status = status
+		invitation.InviteeID = &userID
+		invitation.RespondedAt = &now
+		return nil
+	})
+	return responded, err
+}
+
+func (r *InvitationRepository) Delete(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&models.TripInvitation{}).Error
+}
+
+// HasDeclinedSince indica se o usuário recusou um convite para a viagem a
+// partir de since
+func (r *InvitationRepository) HasDeclinedSince(itineraryID, inviteeID uint, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TripInvitation{}).
+		Where("itinerary_id = ? AND invitee_id = ? AND status = ? AND responded_at >= ?",
+			itineraryID, inviteeID, models.InvitationStatusDeclined, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetParticipants lista colaboradores e espectadores da viagem, na ordem em
+// que entraram
+func (r *InvitationRepository) GetParticipants(itineraryID uint) ([]models.ItineraryCollaborator, error) {
+	var participants []models.ItineraryCollaborator
+	err := r.db.Preload("User").
+		Where("itinerary_id = ?", itineraryID).
+		Order("created_at ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+func (r *InvitationRepository) RemoveParticipant(itineraryID, userID uint) (bool, error) {
+	result := r.db.Where("itinerary_id = ? AND user_id = ?", itineraryID, userID).
+		Delete(&models.ItineraryCollaborator{})
+	return result.RowsAffected > 0, result.Error
+}
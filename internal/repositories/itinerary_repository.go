@@ -1,28 +1,111 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/Ulpio/guIA-backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ItineraryRepositoryInterface interface {
 	Create(itinerary *models.Itinerary) error
 	GetByID(id uint) (*models.Itinerary, error)
+	GetPublicByIDs(ids []uint) ([]models.Itinerary, error)
 	Update(itinerary *models.Itinerary) error
 	Delete(id uint) error
 	GetByAuthor(authorID uint, limit, offset int) ([]models.Itinerary, error)
 	GetByCategory(category models.ItineraryCategory, limit, offset int) ([]models.Itinerary, error)
 	GetFeatured(limit, offset int) ([]models.Itinerary, error)
+	GetPopularByCity(cityID uint, limit, offset int) ([]models.Itinerary, error)
+	GetByCost(filter ItineraryCostFilter) ([]models.Itinerary, error)
 	GetTrending(limit, offset int) ([]models.Itinerary, error)
+	GetTrendingDestinations(since time.Time, limit int) ([]models.TrendingDestination, error)
 	SearchItineraries(query string, limit, offset int) ([]models.Itinerary, error)
+	SearchFiltered(filter ItinerarySearchFilter) ([]models.Itinerary, error)
+	GetSearchFacets(filter ItinerarySearchFilter, durations, priceBands []FacetRange) (*models.ItinerarySearchFacets, error)
 	RateItinerary(userID, itineraryID uint, rating int, comment string) error
 	GetUserRating(userID, itineraryID uint) (*models.ItineraryRating, error)
 	UpdateRating(userID, itineraryID uint, rating int, comment string) error
 	DeleteRating(userID, itineraryID uint) error
 	IncrementViews(id uint) error
+	LikeItinerary(userID, itineraryID uint) (bool, error)
+	UnlikeItinerary(userID, itineraryID uint) (bool, error)
+	GetLikedItineraryIDs(userID uint, itineraryIDs []uint) (map[uint]bool, error)
+	GetSavedItineraryIDs(userID uint, itineraryIDs []uint) (map[uint]bool, error)
 	GetSimilar(itineraryID uint, limit int) ([]models.Itinerary, error)
+	GetWithoutGeoReference(afterID uint, limit int) ([]models.Itinerary, error)
+	UpdateGeoReference(id uint, fields map[string]interface{}) error
+	GetPlaceCandidates(itinerary *models.Itinerary, limit int) ([]models.ItineraryLocation, error)
+	GetLocationByID(id uint) (*models.ItineraryLocation, error)
+	GetParticipantRole(itineraryID, userID uint) (models.CollaboratorRole, error)
+	GetParticipantIDs(itineraryID uint) ([]uint, error)
+	AddLocation(location *models.ItineraryLocation) error
+	AddDays(itineraryID uint, days []models.ItineraryDay) error
+	AddLocations(locations []models.ItineraryLocation) error
+	UpdateLocation(location *models.ItineraryLocation, columns []string) error
+	Clone(itineraryID, authorID uint) (*models.Itinerary, error)
+	AddPhotos(dayImages, locationImages map[uint][]string) error
+}
+
+// ItineraryCostFilter busca roteiros públicos comparando o custo estimado já
+// convertido para a moeda de quem consulta, pelas cotações de exchange_rates
+type ItineraryCostFilter struct {
+	Category     models.ItineraryCategory
+	Country      string
+	City         string
+	MinDuration  int
+	MaxDuration  int
+	Difficulty   int
+	IsFeatured   bool
+	MinCost      float64
+	MaxCost      float64
+	Currency     string
+	CurrencyRate float64 // unidades da moeda por USD; 0 compara apenas roteiros na mesma moeda
+	SortByCost   string  // "asc", "desc" ou vazio (mais recentes)
+	Limit        int
+	Offset       int
+}
+
+// ItinerarySearchFilter é a busca textual de roteiros com os filtros da
+// listagem; os custos são comparados como em ItineraryCostFilter. Area
+// restringe aos roteiros com algum local dentro dela, e basta ela ou o texto
+type ItinerarySearchFilter struct {
+	Query        string
+	Area         *GeoArea
+	Category     models.ItineraryCategory
+	Country      string
+	City         string
+	MinDuration  int
+	MaxDuration  int
+	Difficulty   int
+	MinCost      float64
+	MaxCost      float64
+	Currency     string
+	CurrencyRate float64
+	Limit        int
+	Offset       int
+}
+
+// FacetRange é uma faixa das facetas numéricas, de Min a Max inclusive; Max
+// 0 é sem limite superior
+type FacetRange struct {
+	Min float64
+	Max float64
 }
 
+// Facetas da busca de roteiros; cada uma é contada sem o próprio filtro
+const (
+	itineraryFacetCategory   = "category"
+	itineraryFacetCountry    = "country"
+	itineraryFacetDifficulty = "difficulty"
+	itineraryFacetDuration   = "duration"
+	itineraryFacetPrice      = "price"
+
+	// Países listados na faceta, dos com mais roteiros
+	itineraryCountryFacetLimit = 20
+)
+
 type ItineraryRepository struct {
 	db *gorm.DB
 }
@@ -59,6 +142,19 @@ func (r *ItineraryRepository) GetByID(id uint) (*models.Itinerary, error) {
 	return &itinerary, nil
 }
 
+// GetPublicByIDs retorna os roteiros públicos e visíveis dentre os IDs, sem
+// ordem definida
+func (r *ItineraryRepository) GetPublicByIDs(ids []uint) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	if len(ids) == 0 {
+		return itineraries, nil
+	}
+	err := r.db.Preload("Author").
+		Where("id IN ? AND is_public = ? AND hidden_at IS NULL", ids, true).
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
 func (r *ItineraryRepository) Update(itinerary *models.Itinerary) error {
 	return r.db.Save(itinerary).Error
 }
@@ -85,7 +181,7 @@ func (r *ItineraryRepository) Delete(id uint) error {
 func (r *ItineraryRepository) GetByAuthor(authorID uint, limit, offset int) ([]models.Itinerary, error) {
 	var itineraries []models.Itinerary
 	err := r.db.Preload("Author").
-		Where("author_id = ? AND is_public = ?", authorID, true).
+		Where("author_id = ? AND is_public = ? AND hidden_at IS NULL", authorID, true).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -96,7 +192,7 @@ func (r *ItineraryRepository) GetByAuthor(authorID uint, limit, offset int) ([]m
 func (r *ItineraryRepository) GetByCategory(category models.ItineraryCategory, limit, offset int) ([]models.Itinerary, error) {
 	var itineraries []models.Itinerary
 	err := r.db.Preload("Author").
-		Where("category = ? AND is_public = ?", category, true).
+		Where("category = ? AND is_public = ? AND hidden_at IS NULL", category, true).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -104,11 +200,114 @@ func (r *ItineraryRepository) GetByCategory(category models.ItineraryCategory, l
 	return itineraries, err
 }
 
+// GetPopularByCity lista os roteiros públicos de uma cidade de referência,
+// dos mais engajados aos menos
+func (r *ItineraryRepository) GetPopularByCity(cityID uint, limit, offset int) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	err := r.db.Preload("Author").
+		Where("city_id = ? AND is_public = ? AND hidden_at IS NULL", cityID, true).
+		Order("(views_count + likes_count * 2 + ratings_count * 3 + clones_count * 4) DESC, created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
+// itineraryCostExpr é o custo estimado convertido para a moeda pedida pela
+// cotação de exchange_rates; exige o join de itineraryComparableCost
+func itineraryCostExpr(currency string, rate float64) clause.Expr {
+	return clause.Expr{
+		SQL: `CASE WHEN UPPER(itineraries.currency) = ? THEN itineraries.estimated_cost
+			ELSE itineraries.estimated_cost / NULLIF(exchange_rates.rate, 0) * ? END`,
+		Vars: []interface{}{currency, rate},
+	}
+}
+
+// itineraryComparableCost junta as cotações e restringe aos roteiros cujo
+// custo pode ser convertido para a moeda pedida; sem a cotação dela, só os
+// roteiros na mesma moeda
+func itineraryComparableCost(currency string, rate float64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Joins("LEFT JOIN exchange_rates ON exchange_rates.currency = UPPER(itineraries.currency)")
+		if rate <= 0 {
+			return db.Where("UPPER(itineraries.currency) = ?", currency)
+		}
+		return db.Where("(UPPER(itineraries.currency) = ? OR exchange_rates.rate > 0)", currency)
+	}
+}
+
+func (r *ItineraryRepository) GetByCost(filter ItineraryCostFilter) ([]models.Itinerary, error) {
+	costExpr := itineraryCostExpr(filter.Currency, filter.CurrencyRate)
+
+	query := r.db.Preload("Author").
+		Scopes(itineraryComparableCost(filter.Currency, filter.CurrencyRate)).
+		Where("itineraries.is_public = ? AND itineraries.hidden_at IS NULL", true)
+
+	if filter.MinCost > 0 {
+		query = query.Where("? >= ?", costExpr, filter.MinCost)
+	}
+	if filter.MaxCost > 0 {
+		query = query.Where("? <= ?", costExpr, filter.MaxCost)
+	}
+	if filter.MinCost > 0 || filter.MaxCost > 0 {
+		query = query.Where("itineraries.estimated_cost IS NOT NULL")
+	}
+
+	if filter.Category != "" {
+		query = query.Where("itineraries.category = ?", filter.Category)
+	}
+	if filter.Country != "" {
+		query = query.Where("LOWER(itineraries.country) = LOWER(?)", filter.Country)
+	}
+	if filter.City != "" {
+		query = query.Where("LOWER(itineraries.city) = LOWER(?)", filter.City)
+	}
+	if filter.MinDuration > 0 {
+		query = query.Where("itineraries.duration >= ?", filter.MinDuration)
+	}
+	if filter.MaxDuration > 0 {
+		query = query.Where("itineraries.duration <= ?", filter.MaxDuration)
+	}
+	if filter.Difficulty > 0 {
+		query = query.Where("itineraries.difficulty = ?", filter.Difficulty)
+	}
+	if filter.IsFeatured {
+		now := time.Now()
+		query = query.Where("itineraries.is_featured = ?", true).
+			Where("itineraries.featured_from IS NULL OR itineraries.featured_from <= ?", now).
+			Where("itineraries.featured_until IS NULL OR itineraries.featured_until > ?", now)
+	}
+
+	switch filter.SortByCost {
+	case "asc":
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: "? ASC NULLS LAST, itineraries.created_at DESC", Vars: []interface{}{costExpr},
+		}})
+	case "desc":
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: "? DESC NULLS LAST, itineraries.created_at DESC", Vars: []interface{}{costExpr},
+		}})
+	default:
+		query = query.Order("itineraries.created_at DESC")
+	}
+
+	var itineraries []models.Itinerary
+	err := query.Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
+// GetFeatured retorna os destaques dentro da janela agendada, na ordem
+// definida pela curadoria
 func (r *ItineraryRepository) GetFeatured(limit, offset int) ([]models.Itinerary, error) {
 	var itineraries []models.Itinerary
+	now := time.Now()
 	err := r.db.Preload("Author").
-		Where("is_featured = ? AND is_public = ?", true, true).
-		Order("created_at DESC").
+		Where("is_featured = ? AND is_public = ? AND hidden_at IS NULL", true, true).
+		Where("featured_from IS NULL OR featured_from <= ?", now).
+		Where("featured_until IS NULL OR featured_until > ?", now).
+		Order("featured_order ASC NULLS LAST, created_at DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&itineraries).Error
@@ -120,8 +319,8 @@ func (r *ItineraryRepository) GetTrending(limit, offset int) ([]models.Itinerary
 
 	// Roteiros trending baseado em visualizações, curtidas e avaliações recentes
 	err := r.db.Preload("Author").
-		Where("is_public = ? AND created_at > NOW() - INTERVAL '30 days'", true).
-		Order("(views_count + likes_count * 2 + ratings_count * 3) DESC, average_rating DESC, created_at DESC").
+		Where("is_public = ? AND hidden_at IS NULL AND created_at > NOW() - INTERVAL '30 days'", true).
+		Order("(views_count + likes_count * 2 + ratings_count * 3 + clones_count * 4) DESC, average_rating DESC, created_at DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&itineraries).Error
@@ -129,19 +328,222 @@ func (r *ItineraryRepository) GetTrending(limit, offset int) ([]models.Itinerary
 	return itineraries, err
 }
 
+// GetTrendingDestinations agrupa por país e cidade os roteiros públicos criados
+// desde a data informada, ordenando pelo engajamento somado
+func (r *ItineraryRepository) GetTrendingDestinations(since time.Time, limit int) ([]models.TrendingDestination, error) {
+	var destinations []models.TrendingDestination
+	err := r.db.Model(&models.Itinerary{}).
+		Select(`country, city, MAX(cover_image) AS cover_image, COUNT(*) AS itineraries_count,
+			SUM(views_count + likes_count * 2 + ratings_count * 3 + clones_count * 4) AS score`).
+		Where("is_public = ? AND hidden_at IS NULL AND created_at > ? AND country <> ''", true, since).
+		Group("country, city").
+		Order("score DESC, itineraries_count DESC").
+		Limit(limit).
+		Scan(&destinations).Error
+	return destinations, err
+}
+
+// SearchItineraries busca no título, destino e descrição dos roteiros
+// públicos, dos mais relevantes para os menos
 func (r *ItineraryRepository) SearchItineraries(query string, limit, offset int) ([]models.Itinerary, error) {
+	return r.SearchFiltered(ItinerarySearchFilter{Query: query, Limit: limit, Offset: offset})
+}
+
+// SearchFiltered faz a busca com os filtros, por relevância. Sem texto, os
+// roteiros com o local mais próximo do centro do raio ou, num retângulo, os
+// mais populares
+func (r *ItineraryRepository) SearchFiltered(filter ItinerarySearchFilter) ([]models.Itinerary, error) {
 	var itineraries []models.Itinerary
-	searchQuery := "%" + query + "%"
-	err := r.db.Preload("Author").
-		Where("(title ILIKE ? OR description ILIKE ? OR city ILIKE ? OR country ILIKE ?) AND is_public = ?",
-			searchQuery, searchQuery, searchQuery, searchQuery, true).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
+	terms := searchTerms(filter.Query)
+	if terms == "" && filter.Area == nil {
+		return itineraries, nil
+	}
+
+	query := r.db.Preload("Author").
+		Scopes(itinerarySearchFilters(filter, ""))
+	switch {
+	case terms != "":
+		query = query.Scopes(orderBySearchRank("itineraries", terms))
+	case filter.Area.Box == nil:
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: `(SELECT MIN(?) FROM itinerary_locations
+				JOIN itinerary_days ON itinerary_days.id = itinerary_locations.day_id
+				WHERE itinerary_days.itinerary_id = itineraries.id) ASC, itineraries.created_at DESC`,
+			Vars: []interface{}{distanceFrom("itinerary_locations.geo_point", filter.Area)},
+		}})
+	default:
+		query = query.Order("itineraries.likes_count DESC, itineraries.created_at DESC")
+	}
+
+	err := query.Limit(filter.Limit).
+		Offset(filter.Offset).
 		Find(&itineraries).Error
 	return itineraries, err
 }
 
+// GetSearchFacets conta os resultados da busca por categoria, país,
+// dificuldade, faixa de duração e faixa de preço. Sem faixas de preço (moeda
+// sem cotação), a faceta de preço vem vazia
+func (r *ItineraryRepository) GetSearchFacets(filter ItinerarySearchFilter, durations, priceBands []FacetRange) (*models.ItinerarySearchFacets, error) {
+	facets := &models.ItinerarySearchFacets{
+		Categories:   []models.FacetCount{},
+		Countries:    []models.FacetCount{},
+		Difficulties: []models.FacetCount{},
+		Durations:    []models.RangeFacetCount{},
+		PriceBands:   []models.RangeFacetCount{},
+		Currency:     filter.Currency,
+	}
+	if searchTerms(filter.Query) == "" && filter.Area == nil {
+		return facets, nil
+	}
+
+	err := r.facetQuery(filter, itineraryFacetCategory).
+		Select("itineraries.category AS value, COUNT(*) AS count").
+		Group("itineraries.category").
+		Order("count DESC, value").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.facetQuery(filter, itineraryFacetCountry).
+		Select("itineraries.country AS value, COUNT(*) AS count").
+		Where("itineraries.country <> ''").
+		Group("itineraries.country").
+		Order("count DESC, value").
+		Limit(itineraryCountryFacetLimit).
+		Scan(&facets.Countries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.facetQuery(filter, itineraryFacetDifficulty).
+		Select("CAST(itineraries.difficulty AS TEXT) AS value, COUNT(*) AS count").
+		Where("itineraries.difficulty > 0").
+		Group("itineraries.difficulty").
+		Order("itineraries.difficulty").
+		Scan(&facets.Difficulties).Error
+	if err != nil {
+		return nil, err
+	}
+
+	facets.Durations, err = r.countRanges(r.facetQuery(filter, itineraryFacetDuration),
+		clause.Expr{SQL: "itineraries.duration"}, durations)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(priceBands) > 0 {
+		query := r.facetQuery(filter, itineraryFacetPrice).
+			Scopes(itineraryComparableCost(filter.Currency, filter.CurrencyRate)).
+			Where("itineraries.estimated_cost IS NOT NULL")
+		facets.PriceBands, err = r.countRanges(query, itineraryCostExpr(filter.Currency, filter.CurrencyRate), priceBands)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return facets, nil
+}
+
+func (r *ItineraryRepository) facetQuery(filter ItinerarySearchFilter, facet string) *gorm.DB {
+	return r.db.Model(&models.Itinerary{}).
+		Scopes(itinerarySearchFilters(filter, facet))
+}
+
+// countRanges conta quantas linhas caem em cada faixa; um valor na divisa
+// de duas faixas conta para a primeira
+func (r *ItineraryRepository) countRanges(query *gorm.DB, value clause.Expr, ranges []FacetRange) ([]models.RangeFacetCount, error) {
+	counts := make([]models.RangeFacetCount, len(ranges))
+	if len(ranges) == 0 {
+		return counts, nil
+	}
+
+	bucket := clause.Expr{SQL: "CASE"}
+	for i, rng := range ranges {
+		counts[i].Min = rng.Min
+		if rng.Max > 0 {
+			upper := rng.Max
+			counts[i].Max = &upper
+			bucket.SQL += " WHEN ? >= ? AND ? <= ? THEN ?"
+			bucket.Vars = append(bucket.Vars, value, rng.Min, value, rng.Max, i)
+		} else {
+			bucket.SQL += " WHEN ? >= ? THEN ?"
+			bucket.Vars = append(bucket.Vars, value, rng.Min, i)
+		}
+	}
+	bucket.SQL += " ELSE -1 END"
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := query.Select("? AS bucket, COUNT(*) AS count", bucket).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(counts) {
+			counts[row.Bucket].Count = row.Count
+		}
+	}
+	return counts, nil
+}
+
+// itinerarySearchFilters aplica a visibilidade, o texto, a área e os filtros
+// da busca, exceto o da faceta skip
+func itinerarySearchFilters(filter ItinerarySearchFilter, skip string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("itineraries.is_public = ? AND itineraries.hidden_at IS NULL", true)
+
+		if terms := searchTerms(filter.Query); terms != "" {
+			db = db.Scopes(matchSearch("itineraries", terms))
+		}
+		if filter.Area != nil {
+			db = db.Where(`EXISTS (SELECT 1 FROM itinerary_locations
+				JOIN itinerary_days ON itinerary_days.id = itinerary_locations.day_id
+				WHERE itinerary_days.itinerary_id = itineraries.id AND ?)`,
+				withinArea("itinerary_locations.geo_point", filter.Area))
+		}
+
+		if filter.Category != "" && skip != itineraryFacetCategory {
+			db = db.Where("itineraries.category = ?", filter.Category)
+		}
+		if filter.Country != "" && skip != itineraryFacetCountry {
+			db = db.Where("LOWER(itineraries.country) = LOWER(?)", filter.Country)
+		}
+		if filter.City != "" {
+			db = db.Where("LOWER(itineraries.city) = LOWER(?)", filter.City)
+		}
+		if filter.Difficulty > 0 && skip != itineraryFacetDifficulty {
+			db = db.Where("itineraries.difficulty = ?", filter.Difficulty)
+		}
+		if skip != itineraryFacetDuration {
+			if filter.MinDuration > 0 {
+				db = db.Where("itineraries.duration >= ?", filter.MinDuration)
+			}
+			if filter.MaxDuration > 0 {
+				db = db.Where("itineraries.duration <= ?", filter.MaxDuration)
+			}
+		}
+		if (filter.MinCost > 0 || filter.MaxCost > 0) && skip != itineraryFacetPrice {
+			costExpr := itineraryCostExpr(filter.Currency, filter.CurrencyRate)
+			db = db.Scopes(itineraryComparableCost(filter.Currency, filter.CurrencyRate)).
+				Where("itineraries.estimated_cost IS NOT NULL")
+			if filter.MinCost > 0 {
+				db = db.Where("? >= ?", costExpr, filter.MinCost)
+			}
+			if filter.MaxCost > 0 {
+				db = db.Where("? <= ?", costExpr, filter.MaxCost)
+			}
+		}
+		return db
+	}
+}
+
 func (r *ItineraryRepository) RateItinerary(userID, itineraryID uint, rating int, comment string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Criar a avaliação
@@ -157,7 +559,7 @@ func (r *ItineraryRepository) RateItinerary(userID, itineraryID uint, rating int
 		}
 
 		// Recalcular média e contador de avaliações
-		return r.updateItineraryRatingStats(tx, itineraryID)
+		return updateItineraryRatingStats(tx, itineraryID)
 	})
 }
 
@@ -185,14 +587,22 @@ func (r *ItineraryRepository) UpdateRating(userID, itineraryID uint, rating int,
 		}
 
 		// Recalcular média e contador de avaliações
-		return r.updateItineraryRatingStats(tx, itineraryID)
+		return updateItineraryRatingStats(tx, itineraryID)
 	})
 }
 
 func (r *ItineraryRepository) DeleteRating(userID, itineraryID uint) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Deletar a avaliação
-		err := tx.Where("user_id = ? AND itinerary_id = ?", userID, itineraryID).
+		// Deletar os votos de utilidade e a avaliação
+		ratingIDs := tx.Model(&models.ItineraryRating{}).Select("id").
+			Where("user_id = ? AND itinerary_id = ?", userID, itineraryID)
+		err := tx.Where("rating_id IN (?)", ratingIDs).
+			Delete(&models.ItineraryRatingVote{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ? AND itinerary_id = ?", userID, itineraryID).
 			Delete(&models.ItineraryRating{}).Error
 
 		if err != nil {
@@ -200,7 +610,7 @@ func (r *ItineraryRepository) DeleteRating(userID, itineraryID uint) error {
 		}
 
 		// Recalcular média e contador de avaliações
-		return r.updateItineraryRatingStats(tx, itineraryID)
+		return updateItineraryRatingStats(tx, itineraryID)
 	})
 }
 
@@ -209,32 +619,469 @@ func (r *ItineraryRepository) IncrementViews(id uint) error {
 		Update("views_count", gorm.Expr("views_count + 1")).Error
 }
 
+// LikeItinerary registra a curtida e atualiza o contador; retorna false se o
+// usuário já tinha curtido
+func (r *ItineraryRepository) LikeItinerary(userID, itineraryID uint) (bool, error) {
+	liked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		like := &models.ItineraryLike{
+			UserID:      userID,
+			ItineraryID: itineraryID,
+		}
+		result := tx.Omit("User", "Itinerary").
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(like)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		liked = true
+		return tx.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+			Update("likes_count", gorm.Expr("likes_count + 1")).Error
+	})
+	return liked, err
+}
+
+// UnlikeItinerary remove a curtida e atualiza o contador; retorna false se o
+// usuário não tinha curtido
+func (r *ItineraryRepository) UnlikeItinerary(userID, itineraryID uint) (bool, error) {
+	unliked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND itinerary_id = ?", userID, itineraryID).
+			Delete(&models.ItineraryLike{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		unliked = true
+		return tx.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+			Update("likes_count", gorm.Expr("GREATEST(likes_count - 1, 0)")).Error
+	})
+	return unliked, err
+}
+
+func (r *ItineraryRepository) GetLikedItineraryIDs(userID uint, itineraryIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(itineraryIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&models.ItineraryLike{}).
+		Where("user_id = ? AND itinerary_id IN ?", userID, itineraryIDs).
+		Pluck("itinerary_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// GetSavedItineraryIDs indica quais roteiros estão em alguma coleção do usuário
+func (r *ItineraryRepository) GetSavedItineraryIDs(userID uint, itineraryIDs []uint) (map[uint]bool, error) {
+	saved := make(map[uint]bool)
+	if len(itineraryIDs) == 0 {
+		return saved, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&models.CollectionItem{}).
+		Joins("JOIN collections ON collections.id = collection_items.collection_id").
+		Where("collections.user_id = ? AND collection_items.itinerary_id IN ?", userID, itineraryIDs).
+		Distinct().
+		Pluck("collection_items.itinerary_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		saved[id] = true
+	}
+	return saved, nil
+}
+
+// Pesos da pontuação de similaridade (somam 1)
+const (
+	similarityCategoryWeight    = 0.25
+	similarityDestinationWeight = 0.30
+	similarityDurationWeight    = 0.15
+	similarityCostWeight        = 0.10
+	similarityPlacesWeight      = 0.20
+
+	// Distância entre as cidades a partir da qual o destino não conta mais
+	similarityMaxDistanceKm = 500
+)
+
+// GetSimilar ordena os roteiros públicos por uma pontuação ponderada de
+// similaridade com o roteiro informado: mesma categoria, proximidade do
+// destino (distância entre as cidades de referência ou, sem elas, mesma
+// cidade/país), duração parecida, custo diário na mesma faixa (convertido
+// para dólar pelas cotações) e lugares em comum (google_place_id). Só entram
+// na disputa roteiros com ao menos um critério forte em comum
 func (r *ItineraryRepository) GetSimilar(itineraryID uint, limit int) ([]models.Itinerary, error) {
-	// Buscar roteiro original para obter categoria e localização
-	var originalItinerary models.Itinerary
-	if err := r.db.Where("id = ?", itineraryID).First(&originalItinerary).Error; err != nil {
+	var scored []struct {
+		ID    uint
+		Score float64
+	}
+
+	err := r.db.Raw(`
+		WITH src AS (
+			SELECT i.id, i.category, i.duration, i.city_id, i.country_id,
+				LOWER(i.city) AS city, LOWER(i.country) AS country,
+				c.latitude AS lat, c.longitude AS lon,
+				CASE WHEN UPPER(i.currency) = 'USD' THEN i.estimated_cost
+					ELSE i.estimated_cost / NULLIF(er.rate, 0) END / GREATEST(i.duration, 1) AS daily_cost
+			FROM itineraries i
+			LEFT JOIN geo_cities c ON c.id = i.city_id
+			LEFT JOIN exchange_rates er ON er.currency = UPPER(i.currency)
+			WHERE i.id = @id AND i.deleted_at IS NULL
+		),
+		src_places AS (
+			SELECT DISTINCT l.google_place_id
+			FROM itinerary_locations l
+			JOIN itinerary_days d ON d.id = l.day_id
+			WHERE d.itinerary_id = @id AND l.google_place_id <> ''
+		),
+		-- Só os roteiros que compartilham algum lugar com a origem, achados pelo
+		-- índice em google_place_id, em vez de agregar a tabela inteira
+		shared_places AS (
+			SELECT d.itinerary_id, COUNT(DISTINCT l.google_place_id) AS shared
+			FROM src_places sp
+			JOIN itinerary_locations l ON l.google_place_id = sp.google_place_id
+			JOIN itinerary_days d ON d.id = l.day_id
+			WHERE d.itinerary_id <> @id
+			GROUP BY d.itinerary_id
+		),
+		place_overlap AS (
+			SELECT sp.itinerary_id, sp.shared, COUNT(DISTINCT l.google_place_id) AS total
+			FROM shared_places sp
+			JOIN itinerary_days d ON d.itinerary_id = sp.itinerary_id
+			JOIN itinerary_locations l ON l.day_id = d.id
+			WHERE l.google_place_id <> ''
+			GROUP BY sp.itinerary_id, sp.shared
+		),
+		candidates AS (
+			SELECT i.id, i.average_rating, i.views_count,
+				CASE WHEN i.category = src.category THEN 1 ELSE 0 END AS category_score,
+				CASE
+					WHEN i.city_id IS NOT NULL AND i.city_id = src.city_id THEN 1
+					WHEN c.id IS NOT NULL AND src.lat IS NOT NULL THEN GREATEST(0, 1 - (
+						2 * 6371 * ASIN(LEAST(1, SQRT(
+							POWER(SIN(RADIANS(c.latitude - src.lat) / 2), 2) +
+							COS(RADIANS(src.lat)) * COS(RADIANS(c.latitude)) *
+							POWER(SIN(RADIANS(c.longitude - src.lon) / 2), 2)
+						)))) / @max_distance)
+					WHEN i.city <> '' AND LOWER(i.city) = src.city THEN 1
+					WHEN LOWER(i.country) = src.country THEN 0.3
+					ELSE 0
+				END AS destination_score,
+				1 - LEAST(ABS(i.duration - src.duration)::float / GREATEST(i.duration, src.duration, 1), 1) AS duration_score,
+				CASE WHEN cost.daily_cost > 0 AND src.daily_cost > 0
+					THEN LEAST(cost.daily_cost, src.daily_cost) / GREATEST(cost.daily_cost, src.daily_cost)
+					ELSE 0
+				END AS cost_score,
+				COALESCE(po.shared::float / NULLIF(LEAST(po.total, (SELECT COUNT(*) FROM src_places)), 0), 0) AS places_score
+			FROM itineraries i
+			CROSS JOIN src
+			LEFT JOIN geo_cities c ON c.id = i.city_id
+			LEFT JOIN exchange_rates er ON er.currency = UPPER(i.currency)
+			LEFT JOIN place_overlap po ON po.itinerary_id = i.id
+			CROSS JOIN LATERAL (
+				SELECT CASE WHEN UPPER(i.currency) = 'USD' THEN i.estimated_cost
+					ELSE i.estimated_cost / NULLIF(er.rate, 0) END / GREATEST(i.duration, 1) AS daily_cost
+			) cost
+			WHERE i.id <> src.id AND i.is_public = TRUE AND i.hidden_at IS NULL AND i.deleted_at IS NULL
+				AND (i.category = src.category OR i.country_id = src.country_id
+					OR LOWER(i.country) = src.country OR po.shared > 0)
+		)
+		SELECT id,
+			@category_weight * category_score +
+			@destination_weight * destination_score +
+			@duration_weight * duration_score +
+			@cost_weight * cost_score +
+			@places_weight * places_score AS score
+		FROM candidates
+		ORDER BY score DESC, average_rating DESC, views_count DESC
+		LIMIT @limit`,
+		map[string]interface{}{
+			"id":                 itineraryID,
+			"max_distance":       similarityMaxDistanceKm,
+			"category_weight":    similarityCategoryWeight,
+			"destination_weight": similarityDestinationWeight,
+			"duration_weight":    similarityDurationWeight,
+			"cost_weight":        similarityCostWeight,
+			"places_weight":      similarityPlacesWeight,
+			"limit":              limit,
+		}).Scan(&scored).Error
+	if err != nil {
 		return nil, err
 	}
+	if len(scored) == 0 {
+		return []models.Itinerary{}, nil
+	}
+
+	ids := make([]uint, len(scored))
+	for i, item := range scored {
+		ids[i] = item.ID
+	}
 
 	var itineraries []models.Itinerary
-	err := r.db.Preload("Author").
-		Where("id != ? AND (category = ? OR city = ? OR country = ?) AND is_public = ?",
-			itineraryID, originalItinerary.Category, originalItinerary.City, originalItinerary.Country, true).
-		Order("average_rating DESC, views_count DESC").
+	if err := r.db.Preload("Author").Where("id IN ?", ids).Find(&itineraries).Error; err != nil {
+		return nil, err
+	}
+
+	// Manter a ordem da pontuação
+	byID := make(map[uint]models.Itinerary, len(itineraries))
+	for _, itinerary := range itineraries {
+		byID[itinerary.ID] = itinerary
+	}
+	ordered := make([]models.Itinerary, 0, len(itineraries))
+	for _, id := range ids {
+		if itinerary, ok := byID[id]; ok {
+			ordered = append(ordered, itinerary)
+		}
+	}
+
+	return ordered, nil
+}
+
+func (r *ItineraryRepository) GetWithoutGeoReference(afterID uint, limit int) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	err := r.db.Where("id > ? AND geo_checked_at IS NULL AND (country_id IS NULL OR timezone IS NULL OR timezone = '')", afterID).
+		Order("id ASC").
 		Limit(limit).
 		Find(&itineraries).Error
-
 	return itineraries, err
 }
 
-// Função auxiliar para recalcular estatísticas de avaliação
-func (r *ItineraryRepository) updateItineraryRatingStats(tx *gorm.DB, itineraryID uint) error {
+func (r *ItineraryRepository) UpdateGeoReference(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&models.Itinerary{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *ItineraryRepository) GetPlaceCandidates(itinerary *models.Itinerary, limit int) ([]models.ItineraryLocation, error) {
+	var locations []models.ItineraryLocation
+
+	query := r.db.Preload("Day.Itinerary").
+		Joins("JOIN itinerary_days ON itinerary_days.id = itinerary_locations.day_id").
+		Joins("JOIN itineraries ON itineraries.id = itinerary_days.itinerary_id").
+		Where("itineraries.id != ? AND itineraries.is_public = ? AND itineraries.deleted_at IS NULL", itinerary.ID, true)
+
+	// Mesmo destino: pela cidade de referência quando disponível, senão pelo texto
+	if itinerary.CityID != nil {
+		query = query.Where("itineraries.city_id = ?", *itinerary.CityID)
+	} else {
+		query = query.Where("LOWER(itineraries.city) = LOWER(?) AND LOWER(itineraries.country) = LOWER(?)",
+			itinerary.City, itinerary.Country)
+	}
+
+	err := query.Order("itineraries.average_rating DESC, itineraries.views_count DESC").
+		Limit(limit).
+		Find(&locations).Error
+
+	return locations, err
+}
+
+func (r *ItineraryRepository) GetLocationByID(id uint) (*models.ItineraryLocation, error) {
+	var location models.ItineraryLocation
+	err := r.db.Preload("Day.Itinerary").Where("id = ?", id).First(&location).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// GetParticipantRole retorna o papel do usuário na viagem, ou vazio se ele
+// não participa
+func (r *ItineraryRepository) GetParticipantRole(itineraryID, userID uint) (models.CollaboratorRole, error) {
+	var participants []models.ItineraryCollaborator
+	err := r.db.Where("itinerary_id = ? AND user_id = ?", itineraryID, userID).
+		Limit(1).
+		Find(&participants).Error
+	if err != nil || len(participants) == 0 {
+		return "", err
+	}
+	return participants[0].Role, nil
+}
+
+// GetParticipantIDs lista os participantes da viagem (colaboradores e
+// espectadores), sem o autor
+func (r *ItineraryRepository) GetParticipantIDs(itineraryID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.ItineraryCollaborator{}).
+		Where("itinerary_id = ?", itineraryID).
+		Order("created_at").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *ItineraryRepository) AddLocation(location *models.ItineraryLocation) error {
+	return r.db.Omit(clause.Associations).Create(location).Error
+}
+
+// AddDays cria os dias (com seus locais) no fim do roteiro e amplia a
+// duração quando os novos dias passam dela
+func (r *ItineraryRepository) AddDays(itineraryID uint, days []models.ItineraryDay) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		lastDay := 0
+		for i := range days {
+			days[i].ItineraryID = itineraryID
+			if days[i].DayNumber > lastDay {
+				lastDay = days[i].DayNumber
+			}
+		}
+
+		if err := tx.Create(&days).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+			Updates(map[string]interface{}{
+				"duration":   gorm.Expr("GREATEST(duration, ?)", lastDay),
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+// AddLocations cria em lote locais em dias já existentes
+func (r *ItineraryRepository) AddLocations(locations []models.ItineraryLocation) error {
+	return r.db.Omit(clause.Associations).Create(&locations).Error
+}
+
+func (r *ItineraryRepository) UpdateLocation(location *models.ItineraryLocation, columns []string) error {
+	return r.db.Model(location).Select(columns).Updates(location).Error
+}
+
+// AddPhotos acrescenta as fotos confirmadas às galerias dos dias e locais,
+// ignorando as que já estão na galeria
+func (r *ItineraryRepository) AddPhotos(dayImages, locationImages map[uint][]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for dayID, images := range dayImages {
+			var day models.ItineraryDay
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", dayID).First(&day).Error; err != nil {
+				return err
+			}
+			day.Images = appendUnique(day.Images, images)
+			if err := tx.Model(&day).Select("images").Updates(&day).Error; err != nil {
+				return err
+			}
+		}
+
+		for locationID, images := range locationImages {
+			var location models.ItineraryLocation
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", locationID).First(&location).Error; err != nil {
+				return err
+			}
+			location.Images = appendUnique(location.Images, images)
+			if err := tx.Model(&location).Select("images").Updates(&location).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func appendUnique(current, added []string) []string {
+	seen := make(map[string]bool, len(current))
+	for _, value := range current {
+		seen[value] = true
+	}
+	for _, value := range added {
+		if !seen[value] {
+			seen[value] = true
+			current = append(current, value)
+		}
+	}
+	return current
+}
+
+// Clone cria uma cópia privada (com dias e locais) do roteiro para outro
+// autor, registrando a origem e contando a cópia no roteiro original
+func (r *ItineraryRepository) Clone(itineraryID, authorID uint) (*models.Itinerary, error) {
+	var clone models.Itinerary
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var original models.Itinerary
+		if err := tx.Preload("Days.Locations").Where("id = ?", itineraryID).First(&original).Error; err != nil {
+			return err
+		}
+
+		clone = original
+		clone.ID = 0
+		clone.AuthorID = authorID
+		clone.IsPublic = false
+		clone.IsFeatured = false
+		clone.FeaturedOrder = nil
+		clone.FeaturedFrom = nil
+		clone.FeaturedUntil = nil
+		clone.ViewsCount = 0
+		clone.LikesCount = 0
+		clone.RatingsCount = 0
+		clone.AverageRating = 0
+		clone.ClonesCount = 0
+		clone.ClonedFromID = &original.ID
+		clone.IsTemplate = false
+		clone.ReportsCount = 0
+		clone.HiddenAt = nil
+		clone.CreatedAt = time.Time{}
+		clone.UpdatedAt = time.Time{}
+		clone.Author = models.User{}
+		clone.Ratings = nil
+		clone.Days = make([]models.ItineraryDay, len(original.Days))
+
+		for i, day := range original.Days {
+			day.ID = 0
+			day.ItineraryID = 0
+			day.CreatedAt = time.Time{}
+			day.UpdatedAt = time.Time{}
+			day.Itinerary = models.Itinerary{}
+
+			locations := make([]models.ItineraryLocation, len(day.Locations))
+			for j, location := range day.Locations {
+				location.ID = 0
+				location.DayID = 0
+				location.CreatedAt = time.Time{}
+				location.UpdatedAt = time.Time{}
+				location.Day = models.ItineraryDay{}
+				locations[j] = location
+			}
+			day.Locations = locations
+			clone.Days[i] = day
+		}
+
+		if err := tx.Omit("Author", "Ratings").Create(&clone).Error; err != nil {
+			return err
+		}
+
+		// Cópias do próprio autor não contam para a popularidade
+		if original.AuthorID != authorID {
+			if err := tx.Model(&models.Itinerary{}).Where("id = ?", original.ID).
+				UpdateColumn("clones_count", gorm.Expr("clones_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		// Atualizar contador de roteiros do usuário
+		return tx.Model(&models.User{}).Where("id = ?", authorID).
+			Update("itineraries_count", gorm.Expr("itineraries_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &clone, nil
+}
+
+// Função auxiliar para recalcular estatísticas de avaliação; as avaliações
+// ocultadas pela moderação não contam
+func updateItineraryRatingStats(tx *gorm.DB, itineraryID uint) error {
 	var avgRating float64
 	var ratingsCount int64
 
 	// Calcular média e contagem
 	err := tx.Model(&models.ItineraryRating{}).
-		Where("itinerary_id = ?", itineraryID).
+		Where("itinerary_id = ? AND hidden_at IS NULL", itineraryID).
 		Count(&ratingsCount).Error
 	if err != nil {
 		return err
@@ -242,7 +1089,7 @@ func (r *ItineraryRepository) updateItineraryRatingStats(tx *gorm.DB, itineraryI
 
 	if ratingsCount > 0 {
 		err = tx.Model(&models.ItineraryRating{}).
-			Where("itinerary_id = ?", itineraryID).
+			Where("itinerary_id = ? AND hidden_at IS NULL", itineraryID).
 			Select("AVG(rating)").
 			Row().Scan(&avgRating)
 		if err != nil {
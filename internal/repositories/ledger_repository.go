@@ -0,0 +1,365 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnbalancedTransaction indica lançamentos cuja soma não é zero
+var ErrUnbalancedTransaction = errors.New("unbalanced ledger transaction")
+
+// ErrInsufficientBalance indica que a conta não tem saldo para o débito
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+type LedgerRepositoryInterface interface {
+	PostTransaction(txn *models.LedgerTransaction, legs []models.LedgerLeg) (bool, error)
+	GetAccountsByOwner(ownerID uint) ([]models.LedgerAccount, error)
+	GetEntriesByOwner(ownerID uint, limit, offset int) ([]models.LedgerEntry, error)
+	SchedulePayout(payout *models.Payout) error
+	GetPayoutsByUser(userID uint, limit, offset int) ([]models.Payout, error)
+	GetDuePayouts(now time.Time, limit int) ([]models.Payout, error)
+	ClaimPayout(payoutID uint) (bool, error)
+	ReleasePayout(payoutID uint) error
+	CompletePayout(payout *models.Payout, status models.PayoutStatus) (bool, error)
+	GetReconciliationTotals(from, to time.Time) ([]models.ReconciliationTotal, error)
+	GetUnbalancedTransactionIDs(from, to time.Time) ([]uint, error)
+	GetTipsMissingLedger(from, to time.Time) ([]uint, error)
+	GetRefundsMissingLedger(from, to time.Time) ([]uint, error)
+	GetPromotionsMissingLedger(from, to time.Time) ([]uint, error)
+	GetSubscriptionsMissingLedger(from, to time.Time) ([]uint, error)
+	GetAccountsOutOfSync() ([]uint, error)
+	GetProcessingPayoutIDs(before time.Time) ([]uint, error)
+}
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepositoryInterface {
+	return &LedgerRepository{db: db}
+}
+
+// PostTransaction registra a transação e seus lançamentos atualizando os
+// saldos das contas. Uma referência já registrada é ignorada e retorna false
+func (r *LedgerRepository) PostTransaction(txn *models.LedgerTransaction, legs []models.LedgerLeg) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = postTransaction(tx, txn, legs)
+		return err
+	})
+	return created, err
+}
+
+func (r *LedgerRepository) GetAccountsByOwner(ownerID uint) ([]models.LedgerAccount, error) {
+	var accounts []models.LedgerAccount
+	err := r.db.Where("owner_id = ? AND type IN ?", ownerID, []models.LedgerAccountType{
+		models.LedgerAccountCreatorAvailable,
+		models.LedgerAccountCreatorPayoutPending,
+	}).Order("currency").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *LedgerRepository) GetEntriesByOwner(ownerID uint, limit, offset int) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.db.Preload("Account").
+		Preload("Transaction").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_accounts.owner_id = ? AND ledger_accounts.type = ?", ownerID, models.LedgerAccountCreatorAvailable).
+		Order("ledger_entries.created_at DESC, ledger_entries.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	return entries, err
+}
+
+// SchedulePayout reserva o valor do saldo disponível e cria o saque em uma
+// única transação, com a conta bloqueada para evitar saques concorrentes
+func (r *LedgerRepository) SchedulePayout(payout *models.Payout) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var account models.LedgerAccount
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("type = ? AND owner_id = ? AND currency = ?",
+				models.LedgerAccountCreatorAvailable, payout.UserID, payout.Currency).
+			First(&account).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInsufficientBalance
+		}
+		if err != nil {
+			return err
+		}
+		if account.Balance < payout.Amount {
+			return ErrInsufficientBalance
+		}
+
+		if err := tx.Create(payout).Error; err != nil {
+			return err
+		}
+
+		_, err = postTransaction(tx, &models.LedgerTransaction{
+			Kind:        models.LedgerKindPayout,
+			Reference:   fmt.Sprintf("payout:%d", payout.ID),
+			Currency:    payout.Currency,
+			Description: "Saque agendado",
+		}, []models.LedgerLeg{
+			{AccountType: models.LedgerAccountCreatorAvailable, OwnerID: payout.UserID, Amount: -payout.Amount},
+			{AccountType: models.LedgerAccountCreatorPayoutPending, OwnerID: payout.UserID, Amount: payout.Amount},
+		})
+		return err
+	})
+}
+
+func (r *LedgerRepository) GetPayoutsByUser(userID uint, limit, offset int) ([]models.Payout, error) {
+	var payouts []models.Payout
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&payouts).Error
+	return payouts, err
+}
+
+func (r *LedgerRepository) GetDuePayouts(now time.Time, limit int) ([]models.Payout, error) {
+	var payouts []models.Payout
+	err := r.db.Where("status = ? AND scheduled_for <= ?", models.PayoutStatusScheduled, now).
+		Order("scheduled_for ASC").
+		Limit(limit).
+		Find(&payouts).Error
+	return payouts, err
+}
+
+// ClaimPayout reserva o saque agendado para o envio ao provedor; retorna
+// false se outro processamento (agendador, admin ou outra instância) já o
+// reservou
+func (r *LedgerRepository) ClaimPayout(payoutID uint) (bool, error) {
+	result := r.db.Model(&models.Payout{}).
+		Where("id = ? AND status = ?", payoutID, models.PayoutStatusScheduled).
+		Update("status", models.PayoutStatusProcessing)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleasePayout devolve à fila o saque reservado que não chegou ao provedor
+func (r *LedgerRepository) ReleasePayout(payoutID uint) error {
+	return r.db.Model(&models.Payout{}).
+		Where("id = ? AND status = ?", payoutID, models.PayoutStatusProcessing).
+		Update("status", models.PayoutStatusScheduled).Error
+}
+
+// CompletePayout encerra um saque em processamento: pago, o valor reservado sai para o
+// provedor; com falha, volta ao saldo disponível. Retorna false se o saque já
+// tinha sido encerrado
+func (r *LedgerRepository) CompletePayout(payout *models.Payout, status models.PayoutStatus) (bool, error) {
+	completed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"status":             status,
+			"provider_payout_id": payout.ProviderPayoutID,
+			"failure_reason":     payout.FailureReason,
+		}
+		if status == models.PayoutStatusPaid {
+			updates["paid_at"] = time.Now()
+		}
+
+		result := tx.Model(&models.Payout{}).
+			Where("id = ? AND status = ?", payout.ID, models.PayoutStatusProcessing).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		txn := &models.LedgerTransaction{Currency: payout.Currency}
+		counterpart := models.LedgerLeg{Amount: payout.Amount}
+		if status == models.PayoutStatusPaid {
+			txn.Kind = models.LedgerKindPayoutPaid
+			txn.Description = "Saque enviado à conta de recebimento"
+			counterpart.AccountType = models.LedgerAccountProviderClearing
+		} else {
+			txn.Kind = models.LedgerKindPayoutFailed
+			txn.Description = "Saque devolvido ao saldo disponível"
+			counterpart.AccountType = models.LedgerAccountCreatorAvailable
+			counterpart.OwnerID = payout.UserID
+		}
+		txn.Reference = fmt.Sprintf("%s:%d", txn.Kind, payout.ID)
+
+		if _, err := postTransaction(tx, txn, []models.LedgerLeg{
+			{AccountType: models.LedgerAccountCreatorPayoutPending, OwnerID: payout.UserID, Amount: -payout.Amount},
+			counterpart,
+		}); err != nil {
+			return err
+		}
+
+		completed = true
+		return nil
+	})
+	if completed {
+		payout.Status = status
+	}
+	return completed, err
+}
+
+func (r *LedgerRepository) GetReconciliationTotals(from, to time.Time) ([]models.ReconciliationTotal, error) {
+	var totals []models.ReconciliationTotal
+	err := r.db.Table("ledger_transactions").
+		Select(`ledger_transactions.kind, ledger_transactions.currency,
+			COUNT(DISTINCT ledger_transactions.id) AS transactions,
+			COALESCE(SUM(CASE WHEN ledger_entries.amount > 0 THEN ledger_entries.amount ELSE 0 END), 0) AS volume`).
+		Joins("JOIN ledger_entries ON ledger_entries.transaction_id = ledger_transactions.id").
+		Where("ledger_transactions.created_at >= ? AND ledger_transactions.created_at < ?", from, to).
+		Group("ledger_transactions.kind, ledger_transactions.currency").
+		Order("ledger_transactions.kind, ledger_transactions.currency").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *LedgerRepository) GetUnbalancedTransactionIDs(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("ledger_transactions").
+		Select("ledger_transactions.id").
+		Joins("LEFT JOIN ledger_entries ON ledger_entries.transaction_id = ledger_transactions.id").
+		Where("ledger_transactions.created_at >= ? AND ledger_transactions.created_at < ?", from, to).
+		Group("ledger_transactions.id").
+		Having("COALESCE(SUM(ledger_entries.amount), 0) <> 0 OR COUNT(ledger_entries.id) < 2").
+		Order("ledger_transactions.id").
+		Pluck("ledger_transactions.id", &ids).Error
+	return ids, err
+}
+
+// GetTipsMissingLedger lista gorjetas pagas no período sem transação no livro-razão
+func (r *LedgerRepository) GetTipsMissingLedger(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Tip{}).
+		Where("status IN ? AND paid_at >= ? AND paid_at < ?",
+			[]models.TipStatus{models.TipStatusSucceeded, models.TipStatusRefunded}, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM ledger_transactions WHERE reference = 'tip:' || tips.id)").
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetRefundsMissingLedger lista gorjetas estornadas no período sem o estorno lançado
+func (r *LedgerRepository) GetRefundsMissingLedger(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Tip{}).
+		Where("status = ? AND refunded_at >= ? AND refunded_at < ?", models.TipStatusRefunded, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM ledger_transactions WHERE reference = 'refund:tip:' || tips.id)").
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetPromotionsMissingLedger lista promoções pagas no período sem transação no
+// livro-razão
+func (r *LedgerRepository) GetPromotionsMissingLedger(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.DestinationPromotion{}).Unscoped().
+		Where("payment_status = ? AND paid_at >= ? AND paid_at < ?", models.PromotionPaymentPaid, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM ledger_transactions WHERE reference = 'promotion:' || destination_promotions.id)").
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetSubscriptionsMissingLedger lista assinaturas pagas no período sem
+// transação no livro-razão
+func (r *LedgerRepository) GetSubscriptionsMissingLedger(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Subscription{}).
+		Where("status = ? AND paid_at >= ? AND paid_at < ?", models.SubscriptionStatusPaid, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM ledger_transactions WHERE reference = 'subscription:' || subscriptions.id)").
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetAccountsOutOfSync lista contas cujo saldo mantido difere da soma dos lançamentos
+func (r *LedgerRepository) GetAccountsOutOfSync() ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("ledger_accounts").
+		Select("ledger_accounts.id").
+		Joins("LEFT JOIN ledger_entries ON ledger_entries.account_id = ledger_accounts.id").
+		Group("ledger_accounts.id, ledger_accounts.balance").
+		Having("ledger_accounts.balance <> COALESCE(SUM(ledger_entries.amount), 0)").
+		Order("ledger_accounts.id").
+		Pluck("ledger_accounts.id", &ids).Error
+	return ids, err
+}
+
+func postTransaction(tx *gorm.DB, txn *models.LedgerTransaction, legs []models.LedgerLeg) (bool, error) {
+	if err := checkBalanced(legs); err != nil {
+		return false, err
+	}
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Entries").Create(txn)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	for _, leg := range legs {
+		account, err := getOrCreateLedgerAccount(tx, leg.AccountType, leg.OwnerID, txn.Currency)
+		if err != nil {
+			return false, err
+		}
+
+		entry := &models.LedgerEntry{
+			TransactionID: txn.ID,
+			AccountID:     account.ID,
+			Amount:        leg.Amount,
+		}
+		if err := tx.Omit("Account", "Transaction").Create(entry).Error; err != nil {
+			return false, err
+		}
+
+		if err := tx.Model(&models.LedgerAccount{}).
+			Where("id = ?", account.ID).
+			Update("balance", gorm.Expr("balance + ?", leg.Amount)).Error; err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// checkBalanced exige ao menos duas partidas somando zero
+func checkBalanced(legs []models.LedgerLeg) error {
+	var sum int64
+	for _, leg := range legs {
+		sum += leg.Amount
+	}
+	if len(legs) < 2 || sum != 0 {
+		return ErrUnbalancedTransaction
+	}
+	return nil
+}
+
+func getOrCreateLedgerAccount(tx *gorm.DB, accountType models.LedgerAccountType, ownerID uint, currency string) (*models.LedgerAccount, error) {
+	account := &models.LedgerAccount{Type: accountType, OwnerID: ownerID, Currency: currency}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(account).Error; err != nil {
+		return nil, err
+	}
+	if account.ID != 0 {
+		return account, nil
+	}
+
+	err := tx.Where("type = ? AND owner_id = ? AND currency = ?", accountType, ownerID, currency).
+		First(account).Error
+	return account, err
+}
+
+// GetProcessingPayoutIDs lista os saques em processamento desde antes da data
+// informada
+func (r *LedgerRepository) GetProcessingPayoutIDs(before time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Payout{}).
+		Where("status = ? AND updated_at < ?", models.PayoutStatusProcessing, before).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
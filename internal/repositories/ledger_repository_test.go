@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+func TestCheckBalanced(t *testing.T) {
+	tests := []struct {
+		name    string
+		legs    []models.LedgerLeg
+		wantErr bool
+	}{
+		{
+			name: "gorjeta com comissão",
+			legs: []models.LedgerLeg{
+				{AccountType: models.LedgerAccountProviderClearing, Amount: -1000},
+				{AccountType: models.LedgerAccountPlatformRevenue, Amount: 100},
+				{AccountType: models.LedgerAccountCreatorAvailable, OwnerID: 7, Amount: 900},
+			},
+		},
+		{
+			name: "reserva de saque",
+			legs: []models.LedgerLeg{
+				{AccountType: models.LedgerAccountCreatorAvailable, OwnerID: 7, Amount: -500},
+				{AccountType: models.LedgerAccountCreatorPayoutPending, OwnerID: 7, Amount: 500},
+			},
+		},
+		{
+			name: "soma diferente de zero",
+			legs: []models.LedgerLeg{
+				{AccountType: models.LedgerAccountProviderClearing, Amount: -1000},
+				{AccountType: models.LedgerAccountCreatorAvailable, OwnerID: 7, Amount: 900},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "uma só partida",
+			legs:    []models.LedgerLeg{{AccountType: models.LedgerAccountProviderClearing, Amount: 0}},
+			wantErr: true,
+		},
+		{
+			name:    "sem partidas",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBalanced(tt.legs)
+			if tt.wantErr != errors.Is(err, ErrUnbalancedTransaction) || (!tt.wantErr && err != nil) {
+				t.Errorf("checkBalanced = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
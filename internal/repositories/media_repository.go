@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type MediaRepositoryInterface interface {
+	Create(media *models.Media) error
+	GetByID(id uint) (*models.Media, error)
+	GetByURLs(urls []string) ([]models.Media, error)
+	GetByFilePath(filePath string) (*models.Media, error)
+	GetByContentHash(ownerID uint, hash string, private bool) (*models.Media, error)
+	UpdateProcessed(media *models.Media) error
+	UpdateStatus(mediaID uint, status models.MediaProcessingStatus, processingError string) error
+	DeleteByFilePath(filePath string) error
+	CountReferences(media *models.Media) (int64, error)
+}
+
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepositoryInterface {
+	return &MediaRepository{db: db}
+}
+
+func (r *MediaRepository) Create(media *models.Media) error {
+	return r.db.Omit("Owner").Create(media).Error
+}
+
+func (r *MediaRepository) GetByID(id uint) (*models.Media, error) {
+	var media models.Media
+	err := r.db.First(&media, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetByURLs busca as mídias públicas pelas URLs; anexos de conversas ficam
+// de fora, para não serem publicados em posts, stories ou perfis
+func (r *MediaRepository) GetByURLs(urls []string) ([]models.Media, error) {
+	var media []models.Media
+	if len(urls) == 0 {
+		return media, nil
+	}
+	err := r.db.Where("url IN ? AND private = ?", urls, false).Find(&media).Error
+	return media, err
+}
+
+func (r *MediaRepository) GetByFilePath(filePath string) (*models.Media, error) {
+	var media models.Media
+	err := r.db.Where("file_path = ?", filePath).First(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetByContentHash busca um envio anterior do mesmo arquivo pelo usuário,
+// com a mesma visibilidade; mídias recusadas pela moderação não têm mais
+// arquivo e ficam de fora
+func (r *MediaRepository) GetByContentHash(ownerID uint, hash string, private bool) (*models.Media, error) {
+	var media models.Media
+	err := r.db.Where("owner_id = ? AND content_hash = ? AND private = ? AND moderation_status <> ?", ownerID, hash, private, models.MediaModerationRejected).
+		Order("id").First(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// UpdateProcessed grava o resultado do processamento em segundo plano
+// (dimensões e arquivos derivados)
+func (r *MediaRepository) UpdateProcessed(media *models.Media) error {
+	return r.db.Select("width", "height", "variants", "extra_files").
+		Updates(media).Error
+}
+
+func (r *MediaRepository) UpdateStatus(mediaID uint, status models.MediaProcessingStatus, processingError string) error {
+	return r.db.Model(&models.Media{}).Where("id = ?", mediaID).
+		Updates(map[string]interface{}{"status": status, "processing_error": processingError}).Error
+}
+
+func (r *MediaRepository) DeleteByFilePath(filePath string) error {
+	return r.db.Where("file_path = ?", filePath).Delete(&models.Media{}).Error
+}
+
+// CountReferences conta os conteúdos que ainda exibem a mídia. Listas de URLs
+// ficam serializadas em JSON, então a busca é pela URL entre aspas, do mesmo
+// jeito que o serializador a grava
+func (r *MediaRepository) CountReferences(media *models.Media) (int64, error) {
+	quoted, err := json.Marshal(media.URL)
+	if err != nil {
+		return 0, err
+	}
+	inList := "%" + escapeLike(string(quoted)) + "%"
+
+	references := []struct {
+		table string
+		where string
+		args  []interface{}
+	}{
+		{"posts", "deleted_at IS NULL AND (media_url = ? OR CAST(media_urls AS TEXT) LIKE ?)", []interface{}{media.URL, inList}},
+		{"stories", "deleted_at IS NULL AND media_id = ?", []interface{}{media.ID}},
+		{"users", "deleted_at IS NULL AND (profile_picture = ? OR cover_photo = ?)", []interface{}{media.URL, media.URL}},
+		{"itineraries", "deleted_at IS NULL AND (cover_image = ? OR CAST(images AS TEXT) LIKE ?)", []interface{}{media.URL, inList}},
+		{"itinerary_days", "CAST(images AS TEXT) LIKE ?", []interface{}{inList}},
+		{"itinerary_locations", "CAST(images AS TEXT) LIKE ?", []interface{}{inList}},
+		{"location_check_ins", "CAST(photos AS TEXT) LIKE ?", []interface{}{inList}},
+		{"experiences", "deleted_at IS NULL AND CAST(images AS TEXT) LIKE ?", []interface{}{inList}},
+		{"year_reviews", "image_url = ?", []interface{}{media.URL}},
+		{"messages", "deleted_at IS NULL AND media_id = ?", []interface{}{media.ID}},
+	}
+
+	var total int64
+	for _, reference := range references {
+		var count int64
+		if err := r.db.Table(reference.table).Where(reference.where, reference.args...).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// escapeLike escapa os curingas do LIKE; nomes de arquivo costumam ter "_"
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
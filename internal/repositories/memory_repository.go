@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type MemoryRepositoryInterface interface {
+	GetPostsOnDay(userID uint, month time.Month, day int, before time.Time) ([]models.Post, error)
+	GetItinerariesOnDay(userID uint, month time.Month, day int, before time.Time) ([]models.Itinerary, error)
+	GetUsersWithMemories(month time.Month, day int, before time.Time) ([]uint, error)
+	IsMemoriesEnabled(userID uint) (bool, error)
+	SetMemoriesEnabled(userID uint, enabled bool) error
+}
+
+type MemoryRepository struct {
+	db *gorm.DB
+}
+
+func NewMemoryRepository(db *gorm.DB) MemoryRepositoryInterface {
+	return &MemoryRepository{db: db}
+}
+
+func (r *MemoryRepository) GetPostsOnDay(userID uint, month time.Month, day int, before time.Time) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Preload("Author").
+		Where("author_id = ? AND is_active = ?", userID, true).
+		Where("EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(DAY FROM created_at) = ? AND created_at < ?", int(month), day, before).
+		Order("created_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *MemoryRepository) GetItinerariesOnDay(userID uint, month time.Month, day int, before time.Time) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	err := r.db.Preload("Author").
+		Where("author_id = ?", userID).
+		Where("EXTRACT(MONTH FROM created_at) = ? AND EXTRACT(DAY FROM created_at) = ? AND created_at < ?", int(month), day, before).
+		Order("created_at DESC").
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
+// GetUsersWithMemories retorna os usuários ativos, que não desativaram as
+// lembranças, com posts ou roteiros criados no mesmo dia de anos anteriores
+func (r *MemoryRepository) GetUsersWithMemories(month time.Month, day int, before time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Raw(`
+		SELECT u.id FROM users u
+		WHERE u.deleted_at IS NULL AND u.is_active = true AND u.memories_enabled = true
+		AND (
+			EXISTS (
+				SELECT 1 FROM posts p
+				WHERE p.author_id = u.id AND p.deleted_at IS NULL AND p.is_active = true
+				AND EXTRACT(MONTH FROM p.created_at) = ? AND EXTRACT(DAY FROM p.created_at) = ? AND p.created_at < ?
+			)
+			OR EXISTS (
+				SELECT 1 FROM itineraries i
+				WHERE i.author_id = u.id AND i.deleted_at IS NULL
+				AND EXTRACT(MONTH FROM i.created_at) = ? AND EXTRACT(DAY FROM i.created_at) = ? AND i.created_at < ?
+			)
+		)
+		ORDER BY u.id`,
+		int(month), day, before, int(month), day, before,
+	).Scan(&userIDs).Error
+	return userIDs, err
+}
+
+func (r *MemoryRepository) IsMemoriesEnabled(userID uint) (bool, error) {
+	var user models.User
+	err := r.db.Select("id", "memories_enabled").First(&user, userID).Error
+	return user.MemoriesEnabled, err
+}
+
+func (r *MemoryRepository) SetMemoriesEnabled(userID uint, enabled bool) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", userID).Update("memories_enabled", enabled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
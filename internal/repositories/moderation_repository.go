@@ -0,0 +1,525 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyReported indica que o usuário já denunciou o alvo ou a mensagem
+var ErrAlreadyReported = errors.New("conteúdo já denunciado pelo usuário")
+
+type ModerationRepositoryInterface interface {
+	GetPostForModeration(postID uint) (*models.Post, error)
+	HidePost(postID uint, audit *models.ModerationAction) (bool, error)
+	RestorePost(postID uint, audit *models.ModerationAction) error
+	RemovePost(postID uint, audit *models.ModerationAction) error
+	GetItineraryForModeration(itineraryID uint) (*models.Itinerary, error)
+	GetCommentForModeration(commentID uint) (*models.Comment, error)
+	GetRatingForModeration(ratingID uint) (*models.ItineraryRating, error)
+	RestoreItinerary(itineraryID uint, audit *models.ModerationAction) error
+	RemoveItinerary(itineraryID uint, audit *models.ModerationAction) error
+	CreateMessageReport(report *models.MessageReport) error
+	GetMessageReportByID(id uint) (*models.MessageReport, error)
+	GetMessageReports(status models.ReportStatus, limit, offset int) ([]models.MessageReport, error)
+	ResolveMessageReport(report *models.MessageReport, status models.ReportStatus, audit *models.ModerationAction) (bool, error)
+	GetMessageForModeration(messageID uint) (*models.Message, error)
+	GetMessageContext(conversationID, messageID uint, count int) ([]models.Message, error)
+	RemoveMessage(messageID uint, audit *models.ModerationAction) (bool, error)
+	CreateAction(audit *models.ModerationAction) error
+	GetUserForModeration(userID uint) (*models.User, error)
+	BanUser(userID uint, reason string, audit *models.ModerationAction) (bool, error)
+	GetMediaByModerationStatus(status models.MediaModerationStatus, limit, offset int) ([]models.Media, error)
+	GetMediaForModeration(mediaID uint) (*models.Media, error)
+	ReviewMedia(media *models.Media, status models.MediaModerationStatus, audit *models.ModerationAction) (bool, error)
+	GetModerationActions(filter ModerationActionFilter, limit, offset int) ([]models.ModerationAction, error)
+	CreateBulkJob(job *models.BulkModerationJob) error
+	GetBulkJobByID(id uint) (*models.BulkModerationJob, error)
+	UpdateBulkJob(job *models.BulkModerationJob) error
+	GetUnfinishedBulkJobs() ([]models.BulkModerationJob, error)
+}
+
+// ModerationActionFilter restringe o histórico de auditoria; campos vazios
+// não filtram
+type ModerationActionFilter struct {
+	AdminID    uint
+	TargetType models.ModerationTargetType
+	TargetID   uint
+}
+
+type ModerationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) ModerationRepositoryInterface {
+	return &ModerationRepository{db: db}
+}
+
+// GetPostForModeration busca o post independente de estar oculto ou excluído
+func (r *ModerationRepository) GetPostForModeration(postID uint) (*models.Post, error) {
+	var post models.Post
+	err := r.db.Unscoped().Preload("Author").Where("id = ?", postID).First(&post).Error
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// HidePost oculta um post visível mantendo as denúncias pendentes na fila;
+// retorna false se ele já estava oculto
+func (r *ModerationRepository) HidePost(postID uint, audit *models.ModerationAction) (bool, error) {
+	hidden := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Post{}).
+			Where("id = ? AND is_active = ?", postID, true).
+			Updates(map[string]interface{}{
+				"is_active": false,
+				"hidden_at": time.Now(),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		hidden = true
+		return tx.Create(audit).Error
+	})
+	return hidden, err
+}
+
+// RestorePost reativa um post ocultado e descarta o caso de denúncias
+// pendente
+func (r *ModerationRepository) RestorePost(postID uint, audit *models.ModerationAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Post{}).Where("id = ?", postID).
+			Updates(map[string]interface{}{
+				"is_active": true,
+				"hidden_at": nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := closePendingReportCase(tx, models.ReportTargetPost, postID, models.ReportStatusDismissed, audit.AdminID, audit.Note); err != nil {
+			return err
+		}
+
+		return tx.Create(audit).Error
+	})
+}
+
+// RemovePost exclui definitivamente o post, suas curtidas e comentários, e dá
+// o caso de denúncias pendente como procedente
+func (r *ModerationRepository) RemovePost(postID uint, audit *models.ModerationAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var post models.Post
+		if err := tx.Unscoped().Where("id = ?", postID).First(&post).Error; err != nil {
+			return err
+		}
+
+		if err := closePendingReportCase(tx, models.ReportTargetPost, postID, models.ReportStatusResolved, audit.AdminID, audit.Note); err != nil {
+			return err
+		}
+
+		if err := tx.Create(audit).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("post_id = ?", postID).Delete(&models.PostLike{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("post_id = ?", postID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&models.Post{}, postID).Error; err != nil {
+			return err
+		}
+
+		// Posts já excluídos pelo autor não contam mais para o perfil
+		if post.DeletedAt.Valid {
+			return nil
+		}
+		return tx.Model(&models.User{}).Where("id = ?", post.AuthorID).
+			Update("posts_count", gorm.Expr("posts_count - 1")).Error
+	})
+}
+
+// GetItineraryForModeration busca o roteiro independente de estar retirado
+// ou excluído
+func (r *ModerationRepository) GetItineraryForModeration(itineraryID uint) (*models.Itinerary, error) {
+	var itinerary models.Itinerary
+	err := r.db.Unscoped().Preload("Author").Where("id = ?", itineraryID).First(&itinerary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &itinerary, nil
+}
+
+// GetCommentForModeration busca o comentário independente de estar oculto ou
+// excluído
+func (r *ModerationRepository) GetCommentForModeration(commentID uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.Unscoped().Preload("Author").Where("id = ?", commentID).First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetRatingForModeration busca a avaliação mesmo que esteja oculta
+func (r *ModerationRepository) GetRatingForModeration(ratingID uint) (*models.ItineraryRating, error) {
+	var rating models.ItineraryRating
+	err := r.db.Preload("User").Where("id = ?", ratingID).First(&rating).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// RestoreItinerary devolve o roteiro às listagens e descarta o caso de
+// denúncias pendente
+func (r *ModerationRepository) RestoreItinerary(itineraryID uint, audit *models.ModerationAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+			UpdateColumn("hidden_at", nil).Error; err != nil {
+			return err
+		}
+
+		if err := closePendingReportCase(tx, models.ReportTargetItinerary, itineraryID, models.ReportStatusDismissed, audit.AdminID, audit.Note); err != nil {
+			return err
+		}
+
+		return tx.Create(audit).Error
+	})
+}
+
+// RemoveItinerary exclui o roteiro (soft delete, como a exclusão pelo autor,
+// já que viagens, coleções e despesas apontam para ele) e dá o caso de
+// denúncias pendente como procedente
+func (r *ModerationRepository) RemoveItinerary(itineraryID uint, audit *models.ModerationAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var itinerary models.Itinerary
+		if err := tx.Unscoped().Where("id = ?", itineraryID).First(&itinerary).Error; err != nil {
+			return err
+		}
+
+		if err := closePendingReportCase(tx, models.ReportTargetItinerary, itineraryID, models.ReportStatusResolved, audit.AdminID, audit.Note); err != nil {
+			return err
+		}
+
+		if err := tx.Create(audit).Error; err != nil {
+			return err
+		}
+
+		// Roteiros já excluídos pelo autor não contam mais para o perfil
+		if itinerary.DeletedAt.Valid {
+			return nil
+		}
+
+		if err := tx.Delete(&models.Itinerary{}, itineraryID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", itinerary.AuthorID).
+			Update("itineraries_count", gorm.Expr("itineraries_count - 1")).Error
+	})
+}
+
+func (r *ModerationRepository) CreateMessageReport(report *models.MessageReport) error {
+	result := r.db.Omit("Reporter", "Sender").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(report)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyReported
+	}
+	return nil
+}
+
+func (r *ModerationRepository) GetMessageReportByID(id uint) (*models.MessageReport, error) {
+	var report models.MessageReport
+	err := r.db.Preload("Reporter").
+		Preload("Sender").
+		Where("id = ?", id).
+		First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetMessageReports lista a fila de moderação das mensagens, das denúncias
+// mais antigas para as mais recentes, sem o conteúdo denunciado
+func (r *ModerationRepository) GetMessageReports(status models.ReportStatus, limit, offset int) ([]models.MessageReport, error) {
+	var reports []models.MessageReport
+	err := r.db.Preload("Reporter").
+		Preload("Sender").
+		Omit("content").
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error
+	return reports, err
+}
+
+// ResolveMessageReport encerra uma denúncia pendente; retorna false se ela já
+// tinha sido decidida por outro admin
+func (r *ModerationRepository) ResolveMessageReport(report *models.MessageReport, status models.ReportStatus, audit *models.ModerationAction) (bool, error) {
+	now := time.Now()
+	resolved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.MessageReport{}).
+			Where("id = ? AND status = ?", report.ID, models.ReportStatusPending).
+			Updates(map[string]interface{}{
+				"status":          status,
+				"resolved_by_id":  report.ResolvedByID,
+				"resolution_note": report.ResolutionNote,
+				"resolved_at":     now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		resolved = true
+		return tx.Create(audit).Error
+	})
+	if err != nil || !resolved {
+		return false, err
+	}
+
+	report.Status = status
+	report.ResolvedAt = &now
+	return true, nil
+}
+
+// GetMessageForModeration busca a mensagem independente de ter sido removida
+func (r *ModerationRepository) GetMessageForModeration(messageID uint) (*models.Message, error) {
+	var message models.Message
+	err := r.db.Unscoped().Where("id = ?", messageID).First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetMessageContext retorna até count mensagens antes e count depois da
+// mensagem, em ordem cronológica, sem a própria mensagem
+func (r *ModerationRepository) GetMessageContext(conversationID, messageID uint, count int) ([]models.Message, error) {
+	var before, after []models.Message
+	err := r.db.Preload("Sender").
+		Where("conversation_id = ? AND id < ?", conversationID, messageID).
+		Order("id DESC").
+		Limit(count).
+		Find(&before).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Preload("Sender").
+		Where("conversation_id = ? AND id > ?", conversationID, messageID).
+		Order("id ASC").
+		Limit(count).
+		Find(&after).Error
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]models.Message, 0, len(before)+len(after))
+	for i := len(before) - 1; i >= 0; i-- {
+		messages = append(messages, before[i])
+	}
+	return append(messages, after...), nil
+}
+
+// RemoveMessage apaga a mensagem da conversa e dá as denúncias pendentes
+// como procedentes; a cópia do conteúdo nas denúncias fica como histórico.
+// Retorna false se a mensagem já estava apagada
+func (r *ModerationRepository) RemoveMessage(messageID uint, audit *models.ModerationAction) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Message{}, messageID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		removed = true
+
+		if err := tx.Model(&models.MessageReport{}).
+			Where("message_id = ? AND status = ?", messageID, models.ReportStatusPending).
+			Updates(map[string]interface{}{
+				"status":          models.ReportStatusResolved,
+				"resolved_by_id":  audit.AdminID,
+				"resolution_note": audit.Note,
+				"resolved_at":     time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(audit).Error
+	})
+	return removed, err
+}
+
+// CreateAction registra na auditoria uma ação que não altera o conteúdo,
+// como a leitura de uma mensagem denunciada
+func (r *ModerationRepository) CreateAction(audit *models.ModerationAction) error {
+	return r.db.Create(audit).Error
+}
+
+func (r *ModerationRepository) GetUserForModeration(userID uint) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// BanUser desativa a conta por tempo indeterminado, impedindo novos logins e
+// derrubando os tokens emitidos, e dá o caso de denúncias pendente contra o
+// usuário como procedente; uma suspensão em vigor vira banimento, e
+// administradores não podem ser banidos. Retorna false se a conta já estava
+// banida
+func (r *ModerationRepository) BanUser(userID uint, reason string, audit *models.ModerationAction) (bool, error) {
+	banned := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND user_type <> ? AND (suspended_at IS NULL OR suspended_until IS NOT NULL)", userID, models.UserTypeAdmin).
+			Updates(map[string]interface{}{
+				"is_active":         false,
+				"suspended_at":      time.Now(),
+				"suspended_until":   nil,
+				"suspension_reason": reason,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		banned = true
+		if err := closePendingAppeal(tx, userID); err != nil {
+			return err
+		}
+		if err := closePendingReportCase(tx, models.ReportTargetUser, userID, models.ReportStatusResolved, audit.AdminID, audit.Note); err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+	return banned, err
+}
+
+// GetMediaByModerationStatus lista as mídias da fila de moderação, das mais
+// antigas para as mais recentes
+func (r *ModerationRepository) GetMediaByModerationStatus(status models.MediaModerationStatus, limit, offset int) ([]models.Media, error) {
+	var media []models.Media
+	err := r.db.Preload("Owner").
+		Where("moderation_status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&media).Error
+	return media, err
+}
+
+func (r *ModerationRepository) GetMediaForModeration(mediaID uint) (*models.Media, error) {
+	var media models.Media
+	err := r.db.Preload("Owner").Where("id = ?", mediaID).First(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// ReviewMedia decide uma mídia sinalizada pela moderação automática; retorna
+// false se ela já tinha sido revisada por outro admin
+func (r *ModerationRepository) ReviewMedia(media *models.Media, status models.MediaModerationStatus, audit *models.ModerationAction) (bool, error) {
+	now := time.Now()
+	reviewed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Media{}).
+			Where("id = ? AND moderation_status = ?", media.ID, models.MediaModerationFlagged).
+			Updates(map[string]interface{}{
+				"moderation_status": status,
+				"reviewed_by_id":    audit.AdminID,
+				"reviewed_at":       now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		reviewed = true
+		return tx.Create(audit).Error
+	})
+	if err != nil || !reviewed {
+		return false, err
+	}
+
+	media.ModerationStatus = status
+	media.ReviewedByID = &audit.AdminID
+	media.ReviewedAt = &now
+	return true, nil
+}
+
+// GetModerationActions lista o histórico de auditoria, das ações mais recentes
+// para as mais antigas
+func (r *ModerationRepository) GetModerationActions(filter ModerationActionFilter, limit, offset int) ([]models.ModerationAction, error) {
+	query := r.db.Model(&models.ModerationAction{})
+	if filter.AdminID != 0 {
+		query = query.Where("admin_id = ?", filter.AdminID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+
+	var actions []models.ModerationAction
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&actions).Error
+	return actions, err
+}
+
+func (r *ModerationRepository) CreateBulkJob(job *models.BulkModerationJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *ModerationRepository) GetBulkJobByID(id uint) (*models.BulkModerationJob, error) {
+	var job models.BulkModerationJob
+	err := r.db.Where("id = ?", id).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateBulkJob grava o progresso e o estado do lote
+func (r *ModerationRepository) UpdateBulkJob(job *models.BulkModerationJob) error {
+	return r.db.Select("status", "processed", "applied", "skipped", "failed", "results", "started_at", "finished_at").
+		Updates(job).Error
+}
+
+// GetUnfinishedBulkJobs busca os lotes interrompidos, na ordem de criação
+func (r *ModerationRepository) GetUnfinishedBulkJobs() ([]models.BulkModerationJob, error) {
+	var jobs []models.BulkModerationJob
+	err := r.db.Where("status IN ?", []models.BulkModerationStatus{models.BulkModerationPending, models.BulkModerationRunning}).
+		Order("id ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// closePendingReportCase encerra o caso de denúncias pendente do alvo, se
+// houver, quando a moderação decide sobre ele fora da fila
+func closePendingReportCase(tx *gorm.DB, targetType models.ReportTargetType, targetID uint, status models.ReportStatus, adminID uint, note string) error {
+	return tx.Model(&models.ReportCase{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportStatusPending).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"resolved_by_id":  adminID,
+			"resolution_note": note,
+			"resolved_at":     time.Now(),
+		}).Error
+}
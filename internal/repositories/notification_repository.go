@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepositoryInterface interface {
+	Create(notification *models.Notification) (bool, error)
+	AddToGroup(notification *models.Notification, actorID uint, describe func(actorCount int) (string, string)) (bool, bool, error)
+	GetByID(userID, id uint) (*models.Notification, error)
+	GetActors(notificationID uint, limit, offset int) ([]models.User, error)
+	GetByUser(userID uint, limit, offset int) ([]models.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID uint, ids []uint) (int64, error)
+	MarkAllRead(userID uint) (int64, error)
+	GetUsersWithUnreadSince(since time.Time) ([]uint, error)
+	GetUnreadSince(userID uint, since time.Time, limit int) ([]models.Notification, int64, error)
+	GetPreferences(userID uint) ([]models.NotificationPreference, error)
+	GetPreference(userID uint, notificationType models.NotificationType) (*models.NotificationPreference, error)
+	SavePreferences(preferences []models.NotificationPreference) error
+	GetQuietHours(userID uint) (*models.QuietHours, error)
+	SaveQuietHours(quietHours *models.QuietHours) error
+	DeferPush(id uint, until time.Time) error
+	GetDueDeferredPushes(now time.Time, limit int) ([]models.Notification, error)
+	ClaimDeferredPush(id uint) (bool, error)
+}
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepositoryInterface {
+	return &NotificationRepository{db: db}
+}
+
+// Create grava a notificação; retorna false se já existia uma com a mesma chave
+func (r *NotificationRepository) Create(notification *models.Notification) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddToGroup junta o ator à notificação não lida do mesmo grupo ou cria uma
+// nova. describe gera título e corpo para o total de atores. Retorna se a
+// notificação foi criada e se o ator foi incluído (false quando ele já estava
+// no grupo). O lock por usuário e grupo impede que eventos simultâneos abram
+// dois grupos
+func (r *NotificationRepository) AddToGroup(notification *models.Notification, actorID uint, describe func(actorCount int) (string, string)) (bool, bool, error) {
+	var created, added bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", notification.UserID, *notification.GroupKey).Error; err != nil {
+			return err
+		}
+
+		var existing models.Notification
+		err := tx.Where("user_id = ? AND group_key = ? AND read_at IS NULL AND hidden_in_app = ?",
+			notification.UserID, *notification.GroupKey, notification.HiddenInApp).
+			Order("id DESC").
+			First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			notification.ActorCount = 1
+			notification.Title, notification.Body = describe(notification.ActorCount)
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+			created, added = true, true
+			return tx.Create(&models.NotificationActor{NotificationID: notification.ID, ActorID: actorID}).Error
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.NotificationActor{NotificationID: existing.ID, ActorID: actorID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			*notification = existing
+			return nil
+		}
+
+		// A notificação volta ao topo da lista com o evento mais recente
+		existing.ActorCount++
+		existing.Title, existing.Body = describe(existing.ActorCount)
+		existing.Data = notification.Data
+		existing.CreatedAt = time.Now()
+		if err := tx.Model(&existing).
+			Select("actor_count", "title", "body", "data", "created_at").
+			Updates(&existing).Error; err != nil {
+			return err
+		}
+		*notification = existing
+		added = true
+		return nil
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return created, added, nil
+}
+
+// GetByID busca uma notificação do app que pertence ao usuário
+func (r *NotificationRepository) GetByID(userID, id uint) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.Where("id = ? AND user_id = ? AND hidden_in_app = ?", id, userID, false).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// GetActors lista os usuários de uma notificação agrupada, do mais recente
+// para o mais antigo
+func (r *NotificationRepository) GetActors(notificationID uint, limit, offset int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Joins("JOIN notification_actors ON notification_actors.actor_id = users.id").
+		Where("notification_actors.notification_id = ?", notificationID).
+		Order("notification_actors.created_at DESC, notification_actors.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *NotificationRepository) GetByUser(userID uint, limit, offset int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Where("user_id = ? AND hidden_in_app = ?", userID, false).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread conta as notificações não lidas do usuário pelo índice parcial
+// idx_notifications_unread, sem carregar a lista. O filtro é literal para o
+// Postgres reconhecer o predicado do índice
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL AND hidden_in_app = false", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marca como lidas as notificações informadas que pertencem ao
+// usuário; retorna quantas ainda não estavam lidas
+func (r *NotificationRepository) MarkRead(userID uint, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND id IN ? AND read_at IS NULL", userID, ids).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *NotificationRepository) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL AND hidden_in_app = false", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// GetUsersWithUnreadSince lista os usuários com notificações não lidas
+// criadas a partir de since
+func (r *NotificationRepository) GetUsersWithUnreadSince(since time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.Notification{}).
+		Where("read_at IS NULL AND hidden_in_app = ? AND created_at >= ?", false, since).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetUnreadSince retorna as notificações não lidas mais recentes do usuário
+// desde since e o total delas
+func (r *NotificationRepository) GetUnreadSince(userID uint, since time.Time, limit int) ([]models.Notification, int64, error) {
+	var total int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL AND hidden_in_app = ? AND created_at >= ?", userID, false, since).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	err = r.db.Where("user_id = ? AND read_at IS NULL AND hidden_in_app = ? AND created_at >= ?", userID, false, since).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *NotificationRepository) GetPreferences(userID uint) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+func (r *NotificationRepository) GetPreference(userID uint, notificationType models.NotificationType) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.db.Where("user_id = ? AND type = ?", userID, notificationType).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SavePreferences grava as preferências de uma vez, substituindo as
+// existentes para o mesmo tipo
+func (r *NotificationRepository) SavePreferences(preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"in_app", "push", "email", "updated_at"}),
+	}).Create(&preferences).Error
+}
+
+func (r *NotificationRepository) GetQuietHours(userID uint) (*models.QuietHours, error) {
+	var quietHours models.QuietHours
+	err := r.db.Where("user_id = ?", userID).First(&quietHours).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quietHours, nil
+}
+
+func (r *NotificationRepository) SaveQuietHours(quietHours *models.QuietHours) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "start_minute", "end_minute", "updated_at"}),
+	}).Create(quietHours).Error
+}
+
+// DeferPush segura o push da notificação até o fim do horário de silêncio
+func (r *NotificationRepository) DeferPush(id uint, until time.Time) error {
+	return r.db.Model(&models.Notification{}).Where("id = ?", id).Update("push_deferred_until", until).Error
+}
+
+// GetDueDeferredPushes lista os pushes segurados cujo horário de silêncio já
+// terminou, agrupados por usuário
+func (r *NotificationRepository) GetDueDeferredPushes(now time.Time, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Where("push_deferred_until IS NOT NULL AND push_deferred_until <= ?", now).
+		Order("user_id, created_at").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// ClaimDeferredPush libera o push segurado; false se outra instância já o
+// enviou
+func (r *NotificationRepository) ClaimDeferredPush(id uint) (bool, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND push_deferred_until IS NOT NULL", id).
+		Update("push_deferred_until", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
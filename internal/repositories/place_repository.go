@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlaceRepositoryInterface interface {
+	GetDetails(placeID string) (*models.PlaceDetails, error)
+	SaveDetails(details *models.PlaceDetails) error
+}
+
+type PlaceRepository struct {
+	db *gorm.DB
+}
+
+func NewPlaceRepository(db *gorm.DB) PlaceRepositoryInterface {
+	return &PlaceRepository{db: db}
+}
+
+func (r *PlaceRepository) GetDetails(placeID string) (*models.PlaceDetails, error) {
+	var details models.PlaceDetails
+	err := r.db.Where("place_id = ?", placeID).First(&details).Error
+	if err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+func (r *PlaceRepository) SaveDetails(details *models.PlaceDetails) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "place_id"}},
+		UpdateAll: true,
+	}).Create(details).Error
+}
@@ -1,22 +1,48 @@
 package repositories
 
 import (
+	"sort"
+	"time"
+
 	"github.com/Ulpio/guIA-backend/internal/models"
 	"gorm.io/gorm"
 )
 
 type PostRepositoryInterface interface {
 	Create(post *models.Post) error
-	GetByID(id uint) (*models.Post, error)
+	GetByID(id, viewerID uint) (*models.Post, error)
+	GetByIDs(ids []uint, viewerID uint) ([]models.Post, error)
 	Update(post *models.Post) error
 	Delete(id uint) error
-	GetFeed(userID uint, limit, offset int) ([]models.Post, error)
-	GetByAuthor(authorID uint, limit, offset int) ([]models.Post, error)
+	GetFeed(userID uint, cursor *PostCursor, limit, offset int, options FeedOptions) ([]models.Post, error)
+	GetSuggestedPosts(userID uint, from, to time.Time, limit int, options FeedOptions) ([]models.Post, error)
+	GetByAuthor(authorID, viewerID uint, cursor *PostCursor, limit, offset int) ([]models.Post, error)
 	LikePost(userID, postID uint) error
 	UnlikePost(userID, postID uint) error
 	IsLiked(userID, postID uint) (bool, error)
-	SearchPosts(query string, limit, offset int) ([]models.Post, error)
-	GetTrendingPosts(limit, offset int) ([]models.Post, error)
+	GetLikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error)
+	GetLikers(postID uint, limit, offset int) ([]models.User, error)
+	SearchPosts(query string, area *GeoArea, viewerID uint, limit, offset int) ([]models.Post, error)
+	GetTrendingPosts(cursor *PostCursor, limit, offset int) ([]models.Post, error)
+	GetByItinerary(itineraryID, viewerID uint, limit, offset int) ([]models.Post, error)
+	GetNearby(latitude, longitude, radiusKm float64, viewerID uint, limit, offset int) ([]models.Post, error)
+	RecordEvents(events []models.PostEvent) error
+	SharePost(userID, postID uint) error
+	GetInsightsSeries(postID uint, from, to time.Time) ([]models.PostInsightsPoint, error)
+	GetReach(postID uint, from, to time.Time) (int64, error)
+}
+
+// PostCursor marca o último post retornado para paginação keyset; quando
+// informado, substitui o offset
+type PostCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+	Score     int       `json:"s,omitempty"` // pontuação do ranking de posts em alta
+}
+
+// FeedOptions aplica as preferências do usuário na montagem do feed
+type FeedOptions struct {
+	HideReposts bool // exclui posts que vinculam roteiros de outros autores
 }
 
 type PostRepository struct {
@@ -40,11 +66,13 @@ func (r *PostRepository) Create(post *models.Post) error {
 	})
 }
 
-func (r *PostRepository) GetByID(id uint) (*models.Post, error) {
+func (r *PostRepository) GetByID(id, viewerID uint) (*models.Post, error) {
 	var post models.Post
 	err := r.db.Preload("Author").
 		Preload("Likes").
+		Preload("Itinerary").
 		Preload("Comments").
+		Scopes(visibleTo(viewerID)).
 		Where("id = ? AND is_active = ?", id, true).
 		First(&post).Error
 	if err != nil {
@@ -53,6 +81,22 @@ func (r *PostRepository) GetByID(id uint) (*models.Post, error) {
 	return &post, nil
 }
 
+// GetByIDs retorna os posts ativos visíveis ao usuário dentre os IDs, sem
+// ordem definida
+func (r *PostRepository) GetByIDs(ids []uint, viewerID uint) ([]models.Post, error) {
+	var posts []models.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	err := r.db.Preload("Author").
+		Preload("Likes").
+		Preload("Itinerary").
+		Scopes(visibleTo(viewerID)).
+		Where("id IN ? AND is_active = ?", ids, true).
+		Find(&posts).Error
+	return posts, err
+}
+
 func (r *PostRepository) Update(post *models.Post) error {
 	return r.db.Save(post).Error
 }
@@ -76,33 +120,54 @@ func (r *PostRepository) Delete(id uint) error {
 	})
 }
 
-func (r *PostRepository) GetFeed(userID uint, limit, offset int) ([]models.Post, error) {
+func (r *PostRepository) GetFeed(userID uint, cursor *PostCursor, limit, offset int, options FeedOptions) ([]models.Post, error) {
 	var posts []models.Post
 
 	// Buscar posts dos usuários que o usuário segue + próprios posts
 	err := r.db.Preload("Author").
 		Preload("Likes").
+		Preload("Itinerary").
+		Scopes(visibleTo(userID), pageAfter(cursor, offset), withFeedOptions(options)).
 		Where(`author_id IN (
 			SELECT followed_id FROM follows WHERE follower_id = ?
 			UNION
 			SELECT ?
 		) AND is_active = ?`, userID, userID, true).
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(limit).
-		Offset(offset).
 		Find(&posts).Error
 
 	return posts, err
 }
 
-func (r *PostRepository) GetByAuthor(authorID uint, limit, offset int) ([]models.Post, error) {
+// GetSuggestedPosts retorna posts públicos em alta, publicados no intervalo,
+// de autores que o usuário ainda não segue
+func (r *PostRepository) GetSuggestedPosts(userID uint, from, to time.Time, limit int, options FeedOptions) ([]models.Post, error) {
 	var posts []models.Post
 	err := r.db.Preload("Author").
 		Preload("Likes").
+		Preload("Itinerary").
+		Scopes(withFeedOptions(options)).
+		Where("visibility = ? AND is_active = ?", models.PostVisibilityPublic, true).
+		Where(`author_id <> ? AND author_id NOT IN (
+			SELECT followed_id FROM follows WHERE follower_id = ?
+		)`, userID, userID).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("(likes_count * 2 + comments_count) DESC, created_at DESC, id DESC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostRepository) GetByAuthor(authorID, viewerID uint, cursor *PostCursor, limit, offset int) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Preload("Author").
+		Preload("Likes").
+		Preload("Itinerary").
+		Scopes(visibleTo(viewerID), pageAfter(cursor, offset)).
 		Where("author_id = ? AND is_active = ?", authorID, true).
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(limit).
-		Offset(offset).
 		Find(&posts).Error
 	return posts, err
 }
@@ -158,30 +223,256 @@ func (r *PostRepository) IsLiked(userID, postID uint) (bool, error) {
 	return count > 0, err
 }
 
-func (r *PostRepository) SearchPosts(query string, limit, offset int) ([]models.Post, error) {
+func (r *PostRepository) GetLikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&models.PostLike{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// GetLikers lista os usuários que curtiram o post, das curtidas mais recentes
+// para as mais antigas
+func (r *PostRepository) GetLikers(postID uint, limit, offset int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Joins("JOIN post_likes ON post_likes.user_id = users.id").
+		Where("post_likes.post_id = ? AND users.is_active = ?", postID, true).
+		Order("post_likes.created_at DESC, post_likes.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	return users, err
+}
+
+// SearchPosts busca no texto e no local dos posts, dos mais relevantes para
+// os menos. Com uma área, só os posts com coordenadas dentro dela; sem
+// texto, os mais próximos do centro do raio ou, num retângulo, os mais
+// recentes
+func (r *PostRepository) SearchPosts(query string, area *GeoArea, viewerID uint, limit, offset int) ([]models.Post, error) {
 	var posts []models.Post
-	searchQuery := "%" + query + "%"
-	err := r.db.Preload("Author").
+	terms := searchTerms(query)
+	if terms == "" && area == nil {
+		return posts, nil
+	}
+
+	db := r.db.Preload("Author").
 		Preload("Likes").
-		Where("(content ILIKE ? OR location ILIKE ?) AND is_active = ?", searchQuery, searchQuery, true).
-		Order("created_at DESC").
-		Limit(limit).
+		Preload("Itinerary").
+		Scopes(visibleTo(viewerID)).
+		Where("posts.is_active = ?", true)
+
+	if area != nil {
+		db = db.Where(withinArea("posts.geo_point", area))
+	}
+	switch {
+	case terms != "":
+		db = db.Scopes(fullTextSearch("posts", terms))
+	case area.Box == nil:
+		db = db.Order(gorm.Expr("? ASC, posts.created_at DESC", distanceFrom("posts.geo_point", area)))
+	default:
+		db = db.Order("posts.created_at DESC")
+	}
+
+	err := db.Limit(limit).
 		Offset(offset).
 		Find(&posts).Error
 	return posts, err
 }
 
-func (r *PostRepository) GetTrendingPosts(limit, offset int) ([]models.Post, error) {
+func (r *PostRepository) GetTrendingPosts(cursor *PostCursor, limit, offset int) ([]models.Post, error) {
 	var posts []models.Post
 
+	query := r.db.Preload("Author").
+		Preload("Likes").
+		Preload("Itinerary")
+
+	if cursor != nil {
+		query = query.Where(`((likes_count * 2 + comments_count) < ? OR
+			((likes_count * 2 + comments_count) = ? AND (created_at, id) < (?, ?)))`,
+			cursor.Score, cursor.Score, cursor.CreatedAt, cursor.ID)
+	} else {
+		query = query.Offset(offset)
+	}
+
 	// Posts trending baseado em curtidas e comentários recentes
+	err := query.
+		Where("is_active = ? AND visibility = ? AND created_at > NOW() - INTERVAL '7 days'", true, models.PostVisibilityPublic).
+		Order("(likes_count * 2 + comments_count) DESC, created_at DESC, id DESC").
+		Limit(limit).
+		Find(&posts).Error
+
+	return posts, err
+}
+
+func (r *PostRepository) GetByItinerary(itineraryID, viewerID uint, limit, offset int) ([]models.Post, error) {
+	var posts []models.Post
 	err := r.db.Preload("Author").
 		Preload("Likes").
-		Where("is_active = ? AND created_at > NOW() - INTERVAL '7 days'", true).
-		Order("(likes_count * 2 + comments_count) DESC, created_at DESC").
+		Scopes(visibleTo(viewerID)).
+		Where("itinerary_id = ? AND is_active = ?", itineraryID, true).
+		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&posts).Error
-
 	return posts, err
 }
+
+// GetNearby busca posts dentro do raio informado, dos mais próximos para os
+// mais distantes, pelo índice GiST da coluna geo_point
+func (r *PostRepository) GetNearby(latitude, longitude, radiusKm float64, viewerID uint, limit, offset int) ([]models.Post, error) {
+	area := &GeoArea{Latitude: latitude, Longitude: longitude, RadiusKm: radiusKm}
+	return r.SearchPosts("", area, viewerID, limit, offset)
+}
+
+// RecordEvents grava em lote as exibições de posts
+func (r *PostRepository) RecordEvents(events []models.PostEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(&events, 100).Error
+}
+
+func (r *PostRepository) SharePost(userID, postID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		event := &models.PostEvent{
+			PostID:   postID,
+			ViewerID: userID,
+			Kind:     models.PostEventShare,
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+
+		// Atualizar contador de compartilhamentos do post
+		return tx.Model(&models.Post{}).Where("id = ?", postID).
+			Update("shares_count", gorm.Expr("shares_count + 1")).Error
+	})
+}
+
+// GetInsightsSeries agrega por dia as exibições, curtidas, comentários e
+// compartilhamentos do post no período [from, to); dias sem atividade são omitidos
+func (r *PostRepository) GetInsightsSeries(postID uint, from, to time.Time) ([]models.PostInsightsPoint, error) {
+	type dailyCount struct {
+		Day   time.Time
+		Kind  models.PostEventKind
+		Total int64
+	}
+
+	var eventCounts []dailyCount
+	err := r.db.Model(&models.PostEvent{}).
+		Select("DATE(created_at) AS day, kind, COUNT(*) AS total").
+		Where("post_id = ? AND created_at >= ? AND created_at < ?", postID, from, to).
+		Group("day, kind").
+		Scan(&eventCounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var likeCounts []dailyCount
+	err = r.db.Model(&models.PostLike{}).
+		Select("DATE(created_at) AS day, COUNT(*) AS total").
+		Where("post_id = ? AND created_at >= ? AND created_at < ?", postID, from, to).
+		Group("day").
+		Scan(&likeCounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var commentCounts []dailyCount
+	err = r.db.Model(&models.Comment{}).
+		Select("DATE(created_at) AS day, COUNT(*) AS total").
+		Where("post_id = ? AND created_at >= ? AND created_at < ?", postID, from, to).
+		Group("day").
+		Scan(&commentCounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	points := make(map[string]*models.PostInsightsPoint)
+	point := func(day time.Time) *models.PostInsightsPoint {
+		date := day.Format("2006-01-02")
+		if points[date] == nil {
+			points[date] = &models.PostInsightsPoint{Date: date}
+		}
+		return points[date]
+	}
+
+	for _, count := range eventCounts {
+		switch count.Kind {
+		case models.PostEventImpression:
+			point(count.Day).Impressions += count.Total
+		case models.PostEventView:
+			point(count.Day).Views += count.Total
+		case models.PostEventShare:
+			point(count.Day).Shares += count.Total
+		}
+	}
+	for _, count := range likeCounts {
+		point(count.Day).Likes += count.Total
+	}
+	for _, count := range commentCounts {
+		point(count.Day).Comments += count.Total
+	}
+
+	series := make([]models.PostInsightsPoint, 0, len(points))
+	for _, p := range points {
+		series = append(series, *p)
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Date < series[j].Date })
+
+	return series, nil
+}
+
+// GetReach conta os usuários distintos que viram o post no período
+func (r *PostRepository) GetReach(postID uint, from, to time.Time) (int64, error) {
+	var reach int64
+	err := r.db.Model(&models.PostEvent{}).
+		Where("post_id = ? AND kind IN ? AND created_at >= ? AND created_at < ?",
+			postID, []models.PostEventKind{models.PostEventImpression, models.PostEventView}, from, to).
+		Distinct("viewer_id").
+		Count(&reach).Error
+	return reach, err
+}
+
+// pageAfter aplica a paginação keyset (created_at, id) quando há cursor e o
+// offset tradicional caso contrário
+func pageAfter(cursor *PostCursor, offset int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if cursor == nil {
+			return db.Offset(offset)
+		}
+		return db.Where("(posts.created_at, posts.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+}
+
+func withFeedOptions(options FeedOptions) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if options.HideReposts {
+			db = db.Where(`NOT EXISTS (
+				SELECT 1 FROM itineraries WHERE itineraries.id = posts.itinerary_id AND itineraries.author_id <> posts.author_id
+			)`)
+		}
+		return db
+	}
+}
+
+// visibleTo restringe os posts ao que o usuário pode ver: públicos, os
+// próprios e os "somente seguidores" de quem ele segue
+func visibleTo(viewerID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`(posts.visibility = ? OR posts.author_id = ? OR (posts.visibility = ? AND posts.author_id IN (
+			SELECT followed_id FROM follows WHERE follower_id = ?
+		)))`, models.PostVisibilityPublic, viewerID, models.PostVisibilityFollowers, viewerID)
+	}
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RatingRepositoryInterface interface {
+	GetByID(id uint) (*models.ItineraryRating, error)
+	GetByItinerary(itineraryID uint, sort string, limit, offset int) ([]models.ItineraryRating, error)
+	GetDistribution(itineraryID uint) (map[int]int64, error)
+	SetReply(id uint, reply string, repliedAt *time.Time) error
+	Vote(ratingID, userID uint, helpful bool) error
+	RemoveVote(ratingID, userID uint) (bool, error)
+	GetUserVotes(userID uint, ratingIDs []uint) (map[uint]bool, error)
+}
+
+type RatingRepository struct {
+	db *gorm.DB
+}
+
+func NewRatingRepository(db *gorm.DB) RatingRepositoryInterface {
+	return &RatingRepository{db: db}
+}
+
+func (r *RatingRepository) GetByID(id uint) (*models.ItineraryRating, error) {
+	var rating models.ItineraryRating
+	err := r.db.Preload("User").Where("id = ?", id).First(&rating).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// GetByItinerary lista as avaliações do roteiro, sem as ocultadas pela
+// moderação; sort aceita "highest", "lowest", "helpful" ou vazio (mais
+// recentes)
+func (r *RatingRepository) GetByItinerary(itineraryID uint, sort string, limit, offset int) ([]models.ItineraryRating, error) {
+	var ratings []models.ItineraryRating
+
+	query := r.db.Preload("User").Where("itinerary_id = ? AND hidden_at IS NULL", itineraryID)
+
+	switch sort {
+	case "highest":
+		query = query.Order("rating DESC, created_at DESC")
+	case "lowest":
+		query = query.Order("rating ASC, created_at DESC")
+	case "helpful":
+		query = query.Order("helpful_count - unhelpful_count DESC, helpful_count DESC, created_at DESC")
+	default:
+		query = query.Order("created_at DESC")
+	}
+
+	err := query.Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&ratings).Error
+	return ratings, err
+}
+
+// GetDistribution conta as avaliações do roteiro por nota
+func (r *RatingRepository) GetDistribution(itineraryID uint) (map[int]int64, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+
+	err := r.db.Model(&models.ItineraryRating{}).
+		Select("rating, COUNT(*) AS count").
+		Where("itinerary_id = ? AND hidden_at IS NULL", itineraryID).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		distribution[row.Rating] = row.Count
+	}
+	return distribution, nil
+}
+
+// SetReply grava a resposta do autor; reply vazio e repliedAt nil removem a
+// resposta. UpdateColumns evita mexer no updated_at da avaliação
+func (r *RatingRepository) SetReply(id uint, reply string, repliedAt *time.Time) error {
+	return r.db.Model(&models.ItineraryRating{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"reply":      reply,
+			"replied_at": repliedAt,
+		}).Error
+}
+
+// Vote registra ou troca o voto do usuário e recalcula os contadores da
+// avaliação
+func (r *RatingRepository) Vote(ratingID, userID uint, helpful bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		vote := &models.ItineraryRatingVote{
+			RatingID: ratingID,
+			UserID:   userID,
+			Helpful:  helpful,
+		}
+		err := tx.Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "rating_id"}, {Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"helpful", "updated_at"}),
+			}).
+			Create(vote).Error
+		if err != nil {
+			return err
+		}
+
+		return r.updateVoteCounts(tx, ratingID)
+	})
+}
+
+// RemoveVote retorna false se o usuário não tinha votado
+func (r *RatingRepository) RemoveVote(ratingID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("rating_id = ? AND user_id = ?", ratingID, userID).
+			Delete(&models.ItineraryRatingVote{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		removed = true
+		return r.updateVoteCounts(tx, ratingID)
+	})
+	return removed, err
+}
+
+// GetUserVotes retorna os votos do usuário nas avaliações informadas
+func (r *RatingRepository) GetUserVotes(userID uint, ratingIDs []uint) (map[uint]bool, error) {
+	votes := make(map[uint]bool)
+	if len(ratingIDs) == 0 {
+		return votes, nil
+	}
+
+	var rows []models.ItineraryRatingVote
+	err := r.db.Where("user_id = ? AND rating_id IN ?", userID, ratingIDs).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		votes[row.RatingID] = row.Helpful
+	}
+	return votes, nil
+}
+
+// updateVoteCounts recalcula os contadores a partir dos votos, o que mantém
+// os números corretos mesmo com votos trocados em paralelo
+func (r *RatingRepository) updateVoteCounts(tx *gorm.DB, ratingID uint) error {
+	return tx.Model(&models.ItineraryRating{}).Where("id = ?", ratingID).
+		UpdateColumns(map[string]interface{}{
+			"helpful_count":   gorm.Expr("(SELECT COUNT(*) FROM itinerary_rating_votes WHERE rating_id = ? AND helpful)", ratingID),
+			"unhelpful_count": gorm.Expr("(SELECT COUNT(*) FROM itinerary_rating_votes WHERE rating_id = ? AND NOT helpful)", ratingID),
+		}).Error
+}
@@ -0,0 +1,306 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type RecommendationRepositoryInterface interface {
+	SimilarUsersItineraries(userID uint, neighbors, limit int) ([]RecommendationCandidate, error)
+	FollowingItineraries(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error)
+	InterestItineraries(userID uint, limit int) ([]RecommendationCandidate, error)
+	PopularItineraries(userID uint, limit int) ([]RecommendationCandidate, error)
+	SimilarUsersPosts(userID uint, since time.Time, neighbors, limit int) ([]RecommendationCandidate, error)
+	FollowingPosts(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error)
+	InterestPosts(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error)
+	PopularPosts(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error)
+	Replace(userID uint, recommendations []models.Recommendation) error
+	GetByUser(userID uint, kind models.RecommendationKind, limit int) ([]models.Recommendation, error)
+	GetUsersPendingRecommendations(generatedBefore time.Time, afterID uint, limit int) ([]uint, error)
+}
+
+// RecommendationCandidate é um item sugerido por um dos sinais, com a
+// pontuação na escala do próprio sinal
+type RecommendationCandidate struct {
+	ItemID uint
+	Score  float64
+}
+
+// itineraryInteractions são as interações positivas com roteiros: curtidas,
+// roteiros salvos em coleções e avaliações a partir de 4 estrelas
+const itineraryInteractions = `interactions AS (
+	SELECT user_id, itinerary_id FROM itinerary_likes
+	UNION
+	SELECT collections.user_id, collection_items.itinerary_id FROM collection_items
+	JOIN collections ON collections.id = collection_items.collection_id
+	UNION
+	SELECT user_id, itinerary_id FROM itinerary_ratings WHERE rating >= 4
+)`
+
+// itineraryInterests pesa as categorias e os países dos roteiros com que o
+// usuário interagiu, criou ou agendou, e das intenções de viagem ativas
+const itineraryInterests = `engaged AS (
+	SELECT itinerary_id FROM interactions WHERE user_id = @user
+	UNION
+	SELECT id FROM itineraries WHERE author_id = @user AND deleted_at IS NULL
+	UNION
+	SELECT itinerary_id FROM trips WHERE user_id = @user AND deleted_at IS NULL
+),
+intents AS (
+	SELECT style, country_code FROM travel_intents
+	WHERE user_id = @user AND is_active = true AND deleted_at IS NULL AND end_date >= @now
+),
+categories AS (
+	SELECT category, SUM(weight) AS weight FROM (
+		SELECT category, 1 AS weight FROM itineraries WHERE id IN (SELECT itinerary_id FROM engaged)
+		UNION ALL
+		SELECT style, 3 FROM intents WHERE style <> ''
+	) c GROUP BY category
+),
+countries AS (
+	SELECT country_id, SUM(weight) AS weight FROM (
+		SELECT country_id, 1 AS weight FROM itineraries
+		WHERE id IN (SELECT itinerary_id FROM engaged) AND country_id IS NOT NULL
+		UNION ALL
+		SELECT geo_countries.id, 3 FROM intents JOIN geo_countries ON geo_countries.code = intents.country_code
+	) c GROUP BY country_id
+)`
+
+// recommendableItinerary são os roteiros que podem ser recomendados: públicos,
+// visíveis, de outros autores sem bloqueio entre eles e o usuário, e com que
+// ele ainda não interagiu
+const recommendableItinerary = `i.deleted_at IS NULL AND i.is_public = true AND i.hidden_at IS NULL
+	AND i.author_id <> @user
+	AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE (b.blocker_id = @user AND b.blocked_id = i.author_id) OR (b.blocker_id = i.author_id AND b.blocked_id = @user))
+	AND i.id NOT IN (SELECT itinerary_id FROM interactions WHERE user_id = @user)`
+
+// recommendablePost são os posts públicos recentes que podem ser
+// recomendados: de autores que o usuário não segue (esses já estão no feed),
+// sem bloqueio entre eles e ainda não curtidos
+const recommendablePost = `p.deleted_at IS NULL AND p.is_active = true AND p.hidden_at IS NULL
+	AND p.visibility = @public AND p.created_at >= @since
+	AND p.author_id <> @user
+	AND p.author_id NOT IN (SELECT followed_id FROM follows WHERE follower_id = @user)
+	AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE (b.blocker_id = @user AND b.blocked_id = p.author_id) OR (b.blocker_id = p.author_id AND b.blocked_id = @user))
+	AND NOT EXISTS (SELECT 1 FROM post_likes WHERE post_likes.post_id = p.id AND post_likes.user_id = @user)`
+
+type RecommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepositoryInterface {
+	return &RecommendationRepository{db: db}
+}
+
+// SimilarUsersItineraries faz a co-visitação: acha os usuários que mais
+// interagiram com os mesmos roteiros que o usuário e soma, para cada roteiro
+// deles, quantos roteiros em comum cada um tem
+func (r *RecommendationRepository) SimilarUsersItineraries(userID uint, neighbors, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		WITH `+itineraryInteractions+`,
+		neighbors AS (
+			SELECT other.user_id, COUNT(*) AS overlap
+			FROM interactions mine
+			JOIN interactions other ON other.itinerary_id = mine.itinerary_id AND other.user_id <> mine.user_id
+			WHERE mine.user_id = @user
+			GROUP BY other.user_id
+			ORDER BY overlap DESC
+			LIMIT @neighbors
+		)
+		SELECT i.id AS item_id, SUM(neighbors.overlap) AS score
+		FROM neighbors
+		JOIN interactions ON interactions.user_id = neighbors.user_id
+		JOIN itineraries i ON i.id = interactions.itinerary_id
+		WHERE `+recommendableItinerary+`
+		GROUP BY i.id
+		ORDER BY score DESC, i.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("neighbors", neighbors), sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// FollowingItineraries sugere os roteiros recentes de quem o usuário segue e
+// os que essas pessoas curtiram ou salvaram
+func (r *RecommendationRepository) FollowingItineraries(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		WITH `+itineraryInteractions+`,
+		following AS (SELECT followed_id FROM follows WHERE follower_id = @user),
+		signals AS (
+			SELECT id AS itinerary_id, 2 AS weight FROM itineraries
+			WHERE author_id IN (SELECT followed_id FROM following) AND created_at >= @since
+			UNION ALL
+			SELECT itinerary_id, 1 FROM interactions WHERE user_id IN (SELECT followed_id FROM following)
+		)
+		SELECT i.id AS item_id, SUM(signals.weight) AS score
+		FROM signals
+		JOIN itineraries i ON i.id = signals.itinerary_id
+		WHERE `+recommendableItinerary+`
+		GROUP BY i.id
+		ORDER BY score DESC, i.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("since", since), sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// InterestItineraries sugere roteiros das categorias e dos países de
+// interesse do usuário, favorecendo os mais curtidos e clonados
+func (r *RecommendationRepository) InterestItineraries(userID uint, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		WITH `+itineraryInteractions+`,
+		`+itineraryInterests+`
+		SELECT i.id AS item_id,
+			(COALESCE(categories.weight, 0) + COALESCE(countries.weight, 0) * 2) * LN(2 + i.likes_count + i.clones_count) AS score
+		FROM itineraries i
+		LEFT JOIN categories ON categories.category = i.category
+		LEFT JOIN countries ON countries.country_id = i.country_id
+		WHERE (categories.weight IS NOT NULL OR countries.weight IS NOT NULL)
+		AND `+recommendableItinerary+`
+		ORDER BY score DESC, i.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("now", time.Now()), sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// PopularItineraries completa a lista de quem ainda tem poucas interações
+func (r *RecommendationRepository) PopularItineraries(userID uint, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		WITH `+itineraryInteractions+`
+		SELECT i.id AS item_id, 1 + i.likes_count * 2 + i.clones_count * 3 + i.ratings_count AS score
+		FROM itineraries i
+		WHERE `+recommendableItinerary+`
+		ORDER BY score DESC, i.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// SimilarUsersPosts é a co-visitação dos posts, pelas curtidas
+func (r *RecommendationRepository) SimilarUsersPosts(userID uint, since time.Time, neighbors, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		WITH neighbors AS (
+			SELECT other.user_id, COUNT(*) AS overlap
+			FROM post_likes mine
+			JOIN post_likes other ON other.post_id = mine.post_id AND other.user_id <> mine.user_id
+			WHERE mine.user_id = @user
+			GROUP BY other.user_id
+			ORDER BY overlap DESC
+			LIMIT @neighbors
+		)
+		SELECT p.id AS item_id, SUM(neighbors.overlap) AS score
+		FROM neighbors
+		JOIN post_likes ON post_likes.user_id = neighbors.user_id
+		JOIN posts p ON p.id = post_likes.post_id
+		WHERE `+recommendablePost+`
+		GROUP BY p.id
+		ORDER BY score DESC, p.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("public", models.PostVisibilityPublic), sql.Named("since", since),
+		sql.Named("neighbors", neighbors), sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// FollowingPosts sugere os posts curtidos por quem o usuário segue
+func (r *RecommendationRepository) FollowingPosts(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		SELECT p.id AS item_id, COUNT(*) AS score
+		FROM post_likes
+		JOIN posts p ON p.id = post_likes.post_id
+		WHERE post_likes.user_id IN (SELECT followed_id FROM follows WHERE follower_id = @user)
+		AND `+recommendablePost+`
+		GROUP BY p.id
+		ORDER BY score DESC, p.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("public", models.PostVisibilityPublic), sql.Named("since", since),
+		sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// InterestPosts sugere posts vinculados a roteiros das categorias e dos
+// países de interesse do usuário
+func (r *RecommendationRepository) InterestPosts(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		WITH `+itineraryInteractions+`,
+		`+itineraryInterests+`
+		SELECT p.id AS item_id,
+			(COALESCE(categories.weight, 0) + COALESCE(countries.weight, 0) * 2) * LN(2 + p.likes_count * 2 + p.comments_count) AS score
+		FROM posts p
+		JOIN itineraries i ON i.id = p.itinerary_id AND i.deleted_at IS NULL
+		LEFT JOIN categories ON categories.category = i.category
+		LEFT JOIN countries ON countries.country_id = i.country_id
+		WHERE (categories.weight IS NOT NULL OR countries.weight IS NOT NULL)
+		AND `+recommendablePost+`
+		ORDER BY score DESC, p.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("now", time.Now()), sql.Named("public", models.PostVisibilityPublic),
+		sql.Named("since", since), sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// PopularPosts completa a lista com os posts recentes de mais engajamento
+func (r *RecommendationRepository) PopularPosts(userID uint, since time.Time, limit int) ([]RecommendationCandidate, error) {
+	var candidates []RecommendationCandidate
+	err := r.db.Raw(`
+		SELECT p.id AS item_id, 1 + p.likes_count * 2 + p.comments_count AS score
+		FROM posts p
+		WHERE `+recommendablePost+`
+		ORDER BY score DESC, p.id DESC
+		LIMIT @limit`,
+		sql.Named("user", userID), sql.Named("public", models.PostVisibilityPublic), sql.Named("since", since),
+		sql.Named("limit", limit),
+	).Scan(&candidates).Error
+	return candidates, err
+}
+
+// Replace troca as recomendações do usuário pelas recém-calculadas
+func (r *RecommendationRepository) Replace(userID uint, recommendations []models.Recommendation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if len(recommendations) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&recommendations, 100).Error
+	})
+}
+
+func (r *RecommendationRepository) GetByUser(userID uint, kind models.RecommendationKind, limit int) ([]models.Recommendation, error) {
+	var recommendations []models.Recommendation
+	err := r.db.Where("user_id = ? AND kind = ?", userID, kind).
+		Order("score DESC, id ASC").
+		Limit(limit).
+		Find(&recommendations).Error
+	return recommendations, err
+}
+
+// GetUsersPendingRecommendations retorna os usuários ativos sem recomendações
+// calculadas desde generatedBefore, em ordem de ID
+func (r *RecommendationRepository) GetUsersPendingRecommendations(generatedBefore time.Time, afterID uint, limit int) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Raw(`
+		SELECT u.id FROM users u
+		WHERE u.deleted_at IS NULL AND u.is_active = true AND u.id > ?
+		AND NOT EXISTS (SELECT 1 FROM recommendations r WHERE r.user_id = u.id AND r.created_at >= ?)
+		ORDER BY u.id
+		LIMIT ?`,
+		afterID, generatedBefore, limit,
+	).Scan(&userIDs).Error
+	return userIDs, err
+}
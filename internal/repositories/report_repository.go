@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportRepositoryInterface interface {
+	Create(report *models.Report, targetOwnerID uint, hideThreshold int) (*models.ReportCase, bool, error)
+	Flag(targetType models.ReportTargetType, targetID, targetOwnerID uint, reason models.ReportReason, details string) error
+	GetCaseByID(id uint) (*models.ReportCase, error)
+	GetCases(filter ReportCaseFilter, limit, offset int) ([]models.ReportCase, error)
+	AssignCase(reportCase *models.ReportCase, assigneeID *uint) (bool, error)
+	ResolveCase(reportCase *models.ReportCase, status models.ReportStatus, audit *models.ModerationAction) (bool, error)
+}
+
+// ReportCaseFilter restringe a fila de casos; campos vazios não filtram
+type ReportCaseFilter struct {
+	Status       models.ReportStatus
+	TargetType   models.ReportTargetType
+	AssignedToID uint
+	Unassigned   bool
+	Flagged      bool // só os casos sinalizados automaticamente
+	// Ordena pelos casos com mais denúncias em vez dos mais antigos
+	MostReported bool
+}
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepositoryInterface {
+	return &ReportRepository{db: db}
+}
+
+// Create registra a denúncia no caso pendente do alvo, abrindo um se
+// necessário, e oculta o alvo quando o caso atinge o limite de denúncias.
+// Retorna o caso e true se o alvo foi ocultado agora
+func (r *ReportRepository) Create(report *models.Report, targetOwnerID uint, hideThreshold int) (*models.ReportCase, bool, error) {
+	var reportCase models.ReportCase
+	hidden := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Um caso pendente por alvo (índice parcial); se outro pedido abriu
+		// o caso ao mesmo tempo, fica com o dele
+		opened := &models.ReportCase{
+			TargetType:    report.TargetType,
+			TargetID:      report.TargetID,
+			TargetOwnerID: targetOwnerID,
+			Status:        models.ReportStatusPending,
+		}
+		if err := tx.Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "target_type"}, {Name: "target_id"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'pending'"}}},
+				DoNothing:   true,
+			}).
+			Create(opened).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ? AND status = ?", report.TargetType, report.TargetID, models.ReportStatusPending).
+			First(&reportCase).Error; err != nil {
+			return err
+		}
+
+		report.CaseID = reportCase.ID
+		result := tx.Omit("Reporter").
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyReported
+		}
+
+		reportCase.ReportsCount++
+		if err := tx.Model(&models.ReportCase{}).Where("id = ?", reportCase.ID).
+			UpdateColumn("reports_count", gorm.Expr("reports_count + 1")).Error; err != nil {
+			return err
+		}
+
+		// Posts e roteiros mantêm o total de denúncias recebidas
+		switch report.TargetType {
+		case models.ReportTargetPost:
+			if err := tx.Model(&models.Post{}).Where("id = ?", report.TargetID).
+				UpdateColumn("reports_count", gorm.Expr("reports_count + 1")).Error; err != nil {
+				return err
+			}
+		case models.ReportTargetItinerary:
+			if err := tx.Model(&models.Itinerary{}).Where("id = ?", report.TargetID).
+				UpdateColumn("reports_count", gorm.Expr("reports_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		if hideThreshold <= 0 || reportCase.ReportsCount < hideThreshold || reportCase.HiddenAt != nil {
+			return nil
+		}
+
+		now := time.Now()
+		var err error
+		if hidden, err = hideReportTarget(tx, report.TargetType, report.TargetID, now); err != nil || !hidden {
+			return err
+		}
+
+		reportCase.HiddenAt = &now
+		return tx.Model(&models.ReportCase{}).Where("id = ?", reportCase.ID).
+			UpdateColumn("hidden_at", now).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &reportCase, hidden, nil
+}
+
+// Flag coloca o alvo na fila de moderação pela sinalização automática,
+// abrindo o caso pendente ou marcando o que já existe; o caso entra na fila
+// mesmo sem denúncias
+func (r *ReportRepository) Flag(targetType models.ReportTargetType, targetID, targetOwnerID uint, reason models.ReportReason, details string) error {
+	now := time.Now()
+	result := r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "target_type"}, {Name: "target_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'pending'"}}},
+			DoNothing:   true,
+		}).
+		Create(&models.ReportCase{
+			TargetType:    targetType,
+			TargetID:      targetID,
+			TargetOwnerID: targetOwnerID,
+			Status:        models.ReportStatusPending,
+			FlagReason:    reason,
+			FlagDetails:   details,
+			FlaggedAt:     &now,
+		})
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	// Caso já aberto por denúncias ou por outra sinalização
+	return r.db.Model(&models.ReportCase{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportStatusPending).
+		Updates(map[string]interface{}{
+			"flag_reason":  reason,
+			"flag_details": details,
+			"flagged_at":   gorm.Expr("COALESCE(flagged_at, ?)", now),
+		}).Error
+}
+
+func (r *ReportRepository) GetCaseByID(id uint) (*models.ReportCase, error) {
+	var reportCase models.ReportCase
+	err := r.db.Preload("Reports", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Reports.Reporter").
+		Preload("TargetOwner", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("AssignedTo").
+		Where("id = ?", id).
+		First(&reportCase).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reportCase, nil
+}
+
+// GetCases lista a fila de moderação, dos casos mais antigos para os mais
+// recentes (ou dos mais denunciados), com os motivos de cada denúncia
+func (r *ReportRepository) GetCases(filter ReportCaseFilter, limit, offset int) ([]models.ReportCase, error) {
+	query := r.db.Model(&models.ReportCase{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.AssignedToID != 0 {
+		query = query.Where("assigned_to_id = ?", filter.AssignedToID)
+	}
+	if filter.Unassigned {
+		query = query.Where("assigned_to_id IS NULL")
+	}
+	if filter.Flagged {
+		query = query.Where("flagged_at IS NOT NULL")
+	}
+
+	order := "created_at ASC, id ASC"
+	if filter.MostReported {
+		order = "reports_count DESC, created_at ASC, id ASC"
+	}
+
+	var cases []models.ReportCase
+	err := query.Preload("Reports", func(db *gorm.DB) *gorm.DB { return db.Select("id", "case_id", "reason") }).
+		Preload("TargetOwner", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("AssignedTo").
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&cases).Error
+	return cases, err
+}
+
+// AssignCase atribui o caso pendente a um admin (ou o libera, com nil);
+// retorna false se o caso já tinha sido decidido
+func (r *ReportRepository) AssignCase(reportCase *models.ReportCase, assigneeID *uint) (bool, error) {
+	var assignedAt *time.Time
+	if assigneeID != nil {
+		now := time.Now()
+		assignedAt = &now
+	}
+
+	result := r.db.Model(&models.ReportCase{}).
+		Where("id = ? AND status = ?", reportCase.ID, models.ReportStatusPending).
+		Updates(map[string]interface{}{
+			"assigned_to_id": assigneeID,
+			"assigned_at":    assignedAt,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	reportCase.AssignedToID = assigneeID
+	reportCase.AssignedAt = assignedAt
+	return true, nil
+}
+
+// ResolveCase encerra o caso pendente: improcedente, o alvo volta a ser
+// exibido se estava oculto; procedente, o conteúdo é ocultado se ainda
+// estava visível (usuários são banidos pela administração de usuários).
+// Retorna false se o caso já tinha sido decidido por outro admin
+func (r *ReportRepository) ResolveCase(reportCase *models.ReportCase, status models.ReportStatus, audit *models.ModerationAction) (bool, error) {
+	now := time.Now()
+	resolved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ReportCase{}).
+			Where("id = ? AND status = ?", reportCase.ID, models.ReportStatusPending).
+			Updates(map[string]interface{}{
+				"status":          status,
+				"resolved_by_id":  reportCase.ResolvedByID,
+				"resolution_note": reportCase.ResolutionNote,
+				"resolved_at":     now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		resolved = true
+
+		if status == models.ReportStatusDismissed {
+			if err := restoreReportTarget(tx, reportCase.TargetType, reportCase.TargetID); err != nil {
+				return err
+			}
+		} else if _, err := hideReportTarget(tx, reportCase.TargetType, reportCase.TargetID, now); err != nil {
+			return err
+		}
+
+		return tx.Create(audit).Error
+	})
+	if err != nil || !resolved {
+		return false, err
+	}
+
+	reportCase.Status = status
+	reportCase.ResolvedAt = &now
+	return true, nil
+}
+
+// hideReportTarget oculta o conteúdo denunciado; retorna false se ele já
+// estava oculto ou se o alvo é um usuário
+func hideReportTarget(tx *gorm.DB, targetType models.ReportTargetType, targetID uint, now time.Time) (bool, error) {
+	var result *gorm.DB
+	switch targetType {
+	case models.ReportTargetPost:
+		result = tx.Model(&models.Post{}).
+			Where("id = ? AND is_active = ?", targetID, true).
+			Updates(map[string]interface{}{
+				"is_active": false,
+				"hidden_at": now,
+			})
+	case models.ReportTargetComment:
+		result = tx.Model(&models.Comment{}).
+			Where("id = ? AND hidden_at IS NULL", targetID).
+			UpdateColumn("hidden_at", now)
+	case models.ReportTargetItinerary:
+		result = tx.Model(&models.Itinerary{}).
+			Where("id = ? AND hidden_at IS NULL", targetID).
+			UpdateColumn("hidden_at", now)
+	case models.ReportTargetRating:
+		return setRatingHidden(tx, targetID, &now)
+	default:
+		return false, nil
+	}
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// restoreReportTarget volta a exibir o conteúdo ocultado pela moderação e
+// retira o limite por spam do usuário
+func restoreReportTarget(tx *gorm.DB, targetType models.ReportTargetType, targetID uint) error {
+	switch targetType {
+	case models.ReportTargetUser:
+		return tx.Model(&models.User{}).
+			Where("id = ? AND spam_limited_until IS NOT NULL", targetID).
+			UpdateColumn("spam_limited_until", nil).Error
+	case models.ReportTargetPost:
+		return tx.Model(&models.Post{}).
+			Where("id = ? AND hidden_at IS NOT NULL", targetID).
+			Updates(map[string]interface{}{
+				"is_active": true,
+				"hidden_at": nil,
+			}).Error
+	case models.ReportTargetComment:
+		return tx.Model(&models.Comment{}).
+			Where("id = ? AND hidden_at IS NOT NULL", targetID).
+			UpdateColumn("hidden_at", nil).Error
+	case models.ReportTargetItinerary:
+		return tx.Model(&models.Itinerary{}).
+			Where("id = ? AND hidden_at IS NOT NULL", targetID).
+			UpdateColumn("hidden_at", nil).Error
+	case models.ReportTargetRating:
+		_, err := setRatingHidden(tx, targetID, nil)
+		return err
+	}
+	return nil
+}
+
+// setRatingHidden oculta (ou volta a exibir, com nil) a avaliação e recalcula
+// a média do roteiro, da qual as avaliações ocultas não fazem parte; retorna
+// false se ela já estava no estado pedido
+func setRatingHidden(tx *gorm.DB, ratingID uint, hiddenAt *time.Time) (bool, error) {
+	var rating models.ItineraryRating
+	err := tx.Select("id", "itinerary_id").Where("id = ?", ratingID).First(&rating).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Avaliação já excluída pelo autor
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	query := tx.Model(&models.ItineraryRating{}).Where("id = ? AND hidden_at IS NOT NULL", ratingID)
+	if hiddenAt != nil {
+		query = tx.Model(&models.ItineraryRating{}).Where("id = ? AND hidden_at IS NULL", ratingID)
+	}
+	result := query.UpdateColumn("hidden_at", hiddenAt)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	return true, updateItineraryRatingStats(tx, rating.ItineraryID)
+}
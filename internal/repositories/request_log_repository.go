@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type RequestLogRepositoryInterface interface {
+	CreateBatch(logs []models.RequestLog) error
+	GetByTraceID(traceID string) ([]models.RequestLog, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type RequestLogRepository struct {
+	db *gorm.DB
+}
+
+func NewRequestLogRepository(db *gorm.DB) RequestLogRepositoryInterface {
+	return &RequestLogRepository{db: db}
+}
+
+func (r *RequestLogRepository) CreateBatch(logs []models.RequestLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.Create(&logs).Error
+}
+
+// GetByTraceID busca as requisições com o trace ID; clientes podem reutilizar
+// o mesmo X-Request-ID em mais de uma requisição
+func (r *RequestLogRepository) GetByTraceID(traceID string) ([]models.RequestLog, error) {
+	var logs []models.RequestLog
+	err := r.db.Where("trace_id = ?", traceID).
+		Order("created_at ASC, id ASC").
+		Limit(50).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *RequestLogRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.RequestLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewRepositoryInterface interface {
+	GetUserReview(userID uint, placeKey string) (*models.LocationReview, error)
+	Save(review *models.LocationReview) error
+	Delete(userID uint, placeKey string) (bool, error)
+	GetByPlace(placeKey string, viewerID uint, sort string, limit, offset int) ([]models.LocationReview, error)
+	GetDistribution(placeKey string) (map[int]int64, error)
+	CountItineraries(placeKey string) (int64, error)
+}
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepositoryInterface {
+	return &ReviewRepository{db: db}
+}
+
+func (r *ReviewRepository) GetUserReview(userID uint, placeKey string) (*models.LocationReview, error) {
+	var review models.LocationReview
+	err := r.db.Preload("User").Where("user_id = ? AND place_key = ?", userID, placeKey).First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Save cria ou substitui a avaliação do usuário para o ponto; o local e o
+// roteiro passam a ser os da edição mais recente
+func (r *ReviewRepository) Save(review *models.LocationReview) error {
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "place_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"place_id", "location_id", "itinerary_id", "rating", "comment", "updated_at"}),
+		}).
+		Create(review).Error
+}
+
+func (r *ReviewRepository) Delete(userID uint, placeKey string) (bool, error) {
+	result := r.db.Where("user_id = ? AND place_key = ?", userID, placeKey).Delete(&models.LocationReview{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetByPlace lista as avaliações do ponto. Avaliações feitas em roteiros
+// privados só aparecem para o próprio autor, para não expor o roteiro;
+// sort aceita "highest", "lowest" ou vazio (mais recentes)
+func (r *ReviewRepository) GetByPlace(placeKey string, viewerID uint, sort string, limit, offset int) ([]models.LocationReview, error) {
+	var reviews []models.LocationReview
+
+	query := r.db.Preload("User").
+		Joins("JOIN itineraries ON itineraries.id = location_reviews.itinerary_id").
+		Where("location_reviews.place_key = ?", placeKey).
+		Where("itineraries.is_public = ? OR location_reviews.user_id = ?", true, viewerID)
+
+	switch sort {
+	case "highest":
+		query = query.Order("location_reviews.rating DESC, location_reviews.updated_at DESC")
+	case "lowest":
+		query = query.Order("location_reviews.rating ASC, location_reviews.updated_at DESC")
+	default:
+		query = query.Order("location_reviews.updated_at DESC")
+	}
+
+	err := query.Order("location_reviews.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// GetDistribution conta as avaliações do ponto por nota, incluindo as de
+// roteiros privados, que entram só no agregado
+func (r *ReviewRepository) GetDistribution(placeKey string) (map[int]int64, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+
+	err := r.db.Model(&models.LocationReview{}).
+		Select("rating, COUNT(*) AS count").
+		Where("place_key = ?", placeKey).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		distribution[row.Rating] = row.Count
+	}
+	return distribution, nil
+}
+
+// CountItineraries conta em quantos roteiros públicos o ponto foi avaliado
+func (r *ReviewRepository) CountItineraries(placeKey string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LocationReview{}).
+		Joins("JOIN itineraries ON itineraries.id = location_reviews.itinerary_id").
+		Where("location_reviews.place_key = ? AND itineraries.is_public = ?", placeKey, true).
+		Distinct("location_reviews.itinerary_id").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type RiskRepositoryInterface interface {
+	Create(assessment *models.RiskAssessment) error
+	LinkUser(assessmentID, userID uint, score int) error
+	GetAssessments(action models.RiskAction, minScore, limit, offset int) ([]models.RiskAssessment, error)
+}
+
+type RiskRepository struct {
+	db *gorm.DB
+}
+
+func NewRiskRepository(db *gorm.DB) RiskRepositoryInterface {
+	return &RiskRepository{db: db}
+}
+
+func (r *RiskRepository) Create(assessment *models.RiskAssessment) error {
+	return r.db.Create(assessment).Error
+}
+
+// LinkUser associa a avaliação do cadastro à conta criada e guarda o risco no
+// perfil do usuário
+func (r *RiskRepository) LinkUser(assessmentID, userID uint, score int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RiskAssessment{}).Where("id = ?", assessmentID).
+			Update("user_id", userID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).Where("id = ?", userID).
+			Update("risk_score", score).Error
+	})
+}
+
+// GetAssessments lista as avaliações de maior risco primeiro; action vazia não
+// filtra
+func (r *RiskRepository) GetAssessments(action models.RiskAction, minScore, limit, offset int) ([]models.RiskAssessment, error) {
+	query := r.db.Where("score >= ?", minScore)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	var assessments []models.RiskAssessment
+	err := query.Order("score DESC, created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&assessments).Error
+	return assessments, err
+}
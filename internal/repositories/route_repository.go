@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RouteRepositoryInterface interface {
+	GetCache(hash string) (*models.RouteCache, error)
+	SaveCache(cache *models.RouteCache) error
+}
+
+type RouteRepository struct {
+	db *gorm.DB
+}
+
+func NewRouteRepository(db *gorm.DB) RouteRepositoryInterface {
+	return &RouteRepository{db: db}
+}
+
+func (r *RouteRepository) GetCache(hash string) (*models.RouteCache, error) {
+	var cache models.RouteCache
+	err := r.db.Where("hash = ?", hash).First(&cache).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+func (r *RouteRepository) SaveCache(cache *models.RouteCache) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		UpdateAll: true,
+	}).Create(cache).Error
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A consulta usa os mesmos dicionários das colunas search_vector (ver
+// database.migrateSearch); basta casar em um deles
+const searchTSQuery = "(to_tsquery('portuguese', ?) || to_tsquery('english', ?))"
+
+// searchTerms converte o texto digitado numa consulta do to_tsquery: só
+// letras e números, todos os termos obrigatórios e o último como prefixo,
+// para a busca funcionar enquanto o usuário digita. Vazio quando não sobra
+// nenhum termo
+func searchTerms(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.Join(words, " & ") + ":*"
+}
+
+// matchSearch filtra pela coluna search_vector da tabela, sem ordenar; serve
+// para contagens agrupadas
+func matchSearch(table, terms string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".search_vector @@ "+searchTSQuery, terms, terms)
+	}
+}
+
+// orderBySearchRank ordena por relevância (ts_rank), com os mais recentes
+// primeiro no empate
+func orderBySearchRank(table, terms string) func(db *gorm.DB) *gorm.DB {
+	column := table + ".search_vector"
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + column + ", " + searchTSQuery + ") DESC, " + table + ".created_at DESC",
+			Vars: []interface{}{terms, terms},
+		}})
+	}
+}
+
+// fullTextSearch filtra pela coluna search_vector da tabela e ordena por
+// relevância
+func fullTextSearch(table, terms string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(matchSearch(table, terms), orderBySearchRank(table, terms))
+	}
+}
+
+// GeoArea é a área de uma busca geográfica: o raio em torno de um ponto ou,
+// com Box, um retângulo
+type GeoArea struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Box       *GeoBox
+}
+
+// GeoBox é um retângulo em graus, na ordem do GeoJSON (oeste, sul, leste,
+// norte)
+type GeoBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+// withinArea é a condição sobre uma coluna geo_point (ver
+// database.migrateGeo); ST_DWithin e ST_Intersects usam o índice GiST
+func withinArea(column string, area *GeoArea) clause.Expr {
+	if area.Box != nil {
+		return clause.Expr{
+			SQL: "ST_Intersects(" + column + ", ST_MakeEnvelope(?, ?, ?, ?, 4326)::geography)",
+			Vars: []interface{}{area.Box.MinLongitude, area.Box.MinLatitude,
+				area.Box.MaxLongitude, area.Box.MaxLatitude},
+		}
+	}
+	return clause.Expr{
+		SQL:  "ST_DWithin(" + column + ", ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)",
+		Vars: []interface{}{area.Longitude, area.Latitude, area.RadiusKm * 1000},
+	}
+}
+
+// distanceFrom é a distância em metros da coluna geo_point ao centro da área
+func distanceFrom(column string, area *GeoArea) clause.Expr {
+	return clause.Expr{
+		SQL:  "ST_Distance(" + column + ", ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography)",
+		Vars: []interface{}{area.Longitude, area.Latitude},
+	}
+}
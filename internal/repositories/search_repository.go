@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SearchRepositoryInterface interface {
+	RecordSearch(term string) error
+	GetSearchCounts(terms []string) (map[string]int64, error)
+	AddHashtags(tags []string, usedAt time.Time) error
+	HasHashtags() (bool, error)
+	GetPostsAfter(lastID uint, limit int) ([]models.Post, error)
+	SuggestHashtags(prefix string, limit int) ([]models.Hashtag, error)
+	SuggestUsers(prefix string, limit int) ([]models.User, error)
+	CreateSavedSearch(search *models.SavedSearch) error
+	CountSavedSearches(userID uint) (int64, error)
+	GetSavedSearches(userID uint) ([]models.SavedSearch, error)
+	GetSavedSearch(id uint) (*models.SavedSearch, error)
+	DeleteSavedSearch(id, userID uint) (bool, error)
+	GetSavedSearchesWithAlerts(afterID uint, limit int) ([]models.SavedSearch, error)
+	MarkSavedSearchChecked(id uint, checkedAt time.Time, notified bool) error
+	MatchItineraries(search *models.SavedSearch, since, until time.Time, limit, offset int) ([]models.Itinerary, error)
+}
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepositoryInterface {
+	return &SearchRepository{db: db}
+}
+
+// RecordSearch soma uma busca ao texto
+func (r *SearchRepository) RecordSearch(term string) error {
+	now := time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "term"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"search_count":     gorm.Expr("search_terms.search_count + 1"),
+			"last_searched_at": now,
+		}),
+	}).Create(&models.SearchTerm{Term: term, SearchCount: 1, LastSearchedAt: now}).Error
+}
+
+// GetSearchCounts retorna quantas vezes cada texto foi buscado; os nunca
+// buscados ficam fora do mapa
+func (r *SearchRepository) GetSearchCounts(terms []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(terms) == 0 {
+		return counts, nil
+	}
+
+	var searchTerms []models.SearchTerm
+	if err := r.db.Where("term IN ?", terms).Find(&searchTerms).Error; err != nil {
+		return nil, err
+	}
+	for _, searchTerm := range searchTerms {
+		counts[searchTerm.Term] = searchTerm.SearchCount
+	}
+	return counts, nil
+}
+
+// AddHashtags conta mais um post para cada tag
+func (r *SearchRepository) AddHashtags(tags []string, usedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, tag := range tags {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "tag"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"posts_count":  gorm.Expr("hashtags.posts_count + 1"),
+					"last_used_at": gorm.Expr("GREATEST(hashtags.last_used_at, ?)", usedAt),
+				}),
+			}).Create(&models.Hashtag{Tag: tag, PostsCount: 1, LastUsedAt: usedAt}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SearchRepository) HasHashtags() (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Hashtag{}).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// GetPostsAfter percorre os posts pelo id, só com o texto, para a contagem
+// inicial das hashtags
+func (r *SearchRepository) GetPostsAfter(lastID uint, limit int) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Select("id", "content", "created_at").
+		Where("id > ?", lastID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// SuggestHashtags busca as tags que começam com o prefixo, das mais usadas
+// para as menos; o LIKE usa o índice de trigramas da coluna
+func (r *SearchRepository) SuggestHashtags(prefix string, limit int) ([]models.Hashtag, error) {
+	var hashtags []models.Hashtag
+	err := r.db.Where("tag LIKE ?", escapeLike(prefix)+"%").
+		Order("posts_count DESC, tag ASC").
+		Limit(limit).
+		Find(&hashtags).Error
+	return hashtags, err
+}
+
+// SuggestUsers busca os usuários ativos cujo username começa com o prefixo,
+// dos mais seguidos para os menos
+func (r *SearchRepository) SuggestUsers(prefix string, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("LOWER(username) LIKE ? AND is_active = ?", escapeLike(strings.ToLower(prefix))+"%", true).
+		Order("followers_count DESC, username ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *SearchRepository) CreateSavedSearch(search *models.SavedSearch) error {
+	return r.db.Create(search).Error
+}
+
+func (r *SearchRepository) CountSavedSearches(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SavedSearch{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *SearchRepository) GetSavedSearches(userID uint) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&searches).Error
+	return searches, err
+}
+
+func (r *SearchRepository) GetSavedSearch(id uint) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	if err := r.db.First(&search, id).Error; err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (r *SearchRepository) DeleteSavedSearch(id, userID uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.SavedSearch{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetSavedSearchesWithAlerts percorre pelo id as buscas com alertas ligados
+func (r *SearchRepository) GetSavedSearchesWithAlerts(afterID uint, limit int) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.db.Where("alerts = ? AND id > ?", true, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&searches).Error
+	return searches, err
+}
+
+func (r *SearchRepository) MarkSavedSearchChecked(id uint, checkedAt time.Time, notified bool) error {
+	updates := map[string]interface{}{"last_checked_at": checkedAt}
+	if notified {
+		updates["last_notified_at"] = checkedAt
+	}
+	return r.db.Model(&models.SavedSearch{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+// MatchItineraries busca os roteiros públicos de outros autores que atendem
+// à busca salva, publicados entre since (exclusive; zero para sem limite) e
+// until. Com texto, a ordem é a da relevância; sem ele, os mais recentes
+func (r *SearchRepository) MatchItineraries(search *models.SavedSearch, since, until time.Time, limit, offset int) ([]models.Itinerary, error) {
+	query := r.db.Preload("Author").
+		Where("itineraries.is_public = ? AND itineraries.hidden_at IS NULL", true).
+		Where("itineraries.author_id <> ? AND itineraries.created_at <= ?", search.UserID, until)
+	if !since.IsZero() {
+		query = query.Where("itineraries.created_at > ?", since)
+	}
+
+	if search.Category != "" {
+		query = query.Where("itineraries.category = ?", search.Category)
+	}
+	switch {
+	case search.CountryID != nil:
+		query = query.Where("itineraries.country_id = ?", *search.CountryID)
+	case search.Country != "":
+		query = query.Where("LOWER(itineraries.country) = LOWER(?)", search.Country)
+	}
+	switch {
+	case search.CityID != nil:
+		query = query.Where("itineraries.city_id = ?", *search.CityID)
+	case search.City != "":
+		query = query.Where("LOWER(itineraries.city) = LOWER(?)", search.City)
+	}
+	if search.MinDuration > 0 {
+		query = query.Where("itineraries.duration >= ?", search.MinDuration)
+	}
+	if search.MaxDuration > 0 {
+		query = query.Where("itineraries.duration <= ?", search.MaxDuration)
+	}
+
+	if terms := searchTerms(search.Query); terms != "" {
+		query = query.Scopes(fullTextSearch("itineraries", terms))
+	} else {
+		query = query.Order("itineraries.created_at DESC")
+	}
+
+	var itineraries []models.Itinerary
+	err := query.Limit(limit).
+		Offset(offset).
+		Find(&itineraries).Error
+	return itineraries, err
+}
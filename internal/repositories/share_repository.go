@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShareRepositoryInterface interface {
+	GetByItinerary(itineraryID uint) (*models.ItineraryShareLink, error)
+	GetBySlug(slug string) (*models.ItineraryShareLink, error)
+	Save(link *models.ItineraryShareLink) error
+	IncrementViews(id uint) error
+}
+
+type ShareRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) ShareRepositoryInterface {
+	return &ShareRepository{db: db}
+}
+
+func (r *ShareRepository) GetByItinerary(itineraryID uint) (*models.ItineraryShareLink, error) {
+	var link models.ItineraryShareLink
+	err := r.db.Where("itinerary_id = ?", itineraryID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ShareRepository) GetBySlug(slug string) (*models.ItineraryShareLink, error) {
+	var link models.ItineraryShareLink
+	err := r.db.Where("slug = ?", slug).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ShareRepository) Save(link *models.ItineraryShareLink) error {
+	return r.db.Omit(clause.Associations).Save(link).Error
+}
+
+func (r *ShareRepository) IncrementViews(id uint) error {
+	return r.db.Model(&models.ItineraryShareLink{}).Where("id = ?", id).
+		Update("views_count", gorm.Expr("views_count + 1")).Error
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SpamRepositoryInterface interface {
+	GetSpamLimit(userID uint) (*models.User, error)
+	LimitUser(userID uint, until time.Time) (bool, error)
+	CountDuplicatePosts(authorID uint, content string, since time.Time) (int64, error)
+}
+
+type SpamRepository struct {
+	db *gorm.DB
+}
+
+func NewSpamRepository(db *gorm.DB) SpamRepositoryInterface {
+	return &SpamRepository{db: db}
+}
+
+// GetSpamLimit carrega só o limite por spam da conta, consultado a cada post
+// e follow
+func (r *SpamRepository) GetSpamLimit(userID uint) (*models.User, error) {
+	var user models.User
+	err := r.db.Select("id", "spam_limited_until").
+		Where("id = ?", userID).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LimitUser bloqueia posts e follows da conta até a data informada, sem
+// encurtar um limite maior em vigor; administradores nunca são limitados.
+// Retorna false se nada mudou
+func (r *SpamRepository) LimitUser(userID uint, until time.Time) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND user_type <> ? AND (spam_limited_until IS NULL OR spam_limited_until < ?)", userID, models.UserTypeAdmin, until).
+		UpdateColumn("spam_limited_until", until)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountDuplicatePosts conta os posts do autor com o mesmo texto (sem
+// diferenciar maiúsculas) desde a data informada, inclusive os já removidos
+func (r *SpamRepository) CountDuplicatePosts(authorID uint, content string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Post{}).
+		Where("author_id = ? AND created_at >= ? AND LOWER(content) = LOWER(?)", authorID, since, content).
+		Count(&count).Error
+	return count, err
+}
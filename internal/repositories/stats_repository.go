@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type StatsRepositoryInterface interface {
+	Compute() (*models.PlatformStats, error)
+	Save(stats *models.PlatformStats) error
+	GetLatest() (*models.PlatformStats, error)
+}
+
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) StatsRepositoryInterface {
+	return &StatsRepository{db: db}
+}
+
+// Compute calcula os números agregados a partir dos roteiros públicos e das
+// contas ativas
+func (r *StatsRepository) Compute() (*models.PlatformStats, error) {
+	var stats models.PlatformStats
+
+	err := r.db.Model(&models.Itinerary{}).
+		Select(`COUNT(*) AS public_itineraries,
+			COUNT(DISTINCT COALESCE(CAST(city_id AS TEXT), LOWER(country) || '|' || LOWER(city))) AS destinations,
+			COUNT(DISTINCT COALESCE(CAST(country_id AS TEXT), LOWER(country))) AS countries,
+			COUNT(DISTINCT author_id) AS creators`).
+		Where("is_public = ?", true).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(&models.User{}).Where("is_active = ?", true).Count(&stats.CommunitySize).Error; err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func (r *StatsRepository) Save(stats *models.PlatformStats) error {
+	return r.db.Create(stats).Error
+}
+
+func (r *StatsRepository) GetLatest() (*models.PlatformStats, error) {
+	var stats models.PlatformStats
+	if err := r.db.Order("computed_at DESC").First(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
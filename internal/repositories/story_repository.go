@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoryRepositoryInterface interface {
+	Create(story *models.Story) error
+	GetByID(id uint) (*models.Story, error)
+	Delete(story *models.Story) error
+	GetActiveForViewer(viewerID uint, now time.Time) ([]models.Story, error)
+	GetArchivedByAuthor(authorID uint, limit, offset int) ([]models.Story, error)
+	GetViewedIDs(viewerID uint, storyIDs []uint) (map[uint]bool, error)
+	RecordView(storyID, viewerID uint) error
+	GetViewers(storyID uint, limit, offset int) ([]models.StoryView, error)
+	ArchiveExpired(now time.Time) (int64, error)
+}
+
+type StoryRepository struct {
+	db *gorm.DB
+}
+
+func NewStoryRepository(db *gorm.DB) StoryRepositoryInterface {
+	return &StoryRepository{db: db}
+}
+
+func (r *StoryRepository) Create(story *models.Story) error {
+	return r.db.Omit("Author").Create(story).Error
+}
+
+func (r *StoryRepository) GetByID(id uint) (*models.Story, error) {
+	var story models.Story
+	err := r.db.Preload("Author").First(&story, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+func (r *StoryRepository) Delete(story *models.Story) error {
+	return r.db.Delete(story).Error
+}
+
+// GetActiveForViewer retorna os stories não expirados do próprio usuário e de
+// quem ele segue, agrupáveis por autor
+func (r *StoryRepository) GetActiveForViewer(viewerID uint, now time.Time) ([]models.Story, error) {
+	var stories []models.Story
+	err := r.db.Preload("Author").
+		Where(`author_id IN (
+			SELECT followed_id FROM follows WHERE follower_id = ?
+			UNION
+			SELECT ?
+		)`, viewerID, viewerID).
+		Where("archived_at IS NULL AND expires_at > ?", now).
+		Order("author_id, created_at ASC").
+		Find(&stories).Error
+	return stories, err
+}
+
+func (r *StoryRepository) GetArchivedByAuthor(authorID uint, limit, offset int) ([]models.Story, error) {
+	var stories []models.Story
+	err := r.db.Where("author_id = ? AND archived_at IS NOT NULL", authorID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&stories).Error
+	return stories, err
+}
+
+func (r *StoryRepository) GetViewedIDs(viewerID uint, storyIDs []uint) (map[uint]bool, error) {
+	viewed := make(map[uint]bool)
+	if len(storyIDs) == 0 {
+		return viewed, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&models.StoryView{}).
+		Where("viewer_id = ? AND story_id IN ?", viewerID, storyIDs).
+		Pluck("story_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		viewed[id] = true
+	}
+	return viewed, nil
+}
+
+// RecordView registra a visualização uma única vez por usuário
+func (r *StoryRepository) RecordView(storyID, viewerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.StoryView{
+			StoryID:  storyID,
+			ViewerID: viewerID,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Story{}).Where("id = ?", storyID).
+			Update("views_count", gorm.Expr("views_count + 1")).Error
+	})
+}
+
+func (r *StoryRepository) GetViewers(storyID uint, limit, offset int) ([]models.StoryView, error) {
+	var views []models.StoryView
+	err := r.db.Preload("Viewer").
+		Where("story_id = ?", storyID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&views).Error
+	return views, err
+}
+
+// ArchiveExpired arquiva os stories que passaram da validade
+func (r *StoryRepository) ArchiveExpired(now time.Time) (int64, error) {
+	result := r.db.Model(&models.Story{}).
+		Where("archived_at IS NULL AND expires_at <= ?", now).
+		Update("archived_at", now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SubscriptionRepositoryInterface interface {
+	Create(subscription *models.Subscription) error
+	GetByID(id uint) (*models.Subscription, error)
+	GetByProviderPaymentID(providerPaymentID string) (*models.Subscription, error)
+	GetByUser(userID uint, limit, offset int) ([]models.Subscription, error)
+	GetPaidUntil(userID uint) (*time.Time, error)
+	MarkPaid(subscription *models.Subscription, from models.SubscriptionStatus) (bool, error)
+	UpdateStatus(subscription *models.Subscription, from, to models.SubscriptionStatus) (bool, error)
+}
+
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepositoryInterface {
+	return &SubscriptionRepository{db: db}
+}
+
+func (r *SubscriptionRepository) Create(subscription *models.Subscription) error {
+	return r.db.Create(subscription).Error
+}
+
+func (r *SubscriptionRepository) GetByID(id uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.Where("id = ?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *SubscriptionRepository) GetByProviderPaymentID(providerPaymentID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := r.db.Where("provider_payment_id = ?", providerPaymentID).First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *SubscriptionRepository) GetByUser(userID uint, limit, offset int) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// GetPaidUntil retorna o fim do último período pago do usuário, ou nil se ele
+// nunca pagou
+func (r *SubscriptionRepository) GetPaidUntil(userID uint) (*time.Time, error) {
+	return paidUntil(r.db, userID)
+}
+
+// MarkPaid confirma o pagamento de uma compra que ainda está no status de
+// origem e define o seu período a partir do fim do último período pago (ou de
+// agora). O lock por usuário impede que duas confirmações simultâneas se
+// sobreponham. Retorna se a transição foi aplicada
+func (r *SubscriptionRepository) MarkPaid(subscription *models.Subscription, from models.SubscriptionStatus) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext('subscriptions'))", subscription.UserID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		start := now
+		last, err := paidUntil(tx, subscription.UserID)
+		if err != nil {
+			return err
+		}
+		if last != nil && last.After(now) {
+			start = *last
+		}
+		end := start.AddDate(0, subscription.Months, 0)
+
+		result := tx.Model(&models.Subscription{}).
+			Where("id = ? AND status = ?", subscription.ID, from).
+			Updates(map[string]interface{}{
+				"status":       models.SubscriptionStatusPaid,
+				"period_start": start,
+				"period_end":   end,
+				"paid_at":      now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		subscription.Status = models.SubscriptionStatusPaid
+		subscription.PeriodStart = &start
+		subscription.PeriodEnd = &end
+		subscription.PaidAt = &now
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+// UpdateStatus só altera compras que ainda estão no status de origem
+func (r *SubscriptionRepository) UpdateStatus(subscription *models.Subscription, from, to models.SubscriptionStatus) (bool, error) {
+	result := r.db.Model(&models.Subscription{}).
+		Where("id = ? AND status = ?", subscription.ID, from).
+		Update("status", to)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	subscription.Status = to
+	return true, nil
+}
+
+func paidUntil(db *gorm.DB, userID uint) (*time.Time, error) {
+	var until *time.Time
+	err := db.Model(&models.Subscription{}).
+		Where("user_id = ? AND status = ?", userID, models.SubscriptionStatusPaid).
+		Select("MAX(period_end)").
+		Scan(&until).Error
+	return until, err
+}
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SuspensionRepositoryInterface interface {
+	GetAccountStatus(userID uint) (*models.User, error)
+	SuspendUser(userID uint, until time.Time, reason string, audit *models.ModerationAction) (bool, error)
+	ReinstateUser(userID uint, audit *models.ModerationAction) (bool, error)
+	GetExpiredSuspensions(now time.Time, limit int) ([]uint, error)
+	LiftSuspension(userID uint, now time.Time) (bool, error)
+
+	CreateAppeal(appeal *models.AccountAppeal) error
+	GetAppealByID(id uint) (*models.AccountAppeal, error)
+	GetLatestAppeal(userID uint) (*models.AccountAppeal, error)
+	GetAppeals(status models.AppealStatus, limit, offset int) ([]models.AccountAppeal, error)
+	ReviewAppeal(appeal *models.AccountAppeal, audit *models.ModerationAction) (bool, error)
+}
+
+type SuspensionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuspensionRepository(db *gorm.DB) SuspensionRepositoryInterface {
+	return &SuspensionRepository{db: db}
+}
+
+// GetAccountStatus carrega só os campos da situação da conta, consultados a
+// cada requisição autenticada
+func (r *SuspensionRepository) GetAccountStatus(userID uint) (*models.User, error) {
+	var user models.User
+	err := r.db.Select("id", "is_active", "suspended_at", "suspended_until", "suspension_reason").
+		Where("id = ?", userID).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SuspendUser desativa a conta até a data informada; uma suspensão em vigor
+// é substituída pela nova. Retorna false se a conta é de administrador ou já
+// está banida
+func (r *SuspensionRepository) SuspendUser(userID uint, until time.Time, reason string, audit *models.ModerationAction) (bool, error) {
+	suspended := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND user_type <> ? AND (is_active = ? OR suspended_until IS NOT NULL)", userID, models.UserTypeAdmin, true).
+			Updates(map[string]interface{}{
+				"is_active":         false,
+				"suspended_at":      time.Now(),
+				"suspended_until":   until,
+				"suspension_reason": reason,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		suspended = true
+		if err := closePendingAppeal(tx, userID); err != nil {
+			return err
+		}
+		if err := closePendingReportCase(tx, models.ReportTargetUser, userID, models.ReportStatusResolved, audit.AdminID, audit.Note); err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+	return suspended, err
+}
+
+// ReinstateUser reativa uma conta suspensa ou banida; retorna false se ela
+// não tem restrição (contas desativadas pelo próprio usuário continuam
+// desativadas)
+func (r *SuspensionRepository) ReinstateUser(userID uint, audit *models.ModerationAction) (bool, error) {
+	reinstated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if reinstated, err = reinstateUser(tx, "id = ? AND is_active = ? AND suspended_at IS NOT NULL", userID, false); err != nil || !reinstated {
+			return err
+		}
+		if err := closePendingAppeal(tx, userID); err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+	return reinstated, err
+}
+
+// GetExpiredSuspensions lista as contas cuja suspensão já venceu, das mais
+// antigas para as mais recentes
+func (r *SuspensionRepository) GetExpiredSuspensions(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.User{}).
+		Where("is_active = ? AND suspended_until <= ?", false, now).
+		Order("suspended_until ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// LiftSuspension reativa a conta cuja suspensão venceu; retorna false se ela
+// já foi reativada ou recebeu outra restrição nesse meio-tempo
+func (r *SuspensionRepository) LiftSuspension(userID uint, now time.Time) (bool, error) {
+	lifted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if lifted, err = reinstateUser(tx, "id = ? AND is_active = ? AND suspended_until <= ?", userID, false, now); err != nil || !lifted {
+			return err
+		}
+		return closePendingAppeal(tx, userID)
+	})
+	return lifted, err
+}
+
+func (r *SuspensionRepository) CreateAppeal(appeal *models.AccountAppeal) error {
+	return r.db.Omit("User").Create(appeal).Error
+}
+
+func (r *SuspensionRepository) GetAppealByID(id uint) (*models.AccountAppeal, error) {
+	var appeal models.AccountAppeal
+	err := r.db.Preload("User").Where("id = ?", id).First(&appeal).Error
+	if err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+// GetLatestAppeal retorna o recurso mais recente do usuário
+func (r *SuspensionRepository) GetLatestAppeal(userID uint) (*models.AccountAppeal, error) {
+	var appeal models.AccountAppeal
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&appeal).Error
+	if err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+// GetAppeals lista os recursos da fila, dos mais antigos para os mais
+// recentes
+func (r *SuspensionRepository) GetAppeals(status models.AppealStatus, limit, offset int) ([]models.AccountAppeal, error) {
+	var appeals []models.AccountAppeal
+	err := r.db.Preload("User").
+		Where("status = ?", status).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&appeals).Error
+	return appeals, err
+}
+
+// ReviewAppeal grava a decisão do recurso e, se aceito, reativa a conta;
+// retorna false se o recurso já tinha sido analisado ou encerrado
+func (r *SuspensionRepository) ReviewAppeal(appeal *models.AccountAppeal, audit *models.ModerationAction) (bool, error) {
+	now := time.Now()
+	reviewed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AccountAppeal{}).
+			Where("id = ? AND status = ?", appeal.ID, models.AppealPending).
+			Updates(map[string]interface{}{
+				"status":         appeal.Status,
+				"reviewed_by_id": audit.AdminID,
+				"review_note":    appeal.ReviewNote,
+				"reviewed_at":    now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if appeal.Status == models.AppealAccepted {
+			if _, err := reinstateUser(tx, "id = ? AND is_active = ? AND suspended_at IS NOT NULL", appeal.UserID, false); err != nil {
+				return err
+			}
+		}
+
+		reviewed = true
+		return tx.Create(audit).Error
+	})
+	if err != nil || !reviewed {
+		return false, err
+	}
+
+	appeal.ReviewedByID = &audit.AdminID
+	appeal.ReviewedAt = &now
+	return true, nil
+}
+
+// reinstateUser reativa a conta que atende à condição e apaga os dados da
+// restrição
+func reinstateUser(tx *gorm.DB, condition string, args ...interface{}) (bool, error) {
+	result := tx.Model(&models.User{}).
+		Where(condition, args...).
+		Updates(map[string]interface{}{
+			"is_active":         true,
+			"suspended_at":      nil,
+			"suspended_until":   nil,
+			"suspension_reason": "",
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// closePendingAppeal encerra o recurso em análise do usuário quando a
+// restrição contestada deixa de existir ou é trocada por outra
+func closePendingAppeal(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.AccountAppeal{}).
+		Where("user_id = ? AND status = ?", userID, models.AppealPending).
+		Update("status", models.AppealClosed).Error
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type TemplateFilter struct {
+	Category  models.ItineraryCategory
+	Country   string
+	City      string
+	CompanyID uint
+	Limit     int
+	Offset    int
+}
+
+type TemplateRepositoryInterface interface {
+	GetTemplates(filter TemplateFilter) ([]models.Itinerary, error)
+	SetTemplate(itineraryID uint, isTemplate bool) error
+	CreateUsage(usage *models.TemplateUsage) error
+	CountUsages(templateIDs []uint) (map[uint]int64, error)
+	GetMetrics(companyID uint, since time.Time) ([]models.TemplateMetrics, error)
+}
+
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepositoryInterface {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) GetTemplates(filter TemplateFilter) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+
+	query := r.db.Preload("Author").Where("is_template = ? AND is_public = ? AND hidden_at IS NULL", true, true)
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Country != "" {
+		query = query.Where("country ILIKE ?", "%"+filter.Country+"%")
+	}
+	if filter.City != "" {
+		query = query.Where("city ILIKE ?", "%"+filter.City+"%")
+	}
+	if filter.CompanyID != 0 {
+		query = query.Where("author_id = ?", filter.CompanyID)
+	}
+
+	err := query.Order("clones_count DESC, created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
+func (r *TemplateRepository) SetTemplate(itineraryID uint, isTemplate bool) error {
+	return r.db.Model(&models.Itinerary{}).Where("id = ?", itineraryID).
+		Update("is_template", isTemplate).Error
+}
+
+func (r *TemplateRepository) CreateUsage(usage *models.TemplateUsage) error {
+	return r.db.Omit("Template", "User").Create(usage).Error
+}
+
+func (r *TemplateRepository) CountUsages(templateIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(templateIDs))
+	if len(templateIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TemplateID uint
+		Count      int64
+	}
+	err := r.db.Model(&models.TemplateUsage{}).
+		Select("template_id, COUNT(*) AS count").
+		Where("template_id IN ?", templateIDs).
+		Group("template_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.TemplateID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetMetrics agrega o uso de todos os modelos da empresa, inclusive os que
+// ainda não foram usados
+func (r *TemplateRepository) GetMetrics(companyID uint, since time.Time) ([]models.TemplateMetrics, error) {
+	var metrics []models.TemplateMetrics
+	err := r.db.Raw(`
+		SELECT i.id AS template_id, i.title, i.is_public, i.views_count, i.likes_count,
+			COUNT(u.id) AS uses_count,
+			COUNT(u.id) FILTER (WHERE u.created_at >= ?) AS uses_last_30_days,
+			COUNT(DISTINCT u.user_id) AS unique_users,
+			MAX(u.created_at) AS last_used_at
+		FROM itineraries i
+		LEFT JOIN template_usages u ON u.template_id = i.id
+		WHERE i.author_id = ? AND i.is_template = ? AND i.deleted_at IS NULL
+		GROUP BY i.id
+		ORDER BY uses_count DESC, i.created_at DESC`,
+		since, companyID, true).Scan(&metrics).Error
+	return metrics, err
+}
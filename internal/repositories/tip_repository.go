@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type TipRepositoryInterface interface {
+	Create(tip *models.Tip) error
+	GetByID(id uint) (*models.Tip, error)
+	GetByProviderPaymentID(providerPaymentID string) (*models.Tip, error)
+	UpdateStatus(tip *models.Tip, from, to models.TipStatus) (bool, error)
+	GetBySupporter(supporterID uint, limit, offset int) ([]models.Tip, error)
+	GetByCreator(creatorID uint, limit, offset int) ([]models.Tip, error)
+	GetEarnings(creatorID uint, from, to time.Time) ([]models.CreatorEarnings, error)
+	GetPayoutAccount(userID uint) (*models.PayoutAccount, error)
+	SavePayoutAccount(account *models.PayoutAccount) error
+}
+
+type TipRepository struct {
+	db *gorm.DB
+}
+
+func NewTipRepository(db *gorm.DB) TipRepositoryInterface {
+	return &TipRepository{db: db}
+}
+
+func (r *TipRepository) Create(tip *models.Tip) error {
+	return r.db.Omit("Supporter", "Creator").Create(tip).Error
+}
+
+func (r *TipRepository) GetByID(id uint) (*models.Tip, error) {
+	var tip models.Tip
+	err := r.db.Preload("Supporter").Preload("Creator").Where("id = ?", id).First(&tip).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tip, nil
+}
+
+func (r *TipRepository) GetByProviderPaymentID(providerPaymentID string) (*models.Tip, error) {
+	var tip models.Tip
+	err := r.db.Where("provider_payment_id = ?", providerPaymentID).First(&tip).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tip, nil
+}
+
+// UpdateStatus só altera gorjetas que ainda estão no status de origem,
+// evitando que uma confirmação repetida sobrescreva um estado final. Retorna
+// se a transição foi aplicada
+func (r *TipRepository) UpdateStatus(tip *models.Tip, from, to models.TipStatus) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{"status": to}
+	switch to {
+	case models.TipStatusSucceeded:
+		updates["paid_at"] = now
+	case models.TipStatusRefunded:
+		updates["refunded_at"] = now
+	}
+
+	result := r.db.Model(&models.Tip{}).
+		Where("id = ? AND status = ?", tip.ID, from).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	tip.Status = to
+	switch to {
+	case models.TipStatusSucceeded:
+		tip.PaidAt = &now
+	case models.TipStatusRefunded:
+		tip.RefundedAt = &now
+	}
+	return true, nil
+}
+
+func (r *TipRepository) GetBySupporter(supporterID uint, limit, offset int) ([]models.Tip, error) {
+	var tips []models.Tip
+	err := r.db.Preload("Creator").
+		Where("supporter_id = ?", supporterID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&tips).Error
+	return tips, err
+}
+
+func (r *TipRepository) GetByCreator(creatorID uint, limit, offset int) ([]models.Tip, error) {
+	var tips []models.Tip
+	err := r.db.Preload("Supporter").
+		Where("creator_id = ? AND status = ?", creatorID, models.TipStatusSucceeded).
+		Order("paid_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&tips).Error
+	return tips, err
+}
+
+func (r *TipRepository) GetEarnings(creatorID uint, from, to time.Time) ([]models.CreatorEarnings, error) {
+	var earnings []models.CreatorEarnings
+	err := r.db.Model(&models.Tip{}).
+		Select(`currency,
+			COUNT(*) AS tips_count,
+			COALESCE(SUM(amount), 0) AS gross_amount,
+			COALESCE(SUM(platform_fee), 0) AS platform_fee,
+			COALESCE(SUM(amount - platform_fee), 0) AS net_amount`).
+		Where("creator_id = ? AND status = ? AND paid_at >= ? AND paid_at < ?",
+			creatorID, models.TipStatusSucceeded, from, to).
+		Group("currency").
+		Order("currency").
+		Scan(&earnings).Error
+	return earnings, err
+}
+
+func (r *TipRepository) GetPayoutAccount(userID uint) (*models.PayoutAccount, error) {
+	var account models.PayoutAccount
+	err := r.db.Where("user_id = ?", userID).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *TipRepository) SavePayoutAccount(account *models.PayoutAccount) error {
+	return r.db.Save(account).Error
+}
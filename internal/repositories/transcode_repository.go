@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TranscodeRepositoryInterface interface {
+	Create(job *models.TranscodeJob) (bool, error)
+	GetByID(id uint) (*models.TranscodeJob, error)
+	GetByMediaID(mediaID uint) (*models.TranscodeJob, error)
+	Update(job *models.TranscodeJob) error
+	GetUnfinished() ([]models.TranscodeJob, error)
+}
+
+type TranscodeRepository struct {
+	db *gorm.DB
+}
+
+func NewTranscodeRepository(db *gorm.DB) TranscodeRepositoryInterface {
+	return &TranscodeRepository{db: db}
+}
+
+// Create cria o job se a mídia ainda não tiver um; retorna false se já existia
+func (r *TranscodeRepository) Create(job *models.TranscodeJob) (bool, error) {
+	result := r.db.Omit("Media").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "media_id"}}, DoNothing: true}).
+		Create(job)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *TranscodeRepository) GetByID(id uint) (*models.TranscodeJob, error) {
+	var job models.TranscodeJob
+	err := r.db.Preload("Media").First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *TranscodeRepository) GetByMediaID(mediaID uint) (*models.TranscodeJob, error) {
+	var job models.TranscodeJob
+	err := r.db.Where("media_id = ?", mediaID).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *TranscodeRepository) Update(job *models.TranscodeJob) error {
+	return r.db.Select("status", "error", "started_at", "finished_at").
+		Updates(job).Error
+}
+
+// GetUnfinished busca os jobs interrompidos, na ordem de criação
+func (r *TranscodeRepository) GetUnfinished() ([]models.TranscodeJob, error) {
+	var jobs []models.TranscodeJob
+	err := r.db.Where("status IN ?", []models.TranscodeStatus{models.TranscodeStatusPending, models.TranscodeStatusRunning}).
+		Order("id ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
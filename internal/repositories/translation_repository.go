@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TranslationRepositoryInterface interface {
+	GetPostTranslation(postID uint, language string) (*models.PostTranslation, error)
+	SavePostTranslation(translation *models.PostTranslation) error
+	SetPostLanguage(postID uint, language string) error
+}
+
+type TranslationRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) TranslationRepositoryInterface {
+	return &TranslationRepository{db: db}
+}
+
+func (r *TranslationRepository) GetPostTranslation(postID uint, language string) (*models.PostTranslation, error) {
+	var translation models.PostTranslation
+	err := r.db.Where("post_id = ? AND language = ?", postID, language).First(&translation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+// SavePostTranslation cria ou substitui a tradução do post para o idioma
+func (r *TranslationRepository) SavePostTranslation(translation *models.PostTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "language"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source_language", "source_hash", "content", "provider", "updated_at"}),
+	}).Create(translation).Error
+}
+
+// SetPostLanguage registra o idioma detectado do conteúdo sem alterar updated_at
+func (r *TranslationRepository) SetPostLanguage(postID uint, language string) error {
+	return r.db.Model(&models.Post{}).
+		Where("id = ?", postID).
+		UpdateColumn("language", language).Error
+}
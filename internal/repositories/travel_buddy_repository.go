@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TravelBuddyRepositoryInterface interface {
+	CreateIntent(intent *models.TravelIntent) error
+	GetIntentByID(id uint) (*models.TravelIntent, error)
+	UpdateIntent(intent *models.TravelIntent) error
+	DeleteIntent(id uint) error
+	GetIntentsByUser(userID uint) ([]models.TravelIntent, error)
+	GetCandidateIntents(intent *models.TravelIntent, limit int) ([]models.TravelIntent, error)
+	CreateInterest(interest *models.TravelBuddyInterest) error
+	HasInterest(intentID, targetIntentID uint) (bool, error)
+	GetInterestedIntentIDs(targetIntentID uint) (map[uint]bool, error)
+	CreateMatch(match *models.TravelMatch) error
+	GetMatchedIntentIDs(intentID uint) (map[uint]bool, error)
+	GetMatchesByUser(userID uint, limit, offset int) ([]models.TravelMatch, error)
+}
+
+type TravelBuddyRepository struct {
+	db *gorm.DB
+}
+
+func NewTravelBuddyRepository(db *gorm.DB) TravelBuddyRepositoryInterface {
+	return &TravelBuddyRepository{db: db}
+}
+
+func (r *TravelBuddyRepository) CreateIntent(intent *models.TravelIntent) error {
+	return r.db.Create(intent).Error
+}
+
+func (r *TravelBuddyRepository) GetIntentByID(id uint) (*models.TravelIntent, error) {
+	var intent models.TravelIntent
+	err := r.db.Preload("User").Where("id = ?", id).First(&intent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (r *TravelBuddyRepository) UpdateIntent(intent *models.TravelIntent) error {
+	return r.db.Omit("User").Save(intent).Error
+}
+
+func (r *TravelBuddyRepository) DeleteIntent(id uint) error {
+	return r.db.Delete(&models.TravelIntent{}, id).Error
+}
+
+func (r *TravelBuddyRepository) GetIntentsByUser(userID uint) ([]models.TravelIntent, error) {
+	var intents []models.TravelIntent
+	err := r.db.Where("user_id = ?", userID).
+		Order("start_date ASC").
+		Find(&intents).Error
+	return intents, err
+}
+
+// GetCandidateIntents busca intenções ativas de outros usuários para o mesmo
+// país com datas que se sobrepõem às da intenção informada
+func (r *TravelBuddyRepository) GetCandidateIntents(intent *models.TravelIntent, limit int) ([]models.TravelIntent, error) {
+	var intents []models.TravelIntent
+
+	query := r.db.Preload("User").
+		Where("user_id <> ? AND is_active = ?", intent.UserID, true).
+		Where("start_date <= ? AND end_date >= ?", intent.EndDate, intent.StartDate).
+		Where("end_date >= ?", time.Now().Truncate(24*time.Hour))
+
+	if intent.CountryCode != "" {
+		query = query.Where("country_code = ?", intent.CountryCode)
+	} else {
+		query = query.Where("LOWER(country) = ?", strings.ToLower(intent.Country))
+	}
+
+	err := query.Order("start_date ASC").Limit(limit).Find(&intents).Error
+	return intents, err
+}
+
+func (r *TravelBuddyRepository) CreateInterest(interest *models.TravelBuddyInterest) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(interest).Error
+}
+
+func (r *TravelBuddyRepository) HasInterest(intentID, targetIntentID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TravelBuddyInterest{}).
+		Where("intent_id = ? AND target_intent_id = ?", intentID, targetIntentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetInterestedIntentIDs retorna as intenções cujos autores demonstraram
+// interesse na intenção informada
+func (r *TravelBuddyRepository) GetInterestedIntentIDs(targetIntentID uint) (map[uint]bool, error) {
+	var intentIDs []uint
+	err := r.db.Model(&models.TravelBuddyInterest{}).
+		Where("target_intent_id = ?", targetIntentID).
+		Pluck("intent_id", &intentIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]bool, len(intentIDs))
+	for _, id := range intentIDs {
+		result[id] = true
+	}
+	return result, nil
+}
+
+func (r *TravelBuddyRepository) CreateMatch(match *models.TravelMatch) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(match).Error
+}
+
+func (r *TravelBuddyRepository) GetMatchedIntentIDs(intentID uint) (map[uint]bool, error) {
+	var matches []models.TravelMatch
+	err := r.db.Where("intent_id = ? OR matched_intent_id = ?", intentID, intentID).
+		Find(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]bool, len(matches))
+	for _, match := range matches {
+		if match.IntentID == intentID {
+			result[match.MatchedIntentID] = true
+		} else {
+			result[match.IntentID] = true
+		}
+	}
+	return result, nil
+}
+
+func (r *TravelBuddyRepository) GetMatchesByUser(userID uint, limit, offset int) ([]models.TravelMatch, error) {
+	var matches []models.TravelMatch
+	err := r.db.Preload("Intent.User").
+		Preload("MatchedIntent.User").
+		Where("user_id = ? OR matched_user_id = ?", userID, userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&matches).Error
+	return matches, err
+}
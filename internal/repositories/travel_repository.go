@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TravelRepositoryInterface interface {
+	CreateCompletion(completion *models.ItineraryCompletion) (bool, error)
+	GetCompletion(userID, itineraryID uint) (*models.ItineraryCompletion, error)
+	DeleteCompletion(userID, itineraryID uint) (bool, error)
+	GetCompletionsByUser(userID uint, onlyPublic bool, limit, offset int) ([]models.ItineraryCompletion, error)
+	CountCompletions(userID uint) (int64, error)
+	GetCheckIn(userID, locationID uint) (*models.LocationCheckIn, error)
+	SaveCheckIn(checkIn *models.LocationCheckIn) error
+	DeleteCheckIn(userID, locationID uint) (bool, error)
+	GetCheckInsByItinerary(userID, itineraryID uint) ([]models.LocationCheckIn, error)
+	CountCheckInsByItinerary(userID uint, itineraryIDs []uint) (map[uint]int, error)
+	GetMapCheckIns(userID uint, onlyPublic bool) ([]models.LocationCheckIn, error)
+	GetMapPlaces(userID uint) ([]models.TravelMapPlace, error)
+}
+
+type TravelRepository struct {
+	db *gorm.DB
+}
+
+func NewTravelRepository(db *gorm.DB) TravelRepositoryInterface {
+	return &TravelRepository{db: db}
+}
+
+// CreateCompletion retorna false se o usuário já tinha concluído o roteiro
+func (r *TravelRepository) CreateCompletion(completion *models.ItineraryCompletion) (bool, error) {
+	result := r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(completion)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *TravelRepository) GetCompletion(userID, itineraryID uint) (*models.ItineraryCompletion, error) {
+	var completion models.ItineraryCompletion
+	err := r.db.Where("user_id = ? AND itinerary_id = ?", userID, itineraryID).First(&completion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &completion, nil
+}
+
+func (r *TravelRepository) DeleteCompletion(userID, itineraryID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND itinerary_id = ?", userID, itineraryID).
+		Delete(&models.ItineraryCompletion{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetCompletionsByUser lista as viagens concluídas, das mais recentes para as
+// mais antigas; roteiros excluídos depois da viagem continuam na lista
+func (r *TravelRepository) GetCompletionsByUser(userID uint, onlyPublic bool, limit, offset int) ([]models.ItineraryCompletion, error) {
+	query := r.db.Preload("Itinerary", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Itinerary.Author").
+		Where("itinerary_completions.user_id = ?", userID)
+
+	if onlyPublic {
+		query = query.Joins("JOIN itineraries ON itineraries.id = itinerary_completions.itinerary_id").
+			Where("itineraries.is_public = ?", true)
+	}
+
+	var completions []models.ItineraryCompletion
+	err := query.Order("itinerary_completions.traveled_at DESC, itinerary_completions.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&completions).Error
+	return completions, err
+}
+
+func (r *TravelRepository) CountCompletions(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ItineraryCompletion{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *TravelRepository) GetCheckIn(userID, locationID uint) (*models.LocationCheckIn, error) {
+	var checkIn models.LocationCheckIn
+	err := r.db.Where("user_id = ? AND location_id = ?", userID, locationID).First(&checkIn).Error
+	if err != nil {
+		return nil, err
+	}
+	return &checkIn, nil
+}
+
+func (r *TravelRepository) SaveCheckIn(checkIn *models.LocationCheckIn) error {
+	return r.db.Omit(clause.Associations).Save(checkIn).Error
+}
+
+func (r *TravelRepository) DeleteCheckIn(userID, locationID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND location_id = ?", userID, locationID).
+		Delete(&models.LocationCheckIn{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *TravelRepository) GetCheckInsByItinerary(userID, itineraryID uint) ([]models.LocationCheckIn, error) {
+	var checkIns []models.LocationCheckIn
+	err := r.db.Where("user_id = ? AND itinerary_id = ?", userID, itineraryID).
+		Order("checked_in_at, id").
+		Find(&checkIns).Error
+	return checkIns, err
+}
+
+func (r *TravelRepository) CountCheckInsByItinerary(userID uint, itineraryIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	if len(itineraryIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ItineraryID uint
+		Count       int
+	}
+	err := r.db.Model(&models.LocationCheckIn{}).
+		Select("itinerary_id, COUNT(*) AS count").
+		Where("user_id = ? AND itinerary_id IN ?", userID, itineraryIDs).
+		Group("itinerary_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ItineraryID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetMapCheckIns busca os check-ins com coordenadas, para os pontos do mapa
+func (r *TravelRepository) GetMapCheckIns(userID uint, onlyPublic bool) ([]models.LocationCheckIn, error) {
+	query := r.db.Where("location_check_ins.user_id = ?", userID).
+		Where("location_check_ins.latitude IS NOT NULL AND location_check_ins.longitude IS NOT NULL")
+
+	if onlyPublic {
+		query = query.Joins("JOIN itineraries ON itineraries.id = location_check_ins.itinerary_id").
+			Where("itineraries.is_public = ?", true)
+	}
+
+	var checkIns []models.LocationCheckIn
+	err := query.Order("location_check_ins.checked_in_at DESC").Find(&checkIns).Error
+	return checkIns, err
+}
+
+// GetMapPlaces agrupa as viagens concluídas por país e cidade dos roteiros
+func (r *TravelRepository) GetMapPlaces(userID uint) ([]models.TravelMapPlace, error) {
+	var places []models.TravelMapPlace
+	err := r.db.Table("itinerary_completions").
+		Select(`itineraries.country_id, COALESCE(geo_countries.code, '') AS country_code,
+			COALESCE(geo_countries.name, itineraries.country) AS country,
+			itineraries.city_id, COALESCE(geo_cities.name, itineraries.city) AS city,
+			geo_cities.latitude, geo_cities.longitude, COUNT(*) AS trips`).
+		Joins("JOIN itineraries ON itineraries.id = itinerary_completions.itinerary_id").
+		Joins("LEFT JOIN geo_countries ON geo_countries.id = itineraries.country_id").
+		Joins("LEFT JOIN geo_cities ON geo_cities.id = itineraries.city_id").
+		Where("itinerary_completions.user_id = ?", userID).
+		Group(`itineraries.country_id, geo_countries.code, geo_countries.name, itineraries.country,
+			itineraries.city_id, geo_cities.name, itineraries.city, geo_cities.latitude, geo_cities.longitude`).
+		Order("trips DESC").
+		Scan(&places).Error
+	return places, err
+}
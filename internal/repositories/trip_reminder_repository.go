@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TripReminderRepositoryInterface interface {
+	GetTripsStartingBetween(from, to time.Time) ([]models.Trip, error)
+	GetReminderSetting(tripID uint) (*models.TripReminderSetting, error)
+	GetReminderSettings(tripIDs []uint) ([]models.TripReminderSetting, error)
+	SaveReminderSetting(setting *models.TripReminderSetting) error
+	GetPackingItems(tripID uint) ([]models.TripPackingItem, error)
+	GetPackingItem(tripID, itemID uint) (*models.TripPackingItem, error)
+	CreatePackingItem(item *models.TripPackingItem) error
+	UpdatePackingItem(item *models.TripPackingItem) error
+	DeletePackingItem(tripID, itemID uint) (bool, error)
+	GetPackingStatus(tripID uint) (*models.TripPackingStatus, error)
+}
+
+type TripReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewTripReminderRepository(db *gorm.DB) TripReminderRepositoryInterface {
+	return &TripReminderRepository{db: db}
+}
+
+func (r *TripReminderRepository) GetTripsStartingBetween(from, to time.Time) ([]models.Trip, error) {
+	var trips []models.Trip
+	err := r.db.Where("start_date BETWEEN ? AND ?", from, to).
+		Find(&trips).Error
+	return trips, err
+}
+
+func (r *TripReminderRepository) GetReminderSetting(tripID uint) (*models.TripReminderSetting, error) {
+	var setting models.TripReminderSetting
+	if err := r.db.Where("trip_id = ?", tripID).First(&setting).Error; err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (r *TripReminderRepository) GetReminderSettings(tripIDs []uint) ([]models.TripReminderSetting, error) {
+	var settings []models.TripReminderSetting
+	if len(tripIDs) == 0 {
+		return settings, nil
+	}
+	err := r.db.Where("trip_id IN ?", tripIDs).Find(&settings).Error
+	return settings, err
+}
+
+func (r *TripReminderRepository) SaveReminderSetting(setting *models.TripReminderSetting) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "trip_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"days", "updated_at"}),
+	}).Create(setting).Error
+}
+
+func (r *TripReminderRepository) GetPackingItems(tripID uint) ([]models.TripPackingItem, error) {
+	var items []models.TripPackingItem
+	err := r.db.Where("trip_id = ?", tripID).Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r *TripReminderRepository) GetPackingItem(tripID, itemID uint) (*models.TripPackingItem, error) {
+	var item models.TripPackingItem
+	err := r.db.Where("id = ? AND trip_id = ?", itemID, tripID).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *TripReminderRepository) CreatePackingItem(item *models.TripPackingItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *TripReminderRepository) UpdatePackingItem(item *models.TripPackingItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *TripReminderRepository) DeletePackingItem(tripID, itemID uint) (bool, error) {
+	result := r.db.Where("id = ? AND trip_id = ?", itemID, tripID).Delete(&models.TripPackingItem{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetPackingStatus conta os itens da lista de bagagem e os já separados
+func (r *TripReminderRepository) GetPackingStatus(tripID uint) (*models.TripPackingStatus, error) {
+	var status models.TripPackingStatus
+	err := r.db.Model(&models.TripPackingItem{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE packed) AS packed").
+		Where("trip_id = ?", tripID).
+		Scan(&status).Error
+	return &status, err
+}
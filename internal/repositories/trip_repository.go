@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TripRepositoryInterface interface {
+	Create(trip *models.Trip) error
+	GetByID(id uint) (*models.Trip, error)
+	Update(trip *models.Trip) error
+	Delete(id uint) error
+	GetUpcomingByUser(userID uint, today time.Time, limit, offset int) ([]models.Trip, error)
+}
+
+type TripRepository struct {
+	db *gorm.DB
+}
+
+func NewTripRepository(db *gorm.DB) TripRepositoryInterface {
+	return &TripRepository{db: db}
+}
+
+func (r *TripRepository) Create(trip *models.Trip) error {
+	return r.db.Omit(clause.Associations).Create(trip).Error
+}
+
+func (r *TripRepository) GetByID(id uint) (*models.Trip, error) {
+	var trip models.Trip
+	err := r.db.Preload("Itinerary").
+		Preload("Itinerary.Author").
+		Where("id = ?", id).
+		First(&trip).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trip, nil
+}
+
+func (r *TripRepository) Update(trip *models.Trip) error {
+	return r.db.Omit(clause.Associations).Save(trip).Error
+}
+
+func (r *TripRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Trip{}, id).Error
+}
+
+// GetUpcomingByUser retorna as viagens em andamento e as futuras, a partir
+// da mais próxima
+func (r *TripRepository) GetUpcomingByUser(userID uint, today time.Time, limit, offset int) ([]models.Trip, error) {
+	var trips []models.Trip
+	err := r.db.Preload("Itinerary").
+		Preload("Itinerary.Author").
+		Where("user_id = ? AND end_date >= ?", userID, today).
+		Order("start_date, id").
+		Limit(limit).
+		Offset(offset).
+		Find(&trips).Error
+	return trips, err
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type UploadRepositoryInterface interface {
+	CreateWithinQuota(session *models.UploadSession, check func(open, pendingBytes int64) error) error
+	GetByID(id uint) (*models.UploadSession, error)
+	UpdateOffset(id uint, expected, offset int64, expiresAt time.Time) (bool, error)
+	Complete(id, mediaID uint) error
+	Delete(id uint) error
+	GetExpired(now time.Time, limit int) ([]models.UploadSession, error)
+}
+
+type UploadRepository struct {
+	db *gorm.DB
+}
+
+func NewUploadRepository(db *gorm.DB) UploadRepositoryInterface {
+	return &UploadRepository{db: db}
+}
+
+// CreateWithinQuota cria a sessão só se check aceitar o uso atual do dono
+// (sessões abertas e soma dos tamanhos declarados). O lock por usuário impede
+// que inícios simultâneos passem juntos pelo limite
+func (r *UploadRepository) CreateWithinQuota(session *models.UploadSession, check func(open, pendingBytes int64) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext('upload_sessions'))", session.OwnerID).Error; err != nil {
+			return err
+		}
+
+		var usage struct {
+			Open         int64
+			PendingBytes int64
+		}
+		err := tx.Model(&models.UploadSession{}).
+			Select("COUNT(*) AS open, COALESCE(SUM(total_size), 0) AS pending_bytes").
+			Where("owner_id = ? AND status = ? AND expires_at > ?", session.OwnerID, models.UploadStatusUploading, time.Now()).
+			Scan(&usage).Error
+		if err != nil {
+			return err
+		}
+		if err := check(usage.Open, usage.PendingBytes); err != nil {
+			return err
+		}
+
+		return tx.Omit("Owner").Create(session).Error
+	})
+}
+
+func (r *UploadRepository) GetByID(id uint) (*models.UploadSession, error) {
+	var session models.UploadSession
+	err := r.db.First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateOffset avança o offset só se ele ainda for o esperado, para que duas
+// requisições com a mesma parte não avancem a sessão duas vezes
+func (r *UploadRepository) UpdateOffset(id uint, expected, offset int64, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.UploadSession{}).
+		Where("id = ? AND status = ? AND \"offset\" = ?", id, models.UploadStatusUploading, expected).
+		Updates(map[string]interface{}{
+			"offset":     offset,
+			"expires_at": expiresAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *UploadRepository) Complete(id, mediaID uint) error {
+	return r.db.Model(&models.UploadSession{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":   models.UploadStatusCompleted,
+			"media_id": mediaID,
+		}).Error
+}
+
+func (r *UploadRepository) Delete(id uint) error {
+	return r.db.Delete(&models.UploadSession{}, id).Error
+}
+
+func (r *UploadRepository) GetExpired(now time.Time, limit int) ([]models.UploadSession, error) {
+	var sessions []models.UploadSession
+	err := r.db.Where("expires_at < ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&sessions).Error
+	return sessions, err
+}
@@ -1,8 +1,12 @@
 package repositories
 
 import (
+	"strings"
+	"time"
+
 	"github.com/Ulpio/guIA-backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepositoryInterface interface {
@@ -11,12 +15,21 @@ type UserRepositoryInterface interface {
 	GetByEmail(email string) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	Update(user *models.User) error
+	ReplaceProfileImage(userID uint, column, url string) (string, error)
 	Delete(id uint) error
 	GetFollowers(userID uint, limit, offset int) ([]models.User, error)
 	GetFollowing(userID uint, limit, offset int) ([]models.User, error)
 	FollowUser(followerID, followedID uint) error
 	UnfollowUser(followerID, followedID uint) error
 	IsFollowing(followerID, followedID uint) (bool, error)
+	FilterFollowers(userID uint, candidateIDs []uint) ([]uint, error)
+	GetFollowFlags(viewerID uint, userIDs []uint) (map[uint]bool, map[uint]bool, error)
+	BlockUser(blockerID, blockedID uint) (bool, error)
+	UnblockUser(blockerID, blockedID uint) (bool, error)
+	HasBlockBetween(userID, otherUserID uint) (bool, error)
+	GetBlockedUsers(userID uint, limit, offset int) ([]models.User, error)
+	UpdateLastSeen(userID uint, lastSeenAt time.Time) error
+	SetHidePresence(userID uint, hide bool) error
 	SearchUsers(query string, limit, offset int) ([]models.User, error)
 	UpdateCounts(userID uint) error
 }
@@ -64,6 +77,30 @@ func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// ReplaceProfileImage troca a URL da foto (profile_picture ou cover_photo)
+// com a linha travada, para que dois envios simultâneos não percam a foto
+// anterior de vista; retorna a URL substituída
+func (r *UserRepository) ReplaceProfileImage(userID uint, column, url string) (string, error) {
+	var previous string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current struct {
+			URL string
+		}
+		result := tx.Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select(column+" AS url").Where("id = ?", userID).Scan(&current)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		previous = current.URL
+
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update(column, url).Error
+	})
+	return previous, err
+}
+
 func (r *UserRepository) Delete(id uint) error {
 	return r.db.Model(&models.User{}).Where("id = ?", id).Update("is_active", false).Error
 }
@@ -153,17 +190,153 @@ func (r *UserRepository) IsFollowing(followerID, followedID uint) (bool, error)
 	return count > 0, err
 }
 
-func (r *UserRepository) SearchUsers(query string, limit, offset int) ([]models.User, error) {
+// FilterFollowers retorna, entre os candidatos, os que seguem o usuário
+func (r *UserRepository) FilterFollowers(userID uint, candidateIDs []uint) ([]uint, error) {
+	var followerIDs []uint
+	if len(candidateIDs) == 0 {
+		return followerIDs, nil
+	}
+
+	err := r.db.Model(&models.Follow{}).
+		Where("followed_id = ? AND follower_id IN ?", userID, candidateIDs).
+		Pluck("follower_id", &followerIDs).Error
+	return followerIDs, err
+}
+
+// GetFollowFlags retorna, para os usuários informados, quais o viewer segue e
+// quais seguem o viewer
+func (r *UserRepository) GetFollowFlags(viewerID uint, userIDs []uint) (map[uint]bool, map[uint]bool, error) {
+	following := make(map[uint]bool)
+	followers := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return following, followers, nil
+	}
+
+	var follows []models.Follow
+	err := r.db.Where("(follower_id = ? AND followed_id IN ?) OR (followed_id = ? AND follower_id IN ?)",
+		viewerID, userIDs, viewerID, userIDs).
+		Find(&follows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, follow := range follows {
+		if follow.FollowerID == viewerID {
+			following[follow.FollowedID] = true
+		}
+		if follow.FollowedID == viewerID {
+			followers[follow.FollowerID] = true
+		}
+	}
+
+	return following, followers, nil
+}
+
+// BlockUser registra o bloqueio; retorna false se ele já existia
+func (r *UserRepository) BlockUser(blockerID, blockedID uint) (bool, error) {
+	result := r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.UserBlock{BlockerID: blockerID, BlockedID: blockedID})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UnblockUser desfaz o bloqueio; retorna false se ele não existia
+func (r *UserRepository) UnblockUser(blockerID, blockedID uint) (bool, error) {
+	result := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&models.UserBlock{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// HasBlockBetween indica se um dos dois usuários bloqueou o outro
+func (r *UserRepository) HasBlockBetween(userID, otherUserID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)",
+			userID, otherUserID, otherUserID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetBlockedUsers lista quem o usuário bloqueou, dos bloqueios mais recentes
+// para os mais antigos
+func (r *UserRepository) GetBlockedUsers(userID uint, limit, offset int) ([]models.User, error) {
 	var users []models.User
-	searchQuery := "%" + query + "%"
-	err := r.db.Where("(username ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ? OR company_name ILIKE ?) AND is_active = ?",
-		searchQuery, searchQuery, searchQuery, searchQuery, true).
+	err := r.db.Joins("JOIN user_blocks ON user_blocks.blocked_id = users.id").
+		Where("user_blocks.blocker_id = ?", userID).
+		Order("user_blocks.created_at DESC, user_blocks.id DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&users).Error
 	return users, err
 }
 
+// UpdateLastSeen guarda quando o usuário fechou a última conexão em tempo
+// real
+func (r *UserRepository) UpdateLastSeen(userID uint, lastSeenAt time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("last_seen_at", lastSeenAt).Error
+}
+
+func (r *UserRepository) SetHidePresence(userID uint, hide bool) error {
+	result := r.db.Model(&models.User{}).Where("id = ? AND is_active = ?", userID, true).Update("hide_presence", hide)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SearchUsers busca pelo username, nome e empresa, dos mais relevantes para
+// os menos
+// userSearchName é o texto comparado por trigramas na busca de usuários:
+// username e nome, em minúsculas e sem acentos. Precisa ser idêntico à
+// expressão do índice idx_users_name_trgm (ver database.migrateSearch)
+const userSearchName = "search_unaccent(lower(username || ' ' || coalesce(first_name, '') || ' ' || coalesce(last_name, '')))"
+
+// userSimilarityThreshold é a similaridade mínima (word_similarity) entre o
+// texto buscado e o username ou nome; abaixo do padrão do pg_trgm (0.6) para
+// aceitar erros de digitação em textos curtos
+const userSimilarityThreshold = "0.4"
+
+// SearchUsers busca por similaridade de trigramas no username e no nome, sem
+// considerar acentos, para encontrar perfis mesmo com erros de digitação, e
+// também pela busca textual (que inclui o nome da empresa). Os mais parecidos
+// vêm primeiro, com os mais seguidos subindo entre os de similaridade próxima
+func (r *UserRepository) SearchUsers(query string, limit, offset int) ([]models.User, error) {
+	var users []models.User
+	query = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	if query == "" {
+		return users, nil
+	}
+	normalized := "search_unaccent(lower(?))"
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// O limiar do operador <% vale só para esta transação
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", userSimilarityThreshold).Error; err != nil {
+			return err
+		}
+
+		match := normalized + " <% " + userSearchName
+		args := []interface{}{query}
+		if terms := searchTerms(query); terms != "" {
+			match = "(" + match + " OR users.search_vector @@ " + searchTSQuery + ")"
+			args = append(args, terms, terms)
+		}
+
+		return tx.Where(match, args...).
+			Where("users.is_active = ?", true).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "word_similarity(" + normalized + ", " + userSearchName + ") + 0.05 * log(1 + users.followers_count) DESC, users.followers_count DESC, users.id",
+				Vars: []interface{}{query},
+			}}).
+			Limit(limit).
+			Offset(offset).
+			Find(&users).Error
+	})
+	return users, err
+}
+
 func (r *UserRepository) UpdateCounts(userID uint) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		var postsCount, itinerariesCount, followersCount, followingCount int64
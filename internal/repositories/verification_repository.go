@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type VerificationRepositoryInterface interface {
+	Create(request *models.VerificationRequest) error
+	GetByID(id uint) (*models.VerificationRequest, error)
+	GetLatestByUser(userID uint) (*models.VerificationRequest, error)
+	GetByStatus(status models.VerificationStatus, limit, offset int) ([]models.VerificationRequest, error)
+	Review(request *models.VerificationRequest, audit *models.ModerationAction) (bool, error)
+}
+
+type VerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewVerificationRepository(db *gorm.DB) VerificationRepositoryInterface {
+	return &VerificationRepository{db: db}
+}
+
+func (r *VerificationRepository) Create(request *models.VerificationRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *VerificationRepository) GetByID(id uint) (*models.VerificationRequest, error) {
+	var request models.VerificationRequest
+	err := r.db.Preload("User").Where("id = ?", id).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetLatestByUser retorna o pedido mais recente do usuário
+func (r *VerificationRepository) GetLatestByUser(userID uint) (*models.VerificationRequest, error) {
+	var request models.VerificationRequest
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetByStatus lista os pedidos da fila de verificação, dos mais antigos para
+// os mais recentes
+func (r *VerificationRepository) GetByStatus(status models.VerificationStatus, limit, offset int) ([]models.VerificationRequest, error) {
+	var requests []models.VerificationRequest
+	err := r.db.Preload("User").
+		Where("status = ?", status).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&requests).Error
+	return requests, err
+}
+
+// Review grava a decisão do pedido e, se aprovado, dá o selo à conta; retorna
+// false se o pedido já tinha sido analisado por outro admin
+func (r *VerificationRepository) Review(request *models.VerificationRequest, audit *models.ModerationAction) (bool, error) {
+	now := time.Now()
+	reviewed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.VerificationRequest{}).
+			Where("id = ? AND status = ?", request.ID, models.VerificationPending).
+			Updates(map[string]interface{}{
+				"status":         request.Status,
+				"reviewed_by_id": audit.AdminID,
+				"review_note":    request.ReviewNote,
+				"reviewed_at":    now,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if request.Status == models.VerificationApproved {
+			if err := tx.Model(&models.User{}).Where("id = ?", request.UserID).
+				Update("is_verified", true).Error; err != nil {
+				return err
+			}
+		}
+
+		reviewed = true
+		return tx.Create(audit).Error
+	})
+	if err != nil || !reviewed {
+		return false, err
+	}
+
+	request.ReviewedByID = &audit.AdminID
+	request.ReviewedAt = &now
+	return true, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WeatherRepositoryInterface interface {
+	GetForecasts(cell string, from, to time.Time) ([]models.WeatherForecast, error)
+	SaveForecasts(forecasts []models.WeatherForecast) error
+}
+
+type WeatherRepository struct {
+	db *gorm.DB
+}
+
+func NewWeatherRepository(db *gorm.DB) WeatherRepositoryInterface {
+	return &WeatherRepository{db: db}
+}
+
+func (r *WeatherRepository) GetForecasts(cell string, from, to time.Time) ([]models.WeatherForecast, error) {
+	var forecasts []models.WeatherForecast
+	err := r.db.Where("cell = ? AND date BETWEEN ? AND ?", cell, from, to).
+		Order("date").
+		Find(&forecasts).Error
+	return forecasts, err
+}
+
+func (r *WeatherRepository) SaveForecasts(forecasts []models.WeatherForecast) error {
+	if len(forecasts) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cell"}, {Name: "date"}},
+		UpdateAll: true,
+	}).Create(&forecasts).Error
+}
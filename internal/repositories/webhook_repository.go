@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepositoryInterface interface {
+	Create(event *models.WebhookEvent) (bool, error)
+	GetByID(id uint) (*models.WebhookEvent, error)
+	GetByProviderEventID(provider models.WebhookProvider, eventID string) (*models.WebhookEvent, error)
+	Claim(id uint) (bool, error)
+	UpdateResult(event *models.WebhookEvent) error
+	Requeue(id uint) (bool, error)
+	GetDue(now time.Time, limit int) ([]uint, error)
+	ReleaseProcessing() error
+	GetEvents(provider models.WebhookProvider, status models.WebhookEventStatus, limit, offset int) ([]models.WebhookEvent, error)
+}
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepositoryInterface {
+	return &WebhookRepository{db: db}
+}
+
+// Create grava o evento recebido; retorna false quando o provedor já havia
+// entregue o mesmo evento
+func (r *WebhookRepository) Create(event *models.WebhookEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *WebhookRepository) GetByID(id uint) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *WebhookRepository) GetByProviderEventID(provider models.WebhookProvider, eventID string) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	err := r.db.Where("provider = ? AND event_id = ?", provider, eventID).First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Claim marca o evento como em processamento apenas se ele ainda aguarda
+// processamento, evitando que o worker e o agendador o processem juntos
+func (r *WebhookRepository) Claim(id uint) (bool, error) {
+	result := r.db.Model(&models.WebhookEvent{}).
+		Where("id = ? AND status IN ?", id, []models.WebhookEventStatus{models.WebhookEventPending, models.WebhookEventFailed}).
+		Updates(map[string]interface{}{
+			"status":   models.WebhookEventProcessing,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *WebhookRepository) UpdateResult(event *models.WebhookEvent) error {
+	return r.db.Model(event).
+		Select("Status", "LastError", "NextAttemptAt", "ProcessedAt").
+		Updates(event).Error
+}
+
+// Requeue devolve o evento à fila com as tentativas zeradas; eventos em
+// processamento não são alterados
+func (r *WebhookRepository) Requeue(id uint) (bool, error) {
+	result := r.db.Model(&models.WebhookEvent{}).
+		Where("id = ? AND status <> ?", id, models.WebhookEventProcessing).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookEventPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetDue retorna os eventos cuja próxima tentativa já venceu
+func (r *WebhookRepository) GetDue(now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.WebhookEvent{}).
+		Where("status IN ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+			[]models.WebhookEventStatus{models.WebhookEventPending, models.WebhookEventFailed}, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ReleaseProcessing devolve à fila os eventos interrompidos por uma
+// reinicialização no meio do processamento
+func (r *WebhookRepository) ReleaseProcessing() error {
+	return r.db.Model(&models.WebhookEvent{}).
+		Where("status = ?", models.WebhookEventProcessing).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookEventPending,
+			"next_attempt_at": time.Now(),
+		}).Error
+}
+
+func (r *WebhookRepository) GetEvents(provider models.WebhookProvider, status models.WebhookEventStatus, limit, offset int) ([]models.WebhookEvent, error) {
+	var events []models.WebhookEvent
+	query := r.db.Model(&models.WebhookEvent{})
+	if provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+	return events, err
+}
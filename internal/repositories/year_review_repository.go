@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type YearReviewRepositoryInterface interface {
+	GetByUserAndYear(userID uint, year int) (*models.YearReview, error)
+	Save(review *models.YearReview) error
+	GetPostsInRange(userID uint, from, to time.Time) ([]models.Post, error)
+	GetItinerariesInRange(userID uint, from, to time.Time) ([]models.Itinerary, error)
+	GetUsersPendingReview(year int, from, to time.Time) ([]uint, error)
+}
+
+type YearReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewYearReviewRepository(db *gorm.DB) YearReviewRepositoryInterface {
+	return &YearReviewRepository{db: db}
+}
+
+func (r *YearReviewRepository) GetByUserAndYear(userID uint, year int) (*models.YearReview, error) {
+	var review models.YearReview
+	err := r.db.Preload("User").
+		Where("user_id = ? AND year = ?", userID, year).
+		First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Save grava o resumo, substituindo o gerado anteriormente para o mesmo ano
+func (r *YearReviewRepository) Save(review *models.YearReview) error {
+	return r.db.Omit("User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "year"}},
+		DoUpdates: clause.AssignmentColumns([]string{"stats", "image_url", "image_path", "generated_at", "updated_at"}),
+	}).Create(review).Error
+}
+
+func (r *YearReviewRepository) GetPostsInRange(userID uint, from, to time.Time) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Preload("Author").
+		Where("author_id = ? AND is_active = ?", userID, true).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at ASC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *YearReviewRepository) GetItinerariesInRange(userID uint, from, to time.Time) ([]models.Itinerary, error) {
+	var itineraries []models.Itinerary
+	err := r.db.Where("author_id = ?", userID).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at ASC").
+		Find(&itineraries).Error
+	return itineraries, err
+}
+
+// GetUsersPendingReview retorna os usuários ativos que publicaram no período e
+// ainda não têm o resumo do ano gerado após o seu encerramento
+func (r *YearReviewRepository) GetUsersPendingReview(year int, from, to time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Raw(`
+		SELECT u.id FROM users u
+		WHERE u.deleted_at IS NULL AND u.is_active = true
+		AND NOT EXISTS (SELECT 1 FROM year_reviews y WHERE y.user_id = u.id AND y.year = ? AND y.generated_at >= ?)
+		AND (
+			EXISTS (
+				SELECT 1 FROM posts p
+				WHERE p.author_id = u.id AND p.deleted_at IS NULL AND p.is_active = true
+				AND p.created_at >= ? AND p.created_at < ?
+			)
+			OR EXISTS (
+				SELECT 1 FROM itineraries i
+				WHERE i.author_id = u.id AND i.deleted_at IS NULL
+				AND i.created_at >= ? AND i.created_at < ?
+			)
+		)
+		ORDER BY u.id`,
+		year, to, from, to, from, to,
+	).Scan(&userIDs).Error
+	return userIDs, err
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type AdminUserQuery struct {
+	Query    string
+	UserType models.UserType
+	Status   models.AccountStatus
+	Verified *bool
+}
+
+type AdminServiceInterface interface {
+	GetUsers(query *AdminUserQuery, limit, offset int) ([]models.AdminUserResponse, error)
+	GetUser(userID uint) (*models.AdminUserResponse, error)
+}
+
+type AdminService struct {
+	adminRepo      repositories.AdminRepositoryInterface
+	moderationRepo repositories.ModerationRepositoryInterface
+}
+
+func NewAdminService(adminRepo repositories.AdminRepositoryInterface, moderationRepo repositories.ModerationRepositoryInterface) AdminServiceInterface {
+	return &AdminService{
+		adminRepo:      adminRepo,
+		moderationRepo: moderationRepo,
+	}
+}
+
+func (s *AdminService) GetUsers(query *AdminUserQuery, limit, offset int) ([]models.AdminUserResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	filter := repositories.AdminUserFilter{
+		Query:      strings.TrimSpace(query.Query),
+		IsVerified: query.Verified,
+	}
+	if query.UserType != "" {
+		if query.UserType != models.UserTypeNormal && query.UserType != models.UserTypeCompany && query.UserType != models.UserTypeAdmin {
+			return nil, errors.New("tipo de usuário inválido")
+		}
+		filter.UserType = query.UserType
+	}
+	switch query.Status {
+	case "", models.AccountStatusActive, models.AccountStatusSuspended, models.AccountStatusBanned, models.AccountStatusDeactivated:
+		filter.Status = query.Status
+	default:
+		return nil, errors.New("situação inválida; use active, suspended, banned ou deactivated")
+	}
+
+	users, err := s.adminRepo.GetUsers(filter, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar usuários")
+	}
+
+	responses := make([]models.AdminUserResponse, len(users))
+	for i := range users {
+		responses[i] = *users[i].ToAdminResponse()
+	}
+	return responses, nil
+}
+
+func (s *AdminService) GetUser(userID uint) (*models.AdminUserResponse, error) {
+	user, err := s.moderationRepo.GetUserForModeration(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	return user.ToAdminResponse(), nil
+}
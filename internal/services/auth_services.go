@@ -16,6 +16,7 @@ import (
 type AuthServiceInterface interface {
 	Register(req *RegisterRequest) (*AuthResponse, error)
 	Login(req *LoginRequest) (*AuthResponse, error)
+	Authenticate(req *LoginRequest) (*models.User, error)
 	ValidateToken(tokenString string) (*TokenClaims, error)
 	RefreshToken(tokenString string) (*AuthResponse, error)
 }
@@ -123,6 +124,33 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 }
 
 func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
+	user, err := s.Authenticate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Contas suspensas, banidas ou desativadas não entram
+	if err := accountRestrictionError(user, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Gerar tokens
+	token, refreshToken, expiresAt, err := s.generateTokens(user)
+	if err != nil {
+		return nil, errors.New("erro ao gerar token de acesso")
+	}
+
+	return &AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user.ToResponse(),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// Authenticate confere o login e a senha sem olhar a situação da conta (o
+// recurso contra a suspensão usa as credenciais da conta suspensa)
+func (s *AuthService) Authenticate(req *LoginRequest) (*models.User, error) {
 	// Validações
 	if err := s.validateLoginRequest(req); err != nil {
 		return nil, err
@@ -150,23 +178,7 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 		return nil, errors.New("credenciais inválidas")
 	}
 
-	// Verificar se conta está ativa
-	if !user.IsActive {
-		return nil, errors.New("conta desativada")
-	}
-
-	// Gerar tokens
-	token, refreshToken, expiresAt, err := s.generateTokens(user)
-	if err != nil {
-		return nil, errors.New("erro ao gerar token de acesso")
-	}
-
-	return &AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		User:         user.ToResponse(),
-		ExpiresAt:    expiresAt,
-	}, nil
+	return user, nil
 }
 
 func (s *AuthService) ValidateToken(tokenString string) (*TokenClaims, error) {
@@ -197,8 +209,8 @@ func (s *AuthService) RefreshToken(tokenString string) (*AuthResponse, error) {
 		return nil, errors.New("usuário não encontrado")
 	}
 
-	if !user.IsActive {
-		return nil, errors.New("conta desativada")
+	if err := accountRestrictionError(user, time.Now()); err != nil {
+		return nil, err
 	}
 
 	// Gerar novos tokens
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+// ErrBillingNotConfigured indica que nenhum provedor de pagamentos foi configurado
+var ErrBillingNotConfigured = errors.New("pagamentos não configurados")
+
+// ErrBillingUncertain indica que a operação pode ter sido executada pelo
+// provedor mesmo com o erro (falha de rede, erro interno ou resposta
+// ilegível), então não deve ser dada como recusada
+var ErrBillingUncertain = errors.New("resultado incerto no provedor de pagamentos")
+
+// uncertainBillingError marca um erro do provedor com ErrBillingUncertain sem
+// mudar a mensagem
+type uncertainBillingError struct {
+	err error
+}
+
+func (e *uncertainBillingError) Error() string { return e.err.Error() }
+
+func (e *uncertainBillingError) Unwrap() error { return e.err }
+
+func (e *uncertainBillingError) Is(target error) bool { return target == ErrBillingUncertain }
+
+type BillingConfig struct {
+	Provider           string // "stripe"
+	StripeSecretKey    string
+	StripeAPIURL       string
+	PlatformFeePercent int   // comissão da plataforma sobre cada gorjeta
+	MinTipAmount       int64 // em centavos
+	MaxTipAmount       int64 // em centavos
+	MinPayoutAmount    int64 // em centavos
+	PayoutDelayDays    int   // carência entre o pedido e o envio do saque
+
+	// Preço por dia das promoções de parceiros nas páginas de destino
+	PromotionDailyPrice int64 // em centavos
+	PromotionCurrency   string
+
+	// Preço mensal do plano guIA Plus
+	SubscriptionMonthlyPrice int64 // em centavos
+	SubscriptionCurrency     string
+}
+
+type PaymentIntentStatus string
+
+const (
+	PaymentIntentProcessing PaymentIntentStatus = "processing"
+	PaymentIntentSucceeded  PaymentIntentStatus = "succeeded"
+	PaymentIntentFailed     PaymentIntentStatus = "failed"
+)
+
+type PaymentIntent struct {
+	ID           string
+	ClientSecret string
+	Status       PaymentIntentStatus
+}
+
+type PaymentIntentParams struct {
+	Amount             int64
+	Currency           string
+	PlatformFee        int64
+	DestinationAccount string // vazio para vendas da própria plataforma, sem repasse
+	ManualCapture      bool   // autoriza sem capturar, para pagamentos em revisão manual
+	Description        string
+	Metadata           map[string]string
+}
+
+type ConnectedAccount struct {
+	ID               string
+	Country          string
+	ChargesEnabled   bool
+	PayoutsEnabled   bool
+	DetailsSubmitted bool
+}
+
+// BillingProviderInterface abstrai o provedor de pagamentos usado para cobrar
+// apoiadores e repassar os valores às contas dos criadores
+type BillingProviderInterface interface {
+	Name() string
+	CreatePaymentIntent(params *PaymentIntentParams) (*PaymentIntent, error)
+	GetPaymentIntent(id string) (*PaymentIntent, error)
+	CapturePaymentIntent(id string) (*PaymentIntent, error)
+	CancelPaymentIntent(id string) error
+	CreateConnectedAccount(email, country string) (*ConnectedAccount, error)
+	GetConnectedAccount(id string) (*ConnectedAccount, error)
+	CreateOnboardingLink(accountID, refreshURL, returnURL string) (string, error)
+	CreateRefund(paymentIntentID string) (string, error)
+	// CreatePayout envia o saque; repetir a chamada com a mesma chave de
+	// idempotência não cria um segundo saque
+	CreatePayout(accountID string, amount int64, currency, idempotencyKey string) (string, error)
+}
+
+func NewBillingProvider(config *BillingConfig) BillingProviderInterface {
+	switch config.Provider {
+	case "stripe":
+		if config.StripeSecretKey == "" {
+			return &disabledBillingProvider{}
+		}
+		apiURL := config.StripeAPIURL
+		if apiURL == "" {
+			apiURL = "https://api.stripe.com"
+		}
+		return &stripeBillingProvider{
+			secretKey: config.StripeSecretKey,
+			apiURL:    strings.TrimRight(apiURL, "/"),
+			client:    &http.Client{Timeout: 15 * time.Second},
+		}
+	default:
+		return &disabledBillingProvider{}
+	}
+}
+
+// disabledBillingProvider é usado quando não há credenciais; todas as
+// operações falham com ErrBillingNotConfigured
+type disabledBillingProvider struct{}
+
+func (p *disabledBillingProvider) Name() string { return "none" }
+
+func (p *disabledBillingProvider) CreatePaymentIntent(params *PaymentIntentParams) (*PaymentIntent, error) {
+	return nil, ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) GetPaymentIntent(id string) (*PaymentIntent, error) {
+	return nil, ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) CapturePaymentIntent(id string) (*PaymentIntent, error) {
+	return nil, ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) CancelPaymentIntent(id string) error {
+	return ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) CreateConnectedAccount(email, country string) (*ConnectedAccount, error) {
+	return nil, ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) GetConnectedAccount(id string) (*ConnectedAccount, error) {
+	return nil, ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) CreateOnboardingLink(accountID, refreshURL, returnURL string) (string, error) {
+	return "", ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) CreateRefund(paymentIntentID string) (string, error) {
+	return "", ErrBillingNotConfigured
+}
+
+func (p *disabledBillingProvider) CreatePayout(accountID string, amount int64, currency, idempotencyKey string) (string, error) {
+	return "", ErrBillingNotConfigured
+}
+
+// stripeBillingProvider usa a API REST da Stripe com Connect (contas Express)
+// e cobranças com destino, retendo a comissão da plataforma
+type stripeBillingProvider struct {
+	secretKey string
+	apiURL    string
+	client    *http.Client
+}
+
+type stripePaymentIntent struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+	Status       string `json:"status"`
+}
+
+type stripeAccount struct {
+	ID               string `json:"id"`
+	Country          string `json:"country"`
+	ChargesEnabled   bool   `json:"charges_enabled"`
+	PayoutsEnabled   bool   `json:"payouts_enabled"`
+	DetailsSubmitted bool   `json:"details_submitted"`
+}
+
+func (p *stripeBillingProvider) Name() string { return "stripe" }
+
+func (p *stripeBillingProvider) CreatePaymentIntent(params *PaymentIntentParams) (*PaymentIntent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(params.Amount, 10))
+	form.Set("currency", strings.ToLower(params.Currency))
+	if params.DestinationAccount != "" {
+		form.Set("application_fee_amount", strconv.FormatInt(params.PlatformFee, 10))
+		form.Set("transfer_data[destination]", params.DestinationAccount)
+	}
+	form.Set("automatic_payment_methods[enabled]", "true")
+	if params.ManualCapture {
+		form.Set("capture_method", "manual")
+	}
+	if params.Description != "" {
+		form.Set("description", params.Description)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var intent stripePaymentIntent
+	if err := p.do("", http.MethodPost, "/v1/payment_intents", form, &intent); err != nil {
+		return nil, err
+	}
+	return intent.toPaymentIntent(), nil
+}
+
+func (p *stripeBillingProvider) GetPaymentIntent(id string) (*PaymentIntent, error) {
+	var intent stripePaymentIntent
+	if err := p.do("", http.MethodGet, "/v1/payment_intents/"+url.PathEscape(id), nil, &intent); err != nil {
+		return nil, err
+	}
+	return intent.toPaymentIntent(), nil
+}
+
+func (p *stripeBillingProvider) CapturePaymentIntent(id string) (*PaymentIntent, error) {
+	var intent stripePaymentIntent
+	if err := p.do("", http.MethodPost, "/v1/payment_intents/"+url.PathEscape(id)+"/capture", url.Values{}, &intent); err != nil {
+		return nil, err
+	}
+	return intent.toPaymentIntent(), nil
+}
+
+func (p *stripeBillingProvider) CancelPaymentIntent(id string) error {
+	var intent stripePaymentIntent
+	return p.do("", http.MethodPost, "/v1/payment_intents/"+url.PathEscape(id)+"/cancel", url.Values{}, &intent)
+}
+
+func (p *stripeBillingProvider) CreateConnectedAccount(email, country string) (*ConnectedAccount, error) {
+	form := url.Values{}
+	form.Set("type", "express")
+	form.Set("email", email)
+	form.Set("country", country)
+	form.Set("capabilities[card_payments][requested]", "true")
+	form.Set("capabilities[transfers][requested]", "true")
+
+	var account stripeAccount
+	if err := p.do("", http.MethodPost, "/v1/accounts", form, &account); err != nil {
+		return nil, err
+	}
+	return account.toConnectedAccount(), nil
+}
+
+func (p *stripeBillingProvider) GetConnectedAccount(id string) (*ConnectedAccount, error) {
+	var account stripeAccount
+	if err := p.do("", http.MethodGet, "/v1/accounts/"+url.PathEscape(id), nil, &account); err != nil {
+		return nil, err
+	}
+	return account.toConnectedAccount(), nil
+}
+
+func (p *stripeBillingProvider) CreateOnboardingLink(accountID, refreshURL, returnURL string) (string, error) {
+	form := url.Values{}
+	form.Set("account", accountID)
+	form.Set("refresh_url", refreshURL)
+	form.Set("return_url", returnURL)
+	form.Set("type", "account_onboarding")
+
+	var link struct {
+		URL string `json:"url"`
+	}
+	if err := p.do("", http.MethodPost, "/v1/account_links", form, &link); err != nil {
+		return "", err
+	}
+	return link.URL, nil
+}
+
+// CreateRefund estorna a cobrança, revertendo também o repasse ao criador e a
+// comissão da plataforma
+func (p *stripeBillingProvider) CreateRefund(paymentIntentID string) (string, error) {
+	form := url.Values{}
+	form.Set("payment_intent", paymentIntentID)
+	form.Set("reverse_transfer", "true")
+	form.Set("refund_application_fee", "true")
+
+	var refund struct {
+		ID string `json:"id"`
+	}
+	if err := p.do("", http.MethodPost, "/v1/refunds", form, &refund); err != nil {
+		return "", err
+	}
+	return refund.ID, nil
+}
+
+// CreatePayout transfere o saldo da conta conectada para a conta bancária do criador
+func (p *stripeBillingProvider) CreatePayout(accountID string, amount int64, currency, idempotencyKey string) (string, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(amount, 10))
+	form.Set("currency", strings.ToLower(currency))
+
+	var payout struct {
+		ID string `json:"id"`
+	}
+	if err := p.doIdempotent(accountID, idempotencyKey, http.MethodPost, "/v1/payouts", form, &payout); err != nil {
+		return "", err
+	}
+	return payout.ID, nil
+}
+
+// do executa a chamada na API; connectedAccount, quando informado, executa a
+// operação em nome da conta conectada
+func (p *stripeBillingProvider) do(connectedAccount, method, path string, form url.Values, out interface{}) error {
+	return p.doIdempotent(connectedAccount, "", method, path, form, out)
+}
+
+// doIdempotent executa a chamada com a chave de idempotência, quando
+// informada: a API devolve o resultado da primeira chamada com a mesma chave
+// em vez de repetir a operação. Erros que não garantem que a operação deixou
+// de ser feita são marcados com ErrBillingUncertain
+func (p *stripeBillingProvider) doIdempotent(connectedAccount, idempotencyKey, method, path string, form url.Values, out interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequest(method, p.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.secretKey, "")
+	if connectedAccount != "" {
+		req.Header.Set("Stripe-Account", connectedAccount)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return &uncertainBillingError{fmt.Errorf("erro ao acessar provedor de pagamentos: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		err := fmt.Errorf("erro ao processar pagamento: %s", apiErr.Error.Message)
+		// 409 é outra chamada com a mesma chave ainda em andamento
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusConflict {
+			return &uncertainBillingError{err}
+		}
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return &uncertainBillingError{fmt.Errorf("erro ao ler resposta do provedor de pagamentos: %w", err)}
+	}
+	return nil
+}
+
+// paymentIntentFromWebhook extrai o ID do pagamento de um evento do provedor;
+// eventos de outros objetos voltam vazios
+func paymentIntentFromWebhook(event *models.WebhookEvent) (string, error) {
+	var payload struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID     string `json:"id"`
+				Object string `json:"object"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return "", errors.New("payload do evento de pagamento inválido")
+	}
+
+	if payload.Data.Object.Object != "payment_intent" {
+		return "", nil
+	}
+	return payload.Data.Object.ID, nil
+}
+
+func (i *stripePaymentIntent) toPaymentIntent() *PaymentIntent {
+	status := PaymentIntentProcessing
+	switch i.Status {
+	case "succeeded":
+		status = PaymentIntentSucceeded
+	case "canceled":
+		status = PaymentIntentFailed
+	}
+
+	return &PaymentIntent{
+		ID:           i.ID,
+		ClientSecret: i.ClientSecret,
+		Status:       status,
+	}
+}
+
+func (a *stripeAccount) toConnectedAccount() *ConnectedAccount {
+	return &ConnectedAccount{
+		ID:               a.ID,
+		Country:          a.Country,
+		ChargesEnabled:   a.ChargesEnabled,
+		PayoutsEnabled:   a.PayoutsEnabled,
+		DetailsSubmitted: a.DetailsSubmitted,
+	}
+}
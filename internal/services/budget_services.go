@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type BudgetCategory string
+
+const (
+	BudgetCategoryLodging    BudgetCategory = "lodging"
+	BudgetCategoryFood       BudgetCategory = "food"
+	BudgetCategoryTransport  BudgetCategory = "transport"
+	BudgetCategoryActivities BudgetCategory = "activities"
+	BudgetCategoryShopping   BudgetCategory = "shopping"
+	BudgetCategoryOther      BudgetCategory = "other"
+)
+
+// budgetCategories define a ordem das categorias no resumo
+var budgetCategories = []BudgetCategory{
+	BudgetCategoryLodging,
+	BudgetCategoryFood,
+	BudgetCategoryTransport,
+	BudgetCategoryActivities,
+	BudgetCategoryShopping,
+	BudgetCategoryOther,
+}
+
+// Origem do valor de um dia ou do roteiro no orçamento
+const (
+	BudgetSourceLocations = "locations" // soma dos custos dos locais
+	BudgetSourceDays      = "days"      // soma dos custos dos dias
+	BudgetSourceDeclared  = "declared"  // custo informado no próprio dia/roteiro
+	BudgetSourceNone      = "none"
+)
+
+type BudgetCategoryTotal struct {
+	Category   BudgetCategory `json:"category"`
+	Amount     float64        `json:"amount"`
+	Percentage float64        `json:"percentage"`
+	Locations  int            `json:"locations"`
+}
+
+type DayBudget struct {
+	DayID         uint     `json:"day_id"`
+	DayNumber     int      `json:"day_number"`
+	Title         string   `json:"title"`
+	Total         float64  `json:"total"`
+	Source        string   `json:"source"`
+	LocationsCost float64  `json:"locations_cost"`
+	DeclaredCost  *float64 `json:"declared_cost"`
+}
+
+// ItineraryBudget é o orçamento calculado do roteiro. Unallocated é a parte
+// do total que veio de custos informados nos dias ou no roteiro, sem local
+// (e portanto sem categoria)
+type ItineraryBudget struct {
+	ItineraryID          uint                  `json:"itinerary_id"`
+	Currency             string                `json:"currency"`
+	Total                float64               `json:"total"`
+	Source               string                `json:"source"`
+	DeclaredCost         *float64              `json:"declared_cost"`
+	PerDay               float64               `json:"per_day"`
+	Categories           []BudgetCategoryTotal `json:"categories"`
+	Unallocated          float64               `json:"unallocated"`
+	Days                 []DayBudget           `json:"days"`
+	LocationsWithoutCost int                   `json:"locations_without_cost"`
+}
+
+type BudgetServiceInterface interface {
+	GetBudget(itineraryID, userID uint, currency string) (*ItineraryBudget, error)
+}
+
+type BudgetService struct {
+	itineraryRepo   repositories.ItineraryRepositoryInterface
+	currencyService CurrencyServiceInterface
+}
+
+func NewBudgetService(itineraryRepo repositories.ItineraryRepositoryInterface, currencyService CurrencyServiceInterface) BudgetServiceInterface {
+	return &BudgetService{
+		itineraryRepo:   itineraryRepo,
+		currencyService: currencyService,
+	}
+}
+
+// GetBudget consolida os custos estimados de baixo para cima: os locais
+// somam no dia e os dias somam no roteiro. Um dia sem custos nos locais usa
+// o custo informado no próprio dia, e o roteiro sem custos nos dias usa o
+// custo informado no roteiro
+func (s *BudgetService) GetBudget(itineraryID, userID uint, currency string) (*ItineraryBudget, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	budget := rollupBudget(itinerary)
+
+	currency = normalizeCurrency(currency)
+	if currency != "" && currency != budget.Currency {
+		if !isValidCurrency(currency) {
+			return nil, errors.New("moeda inválida: use um código ISO 4217, como BRL ou USD")
+		}
+		fromRate, okFrom := s.currencyService.RateFor(budget.Currency)
+		toRate, okTo := s.currencyService.RateFor(currency)
+		if !okFrom || !okTo {
+			return nil, fmt.Errorf("cotação indisponível para converter %s em %s", budget.Currency, currency)
+		}
+		convertBudget(budget, toRate/fromRate, currency)
+	}
+
+	return budget, nil
+}
+
+func rollupBudget(itinerary *models.Itinerary) *ItineraryBudget {
+	currency := normalizeCurrency(itinerary.Currency)
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	budget := &ItineraryBudget{
+		ItineraryID:  itinerary.ID,
+		Currency:     currency,
+		Source:       BudgetSourceNone,
+		DeclaredCost: itinerary.EstimatedCost,
+		Days:         []DayBudget{},
+	}
+
+	categories := make(map[BudgetCategory]*BudgetCategoryTotal, len(budgetCategories))
+	for _, category := range budgetCategories {
+		categories[category] = &BudgetCategoryTotal{Category: category}
+	}
+
+	daysWithCost := false
+	for _, day := range sortedDays(itinerary) {
+		dayBudget := DayBudget{
+			DayID:        day.ID,
+			DayNumber:    day.DayNumber,
+			Title:        day.Title,
+			Source:       BudgetSourceNone,
+			DeclaredCost: day.EstimatedCost,
+		}
+
+		for _, location := range day.Locations {
+			if location.EstimatedCost == nil {
+				budget.LocationsWithoutCost++
+				continue
+			}
+			total := categories[budgetCategoryFor(location.LocationType)]
+			total.Amount += *location.EstimatedCost
+			total.Locations++
+			dayBudget.LocationsCost += *location.EstimatedCost
+			dayBudget.Source = BudgetSourceLocations
+		}
+
+		switch {
+		case dayBudget.Source == BudgetSourceLocations:
+			dayBudget.Total = dayBudget.LocationsCost
+		case day.EstimatedCost != nil:
+			dayBudget.Total = *day.EstimatedCost
+			dayBudget.Source = BudgetSourceDeclared
+			budget.Unallocated += *day.EstimatedCost
+		}
+
+		if dayBudget.Source != BudgetSourceNone {
+			daysWithCost = true
+			budget.Total += dayBudget.Total
+		}
+		budget.Days = append(budget.Days, dayBudget)
+	}
+
+	if daysWithCost {
+		budget.Source = BudgetSourceDays
+	} else if itinerary.EstimatedCost != nil {
+		budget.Total = *itinerary.EstimatedCost
+		budget.Unallocated = *itinerary.EstimatedCost
+		budget.Source = BudgetSourceDeclared
+	}
+
+	if itinerary.Duration > 0 {
+		budget.PerDay = budget.Total / float64(itinerary.Duration)
+	}
+
+	for _, category := range budgetCategories {
+		total := categories[category]
+		if budget.Total > 0 {
+			total.Percentage = roundCents(total.Amount / budget.Total * 100)
+		}
+		budget.Categories = append(budget.Categories, *total)
+	}
+
+	roundBudget(budget)
+	return budget
+}
+
+func budgetCategoryFor(locationType models.LocationType) BudgetCategory {
+	switch locationType {
+	case models.LocationTypeHotel:
+		return BudgetCategoryLodging
+	case models.LocationTypeRestaurant:
+		return BudgetCategoryFood
+	case models.LocationTypeTransport:
+		return BudgetCategoryTransport
+	case models.LocationTypeAttraction:
+		return BudgetCategoryActivities
+	case models.LocationTypeShopping:
+		return BudgetCategoryShopping
+	default:
+		return BudgetCategoryOther
+	}
+}
+
+// convertBudget aplica a cotação a todos os valores; os percentuais não mudam
+func convertBudget(budget *ItineraryBudget, rate float64, currency string) {
+	budget.Currency = currency
+	budget.Total *= rate
+	budget.PerDay *= rate
+	budget.Unallocated *= rate
+	if budget.DeclaredCost != nil {
+		declared := *budget.DeclaredCost * rate
+		budget.DeclaredCost = &declared
+	}
+	for i := range budget.Categories {
+		budget.Categories[i].Amount *= rate
+	}
+	for i := range budget.Days {
+		day := &budget.Days[i]
+		day.Total *= rate
+		day.LocationsCost *= rate
+		if day.DeclaredCost != nil {
+			declared := *day.DeclaredCost * rate
+			day.DeclaredCost = &declared
+		}
+	}
+	roundBudget(budget)
+}
+
+func roundBudget(budget *ItineraryBudget) {
+	budget.Total = roundCents(budget.Total)
+	budget.PerDay = roundCents(budget.PerDay)
+	budget.Unallocated = roundCents(budget.Unallocated)
+	if budget.DeclaredCost != nil {
+		declared := roundCents(*budget.DeclaredCost)
+		budget.DeclaredCost = &declared
+	}
+	for i := range budget.Categories {
+		budget.Categories[i].Amount = roundCents(budget.Categories[i].Amount)
+	}
+	for i := range budget.Days {
+		day := &budget.Days[i]
+		day.Total = roundCents(day.Total)
+		day.LocationsCost = roundCents(day.LocationsCost)
+		if day.DeclaredCost != nil {
+			declared := roundCents(*day.DeclaredCost)
+			day.DeclaredCost = &declared
+		}
+	}
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+)
+
+const (
+	// A CloudFront aceita até 3000 caminhos por invalidação em andamento
+	maxCloudFrontPaths = 3000
+	// O Cloudflare aceita até 30 URLs por requisição de purge
+	maxCloudflareURLs = 30
+	cdnRequestTimeout = 30 * time.Second
+	cloudflareAPIURL  = "https://api.cloudflare.com/client/v4"
+)
+
+type CDNConfig struct {
+	// "cloudfront", "cloudflare" ou vazio para desativar
+	Provider string
+	// Caminhos acumulados antes de enviar uma invalidação; além deles o lote
+	// é enviado sem esperar o intervalo do agendador
+	MaxBatchSize int
+
+	// CloudFront; sem chaves usa as credenciais padrão da AWS
+	DistributionID string
+	AWSRegion      string
+	AWSAccessKey   string
+	AWSSecretKey   string
+
+	// Cloudflare
+	CloudflareZoneID   string
+	CloudflareAPIToken string
+	CloudflareAPIURL   string // opcional (testes)
+}
+
+// CDNInvalidator remove arquivos do cache da CDN, para que mídias apagadas ou
+// substituídas deixem de ser servidas
+type CDNInvalidator interface {
+	Name() string
+	// Invalidate recebe as chaves do storage ("images/arquivo.jpg")
+	Invalidate(keys []string) error
+}
+
+// NewCDNInvalidator cria o cliente configurado; nil quando não há CDN na
+// frente do storage ou a invalidação está desativada
+func NewCDNInvalidator(config *MediaConfig) CDNInvalidator {
+	if config.CDN == nil || config.CDN.Provider == "" || config.CDN.Provider == "none" {
+		return nil
+	}
+
+	cdnURL := mediaCDNURL(config)
+	if cdnURL == "" {
+		log.Printf("Invalidação de CDN (%s) ignorada: nenhuma URL de CDN configurada para o storage %q", config.CDN.Provider, config.StorageType)
+		return nil
+	}
+
+	switch config.CDN.Provider {
+	case "cloudfront":
+		if config.CDN.DistributionID == "" {
+			log.Println("AWS_CLOUDFRONT_DISTRIBUTION_ID não configurado; invalidação de CDN desativada")
+			return nil
+		}
+		awsConfig := &aws.Config{Region: aws.String(config.CDN.AWSRegion)}
+		if config.CDN.AWSAccessKey != "" {
+			awsConfig.Credentials = credentials.NewStaticCredentials(config.CDN.AWSAccessKey, config.CDN.AWSSecretKey, "")
+		}
+		sess, err := session.NewSession(awsConfig)
+		if err != nil {
+			log.Printf("Falha ao configurar a CloudFront; invalidação de CDN desativada: %v", err)
+			return nil
+		}
+		return &cloudFrontInvalidator{
+			client:         cloudfront.New(sess),
+			distributionID: config.CDN.DistributionID,
+			pathPrefix:     cdnPathPrefix(cdnURL),
+		}
+	case "cloudflare":
+		if config.CDN.CloudflareZoneID == "" || config.CDN.CloudflareAPIToken == "" {
+			log.Println("CLOUDFLARE_ZONE_ID ou CLOUDFLARE_API_TOKEN não configurados; invalidação de CDN desativada")
+			return nil
+		}
+		apiURL := strings.TrimRight(config.CDN.CloudflareAPIURL, "/")
+		if apiURL == "" {
+			apiURL = cloudflareAPIURL
+		}
+		return &cloudflareInvalidator{
+			apiURL: apiURL,
+			zoneID: config.CDN.CloudflareZoneID,
+			token:  config.CDN.CloudflareAPIToken,
+			cdnURL: cdnURL,
+			client: &http.Client{Timeout: cdnRequestTimeout},
+		}
+	default:
+		log.Printf("Provedor de CDN desconhecido %q; invalidação desativada", config.CDN.Provider)
+		return nil
+	}
+}
+
+// mediaCDNURL é a URL da CDN do storage em uso
+func mediaCDNURL(config *MediaConfig) string {
+	switch config.StorageType {
+	case "s3":
+		if config.AWSConfig != nil {
+			return config.AWSConfig.CDNUrl
+		}
+	case "gcs":
+		if config.GCSConfig != nil {
+			return config.GCSConfig.CDNUrl
+		}
+	case "azure":
+		if config.AzureConfig != nil {
+			return config.AzureConfig.CDNUrl
+		}
+	}
+	return ""
+}
+
+// cdnPathPrefix é o caminho da URL da CDN ("https://cdn.x.com/media" →
+// "/media"), que antecede as chaves nos caminhos invalidados
+func cdnPathPrefix(cdnURL string) string {
+	parsed, err := url.Parse(cdnURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(parsed.Path, "/")
+}
+
+// ============================================================================
+// LOTES
+// ============================================================================
+
+// cdnBatcher acumula as chaves apagadas e as envia em lotes: cada caminho
+// invalidado tem custo na CloudFront, e vários arquivos de uma mídia (original,
+// variantes, segmentos HLS) saem juntos
+type cdnBatcher struct {
+	invalidator  CDNInvalidator
+	maxBatchSize int
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	// Garante um envio por vez, para o lote cheio e o agendador não
+	// dividirem as mesmas chaves
+	flushMu sync.Mutex
+}
+
+func newCDNBatcher(config *MediaConfig) *cdnBatcher {
+	invalidator := NewCDNInvalidator(config)
+	if invalidator == nil {
+		return nil
+	}
+	return &cdnBatcher{
+		invalidator:  invalidator,
+		maxBatchSize: config.CDN.MaxBatchSize,
+		pending:      make(map[string]struct{}),
+	}
+}
+
+func (b *cdnBatcher) add(key string) {
+	b.mu.Lock()
+	b.pending[key] = struct{}{}
+	full := len(b.pending) >= b.maxBatchSize
+	b.mu.Unlock()
+
+	if full {
+		go b.flush()
+	}
+}
+
+func (b *cdnBatcher) flush() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.pending))
+	for key := range b.pending {
+		keys = append(keys, key)
+	}
+	b.pending = make(map[string]struct{})
+	b.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	if err := b.invalidator.Invalidate(keys); err != nil {
+		// As chaves voltam para o próximo lote
+		log.Printf("Falha ao invalidar %d arquivo(s) na CDN (%s): %v", len(keys), b.invalidator.Name(), err)
+		b.mu.Lock()
+		for _, key := range keys {
+			b.pending[key] = struct{}{}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// StartCDNInvalidationScheduler envia periodicamente as invalidações
+// acumuladas; sem CDN configurada não faz nada
+func (s *MediaService) StartCDNInvalidationScheduler(interval time.Duration) {
+	if s.cdn == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.cdn.flush()
+		}
+	}()
+}
+
+// ============================================================================
+// AMAZON CLOUDFRONT
+// ============================================================================
+
+type cloudFrontInvalidator struct {
+	client         *cloudfront.CloudFront
+	distributionID string
+	pathPrefix     string
+}
+
+func (c *cloudFrontInvalidator) Name() string { return "cloudfront" }
+
+func (c *cloudFrontInvalidator) Invalidate(keys []string) error {
+	for start := 0; start < len(keys); start += maxCloudFrontPaths {
+		batch := keys[start:min(start+maxCloudFrontPaths, len(keys))]
+
+		paths := make([]*string, 0, len(batch))
+		for _, key := range batch {
+			paths = append(paths, aws.String(path.Join("/", c.pathPrefix, key)))
+		}
+
+		_, err := c.client.CreateInvalidation(&cloudfront.CreateInvalidationInput{
+			DistributionId: aws.String(c.distributionID),
+			InvalidationBatch: &cloudfront.InvalidationBatch{
+				// Identifica o lote, para a AWS não repetir um reenvio
+				CallerReference: aws.String(fmt.Sprintf("guia-%d-%d", time.Now().UnixNano(), start)),
+				Paths: &cloudfront.Paths{
+					Quantity: aws.Int64(int64(len(paths))),
+					Items:    paths,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// CLOUDFLARE
+// ============================================================================
+
+type cloudflareInvalidator struct {
+	apiURL string
+	zoneID string
+	token  string
+	cdnURL string
+	client *http.Client
+}
+
+func (c *cloudflareInvalidator) Name() string { return "cloudflare" }
+
+func (c *cloudflareInvalidator) Invalidate(keys []string) error {
+	for start := 0; start < len(keys); start += maxCloudflareURLs {
+		batch := keys[start:min(start+maxCloudflareURLs, len(keys))]
+
+		files := make([]string, 0, len(batch))
+		for _, key := range batch {
+			files = append(files, publicURL(c.cdnURL, key))
+		}
+		if err := c.purge(files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cloudflareInvalidator) purge(files []string) error {
+	body, err := json.Marshal(map[string][]string{"files": files})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/zones/%s/purge_cache", c.apiURL, url.PathEscape(c.zoneID)), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := json.Unmarshal(data, &result); err != nil || resp.StatusCode != http.StatusOK || !result.Success {
+		if len(result.Errors) > 0 {
+			return errors.New(result.Errors[0].Message)
+		}
+		return fmt.Errorf("cloudflare respondeu %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type ChallengeServiceInterface interface {
+	CreateChallenge(adminID uint, req *ChallengeRequest) (*models.ChallengeResponse, error)
+	UpdateChallenge(challengeID uint, req *ChallengeRequest) (*models.ChallengeResponse, error)
+	DeleteChallenge(challengeID uint) error
+	GetAllChallenges(limit, offset int) ([]models.ChallengeResponse, error)
+	GetActiveChallenges(userID uint, limit, offset int) ([]models.ChallengeResponse, error)
+	GetChallengeByID(challengeID, userID uint) (*models.ChallengeResponse, error)
+	Enroll(challengeID, userID uint) (*models.ChallengeResponse, error)
+	Unenroll(challengeID, userID uint) error
+	GetLeaderboard(challengeID uint, limit, offset int) ([]models.ChallengeLeaderboardEntry, error)
+	GetUserBadges(userID uint) ([]models.UserBadge, error)
+}
+
+type ChallengeRequest struct {
+	Title       string                   `json:"title" binding:"required"`
+	Description string                   `json:"description"`
+	Action      models.ChallengeAction   `json:"action" binding:"required"`
+	Category    models.ItineraryCategory `json:"category"`
+	Keyword     string                   `json:"keyword"`
+	TargetCount int                      `json:"target_count" binding:"required"`
+	StartsAt    time.Time                `json:"starts_at" binding:"required"`
+	EndsAt      time.Time                `json:"ends_at" binding:"required"`
+	BadgeName   string                   `json:"badge_name"`
+	BadgeImage  string                   `json:"badge_image"`
+	IsActive    *bool                    `json:"is_active"`
+}
+
+type ChallengeService struct {
+	challengeRepo repositories.ChallengeRepositoryInterface
+}
+
+// NewChallengeService cria o serviço e o inscreve nos eventos de domínio que
+// alimentam o progresso dos desafios
+func NewChallengeService(challengeRepo repositories.ChallengeRepositoryInterface, eventBus events.BusInterface) ChallengeServiceInterface {
+	service := &ChallengeService{
+		challengeRepo: challengeRepo,
+	}
+
+	eventBus.Subscribe(events.PostCreated, func(event events.Event) {
+		service.recordActivity(models.ChallengeActionPost, event)
+	})
+	eventBus.Subscribe(events.ItineraryCreated, func(event events.Event) {
+		service.recordActivity(models.ChallengeActionItinerary, event)
+	})
+	eventBus.Subscribe(events.ItineraryTraveled, func(event events.Event) {
+		service.recordActivity(models.ChallengeActionTravel, event)
+	})
+	eventBus.Subscribe(events.LocationCheckedIn, func(event events.Event) {
+		service.recordActivity(models.ChallengeActionCheckIn, event)
+	})
+
+	return service
+}
+
+func (s *ChallengeService) CreateChallenge(adminID uint, req *ChallengeRequest) (*models.ChallengeResponse, error) {
+	if err := s.validateChallengeRequest(req); err != nil {
+		return nil, err
+	}
+
+	challenge := &models.Challenge{CreatedByID: adminID, IsActive: true}
+	s.applyChallengeRequest(challenge, req)
+
+	if err := s.challengeRepo.Create(challenge); err != nil {
+		return nil, errors.New("erro ao criar desafio")
+	}
+
+	return challenge.ToResponse(nil), nil
+}
+
+func (s *ChallengeService) UpdateChallenge(challengeID uint, req *ChallengeRequest) (*models.ChallengeResponse, error) {
+	challenge, err := s.challengeRepo.GetByID(challengeID)
+	if err != nil {
+		return nil, errors.New("desafio não encontrado")
+	}
+
+	if err := s.validateChallengeRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.applyChallengeRequest(challenge, req)
+
+	if err := s.challengeRepo.Update(challenge); err != nil {
+		return nil, errors.New("erro ao atualizar desafio")
+	}
+
+	return challenge.ToResponse(nil), nil
+}
+
+func (s *ChallengeService) DeleteChallenge(challengeID uint) error {
+	if _, err := s.challengeRepo.GetByID(challengeID); err != nil {
+		return errors.New("desafio não encontrado")
+	}
+
+	if err := s.challengeRepo.Delete(challengeID); err != nil {
+		return errors.New("erro ao deletar desafio")
+	}
+
+	return nil
+}
+
+func (s *ChallengeService) GetAllChallenges(limit, offset int) ([]models.ChallengeResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	challenges, err := s.challengeRepo.GetAll(limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar desafios")
+	}
+
+	var responses []models.ChallengeResponse
+	for _, challenge := range challenges {
+		responses = append(responses, *challenge.ToResponse(nil))
+	}
+
+	return responses, nil
+}
+
+func (s *ChallengeService) GetActiveChallenges(userID uint, limit, offset int) ([]models.ChallengeResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	challenges, err := s.challengeRepo.GetRunning(time.Now(), limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar desafios")
+	}
+
+	challengeIDs := make([]uint, 0, len(challenges))
+	for _, challenge := range challenges {
+		challengeIDs = append(challengeIDs, challenge.ID)
+	}
+
+	enrollments, err := s.challengeRepo.GetEnrollmentsByUser(userID, challengeIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar inscrições")
+	}
+
+	enrollmentByChallenge := make(map[uint]*models.ChallengeEnrollment, len(enrollments))
+	for i := range enrollments {
+		enrollmentByChallenge[enrollments[i].ChallengeID] = &enrollments[i]
+	}
+
+	var responses []models.ChallengeResponse
+	for _, challenge := range challenges {
+		responses = append(responses, *challenge.ToResponse(enrollmentByChallenge[challenge.ID]))
+	}
+
+	return responses, nil
+}
+
+func (s *ChallengeService) GetChallengeByID(challengeID, userID uint) (*models.ChallengeResponse, error) {
+	challenge, err := s.challengeRepo.GetByID(challengeID)
+	if err != nil {
+		return nil, errors.New("desafio não encontrado")
+	}
+
+	enrollment, _ := s.challengeRepo.GetEnrollment(challengeID, userID)
+
+	return challenge.ToResponse(enrollment), nil
+}
+
+func (s *ChallengeService) Enroll(challengeID, userID uint) (*models.ChallengeResponse, error) {
+	challenge, err := s.challengeRepo.GetByID(challengeID)
+	if err != nil {
+		return nil, errors.New("desafio não encontrado")
+	}
+
+	if !challenge.IsRunning(time.Now()) {
+		return nil, errors.New("desafio não está em andamento")
+	}
+
+	if _, err := s.challengeRepo.GetEnrollment(challengeID, userID); err == nil {
+		return nil, errors.New("você já está inscrito neste desafio")
+	}
+
+	enrollment := &models.ChallengeEnrollment{
+		ChallengeID: challengeID,
+		UserID:      userID,
+	}
+
+	if err := s.challengeRepo.Enroll(enrollment); err != nil {
+		return nil, errors.New("erro ao se inscrever no desafio")
+	}
+
+	challenge.ParticipantsCount++
+	return challenge.ToResponse(enrollment), nil
+}
+
+func (s *ChallengeService) Unenroll(challengeID, userID uint) error {
+	enrollment, err := s.challengeRepo.GetEnrollment(challengeID, userID)
+	if err != nil {
+		return errors.New("inscrição não encontrada")
+	}
+
+	if enrollment.CompletedAt != nil {
+		return errors.New("não é possível sair de um desafio concluído")
+	}
+
+	if err := s.challengeRepo.Unenroll(challengeID, userID); err != nil {
+		return errors.New("erro ao sair do desafio")
+	}
+
+	return nil
+}
+
+func (s *ChallengeService) GetLeaderboard(challengeID uint, limit, offset int) ([]models.ChallengeLeaderboardEntry, error) {
+	if _, err := s.challengeRepo.GetByID(challengeID); err != nil {
+		return nil, errors.New("desafio não encontrado")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	enrollments, err := s.challengeRepo.GetLeaderboard(challengeID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar ranking")
+	}
+
+	var entries []models.ChallengeLeaderboardEntry
+	for i, enrollment := range enrollments {
+		entries = append(entries, models.ChallengeLeaderboardEntry{
+			Position:    offset + i + 1,
+			User:        enrollment.User.ToResponse(),
+			Progress:    enrollment.Progress,
+			CompletedAt: enrollment.CompletedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+func (s *ChallengeService) GetUserBadges(userID uint) ([]models.UserBadge, error) {
+	badges, err := s.challengeRepo.GetBadgesByUser(userID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar insígnias")
+	}
+
+	return badges, nil
+}
+
+// recordActivity contabiliza a atividade nos desafios em andamento em que o
+// autor está inscrito e concede a insígnia ao atingir a meta
+func (s *ChallengeService) recordActivity(action models.ChallengeAction, event events.Event) {
+	now := event.OccurredAt
+	challenges, err := s.challengeRepo.GetRunningByAction(action, now)
+	if err != nil {
+		log.Printf("Erro ao buscar desafios para o evento %s: %v", event.Type, err)
+		return
+	}
+
+	for _, challenge := range challenges {
+		if !s.matchesCriteria(&challenge, event) {
+			continue
+		}
+
+		enrollment, err := s.challengeRepo.GetEnrollment(challenge.ID, event.ActorID)
+		if err != nil || enrollment.CompletedAt != nil {
+			continue
+		}
+
+		added, err := s.challengeRepo.AddContribution(&models.ChallengeContribution{
+			ChallengeID: challenge.ID,
+			UserID:      event.ActorID,
+			Action:      action,
+			EntityID:    event.EntityID,
+		})
+		if err != nil {
+			log.Printf("Erro ao registrar progresso no desafio %d: %v", challenge.ID, err)
+			continue
+		}
+		if !added || enrollment.Progress+1 < challenge.TargetCount {
+			continue
+		}
+
+		enrollment.CompletedAt = &now
+		badge := &models.UserBadge{
+			UserID:      event.ActorID,
+			ChallengeID: challenge.ID,
+			Name:        s.badgeName(&challenge),
+			Image:       challenge.BadgeImage,
+			AwardedAt:   now,
+		}
+		if err := s.challengeRepo.CompleteEnrollment(enrollment, badge); err != nil {
+			log.Printf("Erro ao concluir desafio %d: %v", challenge.ID, err)
+		}
+	}
+}
+
+func (s *ChallengeService) matchesCriteria(challenge *models.Challenge, event events.Event) bool {
+	if challenge.Category != "" && event.Data["category"] != string(challenge.Category) {
+		return false
+	}
+
+	if challenge.Keyword != "" {
+		keyword := normalizeGeoName(challenge.Keyword)
+		if !strings.Contains(normalizeGeoName(event.Data["text"]), keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *ChallengeService) badgeName(challenge *models.Challenge) string {
+	if challenge.BadgeName != "" {
+		return challenge.BadgeName
+	}
+	return challenge.Title
+}
+
+func (s *ChallengeService) applyChallengeRequest(challenge *models.Challenge, req *ChallengeRequest) {
+	challenge.Title = strings.TrimSpace(req.Title)
+	challenge.Description = strings.TrimSpace(req.Description)
+	challenge.Action = req.Action
+	challenge.Category = req.Category
+	challenge.Keyword = strings.TrimSpace(req.Keyword)
+	challenge.TargetCount = req.TargetCount
+	challenge.StartsAt = req.StartsAt
+	challenge.EndsAt = req.EndsAt
+	challenge.BadgeName = strings.TrimSpace(req.BadgeName)
+	challenge.BadgeImage = req.BadgeImage
+	if req.IsActive != nil {
+		challenge.IsActive = *req.IsActive
+	}
+}
+
+// Funções de validação
+func (s *ChallengeService) validateChallengeRequest(req *ChallengeRequest) error {
+	if len(strings.TrimSpace(req.Title)) < 3 {
+		return errors.New("título deve ter pelo menos 3 caracteres")
+	}
+
+	switch req.Action {
+	case models.ChallengeActionPost, models.ChallengeActionItinerary,
+		models.ChallengeActionTravel, models.ChallengeActionCheckIn:
+	default:
+		return errors.New("ação do desafio inválida")
+	}
+
+	if req.Category != "" {
+		if req.Action == models.ChallengeActionPost {
+			return errors.New("categoria só se aplica a desafios de roteiro, viagem ou check-in")
+		}
+		if err := validateItineraryCategory(req.Category); err != nil {
+			return err
+		}
+	}
+
+	if req.TargetCount <= 0 {
+		return errors.New("meta do desafio deve ser maior que zero")
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return errors.New("data de término deve ser posterior à data de início")
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	// Período padrão das agregações quando o admin não informa o início
+	defaultClientErrorWindow = 7 * 24 * time.Hour
+	// Linhas da stack trace consideradas no agrupamento
+	fingerprintStackLines = 3
+)
+
+var (
+	fingerprintHexPattern   = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	fingerprintDigitPattern = regexp.MustCompile(`\d+`)
+)
+
+type ClientErrorConfig struct {
+	SamplePercent      int // porcentagem de erros e avisos gravados; crashes são sempre gravados
+	RateLimitPerMinute int // relatos por usuário ou IP por minuto
+	RetentionDays      int
+}
+
+type ClientErrorServiceInterface interface {
+	Report(userID *uint, req *ClientErrorReportRequest) (bool, error)
+	GetGroups(platform, appVersion string, since time.Time, limit, offset int) ([]models.ClientErrorGroup, error)
+	GetEndpointErrors(platform, appVersion string, since time.Time, limit, offset int) ([]models.ClientEndpointErrors, error)
+	GetReports(fingerprint string, limit, offset int) ([]models.ClientErrorReport, error)
+	StartClientErrorCleanupScheduler(interval time.Duration)
+}
+
+// ClientErrorReportRequest é o relato enviado pelos apps; o esquema é validado
+// no bind e campos fora dele são ignorados
+type ClientErrorReportRequest struct {
+	Platform    models.ClientPlatform   `json:"platform" binding:"required,oneof=ios android web"`
+	AppVersion  string                  `json:"app_version" binding:"required,max=50"`
+	OSVersion   string                  `json:"os_version" binding:"max=50"`
+	DeviceModel string                  `json:"device_model" binding:"max=100"`
+	Locale      string                  `json:"locale" binding:"max=10"`
+	Level       models.ClientErrorLevel `json:"level" binding:"required,oneof=crash error warning"`
+	ErrorType   string                  `json:"error_type" binding:"max=200"`
+	Message     string                  `json:"message" binding:"required,max=1000"`
+	StackTrace  string                  `json:"stack_trace" binding:"max=16384"`
+	APIEndpoint string                  `json:"api_endpoint" binding:"max=255"`
+	APIStatus   int                     `json:"api_status" binding:"omitempty,min=100,max=599"`
+	TraceID     string                  `json:"trace_id" binding:"max=64"`
+	SampleRate  float64                 `json:"sample_rate" binding:"omitempty,gt=0,lte=1"` // amostragem já aplicada pelo app
+	OccurredAt  *time.Time              `json:"occurred_at"`
+}
+
+type ClientErrorService struct {
+	clientErrorRepo repositories.ClientErrorRepositoryInterface
+	config          *ClientErrorConfig
+}
+
+func NewClientErrorService(clientErrorRepo repositories.ClientErrorRepositoryInterface, config *ClientErrorConfig) ClientErrorServiceInterface {
+	if config.SamplePercent <= 0 || config.SamplePercent > 100 {
+		config.SamplePercent = 100
+	}
+	if config.RetentionDays <= 0 {
+		config.RetentionDays = 30
+	}
+
+	return &ClientErrorService{
+		clientErrorRepo: clientErrorRepo,
+		config:          config,
+	}
+}
+
+// Report grava o relato respeitando a amostragem do servidor; retorna false
+// quando o relato foi descartado pela amostragem
+func (s *ClientErrorService) Report(userID *uint, req *ClientErrorReportRequest) (bool, error) {
+	if err := s.validateReportRequest(req); err != nil {
+		return false, err
+	}
+
+	weight := 1.0
+	if req.SampleRate > 0 {
+		weight = 1 / req.SampleRate
+	}
+
+	if req.Level != models.ClientErrorCrash && s.config.SamplePercent < 100 {
+		if rand.Intn(100) >= s.config.SamplePercent {
+			return false, nil
+		}
+		weight *= 100 / float64(s.config.SamplePercent)
+	}
+
+	now := time.Now()
+	occurredAt := now
+	// Horários do dispositivo podem estar errados; só aceitar os plausíveis
+	if req.OccurredAt != nil && req.OccurredAt.Before(now.Add(5*time.Minute)) &&
+		req.OccurredAt.After(now.AddDate(0, 0, -s.config.RetentionDays)) {
+		occurredAt = *req.OccurredAt
+	}
+
+	// A query string pode conter tokens ou dados pessoais
+	endpoint, _, _ := strings.Cut(strings.TrimSpace(req.APIEndpoint), "?")
+
+	report := &models.ClientErrorReport{
+		UserID:      userID,
+		Platform:    req.Platform,
+		AppVersion:  strings.TrimSpace(req.AppVersion),
+		OSVersion:   strings.TrimSpace(req.OSVersion),
+		DeviceModel: strings.TrimSpace(req.DeviceModel),
+		Locale:      strings.TrimSpace(req.Locale),
+		Level:       req.Level,
+		ErrorType:   strings.TrimSpace(req.ErrorType),
+		Message:     strings.TrimSpace(req.Message),
+		StackTrace:  req.StackTrace,
+		Fingerprint: clientErrorFingerprint(req),
+		APIEndpoint: endpoint,
+		APIStatus:   req.APIStatus,
+		TraceID:     strings.TrimSpace(req.TraceID),
+		Weight:      weight,
+		OccurredAt:  occurredAt,
+	}
+
+	if err := s.clientErrorRepo.Create(report); err != nil {
+		return false, errors.New("erro ao registrar relato de erro")
+	}
+
+	return true, nil
+}
+
+func (s *ClientErrorService) GetGroups(platform, appVersion string, since time.Time, limit, offset int) ([]models.ClientErrorGroup, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	groups, err := s.clientErrorRepo.GetGroups(clientErrorFilter(platform, appVersion, since), limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar erros dos apps")
+	}
+
+	return groups, nil
+}
+
+func (s *ClientErrorService) GetEndpointErrors(platform, appVersion string, since time.Time, limit, offset int) ([]models.ClientEndpointErrors, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	endpoints, err := s.clientErrorRepo.GetEndpointErrors(clientErrorFilter(platform, appVersion, since), limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar erros dos apps")
+	}
+
+	return endpoints, nil
+}
+
+func (s *ClientErrorService) GetReports(fingerprint string, limit, offset int) ([]models.ClientErrorReport, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	reports, err := s.clientErrorRepo.GetByFingerprint(fingerprint, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar relatos de erro")
+	}
+
+	return reports, nil
+}
+
+// StartClientErrorCleanupScheduler apaga periodicamente os relatos mais
+// antigos que o período de retenção
+func (s *ClientErrorService) StartClientErrorCleanupScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deleted, err := s.clientErrorRepo.DeleteOlderThan(time.Now().AddDate(0, 0, -s.config.RetentionDays))
+			if err != nil {
+				log.Println("Falha ao limpar relatos de erro dos apps:", err)
+			} else if deleted > 0 {
+				log.Printf("%d relatos de erro dos apps expirados removidos", deleted)
+			}
+		}
+	}()
+}
+
+func clientErrorFilter(platform, appVersion string, since time.Time) repositories.ClientErrorFilter {
+	if since.IsZero() {
+		since = time.Now().Add(-defaultClientErrorWindow)
+	}
+	return repositories.ClientErrorFilter{
+		Platform:   models.ClientPlatform(platform),
+		AppVersion: appVersion,
+		Since:      since,
+	}
+}
+
+// clientErrorFingerprint agrupa relatos do mesmo problema: usa o tipo do erro
+// e o topo da stack trace (ou a mensagem), sem números e endereços de memória,
+// que variam entre ocorrências
+func clientErrorFingerprint(req *ClientErrorReportRequest) string {
+	signature := req.Message
+	if req.StackTrace != "" {
+		var lines []string
+		for _, line := range strings.Split(req.StackTrace, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+				if len(lines) == fingerprintStackLines {
+					break
+				}
+			}
+		}
+		signature = strings.Join(lines, "\n")
+	}
+
+	signature = fingerprintHexPattern.ReplaceAllString(strings.ToLower(signature), "#")
+	signature = fingerprintDigitPattern.ReplaceAllString(signature, "#")
+
+	hash := sha256.Sum256([]byte(string(req.Platform) + "|" + req.ErrorType + "|" + signature))
+	return hex.EncodeToString(hash[:])
+}
+
+// Funções de validação
+func (s *ClientErrorService) validateReportRequest(req *ClientErrorReportRequest) error {
+	if strings.TrimSpace(req.Message) == "" {
+		return errors.New("mensagem do erro é obrigatória")
+	}
+	if strings.TrimSpace(req.AppVersion) == "" {
+		return errors.New("versão do app é obrigatória")
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type CollectionServiceInterface interface {
+	CreateCollection(userID uint, req *CreateCollectionRequest) (*models.CollectionResponse, error)
+	GetCollections(userID uint, limit, offset int) ([]models.CollectionResponse, error)
+	GetCollection(collectionID, userID uint, limit, offset int) (*models.CollectionResponse, error)
+	UpdateCollection(collectionID, userID uint, req *UpdateCollectionRequest) (*models.CollectionResponse, error)
+	DeleteCollection(collectionID, userID uint) error
+	AddItinerary(collectionID, userID uint, req *AddCollectionItemRequest) (*models.CollectionResponse, error)
+	RemoveItinerary(collectionID, itineraryID, userID uint) error
+}
+
+type CreateCollectionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type AddCollectionItemRequest struct {
+	ItineraryID uint `json:"itinerary_id" binding:"required"`
+}
+
+const maxCollectionsPerUser = 100
+
+type CollectionService struct {
+	collectionRepo repositories.CollectionRepositoryInterface
+	itineraryRepo  repositories.ItineraryRepositoryInterface
+}
+
+func NewCollectionService(collectionRepo repositories.CollectionRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface) CollectionServiceInterface {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		itineraryRepo:  itineraryRepo,
+	}
+}
+
+func (s *CollectionService) CreateCollection(userID uint, req *CreateCollectionRequest) (*models.CollectionResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	description := strings.TrimSpace(req.Description)
+	if err := s.validateCollection(name, description); err != nil {
+		return nil, err
+	}
+
+	count, err := s.collectionRepo.CountByUser(userID)
+	if err != nil {
+		return nil, errors.New("erro ao criar coleção")
+	}
+	if count >= maxCollectionsPerUser {
+		return nil, fmt.Errorf("limite de %d coleções atingido", maxCollectionsPerUser)
+	}
+
+	collection := &models.Collection{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+	}
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, errors.New("erro ao criar coleção")
+	}
+
+	return collection.ToResponse(), nil
+}
+
+func (s *CollectionService) GetCollections(userID uint, limit, offset int) ([]models.CollectionResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	collections, err := s.collectionRepo.GetByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar coleções")
+	}
+
+	responses := make([]models.CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		responses = append(responses, *collection.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetCollection retorna a coleção com uma página dos roteiros salvos nela
+func (s *CollectionService) GetCollection(collectionID, userID uint, limit, offset int) (*models.CollectionResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	collection, err := s.getOwnCollection(collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	itineraries, err := s.collectionRepo.GetItineraries(collectionID, userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiros da coleção")
+	}
+
+	ids := make([]uint, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		ids = append(ids, itinerary.ID)
+	}
+	liked, err := s.itineraryRepo.GetLikedItineraryIDs(userID, ids)
+	if err != nil {
+		liked = map[uint]bool{}
+	}
+
+	response := collection.ToResponse()
+	response.Itineraries = make([]models.ItineraryResponse, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		item := itinerary.ToResponse()
+		item.IsLiked = liked[itinerary.ID]
+		item.IsSaved = true
+		response.Itineraries = append(response.Itineraries, *item)
+	}
+
+	return response, nil
+}
+
+func (s *CollectionService) UpdateCollection(collectionID, userID uint, req *UpdateCollectionRequest) (*models.CollectionResponse, error) {
+	collection, err := s.getOwnCollection(collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		collection.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		collection.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := s.validateCollection(collection.Name, collection.Description); err != nil {
+		return nil, err
+	}
+
+	if err := s.collectionRepo.Update(collection); err != nil {
+		return nil, errors.New("erro ao atualizar coleção")
+	}
+
+	return collection.ToResponse(), nil
+}
+
+func (s *CollectionService) DeleteCollection(collectionID, userID uint) error {
+	if _, err := s.getOwnCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	if err := s.collectionRepo.Delete(collectionID); err != nil {
+		return errors.New("erro ao excluir coleção")
+	}
+
+	return nil
+}
+
+// AddItinerary salva um roteiro visível para o usuário na coleção
+func (s *CollectionService) AddItinerary(collectionID, userID uint, req *AddCollectionItemRequest) (*models.CollectionResponse, error) {
+	collection, err := s.getOwnCollection(collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	itinerary, err := s.itineraryRepo.GetByID(req.ItineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	added, err := s.collectionRepo.AddItem(collectionID, req.ItineraryID)
+	if err != nil {
+		return nil, errors.New("erro ao salvar roteiro na coleção")
+	}
+	if !added {
+		return nil, errors.New("roteiro já está nesta coleção")
+	}
+
+	collection.ItemsCount++
+	return collection.ToResponse(), nil
+}
+
+func (s *CollectionService) RemoveItinerary(collectionID, itineraryID, userID uint) error {
+	if _, err := s.getOwnCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	removed, err := s.collectionRepo.RemoveItem(collectionID, itineraryID)
+	if err != nil {
+		return errors.New("erro ao remover roteiro da coleção")
+	}
+	if !removed {
+		return errors.New("roteiro não encontrado na coleção")
+	}
+
+	return nil
+}
+
+// getOwnCollection busca a coleção do usuário; coleções de outros usuários
+// são tratadas como inexistentes, pois são privadas
+func (s *CollectionService) getOwnCollection(collectionID, userID uint) (*models.Collection, error) {
+	collection, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil || collection.UserID != userID {
+		return nil, errors.New("coleção não encontrada")
+	}
+	return collection, nil
+}
+
+// Funções de validação
+func (s *CollectionService) validateCollection(name, description string) error {
+	if name == "" {
+		return errors.New("nome da coleção é obrigatório")
+	}
+	if len(name) > 100 {
+		return errors.New("nome deve ter no máximo 100 caracteres")
+	}
+	if len(description) > 500 {
+		return errors.New("descrição deve ter no máximo 500 caracteres")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type CommentServiceInterface interface {
+	CreateComment(postID, userID uint, req *CreateCommentRequest) (*models.CommentResponse, error)
+	GetComments(postID, userID uint, limit, offset int) ([]models.CommentResponse, error)
+	DeleteComment(commentID, userID uint) error
+}
+
+type CommentService struct {
+	commentRepo repositories.CommentRepositoryInterface
+	postRepo    repositories.PostRepositoryInterface
+	userRepo    repositories.UserRepositoryInterface
+	profanity   ProfanityServiceInterface
+}
+
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required"`
+	ParentID *uint  `json:"parent_id,omitempty"` // responde a outro comentário do mesmo post
+}
+
+// mentionPattern encontra citações no formato @username
+var mentionPattern = regexp.MustCompile(`@([a-zA-Z0-9_]{3,50})`)
+
+func NewCommentService(commentRepo repositories.CommentRepositoryInterface, postRepo repositories.PostRepositoryInterface, userRepo repositories.UserRepositoryInterface, profanity ProfanityServiceInterface) CommentServiceInterface {
+	return &CommentService{
+		commentRepo: commentRepo,
+		postRepo:    postRepo,
+		userRepo:    userRepo,
+		profanity:   profanity,
+	}
+}
+
+func (s *CommentService) CreateComment(postID, userID uint, req *CreateCommentRequest) (*models.CommentResponse, error) {
+	content := strings.TrimSpace(req.Content)
+	if err := s.validateContent(content); err != nil {
+		return nil, err
+	}
+
+	post, err := s.postRepo.GetByID(postID, userID)
+	if err != nil || !post.IsActive {
+		return nil, errors.New("post não encontrado")
+	}
+
+	if err := s.checkCommentPolicy(post, userID); err != nil {
+		return nil, err
+	}
+
+	filtered, err := s.profanity.Filter(content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		PostID:   post.ID,
+		AuthorID: userID,
+		Content:  filtered.Text,
+	}
+
+	// Respostas ficam sempre no primeiro nível de aninhamento
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(*req.ParentID)
+		if err != nil || parent.PostID != post.ID {
+			return nil, errors.New("comentário respondido não encontrado")
+		}
+		parentID := parent.ID
+		if parent.ParentID != nil {
+			parentID = *parent.ParentID
+		}
+		comment.ParentID = &parentID
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, errors.New("erro ao criar comentário")
+	}
+	s.profanity.FlagContent(models.ReportTargetComment, comment.ID, userID, filtered)
+
+	created, err := s.commentRepo.GetByID(comment.ID)
+	if err != nil {
+		return comment.ToResponse(), nil
+	}
+	return created.ToResponse(), nil
+}
+
+func (s *CommentService) GetComments(postID, userID uint, limit, offset int) ([]models.CommentResponse, error) {
+	if _, err := s.postRepo.GetByID(postID, userID); err != nil {
+		return nil, errors.New("post não encontrado")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	comments, err := s.commentRepo.GetByPost(postID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar comentários")
+	}
+
+	responses := make([]models.CommentResponse, len(comments))
+	for i := range comments {
+		responses[i] = *comments[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// DeleteComment permite remover o comentário ao seu autor e ao autor do post
+func (s *CommentService) DeleteComment(commentID, userID uint) error {
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		return errors.New("comentário não encontrado")
+	}
+
+	if comment.AuthorID != userID {
+		post, err := s.postRepo.GetByID(comment.PostID, userID)
+		if err != nil || post.AuthorID != userID {
+			return errors.New("você não tem permissão para deletar este comentário")
+		}
+	}
+
+	if err := s.commentRepo.Delete(comment); err != nil {
+		return errors.New("erro ao deletar comentário")
+	}
+
+	return nil
+}
+
+// checkCommentPolicy aplica a política de comentários definida pelo autor do post
+func (s *CommentService) checkCommentPolicy(post *models.Post, userID uint) error {
+	if post.AuthorID == userID {
+		return nil
+	}
+
+	switch post.CommentPolicy {
+	case models.CommentPolicyOff:
+		return errors.New("você não tem permissão para comentar: comentários desativados neste post")
+	case models.CommentPolicyFollowers:
+		following, err := s.userRepo.IsFollowing(userID, post.AuthorID)
+		if err != nil {
+			return errors.New("erro ao verificar seguidores")
+		}
+		if !following {
+			return errors.New("você não tem permissão para comentar: apenas seguidores do autor podem comentar")
+		}
+	case models.CommentPolicyMentioned:
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return errors.New("usuário não encontrado")
+		}
+		if !isMentioned(post.Content, user.Username) {
+			return errors.New("você não tem permissão para comentar: apenas usuários citados podem comentar")
+		}
+	}
+
+	return nil
+}
+
+func isMentioned(content, username string) bool {
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		if strings.EqualFold(match[1], username) {
+			return true
+		}
+	}
+	return false
+}
+
+// Funções de validação
+
+func (s *CommentService) validateContent(content string) error {
+	if content == "" {
+		return errors.New("conteúdo do comentário é obrigatório")
+	}
+	if len(content) > 1000 {
+		return errors.New("comentário deve ter no máximo 1000 caracteres")
+	}
+	return nil
+}
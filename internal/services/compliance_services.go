@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type ComplianceServiceInterface interface {
+	RestrictContent(adminID uint, req *RestrictContentRequest) ([]models.ContentRestriction, error)
+	RemoveRestriction(restrictionID, adminID uint, req *ModerationActionRequest) error
+	GetRestrictions(contentType models.RestrictedContentType, contentID uint, limit, offset int) ([]models.ContentRestriction, error)
+	FilterRestricted(contentType models.RestrictedContentType, contentIDs []uint, countryCode string) map[uint]bool
+	GetTransparencyReport(from, to time.Time) (*models.TransparencyReport, error)
+}
+
+type RestrictContentRequest struct {
+	ContentType models.RestrictedContentType `json:"content_type" binding:"required"` // post ou itinerary
+	ContentID   uint                         `json:"content_id" binding:"required"`
+	Countries   []string                     `json:"countries" binding:"required"` // códigos ISO 3166-1 alfa-2
+	Reason      string                       `json:"reason" binding:"required"`
+}
+
+type ComplianceService struct {
+	complianceRepo repositories.ComplianceRepositoryInterface
+	moderationRepo repositories.ModerationRepositoryInterface
+	itineraryRepo  repositories.ItineraryRepositoryInterface
+}
+
+func NewComplianceService(complianceRepo repositories.ComplianceRepositoryInterface, moderationRepo repositories.ModerationRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface) ComplianceServiceInterface {
+	return &ComplianceService{
+		complianceRepo: complianceRepo,
+		moderationRepo: moderationRepo,
+		itineraryRepo:  itineraryRepo,
+	}
+}
+
+// RestrictContent bloqueia o conteúdo nos países informados, registrando a
+// ação na auditoria da moderação
+func (s *ComplianceService) RestrictContent(adminID uint, req *RestrictContentRequest) ([]models.ContentRestriction, error) {
+	countries, err := s.validateRestrictContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType, err := s.checkContentExists(req.ContentType, req.ContentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	restrictions := make([]models.ContentRestriction, 0, len(countries))
+	audits := make([]models.ModerationAction, 0, len(countries))
+	for _, country := range countries {
+		restrictions = append(restrictions, models.ContentRestriction{
+			ContentType: req.ContentType,
+			ContentID:   req.ContentID,
+			CountryCode: country,
+			Reason:      reason,
+			CreatedByID: adminID,
+		})
+		audits = append(audits, models.ModerationAction{
+			AdminID:    adminID,
+			Action:     models.ModerationActionRestrict,
+			TargetType: targetType,
+			TargetID:   req.ContentID,
+			Note:       country + ": " + reason,
+		})
+	}
+
+	created, err := s.complianceRepo.CreateRestrictions(restrictions, audits)
+	if err != nil {
+		return nil, errors.New("erro ao restringir conteúdo")
+	}
+	if len(created) == 0 {
+		return nil, errors.New("conteúdo já restrito nesses países")
+	}
+
+	return created, nil
+}
+
+// RemoveRestriction volta a exibir o conteúdo no país da restrição
+func (s *ComplianceService) RemoveRestriction(restrictionID, adminID uint, req *ModerationActionRequest) error {
+	restriction, err := s.complianceRepo.GetRestrictionByID(restrictionID)
+	if err != nil {
+		return errors.New("restrição não encontrada")
+	}
+
+	targetType := models.ModerationTargetPost
+	if restriction.ContentType == models.RestrictedContentItinerary {
+		targetType = models.ModerationTargetItinerary
+	}
+
+	note := restriction.CountryCode
+	if trimmed := strings.TrimSpace(req.Note); trimmed != "" {
+		note += ": " + trimmed
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionUnrestrict,
+		TargetType: targetType,
+		TargetID:   restriction.ContentID,
+		Note:       note,
+	}
+	if err := s.complianceRepo.DeleteRestriction(restrictionID, audit); err != nil {
+		return errors.New("erro ao remover restrição")
+	}
+
+	return nil
+}
+
+func (s *ComplianceService) GetRestrictions(contentType models.RestrictedContentType, contentID uint, limit, offset int) ([]models.ContentRestriction, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	restrictions, err := s.complianceRepo.GetRestrictions(contentType, contentID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar restrições")
+	}
+
+	return restrictions, nil
+}
+
+// FilterRestricted indica quais conteúdos estão bloqueados no país da
+// requisição e registra os bloqueios para o relatório de transparência. Sem
+// país resolvido nada é bloqueado; uma falha na consulta também libera o
+// conteúdo, para não derrubar a leitura
+func (s *ComplianceService) FilterRestricted(contentType models.RestrictedContentType, contentIDs []uint, countryCode string) map[uint]bool {
+	if countryCode == "" || len(contentIDs) == 0 {
+		return map[uint]bool{}
+	}
+
+	restricted, err := s.complianceRepo.GetRestrictedIDs(contentType, contentIDs, countryCode)
+	if err != nil {
+		log.Printf("Falha ao verificar restrições por país (%s): %v", countryCode, err)
+		return map[uint]bool{}
+	}
+	if len(restricted) == 0 {
+		return restricted
+	}
+
+	ids := make([]uint, 0, len(restricted))
+	for id := range restricted {
+		ids = append(ids, id)
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if err := s.complianceRepo.RecordEnforcements(contentType, ids, countryCode, today); err != nil {
+		log.Printf("Falha ao registrar bloqueios por país (%s): %v", countryCode, err)
+	}
+
+	return restricted
+}
+
+// GetTransparencyReport resume os bloqueios por país no período; sem período
+// informado considera os últimos 30 dias
+func (s *ComplianceService) GetTransparencyReport(from, to time.Time) (*models.TransparencyReport, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	if from.After(to) {
+		return nil, errors.New("data inicial deve ser anterior à data final")
+	}
+
+	entries, err := s.complianceRepo.GetTransparencyReport(from, to)
+	if err != nil {
+		return nil, errors.New("erro ao gerar relatório de transparência")
+	}
+	if entries == nil {
+		entries = []models.TransparencyReportEntry{}
+	}
+
+	return &models.TransparencyReport{
+		From:    from,
+		To:      to,
+		Entries: entries,
+	}, nil
+}
+
+// checkContentExists confirma o conteúdo e devolve o tipo de alvo usado na
+// auditoria
+func (s *ComplianceService) checkContentExists(contentType models.RestrictedContentType, contentID uint) (models.ModerationTargetType, error) {
+	if contentType == models.RestrictedContentItinerary {
+		if _, err := s.itineraryRepo.GetByID(contentID); err != nil {
+			return "", errors.New("roteiro não encontrado")
+		}
+		return models.ModerationTargetItinerary, nil
+	}
+
+	post, err := s.moderationRepo.GetPostForModeration(contentID)
+	if err != nil || post.DeletedAt.Valid {
+		return "", errors.New("post não encontrado")
+	}
+	return models.ModerationTargetPost, nil
+}
+
+// Funções de validação
+func (s *ComplianceService) validateRestrictContentRequest(req *RestrictContentRequest) ([]string, error) {
+	if req.ContentType != models.RestrictedContentPost && req.ContentType != models.RestrictedContentItinerary {
+		return nil, errors.New("tipo de conteúdo deve ser 'post' ou 'itinerary'")
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("motivo da restrição é obrigatório")
+	}
+	if len(req.Reason) > 500 {
+		return nil, errors.New("motivo deve ter no máximo 500 caracteres")
+	}
+
+	seen := make(map[string]bool)
+	var countries []string
+	for _, country := range req.Countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if !isCountryCode(code) {
+			return nil, errors.New("código de país inválido: " + country)
+		}
+		if !seen[code] {
+			seen[code] = true
+			countries = append(countries, code)
+		}
+	}
+	if len(countries) == 0 {
+		return nil, errors.New("informe ao menos um país")
+	}
+
+	return countries, nil
+}
+
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLength é quanto do início do arquivo é lido para identificar o formato
+const sniffLength = 512
+
+var (
+	asfHeader  = []byte{0x30, 0x26, 0xB2, 0x75, 0x8E, 0x66, 0xCF, 0x11, 0xA6, 0xD9, 0x00, 0xAA, 0x00, 0x62, 0xCE, 0x6C}
+	ebmlHeader = []byte{0x1A, 0x45, 0xDF, 0xA3}
+
+	// Trechos que não aparecem em imagens legítimas, mas sim em arquivos que
+	// também são HTML ou PHP (poliglotas)
+	embeddedScriptMarkers = [][]byte{[]byte("<?php"), []byte("<script"), []byte("<html")}
+)
+
+// sniffContentType identifica o formato pelos primeiros bytes do arquivo, sem
+// confiar na extensão nem no Content-Type enviados. Devolve "" para formatos
+// não suportados
+func sniffContentType(header []byte) string {
+	switch {
+	case len(header) >= 3 && header[0] == 0xFF && header[1] == 0xD8 && header[2] == 0xFF:
+		return "image/jpeg"
+	case bytes.HasPrefix(header, pngSignature):
+		return "image/png"
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return "image/gif"
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return "image/webp"
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "AVI ":
+		return "video/x-msvideo"
+	case len(header) >= 12 && string(header[4:8]) == "ftyp":
+		if string(header[8:12]) == "qt  " {
+			return "video/quicktime"
+		}
+		return "video/mp4"
+	case bytes.HasPrefix(header, asfHeader):
+		return "video/x-ms-wmv"
+	case bytes.HasPrefix(header, ebmlHeader) && bytes.Contains(header, []byte("webm")):
+		return "video/webm"
+	}
+	return ""
+}
+
+// checkContent confere se o conteúdo real do arquivo é do tipo de mídia e do
+// formato indicado pela extensão, e devolve o Content-Type detectado
+func (s *MediaService) checkContent(header []byte, fileName string, mediaType MediaType) (string, error) {
+	detected := sniffContentType(header)
+
+	if detected == "" || !strings.HasPrefix(detected, string(mediaType)+"/") {
+		if mediaType == MediaTypeVideo {
+			return "", errors.New("conteúdo do arquivo não é um vídeo suportado")
+		}
+		return "", errors.New("conteúdo do arquivo não é uma imagem suportada")
+	}
+
+	// MP4 e MOV usam o mesmo contêiner e costumam vir trocados
+	expected := s.getContentTypeFromExtension(fileName)
+	isoMedia := func(contentType string) bool {
+		return contentType == "video/mp4" || contentType == "video/quicktime"
+	}
+	if detected != expected && !(isoMedia(detected) && isoMedia(expected)) {
+		return "", fmt.Errorf("conteúdo do arquivo (%s) não corresponde à extensão %s",
+			detected, strings.ToLower(filepath.Ext(fileName)))
+	}
+
+	return detected, nil
+}
+
+// checkImageContent rejeita imagens que escondem scripts e imagens cujas
+// dimensões ocupariam memória demais ao decodificar (bombas de descompressão)
+func (s *MediaService) checkImageContent(data []byte) error {
+	lower := bytes.ToLower(data)
+	for _, marker := range embeddedScriptMarkers {
+		if bytes.Contains(lower, marker) {
+			return fmt.Errorf("conteúdo do arquivo suspeito: contém %q", marker)
+		}
+	}
+
+	width, height := imageConfig(data)
+	if width <= 0 || height <= 0 {
+		return errors.New("conteúdo do arquivo não é uma imagem válida")
+	}
+	if int64(width)*int64(height) > s.config.MaxImagePixels {
+		return fmt.Errorf("imagem muito grande: %dx%d pixels. Máximo: %d megapixels",
+			width, height, s.config.MaxImagePixels/1_000_000)
+	}
+	return nil
+}
+
+// imageConfig lê as dimensões do cabeçalho, sem decodificar os pixels. O Go
+// não lê WebP, cujo cabeçalho é interpretado aqui
+func imageConfig(data []byte) (int, int) {
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return config.Width, config.Height
+	}
+	return webpDimensions(data)
+}
+
+// webpDimensions lê as dimensões do primeiro chunk de um WebP (VP8X, VP8 ou VP8L)
+func webpDimensions(data []byte) (int, int) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0
+	}
+	chunk := data[20:]
+
+	switch string(data[12:16]) {
+	case "VP8X":
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1
+	case "VP8 ":
+		if chunk[3] != 0x9D || chunk[4] != 0x01 || chunk[5] != 0x2A {
+			return 0, 0
+		}
+		width := int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3FFF)
+		height := int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3FFF)
+		return width, height
+	case "VP8L":
+		if chunk[0] != 0x2F {
+			return 0, 0
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		return int(bits&0x3FFF) + 1, int(bits>>14&0x3FFF) + 1
+	}
+	return 0, 0
+}
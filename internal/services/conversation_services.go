@@ -0,0 +1,860 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type ConversationServiceInterface interface {
+	StartDirectConversation(userID, otherUserID uint) (*models.ConversationResponse, error)
+	GetConversations(userID uint, limit, offset int) ([]models.ConversationResponse, error)
+	GetMessages(conversationID, userID uint, limit, offset int) ([]models.MessageResponse, error)
+	SendMessage(conversationID, userID uint, req *SendMessageRequest) (*models.MessageResponse, error)
+	MarkRead(conversationID, userID uint, req *MarkConversationReadRequest) (*models.ConversationReadResponse, error)
+	GetTripConversation(itineraryID, userID uint) (*models.ConversationResponse, error)
+	UpdateConversation(conversationID, userID uint, req *UpdateConversationRequest) (*models.ConversationResponse, error)
+	AddMember(conversationID, userID, memberID uint) (*models.ConversationResponse, error)
+	RemoveMember(conversationID, userID, memberID uint) error
+	UploadAttachment(conversationID, userID uint, file *multipart.FileHeader) (*MediaUploadResponse, error)
+	OpenAttachment(mediaID uint, req *AttachmentRequest) (*AttachmentFile, error)
+	ModeratorAttachment(mediaID, adminID uint) *models.MessageAttachment
+}
+
+type StartConversationRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// SendMessageRequest é uma mensagem com texto, anexo (media_id de um upload
+// em /conversations/{id}/attachments) ou os dois
+type SendMessageRequest struct {
+	Content string `json:"content"`
+	MediaID *uint  `json:"media_id"`
+}
+
+// AttachmentConfig define os links assinados dos anexos das conversas
+type AttachmentConfig struct {
+	// Endereço público da API usado nos links (ex.: https://api.guia.app)
+	BaseURL string
+	// Chave HMAC dos links; vazia gera uma chave aleatória a cada início, e
+	// os links deixam de valer ao reiniciar e entre instâncias
+	Secret string
+	URLTTL time.Duration
+}
+
+// AttachmentRequest são os parâmetros de um link assinado de anexo
+type AttachmentRequest struct {
+	Variant   string `form:"variant"`
+	UserID    uint   `form:"user"`
+	Expires   int64  `form:"expires"`
+	Signature string `form:"signature"`
+}
+
+// AttachmentFile é o arquivo de um anexo aberto para envio; quem recebe
+// fecha Content
+type AttachmentFile struct {
+	Content     io.ReadCloser
+	ContentType string
+	ExpiresAt   time.Time
+}
+
+// MarkConversationReadRequest marca a conversa como lida até a mensagem
+// informada ou, sem ela, até a última
+type MarkConversationReadRequest struct {
+	MessageID *uint `json:"message_id"`
+}
+
+type UpdateConversationRequest struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type AddConversationMemberRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+const maxConversationTitleLength = 100
+
+var errFrozenConversation = errors.New("conversa congelada por um bloqueio entre os participantes")
+
+// Nomes dos campos do roteiro nos avisos do grupo da viagem
+var itineraryFieldLabels = map[string]string{
+	"title":          "título",
+	"description":    "descrição",
+	"category":       "categoria",
+	"estimated_cost": "custo estimado",
+	"duration":       "duração",
+	"difficulty":     "dificuldade",
+	"location":       "destino",
+}
+
+type ConversationService struct {
+	conversationRepo repositories.ConversationRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	itineraryRepo    repositories.ItineraryRepositoryInterface
+	mediaRepo        repositories.MediaRepositoryInterface
+	mediaService     MediaServiceInterface
+	config           *AttachmentConfig
+	eventBus         events.BusInterface
+}
+
+func NewConversationService(conversationRepo repositories.ConversationRepositoryInterface, userRepo repositories.UserRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface, mediaRepo repositories.MediaRepositoryInterface, mediaService MediaServiceInterface, config *AttachmentConfig, eventBus events.BusInterface) ConversationServiceInterface {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.URLTTL <= 0 {
+		config.URLTTL = 15 * time.Minute
+	}
+	if config.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal("Falha ao gerar a chave dos links de anexos: ", err)
+		}
+		config.Secret = hex.EncodeToString(secret)
+		log.Println("ATTACHMENT_URL_SECRET não configurado; os links de anexos valem só até o próximo início")
+	}
+
+	service := &ConversationService{
+		conversationRepo: conversationRepo,
+		userRepo:         userRepo,
+		itineraryRepo:    itineraryRepo,
+		mediaRepo:        mediaRepo,
+		mediaService:     mediaService,
+		config:           config,
+		eventBus:         eventBus,
+	}
+
+	// O grupo da viagem acompanha os participantes e avisa as mudanças
+	eventBus.Subscribe(events.TripParticipantJoined, service.onTripParticipantJoined)
+	eventBus.Subscribe(events.TripParticipantLeft, service.onTripParticipantLeft)
+	eventBus.Subscribe(events.ItineraryUpdated, service.onItineraryUpdated)
+	// Um bloqueio em qualquer direção congela a conversa direta do par
+	eventBus.Subscribe(events.UserBlocked, service.onUserBlocked)
+	eventBus.Subscribe(events.UserUnblocked, service.onUserUnblocked)
+
+	return service
+}
+
+func (s *ConversationService) StartDirectConversation(userID, otherUserID uint) (*models.ConversationResponse, error) {
+	if userID == otherUserID {
+		return nil, errors.New("não é possível iniciar uma conversa consigo mesmo")
+	}
+
+	if _, err := s.userRepo.GetByID(otherUserID); err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	blocked, err := s.userRepo.HasBlockBetween(userID, otherUserID)
+	if err != nil {
+		return nil, errors.New("erro ao iniciar conversa")
+	}
+	if blocked {
+		return nil, errors.New("não é possível conversar com este usuário por causa de um bloqueio")
+	}
+
+	conversation, err := s.conversationRepo.GetOrCreateDirect(directConversationKey(userID, otherUserID), []uint{userID, otherUserID})
+	if err != nil {
+		return nil, errors.New("erro ao iniciar conversa")
+	}
+
+	return conversation.ToResponse(), nil
+}
+
+func (s *ConversationService) GetConversations(userID uint, limit, offset int) ([]models.ConversationResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	conversations, err := s.conversationRepo.GetByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar conversas")
+	}
+
+	conversationIDs := make([]uint, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ID)
+	}
+
+	lastMessages, err := s.conversationRepo.GetLastMessages(conversationIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar conversas")
+	}
+
+	// Listar as conversas entrega ao aparelho as últimas mensagens
+	for i := range conversations {
+		if message, ok := lastMessages[conversations[i].ID]; ok {
+			s.markDelivered(&conversations[i], userID, message.ID)
+		}
+	}
+
+	unreadCounts, err := s.conversationRepo.CountUnread(userID, conversationIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar conversas")
+	}
+
+	var responses []models.ConversationResponse
+	for _, conversation := range conversations {
+		if message, ok := lastMessages[conversation.ID]; ok {
+			conversation.LastMessage = &message
+		}
+		conversation.UnreadCount = unreadCounts[conversation.ID]
+		response := conversation.ToResponse()
+		if conversation.LastMessage != nil && response.LastMessage != nil {
+			response.LastMessage.Attachment = s.attachment(conversation.LastMessage.Media, userID)
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+func (s *ConversationService) GetMessages(conversationID, userID uint, limit, offset int) ([]models.MessageResponse, error) {
+	conversation, err := s.getConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	messages, err := s.conversationRepo.GetMessages(conversationID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mensagens")
+	}
+
+	// As mensagens vêm da mais nova para a mais antiga
+	if len(messages) > 0 {
+		s.markDelivered(conversation, userID, messages[0].ID)
+	}
+
+	var responses []models.MessageResponse
+	for _, message := range messages {
+		responses = append(responses, *s.messageResponse(conversation, &message, userID))
+	}
+
+	return responses, nil
+}
+
+// MarkRead marca a conversa como lida e avisa os outros participantes em
+// tempo real
+func (s *ConversationService) MarkRead(conversationID, userID uint, req *MarkConversationReadRequest) (*models.ConversationReadResponse, error) {
+	conversation, err := s.getConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var messageID uint
+	if req.MessageID != nil {
+		message, err := s.conversationRepo.GetMessage(conversation.ID, *req.MessageID)
+		if err != nil {
+			return nil, errors.New("mensagem não encontrada")
+		}
+		messageID = message.ID
+	} else {
+		lastMessages, err := s.conversationRepo.GetLastMessages([]uint{conversation.ID})
+		if err != nil {
+			return nil, errors.New("erro ao buscar mensagens")
+		}
+		messageID = lastMessages[conversation.ID].ID
+	}
+
+	participant := conversation.Participant(userID)
+	if messageID > participant.LastReadMessageID {
+		updated, err := s.conversationRepo.MarkRead(conversation.ID, userID, messageID)
+		if err != nil {
+			return nil, errors.New("erro ao marcar conversa como lida")
+		}
+		if updated {
+			participant.LastReadMessageID = messageID
+			s.publishReceipt(events.MessagesRead, conversation.ID, userID, messageID)
+		}
+	}
+
+	unreadCounts, err := s.conversationRepo.CountUnread(userID, []uint{conversation.ID})
+	if err != nil {
+		return nil, errors.New("erro ao buscar mensagens")
+	}
+
+	return &models.ConversationReadResponse{
+		ConversationID:    conversation.ID,
+		LastReadMessageID: max(participant.LastReadMessageID, messageID),
+		UnreadCount:       unreadCounts[conversation.ID],
+	}, nil
+}
+
+// markDelivered confirma a entrega ao usuário até messageID, avisando os
+// outros participantes quando a confirmação avança
+func (s *ConversationService) markDelivered(conversation *models.Conversation, userID, messageID uint) {
+	participant := conversation.Participant(userID)
+	if participant == nil || participant.LastDeliveredMessageID >= messageID {
+		return
+	}
+
+	updated, err := s.conversationRepo.MarkDelivered(conversation.ID, userID, messageID)
+	if err != nil {
+		log.Printf("Falha ao confirmar entrega da conversa %d ao usuário %d: %v", conversation.ID, userID, err)
+		return
+	}
+	participant.LastDeliveredMessageID = messageID
+	if updated {
+		s.publishReceipt(events.MessagesDelivered, conversation.ID, userID, messageID)
+	}
+}
+
+func (s *ConversationService) publishReceipt(eventType events.EventType, conversationID, userID, messageID uint) {
+	s.eventBus.Publish(events.Event{
+		Type:     eventType,
+		ActorID:  userID,
+		EntityID: conversationID,
+		Data: map[string]string{
+			"message_id": fmt.Sprint(messageID),
+		},
+	})
+}
+
+func (s *ConversationService) SendMessage(conversationID, userID uint, req *SendMessageRequest) (*models.MessageResponse, error) {
+	conversation, err := s.getConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.FrozenAt != nil {
+		return nil, errFrozenConversation
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if req.MediaID == nil {
+		if err := s.validateMessageContent(content); err != nil {
+			return nil, err
+		}
+	} else if len(content) > 5000 {
+		return nil, errors.New("mensagem deve ter no máximo 5000 caracteres")
+	}
+
+	message := &models.Message{
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Kind:           models.MessageKindText,
+		Content:        content,
+	}
+
+	// O anexo precisa ser uma mídia privada enviada por quem manda a mensagem
+	if req.MediaID != nil {
+		media, err := s.mediaRepo.GetByID(*req.MediaID)
+		if err != nil || media.OwnerID != userID || !media.Private {
+			return nil, errors.New("anexo desconhecido ou enviado por outro usuário")
+		}
+		if err := checkMediaModeration(media); err != nil {
+			return nil, err
+		}
+		message.MediaID = &media.ID
+		message.Media = media
+	}
+
+	if err := s.conversationRepo.CreateMessage(message); err != nil {
+		return nil, errors.New("erro ao enviar mensagem")
+	}
+
+	// Mensagens só com anexo aparecem nas notificações pelo tipo da mídia
+	text := content
+	if text == "" && message.Media != nil {
+		text = "Enviou uma foto"
+		if message.Media.MediaType == models.MediaTypeVideo {
+			text = "Enviou um vídeo"
+		}
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.MessageSent,
+		ActorID:  userID,
+		EntityID: message.ID,
+		Data: map[string]string{
+			"conversation_id": fmt.Sprint(conversationID),
+			"text":            truncateString(text, 500),
+		},
+	})
+
+	return s.messageResponse(conversation, message, userID), nil
+}
+
+// GetTripConversation abre o grupo da viagem, criando-o com o autor e os
+// participantes na primeira vez. Um participante que saiu do grupo volta a
+// ele por aqui
+func (s *ConversationService) GetTripConversation(itineraryID, userID uint) (*models.ConversationResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		role, err := s.itineraryRepo.GetParticipantRole(itineraryID, userID)
+		if err != nil {
+			return nil, errors.New("erro ao buscar participantes")
+		}
+		if role == "" {
+			return nil, errors.New("você não tem permissão para acessar o grupo desta viagem")
+		}
+	}
+
+	conversation, _, err := s.tripConversation(itinerary)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conversation.HasParticipant(userID) {
+		added, err := s.conversationRepo.AddParticipants(conversation.ID, []uint{userID})
+		if err != nil {
+			return nil, errors.New("erro ao entrar no grupo")
+		}
+		if len(added) > 0 {
+			s.postSystemMessage(conversation.ID, userID, fmt.Sprintf("%s entrou no grupo", s.username(userID)))
+		}
+		if conversation, err = s.conversationRepo.GetByID(conversation.ID); err != nil {
+			return nil, errors.New("erro ao buscar conversa")
+		}
+	}
+
+	return conversation.ToResponse(), nil
+}
+
+// UpdateConversation renomeia o grupo da viagem; qualquer membro pode
+func (s *ConversationService) UpdateConversation(conversationID, userID uint, req *UpdateConversationRequest) (*models.ConversationResponse, error) {
+	conversation, err := s.getTripConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, errors.New("título é obrigatório")
+	}
+	if utf8.RuneCountInString(title) > maxConversationTitleLength {
+		return nil, fmt.Errorf("título deve ter no máximo %d caracteres", maxConversationTitleLength)
+	}
+
+	if title != conversation.Title {
+		if err := s.conversationRepo.UpdateTitle(conversation.ID, title); err != nil {
+			return nil, errors.New("erro ao atualizar conversa")
+		}
+		s.postSystemMessage(conversation.ID, userID, fmt.Sprintf("%s renomeou o grupo para \"%s\"", s.username(userID), title))
+		conversation.Title = title
+	}
+
+	return conversation.ToResponse(), nil
+}
+
+// AddMember inclui no grupo um participante da viagem que estava fora dele;
+// só o autor da viagem gerencia os membros
+func (s *ConversationService) AddMember(conversationID, userID, memberID uint) (*models.ConversationResponse, error) {
+	conversation, err := s.getTripConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	itinerary, err := s.itineraryRepo.GetByID(*conversation.ItineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para gerenciar os membros deste grupo")
+	}
+
+	role, err := s.itineraryRepo.GetParticipantRole(itinerary.ID, memberID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar participantes")
+	}
+	if role == "" && memberID != itinerary.AuthorID {
+		return nil, errors.New("só participantes da viagem podem entrar no grupo")
+	}
+
+	added, err := s.conversationRepo.AddParticipants(conversation.ID, []uint{memberID})
+	if err != nil {
+		return nil, errors.New("erro ao adicionar membro")
+	}
+	if len(added) == 0 {
+		return nil, errors.New("usuário já está no grupo")
+	}
+	s.postSystemMessage(conversation.ID, userID, fmt.Sprintf("%s adicionou %s ao grupo", s.username(userID), s.username(memberID)))
+
+	if conversation, err = s.conversationRepo.GetByID(conversation.ID); err != nil {
+		return nil, errors.New("erro ao buscar conversa")
+	}
+	return conversation.ToResponse(), nil
+}
+
+// RemoveMember tira um membro do grupo: o autor da viagem remove qualquer
+// outro, e cada membro pode sair. Quem sai continua na viagem
+func (s *ConversationService) RemoveMember(conversationID, userID, memberID uint) error {
+	conversation, err := s.getTripConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return err
+	}
+	itinerary, err := s.itineraryRepo.GetByID(*conversation.ItineraryID)
+	if err != nil {
+		return errors.New("roteiro não encontrado")
+	}
+
+	if memberID == itinerary.AuthorID {
+		return errors.New("o autor da viagem não pode sair do grupo")
+	}
+	if memberID != userID && itinerary.AuthorID != userID {
+		return errors.New("você não tem permissão para gerenciar os membros deste grupo")
+	}
+
+	removed, err := s.conversationRepo.RemoveParticipant(conversation.ID, memberID)
+	if err != nil {
+		return errors.New("erro ao remover membro")
+	}
+	if !removed {
+		return errors.New("membro não encontrado")
+	}
+
+	if memberID == userID {
+		s.postSystemMessage(conversation.ID, userID, fmt.Sprintf("%s saiu do grupo", s.username(userID)))
+	} else {
+		s.postSystemMessage(conversation.ID, userID, fmt.Sprintf("%s removeu %s do grupo", s.username(userID), s.username(memberID)))
+	}
+	return nil
+}
+
+// tripConversation retorna o grupo da viagem, criando-o com o autor e os
+// participantes
+func (s *ConversationService) tripConversation(itinerary *models.Itinerary) (*models.Conversation, bool, error) {
+	participantIDs, err := s.itineraryRepo.GetParticipantIDs(itinerary.ID)
+	if err != nil {
+		return nil, false, errors.New("erro ao buscar participantes")
+	}
+
+	title := truncateString(itinerary.Title, maxConversationTitleLength)
+	conversation, created, err := s.conversationRepo.GetOrCreateTrip(itinerary.ID, title, append([]uint{itinerary.AuthorID}, participantIDs...))
+	if err != nil {
+		return nil, false, errors.New("erro ao criar grupo da viagem")
+	}
+	if created {
+		s.postSystemMessage(conversation.ID, itinerary.AuthorID, fmt.Sprintf("Grupo da viagem \"%s\" criado", itinerary.Title))
+	}
+	return conversation, created, nil
+}
+
+func (s *ConversationService) onTripParticipantJoined(event events.Event) {
+	userID, err := strconv.ParseUint(event.Data["user_id"], 10, 32)
+	if err != nil {
+		return
+	}
+	itinerary, err := s.itineraryRepo.GetByID(event.EntityID)
+	if err != nil {
+		return
+	}
+
+	conversation, created, err := s.tripConversation(itinerary)
+	if err != nil {
+		log.Printf("Falha ao abrir o grupo da viagem %d: %v", itinerary.ID, err)
+		return
+	}
+	added, err := s.conversationRepo.AddParticipants(conversation.ID, []uint{uint(userID)})
+	if err != nil {
+		log.Printf("Falha ao incluir o usuário %d no grupo da viagem %d: %v", userID, itinerary.ID, err)
+		return
+	}
+	// Mudança de papel de quem já estava no grupo não gera aviso
+	if !created && len(added) == 0 {
+		return
+	}
+	s.postSystemMessage(conversation.ID, uint(userID), fmt.Sprintf("%s entrou na viagem", s.username(uint(userID))))
+}
+
+func (s *ConversationService) onTripParticipantLeft(event events.Event) {
+	userID, err := strconv.ParseUint(event.Data["user_id"], 10, 32)
+	if err != nil {
+		return
+	}
+	conversation, err := s.conversationRepo.GetByItinerary(event.EntityID)
+	if err != nil {
+		return
+	}
+
+	removed, err := s.conversationRepo.RemoveParticipant(conversation.ID, uint(userID))
+	if err != nil {
+		log.Printf("Falha ao tirar o usuário %d do grupo da viagem %d: %v", userID, event.EntityID, err)
+	}
+	if !removed {
+		return
+	}
+
+	if uint(userID) == event.ActorID {
+		s.postSystemMessage(conversation.ID, event.ActorID, fmt.Sprintf("%s saiu da viagem", s.username(event.ActorID)))
+	} else {
+		s.postSystemMessage(conversation.ID, event.ActorID, fmt.Sprintf("%s removeu %s da viagem", s.username(event.ActorID), s.username(uint(userID))))
+	}
+}
+
+// onItineraryUpdated avisa no grupo o que mudou no roteiro e acompanha a
+// troca de título enquanto o grupo não foi renomeado
+func (s *ConversationService) onItineraryUpdated(event events.Event) {
+	conversation, err := s.conversationRepo.GetByItinerary(event.EntityID)
+	if err != nil {
+		return
+	}
+
+	var labels []string
+	titleChanged := false
+	for _, field := range strings.Split(event.Data["fields"], ",") {
+		if label, ok := itineraryFieldLabels[field]; ok {
+			labels = append(labels, label)
+		}
+		titleChanged = titleChanged || field == "title"
+	}
+	if len(labels) == 0 {
+		return
+	}
+
+	if titleChanged && conversation.Title == truncateString(event.Data["old_title"], maxConversationTitleLength) {
+		if itinerary, err := s.itineraryRepo.GetByID(event.EntityID); err == nil {
+			if err := s.conversationRepo.UpdateTitle(conversation.ID, truncateString(itinerary.Title, maxConversationTitleLength)); err != nil {
+				log.Printf("Falha ao atualizar o título do grupo da viagem %d: %v", event.EntityID, err)
+			}
+		}
+	}
+
+	s.postSystemMessage(conversation.ID, event.ActorID, fmt.Sprintf("%s atualizou o roteiro: %s", s.username(event.ActorID), strings.Join(labels, ", ")))
+}
+
+func (s *ConversationService) onUserBlocked(event events.Event) {
+	if _, err := s.conversationRepo.SetDirectFrozen(directConversationKey(event.ActorID, event.EntityID), true); err != nil {
+		log.Printf("Falha ao congelar a conversa entre os usuários %d e %d: %v", event.ActorID, event.EntityID, err)
+	}
+}
+
+// onUserUnblocked descongela a conversa só quando não resta bloqueio em
+// nenhuma das direções
+func (s *ConversationService) onUserUnblocked(event events.Event) {
+	blocked, err := s.userRepo.HasBlockBetween(event.ActorID, event.EntityID)
+	if err != nil {
+		log.Printf("Falha ao verificar bloqueios entre os usuários %d e %d: %v", event.ActorID, event.EntityID, err)
+		return
+	}
+	if blocked {
+		return
+	}
+
+	if _, err := s.conversationRepo.SetDirectFrozen(directConversationKey(event.ActorID, event.EntityID), false); err != nil {
+		log.Printf("Falha ao descongelar a conversa entre os usuários %d e %d: %v", event.ActorID, event.EntityID, err)
+	}
+}
+
+// postSystemMessage grava um aviso no grupo; avisos não geram notificações
+func (s *ConversationService) postSystemMessage(conversationID, actorID uint, content string) {
+	message := &models.Message{
+		ConversationID: conversationID,
+		SenderID:       actorID,
+		Kind:           models.MessageKindSystem,
+		Content:        content,
+	}
+	if err := s.conversationRepo.CreateMessage(message); err != nil {
+		log.Printf("Falha ao gravar aviso na conversa %d: %v", conversationID, err)
+	}
+}
+
+func (s *ConversationService) username(userID uint) string {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "Alguém"
+	}
+	return user.Username
+}
+
+func (s *ConversationService) getTripConversationForParticipant(conversationID, userID uint) (*models.Conversation, error) {
+	conversation, err := s.getConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Type != models.ConversationTypeTrip || conversation.ItineraryID == nil {
+		return nil, errors.New("só grupos de viagem podem ser alterados")
+	}
+	return conversation, nil
+}
+
+func (s *ConversationService) getConversationForParticipant(conversationID, userID uint) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || !conversation.HasParticipant(userID) {
+		return nil, errors.New("conversa não encontrada")
+	}
+	return conversation, nil
+}
+
+// directConversationKey identifica a conversa direta de um par de usuários,
+// independente de quem a iniciou
+func directConversationKey(userID, otherUserID uint) string {
+	if userID > otherUserID {
+		userID, otherUserID = otherUserID, userID
+	}
+	return fmt.Sprintf("%d:%d", userID, otherUserID)
+}
+
+// Funções de validação
+func (s *ConversationService) validateMessageContent(content string) error {
+	if len(content) == 0 {
+		return errors.New("mensagem não pode estar vazia")
+	}
+
+	if len(content) > 5000 {
+		return errors.New("mensagem deve ter no máximo 5000 caracteres")
+	}
+
+	return nil
+}
+
+// UploadAttachment grava uma foto ou vídeo para ser enviado na conversa. O
+// arquivo fica privado e os links devolvidos são assinados para quem enviou
+func (s *ConversationService) UploadAttachment(conversationID, userID uint, file *multipart.FileHeader) (*MediaUploadResponse, error) {
+	conversation, err := s.getConversationForParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.FrozenAt != nil {
+		return nil, errFrozenConversation
+	}
+
+	mediaType := MediaTypeImage
+	if s.mediaService.ValidateFileName(file.Filename, MediaTypeImage) != nil {
+		mediaType = MediaTypeVideo
+	}
+
+	response, err := s.mediaService.UploadPrivateFile(file, userID, mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	// As URLs do storage não são públicas; o cliente recebe os links assinados
+	expiresAt := s.linkExpiry()
+	response.URL = s.signedAttachmentURL(response.ID, "", userID, expiresAt)
+	for name, variant := range response.Variants {
+		variant.URL = s.signedAttachmentURL(response.ID, name, userID, expiresAt)
+		variant.FilePath = ""
+		response.Variants[name] = variant
+	}
+	response.FilePath = ""
+	return response, nil
+}
+
+// OpenAttachment confere o link assinado e abre o arquivo. Além da
+// assinatura, o usuário do link precisa ainda poder ver o anexo: ser o dono,
+// participante de uma conversa onde ele foi enviado ou admin, se a mensagem
+// foi denunciada
+func (s *ConversationService) OpenAttachment(mediaID uint, req *AttachmentRequest) (*AttachmentFile, error) {
+	expected := s.attachmentSignature(mediaID, req.Variant, req.UserID, req.Expires)
+	if !hmac.Equal([]byte(req.Signature), []byte(expected)) {
+		return nil, errors.New("link do anexo inválido")
+	}
+	expiresAt := time.Unix(req.Expires, 0)
+	if time.Now().After(expiresAt) {
+		return nil, errors.New("link do anexo expirado")
+	}
+
+	media, err := s.mediaRepo.GetByID(mediaID)
+	if err != nil || !media.Private {
+		return nil, errors.New("anexo não encontrado")
+	}
+	if media.OwnerID != req.UserID {
+		allowed, err := s.conversationRepo.CanAccessMedia(req.UserID, media.ID)
+		if err == nil && !allowed {
+			// Admins veem anexos de mensagens denunciadas
+			allowed, err = s.conversationRepo.CanModerateMedia(req.UserID, media.ID)
+		}
+		if err != nil {
+			return nil, errors.New("erro ao verificar acesso ao anexo")
+		}
+		if !allowed {
+			return nil, errors.New("você não tem permissão para acessar este anexo")
+		}
+	}
+
+	content, contentType, err := s.mediaService.OpenVariant(media, req.Variant)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || req.Variant != "" {
+			return nil, errors.New("anexo não encontrado")
+		}
+		log.Printf("Falha ao abrir o anexo %d: %v", media.ID, err)
+		return nil, errors.New("erro ao abrir anexo")
+	}
+
+	return &AttachmentFile{Content: content, ContentType: contentType, ExpiresAt: expiresAt}, nil
+}
+
+// ModeratorAttachment assina os links de um anexo denunciado para o admin
+// que abriu a denúncia
+func (s *ConversationService) ModeratorAttachment(mediaID, adminID uint) *models.MessageAttachment {
+	media, err := s.mediaRepo.GetByID(mediaID)
+	if err != nil {
+		return nil
+	}
+	return s.attachment(media, adminID)
+}
+
+// messageResponse converte a mensagem com as confirmações de leitura e os
+// links do anexo assinados para quem está vendo
+func (s *ConversationService) messageResponse(conversation *models.Conversation, message *models.Message, viewerID uint) *models.MessageResponse {
+	response := conversation.ReceiptResponse(message)
+	response.Attachment = s.attachment(message.Media, viewerID)
+	return response
+}
+
+func (s *ConversationService) attachment(media *models.Media, viewerID uint) *models.MessageAttachment {
+	if media == nil {
+		return nil
+	}
+
+	expiresAt := s.linkExpiry()
+	attachment := &models.MessageAttachment{
+		MediaID:   media.ID,
+		MediaType: media.MediaType,
+		MimeType:  media.MimeType,
+		Width:     media.Width,
+		Height:    media.Height,
+		URL:       s.signedAttachmentURL(media.ID, "", viewerID, expiresAt),
+		ExpiresAt: expiresAt,
+	}
+	if len(media.Variants) > 0 {
+		attachment.Variants = make(map[string]string, len(media.Variants))
+		for name := range media.Variants {
+			attachment.Variants[name] = s.signedAttachmentURL(media.ID, name, viewerID, expiresAt)
+		}
+	}
+	return attachment
+}
+
+// linkExpiry arredonda a validade ao minuto, para que o mesmo anexo tenha o
+// mesmo link por um tempo e o cache do aparelho funcione
+func (s *ConversationService) linkExpiry() time.Time {
+	return time.Now().Add(s.config.URLTTL).Truncate(time.Minute)
+}
+
+func (s *ConversationService) signedAttachmentURL(mediaID uint, variant string, userID uint, expiresAt time.Time) string {
+	query := url.Values{}
+	if variant != "" {
+		query.Set("variant", variant)
+	}
+	query.Set("user", fmt.Sprint(userID))
+	query.Set("expires", fmt.Sprint(expiresAt.Unix()))
+	query.Set("signature", s.attachmentSignature(mediaID, variant, userID, expiresAt.Unix()))
+	return fmt.Sprintf("%s/api/v1/attachments/%d?%s", s.config.BaseURL, mediaID, query.Encode())
+}
+
+// attachmentSignature é o HMAC-SHA256 da mídia, variante, usuário e validade
+func (s *ConversationService) attachmentSignature(mediaID uint, variant string, userID uint, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	fmt.Fprintf(mac, "%d:%s:%d:%d", mediaID, variant, userID, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
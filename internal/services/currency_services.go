@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// Moeda usada quando não é possível descobrir a do usuário
+const defaultCurrency = "BRL"
+
+type CurrencyConfig struct {
+	// Endpoint com as cotações do dia no formato {"base_code"|"base": ..., "rates": {...}}
+	RatesURL string
+}
+
+type CurrencyServiceInterface interface {
+	Convert(amount float64, from, to string) (float64, bool)
+	RateFor(currency string) (float64, bool)
+	CurrencyForCountry(countryCode string) string
+	RefreshRates() error
+	StartExchangeRateScheduler(interval time.Duration)
+}
+
+// CurrencyService converte valores usando as cotações em cache. As cotações
+// ficam também no banco, para que os filtros de custo rodem em SQL e para que
+// a API funcione com o provedor fora do ar
+type CurrencyService struct {
+	exchangeRateRepo repositories.ExchangeRateRepositoryInterface
+	geoRepo          repositories.GeoRepositoryInterface
+	config           *CurrencyConfig
+	client           *http.Client
+
+	mu    sync.RWMutex
+	rates map[string]float64
+}
+
+func NewCurrencyService(exchangeRateRepo repositories.ExchangeRateRepositoryInterface, geoRepo repositories.GeoRepositoryInterface, config *CurrencyConfig) CurrencyServiceInterface {
+	return &CurrencyService{
+		exchangeRateRepo: exchangeRateRepo,
+		geoRepo:          geoRepo,
+		config:           config,
+		client:           &http.Client{Timeout: 10 * time.Second},
+		rates:            map[string]float64{"USD": 1},
+	}
+}
+
+// Convert converte o valor entre moedas; retorna false quando falta a cotação
+// de alguma delas
+func (s *CurrencyService) Convert(amount float64, from, to string) (float64, bool) {
+	from = normalizeCurrency(from)
+	to = normalizeCurrency(to)
+	if from == to {
+		return amount, true
+	}
+
+	fromRate, ok := s.RateFor(from)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := s.RateFor(to)
+	if !ok {
+		return 0, false
+	}
+
+	return math.Round(amount/fromRate*toRate*100) / 100, true
+}
+
+// RateFor retorna quantas unidades da moeda valem 1 USD
+func (s *CurrencyService) RateFor(currency string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rate, ok := s.rates[normalizeCurrency(currency)]
+	return rate, ok && rate > 0
+}
+
+// CurrencyForCountry retorna a moeda do país (ISO 3166-1 alpha-2) ou a moeda
+// padrão quando o país é desconhecido
+func (s *CurrencyService) CurrencyForCountry(countryCode string) string {
+	if countryCode == "" {
+		return defaultCurrency
+	}
+	country, err := s.geoRepo.GetCountryByCode(strings.ToUpper(countryCode))
+	if err != nil || country.CurrencyCode == "" {
+		return defaultCurrency
+	}
+	return normalizeCurrency(country.CurrencyCode)
+}
+
+// RefreshRates busca as cotações no provedor e atualiza o banco e o cache
+func (s *CurrencyService) RefreshRates() error {
+	if s.config.RatesURL == "" {
+		return errors.New("provedor de cotações não configurado")
+	}
+
+	resp, err := s.client.Get(s.config.RatesURL)
+	if err != nil {
+		return fmt.Errorf("erro ao acessar provedor de cotações: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("erro ao buscar cotações: provedor respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Base     string             `json:"base"`
+		BaseCode string             `json:"base_code"`
+		Rates    map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return errors.New("erro ao ler cotações do provedor")
+	}
+
+	base := normalizeCurrency(payload.BaseCode)
+	if base == "" {
+		base = normalizeCurrency(payload.Base)
+	}
+	if base == "" {
+		return errors.New("cotações do provedor sem moeda base")
+	}
+	payload.Rates[base] = 1
+
+	// Converter para a base USD
+	usd, ok := payload.Rates["USD"]
+	if !ok || usd <= 0 {
+		return errors.New("cotações do provedor sem o dólar americano")
+	}
+
+	now := time.Now()
+	rates := make([]models.ExchangeRate, 0, len(payload.Rates))
+	for currency, rate := range payload.Rates {
+		currency = normalizeCurrency(currency)
+		if len(currency) != 3 || rate <= 0 {
+			continue
+		}
+		rates = append(rates, models.ExchangeRate{
+			Currency:  currency,
+			Rate:      rate / usd,
+			UpdatedAt: now,
+		})
+	}
+
+	if err := s.exchangeRateRepo.SaveRates(rates); err != nil {
+		return errors.New("erro ao salvar cotações")
+	}
+
+	s.setRates(rates)
+	return nil
+}
+
+// StartExchangeRateScheduler carrega as cotações salvas e as atualiza no
+// provedor na inicialização e a cada intervalo
+func (s *CurrencyService) StartExchangeRateScheduler(interval time.Duration) {
+	if stored, err := s.exchangeRateRepo.GetRates(); err != nil {
+		log.Println("Falha ao carregar cotações salvas:", err)
+	} else {
+		s.setRates(stored)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.RefreshRates(); err != nil {
+				log.Println("Falha ao atualizar cotações:", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *CurrencyService) setRates(rates []models.ExchangeRate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rate := range rates {
+		s.rates[rate.Currency] = rate.Rate
+	}
+}
+
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// isValidCurrency confere o formato do código ISO 4217
+func isValidCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
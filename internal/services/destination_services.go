@@ -0,0 +1,391 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	// Promoções exibidas ao mesmo tempo na página de um destino
+	maxDestinationPromotions    = 5
+	destinationItinerariesLimit = 20
+	maxPromotionDuration        = 180 * 24 * time.Hour
+)
+
+type DestinationServiceInterface interface {
+	GetDestinationPage(cityID, currentUserID uint) (*models.DestinationPage, error)
+	CreatePromotion(cityID, userID uint, isAdmin bool, req *PromotionRequest) (*models.DestinationPromotionResponse, error)
+	UpdatePromotion(promotionID, userID uint, isAdmin bool, req *PromotionRequest) (*models.DestinationPromotionResponse, error)
+	DeletePromotion(promotionID, userID uint, isAdmin bool) error
+	GetMyPromotions(userID uint, isAdmin bool, limit, offset int) ([]models.DestinationPromotionResponse, error)
+	GetPromotionReport(promotionID, userID uint, isAdmin bool) (*models.PromotionReport, error)
+	AddPartner(adminID uint, req *DestinationPartnerRequest) (*models.DestinationPartner, error)
+	RemovePartner(partnerID uint) error
+	GetPartners(cityID uint, limit, offset int) ([]models.DestinationPartner, error)
+}
+
+type PromotionRequest struct {
+	Kind        models.PromotionKind `json:"kind"` // só na criação
+	ItineraryID *uint                `json:"itinerary_id"`
+	Title       string               `json:"title" binding:"required"`
+	Body        string               `json:"body"`
+	ImageURL    string               `json:"image_url"`
+	LinkURL     string               `json:"link_url"`
+	SponsorName string               `json:"sponsor_name" binding:"required"`
+	Position    int                  `json:"position"`
+	StartsAt    time.Time            `json:"starts_at" binding:"required"`
+	EndsAt      time.Time            `json:"ends_at" binding:"required"`
+}
+
+type DestinationPartnerRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+	CityID uint `json:"city_id" binding:"required"`
+}
+
+type DestinationService struct {
+	destinationRepo  repositories.DestinationRepositoryInterface
+	geoRepo          repositories.GeoRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	itineraryRepo    repositories.ItineraryRepositoryInterface
+	itineraryService ItineraryServiceInterface
+}
+
+func NewDestinationService(
+	destinationRepo repositories.DestinationRepositoryInterface,
+	geoRepo repositories.GeoRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	itineraryService ItineraryServiceInterface,
+) DestinationServiceInterface {
+	return &DestinationService{
+		destinationRepo:  destinationRepo,
+		geoRepo:          geoRepo,
+		userRepo:         userRepo,
+		itineraryRepo:    itineraryRepo,
+		itineraryService: itineraryService,
+	}
+}
+
+// GetDestinationPage monta a página da cidade com as promoções vigentes e os
+// roteiros populares, contando uma exibição para cada promoção mostrada
+func (s *DestinationService) GetDestinationPage(cityID, currentUserID uint) (*models.DestinationPage, error) {
+	city, err := s.geoRepo.GetCityByID(cityID)
+	if err != nil {
+		return nil, errors.New("destino não encontrado")
+	}
+
+	now := time.Now()
+	promotions, err := s.destinationRepo.GetActivePromotions(cityID, now, maxDestinationPromotions)
+	if err != nil {
+		return nil, errors.New("erro ao buscar promoções do destino")
+	}
+
+	itineraries, err := s.itineraryService.GetItinerariesByCity(cityID, currentUserID, destinationItinerariesLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.DestinationPage{
+		City:        city,
+		Promotions:  make([]models.DestinationPromotionResponse, 0, len(promotions)),
+		Itineraries: itineraries,
+	}
+
+	ids := make([]uint, 0, len(promotions))
+	for _, promotion := range promotions {
+		page.Promotions = append(page.Promotions, *promotion.ToResponse())
+		ids = append(ids, promotion.ID)
+	}
+
+	// Falhas na contagem não impedem a exibição da página
+	if err := s.destinationRepo.RecordImpressions(ids, truncateDay(now)); err != nil {
+		log.Printf("Erro ao registrar exibições das promoções do destino %d: %v", cityID, err)
+	}
+
+	return page, nil
+}
+
+func (s *DestinationService) CreatePromotion(cityID, userID uint, isAdmin bool, req *PromotionRequest) (*models.DestinationPromotionResponse, error) {
+	if _, err := s.geoRepo.GetCityByID(cityID); err != nil {
+		return nil, errors.New("destino não encontrado")
+	}
+
+	if !isAdmin {
+		partner, err := s.destinationRepo.IsPartner(userID, cityID)
+		if err != nil {
+			return nil, errors.New("erro ao verificar parceria do destino")
+		}
+		if !partner {
+			return nil, errors.New("você não tem permissão para promover conteúdo neste destino")
+		}
+	}
+
+	if err := s.validatePromotionRequest(req, true); err != nil {
+		return nil, err
+	}
+
+	promotion := &models.DestinationPromotion{
+		CityID:      cityID,
+		CreatorID:   userID,
+		Kind:        req.Kind,
+		Title:       strings.TrimSpace(req.Title),
+		Body:        strings.TrimSpace(req.Body),
+		ImageURL:    strings.TrimSpace(req.ImageURL),
+		LinkURL:     strings.TrimSpace(req.LinkURL),
+		SponsorName: strings.TrimSpace(req.SponsorName),
+		Position:    req.Position,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	}
+	// Parceiros pagam pela promoção antes de ela aparecer no destino
+	promotion.PaymentStatus = models.PromotionPaymentNone
+	if !isAdmin {
+		promotion.PaymentStatus = models.PromotionPaymentUnpaid
+	}
+	if req.Kind == models.PromotionKindItinerary {
+		promotion.ItineraryID = req.ItineraryID
+	}
+
+	if err := s.destinationRepo.CreatePromotion(promotion); err != nil {
+		return nil, errors.New("erro ao criar promoção")
+	}
+
+	return s.managedResponse(promotion.ID)
+}
+
+// UpdatePromotion altera conteúdo e período; o tipo e o roteiro promovido não
+// mudam depois de criados, e o período não muda depois que o pagamento começa
+func (s *DestinationService) UpdatePromotion(promotionID, userID uint, isAdmin bool, req *PromotionRequest) (*models.DestinationPromotionResponse, error) {
+	promotion, err := s.getManagedPromotion(promotionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Kind = promotion.Kind
+	req.ItineraryID = promotion.ItineraryID
+	if err := s.validatePromotionRequest(req, false); err != nil {
+		return nil, err
+	}
+
+	switch promotion.PaymentStatus {
+	case models.PromotionPaymentPending, models.PromotionPaymentReview, models.PromotionPaymentPaid:
+		if !req.StartsAt.Equal(promotion.StartsAt) || !req.EndsAt.Equal(promotion.EndsAt) {
+			return nil, errors.New("o período de uma promoção paga não pode ser alterado")
+		}
+	}
+
+	promotion.Title = strings.TrimSpace(req.Title)
+	promotion.Body = strings.TrimSpace(req.Body)
+	promotion.ImageURL = strings.TrimSpace(req.ImageURL)
+	promotion.LinkURL = strings.TrimSpace(req.LinkURL)
+	promotion.SponsorName = strings.TrimSpace(req.SponsorName)
+	promotion.Position = req.Position
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+
+	if err := s.destinationRepo.UpdatePromotion(promotion); err != nil {
+		return nil, errors.New("erro ao atualizar promoção")
+	}
+
+	return s.managedResponse(promotion.ID)
+}
+
+func (s *DestinationService) DeletePromotion(promotionID, userID uint, isAdmin bool) error {
+	if _, err := s.getManagedPromotion(promotionID, userID, isAdmin); err != nil {
+		return err
+	}
+
+	if err := s.destinationRepo.DeletePromotion(promotionID); err != nil {
+		return errors.New("erro ao remover promoção")
+	}
+
+	return nil
+}
+
+// GetMyPromotions lista as promoções criadas pelo usuário; admins veem todas
+func (s *DestinationService) GetMyPromotions(userID uint, isAdmin bool, limit, offset int) ([]models.DestinationPromotionResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	creatorID := userID
+	if isAdmin {
+		creatorID = 0
+	}
+
+	promotions, err := s.destinationRepo.GetPromotionsByCreator(creatorID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar promoções")
+	}
+
+	responses := make([]models.DestinationPromotionResponse, 0, len(promotions))
+	for i := range promotions {
+		responses = append(responses, *promotions[i].ToManagedResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *DestinationService) GetPromotionReport(promotionID, userID uint, isAdmin bool) (*models.PromotionReport, error) {
+	promotion, err := s.getManagedPromotion(promotionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := s.destinationRepo.GetDailyImpressions(promotionID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar exibições da promoção")
+	}
+
+	return &models.PromotionReport{
+		Promotion:   promotion.ToManagedResponse(),
+		Impressions: promotion.Impressions,
+		Daily:       daily,
+	}, nil
+}
+
+// AddPartner autoriza uma conta de empresa a promover conteúdo no destino
+func (s *DestinationService) AddPartner(adminID uint, req *DestinationPartnerRequest) (*models.DestinationPartner, error) {
+	user, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	if user.UserType != models.UserTypeCompany {
+		return nil, errors.New("apenas contas de empresa podem ser parceiras de um destino")
+	}
+
+	if _, err := s.geoRepo.GetCityByID(req.CityID); err != nil {
+		return nil, errors.New("destino não encontrado")
+	}
+
+	partner := &models.DestinationPartner{
+		UserID:    req.UserID,
+		CityID:    req.CityID,
+		GrantedBy: adminID,
+	}
+
+	created, err := s.destinationRepo.CreatePartner(partner)
+	if err != nil {
+		return nil, errors.New("erro ao adicionar parceiro do destino")
+	}
+	if !created {
+		return nil, errors.New("a conta já é parceira deste destino")
+	}
+
+	return partner, nil
+}
+
+func (s *DestinationService) RemovePartner(partnerID uint) error {
+	if _, err := s.destinationRepo.GetPartnerByID(partnerID); err != nil {
+		return errors.New("parceiro do destino não encontrado")
+	}
+
+	if err := s.destinationRepo.DeletePartner(partnerID); err != nil {
+		return errors.New("erro ao remover parceiro do destino")
+	}
+
+	return nil
+}
+
+func (s *DestinationService) GetPartners(cityID uint, limit, offset int) ([]models.DestinationPartner, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	partners, err := s.destinationRepo.GetPartners(cityID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar parceiros dos destinos")
+	}
+
+	return partners, nil
+}
+
+// getManagedPromotion garante que apenas o criador ou um admin gerencie a
+// promoção
+func (s *DestinationService) getManagedPromotion(promotionID, userID uint, isAdmin bool) (*models.DestinationPromotion, error) {
+	promotion, err := s.destinationRepo.GetPromotionByID(promotionID)
+	if err != nil {
+		return nil, errors.New("promoção não encontrada")
+	}
+
+	if !isAdmin && promotion.CreatorID != userID {
+		return nil, errors.New("você não tem permissão para gerenciar esta promoção")
+	}
+
+	return promotion, nil
+}
+
+func (s *DestinationService) managedResponse(promotionID uint) (*models.DestinationPromotionResponse, error) {
+	promotion, err := s.destinationRepo.GetPromotionByID(promotionID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar promoção")
+	}
+
+	return promotion.ToManagedResponse(), nil
+}
+
+// Funções de validação
+func (s *DestinationService) validatePromotionRequest(req *PromotionRequest, creating bool) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > 200 {
+		return errors.New("título deve ter entre 1 e 200 caracteres")
+	}
+
+	sponsor := strings.TrimSpace(req.SponsorName)
+	if sponsor == "" || len(sponsor) > 100 {
+		return errors.New("nome do patrocinador deve ter entre 1 e 100 caracteres")
+	}
+
+	if len(req.Body) > 1000 {
+		return errors.New("texto da promoção deve ter no máximo 1000 caracteres")
+	}
+
+	for _, link := range []string{req.ImageURL, req.LinkURL} {
+		if link = strings.TrimSpace(link); link == "" {
+			continue
+		}
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" || len(link) > 500 {
+			return errors.New("links da promoção devem ser URLs http(s) válidas")
+		}
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return errors.New("o fim da promoção deve ser depois do início")
+	}
+	if req.EndsAt.Sub(req.StartsAt) > maxPromotionDuration {
+		return errors.New("a promoção pode durar no máximo 180 dias")
+	}
+	if !req.EndsAt.After(time.Now()) {
+		return errors.New("o fim da promoção deve estar no futuro")
+	}
+
+	if !creating {
+		return nil
+	}
+
+	switch req.Kind {
+	case models.PromotionKindItinerary:
+		if req.ItineraryID == nil {
+			return errors.New("roteiro promovido é obrigatório")
+		}
+		itinerary, err := s.itineraryRepo.GetByID(*req.ItineraryID)
+		if err != nil || !itinerary.IsPublic {
+			return errors.New("roteiro promovido não encontrado")
+		}
+	case models.PromotionKindAnnouncement:
+		if strings.TrimSpace(req.Body) == "" {
+			return errors.New("texto do aviso é obrigatório")
+		}
+	default:
+		return errors.New("tipo de promoção inválido")
+	}
+
+	return nil
+}
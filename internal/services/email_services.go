@@ -0,0 +1,667 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ses"
+)
+
+const (
+	emailQueueSize = 500
+	// Intervalo base entre tentativas; dobra a cada falha
+	emailRetryBackoff = time.Minute
+	maxEmailRetryWait = 6 * time.Hour
+	emailSendTimeout  = 30 * time.Second
+	sendGridAPIURL    = "https://api.sendgrid.com/v3"
+	// Notificações listadas no resumo semanal
+	weeklyDigestItems = 5
+)
+
+type EmailConfig struct {
+	// "smtp", "sendgrid", "ses", "log" (apenas registra, para desenvolvimento)
+	// ou vazio para desativar
+	Provider    string
+	FromAddress string
+	FromName    string
+	// Endereço do app usado nos links dos e-mails
+	AppURL      string
+	MaxAttempts int // tentativas antes de o e-mail ir para a fila de mensagens mortas
+	// Dia da semana (0 = domingo) e hora, no horário do servidor, do resumo semanal
+	DigestWeekday time.Weekday
+	DigestHour    int
+
+	SMTPHost     string
+	SMTPPort     int // 465 usa TLS direto; as demais, STARTTLS quando o servidor oferece
+	SMTPUsername string
+	SMTPPassword string
+
+	SendGridAPIKey string
+	SendGridAPIURL string // opcional (testes)
+
+	// SES; sem chaves usa as credenciais padrão da AWS
+	AWSRegion    string
+	AWSAccessKey string
+	AWSSecretKey string
+}
+
+// EmailSender entrega um e-mail já renderizado ao provedor
+type EmailSender interface {
+	Name() string
+	Send(message *models.EmailMessage) error
+}
+
+// NewEmailSender cria o provedor configurado; nil quando os e-mails estão
+// desativados
+func NewEmailSender(config *EmailConfig) EmailSender {
+	from := mail.Address{Name: config.FromName, Address: config.FromAddress}
+
+	switch config.Provider {
+	case "smtp":
+		if config.SMTPHost == "" || config.FromAddress == "" {
+			log.Println("SMTP_HOST ou EMAIL_FROM_ADDRESS não configurados; e-mails desativados")
+			return nil
+		}
+		return &smtpSender{config: config, from: from}
+	case "sendgrid":
+		if config.SendGridAPIKey == "" || config.FromAddress == "" {
+			log.Println("SENDGRID_API_KEY ou EMAIL_FROM_ADDRESS não configurados; e-mails desativados")
+			return nil
+		}
+		apiURL := strings.TrimRight(config.SendGridAPIURL, "/")
+		if apiURL == "" {
+			apiURL = sendGridAPIURL
+		}
+		return &sendGridSender{
+			apiURL: apiURL,
+			apiKey: config.SendGridAPIKey,
+			from:   from,
+			client: &http.Client{Timeout: emailSendTimeout},
+		}
+	case "ses":
+		if config.FromAddress == "" {
+			log.Println("EMAIL_FROM_ADDRESS não configurado; e-mails desativados")
+			return nil
+		}
+		awsConfig := &aws.Config{Region: aws.String(config.AWSRegion)}
+		if config.AWSAccessKey != "" {
+			awsConfig.Credentials = credentials.NewStaticCredentials(config.AWSAccessKey, config.AWSSecretKey, "")
+		}
+		sess, err := session.NewSession(awsConfig)
+		if err != nil {
+			log.Printf("Falha ao configurar o SES; e-mails desativados: %v", err)
+			return nil
+		}
+		return &sesSender{client: ses.New(sess), from: from}
+	case "log":
+		return &logSender{}
+	case "", "none":
+		return nil
+	default:
+		log.Printf("Provedor de e-mail desconhecido %q; e-mails desativados", config.Provider)
+		return nil
+	}
+}
+
+type EmailServiceInterface interface {
+	SendVerificationEmail(user *models.User, token string) error
+	SendPasswordResetEmail(user *models.User, token string, expiresIn time.Duration) error
+	SendTripInvitationEmail(invitation *models.TripInvitation, inviterName, itineraryTitle string) error
+	SendNotificationEmail(user *models.User, notification *models.Notification) error
+	SendWeeklyDigests(now time.Time) (int, error)
+	StartEmailWorker()
+	StartEmailRetryScheduler(interval time.Duration)
+	StartWeeklyDigestScheduler(interval time.Duration)
+}
+
+// EmailService renderiza os e-mails transacionais e os grava numa fila; um
+// worker os entrega ao provedor, com novas tentativas em caso de falha
+type EmailService struct {
+	emailRepo        repositories.EmailRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	notificationRepo repositories.NotificationRepositoryInterface
+	sender           EmailSender
+	config           *EmailConfig
+	queue            chan uint
+}
+
+func NewEmailService(emailRepo repositories.EmailRepositoryInterface, userRepo repositories.UserRepositoryInterface, notificationRepo repositories.NotificationRepositoryInterface, config *EmailConfig) EmailServiceInterface {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 6
+	}
+	config.AppURL = strings.TrimRight(config.AppURL, "/")
+
+	return &EmailService{
+		emailRepo:        emailRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		sender:           NewEmailSender(config),
+		config:           config,
+		queue:            make(chan uint, emailQueueSize),
+	}
+}
+
+// SendVerificationEmail envia o link de confirmação do e-mail da conta
+func (s *EmailService) SendVerificationEmail(user *models.User, token string) error {
+	_, err := s.enqueue(&user.ID, user.Email, models.EmailTemplateVerification, nil, verificationEmailData{
+		Name: emailDisplayName(user),
+		URL:  s.appLink("/verify-email", url.Values{"token": {token}}),
+	})
+	return err
+}
+
+// SendPasswordResetEmail envia o link de redefinição de senha
+func (s *EmailService) SendPasswordResetEmail(user *models.User, token string, expiresIn time.Duration) error {
+	_, err := s.enqueue(&user.ID, user.Email, models.EmailTemplatePasswordReset, nil, passwordResetEmailData{
+		Name:      emailDisplayName(user),
+		URL:       s.appLink("/reset-password", url.Values{"token": {token}}),
+		ExpiresIn: formatEmailDuration(expiresIn),
+	})
+	return err
+}
+
+// SendTripInvitationEmail avisa quem foi convidado por e-mail e ainda não tem
+// conta; o link leva ao cadastro com o e-mail do convite
+func (s *EmailService) SendTripInvitationEmail(invitation *models.TripInvitation, inviterName, itineraryTitle string) error {
+	role := "espectador"
+	if invitation.Role == models.CollaboratorRoleCollaborator {
+		role = "colaborador"
+	}
+
+	key := fmt.Sprintf("trip_invitation:%d", invitation.ID)
+	_, err := s.enqueue(invitation.InviteeID, invitation.Email, models.EmailTemplateTripInvitation, &key, tripInvitationEmailData{
+		InviterName:    inviterName,
+		ItineraryTitle: itineraryTitle,
+		Role:           role,
+		Message:        invitation.Message,
+		URL:            s.appLink("/signup", url.Values{"email": {invitation.Email}}),
+	})
+	return err
+}
+
+// SendNotificationEmail envia por e-mail uma notificação do app, para quem
+// ativou o canal de e-mail do tipo
+func (s *EmailService) SendNotificationEmail(user *models.User, notification *models.Notification) error {
+	key := fmt.Sprintf("notification:%d", notification.ID)
+	_, err := s.enqueue(&user.ID, user.Email, models.EmailTemplateNotification, &key, notificationEmailData{
+		Name:  emailDisplayName(user),
+		Title: notification.Title,
+		Body:  notification.Body,
+		URL:   s.appLink("/notifications", nil),
+	})
+	return err
+}
+
+// SendWeeklyDigests envia, no dia e hora configurados, o resumo das
+// notificações não lidas da semana; a chave do e-mail garante no máximo um
+// resumo por usuário por semana
+func (s *EmailService) SendWeeklyDigests(now time.Time) (int, error) {
+	if s.sender == nil || now.Weekday() != s.config.DigestWeekday || now.Hour() < s.config.DigestHour {
+		return 0, nil
+	}
+
+	since := now.AddDate(0, 0, -7)
+	userIDs, err := s.notificationRepo.GetUsersWithUnreadSince(since)
+	if err != nil {
+		return 0, errors.New("erro ao buscar usuários para o resumo semanal")
+	}
+
+	year, week := now.ISOWeek()
+	key := fmt.Sprintf("weekly_digest:%d-W%02d", year, week)
+
+	sent := 0
+	for _, userID := range userIDs {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil || !user.IsActive {
+			continue
+		}
+		channels, err := notificationChannels(s.notificationRepo, userID, models.NotificationTypeWeeklyDigest)
+		if err != nil || !channels.Email {
+			continue
+		}
+
+		notifications, unread, err := s.notificationRepo.GetUnreadSince(userID, since, weeklyDigestItems)
+		if err != nil {
+			log.Printf("Falha ao buscar notificações do resumo do usuário %d: %v", userID, err)
+			continue
+		}
+		if unread == 0 {
+			continue
+		}
+
+		created, err := s.enqueue(&user.ID, user.Email, models.EmailTemplateWeeklyDigest, &key, weeklyDigestEmailData{
+			Name:          emailDisplayName(user),
+			UnreadCount:   unread,
+			Notifications: notifications,
+			URL:           s.appLink("/notifications", nil),
+		})
+		if err != nil {
+			log.Printf("Falha ao enfileirar resumo semanal do usuário %d: %v", userID, err)
+			continue
+		}
+		if created {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartWeeklyDigestScheduler verifica periodicamente se chegou a hora do
+// resumo semanal
+func (s *EmailService) StartWeeklyDigestScheduler(interval time.Duration) {
+	if s.sender == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if sent, err := s.SendWeeklyDigests(time.Now()); err != nil {
+				log.Println("Falha ao enviar resumos semanais:", err)
+			} else if sent > 0 {
+				log.Printf("%d resumos semanais enfileirados", sent)
+			}
+		}
+	}()
+}
+
+// enqueue renderiza o e-mail e o grava na fila; com os e-mails desativados
+// não faz nada. Retorna false quando já havia um e-mail com a mesma chave
+func (s *EmailService) enqueue(userID *uint, to string, template models.EmailTemplate, key *string, data interface{}) (bool, error) {
+	if s.sender == nil {
+		return false, nil
+	}
+	if _, err := mail.ParseAddress(to); err != nil {
+		return false, errors.New("endereço de e-mail inválido")
+	}
+
+	subject, text, html, err := renderEmail(template, data)
+	if err != nil {
+		log.Printf("Falha ao renderizar e-mail %s: %v", template, err)
+		return false, errors.New("erro ao preparar e-mail")
+	}
+
+	now := time.Now()
+	message := &models.EmailMessage{
+		UserID:        userID,
+		To:            to,
+		Template:      template,
+		Subject:       subject,
+		HTMLBody:      html,
+		TextBody:      text,
+		Key:           key,
+		Status:        models.EmailStatusPending,
+		NextAttemptAt: &now,
+	}
+	created, err := s.emailRepo.Create(message)
+	if err != nil {
+		return false, errors.New("erro ao enfileirar e-mail")
+	}
+	if created {
+		s.push(message.ID)
+	}
+	return created, nil
+}
+
+// StartEmailWorker envia os e-mails da fila e retoma os que foram
+// interrompidos por uma reinicialização
+func (s *EmailService) StartEmailWorker() {
+	if s.sender == nil {
+		return
+	}
+
+	go func() {
+		for messageID := range s.queue {
+			if err := s.deliver(messageID); err != nil {
+				log.Printf("Falha ao processar e-mail %d: %v", messageID, err)
+			}
+		}
+	}()
+
+	if err := s.emailRepo.ReleaseSending(); err != nil {
+		log.Println("Falha ao liberar e-mails interrompidos:", err)
+	}
+}
+
+// StartEmailRetryScheduler reenfileira os e-mails cuja próxima tentativa
+// venceu, incluindo os que não couberam na fila
+func (s *EmailService) StartEmailRetryScheduler(interval time.Duration) {
+	if s.sender == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.enqueueDue()
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *EmailService) enqueueDue() {
+	ids, err := s.emailRepo.GetDue(time.Now(), emailQueueSize)
+	if err != nil {
+		log.Println("Falha ao buscar e-mails pendentes:", err)
+		return
+	}
+	for _, id := range ids {
+		s.push(id)
+	}
+}
+
+// push não bloqueia a requisição; com a fila cheia o e-mail fica pendente
+// até a próxima passada do agendador
+func (s *EmailService) push(messageID uint) {
+	select {
+	case s.queue <- messageID:
+	default:
+	}
+}
+
+func (s *EmailService) deliver(messageID uint) error {
+	claimed, err := s.emailRepo.Claim(messageID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	message, err := s.emailRepo.GetByID(messageID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	message.NextAttemptAt = nil
+	if err := s.sender.Send(message); err != nil {
+		message.LastError = truncateString(err.Error(), 500)
+		if message.Attempts >= s.config.MaxAttempts {
+			message.Status = models.EmailStatusDeadLetter
+			log.Printf("E-mail %d (%s) desistido após %d tentativas: %v", message.ID, message.Template, message.Attempts, err)
+		} else {
+			next := now.Add(emailRetryDelay(message.Attempts))
+			message.Status = models.EmailStatusFailed
+			message.NextAttemptAt = &next
+		}
+		return s.emailRepo.UpdateResult(message)
+	}
+
+	message.Status = models.EmailStatusSent
+	message.LastError = ""
+	message.SentAt = &now
+	return s.emailRepo.UpdateResult(message)
+}
+
+func emailRetryDelay(attempts int) time.Duration {
+	delay := emailRetryBackoff
+	for i := 1; i < attempts && delay < maxEmailRetryWait; i++ {
+		delay *= 2
+	}
+	return min(delay, maxEmailRetryWait)
+}
+
+func (s *EmailService) appLink(path string, query url.Values) string {
+	link := s.config.AppURL + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+func emailDisplayName(user *models.User) string {
+	if name := strings.TrimSpace(user.FirstName); name != "" {
+		return name
+	}
+	return user.Username
+}
+
+func formatEmailDuration(d time.Duration) string {
+	if hours := int(d.Hours()); hours >= 1 {
+		if hours == 1 {
+			return "1 hora"
+		}
+		return fmt.Sprintf("%d horas", hours)
+	}
+	minutes := max(1, int(d.Minutes()))
+	if minutes == 1 {
+		return "1 minuto"
+	}
+	return fmt.Sprintf("%d minutos", minutes)
+}
+
+// ============================================================================
+// SMTP
+// ============================================================================
+
+type smtpSender struct {
+	config *EmailConfig
+	from   mail.Address
+}
+
+func (s *smtpSender) Name() string { return "smtp" }
+
+func (s *smtpSender) Send(message *models.EmailMessage) error {
+	body, err := buildMIMEMessage(s.from, message)
+	if err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(s.config.SMTPHost, strconv.Itoa(s.config.SMTPPort))
+	dialer := &net.Dialer{Timeout: emailSendTimeout}
+
+	var conn net.Conn
+	if s.config.SMTPPort == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: s.config.SMTPHost})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(emailSendTimeout))
+
+	client, err := smtp.NewClient(conn, s.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && s.config.SMTPPort != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if s.config.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.config.SMTPUsername, s.config.SMTPPassword, s.config.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMIMEMessage monta o e-mail com as versões em texto e HTML
+// (multipart/alternative), em quoted-printable
+func buildMIMEMessage(from mail.Address, message *models.EmailMessage) ([]byte, error) {
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, err
+	}
+	boundary := "guia-" + hex.EncodeToString(boundaryBytes)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%d.%s@%s>\r\n", message.ID, hex.EncodeToString(boundaryBytes[:6]), emailDomain(from.Address))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", message.TextBody},
+		{"text/html", message.HTMLBody},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+func emailDomain(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return address[at+1:]
+	}
+	return "localhost"
+}
+
+// ============================================================================
+// SENDGRID
+// ============================================================================
+
+type sendGridSender struct {
+	apiURL string
+	apiKey string
+	from   mail.Address
+	client *http.Client
+}
+
+func (s *sendGridSender) Name() string { return "sendgrid" }
+
+func (s *sendGridSender) Send(message *models.EmailMessage) error {
+	type address struct {
+		Email string `json:"email"`
+		Name  string `json:"name,omitempty"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []address{{Email: message.To}}},
+		},
+		"from":    address{Email: s.from.Address, Name: s.from.Name},
+		"subject": message.Subject,
+		"content": []content{
+			{Type: "text/plain", Value: message.TextBody},
+			{Type: "text/html", Value: message.HTMLBody},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.apiURL+"/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sendgrid respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// ============================================================================
+// AMAZON SES
+// ============================================================================
+
+type sesSender struct {
+	client *ses.SES
+	from   mail.Address
+}
+
+func (s *sesSender) Name() string { return "ses" }
+
+func (s *sesSender) Send(message *models.EmailMessage) error {
+	_, err := s.client.SendEmail(&ses.SendEmailInput{
+		Source:      aws.String(s.from.String()),
+		Destination: &ses.Destination{ToAddresses: []*string{aws.String(message.To)}},
+		Message: &ses.Message{
+			Subject: &ses.Content{Charset: aws.String("UTF-8"), Data: aws.String(message.Subject)},
+			Body: &ses.Body{
+				Text: &ses.Content{Charset: aws.String("UTF-8"), Data: aws.String(message.TextBody)},
+				Html: &ses.Content{Charset: aws.String("UTF-8"), Data: aws.String(message.HTMLBody)},
+			},
+		},
+	})
+	return err
+}
+
+// ============================================================================
+// LOG (DESENVOLVIMENTO)
+// ============================================================================
+
+type logSender struct{}
+
+func (s *logSender) Name() string { return "log" }
+
+func (s *logSender) Send(message *models.EmailMessage) error {
+	log.Printf("E-mail %s para %s: %s\n%s", message.Template, message.To, message.Subject, message.TextBody)
+	return nil
+}
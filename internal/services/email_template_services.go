@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+// emailTemplate guarda as três partes de um e-mail: assunto e texto puro
+// (text/template) e o HTML, renderizado dentro do layout comum
+type emailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Dados de cada template
+
+type verificationEmailData struct {
+	Name string
+	URL  string
+}
+
+type passwordResetEmailData struct {
+	Name      string
+	URL       string
+	ExpiresIn string
+}
+
+type tripInvitationEmailData struct {
+	InviterName    string
+	ItineraryTitle string
+	Role           string
+	Message        string
+	URL            string
+}
+
+type notificationEmailData struct {
+	Name  string
+	Title string
+	Body  string
+	URL   string
+}
+
+type weeklyDigestEmailData struct {
+	Name          string
+	UnreadCount   int64
+	Notifications []models.Notification
+	URL           string
+}
+
+func (t *emailTemplate) render(data interface{}) (subject, text, html string, err error) {
+	var buf bytes.Buffer
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	// Quebras de linha (ex.: no título do roteiro) não podem chegar ao cabeçalho
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := t.text.Execute(&buf, data); err != nil {
+		return "", "", "", err
+	}
+	text = strings.TrimSpace(buf.String()) + "\n"
+
+	buf.Reset()
+	if err := t.html.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return "", "", "", err
+	}
+	return subject, text, buf.String(), nil
+}
+
+func renderEmail(name models.EmailTemplate, data interface{}) (subject, text, html string, err error) {
+	tmpl, ok := emailTemplates[name]
+	if !ok {
+		return "", "", "", fmt.Errorf("template de e-mail desconhecido: %s", name)
+	}
+	return tmpl.render(data)
+}
+
+func newEmailTemplate(subject, text, content string) *emailTemplate {
+	layout := htmltemplate.Must(htmltemplate.New("layout").Parse(emailLayoutHTML))
+	return &emailTemplate{
+		subject: texttemplate.Must(texttemplate.New("subject").Parse(subject)),
+		text:    texttemplate.Must(texttemplate.New("text").Parse(text)),
+		html:    htmltemplate.Must(layout.New("content").Parse(content)),
+	}
+}
+
+// Estilos inline, pois boa parte dos clientes de e-mail ignora <style>
+const emailLayoutHTML = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body style="margin:0;padding:0;background:#f4f5f7;font-family:Helvetica,Arial,sans-serif;color:#1f2933;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background:#f4f5f7;padding:24px 0;">
+<tr><td align="center">
+<table role="presentation" width="560" cellpadding="0" cellspacing="0" style="max-width:560px;width:100%;background:#ffffff;border-radius:8px;">
+<tr><td style="padding:24px 32px;border-bottom:1px solid #e4e7eb;font-size:22px;font-weight:bold;color:#0b7285;">guIA</td></tr>
+<tr><td style="padding:32px;font-size:16px;line-height:1.5;">{{template "content" .}}</td></tr>
+<tr><td style="padding:16px 32px;border-top:1px solid #e4e7eb;font-size:12px;color:#7b8794;">Você recebeu este e-mail porque tem uma conta ou um convite no guIA.</td></tr>
+</table>
+</td></tr>
+</table>
+</body>
+</html>`
+
+const emailButtonStyle = `display:inline-block;padding:12px 24px;background:#0b7285;color:#ffffff;text-decoration:none;border-radius:6px;font-weight:bold;`
+
+var emailTemplates = map[models.EmailTemplate]*emailTemplate{
+	models.EmailTemplateVerification: newEmailTemplate(
+		`Confirme seu e-mail no guIA`,
+		`Olá, {{.Name}}!
+
+Confirme seu e-mail para ativar todos os recursos da sua conta:
+{{.URL}}
+
+Se você não criou uma conta no guIA, ignore este e-mail.`,
+		`<p>Olá, {{.Name}}!</p>
+<p>Confirme seu e-mail para ativar todos os recursos da sua conta.</p>
+<p style="margin:24px 0;"><a href="{{.URL}}" style="`+emailButtonStyle+`">Confirmar e-mail</a></p>
+<p style="font-size:13px;color:#7b8794;">Se você não criou uma conta no guIA, ignore este e-mail.</p>`,
+	),
+
+	models.EmailTemplatePasswordReset: newEmailTemplate(
+		`Redefinição de senha do guIA`,
+		`Olá, {{.Name}}!
+
+Recebemos um pedido para redefinir a senha da sua conta. Use o link abaixo em até {{.ExpiresIn}}:
+{{.URL}}
+
+Se você não fez o pedido, ignore este e-mail; sua senha continua a mesma.`,
+		`<p>Olá, {{.Name}}!</p>
+<p>Recebemos um pedido para redefinir a senha da sua conta. O link vale por {{.ExpiresIn}}.</p>
+<p style="margin:24px 0;"><a href="{{.URL}}" style="`+emailButtonStyle+`">Redefinir senha</a></p>
+<p style="font-size:13px;color:#7b8794;">Se você não fez o pedido, ignore este e-mail; sua senha continua a mesma.</p>`,
+	),
+
+	models.EmailTemplateTripInvitation: newEmailTemplate(
+		`{{.InviterName}} convidou você para a viagem {{.ItineraryTitle}}`,
+		`{{.InviterName}} convidou você para participar da viagem "{{.ItineraryTitle}}" no guIA como {{.Role}}.
+{{if .Message}}
+"{{.Message}}"
+{{end}}
+Crie sua conta com este e-mail para responder ao convite:
+{{.URL}}`,
+		`<p><strong>{{.InviterName}}</strong> convidou você para participar da viagem <strong>{{.ItineraryTitle}}</strong> no guIA como {{.Role}}.</p>
+{{if .Message}}<p style="padding:12px 16px;background:#f4f5f7;border-radius:6px;font-style:italic;">“{{.Message}}”</p>{{end}}
+<p>Crie sua conta com este e-mail para responder ao convite.</p>
+<p style="margin:24px 0;"><a href="{{.URL}}" style="`+emailButtonStyle+`">Ver convite</a></p>`,
+	),
+
+	models.EmailTemplateNotification: newEmailTemplate(
+		`{{.Title}}`,
+		`Olá, {{.Name}}!
+
+{{.Title}}
+{{if .Body}}{{.Body}}
+{{end}}
+Veja no app: {{.URL}}`,
+		`<p>Olá, {{.Name}}!</p>
+<p><strong>{{.Title}}</strong></p>
+{{if .Body}}<p>{{.Body}}</p>{{end}}
+<p style="margin:24px 0;"><a href="{{.URL}}" style="`+emailButtonStyle+`">Abrir o guIA</a></p>`,
+	),
+
+	models.EmailTemplateWeeklyDigest: newEmailTemplate(
+		`Sua semana no guIA: {{.UnreadCount}} {{if eq .UnreadCount 1}}novidade{{else}}novidades{{end}}`,
+		`Olá, {{.Name}}!
+
+Você tem {{.UnreadCount}} {{if eq .UnreadCount 1}}notificação não lida{{else}}notificações não lidas{{end}} desta semana:
+{{range .Notifications}}
+- {{.Title}}{{if .Body}}: {{.Body}}{{end}}{{end}}
+
+Veja tudo no app: {{.URL}}`,
+		`<p>Olá, {{.Name}}!</p>
+<p>Você tem <strong>{{.UnreadCount}}</strong> {{if eq .UnreadCount 1}}notificação não lida{{else}}notificações não lidas{{end}} desta semana:</p>
+<ul style="padding-left:20px;">{{range .Notifications}}
+<li style="margin-bottom:8px;"><strong>{{.Title}}</strong>{{if .Body}}<br><span style="color:#52606d;">{{.Body}}</span>{{end}}</li>{{end}}
+</ul>
+<p style="margin:24px 0;"><a href="{{.URL}}" style="`+emailButtonStyle+`">Abrir o guIA</a></p>`,
+	),
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+)
+
+// exifOrEmpty lê o EXIF que será descartado; se estiver malformado, a foto
+// segue sem os metadados
+func exifOrEmpty(tiff []byte) *PhotoMetadata {
+	metadata, err := parseExif(tiff)
+	if err != nil {
+		return &PhotoMetadata{}
+	}
+	return metadata
+}
+
+// hasLocationOrDate indica se há algo útil para sugerir ao usuário
+func (m *PhotoMetadata) hasLocationOrDate() bool {
+	return m.Latitude != nil || m.TakenAt != nil
+}
+
+var (
+	errInvalidImage = errors.New("imagem inválida ou corrompida")
+
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	exifHeader   = []byte("Exif\x00\x00")
+)
+
+// stripImageMetadata remove EXIF, XMP e textos embutidos de JPEG, PNG e WebP
+// sem recodificar a imagem, mantendo só a orientação. Bytes anexados depois do
+// fim da imagem também saem. Outros formatos (GIF) não carregam EXIF e voltam
+// inalterados
+func stripImageMetadata(data []byte) ([]byte, *PhotoMetadata, error) {
+	switch {
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return stripJPEGMetadata(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPNGMetadata(data)
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return stripWebPMetadata(data)
+	default:
+		return data, &PhotoMetadata{}, nil
+	}
+}
+
+// ============================================================================
+// JPEG
+// ============================================================================
+
+func stripJPEGMetadata(data []byte) ([]byte, *PhotoMetadata, error) {
+	exif := &PhotoMetadata{}
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	insertAt := len(out)
+
+	i := 2
+	for {
+		if i >= len(data) || data[i] != 0xFF {
+			return nil, nil, errInvalidImage
+		}
+		// Bytes 0xFF extras antes do marcador são preenchimento
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, nil, errInvalidImage
+		}
+		marker := data[i]
+		start := i - 1
+		i++
+
+		// Fim da imagem: o que vier depois (ZIP, script, vídeo anexado) é
+		// descartado
+		if marker == 0xD9 {
+			out = append(out, 0xFF, 0xD9)
+			break
+		}
+		// Marcadores sem tamanho
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, data[start:i]...)
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		length := int(binary.BigEndian.Uint16(data[i : i+2]))
+		end := i + length
+		if length < 2 || end > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		payload := data[i+2 : end]
+		i = end
+
+		switch marker {
+		case 0xE1: // APP1: EXIF ou XMP
+			if bytes.HasPrefix(payload, exifHeader) {
+				exif = exifOrEmpty(payload[len(exifHeader):])
+			}
+			continue
+		case 0xED: // APP13: IPTC (pode ter localização)
+			continue
+		case 0xFE: // Comentário
+			continue
+		case 0xDA: // Início dos dados da imagem, que seguem até o próximo marcador
+			next := jpegScanEnd(data, end)
+			out = append(out, data[start:next]...)
+			i = next
+			if next == len(data) {
+				return out, exif, nil
+			}
+			continue
+		case 0xE0: // APP0 (JFIF) precisa continuar primeiro
+			out = append(out, data[start:end]...)
+			if insertAt == 2 {
+				insertAt = len(out)
+			}
+			continue
+		}
+		out = append(out, data[start:end]...)
+	}
+
+	if exif.Orientation > 1 {
+		tiff := minimalTIFF(exif.Orientation)
+		segment := make([]byte, 0, 4+len(exifHeader)+len(tiff))
+		segment = append(segment, 0xFF, 0xE1)
+		segment = binary.BigEndian.AppendUint16(segment, uint16(2+len(exifHeader)+len(tiff)))
+		segment = append(segment, exifHeader...)
+		segment = append(segment, tiff...)
+		out = append(out[:insertAt], append(segment, out[insertAt:]...)...)
+	}
+
+	return out, exif, nil
+}
+
+// jpegScanEnd acha o fim dos dados comprimidos que começam em pos: o primeiro
+// 0xFF que não é escape (0xFF00), reinício (RST0-7) ou preenchimento
+func jpegScanEnd(data []byte, pos int) int {
+	for pos < len(data)-1 {
+		if data[pos] != 0xFF {
+			pos++
+			continue
+		}
+		next := data[pos+1]
+		if next == 0x00 || next == 0xFF || (next >= 0xD0 && next <= 0xD7) {
+			pos++
+			continue
+		}
+		return pos
+	}
+	return len(data)
+}
+
+// ============================================================================
+// PNG
+// ============================================================================
+
+func stripPNGMetadata(data []byte) ([]byte, *PhotoMetadata, error) {
+	exif := &PhotoMetadata{}
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	insertAt := 0
+
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		chunk := data[i:end]
+		i = end
+
+		switch chunkType {
+		case "eXIf":
+			exif = exifOrEmpty(chunk[8 : 8+length])
+			continue
+		case "tEXt", "zTXt", "iTXt":
+			continue
+		}
+		out = append(out, chunk...)
+		if chunkType == "IHDR" {
+			insertAt = len(out)
+		}
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	if exif.Orientation > 1 && insertAt > 0 {
+		tiff := minimalTIFF(exif.Orientation)
+		chunk := binary.BigEndian.AppendUint32(nil, uint32(len(tiff)))
+		chunk = append(chunk, "eXIf"...)
+		chunk = append(chunk, tiff...)
+		chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+		out = append(out[:insertAt], append(chunk, out[insertAt:]...)...)
+	}
+
+	return out, exif, nil
+}
+
+// ============================================================================
+// WebP
+// ============================================================================
+
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+func stripWebPMetadata(data []byte) ([]byte, *PhotoMetadata, error) {
+	exif := &PhotoMetadata{}
+	var chunks [][]byte
+	vp8x := -1
+
+	// Bytes além do tamanho declarado no RIFF são descartados
+	riffEnd := 8 + int(binary.LittleEndian.Uint32(data[4:8]))
+	if riffEnd > len(data) {
+		riffEnd = len(data)
+	}
+	data = data[:riffEnd]
+
+	i := 12
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if end > len(data) {
+			if i+8+size != len(data) {
+				return nil, nil, errInvalidImage
+			}
+			end = len(data)
+		}
+		chunk := data[i:end]
+		i = end
+
+		switch fourCC {
+		case "EXIF":
+			payload := bytes.TrimPrefix(chunk[8:8+size], exifHeader)
+			exif = exifOrEmpty(payload)
+			continue
+		case "XMP ":
+			continue
+		case "VP8X":
+			vp8x = len(chunks)
+			chunk = append([]byte(nil), chunk...)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	keepOrientation := exif.Orientation > 1 && vp8x >= 0
+	if vp8x >= 0 && len(chunks[vp8x]) > 8 {
+		flags := chunks[vp8x][8] &^ (webpFlagXMP | webpFlagEXIF)
+		if keepOrientation {
+			flags |= webpFlagEXIF
+		}
+		chunks[vp8x][8] = flags
+	}
+	if keepOrientation {
+		tiff := minimalTIFF(exif.Orientation)
+		chunk := append([]byte("EXIF"), binary.LittleEndian.AppendUint32(nil, uint32(len(tiff)))...)
+		chunk = append(chunk, tiff...)
+		if len(tiff)%2 == 1 {
+			chunk = append(chunk, 0)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+
+	return out, exif, nil
+}
+
+// ============================================================================
+// ORIENTAÇÃO
+// ============================================================================
+
+// minimalTIFF monta um EXIF só com a orientação
+func minimalTIFF(orientation int) []byte {
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x0112) // Orientation
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, uint16(orientation))
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	return tiff
+}
+
+// applyOrientation gira/espelha os pixels conforme a orientação do EXIF, para
+// as variantes (que não carregam EXIF) ficarem na posição certa
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for dy := 0; dy < dstH; dy++ {
+		for dx := 0; dx < dstW; dx++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-dx, dy
+			case 3:
+				sx, sy = w-1-dx, h-1-dy
+			case 4:
+				sx, sy = dx, h-1-dy
+			case 5:
+				sx, sy = dy, dx
+			case 6:
+				sx, sy = dy, h-1-dx
+			case 7:
+				sx, sy = w-1-dy, h-1-dx
+			case 8:
+				sx, sy = w-1-dy, dx
+			}
+			i := src.PixOffset(bounds.Min.X+sx, bounds.Min.Y+sy)
+			copy(dst.Pix[dst.PixOffset(dx, dy):], src.Pix[i:i+4])
+		}
+	}
+	return dst
+}
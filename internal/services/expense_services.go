@@ -0,0 +1,492 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type CreateExpenseRequest struct {
+	Description string                 `json:"description" binding:"required,max=200"`
+	Amount      float64                `json:"amount" binding:"required,gt=0"`
+	Currency    string                 `json:"currency"`
+	Category    models.ExpenseCategory `json:"category"`
+	PayerID     *uint                  `json:"payer_id"` // padrão: quem registra
+	DayID       *uint                  `json:"day_id"`
+	SplitWith   []uint                 `json:"split_with"` // vazio: todos os participantes
+	SpentAt     *time.Time             `json:"spent_at"`
+}
+
+type ExpenseCategoryComparison struct {
+	Category   models.ExpenseCategory `json:"category"`
+	Spent      float64                `json:"spent"`
+	Estimated  float64                `json:"estimated"`
+	Difference float64                `json:"difference"`
+}
+
+// ExpenseMemberBalance é a situação de um participante: Balance positivo
+// significa que ele tem a receber, negativo que deve aos demais
+type ExpenseMemberBalance struct {
+	User    *models.UserResponse `json:"user"`
+	Paid    float64              `json:"paid"`
+	Share   float64              `json:"share"`
+	Balance float64              `json:"balance"`
+}
+
+type ExpenseSettlement struct {
+	FromUserID uint    `json:"from_user_id"`
+	ToUserID   uint    `json:"to_user_id"`
+	Amount     float64 `json:"amount"`
+}
+
+// ExpenseSummary compara os gastos reais com o orçamento estimado do roteiro
+// (ver BudgetService) e calcula quem deve quanto a quem
+type ExpenseSummary struct {
+	ItineraryID  uint                        `json:"itinerary_id"`
+	Currency     string                      `json:"currency"`
+	ExpenseCount int                         `json:"expense_count"`
+	Total        float64                     `json:"total"`
+	Estimated    float64                     `json:"estimated"`
+	Difference   float64                     `json:"difference"`
+	Categories   []ExpenseCategoryComparison `json:"categories"`
+	Members      []ExpenseMemberBalance      `json:"members"`
+	Settlements  []ExpenseSettlement         `json:"settlements"`
+}
+
+type ExpenseServiceInterface interface {
+	AddExpense(itineraryID, userID uint, req *CreateExpenseRequest) (*models.ExpenseResponse, error)
+	GetExpenses(itineraryID, userID uint, category string, limit, offset int) ([]*models.ExpenseResponse, error)
+	DeleteExpense(itineraryID, expenseID, userID uint) error
+	GetSummary(itineraryID, userID uint, currency string) (*ExpenseSummary, error)
+}
+
+type ExpenseService struct {
+	expenseRepo     repositories.ExpenseRepositoryInterface
+	itineraryRepo   repositories.ItineraryRepositoryInterface
+	userRepo        repositories.UserRepositoryInterface
+	currencyService CurrencyServiceInterface
+}
+
+func NewExpenseService(
+	expenseRepo repositories.ExpenseRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	currencyService CurrencyServiceInterface,
+) ExpenseServiceInterface {
+	return &ExpenseService{
+		expenseRepo:     expenseRepo,
+		itineraryRepo:   itineraryRepo,
+		userRepo:        userRepo,
+		currencyService: currencyService,
+	}
+}
+
+func (s *ExpenseService) AddExpense(itineraryID, userID uint, req *CreateExpenseRequest) (*models.ExpenseResponse, error) {
+	itinerary, members, err := s.memberItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCreateExpenseRequest(req); err != nil {
+		return nil, err
+	}
+
+	payerID := userID
+	if req.PayerID != nil {
+		payerID = *req.PayerID
+	}
+	if !members[payerID] {
+		return nil, errors.New("quem pagou deve participar do roteiro")
+	}
+
+	splitWith := make([]uint, 0, len(req.SplitWith))
+	seen := make(map[uint]bool, len(req.SplitWith))
+	for _, id := range req.SplitWith {
+		if seen[id] {
+			continue
+		}
+		if !members[id] {
+			return nil, fmt.Errorf("o usuário %d não participa do roteiro", id)
+		}
+		seen[id] = true
+		splitWith = append(splitWith, id)
+	}
+	if len(splitWith) == 0 {
+		// A divisão fica congelada com os participantes do momento, para que
+		// a entrada de um novo colaborador não altere gastos já registrados
+		for id := range members {
+			splitWith = append(splitWith, id)
+		}
+		sort.Slice(splitWith, func(i, j int) bool { return splitWith[i] < splitWith[j] })
+	}
+
+	if req.DayID != nil {
+		found := false
+		for i := range itinerary.Days {
+			if itinerary.Days[i].ID == *req.DayID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("dia não encontrado neste roteiro")
+		}
+	}
+
+	currency := normalizeCurrency(req.Currency)
+	if currency == "" {
+		currency = normalizeCurrency(itinerary.Currency)
+	}
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	category := req.Category
+	if category == "" {
+		category = models.ExpenseCategoryOther
+	}
+
+	spentAt := time.Now()
+	if req.SpentAt != nil {
+		spentAt = *req.SpentAt
+	}
+
+	expense := &models.Expense{
+		ItineraryID: itineraryID,
+		DayID:       req.DayID,
+		PayerID:     payerID,
+		CreatedByID: userID,
+		Description: strings.TrimSpace(req.Description),
+		Amount:      roundCents(req.Amount),
+		Currency:    currency,
+		Category:    category,
+		SplitWith:   splitWith,
+		SpentAt:     spentAt,
+	}
+
+	if err := s.expenseRepo.Create(expense); err != nil {
+		return nil, errors.New("erro ao registrar despesa")
+	}
+
+	created, err := s.expenseRepo.GetByID(expense.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar despesa registrada")
+	}
+
+	return created.ToResponse(), nil
+}
+
+func (s *ExpenseService) GetExpenses(itineraryID, userID uint, category string, limit, offset int) ([]*models.ExpenseResponse, error) {
+	if _, _, err := s.memberItinerary(itineraryID, userID); err != nil {
+		return nil, err
+	}
+
+	expenseCategory := models.ExpenseCategory(category)
+	if category != "" && !isValidExpenseCategory(expenseCategory) {
+		return nil, errors.New("categoria inválida")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	expenses, err := s.expenseRepo.GetByItinerary(itineraryID, expenseCategory, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar despesas")
+	}
+
+	responses := make([]*models.ExpenseResponse, len(expenses))
+	for i := range expenses {
+		responses[i] = expenses[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// DeleteExpense pode ser feito por quem registrou, por quem pagou ou pelo
+// autor do roteiro
+func (s *ExpenseService) DeleteExpense(itineraryID, expenseID, userID uint) error {
+	itinerary, _, err := s.memberItinerary(itineraryID, userID)
+	if err != nil {
+		return err
+	}
+
+	expense, err := s.expenseRepo.GetByID(expenseID)
+	if err != nil || expense.ItineraryID != itineraryID {
+		return errors.New("despesa não encontrada")
+	}
+
+	if expense.CreatedByID != userID && expense.PayerID != userID && itinerary.AuthorID != userID {
+		return errors.New("você não tem permissão para remover esta despesa")
+	}
+
+	if err := s.expenseRepo.Delete(expenseID); err != nil {
+		return errors.New("erro ao remover despesa")
+	}
+
+	return nil
+}
+
+func (s *ExpenseService) GetSummary(itineraryID, userID uint, currency string) (*ExpenseSummary, error) {
+	itinerary, members, err := s.memberItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := rollupBudget(itinerary)
+
+	currency = normalizeCurrency(currency)
+	if currency == "" {
+		currency = budget.Currency
+	} else if !isValidCurrency(currency) {
+		return nil, errors.New("moeda inválida: use um código ISO 4217, como BRL ou USD")
+	}
+	if currency != budget.Currency {
+		rate, err := s.rate(budget.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		convertBudget(budget, rate, currency)
+	}
+
+	expenses, err := s.expenseRepo.GetAllByItinerary(itineraryID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar despesas")
+	}
+
+	summary := &ExpenseSummary{
+		ItineraryID:  itineraryID,
+		Currency:     currency,
+		ExpenseCount: len(expenses),
+		Estimated:    budget.Total,
+		Members:      []ExpenseMemberBalance{},
+		Settlements:  []ExpenseSettlement{},
+	}
+
+	// Os cálculos da divisão são feitos em centavos, para que a soma das
+	// partes feche exatamente com o valor pago
+	spent := make(map[models.ExpenseCategory]int64)
+	paid := make(map[uint]int64)
+	share := make(map[uint]int64)
+	for _, expense := range expenses {
+		amount := expense.Amount
+		if expense.Currency != currency {
+			rate, err := s.rate(expense.Currency, currency)
+			if err != nil {
+				return nil, err
+			}
+			amount *= rate
+		}
+		cents := int64(math.Round(amount * 100))
+
+		spent[expense.Category] += cents
+		paid[expense.PayerID] += cents
+		for id, part := range splitCents(cents, expense.SplitWith) {
+			share[id] += part
+		}
+	}
+
+	var total int64
+	for _, category := range budgetCategories {
+		expenseCategory := models.ExpenseCategory(category)
+		comparison := ExpenseCategoryComparison{
+			Category: expenseCategory,
+			Spent:    float64(spent[expenseCategory]) / 100,
+		}
+		for _, estimated := range budget.Categories {
+			if estimated.Category == category {
+				comparison.Estimated = estimated.Amount
+			}
+		}
+		comparison.Difference = roundCents(comparison.Spent - comparison.Estimated)
+		summary.Categories = append(summary.Categories, comparison)
+		total += spent[expenseCategory]
+	}
+	summary.Total = float64(total) / 100
+	summary.Difference = roundCents(summary.Total - summary.Estimated)
+
+	// Participantes atuais e também quem já saiu mas ainda tem gastos
+	// registrados entram no balanço
+	userIDs := make([]uint, 0, len(members))
+	for id := range members {
+		userIDs = append(userIDs, id)
+	}
+	for id := range paid {
+		if !members[id] {
+			members[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	for id := range share {
+		if !members[id] {
+			members[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	balances := make(map[uint]int64, len(userIDs))
+	for _, id := range userIDs {
+		balance := ExpenseMemberBalance{
+			Paid:    float64(paid[id]) / 100,
+			Share:   float64(share[id]) / 100,
+			Balance: float64(paid[id]-share[id]) / 100,
+		}
+		if user, err := s.userRepo.GetByID(id); err == nil {
+			balance.User = user.ToResponse()
+		} else {
+			balance.User = &models.UserResponse{ID: id}
+		}
+		summary.Members = append(summary.Members, balance)
+		balances[id] = paid[id] - share[id]
+	}
+
+	summary.Settlements = settleBalances(userIDs, balances)
+
+	return summary, nil
+}
+
+// memberItinerary carrega o roteiro e o conjunto de participantes (autor e
+// colaboradores). As despesas são privadas: quem não participa recebe o
+// mesmo erro de um roteiro inexistente
+func (s *ExpenseService) memberItinerary(itineraryID, userID uint) (*models.Itinerary, map[uint]bool, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, nil, errors.New("roteiro não encontrado")
+	}
+
+	collaborators, err := s.expenseRepo.GetCollaborators(itineraryID)
+	if err != nil {
+		return nil, nil, errors.New("erro ao buscar participantes")
+	}
+
+	members := map[uint]bool{itinerary.AuthorID: true}
+	for _, collaborator := range collaborators {
+		members[collaborator.ID] = true
+	}
+
+	if !members[userID] {
+		return nil, nil, errors.New("roteiro não encontrado")
+	}
+
+	return itinerary, members, nil
+}
+
+// rate retorna o fator para converter valores de from para to
+func (s *ExpenseService) rate(from, to string) (float64, error) {
+	fromRate, okFrom := s.currencyService.RateFor(from)
+	toRate, okTo := s.currencyService.RateFor(to)
+	if !okFrom || !okTo {
+		return 0, fmt.Errorf("cotação indisponível para converter %s em %s", from, to)
+	}
+	return toRate / fromRate, nil
+}
+
+// splitCents divide o valor igualmente; os centavos que sobram vão, um a um,
+// para os primeiros da lista
+func splitCents(cents int64, userIDs []uint) map[uint]int64 {
+	parts := make(map[uint]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return parts
+	}
+
+	count := int64(len(userIDs))
+	base := cents / count
+	remainder := cents % count
+	for i, id := range userIDs {
+		parts[id] = base
+		if int64(i) < remainder {
+			parts[id]++
+		}
+	}
+	return parts
+}
+
+// settleBalances sugere os pagamentos para zerar os saldos, quitando sempre
+// o maior devedor com o maior credor, o que mantém o número de transferências
+// pequeno
+func settleBalances(userIDs []uint, balances map[uint]int64) []ExpenseSettlement {
+	type entry struct {
+		userID uint
+		amount int64
+	}
+
+	var debtors, creditors []entry
+	for _, id := range userIDs {
+		switch balance := balances[id]; {
+		case balance < 0:
+			debtors = append(debtors, entry{id, -balance})
+		case balance > 0:
+			creditors = append(creditors, entry{id, balance})
+		}
+	}
+
+	byAmount := func(entries []entry) func(i, j int) bool {
+		return func(i, j int) bool {
+			if entries[i].amount != entries[j].amount {
+				return entries[i].amount > entries[j].amount
+			}
+			return entries[i].userID < entries[j].userID
+		}
+	}
+	sort.Slice(debtors, byAmount(debtors))
+	sort.Slice(creditors, byAmount(creditors))
+
+	settlements := []ExpenseSettlement{}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := debtors[i].amount
+		if creditors[j].amount < amount {
+			amount = creditors[j].amount
+		}
+
+		settlements = append(settlements, ExpenseSettlement{
+			FromUserID: debtors[i].userID,
+			ToUserID:   creditors[j].userID,
+			Amount:     float64(amount) / 100,
+		})
+
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount == 0 {
+			i++
+		}
+		if creditors[j].amount == 0 {
+			j++
+		}
+	}
+
+	return settlements
+}
+
+// Funções de validação
+
+func validateCreateExpenseRequest(req *CreateExpenseRequest) error {
+	if strings.TrimSpace(req.Description) == "" {
+		return errors.New("descrição é obrigatória")
+	}
+	if req.Amount <= 0 {
+		return errors.New("valor deve ser maior que zero")
+	}
+	if req.Currency != "" && !isValidCurrency(normalizeCurrency(req.Currency)) {
+		return errors.New("moeda inválida: use um código ISO 4217, como BRL ou USD")
+	}
+	if req.Category != "" && !isValidExpenseCategory(req.Category) {
+		return errors.New("categoria inválida")
+	}
+	return nil
+}
+
+func isValidExpenseCategory(category models.ExpenseCategory) bool {
+	for _, valid := range budgetCategories {
+		if models.ExpenseCategory(valid) == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,526 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type ExperienceServiceInterface interface {
+	CreateExperience(guideID uint, req *ExperienceRequest) (*models.ExperienceResponse, error)
+	UpdateExperience(experienceID, guideID uint, req *ExperienceRequest) (*models.ExperienceResponse, error)
+	DeleteExperience(experienceID, guideID uint) error
+	GetExperienceByID(experienceID, currentUserID uint) (*models.ExperienceResponse, error)
+	SearchExperiences(country, city string, limit, offset int) ([]models.ExperienceResponse, error)
+	GetMyExperiences(guideID uint, limit, offset int) ([]models.ExperienceResponse, error)
+	AddSlots(experienceID, guideID uint, req *AddSlotsRequest) ([]models.ExperienceSlotResponse, error)
+	GetAvailability(experienceID uint, from, to time.Time) ([]models.ExperienceSlotResponse, error)
+	CancelSlot(experienceID, slotID, guideID uint) error
+	RequestBooking(experienceID, userID uint, req *CreateBookingRequest) (*models.BookingRequestResponse, error)
+	UpdateBookingStatus(bookingID, userID uint, req *UpdateBookingStatusRequest) (*models.BookingRequestResponse, error)
+	GetMyBookings(userID uint, limit, offset int) ([]models.BookingRequestResponse, error)
+	GetReceivedBookings(guideID uint, status models.BookingStatus, limit, offset int) ([]models.BookingRequestResponse, error)
+}
+
+type ExperienceRequest struct {
+	Title           string   `json:"title" binding:"required"`
+	Description     string   `json:"description"`
+	Country         string   `json:"country" binding:"required"`
+	City            string   `json:"city"`
+	MeetingPoint    string   `json:"meeting_point"`
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	GooglePlaceID   string   `json:"google_place_id"`
+	Price           float64  `json:"price"`
+	Currency        string   `json:"currency"`
+	DurationMinutes int      `json:"duration_minutes"`
+	Capacity        int      `json:"capacity" binding:"required"`
+	Languages       []string `json:"languages"`
+	Images          []string `json:"images"`
+	IsActive        *bool    `json:"is_active"`
+}
+
+type SlotRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Capacity int       `json:"capacity"` // 0 usa a capacidade padrão da experiência
+}
+
+type AddSlotsRequest struct {
+	Slots []SlotRequest `json:"slots" binding:"required,dive"`
+}
+
+type CreateBookingRequest struct {
+	SlotID       uint   `json:"slot_id" binding:"required"`
+	Participants int    `json:"participants" binding:"required"`
+	Message      string `json:"message"`
+}
+
+type UpdateBookingStatusRequest struct {
+	Status models.BookingStatus `json:"status" binding:"required"`
+}
+
+type ExperienceService struct {
+	experienceRepo      repositories.ExperienceRepositoryInterface
+	userRepo            repositories.UserRepositoryInterface
+	geoService          GeoServiceInterface
+	conversationService ConversationServiceInterface
+}
+
+func NewExperienceService(
+	experienceRepo repositories.ExperienceRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	geoService GeoServiceInterface,
+	conversationService ConversationServiceInterface,
+) ExperienceServiceInterface {
+	return &ExperienceService{
+		experienceRepo:      experienceRepo,
+		userRepo:            userRepo,
+		geoService:          geoService,
+		conversationService: conversationService,
+	}
+}
+
+func (s *ExperienceService) CreateExperience(guideID uint, req *ExperienceRequest) (*models.ExperienceResponse, error) {
+	if err := s.checkVerifiedGuide(guideID); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateExperienceRequest(req); err != nil {
+		return nil, err
+	}
+
+	experience := &models.Experience{GuideID: guideID, IsActive: true}
+	s.applyExperienceRequest(experience, req)
+
+	if err := s.experienceRepo.Create(experience); err != nil {
+		return nil, errors.New("erro ao criar experiência")
+	}
+
+	return experience.ToResponse(), nil
+}
+
+func (s *ExperienceService) UpdateExperience(experienceID, guideID uint, req *ExperienceRequest) (*models.ExperienceResponse, error) {
+	experience, err := s.getOwnExperience(experienceID, guideID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateExperienceRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.applyExperienceRequest(experience, req)
+
+	if err := s.experienceRepo.Update(experience); err != nil {
+		return nil, errors.New("erro ao atualizar experiência")
+	}
+
+	return experience.ToResponse(), nil
+}
+
+func (s *ExperienceService) DeleteExperience(experienceID, guideID uint) error {
+	if _, err := s.getOwnExperience(experienceID, guideID); err != nil {
+		return err
+	}
+
+	if err := s.experienceRepo.Delete(experienceID); err != nil {
+		return errors.New("erro ao deletar experiência")
+	}
+
+	return nil
+}
+
+func (s *ExperienceService) GetExperienceByID(experienceID, currentUserID uint) (*models.ExperienceResponse, error) {
+	experience, err := s.experienceRepo.GetByID(experienceID)
+	if err != nil || (!experience.IsActive && experience.GuideID != currentUserID) {
+		return nil, errors.New("experiência não encontrada")
+	}
+
+	return experience.ToResponse(), nil
+}
+
+func (s *ExperienceService) SearchExperiences(country, city string, limit, offset int) ([]models.ExperienceResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	// Usar o código do país quando ele for reconhecido nos dados de referência
+	countryCode := ""
+	if country != "" {
+		countryCode = s.geoService.NormalizeLocation(country, "", "").CountryCode
+	}
+
+	experiences, err := s.experienceRepo.Search(countryCode, country, city, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar experiências")
+	}
+
+	var responses []models.ExperienceResponse
+	for _, experience := range experiences {
+		responses = append(responses, *experience.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *ExperienceService) GetMyExperiences(guideID uint, limit, offset int) ([]models.ExperienceResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	experiences, err := s.experienceRepo.GetByGuide(guideID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar experiências")
+	}
+
+	var responses []models.ExperienceResponse
+	for _, experience := range experiences {
+		responses = append(responses, *experience.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *ExperienceService) AddSlots(experienceID, guideID uint, req *AddSlotsRequest) ([]models.ExperienceSlotResponse, error) {
+	experience, err := s.getOwnExperience(experienceID, guideID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Slots) == 0 || len(req.Slots) > 100 {
+		return nil, errors.New("informe entre 1 e 100 horários por vez")
+	}
+
+	now := time.Now()
+	slots := make([]models.ExperienceSlot, 0, len(req.Slots))
+	for _, slotReq := range req.Slots {
+		if !slotReq.EndsAt.After(slotReq.StartsAt) {
+			return nil, errors.New("horário de término deve ser posterior ao de início")
+		}
+		if slotReq.StartsAt.Before(now) {
+			return nil, errors.New("não é possível criar horários no passado")
+		}
+		if slotReq.Capacity < 0 {
+			return nil, errors.New("capacidade do horário inválida")
+		}
+
+		capacity := slotReq.Capacity
+		if capacity == 0 {
+			capacity = experience.Capacity
+		}
+
+		slots = append(slots, models.ExperienceSlot{
+			ExperienceID: experience.ID,
+			StartsAt:     slotReq.StartsAt,
+			EndsAt:       slotReq.EndsAt,
+			Capacity:     capacity,
+		})
+	}
+
+	if err := s.experienceRepo.CreateSlots(slots); err != nil {
+		return nil, errors.New("erro ao criar horários")
+	}
+
+	var responses []models.ExperienceSlotResponse
+	for _, slot := range slots {
+		responses = append(responses, *slot.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *ExperienceService) GetAvailability(experienceID uint, from, to time.Time) ([]models.ExperienceSlotResponse, error) {
+	if _, err := s.experienceRepo.GetByID(experienceID); err != nil {
+		return nil, errors.New("experiência não encontrada")
+	}
+
+	if from.IsZero() {
+		from = time.Now()
+	}
+	if to.IsZero() || !to.After(from) {
+		to = from.AddDate(0, 1, 0)
+	}
+	if to.Sub(from) > 180*24*time.Hour {
+		return nil, errors.New("período máximo de consulta é de 180 dias")
+	}
+
+	slots, err := s.experienceRepo.GetSlots(experienceID, from, to)
+	if err != nil {
+		return nil, errors.New("erro ao buscar disponibilidade")
+	}
+
+	var responses []models.ExperienceSlotResponse
+	for _, slot := range slots {
+		responses = append(responses, *slot.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *ExperienceService) CancelSlot(experienceID, slotID, guideID uint) error {
+	if _, err := s.getOwnExperience(experienceID, guideID); err != nil {
+		return err
+	}
+
+	slot, err := s.experienceRepo.GetSlotByID(slotID)
+	if err != nil || slot.ExperienceID != experienceID {
+		return errors.New("horário não encontrado")
+	}
+
+	if err := s.experienceRepo.CancelSlot(slotID); err != nil {
+		return errors.New("erro ao cancelar horário")
+	}
+
+	return nil
+}
+
+// RequestBooking cria o pedido de reserva e abre (ou reutiliza) a conversa
+// direta com o guia, enviando o resumo do pedido como primeira mensagem
+func (s *ExperienceService) RequestBooking(experienceID, userID uint, req *CreateBookingRequest) (*models.BookingRequestResponse, error) {
+	experience, err := s.experienceRepo.GetByID(experienceID)
+	if err != nil || !experience.IsActive {
+		return nil, errors.New("experiência não encontrada")
+	}
+
+	if experience.GuideID == userID {
+		return nil, errors.New("não é possível reservar a própria experiência")
+	}
+
+	slot, err := s.experienceRepo.GetSlotByID(req.SlotID)
+	if err != nil || slot.ExperienceID != experienceID || slot.IsCancelled {
+		return nil, errors.New("horário não encontrado")
+	}
+
+	if slot.StartsAt.Before(time.Now()) {
+		return nil, errors.New("horário já passou")
+	}
+
+	if req.Participants <= 0 {
+		return nil, errors.New("número de participantes inválido")
+	}
+
+	if slot.BookedCount+req.Participants > slot.Capacity {
+		return nil, errors.New("não há vagas suficientes neste horário")
+	}
+
+	booking := &models.BookingRequest{
+		ExperienceID: experienceID,
+		SlotID:       slot.ID,
+		UserID:       userID,
+		GuideID:      experience.GuideID,
+		Participants: req.Participants,
+		Message:      strings.TrimSpace(req.Message),
+		Status:       models.BookingStatusPending,
+	}
+
+	conversation, err := s.conversationService.StartDirectConversation(userID, experience.GuideID)
+	if err == nil {
+		booking.ConversationID = &conversation.ID
+	}
+
+	if err := s.experienceRepo.CreateBooking(booking); err != nil {
+		return nil, errors.New("erro ao solicitar reserva")
+	}
+
+	if booking.ConversationID != nil {
+		summary := fmt.Sprintf("Pedido de reserva: %s em %s para %d pessoa(s).",
+			experience.Title, slot.StartsAt.Format("02/01/2006 15:04"), booking.Participants)
+		if booking.Message != "" {
+			summary += "\n" + booking.Message
+		}
+		s.conversationService.SendMessage(*booking.ConversationID, userID, &SendMessageRequest{Content: summary})
+	}
+
+	booking.Experience = *experience
+	booking.Slot = *slot
+	return booking.ToResponse(), nil
+}
+
+// UpdateBookingStatus permite ao guia aceitar ou recusar pedidos pendentes e a
+// qualquer uma das partes cancelar um pedido pendente ou aceito
+func (s *ExperienceService) UpdateBookingStatus(bookingID, userID uint, req *UpdateBookingStatusRequest) (*models.BookingRequestResponse, error) {
+	booking, err := s.experienceRepo.GetBookingByID(bookingID)
+	if err != nil || (booking.UserID != userID && booking.GuideID != userID) {
+		return nil, errors.New("reserva não encontrada")
+	}
+
+	switch req.Status {
+	case models.BookingStatusAccepted, models.BookingStatusDeclined:
+		if booking.GuideID != userID {
+			return nil, errors.New("você não tem permissão para responder a esta reserva")
+		}
+		if booking.Status != models.BookingStatusPending {
+			return nil, errors.New("apenas reservas pendentes podem ser respondidas")
+		}
+	case models.BookingStatusCancelled:
+		if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusAccepted {
+			return nil, errors.New("esta reserva não pode ser cancelada")
+		}
+	default:
+		return nil, errors.New("status de reserva inválido")
+	}
+
+	if err := s.experienceRepo.UpdateBookingStatus(booking, req.Status); err != nil {
+		if errors.Is(err, repositories.ErrSlotFull) {
+			return nil, errors.New("não há vagas suficientes neste horário")
+		}
+		return nil, errors.New("erro ao atualizar reserva")
+	}
+
+	if booking.ConversationID != nil {
+		s.conversationService.SendMessage(*booking.ConversationID, userID, &SendMessageRequest{
+			Content: fmt.Sprintf("Reserva de %s: %s.", booking.Experience.Title, bookingStatusLabel(req.Status)),
+		})
+	}
+
+	updated, err := s.experienceRepo.GetBookingByID(bookingID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar reserva atualizada")
+	}
+
+	return updated.ToResponse(), nil
+}
+
+func (s *ExperienceService) GetMyBookings(userID uint, limit, offset int) ([]models.BookingRequestResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	bookings, err := s.experienceRepo.GetBookingsByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar reservas")
+	}
+
+	var responses []models.BookingRequestResponse
+	for _, booking := range bookings {
+		responses = append(responses, *booking.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *ExperienceService) GetReceivedBookings(guideID uint, status models.BookingStatus, limit, offset int) ([]models.BookingRequestResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	bookings, err := s.experienceRepo.GetBookingsByGuide(guideID, status, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar reservas")
+	}
+
+	var responses []models.BookingRequestResponse
+	for _, booking := range bookings {
+		responses = append(responses, *booking.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *ExperienceService) checkVerifiedGuide(userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	if user.UserType != models.UserTypeCompany && user.UserType != models.UserTypeAdmin {
+		return errors.New("você não tem permissão para publicar experiências")
+	}
+
+	if !user.IsVerified {
+		return errors.New("você não tem permissão para publicar experiências: conta ainda não verificada")
+	}
+
+	return nil
+}
+
+func (s *ExperienceService) getOwnExperience(experienceID, guideID uint) (*models.Experience, error) {
+	experience, err := s.experienceRepo.GetByID(experienceID)
+	if err != nil {
+		return nil, errors.New("experiência não encontrada")
+	}
+
+	if experience.GuideID != guideID {
+		return nil, errors.New("você não tem permissão para alterar esta experiência")
+	}
+
+	return experience, nil
+}
+
+func (s *ExperienceService) applyExperienceRequest(experience *models.Experience, req *ExperienceRequest) {
+	location := s.geoService.NormalizeLocation(req.Country, "", req.City)
+
+	experience.Title = strings.TrimSpace(req.Title)
+	experience.Description = strings.TrimSpace(req.Description)
+	experience.Country = location.Country
+	experience.City = location.City
+	experience.CountryCode = location.CountryCode
+	experience.CityID = location.CityID
+	experience.MeetingPoint = strings.TrimSpace(req.MeetingPoint)
+	experience.Latitude = req.Latitude
+	experience.Longitude = req.Longitude
+	experience.GooglePlaceID = req.GooglePlaceID
+	experience.Price = req.Price
+	experience.Currency = "BRL"
+	if req.Currency != "" {
+		experience.Currency = strings.ToUpper(req.Currency)
+	}
+	experience.DurationMinutes = req.DurationMinutes
+	experience.Capacity = req.Capacity
+	experience.Languages = req.Languages
+	experience.Images = req.Images
+	if req.IsActive != nil {
+		experience.IsActive = *req.IsActive
+	}
+}
+
+func bookingStatusLabel(status models.BookingStatus) string {
+	switch status {
+	case models.BookingStatusAccepted:
+		return "aceita"
+	case models.BookingStatusDeclined:
+		return "recusada"
+	case models.BookingStatusCancelled:
+		return "cancelada"
+	default:
+		return "pendente"
+	}
+}
+
+// Funções de validação
+func (s *ExperienceService) validateExperienceRequest(req *ExperienceRequest) error {
+	if len(strings.TrimSpace(req.Title)) < 3 {
+		return errors.New("título deve ter pelo menos 3 caracteres")
+	}
+
+	if len(req.Title) > 200 {
+		return errors.New("título deve ter no máximo 200 caracteres")
+	}
+
+	if req.Price < 0 {
+		return errors.New("preço não pode ser negativo")
+	}
+
+	if req.Currency != "" && len(req.Currency) != 3 {
+		return errors.New("moeda inválida")
+	}
+
+	if req.Capacity <= 0 {
+		return errors.New("capacidade deve ser maior que zero")
+	}
+
+	if req.DurationMinutes < 0 {
+		return errors.New("duração inválida")
+	}
+
+	if len(req.Images) > 10 {
+		return errors.New("máximo de 10 imagens por experiência")
+	}
+
+	return nil
+}
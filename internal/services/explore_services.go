@@ -0,0 +1,114 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+const (
+	exploreCacheTTL      = 5 * time.Minute
+	exploreSectionLimit  = 10
+	exploreAnonymousUser = 0 // seções em cache são montadas sem personalização
+)
+
+type ExploreServiceInterface interface {
+	GetExplore(userID uint) (*models.ExploreResponse, error)
+}
+
+type ExploreService struct {
+	postService      PostServiceInterface
+	itineraryService ItineraryServiceInterface
+
+	mu        sync.Mutex
+	cached    *models.ExploreResponse
+	expiresAt time.Time
+}
+
+func NewExploreService(postService PostServiceInterface, itineraryService ItineraryServiceInterface) ExploreServiceInterface {
+	return &ExploreService{
+		postService:      postService,
+		itineraryService: itineraryService,
+	}
+}
+
+// GetExplore monta a aba Explorar com posts em alta, roteiros em destaque e
+// destinos em alta. As seções ficam em cache por alguns minutos e apenas as
+// curtidas do usuário são aplicadas a cada requisição.
+func (s *ExploreService) GetExplore(userID uint) (*models.ExploreResponse, error) {
+	snapshot, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ExploreResponse{
+		TrendingPosts:        make([]models.PostResponse, len(snapshot.TrendingPosts)),
+		FeaturedItineraries:  snapshot.FeaturedItineraries,
+		TrendingDestinations: snapshot.TrendingDestinations,
+		GeneratedAt:          snapshot.GeneratedAt,
+	}
+	copy(response.TrendingPosts, snapshot.TrendingPosts)
+
+	postIDs := make([]uint, len(response.TrendingPosts))
+	for i, post := range response.TrendingPosts {
+		postIDs[i] = post.ID
+	}
+	liked, err := s.postService.GetLikedPostIDs(userID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range response.TrendingPosts {
+		response.TrendingPosts[i].IsLiked = liked[response.TrendingPosts[i].ID]
+	}
+
+	return response, nil
+}
+
+func (s *ExploreService) snapshot() (*models.ExploreResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Now().Before(s.expiresAt) {
+		return s.cached, nil
+	}
+
+	posts, _, err := s.postService.GetTrendingPosts(exploreAnonymousUser, "", exploreSectionLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	itineraries, err := s.itineraryService.GetItineraries(&ItineraryFilters{
+		IsFeatured: true,
+		Limit:      exploreSectionLimit,
+	}, exploreAnonymousUser)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations, err := s.itineraryService.GetTrendingDestinations(exploreSectionLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.ExploreResponse{
+		TrendingPosts:        posts,
+		FeaturedItineraries:  itineraries,
+		TrendingDestinations: destinations,
+		GeneratedAt:          time.Now(),
+	}
+	if snapshot.TrendingPosts == nil {
+		snapshot.TrendingPosts = []models.PostResponse{}
+	}
+	if snapshot.FeaturedItineraries == nil {
+		snapshot.FeaturedItineraries = []models.ItineraryResponse{}
+	}
+	if snapshot.TrendingDestinations == nil {
+		snapshot.TrendingDestinations = []models.TrendingDestination{}
+	}
+
+	s.cached = snapshot
+	s.expiresAt = snapshot.GeneratedAt.Add(exploreCacheTTL)
+
+	return snapshot, nil
+}
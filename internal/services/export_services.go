@@ -0,0 +1,1129 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	exportQueueSize    = 100
+	exportCleanupBatch = 100
+	maxExportImageSize = 10 * 1024 * 1024
+	// Imagens maiores que isso nem são decodificadas (bombas de descompressão)
+	maxExportImagePixels = 25_000_000
+)
+
+type ExportConfig struct {
+	// Serviço HTML→PDF compatível com o Gotenberg (URL completa da rota de
+	// conversão); vazio usa o gerador interno
+	PDFRendererURL string
+	// Modelo de URL das miniaturas de mapa com {lat} e {lng}; vazio desativa
+	StaticMapURL string
+	// Roteiros com mais locais que isso são gerados em segundo plano
+	SyncMaxLocations int
+	// Dias que os arquivos gerados ficam disponíveis
+	RetentionDays int
+}
+
+// ItineraryDocument é o conteúdo do roteiro já formatado para impressão,
+// independente do formato do arquivo
+type ItineraryDocument struct {
+	Title       string
+	Description string
+	Author      string
+	Category    string
+	Destination string
+	Duration    int
+	Difficulty  int
+	Cost        string
+	CoverURL    string
+	Days        []DocumentDay
+	GeneratedAt time.Time
+}
+
+type DocumentDay struct {
+	Number      int
+	Title       string
+	Description string
+	Cost        string
+	Locations   []DocumentLocation
+}
+
+type DocumentLocation struct {
+	Name        string
+	Type        string
+	Address     string
+	Description string
+	Schedule    string
+	Cost        string
+	Website     string
+	Phone       string
+	MapURL      string
+}
+
+// ExportResult traz o arquivo pronto (roteiros pequenos) ou a exportação em
+// segundo plano que o cliente deve acompanhar
+type ExportResult struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+	Export      *models.ItineraryExport
+}
+
+type PDFRendererInterface interface {
+	Name() string
+	Render(doc *ItineraryDocument) ([]byte, error)
+}
+
+// NewPDFRenderer escolhe o serviço de renderização: o externo quando
+// configurado ou o gerador interno, que só depende da biblioteca padrão.
+// loadImage lê as mídias da própria plataforma; fora delas, só o servidor
+// das miniaturas de mapa configurado é acessado
+func NewPDFRenderer(config *ExportConfig, loadImage func(url string) (image.Image, error)) PDFRendererInterface {
+	if config.PDFRendererURL != "" {
+		return &htmlPDFRenderer{
+			endpoint: config.PDFRendererURL,
+			client:   &http.Client{Timeout: 60 * time.Second},
+		}
+	}
+
+	renderer := &builtinPDFRenderer{loadImage: loadImage}
+	if mapURL, err := url.Parse(config.StaticMapURL); err == nil && mapURL.Host != "" {
+		renderer.mapScheme, renderer.mapHost = mapURL.Scheme, mapURL.Host
+		renderer.client = &http.Client{
+			Timeout: 10 * time.Second,
+			// Redirecionamentos poderiam levar a outro servidor
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	return renderer
+}
+
+type ExportServiceInterface interface {
+	ExportPDF(itineraryID, userID uint) (*ExportResult, error)
+	ExportICS(itineraryID, userID uint, startDate time.Time) (*ExportResult, error)
+	ExportTrack(itineraryID, userID uint, format models.ExportFormat) (*ExportResult, error)
+	GetGeoJSON(itineraryID, userID uint) (*GeoJSONFeatureCollection, error)
+	GetExport(itineraryID, exportID, userID uint) (*models.ItineraryExport, error)
+	StartExportWorker()
+	StartExportCleanupScheduler(interval time.Duration)
+}
+
+type ExportService struct {
+	exportRepo    repositories.ExportRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	geoService    GeoServiceInterface
+	mediaService  MediaServiceInterface
+	pdfRenderer   PDFRendererInterface
+	config        *ExportConfig
+	jobs          chan uint
+}
+
+func NewExportService(exportRepo repositories.ExportRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface, geoService GeoServiceInterface, mediaService MediaServiceInterface, pdfRenderer PDFRendererInterface, config *ExportConfig) ExportServiceInterface {
+	return &ExportService{
+		exportRepo:    exportRepo,
+		itineraryRepo: itineraryRepo,
+		geoService:    geoService,
+		mediaService:  mediaService,
+		pdfRenderer:   pdfRenderer,
+		config:        config,
+		jobs:          make(chan uint, exportQueueSize),
+	}
+}
+
+// ExportPDF gera o PDF na hora para roteiros pequenos; os grandes entram na
+// fila e o cliente acompanha pelo GetExport
+func (s *ExportService) ExportPDF(itineraryID, userID uint) (*ExportResult, error) {
+	itinerary, err := s.getExportableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if countLocations(itinerary) <= s.config.SyncMaxLocations {
+		data, err := s.pdfRenderer.Render(s.buildDocument(itinerary))
+		if err != nil {
+			log.Printf("Falha ao gerar PDF do roteiro %d (%s): %v", itinerary.ID, s.pdfRenderer.Name(), err)
+			return nil, errors.New("erro ao gerar PDF do roteiro")
+		}
+		return &ExportResult{
+			FileName:    exportFileName(itinerary, models.ExportFormatPDF),
+			ContentType: "application/pdf",
+			Data:        data,
+		}, nil
+	}
+
+	if export, err := s.exportRepo.GetReusable(itinerary.ID, userID, models.ExportFormatPDF, itinerary.UpdatedAt); err == nil {
+		return &ExportResult{Export: export}, nil
+	}
+
+	export := &models.ItineraryExport{
+		ItineraryID: itinerary.ID,
+		UserID:      userID,
+		Format:      models.ExportFormatPDF,
+		Status:      models.ExportStatusPending,
+		Version:     itinerary.UpdatedAt,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, errors.New("erro ao criar exportação")
+	}
+
+	s.enqueue(export.ID)
+	return &ExportResult{Export: export}, nil
+}
+
+func (s *ExportService) GetExport(itineraryID, exportID, userID uint) (*models.ItineraryExport, error) {
+	export, err := s.exportRepo.GetByID(exportID)
+	if err != nil || export.ItineraryID != itineraryID {
+		return nil, errors.New("exportação não encontrada")
+	}
+	if export.UserID != userID {
+		return nil, errors.New("você não tem permissão para ver esta exportação")
+	}
+	return export, nil
+}
+
+// StartExportWorker gera os arquivos em segundo plano, retomando as
+// exportações interrompidas por uma reinicialização
+func (s *ExportService) StartExportWorker() {
+	go func() {
+		for exportID := range s.jobs {
+			if err := s.processExport(exportID); err != nil {
+				log.Printf("Falha ao processar exportação %d: %v", exportID, err)
+			}
+		}
+	}()
+
+	exports, err := s.exportRepo.GetUnfinished()
+	if err != nil {
+		log.Println("Falha ao buscar exportações pendentes:", err)
+		return
+	}
+	for _, export := range exports {
+		s.enqueue(export.ID)
+	}
+}
+
+// StartExportCleanupScheduler remove os arquivos gerados após o período de
+// retenção
+func (s *ExportService) StartExportCleanupScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			removed := s.cleanupExports()
+			if removed > 0 {
+				log.Printf("%d exportações de roteiro expiradas removidas", removed)
+			}
+		}
+	}()
+}
+
+func (s *ExportService) cleanupExports() int {
+	before := time.Now().AddDate(0, 0, -s.config.RetentionDays)
+	removed := 0
+	for {
+		exports, err := s.exportRepo.GetOlderThan(before, exportCleanupBatch)
+		if err != nil {
+			log.Println("Falha ao buscar exportações expiradas:", err)
+			return removed
+		}
+
+		for _, export := range exports {
+			if export.FilePath != "" {
+				if err := s.mediaService.DeleteFile(export.FilePath); err != nil {
+					log.Printf("Falha ao remover arquivo da exportação %d: %v", export.ID, err)
+				}
+			}
+			if err := s.exportRepo.Delete(export.ID); err != nil {
+				log.Printf("Falha ao remover exportação %d: %v", export.ID, err)
+				return removed
+			}
+			removed++
+		}
+
+		if len(exports) < exportCleanupBatch {
+			return removed
+		}
+	}
+}
+
+func (s *ExportService) enqueue(exportID uint) {
+	select {
+	case s.jobs <- exportID:
+	default:
+		go func() { s.jobs <- exportID }()
+	}
+}
+
+func (s *ExportService) processExport(exportID uint) error {
+	export, err := s.exportRepo.GetByID(exportID)
+	if err != nil {
+		return err
+	}
+	if export.Status == models.ExportStatusReady || export.Status == models.ExportStatusFailed {
+		return nil
+	}
+
+	now := time.Now()
+	export.StartedAt = &now
+	export.Status = models.ExportStatusRunning
+	if err := s.exportRepo.Update(export); err != nil {
+		return err
+	}
+
+	filePath, fileURL, size, renderErr := s.renderExport(export)
+
+	finished := time.Now()
+	export.FinishedAt = &finished
+	if renderErr != nil {
+		export.Status = models.ExportStatusFailed
+		export.Error = truncateString(renderErr.Error(), 500)
+	} else {
+		export.Status = models.ExportStatusReady
+		export.FilePath = filePath
+		export.FileURL = fileURL
+		export.FileSize = size
+	}
+	return s.exportRepo.Update(export)
+}
+
+func (s *ExportService) renderExport(export *models.ItineraryExport) (string, string, int64, error) {
+	itinerary, err := s.getExportableItinerary(export.ItineraryID, export.UserID)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	data, err := s.pdfRenderer.Render(s.buildDocument(itinerary))
+	if err != nil {
+		log.Printf("Falha ao gerar PDF do roteiro %d (%s): %v", itinerary.ID, s.pdfRenderer.Name(), err)
+		return "", "", 0, errors.New("erro ao gerar PDF do roteiro")
+	}
+
+	filePath, fileURL, err := s.mediaService.StoreGeneratedFile(data, export.UserID, "exports", ".pdf", "application/pdf")
+	if err != nil {
+		return "", "", 0, errors.New("erro ao salvar PDF do roteiro")
+	}
+	return filePath, fileURL, int64(len(data)), nil
+}
+
+// getExportableItinerary aplica as mesmas regras de acesso da visualização:
+// roteiros privados só podem ser exportados pelo autor
+func (s *ExportService) getExportableItinerary(itineraryID, userID uint) (*models.Itinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if !itinerary.IsPublic && itinerary.AuthorID != userID {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	return itinerary, nil
+}
+
+// dayLocation resolve o fuso do dia como a visualização do roteiro: o do
+// próprio dia, o da coordenada de um dos locais ou o do roteiro
+func dayLocation(geoService GeoServiceInterface, itinerary *models.Itinerary, day *models.ItineraryDay) *time.Location {
+	timezone := day.Timezone
+	if timezone == "" {
+		for _, location := range day.Locations {
+			if location.Latitude == nil || location.Longitude == nil {
+				continue
+			}
+			if timezone = geoService.TimezoneAt(*location.Latitude, *location.Longitude); timezone != "" {
+				break
+			}
+		}
+	}
+	if timezone == "" {
+		timezone = itinerary.Timezone
+	}
+
+	if loc, err := time.LoadLocation(timezone); err == nil && timezone != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+func (s *ExportService) buildDocument(itinerary *models.Itinerary) *ItineraryDocument {
+	var destination []string
+	for _, part := range []string{itinerary.City, itinerary.State, itinerary.Country} {
+		if part != "" {
+			destination = append(destination, part)
+		}
+	}
+
+	doc := &ItineraryDocument{
+		Title:       itinerary.Title,
+		Description: itinerary.Description,
+		Author:      itinerary.Author.Username,
+		Category:    string(itinerary.Category),
+		Destination: strings.Join(destination, ", "),
+		Duration:    itinerary.Duration,
+		Difficulty:  itinerary.Difficulty,
+		Cost:        formatCost(itinerary.EstimatedCost, itinerary.Currency),
+		GeneratedAt: time.Now(),
+	}
+	// Só capas enviadas à plataforma vão para o documento: os renderizadores
+	// não buscam URLs informadas livremente pelo autor
+	if itinerary.CoverImage != "" && s.mediaService.IsStoredMedia(itinerary.CoverImage) {
+		doc.CoverURL = itinerary.CoverImage
+	}
+	if itinerary.Author.FirstName != "" {
+		doc.Author = strings.TrimSpace(itinerary.Author.FirstName + " " + itinerary.Author.LastName)
+	}
+
+	for _, day := range sortedDays(itinerary) {
+		docDay := DocumentDay{
+			Number:      day.DayNumber,
+			Title:       day.Title,
+			Description: day.Description,
+			Cost:        formatCost(day.EstimatedCost, itinerary.Currency),
+		}
+
+		dayLoc := dayLocation(s.geoService, itinerary, &day)
+
+		for _, location := range sortedLocations(&day) {
+			docDay.Locations = append(docDay.Locations, DocumentLocation{
+				Name:        location.Name,
+				Type:        locationTypeLabel(location.LocationType),
+				Address:     location.Address,
+				Description: location.Description,
+				Schedule:    formatSchedule(location.StartTime, location.EndTime, dayLoc),
+				Cost:        formatCost(location.EstimatedCost, itinerary.Currency),
+				Website:     location.Website,
+				Phone:       location.Phone,
+				MapURL:      s.staticMapURL(location.Latitude, location.Longitude),
+			})
+		}
+		doc.Days = append(doc.Days, docDay)
+	}
+
+	return doc
+}
+
+func (s *ExportService) staticMapURL(latitude, longitude *float64) string {
+	if s.config.StaticMapURL == "" || latitude == nil || longitude == nil {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{lat}", strconv.FormatFloat(*latitude, 'f', 6, 64),
+		"{lng}", strconv.FormatFloat(*longitude, 'f', 6, 64),
+	).Replace(s.config.StaticMapURL)
+}
+
+func sortedDays(itinerary *models.Itinerary) []models.ItineraryDay {
+	days := append([]models.ItineraryDay(nil), itinerary.Days...)
+	sort.SliceStable(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+	return days
+}
+
+func sortedLocations(day *models.ItineraryDay) []models.ItineraryLocation {
+	locations := append([]models.ItineraryLocation(nil), day.Locations...)
+	sort.SliceStable(locations, func(i, j int) bool { return locations[i].Order < locations[j].Order })
+	return locations
+}
+
+func countLocations(itinerary *models.Itinerary) int {
+	total := 0
+	for _, day := range itinerary.Days {
+		total += len(day.Locations)
+	}
+	return total
+}
+
+func exportFileName(itinerary *models.Itinerary, format models.ExportFormat) string {
+	var name strings.Builder
+	for _, r := range strings.ToLower(itinerary.Title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			name.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_':
+			name.WriteRune('-')
+		}
+	}
+	slug := strings.Trim(name.String(), "-")
+	if slug == "" {
+		slug = "roteiro-" + strconv.FormatUint(uint64(itinerary.ID), 10)
+	}
+	return truncateString(slug, 80) + "." + string(format)
+}
+
+func formatCost(cost *float64, currency string) string {
+	if cost == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %.2f", currency, *cost)
+}
+
+func formatSchedule(start, end *time.Time, loc *time.Location) string {
+	switch {
+	case start != nil && end != nil:
+		return start.In(loc).Format("15:04") + " - " + end.In(loc).Format("15:04")
+	case start != nil:
+		return start.In(loc).Format("15:04")
+	default:
+		return ""
+	}
+}
+
+func locationTypeLabel(locationType models.LocationType) string {
+	switch locationType {
+	case models.LocationTypeHotel:
+		return "Hospedagem"
+	case models.LocationTypeRestaurant:
+		return "Restaurante"
+	case models.LocationTypeAttraction:
+		return "Atração"
+	case models.LocationTypeTransport:
+		return "Transporte"
+	case models.LocationTypeShopping:
+		return "Compras"
+	default:
+		return "Outro"
+	}
+}
+
+// ExportTrack gera o GPX ou KML com os locais georreferenciados do roteiro
+// GetGeoJSON retorna os locais e percursos do roteiro como FeatureCollection;
+// userID 0 (sem login, em embeds) só acessa roteiros públicos
+func (s *ExportService) GetGeoJSON(itineraryID, userID uint) (*GeoJSONFeatureCollection, error) {
+	itinerary, err := s.getExportableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return buildGeoJSON(itinerary), nil
+}
+
+func (s *ExportService) ExportTrack(itineraryID, userID uint, format models.ExportFormat) (*ExportResult, error) {
+	itinerary, err := s.getExportableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var contentType string
+	switch format {
+	case models.ExportFormatGPX:
+		data, err = encodeGPX(itinerary)
+		contentType = "application/gpx+xml"
+	case models.ExportFormatKML:
+		data, err = encodeKML(itinerary)
+		contentType = "application/vnd.google-earth.kml+xml"
+	default:
+		return nil, errors.New("formato de exportação inválido")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportResult{
+		FileName:    exportFileName(itinerary, format),
+		ContentType: contentType,
+		Data:        data,
+	}, nil
+}
+
+// ============================================================================
+// CALENDÁRIO (ICS)
+// ============================================================================
+
+// ExportICS gera um evento para cada local com horário, na data do seu dia a
+// partir do início da viagem; dias sem locais com horário viram eventos de dia
+// inteiro
+func (s *ExportService) ExportICS(itineraryID, userID uint, startDate time.Time) (*ExportResult, error) {
+	if startDate.IsZero() {
+		return nil, errors.New("data de início da viagem é obrigatória")
+	}
+
+	itinerary, err := s.getExportableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var cal icsWriter
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//guIA//Roteiros//PT")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.property("X-WR-CALNAME", itinerary.Title)
+
+	for _, day := range sortedDays(itinerary) {
+		loc := dayLocation(s.geoService, itinerary, &day)
+		year, month, dayOfMonth := startDate.AddDate(0, 0, day.DayNumber-1).Date()
+		date := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, loc)
+
+		dayTitle := fmt.Sprintf("Dia %d", day.DayNumber)
+		if day.Title != "" {
+			dayTitle += " — " + day.Title
+		}
+
+		timed := 0
+		for _, location := range sortedLocations(&day) {
+			if location.StartTime == nil {
+				continue
+			}
+			timed++
+
+			start := atClock(date, *location.StartTime, loc)
+			end := start.Add(time.Hour)
+			if location.EndTime != nil {
+				end = atClock(date, *location.EndTime, loc)
+				if !end.After(start) {
+					end = end.AddDate(0, 0, 1)
+				}
+			}
+
+			var description []string
+			description = append(description, dayTitle+" · "+locationTypeLabel(location.LocationType))
+			if location.Description != "" {
+				description = append(description, location.Description)
+			}
+			if cost := formatCost(location.EstimatedCost, itinerary.Currency); cost != "" {
+				description = append(description, "Custo estimado: "+cost)
+			}
+			if location.Phone != "" {
+				description = append(description, location.Phone)
+			}
+
+			cal.line("BEGIN:VEVENT")
+			cal.property("UID", fmt.Sprintf("itinerary-%d-location-%d@guia", itinerary.ID, location.ID))
+			cal.line("DTSTAMP:" + now.Format("20060102T150405Z"))
+			cal.line("DTSTART:" + start.UTC().Format("20060102T150405Z"))
+			cal.line("DTEND:" + end.UTC().Format("20060102T150405Z"))
+			cal.property("SUMMARY", location.Name)
+			if location.Address != "" {
+				cal.property("LOCATION", location.Address)
+			}
+			if location.Latitude != nil && location.Longitude != nil {
+				cal.line(fmt.Sprintf("GEO:%.6f;%.6f", *location.Latitude, *location.Longitude))
+			}
+			if location.Website != "" {
+				cal.line("URL:" + location.Website)
+			}
+			cal.property("DESCRIPTION", strings.Join(description, "\n"))
+			cal.line("END:VEVENT")
+		}
+
+		if timed == 0 {
+			cal.line("BEGIN:VEVENT")
+			cal.property("UID", fmt.Sprintf("itinerary-%d-day-%d@guia", itinerary.ID, day.ID))
+			cal.line("DTSTAMP:" + now.Format("20060102T150405Z"))
+			cal.line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+			cal.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+			cal.property("SUMMARY", itinerary.Title+" · "+dayTitle)
+			if day.Description != "" {
+				cal.property("DESCRIPTION", day.Description)
+			}
+			cal.line("END:VEVENT")
+		}
+	}
+
+	cal.line("END:VCALENDAR")
+
+	return &ExportResult{
+		FileName:    exportFileName(itinerary, models.ExportFormatICS),
+		ContentType: "text/calendar; charset=utf-8",
+		Data:        cal.buf.Bytes(),
+	}, nil
+}
+
+// atClock aplica o horário do local (hora e minuto no fuso do dia) à data
+func atClock(date, clock time.Time, loc *time.Location) time.Time {
+	clock = clock.In(loc)
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+}
+
+// icsWriter escreve as linhas do iCalendar (RFC 5545) com CRLF, dobrando as
+// que passam de 75 bytes
+type icsWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *icsWriter) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		limit = 74 // o espaço da continuação conta no tamanho da linha
+	}
+	w.buf.WriteString(content + "\r\n")
+}
+
+// property escreve uma propriedade de texto, escapando os caracteres
+// reservados e as quebras de linha
+func (w *icsWriter) property(name, value string) {
+	value = strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	).Replace(value)
+	w.line(name + ":" + value)
+}
+
+// ============================================================================
+// RENDERIZAÇÃO EXTERNA (HTML → PDF)
+// ============================================================================
+
+var itineraryHTMLTemplate = template.Must(template.New("itinerary").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; margin: 0 24px; }
+.cover { width: 100%; max-height: 320px; object-fit: cover; border-radius: 8px; }
+.meta { color: #666; font-size: 13px; }
+.day { page-break-inside: avoid; margin-top: 28px; }
+.location { margin: 12px 0 0 12px; page-break-inside: avoid; }
+.location .details { color: #555; font-size: 12px; }
+.map { width: 240px; height: 120px; margin-top: 6px; border-radius: 4px; }
+footer { color: #999; font-size: 11px; margin-top: 32px; }
+</style>
+</head>
+<body>
+{{if .CoverURL}}<img class="cover" src="{{.CoverURL}}">{{end}}
+<h1>{{.Title}}</h1>
+<p class="meta">{{if .Destination}}{{.Destination}} · {{end}}{{.Duration}} dia(s) · dificuldade {{.Difficulty}}/5{{if .Cost}} · custo estimado {{.Cost}}{{end}}{{if .Author}} · por {{.Author}}{{end}}</p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{range .Days}}
+<div class="day">
+<h2>Dia {{.Number}}{{if .Title}} — {{.Title}}{{end}}</h2>
+{{if .Cost}}<p class="meta">Custo estimado: {{.Cost}}</p>{{end}}
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{range .Locations}}
+<div class="location">
+<strong>{{.Name}}</strong> <span class="meta">({{.Type}}{{if .Schedule}}, {{.Schedule}}{{end}})</span>
+<div class="details">
+{{if .Address}}{{.Address}}<br>{{end}}
+{{if .Cost}}Custo estimado: {{.Cost}}<br>{{end}}
+{{if .Phone}}{{.Phone}}<br>{{end}}
+{{if .Website}}{{.Website}}<br>{{end}}
+</div>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .MapURL}}<img class="map" src="{{.MapURL}}">{{end}}
+</div>
+{{end}}
+</div>
+{{end}}
+<footer>Gerado pelo guIA em {{.GeneratedAt.Format "02/01/2006 15:04"}}</footer>
+</body>
+</html>
+`))
+
+// htmlPDFRenderer envia o roteiro em HTML para um serviço de conversão
+// (ex.: Gotenberg), que também baixa a capa e os mapas
+type htmlPDFRenderer struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (r *htmlPDFRenderer) Name() string { return "html" }
+
+func (r *htmlPDFRenderer) Render(doc *ItineraryDocument) ([]byte, error) {
+	var html bytes.Buffer
+	if err := itineraryHTMLTemplate.Execute(&html, doc); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("files", "index.html")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(html.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Post(r.endpoint, form.FormDataContentType(), &body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("serviço de PDF respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ============================================================================
+// RENDERIZAÇÃO INTERNA
+// ============================================================================
+
+const (
+	pdfPageWidth  = 595.28 // A4 em pontos
+	pdfPageHeight = 841.89
+	pdfMargin     = 50.0
+)
+
+// builtinPDFRenderer escreve o PDF diretamente, com as fontes padrão do
+// formato (Helvetica) e as imagens recodificadas em JPEG
+type builtinPDFRenderer struct {
+	loadImage func(url string) (image.Image, error)
+
+	// Servidor das miniaturas de mapa (STATIC_MAP_URL), o único externo
+	mapScheme string
+	mapHost   string
+	client    *http.Client
+}
+
+func (r *builtinPDFRenderer) Name() string { return "builtin" }
+
+func (r *builtinPDFRenderer) Render(doc *ItineraryDocument) ([]byte, error) {
+	pdf := newPDFWriter()
+
+	if doc.CoverURL != "" {
+		pdf.image(r.platformImage(doc.CoverURL), pdfPageWidth-2*pdfMargin, 240)
+	}
+
+	pdf.paragraph(doc.Title, 22, true, 0)
+	var meta []string
+	if doc.Destination != "" {
+		meta = append(meta, doc.Destination)
+	}
+	meta = append(meta, fmt.Sprintf("%d dia(s)", doc.Duration), fmt.Sprintf("dificuldade %d/5", doc.Difficulty))
+	if doc.Cost != "" {
+		meta = append(meta, "custo estimado "+doc.Cost)
+	}
+	if doc.Author != "" {
+		meta = append(meta, "por "+doc.Author)
+	}
+	pdf.paragraph(strings.Join(meta, " · "), 10, false, 0)
+	pdf.space(8)
+	pdf.paragraph(doc.Description, 11, false, 0)
+
+	for _, day := range doc.Days {
+		pdf.space(16)
+		pdf.ensureSpace(60)
+		heading := fmt.Sprintf("Dia %d", day.Number)
+		if day.Title != "" {
+			heading += " — " + day.Title
+		}
+		pdf.paragraph(heading, 15, true, 0)
+		if day.Cost != "" {
+			pdf.paragraph("Custo estimado: "+day.Cost, 10, false, 0)
+		}
+		pdf.paragraph(day.Description, 11, false, 0)
+
+		for _, location := range day.Locations {
+			pdf.space(8)
+			pdf.ensureSpace(40)
+			title := location.Name + " (" + location.Type
+			if location.Schedule != "" {
+				title += ", " + location.Schedule
+			}
+			pdf.paragraph(title+")", 12, true, 12)
+			pdf.paragraph(location.Address, 10, false, 12)
+			if location.Cost != "" {
+				pdf.paragraph("Custo estimado: "+location.Cost, 10, false, 12)
+			}
+			pdf.paragraph(location.Phone, 10, false, 12)
+			pdf.paragraph(location.Website, 10, false, 12)
+			pdf.paragraph(location.Description, 10, false, 12)
+			if location.MapURL != "" {
+				pdf.image(r.mapImage(location.MapURL), 240, 120)
+			}
+		}
+	}
+
+	pdf.footer("Gerado pelo guIA em " + doc.GeneratedAt.Format("02/01/2006 15:04"))
+	return pdf.bytes(), nil
+}
+
+// platformImage lê uma mídia da plataforma pelo storage; uma imagem
+// indisponível é omitida do PDF
+func (r *builtinPDFRenderer) platformImage(mediaURL string) image.Image {
+	if r.loadImage == nil {
+		return nil
+	}
+	img, err := r.loadImage(mediaURL)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// mapImage baixa a miniatura de mapa, recusando qualquer servidor que não
+// seja o configurado em STATIC_MAP_URL
+func (r *builtinPDFRenderer) mapImage(rawURL string) image.Image {
+	if r.client == nil {
+		return nil
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Scheme != r.mapScheme || target.Host != r.mapHost {
+		return nil
+	}
+
+	resp, err := r.client.Get(target.String())
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxExportImageSize+1))
+	if err != nil || len(data) > maxExportImageSize {
+		return nil
+	}
+	return decodeBoundedImage(data, maxExportImagePixels)
+}
+
+// decodeBoundedImage lê as dimensões do cabeçalho antes de decodificar, para
+// não alocar os pixels de uma imagem desproporcional ao arquivo
+func decodeBoundedImage(data []byte, maxPixels int64) image.Image {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+type pdfImage struct {
+	data          []byte
+	width, height int
+}
+
+// pdfWriter monta as páginas de cima para baixo, quebrando a página quando o
+// conteúdo não cabe
+type pdfWriter struct {
+	pages  []*bytes.Buffer
+	images []pdfImage
+	y      float64
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.newPage()
+	return w
+}
+
+func (w *pdfWriter) page() *bytes.Buffer {
+	return w.pages[len(w.pages)-1]
+}
+
+func (w *pdfWriter) newPage() {
+	w.pages = append(w.pages, &bytes.Buffer{})
+	w.y = pdfPageHeight - pdfMargin
+}
+
+func (w *pdfWriter) ensureSpace(height float64) {
+	if w.y-height < pdfMargin {
+		w.newPage()
+	}
+}
+
+func (w *pdfWriter) space(height float64) {
+	w.y -= height
+}
+
+// paragraph escreve o texto quebrando as linhas pela largura estimada da
+// Helvetica
+func (w *pdfWriter) paragraph(text string, size float64, bold bool, indent float64) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	font := "F1"
+	charWidth := 0.5 * size
+	if bold {
+		font = "F2"
+		charWidth = 0.56 * size
+	}
+	maxChars := int((pdfPageWidth - 2*pdfMargin - indent) / charWidth)
+	lineHeight := size * 1.35
+
+	for _, block := range strings.Split(text, "\n") {
+		for _, line := range wrapText(block, maxChars) {
+			w.ensureSpace(lineHeight)
+			w.y -= lineHeight
+			fmt.Fprintf(w.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, pdfMargin+indent, w.y, pdfString(line))
+		}
+	}
+}
+
+// image desenha a imagem reduzida para caber na área informada
+func (w *pdfWriter) image(img image.Image, maxWidth, maxHeight float64) {
+	if img == nil {
+		return
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return
+	}
+
+	// Fundo branco para imagens com transparência
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Over)
+
+	var data bytes.Buffer
+	if err := jpeg.Encode(&data, rgba, &jpeg.Options{Quality: 80}); err != nil {
+		return
+	}
+
+	scale := maxWidth / float64(bounds.Dx())
+	if h := float64(bounds.Dy()) * scale; h > maxHeight {
+		scale = maxHeight / float64(bounds.Dy())
+	}
+	width := float64(bounds.Dx()) * scale
+	height := float64(bounds.Dy()) * scale
+
+	w.images = append(w.images, pdfImage{data: data.Bytes(), width: bounds.Dx(), height: bounds.Dy()})
+	w.ensureSpace(height + 6)
+	w.y -= height + 6
+	fmt.Fprintf(w.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, pdfMargin, w.y, len(w.images))
+}
+
+// footer numera as páginas depois que todo o conteúdo foi escrito
+func (w *pdfWriter) footer(text string) {
+	for i, page := range w.pages {
+		line := fmt.Sprintf("%s · página %d de %d", text, i+1, len(w.pages))
+		fmt.Fprintf(page, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n", pdfMargin, pdfMargin/2, pdfString(line))
+	}
+}
+
+func (w *pdfWriter) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", len(offsets), body)
+		if stream != nil {
+			out.WriteString("stream\n")
+			out.Write(stream)
+			out.WriteString("\nendstream\n")
+		}
+		out.WriteString("endobj\n")
+	}
+
+	// Objetos fixos: 1 catálogo, 2 páginas, 3 e 4 fontes; depois as imagens
+	// e, para cada página, a página e o seu conteúdo
+	firstImage := 5
+	firstPage := firstImage + len(w.images)
+
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	var xobjects strings.Builder
+	for i := range w.images {
+		fmt.Fprintf(&xobjects, "/Im%d %d 0 R ", i+1, firstImage+i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)), nil)
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+	for _, img := range w.images {
+		object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			img.width, img.height, len(img.data)), img.data)
+	}
+	for i, page := range w.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> /XObject << %s>> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, xobjects.String(), firstPage+2*i+1), nil)
+		object(fmt.Sprintf("<< /Length %d >>", page.Len()), page.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// wrapText quebra o texto em linhas de até maxChars caracteres, sem partir
+// palavras menores que a linha
+func wrapText(text string, maxChars int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > maxChars {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(runes[:maxChars]))
+			runes = runes[maxChars:]
+		}
+
+		switch {
+		case len(line) == 0:
+			line = runes
+		case len(line)+1+len(runes) <= maxChars:
+			line = append(append(line, ' '), runes...)
+		default:
+			lines = append(lines, string(line))
+			line = runes
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// pdfString converte o texto para WinAnsi (acentos do português incluídos) e
+// escapa os caracteres especiais das strings do PDF
+func pdfString(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		switch b {
+		case '(', ')', '\\':
+			out.WriteByte('\\')
+		}
+		out.WriteByte(b)
+	}
+	return out.String()
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// Situação de um destaque em relação à janela agendada
+const (
+	FeaturedStatusActive    = "active"
+	FeaturedStatusScheduled = "scheduled"
+	FeaturedStatusExpired   = "expired"
+	FeaturedStatusHidden    = "hidden" // roteiro deixou de ser público
+)
+
+type FeatureItineraryRequest struct {
+	FeaturedFrom  *time.Time `json:"featured_from"`  // vazio: a partir de agora
+	FeaturedUntil *time.Time `json:"featured_until"` // vazio: sem prazo
+	Order         *int       `json:"order"`          // vazio: depois dos ordenados
+}
+
+type ReorderFeaturedRequest struct {
+	ItineraryIDs []uint `json:"itinerary_ids" binding:"required"`
+}
+
+type FeaturedItinerary struct {
+	Itinerary     *models.ItineraryResponse `json:"itinerary"`
+	Order         *int                      `json:"order"`
+	FeaturedFrom  *time.Time                `json:"featured_from"`
+	FeaturedUntil *time.Time                `json:"featured_until"`
+	Status        string                    `json:"status"`
+}
+
+type FeaturedServiceInterface interface {
+	GetFeatured() ([]FeaturedItinerary, error)
+	FeatureItinerary(itineraryID uint, req *FeatureItineraryRequest) (*FeaturedItinerary, error)
+	UnfeatureItinerary(itineraryID uint) error
+	ReorderFeatured(req *ReorderFeaturedRequest) ([]FeaturedItinerary, error)
+}
+
+type FeaturedService struct {
+	featuredRepo  repositories.FeaturedRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+}
+
+func NewFeaturedService(featuredRepo repositories.FeaturedRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface) FeaturedServiceInterface {
+	return &FeaturedService{
+		featuredRepo:  featuredRepo,
+		itineraryRepo: itineraryRepo,
+	}
+}
+
+func (s *FeaturedService) GetFeatured() ([]FeaturedItinerary, error) {
+	itineraries, err := s.featuredRepo.GetAll()
+	if err != nil {
+		return nil, errors.New("erro ao buscar destaques")
+	}
+
+	now := time.Now()
+	featured := make([]FeaturedItinerary, len(itineraries))
+	for i := range itineraries {
+		featured[i] = toFeaturedItinerary(&itineraries[i], now)
+	}
+
+	return featured, nil
+}
+
+// FeatureItinerary destaca o roteiro ou altera a janela e a posição de um
+// destaque existente
+func (s *FeaturedService) FeatureItinerary(itineraryID uint, req *FeatureItineraryRequest) (*FeaturedItinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	if err := validateFeatureRequest(itinerary, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.featuredRepo.SetFeatured(itineraryID, req.FeaturedFrom, req.FeaturedUntil, req.Order); err != nil {
+		return nil, errors.New("erro ao destacar roteiro")
+	}
+
+	itinerary.IsFeatured = true
+	itinerary.FeaturedFrom = req.FeaturedFrom
+	itinerary.FeaturedUntil = req.FeaturedUntil
+	itinerary.FeaturedOrder = req.Order
+
+	featured := toFeaturedItinerary(itinerary, time.Now())
+	return &featured, nil
+}
+
+func (s *FeaturedService) UnfeatureItinerary(itineraryID uint) error {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return errors.New("roteiro não encontrado")
+	}
+	if !itinerary.IsFeatured {
+		return errors.New("roteiro não está em destaque")
+	}
+
+	if err := s.featuredRepo.Unfeature(itineraryID); err != nil {
+		return errors.New("erro ao remover destaque")
+	}
+
+	return nil
+}
+
+// ReorderFeatured define a ordem do carrossel: os roteiros informados ficam
+// nas primeiras posições, na ordem da lista
+func (s *FeaturedService) ReorderFeatured(req *ReorderFeaturedRequest) ([]FeaturedItinerary, error) {
+	itineraries, err := s.featuredRepo.GetAll()
+	if err != nil {
+		return nil, errors.New("erro ao buscar destaques")
+	}
+
+	featured := make(map[uint]bool, len(itineraries))
+	for _, itinerary := range itineraries {
+		featured[itinerary.ID] = true
+	}
+
+	seen := make(map[uint]bool, len(req.ItineraryIDs))
+	for _, id := range req.ItineraryIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("o roteiro %d aparece mais de uma vez", id)
+		}
+		if !featured[id] {
+			return nil, fmt.Errorf("o roteiro %d não está em destaque", id)
+		}
+		seen[id] = true
+	}
+
+	if err := s.featuredRepo.Reorder(req.ItineraryIDs); err != nil {
+		return nil, errors.New("erro ao ordenar destaques")
+	}
+
+	return s.GetFeatured()
+}
+
+func toFeaturedItinerary(itinerary *models.Itinerary, now time.Time) FeaturedItinerary {
+	featured := FeaturedItinerary{
+		Itinerary:     itinerary.ToResponse(),
+		Order:         itinerary.FeaturedOrder,
+		FeaturedFrom:  itinerary.FeaturedFrom,
+		FeaturedUntil: itinerary.FeaturedUntil,
+		Status:        FeaturedStatusActive,
+	}
+
+	switch {
+	case !itinerary.IsPublic:
+		featured.Status = FeaturedStatusHidden
+	case itinerary.FeaturedFrom != nil && itinerary.FeaturedFrom.After(now):
+		featured.Status = FeaturedStatusScheduled
+	case itinerary.FeaturedUntil != nil && !itinerary.FeaturedUntil.After(now):
+		featured.Status = FeaturedStatusExpired
+	}
+
+	return featured
+}
+
+// Funções de validação
+
+func validateFeatureRequest(itinerary *models.Itinerary, req *FeatureItineraryRequest) error {
+	if !itinerary.IsPublic {
+		return errors.New("apenas roteiros públicos podem ser destacados")
+	}
+
+	if req.FeaturedUntil != nil {
+		if req.FeaturedUntil.Before(time.Now()) {
+			return errors.New("o fim do destaque não pode estar no passado")
+		}
+		if req.FeaturedFrom != nil && !req.FeaturedUntil.After(*req.FeaturedFrom) {
+			return errors.New("o fim do destaque deve ser posterior ao início")
+		}
+	}
+
+	if req.Order != nil && *req.Order < 1 {
+		return errors.New("a posição deve ser maior que zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type FeedSettingsServiceInterface interface {
+	GetFeedSettings(userID uint) (*models.FeedSettingsResponse, error)
+	UpdateFeedSettings(userID uint, req *UpdateFeedSettingsRequest) (*models.FeedSettingsResponse, error)
+}
+
+type FeedSettingsService struct {
+	feedSettingsRepo repositories.FeedSettingsRepositoryInterface
+}
+
+type UpdateFeedSettingsRequest struct {
+	ContentMix       *models.FeedContentMix `json:"content_mix,omitempty"`
+	IncludeSuggested *bool                  `json:"include_suggested,omitempty"`
+	HideReposts      *bool                  `json:"hide_reposts,omitempty"`
+}
+
+// feedExperimentBuckets define os padrões do feed em cada grupo do experimento;
+// o usuário cai sempre no mesmo grupo enquanto a lista não mudar
+var feedExperimentBuckets = []struct {
+	Name             string
+	ContentMix       models.FeedContentMix
+	IncludeSuggested bool
+}{
+	{Name: "control", ContentMix: models.FeedMixBalanced, IncludeSuggested: false},
+	{Name: "discovery", ContentMix: models.FeedMixBalanced, IncludeSuggested: true},
+	{Name: "itineraries", ContentMix: models.FeedMixMoreItineraries, IncludeSuggested: true},
+}
+
+func NewFeedSettingsService(feedSettingsRepo repositories.FeedSettingsRepositoryInterface) FeedSettingsServiceInterface {
+	return &FeedSettingsService{
+		feedSettingsRepo: feedSettingsRepo,
+	}
+}
+
+// GetFeedSettings retorna as preferências salvas ou, na falta delas, os
+// padrões do grupo de experimento do usuário
+func (s *FeedSettingsService) GetFeedSettings(userID uint) (*models.FeedSettingsResponse, error) {
+	bucket := feedExperimentBuckets[feedBucket(userID)]
+
+	settings, err := s.feedSettingsRepo.Get(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.FeedSettingsResponse{
+				ContentMix:       bucket.ContentMix,
+				IncludeSuggested: bucket.IncludeSuggested,
+				Bucket:           bucket.Name,
+			}, nil
+		}
+		return nil, errors.New("erro ao buscar preferências do feed")
+	}
+
+	return &models.FeedSettingsResponse{
+		ContentMix:       settings.ContentMix,
+		IncludeSuggested: settings.IncludeSuggested,
+		HideReposts:      settings.HideReposts,
+		Bucket:           bucket.Name,
+		Customized:       true,
+	}, nil
+}
+
+// UpdateFeedSettings altera apenas os campos informados, partindo das
+// preferências em vigor
+func (s *FeedSettingsService) UpdateFeedSettings(userID uint, req *UpdateFeedSettingsRequest) (*models.FeedSettingsResponse, error) {
+	if req.ContentMix != nil {
+		if err := s.validateContentMix(*req.ContentMix); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.GetFeedSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.FeedSettings{
+		UserID:           userID,
+		ContentMix:       current.ContentMix,
+		IncludeSuggested: current.IncludeSuggested,
+		HideReposts:      current.HideReposts,
+	}
+	if req.ContentMix != nil {
+		settings.ContentMix = *req.ContentMix
+	}
+	if req.IncludeSuggested != nil {
+		settings.IncludeSuggested = *req.IncludeSuggested
+	}
+	if req.HideReposts != nil {
+		settings.HideReposts = *req.HideReposts
+	}
+
+	if err := s.feedSettingsRepo.Save(settings); err != nil {
+		return nil, errors.New("erro ao salvar preferências do feed")
+	}
+
+	return s.GetFeedSettings(userID)
+}
+
+// feedBucket sorteia de forma determinística o grupo de experimento do usuário
+func feedBucket(userID uint) int {
+	hash := fnv.New32a()
+	hash.Write([]byte("feed-mix:" + strconv.FormatUint(uint64(userID), 10)))
+	return int(hash.Sum32() % uint32(len(feedExperimentBuckets)))
+}
+
+// Funções de validação
+
+func (s *FeedSettingsService) validateContentMix(mix models.FeedContentMix) error {
+	switch mix {
+	case models.FeedMixBalanced, models.FeedMixMoreItineraries, models.FeedMixMorePosts:
+		return nil
+	}
+	return errors.New("mistura de conteúdo inválida")
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// FraudReviewHandler aplica a decisão de uma revisão manual à entidade avaliada
+// (ex.: captura ou cancela o pagamento de uma gorjeta)
+type FraudReviewHandler func(check *models.FraudCheck, approved bool) error
+
+type FraudServiceInterface interface {
+	Evaluate(input *models.FraudCheckInput) (*models.FraudCheck, error)
+	AttachSubject(check *models.FraudCheck, subjectID uint) error
+	RegisterReviewHandler(subject models.FraudSubject, handler FraudReviewHandler)
+	EnsureDefaultRules() error
+	GetRules() ([]models.FraudRule, error)
+	UpdateRule(code models.FraudRuleCode, adminID uint, req *FraudRuleRequest) (*models.FraudRule, error)
+	GetReviewQueue(status models.FraudReviewStatus, limit, offset int) ([]models.FraudCheckResponse, error)
+	ReviewCheck(checkID, adminID uint, req *FraudReviewRequest) (*models.FraudCheckResponse, error)
+}
+
+type FraudRuleRequest struct {
+	IsEnabled     *bool              `json:"is_enabled"`
+	Action        models.FraudAction `json:"action"`
+	MaxCount      *int               `json:"max_count"`
+	MaxAmount     *int64             `json:"max_amount"`
+	WindowMinutes *int               `json:"window_minutes"`
+	Domains       []string           `json:"domains"`
+}
+
+type FraudReviewRequest struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note"`
+}
+
+type FraudService struct {
+	fraudRepo repositories.FraudRepositoryInterface
+
+	mu             sync.RWMutex
+	reviewHandlers map[models.FraudSubject]FraudReviewHandler
+}
+
+func NewFraudService(fraudRepo repositories.FraudRepositoryInterface) FraudServiceInterface {
+	return &FraudService{
+		fraudRepo:      fraudRepo,
+		reviewHandlers: make(map[models.FraudSubject]FraudReviewHandler),
+	}
+}
+
+// Evaluate aplica as regras ativas à tentativa e registra o resultado; a
+// decisão final é a ação mais severa entre as regras violadas
+func (s *FraudService) Evaluate(input *models.FraudCheckInput) (*models.FraudCheck, error) {
+	rules, err := s.fraudRepo.GetRules()
+	if err != nil {
+		return nil, errors.New("erro ao avaliar pagamento")
+	}
+
+	check := &models.FraudCheck{
+		Subject:      input.Subject,
+		UserID:       input.UserID,
+		Amount:       input.Amount,
+		Currency:     strings.ToUpper(input.Currency),
+		IPCountry:    strings.ToUpper(input.IPCountry),
+		Country:      strings.ToUpper(input.Country),
+		Decision:     models.FraudDecisionApproved,
+		ReviewStatus: models.FraudReviewNone,
+	}
+
+	for _, rule := range rules {
+		if !rule.IsEnabled {
+			continue
+		}
+
+		reason, err := s.applyRule(&rule, input, check)
+		if err != nil {
+			return nil, errors.New("erro ao avaliar pagamento")
+		}
+		if reason == "" {
+			continue
+		}
+
+		check.Reasons = append(check.Reasons, reason)
+		switch {
+		case rule.Action == models.FraudActionBlock:
+			check.Decision = models.FraudDecisionBlocked
+		case check.Decision == models.FraudDecisionApproved:
+			check.Decision = models.FraudDecisionReview
+		}
+	}
+
+	if check.Decision == models.FraudDecisionReview {
+		check.ReviewStatus = models.FraudReviewPending
+	}
+
+	if err := s.fraudRepo.CreateCheck(check); err != nil {
+		return nil, errors.New("erro ao registrar avaliação antifraude")
+	}
+
+	return check, nil
+}
+
+func (s *FraudService) AttachSubject(check *models.FraudCheck, subjectID uint) error {
+	check.SubjectID = subjectID
+	return s.fraudRepo.AttachSubject(check.ID, subjectID)
+}
+
+func (s *FraudService) RegisterReviewHandler(subject models.FraudSubject, handler FraudReviewHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reviewHandlers[subject] = handler
+}
+
+// EnsureDefaultRules cria as regras padrão na primeira execução
+func (s *FraudService) EnsureDefaultRules() error {
+	return s.fraudRepo.EnsureRules([]models.FraudRule{
+		{
+			Code:          models.FraudRulePaymentVelocity,
+			Description:   "Muitas tentativas de pagamento ou valor alto em pouco tempo",
+			IsEnabled:     true,
+			Action:        models.FraudActionReview,
+			MaxCount:      5,
+			MaxAmount:     50000,
+			WindowMinutes: 60,
+		},
+		{
+			Code:        models.FraudRuleGeoMismatch,
+			Description: "País da requisição diferente do país informado para a cobrança",
+			IsEnabled:   true,
+			Action:      models.FraudActionReview,
+		},
+		{
+			Code:        models.FraudRuleDisposableEmail,
+			Description: "Conta criada com e-mail temporário",
+			IsEnabled:   true,
+			Action:      models.FraudActionBlock,
+			Domains: []string{
+				"mailinator.com", "guerrillamail.com", "10minutemail.com", "tempmail.com",
+				"temp-mail.org", "yopmail.com", "trashmail.com", "sharklasers.com",
+				"getnada.com", "dispostable.com", "maildrop.cc", "throwawaymail.com",
+			},
+		},
+	})
+}
+
+func (s *FraudService) GetRules() ([]models.FraudRule, error) {
+	rules, err := s.fraudRepo.GetRules()
+	if err != nil {
+		return nil, errors.New("erro ao buscar regras antifraude")
+	}
+	return rules, nil
+}
+
+func (s *FraudService) UpdateRule(code models.FraudRuleCode, adminID uint, req *FraudRuleRequest) (*models.FraudRule, error) {
+	rule, err := s.fraudRepo.GetRuleByCode(code)
+	if err != nil {
+		return nil, errors.New("regra antifraude não encontrada")
+	}
+
+	if err := s.validateFraudRuleRequest(req); err != nil {
+		return nil, err
+	}
+
+	if req.IsEnabled != nil {
+		rule.IsEnabled = *req.IsEnabled
+	}
+	if req.Action != "" {
+		rule.Action = req.Action
+	}
+	if req.MaxCount != nil {
+		rule.MaxCount = *req.MaxCount
+	}
+	if req.MaxAmount != nil {
+		rule.MaxAmount = *req.MaxAmount
+	}
+	if req.WindowMinutes != nil {
+		rule.WindowMinutes = *req.WindowMinutes
+	}
+	if req.Domains != nil {
+		rule.Domains = normalizeDomains(req.Domains)
+	}
+	rule.UpdatedByID = &adminID
+
+	if err := s.fraudRepo.UpdateRule(rule); err != nil {
+		return nil, errors.New("erro ao atualizar regra antifraude")
+	}
+
+	return rule, nil
+}
+
+func (s *FraudService) GetReviewQueue(status models.FraudReviewStatus, limit, offset int) ([]models.FraudCheckResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if status == "" {
+		status = models.FraudReviewPending
+	}
+
+	checks, err := s.fraudRepo.GetChecksByReviewStatus(status, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar fila de revisão")
+	}
+
+	var responses []models.FraudCheckResponse
+	for _, check := range checks {
+		responses = append(responses, *check.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// ReviewCheck aplica a decisão do admin à entidade avaliada e encerra a revisão
+func (s *FraudService) ReviewCheck(checkID, adminID uint, req *FraudReviewRequest) (*models.FraudCheckResponse, error) {
+	check, err := s.fraudRepo.GetCheckByID(checkID)
+	if err != nil {
+		return nil, errors.New("avaliação não encontrada")
+	}
+
+	if check.ReviewStatus != models.FraudReviewPending {
+		return nil, errors.New("avaliação já revisada")
+	}
+
+	s.mu.RLock()
+	handler := s.reviewHandlers[check.Subject]
+	s.mu.RUnlock()
+
+	if handler != nil && check.SubjectID != 0 {
+		if err := handler(check, req.Approve); err != nil {
+			return nil, err
+		}
+	}
+
+	status := models.FraudReviewRejected
+	if req.Approve {
+		status = models.FraudReviewApproved
+	}
+	check.ReviewedByID = &adminID
+	check.ReviewNote = strings.TrimSpace(req.Note)
+
+	completed, err := s.fraudRepo.CompleteReview(check, status)
+	if err != nil {
+		return nil, errors.New("erro ao registrar revisão")
+	}
+	if !completed {
+		return nil, errors.New("avaliação já revisada")
+	}
+
+	return check.ToResponse(), nil
+}
+
+// applyRule retorna o motivo da violação ou vazio quando a regra é respeitada
+func (s *FraudService) applyRule(rule *models.FraudRule, input *models.FraudCheckInput, check *models.FraudCheck) (string, error) {
+	switch rule.Code {
+	case models.FraudRulePaymentVelocity:
+		if rule.WindowMinutes <= 0 {
+			return "", nil
+		}
+		since := time.Now().Add(-time.Duration(rule.WindowMinutes) * time.Minute)
+		attempts, total, err := s.fraudRepo.GetRecentActivity(input.UserID, input.Subject, since)
+		if err != nil {
+			return "", err
+		}
+		// Inclui a tentativa atual, ainda não registrada
+		attempts++
+		total += input.Amount
+		if rule.MaxCount > 0 && attempts > int64(rule.MaxCount) {
+			return fmt.Sprintf("%s: %d tentativas em %d minutos", rule.Code, attempts, rule.WindowMinutes), nil
+		}
+		if rule.MaxAmount > 0 && total > rule.MaxAmount {
+			return fmt.Sprintf("%s: valor acumulado de %d em %d minutos", rule.Code, total, rule.WindowMinutes), nil
+		}
+
+	case models.FraudRuleGeoMismatch:
+		if check.IPCountry != "" && check.Country != "" && check.IPCountry != check.Country {
+			return fmt.Sprintf("%s: requisição de %s para cobrança em %s", rule.Code, check.IPCountry, check.Country), nil
+		}
+
+	case models.FraudRuleDisposableEmail:
+		at := strings.LastIndex(input.Email, "@")
+		if at < 0 {
+			return "", nil
+		}
+		domain := strings.ToLower(input.Email[at+1:])
+		for _, disposable := range rule.Domains {
+			if domain == disposable || strings.HasSuffix(domain, "."+disposable) {
+				return fmt.Sprintf("%s: domínio %s", rule.Code, domain), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized
+}
+
+// Funções de validação
+func (s *FraudService) validateFraudRuleRequest(req *FraudRuleRequest) error {
+	if req.Action != "" && req.Action != models.FraudActionReview && req.Action != models.FraudActionBlock {
+		return errors.New("ação deve ser 'review' ou 'block'")
+	}
+
+	if (req.MaxCount != nil && *req.MaxCount < 0) ||
+		(req.MaxAmount != nil && *req.MaxAmount < 0) ||
+		(req.WindowMinutes != nil && *req.WindowMinutes < 0) {
+		return errors.New("limites não podem ser negativos")
+	}
+
+	return nil
+}
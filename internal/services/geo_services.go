@@ -0,0 +1,578 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"golang.org/x/text/unicode/norm"
+)
+
+type GeoServiceInterface interface {
+	SearchCountries(query string, limit int) ([]models.GeoSuggestion, error)
+	SearchCities(query, countryCode string, limit int) ([]models.GeoSuggestion, error)
+	NormalizeLocation(country, state, city string) *NormalizedLocation
+	TimezoneAt(latitude, longitude float64) string
+	CountryAt(latitude, longitude float64) string
+	SeedReferenceData(dataPath string) error
+	BackfillItineraries() (int, error)
+}
+
+// NormalizedLocation é o resultado da normalização de país/estado/cidade
+// informados em texto livre. Os IDs ficam nulos quando não há correspondência.
+type NormalizedLocation struct {
+	Country     string `json:"country"`
+	State       string `json:"state"`
+	City        string `json:"city"`
+	CountryCode string `json:"country_code"`
+	CountryID   *uint  `json:"country_id"`
+	StateID     *uint  `json:"state_id"`
+	CityID      *uint  `json:"city_id"`
+	Timezone    string `json:"timezone"`
+	Locale      string `json:"locale"`
+}
+
+type GeoService struct {
+	geoRepo       repositories.GeoRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+
+	mu        sync.RWMutex
+	countries map[string]*models.GeoCountry // search name / alias / código -> país
+}
+
+func NewGeoService(geoRepo repositories.GeoRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface) GeoServiceInterface {
+	return &GeoService{
+		geoRepo:       geoRepo,
+		itineraryRepo: itineraryRepo,
+	}
+}
+
+func (s *GeoService) SearchCountries(query string, limit int) ([]models.GeoSuggestion, error) {
+	if limit <= 0 || limit > 20 {
+		limit = 10
+	}
+
+	search := normalizeGeoName(query)
+	if search == "" {
+		return []models.GeoSuggestion{}, nil
+	}
+
+	if err := s.loadCountries(); err != nil {
+		return nil, errors.New("erro ao buscar países")
+	}
+
+	// A lista de países é pequena, então a busca por prefixo (incluindo
+	// nomes em português e apelidos) é feita sobre o cache em memória
+	s.mu.RLock()
+	seen := make(map[uint]bool)
+	var suggestions []models.GeoSuggestion
+	for key, country := range s.countries {
+		if seen[country.ID] || !strings.HasPrefix(key, search) {
+			continue
+		}
+		seen[country.ID] = true
+		suggestions = append(suggestions, models.GeoSuggestion{
+			ID:          country.ID,
+			Name:        countryDisplayName(country),
+			CountryCode: country.Code,
+		})
+	}
+	s.mu.RUnlock()
+
+	sortSuggestions(suggestions)
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+func (s *GeoService) SearchCities(query, countryCode string, limit int) ([]models.GeoSuggestion, error) {
+	if limit <= 0 || limit > 20 {
+		limit = 10
+	}
+
+	search := normalizeGeoName(query)
+	if search == "" {
+		return []models.GeoSuggestion{}, nil
+	}
+
+	cities, err := s.geoRepo.SearchCities(search, strings.ToUpper(strings.TrimSpace(countryCode)), limit)
+	if err != nil {
+		return nil, errors.New("erro ao buscar cidades")
+	}
+
+	if err := s.loadCountries(); err != nil {
+		return nil, errors.New("erro ao buscar cidades")
+	}
+
+	states := make(map[string]map[string]string)
+	var suggestions []models.GeoSuggestion
+	for _, city := range cities {
+		lat, lng := city.Latitude, city.Longitude
+		suggestion := models.GeoSuggestion{
+			ID:          city.ID,
+			Name:        city.Name,
+			CountryCode: city.CountryCode,
+			Latitude:    &lat,
+			Longitude:   &lng,
+		}
+
+		if country := s.countryByKey(strings.ToLower(city.CountryCode)); country != nil {
+			suggestion.CountryName = countryDisplayName(country)
+		}
+
+		if _, ok := states[city.CountryCode]; !ok {
+			states[city.CountryCode] = s.stateNames(city.CountryCode)
+		}
+		suggestion.StateName = states[city.CountryCode][city.StateCode]
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
+
+func (s *GeoService) NormalizeLocation(country, state, city string) *NormalizedLocation {
+	result := &NormalizedLocation{
+		Country: strings.TrimSpace(country),
+		State:   strings.TrimSpace(state),
+		City:    strings.TrimSpace(city),
+	}
+
+	if err := s.loadCountries(); err != nil {
+		return result
+	}
+
+	geoCountry := s.countryByKey(normalizeGeoName(country))
+	if geoCountry == nil {
+		// Sem correspondência: mantém o texto informado pelo usuário
+		return result
+	}
+
+	result.Country = countryDisplayName(geoCountry)
+	result.CountryCode = geoCountry.Code
+	result.CountryID = &geoCountry.ID
+	result.Timezone = geoCountry.Timezone
+	result.Locale = geoCountry.Locale
+
+	stateCode := ""
+	if search := normalizeGeoName(state); search != "" {
+		if geoState, err := s.geoRepo.FindState(geoCountry.Code, search); err == nil {
+			result.State = geoState.Name
+			result.StateID = &geoState.ID
+			stateCode = geoState.Code
+		}
+	}
+
+	if search := normalizeGeoName(city); search != "" {
+		if geoCity, err := s.geoRepo.FindCity(geoCountry.Code, stateCode, search); err == nil {
+			result.City = geoCity.Name
+			result.CityID = &geoCity.ID
+			// O fuso da cidade é mais preciso que o do país (ex.: Manaus, Fernando de Noronha)
+			if geoCity.Timezone != "" {
+				result.Timezone = geoCity.Timezone
+			}
+		}
+	}
+
+	return result
+}
+
+// TimezoneAt resolve o fuso horário de uma coordenada a partir da cidade de
+// referência mais próxima. Retorna vazio quando não há cidade conhecida.
+func (s *GeoService) TimezoneAt(latitude, longitude float64) string {
+	city, err := s.geoRepo.FindNearestCity(latitude, longitude)
+	if err != nil {
+		return ""
+	}
+	return city.Timezone
+}
+
+// CountryAt resolve o nome do país de uma coordenada a partir da cidade de
+// referência mais próxima. Retorna vazio quando não há cidade conhecida.
+func (s *GeoService) CountryAt(latitude, longitude float64) string {
+	city, err := s.geoRepo.FindNearestCity(latitude, longitude)
+	if err != nil {
+		return ""
+	}
+	if err := s.loadCountries(); err != nil {
+		return ""
+	}
+	if country := s.countryByKey(strings.ToLower(city.CountryCode)); country != nil {
+		return countryDisplayName(country)
+	}
+	return ""
+}
+
+// SeedReferenceData popula os países embutidos e, se dataPath apontar para um
+// diretório com os dumps do GeoNames (countryInfo.txt, admin1CodesASCII.txt e
+// cities*.txt), importa o conjunto completo.
+func (s *GeoService) SeedReferenceData(dataPath string) error {
+	if err := s.geoRepo.UpsertCountries(builtinCountries(), []string{"name_pt", "aliases", "timezone", "locale"}); err != nil {
+		return err
+	}
+
+	if dataPath != "" {
+		if err := s.importGeoNames(dataPath); err != nil {
+			return err
+		}
+	}
+
+	// Invalidar cache de países
+	s.mu.Lock()
+	s.countries = nil
+	s.mu.Unlock()
+
+	return nil
+}
+
+// BackfillItineraries mapeia os textos livres dos roteiros existentes para as
+// entradas canônicas do conjunto de referência. Cada roteiro é tentado uma
+// vez: os que não casam com nenhum país ficam marcados em geo_checked_at e não
+// são relidos a cada inicialização.
+func (s *GeoService) BackfillItineraries() (int, error) {
+	const batchSize = 200
+
+	checkedAt := time.Now()
+	updated := 0
+	var lastID uint
+	for {
+		itineraries, err := s.itineraryRepo.GetWithoutGeoReference(lastID, batchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(itineraries) == 0 {
+			return updated, nil
+		}
+
+		for _, itinerary := range itineraries {
+			lastID = itinerary.ID
+
+			location := s.NormalizeLocation(itinerary.Country, itinerary.State, itinerary.City)
+			if location.CountryID == nil {
+				if err := s.itineraryRepo.UpdateGeoReference(itinerary.ID, map[string]interface{}{"geo_checked_at": checkedAt}); err != nil {
+					return updated, err
+				}
+				continue
+			}
+
+			err := s.itineraryRepo.UpdateGeoReference(itinerary.ID, map[string]interface{}{
+				"country":        location.Country,
+				"state":          location.State,
+				"city":           location.City,
+				"country_id":     location.CountryID,
+				"state_id":       location.StateID,
+				"city_id":        location.CityID,
+				"timezone":       location.Timezone,
+				"locale":         location.Locale,
+				"geo_checked_at": checkedAt,
+			})
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+// ============================================================================
+// IMPORTAÇÃO GEONAMES
+// ============================================================================
+
+func (s *GeoService) importGeoNames(dataPath string) error {
+	countryFile := filepath.Join(dataPath, "countryInfo.txt")
+	if _, err := os.Stat(countryFile); err == nil {
+		var countries []models.GeoCountry
+		err := readGeoNamesFile(countryFile, func(fields []string) {
+			if len(fields) < 17 {
+				return
+			}
+			geoNameID, _ := strconv.Atoi(fields[16])
+			// O primeiro idioma listado é o principal (ex.: "pt-BR,es,en,fr")
+			locale := strings.SplitN(fields[15], ",", 2)[0]
+			countries = append(countries, models.GeoCountry{
+				GeoNameID:    geoNameID,
+				Code:         fields[0],
+				Name:         fields[4],
+				SearchName:   normalizeGeoName(fields[4]),
+				CurrencyCode: fields[10],
+				Locale:       locale,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.geoRepo.UpsertCountries(countries, []string{"geo_name_id", "name", "search_name", "currency_code"}); err != nil {
+			return err
+		}
+	}
+
+	stateFile := filepath.Join(dataPath, "admin1CodesASCII.txt")
+	if _, err := os.Stat(stateFile); err == nil {
+		var states []models.GeoState
+		err := readGeoNamesFile(stateFile, func(fields []string) {
+			if len(fields) < 4 {
+				return
+			}
+			// Formato: "BR.27", "São Paulo", "Sao Paulo", geonameid
+			codes := strings.SplitN(fields[0], ".", 2)
+			if len(codes) != 2 {
+				return
+			}
+			geoNameID, _ := strconv.Atoi(fields[3])
+			states = append(states, models.GeoState{
+				GeoNameID:   geoNameID,
+				CountryCode: codes[0],
+				Code:        codes[1],
+				Name:        fields[1],
+				SearchName:  normalizeGeoName(fields[1]),
+			})
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.geoRepo.UpsertStates(states); err != nil {
+			return err
+		}
+	}
+
+	cityFiles, _ := filepath.Glob(filepath.Join(dataPath, "cities*.txt"))
+	for _, cityFile := range cityFiles {
+		var batch []models.GeoCity
+		err := readGeoNamesFile(cityFile, func(fields []string) {
+			if len(fields) < 18 {
+				return
+			}
+			geoNameID, _ := strconv.Atoi(fields[0])
+			lat, _ := strconv.ParseFloat(fields[4], 64)
+			lng, _ := strconv.ParseFloat(fields[5], 64)
+			population, _ := strconv.ParseInt(fields[14], 10, 64)
+			batch = append(batch, models.GeoCity{
+				GeoNameID:   geoNameID,
+				Name:        fields[1],
+				ASCIIName:   fields[2],
+				SearchName:  normalizeGeoName(fields[1]),
+				Latitude:    lat,
+				Longitude:   lng,
+				CountryCode: fields[8],
+				StateCode:   fields[10],
+				Population:  population,
+				Timezone:    fields[17],
+			})
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.geoRepo.UpsertCities(batch); err != nil {
+			return err
+		}
+		log.Printf("GeoNames: %d cidades importadas de %s", len(batch), filepath.Base(cityFile))
+	}
+
+	return nil
+}
+
+func readGeoNamesFile(path string, handle func(fields []string)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		handle(strings.Split(line, "\t"))
+	}
+
+	return scanner.Err()
+}
+
+// ============================================================================
+// FUNÇÕES AUXILIARES
+// ============================================================================
+
+func (s *GeoService) loadCountries() error {
+	s.mu.RLock()
+	loaded := s.countries != nil
+	s.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	countries, err := s.geoRepo.GetAllCountries()
+	if err != nil {
+		return err
+	}
+
+	// Sem países (tabela ainda vazia) o índice não é guardado: a próxima
+	// chamada tenta de novo em vez de ficar presa a um cache vazio
+	if len(countries) == 0 {
+		return nil
+	}
+
+	index := make(map[string]*models.GeoCountry)
+	for i := range countries {
+		country := &countries[i]
+		index[strings.ToLower(country.Code)] = country
+		index[normalizeGeoName(country.Name)] = country
+		if country.NamePT != "" {
+			index[normalizeGeoName(country.NamePT)] = country
+		}
+		for _, alias := range country.Aliases {
+			index[normalizeGeoName(alias)] = country
+		}
+	}
+
+	s.mu.Lock()
+	s.countries = index
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *GeoService) countryByKey(key string) *models.GeoCountry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.countries[key]
+}
+
+func (s *GeoService) stateNames(countryCode string) map[string]string {
+	names := make(map[string]string)
+	states, err := s.geoRepo.GetStatesByCountry(countryCode)
+	if err != nil {
+		return names
+	}
+	for _, state := range states {
+		names[state.Code] = state.Name
+	}
+	return names
+}
+
+func countryDisplayName(country *models.GeoCountry) string {
+	if country.NamePT != "" {
+		return country.NamePT
+	}
+	return country.Name
+}
+
+func sortSuggestions(suggestions []models.GeoSuggestion) {
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Name < suggestions[j].Name
+	})
+}
+
+// normalizeGeoName remove acentos, espaços extras e diferenças de caixa
+// ("São  Paulo" -> "sao paulo")
+func normalizeGeoName(name string) string {
+	decomposed := norm.NFD.String(strings.ToLower(strings.TrimSpace(name)))
+
+	var builder strings.Builder
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// builtinCountries garante um conjunto mínimo de países (com nomes em
+// português e variações comuns) mesmo sem os dumps do GeoNames.
+func builtinCountries() []models.GeoCountry {
+	data := []struct {
+		code, name, namePT, currency, timezone, locale string
+		aliases                                        []string
+	}{
+		{"BR", "Brazil", "Brasil", "BRL", "America/Sao_Paulo", "pt-BR", []string{"bra", "brazil", "brasil"}},
+		{"AR", "Argentina", "Argentina", "ARS", "America/Argentina/Buenos_Aires", "es-AR", nil},
+		{"CL", "Chile", "Chile", "CLP", "America/Santiago", "es-CL", nil},
+		{"UY", "Uruguay", "Uruguai", "UYU", "America/Montevideo", "es-UY", nil},
+		{"PY", "Paraguay", "Paraguai", "PYG", "America/Asuncion", "es-PY", nil},
+		{"PE", "Peru", "Peru", "PEN", "America/Lima", "es-PE", []string{"perú"}},
+		{"BO", "Bolivia", "Bolívia", "BOB", "America/La_Paz", "es-BO", nil},
+		{"CO", "Colombia", "Colômbia", "COP", "America/Bogota", "es-CO", nil},
+		{"EC", "Ecuador", "Equador", "USD", "America/Guayaquil", "es-EC", nil},
+		{"VE", "Venezuela", "Venezuela", "VES", "America/Caracas", "es-VE", nil},
+		{"MX", "Mexico", "México", "MXN", "America/Mexico_City", "es-MX", nil},
+		{"US", "United States", "Estados Unidos", "USD", "America/New_York", "en-US", []string{"usa", "eua", "united states of america"}},
+		{"CA", "Canada", "Canadá", "CAD", "America/Toronto", "en-CA", nil},
+		{"CU", "Cuba", "Cuba", "CUP", "America/Havana", "es-CU", nil},
+		{"PT", "Portugal", "Portugal", "EUR", "Europe/Lisbon", "pt-PT", nil},
+		{"ES", "Spain", "Espanha", "EUR", "Europe/Madrid", "es-ES", []string{"españa"}},
+		{"FR", "France", "França", "EUR", "Europe/Paris", "fr-FR", nil},
+		{"IT", "Italy", "Itália", "EUR", "Europe/Rome", "it-IT", []string{"italia"}},
+		{"DE", "Germany", "Alemanha", "EUR", "Europe/Berlin", "de", []string{"deutschland"}},
+		{"GB", "United Kingdom", "Reino Unido", "GBP", "Europe/London", "en-GB", []string{"uk", "england", "inglaterra"}},
+		{"IE", "Ireland", "Irlanda", "EUR", "Europe/Dublin", "en-IE", nil},
+		{"NL", "Netherlands", "Holanda", "EUR", "Europe/Amsterdam", "nl-NL", []string{"países baixos", "paises baixos"}},
+		{"BE", "Belgium", "Bélgica", "EUR", "Europe/Brussels", "nl-BE", nil},
+		{"CH", "Switzerland", "Suíça", "CHF", "Europe/Zurich", "de-CH", nil},
+		{"AT", "Austria", "Áustria", "EUR", "Europe/Vienna", "de-AT", nil},
+		{"GR", "Greece", "Grécia", "EUR", "Europe/Athens", "el-GR", nil},
+		{"TR", "Turkey", "Turquia", "TRY", "Europe/Istanbul", "tr-TR", []string{"türkiye"}},
+		{"HR", "Croatia", "Croácia", "EUR", "Europe/Zagreb", "hr-HR", nil},
+		{"CZ", "Czechia", "Tchéquia", "CZK", "Europe/Prague", "cs", []string{"czech republic", "república tcheca"}},
+		{"IS", "Iceland", "Islândia", "ISK", "Atlantic/Reykjavik", "is", nil},
+		{"NO", "Norway", "Noruega", "NOK", "Europe/Oslo", "no", nil},
+		{"SE", "Sweden", "Suécia", "SEK", "Europe/Stockholm", "sv-SE", nil},
+		{"EG", "Egypt", "Egito", "EGP", "Africa/Cairo", "ar-EG", nil},
+		{"MA", "Morocco", "Marrocos", "MAD", "Africa/Casablanca", "ar-MA", nil},
+		{"ZA", "South Africa", "África do Sul", "ZAR", "Africa/Johannesburg", "en-ZA", nil},
+		{"AE", "United Arab Emirates", "Emirados Árabes Unidos", "AED", "Asia/Dubai", "ar-AE", []string{"uae", "emirados"}},
+		{"JP", "Japan", "Japão", "JPY", "Asia/Tokyo", "ja", nil},
+		{"CN", "China", "China", "CNY", "Asia/Shanghai", "zh-CN", nil},
+		{"KR", "South Korea", "Coreia do Sul", "KRW", "Asia/Seoul", "ko-KR", nil},
+		{"TH", "Thailand", "Tailândia", "THB", "Asia/Bangkok", "th", nil},
+		{"VN", "Vietnam", "Vietnã", "VND", "Asia/Ho_Chi_Minh", "vi", nil},
+		{"ID", "Indonesia", "Indonésia", "IDR", "Asia/Jakarta", "id", nil},
+		{"IN", "India", "Índia", "INR", "Asia/Kolkata", "en-IN", nil},
+		{"AU", "Australia", "Austrália", "AUD", "Australia/Sydney", "en-AU", nil},
+		{"NZ", "New Zealand", "Nova Zelândia", "NZD", "Pacific/Auckland", "en-NZ", nil},
+	}
+
+	countries := make([]models.GeoCountry, 0, len(data))
+	for _, c := range data {
+		countries = append(countries, models.GeoCountry{
+			Code:         c.code,
+			Name:         c.name,
+			NamePT:       c.namePT,
+			SearchName:   normalizeGeoName(c.name),
+			Aliases:      c.aliases,
+			CurrencyCode: c.currency,
+			Timezone:     c.timezone,
+			Locale:       c.locale,
+		})
+	}
+
+	return countries
+}
+
+// haversineKm calcula a distância em quilômetros entre duas coordenadas
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+type GeoIPConfig struct {
+	// Cabeçalho com o país do visitante preenchido pela CDN à frente da API
+	// (ex.: CF-IPCountry); só é lido de requisições vindas dos proxies
+	// confiáveis (TRUSTED_PROXIES)
+	CountryHeader string
+	// Base de faixas de IP por país em CSV (ip_inicial,ip_final,país, como a
+	// "IP to Country Lite" da DB-IP); vazio desativa a consulta local
+	DatabasePath string
+}
+
+type GeoIPServiceInterface interface {
+	Country(ip string) string
+}
+
+// geoIPRange é uma faixa contínua de endereços de um país
+type geoIPRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+type GeoIPService struct {
+	ranges []geoIPRange // ordenadas pelo início
+}
+
+// NewGeoIPService carrega a base de faixas em memória; sem base (ou com
+// arquivo inválido) toda consulta volta vazia
+func NewGeoIPService(config *GeoIPConfig) GeoIPServiceInterface {
+	service := &GeoIPService{}
+	if config.DatabasePath == "" {
+		return service
+	}
+
+	ranges, err := loadGeoIPRanges(config.DatabasePath)
+	if err != nil {
+		log.Println("Falha ao carregar base de GeoIP:", err)
+		return service
+	}
+	service.ranges = ranges
+	log.Printf("%d faixas de IP carregadas para a localização por país", len(ranges))
+	return service
+}
+
+// Country retorna o código ISO do país do IP, ou vazio quando desconhecido
+func (s *GeoIPService) Country(ip string) string {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil || len(s.ranges) == 0 {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	// Última faixa que começa até o endereço
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].start.Compare(addr) > 0
+	}) - 1
+	if i < 0 || s.ranges[i].end.Compare(addr) < 0 {
+		return ""
+	}
+	return s.ranges[i].country
+}
+
+func loadGeoIPRanges(path string) ([]geoIPRange, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var ranges []geoIPRange
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			continue
+		}
+
+		start, errStart := netip.ParseAddr(strings.TrimSpace(record[0]))
+		end, errEnd := netip.ParseAddr(strings.TrimSpace(record[1]))
+		country := strings.ToUpper(strings.TrimSpace(record[2]))
+		if errStart != nil || errEnd != nil || len(country) != 2 || country == "ZZ" {
+			continue
+		}
+		ranges = append(ranges, geoIPRange{start: start.Unmap(), end: end.Unmap(), country: country})
+	}
+
+	if len(ranges) == 0 {
+		return nil, errors.New("nenhuma faixa de IP válida no arquivo")
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start.Compare(ranges[j].start) < 0 })
+	return ranges, nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeoIPCountry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dbip-country-lite.csv")
+	data := "1.0.0.0,1.0.0.255,AU\n" +
+		"177.0.0.0,177.255.255.255,BR\n" +
+		"invalido,1.2.3.4,US\n" +
+		"2001:db8::,2001:db8::ffff,PT\n" +
+		"10.0.0.0,10.255.255.255,ZZ\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	service := NewGeoIPService(&GeoIPConfig{DatabasePath: path})
+
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{"início da faixa", "177.0.0.0", "BR"},
+		{"meio da faixa", "177.10.20.30", "BR"},
+		{"fim da faixa", "1.0.0.255", "AU"},
+		{"entre faixas", "2.0.0.1", ""},
+		{"antes da primeira faixa", "0.0.0.1", ""},
+		{"IPv4 mapeado em IPv6", "::ffff:177.1.1.1", "BR"},
+		{"IPv6", "2001:db8::1", "PT"},
+		{"país desconhecido ignorado", "10.1.1.1", ""},
+		{"IP inválido", "abc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.Country(tt.ip); got != tt.want {
+				t.Errorf("Country(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+// GeoJSON (RFC 7946) do roteiro, para desenhar a viagem em mapas e embeds
+// sem remontar o JSON normal do roteiro
+
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	BBox     []float64        `json:"bbox,omitempty"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   GeoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSONGeometry usa [longitude, latitude]: um par para Point e uma lista
+// de pares para LineString
+type GeoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// buildGeoJSON gera um Point por local com coordenadas, com o dia e a ordem
+// de visita nas propriedades, e uma LineString com o percurso de cada dia
+func buildGeoJSON(itinerary *models.Itinerary) *GeoJSONFeatureCollection {
+	collection := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []GeoJSONFeature{},
+	}
+
+	minLng, minLat := math.Inf(1), math.Inf(1)
+	maxLng, maxLat := math.Inf(-1), math.Inf(-1)
+	sequence := 0
+	var paths []GeoJSONFeature
+
+	for _, day := range sortedDays(itinerary) {
+		var path [][]float64
+		order := 0
+		for _, location := range sortedLocations(&day) {
+			if location.Latitude == nil || location.Longitude == nil {
+				continue
+			}
+			order++
+			sequence++
+
+			point := []float64{*location.Longitude, *location.Latitude}
+			path = append(path, point)
+			minLng, maxLng = math.Min(minLng, point[0]), math.Max(maxLng, point[0])
+			minLat, maxLat = math.Min(minLat, point[1]), math.Max(maxLat, point[1])
+
+			properties := map[string]interface{}{
+				"feature_type":   "location",
+				"location_id":    location.ID,
+				"name":           location.Name,
+				"description":    location.Description,
+				"location_type":  location.LocationType,
+				"address":        location.Address,
+				"day_id":         day.ID,
+				"day_number":     day.DayNumber,
+				"day_title":      trackDayTitle(&day),
+				"order":          order,
+				"sequence":       sequence,
+				"start_time":     location.StartTime,
+				"end_time":       location.EndTime,
+				"estimated_cost": location.EstimatedCost,
+				"rating":         location.Rating,
+			}
+			if len(location.Images) > 0 {
+				properties["image"] = location.Images[0]
+			}
+
+			collection.Features = append(collection.Features, GeoJSONFeature{
+				Type:       "Feature",
+				ID:         "location-" + strconv.FormatUint(uint64(location.ID), 10),
+				Geometry:   GeoJSONGeometry{Type: "Point", Coordinates: point},
+				Properties: properties,
+			})
+		}
+
+		if len(path) > 1 {
+			paths = append(paths, GeoJSONFeature{
+				Type:     "Feature",
+				ID:       "day-" + strconv.FormatUint(uint64(day.ID), 10),
+				Geometry: GeoJSONGeometry{Type: "LineString", Coordinates: path},
+				Properties: map[string]interface{}{
+					"feature_type": "day_path",
+					"day_id":       day.ID,
+					"day_number":   day.DayNumber,
+					"day_title":    trackDayTitle(&day),
+				},
+			})
+		}
+	}
+
+	// Percursos antes dos pontos, para os clientes desenharem os marcadores
+	// por cima das linhas
+	collection.Features = append(paths, collection.Features...)
+	if sequence > 0 {
+		collection.BBox = []float64{minLng, minLat, maxLng, maxLat}
+	}
+	return collection
+}
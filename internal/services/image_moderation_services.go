@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rekognition"
+)
+
+const (
+	// A Rekognition aceita até 5MB por imagem enviada nos bytes da requisição
+	maxModerationImageSize = 5 * 1024 * 1024
+	moderationImageSide    = 1920
+	moderationTimeout      = 20 * time.Second
+)
+
+// DefaultModerationLabels são as categorias que colocam a imagem em
+// quarentena quando nenhuma é configurada; nomes da Rekognition, comparados
+// também com a categoria pai
+var DefaultModerationLabels = []string{
+	"Explicit Nudity",
+	"Explicit",
+	"Violence",
+	"Graphic Violence",
+	"Visually Disturbing",
+	"Hate Symbols",
+}
+
+type ImageModerationConfig struct {
+	// "rekognition", "http" (modelo próprio) ou vazio para desativar
+	Provider string
+	// Confiança mínima (0 a 100) para uma categoria contar
+	MinConfidence float64
+	Labels        []string
+
+	// Rekognition; sem chaves usa as credenciais padrão da AWS
+	AWSRegion    string
+	AWSAccessKey string
+	AWSSecretKey string
+
+	// Modelo próprio: recebe a imagem no corpo de um POST e responde
+	// {"labels": [{"name": "...", "parent": "...", "confidence": 97.5}]}
+	HTTPURL   string
+	HTTPToken string
+}
+
+// ImageModerator detecta conteúdo impróprio (nudez, violência...) nas imagens
+// enviadas. Recebe JPEG ou PNG de até 5MB
+type ImageModerator interface {
+	Name() string
+	DetectLabels(data []byte) ([]models.ModerationLabel, error)
+}
+
+// NewImageModerator cria o moderador configurado; nil quando a moderação
+// automática está desativada
+func NewImageModerator(config *ImageModerationConfig) ImageModerator {
+	if config == nil {
+		return nil
+	}
+
+	switch config.Provider {
+	case "rekognition":
+		awsConfig := &aws.Config{Region: aws.String(config.AWSRegion)}
+		if config.AWSAccessKey != "" {
+			awsConfig.Credentials = credentials.NewStaticCredentials(config.AWSAccessKey, config.AWSSecretKey, "")
+		}
+		sess, err := session.NewSession(awsConfig)
+		if err != nil {
+			log.Printf("Falha ao configurar a Rekognition; moderação de imagens desativada: %v", err)
+			return nil
+		}
+		return &rekognitionModerator{client: rekognition.New(sess), minConfidence: config.MinConfidence}
+	case "http":
+		if config.HTTPURL == "" {
+			log.Println("MEDIA_MODERATION_URL não configurada; moderação de imagens desativada")
+			return nil
+		}
+		return &httpModerator{
+			url:    config.HTTPURL,
+			token:  config.HTTPToken,
+			client: &http.Client{Timeout: moderationTimeout},
+		}
+	case "", "none":
+		return nil
+	default:
+		log.Printf("Moderação de imagens desconhecida %q; desativada", config.Provider)
+		return nil
+	}
+}
+
+// moderateImage classifica a imagem antes de registrá-la. Se o moderador
+// falhar, a imagem vai para a revisão manual em vez de ser publicada sem análise
+func (s *MediaService) moderateImage(data []byte, mimeType string) (models.MediaModerationStatus, []models.ModerationLabel) {
+	if s.moderator == nil {
+		return models.MediaModerationApproved, nil
+	}
+
+	input, err := moderationInput(data, mimeType)
+	var labels []models.ModerationLabel
+	if err == nil {
+		labels, err = s.moderator.DetectLabels(input)
+	}
+	if err != nil {
+		log.Printf("Falha na moderação automática (%s); imagem enviada para revisão: %v", s.moderator.Name(), err)
+		return models.MediaModerationFlagged, nil
+	}
+
+	config := s.config.Moderation
+	for _, label := range labels {
+		if label.Confidence < config.MinConfidence {
+			continue
+		}
+		for _, blocked := range config.Labels {
+			if strings.EqualFold(label.Name, blocked) || strings.EqualFold(label.Parent, blocked) {
+				return models.MediaModerationFlagged, labels
+			}
+		}
+	}
+	return models.MediaModerationApproved, nil
+}
+
+// moderationInput entrega a imagem num formato e tamanho aceitos pelos
+// moderadores (JPEG ou PNG de até 5MB), reduzindo-a quando preciso
+func moderationInput(data []byte, mimeType string) ([]byte, error) {
+	if (mimeType == "image/jpeg" || mimeType == "image/png") && len(data) <= maxModerationImageSize {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("formato %s não suportado pela moderação", mimeType)
+	}
+
+	src := toRGBA(img)
+	bounds := src.Bounds()
+	width, height := variantSize(bounds.Dx(), bounds.Dy(), ImageVariant{Size: moderationImageSide})
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resizeImage(src, width, height), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ============================================================================
+// AMAZON REKOGNITION
+// ============================================================================
+
+type rekognitionModerator struct {
+	client        *rekognition.Rekognition
+	minConfidence float64
+}
+
+func (m *rekognitionModerator) Name() string { return "rekognition" }
+
+func (m *rekognitionModerator) DetectLabels(data []byte) ([]models.ModerationLabel, error) {
+	output, err := m.client.DetectModerationLabels(&rekognition.DetectModerationLabelsInput{
+		Image:         &rekognition.Image{Bytes: data},
+		MinConfidence: aws.Float64(m.minConfidence),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]models.ModerationLabel, 0, len(output.ModerationLabels))
+	for _, label := range output.ModerationLabels {
+		labels = append(labels, models.ModerationLabel{
+			Name:       aws.StringValue(label.Name),
+			Parent:     aws.StringValue(label.ParentName),
+			Confidence: aws.Float64Value(label.Confidence),
+		})
+	}
+	return labels, nil
+}
+
+// ============================================================================
+// MODELO PRÓPRIO (HTTP)
+// ============================================================================
+
+type httpModerator struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (m *httpModerator) Name() string { return "http" }
+
+func (m *httpModerator) DetectLabels(data []byte) ([]models.ModerationLabel, error) {
+	req, err := http.NewRequest(http.MethodPost, m.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", http.DetectContentType(data))
+	if m.token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.token)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("moderador respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Labels []models.ModerationLabel `json:"labels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.New("resposta inválida do moderador")
+	}
+	return result.Labels, nil
+}
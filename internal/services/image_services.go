@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+const (
+	variantJPEGQuality = 82
+	variantWebPQuality = 80
+	webpEncodeTimeout  = 30 * time.Second
+)
+
+// ImageVariant é um tamanho gerado automaticamente para cada imagem enviada,
+// para que os feeds não carreguem as fotos em resolução original
+type ImageVariant struct {
+	Name string
+	// Maior lado da variante, em pixels; imagens menores não são ampliadas
+	Size int
+	// Recorta o centro da imagem em um quadrado (miniaturas)
+	Crop bool
+	// Vazio mantém JPEG (ou PNG, se houver transparência); "webp" usa o cwebp
+	Format string
+}
+
+// DefaultImageVariants são as variantes geradas quando nenhuma é configurada
+var DefaultImageVariants = []ImageVariant{
+	{Name: "thumb", Size: 200, Crop: true},
+	{Name: "medium", Size: 720},
+	{Name: "large", Size: 1440},
+	{Name: "webp", Size: 1440, Format: "webp"},
+}
+
+// generateVariants grava as variantes configuradas ao lado do original.
+// Falhas não impedem o upload: a variante é omitida e o cliente usa o original
+func (s *MediaService) generateVariants(data []byte, orientation int, original *models.MediaVariant, fileName, directory string) map[string]models.MediaVariant {
+	if len(s.config.ImageVariants) == 0 {
+		return nil
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("Variantes não geradas para %s: %v", fileName, err)
+		return nil
+	}
+	// Redimensionar um GIF perderia a animação
+	if format == "gif" {
+		return nil
+	}
+
+	src := applyOrientation(toRGBA(img), orientation)
+	bounds := src.Bounds()
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+
+	variants := make(map[string]models.MediaVariant, len(s.config.ImageVariants))
+	for _, variant := range s.config.ImageVariants {
+		if variant.Format == "webp" && s.webpEncoder == "" {
+			continue
+		}
+
+		width, height := variantSize(bounds.Dx(), bounds.Dy(), variant)
+		if variant.Format == "" && width == bounds.Dx() && height == bounds.Dy() {
+			variants[variant.Name] = models.MediaVariant{
+				URL:      original.URL,
+				MimeType: original.MimeType,
+				Width:    width,
+				Height:   height,
+			}
+			continue
+		}
+
+		region := src
+		if variant.Crop {
+			region = centerCrop(src, width)
+		}
+		resized := resizeImage(region, width, height)
+
+		encoded, extension, mimeType, err := s.encodeVariant(resized, variant.Format)
+		if err != nil {
+			log.Printf("Falha ao gerar variante %s de %s: %v", variant.Name, fileName, err)
+			continue
+		}
+
+		variantName := fmt.Sprintf("%s_%s%s", base, variant.Name, extension)
+		filePath, url, err := s.store(bytes.NewReader(encoded), mimeType, variantName, directory)
+		if err != nil {
+			log.Printf("Falha ao gravar variante %s de %s: %v", variant.Name, fileName, err)
+			continue
+		}
+
+		variants[variant.Name] = models.MediaVariant{
+			URL:      url,
+			FilePath: filePath,
+			MimeType: mimeType,
+			Width:    width,
+			Height:   height,
+		}
+	}
+
+	return variants
+}
+
+func (s *MediaService) encodeVariant(img *image.RGBA, format string) ([]byte, string, string, error) {
+	if format == "webp" {
+		data, err := s.encodeWebP(img)
+		return data, ".webp", "image/webp", err
+	}
+
+	var buf bytes.Buffer
+	if !img.Opaque() {
+		err := png.Encode(&buf, img)
+		return buf.Bytes(), ".png", "image/png", err
+	}
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: variantJPEGQuality})
+	return buf.Bytes(), ".jpg", "image/jpeg", err
+}
+
+// encodeWebP converte a imagem com o cwebp; a biblioteca padrão do Go só
+// decodifica WebP
+func (s *MediaService) encodeWebP(img *image.RGBA) ([]byte, error) {
+	if s.webpEncoder == "" {
+		return nil, errors.New("encoder webp não configurado")
+	}
+
+	dir, err := os.MkdirTemp("", "guia-webp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.png")
+	output := filepath.Join(dir, "output.webp")
+
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webpEncodeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.webpEncoder, "-quiet", "-q", fmt.Sprint(variantWebPQuality), input, "-o", output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("cwebp: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return os.ReadFile(output)
+}
+
+// removeVariants apaga os arquivos das variantes; as que apontam para o
+// original não têm arquivo próprio
+func (s *MediaService) removeVariants(variants map[string]models.MediaVariant) {
+	for name, variant := range variants {
+		if variant.FilePath == "" {
+			continue
+		}
+		if err := s.removeStoredFile(variant.FilePath); err != nil {
+			log.Printf("Falha ao remover variante %s (%s): %v", name, variant.FilePath, err)
+		}
+	}
+}
+
+// variantSize calcula as dimensões da variante mantendo a proporção, sem
+// ampliar imagens menores que o tamanho configurado
+func variantSize(width, height int, variant ImageVariant) (int, int) {
+	if variant.Crop {
+		side := min(width, height, variant.Size)
+		return side, side
+	}
+	if width <= variant.Size && height <= variant.Size {
+		return width, height
+	}
+	if width >= height {
+		return variant.Size, max(1, height*variant.Size/width)
+	}
+	return max(1, width*variant.Size/height), variant.Size
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// centerCrop recorta o maior quadrado central com o lado informado ou maior,
+// que depois é reduzido para o tamanho da variante
+func centerCrop(img *image.RGBA, side int) *image.RGBA {
+	bounds := img.Bounds()
+	crop := min(bounds.Dx(), bounds.Dy())
+	if crop < side {
+		crop = side
+	}
+	x := bounds.Min.X + (bounds.Dx()-crop)/2
+	y := bounds.Min.Y + (bounds.Dy()-crop)/2
+	return img.SubImage(image.Rect(x, y, x+crop, y+crop)).(*image.RGBA)
+}
+
+// resizeImage reduz a imagem pela média de cada área de pixels de origem,
+// o que evita o serrilhado do vizinho mais próximo em fotos grandes
+func resizeImage(src *image.RGBA, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := max(bounds.Min.Y+(y+1)*srcHeight/height, y0+1)
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := max(bounds.Min.X+(x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					b += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -2,8 +2,14 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/Ulpio/guIA-backend/internal/events"
 	"github.com/Ulpio/guIA-backend/internal/models"
 	"github.com/Ulpio/guIA-backend/internal/repositories"
 )
@@ -14,12 +20,22 @@ type ItineraryServiceInterface interface {
 	UpdateItinerary(itineraryID, userID uint, req *UpdateItineraryRequest) (*models.ItineraryResponse, error)
 	DeleteItinerary(itineraryID, userID uint) error
 	GetItineraries(filters *ItineraryFilters, currentUserID uint) ([]models.ItineraryResponse, error)
+	GetItinerariesByIDs(itineraryIDs []uint, currentUserID uint) ([]models.ItineraryResponse, error)
 	GetItinerariesByAuthor(authorID, currentUserID uint, limit, offset int) ([]models.ItineraryResponse, error)
+	GetItinerariesByCity(cityID, currentUserID uint, limit, offset int) ([]models.ItineraryResponse, error)
 	SearchItineraries(query string, currentUserID uint, limit, offset int) ([]models.ItineraryResponse, error)
+	SearchItinerariesWithFacets(req *ItinerarySearchRequest, currentUserID uint) ([]models.ItineraryResponse, *models.ItinerarySearchFacets, error)
 	RateItinerary(userID, itineraryID uint, rating int, comment string) error
 	UpdateRating(userID, itineraryID uint, rating int, comment string) error
 	DeleteRating(userID, itineraryID uint) error
+	LikeItinerary(userID, itineraryID uint) error
+	UnlikeItinerary(userID, itineraryID uint) error
+	CloneItinerary(itineraryID, userID uint) (*models.ItineraryResponse, error)
 	GetSimilarItineraries(itineraryID uint, limit int) ([]models.ItineraryResponse, error)
+	GetTrendingDestinations(limit int) ([]models.TrendingDestination, error)
+	GetTripSuggestions(itineraryID, currentUserID uint, limit int) ([]models.PlaceSuggestion, error)
+	AddSuggestedPlace(itineraryID, dayID, userID uint, req *AddSuggestedPlaceRequest) (*models.ItineraryResponse, error)
+	ImportTrack(itineraryID, userID uint, fileName string, data []byte) (*TrackImportResult, error)
 }
 
 type CreateItineraryRequest struct {
@@ -81,6 +97,10 @@ type UpdateItineraryRequest struct {
 	IsPublic      *bool                     `json:"is_public,omitempty"`
 }
 
+type AddSuggestedPlaceRequest struct {
+	LocationID uint `json:"location_id" binding:"required"`
+}
+
 type ItineraryFilters struct {
 	Category    models.ItineraryCategory `json:"category"`
 	Country     string                   `json:"country"`
@@ -91,18 +111,69 @@ type ItineraryFilters struct {
 	MaxCost     float64                  `json:"max_cost"`
 	Difficulty  int                      `json:"difficulty"`
 	IsFeatured  bool                     `json:"is_featured"`
-	OrderBy     string                   `json:"order_by"` // "recent", "popular", "rating"
+	OrderBy     string                   `json:"order_by"` // "recent", "popular", "rating", "cost_asc", "cost_desc"
+	Currency    string                   `json:"currency"` // moeda dos filtros e custos normalizados
 	Limit       int                      `json:"limit"`
 	Offset      int                      `json:"offset"`
+
+	// País do visitante, usado para escolher a moeda quando Currency não é
+	// informada; preenchido pelo handler
+	ViewerCountry string `json:"-"`
+}
+
+// ItinerarySearchRequest é a busca textual de roteiros com os filtros da
+// listagem. Com Facets, as contagens por faceta vêm junto dos resultados
+type ItinerarySearchRequest struct {
+	Query       string                   `json:"query"`
+	Category    models.ItineraryCategory `json:"category"`
+	Country     string                   `json:"country"`
+	City        string                   `json:"city"`
+	MinDuration int                      `json:"min_duration"`
+	MaxDuration int                      `json:"max_duration"`
+	MinCost     float64                  `json:"min_cost"`
+	MaxCost     float64                  `json:"max_cost"`
+	Difficulty  int                      `json:"difficulty"`
+	Currency    string                   `json:"currency"`
+	Geo         *GeoFilter               `json:"-"` // roteiros com algum local na área
+	Facets      bool                     `json:"facets"`
+	Limit       int                      `json:"limit"`
+	Offset      int                      `json:"offset"`
+
+	ViewerCountry string `json:"-"`
+}
+
+// Faixas de duração (em dias) da faceta da busca de roteiros
+var itineraryDurationFacets = []repositories.FacetRange{
+	{Min: 1, Max: 3},
+	{Min: 4, Max: 7},
+	{Min: 8, Max: 14},
+	{Min: 15},
+}
+
+// Faixas de preço da faceta da busca de roteiros, em USD; são convertidas
+// para a moeda de quem busca
+var itineraryPriceFacetsUSD = []repositories.FacetRange{
+	{Min: 0, Max: 300},
+	{Min: 300, Max: 1000},
+	{Min: 1000, Max: 3000},
+	{Min: 3000},
 }
 
 type ItineraryService struct {
-	itineraryRepo repositories.ItineraryRepositoryInterface
+	itineraryRepo   repositories.ItineraryRepositoryInterface
+	geoService      GeoServiceInterface
+	eventBus        events.BusInterface
+	currencyService CurrencyServiceInterface
+	profanity       ProfanityServiceInterface
 }
 
-func NewItineraryService(itineraryRepo repositories.ItineraryRepositoryInterface) ItineraryServiceInterface {
+func NewItineraryService(itineraryRepo repositories.ItineraryRepositoryInterface, geoService GeoServiceInterface, eventBus events.BusInterface, currencyService CurrencyServiceInterface, profanity ProfanityServiceInterface) ItineraryServiceInterface {
 	return &ItineraryService{
-		itineraryRepo: itineraryRepo,
+		itineraryRepo:   itineraryRepo,
+		geoService:      geoService,
+		eventBus:        eventBus,
+		currencyService: currencyService,
+		profanity:       profanity,
 	}
 }
 
@@ -130,6 +201,9 @@ func (s *ItineraryService) CreateItinerary(userID uint, req *CreateItineraryRequ
 		IsPublic:      req.IsPublic,
 	}
 
+	// Normalizar país/estado/cidade para as entradas de referência
+	s.applyGeoReference(itinerary)
+
 	if err := s.itineraryRepo.Create(itinerary); err != nil {
 		return nil, errors.New("erro ao criar roteiro")
 	}
@@ -141,6 +215,16 @@ func (s *ItineraryService) CreateItinerary(userID uint, req *CreateItineraryRequ
 		}
 	}
 
+	s.eventBus.Publish(events.Event{
+		Type:     events.ItineraryCreated,
+		ActorID:  userID,
+		EntityID: itinerary.ID,
+		Data: map[string]string{
+			"category": string(itinerary.Category),
+			"text":     strings.Join([]string{itinerary.Title, itinerary.Description, itinerary.City, itinerary.State, itinerary.Country}, " "),
+		},
+	})
+
 	// Buscar roteiro criado com dados completos
 	createdItinerary, err := s.itineraryRepo.GetByID(itinerary.ID)
 	if err != nil {
@@ -156,9 +240,12 @@ func (s *ItineraryService) GetItineraryByID(itineraryID, currentUserID uint) (*m
 		return nil, errors.New("roteiro não encontrado")
 	}
 
-	// Verificar se o roteiro é público ou se o usuário é o autor
+	// Roteiros privados só aparecem para o autor e os participantes da viagem
 	if !itinerary.IsPublic && itinerary.AuthorID != currentUserID {
-		return nil, errors.New("roteiro não encontrado")
+		role, err := s.itineraryRepo.GetParticipantRole(itineraryID, currentUserID)
+		if err != nil || role == "" {
+			return nil, errors.New("roteiro não encontrado")
+		}
 	}
 
 	// Incrementar visualizações se não for o autor
@@ -166,7 +253,34 @@ func (s *ItineraryService) GetItineraryByID(itineraryID, currentUserID uint) (*m
 		s.itineraryRepo.IncrementViews(itineraryID)
 	}
 
-	return itinerary.ToResponse(), nil
+	s.resolveDayTimezones(itinerary)
+
+	response := itinerary.ToResponse()
+	s.setViewerFlags(currentUserID, []*models.ItineraryResponse{response})
+	return response, nil
+}
+
+// GetItinerariesByIDs retorna os roteiros públicos na ordem dos IDs, omitindo
+// os removidos, privados ou ocultados
+func (s *ItineraryService) GetItinerariesByIDs(itineraryIDs []uint, currentUserID uint) ([]models.ItineraryResponse, error) {
+	itineraries, err := s.itineraryRepo.GetPublicByIDs(itineraryIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiros")
+	}
+
+	byID := make(map[uint]*models.Itinerary, len(itineraries))
+	for i := range itineraries {
+		byID[itineraries[i].ID] = &itineraries[i]
+	}
+
+	responses := make([]models.ItineraryResponse, 0, len(itineraries))
+	for _, id := range itineraryIDs {
+		if itinerary, ok := byID[id]; ok {
+			responses = append(responses, *itinerary.ToResponse())
+		}
+	}
+	s.setViewerFlags(currentUserID, responsePointers(responses))
+	return responses, nil
 }
 
 func (s *ItineraryService) UpdateItinerary(itineraryID, userID uint, req *UpdateItineraryRequest) (*models.ItineraryResponse, error) {
@@ -180,6 +294,7 @@ func (s *ItineraryService) UpdateItinerary(itineraryID, userID uint, req *Update
 	if itinerary.AuthorID != userID {
 		return nil, errors.New("você não tem permissão para editar este roteiro")
 	}
+	before := *itinerary
 
 	// Validar e atualizar campos
 	if req.Title != nil {
@@ -195,7 +310,7 @@ func (s *ItineraryService) UpdateItinerary(itineraryID, userID uint, req *Update
 	}
 
 	if req.Category != nil {
-		if err := s.validateCategory(*req.Category); err != nil {
+		if err := validateItineraryCategory(*req.Category); err != nil {
 			return nil, err
 		}
 		itinerary.Category = *req.Category
@@ -206,7 +321,7 @@ func (s *ItineraryService) UpdateItinerary(itineraryID, userID uint, req *Update
 	}
 
 	if req.Currency != nil {
-		itinerary.Currency = *req.Currency
+		itinerary.Currency = s.getDefaultCurrency(*req.Currency)
 	}
 
 	if req.Duration != nil {
@@ -251,10 +366,26 @@ func (s *ItineraryService) UpdateItinerary(itineraryID, userID uint, req *Update
 		itinerary.IsPublic = *req.IsPublic
 	}
 
+	if req.Country != nil || req.State != nil || req.City != nil {
+		s.applyGeoReference(itinerary)
+	}
+
 	if err := s.itineraryRepo.Update(itinerary); err != nil {
 		return nil, errors.New("erro ao atualizar roteiro")
 	}
 
+	if changed := itineraryChanges(&before, itinerary); len(changed) > 0 {
+		s.eventBus.Publish(events.Event{
+			Type:     events.ItineraryUpdated,
+			ActorID:  userID,
+			EntityID: itineraryID,
+			Data: map[string]string{
+				"fields":    strings.Join(changed, ","),
+				"old_title": before.Title,
+			},
+		})
+	}
+
 	// Buscar roteiro atualizado
 	updatedItinerary, err := s.itineraryRepo.GetByID(itineraryID)
 	if err != nil {
@@ -264,6 +395,36 @@ func (s *ItineraryService) UpdateItinerary(itineraryID, userID uint, req *Update
 	return updatedItinerary.ToResponse(), nil
 }
 
+// itineraryChanges lista os campos do roteiro que mudaram na edição, pelos
+// nomes da API
+func itineraryChanges(before, after *models.Itinerary) []string {
+	var changed []string
+	if before.Title != after.Title {
+		changed = append(changed, "title")
+	}
+	if before.Description != after.Description {
+		changed = append(changed, "description")
+	}
+	if before.Category != after.Category {
+		changed = append(changed, "category")
+	}
+	if (before.EstimatedCost == nil) != (after.EstimatedCost == nil) ||
+		(before.EstimatedCost != nil && *before.EstimatedCost != *after.EstimatedCost) ||
+		before.Currency != after.Currency {
+		changed = append(changed, "estimated_cost")
+	}
+	if before.Duration != after.Duration {
+		changed = append(changed, "duration")
+	}
+	if before.Difficulty != after.Difficulty {
+		changed = append(changed, "difficulty")
+	}
+	if before.Country != after.Country || before.State != after.State || before.City != after.City {
+		changed = append(changed, "location")
+	}
+	return changed
+}
+
 func (s *ItineraryService) DeleteItinerary(itineraryID, userID uint) error {
 	// Buscar roteiro
 	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
@@ -288,8 +449,51 @@ func (s *ItineraryService) GetItineraries(filters *ItineraryFilters, currentUser
 		filters.Limit = 20
 	}
 
+	// Custos são comparados na moeda pedida ou, sem ela, na moeda do país do
+	// visitante
+	currency := normalizeCurrency(filters.Currency)
+	if currency == "" {
+		currency = s.currencyService.CurrencyForCountry(filters.ViewerCountry)
+	}
+	if !isValidCurrency(currency) {
+		return nil, errors.New("moeda inválida")
+	}
+	if filters.MinCost < 0 || filters.MaxCost < 0 {
+		return nil, errors.New("custo não pode ser negativo")
+	}
+	if filters.MaxCost > 0 && filters.MinCost > filters.MaxCost {
+		return nil, errors.New("custo mínimo não pode ser maior que o máximo")
+	}
+
 	// Buscar baseado nos filtros
 	switch {
+	case filters.MinCost > 0 || filters.MaxCost > 0 || filters.OrderBy == "cost_asc" || filters.OrderBy == "cost_desc":
+		// Sem a cotação da moeda pedida, só os roteiros na mesma moeda
+		// podem ser comparados
+		rate, _ := s.currencyService.RateFor(currency)
+		sortByCost := ""
+		switch filters.OrderBy {
+		case "cost_asc":
+			sortByCost = "asc"
+		case "cost_desc":
+			sortByCost = "desc"
+		}
+		itineraries, err = s.itineraryRepo.GetByCost(repositories.ItineraryCostFilter{
+			Category:     filters.Category,
+			Country:      filters.Country,
+			City:         filters.City,
+			MinDuration:  filters.MinDuration,
+			MaxDuration:  filters.MaxDuration,
+			Difficulty:   filters.Difficulty,
+			IsFeatured:   filters.IsFeatured,
+			MinCost:      filters.MinCost,
+			MaxCost:      filters.MaxCost,
+			Currency:     currency,
+			CurrencyRate: rate,
+			SortByCost:   sortByCost,
+			Limit:        filters.Limit,
+			Offset:       filters.Offset,
+		})
 	case filters.Category != "":
 		itineraries, err = s.itineraryRepo.GetByCategory(filters.Category, filters.Limit, filters.Offset)
 	case filters.IsFeatured:
@@ -307,12 +511,44 @@ func (s *ItineraryService) GetItineraries(filters *ItineraryFilters, currentUser
 
 	var responses []models.ItineraryResponse
 	for _, itinerary := range itineraries {
-		responses = append(responses, *itinerary.ToResponse())
+		response := itinerary.ToResponse()
+		s.setNormalizedCost(response, currency)
+		responses = append(responses, *response)
 	}
+	s.setViewerFlags(currentUserID, responsePointers(responses))
 
 	return responses, nil
 }
 
+// setNormalizedCost preenche o custo convertido para a moeda do visitante,
+// quando há cotação para as duas moedas
+func (s *ItineraryService) setNormalizedCost(response *models.ItineraryResponse, currency string) {
+	if response.EstimatedCost == nil {
+		return
+	}
+	cost, ok := s.currencyService.Convert(*response.EstimatedCost, response.Currency, currency)
+	if !ok {
+		return
+	}
+	response.NormalizedCost = &cost
+	response.NormalizedCurrency = currency
+}
+
+// GetTrendingDestinations retorna os destinos com mais engajamento nos
+// roteiros públicos dos últimos 30 dias
+func (s *ItineraryService) GetTrendingDestinations(limit int) ([]models.TrendingDestination, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	destinations, err := s.itineraryRepo.GetTrendingDestinations(time.Now().AddDate(0, 0, -30), limit)
+	if err != nil {
+		return nil, errors.New("erro ao buscar destinos em alta")
+	}
+
+	return destinations, nil
+}
+
 func (s *ItineraryService) GetItinerariesByAuthor(authorID, currentUserID uint, limit, offset int) ([]models.ItineraryResponse, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 20
@@ -327,6 +563,26 @@ func (s *ItineraryService) GetItinerariesByAuthor(authorID, currentUserID uint,
 	for _, itinerary := range itineraries {
 		responses = append(responses, *itinerary.ToResponse())
 	}
+	s.setViewerFlags(currentUserID, responsePointers(responses))
+
+	return responses, nil
+}
+
+func (s *ItineraryService) GetItinerariesByCity(cityID, currentUserID uint, limit, offset int) ([]models.ItineraryResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	itineraries, err := s.itineraryRepo.GetPopularByCity(cityID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiros do destino")
+	}
+
+	responses := make([]models.ItineraryResponse, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		responses = append(responses, *itinerary.ToResponse())
+	}
+	s.setViewerFlags(currentUserID, responsePointers(responses))
 
 	return responses, nil
 }
@@ -349,10 +605,119 @@ func (s *ItineraryService) SearchItineraries(query string, currentUserID uint, l
 	for _, itinerary := range itineraries {
 		responses = append(responses, *itinerary.ToResponse())
 	}
+	s.setViewerFlags(currentUserID, responsePointers(responses))
 
 	return responses, nil
 }
 
+// SearchItinerariesWithFacets busca roteiros com os filtros da listagem e,
+// quando pedido, conta os resultados por categoria, país, dificuldade,
+// duração e faixa de preço, para o app mostrar os filtros com as contagens
+func (s *ItineraryService) SearchItinerariesWithFacets(req *ItinerarySearchRequest, currentUserID uint) ([]models.ItineraryResponse, *models.ItinerarySearchFacets, error) {
+	if strings.TrimSpace(req.Query) == "" && req.Geo == nil {
+		return []models.ItineraryResponse{}, nil, nil
+	}
+
+	area, err := req.Geo.area()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	currency := normalizeCurrency(req.Currency)
+	if currency == "" {
+		currency = s.currencyService.CurrencyForCountry(req.ViewerCountry)
+	}
+	if !isValidCurrency(currency) {
+		return nil, nil, errors.New("moeda inválida")
+	}
+	if req.MinCost < 0 || req.MaxCost < 0 {
+		return nil, nil, errors.New("custo não pode ser negativo")
+	}
+	if req.MaxCost > 0 && req.MinCost > req.MaxCost {
+		return nil, nil, errors.New("custo mínimo não pode ser maior que o máximo")
+	}
+	if req.MaxDuration > 0 && req.MinDuration > req.MaxDuration {
+		return nil, nil, errors.New("duração mínima não pode ser maior que a máxima")
+	}
+
+	rate, _ := s.currencyService.RateFor(currency)
+	filter := repositories.ItinerarySearchFilter{
+		Query:        req.Query,
+		Area:         area,
+		Category:     req.Category,
+		Country:      req.Country,
+		City:         req.City,
+		MinDuration:  req.MinDuration,
+		MaxDuration:  req.MaxDuration,
+		Difficulty:   req.Difficulty,
+		MinCost:      req.MinCost,
+		MaxCost:      req.MaxCost,
+		Currency:     currency,
+		CurrencyRate: rate,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+	}
+
+	itineraries, err := s.itineraryRepo.SearchFiltered(filter)
+	if err != nil {
+		return nil, nil, errors.New("erro ao buscar roteiros")
+	}
+
+	responses := make([]models.ItineraryResponse, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		response := itinerary.ToResponse()
+		s.setNormalizedCost(response, currency)
+		responses = append(responses, *response)
+	}
+	s.setViewerFlags(currentUserID, responsePointers(responses))
+
+	if !req.Facets {
+		return responses, nil, nil
+	}
+
+	facets, err := s.itineraryRepo.GetSearchFacets(filter, itineraryDurationFacets, priceFacetRanges(currency, rate))
+	if err != nil {
+		return nil, nil, errors.New("erro ao contar os filtros da busca")
+	}
+	return responses, facets, nil
+}
+
+// priceFacetRanges converte as faixas de preço para a moeda, arredondando os
+// limites para dois algarismos significativos (1620 vira 1600). Sem cotação,
+// não há faixas
+func priceFacetRanges(currency string, rate float64) []repositories.FacetRange {
+	if currency == "USD" {
+		return itineraryPriceFacetsUSD
+	}
+	if rate <= 0 {
+		return nil
+	}
+
+	ranges := make([]repositories.FacetRange, len(itineraryPriceFacetsUSD))
+	for i, band := range itineraryPriceFacetsUSD {
+		ranges[i] = repositories.FacetRange{
+			Min: roundSignificant(band.Min * rate),
+			Max: roundSignificant(band.Max * rate),
+		}
+	}
+	return ranges
+}
+
+func roundSignificant(value float64) float64 {
+	if value <= 0 {
+		return 0
+	}
+	scale := math.Pow(10, math.Floor(math.Log10(value))-1)
+	return math.Round(value/scale) * scale
+}
+
 func (s *ItineraryService) RateItinerary(userID, itineraryID uint, rating int, comment string) error {
 	// Verificar se o roteiro existe
 	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
@@ -375,7 +740,26 @@ func (s *ItineraryService) RateItinerary(userID, itineraryID uint, rating int, c
 		return errors.New("você já avaliou este roteiro")
 	}
 
-	return s.itineraryRepo.RateItinerary(userID, itineraryID, rating, strings.TrimSpace(comment))
+	filtered, err := s.profanity.Filter(strings.TrimSpace(comment))
+	if err != nil {
+		return err
+	}
+
+	if err := s.itineraryRepo.RateItinerary(userID, itineraryID, rating, filtered.Text); err != nil {
+		return err
+	}
+	s.flagRating(userID, itineraryID, filtered)
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.ItineraryRated,
+		ActorID:  userID,
+		EntityID: itineraryID,
+		Data: map[string]string{
+			"author_id": fmt.Sprint(itinerary.AuthorID),
+			"rating":    fmt.Sprint(rating),
+		},
+	})
+	return nil
 }
 
 func (s *ItineraryService) UpdateRating(userID, itineraryID uint, rating int, comment string) error {
@@ -389,7 +773,31 @@ func (s *ItineraryService) UpdateRating(userID, itineraryID uint, rating int, co
 		return err
 	}
 
-	return s.itineraryRepo.UpdateRating(userID, itineraryID, rating, strings.TrimSpace(comment))
+	filtered, err := s.profanity.Filter(strings.TrimSpace(comment))
+	if err != nil {
+		return err
+	}
+
+	if err := s.itineraryRepo.UpdateRating(userID, itineraryID, rating, filtered.Text); err != nil {
+		return err
+	}
+	s.flagRating(userID, itineraryID, filtered)
+	return nil
+}
+
+// flagRating coloca na fila de moderação a avaliação cujo comentário teve
+// termos sinalizados pelo filtro de palavrões
+func (s *ItineraryService) flagRating(userID, itineraryID uint, filtered *ProfanityResult) {
+	if len(filtered.Flagged) == 0 {
+		return
+	}
+
+	rating, err := s.itineraryRepo.GetUserRating(userID, itineraryID)
+	if err != nil {
+		log.Printf("Falha ao buscar avaliação do usuário %d no roteiro %d para sinalizar: %v", userID, itineraryID, err)
+		return
+	}
+	s.profanity.FlagContent(models.ReportTargetRating, rating.ID, userID, filtered)
 }
 
 func (s *ItineraryService) DeleteRating(userID, itineraryID uint) error {
@@ -401,6 +809,135 @@ func (s *ItineraryService) DeleteRating(userID, itineraryID uint) error {
 	return s.itineraryRepo.DeleteRating(userID, itineraryID)
 }
 
+func (s *ItineraryService) LikeItinerary(userID, itineraryID uint) error {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return errors.New("roteiro não encontrado")
+	}
+
+	liked, err := s.itineraryRepo.LikeItinerary(userID, itineraryID)
+	if err != nil {
+		return errors.New("erro ao curtir roteiro")
+	}
+	if !liked {
+		return errors.New("você já curtiu este roteiro")
+	}
+
+	return nil
+}
+
+// CloneItinerary copia dias e locais de um roteiro visível para um novo
+// roteiro privado do usuário ("usar este roteiro")
+func (s *ItineraryService) CloneItinerary(itineraryID, userID uint) (*models.ItineraryResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	clone, err := s.itineraryRepo.Clone(itinerary.ID, userID)
+	if err != nil {
+		return nil, errors.New("erro ao copiar roteiro")
+	}
+
+	created, err := s.itineraryRepo.GetByID(clone.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiro copiado")
+	}
+
+	return created.ToResponse(), nil
+}
+
+// ImportTrack cria dias e locais a partir dos pontos de interesse de um
+// arquivo GPX ou KML, depois dos dias que o roteiro já tem
+func (s *ItineraryService) ImportTrack(itineraryID, userID uint, fileName string, data []byte) (*TrackImportResult, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para editar este roteiro")
+	}
+
+	trackDays, skipped, err := parseTrackFile(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDay := 0
+	for _, day := range itinerary.Days {
+		if day.DayNumber > lastDay {
+			lastDay = day.DayNumber
+		}
+	}
+	if err := s.validateDuration(lastDay + len(trackDays)); err != nil {
+		return nil, err
+	}
+
+	days := make([]models.ItineraryDay, 0, len(trackDays))
+	locationsCount := 0
+	for i, trackDay := range trackDays {
+		day := models.ItineraryDay{
+			DayNumber: lastDay + i + 1,
+			Title:     truncateString(trackDay.Title, 200),
+		}
+		for order, point := range trackDay.Points {
+			latitude, longitude := point.Latitude, point.Longitude
+			locationsCount++
+
+			name := point.Name
+			if name == "" {
+				name = fmt.Sprintf("Ponto %d", locationsCount)
+			}
+			day.Locations = append(day.Locations, models.ItineraryLocation{
+				Name:         truncateString(name, 200),
+				Description:  point.Description,
+				LocationType: trackLocationType(point.Type),
+				Latitude:     &latitude,
+				Longitude:    &longitude,
+				StartTime:    point.Time,
+				Order:        order,
+			})
+		}
+		days = append(days, day)
+	}
+
+	if err := s.itineraryRepo.AddDays(itinerary.ID, days); err != nil {
+		return nil, errors.New("erro ao importar dias do roteiro")
+	}
+
+	updated, err := s.itineraryRepo.GetByID(itinerary.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiro atualizado")
+	}
+	s.resolveDayTimezones(updated)
+
+	if skipped == nil {
+		skipped = []string{}
+	}
+	return &TrackImportResult{
+		Itinerary:        updated.ToResponse(),
+		DaysCreated:      len(days),
+		LocationsCreated: locationsCount,
+		Skipped:          skipped,
+	}, nil
+}
+
+func (s *ItineraryService) UnlikeItinerary(userID, itineraryID uint) error {
+	if _, err := s.itineraryRepo.GetByID(itineraryID); err != nil {
+		return errors.New("roteiro não encontrado")
+	}
+
+	unliked, err := s.itineraryRepo.UnlikeItinerary(userID, itineraryID)
+	if err != nil {
+		return errors.New("erro ao descurtir roteiro")
+	}
+	if !unliked {
+		return errors.New("você não curtiu este roteiro")
+	}
+
+	return nil
+}
+
 func (s *ItineraryService) GetSimilarItineraries(itineraryID uint, limit int) ([]models.ItineraryResponse, error) {
 	if limit <= 0 || limit > 20 {
 		limit = 5
@@ -419,16 +956,254 @@ func (s *ItineraryService) GetSimilarItineraries(itineraryID uint, limit int) ([
 	return responses, nil
 }
 
+func (s *ItineraryService) GetTripSuggestions(itineraryID, currentUserID uint, limit int) ([]models.PlaceSuggestion, error) {
+	if limit <= 0 || limit > 30 {
+		limit = 10
+	}
+
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != currentUserID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	if itinerary.CityID == nil && strings.TrimSpace(itinerary.City) == "" {
+		return []models.PlaceSuggestion{}, nil
+	}
+
+	candidates, err := s.itineraryRepo.GetPlaceCandidates(itinerary, 300)
+	if err != nil {
+		return nil, errors.New("erro ao buscar sugestões")
+	}
+
+	// Locais que já fazem parte do roteiro e perfil de tipos de local do roteiro
+	existing := make(map[string]bool)
+	typeAffinity := make(map[models.LocationType]float64)
+	totalLocations := 0
+	for _, day := range itinerary.Days {
+		for _, location := range day.Locations {
+			existing[placeKey(&location)] = true
+			typeAffinity[location.LocationType]++
+			totalLocations++
+		}
+	}
+	for locationType := range typeAffinity {
+		typeAffinity[locationType] /= float64(totalLocations)
+	}
+
+	// Agrupar candidatos pelo mesmo lugar (place ID do Google ou nome)
+	suggestions := make(map[string]*models.PlaceSuggestion)
+	var order []string
+	for _, candidate := range candidates {
+		key := placeKey(&candidate)
+		if existing[key] {
+			continue
+		}
+
+		source := candidate.Day.Itinerary
+		if suggestion, ok := suggestions[key]; ok {
+			suggestion.Occurrences++
+			suggestion.Score += 1 + source.AverageRating/5
+			continue
+		}
+
+		score := 1 + source.AverageRating/5 + typeAffinity[candidate.LocationType]
+		if source.Category == itinerary.Category {
+			score += 0.5
+		}
+
+		location := candidate
+		location.Day = models.ItineraryDay{}
+		suggestions[key] = &models.PlaceSuggestion{
+			Location:          location,
+			SourceItineraryID: source.ID,
+			SourceTitle:       source.Title,
+			Occurrences:       1,
+			Score:             score,
+		}
+		order = append(order, key)
+	}
+
+	result := make([]models.PlaceSuggestion, 0, len(order))
+	for _, key := range order {
+		result = append(result, *suggestions[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func (s *ItineraryService) AddSuggestedPlace(itineraryID, dayID, userID uint, req *AddSuggestedPlaceRequest) (*models.ItineraryResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	var targetDay *models.ItineraryDay
+	for i := range itinerary.Days {
+		if itinerary.Days[i].ID == dayID {
+			targetDay = &itinerary.Days[i]
+			break
+		}
+	}
+	if targetDay == nil {
+		return nil, errors.New("dia não encontrado neste roteiro")
+	}
+
+	source, err := s.itineraryRepo.GetLocationByID(req.LocationID)
+	if err != nil {
+		return nil, errors.New("local sugerido não encontrado")
+	}
+	if !source.Day.Itinerary.IsPublic && source.Day.Itinerary.AuthorID != userID {
+		return nil, errors.New("local sugerido não encontrado")
+	}
+
+	// Leitores não podem editar o roteiro: criar uma cópia privada e
+	// adicionar o local ao dia correspondente da cópia
+	if itinerary.AuthorID != userID {
+		clone, err := s.itineraryRepo.Clone(itinerary.ID, userID)
+		if err != nil {
+			return nil, errors.New("erro ao copiar roteiro")
+		}
+
+		dayNumber := targetDay.DayNumber
+		targetDay = nil
+		for i := range clone.Days {
+			if clone.Days[i].DayNumber == dayNumber {
+				targetDay = &clone.Days[i]
+				break
+			}
+		}
+		if targetDay == nil {
+			return nil, errors.New("erro ao copiar roteiro")
+		}
+		itinerary = clone
+	}
+
+	location := &models.ItineraryLocation{
+		DayID:         targetDay.ID,
+		Name:          source.Name,
+		Description:   source.Description,
+		LocationType:  source.LocationType,
+		Address:       source.Address,
+		Latitude:      source.Latitude,
+		Longitude:     source.Longitude,
+		GooglePlaceID: source.GooglePlaceID,
+		EstimatedCost: source.EstimatedCost,
+		Order:         len(targetDay.Locations),
+		Images:        source.Images,
+		Website:       source.Website,
+		Phone:         source.Phone,
+		Rating:        source.Rating,
+	}
+
+	if err := s.itineraryRepo.AddLocation(location); err != nil {
+		return nil, errors.New("erro ao adicionar local")
+	}
+
+	updatedItinerary, err := s.itineraryRepo.GetByID(itinerary.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiro atualizado")
+	}
+
+	return updatedItinerary.ToResponse(), nil
+}
+
 // Funções auxiliares e validações
+func placeKey(location *models.ItineraryLocation) string {
+	if location.GooglePlaceID != "" {
+		return "place:" + location.GooglePlaceID
+	}
+	return "name:" + normalizeGeoName(location.Name)
+}
+
 func (s *ItineraryService) createItineraryDays(itineraryID uint, daysReq []CreateItineraryDayRequest) error {
 	// Implementação simplificada - em um sistema real, usaria transação
 	// e salvaria os dias no banco de dados
 	return nil
 }
 
+// setViewerFlags marca os roteiros curtidos e salvos pelo usuário atual; uma
+// falha na consulta apenas deixa o indicador falso
+func (s *ItineraryService) setViewerFlags(userID uint, responses []*models.ItineraryResponse) {
+	if userID == 0 || len(responses) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(responses))
+	for _, response := range responses {
+		ids = append(ids, response.ID)
+	}
+
+	liked, err := s.itineraryRepo.GetLikedItineraryIDs(userID, ids)
+	if err != nil {
+		liked = map[uint]bool{}
+	}
+	saved, err := s.itineraryRepo.GetSavedItineraryIDs(userID, ids)
+	if err != nil {
+		saved = map[uint]bool{}
+	}
+	for _, response := range responses {
+		response.IsLiked = liked[response.ID]
+		response.IsSaved = saved[response.ID]
+	}
+}
+
+func responsePointers(responses []models.ItineraryResponse) []*models.ItineraryResponse {
+	pointers := make([]*models.ItineraryResponse, len(responses))
+	for i := range responses {
+		pointers[i] = &responses[i]
+	}
+	return pointers
+}
+
+func (s *ItineraryService) applyGeoReference(itinerary *models.Itinerary) {
+	location := s.geoService.NormalizeLocation(itinerary.Country, itinerary.State, itinerary.City)
+
+	itinerary.Country = location.Country
+	itinerary.State = location.State
+	itinerary.City = location.City
+	itinerary.CountryID = location.CountryID
+	itinerary.StateID = location.StateID
+	itinerary.CityID = location.CityID
+	itinerary.Timezone = location.Timezone
+	itinerary.Locale = location.Locale
+}
+
+// resolveDayTimezones define o fuso de cada dia a partir do primeiro local com
+// coordenadas, permitindo roteiros que cruzam fusos horários. Dias sem locais
+// georreferenciados herdam o fuso do roteiro.
+func (s *ItineraryService) resolveDayTimezones(itinerary *models.Itinerary) {
+	for i := range itinerary.Days {
+		day := &itinerary.Days[i]
+		if day.Timezone != "" {
+			continue
+		}
+
+		for _, location := range day.Locations {
+			if location.Latitude == nil || location.Longitude == nil {
+				continue
+			}
+			if timezone := s.geoService.TimezoneAt(*location.Latitude, *location.Longitude); timezone != "" {
+				day.Timezone = timezone
+				break
+			}
+		}
+
+		if day.Timezone == "" {
+			day.Timezone = itinerary.Timezone
+		}
+	}
+}
+
 func (s *ItineraryService) getDefaultCurrency(currency string) string {
+	currency = normalizeCurrency(currency)
 	if currency == "" {
-		return "BRL"
+		return defaultCurrency
 	}
 	return currency
 }
@@ -446,7 +1221,7 @@ func (s *ItineraryService) validateCreateItineraryRequest(req *CreateItineraryRe
 		return err
 	}
 
-	if err := s.validateCategory(req.Category); err != nil {
+	if err := validateItineraryCategory(req.Category); err != nil {
 		return err
 	}
 
@@ -478,7 +1253,7 @@ func (s *ItineraryService) validateTitle(title string) error {
 	return nil
 }
 
-func (s *ItineraryService) validateCategory(category models.ItineraryCategory) error {
+func validateItineraryCategory(category models.ItineraryCategory) error {
 	validCategories := []models.ItineraryCategory{
 		models.CategoryAdventure, models.CategoryCultural, models.CategoryGastronomic,
 		models.CategoryNature, models.CategoryUrban, models.CategoryBeach,
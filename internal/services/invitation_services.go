@@ -0,0 +1,456 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// Quem recusa um convite não pode ser convidado de novo para a mesma viagem
+// durante este período
+const declinedInvitationCooldown = 30 * 24 * time.Hour
+
+// InviteRequest convida um seguidor (user_id) ou alguém pelo e-mail
+type InviteRequest struct {
+	UserID  *uint                   `json:"user_id"`
+	Email   string                  `json:"email"`
+	Role    models.CollaboratorRole `json:"role"` // padrão: collaborator
+	Message string                  `json:"message"`
+}
+
+type InvitationServiceInterface interface {
+	Invite(itineraryID, userID uint, req *InviteRequest) (*models.TripInvitationResponse, error)
+	GetItineraryInvitations(itineraryID, userID uint, limit, offset int) ([]*models.TripInvitationResponse, error)
+	CancelInvitation(itineraryID, invitationID, userID uint) error
+	GetInbox(userID uint, status string, limit, offset int) ([]*models.TripInvitationResponse, error)
+	AcceptInvitation(invitationID, userID uint) (*models.TripInvitationResponse, error)
+	DeclineInvitation(invitationID, userID uint) (*models.TripInvitationResponse, error)
+	GetParticipants(itineraryID, userID uint) ([]*models.TripParticipantResponse, error)
+	RemoveParticipant(itineraryID, participantID, userID uint) error
+}
+
+type InvitationService struct {
+	invitationRepo      repositories.InvitationRepositoryInterface
+	itineraryRepo       repositories.ItineraryRepositoryInterface
+	userRepo            repositories.UserRepositoryInterface
+	notificationService NotificationServiceInterface
+	emailService        EmailServiceInterface
+	eventBus            events.BusInterface
+}
+
+func NewInvitationService(
+	invitationRepo repositories.InvitationRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	notificationService NotificationServiceInterface,
+	emailService EmailServiceInterface,
+	eventBus events.BusInterface,
+) InvitationServiceInterface {
+	return &InvitationService{
+		invitationRepo:      invitationRepo,
+		itineraryRepo:       itineraryRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		emailService:        emailService,
+		eventBus:            eventBus,
+	}
+}
+
+// Invite convida alguém para a viagem do roteiro. Por ID, só seguidores do
+// autor podem ser convidados; por e-mail, o convite fica guardado até a
+// pessoa se cadastrar, se ainda não tiver conta. Ninguém entra na viagem sem
+// aceitar: usuários com bloqueio entre si e quem recusou um convite recente
+// para a mesma viagem não podem ser convidados
+func (s *InvitationService) Invite(itineraryID, userID uint, req *InviteRequest) (*models.TripInvitationResponse, error) {
+	itinerary, err := s.authorItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateInviteRequest(req); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.TripInvitation{
+		ItineraryID: itineraryID,
+		InviterID:   userID,
+		Role:        req.Role,
+		Status:      models.InvitationStatusPending,
+		Message:     strings.TrimSpace(req.Message),
+	}
+
+	var invitee *models.User
+	if req.UserID != nil {
+		invitee, err = s.userRepo.GetByID(*req.UserID)
+		if err != nil || !invitee.IsActive {
+			return nil, errors.New("usuário não encontrado")
+		}
+		following, err := s.userRepo.IsFollowing(invitee.ID, userID)
+		if err != nil {
+			return nil, errors.New("erro ao enviar convite")
+		}
+		if !following {
+			return nil, errors.New("só é possível convidar seus seguidores")
+		}
+	} else {
+		email := strings.ToLower(strings.TrimSpace(req.Email))
+		if user, err := s.userRepo.GetByEmail(email); err == nil && user.IsActive {
+			invitee = user
+		} else {
+			invitation.Email = email
+		}
+	}
+
+	if invitee != nil {
+		if invitee.ID == itinerary.AuthorID {
+			return nil, errors.New("o autor já participa do roteiro")
+		}
+
+		// Com bloqueio, o convidado é tratado como inexistente
+		blocked, err := s.userRepo.HasBlockBetween(userID, invitee.ID)
+		if err != nil {
+			return nil, errors.New("erro ao enviar convite")
+		}
+		if blocked {
+			return nil, errors.New("usuário não encontrado")
+		}
+
+		declined, err := s.invitationRepo.HasDeclinedSince(itineraryID, invitee.ID, time.Now().Add(-declinedInvitationCooldown))
+		if err != nil {
+			return nil, errors.New("erro ao enviar convite")
+		}
+		if declined {
+			return nil, errors.New("o usuário recusou um convite recente para esta viagem")
+		}
+
+		// Um espectador pode ser convidado de novo para colaborar
+		role, err := s.itineraryRepo.GetParticipantRole(itineraryID, invitee.ID)
+		if err != nil {
+			return nil, errors.New("erro ao enviar convite")
+		}
+		if role == models.CollaboratorRoleCollaborator || (role != "" && req.Role == models.CollaboratorRoleViewer) {
+			return nil, errors.New("usuário já participa do roteiro")
+		}
+
+		invitation.InviteeID = &invitee.ID
+	}
+
+	if _, err := s.invitationRepo.GetPending(itineraryID, inviteeIDOrZero(invitation), invitation.Email); err == nil {
+		return nil, errors.New("já existe um convite pendente para este usuário")
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, errors.New("erro ao enviar convite")
+	}
+
+	if invitee != nil {
+		key := fmt.Sprintf("trip_invitation:%d", invitation.ID)
+		_, err := s.notificationService.Notify(&models.Notification{
+			UserID: invitee.ID,
+			Type:   models.NotificationTypeTripInvitation,
+			Title:  "Convite para viagem",
+			Body:   truncateString(fmt.Sprintf("%s convidou você para a viagem %s", itinerary.Author.Username, itinerary.Title), 200),
+			Data: map[string]string{
+				"invitation_id": fmt.Sprint(invitation.ID),
+				"itinerary_id":  fmt.Sprint(itinerary.ID),
+				"role":          string(invitation.Role),
+			},
+			Key: &key,
+		})
+		if err != nil {
+			log.Printf("Falha ao notificar convite %d: %v", invitation.ID, err)
+		}
+		invitation.Invitee = invitee
+	} else if err := s.emailService.SendTripInvitationEmail(invitation, itinerary.Author.Username, itinerary.Title); err != nil {
+		log.Printf("Falha ao enviar e-mail do convite %d: %v", invitation.ID, err)
+	}
+
+	return invitation.ToResponse(), nil
+}
+
+// GetItineraryInvitations lista os convites enviados para a viagem, só para o
+// autor
+func (s *InvitationService) GetItineraryInvitations(itineraryID, userID uint, limit, offset int) ([]*models.TripInvitationResponse, error) {
+	if _, err := s.authorItinerary(itineraryID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	invitations, err := s.invitationRepo.GetByItinerary(itineraryID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar convites")
+	}
+
+	return invitationResponses(invitations), nil
+}
+
+// CancelInvitation remove um convite ainda pendente
+func (s *InvitationService) CancelInvitation(itineraryID, invitationID, userID uint) error {
+	if _, err := s.authorItinerary(itineraryID, userID); err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil || invitation.ItineraryID != itineraryID {
+		return errors.New("convite não encontrado")
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return errors.New("convite já foi respondido")
+	}
+
+	if err := s.invitationRepo.Delete(invitation.ID); err != nil {
+		return errors.New("erro ao cancelar convite")
+	}
+	return nil
+}
+
+// GetInbox lista os convites recebidos; status vazio traz os pendentes
+func (s *InvitationService) GetInbox(userID uint, status string, limit, offset int) ([]*models.TripInvitationResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	filter := models.InvitationStatus(status)
+	switch filter {
+	case "":
+		filter = models.InvitationStatusPending
+	case "all":
+		filter = ""
+	case models.InvitationStatusPending, models.InvitationStatusAccepted, models.InvitationStatusDeclined:
+	default:
+		return nil, errors.New("status inválido: use pending, accepted, declined ou all")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	invitations, err := s.invitationRepo.GetInbox(userID, user.Email, filter, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar convites")
+	}
+
+	return invitationResponses(invitations), nil
+}
+
+// AcceptInvitation inclui o usuário na viagem com o papel do convite e avisa
+// o autor
+func (s *InvitationService) AcceptInvitation(invitationID, userID uint) (*models.TripInvitationResponse, error) {
+	invitation, err := s.respond(invitationID, userID, models.InvitationStatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("trip_invitation_accepted:%d", invitation.ID)
+	_, err = s.notificationService.Notify(&models.Notification{
+		UserID: invitation.InviterID,
+		Type:   models.NotificationTypeTripInvitationAccepted,
+		Title:  invitation.Itinerary.Title,
+		Body:   truncateString(fmt.Sprintf("%s aceitou o convite para a viagem", invitation.Invitee.Username), 200),
+		Data: map[string]string{
+			"invitation_id": fmt.Sprint(invitation.ID),
+			"itinerary_id":  fmt.Sprint(invitation.ItineraryID),
+			"user_id":       fmt.Sprint(userID),
+		},
+		Key: &key,
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar aceite do convite %d: %v", invitation.ID, err)
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.TripParticipantJoined,
+		ActorID:  userID,
+		EntityID: invitation.ItineraryID,
+		Data: map[string]string{
+			"user_id": fmt.Sprint(userID),
+		},
+	})
+
+	return invitation.ToResponse(), nil
+}
+
+func (s *InvitationService) DeclineInvitation(invitationID, userID uint) (*models.TripInvitationResponse, error) {
+	invitation, err := s.respond(invitationID, userID, models.InvitationStatusDeclined)
+	if err != nil {
+		return nil, err
+	}
+	return invitation.ToResponse(), nil
+}
+
+// respond confere que o convite é do usuário (pelo ID ou pelo e-mail) e
+// registra a resposta
+func (s *InvitationService) respond(invitationID, userID uint, status models.InvitationStatus) (*models.TripInvitation, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil || invitation.Itinerary.ID == 0 {
+		return nil, errors.New("convite não encontrado")
+	}
+
+	addressedToUser := invitation.InviteeID != nil && *invitation.InviteeID == userID
+	addressedToEmail := invitation.InviteeID == nil && invitation.Email != "" && invitation.Email == user.Email
+	if !addressedToUser && !addressedToEmail {
+		return nil, errors.New("convite não encontrado")
+	}
+
+	if invitation.Status != models.InvitationStatusPending {
+		return nil, errors.New("convite já foi respondido")
+	}
+
+	// Um bloqueio depois do envio invalida o convite
+	if status == models.InvitationStatusAccepted {
+		blocked, err := s.userRepo.HasBlockBetween(userID, invitation.InviterID)
+		if err != nil {
+			return nil, errors.New("erro ao responder convite")
+		}
+		if blocked {
+			return nil, errors.New("convite não encontrado")
+		}
+	}
+
+	responded, err := s.invitationRepo.Respond(invitation, userID, status)
+	if err != nil {
+		return nil, errors.New("erro ao responder convite")
+	}
+	if !responded {
+		return nil, errors.New("convite já foi respondido")
+	}
+
+	invitation.Invitee = user
+	return invitation, nil
+}
+
+// GetParticipants lista o autor e quem entrou na viagem por convite, para
+// qualquer participante
+func (s *InvitationService) GetParticipants(itineraryID, userID uint) ([]*models.TripParticipantResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	participants, err := s.invitationRepo.GetParticipants(itineraryID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar participantes")
+	}
+
+	responses := make([]*models.TripParticipantResponse, 0, len(participants)+1)
+	responses = append(responses, &models.TripParticipantResponse{
+		User: itinerary.Author.ToResponse(),
+		Role: models.CollaboratorRoleAuthor,
+	})
+	member := itinerary.AuthorID == userID
+	for i := range participants {
+		member = member || participants[i].UserID == userID
+		responses = append(responses, participants[i].ToParticipantResponse())
+	}
+
+	// A lista é privada: quem não participa recebe o mesmo erro de um
+	// roteiro inexistente
+	if !member {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	return responses, nil
+}
+
+// RemoveParticipant pode ser feito pelo autor ou pelo próprio participante,
+// para sair da viagem; os gastos já registrados continuam no balanço
+func (s *InvitationService) RemoveParticipant(itineraryID, participantID, userID uint) error {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID && participantID != userID {
+		return errors.New("você não tem permissão para editar este roteiro")
+	}
+
+	removed, err := s.invitationRepo.RemoveParticipant(itineraryID, participantID)
+	if err != nil {
+		return errors.New("erro ao remover participante")
+	}
+	if !removed {
+		return errors.New("participante não encontrado")
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.TripParticipantLeft,
+		ActorID:  userID,
+		EntityID: itineraryID,
+		Data: map[string]string{
+			"user_id": fmt.Sprint(participantID),
+		},
+	})
+	return nil
+}
+
+func (s *InvitationService) authorItinerary(itineraryID, userID uint) (*models.Itinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para convidar pessoas para este roteiro")
+	}
+	return itinerary, nil
+}
+
+func invitationResponses(invitations []models.TripInvitation) []*models.TripInvitationResponse {
+	responses := make([]*models.TripInvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		responses = append(responses, invitations[i].ToResponse())
+	}
+	return responses
+}
+
+func inviteeIDOrZero(invitation *models.TripInvitation) uint {
+	if invitation.InviteeID == nil {
+		return 0
+	}
+	return *invitation.InviteeID
+}
+
+// Funções de validação
+
+func validateInviteRequest(req *InviteRequest) error {
+	hasEmail := strings.TrimSpace(req.Email) != ""
+	if req.UserID == nil && !hasEmail {
+		return errors.New("informe user_id ou email")
+	}
+	if req.UserID != nil && hasEmail {
+		return errors.New("informe apenas um entre user_id e email")
+	}
+
+	if hasEmail {
+		if _, err := mail.ParseAddress(strings.TrimSpace(req.Email)); err != nil {
+			return errors.New("email inválido")
+		}
+	}
+
+	switch req.Role {
+	case "":
+		req.Role = models.CollaboratorRoleCollaborator
+	case models.CollaboratorRoleCollaborator, models.CollaboratorRoleViewer:
+	default:
+		return errors.New("papel inválido: use collaborator ou viewer")
+	}
+
+	if len(req.Message) > 500 {
+		return errors.New("mensagem deve ter no máximo 500 caracteres")
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// LedgerServiceInterface centraliza a movimentação financeira da plataforma
+// (gorjetas, promoções, assinaturas, estornos e saques dos criadores) no
+// livro-razão de partidas dobradas
+type LedgerServiceInterface interface {
+	RecordTip(tip *models.Tip) error
+	RecordTipRefund(tip *models.Tip) error
+	RecordPlatformSale(kind models.LedgerTransactionKind, reference, currency string, amount int64, description string) error
+	GetBalances(userID uint) ([]models.LedgerBalance, error)
+	GetEntries(userID uint, limit, offset int) ([]models.LedgerEntryResponse, error)
+	RequestPayout(userID uint, req *PayoutRequest) (*models.PayoutResponse, error)
+	GetPayouts(userID uint, limit, offset int) ([]models.PayoutResponse, error)
+	ProcessDuePayouts() (int, error)
+	StartPayoutScheduler(interval time.Duration)
+	GetReconciliationReport(from, to time.Time) (*models.ReconciliationReport, error)
+}
+
+type PayoutRequest struct {
+	Amount   int64  `json:"amount" binding:"required"` // em centavos
+	Currency string `json:"currency"`
+}
+
+type LedgerService struct {
+	ledgerRepo repositories.LedgerRepositoryInterface
+	tipRepo    repositories.TipRepositoryInterface
+	provider   BillingProviderInterface
+	config     *BillingConfig
+}
+
+func NewLedgerService(
+	ledgerRepo repositories.LedgerRepositoryInterface,
+	tipRepo repositories.TipRepositoryInterface,
+	provider BillingProviderInterface,
+	config *BillingConfig,
+) LedgerServiceInterface {
+	if config.MinPayoutAmount <= 0 {
+		config.MinPayoutAmount = 1000
+	}
+	if config.PayoutDelayDays < 0 {
+		config.PayoutDelayDays = 0
+	}
+
+	return &LedgerService{
+		ledgerRepo: ledgerRepo,
+		tipRepo:    tipRepo,
+		provider:   provider,
+		config:     config,
+	}
+}
+
+// RecordTip lança uma gorjeta paga: o valor entra pelo provedor, a comissão
+// vai para a plataforma e o restante para o saldo do criador
+func (s *LedgerService) RecordTip(tip *models.Tip) error {
+	_, err := s.ledgerRepo.PostTransaction(&models.LedgerTransaction{
+		Kind:        models.LedgerKindTip,
+		Reference:   fmt.Sprintf("tip:%d", tip.ID),
+		Currency:    tip.Currency,
+		Description: "Apoio recebido",
+	}, []models.LedgerLeg{
+		{AccountType: models.LedgerAccountProviderClearing, Amount: -tip.Amount},
+		{AccountType: models.LedgerAccountPlatformRevenue, Amount: tip.PlatformFee},
+		{AccountType: models.LedgerAccountCreatorAvailable, OwnerID: tip.CreatorID, Amount: tip.Amount - tip.PlatformFee},
+	})
+	return err
+}
+
+// RecordTipRefund reverte os lançamentos da gorjeta; o saldo do criador pode
+// ficar negativo se o valor já tiver sido sacado
+func (s *LedgerService) RecordTipRefund(tip *models.Tip) error {
+	_, err := s.ledgerRepo.PostTransaction(&models.LedgerTransaction{
+		Kind:        models.LedgerKindRefund,
+		Reference:   fmt.Sprintf("refund:tip:%d", tip.ID),
+		Currency:    tip.Currency,
+		Description: "Estorno de apoio",
+	}, []models.LedgerLeg{
+		{AccountType: models.LedgerAccountProviderClearing, Amount: tip.Amount},
+		{AccountType: models.LedgerAccountPlatformRevenue, Amount: -tip.PlatformFee},
+		{AccountType: models.LedgerAccountCreatorAvailable, OwnerID: tip.CreatorID, Amount: -(tip.Amount - tip.PlatformFee)},
+	})
+	return err
+}
+
+// RecordPlatformSale lança uma venda da própria plataforma (promoções,
+// assinaturas), sem repasse a criadores
+func (s *LedgerService) RecordPlatformSale(kind models.LedgerTransactionKind, reference, currency string, amount int64, description string) error {
+	_, err := s.ledgerRepo.PostTransaction(&models.LedgerTransaction{
+		Kind:        kind,
+		Reference:   reference,
+		Currency:    strings.ToUpper(currency),
+		Description: description,
+	}, []models.LedgerLeg{
+		{AccountType: models.LedgerAccountProviderClearing, Amount: -amount},
+		{AccountType: models.LedgerAccountPlatformRevenue, Amount: amount},
+	})
+	return err
+}
+
+func (s *LedgerService) GetBalances(userID uint) ([]models.LedgerBalance, error) {
+	accounts, err := s.ledgerRepo.GetAccountsByOwner(userID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar saldo")
+	}
+
+	var balances []models.LedgerBalance
+	byCurrency := make(map[string]int)
+	for _, account := range accounts {
+		index, ok := byCurrency[account.Currency]
+		if !ok {
+			balances = append(balances, models.LedgerBalance{Currency: account.Currency})
+			index = len(balances) - 1
+			byCurrency[account.Currency] = index
+		}
+
+		switch account.Type {
+		case models.LedgerAccountCreatorAvailable:
+			balances[index].Available = account.Balance
+		case models.LedgerAccountCreatorPayoutPending:
+			balances[index].PendingPayout = account.Balance
+		}
+	}
+
+	return balances, nil
+}
+
+func (s *LedgerService) GetEntries(userID uint, limit, offset int) ([]models.LedgerEntryResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	entries, err := s.ledgerRepo.GetEntriesByOwner(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar extrato")
+	}
+
+	var responses []models.LedgerEntryResponse
+	for _, entry := range entries {
+		responses = append(responses, *entry.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// RequestPayout reserva o valor do saldo disponível e agenda o saque para
+// depois do período de carência configurado
+func (s *LedgerService) RequestPayout(userID uint, req *PayoutRequest) (*models.PayoutResponse, error) {
+	currency := "BRL"
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+	if len(currency) != 3 {
+		return nil, errors.New("moeda deve ser um código ISO de 3 letras")
+	}
+
+	if req.Amount < s.config.MinPayoutAmount {
+		return nil, errors.New("valor do saque abaixo do mínimo permitido")
+	}
+
+	account, err := s.tipRepo.GetPayoutAccount(userID)
+	if err != nil || !account.PayoutsEnabled {
+		return nil, errors.New("conta de recebimento ainda não habilitada para saques")
+	}
+
+	payout := &models.Payout{
+		UserID:       userID,
+		Amount:       req.Amount,
+		Currency:     currency,
+		Status:       models.PayoutStatusScheduled,
+		ScheduledFor: time.Now().AddDate(0, 0, s.config.PayoutDelayDays),
+	}
+
+	if err := s.ledgerRepo.SchedulePayout(payout); err != nil {
+		if errors.Is(err, repositories.ErrInsufficientBalance) {
+			return nil, errors.New("saldo insuficiente para o saque")
+		}
+		return nil, errors.New("erro ao agendar saque")
+	}
+
+	return payout.ToResponse(), nil
+}
+
+func (s *LedgerService) GetPayouts(userID uint, limit, offset int) ([]models.PayoutResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	payouts, err := s.ledgerRepo.GetPayoutsByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar saques")
+	}
+
+	var responses []models.PayoutResponse
+	for _, payout := range payouts {
+		responses = append(responses, *payout.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// ProcessDuePayouts envia ao provedor os saques vencidos; falhas devolvem o
+// valor ao saldo disponível. Retorna quantos saques foram pagos
+func (s *LedgerService) ProcessDuePayouts() (int, error) {
+	payouts, err := s.ledgerRepo.GetDuePayouts(time.Now(), 100)
+	if err != nil {
+		return 0, errors.New("erro ao buscar saques agendados")
+	}
+
+	paid := 0
+	for i := range payouts {
+		payout := &payouts[i]
+
+		// A reserva impede que duas execuções simultâneas enviem o mesmo saque
+		claimed, err := s.ledgerRepo.ClaimPayout(payout.ID)
+		if err != nil {
+			log.Printf("Erro ao reservar saque %d: %v", payout.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		status := models.PayoutStatusFailed
+		account, err := s.tipRepo.GetPayoutAccount(payout.UserID)
+		if err != nil || !account.PayoutsEnabled {
+			payout.FailureReason = "conta de recebimento não habilitada"
+		} else if providerID, err := s.provider.CreatePayout(account.ProviderAccountID, payout.Amount, payout.Currency, fmt.Sprintf("payout:%d", payout.ID)); err != nil {
+			if errors.Is(err, ErrBillingNotConfigured) {
+				// Sem provedor o saque volta a ficar agendado até a configuração
+				if err := s.ledgerRepo.ReleasePayout(payout.ID); err != nil {
+					log.Printf("Erro ao liberar saque %d: %v", payout.ID, err)
+				}
+				continue
+			}
+			if errors.Is(err, ErrBillingUncertain) {
+				// O saque pode ter saído: fica em processamento para a
+				// conciliação em vez de devolver o valor ao saldo
+				log.Printf("Saque %d sem confirmação do provedor: %v", payout.ID, err)
+				continue
+			}
+			payout.FailureReason = err.Error()
+		} else {
+			payout.ProviderPayoutID = providerID
+			status = models.PayoutStatusPaid
+		}
+
+		completed, err := s.ledgerRepo.CompletePayout(payout, status)
+		if err != nil {
+			log.Printf("Erro ao concluir saque %d: %v", payout.ID, err)
+			continue
+		}
+		if completed && status == models.PayoutStatusPaid {
+			paid++
+		}
+	}
+
+	return paid, nil
+}
+
+// StartPayoutScheduler processa os saques vencidos periodicamente em segundo plano
+func (s *LedgerService) StartPayoutScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if paid, err := s.ProcessDuePayouts(); err != nil {
+				log.Println("Falha ao processar saques agendados:", err)
+			} else if paid > 0 {
+				log.Printf("%d saques enviados", paid)
+			}
+		}
+	}()
+}
+
+// GetReconciliationReport confronta o livro-razão com as gorjetas do período;
+// sem período informado considera o mês corrente
+func (s *LedgerService) GetReconciliationReport(from, to time.Time) (*models.ReconciliationReport, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
+	}
+	if !from.Before(to) {
+		return nil, errors.New("período inválido")
+	}
+
+	report := &models.ReconciliationReport{From: from, To: to}
+
+	var err error
+	if report.Totals, err = s.ledgerRepo.GetReconciliationTotals(from, to); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.UnbalancedTransactionIDs, err = s.ledgerRepo.GetUnbalancedTransactionIDs(from, to); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.MissingTipIDs, err = s.ledgerRepo.GetTipsMissingLedger(from, to); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.MissingRefundTipIDs, err = s.ledgerRepo.GetRefundsMissingLedger(from, to); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.MissingPromotionIDs, err = s.ledgerRepo.GetPromotionsMissingLedger(from, to); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.MissingSubscriptionIDs, err = s.ledgerRepo.GetSubscriptionsMissingLedger(from, to); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.AccountsOutOfSync, err = s.ledgerRepo.GetAccountsOutOfSync(); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+	if report.ProcessingPayoutIDs, err = s.ledgerRepo.GetProcessingPayoutIDs(time.Now().Add(-time.Hour)); err != nil {
+		return nil, errors.New("erro ao gerar conciliação")
+	}
+
+	return report, nil
+}
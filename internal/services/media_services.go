@@ -1,38 +1,66 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
 	"io"
+	"log"
 	"mime/multipart"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
 	"github.com/google/uuid"
 )
 
-type MediaType string
+type MediaType = models.MediaType
 
 const (
-	MediaTypeImage MediaType = "image"
-	MediaTypeVideo MediaType = "video"
+	MediaTypeImage = models.MediaTypeImage
+	MediaTypeVideo = models.MediaTypeVideo
 )
 
 type MediaServiceInterface interface {
 	UploadFile(file *multipart.FileHeader, userID uint, mediaType MediaType) (*MediaUploadResponse, error)
+	StoreUpload(src io.Reader, originalName string, size int64, userID uint, mediaType MediaType) (*MediaUploadResponse, error)
+	UploadPrivateFile(file *multipart.FileHeader, userID uint, mediaType MediaType) (*MediaUploadResponse, error)
 	DeleteFile(filePath string) error
+	DeleteMedia(filePath string, userID uint, isAdmin bool) error
+	GetMediaInfo(filePath string, userID uint, isAdmin bool) (*MediaInfo, error)
+	GetMediaByID(mediaID, userID uint, isAdmin bool) (*MediaInfo, error)
 	GetFileURL(filePath string) string
 	ValidateFile(file *multipart.FileHeader, mediaType MediaType) error
+	ValidateFileName(fileName string, mediaType MediaType) error
+	SaveGeneratedImage(data []byte, userID uint, directory string) (*MediaUploadResponse, error)
+	StoreGeneratedFile(data []byte, userID uint, directory, extension, contentType string) (string, string, error)
+	LoadImage(url string) (image.Image, error)
+	IsStoredMedia(url string) bool
+	OpenMedia(media *models.Media) (io.ReadCloser, error)
+	OpenVariant(media *models.Media, variant string) (io.ReadCloser, string, error)
+	StoreDerivedFile(src io.Reader, contentType, directory, fileName string) (string, string, error)
+	RemoveFiles(filePaths []string)
+	StoreProfileImage(file *multipart.FileHeader, userID uint, kind ProfileImageKind, crop *CropRect) (*models.Media, error)
+	DeleteUnusedMedia(url string, userID uint)
+	StartCDNInvalidationScheduler(interval time.Duration)
 }
 
 type MediaUploadResponse struct {
+	ID        uint      `json:"id"`
 	URL       string    `json:"url"`
 	FilePath  string    `json:"file_path"`
 	FileName  string    `json:"file_name"`
@@ -41,16 +69,68 @@ type MediaUploadResponse struct {
 	MediaType MediaType `json:"media_type"`
 	Width     int       `json:"width,omitempty"`
 	Height    int       `json:"height,omitempty"`
+
+	Variants map[string]models.MediaVariant `json:"variants,omitempty"`
+	// Localização e data lidas do EXIF da foto antes de removê-lo
+	Metadata *PhotoMetadata `json:"metadata,omitempty"`
+	// "uploaded" indica que o vídeo entrou na fila de conversão; o andamento
+	// fica em GET /media/{id}
+	Status models.MediaProcessingStatus `json:"status"`
+	// "flagged" indica que a imagem aguarda revisão antes de poder ser usada
+	ModerationStatus models.MediaModerationStatus `json:"moderation_status,omitempty"`
+	// O arquivo já tinha sido enviado pelo usuário; a mídia existente é devolvida
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// MediaInfo é o registro da mídia com o número de conteúdos que a exibem
+type MediaInfo struct {
+	models.Media
+	References int64 `json:"references"`
+	// Processamento concluído e liberada pela moderação
+	Usable bool `json:"usable"`
 }
 
 type MediaConfig struct {
-	StorageType     string // "local" or "s3"
-	LocalPath       string
-	BaseURL         string
-	MaxFileSize     int64
-	AllowedImageExt []string
-	AllowedVideoExt []string
-	AWSConfig       *AWSConfig
+	StorageType string // "local", "s3", "gcs" ou "azure"
+	LocalPath   string
+	// Anexos das conversas no disco local, fora do diretório público
+	PrivateLocalPath string
+	BaseURL          string
+	MaxFileSize      int64
+	AllowedImageExt  []string
+	AllowedVideoExt  []string
+	AWSConfig        *AWSConfig
+	GCSConfig        *GCSConfig
+	AzureConfig      *AzureConfig
+
+	// Uploads em partes (retomáveis): as partes ficam em UploadTempPath, fora
+	// do diretório público, até o upload ser concluído
+	MaxChunkedUploadSize int64
+	UploadTempPath       string
+	UploadSessionTTL     time.Duration
+	// Limites por usuário de sessões abertas e da soma dos tamanhos
+	// declarados, para que ninguém reserve o disco temporário inteiro
+	MaxOpenUploadSessions int
+	MaxPendingUploadBytes int64
+
+	// Variantes geradas para cada imagem enviada (nil usa as padrão); as
+	// variantes WebP precisam do cwebp, procurado no PATH se WebPEncoderPath
+	// estiver vazio
+	ImageVariants   []ImageVariant
+	WebPEncoderPath string
+
+	// Limite de pixels das imagens, conferido no cabeçalho antes de decodificar
+	MaxImagePixels int64
+
+	// O EXIF das fotos é sempre removido; com isto, a localização e a data
+	// são lidas antes e devolvidas a quem enviou
+	ExtractPhotoMetadata bool
+
+	// Moderação automática das imagens enviadas (nil desativa)
+	Moderation *ImageModerationConfig
+
+	// Invalidação do cache da CDN quando arquivos são apagados (nil desativa)
+	CDN *CDNConfig
 }
 
 type AWSConfig struct {
@@ -59,13 +139,25 @@ type AWSConfig struct {
 	AccessKey string
 	SecretKey string
 	CDNUrl    string
+
+	// Endpoint de um storage compatível com S3 (MinIO, Ceph etc.); vazio usa
+	// a AWS. Esses storages costumam exigir ForcePathStyle, com o bucket no
+	// caminho da URL e não no domínio
+	Endpoint       string
+	ForcePathStyle bool
 }
 
 type MediaService struct {
-	config *MediaConfig
+	config      *MediaConfig
+	storage     StorageBackend
+	moderator   ImageModerator
+	cdn         *cdnBatcher
+	mediaRepo   repositories.MediaRepositoryInterface
+	eventBus    events.BusInterface
+	webpEncoder string
 }
 
-func NewMediaService(config *MediaConfig) MediaServiceInterface {
+func NewMediaService(config *MediaConfig, mediaRepo repositories.MediaRepositoryInterface, eventBus events.BusInterface) MediaServiceInterface {
 	if config.MaxFileSize == 0 {
 		config.MaxFileSize = 50 * 1024 * 1024 // 50MB default
 	}
@@ -82,8 +174,69 @@ func NewMediaService(config *MediaConfig) MediaServiceInterface {
 		config.LocalPath = "./uploads"
 	}
 
+	if config.PrivateLocalPath == "" {
+		config.PrivateLocalPath = "./private-uploads"
+	}
+
+	if config.MaxChunkedUploadSize == 0 {
+		config.MaxChunkedUploadSize = 200 * 1024 * 1024 // 200MB default
+	}
+
+	if config.UploadTempPath == "" {
+		config.UploadTempPath = filepath.Join(os.TempDir(), "guia-uploads")
+	}
+
+	if config.UploadSessionTTL == 0 {
+		config.UploadSessionTTL = 24 * time.Hour
+	}
+
+	if config.MaxOpenUploadSessions == 0 {
+		config.MaxOpenUploadSessions = 5
+	}
+
+	if config.MaxPendingUploadBytes == 0 {
+		config.MaxPendingUploadBytes = 1024 * 1024 * 1024 // 1GB default
+	}
+
+	if config.MaxImagePixels == 0 {
+		config.MaxImagePixels = 50_000_000 // 50 megapixels
+	}
+
+	if config.Moderation != nil {
+		if config.Moderation.MinConfidence <= 0 {
+			config.Moderation.MinConfidence = 80
+		}
+		if len(config.Moderation.Labels) == 0 {
+			config.Moderation.Labels = DefaultModerationLabels
+		}
+	}
+
+	if config.ImageVariants == nil {
+		config.ImageVariants = DefaultImageVariants
+	}
+
+	if config.CDN != nil && config.CDN.MaxBatchSize <= 0 {
+		config.CDN.MaxBatchSize = 1000
+	}
+
+	webpEncoder := config.WebPEncoderPath
+	if webpEncoder == "" {
+		webpEncoder, _ = exec.LookPath("cwebp")
+	}
+	for _, variant := range config.ImageVariants {
+		if variant.Format == "webp" && webpEncoder == "" {
+			log.Printf("cwebp não encontrado; a variante %s não será gerada", variant.Name)
+		}
+	}
+
 	return &MediaService{
-		config: config,
+		config:      config,
+		storage:     NewStorageBackend(config),
+		moderator:   NewImageModerator(config.Moderation),
+		cdn:         newCDNBatcher(config),
+		mediaRepo:   mediaRepo,
+		eventBus:    eventBus,
+		webpEncoder: webpEncoder,
 	}
 }
 
@@ -93,8 +246,42 @@ func (s *MediaService) UploadFile(file *multipart.FileHeader, userID uint, media
 		return nil, err
 	}
 
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return s.StoreUpload(src, file.Filename, file.Size, userID, mediaType)
+}
+
+// UploadPrivateFile grava um anexo de conversa em private/, sem acesso
+// público. Passa pelas mesmas verificações dos uploads comuns, mas os vídeos
+// não são convertidos para HLS: o original é servido pelo link assinado
+func (s *MediaService) UploadPrivateFile(file *multipart.FileHeader, userID uint, mediaType MediaType) (*MediaUploadResponse, error) {
+	if err := s.ValidateFile(file, mediaType); err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return s.storeUpload(src, file.Filename, file.Size, userID, mediaType, true)
+}
+
+// StoreUpload grava um arquivo recebido (upload direto ou sessão em partes),
+// com o nome já validado, e registra o usuário como dono. O conteúdo é
+// conferido aqui, porque só agora os bytes estão disponíveis
+func (s *MediaService) StoreUpload(src io.Reader, originalName string, size int64, userID uint, mediaType MediaType) (*MediaUploadResponse, error) {
+	return s.storeUpload(src, originalName, size, userID, mediaType, false)
+}
+
+func (s *MediaService) storeUpload(src io.Reader, originalName string, size int64, userID uint, mediaType MediaType, private bool) (*MediaUploadResponse, error) {
 	// Gerar nome único do arquivo
-	fileName := s.generateFileName(file.Filename, userID)
+	fileName := s.generateFileName(originalName, userID)
 
 	// Determinar diretório baseado no tipo de mídia
 	var directory string
@@ -106,182 +293,424 @@ func (s *MediaService) UploadFile(file *multipart.FileHeader, userID uint, media
 	default:
 		return nil, errors.New("tipo de mídia não suportado")
 	}
+	if private {
+		directory = privateStoragePrefix + "messages/" + directory
+	}
 
-	// Upload baseado no tipo de storage
-	var filePath, url string
-	var err error
+	// Imagens ficam em memória (no máximo MaxFileSize) para remover o EXIF e
+	// gerar as variantes; dos vídeos basta o início para identificar o formato
+	var data []byte
+	var exif *PhotoMetadata
+	var mimeType string
+	var contentHash string
+	var hasher hash.Hash
+	if mediaType == MediaTypeImage {
+		var err error
+		data, err = io.ReadAll(io.LimitReader(src, s.config.MaxFileSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > s.config.MaxFileSize {
+			return nil, fmt.Errorf("arquivo muito grande. Tamanho máximo: %d MB", s.config.MaxFileSize/(1024*1024))
+		}
+		sum := sha256.Sum256(data)
+		contentHash = hex.EncodeToString(sum[:])
+
+		// O formato vem do conteúdo, não da extensão nem do Content-Type enviados
+		mimeType, err = s.checkContent(data[:min(len(data), sniffLength)], originalName, mediaType)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fotos de celular trazem GPS e dados do aparelho no EXIF; por
+		// privacidade nada disso é publicado
+		data, exif, err = stripImageMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkImageContent(data); err != nil {
+			return nil, err
+		}
 
-	switch s.config.StorageType {
-	case "s3":
-		filePath, url, err = s.uploadToS3(file, fileName, directory)
-	default: // local
-		filePath, url, err = s.uploadToLocal(file, fileName, directory)
+		// O mesmo arquivo já enviado pelo usuário é reaproveitado
+		if existing := s.findDuplicate(userID, contentHash, private); existing != nil {
+			response := mediaUploadResponse(existing)
+			response.Duplicate = true
+			if s.config.ExtractPhotoMetadata && exif.hasLocationOrDate() {
+				response.Metadata = exif
+			}
+			return response, nil
+		}
+
+		size = int64(len(data))
+		src = bytes.NewReader(data)
+	} else {
+		// Arquivos em disco (multipart, upload em partes) são lidos duas
+		// vezes para conferir o hash antes de gravar um vídeo repetido; nos
+		// demais o hash é calculado durante a gravação
+		if seeker, ok := src.(io.ReadSeeker); ok {
+			var err error
+			contentHash, err = hashAndRewind(seeker)
+			if err != nil {
+				return nil, err
+			}
+			if existing := s.findDuplicate(userID, contentHash, private); existing != nil {
+				response := mediaUploadResponse(existing)
+				response.Duplicate = true
+				return response, nil
+			}
+		} else {
+			hasher = sha256.New()
+			src = io.TeeReader(src, hasher)
+		}
+
+		reader := bufio.NewReaderSize(src, sniffLength)
+		header, err := reader.Peek(sniffLength)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		mimeType, err = s.checkContent(header, originalName, mediaType)
+		if err != nil {
+			return nil, err
+		}
+		src = reader
 	}
 
+	// Upload baseado no tipo de storage
+	filePath, url, err := s.store(src, mimeType, fileName, directory)
 	if err != nil {
 		return nil, err
 	}
+	if hasher != nil {
+		contentHash = hex.EncodeToString(hasher.Sum(nil))
+	}
 
-	// Obter metadados do arquivo
-	width, height, err := s.getImageDimensions(file, mediaType)
-	if err != nil {
-		// Log do erro, mas não falha o upload
-		width, height = 0, 0
+	// Obter metadados do arquivo e gerar as variantes das imagens
+	var width, height int
+	var variants map[string]models.MediaVariant
+	var metadata *PhotoMetadata
+	status := models.MediaStatusReady
+	moderationStatus := models.MediaModerationApproved
+	var moderationLabels []models.ModerationLabel
+	if mediaType == MediaTypeVideo && !private {
+		status = models.MediaStatusUploaded
+	}
+	if mediaType == MediaTypeImage {
+		width, height = imageDimensions(data, exif.Orientation)
+		original := &models.MediaVariant{URL: url, MimeType: mimeType, Width: width, Height: height}
+		variants = s.generateVariants(data, exif.Orientation, original, fileName, directory)
+		if s.config.ExtractPhotoMetadata && exif.hasLocationOrDate() {
+			metadata = exif
+		}
+		moderationStatus, moderationLabels = s.moderateImage(data, mimeType)
+	}
+
+	// Registrar o dono do arquivo para validar as referências em posts
+	media := &models.Media{
+		OwnerID:   userID,
+		URL:       url,
+		FilePath:  filePath,
+		MediaType: mediaType,
+		MimeType:  mimeType,
+		FileSize:  size,
+		Width:     width,
+		Height:    height,
+		Variants:  variants,
+		Status:    status,
+		Private:   private,
+
+		ContentHash: contentHash,
+
+		ModerationStatus: moderationStatus,
+		ModerationLabels: moderationLabels,
+	}
+	if err := s.mediaRepo.Create(media); err != nil {
+		s.removeVariants(variants)
+		if delErr := s.DeleteFile(filePath); delErr != nil {
+			log.Printf("Falha ao remover arquivo não registrado %s: %v", filePath, delErr)
+		}
+		return nil, errors.New("erro ao registrar mídia")
+	}
+
+	// Vídeos seguem para a conversão em HLS em segundo plano
+	if mediaType == MediaTypeVideo && !private {
+		s.eventBus.Publish(events.Event{
+			Type:     events.MediaUploaded,
+			ActorID:  userID,
+			EntityID: media.ID,
+			Data:     map[string]string{"media_type": string(mediaType)},
+		})
 	}
 
 	return &MediaUploadResponse{
+		ID:        media.ID,
 		URL:       url,
 		FilePath:  filePath,
 		FileName:  fileName,
-		FileSize:  file.Size,
-		MimeType:  file.Header.Get("Content-Type"),
+		FileSize:  size,
+		MimeType:  mimeType,
 		MediaType: mediaType,
 		Width:     width,
 		Height:    height,
+		Variants:  variants,
+		Metadata:  metadata,
+		Status:    status,
+
+		ModerationStatus: moderationStatus,
 	}, nil
 }
 
-// ============================================================================
-// UPLOAD LOCAL
-// ============================================================================
-
-func (s *MediaService) uploadToLocal(file *multipart.FileHeader, fileName, directory string) (string, string, error) {
-	src, err := file.Open()
+// findDuplicate devolve a mídia do usuário com o mesmo conteúdo, se houver
+func (s *MediaService) findDuplicate(userID uint, contentHash string, private bool) *models.Media {
+	media, err := s.mediaRepo.GetByContentHash(userID, contentHash, private)
 	if err != nil {
-		return "", "", err
+		return nil
 	}
-	defer src.Close()
+	return media
+}
 
-	// Criar diretório se não existir
-	fullDir := filepath.Join(s.config.LocalPath, directory)
-	if err := os.MkdirAll(fullDir, 0755); err != nil {
-		return "", "", err
+// hashAndRewind calcula o SHA-256 do arquivo e volta ao início para gravá-lo
+func hashAndRewind(src io.ReadSeeker) (string, error) {
+	start, err := src.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, src); err != nil {
+		return "", err
 	}
+	if _, err := src.Seek(start, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
 
-	// Caminho completo do arquivo
-	filePath := filepath.Join(directory, fileName)
-	fullPath := filepath.Join(s.config.LocalPath, filePath)
+// SaveGeneratedImage grava uma imagem PNG gerada pelo servidor (ex.: resumo
+// do ano) no storage configurado e registra o usuário como dono
+func (s *MediaService) SaveGeneratedImage(data []byte, userID uint, directory string) (*MediaUploadResponse, error) {
+	fileName := s.generateFileName("generated.png", userID)
+	mimeType := "image/png"
 
-	// Criar arquivo
-	dst, err := os.Create(fullPath)
+	filePath, url, err := s.store(bytes.NewReader(data), mimeType, fileName, directory)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	defer dst.Close()
 
-	// Copiar dados
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", "", err
+	var width, height int
+	if config, err := png.DecodeConfig(bytes.NewReader(data)); err == nil {
+		width, height = config.Width, config.Height
 	}
 
-	// Gerar URL
-	url := fmt.Sprintf("%s/%s", strings.TrimRight(s.config.BaseURL, "/"), filePath)
+	media := &models.Media{
+		OwnerID:   userID,
+		URL:       url,
+		FilePath:  filePath,
+		MediaType: MediaTypeImage,
+		MimeType:  mimeType,
+		FileSize:  int64(len(data)),
+		Width:     width,
+		Height:    height,
+	}
+	if err := s.mediaRepo.Create(media); err != nil {
+		if delErr := s.DeleteFile(filePath); delErr != nil {
+			log.Printf("Falha ao remover arquivo não registrado %s: %v", filePath, delErr)
+		}
+		return nil, errors.New("erro ao registrar mídia")
+	}
 
-	return filePath, url, nil
+	return &MediaUploadResponse{
+		ID:        media.ID,
+		URL:       url,
+		FilePath:  filePath,
+		FileName:  fileName,
+		FileSize:  media.FileSize,
+		MimeType:  mimeType,
+		MediaType: MediaTypeImage,
+		Width:     width,
+		Height:    height,
+	}, nil
 }
 
-// ============================================================================
-// UPLOAD S3 (para uso futuro)
-// ============================================================================
+// StoreGeneratedFile grava um arquivo gerado pelo servidor (ex.: exportação
+// de roteiro) sem registrá-lo como mídia, pois ele não pode ser anexado a
+// posts; retorna o caminho e a URL
+func (s *MediaService) StoreGeneratedFile(data []byte, userID uint, directory, extension, contentType string) (string, string, error) {
+	fileName := s.generateFileName("generated"+extension, userID)
+	return s.store(bytes.NewReader(data), contentType, fileName, directory)
+}
 
-func (s *MediaService) uploadToS3(file *multipart.FileHeader, fileName, directory string) (string, string, error) {
-	if s.config.AWSConfig == nil {
-		return "", "", fmt.Errorf("configuração AWS não encontrada")
+// LoadImage decodifica uma imagem enviada à plataforma a partir da sua URL;
+// arquivos locais são lidos do disco e os demais baixados do storage. URLs
+// que não são de mídias registradas são recusadas
+func (s *MediaService) LoadImage(url string) (image.Image, error) {
+	media, err := s.mediaRepo.GetByURLs([]string{url})
+	if err != nil || len(media) == 0 {
+		return nil, errors.New("mídia não encontrada")
 	}
 
-	// Abrir arquivo
-	src, err := file.Open()
+	src, err := s.OpenMedia(&media[0])
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer src.Close()
 
-	// Criar sessão AWS
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(s.config.AWSConfig.Region),
-		Credentials: credentials.NewStaticCredentials(
-			s.config.AWSConfig.AccessKey,
-			s.config.AWSConfig.SecretKey,
-			"",
-		),
-	})
+	data, err := io.ReadAll(io.LimitReader(src, s.config.MaxFileSize+1))
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	if int64(len(data)) > s.config.MaxFileSize {
+		return nil, errors.New("arquivo muito grande")
+	}
+
+	// As dimensões do cabeçalho são conferidas antes de alocar os pixels
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if int64(config.Width)*int64(config.Height) > s.config.MaxImagePixels {
+		return nil, fmt.Errorf("imagem muito grande: %dx%d pixels", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// IsStoredMedia indica se a URL é de uma mídia guardada pela plataforma
+func (s *MediaService) IsStoredMedia(url string) bool {
+	media, err := s.mediaRepo.GetByURLs([]string{url})
+	return err == nil && len(media) > 0
+}
+
+// OpenMedia abre o arquivo original da mídia no storage configurado
+func (s *MediaService) OpenMedia(media *models.Media) (io.ReadCloser, error) {
+	return s.storage.Open(media.FilePath)
+}
+
+// OpenVariant abre uma versão da mídia ("" para o original) e devolve o tipo
+// do arquivo. Variantes que apontam para o original abrem o original
+func (s *MediaService) OpenVariant(media *models.Media, variant string) (io.ReadCloser, string, error) {
+	if variant == "" {
+		src, err := s.storage.Open(media.FilePath)
+		return src, media.MimeType, err
 	}
 
-	// Criar uploader
-	uploader := s3manager.NewUploader(sess)
+	version, ok := media.Variants[variant]
+	if !ok {
+		return nil, "", errors.New("variante não encontrada")
+	}
+	if version.FilePath == "" {
+		src, err := s.storage.Open(media.FilePath)
+		return src, media.MimeType, err
+	}
+	src, err := s.storage.Open(version.FilePath)
+	return src, version.MimeType, err
+}
 
-	// Caminho do arquivo no S3
-	s3Key := fmt.Sprintf("%s/%s", directory, fileName)
+// StoreDerivedFile grava um arquivo gerado a partir de uma mídia (ex.:
+// segmentos HLS) mantendo o nome, pois outros arquivos o referenciam
+func (s *MediaService) StoreDerivedFile(src io.Reader, contentType, directory, fileName string) (string, string, error) {
+	return s.store(src, contentType, fileName, directory)
+}
 
-	// Determinar Content-Type
-	contentType := file.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = s.getContentTypeFromExtension(fileName)
+// RemoveFiles apaga arquivos do storage que não têm registro de mídia próprio
+func (s *MediaService) RemoveFiles(filePaths []string) {
+	for _, filePath := range filePaths {
+		if err := s.removeStoredFile(filePath); err != nil {
+			log.Printf("Falha ao remover arquivo %s: %v", filePath, err)
+		}
 	}
+}
 
-	// Upload
-	result, err := uploader.Upload(&s3manager.UploadInput{
-		Bucket:      aws.String(s.config.AWSConfig.Bucket),
-		Key:         aws.String(s3Key),
-		Body:        src,
-		ContentType: aws.String(contentType),
-		ACL:         aws.String("public-read"),
-	})
-	if err != nil {
+func (s *MediaService) store(src io.Reader, contentType, fileName, directory string) (string, string, error) {
+	filePath := path.Join(directory, fileName)
+	if err := s.storage.Put(filePath, src, contentType); err != nil {
 		return "", "", err
 	}
-
-	return s3Key, result.Location, nil
+	return filePath, s.storage.URL(filePath), nil
 }
 
 // ============================================================================
 // DELETE FILES
 // ============================================================================
 
+// DeleteFile remove o arquivo do storage e, se ele estiver registrado, o
+// registro da mídia. Não confere dono nem referências; para pedidos de
+// usuários use DeleteMedia
 func (s *MediaService) DeleteFile(filePath string) error {
-	switch s.config.StorageType {
-	case "s3":
-		return s.deleteFromS3(filePath)
-	default: // local
-		return s.deleteFromLocal(filePath)
+	if media, err := s.mediaRepo.GetByFilePath(filePath); err == nil {
+		s.removeVariants(media.Variants)
+		s.RemoveFiles(media.ExtraFiles)
 	}
-}
 
-func (s *MediaService) deleteFromLocal(filePath string) error {
-	fullPath := filepath.Join(s.config.LocalPath, filePath)
-	return os.Remove(fullPath)
+	if err := s.removeStoredFile(filePath); err != nil {
+		return err
+	}
+
+	return s.mediaRepo.DeleteByFilePath(filePath)
 }
 
-func (s *MediaService) deleteFromS3(filePath string) error {
-	if s.config.AWSConfig == nil {
-		return fmt.Errorf("configuração AWS não encontrada")
+// removeStoredFile apaga o arquivo do storage e agenda a invalidação da CDN,
+// para que a cópia em cache deixe de ser servida
+func (s *MediaService) removeStoredFile(filePath string) error {
+	if err := s.storage.Delete(filePath); err != nil {
+		return err
 	}
+	if s.cdn != nil {
+		s.cdn.add(filePath)
+	}
+	return nil
+}
 
-	// Criar sessão AWS
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(s.config.AWSConfig.Region),
-		Credentials: credentials.NewStaticCredentials(
-			s.config.AWSConfig.AccessKey,
-			s.config.AWSConfig.SecretKey,
-			"",
-		),
-	})
+// DeleteMedia remove uma mídia a pedido do usuário: só o dono (ou um admin)
+// pode remover, e apenas se nenhum conteúdo ainda a exibe
+func (s *MediaService) DeleteMedia(filePath string, userID uint, isAdmin bool) error {
+	info, err := s.GetMediaInfo(filePath, userID, isAdmin)
 	if err != nil {
 		return err
 	}
 
-	// Criar cliente S3
-	svc := s3.New(sess)
+	if info.References > 0 {
+		return fmt.Errorf("mídia em uso por %d conteúdo(s); remova-a deles antes de apagar", info.References)
+	}
 
-	// Deletar objeto
-	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
-		Bucket: aws.String(s.config.AWSConfig.Bucket),
-		Key:    aws.String(filePath),
-	})
+	if err := s.DeleteFile(info.FilePath); err != nil {
+		log.Printf("Falha ao remover mídia %s: %v", info.FilePath, err)
+		return errors.New("erro ao remover mídia")
+	}
+	return nil
+}
 
-	return err
+// GetMediaInfo retorna o registro da mídia e quantos conteúdos a usam, só
+// para o dono ou um admin
+func (s *MediaService) GetMediaInfo(filePath string, userID uint, isAdmin bool) (*MediaInfo, error) {
+	media, err := s.mediaRepo.GetByFilePath(filePath)
+	if err != nil {
+		return nil, errors.New("mídia não encontrada")
+	}
+	return s.mediaInfo(media, userID, isAdmin)
+}
+
+// GetMediaByID busca a mídia pelo ID, com o andamento do processamento e da
+// moderação, para o cliente saber quando ela está pronta
+func (s *MediaService) GetMediaByID(mediaID, userID uint, isAdmin bool) (*MediaInfo, error) {
+	media, err := s.mediaRepo.GetByID(mediaID)
+	if err != nil {
+		return nil, errors.New("mídia não encontrada")
+	}
+	return s.mediaInfo(media, userID, isAdmin)
+}
+
+func (s *MediaService) mediaInfo(media *models.Media, userID uint, isAdmin bool) (*MediaInfo, error) {
+	if media.OwnerID != userID && !isAdmin {
+		return nil, errors.New("você não tem permissão para acessar esta mídia")
+	}
+
+	references, err := s.mediaRepo.CountReferences(media)
+	if err != nil {
+		return nil, errors.New("erro ao verificar uso da mídia")
+	}
+
+	return &MediaInfo{Media: *media, References: references, Usable: media.IsUsable()}, nil
 }
 
 // ============================================================================
@@ -289,16 +718,7 @@ func (s *MediaService) deleteFromS3(filePath string) error {
 // ============================================================================
 
 func (s *MediaService) GetFileURL(filePath string) string {
-	switch s.config.StorageType {
-	case "s3":
-		if s.config.AWSConfig != nil && s.config.AWSConfig.CDNUrl != "" {
-			return fmt.Sprintf("%s/%s", strings.TrimRight(s.config.AWSConfig.CDNUrl, "/"), filePath)
-		}
-		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s",
-			s.config.AWSConfig.Bucket, s.config.AWSConfig.Region, filePath)
-	default: // local
-		return fmt.Sprintf("%s/%s", strings.TrimRight(s.config.BaseURL, "/"), filePath)
-	}
+	return s.storage.URL(filePath)
 }
 
 func (s *MediaService) ValidateFile(file *multipart.FileHeader, mediaType MediaType) error {
@@ -307,8 +727,12 @@ func (s *MediaService) ValidateFile(file *multipart.FileHeader, mediaType MediaT
 		return fmt.Errorf("arquivo muito grande. Tamanho máximo: %d MB", s.config.MaxFileSize/(1024*1024))
 	}
 
-	// Validar extensão
-	ext := strings.ToLower(filepath.Ext(file.Filename))
+	return s.ValidateFileName(file.Filename, mediaType)
+}
+
+// ValidateFileName confere a extensão do arquivo para o tipo de mídia
+func (s *MediaService) ValidateFileName(fileName string, mediaType MediaType) error {
+	ext := strings.ToLower(filepath.Ext(fileName))
 
 	var allowedExtensions []string
 	switch mediaType {
@@ -338,17 +762,18 @@ func (s *MediaService) generateFileName(originalName string, userID uint) string
 	return fmt.Sprintf("%d_%d_%s%s", userID, timestamp, uuid, ext)
 }
 
-func (s *MediaService) getImageDimensions(file *multipart.FileHeader, mediaType MediaType) (int, int, error) {
-	if mediaType != MediaTypeImage {
-		return 0, 0, nil
+// imageDimensions lê as dimensões do cabeçalho da imagem, já na posição de
+// exibição
+func imageDimensions(data []byte, orientation int) (int, int) {
+	width, height := imageConfig(data)
+	if orientation >= 5 {
+		return height, width
 	}
-
-	// Por enquanto retorna 0,0 - pode ser implementado com bibliotecas de processamento de imagem
-	return 0, 0, nil
+	return width, height
 }
 
 func (s *MediaService) getContentTypeFromExtension(fileName string) string {
-	ext := filepath.Ext(fileName)
+	ext := strings.ToLower(filepath.Ext(fileName))
 
 	contentTypes := map[string]string{
 		".jpg":  "image/jpeg",
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type MemoryServiceInterface interface {
+	GetMemories(userID uint, date time.Time) (*models.MemoriesResponse, error)
+	SetMemoriesEnabled(userID uint, enabled bool) (*models.MemoriesResponse, error)
+	SendMemoryNotifications(date time.Time) (int, error)
+	StartMemoriesScheduler(interval time.Duration)
+}
+
+type MemoryService struct {
+	memoryRepo          repositories.MemoryRepositoryInterface
+	notificationService NotificationServiceInterface
+}
+
+type MemorySettingsRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+func NewMemoryService(memoryRepo repositories.MemoryRepositoryInterface, notificationService NotificationServiceInterface) MemoryServiceInterface {
+	return &MemoryService{
+		memoryRepo:          memoryRepo,
+		notificationService: notificationService,
+	}
+}
+
+// GetMemories retorna os posts e roteiros do usuário criados no mesmo dia e
+// mês em anos anteriores; sem data informada considera o dia de hoje
+func (s *MemoryService) GetMemories(userID uint, date time.Time) (*models.MemoriesResponse, error) {
+	enabled, err := s.memoryRepo.IsMemoriesEnabled(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("usuário não encontrado")
+		}
+		return nil, errors.New("erro ao buscar lembranças")
+	}
+
+	day := memoryDay(date)
+	response := &models.MemoriesResponse{
+		Date:    day.Format("2006-01-02"),
+		Enabled: enabled,
+		Years:   []models.MemoryYear{},
+	}
+	if !enabled {
+		return response, nil
+	}
+
+	years, err := s.collectMemories(userID, day)
+	if err != nil {
+		return nil, err
+	}
+	response.Years = years
+
+	return response, nil
+}
+
+func (s *MemoryService) SetMemoriesEnabled(userID uint, enabled bool) (*models.MemoriesResponse, error) {
+	if err := s.memoryRepo.SetMemoriesEnabled(userID, enabled); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("usuário não encontrado")
+		}
+		return nil, errors.New("erro ao atualizar preferência de lembranças")
+	}
+
+	return s.GetMemories(userID, time.Time{})
+}
+
+// SendMemoryNotifications avisa os usuários que têm lembranças no dia; a
+// chave da notificação garante no máximo um aviso por usuário por dia
+func (s *MemoryService) SendMemoryNotifications(date time.Time) (int, error) {
+	day := memoryDay(date)
+
+	userIDs, err := s.memoryRepo.GetUsersWithMemories(day.Month(), day.Day(), day)
+	if err != nil {
+		return 0, errors.New("erro ao buscar usuários com lembranças")
+	}
+
+	sent := 0
+	for _, userID := range userIDs {
+		years, err := s.collectMemories(userID, day)
+		if err != nil {
+			log.Printf("Falha ao buscar lembranças do usuário %d: %v", userID, err)
+			continue
+		}
+		if len(years) == 0 {
+			continue
+		}
+
+		key := "memories:" + day.Format("2006-01-02")
+		created, err := s.notificationService.Notify(&models.Notification{
+			UserID: userID,
+			Type:   models.NotificationTypeMemory,
+			Title:  "Lembranças de hoje",
+			Body:   memoryMessage(years),
+			Data:   map[string]string{"date": day.Format("2006-01-02")},
+			Key:    &key,
+		})
+		if err != nil {
+			log.Printf("Falha ao notificar lembranças do usuário %d: %v", userID, err)
+			continue
+		}
+		if created {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartMemoriesScheduler envia as notificações de lembranças periodicamente em segundo plano
+func (s *MemoryService) StartMemoriesScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if sent, err := s.SendMemoryNotifications(time.Now()); err != nil {
+				log.Println("Falha ao enviar notificações de lembranças:", err)
+			} else if sent > 0 {
+				log.Printf("%d notificações de lembranças enviadas", sent)
+			}
+		}
+	}()
+}
+
+func (s *MemoryService) collectMemories(userID uint, day time.Time) ([]models.MemoryYear, error) {
+	posts, err := s.memoryRepo.GetPostsOnDay(userID, day.Month(), day.Day(), day)
+	if err != nil {
+		return nil, errors.New("erro ao buscar lembranças")
+	}
+
+	itineraries, err := s.memoryRepo.GetItinerariesOnDay(userID, day.Month(), day.Day(), day)
+	if err != nil {
+		return nil, errors.New("erro ao buscar lembranças")
+	}
+
+	byYear := make(map[int]*models.MemoryYear)
+	yearFor := func(year int) *models.MemoryYear {
+		if memory, ok := byYear[year]; ok {
+			return memory
+		}
+		memory := &models.MemoryYear{
+			Year:        year,
+			YearsAgo:    day.Year() - year,
+			Posts:       []models.PostResponse{},
+			Itineraries: []models.ItineraryResponse{},
+		}
+		byYear[year] = memory
+		return memory
+	}
+
+	for i := range posts {
+		memory := yearFor(posts[i].CreatedAt.UTC().Year())
+		memory.Posts = append(memory.Posts, *posts[i].ToResponse(userID))
+	}
+	for i := range itineraries {
+		memory := yearFor(itineraries[i].CreatedAt.UTC().Year())
+		memory.Itineraries = append(memory.Itineraries, *itineraries[i].ToResponse())
+	}
+
+	years := make([]models.MemoryYear, 0, len(byYear))
+	for _, memory := range byYear {
+		years = append(years, *memory)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year > years[j].Year })
+
+	return years, nil
+}
+
+// memoryDay normaliza a data para o início do dia em UTC
+func memoryDay(date time.Time) time.Time {
+	if date.IsZero() {
+		date = time.Now()
+	}
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// memoryMessage monta o texto da notificação a partir da lembrança mais
+// recente que tenha um lugar associado
+func memoryMessage(years []models.MemoryYear) string {
+	for _, memory := range years {
+		if place := memoryPlace(memory); place != "" {
+			return fmt.Sprintf("%s você estava em %s", yearsAgoText(memory.YearsAgo), place)
+		}
+	}
+	return fmt.Sprintf("%s você compartilhou um momento neste dia", yearsAgoText(years[0].YearsAgo))
+}
+
+func memoryPlace(memory models.MemoryYear) string {
+	for _, post := range memory.Posts {
+		if post.Location != "" {
+			return post.Location
+		}
+	}
+	for _, itinerary := range memory.Itineraries {
+		if itinerary.City != "" {
+			return itinerary.City
+		}
+	}
+	for _, itinerary := range memory.Itineraries {
+		if itinerary.Title != "" {
+			return itinerary.Title
+		}
+	}
+	return ""
+}
+
+func yearsAgoText(years int) string {
+	if years == 1 {
+		return "Há 1 ano"
+	}
+	return fmt.Sprintf("Há %d anos", years)
+}
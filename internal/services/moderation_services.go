@@ -0,0 +1,719 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type ModerationServiceInterface interface {
+	RestorePost(postID, adminID uint, req *ModerationActionRequest) (*models.PostResponse, error)
+	RemovePost(postID, adminID uint, req *ModerationActionRequest) error
+	RestoreItinerary(itineraryID, adminID uint, req *ModerationActionRequest) (*models.ItineraryResponse, error)
+	RemoveItinerary(itineraryID, adminID uint, req *ModerationActionRequest) error
+	ReportMessage(conversationID, messageID, reporterID uint, req *ReportMessageRequest) (*models.MessageReportResponse, error)
+	GetMessageReports(status models.ReportStatus, limit, offset int) ([]models.MessageReportResponse, error)
+	GetMessageReport(reportID, adminID uint) (*models.MessageReportDetail, error)
+	ResolveMessageReport(reportID, adminID uint, req *ResolveReportRequest) (*models.MessageReportResponse, error)
+	RemoveMessage(messageID, adminID uint, req *ModerationActionRequest) error
+	PreviewBulkModeration(req *BulkModerationRequest) (*models.BulkModerationPreview, error)
+	CreateBulkModerationJob(adminID uint, req *BulkModerationRequest) (*models.BulkModerationJob, error)
+	GetBulkModerationJob(jobID uint) (*models.BulkModerationJob, error)
+	GetModerationActions(adminID uint, targetType models.ModerationTargetType, targetID uint, limit, offset int) ([]models.ModerationAction, error)
+	GetFlaggedMedia(status models.MediaModerationStatus, limit, offset int) ([]models.FlaggedMediaResponse, error)
+	ApproveMedia(mediaID, adminID uint, req *ModerationActionRequest) (*models.FlaggedMediaResponse, error)
+	RejectMedia(mediaID, adminID uint, req *ModerationActionRequest) (*models.FlaggedMediaResponse, error)
+	StartBulkModerationWorker()
+}
+
+type ReportMessageRequest struct {
+	Reason  models.ReportReason `json:"reason" binding:"required"`
+	Details string              `json:"details"`
+}
+
+type ResolveReportRequest struct {
+	Status models.ReportStatus `json:"status" binding:"required"` // resolved ou dismissed
+	Note   string              `json:"note"`
+}
+
+type ModerationActionRequest struct {
+	Note string `json:"note"`
+}
+
+type BulkModerationRequest struct {
+	Action models.BulkModerationAction `json:"action" binding:"required"` // hide_posts, delete_posts ou ban_users
+	IDs    []uint                      `json:"ids" binding:"required"`
+	Note   string                      `json:"note"`
+	DryRun bool                        `json:"dry_run"` // apenas simula, sem alterar nada
+}
+
+const (
+	maxBulkModerationIDs = 500
+	bulkJobQueueSize     = 100
+	// Mensagens antes e depois da denunciada exibidas à moderação
+	reportedMessageContext = 5
+)
+
+type ModerationService struct {
+	moderationRepo      repositories.ModerationRepositoryInterface
+	conversationRepo    repositories.ConversationRepositoryInterface
+	mediaService        MediaServiceInterface
+	conversationService ConversationServiceInterface
+	bulkJobs            chan uint
+}
+
+// NewModerationService cria o serviço de moderação; as denúncias de
+// usuários e conteúdo ficam no ReportService
+func NewModerationService(
+	moderationRepo repositories.ModerationRepositoryInterface,
+	conversationRepo repositories.ConversationRepositoryInterface,
+	mediaService MediaServiceInterface,
+	conversationService ConversationServiceInterface,
+) ModerationServiceInterface {
+	return &ModerationService{
+		moderationRepo:      moderationRepo,
+		conversationRepo:    conversationRepo,
+		mediaService:        mediaService,
+		conversationService: conversationService,
+		bulkJobs:            make(chan uint, bulkJobQueueSize),
+	}
+}
+
+// RestorePost volta a exibir um post ocultado e descarta o caso de denúncias
+// pendente
+func (s *ModerationService) RestorePost(postID, adminID uint, req *ModerationActionRequest) (*models.PostResponse, error) {
+	post, err := s.moderationRepo.GetPostForModeration(postID)
+	if err != nil || post.DeletedAt.Valid {
+		return nil, errors.New("post não encontrado")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRestorePost,
+		TargetType: models.ModerationTargetPost,
+		TargetID:   postID,
+		Note:       strings.TrimSpace(req.Note),
+	}
+
+	if err := s.moderationRepo.RestorePost(postID, audit); err != nil {
+		return nil, errors.New("erro ao restaurar post")
+	}
+
+	post.IsActive = true
+	post.HiddenAt = nil
+	return post.ToResponse(adminID), nil
+}
+
+// RemovePost exclui o post definitivamente e dá o caso de denúncias pendente
+// como procedente
+func (s *ModerationService) RemovePost(postID, adminID uint, req *ModerationActionRequest) error {
+	if _, err := s.moderationRepo.GetPostForModeration(postID); err != nil {
+		return errors.New("post não encontrado")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRemovePost,
+		TargetType: models.ModerationTargetPost,
+		TargetID:   postID,
+		Note:       strings.TrimSpace(req.Note),
+	}
+
+	if err := s.moderationRepo.RemovePost(postID, audit); err != nil {
+		return errors.New("erro ao remover post")
+	}
+
+	return nil
+}
+
+// RestoreItinerary devolve às listagens um roteiro retirado e descarta o caso
+// de denúncias pendente
+func (s *ModerationService) RestoreItinerary(itineraryID, adminID uint, req *ModerationActionRequest) (*models.ItineraryResponse, error) {
+	itinerary, err := s.moderationRepo.GetItineraryForModeration(itineraryID)
+	if err != nil || itinerary.DeletedAt.Valid {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRestoreItinerary,
+		TargetType: models.ModerationTargetItinerary,
+		TargetID:   itineraryID,
+		Note:       strings.TrimSpace(req.Note),
+	}
+
+	if err := s.moderationRepo.RestoreItinerary(itineraryID, audit); err != nil {
+		return nil, errors.New("erro ao restaurar roteiro")
+	}
+
+	itinerary.HiddenAt = nil
+	return itinerary.ToResponse(), nil
+}
+
+// RemoveItinerary exclui o roteiro e dá o caso de denúncias pendente como
+// procedente
+func (s *ModerationService) RemoveItinerary(itineraryID, adminID uint, req *ModerationActionRequest) error {
+	if _, err := s.moderationRepo.GetItineraryForModeration(itineraryID); err != nil {
+		return errors.New("roteiro não encontrado")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRemoveItinerary,
+		TargetType: models.ModerationTargetItinerary,
+		TargetID:   itineraryID,
+		Note:       strings.TrimSpace(req.Note),
+	}
+
+	if err := s.moderationRepo.RemoveItinerary(itineraryID, audit); err != nil {
+		return errors.New("erro ao remover roteiro")
+	}
+
+	return nil
+}
+
+// ReportMessage denuncia uma mensagem recebida numa conversa de que o
+// usuário participa, guardando uma cópia do conteúdo para a moderação
+func (s *ModerationService) ReportMessage(conversationID, messageID, reporterID uint, req *ReportMessageRequest) (*models.MessageReportResponse, error) {
+	if err := validateReport(req.Reason, req.Details); err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || !conversation.HasParticipant(reporterID) {
+		return nil, errors.New("conversa não encontrada")
+	}
+	message, err := s.conversationRepo.GetMessage(conversationID, messageID)
+	if err != nil {
+		return nil, errors.New("mensagem não encontrada")
+	}
+
+	if message.SenderID == reporterID {
+		return nil, errors.New("você não pode denunciar sua própria mensagem")
+	}
+	if message.Kind == models.MessageKindSystem {
+		return nil, errors.New("avisos do sistema não podem ser denunciados")
+	}
+
+	report := &models.MessageReport{
+		MessageID:      message.ID,
+		ConversationID: conversationID,
+		ReporterID:     reporterID,
+		SenderID:       message.SenderID,
+		Content:        message.Content,
+		MediaID:        message.MediaID,
+		MessageSentAt:  message.CreatedAt,
+		Reason:         req.Reason,
+		Details:        strings.TrimSpace(req.Details),
+		Status:         models.ReportStatusPending,
+	}
+
+	err = s.moderationRepo.CreateMessageReport(report)
+	if errors.Is(err, repositories.ErrAlreadyReported) {
+		return nil, errors.New("você já denunciou esta mensagem")
+	}
+	if err != nil {
+		return nil, errors.New("erro ao registrar denúncia")
+	}
+
+	return report.ToResponse(), nil
+}
+
+// GetMessageReports lista a fila de denúncias de mensagens; o conteúdo só
+// aparece no detalhe de cada denúncia
+func (s *ModerationService) GetMessageReports(status models.ReportStatus, limit, offset int) ([]models.MessageReportResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if status == "" {
+		status = models.ReportStatusPending
+	}
+
+	reports, err := s.moderationRepo.GetMessageReports(status, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar denúncias")
+	}
+
+	responses := make([]models.MessageReportResponse, 0, len(reports))
+	for _, report := range reports {
+		responses = append(responses, *report.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetMessageReport abre a denúncia com o conteúdo denunciado e as mensagens
+// vizinhas. Conversas são privadas, então cada leitura fica registrada na
+// auditoria com o admin que a fez
+func (s *ModerationService) GetMessageReport(reportID, adminID uint) (*models.MessageReportDetail, error) {
+	report, err := s.moderationRepo.GetMessageReportByID(reportID)
+	if err != nil {
+		return nil, errors.New("denúncia não encontrada")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionViewMessage,
+		TargetType: models.ModerationTargetMessageReport,
+		TargetID:   report.ID,
+	}
+	if err := s.moderationRepo.CreateAction(audit); err != nil {
+		return nil, errors.New("erro ao registrar acesso à denúncia")
+	}
+
+	detail := &models.MessageReportDetail{
+		MessageReportResponse: report.ToResponse(),
+		Content:               report.Content,
+		Context:               []models.MessageResponse{},
+	}
+	if report.MediaID != nil {
+		detail.Attachment = s.conversationService.ModeratorAttachment(*report.MediaID, adminID)
+	}
+
+	message, err := s.moderationRepo.GetMessageForModeration(report.MessageID)
+	detail.Removed = err != nil || message.DeletedAt.Valid
+
+	neighbors, err := s.moderationRepo.GetMessageContext(report.ConversationID, report.MessageID, reportedMessageContext)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mensagens da conversa")
+	}
+	for _, neighbor := range neighbors {
+		detail.Context = append(detail.Context, *neighbor.ToResponse())
+	}
+
+	return detail, nil
+}
+
+// ResolveMessageReport decide uma denúncia isolada sem alterar a mensagem;
+// remover a mensagem é uma ação própria
+func (s *ModerationService) ResolveMessageReport(reportID, adminID uint, req *ResolveReportRequest) (*models.MessageReportResponse, error) {
+	if req.Status != models.ReportStatusResolved && req.Status != models.ReportStatusDismissed {
+		return nil, errors.New("status deve ser 'resolved' ou 'dismissed'")
+	}
+
+	report, err := s.moderationRepo.GetMessageReportByID(reportID)
+	if err != nil {
+		return nil, errors.New("denúncia não encontrada")
+	}
+
+	if report.Status != models.ReportStatusPending {
+		return nil, errors.New("denúncia já resolvida")
+	}
+
+	report.ResolvedByID = &adminID
+	report.ResolutionNote = strings.TrimSpace(req.Note)
+
+	action := models.ModerationActionResolveReport
+	if req.Status == models.ReportStatusDismissed {
+		action = models.ModerationActionDismissReport
+	}
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: models.ModerationTargetMessageReport,
+		TargetID:   report.ID,
+		Note:       report.ResolutionNote,
+	}
+
+	resolved, err := s.moderationRepo.ResolveMessageReport(report, req.Status, audit)
+	if err != nil {
+		return nil, errors.New("erro ao resolver denúncia")
+	}
+	if !resolved {
+		return nil, errors.New("denúncia já resolvida")
+	}
+
+	return report.ToResponse(), nil
+}
+
+// RemoveMessage apaga a mensagem da conversa para todos os participantes e
+// dá as denúncias pendentes como procedentes
+func (s *ModerationService) RemoveMessage(messageID, adminID uint, req *ModerationActionRequest) error {
+	message, err := s.moderationRepo.GetMessageForModeration(messageID)
+	if err != nil {
+		return errors.New("mensagem não encontrada")
+	}
+	if message.DeletedAt.Valid {
+		return errors.New("mensagem já foi removida")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRemoveMessage,
+		TargetType: models.ModerationTargetMessage,
+		TargetID:   messageID,
+		Note:       strings.TrimSpace(req.Note),
+	}
+
+	removed, err := s.moderationRepo.RemoveMessage(messageID, audit)
+	if err != nil {
+		return errors.New("erro ao remover mensagem")
+	}
+	if !removed {
+		return errors.New("mensagem já foi removida")
+	}
+
+	return nil
+}
+
+// PreviewBulkModeration simula o lote e informa o que aconteceria com cada ID
+func (s *ModerationService) PreviewBulkModeration(req *BulkModerationRequest) (*models.BulkModerationPreview, error) {
+	ids, err := s.validateBulkModerationRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.BulkModerationPreview{
+		Action: req.Action,
+		DryRun: true,
+		Total:  len(ids),
+		Items:  make([]models.BulkModerationItem, 0, len(ids)),
+	}
+	for _, id := range ids {
+		item := s.checkBulkTarget(req.Action, id)
+		if item.Status == models.BulkItemWouldApply {
+			preview.Applicable++
+		}
+		preview.Items = append(preview.Items, item)
+	}
+
+	return preview, nil
+}
+
+// CreateBulkModerationJob registra o lote e o coloca na fila de processamento;
+// o progresso é acompanhado pelo GetBulkModerationJob
+func (s *ModerationService) CreateBulkModerationJob(adminID uint, req *BulkModerationRequest) (*models.BulkModerationJob, error) {
+	ids, err := s.validateBulkModerationRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.BulkModerationJob{
+		AdminID:   adminID,
+		Action:    req.Action,
+		TargetIDs: ids,
+		Note:      strings.TrimSpace(req.Note),
+		Status:    models.BulkModerationPending,
+		Total:     len(ids),
+		Results:   []models.BulkModerationItem{},
+	}
+	if err := s.moderationRepo.CreateBulkJob(job); err != nil {
+		return nil, errors.New("erro ao criar lote de moderação")
+	}
+
+	s.enqueueBulkJob(job.ID)
+	return job, nil
+}
+
+func (s *ModerationService) GetBulkModerationJob(jobID uint) (*models.BulkModerationJob, error) {
+	job, err := s.moderationRepo.GetBulkJobByID(jobID)
+	if err != nil {
+		return nil, errors.New("lote de moderação não encontrado")
+	}
+	return job, nil
+}
+
+// GetModerationActions lista o histórico de auditoria; filtros zerados são
+// ignorados
+func (s *ModerationService) GetModerationActions(adminID uint, targetType models.ModerationTargetType, targetID uint, limit, offset int) ([]models.ModerationAction, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	filter := repositories.ModerationActionFilter{
+		AdminID:    adminID,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}
+	actions, err := s.moderationRepo.GetModerationActions(filter, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar histórico de moderação")
+	}
+
+	return actions, nil
+}
+
+// GetFlaggedMedia lista as imagens sinalizadas pela moderação automática
+// (ou já revisadas, pelo status)
+func (s *ModerationService) GetFlaggedMedia(status models.MediaModerationStatus, limit, offset int) ([]models.FlaggedMediaResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if status == "" {
+		status = models.MediaModerationFlagged
+	}
+
+	media, err := s.moderationRepo.GetMediaByModerationStatus(status, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mídias sinalizadas")
+	}
+
+	responses := make([]models.FlaggedMediaResponse, 0, len(media))
+	for i := range media {
+		responses = append(responses, *media[i].ToFlaggedResponse())
+	}
+	return responses, nil
+}
+
+// ApproveMedia libera uma mídia sinalizada para uso em posts e stories
+func (s *ModerationService) ApproveMedia(mediaID, adminID uint, req *ModerationActionRequest) (*models.FlaggedMediaResponse, error) {
+	return s.reviewMedia(mediaID, adminID, req, models.MediaModerationApproved)
+}
+
+// RejectMedia recusa uma mídia sinalizada e apaga seus arquivos do storage;
+// o registro fica como histórico
+func (s *ModerationService) RejectMedia(mediaID, adminID uint, req *ModerationActionRequest) (*models.FlaggedMediaResponse, error) {
+	media, err := s.reviewMedia(mediaID, adminID, req, models.MediaModerationRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	files := append([]string{media.FilePath}, media.ExtraFiles...)
+	for _, variant := range media.Variants {
+		if variant.FilePath != "" {
+			files = append(files, variant.FilePath)
+		}
+	}
+	s.mediaService.RemoveFiles(files)
+
+	return media, nil
+}
+
+func (s *ModerationService) reviewMedia(mediaID, adminID uint, req *ModerationActionRequest, status models.MediaModerationStatus) (*models.FlaggedMediaResponse, error) {
+	media, err := s.moderationRepo.GetMediaForModeration(mediaID)
+	if err != nil {
+		return nil, errors.New("mídia não encontrada")
+	}
+
+	action := models.ModerationActionApproveMedia
+	if status == models.MediaModerationRejected {
+		action = models.ModerationActionRejectMedia
+	}
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: models.ModerationTargetMedia,
+		TargetID:   mediaID,
+		Note:       strings.TrimSpace(req.Note),
+	}
+
+	reviewed, err := s.moderationRepo.ReviewMedia(media, status, audit)
+	if err != nil {
+		return nil, errors.New("erro ao revisar mídia")
+	}
+	if !reviewed {
+		return nil, errors.New("mídia não está aguardando revisão")
+	}
+
+	return media.ToFlaggedResponse(), nil
+}
+
+// StartBulkModerationWorker processa os lotes de moderação em segundo plano,
+// retomando os que foram interrompidos por uma reinicialização
+func (s *ModerationService) StartBulkModerationWorker() {
+	go func() {
+		for jobID := range s.bulkJobs {
+			if err := s.processBulkJob(jobID); err != nil {
+				log.Printf("Falha ao processar lote de moderação %d: %v", jobID, err)
+			}
+		}
+	}()
+
+	jobs, err := s.moderationRepo.GetUnfinishedBulkJobs()
+	if err != nil {
+		log.Println("Falha ao buscar lotes de moderação pendentes:", err)
+		return
+	}
+	for _, job := range jobs {
+		s.enqueueBulkJob(job.ID)
+	}
+}
+
+// enqueueBulkJob não bloqueia a requisição quando a fila está cheia
+func (s *ModerationService) enqueueBulkJob(jobID uint) {
+	select {
+	case s.bulkJobs <- jobID:
+	default:
+		go func() { s.bulkJobs <- jobID }()
+	}
+}
+
+// processBulkJob aplica a ação a cada ID, gravando o progresso item a item;
+// um lote interrompido continua do primeiro item ainda não processado
+func (s *ModerationService) processBulkJob(jobID uint) error {
+	job, err := s.moderationRepo.GetBulkJobByID(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.BulkModerationCompleted {
+		return nil
+	}
+
+	if job.StartedAt == nil {
+		now := time.Now()
+		job.StartedAt = &now
+	}
+	job.Status = models.BulkModerationRunning
+	if err := s.moderationRepo.UpdateBulkJob(job); err != nil {
+		return err
+	}
+
+	for _, id := range job.TargetIDs[job.Processed:] {
+		item := s.applyBulkAction(job, id)
+
+		job.Processed++
+		job.Results = append(job.Results, item)
+		switch item.Status {
+		case models.BulkItemApplied:
+			job.Applied++
+		case models.BulkItemFailed:
+			job.Failed++
+		default:
+			job.Skipped++
+		}
+
+		if err := s.moderationRepo.UpdateBulkJob(job); err != nil {
+			log.Printf("Falha ao registrar progresso do lote de moderação %d: %v", job.ID, err)
+		}
+	}
+
+	now := time.Now()
+	job.Status = models.BulkModerationCompleted
+	job.FinishedAt = &now
+	return s.moderationRepo.UpdateBulkJob(job)
+}
+
+// applyBulkAction executa a ação do lote em um ID, registrando a auditoria
+// vinculada ao lote
+func (s *ModerationService) applyBulkAction(job *models.BulkModerationJob, id uint) models.BulkModerationItem {
+	item := s.checkBulkTarget(job.Action, id)
+	if item.Status != models.BulkItemWouldApply {
+		return item
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:  job.AdminID,
+		TargetID: id,
+		Note:     job.Note,
+		JobID:    &job.ID,
+	}
+
+	applied := true
+	var err error
+	switch job.Action {
+	case models.BulkModerationHidePosts:
+		audit.Action = models.ModerationActionHidePost
+		audit.TargetType = models.ModerationTargetPost
+		applied, err = s.moderationRepo.HidePost(id, audit)
+	case models.BulkModerationDeletePosts:
+		audit.Action = models.ModerationActionRemovePost
+		audit.TargetType = models.ModerationTargetPost
+		err = s.moderationRepo.RemovePost(id, audit)
+	case models.BulkModerationBanUsers:
+		audit.Action = models.ModerationActionBanUser
+		audit.TargetType = models.ModerationTargetUser
+		applied, err = s.moderationRepo.BanUser(id, "", audit)
+	}
+
+	switch {
+	case err != nil:
+		log.Printf("Falha na ação %s do lote de moderação %d para o ID %d: %v", job.Action, job.ID, id, err)
+		return models.BulkModerationItem{ID: id, Status: models.BulkItemFailed, Message: "erro ao aplicar ação"}
+	case !applied:
+		// Alterado por outro moderador entre a verificação e a ação
+		return models.BulkModerationItem{ID: id, Status: models.BulkItemSkipped, Message: "alvo já alterado"}
+	default:
+		return models.BulkModerationItem{ID: id, Status: models.BulkItemApplied}
+	}
+}
+
+// checkBulkTarget verifica se a ação do lote se aplica ao ID, sem alterá-lo
+func (s *ModerationService) checkBulkTarget(action models.BulkModerationAction, id uint) models.BulkModerationItem {
+	item := models.BulkModerationItem{ID: id, Status: models.BulkItemWouldApply}
+
+	switch action {
+	case models.BulkModerationHidePosts, models.BulkModerationDeletePosts:
+		post, err := s.moderationRepo.GetPostForModeration(id)
+		if err != nil || (action == models.BulkModerationHidePosts && post.DeletedAt.Valid) {
+			item.Status = models.BulkItemNotFound
+			item.Message = "post não encontrado"
+		} else if action == models.BulkModerationHidePosts && !post.IsActive {
+			item.Status = models.BulkItemSkipped
+			item.Message = "post já está oculto"
+		}
+	case models.BulkModerationBanUsers:
+		user, err := s.moderationRepo.GetUserForModeration(id)
+		if err != nil {
+			item.Status = models.BulkItemNotFound
+			item.Message = "usuário não encontrado"
+		} else if user.UserType == models.UserTypeAdmin {
+			item.Status = models.BulkItemSkipped
+			item.Message = "administradores não podem ser banidos"
+		} else if user.AccountStatus(time.Now()) == models.AccountStatusBanned {
+			item.Status = models.BulkItemSkipped
+			item.Message = "usuário já está banido"
+		}
+	}
+
+	return item
+}
+
+// Funções de validação
+func validateReport(reportReason models.ReportReason, details string) error {
+	valid := false
+	for _, reason := range models.ReportReasons {
+		if reportReason == reason {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.New("motivo de denúncia inválido")
+	}
+
+	if len(details) > 1000 {
+		return errors.New("detalhes devem ter no máximo 1000 caracteres")
+	}
+
+	return nil
+}
+
+// validateBulkModerationRequest valida o lote e devolve os IDs sem repetição,
+// na ordem enviada
+func (s *ModerationService) validateBulkModerationRequest(req *BulkModerationRequest) ([]uint, error) {
+	switch req.Action {
+	case models.BulkModerationHidePosts, models.BulkModerationDeletePosts, models.BulkModerationBanUsers:
+	default:
+		return nil, errors.New("ação deve ser 'hide_posts', 'delete_posts' ou 'ban_users'")
+	}
+
+	if len(req.Note) > 1000 {
+		return nil, errors.New("nota deve ter no máximo 1000 caracteres")
+	}
+
+	seen := make(map[uint]bool, len(req.IDs))
+	ids := make([]uint, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		if id == 0 {
+			return nil, errors.New("IDs devem ser números válidos")
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("informe ao menos um ID")
+	}
+	if len(ids) > maxBulkModerationIDs {
+		return nil, fmt.Errorf("máximo de %d IDs por lote", maxBulkModerationIDs)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// NotificationListener transforma os eventos de interação entre usuários
+// (curtidas, novos seguidores, mensagens e respostas a avaliações) em
+// notificações, para os serviços de origem não dependerem do
+// NotificationService
+type NotificationListener struct {
+	notificationService NotificationServiceInterface
+	postRepo            repositories.PostRepositoryInterface
+	userRepo            repositories.UserRepositoryInterface
+	conversationRepo    repositories.ConversationRepositoryInterface
+	ratingRepo          repositories.RatingRepositoryInterface
+	itineraryRepo       repositories.ItineraryRepositoryInterface
+}
+
+func NewNotificationListener(
+	notificationService NotificationServiceInterface,
+	postRepo repositories.PostRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	conversationRepo repositories.ConversationRepositoryInterface,
+	ratingRepo repositories.RatingRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	eventBus events.BusInterface,
+) *NotificationListener {
+	listener := &NotificationListener{
+		notificationService: notificationService,
+		postRepo:            postRepo,
+		userRepo:            userRepo,
+		conversationRepo:    conversationRepo,
+		ratingRepo:          ratingRepo,
+		itineraryRepo:       itineraryRepo,
+	}
+
+	eventBus.Subscribe(events.PostLiked, listener.onPostLiked)
+	eventBus.Subscribe(events.UserFollowed, listener.onUserFollowed)
+	eventBus.Subscribe(events.MessageSent, listener.onMessageSent)
+	eventBus.Subscribe(events.RatingReplied, listener.onRatingReplied)
+
+	return listener
+}
+
+// onPostLiked avisa o autor; as curtidas do post se juntam numa só
+// notificação enquanto ela não for lida
+func (l *NotificationListener) onPostLiked(event events.Event) {
+	authorID, err := strconv.ParseUint(event.Data["author_id"], 10, 32)
+	if err != nil || uint(authorID) == event.ActorID {
+		return
+	}
+
+	post, err := l.postRepo.GetByID(event.EntityID, uint(authorID))
+	if err != nil {
+		return
+	}
+	liker, err := l.userRepo.GetByID(event.ActorID)
+	if err != nil {
+		return
+	}
+
+	title := truncateString(post.Content, 200)
+	if title == "" {
+		title = "Seu post"
+	}
+	groupKey := fmt.Sprintf("post_like:%d", post.ID)
+	_, err = l.notificationService.NotifyGrouped(&models.Notification{
+		UserID: post.AuthorID,
+		Type:   models.NotificationTypePostLike,
+		Data: map[string]string{
+			"post_id": fmt.Sprint(post.ID),
+			"user_id": fmt.Sprint(liker.ID),
+		},
+		GroupKey: &groupKey,
+	}, liker.ID, func(actorCount int) (string, string) {
+		return title, truncateString(groupedActorsText(liker.Username, actorCount, "curtiu seu post", "curtiram seu post"), 500)
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar curtida do post %d: %v", post.ID, err)
+	}
+}
+
+// onUserFollowed avisa o usuário seguido; os novos seguidores se juntam numa
+// só notificação enquanto ela não for lida
+func (l *NotificationListener) onUserFollowed(event events.Event) {
+	follower, err := l.userRepo.GetByID(event.ActorID)
+	if err != nil {
+		return
+	}
+
+	groupKey := "new_follower"
+	_, err = l.notificationService.NotifyGrouped(&models.Notification{
+		UserID: event.EntityID,
+		Type:   models.NotificationTypeNewFollower,
+		Data: map[string]string{
+			"user_id": fmt.Sprint(follower.ID),
+		},
+		GroupKey: &groupKey,
+	}, follower.ID, func(actorCount int) (string, string) {
+		title := "Novo seguidor"
+		if actorCount > 1 {
+			title = "Novos seguidores"
+		}
+		return title, truncateString(groupedActorsText(follower.Username, actorCount, "começou a seguir você", "começaram a seguir você"), 500)
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar novo seguidor do usuário %d: %v", event.EntityID, err)
+	}
+}
+
+// onMessageSent avisa os outros participantes; as mensagens da conversa se
+// juntam numa só notificação enquanto ela não for lida
+func (l *NotificationListener) onMessageSent(event events.Event) {
+	conversationID, err := strconv.ParseUint(event.Data["conversation_id"], 10, 32)
+	if err != nil {
+		return
+	}
+	conversation, err := l.conversationRepo.GetByID(uint(conversationID))
+	if err != nil {
+		return
+	}
+	sender, err := l.userRepo.GetByID(event.ActorID)
+	if err != nil {
+		return
+	}
+
+	groupKey := fmt.Sprintf("direct_message:%d", conversation.ID)
+	for _, participant := range conversation.Participants {
+		if participant.UserID == sender.ID {
+			continue
+		}
+		_, err := l.notificationService.NotifyGrouped(&models.Notification{
+			UserID: participant.UserID,
+			Type:   models.NotificationTypeDirectMessage,
+			Data: map[string]string{
+				"conversation_id": fmt.Sprint(conversation.ID),
+				"message_id":      fmt.Sprint(event.EntityID),
+			},
+			GroupKey: &groupKey,
+		}, sender.ID, func(actorCount int) (string, string) {
+			// No grupo da viagem o título da notificação é o nome do grupo
+			if conversation.Type == models.ConversationTypeTrip {
+				if actorCount > 1 {
+					return conversation.Title, truncateString(groupedActorsText(sender.Username, actorCount, "enviou uma mensagem", "enviaram mensagens"), 500)
+				}
+				return conversation.Title, truncateString(sender.Username+": "+event.Data["text"], 500)
+			}
+			if actorCount > 1 {
+				return "Novas mensagens", truncateString(groupedActorsText(sender.Username, actorCount, "enviou uma mensagem", "enviaram mensagens"), 500)
+			}
+			return sender.Username, truncateString(event.Data["text"], 500)
+		})
+		if err != nil {
+			log.Printf("Falha ao notificar mensagem %d da conversa %d: %v", event.EntityID, conversation.ID, err)
+		}
+	}
+}
+
+// onRatingReplied avisa quem avaliou. Só a primeira resposta notifica: a
+// chave evita repetir a notificação quando a resposta é editada ou removida e
+// escrita de novo
+func (l *NotificationListener) onRatingReplied(event events.Event) {
+	rating, err := l.ratingRepo.GetByID(event.EntityID)
+	if err != nil || rating.UserID == event.ActorID || rating.Reply == "" {
+		return
+	}
+	itinerary, err := l.itineraryRepo.GetByID(rating.ItineraryID)
+	if err != nil {
+		return
+	}
+
+	key := fmt.Sprintf("rating_reply:%d", rating.ID)
+	_, err = l.notificationService.Notify(&models.Notification{
+		UserID: rating.UserID,
+		Type:   models.NotificationTypeRatingReply,
+		Title:  itinerary.Title,
+		Body:   truncateString(rating.Reply, 200),
+		Data: map[string]string{
+			"itinerary_id": fmt.Sprint(itinerary.ID),
+			"rating_id":    fmt.Sprint(rating.ID),
+		},
+		Key: &key,
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar resposta da avaliação %d: %v", rating.ID, err)
+	}
+}
@@ -0,0 +1,599 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/realtime"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// PushSender entrega a notificação nos aparelhos do usuário
+type PushSender interface {
+	Send(notification *models.Notification) error
+	// SendBatch entrega num só aviso os pushes segurados durante o horário
+	// de silêncio
+	SendBatch(userID uint, notifications []models.Notification) error
+}
+
+const (
+	// Horário de silêncio sugerido para quem ainda não o configurou
+	defaultQuietHoursStart = 22 * 60
+	defaultQuietHoursEnd   = 7 * 60
+	// Pushes adiados lidos por consulta ao liberar os que venceram
+	deferredPushPageSize = 500
+)
+
+type NotificationServiceInterface interface {
+	Notify(notification *models.Notification) (bool, error)
+	NotifyGrouped(notification *models.Notification, actorID uint, describe func(actorCount int) (string, string)) (bool, error)
+	GetNotifications(userID uint, limit, offset int) ([]models.Notification, error)
+	GetNotification(userID, notificationID uint, limit, offset int) (*models.NotificationDetailResponse, error)
+	GetUnreadCount(userID uint) (*models.NotificationUnreadCount, error)
+	MarkRead(userID uint, req *MarkNotificationsReadRequest) (*models.NotificationReadResponse, error)
+	GetPreferences(userID uint) ([]models.NotificationPreferenceResponse, error)
+	UpdatePreferences(userID uint, req *UpdateNotificationPreferencesRequest) ([]models.NotificationPreferenceResponse, error)
+	GetQuietHours(userID uint) (*models.QuietHoursResponse, error)
+	UpdateQuietHours(userID uint, req *UpdateQuietHoursRequest) (*models.QuietHoursResponse, error)
+	RegisterPushSender(sender PushSender)
+	DeliverDeferredPushes(now time.Time) (int, error)
+	StartDeferredPushScheduler(interval time.Duration)
+}
+
+type NotificationService struct {
+	notificationRepo repositories.NotificationRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	hub              realtime.HubInterface
+	emailService     EmailServiceInterface
+
+	mu         sync.RWMutex
+	pushSender PushSender
+}
+
+// MarkNotificationsReadRequest marca notificações específicas (ids) ou todas
+// (all) como lidas
+type MarkNotificationsReadRequest struct {
+	IDs []uint `json:"ids" binding:"omitempty,max=100"`
+	All bool   `json:"all"`
+}
+
+type NotificationPreferenceUpdate struct {
+	Type  models.NotificationType `json:"type" binding:"required"`
+	InApp *bool                   `json:"in_app,omitempty"`
+	Push  *bool                   `json:"push,omitempty"`
+	Email *bool                   `json:"email,omitempty"`
+}
+
+// UpdateNotificationPreferencesRequest altera vários tipos de uma vez; canais
+// omitidos mantêm o valor em vigor
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceUpdate `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// UpdateQuietHoursRequest altera o horário de silêncio; campos omitidos
+// mantêm o valor atual. Horários no formato HH:MM, no fuso informado
+type UpdateQuietHoursRequest struct {
+	Timezone *string `json:"timezone,omitempty"`
+	Enabled  *bool   `json:"enabled,omitempty"`
+	Start    *string `json:"start,omitempty"`
+	End      *string `json:"end,omitempty"`
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepositoryInterface, userRepo repositories.UserRepositoryInterface, hub realtime.HubInterface, emailService EmailServiceInterface) NotificationServiceInterface {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		hub:              hub,
+		emailService:     emailService,
+	}
+}
+
+// RegisterPushSender liga o provedor de push; sem ele o canal push é ignorado
+func (s *NotificationService) RegisterPushSender(sender PushSender) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pushSender = sender
+}
+
+// Notify entrega a notificação pelos canais que o usuário mantém ativos para
+// o tipo: no app (lista e conexões em tempo real), push e e-mail. O registro
+// é gravado mesmo sem o canal do app, para a mesma chave não ser reenviada;
+// retorna false quando ela já havia sido enviada ou todos os canais estão
+// desativados
+func (s *NotificationService) Notify(notification *models.Notification) (bool, error) {
+	channels, err := notificationChannels(s.notificationRepo, notification.UserID, notification.Type)
+	if err != nil {
+		return false, errors.New("erro ao criar notificação")
+	}
+	if !channels.Any() {
+		return false, nil
+	}
+
+	notification.HiddenInApp = !channels.InApp
+	created, err := s.notificationRepo.Create(notification)
+	if err != nil {
+		return false, errors.New("erro ao criar notificação")
+	}
+	if !created {
+		return false, nil
+	}
+
+	if channels.InApp {
+		s.hub.SendToUser(notification.UserID, realtime.MessageNotification, notification)
+	}
+	if channels.Push {
+		s.sendPush(notification)
+	}
+	if channels.Email {
+		s.sendEmail(notification)
+	}
+	return true, nil
+}
+
+// NotifyGrouped soma o evento de actorID à notificação não lida com o mesmo
+// GroupKey, ou cria uma nova; describe gera título e corpo para o total de
+// atores. Push e e-mail saem só quando o grupo é criado, para uma rajada de
+// curtidas não virar uma rajada de avisos; no app a notificação atualizada é
+// reenviada com o mesmo id. Retorna false quando o ator já estava no grupo ou
+// todos os canais estão desativados
+func (s *NotificationService) NotifyGrouped(notification *models.Notification, actorID uint, describe func(actorCount int) (string, string)) (bool, error) {
+	if notification.GroupKey == nil {
+		return false, errors.New("notificação agrupada sem chave de grupo")
+	}
+
+	channels, err := notificationChannels(s.notificationRepo, notification.UserID, notification.Type)
+	if err != nil {
+		return false, errors.New("erro ao criar notificação")
+	}
+	if !channels.Any() {
+		return false, nil
+	}
+
+	notification.HiddenInApp = !channels.InApp
+	created, added, err := s.notificationRepo.AddToGroup(notification, actorID, describe)
+	if err != nil {
+		return false, errors.New("erro ao criar notificação")
+	}
+	if !added {
+		return false, nil
+	}
+
+	if channels.InApp {
+		s.hub.SendToUser(notification.UserID, realtime.MessageNotification, notification)
+	}
+	if created && channels.Push {
+		s.sendPush(notification)
+	}
+	if created && channels.Email {
+		s.sendEmail(notification)
+	}
+	return true, nil
+}
+
+// sendPush envia o push na hora ou, no horário de silêncio do usuário, o
+// segura até o fim dele; mensagens diretas e alertas da viagem não esperam
+func (s *NotificationService) sendPush(notification *models.Notification) {
+	s.mu.RLock()
+	sender := s.pushSender
+	s.mu.RUnlock()
+	if sender == nil {
+		return
+	}
+
+	if !notification.Type.BypassesQuietHours() {
+		if until, quiet := s.quietUntil(notification.UserID, time.Now()); quiet {
+			if err := s.notificationRepo.DeferPush(notification.ID, until); err != nil {
+				log.Printf("Falha ao adiar push da notificação %d: %v", notification.ID, err)
+			}
+			return
+		}
+	}
+
+	if err := sender.Send(notification); err != nil {
+		log.Printf("Falha ao enviar push da notificação %d: %v", notification.ID, err)
+	}
+}
+
+func (s *NotificationService) sendEmail(notification *models.Notification) {
+	user, err := s.userRepo.GetByID(notification.UserID)
+	if err != nil || !user.IsActive {
+		return
+	}
+
+	if err := s.emailService.SendNotificationEmail(user, notification); err != nil {
+		log.Printf("Falha ao enviar e-mail da notificação %d: %v", notification.ID, err)
+	}
+}
+
+func (s *NotificationService) GetNotifications(userID uint, limit, offset int) ([]models.Notification, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	notifications, err := s.notificationRepo.GetByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar notificações")
+	}
+
+	return notifications, nil
+}
+
+// GetNotification retorna a notificação com os usuários do grupo, paginados
+func (s *NotificationService) GetNotification(userID, notificationID uint, limit, offset int) (*models.NotificationDetailResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	notification, err := s.notificationRepo.GetByID(userID, notificationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notificação não encontrada")
+		}
+		return nil, errors.New("erro ao buscar notificação")
+	}
+
+	detail := &models.NotificationDetailResponse{
+		Notification: *notification,
+		Actors:       []models.UserResponse{},
+	}
+	if notification.GroupKey == nil {
+		return detail, nil
+	}
+
+	actors, err := s.notificationRepo.GetActors(notification.ID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar notificação")
+	}
+	for i := range actors {
+		detail.Actors = append(detail.Actors, *actors[i].ToResponse())
+	}
+	return detail, nil
+}
+
+func (s *NotificationService) GetUnreadCount(userID uint) (*models.NotificationUnreadCount, error) {
+	count, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, errors.New("erro ao contar notificações não lidas")
+	}
+	return &models.NotificationUnreadCount{UnreadCount: count}, nil
+}
+
+// MarkRead marca as notificações como lidas e envia o novo contador às
+// conexões em tempo real, para o ícone se atualizar nos outros aparelhos
+func (s *NotificationService) MarkRead(userID uint, req *MarkNotificationsReadRequest) (*models.NotificationReadResponse, error) {
+	if !req.All && len(req.IDs) == 0 {
+		return nil, errors.New("informe as notificações (ids) ou all")
+	}
+
+	var updated int64
+	var err error
+	if req.All {
+		updated, err = s.notificationRepo.MarkAllRead(userID)
+	} else {
+		updated, err = s.notificationRepo.MarkRead(userID, req.IDs)
+	}
+	if err != nil {
+		return nil, errors.New("erro ao marcar notificações como lidas")
+	}
+
+	unread, err := s.GetUnreadCount(userID)
+	if err != nil {
+		return nil, err
+	}
+	if updated > 0 {
+		s.hub.SendToUser(userID, realtime.MessageUnreadCount, unread)
+	}
+
+	return &models.NotificationReadResponse{Updated: updated, UnreadCount: unread.UnreadCount}, nil
+}
+
+// GetPreferences lista os canais de cada tipo de notificação, com os padrões
+// onde o usuário não mudou nada
+func (s *NotificationService) GetPreferences(userID uint) ([]models.NotificationPreferenceResponse, error) {
+	saved, err := s.notificationRepo.GetPreferences(userID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar preferências de notificação")
+	}
+
+	byType := make(map[models.NotificationType]models.NotificationChannels, len(saved))
+	for _, preference := range saved {
+		byType[preference.Type] = preference.NotificationChannels
+	}
+
+	types := models.NotificationTypes()
+	preferences := make([]models.NotificationPreferenceResponse, 0, len(types))
+	for _, notificationType := range types {
+		channels, customized := byType[notificationType]
+		if !customized {
+			channels = models.DefaultNotificationChannels(notificationType)
+		}
+		preferences = append(preferences, models.NotificationPreferenceResponse{
+			Type:                 notificationType,
+			NotificationChannels: channels,
+			Customized:           customized,
+		})
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences grava de uma vez os canais de vários tipos, usada pela
+// tela de ajustes dos apps
+func (s *NotificationService) UpdatePreferences(userID uint, req *UpdateNotificationPreferencesRequest) ([]models.NotificationPreferenceResponse, error) {
+	current, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[models.NotificationType]models.NotificationChannels, len(current))
+	for _, preference := range current {
+		byType[preference.Type] = preference.NotificationChannels
+	}
+
+	updated := make(map[models.NotificationType]models.NotificationChannels, len(req.Preferences))
+	for _, update := range req.Preferences {
+		channels, ok := byType[update.Type]
+		if !ok {
+			return nil, fmt.Errorf("tipo de notificação inválido: %s", update.Type)
+		}
+		if previous, ok := updated[update.Type]; ok {
+			channels = previous
+		}
+
+		if update.InApp != nil {
+			channels.InApp = *update.InApp
+		}
+		if update.Push != nil {
+			channels.Push = *update.Push
+		}
+		if update.Email != nil {
+			channels.Email = *update.Email
+		}
+		if update.Type == models.NotificationTypeWeeklyDigest && (channels.InApp || channels.Push) {
+			return nil, errors.New("o resumo semanal só é enviado por e-mail")
+		}
+		updated[update.Type] = channels
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(updated))
+	for notificationType, channels := range updated {
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:               userID,
+			Type:                 notificationType,
+			NotificationChannels: channels,
+		})
+	}
+	if err := s.notificationRepo.SavePreferences(preferences); err != nil {
+		return nil, errors.New("erro ao salvar preferências de notificação")
+	}
+
+	return s.GetPreferences(userID)
+}
+
+func (s *NotificationService) GetQuietHours(userID uint) (*models.QuietHoursResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	quietHours, err := s.notificationRepo.GetQuietHours(userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("erro ao buscar horário de silêncio")
+		}
+		quietHours = &models.QuietHours{StartMinute: defaultQuietHoursStart, EndMinute: defaultQuietHoursEnd}
+	}
+
+	return &models.QuietHoursResponse{
+		Timezone: user.Timezone,
+		Enabled:  quietHours.Enabled,
+		Start:    formatMinuteOfDay(quietHours.StartMinute),
+		End:      formatMinuteOfDay(quietHours.EndMinute),
+	}, nil
+}
+
+// UpdateQuietHours grava o fuso do usuário e a janela de silêncio; ativá-la
+// exige um fuso, já que os horários são locais
+func (s *NotificationService) UpdateQuietHours(userID uint, req *UpdateQuietHoursRequest) (*models.QuietHoursResponse, error) {
+	current, err := s.GetQuietHours(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone := current.Timezone
+	if req.Timezone != nil {
+		timezone = strings.TrimSpace(*req.Timezone)
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "" || strings.EqualFold(timezone, "local") {
+			return nil, errors.New("fuso horário inválido; use um nome IANA, como America/Sao_Paulo")
+		}
+	}
+
+	quietHours := &models.QuietHours{UserID: userID, Enabled: current.Enabled}
+	if req.Enabled != nil {
+		quietHours.Enabled = *req.Enabled
+	}
+	start, end := current.Start, current.End
+	if req.Start != nil {
+		start = *req.Start
+	}
+	if req.End != nil {
+		end = *req.End
+	}
+	if quietHours.StartMinute, err = parseMinuteOfDay(start); err != nil {
+		return nil, errors.New("horário de início inválido; use HH:MM")
+	}
+	if quietHours.EndMinute, err = parseMinuteOfDay(end); err != nil {
+		return nil, errors.New("horário de fim inválido; use HH:MM")
+	}
+	if quietHours.StartMinute == quietHours.EndMinute {
+		return nil, errors.New("o início e o fim do horário de silêncio devem ser diferentes")
+	}
+	if quietHours.Enabled && timezone == "" {
+		return nil, errors.New("informe o fuso horário para ativar o horário de silêncio")
+	}
+
+	if timezone != current.Timezone {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, errors.New("usuário não encontrado")
+		}
+		user.Timezone = timezone
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, errors.New("erro ao salvar fuso horário")
+		}
+	}
+	if err := s.notificationRepo.SaveQuietHours(quietHours); err != nil {
+		return nil, errors.New("erro ao salvar horário de silêncio")
+	}
+
+	return s.GetQuietHours(userID)
+}
+
+// quietUntil retorna o fim do horário de silêncio quando o usuário está nele
+func (s *NotificationService) quietUntil(userID uint, now time.Time) (time.Time, bool) {
+	quietHours, err := s.notificationRepo.GetQuietHours(userID)
+	if err != nil || !quietHours.Enabled {
+		return time.Time{}, false
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.Timezone == "" {
+		return time.Time{}, false
+	}
+	location, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return quietHoursEnd(now.In(location), quietHours.StartMinute, quietHours.EndMinute)
+}
+
+// DeliverDeferredPushes libera os pushes cujo horário de silêncio terminou:
+// um aviso por usuário, com a notificação ou o lote delas. As que foram lidas
+// no app enquanto isso não são enviadas
+func (s *NotificationService) DeliverDeferredPushes(now time.Time) (int, error) {
+	s.mu.RLock()
+	sender := s.pushSender
+	s.mu.RUnlock()
+	if sender == nil {
+		return 0, nil
+	}
+
+	var userIDs []uint
+	pending := make(map[uint][]models.Notification)
+	for {
+		due, err := s.notificationRepo.GetDueDeferredPushes(now, deferredPushPageSize)
+		if err != nil {
+			return 0, errors.New("erro ao buscar pushes adiados")
+		}
+
+		for _, notification := range due {
+			claimed, err := s.notificationRepo.ClaimDeferredPush(notification.ID)
+			if err != nil {
+				return 0, errors.New("erro ao liberar push adiado")
+			}
+			if !claimed || notification.ReadAt != nil {
+				continue
+			}
+			if _, ok := pending[notification.UserID]; !ok {
+				userIDs = append(userIDs, notification.UserID)
+			}
+			pending[notification.UserID] = append(pending[notification.UserID], notification)
+		}
+
+		if len(due) < deferredPushPageSize {
+			break
+		}
+	}
+
+	delivered := 0
+	for _, userID := range userIDs {
+		notifications := pending[userID]
+		var err error
+		if len(notifications) == 1 {
+			err = sender.Send(&notifications[0])
+		} else {
+			err = sender.SendBatch(userID, notifications)
+		}
+		if err != nil {
+			log.Printf("Falha ao enviar pushes adiados do usuário %d: %v", userID, err)
+			continue
+		}
+		delivered += len(notifications)
+	}
+	return delivered, nil
+}
+
+func (s *NotificationService) StartDeferredPushScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if delivered, err := s.DeliverDeferredPushes(time.Now()); err != nil {
+				log.Println("Falha ao enviar pushes adiados:", err)
+			} else if delivered > 0 {
+				log.Printf("%d pushes adiados pelo horário de silêncio enviados", delivered)
+			}
+		}
+	}()
+}
+
+// quietHoursEnd diz se o horário local está na janela [start, end) e quando
+// ela termina; janelas com start > end atravessam a meia-noite
+func quietHoursEnd(local time.Time, start, end int) (time.Time, bool) {
+	minute := local.Hour()*60 + local.Minute()
+	day := local
+	switch {
+	case start < end:
+		if minute < start || minute >= end {
+			return time.Time{}, false
+		}
+	case minute >= start:
+		day = local.AddDate(0, 0, 1)
+	case minute >= end:
+		return time.Time{}, false
+	}
+
+	year, month, date := day.Date()
+	return time.Date(year, month, date, end/60, end%60, 0, 0, local.Location()), true
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func formatMinuteOfDay(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// groupedActorsText descreve quem participou de um grupo: "ana curtiu" ou
+// "ana e mais 22 pessoas curtiram"
+func groupedActorsText(actorName string, actorCount int, singular, plural string) string {
+	switch others := actorCount - 1; {
+	case others <= 0:
+		return fmt.Sprintf("%s %s", actorName, singular)
+	case others == 1:
+		return fmt.Sprintf("%s e mais 1 pessoa %s", actorName, plural)
+	default:
+		return fmt.Sprintf("%s e mais %d pessoas %s", actorName, others, plural)
+	}
+}
+
+// notificationChannels retorna os canais ativos do usuário para o tipo
+func notificationChannels(notificationRepo repositories.NotificationRepositoryInterface, userID uint, notificationType models.NotificationType) (models.NotificationChannels, error) {
+	preference, err := notificationRepo.GetPreference(userID, notificationType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DefaultNotificationChannels(notificationType), nil
+		}
+		return models.NotificationChannels{}, err
+	}
+	return preference.NotificationChannels, nil
+}
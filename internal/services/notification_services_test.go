@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("fuso indisponível: %v", err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 6, day, hour, minute, 0, 0, loc)
+	}
+
+	const (
+		night   = 22 * 60 // 22:00
+		morning = 7 * 60  // 07:00
+		lunch   = 12 * 60 // 12:00
+		after   = 14 * 60 // 14:00
+	)
+
+	tests := []struct {
+		name       string
+		local      time.Time
+		start, end int
+		wantEnd    time.Time
+		wantQuiet  bool
+	}{
+		{"antes da meia-noite termina no dia seguinte", at(10, 23, 30), night, morning, at(11, 7, 0), true},
+		{"no início da janela", at(10, 22, 0), night, morning, at(11, 7, 0), true},
+		{"depois da meia-noite termina no mesmo dia", at(11, 3, 15), night, morning, at(11, 7, 0), true},
+		{"no fim da janela já saiu", at(11, 7, 0), night, morning, time.Time{}, false},
+		{"durante o dia fora da janela noturna", at(11, 15, 0), night, morning, time.Time{}, false},
+		{"janela no mesmo dia", at(11, 13, 0), lunch, after, at(11, 14, 0), true},
+		{"antes da janela no mesmo dia", at(11, 11, 59), lunch, after, time.Time{}, false},
+		{"depois da janela no mesmo dia", at(11, 14, 0), lunch, after, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet := quietHoursEnd(tt.local, tt.start, tt.end)
+			if quiet != tt.wantQuiet || !end.Equal(tt.wantEnd) {
+				t.Errorf("quietHoursEnd(%s) = %s, %v; want %s, %v", tt.local, end, quiet, tt.wantEnd, tt.wantQuiet)
+			}
+		})
+	}
+}
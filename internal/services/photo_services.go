@@ -0,0 +1,589 @@
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	MaxOrganizePhotos         = 30
+	DefaultPhotoRadiusKm      = 1.0
+	DefaultPhotoWindowMinutes = 60
+	maxExifBytes              = 256 * 1024
+	exifDateLayout            = "2006:01:02 15:04:05"
+	exifDateWithOffsetLayout  = "2006:01:02 15:04:05-07:00"
+	photoMatchGPS             = "gps"
+	photoMatchTime            = "time"
+	photoMatchDate            = "date"
+	photoMatchNone            = "none"
+)
+
+type PhotoServiceInterface interface {
+	OrganizePhotos(itineraryID, userID uint, files []*multipart.FileHeader, req *OrganizePhotosRequest) (*PhotoOrganizationResponse, error)
+	ConfirmPhotos(itineraryID, userID uint, req *ConfirmPhotosRequest) (*models.ItineraryResponse, error)
+}
+
+// OrganizePhotosRequest ajusta a associação automática; sem StartDate as fotos
+// só são associadas a dias por proximidade de um local
+type OrganizePhotosRequest struct {
+	StartDate     time.Time // data do primeiro dia da viagem
+	RadiusKm      float64
+	WindowMinutes int
+}
+
+// PhotoAssignment é a associação proposta de uma foto a um dia/local do roteiro
+type PhotoAssignment struct {
+	Photo        MediaUploadResponse `json:"photo"`
+	TakenAt      *time.Time          `json:"taken_at,omitempty"`
+	Latitude     *float64            `json:"latitude,omitempty"`
+	Longitude    *float64            `json:"longitude,omitempty"`
+	DayID        *uint               `json:"day_id,omitempty"`
+	DayNumber    int                 `json:"day_number,omitempty"`
+	LocationID   *uint               `json:"location_id,omitempty"`
+	LocationName string              `json:"location_name,omitempty"`
+	DistanceKm   *float64            `json:"distance_km,omitempty"`
+	MatchedBy    string              `json:"matched_by"` // gps, time, date ou none
+}
+
+type PhotoOrganizationResponse struct {
+	ItineraryID uint              `json:"itinerary_id"`
+	Assignments []PhotoAssignment `json:"assignments"`
+	Unmatched   int               `json:"unmatched"`
+	Failed      []FailedPhoto     `json:"failed"`
+}
+
+type FailedPhoto struct {
+	FileName string `json:"file_name"`
+	Error    string `json:"error"`
+	Index    int    `json:"index"`
+}
+
+type ConfirmPhotosRequest struct {
+	Photos []ConfirmedPhoto `json:"photos" binding:"required"`
+}
+
+// ConfirmedPhoto é a associação aceita (ou corrigida) pelo usuário; o local,
+// quando informado, define o dia
+type ConfirmedPhoto struct {
+	URL        string `json:"url" binding:"required"`
+	DayID      *uint  `json:"day_id"`
+	LocationID *uint  `json:"location_id"`
+}
+
+type PhotoService struct {
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	mediaService  MediaServiceInterface
+}
+
+func NewPhotoService(itineraryRepo repositories.ItineraryRepositoryInterface, mediaService MediaServiceInterface) PhotoServiceInterface {
+	return &PhotoService{
+		itineraryRepo: itineraryRepo,
+		mediaService:  mediaService,
+	}
+}
+
+// OrganizePhotos envia as fotos e propõe o dia e o local de cada uma a partir
+// da data e das coordenadas EXIF; nada é gravado no roteiro até a confirmação
+func (s *PhotoService) OrganizePhotos(itineraryID, userID uint, files []*multipart.FileHeader, req *OrganizePhotosRequest) (*PhotoOrganizationResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para organizar as fotos deste roteiro")
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("nenhuma foto enviada")
+	}
+	if len(files) > MaxOrganizePhotos {
+		return nil, fmt.Errorf("máximo de %d fotos por vez", MaxOrganizePhotos)
+	}
+
+	if req.RadiusKm <= 0 {
+		req.RadiusKm = DefaultPhotoRadiusKm
+	}
+	if req.WindowMinutes <= 0 {
+		req.WindowMinutes = DefaultPhotoWindowMinutes
+	}
+
+	loc := time.UTC
+	if itinerary.Timezone != "" {
+		if tz, err := time.LoadLocation(itinerary.Timezone); err == nil {
+			loc = tz
+		}
+	}
+
+	response := &PhotoOrganizationResponse{
+		ItineraryID: itinerary.ID,
+		Assignments: []PhotoAssignment{},
+		Failed:      []FailedPhoto{},
+	}
+
+	for i, file := range files {
+		// Metadados lidos antes do upload; fotos sem EXIF são enviadas mesmo assim
+		metadata, _ := readFileMetadata(file)
+
+		upload, err := s.mediaService.UploadFile(file, userID, MediaTypeImage)
+		if err != nil {
+			response.Failed = append(response.Failed, FailedPhoto{
+				FileName: file.Filename,
+				Error:    err.Error(),
+				Index:    i,
+			})
+			continue
+		}
+
+		assignment := PhotoAssignment{
+			Photo:     *upload,
+			MatchedBy: photoMatchNone,
+		}
+		if metadata != nil {
+			assignment.Latitude = metadata.Latitude
+			assignment.Longitude = metadata.Longitude
+			if metadata.TakenAt != nil {
+				takenAt := metadata.localTime(loc)
+				assignment.TakenAt = &takenAt
+			}
+		}
+
+		s.assignPhoto(itinerary, &assignment, req, loc)
+		if assignment.MatchedBy == photoMatchNone {
+			response.Unmatched++
+		}
+		response.Assignments = append(response.Assignments, assignment)
+	}
+
+	return response, nil
+}
+
+// ConfirmPhotos grava as associações confirmadas nas galerias dos dias e locais
+func (s *PhotoService) ConfirmPhotos(itineraryID, userID uint, req *ConfirmPhotosRequest) (*models.ItineraryResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para organizar as fotos deste roteiro")
+	}
+
+	if len(req.Photos) == 0 {
+		return nil, errors.New("nenhuma foto confirmada")
+	}
+
+	days := make(map[uint]bool)
+	locations := make(map[uint]bool)
+	for _, day := range itinerary.Days {
+		days[day.ID] = true
+		for _, location := range day.Locations {
+			locations[location.ID] = true
+		}
+	}
+
+	dayImages := make(map[uint][]string)
+	locationImages := make(map[uint][]string)
+	for _, photo := range req.Photos {
+		url := strings.TrimSpace(photo.URL)
+		if url == "" {
+			return nil, errors.New("URL da foto é obrigatória")
+		}
+
+		switch {
+		case photo.LocationID != nil:
+			if !locations[*photo.LocationID] {
+				return nil, errors.New("local não encontrado neste roteiro")
+			}
+			locationImages[*photo.LocationID] = append(locationImages[*photo.LocationID], url)
+		case photo.DayID != nil:
+			if !days[*photo.DayID] {
+				return nil, errors.New("dia não encontrado neste roteiro")
+			}
+			dayImages[*photo.DayID] = append(dayImages[*photo.DayID], url)
+		default:
+			return nil, errors.New("informe o dia ou o local de cada foto")
+		}
+	}
+
+	if err := s.itineraryRepo.AddPhotos(dayImages, locationImages); err != nil {
+		return nil, errors.New("erro ao salvar fotos do roteiro")
+	}
+
+	itinerary, err = s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	return itinerary.ToResponse(), nil
+}
+
+// assignPhoto escolhe o dia pela data da foto e o local pela distância GPS ou,
+// sem coordenadas, pelo horário previsto de cada local do dia
+func (s *PhotoService) assignPhoto(itinerary *models.Itinerary, assignment *PhotoAssignment, req *OrganizePhotosRequest, loc *time.Location) {
+	var day *models.ItineraryDay
+	if assignment.TakenAt != nil && !req.StartDate.IsZero() {
+		start := time.Date(req.StartDate.Year(), req.StartDate.Month(), req.StartDate.Day(), 0, 0, 0, 0, loc)
+		taken := assignment.TakenAt.In(loc)
+		takenDay := time.Date(taken.Year(), taken.Month(), taken.Day(), 0, 0, 0, 0, loc)
+		dayNumber := int(math.Round(takenDay.Sub(start).Hours()/24)) + 1
+
+		for i := range itinerary.Days {
+			if itinerary.Days[i].DayNumber == dayNumber {
+				day = &itinerary.Days[i]
+				break
+			}
+		}
+	}
+
+	// Sem dia definido pela data, considera os locais de todos os dias
+	candidates := itinerary.Days
+	if day != nil {
+		candidates = []models.ItineraryDay{*day}
+	}
+
+	if assignment.Latitude != nil && assignment.Longitude != nil {
+		bestDistance := math.MaxFloat64
+		var bestDay *models.ItineraryDay
+		var bestLocation *models.ItineraryLocation
+		for i := range candidates {
+			for j := range candidates[i].Locations {
+				location := &candidates[i].Locations[j]
+				if location.Latitude == nil || location.Longitude == nil {
+					continue
+				}
+				distance := haversineKm(*assignment.Latitude, *assignment.Longitude, *location.Latitude, *location.Longitude)
+				if distance <= req.RadiusKm && distance < bestDistance {
+					bestDistance = distance
+					bestDay = &candidates[i]
+					bestLocation = location
+				}
+			}
+		}
+
+		if bestLocation != nil {
+			distance := math.Round(bestDistance*1000) / 1000
+			assignment.DistanceKm = &distance
+			setPhotoAssignment(assignment, bestDay, bestLocation, photoMatchGPS)
+			return
+		}
+	}
+
+	if day == nil {
+		return
+	}
+
+	if assignment.TakenAt != nil {
+		if location := matchLocationByTime(day, assignment.TakenAt.In(loc), req.WindowMinutes, loc); location != nil {
+			setPhotoAssignment(assignment, day, location, photoMatchTime)
+			return
+		}
+	}
+
+	setPhotoAssignment(assignment, day, nil, photoMatchDate)
+}
+
+// matchLocationByTime compara o horário da foto com a janela prevista de cada
+// local do dia, ampliada pela tolerância; vence o início mais próximo
+func matchLocationByTime(day *models.ItineraryDay, taken time.Time, windowMinutes int, loc *time.Location) *models.ItineraryLocation {
+	clock := func(t time.Time) int {
+		t = t.In(loc)
+		return t.Hour()*60 + t.Minute()
+	}
+	takenClock := clock(taken)
+
+	locations := make([]*models.ItineraryLocation, 0, len(day.Locations))
+	for i := range day.Locations {
+		if day.Locations[i].StartTime != nil {
+			locations = append(locations, &day.Locations[i])
+		}
+	}
+	sort.Slice(locations, func(i, j int) bool { return locations[i].Order < locations[j].Order })
+
+	var best *models.ItineraryLocation
+	bestGap := math.MaxInt
+	for _, location := range locations {
+		start := clock(*location.StartTime)
+		end := start
+		if location.EndTime != nil {
+			end = clock(*location.EndTime)
+		}
+		if takenClock < start-windowMinutes || takenClock > end+windowMinutes {
+			continue
+		}
+
+		gap := takenClock - start
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap < bestGap {
+			bestGap = gap
+			best = location
+		}
+	}
+
+	return best
+}
+
+func setPhotoAssignment(assignment *PhotoAssignment, day *models.ItineraryDay, location *models.ItineraryLocation, matchedBy string) {
+	dayID := day.ID
+	assignment.DayID = &dayID
+	assignment.DayNumber = day.DayNumber
+	if location != nil {
+		locationID := location.ID
+		assignment.LocationID = &locationID
+		assignment.LocationName = location.Name
+	}
+	assignment.MatchedBy = matchedBy
+}
+
+// ============================================================================
+// METADADOS EXIF
+// ============================================================================
+
+var errNoExif = errors.New("foto sem metadados EXIF")
+
+// PhotoMetadata reúne os dados EXIF usados para organizar as fotos e
+// devolvidos no upload, antes de o EXIF ser removido
+type PhotoMetadata struct {
+	TakenAt     *time.Time `json:"taken_at,omitempty"`
+	HasOffset   bool       `json:"-"` // sem offset o horário é o relógio local da câmera
+	Latitude    *float64   `json:"latitude,omitempty"`
+	Longitude   *float64   `json:"longitude,omitempty"`
+	Orientation int        `json:"-"` // 1 a 8; mantida no arquivo para a foto não aparecer girada
+}
+
+// localTime interpreta o horário da foto no fuso do roteiro quando a câmera
+// não registrou o offset
+func (m *PhotoMetadata) localTime(loc *time.Location) time.Time {
+	if m.HasOffset {
+		return *m.TakenAt
+	}
+	t := *m.TakenAt
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+func readFileMetadata(file *multipart.FileHeader) (*PhotoMetadata, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	// O segmento EXIF fica no início do JPEG
+	data, err := io.ReadAll(io.LimitReader(src, maxExifBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return readPhotoMetadata(data)
+}
+
+// readPhotoMetadata extrai data e coordenadas do segmento APP1 (EXIF) de um JPEG
+func readPhotoMetadata(data []byte) (*PhotoMetadata, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errNoExif
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, errNoExif
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+		// Início dos dados da imagem: não há mais metadados
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if size < 2 || pos+2+size > len(data) {
+			break
+		}
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return parseExif(segment[6:])
+		}
+		pos += 2 + size
+	}
+
+	return nil, errNoExif
+}
+
+type exifTag struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+func parseExif(tiff []byte) (*PhotoMetadata, error) {
+	if len(tiff) < 8 {
+		return nil, errNoExif
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, errNoExif
+	}
+
+	ifd0, err := readExifIFD(tiff, order, order.Uint32(tiff[4:]))
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &PhotoMetadata{}
+
+	if tag, ok := ifd0[0x0112]; ok {
+		if orientation := int(tag.number(order)); orientation >= 1 && orientation <= 8 {
+			metadata.Orientation = orientation
+		}
+	}
+
+	dateTime, offset := "", ""
+	if tag, ok := ifd0[0x0132]; ok {
+		dateTime = tag.text()
+	}
+	if pointer, ok := ifd0[0x8769]; ok {
+		if exif, err := readExifIFD(tiff, order, pointer.number(order)); err == nil {
+			if tag, ok := exif[0x9003]; ok {
+				dateTime = tag.text()
+			}
+			if tag, ok := exif[0x9011]; ok {
+				offset = tag.text()
+			}
+		}
+	}
+	if dateTime != "" {
+		if offset != "" {
+			if t, err := time.Parse(exifDateWithOffsetLayout, dateTime+offset); err == nil {
+				metadata.TakenAt = &t
+				metadata.HasOffset = true
+			}
+		}
+		if metadata.TakenAt == nil {
+			if t, err := time.Parse(exifDateLayout, dateTime); err == nil {
+				metadata.TakenAt = &t
+			}
+		}
+	}
+
+	if pointer, ok := ifd0[0x8825]; ok {
+		if gps, err := readExifIFD(tiff, order, pointer.number(order)); err == nil {
+			lat, latOK := exifCoordinate(gps[0x0002], gps[0x0001], order, "S")
+			lng, lngOK := exifCoordinate(gps[0x0004], gps[0x0003], order, "W")
+			if latOK && lngOK && lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180 && (lat != 0 || lng != 0) {
+				metadata.Latitude = &lat
+				metadata.Longitude = &lng
+			}
+		}
+	}
+
+	return metadata, nil
+}
+
+func readExifIFD(tiff []byte, order binary.ByteOrder, offset uint32) (map[uint16]exifTag, error) {
+	start := int(offset)
+	if start < 8 || start+2 > len(tiff) {
+		return nil, errNoExif
+	}
+
+	count := int(order.Uint16(tiff[start:]))
+	tags := make(map[uint16]exifTag, count)
+	for i := 0; i < count; i++ {
+		entry := start + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		id := order.Uint16(tiff[entry:])
+		typ := order.Uint16(tiff[entry+2:])
+		n := order.Uint32(tiff[entry+4:])
+		unit := exifTypeSize(typ)
+		if unit == 0 || n == 0 || n > 1<<16 {
+			continue
+		}
+
+		size := unit * int(n)
+		var value []byte
+		if size <= 4 {
+			value = tiff[entry+8 : entry+8+size]
+		} else {
+			pos := int(order.Uint32(tiff[entry+8:]))
+			if pos < 0 || pos+size > len(tiff) {
+				continue
+			}
+			value = tiff[pos : pos+size]
+		}
+		tags[id] = exifTag{typ: typ, count: n, value: value}
+	}
+
+	return tags, nil
+}
+
+func exifTypeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 7: // BYTE, ASCII, UNDEFINED
+		return 1
+	case 3: // SHORT
+		return 2
+	case 4, 9: // LONG, SLONG
+		return 4
+	case 5, 10: // RATIONAL, SRATIONAL
+		return 8
+	}
+	return 0
+}
+
+func (t exifTag) text() string {
+	return strings.TrimRight(string(t.value), "\x00 ")
+}
+
+func (t exifTag) number(order binary.ByteOrder) uint32 {
+	switch t.typ {
+	case 3:
+		return uint32(order.Uint16(t.value))
+	case 4, 9:
+		return order.Uint32(t.value)
+	}
+	return 0
+}
+
+// exifCoordinate converte graus, minutos e segundos em graus decimais
+func exifCoordinate(value, ref exifTag, order binary.ByteOrder, negativeRef string) (float64, bool) {
+	if value.typ != 5 || value.count < 3 {
+		return 0, false
+	}
+
+	var parts [3]float64
+	for i := range parts {
+		num := order.Uint32(value.value[i*8:])
+		den := order.Uint32(value.value[i*8+4:])
+		if den == 0 {
+			return 0, false
+		}
+		parts[i] = float64(num) / float64(den)
+	}
+
+	coordinate := parts[0] + parts[1]/60 + parts[2]/3600
+	if strings.EqualFold(ref.text(), negativeRef) {
+		coordinate = -coordinate
+	}
+	return coordinate, true
+}
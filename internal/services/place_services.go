@@ -0,0 +1,1240 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// ErrPlacesNotConfigured indica que nenhum provedor de lugares foi configurado
+var ErrPlacesNotConfigured = errors.New("busca de lugares não configurada")
+
+// errPlaceDetailsUnsupported indica um provedor sem detalhes por place ID
+var errPlaceDetailsUnsupported = errors.New("provedor de lugares não suporta busca por google_place_id")
+
+const (
+	maxPlaceImportSize  = 2 * 1024 * 1024
+	maxPlaceImportItems = 200
+	// Distância abaixo da qual dois lugares com o mesmo nome são o mesmo
+	placeDuplicateKm  = 0.1
+	maxPlacePhotos    = 3
+	maxPlacePhotoSize = 10 * 1024 * 1024
+	maxSuggestions    = 5
+	// Raio (m) em que o autocomplete prioriza resultados perto do usuário
+	autocompleteBiasRadius = 50000
+)
+
+type PlacesConfig struct {
+	Provider  string // "google" ou "nominatim"
+	APIKey    string
+	APIURL    string
+	CacheDays int // validade do cache de detalhes dos lugares
+	// Nominatim usado no autocomplete e na geocodificação quando o Google
+	// falha (cota esgotada, indisponibilidade); vazio desativa
+	FallbackURL string
+	// Buscas de autocomplete por usuário por minuto
+	AutocompleteRateLimit int
+}
+
+// PlaceAutocompleteQuery é uma busca do editor de roteiros; SessionToken
+// agrupa as buscas e o detalhe escolhido numa única sessão de cobrança do
+// Google
+type PlaceAutocompleteQuery struct {
+	Input        string
+	SessionToken string
+	Latitude     *float64
+	Longitude    *float64
+}
+
+// PlaceSuggestion é um resultado do autocomplete. Sugestões do Google trazem
+// apenas o place_id (coordenadas via /places/details); as do Nominatim já
+// trazem as coordenadas
+type PlaceSuggestion struct {
+	PlaceID     string   `json:"place_id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	Provider    string   `json:"provider"`
+}
+
+// PlaceResult é um lugar encontrado pelo provedor
+type PlaceResult struct {
+	Name            string
+	Address         string
+	Latitude        float64
+	Longitude       float64
+	PlaceID         string
+	Phone           string
+	Website         string
+	Rating          *float64
+	OpeningHours    []string
+	PhotoReferences []string
+}
+
+// PlacesProviderInterface abstrai o serviço externo de lugares
+type PlacesProviderInterface interface {
+	Name() string
+	Geocode(query string) (*PlaceResult, error)
+	Autocomplete(query *PlaceAutocompleteQuery) ([]PlaceSuggestion, error)
+	// Details retorna nil quando o place ID não existe no provedor
+	Details(placeID, sessionToken string) (*PlaceResult, error)
+	Photo(reference string) ([]byte, string, error)
+}
+
+func NewPlacesProvider(config *PlacesConfig) PlacesProviderInterface {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch config.Provider {
+	case "google":
+		if config.APIKey == "" {
+			return &disabledPlacesProvider{}
+		}
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://maps.googleapis.com"
+		}
+		provider := &googlePlacesProvider{
+			apiKey: config.APIKey,
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+		if config.FallbackURL != "" {
+			provider.fallback = &nominatimPlacesProvider{
+				apiURL: strings.TrimRight(config.FallbackURL, "/"),
+				client: client,
+			}
+		}
+		return provider
+	case "nominatim":
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://nominatim.openstreetmap.org"
+		}
+		return &nominatimPlacesProvider{
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+	default:
+		return &disabledPlacesProvider{}
+	}
+}
+
+type disabledPlacesProvider struct{}
+
+func (p *disabledPlacesProvider) Name() string { return "none" }
+
+func (p *disabledPlacesProvider) Geocode(query string) (*PlaceResult, error) {
+	return nil, ErrPlacesNotConfigured
+}
+
+func (p *disabledPlacesProvider) Autocomplete(query *PlaceAutocompleteQuery) ([]PlaceSuggestion, error) {
+	return nil, ErrPlacesNotConfigured
+}
+
+func (p *disabledPlacesProvider) Details(placeID, sessionToken string) (*PlaceResult, error) {
+	return nil, ErrPlacesNotConfigured
+}
+
+func (p *disabledPlacesProvider) Photo(reference string) ([]byte, string, error) {
+	return nil, "", ErrPlacesNotConfigured
+}
+
+// googlePlacesProvider usa as APIs de Geocoding e Places do Google Maps
+// Platform
+type googlePlacesProvider struct {
+	apiKey   string
+	apiURL   string
+	client   *http.Client
+	fallback PlacesProviderInterface
+}
+
+func (p *googlePlacesProvider) Name() string { return "google" }
+
+func (p *googlePlacesProvider) Geocode(query string) (*PlaceResult, error) {
+	result, err := p.geocode(query)
+	if err != nil && p.fallback != nil {
+		return p.fallback.Geocode(query)
+	}
+	return result, err
+}
+
+func (p *googlePlacesProvider) geocode(query string) (*PlaceResult, error) {
+	params := url.Values{}
+	params.Set("address", query)
+	params.Set("key", p.apiKey)
+
+	var result struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Results      []struct {
+			FormattedAddress string `json:"formatted_address"`
+			PlaceID          string `json:"place_id"`
+			Geometry         struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/maps/api/geocode/json?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+	switch result.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("erro ao buscar lugar: %s %s", result.Status, result.ErrorMessage)
+	}
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+
+	first := result.Results[0]
+	return &PlaceResult{
+		Address:   first.FormattedAddress,
+		Latitude:  first.Geometry.Location.Lat,
+		Longitude: first.Geometry.Location.Lng,
+		PlaceID:   first.PlaceID,
+	}, nil
+}
+
+func (p *googlePlacesProvider) Autocomplete(query *PlaceAutocompleteQuery) ([]PlaceSuggestion, error) {
+	params := url.Values{}
+	params.Set("input", query.Input)
+	params.Set("language", "pt-BR")
+	params.Set("key", p.apiKey)
+	if query.SessionToken != "" {
+		params.Set("sessiontoken", query.SessionToken)
+	}
+	if query.Latitude != nil && query.Longitude != nil {
+		params.Set("location", fmt.Sprintf("%f,%f", *query.Latitude, *query.Longitude))
+		params.Set("radius", strconv.Itoa(autocompleteBiasRadius))
+	}
+
+	var result struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Predictions  []struct {
+			Description          string `json:"description"`
+			PlaceID              string `json:"place_id"`
+			StructuredFormatting struct {
+				MainText      string `json:"main_text"`
+				SecondaryText string `json:"secondary_text"`
+			} `json:"structured_formatting"`
+		} `json:"predictions"`
+	}
+	err := getPlacesJSON(p.client, p.apiURL+"/maps/api/place/autocomplete/json?"+params.Encode(), &result)
+	if err == nil && result.Status != "OK" && result.Status != "ZERO_RESULTS" {
+		err = fmt.Errorf("erro ao buscar lugar: %s %s", result.Status, result.ErrorMessage)
+	}
+	if err != nil {
+		if p.fallback != nil {
+			return p.fallback.Autocomplete(query)
+		}
+		return nil, err
+	}
+
+	suggestions := []PlaceSuggestion{}
+	for _, prediction := range result.Predictions {
+		if len(suggestions) >= maxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, PlaceSuggestion{
+			PlaceID:     prediction.PlaceID,
+			Name:        firstNonEmpty(prediction.StructuredFormatting.MainText, prediction.Description),
+			Description: firstNonEmpty(prediction.StructuredFormatting.SecondaryText, prediction.Description),
+			Provider:    p.Name(),
+		})
+	}
+	return suggestions, nil
+}
+
+func (p *googlePlacesProvider) Details(placeID, sessionToken string) (*PlaceResult, error) {
+	params := url.Values{}
+	params.Set("place_id", placeID)
+	if sessionToken != "" {
+		params.Set("sessiontoken", sessionToken)
+	}
+	params.Set("fields", "name,formatted_address,geometry/location,international_phone_number,website,rating,opening_hours/weekday_text,photos")
+	params.Set("language", "pt-BR")
+	params.Set("key", p.apiKey)
+
+	var result struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Result       struct {
+			Name             string   `json:"name"`
+			FormattedAddress string   `json:"formatted_address"`
+			Phone            string   `json:"international_phone_number"`
+			Website          string   `json:"website"`
+			Rating           *float64 `json:"rating"`
+			Geometry         struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+			OpeningHours struct {
+				WeekdayText []string `json:"weekday_text"`
+			} `json:"opening_hours"`
+			Photos []struct {
+				PhotoReference string `json:"photo_reference"`
+			} `json:"photos"`
+		} `json:"result"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/maps/api/place/details/json?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	switch result.Status {
+	case "OK":
+	case "NOT_FOUND", "INVALID_REQUEST", "ZERO_RESULTS":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("erro ao buscar lugar: %s %s", result.Status, result.ErrorMessage)
+	}
+
+	place := &PlaceResult{
+		Name:         result.Result.Name,
+		Address:      result.Result.FormattedAddress,
+		Latitude:     result.Result.Geometry.Location.Lat,
+		Longitude:    result.Result.Geometry.Location.Lng,
+		PlaceID:      placeID,
+		Phone:        result.Result.Phone,
+		Website:      result.Result.Website,
+		Rating:       result.Result.Rating,
+		OpeningHours: result.Result.OpeningHours.WeekdayText,
+	}
+	for _, photo := range result.Result.Photos {
+		place.PhotoReferences = append(place.PhotoReferences, photo.PhotoReference)
+	}
+	return place, nil
+}
+
+func (p *googlePlacesProvider) Photo(reference string) ([]byte, string, error) {
+	params := url.Values{}
+	params.Set("photo_reference", reference)
+	params.Set("maxwidth", "1200")
+	params.Set("key", p.apiKey)
+
+	resp, err := p.client.Get(p.apiURL + "/maps/api/place/photo?" + params.Encode())
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao baixar foto do lugar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("erro ao baixar foto do lugar: provedor respondeu %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPlacePhotoSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao baixar foto do lugar: %w", err)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// nominatimPlacesProvider usa o Nominatim (OpenStreetMap), público ou
+// auto-hospedado
+type nominatimPlacesProvider struct {
+	apiURL string
+	client *http.Client
+}
+
+func (p *nominatimPlacesProvider) Name() string { return "nominatim" }
+
+func (p *nominatimPlacesProvider) Geocode(query string) (*PlaceResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+
+	var results []struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"display_name"`
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/search?"+params.Encode(), &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	latitude, errLat := strconv.ParseFloat(results[0].Lat, 64)
+	longitude, errLon := strconv.ParseFloat(results[0].Lon, 64)
+	if errLat != nil || errLon != nil {
+		return nil, errors.New("erro ao buscar lugar: coordenadas inválidas do provedor")
+	}
+	return &PlaceResult{
+		Name:      results[0].Name,
+		Address:   results[0].DisplayName,
+		Latitude:  latitude,
+		Longitude: longitude,
+	}, nil
+}
+
+func (p *nominatimPlacesProvider) Autocomplete(query *PlaceAutocompleteQuery) ([]PlaceSuggestion, error) {
+	params := url.Values{}
+	params.Set("q", query.Input)
+	params.Set("format", "jsonv2")
+	params.Set("limit", strconv.Itoa(maxSuggestions))
+	params.Set("accept-language", "pt-BR")
+	if query.Latitude != nil && query.Longitude != nil {
+		// Caixa de ~50 km em volta do usuário, sem excluir o restante
+		params.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f", *query.Longitude-0.5, *query.Latitude+0.5, *query.Longitude+0.5, *query.Latitude-0.5))
+	}
+
+	var results []struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"display_name"`
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/search?"+params.Encode(), &results); err != nil {
+		return nil, err
+	}
+
+	suggestions := []PlaceSuggestion{}
+	for _, result := range results {
+		latitude, errLat := strconv.ParseFloat(result.Lat, 64)
+		longitude, errLon := strconv.ParseFloat(result.Lon, 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+		suggestions = append(suggestions, PlaceSuggestion{
+			Name:        firstNonEmpty(result.Name, result.DisplayName),
+			Description: result.DisplayName,
+			Latitude:    &latitude,
+			Longitude:   &longitude,
+			Provider:    p.Name(),
+		})
+	}
+	return suggestions, nil
+}
+
+func (p *nominatimPlacesProvider) Details(placeID, sessionToken string) (*PlaceResult, error) {
+	return nil, errPlaceDetailsUnsupported
+}
+
+func (p *nominatimPlacesProvider) Photo(reference string) ([]byte, string, error) {
+	return nil, "", errPlaceDetailsUnsupported
+}
+
+func getPlacesJSON(client *http.Client, endpoint string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	// O Nominatim exige identificação do cliente
+	req.Header.Set("User-Agent", "guIA-backend")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao acessar provedor de lugares: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("erro ao buscar lugar: provedor respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ============================================================================
+// IMPORTAÇÃO DE LUGARES SALVOS
+// ============================================================================
+
+type PlaceImportStatus string
+
+const (
+	PlaceImportNew       PlaceImportStatus = "new"       // será criado
+	PlaceImportDuplicate PlaceImportStatus = "duplicate" // já está no roteiro ou repetido no arquivo
+	PlaceImportNotFound  PlaceImportStatus = "not_found" // não foi possível geocodificar
+)
+
+// PlaceImportItem é um lugar do arquivo já resolvido; os itens "new" da
+// prévia podem ser enviados de volta para a importação
+type PlaceImportItem struct {
+	Name          string              `json:"name"`
+	Address       string              `json:"address"`
+	Note          string              `json:"note"`
+	URL           string              `json:"url"`
+	Latitude      *float64            `json:"latitude"`
+	Longitude     *float64            `json:"longitude"`
+	GooglePlaceID string              `json:"google_place_id"`
+	LocationType  models.LocationType `json:"location_type"`
+	Status        PlaceImportStatus   `json:"status,omitempty"`
+	Message       string              `json:"message,omitempty"`
+}
+
+// PlaceImportPreview é o resultado da simulação (dry-run) da importação
+type PlaceImportPreview struct {
+	Total      int               `json:"total"`
+	New        int               `json:"new"`
+	Duplicates int               `json:"duplicates"`
+	NotFound   int               `json:"not_found"`
+	Items      []PlaceImportItem `json:"items"`
+}
+
+type ImportPlacesRequest struct {
+	DayID  *uint             `json:"day_id"` // vazio cria um novo dia no fim do roteiro
+	Places []PlaceImportItem `json:"places" binding:"required"`
+}
+
+type ImportPlacesResult struct {
+	Itinerary *models.ItineraryResponse `json:"itinerary"`
+	Created   int                       `json:"created"`
+	Skipped   []PlaceImportItem         `json:"skipped"`
+}
+
+type PlaceEnrichmentFailure struct {
+	LocationID uint   `json:"location_id"`
+	Name       string `json:"name"`
+	Error      string `json:"error"`
+}
+
+type PlaceEnrichmentResult struct {
+	Itinerary *models.ItineraryResponse `json:"itinerary"`
+	Enriched  int                       `json:"enriched"`
+	Failed    []PlaceEnrichmentFailure  `json:"failed"`
+}
+
+type PlaceServiceInterface interface {
+	PreviewPlacesImport(itineraryID, userID uint, fileName string, data []byte) (*PlaceImportPreview, error)
+	ImportPlaces(itineraryID, userID uint, req *ImportPlacesRequest) (*ImportPlacesResult, error)
+	EnrichLocation(itineraryID, locationID, userID uint) (*models.ItineraryLocation, error)
+	EnrichItinerary(itineraryID, userID uint) (*PlaceEnrichmentResult, error)
+	Autocomplete(query *PlaceAutocompleteQuery) ([]PlaceSuggestion, error)
+	GetPlaceDetails(placeID, sessionToken string, userID uint) (*models.PlaceDetails, error)
+}
+
+type PlaceService struct {
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	placeRepo     repositories.PlaceRepositoryInterface
+	mediaService  MediaServiceInterface
+	provider      PlacesProviderInterface
+	cacheTTL      time.Duration
+}
+
+func NewPlaceService(itineraryRepo repositories.ItineraryRepositoryInterface, placeRepo repositories.PlaceRepositoryInterface, mediaService MediaServiceInterface, provider PlacesProviderInterface, cacheTTL time.Duration) PlaceServiceInterface {
+	return &PlaceService{
+		itineraryRepo: itineraryRepo,
+		placeRepo:     placeRepo,
+		mediaService:  mediaService,
+		provider:      provider,
+		cacheTTL:      cacheTTL,
+	}
+}
+
+// PreviewPlacesImport lê a exportação do Google Maps (Takeout em JSON/GeoJSON
+// ou lista em CSV), geocodifica os lugares sem coordenadas e marca os
+// repetidos, sem alterar o roteiro
+func (s *PlaceService) PreviewPlacesImport(itineraryID, userID uint, fileName string, data []byte) (*PlaceImportPreview, error) {
+	itinerary, err := s.getEditableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxPlaceImportSize {
+		return nil, fmt.Errorf("arquivo deve ter no máximo %d MB", maxPlaceImportSize/(1024*1024))
+	}
+
+	var items []PlaceImportItem
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json", ".geojson":
+		items, err = parseSavedPlacesJSON(data)
+	case ".csv":
+		items, err = parseSavedPlacesCSV(data)
+	default:
+		return nil, errors.New("formato de arquivo inválido: envie a exportação do Google Maps em .json ou .csv")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("arquivo não contém lugares")
+	}
+	if len(items) > maxPlaceImportItems {
+		return nil, fmt.Errorf("arquivo deve ter no máximo %d lugares", maxPlaceImportItems)
+	}
+
+	context := strings.Join(nonEmpty(itinerary.City, itinerary.State, itinerary.Country), ", ")
+	for i := range items {
+		item := &items[i]
+		if item.Latitude != nil && item.Longitude != nil {
+			continue
+		}
+
+		if err := s.geocode(item, context); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := existingPlaces(itinerary)
+	preview := &PlaceImportPreview{Total: len(items), Items: items}
+	for i := range items {
+		item := &items[i]
+		switch {
+		case item.Status == PlaceImportNotFound:
+			preview.NotFound++
+		case seen.contains(item):
+			item.Status = PlaceImportDuplicate
+			item.Message = "lugar já está no roteiro ou repetido no arquivo"
+			preview.Duplicates++
+		default:
+			item.Status = PlaceImportNew
+			seen.add(item)
+			preview.New++
+		}
+	}
+
+	return preview, nil
+}
+
+// ImportPlaces cria os locais escolhidos na prévia; coordenadas e duplicados
+// são validados de novo, pois os itens vêm do cliente
+func (s *PlaceService) ImportPlaces(itineraryID, userID uint, req *ImportPlacesRequest) (*ImportPlacesResult, error) {
+	itinerary, err := s.getEditableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Places) == 0 {
+		return nil, errors.New("nenhum lugar para importar")
+	}
+	if len(req.Places) > maxPlaceImportItems {
+		return nil, fmt.Errorf("é possível importar no máximo %d lugares por vez", maxPlaceImportItems)
+	}
+
+	var targetDay *models.ItineraryDay
+	lastDay := 0
+	for i := range itinerary.Days {
+		if req.DayID != nil && itinerary.Days[i].ID == *req.DayID {
+			targetDay = &itinerary.Days[i]
+		}
+		if itinerary.Days[i].DayNumber > lastDay {
+			lastDay = itinerary.Days[i].DayNumber
+		}
+	}
+	if req.DayID != nil && targetDay == nil {
+		return nil, errors.New("dia não encontrado neste roteiro")
+	}
+	if targetDay == nil && lastDay+1 > 365 {
+		return nil, errors.New("duração não pode ser maior que 365 dias")
+	}
+
+	order := 0
+	if targetDay != nil {
+		for _, location := range targetDay.Locations {
+			if location.Order >= order {
+				order = location.Order + 1
+			}
+		}
+	}
+
+	seen := existingPlaces(itinerary)
+	skipped := []PlaceImportItem{}
+	var locations []models.ItineraryLocation
+	for _, item := range req.Places {
+		item.Name = strings.TrimSpace(item.Name)
+		switch {
+		case item.Name == "":
+			item.Status, item.Message = PlaceImportNotFound, "nome é obrigatório"
+		case item.Latitude == nil || item.Longitude == nil || !validCoordinates(*item.Latitude, *item.Longitude):
+			item.Status, item.Message = PlaceImportNotFound, "coordenadas inválidas"
+		case seen.contains(&item):
+			item.Status, item.Message = PlaceImportDuplicate, "lugar já está no roteiro ou repetido na lista"
+		}
+		if item.Status == PlaceImportNotFound || item.Status == PlaceImportDuplicate {
+			skipped = append(skipped, item)
+			continue
+		}
+		seen.add(&item)
+
+		locationType := item.LocationType
+		if !validLocationType(locationType) {
+			locationType = models.LocationTypeOther
+		}
+		locations = append(locations, models.ItineraryLocation{
+			Name:          truncateString(item.Name, 200),
+			Description:   item.Note,
+			LocationType:  locationType,
+			Address:       truncateString(item.Address, 300),
+			Latitude:      item.Latitude,
+			Longitude:     item.Longitude,
+			GooglePlaceID: truncateString(item.GooglePlaceID, 100),
+			Website:       truncateString(item.URL, 200),
+			Order:         order + len(locations),
+		})
+	}
+
+	if len(locations) > 0 {
+		if targetDay != nil {
+			for i := range locations {
+				locations[i].DayID = targetDay.ID
+			}
+			err = s.itineraryRepo.AddLocations(locations)
+		} else {
+			err = s.itineraryRepo.AddDays(itinerary.ID, []models.ItineraryDay{{
+				DayNumber: lastDay + 1,
+				Title:     "Lugares importados",
+				Locations: locations,
+			}})
+		}
+		if err != nil {
+			return nil, errors.New("erro ao importar lugares")
+		}
+	}
+
+	updated, err := s.itineraryRepo.GetByID(itinerary.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiro atualizado")
+	}
+
+	return &ImportPlacesResult{
+		Itinerary: updated.ToResponse(),
+		Created:   len(locations),
+		Skipped:   skipped,
+	}, nil
+}
+
+// ============================================================================
+// AUTOCOMPLETE
+// ============================================================================
+
+// Autocomplete repassa a busca ao provedor, mantendo a chave da API no
+// servidor
+func (s *PlaceService) Autocomplete(query *PlaceAutocompleteQuery) ([]PlaceSuggestion, error) {
+	query.Input = strings.TrimSpace(query.Input)
+	if err := s.validateAutocompleteQuery(query); err != nil {
+		return nil, err
+	}
+	return s.provider.Autocomplete(query)
+}
+
+// GetPlaceDetails retorna os detalhes do lugar escolhido no autocomplete,
+// encerrando a sessão de cobrança do Google
+func (s *PlaceService) GetPlaceDetails(placeID, sessionToken string, userID uint) (*models.PlaceDetails, error) {
+	placeID = strings.TrimSpace(placeID)
+	if placeID == "" || len(placeID) > 255 {
+		return nil, errors.New("place_id inválido")
+	}
+	if err := validateSessionToken(sessionToken); err != nil {
+		return nil, err
+	}
+	return s.placeDetails(placeID, sessionToken, userID)
+}
+
+// ============================================================================
+// ENRIQUECIMENTO DE LOCAIS
+// ============================================================================
+
+// EnrichLocation preenche endereço, coordenadas, contato, horários, nota e
+// fotos do local a partir do google_place_id
+func (s *PlaceService) EnrichLocation(itineraryID, locationID, userID uint) (*models.ItineraryLocation, error) {
+	location, err := s.itineraryRepo.GetLocationByID(locationID)
+	if err != nil || location.Day.ItineraryID != itineraryID {
+		return nil, errors.New("local não encontrado")
+	}
+	if location.Day.Itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para editar este roteiro")
+	}
+	if location.GooglePlaceID == "" {
+		return nil, errors.New("local não possui google_place_id")
+	}
+
+	if err := s.enrich(location, userID); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// EnrichItinerary enriquece todos os locais do roteiro com google_place_id;
+// falhas em um local não impedem os demais
+func (s *PlaceService) EnrichItinerary(itineraryID, userID uint) (*PlaceEnrichmentResult, error) {
+	itinerary, err := s.getEditableItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlaceEnrichmentResult{Failed: []PlaceEnrichmentFailure{}}
+	found := false
+	for i := range itinerary.Days {
+		for j := range itinerary.Days[i].Locations {
+			location := &itinerary.Days[i].Locations[j]
+			if location.GooglePlaceID == "" {
+				continue
+			}
+			found = true
+
+			if err := s.enrich(location, userID); err != nil {
+				// Sem provedor configurado nenhum local pode ser enriquecido
+				if errors.Is(err, ErrPlacesNotConfigured) || errors.Is(err, errPlaceDetailsUnsupported) {
+					return nil, err
+				}
+				result.Failed = append(result.Failed, PlaceEnrichmentFailure{
+					LocationID: location.ID,
+					Name:       location.Name,
+					Error:      err.Error(),
+				})
+				continue
+			}
+			result.Enriched++
+		}
+	}
+	if !found {
+		return nil, errors.New("nenhum local do roteiro possui google_place_id")
+	}
+
+	updated, err := s.itineraryRepo.GetByID(itinerary.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiro atualizado")
+	}
+	result.Itinerary = updated.ToResponse()
+	return result, nil
+}
+
+// enrich aplica os detalhes do lugar ao local, mantendo os dados já
+// informados quando o provedor não os tem
+func (s *PlaceService) enrich(location *models.ItineraryLocation, userID uint) error {
+	details, err := s.placeDetails(location.GooglePlaceID, "", userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	location.EnrichedAt = &now
+	columns := []string{"enriched_at"}
+
+	if details.Address != "" {
+		location.Address = truncateString(details.Address, 300)
+		columns = append(columns, "address")
+	}
+	if details.Latitude != nil && details.Longitude != nil {
+		location.Latitude, location.Longitude = details.Latitude, details.Longitude
+		columns = append(columns, "latitude", "longitude")
+	}
+	if details.Phone != "" {
+		location.Phone = truncateString(details.Phone, 20)
+		columns = append(columns, "phone")
+	}
+	if details.Website != "" {
+		location.Website = truncateString(details.Website, 200)
+		columns = append(columns, "website")
+	}
+	if details.Rating != nil {
+		location.Rating = details.Rating
+		columns = append(columns, "rating")
+	}
+	if len(details.OpeningHours) > 0 {
+		location.OpeningHours = details.OpeningHours
+		columns = append(columns, "opening_hours")
+	}
+	if len(details.Photos) > 0 {
+		seen := make(map[string]bool, len(location.Images))
+		for _, image := range location.Images {
+			seen[image] = true
+		}
+		for _, photo := range details.Photos {
+			if !seen[photo] {
+				location.Images = append(location.Images, photo)
+			}
+		}
+		columns = append(columns, "images")
+	}
+
+	if err := s.itineraryRepo.UpdateLocation(location, columns); err != nil {
+		return errors.New("erro ao atualizar local")
+	}
+	return nil
+}
+
+// placeDetails consulta o cache antes do provedor; se o provedor falhar, um
+// cache vencido ainda é melhor que nada
+func (s *PlaceService) placeDetails(placeID, sessionToken string, userID uint) (*models.PlaceDetails, error) {
+	cached, err := s.placeRepo.GetDetails(placeID)
+	if err != nil {
+		cached = nil
+	}
+	if cached != nil && time.Since(cached.FetchedAt) < s.cacheTTL {
+		return cached, nil
+	}
+
+	result, err := s.provider.Details(placeID, sessionToken)
+	if err != nil || result == nil {
+		if cached != nil {
+			return cached, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New("lugar não encontrado no provedor")
+	}
+
+	details := &models.PlaceDetails{
+		PlaceID:      placeID,
+		Provider:     s.provider.Name(),
+		Name:         result.Name,
+		Address:      result.Address,
+		Phone:        result.Phone,
+		Website:      result.Website,
+		Rating:       result.Rating,
+		OpeningHours: result.OpeningHours,
+		FetchedAt:    time.Now(),
+	}
+	if validCoordinates(result.Latitude, result.Longitude) && (result.Latitude != 0 || result.Longitude != 0) {
+		latitude, longitude := result.Latitude, result.Longitude
+		details.Latitude, details.Longitude = &latitude, &longitude
+	}
+
+	// As fotos já copiadas são reaproveitadas, para não baixá-las de novo a
+	// cada renovação do cache
+	if cached != nil && len(cached.Photos) > 0 {
+		details.Photos = cached.Photos
+	} else {
+		details.Photos = s.storePlacePhotos(result.PhotoReferences, userID)
+	}
+
+	if err := s.placeRepo.SaveDetails(details); err != nil {
+		log.Printf("Falha ao salvar cache do lugar %s: %v", placeID, err)
+	}
+	return details, nil
+}
+
+func (s *PlaceService) storePlacePhotos(references []string, userID uint) []string {
+	var photos []string
+	for _, reference := range references {
+		if len(photos) >= maxPlacePhotos {
+			break
+		}
+
+		data, contentType, err := s.provider.Photo(reference)
+		if err != nil {
+			log.Printf("Falha ao baixar foto do lugar: %v", err)
+			continue
+		}
+
+		extension := ".jpg"
+		switch contentType {
+		case "image/png":
+			extension = ".png"
+		case "image/webp":
+			extension = ".webp"
+		default:
+			contentType = "image/jpeg"
+		}
+
+		_, fileURL, err := s.mediaService.StoreGeneratedFile(data, userID, "places", extension, contentType)
+		if err != nil {
+			log.Printf("Falha ao salvar foto do lugar: %v", err)
+			continue
+		}
+		photos = append(photos, fileURL)
+	}
+	return photos
+}
+
+func (s *PlaceService) getEditableItinerary(itineraryID, userID uint) (*models.Itinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para editar este roteiro")
+	}
+	return itinerary, nil
+}
+
+// geocode resolve as coordenadas pelo nome e endereço, usando o destino do
+// roteiro para desambiguar lugares sem endereço
+func (s *PlaceService) geocode(item *PlaceImportItem, context string) error {
+	query := item.Name
+	if item.Address != "" {
+		query += ", " + item.Address
+	} else if context != "" {
+		query += ", " + context
+	}
+
+	result, err := s.provider.Geocode(query)
+	if errors.Is(err, ErrPlacesNotConfigured) {
+		return errors.New("geocodificação não configurada: o arquivo tem lugares sem coordenadas")
+	}
+	if err != nil || result == nil || !validCoordinates(result.Latitude, result.Longitude) {
+		item.Status = PlaceImportNotFound
+		item.Message = "lugar não encontrado pelo endereço"
+		return nil
+	}
+
+	latitude, longitude := result.Latitude, result.Longitude
+	item.Latitude = &latitude
+	item.Longitude = &longitude
+	if item.Address == "" {
+		item.Address = result.Address
+	}
+	if item.GooglePlaceID == "" {
+		item.GooglePlaceID = result.PlaceID
+	}
+	return nil
+}
+
+// Funções de validação
+func (s *PlaceService) validateAutocompleteQuery(query *PlaceAutocompleteQuery) error {
+	if len([]rune(query.Input)) < 2 {
+		return errors.New("busca deve ter pelo menos 2 caracteres")
+	}
+	if len(query.Input) > 200 {
+		return errors.New("busca deve ter no máximo 200 caracteres")
+	}
+	if (query.Latitude == nil) != (query.Longitude == nil) {
+		return errors.New("informe latitude e longitude juntas")
+	}
+	if query.Latitude != nil && !validCoordinates(*query.Latitude, *query.Longitude) {
+		return errors.New("coordenadas inválidas")
+	}
+	return validateSessionToken(query.SessionToken)
+}
+
+func validateSessionToken(token string) error {
+	if token == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(token); err != nil {
+		return errors.New("session_token deve ser um UUID")
+	}
+	return nil
+}
+
+// placeSet identifica lugares repetidos pelo ID do Google ou pelo nome com
+// coordenadas próximas
+type placeSet struct {
+	placeIDs map[string]bool
+	places   []PlaceImportItem
+}
+
+func existingPlaces(itinerary *models.Itinerary) *placeSet {
+	set := &placeSet{placeIDs: map[string]bool{}}
+	for _, day := range itinerary.Days {
+		for _, location := range day.Locations {
+			set.add(&PlaceImportItem{
+				Name:          location.Name,
+				Latitude:      location.Latitude,
+				Longitude:     location.Longitude,
+				GooglePlaceID: location.GooglePlaceID,
+			})
+		}
+	}
+	return set
+}
+
+func (s *placeSet) add(item *PlaceImportItem) {
+	if item.GooglePlaceID != "" {
+		s.placeIDs[item.GooglePlaceID] = true
+	}
+	s.places = append(s.places, *item)
+}
+
+func (s *placeSet) contains(item *PlaceImportItem) bool {
+	if item.GooglePlaceID != "" && s.placeIDs[item.GooglePlaceID] {
+		return true
+	}
+	if item.Latitude == nil || item.Longitude == nil {
+		return false
+	}
+
+	name := strings.ToLower(strings.TrimSpace(item.Name))
+	for _, place := range s.places {
+		if place.Latitude == nil || place.Longitude == nil || strings.ToLower(strings.TrimSpace(place.Name)) != name {
+			continue
+		}
+		if haversineKm(*item.Latitude, *item.Longitude, *place.Latitude, *place.Longitude) <= placeDuplicateKm {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSavedPlacesJSON lê o "Saved Places.json" do Takeout (GeoJSON), nos
+// formatos antigo ("Title", "Location") e novo ("location", "google_maps_url")
+func parseSavedPlacesJSON(data []byte) ([]PlaceImportItem, error) {
+	var file struct {
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties struct {
+				Title    string `json:"Title"`
+				URL      string `json:"Google Maps URL"`
+				Comment  string `json:"Comment"`
+				Location struct {
+					Address      string `json:"Address"`
+					BusinessName string `json:"Business Name"`
+				} `json:"Location"`
+
+				GoogleMapsURL string `json:"google_maps_url"`
+				LowerComment  string `json:"comment"`
+				LowerLocation struct {
+					Name    string `json:"name"`
+					Address string `json:"address"`
+				} `json:"location"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.New("arquivo JSON inválido: envie o \"Saved Places.json\" do Google Takeout")
+	}
+
+	items := make([]PlaceImportItem, 0, len(file.Features))
+	for _, feature := range file.Features {
+		props := feature.Properties
+		item := PlaceImportItem{
+			Name:    firstNonEmpty(props.Title, props.Location.BusinessName, props.LowerLocation.Name),
+			Address: firstNonEmpty(props.Location.Address, props.LowerLocation.Address),
+			Note:    firstNonEmpty(props.Comment, props.LowerComment),
+			URL:     firstNonEmpty(props.URL, props.GoogleMapsURL),
+		}
+		if item.Name == "" {
+			item.Name = item.Address
+		}
+		if item.Name == "" {
+			continue
+		}
+
+		// GeoJSON usa [longitude, latitude]; exportações recentes trazem [0, 0]
+		// para lugares sem coordenadas
+		if coords := feature.Geometry.Coordinates; len(coords) >= 2 && (coords[0] != 0 || coords[1] != 0) && validCoordinates(coords[1], coords[0]) {
+			latitude, longitude := coords[1], coords[0]
+			item.Latitude, item.Longitude = &latitude, &longitude
+		} else {
+			applyMapsURL(&item)
+		}
+		if item.GooglePlaceID == "" {
+			item.GooglePlaceID = placeIDFromURL(item.URL)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseSavedPlacesCSV lê as listas salvas do Google Maps exportadas em CSV
+// (colunas Title, Note, URL e Comment)
+func parseSavedPlacesCSV(data []byte) ([]PlaceImportItem, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, errors.New("arquivo CSV inválido")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("arquivo CSV inválido: coluna Title não encontrada")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var items []PlaceImportItem
+	for _, record := range records[1:] {
+		item := PlaceImportItem{
+			Name: field(record, "title"),
+			Note: strings.TrimSpace(field(record, "note") + "\n" + field(record, "comment")),
+			URL:  field(record, "url"),
+		}
+		if item.Name == "" {
+			continue
+		}
+		applyMapsURL(&item)
+		item.GooglePlaceID = placeIDFromURL(item.URL)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+var (
+	mapsAtCoordinates    = regexp.MustCompile(`@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`)
+	mapsDataCoordinates  = regexp.MustCompile(`!3d(-?\d+(?:\.\d+)?)!4d(-?\d+(?:\.\d+)?)`)
+	mapsQueryCoordinates = regexp.MustCompile(`[?&](?:q|query|ll)=(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`)
+	mapsPlaceID          = regexp.MustCompile(`(?:query_place_id=|place_id:)([A-Za-z0-9_-]+)`)
+)
+
+// applyMapsURL extrai as coordenadas do link do Google Maps, quando presentes;
+// o marcador do lugar (!3d/!4d) tem prioridade sobre o centro do mapa (@)
+func applyMapsURL(item *PlaceImportItem) {
+	for _, pattern := range []*regexp.Regexp{mapsDataCoordinates, mapsQueryCoordinates, mapsAtCoordinates} {
+		match := pattern.FindStringSubmatch(item.URL)
+		if match == nil {
+			continue
+		}
+		latitude, errLat := strconv.ParseFloat(match[1], 64)
+		longitude, errLon := strconv.ParseFloat(match[2], 64)
+		if errLat == nil && errLon == nil && validCoordinates(latitude, longitude) && !(latitude == 0 && longitude == 0) {
+			item.Latitude, item.Longitude = &latitude, &longitude
+			return
+		}
+	}
+}
+
+func placeIDFromURL(mapsURL string) string {
+	if match := mapsPlaceID.FindStringSubmatch(mapsURL); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func validLocationType(locationType models.LocationType) bool {
+	switch locationType {
+	case models.LocationTypeHotel, models.LocationTypeRestaurant, models.LocationTypeAttraction,
+		models.LocationTypeTransport, models.LocationTypeShopping, models.LocationTypeOther:
+		return true
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
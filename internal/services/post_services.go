@@ -1,50 +1,88 @@
 package services
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"strings"
+	"time"
 
+	"github.com/Ulpio/guIA-backend/internal/events"
 	"github.com/Ulpio/guIA-backend/internal/models"
 	"github.com/Ulpio/guIA-backend/internal/repositories"
 )
 
 type PostServiceInterface interface {
 	CreatePost(userID uint, req *CreatePostRequest) (*models.PostResponse, error)
-	GetFeed(userID uint, limit, offset int) ([]models.PostResponse, error)
+	GetFeed(userID uint, cursor string, limit, offset int) ([]models.PostResponse, string, error)
 	GetPostByID(postID, userID uint) (*models.PostResponse, error)
 	UpdatePost(postID, userID uint, req *UpdatePostRequest) (*models.PostResponse, error)
 	DeletePost(postID, userID uint) error
 	LikePost(userID, postID uint) error
 	UnlikePost(userID, postID uint) error
-	GetPostsByAuthor(authorID, currentUserID uint, limit, offset int) ([]models.PostResponse, error)
-	SearchPosts(query string, currentUserID uint, limit, offset int) ([]models.PostResponse, error)
-	GetTrendingPosts(currentUserID uint, limit, offset int) ([]models.PostResponse, error)
+	GetPostLikers(postID, currentUserID uint, limit, offset int) ([]models.UserResponse, error)
+	GetPostsByAuthor(authorID, currentUserID uint, cursor string, limit, offset int) ([]models.PostResponse, string, error)
+	SearchPosts(query string, geo *GeoFilter, currentUserID uint, limit, offset int) ([]models.PostResponse, error)
+	GetTrendingPosts(currentUserID uint, cursor string, limit, offset int) ([]models.PostResponse, string, error)
+	GetLikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error)
+	GetPostsByIDs(postIDs []uint, currentUserID uint) ([]models.PostResponse, error)
+	GetPostsByItinerary(itineraryID, currentUserID uint, limit, offset int) ([]models.PostResponse, error)
+	GetNearbyPosts(latitude, longitude, radiusKm float64, currentUserID uint, limit, offset int) ([]models.PostResponse, error)
+	SharePost(userID, postID uint) error
+	GetPostInsights(postID, userID uint, from, to time.Time) (*models.PostInsights, error)
 }
 
 type CreatePostRequest struct {
-	Content   string          `json:"content" binding:"required"`
-	PostType  models.PostType `json:"post_type"`
-	MediaURLs []string        `json:"media_urls,omitempty"`
-	Location  string          `json:"location,omitempty"`
-	Latitude  *float64        `json:"latitude,omitempty"`
-	Longitude *float64        `json:"longitude,omitempty"`
+	Content     string                `json:"content" binding:"required"`
+	PostType    models.PostType       `json:"post_type"`
+	Visibility  models.PostVisibility `json:"visibility,omitempty"`
+	ItineraryID *uint                 `json:"itinerary_id,omitempty"`
+	// Quem pode comentar: everyone (padrão), followers, mentioned ou off
+	CommentPolicy models.CommentPolicy `json:"comment_policy,omitempty"`
+	MediaURLs     []string             `json:"media_urls,omitempty"`
+	Location      string               `json:"location,omitempty"`
+	Latitude      *float64             `json:"latitude,omitempty"`
+	Longitude     *float64             `json:"longitude,omitempty"`
 }
 
 type UpdatePostRequest struct {
-	Content   *string  `json:"content,omitempty"`
-	Location  *string  `json:"location,omitempty"`
-	Latitude  *float64 `json:"latitude,omitempty"`
-	Longitude *float64 `json:"longitude,omitempty"`
+	Content       *string                `json:"content,omitempty"`
+	Visibility    *models.PostVisibility `json:"visibility,omitempty"`
+	CommentPolicy *models.CommentPolicy  `json:"comment_policy,omitempty"`
+	ItineraryID   *uint                  `json:"itinerary_id,omitempty"` // 0 remove o vínculo
+	Location      *string                `json:"location,omitempty"`
+	Latitude      *float64               `json:"latitude,omitempty"`
+	Longitude     *float64               `json:"longitude,omitempty"`
 }
 
 type PostService struct {
-	postRepo repositories.PostRepositoryInterface
-	userRepo repositories.UserRepositoryInterface
+	postRepo      repositories.PostRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	mediaRepo     repositories.MediaRepositoryInterface
+	feedSettings  FeedSettingsServiceInterface
+	profanity     ProfanityServiceInterface
+	spam          SpamServiceInterface
+	eventBus      events.BusInterface
 }
 
-func NewPostService(postRepo repositories.PostRepositoryInterface) PostServiceInterface {
+const (
+	suggestedPostsEvery  = 4                  // um post sugerido após cada quatro do feed
+	suggestedPostsWindow = 7 * 24 * time.Hour // idade máxima das sugestões na primeira página
+)
+
+func NewPostService(postRepo repositories.PostRepositoryInterface, userRepo repositories.UserRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface, mediaRepo repositories.MediaRepositoryInterface, feedSettings FeedSettingsServiceInterface, profanity ProfanityServiceInterface, spam SpamServiceInterface, eventBus events.BusInterface) PostServiceInterface {
 	return &PostService{
-		postRepo: postRepo,
+		postRepo:      postRepo,
+		userRepo:      userRepo,
+		itineraryRepo: itineraryRepo,
+		mediaRepo:     mediaRepo,
+		feedSettings:  feedSettings,
+		profanity:     profanity,
+		spam:          spam,
+		eventBus:      eventBus,
 	}
 }
 
@@ -54,28 +92,57 @@ func (s *PostService) CreatePost(userID uint, req *CreatePostRequest) (*models.P
 		return nil, err
 	}
 
-	// Determinar tipo do post baseado na mídia
-	postType := models.PostTypeText
-	if len(req.MediaURLs) > 0 {
-		// Por simplicidade, vamos assumir que se há mídia, é imagem
-		// Em um sistema real, você verificaria o tipo do arquivo
-		postType = models.PostTypeImage
+	if req.ItineraryID != nil {
+		if err := s.validateItineraryLink(*req.ItineraryID); err != nil {
+			return nil, err
+		}
 	}
 
-	if req.PostType != "" {
-		postType = req.PostType
+	// Determinar tipo do post a partir das mídias registradas do autor
+	mediaItems, err := s.resolveMediaItems(userID, req.MediaURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	postType := postTypeForMedia(mediaItems)
+	if req.PostType != "" && req.PostType != postType {
+		return nil, errors.New("tipo do post não corresponde às mídias enviadas")
+	}
+
+	visibility := models.PostVisibilityPublic
+	if req.Visibility != "" {
+		visibility = req.Visibility
+	}
+
+	commentPolicy := models.CommentPolicyEveryone
+	if req.CommentPolicy != "" {
+		commentPolicy = req.CommentPolicy
+	}
+
+	filtered, err := s.profanity.Filter(strings.TrimSpace(req.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	spam, err := s.spam.CheckPost(userID, filtered.Text)
+	if err != nil {
+		return nil, err
 	}
 
 	// Criar post
 	post := &models.Post{
-		AuthorID:  userID,
-		Content:   strings.TrimSpace(req.Content),
-		PostType:  postType,
-		MediaURLs: req.MediaURLs,
-		Location:  req.Location,
-		Latitude:  req.Latitude,
-		Longitude: req.Longitude,
-		IsActive:  true,
+		AuthorID:      userID,
+		Content:       filtered.Text,
+		PostType:      postType,
+		MediaURLs:     req.MediaURLs,
+		MediaItems:    mediaItems,
+		Location:      req.Location,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		IsActive:      true,
+		Visibility:    visibility,
+		CommentPolicy: commentPolicy,
+		ItineraryID:   req.ItineraryID,
 	}
 
 	// Para compatibilidade, definir MediaURL como primeira URL se existir
@@ -86,9 +153,20 @@ func (s *PostService) CreatePost(userID uint, req *CreatePostRequest) (*models.P
 	if err := s.postRepo.Create(post); err != nil {
 		return nil, errors.New("erro ao criar post")
 	}
+	s.profanity.FlagContent(models.ReportTargetPost, post.ID, userID, filtered)
+	s.spam.FlagPost(post.ID, userID, spam)
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.PostCreated,
+		ActorID:  userID,
+		EntityID: post.ID,
+		Data: map[string]string{
+			"text": post.Content + " " + post.Location,
+		},
+	})
 
 	// Buscar post criado com dados completos
-	createdPost, err := s.postRepo.GetByID(post.ID)
+	createdPost, err := s.postRepo.GetByID(post.ID, userID)
 	if err != nil {
 		return nil, errors.New("erro ao buscar post criado")
 	}
@@ -96,36 +174,116 @@ func (s *PostService) CreatePost(userID uint, req *CreatePostRequest) (*models.P
 	return createdPost.ToResponse(userID), nil
 }
 
-func (s *PostService) GetFeed(userID uint, limit, offset int) ([]models.PostResponse, error) {
+func (s *PostService) GetFeed(userID uint, cursor string, limit, offset int) ([]models.PostResponse, string, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 20
 	}
 
-	posts, err := s.postRepo.GetFeed(userID, limit, offset)
+	after, err := decodePostCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	settings, err := s.feedSettings.GetFeedSettings(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	options := repositories.FeedOptions{HideReposts: settings.HideReposts}
+
+	posts, err := s.postRepo.GetFeed(userID, after, limit, offset, options)
 	if err != nil {
-		return nil, errors.New("erro ao buscar feed")
+		return nil, "", errors.New("erro ao buscar feed")
+	}
+
+	// O cursor segue a ordem cronológica; a mistura só reordena a página
+	nextCursor := nextPostCursor(posts, limit, false)
+	shown := applyContentMix(posts, settings.ContentMix)
+
+	var suggested []models.Post
+	if settings.IncludeSuggested {
+		suggested = s.suggestedForPage(userID, after, offset, posts, limit, options)
 	}
 
 	var responses []models.PostResponse
-	for _, post := range posts {
+	next := 0
+	for i, post := range shown {
 		responses = append(responses, *post.ToResponse(userID))
+		if (i+1)%suggestedPostsEvery == 0 && next < len(suggested) {
+			response := suggested[next].ToResponse(userID)
+			response.Suggested = true
+			responses = append(responses, *response)
+			next++
+		}
 	}
 
-	return responses, nil
+	s.recordEvents(append(shown, suggested[:next]...), userID, models.PostEventImpression)
+
+	return responses, nextCursor, nil
+}
+
+// suggestedForPage busca sugestões publicadas no mesmo intervalo de tempo da
+// página, de modo que cada página do feed traga sugestões diferentes
+func (s *PostService) suggestedForPage(userID uint, after *repositories.PostCursor, offset int, posts []models.Post, limit int, options repositories.FeedOptions) []models.Post {
+	to := time.Now()
+	switch {
+	case after != nil:
+		to = after.CreatedAt
+	case offset > 0 && len(posts) > 0:
+		to = posts[0].CreatedAt
+	}
+
+	from := to.Add(-suggestedPostsWindow)
+	if len(posts) == limit {
+		from = posts[len(posts)-1].CreatedAt
+	}
+
+	count := limit / suggestedPostsEvery
+	if count == 0 {
+		count = 1
+	}
+
+	suggested, err := s.postRepo.GetSuggestedPosts(userID, from, to, count, options)
+	if err != nil {
+		log.Printf("Falha ao buscar posts sugeridos para o usuário %d: %v", userID, err)
+		return nil
+	}
+	return suggested
+}
+
+// applyContentMix prioriza, mantendo a ordem cronológica dentro de cada grupo,
+// os posts com ou sem roteiro vinculado conforme a preferência do usuário
+func applyContentMix(posts []models.Post, mix models.FeedContentMix) []models.Post {
+	if mix != models.FeedMixMoreItineraries && mix != models.FeedMixMorePosts {
+		return posts
+	}
+
+	preferItineraries := mix == models.FeedMixMoreItineraries
+	mixed := make([]models.Post, 0, len(posts))
+	var rest []models.Post
+	for _, post := range posts {
+		if (post.ItineraryID != nil) == preferItineraries {
+			mixed = append(mixed, post)
+		} else {
+			rest = append(rest, post)
+		}
+	}
+	return append(mixed, rest...)
 }
 
 func (s *PostService) GetPostByID(postID, userID uint) (*models.PostResponse, error) {
-	post, err := s.postRepo.GetByID(postID)
+	post, err := s.postRepo.GetByID(postID, userID)
 	if err != nil {
 		return nil, errors.New("post não encontrado")
 	}
 
+	s.recordEvents([]models.Post{*post}, userID, models.PostEventView)
+
 	return post.ToResponse(userID), nil
 }
 
 func (s *PostService) UpdatePost(postID, userID uint, req *UpdatePostRequest) (*models.PostResponse, error) {
 	// Buscar post
-	post, err := s.postRepo.GetByID(postID)
+	post, err := s.postRepo.GetByID(postID, userID)
 	if err != nil {
 		return nil, errors.New("post não encontrado")
 	}
@@ -136,12 +294,19 @@ func (s *PostService) UpdatePost(postID, userID uint, req *UpdatePostRequest) (*
 	}
 
 	// Validar e atualizar campos
+	var filtered *ProfanityResult
 	if req.Content != nil {
 		content := strings.TrimSpace(*req.Content)
 		if err := s.validateContent(content); err != nil {
 			return nil, err
 		}
-		post.Content = content
+		if filtered, err = s.profanity.Filter(content); err != nil {
+			return nil, err
+		}
+		if content = filtered.Text; content != post.Content {
+			post.Content = content
+			post.Language = "" // detectado novamente na próxima tradução
+		}
 	}
 
 	if req.Location != nil {
@@ -156,12 +321,40 @@ func (s *PostService) UpdatePost(postID, userID uint, req *UpdatePostRequest) (*
 		post.Longitude = req.Longitude
 	}
 
+	if req.Visibility != nil {
+		if err := s.validateVisibility(*req.Visibility); err != nil {
+			return nil, err
+		}
+		post.Visibility = *req.Visibility
+	}
+
+	if req.CommentPolicy != nil {
+		if err := s.validateCommentPolicy(*req.CommentPolicy); err != nil {
+			return nil, err
+		}
+		post.CommentPolicy = *req.CommentPolicy
+	}
+
+	if req.ItineraryID != nil {
+		if *req.ItineraryID == 0 {
+			post.ItineraryID = nil
+		} else {
+			if err := s.validateItineraryLink(*req.ItineraryID); err != nil {
+				return nil, err
+			}
+			post.ItineraryID = req.ItineraryID
+		}
+		// Evitar que a associação carregada sobrescreva a nova chave ao salvar
+		post.Itinerary = nil
+	}
+
 	if err := s.postRepo.Update(post); err != nil {
 		return nil, errors.New("erro ao atualizar post")
 	}
+	s.profanity.FlagContent(models.ReportTargetPost, post.ID, userID, filtered)
 
 	// Buscar post atualizado
-	updatedPost, err := s.postRepo.GetByID(postID)
+	updatedPost, err := s.postRepo.GetByID(postID, userID)
 	if err != nil {
 		return nil, errors.New("erro ao buscar post atualizado")
 	}
@@ -171,7 +364,7 @@ func (s *PostService) UpdatePost(postID, userID uint, req *UpdatePostRequest) (*
 
 func (s *PostService) DeletePost(postID, userID uint) error {
 	// Buscar post
-	post, err := s.postRepo.GetByID(postID)
+	post, err := s.postRepo.GetByID(postID, userID)
 	if err != nil {
 		return errors.New("post não encontrado")
 	}
@@ -186,7 +379,7 @@ func (s *PostService) DeletePost(postID, userID uint) error {
 
 func (s *PostService) LikePost(userID, postID uint) error {
 	// Verificar se o post existe
-	_, err := s.postRepo.GetByID(postID)
+	post, err := s.postRepo.GetByID(postID, userID)
 	if err != nil {
 		return errors.New("post não encontrado")
 	}
@@ -201,12 +394,24 @@ func (s *PostService) LikePost(userID, postID uint) error {
 		return errors.New("você já curtiu este post")
 	}
 
-	return s.postRepo.LikePost(userID, postID)
+	if err := s.postRepo.LikePost(userID, postID); err != nil {
+		return err
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.PostLiked,
+		ActorID:  userID,
+		EntityID: postID,
+		Data: map[string]string{
+			"author_id": fmt.Sprint(post.AuthorID),
+		},
+	})
+	return nil
 }
 
 func (s *PostService) UnlikePost(userID, postID uint) error {
 	// Verificar se o post existe
-	_, err := s.postRepo.GetByID(postID)
+	_, err := s.postRepo.GetByID(postID, userID)
 	if err != nil {
 		return errors.New("post não encontrado")
 	}
@@ -224,14 +429,138 @@ func (s *PostService) UnlikePost(userID, postID uint) error {
 	return s.postRepo.UnlikePost(userID, postID)
 }
 
-func (s *PostService) GetPostsByAuthor(authorID, currentUserID uint, limit, offset int) ([]models.PostResponse, error) {
+func (s *PostService) SharePost(userID, postID uint) error {
+	// Verificar se o post existe
+	if _, err := s.postRepo.GetByID(postID, userID); err != nil {
+		return errors.New("post não encontrado")
+	}
+
+	if err := s.postRepo.SharePost(userID, postID); err != nil {
+		return errors.New("erro ao compartilhar post")
+	}
+
+	return nil
+}
+
+// GetPostInsights retorna as métricas diárias do post para o autor; o período
+// padrão são os últimos 30 dias e "to" é inclusivo
+func (s *PostService) GetPostInsights(postID, userID uint, from, to time.Time) (*models.PostInsights, error) {
+	post, err := s.postRepo.GetByID(postID, userID)
+	if err != nil {
+		return nil, errors.New("post não encontrado")
+	}
+
+	if post.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para ver as estatísticas deste post")
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = truncateDay(to).AddDate(0, 0, 1)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	from = truncateDay(from)
+
+	if !from.Before(to) {
+		return nil, errors.New("data inicial deve ser anterior à data final")
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return nil, errors.New("período deve ter no máximo 366 dias")
+	}
+
+	points, err := s.postRepo.GetInsightsSeries(postID, from, to)
+	if err != nil {
+		return nil, errors.New("erro ao buscar estatísticas do post")
+	}
+
+	reach, err := s.postRepo.GetReach(postID, from, to)
+	if err != nil {
+		return nil, errors.New("erro ao buscar estatísticas do post")
+	}
+
+	insights := &models.PostInsights{
+		PostID: postID,
+		From:   from,
+		To:     to.AddDate(0, 0, -1),
+		Reach:  reach,
+	}
+
+	// Preenche os dias sem atividade para a série ficar contínua
+	byDate := make(map[string]models.PostInsightsPoint, len(points))
+	for _, point := range points {
+		byDate[point.Date] = point
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		point, ok := byDate[date]
+		if !ok {
+			point = models.PostInsightsPoint{Date: date}
+		}
+
+		insights.Impressions += point.Impressions
+		insights.Views += point.Views
+		insights.Likes += point.Likes
+		insights.Comments += point.Comments
+		insights.Shares += point.Shares
+		insights.Series = append(insights.Series, point)
+	}
+
+	return insights, nil
+}
+
+func (s *PostService) GetPostLikers(postID, currentUserID uint, limit, offset int) ([]models.UserResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	// Respeita a visibilidade do post para quem consulta
+	if _, err := s.postRepo.GetByID(postID, currentUserID); err != nil {
+		return nil, errors.New("post não encontrado")
+	}
+
+	users, err := s.postRepo.GetLikers(postID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar curtidas")
+	}
+
+	userIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	following, followers, err := s.userRepo.GetFollowFlags(currentUserID, userIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar curtidas")
+	}
+
+	var responses []models.UserResponse
+	for _, user := range users {
+		response := user.ToResponse()
+		isFollowing := following[user.ID]
+		followsYou := followers[user.ID]
+		response.IsFollowing = &isFollowing
+		response.FollowsYou = &followsYou
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+func (s *PostService) GetPostsByAuthor(authorID, currentUserID uint, cursor string, limit, offset int) ([]models.PostResponse, string, error) {
 	if limit <= 0 || limit > 50 {
 		limit = 20
 	}
 
-	posts, err := s.postRepo.GetByAuthor(authorID, limit, offset)
+	after, err := decodePostCursor(cursor)
 	if err != nil {
-		return nil, errors.New("erro ao buscar posts do usuário")
+		return nil, "", err
+	}
+
+	posts, err := s.postRepo.GetByAuthor(authorID, currentUserID, after, limit, offset)
+	if err != nil {
+		return nil, "", errors.New("erro ao buscar posts do usuário")
 	}
 
 	var responses []models.PostResponse
@@ -239,39 +568,111 @@ func (s *PostService) GetPostsByAuthor(authorID, currentUserID uint, limit, offs
 		responses = append(responses, *post.ToResponse(currentUserID))
 	}
 
-	return responses, nil
+	return responses, nextPostCursor(posts, limit, false), nil
 }
 
-func (s *PostService) SearchPosts(query string, currentUserID uint, limit, offset int) ([]models.PostResponse, error) {
-	if strings.TrimSpace(query) == "" {
+// SearchPosts busca pelo texto e, com geo, só entre os posts publicados na
+// área; no raio, cada post traz a distância até o centro
+func (s *PostService) SearchPosts(query string, geo *GeoFilter, currentUserID uint, limit, offset int) ([]models.PostResponse, error) {
+	if strings.TrimSpace(query) == "" && geo == nil {
 		return []models.PostResponse{}, nil
 	}
 
+	area, err := geo.area()
+	if err != nil {
+		return nil, err
+	}
+
 	if limit <= 0 || limit > 50 {
 		limit = 20
 	}
 
-	posts, err := s.postRepo.SearchPosts(query, limit, offset)
+	posts, err := s.postRepo.SearchPosts(query, area, currentUserID, limit, offset)
 	if err != nil {
 		return nil, errors.New("erro ao buscar posts")
 	}
 
+	var responses []models.PostResponse
+	for _, post := range posts {
+		response := post.ToResponse(currentUserID)
+		if area != nil && area.Box == nil && post.Latitude != nil && post.Longitude != nil {
+			distance := haversineKm(area.Latitude, area.Longitude, *post.Latitude, *post.Longitude)
+			response.DistanceKm = &distance
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+func (s *PostService) GetTrendingPosts(currentUserID uint, cursor string, limit, offset int) ([]models.PostResponse, string, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	after, err := decodePostCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	posts, err := s.postRepo.GetTrendingPosts(after, limit, offset)
+	if err != nil {
+		return nil, "", errors.New("erro ao buscar posts em alta")
+	}
+
 	var responses []models.PostResponse
 	for _, post := range posts {
 		responses = append(responses, *post.ToResponse(currentUserID))
 	}
 
+	return responses, nextPostCursor(posts, limit, true), nil
+}
+
+// GetLikedPostIDs indica quais dos posts o usuário curtiu, para personalizar
+// respostas montadas sem o usuário (ex.: cache da aba Explorar)
+func (s *PostService) GetLikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked, err := s.postRepo.GetLikedPostIDs(userID, postIDs)
+	if err != nil {
+		return nil, errors.New("erro ao verificar curtidas")
+	}
+	return liked, nil
+}
+
+// GetPostsByIDs retorna os posts na ordem dos IDs, omitindo os removidos e
+// os que o usuário não pode ver
+func (s *PostService) GetPostsByIDs(postIDs []uint, currentUserID uint) ([]models.PostResponse, error) {
+	posts, err := s.postRepo.GetByIDs(postIDs, currentUserID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar posts")
+	}
+
+	byID := make(map[uint]*models.Post, len(posts))
+	for i := range posts {
+		byID[posts[i].ID] = &posts[i]
+	}
+
+	responses := make([]models.PostResponse, 0, len(posts))
+	for _, id := range postIDs {
+		if post, ok := byID[id]; ok {
+			responses = append(responses, *post.ToResponse(currentUserID))
+		}
+	}
 	return responses, nil
 }
 
-func (s *PostService) GetTrendingPosts(currentUserID uint, limit, offset int) ([]models.PostResponse, error) {
+func (s *PostService) GetPostsByItinerary(itineraryID, currentUserID uint, limit, offset int) ([]models.PostResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != currentUserID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
 	if limit <= 0 || limit > 50 {
 		limit = 20
 	}
 
-	posts, err := s.postRepo.GetTrendingPosts(limit, offset)
+	posts, err := s.postRepo.GetByItinerary(itineraryID, currentUserID, limit, offset)
 	if err != nil {
-		return nil, errors.New("erro ao buscar posts em alta")
+		return nil, errors.New("erro ao buscar posts do roteiro")
 	}
 
 	var responses []models.PostResponse
@@ -282,6 +683,179 @@ func (s *PostService) GetTrendingPosts(currentUserID uint, limit, offset int) ([
 	return responses, nil
 }
 
+func (s *PostService) GetNearbyPosts(latitude, longitude, radiusKm float64, currentUserID uint, limit, offset int) ([]models.PostResponse, error) {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, errors.New("coordenadas inválidas")
+	}
+
+	if radiusKm <= 0 {
+		radiusKm = 10
+	}
+	if radiusKm > 100 {
+		return nil, errors.New("raio deve ser de no máximo 100 km")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	posts, err := s.postRepo.GetNearby(latitude, longitude, radiusKm, currentUserID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar posts próximos")
+	}
+
+	var responses []models.PostResponse
+	for _, post := range posts {
+		response := post.ToResponse(currentUserID)
+		distance := haversineKm(latitude, longitude, *post.Latitude, *post.Longitude)
+		response.DistanceKm = &distance
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+// nextPostCursor gera o cursor opaco da próxima página a partir do último post.
+// Página incompleta indica o fim da listagem e retorna cursor vazio.
+// recordEvents registra em segundo plano as exibições dos posts, ignorando as
+// do próprio autor
+func (s *PostService) recordEvents(posts []models.Post, viewerID uint, kind models.PostEventKind) {
+	var events []models.PostEvent
+	for _, post := range posts {
+		if post.AuthorID == viewerID {
+			continue
+		}
+		events = append(events, models.PostEvent{
+			PostID:   post.ID,
+			ViewerID: viewerID,
+			Kind:     kind,
+		})
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	go func() {
+		if err := s.postRepo.RecordEvents(events); err != nil {
+			log.Printf("Falha ao registrar exibições de posts: %v", err)
+		}
+	}()
+}
+
+func truncateDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func nextPostCursor(posts []models.Post, limit int, withScore bool) string {
+	if len(posts) < limit {
+		return ""
+	}
+
+	last := posts[len(posts)-1]
+	cursor := repositories.PostCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	if withScore {
+		cursor.Score = last.LikesCount*2 + last.CommentsCount
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePostCursor(cursor string) (*repositories.PostCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("cursor inválido")
+	}
+
+	var decoded repositories.PostCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == 0 {
+		return nil, errors.New("cursor inválido")
+	}
+	return &decoded, nil
+}
+
+// resolveMediaItems confere que cada URL é uma mídia enviada pelo próprio
+// usuário e obtém o tipo de cada anexo
+func (s *PostService) resolveMediaItems(userID uint, urls []string) ([]models.PostMediaItem, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	records, err := s.mediaRepo.GetByURLs(urls)
+	if err != nil {
+		return nil, errors.New("erro ao verificar mídias")
+	}
+
+	byURL := make(map[string]models.Media, len(records))
+	for _, media := range records {
+		byURL[media.URL] = media
+	}
+
+	items := make([]models.PostMediaItem, 0, len(urls))
+	for _, url := range urls {
+		media, ok := byURL[url]
+		if !ok || media.OwnerID != userID {
+			return nil, fmt.Errorf("mídia desconhecida ou enviada por outro usuário: %s", url)
+		}
+		if err := checkMediaModeration(&media); err != nil {
+			return nil, fmt.Errorf("%v: %s", err, url)
+		}
+		items = append(items, models.PostMediaItem{
+			MediaID:   media.ID,
+			URL:       media.URL,
+			MediaType: media.MediaType,
+			MimeType:  media.MimeType,
+			Width:     media.Width,
+			Height:    media.Height,
+			Variants:  media.Variants,
+		})
+	}
+
+	return items, nil
+}
+
+// checkMediaModeration impede o uso de mídias em quarentena ou recusadas
+// pela moderação
+func checkMediaModeration(media *models.Media) error {
+	switch media.ModerationStatus {
+	case models.MediaModerationFlagged:
+		return errors.New("mídia em análise pela moderação")
+	case models.MediaModerationRejected:
+		return errors.New("mídia recusada pela moderação")
+	}
+	return nil
+}
+
+func postTypeForMedia(items []models.PostMediaItem) models.PostType {
+	hasImage, hasVideo := false, false
+	for _, item := range items {
+		switch item.MediaType {
+		case models.MediaTypeImage:
+			hasImage = true
+		case models.MediaTypeVideo:
+			hasVideo = true
+		}
+	}
+
+	switch {
+	case hasImage && hasVideo:
+		return models.PostTypeMixed
+	case hasVideo:
+		return models.PostTypeVideo
+	case hasImage:
+		return models.PostTypeImage
+	}
+	return models.PostTypeText
+}
+
 // Funções de validação
 func (s *PostService) validateCreatePostRequest(req *CreatePostRequest) error {
 	if err := s.validateContent(req.Content); err != nil {
@@ -292,11 +866,24 @@ func (s *PostService) validateCreatePostRequest(req *CreatePostRequest) error {
 	if req.PostType != "" {
 		if req.PostType != models.PostTypeText &&
 			req.PostType != models.PostTypeImage &&
-			req.PostType != models.PostTypeVideo {
+			req.PostType != models.PostTypeVideo &&
+			req.PostType != models.PostTypeMixed {
 			return errors.New("tipo de post inválido")
 		}
 	}
 
+	if req.Visibility != "" {
+		if err := s.validateVisibility(req.Visibility); err != nil {
+			return err
+		}
+	}
+
+	if req.CommentPolicy != "" {
+		if err := s.validateCommentPolicy(req.CommentPolicy); err != nil {
+			return err
+		}
+	}
+
 	// Validar URLs de mídia
 	if len(req.MediaURLs) > 10 {
 		return errors.New("máximo de 10 mídias por post")
@@ -338,6 +925,36 @@ func (s *PostService) validateContent(content string) error {
 	return nil
 }
 
+func (s *PostService) validateVisibility(visibility models.PostVisibility) error {
+	switch visibility {
+	case models.PostVisibilityPublic, models.PostVisibilityFollowers, models.PostVisibilityPrivate:
+		return nil
+	}
+	return errors.New("visibilidade inválida")
+}
+
+func (s *PostService) validateCommentPolicy(policy models.CommentPolicy) error {
+	switch policy {
+	case models.CommentPolicyEveryone, models.CommentPolicyFollowers, models.CommentPolicyMentioned, models.CommentPolicyOff:
+		return nil
+	}
+	return errors.New("política de comentários inválida")
+}
+
+// validateItineraryLink garante que apenas roteiros publicados sejam vinculados
+func (s *PostService) validateItineraryLink(itineraryID uint) error {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return errors.New("roteiro vinculado não encontrado")
+	}
+
+	if !itinerary.IsPublic {
+		return errors.New("apenas roteiros públicos podem ser vinculados a posts")
+	}
+
+	return nil
+}
+
 func (s *PostService) validateMediaURL(url string) error {
 	if url == "" {
 		return errors.New("URL de mídia não pode ser vazia")
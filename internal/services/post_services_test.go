@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+func TestPostCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC)
+	posts := []models.Post{
+		{ID: 9, CreatedAt: createdAt.Add(time.Minute), LikesCount: 1},
+		{ID: 7, CreatedAt: createdAt, LikesCount: 4, CommentsCount: 3},
+	}
+
+	tests := []struct {
+		name      string
+		withScore bool
+		wantScore int
+	}{
+		{"cronológico", false, 0},
+		{"em alta", true, 11}, // 4 curtidas * 2 + 3 comentários
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := nextPostCursor(posts, len(posts), tt.withScore)
+			if cursor == "" {
+				t.Fatal("nextPostCursor devolveu cursor vazio para página completa")
+			}
+
+			decoded, err := decodePostCursor(cursor)
+			if err != nil {
+				t.Fatalf("decodePostCursor: %v", err)
+			}
+			if decoded.ID != 7 || !decoded.CreatedAt.Equal(createdAt) || decoded.Score != tt.wantScore {
+				t.Errorf("decodePostCursor = %+v, want id 7, %s, score %d", decoded, createdAt, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestNextPostCursorLastPage(t *testing.T) {
+	posts := []models.Post{{ID: 1, CreatedAt: time.Now()}}
+	if cursor := nextPostCursor(posts, 20, false); cursor != "" {
+		t.Errorf("nextPostCursor em página incompleta = %q, want vazio", cursor)
+	}
+}
+
+func TestDecodePostCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "vazio é a primeira página", cursor: "", wantNil: true},
+		{name: "base64 inválido", cursor: "%%%", wantErr: true},
+		{name: "json inválido", cursor: base64.RawURLEncoding.EncodeToString([]byte("{")), wantErr: true},
+		{name: "sem id", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"c":"2025-03-14T15:09:26Z"}`)), wantErr: true},
+		{name: "válido", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"c":"2025-03-14T15:09:26Z","i":3}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodePostCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodePostCursor(%q) erro = %v, wantErr %v", tt.cursor, err, tt.wantErr)
+			}
+			if !tt.wantErr && (decoded == nil) != tt.wantNil {
+				t.Errorf("decodePostCursor(%q) = %+v, wantNil %v", tt.cursor, decoded, tt.wantNil)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrProfanityRejected é devolvido quando o texto tem termos cuja ação é
+// recusar
+var ErrProfanityRejected = errors.New("texto inválido: contém termos ofensivos")
+
+// ProfanityAction é o que o filtro faz com o texto que tem termos de uma
+// categoria
+type ProfanityAction string
+
+const (
+	ProfanityActionOff    ProfanityAction = "off"
+	ProfanityActionMask   ProfanityAction = "mask"   // troca as letras do termo por asteriscos
+	ProfanityActionFlag   ProfanityAction = "flag"   // publica e coloca na fila de moderação
+	ProfanityActionReject ProfanityAction = "reject" // recusa o texto
+)
+
+type ProfanityConfig struct {
+	ProfanityAction ProfanityAction // palavrões e xingamentos
+	SlurAction      ProfanityAction // ofensas a grupos (discurso de ódio)
+	ExtraWords      []string        // termos acrescentados aos palavrões
+	AllowedWords    []string        // termos das listas liberados (falsos positivos)
+}
+
+// ProfanityResult é o texto depois do filtro
+type ProfanityResult struct {
+	Text string // texto a gravar, com os termos mascarados quando a ação é mask
+	// Termos que pedem revisão da moderação (ação flag), como escritos
+	Flagged    []string
+	FlagReason models.ReportReason
+}
+
+type ProfanityServiceInterface interface {
+	Filter(text string) (*ProfanityResult, error)
+	FlagContent(targetType models.ReportTargetType, targetID, ownerID uint, result *ProfanityResult)
+}
+
+type profanityCategory int
+
+const (
+	profanityCategoryProfanity profanityCategory = iota
+	profanityCategorySlur
+)
+
+type ProfanityService struct {
+	reportRepo repositories.ReportRepositoryInterface
+	words      map[string]profanityCategory
+	actions    map[profanityCategory]ProfanityAction
+}
+
+func NewProfanityService(reportRepo repositories.ReportRepositoryInterface, config *ProfanityConfig) ProfanityServiceInterface {
+	words := make(map[string]profanityCategory)
+	for _, list := range [][]string{profanityWords, config.ExtraWords} {
+		for _, word := range list {
+			if word = normalizeProfanityToken(word); word != "" {
+				words[word] = profanityCategoryProfanity
+			}
+		}
+	}
+	for _, word := range slurWords {
+		words[normalizeProfanityToken(word)] = profanityCategorySlur
+	}
+	for _, word := range config.AllowedWords {
+		delete(words, normalizeProfanityToken(word))
+	}
+
+	return &ProfanityService{
+		reportRepo: reportRepo,
+		words:      words,
+		actions: map[profanityCategory]ProfanityAction{
+			profanityCategoryProfanity: parseProfanityAction(config.ProfanityAction, ProfanityActionMask),
+			profanityCategorySlur:      parseProfanityAction(config.SlurAction, ProfanityActionReject),
+		},
+	}
+}
+
+// Filter aplica a ação de cada termo encontrado: recusa o texto inteiro se
+// algum termo pede reject, mascara os termos com mask e lista os com flag
+// para a moderação. Só palavras inteiras contam, para não pegar termos
+// dentro de outras palavras
+func (s *ProfanityService) Filter(text string) (*ProfanityResult, error) {
+	result := &ProfanityResult{Text: text}
+
+	var masked strings.Builder
+	last := 0
+	for _, token := range profanityTokens(text) {
+		category, found := s.lookup(normalizeProfanityToken(text[token.start:token.end]))
+		if !found {
+			continue
+		}
+
+		switch s.actions[category] {
+		case ProfanityActionReject:
+			return nil, ErrProfanityRejected
+		case ProfanityActionMask:
+			masked.WriteString(text[last:token.start])
+			masked.WriteString(maskProfanity(text[token.start:token.end]))
+			last = token.end
+		case ProfanityActionFlag:
+			result.Flagged = append(result.Flagged, text[token.start:token.end])
+			if category == profanityCategorySlur || result.FlagReason == "" {
+				result.FlagReason = flagReasonForCategory(category)
+			}
+		}
+	}
+
+	if last > 0 {
+		masked.WriteString(text[last:])
+		result.Text = masked.String()
+	}
+	return result, nil
+}
+
+// FlagContent coloca o conteúdo recém-gravado na fila de moderação quando o
+// filtro encontrou termos com a ação flag
+func (s *ProfanityService) FlagContent(targetType models.ReportTargetType, targetID, ownerID uint, result *ProfanityResult) {
+	if result == nil || len(result.Flagged) == 0 {
+		return
+	}
+
+	details := truncateString("Termos sinalizados pelo filtro: "+strings.Join(result.Flagged, ", "), 500)
+	if err := s.reportRepo.Flag(targetType, targetID, ownerID, result.FlagReason, details); err != nil {
+		log.Printf("Falha ao sinalizar %s %d pelo filtro de palavrões: %v", targetType, targetID, err)
+	}
+}
+
+// lookup procura o termo normalizado, também sem o "s" do plural
+func (s *ProfanityService) lookup(token string) (profanityCategory, bool) {
+	if token == "" {
+		return 0, false
+	}
+	if category, ok := s.words[token]; ok {
+		return category, true
+	}
+	if singular := strings.TrimSuffix(token, "s"); singular != token {
+		category, ok := s.words[singular]
+		return category, ok
+	}
+	return 0, false
+}
+
+type profanityToken struct {
+	start, end int // posição em bytes no texto
+}
+
+// profanityTokens separa as palavras do texto; dígitos e os símbolos do
+// leetspeak ("@", "$") fazem parte da palavra
+func profanityTokens(text string) []profanityToken {
+	var tokens []profanityToken
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || r == '@' || r == '$'
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, profanityToken{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, profanityToken{start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// profanityLeetspeak são as trocas de letras por números e símbolos
+var profanityLeetspeak = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'@': 'a',
+	'$': 's',
+}
+
+// normalizeProfanityToken deixa a palavra em minúsculas, sem acentos, com o
+// leetspeak desfeito e sem letras repetidas ("M3RRRDA" vira "merda")
+func normalizeProfanityToken(token string) string {
+	decomposed := norm.NFD.String(strings.ToLower(strings.TrimSpace(token)))
+
+	var b strings.Builder
+	var previous rune
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if replacement, ok := profanityLeetspeak[r]; ok {
+			r = replacement
+		}
+		if r == previous {
+			continue
+		}
+		b.WriteRune(r)
+		previous = r
+	}
+	return b.String()
+}
+
+// maskProfanity mantém a primeira letra e troca as demais por asteriscos
+func maskProfanity(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	rest := norm.NFC.String(word[size:])
+	return string(first) + strings.Repeat("*", utf8.RuneCountInString(rest))
+}
+
+func parseProfanityAction(action, fallback ProfanityAction) ProfanityAction {
+	switch action {
+	case ProfanityActionOff, ProfanityActionMask, ProfanityActionFlag, ProfanityActionReject:
+		return action
+	case "":
+		return fallback
+	default:
+		log.Printf("Ação do filtro de palavrões inválida %q, usando %s", action, fallback)
+		return fallback
+	}
+}
+
+func flagReasonForCategory(category profanityCategory) models.ReportReason {
+	if category == profanityCategorySlur {
+		return models.ReportReasonHateSpeech
+	}
+	return models.ReportReasonOther
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeProfanityToken(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{"merda", "merda"},
+		{"MERDA", "merda"},
+		{"  merda ", "merda"},
+		{"m3rd4", "merda"},
+		{"M3RRRDA", "merda"},
+		{"$h1t", "shit"},
+		{"p@rr@", "para"}, // letras repetidas viram uma só antes da comparação
+		{"otário", "otario"},
+		{"cuzão", "cuzao"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			if got := normalizeProfanityToken(tt.token); got != tt.want {
+				t.Errorf("normalizeProfanityToken(%q) = %q, want %q", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskProfanity(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"merda", "m****"},
+		{"Otário", "O*****"},
+		{"cu", "c*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := maskProfanity(tt.word); got != tt.want {
+				t.Errorf("maskProfanity(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfanityFilter(t *testing.T) {
+	service := NewProfanityService(nil, &ProfanityConfig{
+		ProfanityAction: ProfanityActionMask,
+		SlurAction:      ProfanityActionReject,
+		AllowedWords:    []string{"cock"},
+	})
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr error
+	}{
+		{name: "sem termos", text: "Que praia linda!", want: "Que praia linda!"},
+		{name: "mascara o termo", text: "Que merda de trânsito", want: "Que m**** de trânsito"},
+		{name: "mascara leetspeak e plural", text: "M3RDAS!", want: "M*****!"},
+		{name: "não pega dentro de outra palavra", text: "Cuba e Curitiba", want: "Cuba e Curitiba"},
+		{name: "termo liberado", text: "cock-tail", want: "cock-tail"},
+		{name: "ofensa é recusada", text: "seu retardado", wantErr: ErrProfanityRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Filter(tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Filter(%q) erro = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if err == nil && result.Text != tt.want {
+				t.Errorf("Filter(%q) = %q, want %q", tt.text, result.Text, tt.want)
+			}
+		})
+	}
+}
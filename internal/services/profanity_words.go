@@ -0,0 +1,37 @@
+package services
+
+// Listas padrão do filtro de palavrões, em português e inglês. Os termos são
+// normalizados como o texto filtrado (minúsculas, sem acentos, leetspeak e
+// letras repetidas), e o plural com "s" é reconhecido; para liberar um
+// falso positivo use PROFANITY_ALLOWED_WORDS
+
+// profanityWords são os palavrões e xingamentos comuns
+var profanityWords = []string{
+	// Português
+	"arrombado", "arrombada", "babaca", "bosta", "buceta", "boceta", "caralho", "corno", "cu",
+	"cuzao", "cuzão", "cuzona", "desgraçado", "desgraçada", "escroto", "escrota", "foda", "foder",
+	"fodido", "fodida", "fuder", "merda", "merdinha", "otario", "otário", "otaria", "otária",
+	"piroca", "porra", "punheta", "puta", "puto", "putaria", "vagabundo", "vagabunda",
+	"xereca", "xoxota",
+	// Abreviações
+	"fdp", "krl", "pqp", "tnc", "vsf",
+
+	// Inglês
+	"asshole", "bastard", "bitch", "bullshit", "cock", "cocksucker", "cunt", "dick", "dickhead",
+	"douche", "douchebag", "dumbass", "fuck", "fucked", "fucker", "fucking", "jackass",
+	"motherfucker", "prick", "pussy", "shit", "shitty", "slut", "twat", "wanker", "whore",
+	// Abreviações
+	"stfu", "wtf",
+}
+
+// slurWords são as ofensas a grupos (raça, etnia, orientação sexual,
+// identidade de gênero, deficiência), tratadas como discurso de ódio
+var slurWords = []string{
+	// Português
+	"baitola", "boiola", "mongoloide", "mongolóide", "retardado", "retardada", "sapatão",
+	"traveco", "viado",
+
+	// Inglês
+	"chink", "dyke", "fag", "faggot", "kike", "nigga", "nigger", "retard", "retarded", "spic",
+	"tranny", "wetback",
+}
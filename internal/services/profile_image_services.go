@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+type ProfileImageKind string
+
+const (
+	ProfileImageAvatar ProfileImageKind = "avatar"
+	ProfileImageCover  ProfileImageKind = "cover"
+)
+
+// CropRect é a área escolhida pelo usuário, em pixels da foto já na
+// orientação correta. Vazia usa o centro da foto
+type CropRect struct {
+	X      int `form:"x" json:"x"`
+	Y      int `form:"y" json:"y"`
+	Width  int `form:"width" json:"width"`
+	Height int `form:"height" json:"height"`
+}
+
+func (r *CropRect) isEmpty() bool {
+	return r == nil || (r.Width == 0 && r.Height == 0)
+}
+
+type profileRendition struct {
+	name          string
+	width, height int
+}
+
+// profileRenditions são os tamanhos gerados para cada tipo de foto de perfil;
+// o primeiro é o principal, gravado no usuário
+var profileRenditions = map[ProfileImageKind][]profileRendition{
+	ProfileImageAvatar: {
+		{name: "large", width: 400, height: 400},
+		{name: "medium", width: 200, height: 200},
+		{name: "small", width: 64, height: 64},
+	},
+	ProfileImageCover: {
+		{name: "large", width: 1500, height: 500},
+		{name: "small", width: 750, height: 250},
+	},
+}
+
+// StoreProfileImage recorta a foto de perfil ou de capa, grava os tamanhos
+// padrão e registra o usuário como dono. Diferente dos uploads comuns, o
+// original não é guardado
+func (s *MediaService) StoreProfileImage(file *multipart.FileHeader, userID uint, kind ProfileImageKind, crop *CropRect) (*models.Media, error) {
+	renditions, ok := profileRenditions[kind]
+	if !ok {
+		return nil, errors.New("tipo de foto de perfil não suportado")
+	}
+
+	if err := s.ValidateFile(file, MediaTypeImage); err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, s.config.MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.config.MaxFileSize {
+		return nil, fmt.Errorf("arquivo muito grande. Tamanho máximo: %d MB", s.config.MaxFileSize/(1024*1024))
+	}
+
+	// Mesmas verificações dos uploads comuns: formato pelo conteúdo, sem
+	// metadados e sem bombas de descompressão
+	mimeType, err := s.checkContent(data[:min(len(data), sniffLength)], file.Filename, MediaTypeImage)
+	if err != nil {
+		return nil, err
+	}
+	data, exif, err := stripImageMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkImageContent(data); err != nil {
+		return nil, err
+	}
+
+	// A foto de perfil aparece na hora para todos, então não há quarentena:
+	// o que a moderação sinalizar é recusado
+	if status, _ := s.moderateImage(data, mimeType); status != models.MediaModerationApproved {
+		return nil, errors.New("imagem recusada pela moderação automática")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errInvalidImage
+	}
+	primarySize := renditions[0]
+	region, err := cropRegion(applyOrientation(toRGBA(img), exif.Orientation), crop, primarySize.width, primarySize.height)
+	if err != nil {
+		return nil, err
+	}
+
+	directory := string(kind) + "s"
+	base := strings.TrimSuffix(s.generateFileName(file.Filename, userID), filepath.Ext(file.Filename))
+
+	variants := make(map[string]models.MediaVariant, len(renditions))
+	for _, rendition := range renditions {
+		width, height := rendition.width, rendition.height
+		// Recortes menores que o tamanho não são ampliados
+		if region.Bounds().Dx() < width {
+			width = region.Bounds().Dx()
+			height = max(1, width*rendition.height/rendition.width)
+		}
+
+		encoded, extension, contentType, err := s.encodeVariant(resizeImage(region, width, height), "")
+		if err == nil {
+			var filePath, url string
+			filePath, url, err = s.store(bytes.NewReader(encoded), contentType, base+"_"+rendition.name+extension, directory)
+			variants[rendition.name] = models.MediaVariant{
+				URL:      url,
+				FilePath: filePath,
+				MimeType: contentType,
+				Width:    width,
+				Height:   height,
+			}
+		}
+		if err != nil {
+			s.removeVariants(variants)
+			log.Printf("Falha ao gravar %s %s do usuário %d: %v", kind, rendition.name, userID, err)
+			return nil, errors.New("erro ao salvar imagem")
+		}
+	}
+
+	// O tamanho principal é o arquivo da mídia; nas variantes ele aponta para
+	// o próprio registro, sem arquivo separado
+	primary := variants[primarySize.name]
+	variants[primarySize.name] = models.MediaVariant{
+		URL:      primary.URL,
+		MimeType: primary.MimeType,
+		Width:    primary.Width,
+		Height:   primary.Height,
+	}
+
+	media := &models.Media{
+		OwnerID:   userID,
+		URL:       primary.URL,
+		FilePath:  primary.FilePath,
+		MediaType: MediaTypeImage,
+		MimeType:  primary.MimeType,
+		FileSize:  int64(len(data)),
+		Width:     primary.Width,
+		Height:    primary.Height,
+		Variants:  variants,
+
+		ModerationStatus: models.MediaModerationApproved,
+	}
+	if err := s.mediaRepo.Create(media); err != nil {
+		s.removeVariants(variants)
+		s.RemoveFiles([]string{primary.FilePath})
+		return nil, errors.New("erro ao registrar mídia")
+	}
+
+	return media, nil
+}
+
+// DeleteUnusedMedia apaga a mídia da URL se ela pertence ao usuário e nenhum
+// conteúdo a exibe mais (ex.: a foto de perfil anterior). URLs externas ou
+// ainda em uso são mantidas
+func (s *MediaService) DeleteUnusedMedia(url string, userID uint) {
+	media, err := s.mediaRepo.GetByURLs([]string{url})
+	if err != nil || len(media) == 0 || media[0].OwnerID != userID {
+		return
+	}
+
+	references, err := s.mediaRepo.CountReferences(&media[0])
+	if err != nil || references > 0 {
+		return
+	}
+
+	if err := s.DeleteFile(media[0].FilePath); err != nil {
+		log.Printf("Falha ao remover mídia %s: %v", media[0].FilePath, err)
+	}
+}
+
+// cropRegion aplica o recorte pedido e o ajusta, pelo centro, à proporção do
+// tamanho final
+func cropRegion(img *image.RGBA, crop *CropRect, width, height int) (*image.RGBA, error) {
+	bounds := img.Bounds()
+	region := bounds
+	if !crop.isEmpty() {
+		region = image.Rect(crop.X, crop.Y, crop.X+crop.Width, crop.Y+crop.Height).Add(bounds.Min)
+		if crop.X < 0 || crop.Y < 0 || crop.Width <= 0 || crop.Height <= 0 || !region.In(bounds) {
+			return nil, fmt.Errorf("área de recorte inválida: a foto tem %dx%d pixels", bounds.Dx(), bounds.Dy())
+		}
+	}
+
+	regionWidth, regionHeight := region.Dx(), region.Dy()
+	if regionWidth*height > regionHeight*width {
+		regionWidth = max(1, regionHeight*width/height)
+	} else {
+		regionHeight = max(1, regionWidth*height/width)
+	}
+	x := region.Min.X + (region.Dx()-regionWidth)/2
+	y := region.Min.Y + (region.Dy()-regionHeight)/2
+
+	return img.SubImage(image.Rect(x, y, x+regionWidth, y+regionHeight)).(*image.RGBA), nil
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// PromotionPaymentServiceInterface cobra dos parceiros as promoções nas
+// páginas de destino; a promoção só é exibida depois de paga
+type PromotionPaymentServiceInterface interface {
+	CheckoutPromotion(promotionID, userID uint, req *PromotionCheckoutRequest) (*models.PromotionCheckoutResponse, error)
+	ConfirmPromotionPayment(promotionID, userID uint) (*models.DestinationPromotionResponse, error)
+}
+
+type PromotionCheckoutRequest struct {
+	BillingCountry string `json:"billing_country"` // país da cobrança, usado na análise antifraude
+	IPCountry      string `json:"-"`               // país da requisição, preenchido pelo handler
+}
+
+type PromotionPaymentService struct {
+	destinationRepo repositories.DestinationRepositoryInterface
+	userRepo        repositories.UserRepositoryInterface
+	ledgerService   LedgerServiceInterface
+	fraudService    FraudServiceInterface
+	provider        BillingProviderInterface
+	config          *BillingConfig
+}
+
+func NewPromotionPaymentService(
+	destinationRepo repositories.DestinationRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	ledgerService LedgerServiceInterface,
+	fraudService FraudServiceInterface,
+	webhookService WebhookServiceInterface,
+	provider BillingProviderInterface,
+	config *BillingConfig,
+) PromotionPaymentServiceInterface {
+	if config.PromotionDailyPrice <= 0 {
+		config.PromotionDailyPrice = 5000
+	}
+	if len(config.PromotionCurrency) != 3 {
+		config.PromotionCurrency = "BRL"
+	}
+	config.PromotionCurrency = strings.ToUpper(config.PromotionCurrency)
+
+	service := &PromotionPaymentService{
+		destinationRepo: destinationRepo,
+		userRepo:        userRepo,
+		ledgerService:   ledgerService,
+		fraudService:    fraudService,
+		provider:        provider,
+		config:          config,
+	}
+
+	fraudService.RegisterReviewHandler(models.FraudSubjectPromotion, service.applyFraudReview)
+	webhookService.RegisterProcessor(models.WebhookProvider(provider.Name()), service.applyPaymentWebhook)
+
+	return service
+}
+
+// CheckoutPromotion inicia o pagamento de uma promoção criada pelo parceiro,
+// cobrando cada dia do período. Pagamentos recusados podem ser refeitos
+func (s *PromotionPaymentService) CheckoutPromotion(promotionID, userID uint, req *PromotionCheckoutRequest) (*models.PromotionCheckoutResponse, error) {
+	promotion, err := s.getOwnPromotion(promotionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := promotion.PaymentStatus
+	switch from {
+	case models.PromotionPaymentUnpaid, models.PromotionPaymentFailed:
+	case models.PromotionPaymentNone:
+		return nil, errors.New("esta promoção não precisa de pagamento")
+	case models.PromotionPaymentPaid:
+		return nil, errors.New("promoção já paga")
+	default:
+		return nil, errors.New("pagamento da promoção já iniciado")
+	}
+
+	if !promotion.EndsAt.After(time.Now()) {
+		return nil, errors.New("promoção já encerrada")
+	}
+
+	partner, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	amount := promotionDays(promotion) * s.config.PromotionDailyPrice
+	currency := s.config.PromotionCurrency
+
+	check, err := s.fraudService.Evaluate(&models.FraudCheckInput{
+		Subject:   models.FraudSubjectPromotion,
+		UserID:    userID,
+		Email:     partner.Email,
+		Amount:    amount,
+		Currency:  currency,
+		IPCountry: req.IPCountry,
+		Country:   req.BillingCountry,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if check.Decision == models.FraudDecisionBlocked {
+		return nil, errors.New("pagamento recusado pela análise de segurança")
+	}
+	underReview := check.Decision == models.FraudDecisionReview
+
+	intent, err := s.provider.CreatePaymentIntent(&PaymentIntentParams{
+		Amount:        amount,
+		Currency:      currency,
+		ManualCapture: underReview,
+		Description:   "Promoção em página de destino no guIA",
+		Metadata: map[string]string{
+			"promotion_id": strconv.FormatUint(uint64(promotion.ID), 10),
+			"partner_id":   strconv.FormatUint(uint64(userID), 10),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.PaymentStatus = models.PromotionPaymentPending
+	if underReview {
+		// O pagamento é apenas autorizado e só é capturado após a revisão
+		promotion.PaymentStatus = models.PromotionPaymentReview
+	}
+	promotion.Amount = amount
+	promotion.Currency = currency
+	promotion.Provider = s.provider.Name()
+	promotion.ProviderPaymentID = intent.ID
+	promotion.FraudCheckID = &check.ID
+
+	started, err := s.destinationRepo.StartPromotionPayment(promotion, from)
+	if err != nil || !started {
+		if cancelErr := s.provider.CancelPaymentIntent(intent.ID); cancelErr != nil {
+			log.Printf("Erro ao cancelar pagamento %s da promoção %d: %v", intent.ID, promotion.ID, cancelErr)
+		}
+		if err != nil {
+			return nil, errors.New("erro ao registrar pagamento da promoção")
+		}
+		return nil, errors.New("pagamento da promoção já iniciado")
+	}
+
+	if err := s.fraudService.AttachSubject(check, promotion.ID); err != nil {
+		log.Printf("Erro ao vincular avaliação antifraude %d à promoção %d: %v", check.ID, promotion.ID, err)
+	}
+
+	return &models.PromotionCheckoutResponse{
+		Promotion:    promotion.ToManagedResponse(),
+		ClientSecret: intent.ClientSecret,
+	}, nil
+}
+
+// ConfirmPromotionPayment consulta o provedor para atualizar o pagamento
+// pendente depois que o parceiro conclui a cobrança no app
+func (s *PromotionPaymentService) ConfirmPromotionPayment(promotionID, userID uint) (*models.DestinationPromotionResponse, error) {
+	promotion, err := s.getOwnPromotion(promotionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if promotion.PaymentStatus != models.PromotionPaymentPending {
+		return promotion.ToManagedResponse(), nil
+	}
+
+	intent, err := s.provider.GetPaymentIntent(promotion.ProviderPaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPaymentIntent(promotion, models.PromotionPaymentPending, intent); err != nil {
+		return nil, err
+	}
+
+	return promotion.ToManagedResponse(), nil
+}
+
+// applyPaymentIntent reflete o status do pagamento na promoção e lança no
+// livro-razão as promoções pagas
+func (s *PromotionPaymentService) applyPaymentIntent(promotion *models.DestinationPromotion, from models.PromotionPaymentStatus, intent *PaymentIntent) error {
+	status := models.PromotionPaymentPending
+	switch intent.Status {
+	case PaymentIntentSucceeded:
+		status = models.PromotionPaymentPaid
+	case PaymentIntentFailed:
+		status = models.PromotionPaymentFailed
+	}
+	if status == from {
+		return nil
+	}
+
+	updated, err := s.destinationRepo.UpdatePromotionPaymentStatus(promotion, from, status)
+	if err != nil {
+		return errors.New("erro ao atualizar pagamento da promoção")
+	}
+
+	if updated && status == models.PromotionPaymentPaid {
+		// Falhas aqui aparecem no relatório de conciliação
+		err := s.ledgerService.RecordPlatformSale(models.LedgerKindPromotion, fmt.Sprintf("promotion:%d", promotion.ID),
+			promotion.Currency, promotion.Amount, "Promoção em página de destino")
+		if err != nil {
+			log.Printf("Erro ao lançar promoção %d no livro-razão: %v", promotion.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// applyPaymentWebhook confirma os pagamentos pendentes de promoções quando o
+// provedor avisa que mudaram de status; eventos de outros pagamentos são
+// ignorados
+func (s *PromotionPaymentService) applyPaymentWebhook(event *models.WebhookEvent) error {
+	paymentID, err := paymentIntentFromWebhook(event)
+	if err != nil || paymentID == "" {
+		return err
+	}
+
+	promotion, err := s.destinationRepo.GetPromotionByProviderPaymentID(paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.New("erro ao buscar promoção")
+	}
+
+	// Pagamentos em revisão antifraude seguem o fluxo da revisão manual
+	if promotion.PaymentStatus != models.PromotionPaymentPending {
+		return nil
+	}
+
+	intent, err := s.provider.GetPaymentIntent(promotion.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPaymentIntent(promotion, models.PromotionPaymentPending, intent)
+}
+
+// applyFraudReview captura o pagamento autorizado de uma promoção aprovada na
+// revisão manual ou o cancela quando rejeitada
+func (s *PromotionPaymentService) applyFraudReview(check *models.FraudCheck, approved bool) error {
+	promotion, err := s.destinationRepo.GetPromotionByID(check.SubjectID)
+	if err != nil {
+		return errors.New("promoção não encontrada")
+	}
+
+	if promotion.PaymentStatus != models.PromotionPaymentReview {
+		return nil
+	}
+
+	if !approved {
+		if err := s.provider.CancelPaymentIntent(promotion.ProviderPaymentID); err != nil {
+			return err
+		}
+		if _, err := s.destinationRepo.UpdatePromotionPaymentStatus(promotion, models.PromotionPaymentReview, models.PromotionPaymentFailed); err != nil {
+			return errors.New("erro ao atualizar pagamento da promoção")
+		}
+		return nil
+	}
+
+	intent, err := s.provider.CapturePaymentIntent(promotion.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPaymentIntent(promotion, models.PromotionPaymentReview, intent)
+}
+
+func (s *PromotionPaymentService) getOwnPromotion(promotionID, userID uint) (*models.DestinationPromotion, error) {
+	promotion, err := s.destinationRepo.GetPromotionByID(promotionID)
+	if err != nil {
+		return nil, errors.New("promoção não encontrada")
+	}
+
+	if promotion.CreatorID != userID {
+		return nil, errors.New("você não tem permissão para pagar esta promoção")
+	}
+
+	return promotion, nil
+}
+
+// promotionDays conta os dias cobrados do período, arredondando para cima
+func promotionDays(promotion *models.DestinationPromotion) int64 {
+	period := promotion.EndsAt.Sub(promotion.StartsAt)
+	days := int64(period / (24 * time.Hour))
+	if period%(24*time.Hour) != 0 {
+		days++
+	}
+	return days
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const maxRatingReplyLength = 1000
+
+// Ordenações aceitas na listagem de avaliações
+const (
+	RatingSortRecent  = "recent"
+	RatingSortHighest = "highest"
+	RatingSortLowest  = "lowest"
+	RatingSortHelpful = "helpful"
+)
+
+type RatingBucket struct {
+	Rating     int     `json:"rating"`
+	Count      int64   `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// ItineraryRatings é uma página de avaliações do roteiro, com o histograma
+// de notas calculado sobre todas as avaliações
+type ItineraryRatings struct {
+	ItineraryID   uint                              `json:"itinerary_id"`
+	AverageRating float64                           `json:"average_rating"`
+	RatingsCount  int64                             `json:"ratings_count"`
+	Distribution  []RatingBucket                    `json:"distribution"`
+	Ratings       []*models.ItineraryRatingResponse `json:"ratings"`
+}
+
+type RatingServiceInterface interface {
+	GetRatings(itineraryID, userID uint, sort string, limit, offset int) (*ItineraryRatings, error)
+	ReplyToRating(itineraryID, ratingID, userID uint, reply string) (*models.ItineraryRatingResponse, error)
+	DeleteReply(itineraryID, ratingID, userID uint) error
+	VoteRating(itineraryID, ratingID, userID uint, helpful bool) (*models.ItineraryRatingResponse, error)
+	RemoveVote(itineraryID, ratingID, userID uint) (*models.ItineraryRatingResponse, error)
+}
+
+type RatingService struct {
+	ratingRepo    repositories.RatingRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	eventBus      events.BusInterface
+}
+
+func NewRatingService(
+	ratingRepo repositories.RatingRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	eventBus events.BusInterface,
+) RatingServiceInterface {
+	return &RatingService{
+		ratingRepo:    ratingRepo,
+		itineraryRepo: itineraryRepo,
+		eventBus:      eventBus,
+	}
+}
+
+func (s *RatingService) GetRatings(itineraryID, userID uint, sort string, limit, offset int) (*ItineraryRatings, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	switch sort {
+	case "", RatingSortRecent:
+		sort = ""
+	case RatingSortHighest, RatingSortLowest, RatingSortHelpful:
+	default:
+		return nil, errors.New("ordenação inválida: use recent, highest, lowest ou helpful")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	distribution, err := s.ratingRepo.GetDistribution(itineraryID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações")
+	}
+
+	ratings, err := s.ratingRepo.GetByItinerary(itineraryID, sort, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações")
+	}
+
+	result := &ItineraryRatings{
+		ItineraryID: itineraryID,
+		Ratings:     make([]*models.ItineraryRatingResponse, 0, len(ratings)),
+	}
+	result.AverageRating, result.RatingsCount, result.Distribution = summarizeRatings(distribution)
+
+	ratingIDs := make([]uint, 0, len(ratings))
+	for i := range ratings {
+		ratingIDs = append(ratingIDs, ratings[i].ID)
+	}
+	votes, err := s.ratingRepo.GetUserVotes(userID, ratingIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações")
+	}
+
+	for i := range ratings {
+		response := ratings[i].ToResponse()
+		if helpful, ok := votes[ratings[i].ID]; ok {
+			response.ViewerVote = &helpful
+		}
+		result.Ratings = append(result.Ratings, response)
+	}
+
+	return result, nil
+}
+
+// ReplyToRating cria ou edita a resposta do autor do roteiro a uma
+// avaliação; cada avaliação tem no máximo uma resposta
+func (s *RatingService) ReplyToRating(itineraryID, ratingID, userID uint, reply string) (*models.ItineraryRatingResponse, error) {
+	itinerary, rating, err := s.authorRating(itineraryID, ratingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return nil, errors.New("resposta é obrigatória")
+	}
+	if utf8.RuneCountInString(reply) > maxRatingReplyLength {
+		return nil, fmt.Errorf("resposta deve ter no máximo %d caracteres", maxRatingReplyLength)
+	}
+
+	now := time.Now()
+	if err := s.ratingRepo.SetReply(rating.ID, reply, &now); err != nil {
+		return nil, errors.New("erro ao responder avaliação")
+	}
+
+	rating.Reply = reply
+	rating.RepliedAt = &now
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.RatingReplied,
+		ActorID:  userID,
+		EntityID: rating.ID,
+		Data: map[string]string{
+			"itinerary_id": fmt.Sprint(itinerary.ID),
+		},
+	})
+
+	return rating.ToResponse(), nil
+}
+
+func (s *RatingService) DeleteReply(itineraryID, ratingID, userID uint) error {
+	_, rating, err := s.authorRating(itineraryID, ratingID, userID)
+	if err != nil {
+		return err
+	}
+
+	if rating.RepliedAt == nil {
+		return errors.New("resposta não encontrada")
+	}
+
+	if err := s.ratingRepo.SetReply(rating.ID, "", nil); err != nil {
+		return errors.New("erro ao remover resposta")
+	}
+	return nil
+}
+
+// VoteRating marca a avaliação como útil ou não útil; votar de novo troca o
+// voto anterior. O autor da avaliação não vota na própria
+func (s *RatingService) VoteRating(itineraryID, ratingID, userID uint, helpful bool) (*models.ItineraryRatingResponse, error) {
+	rating, err := s.visibleRating(itineraryID, ratingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rating.UserID == userID {
+		return nil, errors.New("você não pode votar na própria avaliação")
+	}
+
+	if err := s.ratingRepo.Vote(rating.ID, userID, helpful); err != nil {
+		return nil, errors.New("erro ao registrar voto")
+	}
+
+	return s.ratingWithVote(rating.ID, userID)
+}
+
+func (s *RatingService) RemoveVote(itineraryID, ratingID, userID uint) (*models.ItineraryRatingResponse, error) {
+	rating, err := s.visibleRating(itineraryID, ratingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.ratingRepo.RemoveVote(rating.ID, userID)
+	if err != nil {
+		return nil, errors.New("erro ao remover voto")
+	}
+	if !removed {
+		return nil, errors.New("voto não encontrado")
+	}
+
+	return s.ratingWithVote(rating.ID, userID)
+}
+
+// summarizeRatings calcula média, total e histograma a partir da contagem por
+// nota. O histograma vai do maior para o menor, como nos das lojas de apps
+func summarizeRatings(distribution map[int]int64) (float64, int64, []RatingBucket) {
+	var count, sum int64
+	for stars := 1; stars <= 5; stars++ {
+		count += distribution[stars]
+		sum += int64(stars) * distribution[stars]
+	}
+
+	buckets := make([]RatingBucket, 0, 5)
+	for stars := 5; stars >= 1; stars-- {
+		bucket := RatingBucket{Rating: stars, Count: distribution[stars]}
+		if count > 0 {
+			bucket.Percentage = roundCents(float64(bucket.Count) / float64(count) * 100)
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if count == 0 {
+		return 0, 0, buckets
+	}
+	return roundCents(float64(sum) / float64(count)), count, buckets
+}
+
+// visibleRating carrega a avaliação de um roteiro que o usuário pode ver
+func (s *RatingService) visibleRating(itineraryID, ratingID, userID uint) (*models.ItineraryRating, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	rating, err := s.ratingRepo.GetByID(ratingID)
+	if err != nil || rating.ItineraryID != itineraryID {
+		return nil, errors.New("avaliação não encontrada")
+	}
+
+	return rating, nil
+}
+
+// ratingWithVote recarrega a avaliação com os contadores atualizados e o voto
+// do usuário
+func (s *RatingService) ratingWithVote(ratingID, userID uint) (*models.ItineraryRatingResponse, error) {
+	rating, err := s.ratingRepo.GetByID(ratingID)
+	if err != nil {
+		return nil, errors.New("avaliação não encontrada")
+	}
+
+	response := rating.ToResponse()
+	votes, err := s.ratingRepo.GetUserVotes(userID, []uint{ratingID})
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliação")
+	}
+	if helpful, ok := votes[ratingID]; ok {
+		response.ViewerVote = &helpful
+	}
+
+	return response, nil
+}
+
+// authorRating carrega a avaliação do roteiro, garantindo que quem responde
+// é o autor do roteiro
+func (s *RatingService) authorRating(itineraryID, ratingID, userID uint) (*models.Itinerary, *models.ItineraryRating, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, nil, errors.New("você não tem permissão para responder avaliações deste roteiro")
+	}
+
+	rating, err := s.ratingRepo.GetByID(ratingID)
+	if err != nil || rating.ItineraryID != itineraryID {
+		return nil, nil, errors.New("avaliação não encontrada")
+	}
+
+	return itinerary, rating, nil
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/realtime"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// MediaStatusMessage é o andamento do processamento de uma mídia enviado ao
+// dono em tempo real
+type MediaStatusMessage struct {
+	MediaID uint                         `json:"media_id"`
+	Status  models.MediaProcessingStatus `json:"status"`
+	Error   string                       `json:"error,omitempty"`
+}
+
+// MessageReceiptMessage avisa os participantes de uma conversa até qual
+// mensagem um deles recebeu ou leu
+type MessageReceiptMessage struct {
+	ConversationID uint                 `json:"conversation_id"`
+	UserID         uint                 `json:"user_id"`
+	Status         models.MessageStatus `json:"status"`
+	MessageID      uint                 `json:"message_id"`
+}
+
+const (
+	// Um aviso de digitação vale por typingTTL; o cliente que continua
+	// digitando repete o aviso e o servidor repassa no máximo um a cada
+	// typingThrottle por usuário e conversa
+	typingTTL      = 8 * time.Second
+	typingThrottle = 3 * time.Second
+)
+
+// TypingMessage avisa os outros participantes que alguém começou ou parou de
+// digitar. Sem novo aviso em expires_in segundos, o cliente deve considerar
+// que a pessoa parou
+type TypingMessage struct {
+	ConversationID uint `json:"conversation_id"`
+	UserID         uint `json:"user_id"`
+	Typing         bool `json:"typing"`
+	ExpiresIn      int  `json:"expires_in"`
+}
+
+// PresenceMessage avisa os contatos conectados que o usuário entrou ou saiu
+type PresenceMessage struct {
+	UserID     uint       `json:"user_id"`
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+// ParticipantPresence é a presença de um participante da conversa vista por
+// outro. Hidden indica que ela não pode ser mostrada: um dos dois esconde a
+// presença ou há um bloqueio entre eles
+type ParticipantPresence struct {
+	UserID     uint       `json:"user_id"`
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	Hidden     bool       `json:"hidden"`
+}
+
+// TypingRequest é o aviso de digitação; pelo WebSocket vem com o
+// conversation_id, pela API ele vem na rota
+type TypingRequest struct {
+	ConversationID uint  `json:"conversation_id"`
+	Typing         *bool `json:"typing" binding:"required"`
+}
+
+type PresenceSettingsRequest struct {
+	HidePresence *bool `json:"hide_presence" binding:"required"`
+}
+
+type PresenceSettingsResponse struct {
+	HidePresence bool `json:"hide_presence"`
+}
+
+type typingKey struct {
+	conversationID uint
+	userID         uint
+}
+
+// RealtimeService repassa os eventos do barramento às conexões em tempo real:
+// posts novos aos seguidores conectados, o processamento das mídias ao dono e
+// as confirmações de entrega e leitura aos participantes das conversas. Cuida
+// também dos estados passageiros das conversas, a digitação e a presença. As
+// notificações são entregues pelo NotificationService
+type RealtimeService struct {
+	hub              realtime.HubInterface
+	postRepo         repositories.PostRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	conversationRepo repositories.ConversationRepositoryInterface
+
+	typingMu         sync.Mutex
+	typingSent       map[typingKey]time.Time
+	typingLastPruned time.Time
+}
+
+func NewRealtimeService(hub realtime.HubInterface, postRepo repositories.PostRepositoryInterface, userRepo repositories.UserRepositoryInterface, conversationRepo repositories.ConversationRepositoryInterface, eventBus events.BusInterface) *RealtimeService {
+	service := &RealtimeService{
+		hub:              hub,
+		postRepo:         postRepo,
+		userRepo:         userRepo,
+		conversationRepo: conversationRepo,
+		typingSent:       make(map[typingKey]time.Time),
+		typingLastPruned: time.Now(),
+	}
+
+	hub.OnPresenceChange(service.onPresenceChange)
+
+	eventBus.Subscribe(events.PostCreated, service.onPostCreated)
+	eventBus.Subscribe(events.MediaProcessed, service.onMediaProcessed)
+	eventBus.Subscribe(events.MessagesDelivered, service.onMessageReceipt)
+	eventBus.Subscribe(events.MessagesRead, service.onMessageReceipt)
+
+	return service
+}
+
+// Register abre uma conexão do usuário no hub
+func (s *RealtimeService) Register(userID uint) *realtime.Client {
+	return s.hub.Register(userID)
+}
+
+func (s *RealtimeService) Unregister(client *realtime.Client) {
+	s.hub.Unregister(client)
+}
+
+// Replay devolve o que o usuário perdeu desde afterID; false indica que o
+// histórico não cobre o cursor
+func (s *RealtimeService) Replay(userID uint, afterID uint64) ([]realtime.Message, bool) {
+	return s.hub.Replay(userID, afterID)
+}
+
+func (s *RealtimeService) onPostCreated(event events.Event) {
+	online := s.hub.OnlineUsers()
+	if len(online) == 0 {
+		return
+	}
+
+	post, err := s.postRepo.GetByID(event.EntityID, event.ActorID)
+	if err != nil {
+		log.Printf("Falha ao carregar post %d para envio em tempo real: %v", event.EntityID, err)
+		return
+	}
+	// Posts privados não aparecem no feed de ninguém além do autor
+	if post.Visibility == models.PostVisibilityPrivate {
+		return
+	}
+
+	recipients, err := s.userRepo.FilterFollowers(post.AuthorID, online)
+	if err != nil {
+		log.Printf("Falha ao buscar seguidores conectados do usuário %d: %v", post.AuthorID, err)
+		return
+	}
+	// O autor também recebe, para as outras abas e aparelhos abertos
+	recipients = append(recipients, post.AuthorID)
+
+	s.hub.SendToUsers(recipients, realtime.MessageFeedPost, post.ToResponse(0))
+}
+
+func (s *RealtimeService) onMediaProcessed(event events.Event) {
+	s.hub.SendToUser(event.ActorID, realtime.MessageMediaStatus, MediaStatusMessage{
+		MediaID: event.EntityID,
+		Status:  models.MediaProcessingStatus(event.Data["status"]),
+		Error:   event.Data["error"],
+	})
+}
+
+// onMessageReceipt envia a confirmação aos participantes conectados. A
+// leitura vai também ao próprio leitor, para os outros aparelhos dele
+// zerarem o contador
+func (s *RealtimeService) onMessageReceipt(event events.Event) {
+	messageID, err := strconv.ParseUint(event.Data["message_id"], 10, 32)
+	if err != nil {
+		return
+	}
+	conversation, err := s.conversationRepo.GetByID(event.EntityID)
+	if err != nil {
+		return
+	}
+
+	status := models.MessageStatusDelivered
+	if event.Type == events.MessagesRead {
+		status = models.MessageStatusRead
+	}
+
+	recipients := make([]uint, 0, len(conversation.Participants))
+	for _, participant := range conversation.Participants {
+		if participant.UserID == event.ActorID && status == models.MessageStatusDelivered {
+			continue
+		}
+		recipients = append(recipients, participant.UserID)
+	}
+
+	s.hub.SendToUsers(recipients, realtime.MessageReceipt, MessageReceiptMessage{
+		ConversationID: conversation.ID,
+		UserID:         event.ActorID,
+		Status:         status,
+		MessageID:      uint(messageID),
+	})
+}
+
+// Typing repassa aos outros participantes conectados que o usuário está ou
+// não digitando. Nada é gravado: quem não estava conectado não recebe
+func (s *RealtimeService) Typing(conversationID, userID uint, typing bool) error {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || !conversation.HasParticipant(userID) {
+		return errors.New("conversa não encontrada")
+	}
+	if conversation.FrozenAt != nil {
+		return errFrozenConversation
+	}
+	if !s.allowTyping(typingKey{conversationID: conversationID, userID: userID}, typing) {
+		return nil
+	}
+
+	recipients := make([]uint, 0, len(conversation.Participants))
+	for _, participant := range conversation.Participants {
+		if participant.UserID != userID {
+			recipients = append(recipients, participant.UserID)
+		}
+	}
+
+	s.hub.SendEphemeral(recipients, realtime.MessageTyping, TypingMessage{
+		ConversationID: conversationID,
+		UserID:         userID,
+		Typing:         typing,
+		ExpiresIn:      int(typingTTL.Seconds()),
+	})
+	return nil
+}
+
+// allowTyping limita os avisos de que o usuário está digitando; o de que
+// parou sempre passa e libera o próximo
+func (s *RealtimeService) allowTyping(key typingKey, typing bool) bool {
+	s.typingMu.Lock()
+	defer s.typingMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.typingLastPruned) >= typingTTL {
+		s.typingLastPruned = now
+		for k, sentAt := range s.typingSent {
+			if now.Sub(sentAt) >= typingThrottle {
+				delete(s.typingSent, k)
+			}
+		}
+	}
+
+	if !typing {
+		delete(s.typingSent, key)
+		return true
+	}
+	if sentAt, ok := s.typingSent[key]; ok && now.Sub(sentAt) < typingThrottle {
+		return false
+	}
+	s.typingSent[key] = now
+	return true
+}
+
+// GetConversationPresence retorna se os outros participantes estão online e
+// quando foram vistos por último. Quem esconde a própria presença também não
+// vê a dos outros
+func (s *RealtimeService) GetConversationPresence(conversationID, userID uint) ([]ParticipantPresence, error) {
+	conversation, err := s.conversationRepo.GetByID(conversationID)
+	if err != nil || !conversation.HasParticipant(userID) {
+		return nil, errors.New("conversa não encontrada")
+	}
+
+	hideAll := conversation.FrozenAt != nil || conversation.Participant(userID).User.HidePresence
+
+	presence := make([]ParticipantPresence, 0, len(conversation.Participants))
+	for _, participant := range conversation.Participants {
+		if participant.UserID == userID {
+			continue
+		}
+
+		hidden := hideAll || participant.User.HidePresence
+		if !hidden {
+			blocked, err := s.userRepo.HasBlockBetween(userID, participant.UserID)
+			if err != nil {
+				return nil, errors.New("erro ao buscar presença")
+			}
+			hidden = blocked
+		}
+		if hidden {
+			presence = append(presence, ParticipantPresence{UserID: participant.UserID, Hidden: true})
+			continue
+		}
+
+		online := s.hub.IsOnline(participant.UserID)
+		item := ParticipantPresence{UserID: participant.UserID, Online: online}
+		if !online {
+			item.LastSeenAt = participant.User.LastSeenAt
+		}
+		presence = append(presence, item)
+	}
+	return presence, nil
+}
+
+func (s *RealtimeService) GetPresenceSettings(userID uint) (*PresenceSettingsResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	return &PresenceSettingsResponse{HidePresence: user.HidePresence}, nil
+}
+
+// UpdatePresenceSettings liga ou desliga a presença do usuário. Os contatos
+// conectados são avisados na hora: quem passa a esconder aparece como fora
+func (s *RealtimeService) UpdatePresenceSettings(userID uint, hide bool) (*PresenceSettingsResponse, error) {
+	if err := s.userRepo.SetHidePresence(userID, hide); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("usuário não encontrado")
+		}
+		return nil, errors.New("erro ao atualizar presença")
+	}
+
+	if s.hub.IsOnline(userID) {
+		s.broadcastPresence(userID, PresenceMessage{UserID: userID, Online: !hide})
+	}
+	return &PresenceSettingsResponse{HidePresence: hide}, nil
+}
+
+// onPresenceChange é chamado pelo hub quando o usuário abre a primeira
+// conexão ou fecha a última. As chamadas podem chegar fora de ordem, então o
+// estado vem do hub e não da transição
+func (s *RealtimeService) onPresenceChange(userID uint) {
+	message := PresenceMessage{UserID: userID, Online: s.hub.IsOnline(userID)}
+	if !message.Online {
+		now := time.Now()
+		if err := s.userRepo.UpdateLastSeen(userID, now); err != nil {
+			log.Printf("Falha ao registrar a última conexão do usuário %d: %v", userID, err)
+		}
+		message.LastSeenAt = &now
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.HidePresence {
+		return
+	}
+	s.broadcastPresence(userID, message)
+}
+
+func (s *RealtimeService) broadcastPresence(userID uint, message PresenceMessage) {
+	contacts, err := s.conversationRepo.FilterPresenceContacts(userID, s.hub.OnlineUsers())
+	if err != nil {
+		log.Printf("Falha ao buscar contatos conectados do usuário %d: %v", userID, err)
+		return
+	}
+	if len(contacts) == 0 {
+		return
+	}
+	s.hub.SendEphemeral(contacts, realtime.MessagePresence, message)
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	// Recomendações guardadas por tipo a cada cálculo
+	recommendationsPerKind = 50
+	// Candidatos buscados por sinal antes de combinar
+	recommendationCandidates = 100
+	// Usuários parecidos considerados na co-visitação
+	recommendationNeighbors = 100
+	// Posts mais antigos que isso não são recomendados
+	recommendationPostWindow = 30 * 24 * time.Hour
+	// Roteiros de quem o usuário segue entram se publicados nesse período
+	recommendationFollowingWindow = 90 * 24 * time.Hour
+	// Hora (UTC) a partir da qual as recomendações do dia são recalculadas;
+	// 3h UTC é meia-noite em Brasília
+	recommendationRefreshHour = 3
+	recommendationBatchSize   = 200
+)
+
+// Peso de cada sinal na pontuação final. A pontuação de cada sinal é
+// normalizada pela maior dele antes de aplicar o peso
+var recommendationWeights = map[models.RecommendationReason]float64{
+	models.RecommendationReasonSimilarUsers: 1.0,
+	models.RecommendationReasonFollowing:    0.8,
+	models.RecommendationReasonInterests:    0.6,
+	models.RecommendationReasonPopular:      0.2,
+}
+
+type RecommendationServiceInterface interface {
+	GetRecommendations(userID uint, limit int) (*models.RecommendationsResponse, error)
+	GenerateRecommendations(userID uint) error
+	RefreshRecommendations(now time.Time) (int, error)
+	StartRecommendationScheduler(interval time.Duration)
+}
+
+type RecommendationService struct {
+	recommendationRepo repositories.RecommendationRepositoryInterface
+	postService        PostServiceInterface
+	itineraryService   ItineraryServiceInterface
+}
+
+func NewRecommendationService(recommendationRepo repositories.RecommendationRepositoryInterface, postService PostServiceInterface, itineraryService ItineraryServiceInterface) RecommendationServiceInterface {
+	return &RecommendationService{
+		recommendationRepo: recommendationRepo,
+		postService:        postService,
+		itineraryService:   itineraryService,
+	}
+}
+
+// recommendationSource é um dos sinais que sugerem itens ao usuário
+type recommendationSource struct {
+	reason models.RecommendationReason
+	fetch  func() ([]repositories.RecommendationCandidate, error)
+}
+
+// GetRecommendations retorna os roteiros e posts pré-calculados para o
+// usuário. Quem ainda não tem recomendações (conta nova) as recebe calculadas
+// na hora. Itens removidos ou curtidos desde o cálculo são omitidos
+func (s *RecommendationService) GetRecommendations(userID uint, limit int) (*models.RecommendationsResponse, error) {
+	if limit <= 0 || limit > recommendationsPerKind {
+		limit = 20
+	}
+
+	itineraryRecs, err := s.recommendationRepo.GetByUser(userID, models.RecommendationKindItinerary, recommendationsPerKind)
+	if err != nil {
+		return nil, errors.New("erro ao buscar recomendações")
+	}
+	postRecs, err := s.recommendationRepo.GetByUser(userID, models.RecommendationKindPost, recommendationsPerKind)
+	if err != nil {
+		return nil, errors.New("erro ao buscar recomendações")
+	}
+
+	if len(itineraryRecs) == 0 && len(postRecs) == 0 {
+		if err := s.GenerateRecommendations(userID); err != nil {
+			return nil, err
+		}
+		if itineraryRecs, err = s.recommendationRepo.GetByUser(userID, models.RecommendationKindItinerary, recommendationsPerKind); err != nil {
+			return nil, errors.New("erro ao buscar recomendações")
+		}
+		if postRecs, err = s.recommendationRepo.GetByUser(userID, models.RecommendationKindPost, recommendationsPerKind); err != nil {
+			return nil, errors.New("erro ao buscar recomendações")
+		}
+	}
+
+	response := &models.RecommendationsResponse{
+		Itineraries: []models.ItineraryResponse{},
+		Posts:       []models.PostResponse{},
+	}
+	for _, recs := range [][]models.Recommendation{itineraryRecs, postRecs} {
+		if len(recs) > 0 && (response.GeneratedAt == nil || recs[0].CreatedAt.Before(*response.GeneratedAt)) {
+			generatedAt := recs[0].CreatedAt
+			response.GeneratedAt = &generatedAt
+		}
+	}
+
+	ids, reasons := recommendationItems(itineraryRecs)
+	itineraries, err := s.itineraryService.GetItinerariesByIDs(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, itinerary := range itineraries {
+		if itinerary.IsLiked || itinerary.IsSaved {
+			continue
+		}
+		itinerary.RecommendationReason = reasons[itinerary.ID]
+		response.Itineraries = append(response.Itineraries, itinerary)
+		if len(response.Itineraries) == limit {
+			break
+		}
+	}
+
+	ids, reasons = recommendationItems(postRecs)
+	posts, err := s.postService.GetPostsByIDs(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		if post.IsLiked {
+			continue
+		}
+		post.RecommendationReason = reasons[post.ID]
+		response.Posts = append(response.Posts, post)
+		if len(response.Posts) == limit {
+			break
+		}
+	}
+
+	return response, nil
+}
+
+// GenerateRecommendations calcula e substitui as recomendações do usuário,
+// combinando a co-visitação (quem curte o mesmo que ele), quem ele segue, os
+// interesses (categorias e destinos com que interage) e, com peso baixo, o
+// que está em alta
+func (s *RecommendationService) GenerateRecommendations(userID uint) error {
+	now := time.Now()
+	postsSince := now.Add(-recommendationPostWindow)
+
+	itineraries, err := combineRecommendations([]recommendationSource{
+		{models.RecommendationReasonSimilarUsers, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.SimilarUsersItineraries(userID, recommendationNeighbors, recommendationCandidates)
+		}},
+		{models.RecommendationReasonFollowing, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.FollowingItineraries(userID, now.Add(-recommendationFollowingWindow), recommendationCandidates)
+		}},
+		{models.RecommendationReasonInterests, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.InterestItineraries(userID, recommendationCandidates)
+		}},
+		{models.RecommendationReasonPopular, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.PopularItineraries(userID, recommendationsPerKind)
+		}},
+	})
+	if err != nil {
+		return errors.New("erro ao calcular recomendações de roteiros")
+	}
+
+	posts, err := combineRecommendations([]recommendationSource{
+		{models.RecommendationReasonSimilarUsers, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.SimilarUsersPosts(userID, postsSince, recommendationNeighbors, recommendationCandidates)
+		}},
+		{models.RecommendationReasonFollowing, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.FollowingPosts(userID, postsSince, recommendationCandidates)
+		}},
+		{models.RecommendationReasonInterests, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.InterestPosts(userID, postsSince, recommendationCandidates)
+		}},
+		{models.RecommendationReasonPopular, func() ([]repositories.RecommendationCandidate, error) {
+			return s.recommendationRepo.PopularPosts(userID, postsSince, recommendationsPerKind)
+		}},
+	})
+	if err != nil {
+		return errors.New("erro ao calcular recomendações de posts")
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(itineraries)+len(posts))
+	for _, rec := range itineraries {
+		rec.UserID = userID
+		rec.Kind = models.RecommendationKindItinerary
+		rec.CreatedAt = now
+		recommendations = append(recommendations, rec)
+	}
+	for _, rec := range posts {
+		rec.UserID = userID
+		rec.Kind = models.RecommendationKindPost
+		rec.CreatedAt = now
+		recommendations = append(recommendations, rec)
+	}
+
+	if err := s.recommendationRepo.Replace(userID, recommendations); err != nil {
+		return errors.New("erro ao salvar recomendações")
+	}
+	return nil
+}
+
+// RefreshRecommendations recalcula as recomendações dos usuários ativos que
+// ainda não as têm desde o último horário de atualização (3h UTC)
+func (s *RecommendationService) RefreshRecommendations(now time.Time) (int, error) {
+	cutoff := recommendationCutoff(now)
+	if now.Before(cutoff) {
+		return 0, nil
+	}
+
+	generated := 0
+	var lastID uint
+	for {
+		userIDs, err := s.recommendationRepo.GetUsersPendingRecommendations(cutoff, lastID, recommendationBatchSize)
+		if err != nil {
+			return generated, errors.New("erro ao buscar usuários para recomendações")
+		}
+		if len(userIDs) == 0 {
+			return generated, nil
+		}
+
+		for _, userID := range userIDs {
+			lastID = userID
+			if err := s.GenerateRecommendations(userID); err != nil {
+				log.Printf("Falha ao calcular recomendações do usuário %d: %v", userID, err)
+				continue
+			}
+			generated++
+		}
+	}
+}
+
+// StartRecommendationScheduler verifica periodicamente se é hora de
+// recalcular as recomendações; o cálculo roda uma vez por noite
+func (s *RecommendationService) StartRecommendationScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if generated, err := s.RefreshRecommendations(time.Now()); err != nil {
+				log.Println("Falha ao recalcular recomendações:", err)
+			} else if generated > 0 {
+				log.Printf("Recomendações recalculadas para %d usuários", generated)
+			}
+		}
+	}()
+}
+
+// recommendationCutoff é o horário de atualização mais recente até now
+func recommendationCutoff(now time.Time) time.Time {
+	now = now.UTC()
+	cutoff := time.Date(now.Year(), now.Month(), now.Day(), recommendationRefreshHour, 0, 0, 0, time.UTC)
+	if now.Before(cutoff) {
+		cutoff = cutoff.AddDate(0, 0, -1)
+	}
+	return cutoff
+}
+
+// combineRecommendations soma a pontuação normalizada e ponderada de cada
+// sinal e fica com os itens mais bem pontuados. O motivo de cada item é o
+// sinal que mais contribuiu
+func combineRecommendations(sources []recommendationSource) ([]models.Recommendation, error) {
+	type scored struct {
+		itemID uint
+		score  float64
+		best   float64
+		reason models.RecommendationReason
+	}
+
+	var items []*scored
+	byItem := make(map[uint]*scored)
+	for _, source := range sources {
+		candidates, err := source.fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		var maxScore float64
+		for _, candidate := range candidates {
+			maxScore = max(maxScore, candidate.Score)
+		}
+		if maxScore <= 0 {
+			continue
+		}
+
+		weight := recommendationWeights[source.reason]
+		for _, candidate := range candidates {
+			contribution := weight * candidate.Score / maxScore
+			item, ok := byItem[candidate.ItemID]
+			if !ok {
+				item = &scored{itemID: candidate.ItemID}
+				byItem[candidate.ItemID] = item
+				items = append(items, item)
+			}
+			item.score += contribution
+			if contribution > item.best {
+				item.best = contribution
+				item.reason = source.reason
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].score > items[j].score
+	})
+	if len(items) > recommendationsPerKind {
+		items = items[:recommendationsPerKind]
+	}
+
+	recommendations := make([]models.Recommendation, len(items))
+	for i, item := range items {
+		recommendations[i] = models.Recommendation{
+			ItemID: item.itemID,
+			Score:  item.score,
+			Reason: item.reason,
+		}
+	}
+	return recommendations, nil
+}
+
+func recommendationItems(recommendations []models.Recommendation) ([]uint, map[uint]models.RecommendationReason) {
+	ids := make([]uint, len(recommendations))
+	reasons := make(map[uint]models.RecommendationReason, len(recommendations))
+	for i, rec := range recommendations {
+		ids[i] = rec.ItemID
+		reasons[rec.ItemID] = rec.Reason
+	}
+	return ids, reasons
+}
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// Filtro de atribuição da fila de casos, além do ID de um admin
+const (
+	ReportCaseAssignedToMe = "me"
+	ReportCaseUnassigned   = "none"
+)
+
+// Ordenação da fila de casos
+const (
+	ReportCaseSortOldest  = "oldest"
+	ReportCaseSortReports = "reports"
+)
+
+type ReportRequest struct {
+	Reason  models.ReportReason `json:"reason" binding:"required"`
+	Details string              `json:"details"`
+}
+
+type ReportResult struct {
+	Report *models.ReportResponse `json:"report"`
+	Hidden bool                   `json:"hidden"` // o conteúdo foi ocultado por esta denúncia
+}
+
+type ReportCaseQuery struct {
+	Status     models.ReportStatus
+	TargetType models.ReportTargetType
+	Assigned   string // me, none ou o ID de um admin
+	Sort       string // oldest ou reports
+	Flagged    bool   // só os casos sinalizados automaticamente
+}
+
+// ReportHideThresholds é o número de denúncias pendentes que oculta cada tipo
+// de conteúdo automaticamente (0 desativa); usuários denunciados nunca são
+// bloqueados automaticamente
+type ReportHideThresholds struct {
+	Post      int
+	Comment   int
+	Itinerary int
+	Rating    int
+}
+
+type ReportServiceInterface interface {
+	Report(targetType models.ReportTargetType, targetID, reporterID uint, req *ReportRequest) (*ReportResult, error)
+	ReportRating(itineraryID, ratingID, reporterID uint, req *ReportRequest) (*ReportResult, error)
+	GetCases(adminID uint, query *ReportCaseQuery, limit, offset int) ([]models.ReportCaseResponse, error)
+	GetCase(caseID, adminID uint) (*models.ReportCaseResponse, error)
+	AssignCase(caseID, adminID uint) (*models.ReportCaseResponse, error)
+	UnassignCase(caseID, adminID uint) (*models.ReportCaseResponse, error)
+	ResolveCase(caseID, adminID uint, req *ResolveReportRequest) (*models.ReportCaseResponse, error)
+}
+
+type ReportService struct {
+	reportRepo     repositories.ReportRepositoryInterface
+	moderationRepo repositories.ModerationRepositoryInterface
+	postRepo       repositories.PostRepositoryInterface
+	commentRepo    repositories.CommentRepositoryInterface
+	itineraryRepo  repositories.ItineraryRepositoryInterface
+	ratingRepo     repositories.RatingRepositoryInterface
+	userRepo       repositories.UserRepositoryInterface
+	thresholds     ReportHideThresholds
+}
+
+func NewReportService(
+	reportRepo repositories.ReportRepositoryInterface,
+	moderationRepo repositories.ModerationRepositoryInterface,
+	postRepo repositories.PostRepositoryInterface,
+	commentRepo repositories.CommentRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	ratingRepo repositories.RatingRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	thresholds ReportHideThresholds,
+) ReportServiceInterface {
+	return &ReportService{
+		reportRepo:     reportRepo,
+		moderationRepo: moderationRepo,
+		postRepo:       postRepo,
+		commentRepo:    commentRepo,
+		itineraryRepo:  itineraryRepo,
+		ratingRepo:     ratingRepo,
+		userRepo:       userRepo,
+		thresholds:     thresholds,
+	}
+}
+
+// Report denuncia um usuário, post, comentário, roteiro ou avaliação visível
+// para quem denuncia. A denúncia entra no caso pendente do alvo, e o conteúdo é
+// ocultado quando o caso atinge o limite de denúncias do tipo
+func (s *ReportService) Report(targetType models.ReportTargetType, targetID, reporterID uint, req *ReportRequest) (*ReportResult, error) {
+	if err := validateReport(req.Reason, req.Details); err != nil {
+		return nil, err
+	}
+
+	var ownerID uint
+	var hideThreshold int
+	var label string
+	switch targetType {
+	case models.ReportTargetUser:
+		user, err := s.userRepo.GetByID(targetID)
+		if err != nil || !user.IsActive {
+			return nil, errors.New("usuário não encontrado")
+		}
+		if user.ID == reporterID {
+			return nil, errors.New("você não pode denunciar a si mesmo")
+		}
+		ownerID, label = user.ID, "este usuário"
+
+	case models.ReportTargetPost:
+		// Só é possível denunciar posts visíveis para o usuário
+		post, err := s.postRepo.GetByID(targetID, reporterID)
+		if err != nil {
+			return nil, errors.New("post não encontrado")
+		}
+		if post.AuthorID == reporterID {
+			return nil, errors.New("você não pode denunciar seu próprio post")
+		}
+		ownerID, hideThreshold, label = post.AuthorID, s.thresholds.Post, "este post"
+
+	case models.ReportTargetComment:
+		comment, err := s.commentRepo.GetByID(targetID)
+		if err != nil || comment.HiddenAt != nil {
+			return nil, errors.New("comentário não encontrado")
+		}
+		if _, err := s.postRepo.GetByID(comment.PostID, reporterID); err != nil {
+			return nil, errors.New("comentário não encontrado")
+		}
+		if comment.AuthorID == reporterID {
+			return nil, errors.New("você não pode denunciar seu próprio comentário")
+		}
+		ownerID, hideThreshold, label = comment.AuthorID, s.thresholds.Comment, "este comentário"
+
+	case models.ReportTargetItinerary:
+		// Só é possível denunciar roteiros visíveis para o usuário
+		itinerary, err := s.itineraryRepo.GetByID(targetID)
+		if err != nil || !itinerary.IsPublic {
+			return nil, errors.New("roteiro não encontrado")
+		}
+		if itinerary.AuthorID == reporterID {
+			return nil, errors.New("você não pode denunciar seu próprio roteiro")
+		}
+		ownerID, hideThreshold, label = itinerary.AuthorID, s.thresholds.Itinerary, "este roteiro"
+
+	case models.ReportTargetRating:
+		rating, err := s.ratingRepo.GetByID(targetID)
+		if err != nil || rating.HiddenAt != nil {
+			return nil, errors.New("avaliação não encontrada")
+		}
+		if rating.UserID == reporterID {
+			return nil, errors.New("você não pode denunciar sua própria avaliação")
+		}
+		ownerID, hideThreshold, label = rating.UserID, s.thresholds.Rating, "esta avaliação"
+
+	default:
+		return nil, errors.New("tipo de denúncia inválido")
+	}
+
+	report := &models.Report{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Details:    strings.TrimSpace(req.Details),
+	}
+
+	_, hidden, err := s.reportRepo.Create(report, ownerID, hideThreshold)
+	if errors.Is(err, repositories.ErrAlreadyReported) {
+		return nil, errors.New("você já denunciou " + label)
+	}
+	if err != nil {
+		return nil, errors.New("erro ao registrar denúncia")
+	}
+
+	return &ReportResult{
+		Report: report.ToResponse(),
+		Hidden: hidden,
+	}, nil
+}
+
+// ReportRating denuncia uma avaliação de um roteiro público
+func (s *ReportService) ReportRating(itineraryID, ratingID, reporterID uint, req *ReportRequest) (*ReportResult, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || !itinerary.IsPublic {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	rating, err := s.ratingRepo.GetByID(ratingID)
+	if err != nil || rating.ItineraryID != itineraryID {
+		return nil, errors.New("avaliação não encontrada")
+	}
+
+	return s.Report(models.ReportTargetRating, ratingID, reporterID, req)
+}
+
+// GetCases lista a fila de casos de denúncia; por padrão os pendentes, dos
+// mais antigos para os mais recentes
+func (s *ReportService) GetCases(adminID uint, query *ReportCaseQuery, limit, offset int) ([]models.ReportCaseResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	filter := repositories.ReportCaseFilter{
+		Status:     query.Status,
+		TargetType: query.TargetType,
+		Flagged:    query.Flagged,
+	}
+	switch filter.Status {
+	case "":
+		filter.Status = models.ReportStatusPending
+	case models.ReportStatusPending, models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		return nil, errors.New("status deve ser 'pending', 'resolved' ou 'dismissed'")
+	}
+	if filter.TargetType != "" && !isReportTargetType(filter.TargetType) {
+		return nil, errors.New("tipo de alvo inválido")
+	}
+	switch query.Assigned {
+	case "":
+	case ReportCaseAssignedToMe:
+		filter.AssignedToID = adminID
+	case ReportCaseUnassigned:
+		filter.Unassigned = true
+	default:
+		assigneeID, err := strconv.ParseUint(query.Assigned, 10, 32)
+		if err != nil || assigneeID == 0 {
+			return nil, errors.New("atribuição deve ser 'me', 'none' ou o ID de um admin")
+		}
+		filter.AssignedToID = uint(assigneeID)
+	}
+	switch query.Sort {
+	case "", ReportCaseSortOldest:
+	case ReportCaseSortReports:
+		filter.MostReported = true
+	default:
+		return nil, errors.New("ordenação deve ser 'oldest' ou 'reports'")
+	}
+
+	cases, err := s.reportRepo.GetCases(filter, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar denúncias")
+	}
+
+	responses := make([]models.ReportCaseResponse, 0, len(cases))
+	for _, reportCase := range cases {
+		responses = append(responses, *reportCase.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetCase abre o caso com todas as denúncias e o conteúdo denunciado, mesmo
+// que esteja oculto
+func (s *ReportService) GetCase(caseID, adminID uint) (*models.ReportCaseResponse, error) {
+	reportCase, err := s.reportRepo.GetCaseByID(caseID)
+	if err != nil {
+		return nil, errors.New("denúncia não encontrada")
+	}
+
+	response := reportCase.ToResponse()
+	response.Target = s.reportTarget(reportCase, adminID)
+	response.Reports = make([]models.ReportResponse, 0, len(reportCase.Reports))
+	for _, report := range reportCase.Reports {
+		response.Reports = append(response.Reports, *report.ToResponse())
+	}
+	return response, nil
+}
+
+// AssignCase atribui o caso pendente ao admin, inclusive se estava com
+// outro
+func (s *ReportService) AssignCase(caseID, adminID uint) (*models.ReportCaseResponse, error) {
+	reportCase, err := s.reportRepo.GetCaseByID(caseID)
+	if err != nil {
+		return nil, errors.New("denúncia não encontrada")
+	}
+
+	assigned, err := s.reportRepo.AssignCase(reportCase, &adminID)
+	if err != nil {
+		return nil, errors.New("erro ao atribuir denúncia")
+	}
+	if !assigned {
+		return nil, errors.New("denúncia já resolvida")
+	}
+
+	return s.GetCase(caseID, adminID)
+}
+
+// UnassignCase devolve o caso pendente à fila sem responsável
+func (s *ReportService) UnassignCase(caseID, adminID uint) (*models.ReportCaseResponse, error) {
+	reportCase, err := s.reportRepo.GetCaseByID(caseID)
+	if err != nil {
+		return nil, errors.New("denúncia não encontrada")
+	}
+
+	unassigned, err := s.reportRepo.AssignCase(reportCase, nil)
+	if err != nil {
+		return nil, errors.New("erro ao liberar denúncia")
+	}
+	if !unassigned {
+		return nil, errors.New("denúncia já resolvida")
+	}
+
+	return s.GetCase(caseID, adminID)
+}
+
+// ResolveCase decide o caso: improcedente (dismissed) volta a exibir o
+// conteúdo ocultado pelas denúncias; procedente (resolved) o mantém oculto
+func (s *ReportService) ResolveCase(caseID, adminID uint, req *ResolveReportRequest) (*models.ReportCaseResponse, error) {
+	if req.Status != models.ReportStatusResolved && req.Status != models.ReportStatusDismissed {
+		return nil, errors.New("status deve ser 'resolved' ou 'dismissed'")
+	}
+
+	reportCase, err := s.reportRepo.GetCaseByID(caseID)
+	if err != nil {
+		return nil, errors.New("denúncia não encontrada")
+	}
+	if reportCase.Status != models.ReportStatusPending {
+		return nil, errors.New("denúncia já resolvida")
+	}
+
+	reportCase.ResolvedByID = &adminID
+	reportCase.ResolutionNote = strings.TrimSpace(req.Note)
+
+	action := models.ModerationActionResolveReport
+	if req.Status == models.ReportStatusDismissed {
+		action = models.ModerationActionDismissReport
+	}
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: models.ModerationTargetReportCase,
+		TargetID:   reportCase.ID,
+		Note:       reportCase.ResolutionNote,
+	}
+
+	resolved, err := s.reportRepo.ResolveCase(reportCase, req.Status, audit)
+	if err != nil {
+		return nil, errors.New("erro ao resolver denúncia")
+	}
+	if !resolved {
+		return nil, errors.New("denúncia já resolvida")
+	}
+
+	return s.GetCase(caseID, adminID)
+}
+
+// reportTarget carrega o conteúdo denunciado como a moderação o vê; usuários
+// denunciados já vêm no dono do caso
+func (s *ReportService) reportTarget(reportCase *models.ReportCase, adminID uint) *models.ReportTarget {
+	target := &models.ReportTarget{}
+	switch reportCase.TargetType {
+	case models.ReportTargetUser:
+		return nil
+
+	case models.ReportTargetPost:
+		post, err := s.moderationRepo.GetPostForModeration(reportCase.TargetID)
+		if err != nil || post.DeletedAt.Valid {
+			target.Removed = true
+			return target
+		}
+		target.Post = post.ToResponse(adminID)
+		target.Hidden = !post.IsActive
+
+	case models.ReportTargetComment:
+		comment, err := s.moderationRepo.GetCommentForModeration(reportCase.TargetID)
+		if err != nil || comment.DeletedAt.Valid {
+			target.Removed = true
+			return target
+		}
+		target.Comment = comment.ToResponse()
+		target.Hidden = comment.HiddenAt != nil
+
+	case models.ReportTargetItinerary:
+		itinerary, err := s.moderationRepo.GetItineraryForModeration(reportCase.TargetID)
+		if err != nil || itinerary.DeletedAt.Valid {
+			target.Removed = true
+			return target
+		}
+		target.Itinerary = itinerary.ToResponse()
+		target.Hidden = itinerary.HiddenAt != nil
+
+	case models.ReportTargetRating:
+		rating, err := s.moderationRepo.GetRatingForModeration(reportCase.TargetID)
+		if err != nil {
+			target.Removed = true
+			return target
+		}
+		target.Rating = rating.ToResponse()
+		target.Hidden = rating.HiddenAt != nil
+	}
+	return target
+}
+
+func isReportTargetType(targetType models.ReportTargetType) bool {
+	for _, valid := range models.ReportTargetTypes {
+		if targetType == valid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type RequestLogServiceInterface interface {
+	Record(entry *models.RequestLog)
+	GetByTraceID(traceID string) ([]models.RequestLog, error)
+	StartRequestLogWriter()
+	StartRequestLogCleanupScheduler(interval time.Duration)
+}
+
+const (
+	requestLogQueueSize     = 1000
+	requestLogBatchSize     = 100
+	requestLogFlushInterval = 2 * time.Second
+)
+
+type RequestLogService struct {
+	requestLogRepo repositories.RequestLogRepositoryInterface
+	retention      time.Duration
+	entries        chan models.RequestLog
+}
+
+// NewRequestLogService cria o serviço de registros de requisição; retention é
+// por quanto tempo os registros ficam disponíveis para o suporte
+func NewRequestLogService(requestLogRepo repositories.RequestLogRepositoryInterface, retention time.Duration) RequestLogServiceInterface {
+	return &RequestLogService{
+		requestLogRepo: requestLogRepo,
+		retention:      retention,
+		entries:        make(chan models.RequestLog, requestLogQueueSize),
+	}
+}
+
+// Record enfileira o registro sem bloquear a requisição; com a fila cheia o
+// registro é descartado
+func (s *RequestLogService) Record(entry *models.RequestLog) {
+	entry.CreatedAt = time.Now()
+	select {
+	case s.entries <- *entry:
+	default:
+		log.Printf("Fila de registros de requisição cheia, trace %s descartado", entry.TraceID)
+	}
+}
+
+func (s *RequestLogService) GetByTraceID(traceID string) ([]models.RequestLog, error) {
+	traceID = strings.TrimSpace(traceID)
+	if traceID == "" {
+		return nil, errors.New("trace ID é obrigatório")
+	}
+
+	logs, err := s.requestLogRepo.GetByTraceID(traceID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar registros da requisição")
+	}
+	if len(logs) == 0 {
+		return nil, errors.New("nenhum registro encontrado para o trace ID")
+	}
+
+	return logs, nil
+}
+
+// StartRequestLogWriter grava os registros enfileirados em lotes, em segundo
+// plano
+func (s *RequestLogService) StartRequestLogWriter() {
+	go func() {
+		ticker := time.NewTicker(requestLogFlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]models.RequestLog, 0, requestLogBatchSize)
+		flush := func() {
+			if err := s.requestLogRepo.CreateBatch(batch); err != nil {
+				log.Println("Falha ao gravar registros de requisição:", err)
+			}
+			batch = batch[:0]
+		}
+
+		for {
+			select {
+			case entry := <-s.entries:
+				batch = append(batch, entry)
+				if len(batch) >= requestLogBatchSize {
+					flush()
+				}
+			case <-ticker.C:
+				flush()
+			}
+		}
+	}()
+}
+
+// StartRequestLogCleanupScheduler apaga periodicamente os registros mais
+// antigos que o período de retenção
+func (s *RequestLogService) StartRequestLogCleanupScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deleted, err := s.requestLogRepo.DeleteOlderThan(time.Now().Add(-s.retention))
+			if err != nil {
+				log.Println("Falha ao limpar registros de requisição:", err)
+			} else if deleted > 0 {
+				log.Printf("%d registros de requisição expirados removidos", deleted)
+			}
+		}
+	}()
+}
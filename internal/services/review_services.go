@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const maxLocationReviewLength = 1000
+
+// PlaceReviews é uma página de avaliações de um ponto, somando as feitas em
+// todos os roteiros que passam por ele
+type PlaceReviews struct {
+	PlaceID          string                           `json:"place_id,omitempty"`
+	LocationID       *uint                            `json:"location_id,omitempty"` // só para locais sem lugar no provedor
+	AverageRating    float64                          `json:"average_rating"`
+	ReviewsCount     int64                            `json:"reviews_count"`
+	ItinerariesCount int64                            `json:"itineraries_count"`
+	Distribution     []RatingBucket                   `json:"distribution"`
+	ViewerReview     *models.LocationReviewResponse   `json:"viewer_review,omitempty"`
+	Reviews          []*models.LocationReviewResponse `json:"reviews"`
+}
+
+type ReviewServiceInterface interface {
+	ReviewLocation(itineraryID, locationID, userID uint, rating int, comment string) (*models.LocationReviewResponse, error)
+	DeleteLocationReview(itineraryID, locationID, userID uint) error
+	GetLocationReviews(itineraryID, locationID, userID uint, sort string, limit, offset int) (*PlaceReviews, error)
+	GetPlaceReviews(placeID string, userID uint, sort string, limit, offset int) (*PlaceReviews, error)
+}
+
+type ReviewService struct {
+	reviewRepo    repositories.ReviewRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+}
+
+func NewReviewService(reviewRepo repositories.ReviewRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface) ReviewServiceInterface {
+	return &ReviewService{
+		reviewRepo:    reviewRepo,
+		itineraryRepo: itineraryRepo,
+	}
+}
+
+// ReviewLocation cria ou edita a avaliação do usuário para o local. Se o
+// usuário já avaliou o mesmo lugar em outro roteiro, a avaliação é substituída
+func (s *ReviewService) ReviewLocation(itineraryID, locationID, userID uint, rating int, comment string) (*models.LocationReviewResponse, error) {
+	location, err := s.visibleLocation(itineraryID, locationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("avaliação deve ser entre 1 e 5")
+	}
+
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > maxLocationReviewLength {
+		return nil, fmt.Errorf("comentário deve ter no máximo %d caracteres", maxLocationReviewLength)
+	}
+
+	placeKey := locationPlaceKey(location)
+	review := &models.LocationReview{
+		UserID:      userID,
+		PlaceKey:    placeKey,
+		PlaceID:     strings.TrimSpace(location.GooglePlaceID),
+		LocationID:  location.ID,
+		ItineraryID: itineraryID,
+		Rating:      rating,
+		Comment:     comment,
+	}
+	if err := s.reviewRepo.Save(review); err != nil {
+		return nil, errors.New("erro ao salvar avaliação")
+	}
+
+	saved, err := s.reviewRepo.GetUserReview(userID, placeKey)
+	if err != nil {
+		return nil, errors.New("erro ao salvar avaliação")
+	}
+	return saved.ToResponse(), nil
+}
+
+func (s *ReviewService) DeleteLocationReview(itineraryID, locationID, userID uint) error {
+	location, err := s.visibleLocation(itineraryID, locationID, userID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.reviewRepo.Delete(userID, locationPlaceKey(location))
+	if err != nil {
+		return errors.New("erro ao remover avaliação")
+	}
+	if !removed {
+		return errors.New("avaliação não encontrada")
+	}
+	return nil
+}
+
+// GetLocationReviews lista as avaliações do ponto a que o local se refere,
+// incluindo as feitas em outros roteiros
+func (s *ReviewService) GetLocationReviews(itineraryID, locationID, userID uint, sort string, limit, offset int) (*PlaceReviews, error) {
+	location, err := s.visibleLocation(itineraryID, locationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.placeReviews(locationPlaceKey(location), userID, sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result.PlaceID = strings.TrimSpace(location.GooglePlaceID)
+	if result.PlaceID == "" {
+		result.LocationID = &location.ID
+	}
+	return result, nil
+}
+
+// GetPlaceReviews lista as avaliações de um lugar do provedor em todos os
+// roteiros
+func (s *ReviewService) GetPlaceReviews(placeID string, userID uint, sort string, limit, offset int) (*PlaceReviews, error) {
+	placeID = strings.TrimSpace(placeID)
+	if placeID == "" {
+		return nil, errors.New("ID do lugar é obrigatório")
+	}
+
+	result, err := s.placeReviews("place:"+placeID, userID, sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result.PlaceID = placeID
+	return result, nil
+}
+
+func (s *ReviewService) placeReviews(placeKey string, userID uint, sort string, limit, offset int) (*PlaceReviews, error) {
+	switch sort {
+	case "", RatingSortRecent:
+		sort = ""
+	case RatingSortHighest, RatingSortLowest:
+	default:
+		return nil, errors.New("ordenação inválida: use recent, highest ou lowest")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	distribution, err := s.reviewRepo.GetDistribution(placeKey)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações")
+	}
+
+	itineraries, err := s.reviewRepo.CountItineraries(placeKey)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações")
+	}
+
+	reviews, err := s.reviewRepo.GetByPlace(placeKey, userID, sort, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações")
+	}
+
+	result := &PlaceReviews{
+		ItinerariesCount: itineraries,
+		Reviews:          make([]*models.LocationReviewResponse, 0, len(reviews)),
+	}
+	result.AverageRating, result.ReviewsCount, result.Distribution = summarizeRatings(distribution)
+
+	for i := range reviews {
+		result.Reviews = append(result.Reviews, reviews[i].ToResponse())
+	}
+
+	if review, err := s.reviewRepo.GetUserReview(userID, placeKey); err == nil {
+		result.ViewerReview = review.ToResponse()
+	}
+
+	return result, nil
+}
+
+// visibleLocation carrega o local de um roteiro que o usuário pode ver
+func (s *ReviewService) visibleLocation(itineraryID, locationID, userID uint) (*models.ItineraryLocation, error) {
+	location, err := s.itineraryRepo.GetLocationByID(locationID)
+	if err != nil || location.Day.ItineraryID != itineraryID {
+		return nil, errors.New("local não encontrado")
+	}
+
+	itinerary := location.Day.Itinerary
+	if itinerary.ID == 0 || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	return location, nil
+}
+
+// locationPlaceKey agrupa as avaliações pelo lugar do provedor quando o local
+// tem um, ou pelo próprio local
+func locationPlaceKey(location *models.ItineraryLocation) string {
+	if placeID := strings.TrimSpace(location.GooglePlaceID); placeID != "" {
+		return "place:" + placeID
+	}
+	return fmt.Sprintf("location:%d", location.ID)
+}
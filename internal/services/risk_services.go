@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"log"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type RiskServiceInterface interface {
+	Assess(assessment *models.RiskAssessment, action models.RiskAction, userID *uint) bool
+	LinkUser(assessment *models.RiskAssessment, userID uint)
+	GetAssessments(action models.RiskAction, minScore, limit, offset int) ([]models.RiskAssessment, error)
+}
+
+type RiskService struct {
+	riskRepo       repositories.RiskRepositoryInterface
+	blockThreshold int
+}
+
+// NewRiskService cria o serviço de risco de automação; blockThreshold é a
+// pontuação a partir da qual cadastros e publicações são recusados (0 apenas
+// registra)
+func NewRiskService(riskRepo repositories.RiskRepositoryInterface, blockThreshold int) RiskServiceInterface {
+	return &RiskService{
+		riskRepo:       riskRepo,
+		blockThreshold: blockThreshold,
+	}
+}
+
+// Assess decide se a ação deve ser bloqueada e grava a avaliação quando há
+// algum sinal de automação; retorna true para bloquear
+func (s *RiskService) Assess(assessment *models.RiskAssessment, action models.RiskAction, userID *uint) bool {
+	if assessment == nil || assessment.Score == 0 {
+		return false
+	}
+
+	assessment.Action = action
+	assessment.UserID = userID
+	assessment.Blocked = s.blockThreshold > 0 && assessment.Score >= s.blockThreshold
+
+	// A falha ao registrar não impede a decisão
+	if err := s.riskRepo.Create(assessment); err != nil {
+		log.Printf("Falha ao registrar avaliação de risco (%s): %v", action, err)
+	}
+
+	return assessment.Blocked
+}
+
+// LinkUser vincula a avaliação do cadastro à conta recém-criada
+func (s *RiskService) LinkUser(assessment *models.RiskAssessment, userID uint) {
+	if assessment == nil || assessment.ID == 0 {
+		return
+	}
+
+	if err := s.riskRepo.LinkUser(assessment.ID, userID, assessment.Score); err != nil {
+		log.Printf("Falha ao vincular avaliação de risco %d ao usuário %d: %v", assessment.ID, userID, err)
+	}
+}
+
+func (s *RiskService) GetAssessments(action models.RiskAction, minScore, limit, offset int) ([]models.RiskAssessment, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if minScore < 0 {
+		minScore = 0
+	}
+
+	assessments, err := s.riskRepo.GetAssessments(action, minScore, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar avaliações de risco")
+	}
+
+	return assessments, nil
+}
@@ -0,0 +1,480 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// ErrRoutingNotConfigured indica que nenhum provedor de rotas foi configurado
+var ErrRoutingNotConfigured = errors.New("cálculo de rotas não configurado")
+
+type RouteMode string
+
+const (
+	RouteModeWalking RouteMode = "walking"
+	RouteModeDriving RouteMode = "driving"
+)
+
+const (
+	// Limite de paradas por requisição da Directions API do Google
+	maxRouteStops = 25
+
+	// Estimativa em linha reta quando não há provedor: fator de desvio das
+	// ruas e velocidades médias
+	routeDetourFactor = 1.3
+	walkingSpeedKmh   = 4.5
+	drivingSpeedKmh   = 35
+
+	// Limites para avisar sobre dias irreais
+	maxDailyTravel  = 4 * time.Hour
+	maxDayLength    = 14 * time.Hour
+	maxWalkingLegKm = 3.0
+)
+
+type RoutingConfig struct {
+	Provider  string // "google" ou "osrm"
+	APIKey    string
+	APIURL    string
+	CacheDays int
+}
+
+type RoutePoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// RoutingProviderInterface abstrai o serviço externo de rotas; Route retorna
+// um trecho para cada par de pontos consecutivos
+type RoutingProviderInterface interface {
+	Name() string
+	Route(points []RoutePoint, mode RouteMode) ([]models.RouteLeg, error)
+}
+
+func NewRoutingProvider(config *RoutingConfig) RoutingProviderInterface {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch config.Provider {
+	case "google":
+		if config.APIKey == "" {
+			return &disabledRoutingProvider{}
+		}
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://maps.googleapis.com"
+		}
+		return &googleRoutingProvider{
+			apiKey: config.APIKey,
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+	case "osrm":
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://router.project-osrm.org"
+		}
+		return &osrmRoutingProvider{
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+	default:
+		return &disabledRoutingProvider{}
+	}
+}
+
+type disabledRoutingProvider struct{}
+
+func (p *disabledRoutingProvider) Name() string { return "none" }
+
+func (p *disabledRoutingProvider) Route(points []RoutePoint, mode RouteMode) ([]models.RouteLeg, error) {
+	return nil, ErrRoutingNotConfigured
+}
+
+// googleRoutingProvider usa a Directions API do Google Maps Platform
+type googleRoutingProvider struct {
+	apiKey string
+	apiURL string
+	client *http.Client
+}
+
+func (p *googleRoutingProvider) Name() string { return "google" }
+
+func (p *googleRoutingProvider) Route(points []RoutePoint, mode RouteMode) ([]models.RouteLeg, error) {
+	params := url.Values{}
+	params.Set("origin", routePointParam(points[0]))
+	params.Set("destination", routePointParam(points[len(points)-1]))
+	if len(points) > 2 {
+		waypoints := make([]string, 0, len(points)-2)
+		for _, point := range points[1 : len(points)-1] {
+			waypoints = append(waypoints, routePointParam(point))
+		}
+		params.Set("waypoints", strings.Join(waypoints, "|"))
+	}
+	params.Set("mode", string(mode))
+	params.Set("key", p.apiKey)
+
+	var result struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Routes       []struct {
+			Legs []struct {
+				Distance struct {
+					Value float64 `json:"value"`
+				} `json:"distance"`
+				Duration struct {
+					Value float64 `json:"value"`
+				} `json:"duration"`
+			} `json:"legs"`
+		} `json:"routes"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/maps/api/directions/json?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+	if result.Status != "OK" || len(result.Routes) == 0 {
+		return nil, fmt.Errorf("erro ao calcular rota: %s %s", result.Status, result.ErrorMessage)
+	}
+
+	legs := make([]models.RouteLeg, 0, len(result.Routes[0].Legs))
+	for _, leg := range result.Routes[0].Legs {
+		legs = append(legs, models.RouteLeg{
+			DistanceMeters:  leg.Distance.Value,
+			DurationSeconds: leg.Duration.Value,
+		})
+	}
+	return legs, nil
+}
+
+// osrmRoutingProvider usa um servidor OSRM (OpenStreetMap); o perfil "foot"
+// precisa estar disponível no servidor para rotas a pé
+type osrmRoutingProvider struct {
+	apiURL string
+	client *http.Client
+}
+
+func (p *osrmRoutingProvider) Name() string { return "osrm" }
+
+func (p *osrmRoutingProvider) Route(points []RoutePoint, mode RouteMode) ([]models.RouteLeg, error) {
+	profile := "driving"
+	if mode == RouteModeWalking {
+		profile = "foot"
+	}
+
+	coordinates := make([]string, 0, len(points))
+	for _, point := range points {
+		coordinates = append(coordinates, fmt.Sprintf("%.6f,%.6f", point.Longitude, point.Latitude))
+	}
+
+	var result struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Routes  []struct {
+			Legs []struct {
+				Distance float64 `json:"distance"`
+				Duration float64 `json:"duration"`
+			} `json:"legs"`
+		} `json:"routes"`
+	}
+	endpoint := fmt.Sprintf("%s/route/v1/%s/%s?overview=false", p.apiURL, profile, strings.Join(coordinates, ";"))
+	if err := getPlacesJSON(p.client, endpoint, &result); err != nil {
+		return nil, err
+	}
+	if result.Code != "Ok" || len(result.Routes) == 0 {
+		return nil, fmt.Errorf("erro ao calcular rota: %s %s", result.Code, result.Message)
+	}
+
+	legs := make([]models.RouteLeg, 0, len(result.Routes[0].Legs))
+	for _, leg := range result.Routes[0].Legs {
+		legs = append(legs, models.RouteLeg{
+			DistanceMeters:  leg.Distance,
+			DurationSeconds: leg.Duration,
+		})
+	}
+	return legs, nil
+}
+
+func routePointParam(point RoutePoint) string {
+	return fmt.Sprintf("%.6f,%.6f", point.Latitude, point.Longitude)
+}
+
+// RouteStop é um local do dia, na ordem de visita
+type RouteStop struct {
+	LocationID uint       `json:"location_id"`
+	Name       string     `json:"name"`
+	Latitude   *float64   `json:"latitude"`
+	Longitude  *float64   `json:"longitude"`
+	StartTime  *time.Time `json:"start_time"`
+	EndTime    *time.Time `json:"end_time"`
+}
+
+type DayRouteLeg struct {
+	FromLocationID  uint    `json:"from_location_id"`
+	ToLocationID    uint    `json:"to_location_id"`
+	DistanceMeters  float64 `json:"distance_meters"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+type RouteWarning struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	LocationID *uint  `json:"location_id,omitempty"`
+}
+
+// DayRoute é a rota de um dia do roteiro. Estimated indica distâncias em
+// linha reta, usadas quando o provedor de rotas não está disponível
+type DayRoute struct {
+	DayID                uint           `json:"day_id"`
+	DayNumber            int            `json:"day_number"`
+	Mode                 RouteMode      `json:"mode"`
+	Provider             string         `json:"provider"`
+	Estimated            bool           `json:"estimated"`
+	Stops                []RouteStop    `json:"stops"`
+	Legs                 []DayRouteLeg  `json:"legs"`
+	TotalDistanceMeters  float64        `json:"total_distance_meters"`
+	TotalDurationSeconds float64        `json:"total_duration_seconds"`
+	Skipped              []RouteStop    `json:"skipped"` // locais sem coordenadas
+	Warnings             []RouteWarning `json:"warnings"`
+}
+
+type RouteServiceInterface interface {
+	GetDayRoute(itineraryID, dayID, userID uint, mode RouteMode) (*DayRoute, error)
+}
+
+type RouteService struct {
+	routeRepo     repositories.RouteRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	geoService    GeoServiceInterface
+	provider      RoutingProviderInterface
+	cacheTTL      time.Duration
+}
+
+func NewRouteService(routeRepo repositories.RouteRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface, geoService GeoServiceInterface, provider RoutingProviderInterface, cacheTTL time.Duration) RouteServiceInterface {
+	return &RouteService{
+		routeRepo:     routeRepo,
+		itineraryRepo: itineraryRepo,
+		geoService:    geoService,
+		provider:      provider,
+		cacheTTL:      cacheTTL,
+	}
+}
+
+// GetDayRoute calcula distâncias e tempos de deslocamento entre os locais do
+// dia, na ordem do roteiro, e avisa sobre deslocamentos ou horários irreais
+func (s *RouteService) GetDayRoute(itineraryID, dayID, userID uint, mode RouteMode) (*DayRoute, error) {
+	if mode == "" {
+		mode = RouteModeWalking
+	}
+	if mode != RouteModeWalking && mode != RouteModeDriving {
+		return nil, errors.New("modo de deslocamento inválido: use walking ou driving")
+	}
+
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	var day *models.ItineraryDay
+	for i := range itinerary.Days {
+		if itinerary.Days[i].ID == dayID {
+			day = &itinerary.Days[i]
+			break
+		}
+	}
+	if day == nil {
+		return nil, errors.New("dia não encontrado neste roteiro")
+	}
+
+	route := &DayRoute{
+		DayID:     day.ID,
+		DayNumber: day.DayNumber,
+		Mode:      mode,
+		Provider:  s.provider.Name(),
+		Stops:     []RouteStop{},
+		Legs:      []DayRouteLeg{},
+		Skipped:   []RouteStop{},
+		Warnings:  []RouteWarning{},
+	}
+
+	var points []RoutePoint
+	for _, location := range sortedLocations(day) {
+		stop := RouteStop{
+			LocationID: location.ID,
+			Name:       location.Name,
+			Latitude:   location.Latitude,
+			Longitude:  location.Longitude,
+			StartTime:  location.StartTime,
+			EndTime:    location.EndTime,
+		}
+		if location.Latitude == nil || location.Longitude == nil {
+			route.Skipped = append(route.Skipped, stop)
+			continue
+		}
+		route.Stops = append(route.Stops, stop)
+		points = append(points, RoutePoint{Latitude: *location.Latitude, Longitude: *location.Longitude})
+	}
+	if len(points) > maxRouteStops {
+		return nil, fmt.Errorf("dia tem locais demais para calcular a rota (máximo %d)", maxRouteStops)
+	}
+	if len(points) < 2 {
+		return route, nil
+	}
+
+	legs, provider, estimated := s.routeLegs(points, mode)
+	route.Provider, route.Estimated = provider, estimated
+	for i, leg := range legs {
+		route.Legs = append(route.Legs, DayRouteLeg{
+			FromLocationID:  route.Stops[i].LocationID,
+			ToLocationID:    route.Stops[i+1].LocationID,
+			DistanceMeters:  leg.DistanceMeters,
+			DurationSeconds: leg.DurationSeconds,
+		})
+		route.TotalDistanceMeters += leg.DistanceMeters
+		route.TotalDurationSeconds += leg.DurationSeconds
+	}
+
+	route.Warnings = routeWarnings(route, dayLocation(s.geoService, itinerary, day))
+	return route, nil
+}
+
+// routeLegs consulta o cache antes do provedor; sem provedor (ou com falha)
+// os trechos são estimados pela distância em linha reta, sem cache
+func (s *RouteService) routeLegs(points []RoutePoint, mode RouteMode) ([]models.RouteLeg, string, bool) {
+	hash := routeHash(points, mode)
+	if cached, err := s.routeRepo.GetCache(hash); err == nil &&
+		time.Since(cached.ComputedAt) < s.cacheTTL && len(cached.Legs) == len(points)-1 {
+		return cached.Legs, cached.Provider, false
+	}
+
+	legs, err := s.provider.Route(points, mode)
+	if err == nil && len(legs) == len(points)-1 {
+		cache := &models.RouteCache{
+			Hash:       hash,
+			Mode:       string(mode),
+			Provider:   s.provider.Name(),
+			Legs:       legs,
+			ComputedAt: time.Now(),
+		}
+		if err := s.routeRepo.SaveCache(cache); err != nil {
+			log.Printf("Falha ao salvar cache de rota: %v", err)
+		}
+		return legs, s.provider.Name(), false
+	}
+	if err != nil && !errors.Is(err, ErrRoutingNotConfigured) {
+		log.Printf("Falha ao calcular rota, usando estimativa: %v", err)
+	}
+
+	return estimateLegs(points, mode), "estimate", true
+}
+
+// routeHash identifica a rota pelo modo e pelas coordenadas arredondadas
+// (~1 m), para reaproveitar o cálculo entre roteiros com as mesmas paradas
+func routeHash(points []RoutePoint, mode RouteMode) string {
+	var key strings.Builder
+	key.WriteString(string(mode))
+	for _, point := range points {
+		fmt.Fprintf(&key, "|%.5f,%.5f", point.Latitude, point.Longitude)
+	}
+	sum := sha256.Sum256([]byte(key.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func estimateLegs(points []RoutePoint, mode RouteMode) []models.RouteLeg {
+	speed := walkingSpeedKmh
+	if mode == RouteModeDriving {
+		speed = drivingSpeedKmh
+	}
+
+	legs := make([]models.RouteLeg, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		km := haversineKm(points[i-1].Latitude, points[i-1].Longitude, points[i].Latitude, points[i].Longitude) * routeDetourFactor
+		legs = append(legs, models.RouteLeg{
+			DistanceMeters:  km * 1000,
+			DurationSeconds: km / speed * 3600,
+		})
+	}
+	return legs
+}
+
+// routeWarnings aponta deslocamentos longos e horários que não comportam o
+// trajeto até o próximo local
+func routeWarnings(route *DayRoute, loc *time.Location) []RouteWarning {
+	warnings := []RouteWarning{}
+
+	travel := time.Duration(route.TotalDurationSeconds) * time.Second
+	if travel > maxDailyTravel {
+		warnings = append(warnings, RouteWarning{
+			Code:    "long_travel",
+			Message: fmt.Sprintf("O dia tem %s de deslocamento, acima de %s", formatRouteDuration(travel), formatRouteDuration(maxDailyTravel)),
+		})
+	}
+
+	visits := time.Duration(0)
+	for _, stop := range route.Stops {
+		if stop.StartTime != nil && stop.EndTime != nil && stop.EndTime.After(*stop.StartTime) {
+			visits += stop.EndTime.Sub(*stop.StartTime)
+		}
+	}
+	if travel+visits > maxDayLength {
+		warnings = append(warnings, RouteWarning{
+			Code:    "long_day",
+			Message: fmt.Sprintf("Deslocamentos e visitas somam %s, acima de %s", formatRouteDuration(travel+visits), formatRouteDuration(maxDayLength)),
+		})
+	}
+
+	// Os horários são comparados no fuso do dia, sobre uma mesma data
+	date := time.Date(2000, 1, 1, 0, 0, 0, 0, loc)
+	for i, leg := range route.Legs {
+		from, to := route.Stops[i], route.Stops[i+1]
+		toID := to.LocationID
+
+		if route.Mode == RouteModeWalking && leg.DistanceMeters/1000 > maxWalkingLegKm {
+			warnings = append(warnings, RouteWarning{
+				Code:       "long_walk",
+				Message:    fmt.Sprintf("%.1f km a pé de %s até %s; considere outro transporte", leg.DistanceMeters/1000, from.Name, to.Name),
+				LocationID: &toID,
+			})
+		}
+
+		departure := from.EndTime
+		if departure == nil {
+			departure = from.StartTime
+		}
+		if departure == nil || to.StartTime == nil {
+			continue
+		}
+		gap := atClock(date, *to.StartTime, loc).Sub(atClock(date, *departure, loc))
+		needed := time.Duration(leg.DurationSeconds) * time.Second
+		if gap < needed {
+			warnings = append(warnings, RouteWarning{
+				Code:       "tight_connection",
+				Message:    fmt.Sprintf("O trajeto de %s até %s leva %s, mas há apenas %s entre os horários", from.Name, to.Name, formatRouteDuration(needed), formatRouteDuration(gap)),
+				LocationID: &toID,
+			})
+		}
+	}
+
+	return warnings
+}
+
+func formatRouteDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Minute)
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	if hours == 0 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	if minutes == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh%02d", hours, minutes)
+}
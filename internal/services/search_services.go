@@ -0,0 +1,728 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	// Resultados por seção na primeira página, que mostra as três juntas
+	searchSectionLimit = 5
+	// Limite por página ao paginar uma seção sozinha
+	searchMaxLimit = 20
+
+	suggestLimit    = 10
+	suggestMaxLimit = 20
+	// Peso das buscas registradas na ordem das sugestões. Cada sugestão
+	// soma a popularidade dentro do seu tipo (população, posts com a tag,
+	// seguidores), de 0 a 1, com a frequência com que o texto é buscado,
+	// também de 0 a 1, multiplicada por este peso
+	suggestSearchWeight = 1.5
+	// Tamanho máximo guardado de buscas e hashtags
+	searchTermMaxLength = 100
+
+	maxSavedSearchesPerUser = 20
+	// Roteiros novos citados em cada alerta de busca salva
+	savedSearchAlertItems = 10
+)
+
+var hashtagPattern = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+
+type SearchServiceInterface interface {
+	Search(userID uint, req *SearchRequest) (*models.SearchResponse, error)
+	Suggest(query string, limit int) ([]models.SearchSuggestion, error)
+	BackfillHashtags() (int, error)
+	SaveSearch(userID uint, req *SavedSearchRequest) (*models.SavedSearch, error)
+	GetSavedSearches(userID uint) ([]models.SavedSearch, error)
+	DeleteSavedSearch(id, userID uint) error
+	SendSavedSearchAlerts(now time.Time) (int, error)
+	StartSavedSearchAlertScheduler(interval time.Duration)
+}
+
+// SearchRequest é a busca da barra única do app. Sem Type vêm as três
+// seções; com Type (e o Cursor devolvido por ela) vem a próxima página só
+// daquela seção
+type SearchRequest struct {
+	Query  string
+	Type   string
+	Cursor string
+	Limit  int
+}
+
+// GeoFilter restringe uma busca ao raio em torno de Latitude/Longitude ou,
+// com BBox, a um retângulo em graus na ordem do GeoJSON (oeste, sul, leste,
+// norte)
+type GeoFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	BBox      []float64
+}
+
+// area valida o filtro e o converte para a consulta; nil sem filtro
+func (f *GeoFilter) area() (*repositories.GeoArea, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	if f.BBox != nil {
+		if len(f.BBox) != 4 {
+			return nil, errors.New("a área (bbox) deve ter oeste, sul, leste e norte")
+		}
+		box := &repositories.GeoBox{
+			MinLongitude: f.BBox[0],
+			MinLatitude:  f.BBox[1],
+			MaxLongitude: f.BBox[2],
+			MaxLatitude:  f.BBox[3],
+		}
+		if !validCoordinates(box.MinLatitude, box.MinLongitude) || !validCoordinates(box.MaxLatitude, box.MaxLongitude) {
+			return nil, errors.New("coordenadas inválidas")
+		}
+		if box.MinLongitude >= box.MaxLongitude || box.MinLatitude >= box.MaxLatitude {
+			return nil, errors.New("a área (bbox) deve ir de oeste para leste e de sul para norte")
+		}
+		if box.MaxLongitude-box.MinLongitude > 180 {
+			return nil, errors.New("a área (bbox) deve ter no máximo 180 graus de largura")
+		}
+		return &repositories.GeoArea{Box: box}, nil
+	}
+
+	if !validCoordinates(f.Latitude, f.Longitude) {
+		return nil, errors.New("coordenadas inválidas")
+	}
+	radiusKm := f.RadiusKm
+	if radiusKm <= 0 {
+		radiusKm = 10
+	}
+	if radiusKm > 100 {
+		return nil, errors.New("raio deve ser de no máximo 100 km")
+	}
+	return &repositories.GeoArea{Latitude: f.Latitude, Longitude: f.Longitude, RadiusKm: radiusKm}, nil
+}
+
+// SavedSearchRequest são os critérios de uma busca de roteiros salva; ao
+// menos um é obrigatório. Sem nome, o rótulo é montado dos critérios
+type SavedSearchRequest struct {
+	Name        string                   `json:"name"`
+	Query       string                   `json:"query"`
+	Category    models.ItineraryCategory `json:"category"`
+	Country     string                   `json:"country"`
+	City        string                   `json:"city"`
+	MinDuration int                      `json:"min_duration"`
+	MaxDuration int                      `json:"max_duration"`
+	Alerts      *bool                    `json:"alerts"` // padrão: ligados
+}
+
+// searchCursor é a posição numa seção da busca. Os resultados são ordenados
+// por relevância, então a página seguinte é pelo deslocamento; o texto e a
+// seção vão junto para o cursor não ser usado em outra busca
+type searchCursor struct {
+	Type   string `json:"t"`
+	Query  string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+// scoredSuggestion é uma sugestão candidata; key é o texto normalizado usado
+// para cruzar com as buscas registradas
+type scoredSuggestion struct {
+	suggestion models.SearchSuggestion
+	key        string
+	score      float64
+}
+
+type SearchService struct {
+	searchRepo          repositories.SearchRepositoryInterface
+	userService         UserServiceInterface
+	postService         PostServiceInterface
+	itineraryService    ItineraryServiceInterface
+	geoService          GeoServiceInterface
+	notificationService NotificationServiceInterface
+}
+
+func NewSearchService(searchRepo repositories.SearchRepositoryInterface, userService UserServiceInterface, postService PostServiceInterface, itineraryService ItineraryServiceInterface, geoService GeoServiceInterface, notificationService NotificationServiceInterface, eventBus events.BusInterface) SearchServiceInterface {
+	service := &SearchService{
+		searchRepo:          searchRepo,
+		userService:         userService,
+		postService:         postService,
+		itineraryService:    itineraryService,
+		geoService:          geoService,
+		notificationService: notificationService,
+	}
+
+	eventBus.Subscribe(events.PostCreated, service.onPostCreated)
+
+	return service
+}
+
+// Search busca usuários, posts e roteiros de uma vez, cada seção com sua
+// própria paginação
+func (s *SearchService) Search(userID uint, req *SearchRequest) (*models.SearchResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, errors.New("o texto da busca é obrigatório")
+	}
+
+	types := []string{models.SearchTypeUsers, models.SearchTypePosts, models.SearchTypeItineraries}
+	limit := searchSectionLimit
+	offset := 0
+
+	if req.Type != "" {
+		if !isSearchType(req.Type) {
+			return nil, errors.New("tipo de busca inválido: use users, posts ou itineraries")
+		}
+		types = []string{req.Type}
+		if req.Limit > 0 {
+			limit = min(req.Limit, searchMaxLimit)
+		}
+		if req.Cursor != "" {
+			cursor, err := decodeSearchCursor(req.Cursor)
+			if err != nil || cursor.Type != req.Type || cursor.Query != query {
+				return nil, errors.New("cursor inválido")
+			}
+			offset = cursor.Offset
+		}
+	} else if req.Cursor != "" {
+		return nil, errors.New("informe o tipo da seção para usar o cursor")
+	}
+
+	// Só a primeira página conta como uma busca feita
+	if req.Cursor == "" {
+		s.recordSearch(query)
+	}
+
+	response := &models.SearchResponse{Query: query}
+	for _, searchType := range types {
+		switch searchType {
+		case models.SearchTypeUsers:
+			users, err := s.userService.SearchUsers(query, limit, offset)
+			if err != nil {
+				return nil, err
+			}
+			if users == nil {
+				users = []models.UserResponse{}
+			}
+			response.Users = &models.UserSearchSection{
+				Items:      users,
+				NextCursor: nextSearchCursor(searchType, query, offset, limit, len(users)),
+			}
+		case models.SearchTypePosts:
+			posts, err := s.postService.SearchPosts(query, nil, userID, limit, offset)
+			if err != nil {
+				return nil, err
+			}
+			if posts == nil {
+				posts = []models.PostResponse{}
+			}
+			response.Posts = &models.PostSearchSection{
+				Items:      posts,
+				NextCursor: nextSearchCursor(searchType, query, offset, limit, len(posts)),
+			}
+		case models.SearchTypeItineraries:
+			itineraries, err := s.itineraryService.SearchItineraries(query, userID, limit, offset)
+			if err != nil {
+				return nil, err
+			}
+			if itineraries == nil {
+				itineraries = []models.ItineraryResponse{}
+			}
+			response.Itineraries = &models.ItinerarySearchSection{
+				Items:      itineraries,
+				NextCursor: nextSearchCursor(searchType, query, offset, limit, len(itineraries)),
+			}
+		}
+	}
+
+	return response, nil
+}
+
+func isSearchType(searchType string) bool {
+	switch searchType {
+	case models.SearchTypeUsers, models.SearchTypePosts, models.SearchTypeItineraries:
+		return true
+	}
+	return false
+}
+
+// nextSearchCursor aponta para a página seguinte da seção; vazio quando a
+// página veio incompleta
+func nextSearchCursor(searchType, query string, offset, limit, count int) string {
+	if count < limit {
+		return ""
+	}
+
+	data, err := json.Marshal(searchCursor{Type: searchType, Query: query, Offset: offset + count})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSearchCursor(cursor string) (*searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded searchCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Offset < 0 {
+		return nil, errors.New("cursor inválido")
+	}
+	return &decoded, nil
+}
+
+// Suggest completa o que o usuário está digitando com destinos, hashtags e
+// usernames que começam com o texto. Começando com "#" só vêm hashtags e com
+// "@" só usuários
+func (s *SearchService) Suggest(query string, limit int) ([]models.SearchSuggestion, error) {
+	if limit <= 0 || limit > suggestMaxLimit {
+		limit = suggestLimit
+	}
+
+	query = strings.TrimSpace(query)
+	onlyHashtags := strings.HasPrefix(query, "#")
+	onlyUsers := strings.HasPrefix(query, "@")
+	prefix := normalizeGeoName(strings.TrimLeft(query, "#@"))
+	if prefix == "" {
+		return []models.SearchSuggestion{}, nil
+	}
+
+	var candidates []scoredSuggestion
+	if !onlyHashtags && !onlyUsers {
+		destinations, err := s.destinationSuggestions(prefix, limit)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, destinations...)
+	}
+	if !onlyUsers {
+		hashtags, err := s.searchRepo.SuggestHashtags(strings.ReplaceAll(prefix, " ", "_"), limit)
+		if err != nil {
+			return nil, errors.New("erro ao buscar sugestões")
+		}
+		for i, hashtag := range hashtags {
+			candidates = append(candidates, scoredSuggestion{
+				suggestion: models.SearchSuggestion{Type: models.SearchSuggestionHashtag, Text: "#" + hashtag.Tag},
+				key:        hashtag.Tag,
+				score:      rankPopularity(i, len(hashtags)),
+			})
+		}
+	}
+	if !onlyHashtags {
+		users, err := s.searchRepo.SuggestUsers(prefix, limit)
+		if err != nil {
+			return nil, errors.New("erro ao buscar sugestões")
+		}
+		for i, user := range users {
+			candidates = append(candidates, scoredSuggestion{
+				suggestion: models.SearchSuggestion{
+					Type:           models.SearchSuggestionUser,
+					Text:           "@" + user.Username,
+					Detail:         strings.TrimSpace(user.FirstName + " " + user.LastName),
+					UserID:         user.ID,
+					ProfilePicture: user.ProfilePicture,
+				},
+				key:   normalizeGeoName(user.Username),
+				score: rankPopularity(i, len(users)),
+			})
+		}
+	}
+
+	if err := s.weighBySearches(candidates); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	suggestions := make([]models.SearchSuggestion, len(candidates))
+	for i, candidate := range candidates {
+		suggestions[i] = candidate.suggestion
+	}
+	return suggestions, nil
+}
+
+// destinationSuggestions junta países e cidades, que já vêm na ordem de
+// relevância do GeoService (cidades pela população)
+func (s *SearchService) destinationSuggestions(prefix string, limit int) ([]scoredSuggestion, error) {
+	countries, err := s.geoService.SearchCountries(prefix, limit)
+	if err != nil {
+		return nil, errors.New("erro ao buscar sugestões")
+	}
+	cities, err := s.geoService.SearchCities(prefix, "", limit)
+	if err != nil {
+		return nil, errors.New("erro ao buscar sugestões")
+	}
+
+	destinations := make([]scoredSuggestion, 0, len(countries)+len(cities))
+	for _, country := range countries {
+		destinations = append(destinations, scoredSuggestion{
+			suggestion: models.SearchSuggestion{
+				Type:        models.SearchSuggestionDestination,
+				Text:        country.Name,
+				CountryCode: country.CountryCode,
+			},
+			key: normalizeGeoName(country.Name),
+			// Um país que começa com o texto costuma ser o que se procura
+			score: 1,
+		})
+	}
+	for i, city := range cities {
+		detail := city.CountryName
+		if city.StateName != "" {
+			detail = city.StateName + ", " + city.CountryName
+		}
+		destinations = append(destinations, scoredSuggestion{
+			suggestion: models.SearchSuggestion{
+				Type:        models.SearchSuggestionDestination,
+				Text:        city.Name,
+				Detail:      detail,
+				CountryCode: city.CountryCode,
+			},
+			key:   normalizeGeoName(city.Name),
+			score: rankPopularity(i, len(cities)),
+		})
+	}
+	return destinations, nil
+}
+
+// weighBySearches soma à pontuação das sugestões o quanto o texto de cada
+// uma é buscado, em escala logarítmica relativa ao mais buscado entre elas
+func (s *SearchService) weighBySearches(candidates []scoredSuggestion) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		keys[i] = candidate.key
+	}
+	counts, err := s.searchRepo.GetSearchCounts(keys)
+	if err != nil {
+		return errors.New("erro ao buscar sugestões")
+	}
+
+	var most int64
+	for _, count := range counts {
+		most = max(most, count)
+	}
+	if most == 0 {
+		return nil
+	}
+
+	for i := range candidates {
+		if count := counts[candidates[i].key]; count > 0 {
+			candidates[i].score += suggestSearchWeight * math.Log1p(float64(count)) / math.Log1p(float64(most))
+		}
+	}
+	return nil
+}
+
+// rankPopularity converte a posição na lista (já ordenada pela popularidade
+// própria do tipo) numa nota de 0 a 1
+func rankPopularity(position, total int) float64 {
+	return float64(total-position) / float64(total)
+}
+
+// recordSearch registra a busca para pesar nas sugestões; uma falha não
+// impede a busca
+func (s *SearchService) recordSearch(query string) {
+	term := normalizeGeoName(strings.TrimLeft(query, "#@"))
+	if term == "" || len(term) > searchTermMaxLength {
+		return
+	}
+	if err := s.searchRepo.RecordSearch(term); err != nil {
+		log.Printf("Falha ao registrar busca %q: %v", term, err)
+	}
+}
+
+func (s *SearchService) onPostCreated(event events.Event) {
+	tags := extractHashtags(event.Data["text"])
+	if len(tags) == 0 {
+		return
+	}
+	if err := s.searchRepo.AddHashtags(tags, time.Now()); err != nil {
+		log.Printf("Falha ao contar hashtags do post %d: %v", event.EntityID, err)
+	}
+}
+
+// BackfillHashtags conta as hashtags dos posts já publicados. Só roda com a
+// tabela vazia; daí em diante cada post novo é contado ao ser criado
+func (s *SearchService) BackfillHashtags() (int, error) {
+	const batchSize = 500
+
+	if exists, err := s.searchRepo.HasHashtags(); err != nil || exists {
+		return 0, err
+	}
+
+	counted := 0
+	var lastID uint
+	for {
+		posts, err := s.searchRepo.GetPostsAfter(lastID, batchSize)
+		if err != nil {
+			return counted, err
+		}
+		if len(posts) == 0 {
+			return counted, nil
+		}
+
+		for _, post := range posts {
+			lastID = post.ID
+			tags := extractHashtags(post.Content)
+			if len(tags) == 0 {
+				continue
+			}
+			if err := s.searchRepo.AddHashtags(tags, post.CreatedAt); err != nil {
+				return counted, err
+			}
+			counted++
+		}
+	}
+}
+
+// extractHashtags retorna as tags distintas do texto, normalizadas como as
+// buscas (minúsculas e sem acentos)
+func extractHashtags(text string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, match := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		tag := normalizeGeoName(match[1])
+		if tag == "" || len(tag) > searchTermMaxLength || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// SaveSearch guarda os critérios de uma busca de roteiros. País e cidade são
+// associados aos dados de referência, como nos roteiros, para "Japão" e
+// "Japan" valerem o mesmo
+func (s *SearchService) SaveSearch(userID uint, req *SavedSearchRequest) (*models.SavedSearch, error) {
+	search := &models.SavedSearch{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Query:       strings.TrimSpace(req.Query),
+		Category:    req.Category,
+		Country:     strings.TrimSpace(req.Country),
+		City:        strings.TrimSpace(req.City),
+		MinDuration: req.MinDuration,
+		MaxDuration: req.MaxDuration,
+		Alerts:      req.Alerts == nil || *req.Alerts,
+	}
+
+	if search.Query == "" && search.Category == "" && search.Country == "" && search.City == "" &&
+		search.MinDuration == 0 && search.MaxDuration == 0 {
+		return nil, errors.New("informe ao menos um critério de busca")
+	}
+	if utf8.RuneCountInString(search.Name) > 150 {
+		return nil, errors.New("nome deve ter no máximo 150 caracteres")
+	}
+	if utf8.RuneCountInString(search.Query) > 200 {
+		return nil, errors.New("texto da busca deve ter no máximo 200 caracteres")
+	}
+	if search.Category != "" {
+		if err := validateItineraryCategory(search.Category); err != nil {
+			return nil, err
+		}
+	}
+	if search.MinDuration < 0 || search.MaxDuration < 0 {
+		return nil, errors.New("duração não pode ser negativa")
+	}
+	if search.MaxDuration > 0 && search.MinDuration > search.MaxDuration {
+		return nil, errors.New("duração mínima não pode ser maior que a máxima")
+	}
+
+	if search.Country != "" || search.City != "" {
+		location := s.geoService.NormalizeLocation(search.Country, "", search.City)
+		if search.Country != "" && location.CountryID != nil {
+			search.Country = location.Country
+			search.CountryID = location.CountryID
+		}
+		if search.City != "" && location.CityID != nil {
+			search.City = location.City
+			search.CityID = location.CityID
+		}
+	}
+	if search.Name == "" {
+		search.Name = savedSearchName(search)
+	}
+
+	count, err := s.searchRepo.CountSavedSearches(userID)
+	if err != nil {
+		return nil, errors.New("erro ao salvar busca")
+	}
+	if count >= maxSavedSearchesPerUser {
+		return nil, fmt.Errorf("limite de %d buscas salvas atingido", maxSavedSearchesPerUser)
+	}
+
+	// Os alertas valem para o que for publicado daqui em diante
+	search.LastCheckedAt = time.Now()
+	if err := s.searchRepo.CreateSavedSearch(search); err != nil {
+		return nil, errors.New("erro ao salvar busca")
+	}
+	return search, nil
+}
+
+func (s *SearchService) GetSavedSearches(userID uint) ([]models.SavedSearch, error) {
+	searches, err := s.searchRepo.GetSavedSearches(userID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar buscas salvas")
+	}
+	return searches, nil
+}
+
+func (s *SearchService) DeleteSavedSearch(id, userID uint) error {
+	deleted, err := s.searchRepo.DeleteSavedSearch(id, userID)
+	if err != nil {
+		return errors.New("erro ao remover busca salva")
+	}
+	if !deleted {
+		return errors.New("busca salva não encontrada")
+	}
+	return nil
+}
+
+// SendSavedSearchAlerts avisa os donos das buscas salvas com alertas sobre os
+// roteiros públicos publicados desde a última verificação. A chave da
+// notificação inclui o roteiro mais novo, então uma verificação repetida não
+// avisa duas vezes
+func (s *SearchService) SendSavedSearchAlerts(now time.Time) (int, error) {
+	const batchSize = 200
+
+	sent := 0
+	var lastID uint
+	for {
+		searches, err := s.searchRepo.GetSavedSearchesWithAlerts(lastID, batchSize)
+		if err != nil {
+			return sent, errors.New("erro ao buscar buscas salvas")
+		}
+		if len(searches) == 0 {
+			return sent, nil
+		}
+
+		for i := range searches {
+			search := &searches[i]
+			lastID = search.ID
+
+			itineraries, err := s.searchRepo.MatchItineraries(search, search.LastCheckedAt, now, savedSearchAlertItems, 0)
+			if err != nil {
+				log.Printf("Falha ao verificar a busca salva %d: %v", search.ID, err)
+				continue
+			}
+
+			created := false
+			if len(itineraries) > 0 {
+				created, err = s.notifySavedSearch(search, itineraries)
+				if err != nil {
+					log.Printf("Falha ao enviar alerta da busca salva %d: %v", search.ID, err)
+					continue
+				}
+			}
+			if err := s.searchRepo.MarkSavedSearchChecked(search.ID, now, created); err != nil {
+				log.Printf("Falha ao atualizar a busca salva %d: %v", search.ID, err)
+				continue
+			}
+			if created {
+				sent++
+			}
+		}
+	}
+}
+
+func (s *SearchService) notifySavedSearch(search *models.SavedSearch, itineraries []models.Itinerary) (bool, error) {
+	var newestID uint
+	ids := make([]string, len(itineraries))
+	for i, itinerary := range itineraries {
+		ids[i] = fmt.Sprint(itinerary.ID)
+		newestID = max(newestID, itinerary.ID)
+	}
+
+	body := fmt.Sprintf("\"%s\" combina com a sua busca", itineraries[0].Title)
+	if len(itineraries) > 1 {
+		body = fmt.Sprintf("%d roteiros novos combinam com a sua busca", len(itineraries))
+		if len(itineraries) == savedSearchAlertItems {
+			body = fmt.Sprintf("Mais de %d roteiros novos combinam com a sua busca", savedSearchAlertItems-1)
+		}
+	}
+
+	key := fmt.Sprintf("saved_search:%d:%d", search.ID, newestID)
+	return s.notificationService.Notify(&models.Notification{
+		UserID: search.UserID,
+		Type:   models.NotificationTypeSavedSearch,
+		Title:  search.Name,
+		Body:   body,
+		Data: map[string]string{
+			"saved_search_id": fmt.Sprint(search.ID),
+			"itinerary_id":    ids[0],
+			"itinerary_ids":   strings.Join(ids, ","),
+		},
+		Key: &key,
+	})
+}
+
+// StartSavedSearchAlertScheduler verifica as buscas salvas periodicamente em
+// segundo plano
+func (s *SearchService) StartSavedSearchAlertScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if sent, err := s.SendSavedSearchAlerts(time.Now()); err != nil {
+				log.Println("Falha ao enviar alertas de buscas salvas:", err)
+			} else if sent > 0 {
+				log.Printf("%d alertas de buscas salvas enviados", sent)
+			}
+		}
+	}()
+}
+
+// savedSearchName monta o rótulo da busca a partir dos critérios, como
+// "praia, Japão, 7–10 dias, nature"
+func savedSearchName(search *models.SavedSearch) string {
+	var parts []string
+	for _, part := range []string{search.Query, search.City, search.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	switch {
+	case search.MinDuration > 0 && search.MaxDuration > 0 && search.MinDuration == search.MaxDuration:
+		parts = append(parts, fmt.Sprintf("%d dias", search.MinDuration))
+	case search.MinDuration > 0 && search.MaxDuration > 0:
+		parts = append(parts, fmt.Sprintf("%d–%d dias", search.MinDuration, search.MaxDuration))
+	case search.MinDuration > 0:
+		parts = append(parts, fmt.Sprintf("%d+ dias", search.MinDuration))
+	case search.MaxDuration > 0:
+		parts = append(parts, fmt.Sprintf("até %d dias", search.MaxDuration))
+	}
+
+	if search.Category != "" {
+		parts = append(parts, string(search.Category))
+	}
+
+	name := strings.Join(parts, ", ")
+	if utf8.RuneCountInString(name) > 150 {
+		name = string([]rune(name)[:150])
+	}
+	return name
+}
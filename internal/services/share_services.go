@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type UpdateShareLinkRequest struct {
+	Enabled    *bool `json:"enabled"`
+	NoIndex    *bool `json:"noindex"`
+	Regenerate bool  `json:"regenerate"` // novo slug; o link anterior deixa de funcionar
+}
+
+type ShareLinkResponse struct {
+	ItineraryID uint   `json:"itinerary_id"`
+	Slug        string `json:"slug"`
+	URL         string `json:"url,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	NoIndex     bool   `json:"noindex"`
+	ViewsCount  int    `json:"views_count"`
+}
+
+// PublicItinerary é o roteiro visto pelo link público; NoIndex deve virar a
+// meta tag robots na página renderizada
+type PublicItinerary struct {
+	Slug      string                    `json:"slug"`
+	NoIndex   bool                      `json:"noindex"`
+	Itinerary *models.ItineraryResponse `json:"itinerary"`
+}
+
+type ShareServiceInterface interface {
+	GetShareLink(itineraryID, userID uint) (*ShareLinkResponse, error)
+	UpdateShareLink(itineraryID, userID uint, req *UpdateShareLinkRequest) (*ShareLinkResponse, error)
+	GetPublicItinerary(slug string) (*PublicItinerary, error)
+}
+
+type ShareService struct {
+	shareRepo        repositories.ShareRepositoryInterface
+	itineraryRepo    repositories.ItineraryRepositoryInterface
+	itineraryService ItineraryServiceInterface
+	baseURL          string
+}
+
+func NewShareService(shareRepo repositories.ShareRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface, itineraryService ItineraryServiceInterface, baseURL string) ShareServiceInterface {
+	return &ShareService{
+		shareRepo:        shareRepo,
+		itineraryRepo:    itineraryRepo,
+		itineraryService: itineraryService,
+		baseURL:          strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *ShareService) GetShareLink(itineraryID, userID uint) (*ShareLinkResponse, error) {
+	if _, err := s.authorItinerary(itineraryID, userID); err != nil {
+		return nil, err
+	}
+
+	link, err := s.shareRepo.GetByItinerary(itineraryID)
+	if err != nil {
+		return nil, errors.New("link de compartilhamento não encontrado")
+	}
+
+	return s.toResponse(link), nil
+}
+
+// UpdateShareLink cria o link na primeira chamada e depois altera as opções
+func (s *ShareService) UpdateShareLink(itineraryID, userID uint, req *UpdateShareLinkRequest) (*ShareLinkResponse, error) {
+	itinerary, err := s.authorItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.shareRepo.GetByItinerary(itineraryID)
+	if err != nil {
+		link = &models.ItineraryShareLink{
+			ItineraryID: itineraryID,
+			Enabled:     true,
+		}
+	}
+
+	if link.Slug == "" || req.Regenerate {
+		slug, err := shareSlug(itinerary.Title)
+		if err != nil {
+			return nil, errors.New("erro ao gerar link de compartilhamento")
+		}
+		link.Slug = slug
+	}
+	if req.Enabled != nil {
+		link.Enabled = *req.Enabled
+	}
+	if req.NoIndex != nil {
+		link.NoIndex = *req.NoIndex
+	}
+
+	enabling := link.ID == 0 || (req.Enabled != nil && *req.Enabled)
+	if enabling && link.Enabled && !itinerary.IsPublic {
+		return nil, errors.New("apenas roteiros públicos podem ser compartilhados por link")
+	}
+
+	if err := s.shareRepo.Save(link); err != nil {
+		return nil, errors.New("erro ao salvar link de compartilhamento")
+	}
+
+	return s.toResponse(link), nil
+}
+
+// GetPublicItinerary abre o roteiro pelo link, sem login. O link só funciona
+// ativo e enquanto o roteiro continuar público; as visualizações contam no
+// link e no roteiro
+func (s *ShareService) GetPublicItinerary(slug string) (*PublicItinerary, error) {
+	link, err := s.shareRepo.GetBySlug(slug)
+	if err != nil || !link.Enabled {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	itinerary, err := s.itineraryService.GetItineraryByID(link.ItineraryID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.shareRepo.IncrementViews(link.ID); err != nil {
+		log.Printf("Falha ao contar visualização do link %d: %v", link.ID, err)
+	}
+
+	return &PublicItinerary{
+		Slug:      link.Slug,
+		NoIndex:   link.NoIndex,
+		Itinerary: itinerary,
+	}, nil
+}
+
+func (s *ShareService) authorItinerary(itineraryID, userID uint) (*models.Itinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para editar este roteiro")
+	}
+	return itinerary, nil
+}
+
+func (s *ShareService) toResponse(link *models.ItineraryShareLink) *ShareLinkResponse {
+	response := &ShareLinkResponse{
+		ItineraryID: link.ItineraryID,
+		Slug:        link.Slug,
+		Enabled:     link.Enabled,
+		NoIndex:     link.NoIndex,
+		ViewsCount:  link.ViewsCount,
+	}
+	if s.baseURL != "" {
+		response.URL = s.baseURL + "/" + link.Slug
+	}
+	return response
+}
+
+// shareSlug junta o título legível a um sufixo aleatório, que é o que torna o
+// link difícil de adivinhar ("roteiro-em-lisboa-k3j9x2abqe")
+func shareSlug(title string) (string, error) {
+	var name strings.Builder
+	dash := false
+	for _, r := range normalizeGeoName(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			name.WriteRune(r)
+			dash = false
+		case (r == ' ' || r == '-' || r == '_') && !dash:
+			name.WriteRune('-')
+			dash = true
+		}
+	}
+	prefix := strings.Trim(truncateString(name.String(), 50), "-")
+
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	token := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(random))[:12]
+
+	if prefix == "" {
+		return token, nil
+	}
+	return prefix + "-" + token, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type SpamConfig struct {
+	// Pontuação (0 a 100) a partir da qual a conta é limitada (0 apenas
+	// sinaliza para a moderação)
+	LimitThreshold int
+	LimitHours     int // duração do limite
+	DuplicateLimit int // posts com o mesmo texto em uma hora
+	MaxLinks       int // links aceitos em um post
+	// Follows e contas seguidas e logo deixadas de seguir em uma hora
+	FollowBurstLimit int
+	FollowChurnLimit int
+}
+
+// SpamResult é a avaliação de spam de um post ou follow
+type SpamResult struct {
+	Score   int
+	Signals []string
+}
+
+type SpamServiceInterface interface {
+	CheckPost(userID uint, content string) (*SpamResult, error)
+	FlagPost(postID, userID uint, result *SpamResult)
+	CheckFollow(followerID, followedID uint) error
+	RecordUnfollow(followerID, followedID uint)
+}
+
+// Peso de cada sinal na pontuação de spam
+const (
+	spamWeightDuplicateBurst = 60
+	spamWeightLinkHeavy      = 30
+	spamWeightFollowBurst    = 40
+	spamWeightFollowChurn    = 60
+)
+
+// Janela em que os posts repetidos e os follows são contados
+const spamWindow = time.Hour
+
+// spamLinkPattern encontra os links de um texto, com ou sem o protocolo
+var spamLinkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+`)
+
+type followActivity struct {
+	at         time.Time
+	followedID uint
+	unfollow   bool
+}
+
+type SpamService struct {
+	spamRepo   repositories.SpamRepositoryInterface
+	reportRepo repositories.ReportRepositoryInterface
+	config     *SpamConfig
+
+	// Follows e unfollows recentes por usuário. Ficam em memória, então a
+	// rotatividade é medida por instância da API
+	mu      sync.Mutex
+	follows map[uint][]followActivity
+	sweptAt time.Time
+}
+
+func NewSpamService(spamRepo repositories.SpamRepositoryInterface, reportRepo repositories.ReportRepositoryInterface, config *SpamConfig) SpamServiceInterface {
+	return &SpamService{
+		spamRepo:   spamRepo,
+		reportRepo: reportRepo,
+		config:     config,
+		follows:    make(map[uint][]followActivity),
+		sweptAt:    time.Now(),
+	}
+}
+
+// CheckPost avalia o texto de um post antes da criação. Contas limitadas não
+// publicam; acima do limite de pontuação o post é recusado e a conta é
+// limitada e enviada à fila de moderação. Abaixo dele o resultado volta
+// para que o post seja sinalizado com FlagPost depois de criado (nil quando
+// não há sinais)
+func (s *SpamService) CheckPost(userID uint, content string) (*SpamResult, error) {
+	if err := s.checkLimit(userID); err != nil {
+		return nil, err
+	}
+
+	result := &SpamResult{}
+	content = strings.TrimSpace(content)
+	if content != "" && s.config.DuplicateLimit > 0 {
+		// O post em avaliação conta como mais uma repetição
+		count, err := s.spamRepo.CountDuplicatePosts(userID, content, time.Now().Add(-spamWindow))
+		if err != nil {
+			log.Printf("Falha ao contar posts repetidos do usuário %d: %v", userID, err)
+		} else if int(count)+1 >= s.config.DuplicateLimit {
+			result.add(models.SpamSignalDuplicateBurst, spamWeightDuplicateBurst)
+		}
+	}
+	if s.linkHeavy(content) {
+		result.add(models.SpamSignalLinkHeavy, spamWeightLinkHeavy)
+	}
+
+	if result.Score == 0 {
+		return nil, nil
+	}
+	if err := s.enforce(userID, result, "posts"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FlagPost coloca na fila de moderação o post criado com sinais de spam
+// abaixo do limite
+func (s *SpamService) FlagPost(postID, userID uint, result *SpamResult) {
+	if result == nil {
+		return
+	}
+
+	if err := s.reportRepo.Flag(models.ReportTargetPost, postID, userID, models.ReportReasonSpam, result.details("")); err != nil {
+		log.Printf("Falha ao sinalizar post %d como spam: %v", postID, err)
+	}
+}
+
+// CheckFollow avalia um follow antes de ele ser feito; a tentativa entra na
+// contagem da rotatividade mesmo se o follow não acontecer. Contas limitadas
+// não seguem ninguém, e a conta com sinais vai para a fila de moderação
+func (s *SpamService) CheckFollow(followerID, followedID uint) error {
+	if err := s.checkLimit(followerID); err != nil {
+		return err
+	}
+
+	follows, churn := s.recordFollowActivity(followerID, followActivity{at: time.Now(), followedID: followedID})
+
+	result := &SpamResult{}
+	if s.config.FollowBurstLimit > 0 && follows >= s.config.FollowBurstLimit {
+		result.add(models.SpamSignalFollowBurst, spamWeightFollowBurst)
+	}
+	if s.config.FollowChurnLimit > 0 && churn >= s.config.FollowChurnLimit {
+		result.add(models.SpamSignalFollowChurn, spamWeightFollowChurn)
+	}
+
+	if result.Score == 0 {
+		return nil
+	}
+	if err := s.enforce(followerID, result, "follows"); err != nil {
+		return err
+	}
+
+	// Sem conteúdo para sinalizar, a conta vai para a fila mesmo sem limite
+	if err := s.reportRepo.Flag(models.ReportTargetUser, followerID, followerID, models.ReportReasonSpam, result.details("follows")); err != nil {
+		log.Printf("Falha ao sinalizar usuário %d como spam: %v", followerID, err)
+	}
+	return nil
+}
+
+// RecordUnfollow registra o unfollow para a medida de rotatividade
+func (s *SpamService) RecordUnfollow(followerID, followedID uint) {
+	s.recordFollowActivity(followerID, followActivity{at: time.Now(), followedID: followedID, unfollow: true})
+}
+
+// checkLimit recusa a ação da conta limitada por spam; falhas na consulta não
+// bloqueiam ninguém
+func (s *SpamService) checkLimit(userID uint) error {
+	user, err := s.spamRepo.GetSpamLimit(userID)
+	if err != nil {
+		return nil
+	}
+	if user.SpamLimited(time.Now()) {
+		return spamLimitError(*user.SpamLimitedUntil)
+	}
+	return nil
+}
+
+// enforce limita a conta cuja pontuação atingiu o limite configurado e a
+// envia à fila de moderação, devolvendo o erro mostrado ao usuário
+func (s *SpamService) enforce(userID uint, result *SpamResult, activity string) error {
+	if s.config.LimitThreshold <= 0 || result.Score < s.config.LimitThreshold {
+		return nil
+	}
+
+	until := time.Now().Add(time.Duration(s.config.LimitHours) * time.Hour)
+	limited, err := s.spamRepo.LimitUser(userID, until)
+	if err != nil {
+		log.Printf("Falha ao limitar usuário %d por spam: %v", userID, err)
+		return nil
+	}
+	if !limited {
+		// Administradores não são limitados
+		return nil
+	}
+
+	log.Printf("Usuário %d limitado por spam em %s até %s: %s", userID, activity, until.UTC().Format(time.RFC3339), strings.Join(result.Signals, ", "))
+	details := truncateString(result.details(activity)+"; limitado até "+until.UTC().Format("02/01/2006 15:04")+" (UTC)", 500)
+	if err := s.reportRepo.Flag(models.ReportTargetUser, userID, userID, models.ReportReasonSpam, details); err != nil {
+		log.Printf("Falha ao sinalizar usuário %d como spam: %v", userID, err)
+	}
+	return spamLimitError(until)
+}
+
+// linkHeavy identifica posts com links demais ou feitos quase só de links
+func (s *SpamService) linkHeavy(content string) bool {
+	if s.config.MaxLinks <= 0 {
+		return false
+	}
+
+	links := spamLinkPattern.FindAllString(content, -1)
+	if len(links) > s.config.MaxLinks {
+		return true
+	}
+	if len(links) < 2 {
+		return false
+	}
+
+	linkLength := 0
+	for _, link := range links {
+		linkLength += utf8.RuneCountInString(link)
+	}
+	return linkLength*2 > utf8.RuneCountInString(content)
+}
+
+// recordFollowActivity guarda o follow ou unfollow e devolve, na janela, os
+// follows feitos e as contas seguidas e deixadas de seguir
+func (s *SpamService) recordFollowActivity(userID uint, activity followActivity) (follows, churn int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since := activity.at.Add(-spamWindow)
+	if activity.at.Sub(s.sweptAt) >= spamWindow {
+		for id, activities := range s.follows {
+			if activities[len(activities)-1].at.Before(since) {
+				delete(s.follows, id)
+			}
+		}
+		s.sweptAt = activity.at
+	}
+
+	recent := s.follows[userID][:0]
+	for _, previous := range s.follows[userID] {
+		if !previous.at.Before(since) {
+			recent = append(recent, previous)
+		}
+	}
+	recent = append(recent, activity)
+	s.follows[userID] = recent
+
+	followed := make(map[uint]bool)
+	for _, previous := range recent {
+		switch {
+		case !previous.unfollow:
+			follows++
+			followed[previous.followedID] = true
+		case followed[previous.followedID]:
+			churn++
+			delete(followed, previous.followedID)
+		}
+	}
+	return follows, churn
+}
+
+func (r *SpamResult) add(signal string, weight int) {
+	r.Signals = append(r.Signals, signal)
+	r.Score += weight
+	if r.Score > 100 {
+		r.Score = 100
+	}
+}
+
+// details descreve a avaliação para a moderação
+func (r *SpamResult) details(activity string) string {
+	details := fmt.Sprintf("Sinais de spam: %s (pontuação %d)", strings.Join(r.Signals, ", "), r.Score)
+	if activity != "" {
+		details += " em " + activity
+	}
+	return details
+}
+
+func spamLimitError(until time.Time) error {
+	return fmt.Errorf("conta limitada por suspeita de spam até %s (UTC)", until.UTC().Format("02/01/2006 15:04"))
+}
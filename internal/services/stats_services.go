@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type StatsServiceInterface interface {
+	GetPublicStats() (*models.PlatformStats, error)
+	RefreshStats() error
+	StartStatsScheduler(interval time.Duration)
+}
+
+// StatsService serve as estatísticas públicas a partir do último cálculo, sem
+// consultas na requisição
+type StatsService struct {
+	statsRepo repositories.StatsRepositoryInterface
+
+	mu     sync.RWMutex
+	latest *models.PlatformStats
+}
+
+func NewStatsService(statsRepo repositories.StatsRepositoryInterface) StatsServiceInterface {
+	return &StatsService{
+		statsRepo: statsRepo,
+	}
+}
+
+func (s *StatsService) GetPublicStats() (*models.PlatformStats, error) {
+	s.mu.RLock()
+	latest := s.latest
+	s.mu.RUnlock()
+	if latest != nil {
+		return latest, nil
+	}
+
+	// Antes do primeiro cálculo desta instância, usar o último gravado
+	stored, err := s.statsRepo.GetLatest()
+	if err != nil {
+		return nil, errors.New("estatísticas ainda não disponíveis")
+	}
+
+	s.mu.Lock()
+	if s.latest == nil {
+		s.latest = stored
+	}
+	s.mu.Unlock()
+
+	return stored, nil
+}
+
+// RefreshStats recalcula os números, grava o retrato e atualiza o cache
+func (s *StatsService) RefreshStats() error {
+	stats, err := s.statsRepo.Compute()
+	if err != nil {
+		return errors.New("erro ao calcular estatísticas")
+	}
+	stats.ComputedAt = time.Now()
+
+	if err := s.statsRepo.Save(stats); err != nil {
+		return errors.New("erro ao salvar estatísticas")
+	}
+
+	s.mu.Lock()
+	s.latest = stats
+	s.mu.Unlock()
+
+	return nil
+}
+
+// StartStatsScheduler calcula as estatísticas na inicialização e depois a
+// cada intervalo
+func (s *StatsService) StartStatsScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.RefreshStats(); err != nil {
+				log.Println("Falha ao calcular estatísticas públicas:", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	azureAPIVersion     = "2021-08-06"
+	azureRequestTimeout = 5 * time.Minute
+)
+
+type AzureConfig struct {
+	AccountName string
+	AccountKey  string // chave de acesso da conta, em base64
+	Container   string
+	// Container sem acesso público para os arquivos privados; vazio usa
+	// Container, que então precisa ter o acesso público desligado
+	PrivateContainer string
+	CDNUrl           string
+	// Opcional; padrão https://<conta>.blob.core.windows.net (o Azurite usa
+	// http://127.0.0.1:10000/<conta>)
+	Endpoint string
+}
+
+// azureStorage usa a API REST do Blob Storage assinando cada requisição com
+// a chave da conta (Shared Key)
+type azureStorage struct {
+	config   *AzureConfig
+	endpoint string
+	key      []byte
+	client   *http.Client
+	err      error
+}
+
+func newAzureStorage(config *AzureConfig) *azureStorage {
+	if config == nil || config.AccountName == "" || config.Container == "" {
+		return &azureStorage{err: errors.New("configuração do Azure Blob Storage não encontrada")}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+	if err != nil || len(key) == 0 {
+		return &azureStorage{config: config, err: errors.New("chave da conta do Azure inválida")}
+	}
+
+	endpoint := strings.TrimRight(config.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+
+	if config.PrivateContainer == "" {
+		log.Println("AZURE_STORAGE_PRIVATE_CONTAINER não configurado; os anexos das conversas ficam no container público, em private/")
+	}
+
+	return &azureStorage{
+		config:   config,
+		endpoint: endpoint,
+		key:      key,
+		client:   &http.Client{Timeout: azureRequestTimeout},
+	}
+}
+
+func (a *azureStorage) Name() string { return "azure" }
+
+func (a *azureStorage) Put(key string, src io.Reader, contentType string) error {
+	body, size, cleanup, err := sizedBody(src)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	req, err := http.NewRequest(http.MethodPut, a.blobURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-blob-content-type", contentType)
+
+	resp, err := a.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (a *azureStorage) Open(key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, a.blobURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (a *azureStorage) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, a.blobURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.do(req)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (a *azureStorage) URL(key string) string {
+	if a.config == nil {
+		return key
+	}
+	if a.config.CDNUrl != "" {
+		return publicURL(a.config.CDNUrl, key)
+	}
+	return a.blobURL(key)
+}
+
+func (a *azureStorage) blobURL(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	container := a.config.Container
+	if isPrivateKey(key) && a.config.PrivateContainer != "" {
+		container = a.config.PrivateContainer
+	}
+	return fmt.Sprintf("%s/%s/%s", a.endpoint, url.PathEscape(container), strings.Join(segments, "/"))
+}
+
+// do assina e envia a requisição; respostas fora de 2xx viram erro (404
+// vira os.ErrNotExist) e o corpo é fechado
+func (a *azureStorage) do(req *http.Request) (*http.Response, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", a.config.AccountName, a.sign(req)))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, os.ErrNotExist
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("azure blob storage: status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// sign calcula a assinatura Shared Key: HMAC-SHA256 dos cabeçalhos padrão,
+// dos cabeçalhos x-ms-* e do recurso canônico
+func (a *azureStorage) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	var msHeaders []string
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			msHeaders = append(msHeaders, lower)
+		}
+	}
+	sort.Strings(msHeaders)
+
+	var b strings.Builder
+	for _, value := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date (usa x-ms-date)
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+	for _, name := range msHeaders {
+		b.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+
+	b.WriteString("/" + a.config.AccountName + req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		values := query[name]
+		sort.Strings(values)
+		b.WriteString("\n" + strings.ToLower(name) + ":" + strings.Join(values, ","))
+	}
+
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	gcsAPIURL         = "https://storage.googleapis.com"
+	gcsScope          = "https://www.googleapis.com/auth/devstorage.read_write"
+	gcsMetadataToken  = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	gcsTokenLeeway    = time.Minute
+	gcsRequestTimeout = 5 * time.Minute
+)
+
+type GCSConfig struct {
+	Bucket string
+	// Arquivo JSON da conta de serviço; vazio usa a conta da instância
+	// (servidor de metadados do GCE/Cloud Run)
+	CredentialsFile string
+	CDNUrl          string
+	APIURL          string // opcional (emuladores)
+	// Bucket sem acesso público para os arquivos privados; vazio usa Bucket,
+	// que então não pode liberar leitura pública em private/
+	PrivateBucket string
+}
+
+// gcsStorage usa a API JSON do Cloud Storage com um token OAuth da conta de
+// serviço, renovado antes de expirar
+type gcsStorage struct {
+	config  *GCSConfig
+	apiURL  string
+	client  *http.Client
+	account *gcsServiceAccount
+	err     error
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+type gcsServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func newGCSStorage(config *GCSConfig) *gcsStorage {
+	if config == nil || config.Bucket == "" {
+		return &gcsStorage{err: errors.New("configuração do Google Cloud Storage não encontrada")}
+	}
+
+	storage := &gcsStorage{
+		config: config,
+		apiURL: strings.TrimRight(config.APIURL, "/"),
+		client: &http.Client{Timeout: gcsRequestTimeout},
+	}
+	if storage.apiURL == "" {
+		storage.apiURL = gcsAPIURL
+	}
+	if config.PrivateBucket == "" {
+		log.Println("GCS_PRIVATE_BUCKET não configurado; os anexos das conversas ficam no bucket público, em private/")
+	}
+
+	if config.CredentialsFile != "" {
+		data, err := os.ReadFile(config.CredentialsFile)
+		if err != nil {
+			storage.err = fmt.Errorf("erro ao ler credenciais do Google Cloud: %v", err)
+			return storage
+		}
+		var account gcsServiceAccount
+		if err := json.Unmarshal(data, &account); err != nil || account.ClientEmail == "" || account.PrivateKey == "" {
+			storage.err = errors.New("credenciais do Google Cloud inválidas")
+			return storage
+		}
+		if account.TokenURI == "" {
+			account.TokenURI = "https://oauth2.googleapis.com/token"
+		}
+		storage.account = &account
+	}
+
+	return storage
+}
+
+func (g *gcsStorage) Name() string { return "gcs" }
+
+func (g *gcsStorage) Put(key string, src io.Reader, contentType string) error {
+	body, size, cleanup, err := sizedBody(src)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	endpoint := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		g.apiURL, url.PathEscape(g.bucket(key)), url.QueryEscape(key))
+	req, err := http.NewRequest(http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := g.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (g *gcsStorage) Open(key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, g.objectURL(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (g *gcsStorage) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, g.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := g.do(req)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (g *gcsStorage) URL(key string) string {
+	if g.config == nil {
+		return key
+	}
+	if g.config.CDNUrl != "" {
+		return publicURL(g.config.CDNUrl, key)
+	}
+	return fmt.Sprintf("%s/%s/%s", gcsAPIURL, g.config.Bucket, key)
+}
+
+func (g *gcsStorage) objectURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", g.apiURL, url.PathEscape(g.bucket(key)), url.PathEscape(key))
+}
+
+func (g *gcsStorage) bucket(key string) string {
+	if isPrivateKey(key) && g.config.PrivateBucket != "" {
+		return g.config.PrivateBucket
+	}
+	return g.config.Bucket
+}
+
+// do envia a requisição autenticada; respostas fora de 2xx viram erro (404
+// vira os.ErrNotExist) e o corpo é fechado
+func (g *gcsStorage) do(req *http.Request) (*http.Response, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	token, err := g.accessToken()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, os.ErrNotExist
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("google cloud storage: status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// accessToken devolve o token em cache ou obtém um novo: pela conta de
+// serviço (JWT assinado) ou pelo servidor de metadados da instância
+func (g *gcsStorage) accessToken() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.token != "" && time.Now().Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+
+	var req *http.Request
+	var err error
+	if g.account != nil {
+		req, err = g.serviceAccountTokenRequest()
+	} else {
+		req, err = http.NewRequest(http.MethodGet, gcsMetadataToken, nil)
+		if err == nil {
+			req.Header.Set("Metadata-Flavor", "Google")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("erro ao obter token do Google Cloud: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&result) != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("erro ao obter token do Google Cloud: status %d", resp.StatusCode)
+	}
+
+	g.token = result.AccessToken
+	g.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - gcsTokenLeeway)
+	return g.token, nil
+}
+
+func (g *gcsStorage) serviceAccountTokenRequest() (*http.Request, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(g.account.PrivateKey))
+	if err != nil {
+		return nil, errors.New("chave privada do Google Cloud inválida")
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   g.account.ClientEmail,
+		"scope": gcsScope,
+		"aud":   g.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequest(http.MethodPost, g.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
@@ -0,0 +1,110 @@
+//go:build integration
+
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Roda contra o MinIO do docker-compose:
+//
+//	docker-compose --profile minio up -d minio
+//	go test -tags integration -run MinIO ./internal/services/
+//
+// MINIO_ENDPOINT, MINIO_ROOT_USER e MINIO_ROOT_PASSWORD apontam para outro
+// servidor
+
+func minioTestConfig() *AWSConfig {
+	return &AWSConfig{
+		Region:         "us-east-1",
+		Bucket:         "guia-integration",
+		AccessKey:      getTestEnv("MINIO_ROOT_USER", "guia_minio"),
+		SecretKey:      getTestEnv("MINIO_ROOT_PASSWORD", "guia_minio_password"),
+		Endpoint:       getTestEnv("MINIO_ENDPOINT", "http://localhost:9000"),
+		ForcePathStyle: true,
+	}
+}
+
+func getTestEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// prepareMinIOBucket cria o bucket de teste com leitura pública, como o
+// README pede para o bucket de mídia
+func prepareMinIOBucket(t *testing.T, storage *s3Storage) {
+	t.Helper()
+
+	client := s3.New(storage.sess)
+	bucket := storage.config.Bucket
+	if _, err := client.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		var aerr awserr.Error
+		if !errors.As(err, &aerr) || (aerr.Code() != s3.ErrCodeBucketAlreadyOwnedByYou && aerr.Code() != s3.ErrCodeBucketAlreadyExists) {
+			t.Fatalf("CreateBucket: %v", err)
+		}
+	}
+
+	policy := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/*"]}]}`, bucket)
+	if _, err := client.PutBucketPolicy(&s3.PutBucketPolicyInput{Bucket: aws.String(bucket), Policy: aws.String(policy)}); err != nil {
+		t.Fatalf("PutBucketPolicy: %v", err)
+	}
+}
+
+func TestMinIOStorageRoundTrip(t *testing.T) {
+	storage := newS3Storage(minioTestConfig())
+	if storage.err != nil {
+		t.Fatalf("newS3Storage: %v", storage.err)
+	}
+	prepareMinIOBucket(t, storage)
+
+	key := fmt.Sprintf("integration/%d.txt", time.Now().UnixNano())
+	content := []byte("guIA integration test")
+
+	if err := storage.Put(key, bytes.NewReader(content), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	reader, err := storage.Open(key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatalf("ler objeto: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("Open devolveu %q, want %q", got, content)
+	}
+
+	// A URL pública precisa servir o mesmo conteúdo
+	resp, err := http.Get(storage.URL(key))
+	if err != nil {
+		t.Fatalf("GET %s: %v", storage.URL(key), err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+		t.Fatalf("GET %s = %d %q, want 200 %q", storage.URL(key), resp.StatusCode, body, content)
+	}
+
+	if err := storage.Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := storage.Open(key); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Open depois do Delete = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// StorageBackend é onde os arquivos das mídias ficam guardados. As chaves são
+// caminhos relativos ("images/arquivo.jpg"), gravados em Media.FilePath.
+// Chaves em private/ (anexos das conversas) são gravadas sem acesso público e
+// só são lidas pelo servidor, por Open
+type StorageBackend interface {
+	Name() string
+	Put(key string, src io.Reader, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	// Delete não falha se o arquivo já não existe
+	Delete(key string) error
+	// URL é o endereço público do arquivo (CDN, se configurada)
+	URL(key string) string
+}
+
+// privateStoragePrefix separa os arquivos privados no storage
+const privateStoragePrefix = "private/"
+
+func isPrivateKey(key string) bool {
+	return strings.HasPrefix(key, privateStoragePrefix)
+}
+
+// NewStorageBackend escolhe o storage pelo MediaConfig.StorageType; tipos
+// desconhecidos usam o disco local
+func NewStorageBackend(config *MediaConfig) StorageBackend {
+	switch config.StorageType {
+	case "s3":
+		return newS3Storage(config.AWSConfig)
+	case "gcs":
+		return newGCSStorage(config.GCSConfig)
+	case "azure":
+		return newAzureStorage(config.AzureConfig)
+	case "local", "":
+	default:
+		log.Printf("Storage de mídia desconhecido %q; usando o disco local", config.StorageType)
+	}
+	return &localStorage{root: config.LocalPath, privateRoot: config.PrivateLocalPath, baseURL: strings.TrimRight(config.BaseURL, "/")}
+}
+
+// publicURL monta a URL de um arquivo a partir da base (CDN ou endpoint)
+func publicURL(base, key string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimRight(base, "/"), key)
+}
+
+// sizedBody garante que o tamanho do conteúdo é conhecido, exigido pelas APIs
+// REST de upload. Conteúdos de tamanho desconhecido vão para um arquivo
+// temporário, removido por cleanup
+func sizedBody(src io.Reader) (body io.Reader, size int64, cleanup func(), err error) {
+	cleanup = func() {}
+
+	switch v := src.(type) {
+	case *bytes.Reader:
+		return v, int64(v.Len()), cleanup, nil
+	case *bytes.Buffer:
+		return v, int64(v.Len()), cleanup, nil
+	case *strings.Reader:
+		return v, int64(v.Len()), cleanup, nil
+	case *os.File:
+		info, err := v.Stat()
+		if err == nil && info.Mode().IsRegular() {
+			if offset, err := v.Seek(0, io.SeekCurrent); err == nil {
+				return v, info.Size() - offset, cleanup, nil
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "guia-storage-*")
+	if err != nil {
+		return nil, 0, cleanup, err
+	}
+	cleanup = func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	size, err = io.Copy(tmp, src)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, 0, func() {}, err
+	}
+	return tmp, size, cleanup, nil
+}
+
+// ============================================================================
+// DISCO LOCAL
+// ============================================================================
+
+// localStorage grava os arquivos privados fora do diretório servido em
+// /uploads
+type localStorage struct {
+	root        string
+	privateRoot string
+	baseURL     string
+}
+
+func (l *localStorage) Name() string { return "local" }
+
+func (l *localStorage) path(key string) string {
+	if isPrivateKey(key) {
+		return filepath.Join(l.privateRoot, strings.TrimPrefix(key, privateStoragePrefix))
+	}
+	return filepath.Join(l.root, key)
+}
+
+func (l *localStorage) Put(key string, src io.Reader, contentType string) error {
+	fullPath := l.path(key)
+
+	// Criar diretório se não existir
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(fullPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return err
+	}
+	return dst.Close()
+}
+
+func (l *localStorage) Open(key string) (io.ReadCloser, error) {
+	return os.Open(l.path(key))
+}
+
+func (l *localStorage) Delete(key string) error {
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *localStorage) URL(key string) string {
+	return publicURL(l.baseURL, key)
+}
+
+// ============================================================================
+// AMAZON S3
+// ============================================================================
+
+type s3Storage struct {
+	config *AWSConfig
+	sess   *session.Session
+	err    error
+}
+
+func newS3Storage(config *AWSConfig) *s3Storage {
+	if config == nil {
+		return &s3Storage{err: fmt.Errorf("configuração AWS não encontrada")}
+	}
+
+	awsConfig := &aws.Config{
+		Region: aws.String(config.Region),
+		Credentials: credentials.NewStaticCredentials(
+			config.AccessKey,
+			config.SecretKey,
+			"",
+		),
+		S3ForcePathStyle: aws.Bool(config.ForcePathStyle),
+	}
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	return &s3Storage{config: config, sess: sess, err: err}
+}
+
+func (b *s3Storage) Name() string { return "s3" }
+
+func (b *s3Storage) Put(key string, src io.Reader, contentType string) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	acl := "public-read"
+	if isPrivateKey(key) {
+		acl = "private"
+	}
+
+	_, err := s3manager.NewUploader(b.sess).Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(b.config.Bucket),
+		Key:         aws.String(key),
+		Body:        src,
+		ContentType: aws.String(contentType),
+		ACL:         aws.String(acl),
+	})
+	return err
+}
+
+func (b *s3Storage) Open(key string) (io.ReadCloser, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	output, err := s3.New(b.sess).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(b.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+func (b *s3Storage) Delete(key string) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	_, err := s3.New(b.sess).DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(b.config.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (b *s3Storage) URL(key string) string {
+	if b.config == nil {
+		return key
+	}
+	if b.config.CDNUrl != "" {
+		return publicURL(b.config.CDNUrl, key)
+	}
+	if b.config.Endpoint != "" {
+		return s3EndpointURL(b.config.Endpoint, b.config.Bucket, key, b.config.ForcePathStyle)
+	}
+	if b.config.ForcePathStyle {
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", b.config.Region, b.config.Bucket, key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", b.config.Bucket, b.config.Region, key)
+}
+
+// s3EndpointURL monta a URL pública num endpoint próprio: com o bucket no
+// caminho (path-style) ou como subdomínio do endpoint
+func s3EndpointURL(endpoint, bucket, key string, pathStyle bool) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		// Endpoint sem esquema ("minio:9000"), como o SDK aceita
+		parsed, err = url.Parse("https://" + endpoint)
+		if err != nil {
+			return publicURL(endpoint, bucket+"/"+key)
+		}
+	}
+
+	if pathStyle {
+		parsed.Path = strings.TrimRight(parsed.Path, "/") + "/" + bucket + "/" + key
+	} else {
+		parsed.Host = bucket + "." + parsed.Host
+		parsed.Path = strings.TrimRight(parsed.Path, "/") + "/" + key
+	}
+	return parsed.String()
+}
@@ -0,0 +1,65 @@
+package services
+
+import "testing"
+
+func TestS3EndpointURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		pathStyle bool
+		want      string
+	}{
+		{"path-style", "http://localhost:9000", true, "http://localhost:9000/guia/images/a.jpg"},
+		{"path-style com barra final", "http://localhost:9000/", true, "http://localhost:9000/guia/images/a.jpg"},
+		{"path-style com prefixo", "https://storage.example.com/s3", true, "https://storage.example.com/s3/guia/images/a.jpg"},
+		{"virtual-hosted", "https://storage.example.com", false, "https://guia.storage.example.com/images/a.jpg"},
+		{"sem esquema", "minio:9000", true, "https://minio:9000/guia/images/a.jpg"},
+		{"sem esquema virtual-hosted", "minio:9000", false, "https://guia.minio:9000/images/a.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s3EndpointURL(tt.endpoint, "guia", "images/a.jpg", tt.pathStyle); got != tt.want {
+				t.Errorf("s3EndpointURL(%q, %v) = %q, want %q", tt.endpoint, tt.pathStyle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3StorageURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *AWSConfig
+		want   string
+	}{
+		{
+			name:   "aws",
+			config: &AWSConfig{Region: "sa-east-1", Bucket: "guia"},
+			want:   "https://guia.s3.sa-east-1.amazonaws.com/images/a.jpg",
+		},
+		{
+			name:   "aws path-style",
+			config: &AWSConfig{Region: "sa-east-1", Bucket: "guia", ForcePathStyle: true},
+			want:   "https://s3.sa-east-1.amazonaws.com/guia/images/a.jpg",
+		},
+		{
+			name:   "endpoint próprio",
+			config: &AWSConfig{Region: "us-east-1", Bucket: "guia", Endpoint: "http://localhost:9000", ForcePathStyle: true},
+			want:   "http://localhost:9000/guia/images/a.jpg",
+		},
+		{
+			name:   "cdn tem prioridade sobre o endpoint",
+			config: &AWSConfig{Region: "us-east-1", Bucket: "guia", Endpoint: "http://localhost:9000", ForcePathStyle: true, CDNUrl: "https://cdn.example.com"},
+			want:   "https://cdn.example.com/images/a.jpg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &s3Storage{config: tt.config}
+			if got := storage.URL("images/a.jpg"); got != tt.want {
+				t.Errorf("URL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
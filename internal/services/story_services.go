@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const storyLifetime = 24 * time.Hour
+
+type StoryServiceInterface interface {
+	CreateStory(userID uint, req *CreateStoryRequest) (*models.StoryResponse, error)
+	GetStoriesFeed(userID uint) ([]models.StoryGroup, error)
+	ViewStory(storyID, userID uint) (*models.StoryResponse, error)
+	GetStoryViewers(storyID, userID uint, limit, offset int) ([]models.StoryViewerResponse, error)
+	GetArchivedStories(userID uint, limit, offset int) ([]models.StoryResponse, error)
+	DeleteStory(storyID, userID uint) error
+	ArchiveExpiredStories() (int64, error)
+	StartStoryCleanupScheduler(interval time.Duration)
+}
+
+type StoryService struct {
+	storyRepo repositories.StoryRepositoryInterface
+	userRepo  repositories.UserRepositoryInterface
+	mediaRepo repositories.MediaRepositoryInterface
+}
+
+type CreateStoryRequest struct {
+	MediaURL string `json:"media_url" binding:"required"`
+	Caption  string `json:"caption,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+func NewStoryService(storyRepo repositories.StoryRepositoryInterface, userRepo repositories.UserRepositoryInterface, mediaRepo repositories.MediaRepositoryInterface) StoryServiceInterface {
+	return &StoryService{
+		storyRepo: storyRepo,
+		userRepo:  userRepo,
+		mediaRepo: mediaRepo,
+	}
+}
+
+func (s *StoryService) CreateStory(userID uint, req *CreateStoryRequest) (*models.StoryResponse, error) {
+	if err := s.validateCreateStoryRequest(req); err != nil {
+		return nil, err
+	}
+
+	records, err := s.mediaRepo.GetByURLs([]string{req.MediaURL})
+	if err != nil {
+		return nil, errors.New("erro ao verificar mídia")
+	}
+	if len(records) == 0 || records[0].OwnerID != userID {
+		return nil, errors.New("mídia desconhecida ou enviada por outro usuário")
+	}
+	media := records[0]
+	if err := checkMediaModeration(&media); err != nil {
+		return nil, err
+	}
+
+	story := &models.Story{
+		AuthorID:  userID,
+		MediaID:   media.ID,
+		MediaURL:  media.URL,
+		MediaType: media.MediaType,
+		Caption:   strings.TrimSpace(req.Caption),
+		Location:  strings.TrimSpace(req.Location),
+		ExpiresAt: time.Now().Add(storyLifetime),
+	}
+
+	if err := s.storyRepo.Create(story); err != nil {
+		return nil, errors.New("erro ao criar story")
+	}
+
+	return story.ToResponse(userID), nil
+}
+
+// GetStoriesFeed retorna os stories ativos agrupados por autor: primeiro os do
+// próprio usuário, depois os autores com stories não vistos e, por fim, os mais recentes
+func (s *StoryService) GetStoriesFeed(userID uint) ([]models.StoryGroup, error) {
+	stories, err := s.storyRepo.GetActiveForViewer(userID, time.Now())
+	if err != nil {
+		return nil, errors.New("erro ao buscar stories")
+	}
+
+	storyIDs := make([]uint, len(stories))
+	for i, story := range stories {
+		storyIDs[i] = story.ID
+	}
+	viewed, err := s.storyRepo.GetViewedIDs(userID, storyIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar stories")
+	}
+
+	var groups []models.StoryGroup
+	index := make(map[uint]int)
+	for i := range stories {
+		story := &stories[i]
+		position, ok := index[story.AuthorID]
+		if !ok {
+			position = len(groups)
+			index[story.AuthorID] = position
+			groups = append(groups, models.StoryGroup{
+				Author:  *story.Author.ToResponse(),
+				Stories: []models.StoryResponse{},
+			})
+		}
+
+		response := story.ToResponse(userID)
+		if viewed[story.ID] {
+			response.Viewed = true
+		}
+
+		group := &groups[position]
+		group.Stories = append(group.Stories, *response)
+		if !response.Viewed {
+			group.HasUnseen = true
+		}
+		if story.CreatedAt.After(group.LatestAt) {
+			group.LatestAt = story.CreatedAt
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Author.ID == userID) != (b.Author.ID == userID) {
+			return a.Author.ID == userID
+		}
+		if a.HasUnseen != b.HasUnseen {
+			return a.HasUnseen
+		}
+		return a.LatestAt.After(b.LatestAt)
+	})
+
+	if groups == nil {
+		groups = []models.StoryGroup{}
+	}
+	return groups, nil
+}
+
+// ViewStory retorna o story e registra a visualização de quem não é o autor
+func (s *StoryService) ViewStory(storyID, userID uint) (*models.StoryResponse, error) {
+	story, err := s.storyRepo.GetByID(storyID)
+	if err != nil {
+		return nil, errors.New("story não encontrado")
+	}
+
+	if story.AuthorID == userID {
+		return story.ToResponse(userID), nil
+	}
+
+	if story.ArchivedAt != nil || !story.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("story não encontrado")
+	}
+
+	following, err := s.userRepo.IsFollowing(userID, story.AuthorID)
+	if err != nil {
+		return nil, errors.New("erro ao verificar seguidores")
+	}
+	if !following {
+		return nil, errors.New("você não tem permissão para ver este story")
+	}
+
+	if err := s.storyRepo.RecordView(story.ID, userID); err != nil {
+		return nil, errors.New("erro ao registrar visualização")
+	}
+
+	response := story.ToResponse(userID)
+	response.Viewed = true
+	return response, nil
+}
+
+func (s *StoryService) GetStoryViewers(storyID, userID uint, limit, offset int) ([]models.StoryViewerResponse, error) {
+	story, err := s.storyRepo.GetByID(storyID)
+	if err != nil {
+		return nil, errors.New("story não encontrado")
+	}
+	if story.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para ver quem visualizou este story")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	views, err := s.storyRepo.GetViewers(storyID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar visualizações")
+	}
+
+	viewers := make([]models.StoryViewerResponse, len(views))
+	for i, view := range views {
+		viewers[i] = models.StoryViewerResponse{
+			Viewer:   *view.Viewer.ToResponse(),
+			ViewedAt: view.CreatedAt,
+		}
+	}
+
+	return viewers, nil
+}
+
+func (s *StoryService) GetArchivedStories(userID uint, limit, offset int) ([]models.StoryResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	stories, err := s.storyRepo.GetArchivedByAuthor(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar stories arquivados")
+	}
+
+	responses := make([]models.StoryResponse, len(stories))
+	for i := range stories {
+		responses[i] = *stories[i].ToResponse(userID)
+	}
+
+	return responses, nil
+}
+
+func (s *StoryService) DeleteStory(storyID, userID uint) error {
+	story, err := s.storyRepo.GetByID(storyID)
+	if err != nil {
+		return errors.New("story não encontrado")
+	}
+	if story.AuthorID != userID {
+		return errors.New("você não tem permissão para deletar este story")
+	}
+
+	if err := s.storyRepo.Delete(story); err != nil {
+		return errors.New("erro ao deletar story")
+	}
+
+	return nil
+}
+
+func (s *StoryService) ArchiveExpiredStories() (int64, error) {
+	archived, err := s.storyRepo.ArchiveExpired(time.Now())
+	if err != nil {
+		return 0, errors.New("erro ao arquivar stories expirados")
+	}
+	return archived, nil
+}
+
+// StartStoryCleanupScheduler arquiva periodicamente em segundo plano os stories expirados
+func (s *StoryService) StartStoryCleanupScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if archived, err := s.ArchiveExpiredStories(); err != nil {
+				log.Println("Falha ao arquivar stories expirados:", err)
+			} else if archived > 0 {
+				log.Printf("%d stories arquivados", archived)
+			}
+		}
+	}()
+}
+
+// Funções de validação
+
+func (s *StoryService) validateCreateStoryRequest(req *CreateStoryRequest) error {
+	if strings.TrimSpace(req.MediaURL) == "" {
+		return errors.New("mídia é obrigatória")
+	}
+	if len(req.Caption) > 300 {
+		return errors.New("legenda deve ter no máximo 300 caracteres")
+	}
+	if len(req.Location) > 200 {
+		return errors.New("localização deve ter no máximo 200 caracteres")
+	}
+	return nil
+}
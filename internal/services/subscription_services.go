@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const maxSubscriptionMonths = 12
+
+// SubscriptionServiceInterface vende meses do plano guIA Plus, pagos de uma
+// vez; cada compra confirmada estende o período já pago
+type SubscriptionServiceInterface interface {
+	Subscribe(userID uint, req *SubscribeRequest) (*models.SubscriptionIntentResponse, error)
+	ConfirmSubscription(subscriptionID, userID uint) (*models.SubscriptionResponse, error)
+	GetStatus(userID uint) (*models.SubscriptionStatusResponse, error)
+	GetSubscriptions(userID uint, limit, offset int) ([]models.SubscriptionResponse, error)
+}
+
+type SubscribeRequest struct {
+	Months int `json:"months" binding:"required"`
+}
+
+type SubscriptionService struct {
+	subscriptionRepo repositories.SubscriptionRepositoryInterface
+	ledgerService    LedgerServiceInterface
+	provider         BillingProviderInterface
+	config           *BillingConfig
+}
+
+func NewSubscriptionService(
+	subscriptionRepo repositories.SubscriptionRepositoryInterface,
+	ledgerService LedgerServiceInterface,
+	webhookService WebhookServiceInterface,
+	provider BillingProviderInterface,
+	config *BillingConfig,
+) SubscriptionServiceInterface {
+	if config.SubscriptionMonthlyPrice <= 0 {
+		config.SubscriptionMonthlyPrice = 1990
+	}
+	if len(config.SubscriptionCurrency) != 3 {
+		config.SubscriptionCurrency = "BRL"
+	}
+	config.SubscriptionCurrency = strings.ToUpper(config.SubscriptionCurrency)
+
+	service := &SubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		ledgerService:    ledgerService,
+		provider:         provider,
+		config:           config,
+	}
+
+	webhookService.RegisterProcessor(models.WebhookProvider(provider.Name()), service.applyPaymentWebhook)
+
+	return service
+}
+
+func (s *SubscriptionService) Subscribe(userID uint, req *SubscribeRequest) (*models.SubscriptionIntentResponse, error) {
+	if req.Months < 1 || req.Months > maxSubscriptionMonths {
+		return nil, fmt.Errorf("a assinatura deve ter entre 1 e %d meses", maxSubscriptionMonths)
+	}
+
+	amount := int64(req.Months) * s.config.SubscriptionMonthlyPrice
+	currency := s.config.SubscriptionCurrency
+
+	intent, err := s.provider.CreatePaymentIntent(&PaymentIntentParams{
+		Amount:      amount,
+		Currency:    currency,
+		Description: "Assinatura guIA Plus",
+		Metadata: map[string]string{
+			"user_id": strconv.FormatUint(uint64(userID), 10),
+			"months":  strconv.Itoa(req.Months),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &models.Subscription{
+		UserID:            userID,
+		Months:            req.Months,
+		Amount:            amount,
+		Currency:          currency,
+		Status:            models.SubscriptionStatusPending,
+		Provider:          s.provider.Name(),
+		ProviderPaymentID: intent.ID,
+	}
+	if err := s.subscriptionRepo.Create(subscription); err != nil {
+		return nil, errors.New("erro ao registrar assinatura")
+	}
+
+	return &models.SubscriptionIntentResponse{
+		Subscription: subscription.ToResponse(),
+		ClientSecret: intent.ClientSecret,
+	}, nil
+}
+
+// ConfirmSubscription consulta o provedor para atualizar uma compra pendente
+// depois que o usuário conclui o pagamento no app
+func (s *SubscriptionService) ConfirmSubscription(subscriptionID, userID uint) (*models.SubscriptionResponse, error) {
+	subscription, err := s.subscriptionRepo.GetByID(subscriptionID)
+	if err != nil {
+		return nil, errors.New("assinatura não encontrada")
+	}
+
+	if subscription.UserID != userID {
+		return nil, errors.New("você não tem permissão para acessar esta assinatura")
+	}
+
+	if subscription.Status != models.SubscriptionStatusPending {
+		return subscription.ToResponse(), nil
+	}
+
+	intent, err := s.provider.GetPaymentIntent(subscription.ProviderPaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPaymentIntent(subscription, intent); err != nil {
+		return nil, err
+	}
+
+	return subscription.ToResponse(), nil
+}
+
+func (s *SubscriptionService) GetStatus(userID uint) (*models.SubscriptionStatusResponse, error) {
+	until, err := s.subscriptionRepo.GetPaidUntil(userID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar assinatura")
+	}
+
+	// Renovações começam no fim do período anterior, então os períodos pagos
+	// são contínuos e basta o fim do último
+	return &models.SubscriptionStatusResponse{
+		Active:    until != nil && until.After(time.Now()),
+		ExpiresAt: until,
+	}, nil
+}
+
+func (s *SubscriptionService) GetSubscriptions(userID uint, limit, offset int) ([]models.SubscriptionResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	subscriptions, err := s.subscriptionRepo.GetByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar assinaturas")
+	}
+
+	responses := make([]models.SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		responses = append(responses, *subscription.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// applyPaymentIntent reflete o status do pagamento na compra e lança no
+// livro-razão as compras pagas
+func (s *SubscriptionService) applyPaymentIntent(subscription *models.Subscription, intent *PaymentIntent) error {
+	switch intent.Status {
+	case PaymentIntentSucceeded:
+		updated, err := s.subscriptionRepo.MarkPaid(subscription, models.SubscriptionStatusPending)
+		if err != nil {
+			return errors.New("erro ao atualizar assinatura")
+		}
+		if updated {
+			// Falhas aqui aparecem no relatório de conciliação
+			err := s.ledgerService.RecordPlatformSale(models.LedgerKindSubscription, fmt.Sprintf("subscription:%d", subscription.ID),
+				subscription.Currency, subscription.Amount, "Assinatura guIA Plus")
+			if err != nil {
+				log.Printf("Erro ao lançar assinatura %d no livro-razão: %v", subscription.ID, err)
+			}
+		}
+
+	case PaymentIntentFailed:
+		if _, err := s.subscriptionRepo.UpdateStatus(subscription, models.SubscriptionStatusPending, models.SubscriptionStatusFailed); err != nil {
+			return errors.New("erro ao atualizar assinatura")
+		}
+	}
+
+	return nil
+}
+
+// applyPaymentWebhook confirma as compras pendentes quando o provedor avisa
+// que o pagamento mudou de status; eventos de outros pagamentos são ignorados
+func (s *SubscriptionService) applyPaymentWebhook(event *models.WebhookEvent) error {
+	paymentID, err := paymentIntentFromWebhook(event)
+	if err != nil || paymentID == "" {
+		return err
+	}
+
+	subscription, err := s.subscriptionRepo.GetByProviderPaymentID(paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.New("erro ao buscar assinatura")
+	}
+
+	if subscription.Status != models.SubscriptionStatusPending {
+		return nil
+	}
+
+	intent, err := s.provider.GetPaymentIntent(subscription.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPaymentIntent(subscription, intent)
+}
@@ -0,0 +1,411 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/realtime"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Contas com a suspensão vencida reativadas por rodada da rotina
+const unsuspensionBatchSize = 200
+
+type SuspendUserRequest struct {
+	Days   int    `json:"days" binding:"required,min=1,max=365"`
+	Reason string `json:"reason" binding:"required,max=500"` // mostrado ao usuário
+	Note   string `json:"note" binding:"max=1000"`           // nota interna da moderação
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"max=500"` // mostrado ao usuário
+	Note   string `json:"note" binding:"max=1000"`  // nota interna da moderação
+}
+
+// AccountAppealRequest é o recurso contra a suspensão ou o banimento; como a
+// conta não consegue entrar, o usuário se identifica com o login e a senha
+type AccountAppealRequest struct {
+	LoginRequest
+	Message string `json:"message" binding:"required,max=2000"`
+}
+
+type ReviewAppealRequest struct {
+	Accept bool   `json:"accept"`
+	Note   string `json:"note" binding:"max=1000"` // mostrada ao usuário
+}
+
+type SuspensionServiceInterface interface {
+	CheckAccount(userID uint) error
+	SuspendUser(userID, adminID uint, req *SuspendUserRequest) (*models.AdminUserResponse, error)
+	BanUser(userID, adminID uint, req *BanUserRequest) (*models.AdminUserResponse, error)
+	UnbanUser(userID, adminID uint, req *ModerationActionRequest) (*models.AdminUserResponse, error)
+	CreateAppeal(user *models.User, message string) (*models.AccountAppealResponse, error)
+	GetAppeals(status models.AppealStatus, limit, offset int) ([]models.AccountAppealResponse, error)
+	ReviewAppeal(appealID, adminID uint, req *ReviewAppealRequest) (*models.AccountAppealResponse, error)
+	LiftExpiredSuspensions() (int, error)
+	StartUnsuspensionScheduler(interval time.Duration)
+}
+
+type SuspensionService struct {
+	suspensionRepo      repositories.SuspensionRepositoryInterface
+	moderationRepo      repositories.ModerationRepositoryInterface
+	notificationService NotificationServiceInterface
+	hub                 realtime.HubInterface
+}
+
+func NewSuspensionService(suspensionRepo repositories.SuspensionRepositoryInterface, moderationRepo repositories.ModerationRepositoryInterface, notificationService NotificationServiceInterface, hub realtime.HubInterface) SuspensionServiceInterface {
+	return &SuspensionService{
+		suspensionRepo:      suspensionRepo,
+		moderationRepo:      moderationRepo,
+		notificationService: notificationService,
+		hub:                 hub,
+	}
+}
+
+// CheckAccount diz se a conta do token ainda pode usar a API. A consulta é
+// feita a cada requisição (pela chave primária) para que a suspensão e o
+// banimento valham na hora, em todas as instâncias
+func (s *SuspensionService) CheckAccount(userID uint) error {
+	user, err := s.suspensionRepo.GetAccountStatus(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("conta desativada")
+	}
+	if err != nil {
+		// Uma falha do banco não derruba todas as sessões
+		log.Printf("Falha ao verificar a situação da conta %d: %v", userID, err)
+		return nil
+	}
+	return accountRestrictionError(user, time.Now())
+}
+
+// SuspendUser desativa a conta por alguns dias; ela volta sozinha quando o
+// prazo acaba. Uma suspensão em vigor é substituída pela nova
+func (s *SuspensionService) SuspendUser(userID, adminID uint, req *SuspendUserRequest) (*models.AdminUserResponse, error) {
+	user, err := s.moderationRepo.GetUserForModeration(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	if user.UserType == models.UserTypeAdmin {
+		return nil, errors.New("administradores não podem ser suspensos")
+	}
+	switch user.AccountStatus(time.Now()) {
+	case models.AccountStatusBanned:
+		return nil, errors.New("usuário já está banido")
+	case models.AccountStatusDeactivated:
+		return nil, errors.New("conta desativada pelo usuário")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("motivo da suspensão é obrigatório")
+	}
+	until := time.Now().AddDate(0, 0, req.Days)
+
+	suspended, err := s.suspensionRepo.SuspendUser(userID, until, reason, &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionSuspendUser,
+		TargetType: models.ModerationTargetUser,
+		TargetID:   userID,
+		Note:       req.Note,
+	})
+	if err != nil {
+		return nil, errors.New("erro ao suspender usuário")
+	}
+	if !suspended {
+		return nil, errors.New("usuário já está banido")
+	}
+	s.hub.DisconnectUser(userID)
+
+	now := time.Now()
+	user.IsActive = false
+	user.SuspendedAt = &now
+	user.SuspendedUntil = &until
+	user.SuspensionReason = reason
+	return user.ToAdminResponse(), nil
+}
+
+// BanUser desativa a conta por tempo indeterminado; os tokens já emitidos
+// param de funcionar na próxima requisição e as conexões em tempo real são
+// encerradas. A decisão fica na auditoria da moderação
+func (s *SuspensionService) BanUser(userID, adminID uint, req *BanUserRequest) (*models.AdminUserResponse, error) {
+	user, err := s.moderationRepo.GetUserForModeration(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	if user.UserType == models.UserTypeAdmin {
+		return nil, errors.New("administradores não podem ser banidos")
+	}
+	if user.AccountStatus(time.Now()) == models.AccountStatusBanned {
+		return nil, errors.New("usuário já está banido")
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	banned, err := s.moderationRepo.BanUser(userID, reason, &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionBanUser,
+		TargetType: models.ModerationTargetUser,
+		TargetID:   userID,
+		Note:       req.Note,
+	})
+	if err != nil {
+		return nil, errors.New("erro ao banir usuário")
+	}
+	if !banned {
+		return nil, errors.New("usuário já está banido")
+	}
+	s.hub.DisconnectUser(userID)
+
+	now := time.Now()
+	user.IsActive = false
+	user.SuspendedAt = &now
+	user.SuspendedUntil = nil
+	user.SuspensionReason = reason
+	return user.ToAdminResponse(), nil
+}
+
+// UnbanUser reativa uma conta suspensa ou banida antes do prazo
+func (s *SuspensionService) UnbanUser(userID, adminID uint, req *ModerationActionRequest) (*models.AdminUserResponse, error) {
+	user, err := s.moderationRepo.GetUserForModeration(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	status := user.AccountStatus(time.Now())
+	if status != models.AccountStatusSuspended && status != models.AccountStatusBanned {
+		return nil, errors.New("usuário não está suspenso nem banido")
+	}
+
+	unbanned, err := s.suspensionRepo.ReinstateUser(userID, &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionUnbanUser,
+		TargetType: models.ModerationTargetUser,
+		TargetID:   userID,
+		Note:       req.Note,
+	})
+	if err != nil {
+		return nil, errors.New("erro ao desbanir usuário")
+	}
+	if !unbanned {
+		return nil, errors.New("usuário não está suspenso nem banido")
+	}
+
+	user.IsActive = true
+	user.SuspendedAt = nil
+	user.SuspendedUntil = nil
+	user.SuspensionReason = ""
+	return user.ToAdminResponse(), nil
+}
+
+// CreateAppeal registra o recurso do usuário contra a restrição em vigor; só
+// um recurso fica em análise por vez, e cada restrição pode ser contestada
+// uma vez
+func (s *SuspensionService) CreateAppeal(user *models.User, message string) (*models.AccountAppealResponse, error) {
+	status := user.AccountStatus(time.Now())
+	if status != models.AccountStatusSuspended && status != models.AccountStatusBanned {
+		return nil, errors.New("a conta não está suspensa nem banida")
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, errors.New("explique por que a restrição deve ser revista")
+	}
+
+	latest, err := s.suspensionRepo.GetLatestAppeal(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("erro ao buscar recurso")
+	}
+	if latest != nil {
+		if latest.Status == models.AppealPending {
+			return nil, errors.New("já existe um recurso em análise")
+		}
+		if latest.Status == models.AppealRejected && latest.SuspendedAt.Equal(*user.SuspendedAt) {
+			rejection := "recurso já analisado e recusado"
+			if latest.ReviewNote != "" {
+				rejection += ": " + latest.ReviewNote
+			}
+			return nil, errors.New(rejection)
+		}
+	}
+
+	appeal := &models.AccountAppeal{
+		UserID:      user.ID,
+		Status:      models.AppealPending,
+		Message:     message,
+		SuspendedAt: *user.SuspendedAt,
+	}
+	if err := s.suspensionRepo.CreateAppeal(appeal); err != nil {
+		return nil, errors.New("erro ao registrar recurso")
+	}
+
+	return appeal.ToResponse(), nil
+}
+
+func (s *SuspensionService) GetAppeals(status models.AppealStatus, limit, offset int) ([]models.AccountAppealResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if status == "" {
+		status = models.AppealPending
+	}
+	if status != models.AppealPending && status != models.AppealAccepted && status != models.AppealRejected && status != models.AppealClosed {
+		return nil, errors.New("status inválido")
+	}
+
+	appeals, err := s.suspensionRepo.GetAppeals(status, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar recursos")
+	}
+
+	responses := make([]models.AccountAppealResponse, len(appeals))
+	for i := range appeals {
+		responses[i] = *toAdminAppealResponse(&appeals[i])
+	}
+	return responses, nil
+}
+
+// ReviewAppeal aceita (reativando a conta) ou recusa um recurso em análise;
+// o usuário é avisado quando a conta volta
+func (s *SuspensionService) ReviewAppeal(appealID, adminID uint, req *ReviewAppealRequest) (*models.AccountAppealResponse, error) {
+	appeal, err := s.suspensionRepo.GetAppealByID(appealID)
+	if err != nil {
+		return nil, errors.New("recurso não encontrado")
+	}
+	if appeal.Status != models.AppealPending {
+		return nil, errors.New("recurso já analisado")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRejectAppeal,
+		TargetType: models.ModerationTargetAppeal,
+		TargetID:   appeal.ID,
+		Note:       req.Note,
+	}
+	appeal.Status = models.AppealRejected
+	if req.Accept {
+		audit.Action = models.ModerationActionAcceptAppeal
+		appeal.Status = models.AppealAccepted
+	}
+	appeal.ReviewNote = strings.TrimSpace(req.Note)
+
+	reviewed, err := s.suspensionRepo.ReviewAppeal(appeal, audit)
+	if err != nil {
+		return nil, errors.New("erro ao analisar recurso")
+	}
+	if !reviewed {
+		return nil, errors.New("recurso já analisado")
+	}
+
+	if req.Accept {
+		appeal.User.IsActive = true
+		appeal.User.SuspendedAt = nil
+		appeal.User.SuspendedUntil = nil
+		appeal.User.SuspensionReason = ""
+
+		body := "Seu recurso foi aceito e a conta voltou a funcionar"
+		if appeal.ReviewNote != "" {
+			body += ": " + appeal.ReviewNote
+		}
+		s.notifyReinstated(appeal.UserID, fmt.Sprintf("appeal:%d", appeal.ID), body)
+	}
+
+	return toAdminAppealResponse(appeal), nil
+}
+
+// LiftExpiredSuspensions reativa as contas cuja suspensão venceu e avisa os
+// usuários; retorna quantas foram reativadas
+func (s *SuspensionService) LiftExpiredSuspensions() (int, error) {
+	now := time.Now()
+	lifted := 0
+	for {
+		ids, err := s.suspensionRepo.GetExpiredSuspensions(now, unsuspensionBatchSize)
+		if err != nil {
+			return lifted, err
+		}
+
+		progressed := false
+		for _, id := range ids {
+			ok, err := s.suspensionRepo.LiftSuspension(id, now)
+			if err != nil {
+				log.Printf("Falha ao reativar a conta %d: %v", id, err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			progressed = true
+			lifted++
+			s.notifyReinstated(id, fmt.Sprintf("unsuspension:%d:%d", id, now.Unix()), "A suspensão terminou e a conta voltou a funcionar")
+		}
+
+		// Sem progresso, as falhas ficam para a próxima rodada
+		if len(ids) < unsuspensionBatchSize || !progressed {
+			return lifted, nil
+		}
+	}
+}
+
+// StartUnsuspensionScheduler reativa periodicamente as contas com a
+// suspensão vencida
+func (s *SuspensionService) StartUnsuspensionScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if lifted, err := s.LiftExpiredSuspensions(); err != nil {
+				log.Println("Falha ao reativar contas suspensas:", err)
+			} else if lifted > 0 {
+				log.Printf("%d contas reativadas ao fim da suspensão", lifted)
+			}
+		}
+	}()
+}
+
+func (s *SuspensionService) notifyReinstated(userID uint, key, body string) {
+	_, err := s.notificationService.Notify(&models.Notification{
+		UserID: userID,
+		Type:   models.NotificationTypeAccountStatus,
+		Title:  "Sua conta foi reativada",
+		Body:   truncateString(body, 500),
+		Data: map[string]string{
+			"status": string(models.AccountStatusActive),
+		},
+		Key: &key,
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar a reativação da conta %d: %v", userID, err)
+	}
+}
+
+// accountRestrictionError explica por que a conta não pode entrar nem usar
+// os tokens já emitidos; nil se ela está ativa (ou a suspensão já venceu)
+func accountRestrictionError(user *models.User, now time.Time) error {
+	var message string
+	switch user.AccountStatus(now) {
+	case models.AccountStatusActive:
+		return nil
+	case models.AccountStatusDeactivated:
+		return errors.New("conta desativada")
+	case models.AccountStatusSuspended:
+		message = "conta suspensa até " + user.SuspendedUntil.UTC().Format("02/01/2006 15:04") + " (UTC)"
+	default:
+		message = "conta banida"
+	}
+	if user.SuspensionReason != "" {
+		message += ": " + user.SuspensionReason
+	}
+	return errors.New(message)
+}
+
+func toAdminAppealResponse(appeal *models.AccountAppeal) *models.AccountAppealResponse {
+	response := appeal.ToResponse()
+	if appeal.User.ID != 0 {
+		response.User = appeal.User.ToAdminResponse()
+	}
+	return response
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+func TestAccountRestrictionError(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	suspendedAt := now.Add(-24 * time.Hour)
+	until := now.Add(48 * time.Hour)
+	expired := now.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		user models.User
+		want string
+	}{
+		{
+			name: "ativa",
+			user: models.User{IsActive: true},
+		},
+		{
+			name: "desativada pelo usuário",
+			user: models.User{IsActive: false},
+			want: "conta desativada",
+		},
+		{
+			name: "suspensa",
+			user: models.User{SuspendedAt: &suspendedAt, SuspendedUntil: &until},
+			want: "conta suspensa até 12/06/2025 12:00 (UTC)",
+		},
+		{
+			name: "suspensa com motivo",
+			user: models.User{SuspendedAt: &suspendedAt, SuspendedUntil: &until, SuspensionReason: "spam"},
+			want: "conta suspensa até 12/06/2025 12:00 (UTC): spam",
+		},
+		{
+			name: "suspensão vencida antes da reativação",
+			user: models.User{SuspendedAt: &suspendedAt, SuspendedUntil: &expired},
+		},
+		{
+			name: "banida",
+			user: models.User{SuspendedAt: &suspendedAt},
+			want: "conta banida",
+		},
+		{
+			name: "banida com motivo",
+			user: models.User{SuspendedAt: &suspendedAt, SuspensionReason: "fraude"},
+			want: "conta banida: fraude",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := accountRestrictionError(&tt.user, now)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("accountRestrictionError = %v, want nil", err)
+			case tt.want != "" && (err == nil || err.Error() != tt.want):
+				t.Errorf("accountRestrictionError = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
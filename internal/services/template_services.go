@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type TemplateServiceInterface interface {
+	SetTemplate(itineraryID, userID uint, isTemplate bool) (*models.ItineraryResponse, error)
+	GetTemplates(filter repositories.TemplateFilter) ([]models.TemplateResponse, error)
+	InstantiateTemplate(templateID, userID uint) (*models.ItineraryResponse, error)
+	GetTemplateMetrics(companyID uint) ([]models.TemplateMetrics, error)
+}
+
+type SetTemplateRequest struct {
+	IsTemplate *bool `json:"is_template" binding:"required"`
+}
+
+type TemplateService struct {
+	templateRepo  repositories.TemplateRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+}
+
+func NewTemplateService(templateRepo repositories.TemplateRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface) TemplateServiceInterface {
+	return &TemplateService{
+		templateRepo:  templateRepo,
+		itineraryRepo: itineraryRepo,
+	}
+}
+
+// SetTemplate publica (ou retira) um roteiro da empresa no catálogo de
+// modelos; o acesso de empresa é garantido pela rota
+func (s *TemplateService) SetTemplate(itineraryID, userID uint, isTemplate bool) (*models.ItineraryResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	if itinerary.AuthorID != userID {
+		return nil, errors.New("você não tem permissão para editar este roteiro")
+	}
+
+	if isTemplate {
+		if !itinerary.IsPublic {
+			return nil, errors.New("roteiro precisa ser público para ser publicado como modelo")
+		}
+		if len(itinerary.Days) == 0 {
+			return nil, errors.New("roteiro precisa ter ao menos um dia para ser publicado como modelo")
+		}
+	}
+
+	if err := s.templateRepo.SetTemplate(itinerary.ID, isTemplate); err != nil {
+		return nil, errors.New("erro ao atualizar roteiro")
+	}
+
+	itinerary.IsTemplate = isTemplate
+	return itinerary.ToResponse(), nil
+}
+
+func (s *TemplateService) GetTemplates(filter repositories.TemplateFilter) ([]models.TemplateResponse, error) {
+	if filter.Limit <= 0 || filter.Limit > 50 {
+		filter.Limit = 20
+	}
+
+	itineraries, err := s.templateRepo.GetTemplates(filter)
+	if err != nil {
+		return nil, errors.New("erro ao buscar modelos")
+	}
+
+	ids := make([]uint, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		ids = append(ids, itinerary.ID)
+	}
+	uses, err := s.templateRepo.CountUsages(ids)
+	if err != nil {
+		return nil, errors.New("erro ao buscar modelos")
+	}
+
+	templates := make([]models.TemplateResponse, 0, len(itineraries))
+	for i := range itineraries {
+		templates = append(templates, models.TemplateResponse{
+			Itinerary: itineraries[i].ToResponse(),
+			Company:   itineraries[i].Author.ToTemplateCompany(),
+			UsesCount: uses[itineraries[i].ID],
+		})
+	}
+	return templates, nil
+}
+
+// InstantiateTemplate cria uma cópia privada do modelo para o usuário, que
+// passa a editá-la como uma viagem própria
+func (s *TemplateService) InstantiateTemplate(templateID, userID uint) (*models.ItineraryResponse, error) {
+	template, err := s.itineraryRepo.GetByID(templateID)
+	if err != nil || !template.IsTemplate || !template.IsPublic {
+		return nil, errors.New("modelo não encontrado")
+	}
+
+	clone, err := s.itineraryRepo.Clone(template.ID, userID)
+	if err != nil {
+		return nil, errors.New("erro ao criar roteiro a partir do modelo")
+	}
+
+	// Usos da própria empresa não entram nas métricas
+	if template.AuthorID != userID {
+		usage := &models.TemplateUsage{
+			TemplateID:  template.ID,
+			UserID:      userID,
+			ItineraryID: clone.ID,
+		}
+		if err := s.templateRepo.CreateUsage(usage); err != nil {
+			log.Printf("Falha ao registrar uso do modelo %d: %v", template.ID, err)
+		}
+	}
+
+	created, err := s.itineraryRepo.GetByID(clone.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiro criado")
+	}
+	return created.ToResponse(), nil
+}
+
+func (s *TemplateService) GetTemplateMetrics(companyID uint) ([]models.TemplateMetrics, error) {
+	metrics, err := s.templateRepo.GetMetrics(companyID, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return nil, errors.New("erro ao buscar métricas dos modelos")
+	}
+	return metrics, nil
+}
@@ -0,0 +1,496 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type TipServiceInterface interface {
+	CreateTip(supporterID uint, req *CreateTipRequest) (*models.TipIntentResponse, error)
+	ConfirmTip(tipID, userID uint) (*models.TipResponse, error)
+	RefundTip(tipID uint) (*models.TipResponse, error)
+	GetSentTips(userID uint, limit, offset int) ([]models.TipResponse, error)
+	GetReceivedTips(userID uint, limit, offset int) ([]models.TipResponse, error)
+	GetEarnings(userID uint, from, to time.Time) (*EarningsReport, error)
+	SetupPayoutAccount(userID uint, req *PayoutOnboardingRequest) (*models.PayoutAccountResponse, error)
+	GetPayoutAccount(userID uint) (*models.PayoutAccountResponse, error)
+}
+
+type CreateTipRequest struct {
+	CreatorID   uint   `json:"creator_id" binding:"required"`
+	ItineraryID *uint  `json:"itinerary_id"`
+	Amount      int64  `json:"amount" binding:"required"` // em centavos
+	Currency    string `json:"currency"`
+	Message     string `json:"message"`
+
+	BillingCountry string `json:"billing_country"` // país da cobrança, usado na análise antifraude
+	IPCountry      string `json:"-"`               // país da requisição, preenchido pelo handler
+}
+
+type PayoutOnboardingRequest struct {
+	Country    string `json:"country"`
+	RefreshURL string `json:"refresh_url" binding:"required"`
+	ReturnURL  string `json:"return_url" binding:"required"`
+}
+
+type EarningsReport struct {
+	From   time.Time                `json:"from"`
+	To     time.Time                `json:"to"`
+	Totals []models.CreatorEarnings `json:"totals"`
+}
+
+type TipService struct {
+	tipRepo       repositories.TipRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	ledgerService LedgerServiceInterface
+	fraudService  FraudServiceInterface
+	provider      BillingProviderInterface
+	config        *BillingConfig
+}
+
+func NewTipService(
+	tipRepo repositories.TipRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	ledgerService LedgerServiceInterface,
+	fraudService FraudServiceInterface,
+	webhookService WebhookServiceInterface,
+	provider BillingProviderInterface,
+	config *BillingConfig,
+) TipServiceInterface {
+	if config.PlatformFeePercent < 0 || config.PlatformFeePercent > 100 {
+		config.PlatformFeePercent = 10
+	}
+	if config.MinTipAmount <= 0 {
+		config.MinTipAmount = 100
+	}
+	if config.MaxTipAmount <= 0 {
+		config.MaxTipAmount = 100000
+	}
+
+	service := &TipService{
+		tipRepo:       tipRepo,
+		userRepo:      userRepo,
+		itineraryRepo: itineraryRepo,
+		ledgerService: ledgerService,
+		fraudService:  fraudService,
+		provider:      provider,
+		config:        config,
+	}
+
+	fraudService.RegisterReviewHandler(models.FraudSubjectTip, service.applyFraudReview)
+	webhookService.RegisterProcessor(models.WebhookProvider(provider.Name()), service.applyPaymentWebhook)
+
+	return service
+}
+
+func (s *TipService) CreateTip(supporterID uint, req *CreateTipRequest) (*models.TipIntentResponse, error) {
+	if err := s.validateCreateTipRequest(supporterID, req); err != nil {
+		return nil, err
+	}
+
+	supporter, err := s.userRepo.GetByID(supporterID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	if _, err := s.userRepo.GetByID(req.CreatorID); err != nil {
+		return nil, errors.New("criador não encontrado")
+	}
+
+	if req.ItineraryID != nil {
+		itinerary, err := s.itineraryRepo.GetByID(*req.ItineraryID)
+		if err != nil {
+			return nil, errors.New("roteiro não encontrado")
+		}
+		if itinerary.AuthorID != req.CreatorID || !itinerary.IsPublic {
+			return nil, errors.New("roteiro não pertence ao criador")
+		}
+	}
+
+	account, err := s.tipRepo.GetPayoutAccount(req.CreatorID)
+	if err != nil || !account.ChargesEnabled {
+		return nil, errors.New("criador ainda não está habilitado para receber apoio")
+	}
+
+	currency := "BRL"
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+
+	check, err := s.fraudService.Evaluate(&models.FraudCheckInput{
+		Subject:   models.FraudSubjectTip,
+		UserID:    supporterID,
+		Email:     supporter.Email,
+		Amount:    req.Amount,
+		Currency:  currency,
+		IPCountry: req.IPCountry,
+		Country:   req.BillingCountry,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if check.Decision == models.FraudDecisionBlocked {
+		return nil, errors.New("apoio recusado pela análise de segurança")
+	}
+	underReview := check.Decision == models.FraudDecisionReview
+
+	platformFee := req.Amount * int64(s.config.PlatformFeePercent) / 100
+
+	metadata := map[string]string{
+		"supporter_id": strconv.FormatUint(uint64(supporterID), 10),
+		"creator_id":   strconv.FormatUint(uint64(req.CreatorID), 10),
+	}
+	if req.ItineraryID != nil {
+		metadata["itinerary_id"] = strconv.FormatUint(uint64(*req.ItineraryID), 10)
+	}
+
+	intent, err := s.provider.CreatePaymentIntent(&PaymentIntentParams{
+		Amount:             req.Amount,
+		Currency:           currency,
+		PlatformFee:        platformFee,
+		DestinationAccount: account.ProviderAccountID,
+		ManualCapture:      underReview,
+		Description:        "Apoio ao criador no guIA",
+		Metadata:           metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tip := &models.Tip{
+		SupporterID:       supporterID,
+		CreatorID:         req.CreatorID,
+		ItineraryID:       req.ItineraryID,
+		Amount:            req.Amount,
+		PlatformFee:       platformFee,
+		Currency:          currency,
+		Message:           strings.TrimSpace(req.Message),
+		Status:            models.TipStatusPending,
+		Provider:          s.provider.Name(),
+		ProviderPaymentID: intent.ID,
+		FraudCheckID:      &check.ID,
+	}
+	if underReview {
+		// O pagamento é apenas autorizado e só é capturado após a revisão
+		tip.Status = models.TipStatusReview
+	}
+
+	if err := s.tipRepo.Create(tip); err != nil {
+		return nil, errors.New("erro ao registrar apoio")
+	}
+
+	if err := s.fraudService.AttachSubject(check, tip.ID); err != nil {
+		log.Printf("Erro ao vincular avaliação antifraude %d ao apoio %d: %v", check.ID, tip.ID, err)
+	}
+
+	return &models.TipIntentResponse{
+		Tip:          tip.ToResponse(),
+		ClientSecret: intent.ClientSecret,
+	}, nil
+}
+
+// ConfirmTip consulta o provedor para atualizar o status de uma gorjeta
+// pendente após o apoiador concluir o pagamento no app
+func (s *TipService) ConfirmTip(tipID, userID uint) (*models.TipResponse, error) {
+	tip, err := s.tipRepo.GetByID(tipID)
+	if err != nil {
+		return nil, errors.New("apoio não encontrado")
+	}
+
+	if tip.SupporterID != userID && tip.CreatorID != userID {
+		return nil, errors.New("você não tem permissão para acessar este apoio")
+	}
+
+	if tip.Status != models.TipStatusPending {
+		return tip.ToResponse(), nil
+	}
+
+	intent, err := s.provider.GetPaymentIntent(tip.ProviderPaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPaymentIntent(tip, models.TipStatusPending, intent); err != nil {
+		return nil, err
+	}
+
+	return tip.ToResponse(), nil
+}
+
+// applyPaymentIntent reflete o status do pagamento na gorjeta e lança no
+// livro-razão as gorjetas confirmadas
+func (s *TipService) applyPaymentIntent(tip *models.Tip, from models.TipStatus, intent *PaymentIntent) error {
+	status := models.TipStatusPending
+	switch intent.Status {
+	case PaymentIntentSucceeded:
+		status = models.TipStatusSucceeded
+	case PaymentIntentFailed:
+		status = models.TipStatusFailed
+	}
+	if status == from {
+		return nil
+	}
+
+	updated, err := s.tipRepo.UpdateStatus(tip, from, status)
+	if err != nil {
+		return errors.New("erro ao atualizar apoio")
+	}
+
+	if updated && status == models.TipStatusSucceeded {
+		// Falhas aqui aparecem no relatório de conciliação
+		if err := s.ledgerService.RecordTip(tip); err != nil {
+			log.Printf("Erro ao lançar apoio %d no livro-razão: %v", tip.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// applyPaymentWebhook confirma as gorjetas pendentes quando o provedor avisa
+// que o pagamento mudou de status, sem depender do app chamar ConfirmTip. O
+// status é consultado no provedor em vez de confiar no payload
+func (s *TipService) applyPaymentWebhook(event *models.WebhookEvent) error {
+	paymentID, err := paymentIntentFromWebhook(event)
+	if err != nil || paymentID == "" {
+		return err
+	}
+
+	tip, err := s.tipRepo.GetByProviderPaymentID(paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.New("erro ao buscar apoio")
+	}
+
+	// Gorjetas em revisão antifraude seguem o fluxo da revisão manual
+	if tip.Status != models.TipStatusPending {
+		return nil
+	}
+
+	intent, err := s.provider.GetPaymentIntent(tip.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPaymentIntent(tip, models.TipStatusPending, intent)
+}
+
+// applyFraudReview captura o pagamento autorizado de uma gorjeta aprovada na
+// revisão manual ou o cancela quando rejeitada
+func (s *TipService) applyFraudReview(check *models.FraudCheck, approved bool) error {
+	tip, err := s.tipRepo.GetByID(check.SubjectID)
+	if err != nil {
+		return errors.New("apoio não encontrado")
+	}
+
+	if tip.Status != models.TipStatusReview {
+		return nil
+	}
+
+	if !approved {
+		if err := s.provider.CancelPaymentIntent(tip.ProviderPaymentID); err != nil {
+			return err
+		}
+		if _, err := s.tipRepo.UpdateStatus(tip, models.TipStatusReview, models.TipStatusFailed); err != nil {
+			return errors.New("erro ao atualizar apoio")
+		}
+		return nil
+	}
+
+	intent, err := s.provider.CapturePaymentIntent(tip.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPaymentIntent(tip, models.TipStatusReview, intent)
+}
+
+// RefundTip estorna uma gorjeta paga no provedor e reverte seus lançamentos
+func (s *TipService) RefundTip(tipID uint) (*models.TipResponse, error) {
+	tip, err := s.tipRepo.GetByID(tipID)
+	if err != nil {
+		return nil, errors.New("apoio não encontrado")
+	}
+
+	if tip.Status != models.TipStatusSucceeded {
+		return nil, errors.New("apenas apoios confirmados podem ser estornados")
+	}
+
+	if _, err := s.provider.CreateRefund(tip.ProviderPaymentID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.tipRepo.UpdateStatus(tip, models.TipStatusSucceeded, models.TipStatusRefunded)
+	if err != nil {
+		return nil, errors.New("erro ao atualizar apoio")
+	}
+
+	if updated {
+		if err := s.ledgerService.RecordTipRefund(tip); err != nil {
+			log.Printf("Erro ao lançar estorno do apoio %d no livro-razão: %v", tip.ID, err)
+		}
+	}
+
+	return tip.ToResponse(), nil
+}
+
+func (s *TipService) GetSentTips(userID uint, limit, offset int) ([]models.TipResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	tips, err := s.tipRepo.GetBySupporter(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar apoios enviados")
+	}
+
+	var responses []models.TipResponse
+	for _, tip := range tips {
+		responses = append(responses, *tip.ToResponse())
+	}
+
+	return responses, nil
+}
+
+func (s *TipService) GetReceivedTips(userID uint, limit, offset int) ([]models.TipResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	tips, err := s.tipRepo.GetByCreator(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar apoios recebidos")
+	}
+
+	var responses []models.TipResponse
+	for _, tip := range tips {
+		responses = append(responses, *tip.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetEarnings soma as gorjetas confirmadas do criador no período, por moeda;
+// sem período informado considera os últimos 30 dias
+func (s *TipService) GetEarnings(userID uint, from, to time.Time) (*EarningsReport, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	if !from.Before(to) {
+		return nil, errors.New("período inválido")
+	}
+
+	totals, err := s.tipRepo.GetEarnings(userID, from, to)
+	if err != nil {
+		return nil, errors.New("erro ao calcular ganhos")
+	}
+
+	return &EarningsReport{
+		From:   from,
+		To:     to,
+		Totals: totals,
+	}, nil
+}
+
+// SetupPayoutAccount cria a conta de recebimento do criador no provedor, se
+// ainda não existir, e devolve o link de cadastro dos dados bancários
+func (s *TipService) SetupPayoutAccount(userID uint, req *PayoutOnboardingRequest) (*models.PayoutAccountResponse, error) {
+	account, err := s.tipRepo.GetPayoutAccount(userID)
+	if err != nil {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, errors.New("usuário não encontrado")
+		}
+
+		country := "BR"
+		if req.Country != "" {
+			country = strings.ToUpper(req.Country)
+		}
+		if len(country) != 2 {
+			return nil, errors.New("país deve ser um código ISO de 2 letras")
+		}
+
+		connected, err := s.provider.CreateConnectedAccount(user.Email, country)
+		if err != nil {
+			return nil, err
+		}
+
+		account = &models.PayoutAccount{
+			UserID:            userID,
+			Provider:          s.provider.Name(),
+			ProviderAccountID: connected.ID,
+		}
+		applyConnectedAccount(account, connected)
+
+		if err := s.tipRepo.SavePayoutAccount(account); err != nil {
+			return nil, errors.New("erro ao salvar conta de recebimento")
+		}
+	}
+
+	link, err := s.provider.CreateOnboardingLink(account.ProviderAccountID, req.RefreshURL, req.ReturnURL)
+	if err != nil {
+		return nil, err
+	}
+
+	response := account.ToResponse()
+	response.OnboardingURL = link
+	return response, nil
+}
+
+func (s *TipService) GetPayoutAccount(userID uint) (*models.PayoutAccountResponse, error) {
+	account, err := s.tipRepo.GetPayoutAccount(userID)
+	if err != nil {
+		return nil, errors.New("conta de recebimento não encontrada")
+	}
+
+	// Sincroniza o status do cadastro, que é concluído fora do app
+	if connected, err := s.provider.GetConnectedAccount(account.ProviderAccountID); err == nil {
+		applyConnectedAccount(account, connected)
+		if err := s.tipRepo.SavePayoutAccount(account); err != nil {
+			return nil, errors.New("erro ao salvar conta de recebimento")
+		}
+	}
+
+	return account.ToResponse(), nil
+}
+
+func applyConnectedAccount(account *models.PayoutAccount, connected *ConnectedAccount) {
+	account.Country = connected.Country
+	account.ChargesEnabled = connected.ChargesEnabled
+	account.PayoutsEnabled = connected.PayoutsEnabled
+	account.DetailsSubmitted = connected.DetailsSubmitted
+}
+
+// Funções de validação
+func (s *TipService) validateCreateTipRequest(supporterID uint, req *CreateTipRequest) error {
+	if req.CreatorID == supporterID {
+		return errors.New("você não pode apoiar a si mesmo")
+	}
+
+	if req.Amount < s.config.MinTipAmount || req.Amount > s.config.MaxTipAmount {
+		return errors.New("valor do apoio fora dos limites permitidos")
+	}
+
+	if req.Currency != "" && len(req.Currency) != 3 {
+		return errors.New("moeda deve ser um código ISO de 3 letras")
+	}
+
+	if len(req.Message) > 500 {
+		return errors.New("mensagem deve ter no máximo 500 caracteres")
+	}
+
+	return nil
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+)
+
+// Arquivos GPX/KML: exportação dos locais do roteiro e importação de pontos
+// de interesse para criar dias e locais
+
+const (
+	maxTrackFileSize  = 5 * 1024 * 1024
+	maxTrackLocations = 500
+)
+
+// TrackPoint é um ponto de interesse lido do arquivo
+type TrackPoint struct {
+	Name        string
+	Description string
+	Type        string
+	Latitude    float64
+	Longitude   float64
+	Time        *time.Time
+}
+
+// TrackDay agrupa os pontos que viram um dia do roteiro
+type TrackDay struct {
+	Title  string
+	Points []TrackPoint
+}
+
+// TrackImportResult resume a importação; Skipped lista os pontos descartados
+// e o motivo
+type TrackImportResult struct {
+	Itinerary        *models.ItineraryResponse `json:"itinerary"`
+	DaysCreated      int                       `json:"days_created"`
+	LocationsCreated int                       `json:"locations_created"`
+	Skipped          []string                  `json:"skipped"`
+}
+
+// ============================================================================
+// GPX
+// ============================================================================
+
+type gpxFile struct {
+	XMLName   xml.Name     `xml:"gpx"`
+	Version   string       `xml:"version,attr,omitempty"`
+	Creator   string       `xml:"creator,attr,omitempty"`
+	Xmlns     string       `xml:"xmlns,attr,omitempty"`
+	Metadata  *gpxMetadata `xml:"metadata,omitempty"`
+	Waypoints []gpxPoint   `xml:"wpt"`
+	Routes    []gpxRoute   `xml:"rte"`
+	Tracks    []gpxTrack   `xml:"trk"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+}
+
+type gpxPoint struct {
+	Lat  string `xml:"lat,attr"`
+	Lon  string `xml:"lon,attr"`
+	Time string `xml:"time,omitempty"`
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+	Type string `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// ============================================================================
+// KML
+// ============================================================================
+
+type kmlFile struct {
+	XMLName  xml.Name     `xml:"kml"`
+	Xmlns    string       `xml:"xmlns,attr,omitempty"`
+	Document kmlContainer `xml:"Document"`
+}
+
+type kmlContainer struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	Placemarks  []kmlPlacemark `xml:"Placemark"`
+	Folders     []kmlContainer `xml:"Folder"`
+}
+
+type kmlPlacemark struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	TimeStamp   *kmlTimeStamp  `xml:"TimeStamp,omitempty"`
+	Point       *kmlPoint      `xml:"Point,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+}
+
+type kmlTimeStamp struct {
+	When string `xml:"when"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlLineString struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+// ============================================================================
+// EXPORTAÇÃO
+// ============================================================================
+
+// encodeGPX gera um waypoint por local e uma trilha por dia, ligando os locais
+// na ordem de visita
+func encodeGPX(itinerary *models.Itinerary) ([]byte, error) {
+	file := gpxFile{
+		Version:  "1.1",
+		Creator:  "guIA",
+		Xmlns:    "http://www.topografix.com/GPX/1/1",
+		Metadata: &gpxMetadata{Name: itinerary.Title, Desc: itinerary.Description},
+	}
+
+	for _, day := range sortedDays(itinerary) {
+		var segment gpxSegment
+		for _, location := range sortedLocations(&day) {
+			if location.Latitude == nil || location.Longitude == nil {
+				continue
+			}
+			point := gpxPoint{
+				Lat:  formatCoordinate(*location.Latitude),
+				Lon:  formatCoordinate(*location.Longitude),
+				Name: location.Name,
+				Desc: location.Description,
+				Type: string(location.LocationType),
+			}
+			if location.StartTime != nil {
+				point.Time = location.StartTime.UTC().Format(time.RFC3339)
+			}
+			file.Waypoints = append(file.Waypoints, point)
+			segment.Points = append(segment.Points, gpxPoint{Lat: point.Lat, Lon: point.Lon, Name: point.Name})
+		}
+		if len(segment.Points) > 0 {
+			file.Tracks = append(file.Tracks, gpxTrack{
+				Name:     trackDayTitle(&day),
+				Segments: []gpxSegment{segment},
+			})
+		}
+	}
+
+	if len(file.Waypoints) == 0 {
+		return nil, errors.New("roteiro não tem locais com coordenadas")
+	}
+	return marshalTrackXML(file)
+}
+
+// encodeKML gera uma pasta por dia com os locais e o percurso do dia
+func encodeKML(itinerary *models.Itinerary) ([]byte, error) {
+	file := kmlFile{
+		Xmlns: "http://www.opengis.net/kml/2.2",
+		Document: kmlContainer{
+			Name:        itinerary.Title,
+			Description: itinerary.Description,
+		},
+	}
+
+	total := 0
+	for _, day := range sortedDays(itinerary) {
+		folder := kmlContainer{Name: trackDayTitle(&day), Description: day.Description}
+		var path []string
+		for _, location := range sortedLocations(&day) {
+			if location.Latitude == nil || location.Longitude == nil {
+				continue
+			}
+			coordinates := formatCoordinate(*location.Longitude) + "," + formatCoordinate(*location.Latitude)
+			placemark := kmlPlacemark{
+				Name:        location.Name,
+				Description: location.Description,
+				Point:       &kmlPoint{Coordinates: coordinates},
+			}
+			if location.StartTime != nil {
+				placemark.TimeStamp = &kmlTimeStamp{When: location.StartTime.UTC().Format(time.RFC3339)}
+			}
+			folder.Placemarks = append(folder.Placemarks, placemark)
+			path = append(path, coordinates)
+		}
+		if len(path) == 0 {
+			continue
+		}
+		if len(path) > 1 {
+			folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+				Name:       "Percurso do dia " + strconv.Itoa(day.DayNumber),
+				LineString: &kmlLineString{Coordinates: strings.Join(path, " ")},
+			})
+		}
+		total += len(path)
+		file.Document.Folders = append(file.Document.Folders, folder)
+	}
+
+	if total == 0 {
+		return nil, errors.New("roteiro não tem locais com coordenadas")
+	}
+	return marshalTrackXML(file)
+}
+
+func marshalTrackXML(file interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+	return out.Bytes(), nil
+}
+
+func trackDayTitle(day *models.ItineraryDay) string {
+	title := fmt.Sprintf("Dia %d", day.DayNumber)
+	if day.Title != "" {
+		title += " — " + day.Title
+	}
+	return title
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
+
+// ============================================================================
+// IMPORTAÇÃO
+// ============================================================================
+
+// parseTrackFile lê um GPX ou KML (pela extensão) e agrupa os pontos em dias;
+// pontos com coordenadas inválidas são descartados e listados em skipped
+func parseTrackFile(fileName string, data []byte) ([]TrackDay, []string, error) {
+	if len(data) > maxTrackFileSize {
+		return nil, nil, fmt.Errorf("arquivo deve ter no máximo %d MB", maxTrackFileSize/(1024*1024))
+	}
+
+	var days []TrackDay
+	var skipped []string
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".gpx":
+		days, skipped, err = parseGPX(data)
+	case ".kml":
+		days, skipped, err = parseKML(data)
+	default:
+		return nil, nil, errors.New("formato de arquivo inválido: envie um arquivo .gpx ou .kml")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total := 0
+	for _, day := range days {
+		total += len(day.Points)
+	}
+	if total == 0 {
+		return nil, skipped, errors.New("arquivo não contém pontos de interesse válidos")
+	}
+	if total > maxTrackLocations {
+		return nil, skipped, fmt.Errorf("arquivo deve ter no máximo %d pontos de interesse", maxTrackLocations)
+	}
+
+	return days, skipped, nil
+}
+
+// parseGPX transforma cada rota em um dia e agrupa os waypoints pela data;
+// waypoints sem horário formam um último dia. Trilhas (trk) são o traçado
+// percorrido, não pontos de interesse, e são ignoradas
+func parseGPX(data []byte) ([]TrackDay, []string, error) {
+	var file gpxFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, nil, errors.New("arquivo GPX inválido")
+	}
+
+	var days []TrackDay
+	var skipped []string
+	position := 0
+
+	for _, route := range file.Routes {
+		day := TrackDay{Title: strings.TrimSpace(route.Name)}
+		for _, raw := range route.Points {
+			position++
+			if point, reason := gpxTrackPoint(raw, position); reason != "" {
+				skipped = append(skipped, reason)
+			} else {
+				day.Points = append(day.Points, point)
+			}
+		}
+		if len(day.Points) > 0 {
+			days = append(days, day)
+		}
+	}
+
+	byDate := map[string]*TrackDay{}
+	var dates []string
+	var undated TrackDay
+	for _, raw := range file.Waypoints {
+		position++
+		point, reason := gpxTrackPoint(raw, position)
+		if reason != "" {
+			skipped = append(skipped, reason)
+			continue
+		}
+		if point.Time == nil {
+			undated.Points = append(undated.Points, point)
+			continue
+		}
+
+		date := point.Time.Format("2006-01-02")
+		if byDate[date] == nil {
+			byDate[date] = &TrackDay{}
+			dates = append(dates, date)
+		}
+		byDate[date].Points = append(byDate[date].Points, point)
+	}
+
+	sort.Strings(dates)
+	for _, date := range dates {
+		day := byDate[date]
+		sort.SliceStable(day.Points, func(i, j int) bool { return day.Points[i].Time.Before(*day.Points[j].Time) })
+		days = append(days, *day)
+	}
+	if len(undated.Points) > 0 {
+		days = append(days, undated)
+	}
+
+	return days, skipped, nil
+}
+
+func gpxTrackPoint(raw gpxPoint, position int) (TrackPoint, string) {
+	latitude, errLat := strconv.ParseFloat(strings.TrimSpace(raw.Lat), 64)
+	longitude, errLon := strconv.ParseFloat(strings.TrimSpace(raw.Lon), 64)
+	point := TrackPoint{
+		Name:        strings.TrimSpace(raw.Name),
+		Description: strings.TrimSpace(raw.Desc),
+		Type:        strings.TrimSpace(raw.Type),
+		Latitude:    latitude,
+		Longitude:   longitude,
+	}
+	if errLat != nil || errLon != nil || !validCoordinates(latitude, longitude) {
+		return point, skippedPoint(point.Name, position, "coordenadas inválidas")
+	}
+	if raw.Time != "" {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(raw.Time)); err == nil {
+			point.Time = &t
+		}
+	}
+	return point, ""
+}
+
+// parseKML transforma cada pasta em um dia e os marcadores soltos no
+// documento em um dia com o nome do documento; só marcadores de ponto viram
+// locais
+func parseKML(data []byte) ([]TrackDay, []string, error) {
+	var file kmlFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, nil, errors.New("arquivo KML inválido")
+	}
+
+	var days []TrackDay
+	var skipped []string
+	position := 0
+
+	var visit func(container kmlContainer)
+	visit = func(container kmlContainer) {
+		day := TrackDay{Title: strings.TrimSpace(container.Name)}
+		for _, placemark := range container.Placemarks {
+			if placemark.Point == nil {
+				continue
+			}
+			position++
+			point, reason := kmlTrackPoint(placemark, position)
+			if reason != "" {
+				skipped = append(skipped, reason)
+				continue
+			}
+			day.Points = append(day.Points, point)
+		}
+		if len(day.Points) > 0 {
+			days = append(days, day)
+		}
+		for _, folder := range container.Folders {
+			visit(folder)
+		}
+	}
+	visit(file.Document)
+
+	return days, skipped, nil
+}
+
+func kmlTrackPoint(placemark kmlPlacemark, position int) (TrackPoint, string) {
+	point := TrackPoint{
+		Name:        strings.TrimSpace(placemark.Name),
+		Description: strings.TrimSpace(placemark.Description),
+	}
+
+	// KML usa longitude,latitude[,altitude]
+	parts := strings.Split(strings.TrimSpace(placemark.Point.Coordinates), ",")
+	if len(parts) < 2 {
+		return point, skippedPoint(point.Name, position, "coordenadas inválidas")
+	}
+	longitude, errLon := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	latitude, errLat := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errLat != nil || errLon != nil || !validCoordinates(latitude, longitude) {
+		return point, skippedPoint(point.Name, position, "coordenadas inválidas")
+	}
+	point.Latitude = latitude
+	point.Longitude = longitude
+
+	if placemark.TimeStamp != nil {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(placemark.TimeStamp.When)); err == nil {
+			point.Time = &t
+		}
+	}
+	return point, ""
+}
+
+func validCoordinates(latitude, longitude float64) bool {
+	if math.IsNaN(latitude) || math.IsNaN(longitude) {
+		return false
+	}
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+func skippedPoint(name string, position int, reason string) string {
+	if name == "" {
+		return fmt.Sprintf("ponto %d: %s", position, reason)
+	}
+	return fmt.Sprintf("ponto %d (%s): %s", position, name, reason)
+}
+
+// trackLocationType traduz o tipo do ponto (GPX <type>) para o tipo de local;
+// tipos desconhecidos viram "other"
+func trackLocationType(value string) models.LocationType {
+	value = strings.ToLower(value)
+	switch {
+	case value == "":
+		return models.LocationTypeOther
+	case strings.Contains(value, "hotel"), strings.Contains(value, "lodging"), strings.Contains(value, "hostel"), strings.Contains(value, "camp"):
+		return models.LocationTypeHotel
+	case strings.Contains(value, "restaurant"), strings.Contains(value, "food"), strings.Contains(value, "cafe"), strings.Contains(value, "bar"):
+		return models.LocationTypeRestaurant
+	case strings.Contains(value, "transport"), strings.Contains(value, "airport"), strings.Contains(value, "station"), strings.Contains(value, "parking"):
+		return models.LocationTypeTransport
+	case strings.Contains(value, "shop"), strings.Contains(value, "store"), strings.Contains(value, "market"):
+		return models.LocationTypeShopping
+	case strings.Contains(value, "attraction"), strings.Contains(value, "museum"), strings.Contains(value, "park"), strings.Contains(value, "viewpoint"), strings.Contains(value, "beach"):
+		return models.LocationTypeAttraction
+	default:
+		return models.LocationTypeOther
+	}
+}
@@ -0,0 +1,590 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	transcodeQueueSize = 100
+	transcodeTimeout   = 30 * time.Minute
+	posterMaxHeight    = 720
+)
+
+type TranscodeConfig struct {
+	// Caminhos do ffmpeg e do ffprobe; vazios procuram no PATH. Sem eles os
+	// vídeos ficam só no formato original
+	FFmpegPath  string
+	FFprobePath string
+	// Qualidades geradas; as maiores que o vídeo original são ignoradas
+	Renditions []HLSRendition
+	// Duração de cada segmento HLS, em segundos
+	SegmentSeconds int
+}
+
+// HLSRendition é uma qualidade da playlist HLS
+type HLSRendition struct {
+	Height int
+	// Taxas de bits em kbps
+	VideoBitrate int
+	AudioBitrate int
+}
+
+// DefaultHLSRenditions são as qualidades geradas quando nenhuma é configurada
+var DefaultHLSRenditions = []HLSRendition{
+	{Height: 360, VideoBitrate: 800, AudioBitrate: 96},
+	{Height: 720, VideoBitrate: 2800, AudioBitrate: 128},
+	{Height: 1080, VideoBitrate: 5000, AudioBitrate: 128},
+}
+
+type TranscodeServiceInterface interface {
+	GetMediaStatus(mediaID, userID uint, isAdmin bool) (*MediaStatus, error)
+	StartTranscodeWorker()
+}
+
+type TranscodeService struct {
+	transcodeRepo       repositories.TranscodeRepositoryInterface
+	mediaRepo           repositories.MediaRepositoryInterface
+	mediaService        MediaServiceInterface
+	notificationService NotificationServiceInterface
+	eventBus            events.BusInterface
+	config              *TranscodeConfig
+	ffmpeg              string
+	ffprobe             string
+	jobs                chan uint
+}
+
+// MediaStatus é o andamento do processamento de uma mídia. Imagens e vídeos
+// sem conversão disponível ficam prontos no formato original
+type MediaStatus struct {
+	MediaID    uint                   `json:"media_id"`
+	MediaType  MediaType              `json:"media_type"`
+	Status     models.TranscodeStatus `json:"status"`
+	URL        string                 `json:"url"`
+	HLSURL     string                 `json:"hls_url,omitempty"`
+	PosterURL  string                 `json:"poster_url,omitempty"`
+	Width      int                    `json:"width,omitempty"`
+	Height     int                    `json:"height,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+}
+
+func NewTranscodeService(transcodeRepo repositories.TranscodeRepositoryInterface, mediaRepo repositories.MediaRepositoryInterface, mediaService MediaServiceInterface, notificationService NotificationServiceInterface, eventBus events.BusInterface, config *TranscodeConfig) TranscodeServiceInterface {
+	if len(config.Renditions) == 0 {
+		config.Renditions = DefaultHLSRenditions
+	}
+	if config.SegmentSeconds <= 0 {
+		config.SegmentSeconds = 6
+	}
+
+	service := &TranscodeService{
+		transcodeRepo:       transcodeRepo,
+		mediaRepo:           mediaRepo,
+		mediaService:        mediaService,
+		notificationService: notificationService,
+		eventBus:            eventBus,
+		config:              config,
+		ffmpeg:              lookupBinary(config.FFmpegPath, "ffmpeg"),
+		ffprobe:             lookupBinary(config.FFprobePath, "ffprobe"),
+		jobs:                make(chan uint, transcodeQueueSize),
+	}
+
+	if !service.enabled() {
+		log.Println("ffmpeg/ffprobe não encontrados; vídeos não serão convertidos para HLS")
+	}
+
+	eventBus.Subscribe(events.MediaUploaded, service.onMediaUploaded)
+	return service
+}
+
+func (s *TranscodeService) GetMediaStatus(mediaID, userID uint, isAdmin bool) (*MediaStatus, error) {
+	media, err := s.mediaRepo.GetByID(mediaID)
+	if err != nil {
+		return nil, errors.New("mídia não encontrada")
+	}
+	if media.OwnerID != userID && !isAdmin {
+		return nil, errors.New("você não tem permissão para acessar esta mídia")
+	}
+
+	status := &MediaStatus{
+		MediaID:   media.ID,
+		MediaType: media.MediaType,
+		Status:    models.TranscodeStatusReady,
+		URL:       media.URL,
+		HLSURL:    media.Variants["hls"].URL,
+		PosterURL: media.Variants["poster"].URL,
+		Width:     media.Width,
+		Height:    media.Height,
+	}
+	if media.MediaType != MediaTypeVideo {
+		return status, nil
+	}
+
+	job, err := s.transcodeRepo.GetByMediaID(media.ID)
+	if err != nil {
+		// O job é criado logo após o upload; até lá o vídeo está na fila
+		if s.enabled() {
+			status.Status = models.TranscodeStatusPending
+		}
+		return status, nil
+	}
+
+	status.Status = job.Status
+	status.Error = job.Error
+	status.FinishedAt = job.FinishedAt
+	return status, nil
+}
+
+// StartTranscodeWorker converte os vídeos em segundo plano, um por vez,
+// retomando os jobs interrompidos por uma reinicialização
+func (s *TranscodeService) StartTranscodeWorker() {
+	if !s.enabled() {
+		return
+	}
+
+	go func() {
+		for jobID := range s.jobs {
+			if err := s.processJob(jobID); err != nil {
+				log.Printf("Falha ao processar conversão %d: %v", jobID, err)
+			}
+		}
+	}()
+
+	jobs, err := s.transcodeRepo.GetUnfinished()
+	if err != nil {
+		log.Println("Falha ao buscar conversões pendentes:", err)
+		return
+	}
+	for _, job := range jobs {
+		s.enqueue(job.ID)
+	}
+}
+
+func (s *TranscodeService) onMediaUploaded(event events.Event) {
+	// Sem ffmpeg o vídeo fica só no formato original, já pronto
+	if !s.enabled() {
+		s.setMediaStatus(event.EntityID, event.ActorID, models.MediaStatusReady, "")
+		return
+	}
+
+	job := &models.TranscodeJob{
+		MediaID: event.EntityID,
+		OwnerID: event.ActorID,
+		Status:  models.TranscodeStatusPending,
+	}
+	created, err := s.transcodeRepo.Create(job)
+	if err != nil {
+		log.Printf("Falha ao criar conversão da mídia %d: %v", event.EntityID, err)
+		return
+	}
+	if created {
+		s.enqueue(job.ID)
+	}
+}
+
+func (s *TranscodeService) enqueue(jobID uint) {
+	select {
+	case s.jobs <- jobID:
+	default:
+		go func() { s.jobs <- jobID }()
+	}
+}
+
+func (s *TranscodeService) processJob(jobID uint) error {
+	job, err := s.transcodeRepo.GetByID(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.TranscodeStatusReady || job.Status == models.TranscodeStatusFailed {
+		return nil
+	}
+	if job.Media.ID == 0 {
+		job.Status = models.TranscodeStatusFailed
+		job.Error = "mídia removida antes da conversão"
+		return s.transcodeRepo.Update(job)
+	}
+
+	now := time.Now()
+	job.StartedAt = &now
+	job.Status = models.TranscodeStatusRunning
+	if err := s.transcodeRepo.Update(job); err != nil {
+		return err
+	}
+	s.setMediaStatus(job.MediaID, job.OwnerID, models.MediaStatusProcessing, "")
+
+	transcodeErr := s.transcode(&job.Media)
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if transcodeErr != nil {
+		job.Status = models.TranscodeStatusFailed
+		job.Error = truncateString(transcodeErr.Error(), 500)
+	} else {
+		job.Status = models.TranscodeStatusReady
+		job.Error = ""
+	}
+	if err := s.transcodeRepo.Update(job); err != nil {
+		return err
+	}
+
+	if job.Status == models.TranscodeStatusReady {
+		s.setMediaStatus(job.MediaID, job.OwnerID, models.MediaStatusReady, "")
+		s.notifyReady(job)
+	} else {
+		s.setMediaStatus(job.MediaID, job.OwnerID, models.MediaStatusFailed, job.Error)
+	}
+	return nil
+}
+
+// setMediaStatus grava o andamento na mídia e o publica no barramento, para
+// quem acompanha o upload em tempo real
+func (s *TranscodeService) setMediaStatus(mediaID, ownerID uint, status models.MediaProcessingStatus, processingError string) {
+	if err := s.mediaRepo.UpdateStatus(mediaID, status, processingError); err != nil {
+		log.Printf("Falha ao atualizar status da mídia %d: %v", mediaID, err)
+		return
+	}
+
+	data := map[string]string{"status": string(status)}
+	if processingError != "" {
+		data["error"] = processingError
+	}
+	s.eventBus.Publish(events.Event{
+		Type:     events.MediaProcessed,
+		ActorID:  ownerID,
+		EntityID: mediaID,
+		Data:     data,
+	})
+}
+
+// transcode gera as qualidades HLS e a capa num diretório temporário, grava
+// tudo no storage ao lado do original e registra o resultado na mídia
+func (s *TranscodeService) transcode(media *models.Media) error {
+	ctx, cancel := context.WithTimeout(context.Background(), transcodeTimeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp("", "guia-hls")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	input, err := s.localInput(media, workDir)
+	if err != nil {
+		return fmt.Errorf("erro ao ler vídeo: %v", err)
+	}
+
+	probe, err := s.probe(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	outDir := filepath.Join(workDir, "out")
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	renditions := s.renditionsFor(probe.height)
+	var master strings.Builder
+	master.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rendition := range renditions {
+		width := evenDimension(probe.width * rendition.Height / probe.height)
+		if err := s.encodeRendition(ctx, input, outDir, rendition); err != nil {
+			return err
+		}
+		bandwidth := (rendition.VideoBitrate + rendition.AudioBitrate) * 1000
+		fmt.Fprintf(&master, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n%dp.m3u8\n", bandwidth, width, rendition.Height, rendition.Height)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "master.m3u8"), []byte(master.String()), 0644); err != nil {
+		return err
+	}
+
+	posterHeight := min(probe.height, posterMaxHeight)
+	if err := s.extractPoster(ctx, input, filepath.Join(outDir, "poster.jpg"), probe.duration, posterHeight); err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(media.FilePath), filepath.Ext(media.FilePath))
+	directory := filepath.ToSlash(filepath.Join(filepath.Dir(media.FilePath), "hls", base))
+	stored, err := s.storeOutputs(outDir, directory)
+	if err != nil {
+		return err
+	}
+
+	largest := renditions[len(renditions)-1]
+	variants := make(map[string]models.MediaVariant, len(media.Variants)+2)
+	for name, variant := range media.Variants {
+		variants[name] = variant
+	}
+	variants["hls"] = models.MediaVariant{
+		URL:      stored["master.m3u8"].url,
+		FilePath: stored["master.m3u8"].filePath,
+		MimeType: "application/vnd.apple.mpegurl",
+		Width:    evenDimension(probe.width * largest.Height / probe.height),
+		Height:   largest.Height,
+	}
+	variants["poster"] = models.MediaVariant{
+		URL:      stored["poster.jpg"].url,
+		FilePath: stored["poster.jpg"].filePath,
+		MimeType: "image/jpeg",
+		Width:    evenDimension(probe.width * posterHeight / probe.height),
+		Height:   posterHeight,
+	}
+
+	var extraFiles []string
+	for name, file := range stored {
+		if name != "master.m3u8" && name != "poster.jpg" {
+			extraFiles = append(extraFiles, file.filePath)
+		}
+	}
+	sort.Strings(extraFiles)
+
+	media.Width = probe.width
+	media.Height = probe.height
+	media.Variants = variants
+	media.ExtraFiles = extraFiles
+	if err := s.mediaRepo.UpdateProcessed(media); err != nil {
+		s.mediaService.RemoveFiles(storedPaths(stored))
+		return fmt.Errorf("erro ao registrar conversão: %v", err)
+	}
+	return nil
+}
+
+// localInput devolve um caminho local do vídeo para o ffmpeg, baixando-o
+// quando o storage é remoto
+func (s *TranscodeService) localInput(media *models.Media, workDir string) (string, error) {
+	src, err := s.mediaService.OpenMedia(media)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	if file, ok := src.(*os.File); ok {
+		return file.Name(), nil
+	}
+
+	input := filepath.Join(workDir, "input"+filepath.Ext(media.FilePath))
+	dst, err := os.Create(input)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", err
+	}
+	return input, nil
+}
+
+type videoProbe struct {
+	width    int
+	height   int
+	duration float64
+}
+
+func (s *TranscodeService) probe(ctx context.Context, input string) (*videoProbe, error) {
+	out, err := exec.CommandContext(ctx, s.ffprobe,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		input,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe: %v", err)
+	}
+
+	var result struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("ffprobe: %v", err)
+	}
+	if len(result.Streams) == 0 || result.Streams[0].Width <= 0 || result.Streams[0].Height <= 0 {
+		return nil, errors.New("arquivo sem faixa de vídeo")
+	}
+
+	duration, _ := strconv.ParseFloat(result.Format.Duration, 64)
+	return &videoProbe{
+		width:    result.Streams[0].Width,
+		height:   result.Streams[0].Height,
+		duration: duration,
+	}, nil
+}
+
+// renditionsFor escolhe as qualidades que não ampliam o vídeo, em ordem
+// crescente; vídeos menores que a menor qualidade mantêm a altura original
+func (s *TranscodeService) renditionsFor(height int) []HLSRendition {
+	renditions := append([]HLSRendition(nil), s.config.Renditions...)
+	sort.Slice(renditions, func(i, j int) bool { return renditions[i].Height < renditions[j].Height })
+
+	var selected []HLSRendition
+	for _, rendition := range renditions {
+		if rendition.Height <= height {
+			selected = append(selected, rendition)
+		}
+	}
+	if len(selected) == 0 {
+		smallest := renditions[0]
+		smallest.Height = evenDimension(height)
+		selected = append(selected, smallest)
+	}
+	return selected
+}
+
+func (s *TranscodeService) encodeRendition(ctx context.Context, input, outDir string, rendition HLSRendition) error {
+	name := fmt.Sprintf("%dp", rendition.Height)
+	args := []string{
+		"-y", "-v", "error",
+		"-i", input,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", fmt.Sprintf("scale=-2:%d", rendition.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-b:v", fmt.Sprintf("%dk", rendition.VideoBitrate),
+		"-maxrate", fmt.Sprintf("%dk", rendition.VideoBitrate*107/100),
+		"-bufsize", fmt.Sprintf("%dk", rendition.VideoBitrate*3/2),
+		"-c:a", "aac", "-b:a", fmt.Sprintf("%dk", rendition.AudioBitrate), "-ac", "2",
+		"-hls_time", strconv.Itoa(s.config.SegmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outDir, name+"_%03d.ts"),
+		filepath.Join(outDir, name+".m3u8"),
+	}
+	if out, err := exec.CommandContext(ctx, s.ffmpeg, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg (%s): %v: %s", name, err, lastLine(out))
+	}
+	return nil
+}
+
+// extractPoster usa o quadro de 1s (ou do meio, em vídeos curtos) como capa
+func (s *TranscodeService) extractPoster(ctx context.Context, input, output string, duration float64, height int) error {
+	at := 1.0
+	if duration > 0 && duration < 2 {
+		at = duration / 2
+	}
+	args := []string{
+		"-y", "-v", "error",
+		"-ss", strconv.FormatFloat(at, 'f', 2, 64),
+		"-i", input,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=-2:%d", height),
+		"-q:v", "3",
+		output,
+	}
+	if out, err := exec.CommandContext(ctx, s.ffmpeg, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg (capa): %v: %s", err, lastLine(out))
+	}
+	return nil
+}
+
+type storedFile struct {
+	filePath string
+	url      string
+}
+
+// storeOutputs grava os arquivos gerados no storage; se algum falhar, os já
+// gravados são removidos
+func (s *TranscodeService) storeOutputs(outDir, directory string) (map[string]storedFile, error) {
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]storedFile, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		file, err := os.Open(filepath.Join(outDir, name))
+		if err != nil {
+			s.mediaService.RemoveFiles(storedPaths(stored))
+			return nil, err
+		}
+
+		filePath, url, err := s.mediaService.StoreDerivedFile(file, hlsContentType(name), directory, name)
+		file.Close()
+		if err != nil {
+			s.mediaService.RemoveFiles(storedPaths(stored))
+			return nil, fmt.Errorf("erro ao gravar %s: %v", name, err)
+		}
+		stored[name] = storedFile{filePath: filePath, url: url}
+	}
+	return stored, nil
+}
+
+func (s *TranscodeService) notifyReady(job *models.TranscodeJob) {
+	key := fmt.Sprintf("media_ready:%d", job.MediaID)
+	_, err := s.notificationService.Notify(&models.Notification{
+		UserID: job.OwnerID,
+		Type:   models.NotificationTypeMediaReady,
+		Title:  "Seu vídeo está pronto",
+		Body:   "O vídeo enviado foi processado e já pode ser assistido em qualquer conexão",
+		Data: map[string]string{
+			"media_id": fmt.Sprint(job.MediaID),
+		},
+		Key: &key,
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar conversão da mídia %d: %v", job.MediaID, err)
+	}
+}
+
+func (s *TranscodeService) enabled() bool {
+	return s.ffmpeg != "" && s.ffprobe != ""
+}
+
+func lookupBinary(configured, name string) string {
+	if configured != "" {
+		return configured
+	}
+	path, _ := exec.LookPath(name)
+	return path
+}
+
+func evenDimension(value int) int {
+	return max(2, value-value%2)
+}
+
+func hlsContentType(fileName string) string {
+	switch filepath.Ext(fileName) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".ts":
+		return "video/mp2t"
+	case ".jpg":
+		return "image/jpeg"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func storedPaths(stored map[string]storedFile) []string {
+	paths := make([]string, 0, len(stored))
+	for _, file := range stored {
+		paths = append(paths, file.filePath)
+	}
+	return paths
+}
+
+// lastLine devolve a última linha da saída do ffmpeg, que costuma trazer o erro
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return truncateString(lines[len(lines)-1], 200)
+}
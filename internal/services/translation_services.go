@@ -0,0 +1,277 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// ErrTranslationNotConfigured indica que nenhum provedor de tradução foi configurado
+var ErrTranslationNotConfigured = errors.New("tradução não configurada")
+
+type TranslationConfig struct {
+	Provider string // "google" ou "libretranslate"
+	APIKey   string
+	APIURL   string
+}
+
+// TranslationProviderInterface abstrai o serviço externo de tradução; o idioma
+// de origem é detectado pelo provedor
+type TranslationProviderInterface interface {
+	Name() string
+	Translate(text, targetLanguage string) (translated string, sourceLanguage string, err error)
+}
+
+func NewTranslationProvider(config *TranslationConfig) TranslationProviderInterface {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch config.Provider {
+	case "google":
+		if config.APIKey == "" {
+			return &disabledTranslationProvider{}
+		}
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://translation.googleapis.com"
+		}
+		return &googleTranslationProvider{
+			apiKey: config.APIKey,
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+	case "libretranslate":
+		if config.APIURL == "" {
+			return &disabledTranslationProvider{}
+		}
+		return &libreTranslationProvider{
+			apiKey: config.APIKey,
+			apiURL: strings.TrimRight(config.APIURL, "/"),
+			client: client,
+		}
+	default:
+		return &disabledTranslationProvider{}
+	}
+}
+
+type disabledTranslationProvider struct{}
+
+func (p *disabledTranslationProvider) Name() string { return "none" }
+
+func (p *disabledTranslationProvider) Translate(text, targetLanguage string) (string, string, error) {
+	return "", "", ErrTranslationNotConfigured
+}
+
+// googleTranslationProvider usa a API REST v2 do Google Cloud Translation
+type googleTranslationProvider struct {
+	apiKey string
+	apiURL string
+	client *http.Client
+}
+
+func (p *googleTranslationProvider) Name() string { return "google" }
+
+func (p *googleTranslationProvider) Translate(text, targetLanguage string) (string, string, error) {
+	form := url.Values{}
+	form.Set("q", text)
+	form.Set("target", targetLanguage)
+	form.Set("format", "text")
+	form.Set("key", p.apiKey)
+
+	var result struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText         string `json:"translatedText"`
+				DetectedSourceLanguage string `json:"detectedSourceLanguage"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	if err := postTranslationForm(p.client, p.apiURL+"/language/translate/v2", form, &result); err != nil {
+		return "", "", err
+	}
+	if len(result.Data.Translations) == 0 {
+		return "", "", errors.New("erro ao traduzir: resposta vazia do provedor")
+	}
+
+	translation := result.Data.Translations[0]
+	return translation.TranslatedText, translation.DetectedSourceLanguage, nil
+}
+
+// libreTranslationProvider usa uma instância do LibreTranslate (auto-hospedável)
+type libreTranslationProvider struct {
+	apiKey string
+	apiURL string
+	client *http.Client
+}
+
+func (p *libreTranslationProvider) Name() string { return "libretranslate" }
+
+func (p *libreTranslationProvider) Translate(text, targetLanguage string) (string, string, error) {
+	form := url.Values{}
+	form.Set("q", text)
+	form.Set("source", "auto")
+	form.Set("target", targetLanguage)
+	form.Set("format", "text")
+	if p.apiKey != "" {
+		form.Set("api_key", p.apiKey)
+	}
+
+	var result struct {
+		TranslatedText   string `json:"translatedText"`
+		DetectedLanguage struct {
+			Language string `json:"language"`
+		} `json:"detectedLanguage"`
+	}
+	if err := postTranslationForm(p.client, p.apiURL+"/translate", form, &result); err != nil {
+		return "", "", err
+	}
+
+	return result.TranslatedText, result.DetectedLanguage.Language, nil
+}
+
+func postTranslationForm(client *http.Client, endpoint string, form url.Values, out interface{}) error {
+	resp, err := client.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("erro ao acessar provedor de tradução: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("erro ao traduzir: provedor respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type TranslationServiceInterface interface {
+	TranslatePost(postID, userID uint, language string) (*models.PostTranslationResponse, error)
+}
+
+type TranslationService struct {
+	translationRepo repositories.TranslationRepositoryInterface
+	postRepo        repositories.PostRepositoryInterface
+	provider        TranslationProviderInterface
+}
+
+func NewTranslationService(translationRepo repositories.TranslationRepositoryInterface, postRepo repositories.PostRepositoryInterface, provider TranslationProviderInterface) TranslationServiceInterface {
+	return &TranslationService{
+		translationRepo: translationRepo,
+		postRepo:        postRepo,
+		provider:        provider,
+	}
+}
+
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2,4})?$`)
+
+// TranslatePost traduz o conteúdo do post sob demanda; a tradução fica em
+// cache até o conteúdo ser editado
+func (s *TranslationService) TranslatePost(postID, userID uint, language string) (*models.PostTranslationResponse, error) {
+	language = normalizeLanguage(language)
+	if !languageCodePattern.MatchString(language) {
+		return nil, errors.New("idioma inválido, use um código como 'pt' ou 'en'")
+	}
+
+	post, err := s.postRepo.GetByID(postID, userID)
+	if err != nil {
+		return nil, errors.New("post não encontrado")
+	}
+
+	original := &models.PostTranslationResponse{
+		PostID:         post.ID,
+		Language:       language,
+		SourceLanguage: post.Language,
+		Content:        post.Content,
+	}
+
+	// Conteúdo já no idioma pedido
+	if sameLanguage(post.Language, language) {
+		return original, nil
+	}
+
+	hash := contentHash(post.Content)
+	if cached, err := s.translationRepo.GetPostTranslation(post.ID, language); err == nil && cached.SourceHash == hash {
+		return &models.PostTranslationResponse{
+			PostID:         post.ID,
+			Language:       cached.Language,
+			SourceLanguage: cached.SourceLanguage,
+			Content:        cached.Content,
+			Translated:     true,
+			Provider:       cached.Provider,
+		}, nil
+	}
+
+	translated, source, err := s.provider.Translate(post.Content, language)
+	if err != nil {
+		if errors.Is(err, ErrTranslationNotConfigured) {
+			return nil, err
+		}
+		return nil, errors.New("erro ao traduzir post")
+	}
+	source = normalizeLanguage(source)
+
+	if source != "" && source != post.Language {
+		if err := s.translationRepo.SetPostLanguage(post.ID, source); err != nil {
+			return nil, errors.New("erro ao registrar idioma do post")
+		}
+	}
+
+	if sameLanguage(source, language) {
+		original.SourceLanguage = source
+		return original, nil
+	}
+
+	translation := &models.PostTranslation{
+		PostID:         post.ID,
+		Language:       language,
+		SourceLanguage: source,
+		SourceHash:     hash,
+		Content:        translated,
+		Provider:       s.provider.Name(),
+	}
+	if err := s.translationRepo.SavePostTranslation(translation); err != nil {
+		return nil, errors.New("erro ao salvar tradução")
+	}
+
+	return &models.PostTranslationResponse{
+		PostID:         post.ID,
+		Language:       language,
+		SourceLanguage: source,
+		Content:        translated,
+		Translated:     true,
+		Provider:       translation.Provider,
+	}, nil
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
+
+// sameLanguage compara apenas o idioma base ("pt-br" equivale a "pt")
+func sameLanguage(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	base := func(language string) string {
+		if i := strings.Index(language, "-"); i > 0 {
+			return language[:i]
+		}
+		return language
+	}
+	return base(a) == base(b)
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
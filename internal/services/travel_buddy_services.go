@@ -0,0 +1,369 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type TravelBuddyServiceInterface interface {
+	CreateIntent(userID uint, req *TravelIntentRequest) (*models.TravelIntentResponse, error)
+	UpdateIntent(intentID, userID uint, req *TravelIntentRequest) (*models.TravelIntentResponse, error)
+	DeleteIntent(intentID, userID uint) error
+	GetMyIntents(userID uint) ([]models.TravelIntentResponse, error)
+	GetSuggestions(intentID, userID uint, limit int) ([]models.TravelBuddySuggestion, error)
+	ExpressInterest(intentID, userID uint, req *ExpressInterestRequest) (*ExpressInterestResult, error)
+	GetMatches(userID uint, limit, offset int) ([]models.TravelMatchResponse, error)
+}
+
+type TravelIntentRequest struct {
+	Country     string                   `json:"country" binding:"required"`
+	City        string                   `json:"city"`
+	StartDate   time.Time                `json:"start_date" binding:"required"`
+	EndDate     time.Time                `json:"end_date" binding:"required"`
+	Style       models.ItineraryCategory `json:"style"`
+	Description string                   `json:"description"`
+	IsActive    *bool                    `json:"is_active"`
+}
+
+type ExpressInterestRequest struct {
+	TargetIntentID uint `json:"target_intent_id" binding:"required"`
+}
+
+// ExpressInterestResult informa se o interesse já é mútuo e, nesse caso, a
+// conversa aberta entre os dois viajantes
+type ExpressInterestResult struct {
+	Matched      bool                         `json:"matched"`
+	Conversation *models.ConversationResponse `json:"conversation,omitempty"`
+}
+
+type TravelBuddyService struct {
+	travelBuddyRepo     repositories.TravelBuddyRepositoryInterface
+	userRepo            repositories.UserRepositoryInterface
+	geoService          GeoServiceInterface
+	conversationService ConversationServiceInterface
+}
+
+func NewTravelBuddyService(
+	travelBuddyRepo repositories.TravelBuddyRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	geoService GeoServiceInterface,
+	conversationService ConversationServiceInterface,
+) TravelBuddyServiceInterface {
+	return &TravelBuddyService{
+		travelBuddyRepo:     travelBuddyRepo,
+		userRepo:            userRepo,
+		geoService:          geoService,
+		conversationService: conversationService,
+	}
+}
+
+func (s *TravelBuddyService) CreateIntent(userID uint, req *TravelIntentRequest) (*models.TravelIntentResponse, error) {
+	if err := s.validateIntentRequest(req); err != nil {
+		return nil, err
+	}
+
+	intent := &models.TravelIntent{UserID: userID, IsActive: true}
+	s.applyIntentRequest(intent, req)
+
+	if err := s.travelBuddyRepo.CreateIntent(intent); err != nil {
+		return nil, errors.New("erro ao publicar intenção de viagem")
+	}
+
+	return intent.ToResponse(), nil
+}
+
+func (s *TravelBuddyService) UpdateIntent(intentID, userID uint, req *TravelIntentRequest) (*models.TravelIntentResponse, error) {
+	intent, err := s.getOwnIntent(intentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateIntentRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.applyIntentRequest(intent, req)
+
+	if err := s.travelBuddyRepo.UpdateIntent(intent); err != nil {
+		return nil, errors.New("erro ao atualizar intenção de viagem")
+	}
+
+	return intent.ToResponse(), nil
+}
+
+func (s *TravelBuddyService) DeleteIntent(intentID, userID uint) error {
+	if _, err := s.getOwnIntent(intentID, userID); err != nil {
+		return err
+	}
+
+	if err := s.travelBuddyRepo.DeleteIntent(intentID); err != nil {
+		return errors.New("erro ao deletar intenção de viagem")
+	}
+
+	return nil
+}
+
+func (s *TravelBuddyService) GetMyIntents(userID uint) ([]models.TravelIntentResponse, error) {
+	intents, err := s.travelBuddyRepo.GetIntentsByUser(userID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar intenções de viagem")
+	}
+
+	var responses []models.TravelIntentResponse
+	for _, intent := range intents {
+		responses = append(responses, *intent.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetSuggestions sugere viajantes compatíveis com a intenção informada,
+// pontuando sobreposição de datas, mesma cidade, mesmo estilo de viagem,
+// interesse já demonstrado pelo outro viajante e vínculo de seguidores
+func (s *TravelBuddyService) GetSuggestions(intentID, userID uint, limit int) ([]models.TravelBuddySuggestion, error) {
+	intent, err := s.getOwnIntent(intentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	candidates, err := s.travelBuddyRepo.GetCandidateIntents(intent, 200)
+	if err != nil {
+		return nil, errors.New("erro ao buscar viajantes compatíveis")
+	}
+
+	interested, err := s.travelBuddyRepo.GetInterestedIntentIDs(intent.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar viajantes compatíveis")
+	}
+
+	matched, err := s.travelBuddyRepo.GetMatchedIntentIDs(intent.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar viajantes compatíveis")
+	}
+
+	intentDays := daysBetween(intent.StartDate, intent.EndDate)
+	seenUsers := make(map[uint]bool)
+
+	var suggestions []models.TravelBuddySuggestion
+	for _, candidate := range candidates {
+		// Uma sugestão por viajante, ignorando quem já deu match
+		if matched[candidate.ID] || seenUsers[candidate.UserID] {
+			continue
+		}
+		seenUsers[candidate.UserID] = true
+
+		overlapDays := overlappingDays(intent, &candidate)
+		suggestion := models.TravelBuddySuggestion{
+			Intent:          *candidate.ToResponse(),
+			OverlapDays:     overlapDays,
+			SameCity:        sameTravelCity(intent, &candidate),
+			SameStyle:       intent.Style != "" && intent.Style == candidate.Style,
+			InterestedInYou: interested[candidate.ID],
+		}
+
+		score := 2.0 * float64(overlapDays) / float64(intentDays)
+		if suggestion.SameCity {
+			score += 1.0
+		}
+		if suggestion.SameStyle {
+			score += 1.0
+		}
+		if suggestion.InterestedInYou {
+			score += 1.5
+		}
+		score += s.followAffinity(userID, candidate.UserID)
+
+		suggestion.Score = score
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+// ExpressInterest registra o interesse na intenção de outro viajante. Quando o
+// interesse é recíproco, o match é criado e uma conversa direta é aberta.
+func (s *TravelBuddyService) ExpressInterest(intentID, userID uint, req *ExpressInterestRequest) (*ExpressInterestResult, error) {
+	intent, err := s.getOwnIntent(intentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.travelBuddyRepo.GetIntentByID(req.TargetIntentID)
+	if err != nil || !target.IsActive {
+		return nil, errors.New("intenção de viagem não encontrada")
+	}
+
+	if target.UserID == userID {
+		return nil, errors.New("não é possível demonstrar interesse na própria intenção")
+	}
+
+	interest := &models.TravelBuddyInterest{
+		IntentID:       intent.ID,
+		TargetIntentID: target.ID,
+		UserID:         userID,
+		TargetUserID:   target.UserID,
+	}
+	if err := s.travelBuddyRepo.CreateInterest(interest); err != nil {
+		return nil, errors.New("erro ao registrar interesse")
+	}
+
+	mutual, err := s.travelBuddyRepo.HasInterest(target.ID, intent.ID)
+	if err != nil {
+		return nil, errors.New("erro ao verificar interesse")
+	}
+	if !mutual {
+		return &ExpressInterestResult{Matched: false}, nil
+	}
+
+	conversation, err := s.conversationService.StartDirectConversation(userID, target.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	match := &models.TravelMatch{
+		IntentID:        intent.ID,
+		MatchedIntentID: target.ID,
+		UserID:          userID,
+		MatchedUserID:   target.UserID,
+		ConversationID:  conversation.ID,
+	}
+	if match.IntentID > match.MatchedIntentID {
+		match.IntentID, match.MatchedIntentID = match.MatchedIntentID, match.IntentID
+		match.UserID, match.MatchedUserID = match.MatchedUserID, match.UserID
+	}
+	if err := s.travelBuddyRepo.CreateMatch(match); err != nil {
+		return nil, errors.New("erro ao registrar match")
+	}
+
+	return &ExpressInterestResult{Matched: true, Conversation: conversation}, nil
+}
+
+func (s *TravelBuddyService) GetMatches(userID uint, limit, offset int) ([]models.TravelMatchResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	matches, err := s.travelBuddyRepo.GetMatchesByUser(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar matches")
+	}
+
+	var responses []models.TravelMatchResponse
+	for _, match := range matches {
+		responses = append(responses, *match.ToResponse(userID))
+	}
+
+	return responses, nil
+}
+
+func (s *TravelBuddyService) getOwnIntent(intentID, userID uint) (*models.TravelIntent, error) {
+	intent, err := s.travelBuddyRepo.GetIntentByID(intentID)
+	if err != nil {
+		return nil, errors.New("intenção de viagem não encontrada")
+	}
+
+	if intent.UserID != userID {
+		return nil, errors.New("você não tem permissão para alterar esta intenção de viagem")
+	}
+
+	return intent, nil
+}
+
+func (s *TravelBuddyService) applyIntentRequest(intent *models.TravelIntent, req *TravelIntentRequest) {
+	location := s.geoService.NormalizeLocation(req.Country, "", req.City)
+
+	intent.Country = location.Country
+	intent.City = location.City
+	intent.CountryCode = location.CountryCode
+	intent.CityID = location.CityID
+	intent.StartDate = req.StartDate
+	intent.EndDate = req.EndDate
+	intent.Style = req.Style
+	intent.Description = strings.TrimSpace(req.Description)
+	if req.IsActive != nil {
+		intent.IsActive = *req.IsActive
+	}
+}
+
+// followAffinity favorece viajantes que já se conhecem na rede
+func (s *TravelBuddyService) followAffinity(userID, otherUserID uint) float64 {
+	affinity := 0.0
+	if following, _ := s.userRepo.IsFollowing(userID, otherUserID); following {
+		affinity += 0.25
+	}
+	if followedBy, _ := s.userRepo.IsFollowing(otherUserID, userID); followedBy {
+		affinity += 0.25
+	}
+	return affinity
+}
+
+func sameTravelCity(intent, other *models.TravelIntent) bool {
+	if intent.CityID != nil && other.CityID != nil {
+		return *intent.CityID == *other.CityID
+	}
+	return intent.City != "" && normalizeGeoName(intent.City) == normalizeGeoName(other.City)
+}
+
+func overlappingDays(intent, other *models.TravelIntent) int {
+	start := intent.StartDate
+	if other.StartDate.After(start) {
+		start = other.StartDate
+	}
+	end := intent.EndDate
+	if other.EndDate.Before(end) {
+		end = other.EndDate
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return daysBetween(start, end)
+}
+
+// daysBetween conta os dias do período, incluindo o primeiro e o último
+func daysBetween(start, end time.Time) int {
+	return int(end.Truncate(24*time.Hour).Sub(start.Truncate(24*time.Hour)).Hours()/24) + 1
+}
+
+// Funções de validação
+func (s *TravelBuddyService) validateIntentRequest(req *TravelIntentRequest) error {
+	if len(strings.TrimSpace(req.Country)) < 2 {
+		return errors.New("país é obrigatório")
+	}
+
+	if req.EndDate.Before(req.StartDate) {
+		return errors.New("data de término deve ser posterior à data de início")
+	}
+
+	if req.EndDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		return errors.New("a viagem não pode terminar no passado")
+	}
+
+	if daysBetween(req.StartDate, req.EndDate) > 365 {
+		return errors.New("a viagem deve ter no máximo 365 dias")
+	}
+
+	if req.Style != "" {
+		if err := validateItineraryCategory(req.Style); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/events"
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const maxCheckInPhotos = 10
+
+type CompleteItineraryRequest struct {
+	TripID     *uint      `json:"trip_id"`     // viagem agendada que seguiu o roteiro (opcional)
+	TraveledAt *time.Time `json:"traveled_at"` // padrão: término da viagem informada ou hoje
+	Notes      string     `json:"notes"`
+}
+
+type CheckInRequest struct {
+	Photos []string `json:"photos"`
+	Note   string   `json:"note"`
+}
+
+// ItineraryProgress mostra o que o usuário já fez de um roteiro
+type ItineraryProgress struct {
+	ItineraryID    uint                                `json:"itinerary_id"`
+	Completed      bool                                `json:"completed"`
+	Completion     *models.ItineraryCompletionResponse `json:"completion,omitempty"`
+	LocationsCount int                                 `json:"locations_count"`
+	CheckInsCount  int                                 `json:"check_ins_count"`
+	CheckIns       []models.LocationCheckIn            `json:"check_ins"`
+}
+
+type TravelMapCountry struct {
+	CountryID   *uint  `json:"country_id"`
+	CountryCode string `json:"country_code"`
+	Name        string `json:"name"`
+	Trips       int    `json:"trips"`
+}
+
+type TravelMapPoint struct {
+	LocationID  uint      `json:"location_id"`
+	ItineraryID uint      `json:"itinerary_id"`
+	Name        string    `json:"name"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
+
+// TravelMap é o mapa de viagens do perfil: países e cidades das viagens
+// concluídas e os pontos dos check-ins
+type TravelMap struct {
+	UserID         uint                    `json:"user_id"`
+	CompletedTrips int64                   `json:"completed_trips"`
+	CountriesCount int                     `json:"countries_count"`
+	CitiesCount    int                     `json:"cities_count"`
+	Countries      []TravelMapCountry      `json:"countries"`
+	Cities         []models.TravelMapPlace `json:"cities"`
+	CheckIns       []TravelMapPoint        `json:"check_ins"`
+}
+
+type TravelServiceInterface interface {
+	CompleteItinerary(itineraryID, userID uint, req *CompleteItineraryRequest) (*models.ItineraryCompletionResponse, error)
+	UncompleteItinerary(itineraryID, userID uint) error
+	CheckIn(itineraryID, locationID, userID uint, req *CheckInRequest) (*models.LocationCheckIn, error)
+	RemoveCheckIn(itineraryID, locationID, userID uint) error
+	GetItineraryProgress(itineraryID, userID uint) (*ItineraryProgress, error)
+	GetCompletedTrips(targetUserID, viewerID uint, limit, offset int) ([]*models.ItineraryCompletionResponse, error)
+	GetTravelMap(targetUserID, viewerID uint) (*TravelMap, error)
+}
+
+type TravelService struct {
+	travelRepo    repositories.TravelRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	tripRepo      repositories.TripRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	eventBus      events.BusInterface
+}
+
+func NewTravelService(
+	travelRepo repositories.TravelRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	tripRepo repositories.TripRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	eventBus events.BusInterface,
+) TravelServiceInterface {
+	return &TravelService{
+		travelRepo:    travelRepo,
+		itineraryRepo: itineraryRepo,
+		tripRepo:      tripRepo,
+		userRepo:      userRepo,
+		eventBus:      eventBus,
+	}
+}
+
+// CompleteItinerary marca o roteiro como viajado pelo usuário
+func (s *TravelService) CompleteItinerary(itineraryID, userID uint, req *CompleteItineraryRequest) (*models.ItineraryCompletionResponse, error) {
+	itinerary, err := s.visibleItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Notes) > 1000 {
+		return nil, errors.New("notas devem ter no máximo 1000 caracteres")
+	}
+
+	today := calendarDate(time.Now())
+	traveledAt := today
+
+	if req.TripID != nil {
+		trip, err := s.tripRepo.GetByID(*req.TripID)
+		if err != nil || trip.UserID != userID || trip.ItineraryID != itineraryID {
+			return nil, errors.New("viagem não encontrada")
+		}
+		if end := calendarDate(trip.EndDate); end.Before(today) {
+			traveledAt = end
+		}
+	}
+	if req.TraveledAt != nil {
+		traveledAt = calendarDate(*req.TraveledAt)
+	}
+	if traveledAt.After(today) {
+		return nil, errors.New("data da viagem não pode ser no futuro")
+	}
+
+	completion := &models.ItineraryCompletion{
+		UserID:      userID,
+		ItineraryID: itineraryID,
+		TripID:      req.TripID,
+		TraveledAt:  traveledAt,
+		Notes:       strings.TrimSpace(req.Notes),
+	}
+
+	created, err := s.travelRepo.CreateCompletion(completion)
+	if err != nil {
+		return nil, errors.New("erro ao registrar viagem")
+	}
+	if !created {
+		return nil, errors.New("você já marcou este roteiro como viajado")
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.ItineraryTraveled,
+		ActorID:  userID,
+		EntityID: itinerary.ID,
+		Data: map[string]string{
+			"category": string(itinerary.Category),
+			"text":     strings.Join([]string{itinerary.Title, itinerary.City, itinerary.State, itinerary.Country}, " "),
+		},
+	})
+
+	completion.Itinerary = *itinerary
+	return completion.ToResponse(), nil
+}
+
+// UncompleteItinerary desfaz a marcação; os check-ins são mantidos
+func (s *TravelService) UncompleteItinerary(itineraryID, userID uint) error {
+	removed, err := s.travelRepo.DeleteCompletion(userID, itineraryID)
+	if err != nil {
+		return errors.New("erro ao remover viagem")
+	}
+	if !removed {
+		return errors.New("viagem concluída não encontrada")
+	}
+	return nil
+}
+
+// CheckIn registra a passagem do usuário por um local do roteiro; repetir o
+// check-in atualiza as fotos e a nota, mantendo o horário original
+func (s *TravelService) CheckIn(itineraryID, locationID, userID uint, req *CheckInRequest) (*models.LocationCheckIn, error) {
+	itinerary, err := s.visibleItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var location *models.ItineraryLocation
+	for _, day := range itinerary.Days {
+		for i := range day.Locations {
+			if day.Locations[i].ID == locationID {
+				location = &day.Locations[i]
+			}
+		}
+	}
+	if location == nil {
+		return nil, errors.New("local não encontrado no roteiro")
+	}
+
+	if err := validateCheckInRequest(req); err != nil {
+		return nil, err
+	}
+
+	checkIn, err := s.travelRepo.GetCheckIn(userID, locationID)
+	isNew := err != nil
+	if isNew {
+		checkIn = &models.LocationCheckIn{
+			UserID:      userID,
+			LocationID:  locationID,
+			ItineraryID: itineraryID,
+			CheckedInAt: time.Now(),
+		}
+	}
+
+	checkIn.LocationName = location.Name
+	checkIn.Latitude = location.Latitude
+	checkIn.Longitude = location.Longitude
+	checkIn.Photos = req.Photos
+	if checkIn.Photos == nil {
+		checkIn.Photos = []string{}
+	}
+	checkIn.Note = strings.TrimSpace(req.Note)
+
+	if err := s.travelRepo.SaveCheckIn(checkIn); err != nil {
+		return nil, errors.New("erro ao registrar check-in")
+	}
+
+	if isNew {
+		s.eventBus.Publish(events.Event{
+			Type:     events.LocationCheckedIn,
+			ActorID:  userID,
+			EntityID: locationID,
+			Data: map[string]string{
+				"category":     string(itinerary.Category),
+				"itinerary_id": fmt.Sprint(itineraryID),
+				"text":         strings.Join([]string{location.Name, location.Address, itinerary.City, itinerary.Country}, " "),
+			},
+		})
+	}
+
+	return checkIn, nil
+}
+
+func (s *TravelService) RemoveCheckIn(itineraryID, locationID, userID uint) error {
+	checkIn, err := s.travelRepo.GetCheckIn(userID, locationID)
+	if err != nil || checkIn.ItineraryID != itineraryID {
+		return errors.New("check-in não encontrado")
+	}
+
+	if _, err := s.travelRepo.DeleteCheckIn(userID, locationID); err != nil {
+		return errors.New("erro ao remover check-in")
+	}
+	return nil
+}
+
+func (s *TravelService) GetItineraryProgress(itineraryID, userID uint) (*ItineraryProgress, error) {
+	itinerary, err := s.visibleItinerary(itineraryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkIns, err := s.travelRepo.GetCheckInsByItinerary(userID, itineraryID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar check-ins")
+	}
+
+	progress := &ItineraryProgress{
+		ItineraryID:   itineraryID,
+		CheckInsCount: len(checkIns),
+		CheckIns:      checkIns,
+	}
+	for _, day := range itinerary.Days {
+		progress.LocationsCount += len(day.Locations)
+	}
+
+	if completion, err := s.travelRepo.GetCompletion(userID, itineraryID); err == nil {
+		progress.Completed = true
+		progress.Completion = completion.ToResponse()
+		progress.Completion.CheckIns = len(checkIns)
+	}
+
+	return progress, nil
+}
+
+// GetCompletedTrips lista as viagens concluídas do usuário; para outros
+// usuários aparecem apenas as de roteiros públicos
+func (s *TravelService) GetCompletedTrips(targetUserID, viewerID uint, limit, offset int) ([]*models.ItineraryCompletionResponse, error) {
+	if _, err := s.userRepo.GetByID(targetUserID); err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	completions, err := s.travelRepo.GetCompletionsByUser(targetUserID, targetUserID != viewerID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar viagens concluídas")
+	}
+
+	itineraryIDs := make([]uint, 0, len(completions))
+	for _, completion := range completions {
+		itineraryIDs = append(itineraryIDs, completion.ItineraryID)
+	}
+	checkIns, err := s.travelRepo.CountCheckInsByItinerary(targetUserID, itineraryIDs)
+	if err != nil {
+		return nil, errors.New("erro ao buscar viagens concluídas")
+	}
+
+	responses := make([]*models.ItineraryCompletionResponse, 0, len(completions))
+	for i := range completions {
+		response := completions[i].ToResponse()
+		response.CheckIns = checkIns[completions[i].ItineraryID]
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// GetTravelMap monta o mapa de viagens do perfil. Países e cidades contam
+// todas as viagens concluídas; os pontos de check-in de roteiros privados só
+// aparecem para o próprio usuário
+func (s *TravelService) GetTravelMap(targetUserID, viewerID uint) (*TravelMap, error) {
+	if _, err := s.userRepo.GetByID(targetUserID); err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	completed, err := s.travelRepo.CountCompletions(targetUserID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mapa de viagens")
+	}
+
+	places, err := s.travelRepo.GetMapPlaces(targetUserID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mapa de viagens")
+	}
+
+	checkIns, err := s.travelRepo.GetMapCheckIns(targetUserID, targetUserID != viewerID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mapa de viagens")
+	}
+
+	travelMap := &TravelMap{
+		UserID:         targetUserID,
+		CompletedTrips: completed,
+		Countries:      []TravelMapCountry{},
+		Cities:         []models.TravelMapPlace{},
+		CheckIns:       make([]TravelMapPoint, 0, len(checkIns)),
+	}
+
+	// Os lugares vêm ordenados por número de viagens; países sem referência
+	// geográfica são agrupados pelo nome normalizado
+	countryIndex := make(map[string]int)
+	for _, place := range places {
+		key := normalizeGeoName(place.Country)
+		if place.CountryID != nil {
+			key = fmt.Sprintf("id:%d", *place.CountryID)
+		}
+		if key != "" {
+			if i, ok := countryIndex[key]; ok {
+				travelMap.Countries[i].Trips += place.Trips
+			} else {
+				countryIndex[key] = len(travelMap.Countries)
+				travelMap.Countries = append(travelMap.Countries, TravelMapCountry{
+					CountryID:   place.CountryID,
+					CountryCode: place.CountryCode,
+					Name:        place.Country,
+					Trips:       place.Trips,
+				})
+			}
+		}
+
+		if place.CityID != nil || strings.TrimSpace(place.City) != "" {
+			travelMap.Cities = append(travelMap.Cities, place)
+		}
+	}
+	travelMap.CountriesCount = len(travelMap.Countries)
+	travelMap.CitiesCount = len(travelMap.Cities)
+
+	for _, checkIn := range checkIns {
+		travelMap.CheckIns = append(travelMap.CheckIns, TravelMapPoint{
+			LocationID:  checkIn.LocationID,
+			ItineraryID: checkIn.ItineraryID,
+			Name:        checkIn.LocationName,
+			Latitude:    *checkIn.Latitude,
+			Longitude:   *checkIn.Longitude,
+			CheckedInAt: checkIn.CheckedInAt,
+		})
+	}
+
+	return travelMap, nil
+}
+
+func (s *TravelService) visibleItinerary(itineraryID, userID uint) (*models.Itinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+	return itinerary, nil
+}
+
+// Funções de validação
+
+func validateCheckInRequest(req *CheckInRequest) error {
+	if len(req.Photos) > maxCheckInPhotos {
+		return fmt.Errorf("máximo de %d fotos por check-in", maxCheckInPhotos)
+	}
+
+	for _, photo := range req.Photos {
+		if len(photo) > 500 {
+			return errors.New("URL da foto deve ter no máximo 500 caracteres")
+		}
+		if !strings.HasPrefix(photo, "http://") && !strings.HasPrefix(photo, "https://") {
+			return errors.New("URL da foto deve começar com http:// ou https://")
+		}
+	}
+
+	if len(req.Note) > 500 {
+		return errors.New("nota deve ter no máximo 500 caracteres")
+	}
+
+	return nil
+}
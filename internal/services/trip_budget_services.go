@@ -0,0 +1,240 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// TripBudgetCategory compara o previsto e o realizado de uma categoria
+type TripBudgetCategory struct {
+	Category     models.ExpenseCategory `json:"category"`
+	Estimated    float64                `json:"estimated"`
+	Actual       float64                `json:"actual"`
+	Difference   float64                `json:"difference"`
+	ExpenseCount int                    `json:"expense_count"`
+}
+
+// TripBudgetReport confronta o orçamento estimado do roteiro (ver
+// BudgetService) com as despesas registradas nas datas da viagem, tudo na
+// mesma moeda. Unallocated é a parte do previsto sem categoria
+type TripBudgetReport struct {
+	TripID       uint                 `json:"trip_id"`
+	ItineraryID  uint                 `json:"itinerary_id"`
+	Title        string               `json:"title"`
+	StartDate    string               `json:"start_date"` // YYYY-MM-DD
+	EndDate      string               `json:"end_date"`   // YYYY-MM-DD
+	Currency     string               `json:"currency"`
+	Estimated    float64              `json:"estimated"`
+	Actual       float64              `json:"actual"`
+	Difference   float64              `json:"difference"`
+	Unallocated  float64              `json:"unallocated"`
+	ExpenseCount int                  `json:"expense_count"`
+	Categories   []TripBudgetCategory `json:"categories"`
+}
+
+type TripBudgetServiceInterface interface {
+	GetBudgetReport(tripID, userID uint, currency string) (*TripBudgetReport, error)
+	ExportBudgetReport(tripID, userID uint, currency string) (*ExportResult, error)
+}
+
+type TripBudgetService struct {
+	tripRepo        repositories.TripRepositoryInterface
+	itineraryRepo   repositories.ItineraryRepositoryInterface
+	expenseRepo     repositories.ExpenseRepositoryInterface
+	currencyService CurrencyServiceInterface
+}
+
+func NewTripBudgetService(
+	tripRepo repositories.TripRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	expenseRepo repositories.ExpenseRepositoryInterface,
+	currencyService CurrencyServiceInterface,
+) TripBudgetServiceInterface {
+	return &TripBudgetService{
+		tripRepo:        tripRepo,
+		itineraryRepo:   itineraryRepo,
+		expenseRepo:     expenseRepo,
+		currencyService: currencyService,
+	}
+}
+
+// GetBudgetReport compara, por categoria, o orçamento estimado do roteiro
+// com as despesas registradas entre o início e o fim da viagem, convertendo
+// tudo para a moeda pedida (padrão: a do roteiro)
+func (s *TripBudgetService) GetBudgetReport(tripID, userID uint, currency string) (*TripBudgetReport, error) {
+	trip, err := s.getOwnTrip(tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	itinerary, err := s.itineraryRepo.GetByID(trip.ItineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	budget := rollupBudget(itinerary)
+
+	currency = normalizeCurrency(currency)
+	if currency == "" {
+		currency = budget.Currency
+	} else if !isValidCurrency(currency) {
+		return nil, errors.New("moeda inválida: use um código ISO 4217, como BRL ou USD")
+	}
+	if currency != budget.Currency {
+		rate, err := s.rate(budget.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		convertBudget(budget, rate, currency)
+	}
+
+	expenses, err := s.tripExpenses(trip, itinerary)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TripBudgetReport{
+		TripID:       trip.ID,
+		ItineraryID:  itinerary.ID,
+		Title:        trip.Title,
+		StartDate:    trip.StartDate.Format("2006-01-02"),
+		EndDate:      trip.EndDate.Format("2006-01-02"),
+		Currency:     currency,
+		Estimated:    budget.Total,
+		Unallocated:  budget.Unallocated,
+		ExpenseCount: len(expenses),
+		Categories:   []TripBudgetCategory{},
+	}
+
+	// Somado em centavos, como no resumo de despesas
+	actual := make(map[models.ExpenseCategory]int64)
+	counts := make(map[models.ExpenseCategory]int)
+	for _, expense := range expenses {
+		amount := expense.Amount
+		if expense.Currency != currency {
+			rate, err := s.rate(expense.Currency, currency)
+			if err != nil {
+				return nil, err
+			}
+			amount *= rate
+		}
+		actual[expense.Category] += int64(math.Round(amount * 100))
+		counts[expense.Category]++
+	}
+
+	var total int64
+	for i, category := range budgetCategories {
+		expenseCategory := models.ExpenseCategory(category)
+		line := TripBudgetCategory{
+			Category:     expenseCategory,
+			Estimated:    budget.Categories[i].Amount,
+			Actual:       float64(actual[expenseCategory]) / 100,
+			ExpenseCount: counts[expenseCategory],
+		}
+		line.Difference = roundCents(line.Actual - line.Estimated)
+		report.Categories = append(report.Categories, line)
+		total += actual[expenseCategory]
+	}
+	report.Actual = float64(total) / 100
+	report.Difference = roundCents(report.Actual - report.Estimated)
+
+	return report, nil
+}
+
+// ExportBudgetReport gera o relatório de orçamento da viagem em CSV
+func (s *TripBudgetService) ExportBudgetReport(tripID, userID uint, currency string) (*ExportResult, error) {
+	report, err := s.GetBudgetReport(tripID, userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := encodeTripBudgetCSV(report)
+	if err != nil {
+		return nil, errors.New("erro ao gerar relatório de orçamento")
+	}
+
+	return &ExportResult{
+		FileName:    fmt.Sprintf("viagem-%d-orcamento.csv", report.TripID),
+		ContentType: "text/csv; charset=utf-8",
+		Data:        data,
+	}, nil
+}
+
+// getOwnTrip só encontra as viagens do próprio usuário
+func (s *TripBudgetService) getOwnTrip(tripID, userID uint) (*models.Trip, error) {
+	trip, err := s.tripRepo.GetByID(tripID)
+	if err != nil || trip.UserID != userID {
+		return nil, errors.New("viagem não encontrada")
+	}
+	return trip, nil
+}
+
+// tripExpenses busca as despesas do roteiro feitas nos dias da viagem. As
+// despesas são privadas dos participantes do roteiro: a viagem de quem não
+// participa não tem despesas
+func (s *TripBudgetService) tripExpenses(trip *models.Trip, itinerary *models.Itinerary) ([]models.Expense, error) {
+	if itinerary.AuthorID != trip.UserID {
+		collaborator, err := s.expenseRepo.IsCollaborator(itinerary.ID, trip.UserID)
+		if err != nil {
+			return nil, errors.New("erro ao buscar despesas")
+		}
+		if !collaborator {
+			return nil, nil
+		}
+	}
+
+	expenses, err := s.expenseRepo.GetAllByItinerary(itinerary.ID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar despesas")
+	}
+
+	from := calendarDate(trip.StartDate)
+	to := calendarDate(trip.EndDate).AddDate(0, 0, 1)
+	inTrip := make([]models.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		if !expense.SpentAt.Before(from) && expense.SpentAt.Before(to) {
+			inTrip = append(inTrip, expense)
+		}
+	}
+	return inTrip, nil
+}
+
+// rate retorna o fator para converter valores de from para to
+func (s *TripBudgetService) rate(from, to string) (float64, error) {
+	fromRate, okFrom := s.currencyService.RateFor(from)
+	toRate, okTo := s.currencyService.RateFor(to)
+	if !okFrom || !okTo {
+		return 0, fmt.Errorf("cotação indisponível para converter %s em %s", from, to)
+	}
+	return toRate / fromRate, nil
+}
+
+// encodeTripBudgetCSV escreve uma linha por categoria, a parte do previsto
+// sem categoria (quando houver) e o total
+func encodeTripBudgetCSV(report *TripBudgetReport) ([]byte, error) {
+	amount := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	rows := [][]string{{"category", "currency", "estimated", "actual", "difference", "expenses"}}
+	for _, line := range report.Categories {
+		rows = append(rows, []string{string(line.Category), report.Currency, amount(line.Estimated), amount(line.Actual), amount(line.Difference), strconv.Itoa(line.ExpenseCount)})
+	}
+	if report.Unallocated != 0 {
+		rows = append(rows, []string{"unallocated", report.Currency, amount(report.Unallocated), amount(0), amount(-report.Unallocated), "0"})
+	}
+	rows = append(rows, []string{"total", report.Currency, amount(report.Estimated), amount(report.Actual), amount(report.Difference), strconv.Itoa(report.ExpenseCount)})
+
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
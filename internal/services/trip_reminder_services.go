@@ -0,0 +1,397 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Dias de antecedência dos lembretes de viagem, do maior para o menor; é
+// também o padrão das viagens que não escolhem os seus
+var tripReminderOffsets = []int{7, 1}
+
+// Limite de itens na lista de bagagem de uma viagem
+const maxPackingItems = 200
+
+type TripReminderSettingsRequest struct {
+	// Dias de antecedência dos lembretes, entre 7 e 1; vazio desativa os
+	// lembretes
+	Days []int `json:"days" binding:"required"`
+}
+
+type PackingItemRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdatePackingItemRequest struct {
+	Name   *string `json:"name"`
+	Packed *bool   `json:"packed"`
+}
+
+// TripReminderServiceInterface avisa os viajantes antes da partida e cuida
+// da lista de bagagem, cuja situação vai nos lembretes
+type TripReminderServiceInterface interface {
+	GetReminderSettings(tripID, userID uint) (*models.TripReminderSetting, error)
+	UpdateReminderSettings(tripID, userID uint, req *TripReminderSettingsRequest) (*models.TripReminderSetting, error)
+	GetPackingList(tripID, userID uint) ([]models.TripPackingItem, error)
+	AddPackingItem(tripID, userID uint, req *PackingItemRequest) (*models.TripPackingItem, error)
+	UpdatePackingItem(tripID, itemID, userID uint, req *UpdatePackingItemRequest) (*models.TripPackingItem, error)
+	DeletePackingItem(tripID, itemID, userID uint) error
+	SendTripReminders(now time.Time) (int, error)
+	StartTripReminderScheduler(interval time.Duration)
+}
+
+type TripReminderService struct {
+	tripRepo            repositories.TripRepositoryInterface
+	reminderRepo        repositories.TripReminderRepositoryInterface
+	weatherService      WeatherServiceInterface
+	notificationService NotificationServiceInterface
+}
+
+func NewTripReminderService(
+	tripRepo repositories.TripRepositoryInterface,
+	reminderRepo repositories.TripReminderRepositoryInterface,
+	weatherService WeatherServiceInterface,
+	notificationService NotificationServiceInterface,
+) TripReminderServiceInterface {
+	return &TripReminderService{
+		tripRepo:            tripRepo,
+		reminderRepo:        reminderRepo,
+		weatherService:      weatherService,
+		notificationService: notificationService,
+	}
+}
+
+func (s *TripReminderService) GetReminderSettings(tripID, userID uint) (*models.TripReminderSetting, error) {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	setting, err := s.reminderRepo.GetReminderSetting(tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.TripReminderSetting{TripID: tripID, Days: tripReminderOffsets}, nil
+		}
+		return nil, errors.New("erro ao buscar lembretes da viagem")
+	}
+	return setting, nil
+}
+
+func (s *TripReminderService) UpdateReminderSettings(tripID, userID uint, req *TripReminderSettingsRequest) (*models.TripReminderSetting, error) {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	days, err := normalizeReminderDays(req.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	setting := &models.TripReminderSetting{TripID: tripID, Days: days}
+	if err := s.reminderRepo.SaveReminderSetting(setting); err != nil {
+		return nil, errors.New("erro ao salvar lembretes da viagem")
+	}
+	return setting, nil
+}
+
+func (s *TripReminderService) GetPackingList(tripID, userID uint) ([]models.TripPackingItem, error) {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.reminderRepo.GetPackingItems(tripID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar lista de bagagem")
+	}
+	return items, nil
+}
+
+func (s *TripReminderService) AddPackingItem(tripID, userID uint, req *PackingItemRequest) (*models.TripPackingItem, error) {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	name, err := validatePackingItemName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.reminderRepo.GetPackingStatus(tripID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar lista de bagagem")
+	}
+	if status.Total >= maxPackingItems {
+		return nil, fmt.Errorf("a lista de bagagem pode ter no máximo %d itens", maxPackingItems)
+	}
+
+	item := &models.TripPackingItem{TripID: tripID, Name: name}
+	if err := s.reminderRepo.CreatePackingItem(item); err != nil {
+		return nil, errors.New("erro ao adicionar item à lista de bagagem")
+	}
+	return item, nil
+}
+
+func (s *TripReminderService) UpdatePackingItem(tripID, itemID, userID uint, req *UpdatePackingItemRequest) (*models.TripPackingItem, error) {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.reminderRepo.GetPackingItem(tripID, itemID)
+	if err != nil {
+		return nil, errors.New("item da lista de bagagem não encontrado")
+	}
+
+	if req.Name != nil {
+		if item.Name, err = validatePackingItemName(*req.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Packed != nil {
+		item.Packed = *req.Packed
+	}
+
+	if err := s.reminderRepo.UpdatePackingItem(item); err != nil {
+		return nil, errors.New("erro ao atualizar item da lista de bagagem")
+	}
+	return item, nil
+}
+
+func (s *TripReminderService) DeletePackingItem(tripID, itemID, userID uint) error {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.reminderRepo.DeletePackingItem(tripID, itemID)
+	if err != nil {
+		return errors.New("erro ao remover item da lista de bagagem")
+	}
+	if !deleted {
+		return errors.New("item da lista de bagagem não encontrado")
+	}
+	return nil
+}
+
+// SendTripReminders avisa os viajantes das viagens que começam nos próximos
+// dias, uma vez por antecedência escolhida na viagem (7 e 1 dia por padrão).
+// Uma viagem criada já dentro do prazo recebe o lembrete da menor
+// antecedência que ainda cabe. O aviso traz a situação da lista de bagagem e
+// a previsão do tempo do primeiro dia e sai pelos canais que o usuário
+// mantém ativos para lembretes de viagem. A chave inclui a data de início e
+// a antecedência, então uma viagem remarcada gera novos lembretes
+func (s *TripReminderService) SendTripReminders(now time.Time) (int, error) {
+	today := calendarDate(now)
+
+	trips, err := s.reminderRepo.GetTripsStartingBetween(today, today.AddDate(0, 0, tripReminderOffsets[0]))
+	if err != nil {
+		return 0, errors.New("erro ao buscar viagens")
+	}
+
+	tripIDs := make([]uint, len(trips))
+	for i := range trips {
+		tripIDs[i] = trips[i].ID
+	}
+	settings, err := s.reminderRepo.GetReminderSettings(tripIDs)
+	if err != nil {
+		return 0, errors.New("erro ao buscar lembretes das viagens")
+	}
+	reminderDays := make(map[uint][]int, len(settings))
+	for _, setting := range settings {
+		reminderDays[setting.TripID] = setting.Days
+	}
+
+	sent := 0
+	for i := range trips {
+		trip := &trips[i]
+		startDate := calendarDate(trip.StartDate)
+		daysUntil := int(startDate.Sub(today).Hours() / 24)
+
+		days, ok := reminderDays[trip.ID]
+		if !ok {
+			days = tripReminderOffsets
+		}
+		offset, ok := reminderOffset(days, daysUntil)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("trip:%d:%s:%dd", trip.ID, startDate.Format("2006-01-02"), offset)
+
+		created, err := s.notificationService.Notify(&models.Notification{
+			UserID: trip.UserID,
+			Type:   models.NotificationTypeTripReminder,
+			Title:  trip.Title,
+			Body:   s.tripReminderBody(trip, daysUntil),
+			Data: map[string]string{
+				"trip_id":       fmt.Sprint(trip.ID),
+				"itinerary_id":  fmt.Sprint(trip.ItineraryID),
+				"start_date":    startDate.Format("2006-01-02"),
+				"reminder_days": strconv.Itoa(offset),
+			},
+			Key: &key,
+		})
+		if err != nil {
+			log.Printf("Falha ao enviar lembrete da viagem %d: %v", trip.ID, err)
+			continue
+		}
+		if created {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartTripReminderScheduler envia os lembretes de viagem periodicamente em segundo plano
+func (s *TripReminderService) StartTripReminderScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if sent, err := s.SendTripReminders(time.Now()); err != nil {
+				log.Println("Falha ao enviar lembretes de viagem:", err)
+			} else if sent > 0 {
+				log.Printf("%d lembretes de viagem enviados", sent)
+			}
+		}
+	}()
+}
+
+// getOwnTrip só encontra as viagens do próprio usuário
+func (s *TripReminderService) getOwnTrip(tripID, userID uint) (*models.Trip, error) {
+	trip, err := s.tripRepo.GetByID(tripID)
+	if err != nil || trip.UserID != userID {
+		return nil, errors.New("viagem não encontrada")
+	}
+	return trip, nil
+}
+
+// tripReminderBody junta ao aviso da partida a situação da lista de bagagem
+// e a previsão do tempo, quando disponíveis
+func (s *TripReminderService) tripReminderBody(trip *models.Trip, daysUntil int) string {
+	parts := []string{tripReminderMessage(trip.Title, daysUntil)}
+
+	if status, err := s.reminderRepo.GetPackingStatus(trip.ID); err != nil {
+		log.Printf("Falha ao buscar lista de bagagem da viagem %d: %v", trip.ID, err)
+	} else {
+		parts = append(parts, packingStatusMessage(status))
+	}
+
+	if outlook := s.weatherOutlook(trip); outlook != "" {
+		parts = append(parts, outlook)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// weatherOutlook descreve a previsão do primeiro dia da viagem que já tem
+// previsão; fica vazio fora do alcance do provedor ou sem locais
+// geolocalizados
+func (s *TripReminderService) weatherOutlook(trip *models.Trip) string {
+	weather, err := s.weatherService.GetItineraryWeather(trip.ItineraryID, trip.UserID, trip.StartDate)
+	if err != nil {
+		log.Printf("Falha ao buscar previsão do tempo da viagem %d: %v", trip.ID, err)
+		return ""
+	}
+
+	for _, day := range weather.Days {
+		if day.Status != WeatherStatusOK || day.Forecast == nil {
+			continue
+		}
+
+		date, _ := time.Parse("2006-01-02", day.Date)
+		outlook := fmt.Sprintf("Previsão para %s", date.Format("02/01"))
+		if day.LocationName != "" {
+			outlook += " em " + day.LocationName
+		}
+		outlook += ": "
+		if day.Forecast.Description != "" {
+			outlook += strings.ToLower(day.Forecast.Description) + ", "
+		}
+		outlook += fmt.Sprintf("de %.0f °C a %.0f °C", day.Forecast.TempMinC, day.Forecast.TempMaxC)
+		if probability := day.Forecast.PrecipitationProbability; probability != nil && *probability > 0 {
+			outlook += fmt.Sprintf(", %.0f%% de chance de chuva", *probability)
+		}
+		return outlook + "."
+	}
+	return ""
+}
+
+func tripReminderMessage(title string, daysUntil int) string {
+	switch daysUntil {
+	case 0:
+		return fmt.Sprintf("Sua viagem \"%s\" começa hoje. Boa viagem!", title)
+	case 1:
+		return fmt.Sprintf("Sua viagem \"%s\" começa amanhã.", title)
+	default:
+		return fmt.Sprintf("Sua viagem \"%s\" começa em %d dias.", title, daysUntil)
+	}
+}
+
+func packingStatusMessage(status *models.TripPackingStatus) string {
+	switch {
+	case status.Total == 0:
+		return "Sua lista de bagagem ainda está vazia."
+	case status.Packed == status.Total:
+		return "Bagagem pronta: todos os itens da lista foram separados."
+	default:
+		return fmt.Sprintf("Bagagem: %d de %d itens separados.", status.Packed, status.Total)
+	}
+}
+
+// reminderOffset escolhe a menor antecedência que ainda cabe nos dias que
+// faltam para a partida
+func reminderOffset(reminderDays []int, daysUntil int) (int, bool) {
+	offset, found := 0, false
+	for _, days := range reminderDays {
+		if days >= daysUntil && (!found || days < offset) {
+			offset, found = days, true
+		}
+	}
+	return offset, found
+}
+
+// Funções de validação
+
+// normalizeReminderDays aceita só as antecedências oferecidas, sem repetição,
+// da maior para a menor
+func normalizeReminderDays(days []int) ([]int, error) {
+	normalized := make([]int, 0, len(days))
+	seen := make(map[int]bool, len(days))
+	for _, day := range days {
+		valid := false
+		for _, offset := range tripReminderOffsets {
+			if day == offset {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, errors.New("lembretes inválidos: use 7 e/ou 1 dia antes da viagem")
+		}
+		if !seen[day] {
+			seen[day] = true
+			normalized = append(normalized, day)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
+	return normalized, nil
+}
+
+func validatePackingItemName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("nome do item é obrigatório")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", errors.New("nome do item deve ter no máximo 100 caracteres")
+	}
+	return name, nil
+}
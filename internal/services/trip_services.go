@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+type CreateTripRequest struct {
+	ItineraryID uint       `json:"itinerary_id" binding:"required"`
+	Title       string     `json:"title"`
+	StartDate   time.Time  `json:"start_date" binding:"required"`
+	EndDate     *time.Time `json:"end_date"` // padrão: início + duração do roteiro
+	Notes       string     `json:"notes"`
+}
+
+type UpdateTripRequest struct {
+	Title     *string    `json:"title"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	Notes     *string    `json:"notes"`
+}
+
+type TripScheduleLocation struct {
+	LocationID   uint                `json:"location_id"`
+	Name         string              `json:"name"`
+	LocationType models.LocationType `json:"location_type"`
+	Address      string              `json:"address"`
+	Latitude     *float64            `json:"latitude"`
+	Longitude    *float64            `json:"longitude"`
+	StartsAt     *time.Time          `json:"starts_at"`
+	EndsAt       *time.Time          `json:"ends_at"`
+}
+
+// TripScheduleDay é um dia do roteiro na data real da viagem; InTrip é falso
+// para os dias do roteiro que ficam depois da data de término
+type TripScheduleDay struct {
+	DayID     uint                   `json:"day_id"`
+	DayNumber int                    `json:"day_number"`
+	Title     string                 `json:"title"`
+	Date      string                 `json:"date"` // YYYY-MM-DD
+	Timezone  string                 `json:"timezone"`
+	InTrip    bool                   `json:"in_trip"`
+	Locations []TripScheduleLocation `json:"locations"`
+}
+
+type TripSchedule struct {
+	Trip *models.TripResponse `json:"trip"`
+	Days []TripScheduleDay    `json:"days"`
+}
+
+type TripServiceInterface interface {
+	CreateTrip(userID uint, req *CreateTripRequest) (*models.TripResponse, error)
+	GetTrip(tripID, userID uint) (*TripSchedule, error)
+	UpdateTrip(tripID, userID uint, req *UpdateTripRequest) (*models.TripResponse, error)
+	DeleteTrip(tripID, userID uint) error
+	GetUpcomingTrips(userID uint, limit, offset int) ([]*models.TripResponse, error)
+}
+
+type TripService struct {
+	tripRepo      repositories.TripRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	geoService    GeoServiceInterface
+}
+
+func NewTripService(
+	tripRepo repositories.TripRepositoryInterface,
+	itineraryRepo repositories.ItineraryRepositoryInterface,
+	geoService GeoServiceInterface,
+) TripServiceInterface {
+	return &TripService{
+		tripRepo:      tripRepo,
+		itineraryRepo: itineraryRepo,
+		geoService:    geoService,
+	}
+}
+
+func (s *TripService) CreateTrip(userID uint, req *CreateTripRequest) (*models.TripResponse, error) {
+	itinerary, err := s.itineraryRepo.GetByID(req.ItineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	startDate := calendarDate(req.StartDate)
+	endDate := startDate
+	if req.EndDate != nil {
+		endDate = calendarDate(*req.EndDate)
+	} else if itinerary.Duration > 1 {
+		endDate = startDate.AddDate(0, 0, itinerary.Duration-1)
+	}
+
+	if err := validateTripDates(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = itinerary.Title
+	}
+	if len(title) > 200 {
+		return nil, errors.New("título deve ter no máximo 200 caracteres")
+	}
+
+	trip := &models.Trip{
+		UserID:      userID,
+		ItineraryID: itinerary.ID,
+		Title:       title,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Notes:       strings.TrimSpace(req.Notes),
+	}
+
+	if err := s.tripRepo.Create(trip); err != nil {
+		return nil, errors.New("erro ao criar viagem")
+	}
+
+	trip.Itinerary = *itinerary
+	return trip.ToResponse(time.Now()), nil
+}
+
+// GetTrip retorna a viagem com os dias do roteiro nas datas reais e os
+// horários dos locais convertidos em instantes, no fuso de cada dia
+func (s *TripService) GetTrip(tripID, userID uint) (*TripSchedule, error) {
+	trip, err := s.getOwnTrip(tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	itinerary, err := s.itineraryRepo.GetByID(trip.ItineraryID)
+	if err != nil {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	schedule := &TripSchedule{
+		Trip: trip.ToResponse(time.Now()),
+		Days: []TripScheduleDay{},
+	}
+
+	for _, day := range sortedDays(itinerary) {
+		loc := dayLocation(s.geoService, itinerary, &day)
+		year, month, dayOfMonth := trip.StartDate.AddDate(0, 0, day.DayNumber-1).Date()
+		date := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, loc)
+
+		scheduleDay := TripScheduleDay{
+			DayID:     day.ID,
+			DayNumber: day.DayNumber,
+			Title:     day.Title,
+			Date:      date.Format("2006-01-02"),
+			Timezone:  loc.String(),
+			InTrip:    !calendarDate(date).After(calendarDate(trip.EndDate)),
+			Locations: []TripScheduleLocation{},
+		}
+
+		for _, location := range sortedLocations(&day) {
+			item := TripScheduleLocation{
+				LocationID:   location.ID,
+				Name:         location.Name,
+				LocationType: location.LocationType,
+				Address:      location.Address,
+				Latitude:     location.Latitude,
+				Longitude:    location.Longitude,
+			}
+			if location.StartTime != nil {
+				start := atClock(date, *location.StartTime, loc)
+				item.StartsAt = &start
+				if location.EndTime != nil {
+					end := atClock(date, *location.EndTime, loc)
+					if !end.After(start) {
+						end = end.AddDate(0, 0, 1)
+					}
+					item.EndsAt = &end
+				}
+			}
+			scheduleDay.Locations = append(scheduleDay.Locations, item)
+		}
+
+		schedule.Days = append(schedule.Days, scheduleDay)
+	}
+
+	return schedule, nil
+}
+
+func (s *TripService) UpdateTrip(tripID, userID uint, req *UpdateTripRequest) (*models.TripResponse, error) {
+	trip, err := s.getOwnTrip(tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, errors.New("título não pode ser vazio")
+		}
+		if len(title) > 200 {
+			return nil, errors.New("título deve ter no máximo 200 caracteres")
+		}
+		trip.Title = title
+	}
+
+	if req.StartDate != nil {
+		// Sem nova data de término, a viagem é deslocada mantendo a duração
+		startDate := calendarDate(*req.StartDate)
+		if req.EndDate == nil {
+			trip.EndDate = trip.EndDate.AddDate(0, 0, int(startDate.Sub(calendarDate(trip.StartDate)).Hours()/24))
+		}
+		trip.StartDate = startDate
+	}
+	if req.EndDate != nil {
+		trip.EndDate = calendarDate(*req.EndDate)
+	}
+	if req.StartDate != nil || req.EndDate != nil {
+		if err := validateTripDates(trip.StartDate, trip.EndDate); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Notes != nil {
+		trip.Notes = strings.TrimSpace(*req.Notes)
+	}
+
+	if err := s.tripRepo.Update(trip); err != nil {
+		return nil, errors.New("erro ao atualizar viagem")
+	}
+
+	return trip.ToResponse(time.Now()), nil
+}
+
+func (s *TripService) DeleteTrip(tripID, userID uint) error {
+	if _, err := s.getOwnTrip(tripID, userID); err != nil {
+		return err
+	}
+
+	if err := s.tripRepo.Delete(tripID); err != nil {
+		return errors.New("erro ao remover viagem")
+	}
+
+	return nil
+}
+
+func (s *TripService) GetUpcomingTrips(userID uint, limit, offset int) ([]*models.TripResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	now := time.Now()
+	trips, err := s.tripRepo.GetUpcomingByUser(userID, calendarDate(now), limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar viagens")
+	}
+
+	responses := make([]*models.TripResponse, len(trips))
+	for i := range trips {
+		responses[i] = trips[i].ToResponse(now)
+	}
+
+	return responses, nil
+}
+
+// getOwnTrip só encontra as viagens do próprio usuário
+func (s *TripService) getOwnTrip(tripID, userID uint) (*models.Trip, error) {
+	trip, err := s.tripRepo.GetByID(tripID)
+	if err != nil || trip.UserID != userID {
+		return nil, errors.New("viagem não encontrada")
+	}
+	return trip, nil
+}
+
+// calendarDate descarta o horário e o fuso, mantendo o dia informado
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Funções de validação
+
+func validateTripDates(startDate, endDate time.Time) error {
+	if endDate.Before(startDate) {
+		return errors.New("data de término deve ser posterior à data de início")
+	}
+
+	if daysBetween(startDate, endDate) > 365 {
+		return errors.New("a viagem deve ter no máximo 365 dias")
+	}
+
+	return nil
+}
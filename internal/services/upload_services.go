@@ -0,0 +1,362 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	// Tamanho máximo de cada parte enviada numa requisição
+	maxUploadChunkSize = 10 * 1024 * 1024
+	uploadCleanupBatch = 100
+)
+
+type UploadServiceInterface interface {
+	CreateSession(userID uint, req *CreateUploadRequest) (*models.UploadSession, error)
+	GetSession(sessionID, userID uint) (*models.UploadSession, error)
+	AppendChunk(sessionID, userID uint, offset int64, chunk io.Reader) (*models.UploadSession, error)
+	CompleteSession(sessionID, userID uint) (*MediaUploadResponse, error)
+	AbortSession(sessionID, userID uint) error
+	StartUploadCleanupScheduler(interval time.Duration)
+}
+
+type UploadService struct {
+	uploadRepo   repositories.UploadRepositoryInterface
+	mediaRepo    repositories.MediaRepositoryInterface
+	mediaService MediaServiceInterface
+	config       *MediaConfig
+
+	// Uma requisição por vez em cada sessão; as partes são gravadas no mesmo arquivo
+	locks sync.Map
+}
+
+type CreateUploadRequest struct {
+	FileName  string    `json:"file_name" binding:"required"`
+	MediaType MediaType `json:"media_type" binding:"required"`
+	MimeType  string    `json:"mime_type,omitempty"`
+	TotalSize int64     `json:"total_size" binding:"required"`
+}
+
+func NewUploadService(uploadRepo repositories.UploadRepositoryInterface, mediaRepo repositories.MediaRepositoryInterface, mediaService MediaServiceInterface, config *MediaConfig) UploadServiceInterface {
+	if err := os.MkdirAll(config.UploadTempPath, 0755); err != nil {
+		log.Printf("Falha ao criar diretório de uploads em partes %s: %v", config.UploadTempPath, err)
+	}
+
+	return &UploadService{
+		uploadRepo:   uploadRepo,
+		mediaRepo:    mediaRepo,
+		mediaService: mediaService,
+		config:       config,
+	}
+}
+
+// CreateSession abre um upload em partes; o arquivo só é validado pelo nome e
+// tamanho declarados, e os bytes chegam depois via AppendChunk
+func (s *UploadService) CreateSession(userID uint, req *CreateUploadRequest) (*models.UploadSession, error) {
+	if err := s.validateCreateUploadRequest(req); err != nil {
+		return nil, err
+	}
+
+	session := &models.UploadSession{
+		OwnerID:   userID,
+		FileName:  filepath.Base(strings.TrimSpace(req.FileName)),
+		MediaType: req.MediaType,
+		MimeType:  strings.TrimSpace(req.MimeType),
+		TotalSize: req.TotalSize,
+		Status:    models.UploadStatusUploading,
+		ExpiresAt: time.Now().Add(s.config.UploadSessionTTL),
+	}
+	var quotaErr error
+	err := s.uploadRepo.CreateWithinQuota(session, func(open, pendingBytes int64) error {
+		if open >= int64(s.config.MaxOpenUploadSessions) {
+			quotaErr = fmt.Errorf("limite de %d uploads em andamento atingido: conclua ou cancele um upload antes de iniciar outro", s.config.MaxOpenUploadSessions)
+		} else if pendingBytes+req.TotalSize > s.config.MaxPendingUploadBytes {
+			quotaErr = fmt.Errorf("uploads em andamento excedem o limite de %d MB pendentes por usuário", s.config.MaxPendingUploadBytes/(1024*1024))
+		}
+		return quotaErr
+	})
+	if quotaErr != nil {
+		return nil, quotaErr
+	}
+	if err != nil {
+		return nil, errors.New("erro ao criar sessão de upload")
+	}
+
+	file, err := os.Create(s.partPath(session.ID))
+	if err != nil {
+		if delErr := s.uploadRepo.Delete(session.ID); delErr != nil {
+			log.Printf("Falha ao remover sessão de upload %d: %v", session.ID, delErr)
+		}
+		return nil, errors.New("erro ao criar sessão de upload")
+	}
+	file.Close()
+
+	return session, nil
+}
+
+func (s *UploadService) GetSession(sessionID, userID uint) (*models.UploadSession, error) {
+	return s.getOwnSession(sessionID, userID)
+}
+
+// AppendChunk grava a parte a partir de offset, que precisa ser exatamente o
+// offset atual da sessão. Se a conexão cair no meio da parte, os bytes já
+// recebidos são mantidos e o cliente retoma a partir do novo offset
+func (s *UploadService) AppendChunk(sessionID, userID uint, offset int64, chunk io.Reader) (*models.UploadSession, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.getOwnSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.UploadStatusUploading {
+		return nil, errors.New("upload já concluído")
+	}
+	if offset != session.Offset {
+		return nil, fmt.Errorf("offset inválido: esperado %d", session.Offset)
+	}
+
+	remaining := session.TotalSize - session.Offset
+	if remaining == 0 {
+		return nil, errors.New("todos os bytes já foram recebidos; conclua o upload")
+	}
+	limit := remaining
+	if limit > maxUploadChunkSize {
+		limit = maxUploadChunkSize
+	}
+
+	file, err := os.OpenFile(s.partPath(session.ID), os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.New("erro ao gravar parte do upload")
+	}
+	defer file.Close()
+
+	// Descarta restos de uma gravação anterior que não chegou a avançar o offset
+	if err := file.Truncate(offset); err != nil {
+		return nil, errors.New("erro ao gravar parte do upload")
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, errors.New("erro ao gravar parte do upload")
+	}
+
+	written, copyErr := io.Copy(file, io.LimitReader(chunk, limit+1))
+	if written > limit {
+		if err := file.Truncate(offset); err != nil {
+			log.Printf("Falha ao descartar parte do upload %d: %v", session.ID, err)
+		}
+		if limit < remaining {
+			return nil, fmt.Errorf("parte muito grande. Tamanho máximo: %d MB", maxUploadChunkSize/(1024*1024))
+		}
+		return nil, errors.New("parte excede o tamanho declarado do arquivo")
+	}
+
+	if written > 0 {
+		newOffset := offset + written
+		updated, err := s.uploadRepo.UpdateOffset(session.ID, offset, newOffset, time.Now().Add(s.config.UploadSessionTTL))
+		if err != nil {
+			return nil, errors.New("erro ao atualizar sessão de upload")
+		}
+		if !updated {
+			return nil, errors.New("offset inválido: a sessão foi alterada por outra requisição")
+		}
+		session.Offset = newOffset
+	}
+
+	if copyErr != nil {
+		return nil, fmt.Errorf("erro ao receber parte do upload; retome a partir do offset %d", session.Offset)
+	}
+
+	return session, nil
+}
+
+// CompleteSession registra a mídia depois que todos os bytes chegaram.
+// Repetir a chamada numa sessão concluída devolve a mesma mídia
+func (s *UploadService) CompleteSession(sessionID, userID uint) (*MediaUploadResponse, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.getOwnSession(sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status == models.UploadStatusCompleted && session.MediaID != nil {
+		media, err := s.mediaRepo.GetByID(*session.MediaID)
+		if err != nil {
+			return nil, errors.New("mídia não encontrada")
+		}
+		return mediaUploadResponse(media), nil
+	}
+
+	if session.Offset != session.TotalSize {
+		return nil, fmt.Errorf("upload incompleto: recebidos %d de %d bytes", session.Offset, session.TotalSize)
+	}
+
+	file, err := os.Open(s.partPath(session.ID))
+	if err != nil {
+		return nil, errors.New("erro ao ler arquivo do upload")
+	}
+	defer file.Close()
+
+	response, err := s.mediaService.StoreUpload(file, session.FileName, session.TotalSize, session.OwnerID, session.MediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.uploadRepo.Complete(session.ID, response.ID); err != nil {
+		return nil, errors.New("erro ao concluir sessão de upload")
+	}
+	s.removePart(session.ID)
+
+	return response, nil
+}
+
+// AbortSession cancela o upload e descarta os bytes recebidos
+func (s *UploadService) AbortSession(sessionID, userID uint) error {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.getOwnSession(sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if session.Status == models.UploadStatusCompleted {
+		return errors.New("upload já concluído")
+	}
+
+	if err := s.uploadRepo.Delete(session.ID); err != nil {
+		return errors.New("erro ao cancelar upload")
+	}
+	s.removePart(session.ID)
+
+	return nil
+}
+
+// StartUploadCleanupScheduler remove periodicamente as sessões expiradas e
+// os arquivos parciais que ficaram para trás
+func (s *UploadService) StartUploadCleanupScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			removed := s.cleanupSessions()
+			if removed > 0 {
+				log.Printf("%d sessões de upload expiradas removidas", removed)
+			}
+		}
+	}()
+}
+
+func (s *UploadService) cleanupSessions() int {
+	removed := 0
+	for {
+		sessions, err := s.uploadRepo.GetExpired(time.Now(), uploadCleanupBatch)
+		if err != nil {
+			log.Println("Falha ao buscar sessões de upload expiradas:", err)
+			return removed
+		}
+
+		for _, session := range sessions {
+			unlock := s.lock(session.ID)
+			err := s.uploadRepo.Delete(session.ID)
+			if err == nil {
+				s.removePart(session.ID)
+			}
+			unlock()
+
+			if err != nil {
+				log.Printf("Falha ao remover sessão de upload %d: %v", session.ID, err)
+				return removed
+			}
+			removed++
+		}
+
+		if len(sessions) < uploadCleanupBatch {
+			return removed
+		}
+	}
+}
+
+func (s *UploadService) getOwnSession(sessionID, userID uint) (*models.UploadSession, error) {
+	session, err := s.uploadRepo.GetByID(sessionID)
+	if err != nil || session.OwnerID != userID {
+		return nil, errors.New("sessão de upload não encontrada")
+	}
+	if session.Status == models.UploadStatusUploading && time.Now().After(session.ExpiresAt) {
+		return nil, errors.New("sessão de upload não encontrada")
+	}
+	return session, nil
+}
+
+func (s *UploadService) lock(sessionID uint) func() {
+	value, _ := s.locks.LoadOrStore(sessionID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func (s *UploadService) partPath(sessionID uint) string {
+	return filepath.Join(s.config.UploadTempPath, fmt.Sprintf("%d.part", sessionID))
+}
+
+func (s *UploadService) removePart(sessionID uint) {
+	if err := os.Remove(s.partPath(sessionID)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Falha ao remover arquivo parcial do upload %d: %v", sessionID, err)
+	}
+	s.locks.Delete(sessionID)
+}
+
+func mediaUploadResponse(media *models.Media) *MediaUploadResponse {
+	return &MediaUploadResponse{
+		ID:        media.ID,
+		URL:       media.URL,
+		FilePath:  media.FilePath,
+		FileName:  filepath.Base(media.FilePath),
+		FileSize:  media.FileSize,
+		MimeType:  media.MimeType,
+		MediaType: media.MediaType,
+		Width:     media.Width,
+		Height:    media.Height,
+		Variants:  media.Variants,
+		Status:    media.Status,
+
+		ModerationStatus: media.ModerationStatus,
+	}
+}
+
+// Funções de validação
+
+func (s *UploadService) validateCreateUploadRequest(req *CreateUploadRequest) error {
+	if strings.TrimSpace(req.FileName) == "" {
+		return errors.New("nome do arquivo é obrigatório")
+	}
+	if len(req.FileName) > 255 {
+		return errors.New("nome do arquivo deve ter no máximo 255 caracteres")
+	}
+	if err := s.mediaService.ValidateFileName(req.FileName, req.MediaType); err != nil {
+		return err
+	}
+	if req.TotalSize <= 0 {
+		return errors.New("tamanho do arquivo deve ser maior que zero")
+	}
+
+	maxSize := s.config.MaxChunkedUploadSize
+	if req.MediaType == MediaTypeImage {
+		maxSize = s.config.MaxFileSize
+	}
+	if req.TotalSize > maxSize {
+		return fmt.Errorf("arquivo muito grande. Tamanho máximo: %d MB", maxSize/(1024*1024))
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"errors"
+	"log"
+	"mime/multipart"
 	"strings"
 
+	"github.com/Ulpio/guIA-backend/internal/events"
 	"github.com/Ulpio/guIA-backend/internal/models"
 	"github.com/Ulpio/guIA-backend/internal/repositories"
 	"golang.org/x/crypto/bcrypt"
@@ -12,6 +15,7 @@ import (
 type UserServiceInterface interface {
 	GetProfile(userID uint) (*models.UserResponse, error)
 	UpdateProfile(userID uint, updateData *UpdateProfileRequest) (*models.UserResponse, error)
+	UpdateProfileImage(userID uint, kind ProfileImageKind, file *multipart.FileHeader, crop *CropRect) (*ProfileImageResponse, error)
 	GetUserByID(userID uint) (*models.UserResponse, error)
 	SearchUsers(query string, limit, offset int) ([]models.UserResponse, error)
 	FollowUser(followerID, followedID uint) error
@@ -19,6 +23,9 @@ type UserServiceInterface interface {
 	GetFollowers(userID uint, limit, offset int) ([]models.UserResponse, error)
 	GetFollowing(userID uint, limit, offset int) ([]models.UserResponse, error)
 	IsFollowing(followerID, followedID uint) (bool, error)
+	BlockUser(blockerID, blockedID uint) error
+	UnblockUser(blockerID, blockedID uint) error
+	GetBlockedUsers(userID uint, limit, offset int) ([]models.UserResponse, error)
 	ChangePassword(userID uint, oldPassword, newPassword string) error
 	DeactivateAccount(userID uint) error
 }
@@ -33,13 +40,25 @@ type UpdateProfileRequest struct {
 	CompanyName    *string `json:"company_name,omitempty"`
 }
 
+// ProfileImageResponse é o perfil atualizado com os tamanhos gerados da foto
+type ProfileImageResponse struct {
+	User     *models.UserResponse           `json:"user"`
+	Variants map[string]models.MediaVariant `json:"variants"`
+}
+
 type UserService struct {
-	userRepo repositories.UserRepositoryInterface
+	userRepo     repositories.UserRepositoryInterface
+	mediaService MediaServiceInterface
+	spamService  SpamServiceInterface
+	eventBus     events.BusInterface
 }
 
-func NewUserService(userRepo repositories.UserRepositoryInterface) UserServiceInterface {
+func NewUserService(userRepo repositories.UserRepositoryInterface, mediaService MediaServiceInterface, spamService SpamServiceInterface, eventBus events.BusInterface) UserServiceInterface {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		mediaService: mediaService,
+		spamService:  spamService,
+		eventBus:     eventBus,
 	}
 }
 
@@ -109,6 +128,42 @@ func (s *UserService) UpdateProfile(userID uint, updateData *UpdateProfileReques
 	return user.ToResponse(), nil
 }
 
+// UpdateProfileImage troca a foto de perfil (avatar) ou de capa pela imagem
+// enviada, recortada e redimensionada, e apaga a anterior se nada mais a usa
+func (s *UserService) UpdateProfileImage(userID uint, kind ProfileImageKind, file *multipart.FileHeader, crop *CropRect) (*ProfileImageResponse, error) {
+	column := "profile_picture"
+	if kind == ProfileImageCover {
+		column = "cover_photo"
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	media, err := s.mediaService.StoreProfileImage(file, userID, kind, crop)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.userRepo.ReplaceProfileImage(userID, column, media.URL)
+	if err != nil {
+		if delErr := s.mediaService.DeleteFile(media.FilePath); delErr != nil {
+			log.Printf("Falha ao remover foto não usada %s: %v", media.FilePath, delErr)
+		}
+		return nil, errors.New("erro ao atualizar foto do perfil")
+	}
+	if previous != "" && previous != media.URL {
+		s.mediaService.DeleteUnusedMedia(previous, userID)
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	return &ProfileImageResponse{User: user.ToResponse(), Variants: media.Variants}, nil
+}
+
 func (s *UserService) GetUserByID(userID uint) (*models.UserResponse, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
@@ -161,7 +216,20 @@ func (s *UserService) FollowUser(followerID, followedID uint) error {
 		return errors.New("você já está seguindo este usuário")
 	}
 
-	return s.userRepo.FollowUser(followerID, followedID)
+	if err := s.spamService.CheckFollow(followerID, followedID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.FollowUser(followerID, followedID); err != nil {
+		return err
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.UserFollowed,
+		ActorID:  followerID,
+		EntityID: followedID,
+	})
+	return nil
 }
 
 func (s *UserService) UnfollowUser(followerID, followedID uint) error {
@@ -179,7 +247,12 @@ func (s *UserService) UnfollowUser(followerID, followedID uint) error {
 		return errors.New("você não está seguindo este usuário")
 	}
 
-	return s.userRepo.UnfollowUser(followerID, followedID)
+	if err := s.userRepo.UnfollowUser(followerID, followedID); err != nil {
+		return err
+	}
+
+	s.spamService.RecordUnfollow(followerID, followedID)
+	return nil
 }
 
 func (s *UserService) GetFollowers(userID uint, limit, offset int) ([]models.UserResponse, error) {
@@ -222,6 +295,70 @@ func (s *UserService) IsFollowing(followerID, followedID uint) (bool, error) {
 	return s.userRepo.IsFollowing(followerID, followedID)
 }
 
+// BlockUser bloqueia o usuário; a conversa direta entre os dois é congelada
+// a partir do evento
+func (s *UserService) BlockUser(blockerID, blockedID uint) error {
+	if blockerID == blockedID {
+		return errors.New("você não pode bloquear a si mesmo")
+	}
+
+	if _, err := s.userRepo.GetByID(blockedID); err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	blocked, err := s.userRepo.BlockUser(blockerID, blockedID)
+	if err != nil {
+		return errors.New("erro ao bloquear usuário")
+	}
+	if !blocked {
+		return errors.New("você já bloqueou este usuário")
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.UserBlocked,
+		ActorID:  blockerID,
+		EntityID: blockedID,
+	})
+	return nil
+}
+
+// UnblockUser desfaz o bloqueio; a conversa só volta a aceitar mensagens se
+// o outro usuário também não tiver bloqueado
+func (s *UserService) UnblockUser(blockerID, blockedID uint) error {
+	unblocked, err := s.userRepo.UnblockUser(blockerID, blockedID)
+	if err != nil {
+		return errors.New("erro ao desbloquear usuário")
+	}
+	if !unblocked {
+		return errors.New("bloqueio não encontrado")
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type:     events.UserUnblocked,
+		ActorID:  blockerID,
+		EntityID: blockedID,
+	})
+	return nil
+}
+
+func (s *UserService) GetBlockedUsers(userID uint, limit, offset int) ([]models.UserResponse, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	users, err := s.userRepo.GetBlockedUsers(userID, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar usuários bloqueados")
+	}
+
+	responses := make([]models.UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, *user.ToResponse())
+	}
+
+	return responses, nil
+}
+
 func (s *UserService) ChangePassword(userID uint, oldPassword, newPassword string) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
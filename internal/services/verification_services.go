@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Prazo para pedir de novo a verificação depois de uma recusa
+const verificationRetryAfter = 30 * 24 * time.Hour
+
+type RequestVerificationRequest struct {
+	Message string `json:"message" binding:"required,max=1000"` // por que a conta deve ser verificada
+}
+
+type ReviewVerificationRequest struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note" binding:"max=1000"` // mostrada ao usuário
+}
+
+type VerificationServiceInterface interface {
+	RequestVerification(userID uint, req *RequestVerificationRequest) (*models.VerificationRequestResponse, error)
+	GetMyVerification(userID uint) (*models.VerificationRequestResponse, error)
+	GetVerificationRequests(status models.VerificationStatus, limit, offset int) ([]models.VerificationRequestResponse, error)
+	ReviewVerification(requestID, adminID uint, req *ReviewVerificationRequest) (*models.VerificationRequestResponse, error)
+}
+
+type VerificationService struct {
+	verificationRepo    repositories.VerificationRepositoryInterface
+	userRepo            repositories.UserRepositoryInterface
+	notificationService NotificationServiceInterface
+}
+
+func NewVerificationService(verificationRepo repositories.VerificationRepositoryInterface, userRepo repositories.UserRepositoryInterface, notificationService NotificationServiceInterface) VerificationServiceInterface {
+	return &VerificationService{
+		verificationRepo:    verificationRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// RequestVerification coloca a conta na fila de verificação; só um pedido
+// fica em análise por vez, e depois de uma recusa é preciso esperar 30 dias
+func (s *VerificationService) RequestVerification(userID uint, req *RequestVerificationRequest) (*models.VerificationRequestResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	if user.IsVerified {
+		return nil, errors.New("a conta já é verificada")
+	}
+
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return nil, errors.New("explique por que a conta deve ser verificada")
+	}
+
+	latest, err := s.verificationRepo.GetLatestByUser(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("erro ao buscar pedido de verificação")
+	}
+	if latest != nil {
+		if latest.Status == models.VerificationPending {
+			return nil, errors.New("já existe um pedido de verificação em análise")
+		}
+		if latest.Status == models.VerificationRejected && latest.ReviewedAt != nil &&
+			time.Since(*latest.ReviewedAt) < verificationRetryAfter {
+			retryAt := latest.ReviewedAt.Add(verificationRetryAfter)
+			return nil, fmt.Errorf("um novo pedido só pode ser feito a partir de %s", retryAt.Format("02/01/2006"))
+		}
+	}
+
+	request := &models.VerificationRequest{
+		UserID:  userID,
+		Status:  models.VerificationPending,
+		Message: message,
+	}
+	if err := s.verificationRepo.Create(request); err != nil {
+		return nil, errors.New("erro ao criar pedido de verificação")
+	}
+
+	return request.ToResponse(), nil
+}
+
+// GetMyVerification retorna o pedido mais recente do usuário
+func (s *VerificationService) GetMyVerification(userID uint) (*models.VerificationRequestResponse, error) {
+	request, err := s.verificationRepo.GetLatestByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pedido de verificação não encontrado")
+		}
+		return nil, errors.New("erro ao buscar pedido de verificação")
+	}
+	return request.ToResponse(), nil
+}
+
+func (s *VerificationService) GetVerificationRequests(status models.VerificationStatus, limit, offset int) ([]models.VerificationRequestResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if status == "" {
+		status = models.VerificationPending
+	}
+	if status != models.VerificationPending && status != models.VerificationApproved && status != models.VerificationRejected {
+		return nil, errors.New("status inválido")
+	}
+
+	requests, err := s.verificationRepo.GetByStatus(status, limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar pedidos de verificação")
+	}
+
+	responses := make([]models.VerificationRequestResponse, len(requests))
+	for i := range requests {
+		responses[i] = *toAdminVerificationResponse(&requests[i])
+	}
+	return responses, nil
+}
+
+// ReviewVerification aprova (dando o selo à conta) ou recusa um pedido em
+// análise e avisa o usuário da decisão
+func (s *VerificationService) ReviewVerification(requestID, adminID uint, req *ReviewVerificationRequest) (*models.VerificationRequestResponse, error) {
+	request, err := s.verificationRepo.GetByID(requestID)
+	if err != nil {
+		return nil, errors.New("pedido de verificação não encontrado")
+	}
+	if request.Status != models.VerificationPending {
+		return nil, errors.New("pedido de verificação já analisado")
+	}
+
+	audit := &models.ModerationAction{
+		AdminID:    adminID,
+		Action:     models.ModerationActionRejectVerification,
+		TargetType: models.ModerationTargetVerification,
+		TargetID:   request.ID,
+		Note:       req.Note,
+	}
+	request.Status = models.VerificationRejected
+	if req.Approve {
+		audit.Action = models.ModerationActionApproveVerification
+		request.Status = models.VerificationApproved
+	}
+	request.ReviewNote = strings.TrimSpace(req.Note)
+
+	reviewed, err := s.verificationRepo.Review(request, audit)
+	if err != nil {
+		return nil, errors.New("erro ao analisar pedido de verificação")
+	}
+	if !reviewed {
+		return nil, errors.New("pedido de verificação já analisado")
+	}
+
+	if req.Approve {
+		request.User.IsVerified = true
+	}
+	s.notifyReview(request)
+
+	return toAdminVerificationResponse(request), nil
+}
+
+func (s *VerificationService) notifyReview(request *models.VerificationRequest) {
+	title := "Sua conta foi verificada"
+	body := "Agora seu perfil exibe o selo de conta verificada"
+	if request.Status == models.VerificationRejected {
+		title = "Pedido de verificação recusado"
+		body = "Seu pedido de verificação não foi aprovado"
+		if request.ReviewNote != "" {
+			body += ": " + request.ReviewNote
+		}
+	}
+
+	key := fmt.Sprintf("verification:%d", request.ID)
+	_, err := s.notificationService.Notify(&models.Notification{
+		UserID: request.UserID,
+		Type:   models.NotificationTypeVerification,
+		Title:  title,
+		Body:   truncateString(body, 500),
+		Data: map[string]string{
+			"verification_request_id": fmt.Sprint(request.ID),
+			"status":                  string(request.Status),
+		},
+		Key: &key,
+	})
+	if err != nil {
+		log.Printf("Falha ao notificar a análise do pedido de verificação %d: %v", request.ID, err)
+	}
+}
+
+func toAdminVerificationResponse(request *models.VerificationRequest) *models.VerificationRequestResponse {
+	response := request.ToResponse()
+	if request.User.ID != 0 {
+		response.User = request.User.ToAdminResponse()
+	}
+	return response
+}
@@ -0,0 +1,493 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+// ErrWeatherNotConfigured indica que nenhum provedor de previsão do tempo foi configurado
+var ErrWeatherNotConfigured = errors.New("previsão do tempo não configurada")
+
+// Estado da previsão de um dia do roteiro
+const (
+	WeatherStatusOK          = "ok"
+	WeatherStatusOutOfRange  = "out_of_range" // data no passado ou além do horizonte do provedor
+	WeatherStatusNoLocation  = "no_location"  // nenhum local com coordenadas até este dia
+	WeatherStatusUnavailable = "unavailable"  // provedor falhou e não há cache
+)
+
+type WeatherConfig struct {
+	Provider   string // "openmeteo" ou "openweathermap"
+	APIKey     string
+	APIURL     string
+	CacheHours int
+}
+
+// WeatherProviderInterface abstrai o serviço externo de previsão do tempo;
+// Forecast retorna uma previsão por data do intervalo, nas datas locais do ponto
+type WeatherProviderInterface interface {
+	Name() string
+	ForecastDays() int
+	Forecast(latitude, longitude float64, from, to time.Time) ([]models.WeatherForecast, error)
+}
+
+func NewWeatherProvider(config *WeatherConfig) WeatherProviderInterface {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch config.Provider {
+	case "openmeteo":
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://api.open-meteo.com"
+		}
+		return &openMeteoWeatherProvider{
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+	case "openweathermap":
+		if config.APIKey == "" {
+			return &disabledWeatherProvider{}
+		}
+		apiURL := config.APIURL
+		if apiURL == "" {
+			apiURL = "https://api.openweathermap.org"
+		}
+		return &openWeatherMapProvider{
+			apiKey: config.APIKey,
+			apiURL: strings.TrimRight(apiURL, "/"),
+			client: client,
+		}
+	default:
+		return &disabledWeatherProvider{}
+	}
+}
+
+type disabledWeatherProvider struct{}
+
+func (p *disabledWeatherProvider) Name() string { return "none" }
+
+func (p *disabledWeatherProvider) ForecastDays() int { return 0 }
+
+func (p *disabledWeatherProvider) Forecast(latitude, longitude float64, from, to time.Time) ([]models.WeatherForecast, error) {
+	return nil, ErrWeatherNotConfigured
+}
+
+// openMeteoWeatherProvider usa a API pública do Open-Meteo (sem chave), com
+// previsão diária de até 16 dias
+type openMeteoWeatherProvider struct {
+	apiURL string
+	client *http.Client
+}
+
+func (p *openMeteoWeatherProvider) Name() string { return "openmeteo" }
+
+func (p *openMeteoWeatherProvider) ForecastDays() int { return 16 }
+
+func (p *openMeteoWeatherProvider) Forecast(latitude, longitude float64, from, to time.Time) ([]models.WeatherForecast, error) {
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%.4f", latitude))
+	params.Set("longitude", fmt.Sprintf("%.4f", longitude))
+	params.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max,precipitation_sum,wind_speed_10m_max")
+	params.Set("timezone", "auto")
+	params.Set("start_date", from.Format("2006-01-02"))
+	params.Set("end_date", to.Format("2006-01-02"))
+
+	var result struct {
+		Daily struct {
+			Time                        []string   `json:"time"`
+			WeatherCode                 []*int     `json:"weather_code"`
+			Temperature2mMax            []*float64 `json:"temperature_2m_max"`
+			Temperature2mMin            []*float64 `json:"temperature_2m_min"`
+			PrecipitationProbabilityMax []*float64 `json:"precipitation_probability_max"`
+			PrecipitationSum            []*float64 `json:"precipitation_sum"`
+			WindSpeed10mMax             []*float64 `json:"wind_speed_10m_max"`
+		} `json:"daily"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/v1/forecast?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	valueAt := func(values []*float64, i int) *float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return nil
+	}
+	orZero := func(value *float64) float64 {
+		if value == nil {
+			return 0
+		}
+		return *value
+	}
+
+	daily := result.Daily
+	forecasts := make([]models.WeatherForecast, 0, len(daily.Time))
+	for i, day := range daily.Time {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+		code := -1
+		if i < len(daily.WeatherCode) && daily.WeatherCode[i] != nil {
+			code = *daily.WeatherCode[i]
+		}
+		condition, description := wmoCondition(code)
+
+		forecasts = append(forecasts, models.WeatherForecast{
+			Date:                     date,
+			Condition:                condition,
+			Description:              description,
+			TempMinC:                 orZero(valueAt(daily.Temperature2mMin, i)),
+			TempMaxC:                 orZero(valueAt(daily.Temperature2mMax, i)),
+			PrecipitationProbability: valueAt(daily.PrecipitationProbabilityMax, i),
+			PrecipitationMm:          orZero(valueAt(daily.PrecipitationSum, i)),
+			WindSpeedKmh:             orZero(valueAt(daily.WindSpeed10mMax, i)),
+		})
+	}
+	return forecasts, nil
+}
+
+// openWeatherMapProvider usa a One Call API 3.0, com previsão diária de 8 dias
+type openWeatherMapProvider struct {
+	apiKey string
+	apiURL string
+	client *http.Client
+}
+
+func (p *openWeatherMapProvider) Name() string { return "openweathermap" }
+
+func (p *openWeatherMapProvider) ForecastDays() int { return 8 }
+
+func (p *openWeatherMapProvider) Forecast(latitude, longitude float64, from, to time.Time) ([]models.WeatherForecast, error) {
+	params := url.Values{}
+	params.Set("lat", fmt.Sprintf("%.4f", latitude))
+	params.Set("lon", fmt.Sprintf("%.4f", longitude))
+	params.Set("exclude", "current,minutely,hourly,alerts")
+	params.Set("units", "metric")
+	params.Set("lang", "pt_br")
+	params.Set("appid", p.apiKey)
+
+	var result struct {
+		TimezoneOffset int64 `json:"timezone_offset"`
+		Daily          []struct {
+			Dt   int64 `json:"dt"`
+			Temp struct {
+				Min float64 `json:"min"`
+				Max float64 `json:"max"`
+			} `json:"temp"`
+			Pop       float64 `json:"pop"`
+			Rain      float64 `json:"rain"`
+			Snow      float64 `json:"snow"`
+			WindSpeed float64 `json:"wind_speed"` // m/s
+			Weather   []struct {
+				ID          int    `json:"id"`
+				Description string `json:"description"`
+			} `json:"weather"`
+		} `json:"daily"`
+	}
+	if err := getPlacesJSON(p.client, p.apiURL+"/data/3.0/onecall?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]models.WeatherForecast, 0, len(result.Daily))
+	for _, day := range result.Daily {
+		date := calendarDate(time.Unix(day.Dt+result.TimezoneOffset, 0).UTC())
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+
+		condition, description := models.WeatherUnknown, ""
+		if len(day.Weather) > 0 {
+			condition, description = owmCondition(day.Weather[0].ID)
+			if day.Weather[0].Description != "" {
+				description = day.Weather[0].Description
+			}
+		}
+		probability := math.Round(day.Pop * 100)
+
+		forecasts = append(forecasts, models.WeatherForecast{
+			Date:                     date,
+			Condition:                condition,
+			Description:              description,
+			TempMinC:                 day.Temp.Min,
+			TempMaxC:                 day.Temp.Max,
+			PrecipitationProbability: &probability,
+			PrecipitationMm:          day.Rain + day.Snow,
+			WindSpeedKmh:             day.WindSpeed * 3.6,
+		})
+	}
+	return forecasts, nil
+}
+
+// wmoCondition traduz os códigos de tempo da OMM usados pelo Open-Meteo
+func wmoCondition(code int) (models.WeatherCondition, string) {
+	switch {
+	case code == 0:
+		return models.WeatherClear, "Céu limpo"
+	case code == 1 || code == 2:
+		return models.WeatherPartlyCloudy, "Parcialmente nublado"
+	case code == 3:
+		return models.WeatherCloudy, "Nublado"
+	case code == 45 || code == 48:
+		return models.WeatherFog, "Neblina"
+	case code >= 51 && code <= 57:
+		return models.WeatherDrizzle, "Garoa"
+	case (code >= 61 && code <= 67) || (code >= 80 && code <= 82):
+		return models.WeatherRain, "Chuva"
+	case (code >= 71 && code <= 77) || code == 85 || code == 86:
+		return models.WeatherSnow, "Neve"
+	case code >= 95 && code <= 99:
+		return models.WeatherThunderstorm, "Tempestade"
+	default:
+		return models.WeatherUnknown, ""
+	}
+}
+
+// owmCondition traduz os grupos de códigos do OpenWeatherMap
+func owmCondition(id int) (models.WeatherCondition, string) {
+	switch {
+	case id >= 200 && id < 300:
+		return models.WeatherThunderstorm, "Tempestade"
+	case id >= 300 && id < 400:
+		return models.WeatherDrizzle, "Garoa"
+	case id >= 500 && id < 600:
+		return models.WeatherRain, "Chuva"
+	case id >= 600 && id < 700:
+		return models.WeatherSnow, "Neve"
+	case id >= 700 && id < 800:
+		return models.WeatherFog, "Neblina"
+	case id == 800:
+		return models.WeatherClear, "Céu limpo"
+	case id == 801 || id == 802:
+		return models.WeatherPartlyCloudy, "Parcialmente nublado"
+	case id == 803 || id == 804:
+		return models.WeatherCloudy, "Nublado"
+	default:
+		return models.WeatherUnknown, ""
+	}
+}
+
+// DayWeather é a previsão de um dia do roteiro, para o local principal do
+// dia (a hospedagem ou, sem ela, o primeiro local com coordenadas). Um dia
+// sem locais geolocalizados usa o ponto do dia anterior
+type DayWeather struct {
+	DayID        uint                    `json:"day_id"`
+	DayNumber    int                     `json:"day_number"`
+	Title        string                  `json:"title"`
+	Date         string                  `json:"date"` // YYYY-MM-DD
+	Status       string                  `json:"status"`
+	LocationID   *uint                   `json:"location_id"`
+	LocationName string                  `json:"location_name"`
+	Latitude     *float64                `json:"latitude"`
+	Longitude    *float64                `json:"longitude"`
+	Forecast     *models.WeatherForecast `json:"forecast"`
+}
+
+type ItineraryWeather struct {
+	ItineraryID uint         `json:"itinerary_id"`
+	StartDate   string       `json:"start_date"`
+	Provider    string       `json:"provider"`
+	Days        []DayWeather `json:"days"`
+}
+
+type WeatherServiceInterface interface {
+	GetItineraryWeather(itineraryID, userID uint, startDate time.Time) (*ItineraryWeather, error)
+}
+
+type WeatherService struct {
+	weatherRepo   repositories.WeatherRepositoryInterface
+	itineraryRepo repositories.ItineraryRepositoryInterface
+	provider      WeatherProviderInterface
+	cacheTTL      time.Duration
+}
+
+func NewWeatherService(weatherRepo repositories.WeatherRepositoryInterface, itineraryRepo repositories.ItineraryRepositoryInterface, provider WeatherProviderInterface, cacheTTL time.Duration) WeatherServiceInterface {
+	return &WeatherService{
+		weatherRepo:   weatherRepo,
+		itineraryRepo: itineraryRepo,
+		provider:      provider,
+		cacheTTL:      cacheTTL,
+	}
+}
+
+// GetItineraryWeather busca a previsão de cada dia do roteiro, com o dia 1 em
+// startDate. Os dias são agrupados por célula da grade, e cada célula gera no
+// máximo uma consulta ao provedor cobrindo todas as suas datas
+func (s *WeatherService) GetItineraryWeather(itineraryID, userID uint, startDate time.Time) (*ItineraryWeather, error) {
+	if startDate.IsZero() {
+		return nil, errors.New("data de início da viagem é obrigatória")
+	}
+
+	itinerary, err := s.itineraryRepo.GetByID(itineraryID)
+	if err != nil || (!itinerary.IsPublic && itinerary.AuthorID != userID) {
+		return nil, errors.New("roteiro não encontrado")
+	}
+
+	startDate = calendarDate(startDate)
+	today := calendarDate(time.Now())
+	horizon := today.AddDate(0, 0, s.provider.ForecastDays()-1)
+
+	weather := &ItineraryWeather{
+		ItineraryID: itinerary.ID,
+		StartDate:   startDate.Format("2006-01-02"),
+		Provider:    s.provider.Name(),
+		Days:        []DayWeather{},
+	}
+
+	// Dias com previsão a buscar, agrupados pela célula da grade
+	pending := make(map[string][]int)
+	var cells []string
+
+	var previous *models.ItineraryLocation
+	for _, day := range sortedDays(itinerary) {
+		date := startDate.AddDate(0, 0, day.DayNumber-1)
+		dayWeather := DayWeather{
+			DayID:     day.ID,
+			DayNumber: day.DayNumber,
+			Title:     day.Title,
+			Date:      date.Format("2006-01-02"),
+		}
+
+		location := primaryLocation(&day)
+		if location == nil {
+			location = previous
+		}
+		previous = location
+
+		switch {
+		case location == nil:
+			dayWeather.Status = WeatherStatusNoLocation
+		case date.Before(today) || date.After(horizon):
+			dayWeather.Status = WeatherStatusOutOfRange
+		default:
+			dayWeather.Status = WeatherStatusUnavailable
+			cell := weatherCell(*location.Latitude, *location.Longitude)
+			if _, ok := pending[cell]; !ok {
+				cells = append(cells, cell)
+			}
+			pending[cell] = append(pending[cell], len(weather.Days))
+		}
+
+		if location != nil {
+			dayWeather.LocationID = &location.ID
+			dayWeather.LocationName = location.Name
+			dayWeather.Latitude = location.Latitude
+			dayWeather.Longitude = location.Longitude
+		}
+
+		weather.Days = append(weather.Days, dayWeather)
+	}
+
+	for _, cell := range cells {
+		indexes := pending[cell]
+		from, to := weather.Days[indexes[0]].Date, weather.Days[indexes[0]].Date
+		for _, i := range indexes {
+			if date := weather.Days[i].Date; date < from {
+				from = date
+			} else if date > to {
+				to = date
+			}
+		}
+		fromDate, _ := time.Parse("2006-01-02", from)
+		toDate, _ := time.Parse("2006-01-02", to)
+
+		forecasts, err := s.cellForecasts(cell, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range indexes {
+			if forecast, ok := forecasts[weather.Days[i].Date]; ok {
+				forecast := forecast
+				weather.Days[i].Forecast = &forecast
+				weather.Days[i].Status = WeatherStatusOK
+			}
+		}
+	}
+
+	return weather, nil
+}
+
+// cellForecasts retorna as previsões da célula por data, do cache quando
+// todas as datas estão lá e dentro da validade. Se o provedor falhar, usa o
+// que houver em cache, mesmo vencido
+func (s *WeatherService) cellForecasts(cell string, from, to time.Time) (map[string]models.WeatherForecast, error) {
+	byDate := make(map[string]models.WeatherForecast)
+
+	cached, err := s.weatherRepo.GetForecasts(cell, from, to)
+	if err != nil {
+		log.Printf("Falha ao buscar previsões em cache da célula %s: %v", cell, err)
+	}
+	fresh := len(cached) > 0
+	for _, forecast := range cached {
+		byDate[forecast.Date.Format("2006-01-02")] = forecast
+		if time.Since(forecast.FetchedAt) > s.cacheTTL {
+			fresh = false
+		}
+	}
+	if fresh && len(byDate) == daysBetween(from, to) {
+		return byDate, nil
+	}
+
+	latitude, longitude := weatherCellCenter(cell)
+	forecasts, err := s.provider.Forecast(latitude, longitude, from, to)
+	if errors.Is(err, ErrWeatherNotConfigured) {
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("Falha ao buscar previsão do tempo da célula %s: %v", cell, err)
+		return byDate, nil
+	}
+
+	now := time.Now()
+	for i := range forecasts {
+		forecasts[i].Cell = cell
+		forecasts[i].Provider = s.provider.Name()
+		forecasts[i].FetchedAt = now
+		byDate[forecasts[i].Date.Format("2006-01-02")] = forecasts[i]
+	}
+	if err := s.weatherRepo.SaveForecasts(forecasts); err != nil {
+		log.Printf("Falha ao salvar previsões da célula %s: %v", cell, err)
+	}
+
+	return byDate, nil
+}
+
+// primaryLocation escolhe a hospedagem do dia ou, sem ela, o primeiro local
+// com coordenadas na ordem de visita
+func primaryLocation(day *models.ItineraryDay) *models.ItineraryLocation {
+	var first *models.ItineraryLocation
+	for _, location := range sortedLocations(day) {
+		if location.Latitude == nil || location.Longitude == nil || !validCoordinates(*location.Latitude, *location.Longitude) {
+			continue
+		}
+		location := location
+		if location.LocationType == models.LocationTypeHotel {
+			return &location
+		}
+		if first == nil {
+			first = &location
+		}
+	}
+	return first
+}
+
+// weatherCell arredonda as coordenadas para uma casa decimal (cerca de 11 km)
+func weatherCell(latitude, longitude float64) string {
+	return fmt.Sprintf("%.1f,%.1f", math.Round(latitude*10)/10, math.Round(longitude*10)/10)
+}
+
+func weatherCellCenter(cell string) (float64, float64) {
+	var latitude, longitude float64
+	fmt.Sscanf(cell, "%f,%f", &latitude, &longitude)
+	return latitude, longitude
+}
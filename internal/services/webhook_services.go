@@ -0,0 +1,373 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+)
+
+const (
+	webhookQueueSize = 500
+	// Intervalo base entre tentativas; dobra a cada falha
+	webhookRetryBackoff = time.Minute
+	maxWebhookRetryWait = 6 * time.Hour
+)
+
+// MaxWebhookPayload é o maior corpo de callback aceito, em bytes
+const MaxWebhookPayload = 256 * 1024
+
+// WebhookProcessor aplica um evento recebido ao domínio (ex.: confirma a
+// gorjeta de um pagamento). Precisa ser idempotente, pois um evento pode ser
+// reprocessado após uma falha ou um reenvio manual, e ignorar os eventos que
+// não são seus, já que todos os processadores do provedor recebem cada evento
+type WebhookProcessor func(event *models.WebhookEvent) error
+
+type WebhookConfig struct {
+	StripeSecret      string
+	TranscodingSecret string
+	PushSecret        string
+	ToleranceSeconds  int // diferença máxima entre o timestamp assinado e o relógio do servidor
+	MaxAttempts       int // tentativas antes de o evento ir para a fila de mensagens mortas
+}
+
+type WebhookServiceInterface interface {
+	Receive(provider string, signature string, payload []byte) (*models.WebhookEvent, bool, error)
+	RegisterProcessor(provider models.WebhookProvider, processor WebhookProcessor)
+	GetEvents(provider, status string, limit, offset int) ([]models.WebhookEvent, error)
+	Redeliver(eventID uint) (*models.WebhookEvent, error)
+	StartWebhookWorker()
+	StartWebhookRetryScheduler(interval time.Duration)
+}
+
+type WebhookService struct {
+	webhookRepo repositories.WebhookRepositoryInterface
+	config      *WebhookConfig
+	mu          sync.RWMutex
+	processors  map[models.WebhookProvider][]WebhookProcessor
+	queue       chan uint
+}
+
+func NewWebhookService(webhookRepo repositories.WebhookRepositoryInterface, config *WebhookConfig) WebhookServiceInterface {
+	if config.ToleranceSeconds <= 0 {
+		config.ToleranceSeconds = 300
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		config:      config,
+		processors:  make(map[models.WebhookProvider][]WebhookProcessor),
+		queue:       make(chan uint, webhookQueueSize),
+	}
+}
+
+// Receive valida a assinatura e o timestamp do callback e grava o evento para
+// processamento assíncrono. Retorna true quando o evento é um reenvio de um
+// já recebido, que não é processado de novo
+func (s *WebhookService) Receive(provider string, signature string, payload []byte) (*models.WebhookEvent, bool, error) {
+	webhookProvider := models.WebhookProvider(provider)
+	secret := s.secretFor(webhookProvider)
+	if secret == "" {
+		return nil, false, errors.New("provedor de webhook não encontrado")
+	}
+
+	if len(payload) > MaxWebhookPayload {
+		return nil, false, errors.New("payload do webhook muito grande")
+	}
+
+	if err := s.verifySignature(secret, signature, payload, time.Now()); err != nil {
+		return nil, false, err
+	}
+
+	var envelope struct {
+		ID      string `json:"id"`
+		EventID string `json:"event_id"`
+		Type    string `json:"type"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, false, errors.New("payload do webhook inválido")
+	}
+	eventID := envelope.ID
+	if eventID == "" {
+		eventID = envelope.EventID
+	}
+	if eventID == "" || len(eventID) > 255 {
+		return nil, false, errors.New("evento do webhook sem identificador")
+	}
+
+	now := time.Now()
+	event := &models.WebhookEvent{
+		Provider:      webhookProvider,
+		EventID:       eventID,
+		EventType:     truncateString(envelope.Type, 100),
+		Payload:       string(payload),
+		Status:        models.WebhookEventPending,
+		NextAttemptAt: &now,
+	}
+
+	created, err := s.webhookRepo.Create(event)
+	if err != nil {
+		return nil, false, errors.New("erro ao registrar evento do webhook")
+	}
+	if !created {
+		existing, err := s.webhookRepo.GetByProviderEventID(webhookProvider, eventID)
+		if err != nil {
+			return nil, false, errors.New("erro ao registrar evento do webhook")
+		}
+		return existing, true, nil
+	}
+
+	s.enqueue(event.ID)
+	return event, false, nil
+}
+
+func (s *WebhookService) RegisterProcessor(provider models.WebhookProvider, processor WebhookProcessor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processors[provider] = append(s.processors[provider], processor)
+}
+
+func (s *WebhookService) GetEvents(provider, status string, limit, offset int) ([]models.WebhookEvent, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	events, err := s.webhookRepo.GetEvents(models.WebhookProvider(provider), models.WebhookEventStatus(status), limit, offset)
+	if err != nil {
+		return nil, errors.New("erro ao buscar eventos de webhook")
+	}
+
+	return events, nil
+}
+
+// Redeliver devolve um evento à fila com as tentativas zeradas, normalmente
+// depois de corrigida a causa que o levou à fila de mensagens mortas
+func (s *WebhookService) Redeliver(eventID uint) (*models.WebhookEvent, error) {
+	if _, err := s.webhookRepo.GetByID(eventID); err != nil {
+		return nil, errors.New("evento de webhook não encontrado")
+	}
+
+	requeued, err := s.webhookRepo.Requeue(eventID)
+	if err != nil {
+		return nil, errors.New("erro ao reenviar evento de webhook")
+	}
+	if !requeued {
+		return nil, errors.New("evento de webhook já está em processamento")
+	}
+
+	s.enqueue(eventID)
+
+	event, err := s.webhookRepo.GetByID(eventID)
+	if err != nil {
+		return nil, errors.New("erro ao reenviar evento de webhook")
+	}
+	return event, nil
+}
+
+// StartWebhookWorker processa os eventos da fila e retoma os que foram
+// interrompidos por uma reinicialização
+func (s *WebhookService) StartWebhookWorker() {
+	go func() {
+		for eventID := range s.queue {
+			if err := s.processEvent(eventID); err != nil {
+				log.Printf("Falha ao processar evento de webhook %d: %v", eventID, err)
+			}
+		}
+	}()
+
+	if err := s.webhookRepo.ReleaseProcessing(); err != nil {
+		log.Println("Falha ao liberar eventos de webhook interrompidos:", err)
+	}
+}
+
+// StartWebhookRetryScheduler reenfileira os eventos cuja próxima tentativa
+// venceu, incluindo os que não couberam na fila no recebimento
+func (s *WebhookService) StartWebhookRetryScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.enqueueDue()
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *WebhookService) enqueueDue() {
+	ids, err := s.webhookRepo.GetDue(time.Now(), webhookQueueSize)
+	if err != nil {
+		log.Println("Falha ao buscar eventos de webhook pendentes:", err)
+		return
+	}
+	for _, id := range ids {
+		s.enqueue(id)
+	}
+}
+
+// enqueue não bloqueia a requisição; com a fila cheia o evento fica
+// pendente até a próxima passada do agendador
+func (s *WebhookService) enqueue(eventID uint) {
+	select {
+	case s.queue <- eventID:
+	default:
+	}
+}
+
+func (s *WebhookService) processEvent(eventID uint) error {
+	claimed, err := s.webhookRepo.Claim(eventID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	event, err := s.webhookRepo.GetByID(eventID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	processors := s.processors[event.Provider]
+	s.mu.RUnlock()
+
+	now := time.Now()
+	event.NextAttemptAt = nil
+	if len(processors) == 0 {
+		event.Status = models.WebhookEventIgnored
+		event.ProcessedAt = &now
+		return s.webhookRepo.UpdateResult(event)
+	}
+
+	if err := runWebhookProcessors(processors, event); err != nil {
+		event.LastError = truncateString(err.Error(), 500)
+		if event.Attempts >= s.config.MaxAttempts {
+			event.Status = models.WebhookEventDeadLetter
+			log.Printf("Evento de webhook %d (%s) movido para a fila de mensagens mortas: %v", event.ID, event.Provider, err)
+		} else {
+			next := now.Add(webhookRetryDelay(event.Attempts))
+			event.Status = models.WebhookEventFailed
+			event.NextAttemptAt = &next
+		}
+		return s.webhookRepo.UpdateResult(event)
+	}
+
+	event.Status = models.WebhookEventProcessed
+	event.LastError = ""
+	event.ProcessedAt = &now
+	return s.webhookRepo.UpdateResult(event)
+}
+
+// runWebhookProcessors executa todos os processadores do provedor; a tentativa
+// falha se algum falhar, e o reprocessamento roda todos de novo
+func runWebhookProcessors(processors []WebhookProcessor, event *models.WebhookEvent) error {
+	var failed error
+	for _, processor := range processors {
+		if err := runWebhookProcessor(processor, event); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// runWebhookProcessor converte um panic do processador em falha da tentativa
+func runWebhookProcessor(processor WebhookProcessor, event *models.WebhookEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic ao processar evento: %v", r)
+		}
+	}()
+	return processor(event)
+}
+
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBackoff
+	for i := 1; i < attempts && delay < maxWebhookRetryWait; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookRetryWait {
+		delay = maxWebhookRetryWait
+	}
+	return delay
+}
+
+func (s *WebhookService) secretFor(provider models.WebhookProvider) string {
+	switch provider {
+	case models.WebhookProviderStripe:
+		return s.config.StripeSecret
+	case models.WebhookProviderTranscoding:
+		return s.config.TranscodingSecret
+	case models.WebhookProviderPush:
+		return s.config.PushSecret
+	}
+	return ""
+}
+
+// verifySignature valida o cabeçalho no formato "t=<unix>,v1=<hex>" (o mesmo
+// da Stripe), em que v1 é o HMAC-SHA256 de "<t>.<payload>". O timestamp
+// assinado limita a janela em que uma requisição capturada pode ser repetida
+func (s *WebhookService) verifySignature(secret, header string, payload []byte, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return errors.New("assinatura do webhook inválida")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("assinatura do webhook inválida")
+	}
+	skew := now.Sub(time.Unix(unix, 0))
+	tolerance := time.Duration(s.config.ToleranceSeconds) * time.Second
+	if skew > tolerance || skew < -tolerance {
+		return errors.New("timestamp do webhook fora da tolerância")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return errors.New("assinatura do webhook inválida")
+}
+
+// truncateString limita o texto a max bytes sem partir um caractere UTF-8
+func truncateString(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
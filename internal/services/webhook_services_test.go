@@ -0,0 +1,91 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.succeeded"}`)
+	now := time.Unix(1_750_000_000, 0)
+	ts := now.Unix()
+	header := func(timestamp int64, signatures ...string) string {
+		value := "t=" + strconv.FormatInt(timestamp, 10)
+		for _, signature := range signatures {
+			value += ",v1=" + signature
+		}
+		return value
+	}
+
+	service := &WebhookService{config: &WebhookConfig{ToleranceSeconds: 300}}
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		wantErr string
+	}{
+		{name: "válida", header: header(ts, signWebhook(secret, ts, payload)), payload: payload},
+		{name: "com espaços", header: "t=" + strconv.FormatInt(ts, 10) + ", v1=" + signWebhook(secret, ts, payload), payload: payload},
+		{name: "uma de várias assinaturas", header: header(ts, "00", signWebhook(secret, ts, payload)), payload: payload},
+		{name: "no limite da tolerância", header: header(ts-300, signWebhook(secret, ts-300, payload)), payload: payload},
+		{name: "antiga demais", header: header(ts-301, signWebhook(secret, ts-301, payload)), payload: payload, wantErr: "timestamp do webhook fora da tolerância"},
+		{name: "no futuro", header: header(ts+301, signWebhook(secret, ts+301, payload)), payload: payload, wantErr: "timestamp do webhook fora da tolerância"},
+		{name: "outro segredo", header: header(ts, signWebhook("outro", ts, payload)), payload: payload, wantErr: "assinatura do webhook inválida"},
+		{name: "payload alterado", header: header(ts, signWebhook(secret, ts, payload)), payload: []byte(`{"id":"evt_2"}`), wantErr: "assinatura do webhook inválida"},
+		{name: "timestamp trocado", header: header(ts+1, signWebhook(secret, ts, payload)), payload: payload, wantErr: "assinatura do webhook inválida"},
+		{name: "sem timestamp", header: "v1=" + signWebhook(secret, ts, payload), payload: payload, wantErr: "assinatura do webhook inválida"},
+		{name: "sem assinatura", header: header(ts), payload: payload, wantErr: "assinatura do webhook inválida"},
+		{name: "timestamp inválido", header: "t=ontem,v1=" + signWebhook(secret, ts, payload), payload: payload, wantErr: "assinatura do webhook inválida"},
+		{name: "vazio", header: "", payload: payload, wantErr: "assinatura do webhook inválida"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.verifySignature(secret, tt.header, tt.payload, now)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("verifySignature = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("verifySignature = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		max   int
+		want  string
+	}{
+		{"menor que o limite", "praia", 10, "praia"},
+		{"no limite", "praia", 5, "praia"},
+		{"ascii", "praia do forte", 5, "praia"},
+		{"não parte acentos", "ação", 2, "a"}, // "ç" ocupa os bytes 1 e 2
+		{"acento inteiro", "ação", 3, "aç"},
+		{"emoji", "🏖️ praia", 3, ""}, // o emoji ocupa 4 bytes
+		{"limite zero", "praia", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateString(tt.value, tt.max); got != tt.want {
+				t.Errorf("truncateString(%q, %d) = %q, want %q", tt.value, tt.max, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Ulpio/guIA-backend/internal/models"
+	"github.com/Ulpio/guIA-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	yearReviewTopPhotos  = 9
+	yearReviewImageSize  = 1080
+	yearReviewHeaderSize = 240
+	yearReviewFreshness  = 24 * time.Hour // validade de um resumo gerado com o ano em andamento
+)
+
+type YearReviewServiceInterface interface {
+	GetYearReview(userID uint, year int) (*models.YearReviewResponse, error)
+	GenerateYearReview(userID uint, year int) (*models.YearReview, error)
+	GeneratePendingReviews(year int) (int, error)
+	StartYearReviewScheduler(interval time.Duration)
+}
+
+type YearReviewService struct {
+	yearReviewRepo repositories.YearReviewRepositoryInterface
+	postRepo       repositories.PostRepositoryInterface
+	geoService     GeoServiceInterface
+	mediaService   MediaServiceInterface
+}
+
+func NewYearReviewService(yearReviewRepo repositories.YearReviewRepositoryInterface, postRepo repositories.PostRepositoryInterface, geoService GeoServiceInterface, mediaService MediaServiceInterface) YearReviewServiceInterface {
+	return &YearReviewService{
+		yearReviewRepo: yearReviewRepo,
+		postRepo:       postRepo,
+		geoService:     geoService,
+		mediaService:   mediaService,
+	}
+}
+
+// GetYearReview retorna o resumo do ano; resumos gerados antes do fim do ano
+// são recalculados quando ficam desatualizados
+func (s *YearReviewService) GetYearReview(userID uint, year int) (*models.YearReviewResponse, error) {
+	if err := validateReviewYear(year); err != nil {
+		return nil, err
+	}
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	review, err := s.yearReviewRepo.GetByUserAndYear(userID, year)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("erro ao buscar resumo do ano")
+	}
+
+	if review == nil || (review.GeneratedAt.Before(end) && time.Since(review.GeneratedAt) > yearReviewFreshness) {
+		if review, err = s.GenerateYearReview(userID, year); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.YearReviewResponse{
+		Year:        review.Year,
+		User:        *review.User.ToResponse(),
+		Stats:       review.Stats,
+		ImageURL:    review.ImageURL,
+		GeneratedAt: review.GeneratedAt,
+	}
+	if review.Stats.MostLikedPostID != nil {
+		if post, err := s.postRepo.GetByID(*review.Stats.MostLikedPostID, userID); err == nil {
+			response.MostLikedPost = post.ToResponse(userID)
+		}
+	}
+
+	return response, nil
+}
+
+// GenerateYearReview compila as estatísticas do ano e gera a imagem de
+// compartilhamento a partir das fotos mais curtidas
+func (s *YearReviewService) GenerateYearReview(userID uint, year int) (*models.YearReview, error) {
+	if err := validateReviewYear(year); err != nil {
+		return nil, err
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	posts, err := s.yearReviewRepo.GetPostsInRange(userID, from, to)
+	if err != nil {
+		return nil, errors.New("erro ao buscar posts do ano")
+	}
+
+	itineraries, err := s.yearReviewRepo.GetItinerariesInRange(userID, from, to)
+	if err != nil {
+		return nil, errors.New("erro ao buscar roteiros do ano")
+	}
+
+	previous, err := s.yearReviewRepo.GetByUserAndYear(userID, year)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("erro ao buscar resumo do ano")
+	}
+
+	review := &models.YearReview{
+		UserID:      userID,
+		Year:        year,
+		Stats:       s.compileStats(posts, itineraries),
+		GeneratedAt: time.Now(),
+	}
+
+	if data, err := s.renderImage(year, review.Stats.TopPhotos); err != nil {
+		log.Printf("Falha ao gerar imagem do resumo %d do usuário %d: %v", year, userID, err)
+	} else if uploaded, err := s.mediaService.SaveGeneratedImage(data, userID, "year-reviews"); err != nil {
+		log.Printf("Falha ao salvar imagem do resumo %d do usuário %d: %v", year, userID, err)
+	} else {
+		review.ImageURL = uploaded.URL
+		review.ImagePath = uploaded.FilePath
+	}
+
+	if err := s.yearReviewRepo.Save(review); err != nil {
+		if review.ImagePath != "" {
+			if delErr := s.mediaService.DeleteFile(review.ImagePath); delErr != nil {
+				log.Printf("Falha ao remover imagem não utilizada %s: %v", review.ImagePath, delErr)
+			}
+		}
+		return nil, errors.New("erro ao salvar resumo do ano")
+	}
+
+	// A imagem anterior deixa de ser referenciada quando o resumo é regerado
+	if previous != nil && previous.ImagePath != "" && previous.ImagePath != review.ImagePath && review.ImagePath != "" {
+		if err := s.mediaService.DeleteFile(previous.ImagePath); err != nil {
+			log.Printf("Falha ao remover imagem antiga %s: %v", previous.ImagePath, err)
+		}
+	}
+
+	saved, err := s.yearReviewRepo.GetByUserAndYear(userID, year)
+	if err != nil {
+		return nil, errors.New("erro ao buscar resumo do ano")
+	}
+
+	return saved, nil
+}
+
+// GeneratePendingReviews gera o resumo do ano para os usuários ativos que
+// ainda não o possuem ou que só têm um resumo parcial, gerado antes do fim do ano
+func (s *YearReviewService) GeneratePendingReviews(year int) (int, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	userIDs, err := s.yearReviewRepo.GetUsersPendingReview(year, from, to)
+	if err != nil {
+		return 0, errors.New("erro ao buscar usuários sem resumo do ano")
+	}
+
+	generated := 0
+	for _, userID := range userIDs {
+		if _, err := s.GenerateYearReview(userID, year); err != nil {
+			log.Printf("Falha ao gerar resumo %d do usuário %d: %v", year, userID, err)
+			continue
+		}
+		generated++
+	}
+
+	return generated, nil
+}
+
+// StartYearReviewScheduler gera periodicamente em segundo plano os resumos do
+// ano que acabou de terminar
+func (s *YearReviewService) StartYearReviewScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			year := time.Now().UTC().Year() - 1
+			if generated, err := s.GeneratePendingReviews(year); err != nil {
+				log.Println("Falha ao gerar resumos do ano:", err)
+			} else if generated > 0 {
+				log.Printf("%d resumos do ano %d gerados", generated, year)
+			}
+		}
+	}()
+}
+
+func (s *YearReviewService) compileStats(posts []models.Post, itineraries []models.Itinerary) models.YearReviewStats {
+	stats := models.YearReviewStats{
+		PostsCount:       len(posts),
+		ItinerariesCount: len(itineraries),
+		Countries:        []string{},
+		TopPhotos:        []models.YearReviewPhoto{},
+	}
+
+	countries := make(map[string]bool)
+	addCountry := func(name string) {
+		if name != "" && !countries[name] {
+			countries[name] = true
+			stats.Countries = append(stats.Countries, name)
+		}
+	}
+
+	for _, itinerary := range itineraries {
+		if itinerary.Country == "" {
+			continue
+		}
+		if normalized := s.geoService.NormalizeLocation(itinerary.Country, "", ""); normalized.Country != "" {
+			addCountry(normalized.Country)
+		} else {
+			addCountry(itinerary.Country)
+		}
+	}
+
+	// Os posts vêm em ordem cronológica, então a distância entre coordenadas
+	// consecutivas aproxima o trajeto percorrido no ano
+	var lastLat, lastLng *float64
+	resolved := make(map[[2]float64]bool)
+	for _, post := range posts {
+		stats.LikesReceived += post.LikesCount
+		stats.CommentsReceived += post.CommentsCount
+
+		if post.Latitude == nil || post.Longitude == nil {
+			continue
+		}
+		if lastLat != nil {
+			stats.KmTraveled += haversineKm(*lastLat, *lastLng, *post.Latitude, *post.Longitude)
+		}
+		lastLat, lastLng = post.Latitude, post.Longitude
+
+		cell := [2]float64{math.Round(*post.Latitude*10) / 10, math.Round(*post.Longitude*10) / 10}
+		if !resolved[cell] {
+			resolved[cell] = true
+			addCountry(s.geoService.CountryAt(*post.Latitude, *post.Longitude))
+		}
+	}
+	stats.KmTraveled = math.Round(stats.KmTraveled*10) / 10
+	stats.CountriesCount = len(stats.Countries)
+
+	ranked := make([]models.Post, len(posts))
+	copy(ranked, posts)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].LikesCount > ranked[j].LikesCount })
+
+	if len(ranked) > 0 {
+		stats.MostLikedPostID = &ranked[0].ID
+	}
+
+	for _, post := range ranked {
+		if len(stats.TopPhotos) == yearReviewTopPhotos {
+			break
+		}
+		if url := postImageURL(&post); url != "" {
+			stats.TopPhotos = append(stats.TopPhotos, models.YearReviewPhoto{
+				PostID:     post.ID,
+				URL:        url,
+				LikesCount: post.LikesCount,
+			})
+		}
+	}
+
+	return stats
+}
+
+// postImageURL retorna a primeira imagem do post, se houver
+func postImageURL(post *models.Post) string {
+	for _, item := range post.MediaItems {
+		if item.MediaType == models.MediaTypeImage {
+			return item.URL
+		}
+	}
+	if len(post.MediaItems) == 0 && post.PostType == models.PostTypeImage {
+		if len(post.MediaURLs) > 0 {
+			return post.MediaURLs[0]
+		}
+		return post.MediaURL
+	}
+	return ""
+}
+
+func validateReviewYear(year int) error {
+	if year < 2000 || year > time.Now().UTC().Year() {
+		return errors.New("ano inválido")
+	}
+	return nil
+}
+
+// ============================================================================
+// GERAÇÃO DA IMAGEM
+// ============================================================================
+
+var (
+	yearReviewBackground = color.RGBA{R: 15, G: 118, B: 110, A: 255}
+	yearReviewEmptyCell  = color.RGBA{R: 45, G: 148, B: 140, A: 255}
+	yearReviewForeground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+// yearReviewDigits é uma fonte bitmap 5x7 usada para escrever o ano na imagem
+var yearReviewDigits = map[rune][7]string{
+	'0': {"01110", "10001", "10011", "10101", "11001", "10001", "01110"},
+	'1': {"00100", "01100", "00100", "00100", "00100", "00100", "01110"},
+	'2': {"01110", "10001", "00001", "00010", "00100", "01000", "11111"},
+	'3': {"11110", "00001", "00001", "01110", "00001", "00001", "11110"},
+	'4': {"00010", "00110", "01010", "10010", "11111", "00010", "00010"},
+	'5': {"11111", "10000", "11110", "00001", "00001", "10001", "01110"},
+	'6': {"00110", "01000", "10000", "11110", "10001", "10001", "01110"},
+	'7': {"11111", "00001", "00010", "00100", "01000", "01000", "01000"},
+	'8': {"01110", "10001", "10001", "01110", "10001", "10001", "01110"},
+	'9': {"01110", "10001", "10001", "01111", "00001", "00010", "01100"},
+}
+
+// renderImage monta um PNG quadrado com o ano no topo e um mosaico das fotos
+// mais curtidas; fotos que não puderem ser lidas são ignoradas
+func (s *YearReviewService) renderImage(year int, photos []models.YearReviewPhoto) ([]byte, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, yearReviewImageSize, yearReviewImageSize))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: yearReviewBackground}, image.Point{}, draw.Src)
+
+	drawYear(canvas, strconv.Itoa(year))
+
+	var images []image.Image
+	for _, photo := range photos {
+		img, err := s.mediaService.LoadImage(photo.URL)
+		if err != nil {
+			continue
+		}
+		images = append(images, img)
+	}
+
+	columns := 1
+	switch {
+	case len(images) > 4:
+		columns = 3
+	case len(images) > 1:
+		columns = 2
+	}
+
+	const margin, gap = 140, 10
+	gridSize := yearReviewImageSize - 2*margin
+	cellSize := (gridSize - (columns-1)*gap) / columns
+	for i := 0; i < columns*columns; i++ {
+		x := margin + (i%columns)*(cellSize+gap)
+		y := yearReviewHeaderSize + (i/columns)*(cellSize+gap)
+		cell := image.Rect(x, y, x+cellSize, y+cellSize)
+		if i < len(images) {
+			drawCover(canvas, cell, images[i])
+		} else {
+			draw.Draw(canvas, cell, &image.Uniform{C: yearReviewEmptyCell}, image.Point{}, draw.Src)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawYear escreve o texto centralizado na faixa superior da imagem
+func drawYear(canvas *image.RGBA, text string) {
+	const scale, spacing = 16, 2
+	width := len(text)*5*scale + (len(text)-1)*spacing*scale
+	left := (yearReviewImageSize - width) / 2
+	top := (yearReviewHeaderSize - 7*scale) / 2
+
+	for i, char := range text {
+		glyph, ok := yearReviewDigits[char]
+		if !ok {
+			continue
+		}
+		offset := left + i*(5+spacing)*scale
+		for row, line := range glyph {
+			for col, pixel := range line {
+				if pixel != '1' {
+					continue
+				}
+				x := offset + col*scale
+				y := top + row*scale
+				draw.Draw(canvas, image.Rect(x, y, x+scale, y+scale), &image.Uniform{C: yearReviewForeground}, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+// drawCover preenche a célula com a imagem recortada ao centro, mantendo a
+// proporção (amostragem pelo vizinho mais próximo)
+func drawCover(canvas *image.RGBA, cell image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	if side == 0 {
+		return
+	}
+	cropX := bounds.Min.X + (bounds.Dx()-side)/2
+	cropY := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	for y := 0; y < cell.Dy(); y++ {
+		srcY := cropY + y*side/cell.Dy()
+		for x := 0; x < cell.Dx(); x++ {
+			srcX := cropX + x*side/cell.Dx()
+			canvas.Set(cell.Min.X+x, cell.Min.Y+y, src.At(srcX, srcY))
+		}
+	}
+}